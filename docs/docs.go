@@ -4,11 +4,11 @@ package docs
 import "github.com/swaggo/swag"
 
 const docTemplate = `{
-    "schemes": {{ marshal .Schemes }},
+    "schemes": [[ marshal .Schemes ]],
     "swagger": "2.0",
     "info": {
-        "description": "{{escape .Description}}",
-        "title": "{{.Title}}",
+        "description": "[[escape .Description]]",
+        "title": "[[.Title]]",
         "termsOfService": "http://swagger.io/terms/",
         "contact": {
             "name": "API Support",
@@ -19,14 +19,14 @@ const docTemplate = `{
             "name": "Apache 2.0",
             "url": "http://www.apache.org/licenses/LICENSE-2.0.html"
         },
-        "version": "{{.Version}}"
+        "version": "[[.Version]]"
     },
-    "host": "{{.Host}}",
-    "basePath": "{{.BasePath}}",
+    "host": "[[.Host]]",
+    "basePath": "[[.BasePath]]",
     "paths": {
-        "/products": {
-            "get": {
-                "description": "Retrieves a list of all products, sorted by creation date in descending order (newest first)",
+        "/admin/attributes/migrate": {
+            "post": {
+                "description": "Starts a background job that renames an attribute key and/or remaps its values on every variant, e.g. colour to color and Navy to Dark Blue. Each changed product is written as an update event tagged with the job.",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,71 +34,45 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "jobs"
                 ],
-                "summary": "List all products",
+                "summary": "Rename or remap a variant attribute",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Page number (starts from 1)",
-                        "name": "page",
-                        "in": "query",
-                        "default": 1
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of products per page",
-                        "name": "size",
-                        "in": "query",
-                        "default": 10
+                        "description": "Attribute migration",
+                        "name": "migration",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AttributeMigration"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "object",
-                            "properties": {
-                                "data": {
-                                    "type": "array",
-                                    "items": {
-                                        "$ref": "#/definitions/models.Product"
-                                    }
-                                },
-                                "pagination": {
-                                    "type": "object",
-                                    "properties": {
-                                        "current_page": {
-                                            "type": "integer",
-                                            "example": 1
-                                        },
-                                        "page_size": {
-                                            "type": "integer",
-                                            "example": 10
-                                        },
-                                        "total_items": {
-                                            "type": "integer",
-                                            "example": 100
-                                        },
-                                        "total_pages": {
-                                            "type": "integer",
-                                            "example": 10
-                                        }
-                                    }
-                                }
-                            }
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/models.APIError"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/catalog/clone": {
             "post": {
-                "description": "Creates a new product with the specified details",
+                "description": "Fetches the filtered catalog export of a configured environment and starts importing it here, e.g. staging to prod, or prod to test with prices anonymized by the source",
                 "consumes": [
                     "application/json"
                 ],
@@ -106,95 +80,115 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "catalog"
                 ],
-                "summary": "Create a new product",
+                "summary": "Clone a catalog from another environment",
                 "parameters": [
                     {
-                        "description": "Product details",
-                        "name": "product",
+                        "description": "Source, filter and options",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/interfaces.CatalogCloneRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.Job"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
                         }
                     }
                 }
             }
         },
-        "/products/batch": {
-            "put": {
-                "description": "Updates multiple products in a single request. All products must exist and contain valid data.",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/catalog/export": {
+            "get": {
+                "description": "Returns the products matching the filter in a form another environment can import",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "catalog"
                 ],
-                "summary": "Batch update multiple products simultaneously",
+                "summary": "Export the catalog",
                 "parameters": [
                     {
-                        "description": "Array of products to update with their IDs and new data",
-                        "name": "products",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Product"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Comma-separated product IDs",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products whose SKU starts with this prefix",
+                        "name": "sku_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products with metadata for this market",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Replace prices with made-up amounts",
+                        "name": "anonymize_prices",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Array of updated products",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Product"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid JSON data or validation errors",
-                        "schema": {
-                            "$ref": "#/definitions/models.APIError"
-                        }
-                    },
-                    "404": {
-                        "description": "One or more products not found",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.APIError"
+                            "$ref": "#/definitions/models.CatalogExport"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.APIError"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/catalog/import": {
             "post": {
-                "description": "Creates multiple products simultaneously in a single request",
+                "description": "Starts a background job that writes the products of a catalog export, keeping their IDs if asked to",
                 "consumes": [
                     "application/json"
                 ],
@@ -202,65 +196,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "catalog"
                 ],
-                "summary": "Create multiple products in bulk",
+                "summary": "Import an exported catalog",
                 "parameters": [
                     {
-                        "description": "Array of products to create",
-                        "name": "products",
+                        "description": "Catalog export and options",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Product"
-                            }
+                            "$ref": "#/definitions/interfaces.CatalogImportRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Array of created products",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Product"
-                            }
+                            "$ref": "#/definitions/models.Job"
                         }
                     },
                     "400": {
-                        "description": "Invalid JSON data",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/models.APIError"
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/models.APIError"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes multiple products in a single request by their IDs. Returns results of deletion operations.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/admin/catalog/sources": {
+            "get": {
+                "description": "Returns the environments catalogs can be cloned from",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "catalog"
                 ],
-                "summary": "Batch delete multiple products simultaneously",
-                "parameters": [
-                    {
-                        "description": "Array of product IDs to delete",
-                        "name": "productIDs",
-                        "in": "body",
-                        "required": true,
+                "summary": "List catalog sources",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
@@ -268,317 +252,8194 @@ const docTemplate = `{
                             }
                         }
                     }
+                }
+            }
+        },
+        "/admin/dashboard/consumers": {
+            "get": {
+                "description": "Returns each internal event subscriber's committed sequence and how far it trails the latest published sequence",
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Event consumer lag",
                 "responses": {
                     "200": {
-                        "description": "Map of product IDs to deletion status",
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.ConsumerLag"
                             }
                         }
-                    },
-                    "400": {
-                        "description": "Invalid JSON data",
-                        "schema": {
-                            "$ref": "#/definitions/models.APIError"
-                        }
-                    },
-                    "404": {
-                        "description": "One or more products not found",
-                        "schema": {
-                            "$ref": "#/definitions/models.APIError"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/models.APIError"
-                        }
                     }
                 }
             }
         },
-        "/products/{id}": {
+        "/admin/dashboard/deprecations": {
             "get": {
-                "description": "Retrieves a product with the specified ID",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns every deprecated route and field with its planned sunset and how often, and by which callers, it was used since the process started",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
-                ],
-                "summary": "Get a product",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "admin"
                 ],
+                "summary": "Deprecation usage",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.DeprecationUsage"
+                            }
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Updates an existing product",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/admin/dashboard/errors": {
+            "get": {
+                "description": "Returns response counts per status and the server error rate for a time window",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "admin"
                 ],
-                "summary": "Update a product",
+                "summary": "Error-rate summary",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated product details",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Product"
-                        }
+                        "default": "15m",
+                        "description": "Time window, e.g. 15m or 1h",
+                        "name": "window",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/interfaces.ErrorRateSummary"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "$ref": "#/definitions/models.APIError"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Deletes a product with the specified ID",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/admin/dashboard/events": {
+            "get": {
+                "description": "Returns the latest product events, newest first",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "products"
+                    "admin"
                 ],
-                "summary": "Delete a product",
+                "summary": "Recent events feed",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of events",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "404": {
-                        "description": "Not Found",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Event"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/dashboard/jobs": {
+            "get": {
+                "description": "Returns the most recent batch jobs and counts per status",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Job statuses",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of jobs",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.JobStatusSummary"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/dashboard/latency": {
+            "get": {
+                "description": "Returns each route's latency budget, p95 over the last 5 minutes and error budget burn rates",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Route latency objectives",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.RouteLatency"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/dashboard/top-edited": {
+            "get": {
+                "description": "Returns the products with the most recent updates, ordered by edit count",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Top recently edited products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of products",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.EditedProduct"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/dashboard/websocket": {
+            "get": {
+                "description": "Returns the number of connected WebSocket clients",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "WebSocket client count",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.WebSocketSummary"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/diagnostics": {
+            "get": {
+                "description": "Reports the build, the backend behind each component, which optional features are enabled, the configuration read from the environment with secrets redacted, the startup self-check results and recent response error counts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get diagnostics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.Diagnostics"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/forecasting/inputs": {
+            "post": {
+                "description": "Stores stock ledger entries and sales velocities from external systems, e.g. the POS or a BI tool. A batch with any invalid input is rejected as a whole.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "forecasting"
+                ],
+                "summary": "Push forecasting inputs",
+                "parameters": [
+                    {
+                        "description": "Ledger entries and velocities",
+                        "name": "inputs",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.ForecastInputs"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.ForecastInputsResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/forecasting/stockouts": {
+            "get": {
+                "description": "Returns the projected stockout date per variant with forecasting inputs, soonest first. Variants that are not selling have no date and come last.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "forecasting"
+                ],
+                "summary": "Projected stockouts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only variants projected to run out within this many days",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of variants",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockoutForecast"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/edit-sessions": {
+            "get": {
+                "description": "Returns the live edit sessions on a product, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "List edit sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EditSession"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Starts an edit session that expires unless renewed and returns the other editors of the product. Sessions never block updates; they let admin UIs warn about concurrent editors before a version conflict.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Claim a product for editing",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Editor and session length",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EditSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.EditClaim"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/edit-sessions/{session}": {
+            "put": {
+                "description": "Extends an edit session, e.g. on a heartbeat from the edit form, and returns the product's current version and other editors",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Renew an edit session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Session length",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EditSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.EditClaim"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Ends an edit session when the editor saves or leaves the form",
+                "tags": [
+                    "products"
+                ],
+                "summary": "Release an edit session",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "session",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/notes": {
+            "get": {
+                "description": "Returns the internal note threads on a product with their replies, oldest first. Notes are never part of product responses.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "List product notes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NoteThread"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Starts a thread on a product, or replies to one with thread_id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Add a product note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Note",
+                        "name": "note",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductNote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/notes/{note}": {
+            "put": {
+                "description": "Replaces the body of a note; only its author may",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Edit a product note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Note ID",
+                        "name": "note",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NoteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductNote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a note, and its replies when it starts a thread; only its author may",
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Delete a product note",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Note ID",
+                        "name": "note",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author of the note, when not logged in",
+                        "name": "author",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/{id}/notes/{note}/resolution": {
+            "put": {
+                "description": "Marks a thread as settled, or reopens it. Anyone may resolve a thread.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notes"
+                ],
+                "summary": "Resolve a note thread",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the note starting the thread",
+                        "name": "note",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Whether the thread is resolved",
+                        "name": "resolution",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.NoteResolution"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductNote"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reprocess": {
+            "post": {
+                "description": "Starts a background job that delivers the latest event of each selected product to an internal consumer again, at a limited rate. Use it after fixing a bug in a consumer. Offsets are not changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Reprocess products through event consumers",
+                "parameters": [
+                    {
+                        "description": "Products, consumer and rate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.ReprocessRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/runbook/deliveries/resend": {
+            "post": {
+                "description": "Delivers the latest event of every product whose delivery to one of the targets last failed between from and to again, through the consumer of the target. The run is recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "runbook"
+                ],
+                "summary": "Resend failed deliveries",
+                "parameters": [
+                    {
+                        "description": "Targets and time range",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ResendDeliveriesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RunbookRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/runbook/products/{id}/event-chain/verify": {
+            "post": {
+                "description": "Checks that the product's events follow each other without gaps, that their hashes chain, and that the stored product matches the latest event. A broken chain is reported in the result with valid false. The run is recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "runbook"
+                ],
+                "summary": "Re-verify a product's event chain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Actor, when not logged in",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RunbookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RunbookRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/runbook/products/{id}/locks/release": {
+            "post": {
+                "description": "Force-releases the product's write lock and edit sessions held for longer than older_than_seconds, e.g. after a writer crashed. The run is recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "runbook"
+                ],
+                "summary": "Release a product's stale locks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Minimum lock age and actor",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LockReleaseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RunbookRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/runbook/projections/{name}/rebuild": {
+            "post": {
+                "description": "Drops a projection built by an internal consumer, such as the dashboard, and delivers every stored event to it again. The run is recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "runbook"
+                ],
+                "summary": "Rebuild a projection",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Projection, e.g. dashboard",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Actor, when not logged in",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RunbookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RunbookRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/runbook/runs": {
+            "get": {
+                "description": "Returns the audit trail of runbook actions, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "runbook"
+                ],
+                "summary": "List runbook runs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of runs (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RunbookRun"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/text/replace": {
+            "post": {
+                "description": "Replaces a string or regular expression in titles, descriptions and keywords of the filtered products. With dry_run the changes are returned as a preview; otherwise a background job writes each changed product as an update event tagged with the job.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Search and replace text across the catalog",
+                "parameters": [
+                    {
+                        "description": "Text replacement",
+                        "name": "replacement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TextReplacement"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TextReplacementPreview"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "Returns every category ordered by ID, or with tree=true the roots with their subcategories nested under them, ordered by position and slug",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "List categories",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Nest subcategories under their parents",
+                        "name": "tree",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a category, below parent_id if set. Slugs are unique among siblings and categories nest at most 10 deep. Publishes category.created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Create a category",
+                "parameters": [
+                    {
+                        "description": "Category; the ID is generated",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Get a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the slug, names, position and parent of a category. Changing the parent moves the category with its subcategories. Publishes category.updated, with action moved when the parent changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Replace a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category; the ID is taken from the path",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a category that has no subcategories and no products. Publishes category.deleted.",
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Delete a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{id}/products": {
+            "get": {
+                "description": "Returns the products in a category and, unless include_subcategories=false, in every category below it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "List products in a category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Include products in subcategories",
+                        "name": "include_subcategories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs": {
+            "get": {
+                "description": "Returns the most recent batch and import jobs, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only jobs of this type, e.g. import",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of jobs",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Job"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "description": "Returns the status, counts and errors of a batch or import job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get a job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}/rollback": {
+            "post": {
+                "description": "Reverts all changes made by a finished batch job as new compensating changes, recorded as a rollback job",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Roll back a batch job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.JobRollbackResult"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/marketplaces": {
+            "get": {
+                "description": "Returns the names of the marketplaces products are exported to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marketplaces"
+                ],
+                "summary": "List marketplaces",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/marketplaces/{marketplace}/sync-status": {
+            "get": {
+                "description": "Returns the latest per-product sync statuses for a marketplace, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marketplaces"
+                ],
+                "summary": "Marketplace sync status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Marketplace name, e.g. amazon",
+                        "name": "marketplace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only statuses in this state: synced, failed, skipped or removed",
+                        "name": "state",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of statuses",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SyncStatus"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/markets/{market}/allocation-policies": {
+            "get": {
+                "description": "Returns the allocation policies configured for a market and its sales channels",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "allocation"
+                ],
+                "summary": "List allocation policies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AllocationPolicy"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/markets/{market}/allocation-policy": {
+            "get": {
+                "description": "Returns the policy that applies to a sales channel: the channel's own, else the market's, else the default (every stocked location in listed order, split shipments allowed)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "allocation"
+                ],
+                "summary": "Get the allocation policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sales channel, e.g. web; empty for the market-wide policy",
+                        "name": "channel",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AllocationPolicy"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Creates or replaces the allocation policy of a market or one of its sales channels. The nearest strategy needs coordinates for every location.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "allocation"
+                ],
+                "summary": "Set the allocation policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sales channel, e.g. web; empty for the market-wide policy",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Policy; market and channel are taken from the path and query",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AllocationPolicy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AllocationPolicy"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a configured policy; the channel falls back to the market's policy, and the market to the default",
+                "tags": [
+                    "allocation"
+                ],
+                "summary": "Delete an allocation policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sales channel, e.g. web; empty for the market-wide policy",
+                        "name": "channel",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/markets/{market}/launch-checklist": {
+            "get": {
+                "description": "Lists products blocked from launching in a market (missing translation, price, stock or image) with reasons",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "markets"
+                ],
+                "summary": "Market launch checklist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to require a price in (defaults to the market currency)",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LaunchChecklist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/markets/{market}/merchandising": {
+            "get": {
+                "description": "Returns the pinned products of every curated listing in a market",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "markets"
+                ],
+                "summary": "List merchandising positions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Merchandising"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/markets/{market}/merchandising/pins": {
+            "get": {
+                "description": "Returns the pinned products of one listing",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "markets"
+                ],
+                "summary": "Get merchandising positions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curated category; empty for the full listing",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Merchandising"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces every pin of one listing. Each product needs metadata for the market and a slot of its own.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "markets"
+                ],
+                "summary": "Replace merchandising positions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curated category; empty for the full listing",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Pins; market and category are taken from the path and query",
+                        "name": "pins",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Merchandising"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Merchandising"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/markets/{market}/merchandising/pins/{id}": {
+            "put": {
+                "description": "Places a product at a 1-based position in one listing, moving it if it was already pinned. A position held by another product is rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "markets"
+                ],
+                "summary": "Pin a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curated category; empty for the full listing",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Position; product_id is taken from the path",
+                        "name": "pin",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Pin"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Merchandising"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a product's pin from one listing; it falls back to the default order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "markets"
+                ],
+                "summary": "Unpin a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curated category; empty for the full listing",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Merchandising"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/markets/{market}/products": {
+            "get": {
+                "description": "Returns the products with metadata for the market. Pinned products take their merchandising positions for the category; the others follow oldest first, so pages are stable.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "markets"
+                ],
+                "summary": "List products in a market",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curated category whose pins apply; empty for the full listing",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/price-lists": {
+            "get": {
+                "description": "Returns every price list with its prices, ordered by ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "List price lists",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PriceList"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a price list in one currency, scoped to a market and/or customer group. Each price applies to a product, or one of its variants, from valid_from (inclusive) to valid_to (exclusive). Every product whose price changes gets a price.changed event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Create a price list",
+                "parameters": [
+                    {
+                        "description": "Price list; the ID is generated",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceList"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/price-lists/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Get a price list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Price list ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceList"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces the scope and prices of a price list. Products whose prices in the list changed get a price.changed event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Replace a price list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Price list ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Price list; the ID is taken from the path",
+                        "name": "list",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceList"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceList"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a price list; its products fall back to the other lists and their own prices",
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Delete a price list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Price list ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "Hämtar en lista över alla produkter",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Lista alla produkter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "List the catalog as it existed at this RFC 3339 timestamp",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only products with every one of these tags",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only products in at least one of these categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only the product with this product or variant SKU",
+                        "name": "sku",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products with metadata for this market",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products with a price in this currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only products with a price of at least this amount, in currency if given",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only products with a price of at most this amount, in currency if given",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products whose base or market title contains this text, ignoring case",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products created after this RFC 3339 timestamp",
+                        "name": "created_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products created before this RFC 3339 timestamp",
+                        "name": "created_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted products",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from next_cursor; switches to cursor pagination",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size in cursor pagination, default 10",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Also show each price converted to this ISO 4217 currency",
+                        "name": "display_currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy of the page; 304 is returned while it is current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new product with the given details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Create a new product",
+                "parameters": [
+                    {
+                        "description": "Product details",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "423": {
+                        "description": "The SKU is being written",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/batch": {
+            "put": {
+                "description": "Updates multiple products in a single request. All products must exist and contain valid data.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Batch update multiple products simultaneously",
+                "parameters": [
+                    {
+                        "description": "Array of products to update with their IDs and new data",
+                        "name": "products",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Array of updated products",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON data, validation errors or too many products",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "One or more products not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates multiple products simultaneously in a single request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Create multiple products in bulk",
+                "parameters": [
+                    {
+                        "description": "Array of products to create",
+                        "name": "products",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Array of created products",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON data or too many products",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes multiple products in a single request by their IDs. Returns results of deletion operations.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Batch delete multiple products simultaneously",
+                "parameters": [
+                    {
+                        "description": "Array of product IDs to delete",
+                        "name": "productIDs",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Map of product IDs to deletion status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid JSON data or too many product IDs",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "One or more products not found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/compare": {
+            "get": {
+                "description": "Returns an attribute-aligned comparison matrix with prices per currency, variant attributes and stock",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Compare products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated product IDs (2-10)",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductComparison"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/export": {
+            "get": {
+                "description": "Streams every product matching the filters, one row per product with a column per field, price currency and market metadata attribute. Column names match the import mapping targets, so the file can be imported again.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Export products as CSV or Excel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (default) or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product or variant SKU",
+                        "name": "sku",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products with metadata for this market",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products with a price in this currency",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products with this tag",
+                        "name": "tag",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/facets": {
+            "get": {
+                "description": "Counts the products matching the same filters as GET /products per market, currency, variant attribute value (e.g. size and color) and, with a currency, price bucket. A product counts once per value. Without price_buckets about five buckets of a round width cover the prices.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Count products per filter value",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only products with every one of these tags",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only products in at least one of these categories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products with metadata for this market",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products with a price in this currency, which the price buckets count",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only products with a price of at least this amount, in currency if given",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Only products with a price of at most this amount, in currency if given",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products whose base or market title contains this text, ignoring case",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lower bounds of the price buckets, e.g. 0,100,500",
+                        "name": "price_buckets",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Facets"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/import": {
+            "post": {
+                "description": "Transforms flat records into products using per-field template expressions (e.g. \"{{upper .sku}}\", \"{{mul .price_eur 11.5}}\"). In create mode the products are created as a batch job; in update mode each record is merged into the product or variant with the same SKU. Set dry_run to preview the result. A multipart/form-data body imports the CSV file in its file field instead, with the other request fields as form fields sent before it; without a mapping, columns named after a target field are imported as they are.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Import products with a field mapping",
+                "parameters": [
+                    {
+                        "description": "Records and mapping",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.ImportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.ImportResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/metadata/copy": {
+            "post": {
+                "description": "Starts a background job that copies the titles, descriptions and keywords of a source market to target markets of the filtered products, optionally only where the target is empty. Each changed product is written as an update event tagged with the job, so the copy can be undone with POST /jobs/{id}/rollback.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Copy metadata between markets",
+                "parameters": [
+                    {
+                        "description": "Metadata copy",
+                        "name": "copy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MetadataCopy"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/search": {
+            "get": {
+                "description": "Full-text search over SKUs, titles, descriptions, keywords and tags, most relevant first. Any of the words matches; SKU and title matches weigh more than descriptions. With a market only products with metadata for it are searched, with the market's language analyzer, so e.g. \"skjortor\" finds \"skjorta\" in SE.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Search products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Words to search for",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search the texts of a market, e.g. SE",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size, at most 100",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProductSearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/sku/{sku}": {
+            "get": {
+                "description": "Fetches the product with the given SKU; SKUs are unique across products",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get a product by SKU",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product SKU",
+                        "name": "sku",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Also show the prices converted to this ISO 4217 currency",
+                        "name": "display_currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/tags": {
+            "post": {
+                "description": "Starts a background job that adds and removes tags on the listed products, or on every product with a tag. Each changed product is written as an update tagged with the job, so the change can be undone with POST /jobs/{id}/rollback.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Add and remove tags in bulk",
+                "parameters": [
+                    {
+                        "description": "Products and tags",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TagUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Job"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "description": "Fetches a product with the given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Also show the prices converted to this ISO 4217 currency",
+                        "name": "display_currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy; 304 is returned while the version is current",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time of a cached copy; 304 is returned if the product has not changed since",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates an existing product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Update a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product version the update is based on",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated product details",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "423": {
+                        "description": "The product is being written",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft-deletes the product with the given ID, so it can be restored, or removes it for good with permanent=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Remove the product and not just mark it as deleted",
+                        "name": "permanent",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "423": {
+                        "description": "The product is being written",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Applies a JSON Merge Patch (application/merge-patch+json, or application/json) or a JSON Patch (application/json-patch+json) to the current product, validates the result and saves it as the next version. id, version and timestamps cannot be patched.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Partially update a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product version the patch is based on",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge patch object or array of JSON Patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "423": {
+                        "description": "The product is being written",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/availability": {
+            "get": {
+                "description": "Reports how much of each variant one order in the market and channel can get from the locations its allocation policy allows",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "allocation"
+                ],
+                "summary": "Product availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sales channel, e.g. web",
+                        "name": "channel",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Availability"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/bundle": {
+            "get": {
+                "description": "Computes the bundle price per currency with its price strategy (sum, fixed or percentage_discount) and the number of bundles the component stock covers, from the current components",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get a bundle's price and availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bundle product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BundleSummary"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/categories": {
+            "put": {
+                "description": "Replaces the categories of a product; every category must exist. An empty list removes the product from all categories. Publishes product.updated with action categories_assigned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "categories"
+                ],
+                "summary": "Assign a product to categories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category IDs, e.g. {\\",
+                        "name": "assignment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/events": {
+            "get": {
+                "description": "Returns the product's events from from_version on, oldest first, after verifying their hash chain",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "List the events of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First version to return, default 1",
+                        "name": "from_version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Event"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The event chain is broken",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/images": {
+            "post": {
+                "description": "Appends an image to the product. A JSON body attaches an image served from an external URL. A multipart/form-data body uploads the image in its file field to the media storage instead, with alt_text and alt_texts (a JSON object of alt texts per market) as form fields sent before it.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "media"
+                ],
+                "summary": "Add a product image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image with an absolute URL",
+                        "name": "image",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Image"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/images/order": {
+            "put": {
+                "description": "Puts the images in the order of the IDs, which must list every image of the product once. The first image is the main image.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "media"
+                ],
+                "summary": "Reorder product images",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image IDs, e.g. {\\",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/images/{image_id}": {
+            "delete": {
+                "description": "Detaches the image from the product; the binary of an uploaded image is deleted from the media storage",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "media"
+                ],
+                "summary": "Remove a product image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Image ID",
+                        "name": "image_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/price": {
+            "get": {
+                "description": "Returns the price that applies right now, or at a given time: the most specific price list with a valid price (customer group before market before neither, then by descending priority), else the variant's or the product's own price",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "pricing"
+                ],
+                "summary": "Resolve a product price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency, e.g. SEK",
+                        "name": "currency",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Customer group, e.g. b2b",
+                        "name": "customer_group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Variant ID",
+                        "name": "variant_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC 3339 time to resolve at, default now",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResolvedPrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/related": {
+            "get": {
+                "description": "Returns the products linked from a product with their relation type, in one call. Deleted products are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relations"
+                ],
+                "summary": "List related products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only products linked with this relation type",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RelatedProduct"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/relations": {
+            "get": {
+                "description": "Returns the relations from a product, ordered by type, position and related product ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relations"
+                ],
+                "summary": "List product relations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only relations of this type: related, upsell, cross_sell, bundle or variant_of",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProductRelation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a relation of a type from the product to another product, or replaces the relation of that type between them",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "relations"
+                ],
+                "summary": "Link a product to another",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Relation; the product ID is taken from the path",
+                        "name": "relation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductRelation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductRelation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/relations/{type}/{related}": {
+            "delete": {
+                "tags": [
+                    "relations"
+                ],
+                "summary": "Remove a product relation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Relation type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related product ID",
+                        "name": "related",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/reservations": {
+            "post": {
+                "description": "Allocates the items to stock locations under the market's or channel's allocation policy and reserves them in one stock adjustment. Nothing is reserved when the allowed locations cannot cover every item.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "allocation"
+                ],
+                "summary": "Reserve stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Items to reserve",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AllocationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Allocation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "423": {
+                        "description": "The product is being written",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/restore": {
+            "post": {
+                "description": "Re-activates a soft-deleted product as a new version and publishes a product.restored event",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Restore a soft-deleted product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The product is not deleted",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "423": {
+                        "description": "The product is being written",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/rollback": {
+            "post": {
+                "description": "Reconstructs the product state at to_version from the event history and applies it as a new version",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Roll back a product to an earlier version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Version to restore",
+                        "name": "to_version",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/stock": {
+            "post": {
+                "description": "Applies stock deltas per variant and location in one conditional update. The request fails without changes if any location without backorders would go below zero.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Adjust product stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stock adjustments",
+                        "name": "adjustments",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockAdjustment"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "423": {
+                        "description": "The product is being written",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/sync-status": {
+            "get": {
+                "description": "Returns the last synced version and recent delivery errors of a product for every downstream target (search, feeds, marketplaces, webhook endpoints)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Product sync status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/interfaces.ProductSyncStatus"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/variants/{vid}/stock": {
+            "get": {
+                "description": "Returns the stock of a variant at every location it has an entry for, with the product version it was read at",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock"
+                ],
+                "summary": "Get variant stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Variant ID",
+                        "name": "vid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.VariantStock"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Sets the quantities of a variant at the listed locations in one conditional update, e.g. after a stock count. Locations that are not listed keep their quantities. Nothing changes if a location without backorders would be set below zero.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock"
+                ],
+                "summary": "Set variant stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Variant ID",
+                        "name": "vid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quantities per location",
+                        "name": "levels",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.StockLevel"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.VariantStock"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "423": {
+                        "description": "The product is being written",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/versions": {
+            "get": {
+                "description": "Lists the versions that can be reconstructed from the product's verified events, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "List the versions of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProductVersion"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The event chain is broken",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/versions/{version}": {
+            "get": {
+                "description": "Replays the product's events up to the version and returns the product as it looked then",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Get a product as it was at a version",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Product version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "409": {
+                        "description": "The event chain is broken",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/markets/{market}/products": {
+            "get": {
+                "description": "Returns the storefront listing of a market in merchandising order, without internal fields",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "List published products in a market",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Market code, e.g. SE",
+                        "name": "market",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Curated category whose pins apply; empty for the full listing",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/products": {
+            "get": {
+                "description": "Returns published products without internal fields such as sourcing. Requires an X-API-Key header when public API keys are configured.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "List published products",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only products with every one of these tags",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/public/products/{id}": {
+            "get": {
+                "description": "Returns a published product without internal fields. Drafts are not found.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "public"
+                ],
+                "summary": "Get a published product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/stock/adjustments": {
+            "post": {
+                "description": "Applies stock deltas, or absolute quantities, to variants of several products. The adjustments of each product are applied in one conditional update or not at all, so a product that would go below zero at a location without backorders fails without affecting the others. Each product gets a result, in the order the products first appear.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock"
+                ],
+                "summary": "Adjust stock across products",
+                "parameters": [
+                    {
+                        "description": "Stock adjustments",
+                        "name": "adjustments",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProductStockAdjustment"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.BatchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Returns every tag in use with the number of products that have it, most used first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "List tags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TagUsage"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/{tag}/products": {
+            "delete": {
+                "description": "Deletes every product with the tag as one batch job, which can be undone with POST /jobs/{id}/rollback",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "products"
+                ],
+                "summary": "Delete tagged products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tag",
+                        "name": "tag",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/interfaces.BatchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Returns the version and commit of the running build and the event schema versions it publishes and reads. Clients compare these with their own to detect incompatible builds during rolling upgrades.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get the build version",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.VersionInfo"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "Returns every registered endpoint, oldest first, without secrets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook endpoints",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookEndpoint"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Registers a URL that product events are POSTed to as JSON, signed with the secret in X-Ecom-Signature. The secret is only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Register a webhook endpoint",
+                "parameters": [
+                    {
+                        "description": "Endpoint",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookRegistration"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookEndpoint"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes an endpoint and its delivery log",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Returns the latest delivery attempts to an endpoint, newest first, with the response status, error and next retry of each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Webhook delivery log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Maximum number of attempts",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}/state": {
+            "put": {
+                "description": "A disabled endpoint receives no deliveries, and its scheduled retries are dropped. Events published while it was disabled are not delivered when it is enabled again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Disable or enable a webhook endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Endpoint ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Whether the endpoint is disabled",
+                        "name": "state",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebhookState"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookEndpoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.APIError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.EditSessionRequest": {
+            "type": "object",
+            "properties": {
+                "editor": {
+                    "description": "Editor names the user editing. Ignored when the caller is logged in, the\nsession is then claimed for the logged in user.",
+                    "type": "string"
+                },
+                "ttl_seconds": {
+                    "description": "TTLSeconds is how long the session lasts without a renewal, default 120",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.LockReleaseRequest": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor names the on-call engineer. Ignored when the caller is logged in,\nthe action is then run by the logged in user.",
+                    "type": "string"
+                },
+                "older_than_seconds": {
+                    "description": "OlderThanSeconds is how long a lock must have been held, default 60",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.NoteRequest": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "description": "Author names the user writing. Ignored when the caller is logged in, the\nnote is then written by the logged in user.",
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "thread_id": {
+                    "description": "ThreadID is the note replied to; empty starts a new thread",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.NoteResolution": {
+            "type": "object",
+            "properties": {
+                "resolved": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.ResendDeliveriesRequest": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor names the on-call engineer. Ignored when the caller is logged in,\nthe action is then run by the logged in user.",
+                    "type": "string"
+                },
+                "from": {
+                    "description": "From and To bound when the delivery last failed; either may be left out",
+                    "type": "string"
+                },
+                "targets": {
+                    "description": "Targets are the delivery targets, e.g. \"webhook:orders\" or \"marketplace:amazon\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RunbookRequest": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "description": "Actor names the on-call engineer. Ignored when the caller is logged in,\nthe action is then run by the logged in user.",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.WebhookRegistration": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "description": "Empty subscribes to every event type",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EventType"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://erp.example.com/hooks/catalog"
+                }
+            }
+        },
+        "handlers.WebhookRequest": {
+            "type": "object",
+            "properties": {
+                "auth": {
+                    "description": "Auth adds a bearer token to the deliveries, see Webhook Authentication",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.WebhookAuth"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "description": "EventTypes are the events delivered; empty subscribes to every product event",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EventType"
+                    }
+                },
+                "secret": {
+                    "description": "Secret signs the deliveries, at least 16 characters; generated when empty",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://erp.example.com/hooks/catalog"
+                }
+            }
+        },
+        "handlers.WebhookState": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "interfaces.BatchResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "warnings": {
+                    "description": "Non-fatal data quality issues",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ValidationWarning"
+                    }
+                }
+            }
+        },
+        "interfaces.CatalogCloneRequest": {
+            "type": "object",
+            "properties": {
+                "anonymize_prices": {
+                    "type": "boolean"
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.CatalogFilter"
+                },
+                "preserve_ids": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.CatalogImportRequest": {
+            "type": "object",
+            "properties": {
+                "anonymize_prices": {
+                    "type": "boolean"
+                },
+                "catalog": {
+                    "$ref": "#/definitions/models.CatalogExport"
+                },
+                "preserve_ids": {
+                    "description": "PreserveIDs keeps the source IDs: products that exist here are updated,\nthe others are created with the same ID. Otherwise every product is\ncreated with a new ID.",
+                    "type": "boolean"
+                },
+                "source": {
+                    "description": "Source names the environment the catalog came from, for the job record",
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.CheckResult": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "interfaces.ConsumerLag": {
+            "type": "object",
+            "properties": {
+                "committed_sequence": {
+                    "type": "integer"
+                },
+                "consumer": {
+                    "type": "string"
+                },
+                "in_flight": {
+                    "type": "integer"
+                },
+                "lag": {
+                    "type": "integer"
+                },
+                "latest_sequence": {
+                    "type": "integer"
+                },
+                "queued": {
+                    "description": "Deliveries waiting for the rate limit",
+                    "type": "integer"
+                },
+                "rate_limit": {
+                    "description": "Delivery limit in events per second, 0 when unlimited",
+                    "type": "number"
+                }
+            }
+        },
+        "interfaces.DeprecationUsage": {
+            "type": "object",
+            "properties": {
+                "clients": {
+                    "description": "Requests per caller (principal subject, or user agent)",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "field": {
+                    "description": "Deprecated field of the routes' responses; empty when the whole routes are deprecated",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_used": {
+                    "type": "string"
+                },
+                "replacement": {
+                    "type": "string"
+                },
+                "requests": {
+                    "description": "Requests since the process started",
+                    "type": "integer"
+                },
+                "routes": {
+                    "description": "Method and path template, e.g. \"PUT /products/{id}\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "since": {
+                    "type": "string"
+                },
+                "sunset": {
+                    "description": "When the route or field will be removed, if decided",
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.Diagnostics": {
+            "type": "object",
+            "properties": {
+                "backends": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "build": {
+                    "$ref": "#/definitions/models.BuildInfo"
+                },
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.CheckResult"
+                    }
+                },
+                "config": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "errors": {
+                    "description": "Response errors over recent windows",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.ErrorRateSummary"
+                    }
+                },
+                "features": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "uptime": {
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.EditClaim": {
+            "type": "object",
+            "properties": {
+                "others": {
+                    "description": "Others are the other sessions on the product, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EditSession"
+                    }
+                },
+                "session": {
+                    "$ref": "#/definitions/models.EditSession"
+                },
+                "version": {
+                    "description": "Version is the product's current version. An update based on an older\nversion will fail with a conflict.",
+                    "type": "integer"
+                }
+            }
+        },
+        "interfaces.EditedProduct": {
+            "type": "object",
+            "properties": {
+                "edit_count": {
+                    "type": "integer"
+                },
+                "last_edited_at": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.ErrorRateSummary": {
+            "type": "object",
+            "properties": {
+                "by_status": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "client_errors": {
+                    "type": "integer"
+                },
+                "error_rate": {
+                    "type": "number"
+                },
+                "server_errors": {
+                    "type": "integer"
+                },
+                "total_requests": {
+                    "type": "integer"
+                },
+                "window": {
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.ForecastInputs": {
+            "type": "object",
+            "properties": {
+                "ledger": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LedgerEntry"
+                    }
+                },
+                "velocities": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SalesVelocity"
+                    }
+                }
+            }
+        },
+        "interfaces.ForecastInputsResult": {
+            "type": "object",
+            "properties": {
+                "ledger": {
+                    "type": "integer"
+                },
+                "velocities": {
+                    "type": "integer"
+                }
+            }
+        },
+        "interfaces.ImportCellError": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.ImportMode": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update"
+            ],
+            "x-enum-varnames": [
+                "ImportCreate",
+                "ImportUpdate"
+            ]
+        },
+        "interfaces.ImportRequest": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "description": "Columns declares the type (\"number\" or \"date\") of record columns that are\nnormalized before the mapping runs, e.g. {\"price\": \"number\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "locale": {
+                    "description": "Locale selects how the number and date columns are written, e.g. \"de-DE\"\nfor \"1.299,50\" and \"31.03.2024\"",
+                    "type": "string"
+                },
+                "mapping": {
+                    "description": "Mapping maps target product fields to transformation expressions,\ne.g. {\"sku\": \"{{upper .sku}}\", \"prices.SEK\": \"{{mul .price_eur 11.5}}\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "mode": {
+                    "description": "Defaults to create",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/interfaces.ImportMode"
+                        }
+                    ]
+                },
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "source": {
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.ImportResult": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "description": "Not set for dry runs",
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.ImportRowResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "interfaces.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "cells": {
+                    "description": "Cells that could not be normalized",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.ImportCellError"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "product": {
+                    "description": "Set for dry runs",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    ]
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ValidationWarning"
+                    }
+                }
+            }
+        },
+        "interfaces.JobRollbackResult": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "$ref": "#/definitions/models.Job"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/interfaces.BatchResult"
+                    }
+                }
+            }
+        },
+        "interfaces.JobStatusSummary": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Job"
+                    }
+                }
+            }
+        },
+        "interfaces.ProductSyncStatus": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "boolean"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SyncStatus"
+                    }
+                },
+                "version": {
+                    "description": "Version is the product's current version; targets with a lower synced_version are behind",
+                    "type": "integer"
+                }
+            }
+        },
+        "interfaces.ReprocessRequest": {
+            "type": "object",
+            "properties": {
+                "consumer": {
+                    "description": "Consumer is the internal subscriber to deliver to, e.g. \"marketplaces\".\nEmpty delivers to every tracked consumer.",
+                    "type": "string"
+                },
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rate": {
+                    "description": "Rate is the number of events delivered per second",
+                    "type": "number"
+                },
+                "sku_prefix": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                },
+                "updated_since": {
+                    "type": "string"
+                }
+            }
+        },
+        "interfaces.RouteLatency": {
+            "type": "object",
+            "properties": {
+                "breached": {
+                    "description": "p95 is over the latency budget",
+                    "type": "boolean"
+                },
+                "budget_ms": {
+                    "type": "number"
+                },
+                "burn_rates": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "over_budget": {
+                    "type": "integer"
+                },
+                "p95_ms": {
+                    "type": "number"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "route": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "number"
+                }
+            }
+        },
+        "interfaces.WebSocketSummary": {
+            "type": "object",
+            "properties": {
+                "active_clients": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.APIError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "HTTP status of the response",
+                    "type": "integer",
+                    "example": 404
+                },
+                "error_code": {
+                    "description": "Machine-readable error code",
+                    "type": "string",
+                    "example": "product_not_found"
+                },
+                "errors": {
+                    "description": "The failing fields of a validation error",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Product not found"
+                }
+            }
+        },
+        "models.Allocation": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AllocationLine"
+                    }
+                },
+                "market": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "shipments": {
+                    "description": "Number of locations the order ships from",
+                    "type": "integer"
+                },
+                "strategy": {
+                    "$ref": "#/definitions/models.AllocationStrategy"
+                },
+                "version": {
+                    "description": "Version of the product once the stock is reserved",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AllocationItem": {
+            "type": "object",
+            "properties": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "variant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AllocationLine": {
+            "type": "object",
+            "properties": {
+                "location_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "variant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AllocationPolicy": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "description": "Sales channel, e.g. \"web\" or \"pos\"; empty for every channel of the market",
+                    "type": "string"
+                },
+                "default": {
+                    "description": "Set when no policy is configured and the default applies",
+                    "type": "boolean"
+                },
+                "locations": {
+                    "description": "Locations that may fulfil orders, in priority order; every stocked location when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FulfillmentLocation"
+                    }
+                },
+                "market": {
+                    "type": "string"
+                },
+                "split_shipments": {
+                    "description": "Whether one order may ship from several locations",
+                    "type": "boolean"
+                },
+                "strategy": {
+                    "$ref": "#/definitions/models.AllocationStrategy"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AllocationRequest": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "destination": {
+                    "description": "Where the order ships to; ranks locations for the nearest strategy",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Coordinates"
+                        }
+                    ]
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AllocationItem"
+                    }
+                },
+                "market": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AllocationStrategy": {
+            "type": "string",
+            "enum": [
+                "priority",
+                "nearest"
+            ],
+            "x-enum-varnames": [
+                "AllocationPriority",
+                "AllocationNearest"
+            ]
+        },
+        "models.AttributeMigration": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "description": "Attribute to migrate",
+                    "type": "string"
+                },
+                "new_key": {
+                    "description": "New name for the attribute, if it is renamed",
+                    "type": "string"
+                },
+                "values": {
+                    "description": "Old value to new value; values not listed are kept",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.AttributeRow": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "shared": {
+                    "type": "boolean"
+                },
+                "values": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "models.Availability": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "split_shipments": {
+                    "type": "boolean"
+                },
+                "strategy": {
+                    "$ref": "#/definitions/models.AllocationStrategy"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.VariantAvailability"
+                    }
+                }
+            }
+        },
+        "models.BlockedProduct": {
+            "type": "object",
+            "properties": {
+                "blockers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LaunchBlocker"
+                    }
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BuildInfo": {
+            "type": "object",
+            "properties": {
+                "commit": {
+                    "type": "string"
+                },
+                "commit_time": {
+                    "type": "string"
+                },
+                "go_version": {
+                    "type": "string"
+                },
+                "modified": {
+                    "description": "Built from a tree with uncommitted changes",
+                    "type": "boolean"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Bundle": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BundleComponent"
+                    }
+                },
+                "discount_percent": {
+                    "description": "Off the component sum with percentage_discount",
+                    "type": "number"
+                },
+                "price_strategy": {
+                    "description": "sum (default), fixed or percentage_discount",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BundlePriceStrategy"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.BundleComponent": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "Units in one bundle",
+                    "type": "integer"
+                },
+                "variant_id": {
+                    "description": "Required when the product has variants",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BundleComponentInfo": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "False when the product or variant no longer exists",
+                    "type": "boolean"
+                },
+                "in_stock": {
+                    "description": "Units across locations; null when untracked or backordered",
+                    "type": "integer"
+                },
+                "prices": {
+                    "description": "Unit price per currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Price"
+                    }
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "description": "Units in one bundle",
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "variant_id": {
+                    "description": "Required when the product has variants",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BundlePriceStrategy": {
+            "type": "string",
+            "enum": [
+                "sum",
+                "fixed",
+                "percentage_discount"
+            ],
+            "x-enum-varnames": [
+                "BundlePriceSum",
+                "BundlePriceFixed",
+                "BundlePriceDiscount"
+            ]
+        },
+        "models.BundleSummary": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Bundles the component stock covers; null when no component tracks stock",
+                    "type": "integer"
+                },
+                "component_sum": {
+                    "description": "Sum of the component prices per currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Price"
+                    }
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BundleComponentInfo"
+                    }
+                },
+                "price_strategy": {
+                    "$ref": "#/definitions/models.BundlePriceStrategy"
+                },
+                "prices": {
+                    "description": "Bundle price per currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Price"
+                    }
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CatalogExport": {
+            "type": "object",
+            "properties": {
+                "exported_at": {
+                    "type": "string"
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.CatalogFilter"
+                },
+                "prices_anonymized": {
+                    "type": "boolean"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Product"
+                    }
+                }
+            }
+        },
+        "models.CatalogFilter": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "market": {
+                    "description": "Only products with metadata for the market",
+                    "type": "string"
+                },
+                "sku_prefix": {
+                    "type": "string"
+                },
+                "tag": {
+                    "description": "Only products with the tag",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "names": {
+                    "description": "Per market, e.g. {\"SE\": \"T-shirts\", \"DE\": \"T-Shirts\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Order among the siblings, lowest first",
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "t-shirts"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Certification": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "reference": {
+                    "description": "Certificate or declaration number",
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ComparedProduct": {
+            "type": "object",
+            "properties": {
+                "base_title": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "markets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sku": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Compliance": {
+            "type": "object",
+            "properties": {
+                "certifications": {
+                    "description": "Certifications the product holds",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Certification"
+                    }
+                },
+                "energy_label": {
+                    "description": "Set for products that carry an energy label",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.EnergyLabel"
+                        }
+                    ]
+                },
+                "hazard_class": {
+                    "description": "UN dangerous goods class, e.g. \"3\" or \"2.1\"",
+                    "type": "string"
+                },
+                "minimum_age": {
+                    "description": "Buyers must be at least this old, e.g. 18 for alcohol",
+                    "type": "integer"
+                },
+                "required_certifications": {
+                    "description": "Certifications per market, e.g. {\"DE\": [\"CE\", \"GS\"]}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "safety_data_sheet_url": {
+                    "description": "Required for hazardous goods",
+                    "type": "string"
+                },
+                "un_number": {
+                    "description": "UN number of the hazardous substance, e.g. \"UN1263\"",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Coordinates": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.DeliveryError": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EditSession": {
+            "type": "object",
+            "properties": {
+                "claimed_at": {
+                    "type": "string"
+                },
+                "editor": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EnergyLabel": {
+            "type": "object",
+            "properties": {
+                "class": {
+                    "description": "A to G",
+                    "type": "string"
+                },
+                "label_url": {
+                    "type": "string"
+                },
+                "registration_id": {
+                    "description": "EPREL registration number",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "entity_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "description": "Batch job that caused the event, if any",
+                    "type": "string"
+                },
+                "schema_version": {
+                    "description": "SchemaVersion is the event format; events stored before it was recorded are version 1",
+                    "type": "integer"
+                },
+                "sequence": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.EventType"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EventSchemaCompatibility": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "description": "Version of the events the build publishes",
+                    "type": "integer"
+                },
+                "supported": {
+                    "description": "Versions the build reads",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.EventType": {
+            "type": "string",
+            "enum": [
+                "product.created",
+                "product.updated",
+                "product.deleted",
+                "product.restored",
+                "stock.changed",
+                "price.changed",
+                "category.created",
+                "category.updated",
+                "category.deleted"
+            ],
+            "x-enum-varnames": [
+                "EventProductCreated",
+                "EventProductUpdated",
+                "EventProductDeleted",
+                "EventProductRestored",
+                "EventStockChanged",
+                "EventPriceChanged",
+                "EventCategoryCreated",
+                "EventCategoryUpdated",
+                "EventCategoryDeleted"
+            ]
+        },
+        "models.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Facets": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Variant attribute values by attribute, e.g. \"size\"",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/models.FacetCount"
+                        }
+                    }
+                },
+                "currencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                },
+                "currency": {
+                    "description": "PriceBuckets count the product prices in Currency, when the\nrequest has a currency",
+                    "type": "string"
+                },
+                "markets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                },
+                "price_buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceBucket"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "JSON path of the field",
+                    "type": "string",
+                    "example": "variants[0].sku"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "variants[0].sku is required"
+                },
+                "tag": {
+                    "description": "The rule the field broke",
+                    "type": "string",
+                    "example": "required"
+                }
+            }
+        },
+        "models.FulfillmentLocation": {
+            "type": "object",
+            "properties": {
+                "coordinates": {
+                    "description": "Required by the nearest strategy",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Coordinates"
+                        }
+                    ]
+                },
+                "id": {
+                    "description": "Matches Stock.LocationID",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Image": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "alt_text": {
+                    "description": "Default alt text",
+                    "type": "string"
+                },
+                "alt_texts": {
+                    "description": "Per market, e.g. {\"SE\": \"Blå skjorta\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "content_type": {
+                    "description": "Set for uploaded images",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Generated when the image is saved",
+                    "type": "string"
+                },
+                "storage_key": {
+                    "description": "Key of an uploaded image in the asset storage",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Job": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rollback_of": {
+                    "description": "Job reverted by a rollback job",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "Where an import came from, e.g. a file name",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.JobStatus"
+                },
+                "succeeded": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.JobType"
+                }
+            }
+        },
+        "models.JobStatus": {
+            "type": "string",
+            "enum": [
+                "running",
+                "completed",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "JobStatusRunning",
+                "JobStatusCompleted",
+                "JobStatusFailed"
+            ]
+        },
+        "models.JobType": {
+            "type": "string",
+            "enum": [
+                "tags.update",
+                "batch.create",
+                "batch.update",
+                "batch.delete",
+                "job.rollback",
+                "import",
+                "attribute.migration",
+                "reprocess",
+                "catalog.import",
+                "text.replace",
+                "metadata.copy"
+            ],
+            "x-enum-varnames": [
+                "JobTagUpdate",
+                "JobBatchCreate",
+                "JobBatchUpdate",
+                "JobBatchDelete",
+                "JobRollback",
+                "JobImport",
+                "JobAttributeMigration",
+                "JobReprocess",
+                "JobCatalogImport",
+                "JobTextReplace",
+                "JobMetadataCopy"
+            ]
+        },
+        "models.LaunchBlocker": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/models.LaunchBlockerCode"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LaunchBlockerCode": {
+            "type": "string",
+            "enum": [
+                "missing_compliance",
+                "missing_translation",
+                "missing_price",
+                "no_stock",
+                "no_image"
+            ],
+            "x-enum-varnames": [
+                "BlockerMissingCompliance",
+                "BlockerMissingTranslation",
+                "BlockerMissingPrice",
+                "BlockerNoStock",
+                "BlockerNoImage"
+            ]
+        },
+        "models.LaunchChecklist": {
+            "type": "object",
+            "properties": {
+                "blocked": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BlockedProduct"
+                    }
+                },
+                "blocked_count": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "ready_count": {
+                    "type": "integer"
+                },
+                "total_products": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.LedgerEntry": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "delta": {
+                    "type": "integer"
+                },
+                "location_id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "e.g. sale, return, restock",
+                    "type": "string"
+                },
+                "variant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LocationQuantity": {
+            "type": "object",
+            "properties": {
+                "backorder": {
+                    "type": "boolean"
+                },
+                "location_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MarketMetadata": {
+            "type": "object",
+            "required": [
+                "market",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Merchandising": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string"
+                },
+                "pins": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Pin"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MetadataCopy": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "description": "Fields to copy; all metadata fields when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.CatalogFilter"
+                },
+                "only_empty": {
+                    "description": "Only fill fields that are empty in the target market",
+                    "type": "boolean"
+                },
+                "source": {
+                    "description": "Market to copy from, e.g. SE",
+                    "type": "string"
+                },
+                "targets": {
+                    "description": "Markets to copy to, e.g. [\"FI\"]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NoteThread": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "replies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductNote"
+                    }
+                },
+                "resolved": {
+                    "description": "Set on a thread once its question is settled",
+                    "type": "boolean"
+                },
+                "thread_id": {
+                    "description": "The note this one replies to; empty for the note starting a thread",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Pin": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "description": "1-based slot in the listing",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Price": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "currency": {
+                    "description": "ISO 4217 code",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PriceList": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "SEK"
+                },
+                "customer_group": {
+                    "type": "string",
+                    "example": "b2b"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "market": {
+                    "type": "string",
+                    "example": "SE"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScheduledPrice"
+                    }
+                },
+                "priority": {
+                    "description": "Higher wins between lists that are equally specific",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceRow": {
+            "type": "object",
+            "properties": {
+                "amounts": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "max_amounts": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "shared": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Product": {
+            "type": "object",
+            "required": [
+                "base_title",
+                "id",
+                "metadata",
+                "prices",
+                "sku"
+            ],
+            "properties": {
+                "base_title": {
+                    "type": "string"
+                },
+                "bundle": {
+                    "description": "Components and pricing of a bundle product",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Bundle"
+                        }
+                    ]
+                },
+                "category_ids": {
+                    "description": "Categories the product is listed in",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "compliance": {
+                    "description": "Age restriction, hazardous goods, energy label and certifications",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Compliance"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "Set while the product is soft-deleted",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "draft": {
+                    "description": "Drafts are hidden from the public API and storefront listings",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "images": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "$ref": "#/definitions/models.Image"
+                    }
+                },
+                "last_hash": {
+                    "description": "Hash of last known state",
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MarketMetadata"
+                    }
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Price"
+                    }
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "sourcing": {
+                    "description": "Supplier and cost, internal only",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Sourcing"
+                        }
+                    ]
+                },
+                "tags": {
+                    "description": "Free-form labels, e.g. \"spring-2025\"",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Variant"
+                    }
+                },
+                "version": {
+                    "description": "Version number for optimistic locking",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductComparison": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AttributeRow"
+                    }
+                },
+                "prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceRow"
+                    }
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ComparedProduct"
+                    }
+                },
+                "stock": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockSummary"
+                    }
+                }
+            }
+        },
+        "models.ProductNote": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "resolved": {
+                    "description": "Set on a thread once its question is settled",
+                    "type": "boolean"
+                },
+                "thread_id": {
+                    "description": "The note this one replies to; empty for the note starting a thread",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductRelation": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Order among the relations of the type, lowest first",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "related_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.RelationType"
+                        }
+                    ],
+                    "example": "cross_sell"
+                }
+            }
+        },
+        "models.ProductSearchResult": {
+            "type": "object",
+            "properties": {
+                "product": {
+                    "$ref": "#/definitions/models.Product"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ProductStockAdjustment": {
+            "type": "object",
+            "required": [
+                "location_id",
+                "product_id",
+                "variant_id"
+            ],
+            "properties": {
+                "delta": {
+                    "type": "integer"
+                },
+                "location_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "variant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductVersion": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "e.g. updated, rolled_back or restored",
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "The event that produced the version",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.EventType"
                         }
+                    ]
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RelatedProduct": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer"
+                },
+                "product": {
+                    "$ref": "#/definitions/models.Product"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.RelationType"
+                }
+            }
+        },
+        "models.RelationType": {
+            "type": "string",
+            "enum": [
+                "related",
+                "upsell",
+                "cross_sell",
+                "bundle",
+                "variant_of"
+            ],
+            "x-enum-varnames": [
+                "RelationRelated",
+                "RelationUpsell",
+                "RelationCrossSell",
+                "RelationBundle",
+                "RelationVariantOf"
+            ]
+        },
+        "models.ResolvedPrice": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "price_list_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "price_list, variant or product",
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                },
+                "variant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RunbookRun": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "params": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "result": {},
+                "started_at": {
+                    "type": "string"
+                },
+                "subject": {
+                    "description": "Subject is what the action ran against: a projection, product or delivery target",
+                    "type": "string"
+                },
+                "succeeded": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.SalesVelocity": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "units_per_day": {
+                    "type": "number"
+                },
+                "variant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ScheduledPrice": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_to": {
+                    "type": "string"
+                },
+                "variant_id": {
+                    "description": "Empty for every variant without its own price",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Sourcing": {
+            "type": "object",
+            "properties": {
+                "costs": {
+                    "description": "Purchase price per currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Price"
+                    }
+                },
+                "supplier": {
+                    "type": "string"
+                },
+                "supplier_sku": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Stock": {
+            "type": "object",
+            "required": [
+                "location_id"
+            ],
+            "properties": {
+                "backorder": {
+                    "type": "boolean"
+                },
+                "location_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockAdjustment": {
+            "type": "object",
+            "required": [
+                "location_id",
+                "variant_id"
+            ],
+            "properties": {
+                "delta": {
+                    "type": "integer"
+                },
+                "location_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "variant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StockLevel": {
+            "type": "object",
+            "required": [
+                "location_id"
+            ],
+            "properties": {
+                "location_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockSummary": {
+            "type": "object",
+            "properties": {
+                "in_stock": {
+                    "type": "boolean"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockoutForecast": {
+            "type": "object",
+            "properties": {
+                "days_of_cover": {
+                    "type": "number"
+                },
+                "forecaster": {
+                    "type": "string"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stockout_at": {
+                    "type": "string"
+                },
+                "units_per_day": {
+                    "type": "number"
+                },
+                "variant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SyncState": {
+            "type": "string",
+            "enum": [
+                "synced",
+                "failed",
+                "skipped",
+                "removed"
+            ],
+            "x-enum-comments": {
+                "SyncStateSkipped": "The product is not part of what the target receives"
+            },
+            "x-enum-varnames": [
+                "SyncStateSynced",
+                "SyncStateFailed",
+                "SyncStateSkipped",
+                "SyncStateRemoved"
+            ]
+        },
+        "models.SyncStatus": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Attempts made for the current version",
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "errors": {
+                    "description": "Most recent delivery errors, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeliveryError"
                     }
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "state": {
+                    "$ref": "#/definitions/models.SyncState"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "synced_version": {
+                    "description": "Last product version the target accepted",
+                    "type": "integer"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Product version of the last attempt",
+                    "type": "integer"
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.ErrorResponse": {
+        },
+        "models.TagUpdate": {
             "type": "object",
             "properties": {
-                "code": {
-                    "type": "integer",
-                    "example": 400
+                "add": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "message": {
-                    "type": "string",
-                    "example": "Invalid request"
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "remove": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tag": {
+                    "type": "string"
                 }
             }
         },
-        "models.APIError": {
+        "models.TagUsage": {
             "type": "object",
             "properties": {
-                "message": {
+                "products": {
+                    "type": "integer"
+                },
+                "tag": {
                     "type": "string"
                 }
             }
         },
-        "models.MarketMetadata": {
+        "models.TextChange": {
             "type": "object",
-            "required": [
-                "market",
-                "title"
-            ],
             "properties": {
-                "description": {
+                "field": {
+                    "description": "e.g. \"base_title\" or \"metadata[SE].keywords\"",
                     "type": "string"
                 },
-                "keywords": {
+                "new": {
                     "type": "string"
                 },
-                "market": {
+                "old": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TextReplacement": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "Preview the changes without writing them",
+                    "type": "boolean"
+                },
+                "fields": {
+                    "description": "Fields to change; all text fields when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filter": {
+                    "$ref": "#/definitions/models.CatalogFilter"
+                },
+                "find": {
                     "type": "string"
                 },
-                "title": {
+                "ignore_case": {
+                    "description": "Match regardless of case",
+                    "type": "boolean"
+                },
+                "regex": {
+                    "description": "Find is a Go regular expression and Replace may use $1 or ${name}",
+                    "type": "boolean"
+                },
+                "replace": {
                     "type": "string"
                 }
             }
         },
-        "models.Price": {
+        "models.TextReplacementDiff": {
             "type": "object",
-            "required": [
-                "amount",
-                "currency"
-            ],
             "properties": {
-                "amount": {
-                    "type": "number",
-                    "minimum": 0
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TextChange"
+                    }
                 },
-                "currency": {
+                "product_id": {
+                    "type": "string"
+                },
+                "sku": {
                     "type": "string"
                 }
             }
         },
-        "models.Product": {
+        "models.TextReplacementPreview": {
             "type": "object",
-            "required": [
-                "base_title",
-                "id",
-                "metadata",
-                "prices",
-                "sku"
-            ],
             "properties": {
-                "base_title": {
-                    "type": "string"
+                "matched": {
+                    "type": "integer"
                 },
-                "created_at": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TextReplacementDiff"
+                    }
+                },
+                "truncated": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ValidationWarning": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/models.ValidationWarningCode"
+                },
+                "field": {
                     "type": "string"
                 },
-                "description": {
+                "message": {
                     "type": "string"
+                }
+            }
+        },
+        "models.ValidationWarningCode": {
+            "type": "string",
+            "enum": [
+                "short_description",
+                "missing_keywords",
+                "missing_alt_text"
+            ],
+            "x-enum-varnames": [
+                "WarningShortDescription",
+                "WarningMissingKeywords",
+                "WarningMissingAltText"
+            ]
+        },
+        "models.Variant": {
+            "type": "object",
+            "required": [
+                "attributes",
+                "id",
+                "sku"
+            ],
+            "properties": {
+                "attributes": {
+                    "description": "e.g. {\"size\": \"XL\", \"color\": \"blue\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
                 },
                 "id": {
                     "type": "string"
                 },
-                "last_hash": {
-                    "description": "Hash of last known state",
+                "prices": {
+                    "description": "Overrides of the product price per currency",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Price"
+                    }
+                },
+                "sku": {
                     "type": "string"
                 },
-                "metadata": {
+                "stock": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.MarketMetadata"
+                        "$ref": "#/definitions/models.Stock"
                     }
+                }
+            }
+        },
+        "models.VariantAvailability": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Total of the eligible locations, or the best single one without split shipments",
+                    "type": "integer"
                 },
-                "prices": {
+                "backorder": {
+                    "description": "An eligible location takes orders beyond its stock",
+                    "type": "boolean"
+                },
+                "locations": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Price"
+                        "$ref": "#/definitions/models.LocationQuantity"
                     }
                 },
                 "sku": {
                     "type": "string"
                 },
-                "updated_at": {
+                "variant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.VariantStock": {
+            "type": "object",
+            "properties": {
+                "product_id": {
                     "type": "string"
                 },
-                "variants": {
+                "stock": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Variant"
+                        "$ref": "#/definitions/models.Stock"
                     }
                 },
+                "variant_id": {
+                    "type": "string"
+                },
                 "version": {
-                    "description": "Version number for optimistic locking",
+                    "description": "Product version the stock was read at",
                     "type": "integer"
                 }
             }
         },
-        "models.Stock": {
+        "models.VersionInfo": {
             "type": "object",
-            "required": [
-                "location_id"
-            ],
             "properties": {
-                "location_id": {
+                "commit": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer",
-                    "minimum": 0
+                "commit_time": {
+                    "type": "string"
+                },
+                "event_schema": {
+                    "$ref": "#/definitions/models.EventSchemaCompatibility"
+                },
+                "go_version": {
+                    "type": "string"
+                },
+                "modified": {
+                    "description": "Built from a tree with uncommitted changes",
+                    "type": "boolean"
+                },
+                "version": {
+                    "type": "string"
                 }
             }
         },
-        "models.Variant": {
+        "models.WebhookAuth": {
             "type": "object",
-            "required": [
-                "attributes",
-                "id",
-                "sku"
-            ],
             "properties": {
-                "attributes": {
-                    "description": "e.g. {\"size\": \"XL\", \"color\": \"blue\"}",
-                    "type": "object",
-                    "additionalProperties": {
+                "audience": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
                         "type": "string"
                     }
                 },
+                "token_url": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "attempt": {
+                    "type": "integer"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "endpoint_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "$ref": "#/definitions/models.EventType"
+                },
                 "id": {
                     "type": "string"
                 },
-                "sku": {
+                "next_retry": {
+                    "description": "Set when the attempt failed and will be retried",
                     "type": "string"
                 },
-                "stock": {
+                "product_id": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "description": "0 when no response was received",
+                    "type": "integer"
+                },
+                "succeeded": {
+                    "type": "boolean"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WebhookEndpoint": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "description": "Empty subscribes to every event type",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.Stock"
+                        "$ref": "#/definitions/models.EventType"
                     }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://erp.example.com/hooks/catalog"
                 }
             }
         }
@@ -595,8 +8456,8 @@ var SwaggerInfo = &swag.Spec{
 	Description:      "A robust and scalable API for product management in e-commerce systems",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
-	LeftDelim:        "{{",
-	RightDelim:       "}}",
+	LeftDelim:        "[[",
+	RightDelim:       "]]",
 }
 
 func init() {
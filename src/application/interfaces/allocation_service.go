@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// AllocationService defines the interface for choosing the stock locations that fulfil orders
+type AllocationService interface {
+	// ListPolicies returns the policies configured for a market and its channels
+	ListPolicies(market string) ([]*models.AllocationPolicy, error)
+	// GetPolicy returns the policy that applies to a channel of a market: the
+	// channel's own, else the market's, else the default policy
+	GetPolicy(market, channel string) (*models.AllocationPolicy, error)
+	// SetPolicy creates or replaces the policy of the policy's market and channel
+	SetPolicy(policy *models.AllocationPolicy) (*models.AllocationPolicy, error)
+	// DeletePolicy removes a configured policy, returning ErrAllocationPolicyNotFound if there is none
+	DeletePolicy(market, channel string) error
+
+	// Availability reports how much of each variant of a product one order in the market and channel can get
+	Availability(ctx context.Context, productID, market, channel string) (*models.Availability, error)
+	// Reserve allocates the requested items under the applicable policy and
+	// reserves them by decrementing the chosen locations' stock
+	Reserve(ctx context.Context, productID string, request *models.AllocationRequest) (*models.Allocation, error)
+}
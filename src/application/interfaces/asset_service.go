@@ -0,0 +1,42 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// AssetStorage stores the binaries of uploaded assets, e.g. on local disk or
+// in an S3 bucket. Implementations are plugged in by name, see the media
+// package.
+type AssetStorage interface {
+	Name() string
+	// Put stores a binary under a key and returns the URL it is served from
+	Put(key, contentType string, body io.Reader) (string, error)
+	// Delete removes a stored binary; removing a missing key is not an error
+	Delete(key string) error
+}
+
+// AssetUpload is a binary uploaded as a product image
+type AssetUpload struct {
+	Filename    string
+	ContentType string
+	Body        io.Reader
+	// Image holds the alt texts of the uploaded image
+	Image models.Image
+}
+
+// AssetService defines the interface for managing the images of products
+type AssetService interface {
+	// AttachImage appends an image served from an external URL
+	AttachImage(ctx context.Context, productID string, image *models.Image) (*models.Product, error)
+	// UploadImage stores an uploaded binary and appends it as an image
+	UploadImage(ctx context.Context, productID string, upload *AssetUpload) (*models.Product, error)
+	// DetachImage removes an image, and its stored binary if it was uploaded,
+	// or fails with ErrImageNotFound
+	DetachImage(ctx context.Context, productID, imageID string) (*models.Product, error)
+	// ReorderImages puts the images in the order of the IDs, which must list
+	// every image of the product once
+	ReorderImages(ctx context.Context, productID string, imageIDs []string) (*models.Product, error)
+}
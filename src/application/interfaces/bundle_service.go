@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// BundleService defines the interface for the computed prices and stock of bundle products
+type BundleService interface {
+	// GetBundle returns the price and availability of a bundle computed from
+	// its components, or fails with ErrNotABundle
+	GetBundle(ctx context.Context, productID string) (*models.BundleSummary, error)
+}
@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// CatalogImportRequest writes an exported catalog into this environment
+type CatalogImportRequest struct {
+	Catalog *models.CatalogExport `json:"catalog"`
+	// PreserveIDs keeps the source IDs: products that exist here are updated,
+	// the others are created with the same ID. Otherwise every product is
+	// created with a new ID.
+	PreserveIDs     bool `json:"preserve_ids,omitempty"`
+	AnonymizePrices bool `json:"anonymize_prices,omitempty"`
+	// Source names the environment the catalog came from, for the job record
+	Source string `json:"source,omitempty"`
+}
+
+// CatalogCloneRequest copies the filtered catalog of a configured environment into this one
+type CatalogCloneRequest struct {
+	Source          string               `json:"source"`
+	Filter          models.CatalogFilter `json:"filter"`
+	PreserveIDs     bool                 `json:"preserve_ids,omitempty"`
+	AnonymizePrices bool                 `json:"anonymize_prices,omitempty"`
+}
+
+// CatalogSource fetches a catalog export from another environment
+type CatalogSource interface {
+	FetchCatalog(filter models.CatalogFilter, anonymizePrices bool) (*models.CatalogExport, error)
+}
+
+// CatalogCloneService defines the interface for cloning catalogs between environments
+type CatalogCloneService interface {
+	// Sources returns the names of the environments catalogs can be cloned from
+	Sources() []string
+	// CloneCatalog fetches the export from the source and starts importing it
+	CloneCatalog(ctx context.Context, req *CatalogCloneRequest) (*models.Job, error)
+}
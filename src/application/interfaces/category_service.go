@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// CategoryService defines the interface for the category taxonomy and the
+// products listed in it
+type CategoryService interface {
+	// ListCategories returns every category, ordered by ID
+	ListCategories() ([]*models.Category, error)
+	// CategoryTree returns the categories nested under their parents, roots first
+	CategoryTree() ([]*models.CategoryNode, error)
+	// GetCategory returns a category, or ErrCategoryNotFound
+	GetCategory(id string) (*models.Category, error)
+	// CreateCategory validates and stores a new category under a generated ID
+	CreateCategory(category *models.Category) (*models.Category, error)
+	// UpdateCategory replaces a stored category, which may move it to another
+	// parent, or fails with ErrCategoryNotFound
+	UpdateCategory(category *models.Category) (*models.Category, error)
+	// DeleteCategory removes a category without subcategories or products,
+	// or fails with ErrCategoryInUse
+	DeleteCategory(ctx context.Context, id string) error
+
+	// AssignCategories replaces the categories of a product after checking
+	// that they exist
+	AssignCategories(ctx context.Context, productID string, categoryIDs []string) (*models.Product, error)
+	// ListProducts returns a page of the products in a category, and in its
+	// subcategories when includeSubcategories is set
+	ListProducts(ctx context.Context, categoryID string, includeSubcategories bool, page, pageSize int) ([]*models.Product, int, error)
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// ExchangeRateProvider fetches the current exchange rates, e.g. from static
+// configuration or a central bank feed. Implementations are plugged in by
+// name, see the currency package.
+type ExchangeRateProvider interface {
+	Name() string
+	Rates() (*models.ExchangeRates, error)
+}
+
+// CurrencyService defines the interface for converting prices between currencies
+type CurrencyService interface {
+	// Rates returns the current exchange rates, cached between provider fetches
+	Rates() (*models.ExchangeRates, error)
+	// Convert converts an amount between currencies, rounded to the minor units of the target
+	Convert(amount float64, from, to string) (float64, error)
+	// DisplayProduct returns the product with its prices shown in the currency
+	DisplayProduct(product *models.Product, currency string) (*models.DisplayedProduct, error)
+}
@@ -0,0 +1,129 @@
+package interfaces
+
+import (
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// RequestStatsProvider exposes aggregated HTTP response counts
+type RequestStatsProvider interface {
+	Counts(since time.Time) map[int]int
+}
+
+// ClientCounter reports the number of connected real-time clients
+type ClientCounter interface {
+	ClientCount() int
+}
+
+// EditedProduct summarizes recent edit activity for a product
+type EditedProduct struct {
+	ProductID    string    `json:"product_id"`
+	SKU          string    `json:"sku"`
+	Title        string    `json:"title"`
+	EditCount    int       `json:"edit_count"`
+	LastEditedAt time.Time `json:"last_edited_at"`
+}
+
+// ProductSummary describes the latest known state of a recently changed product
+type ProductSummary struct {
+	ProductID string    `json:"product_id"`
+	SKU       string    `json:"sku"`
+	Title     string    `json:"title"`
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ErrorRateSummary describes failed HTTP requests in a time window.
+// ErrorRate is the share of responses that were server errors (5xx).
+type ErrorRateSummary struct {
+	Window        string      `json:"window"`
+	TotalRequests int         `json:"total_requests"`
+	ClientErrors  int         `json:"client_errors"`
+	ServerErrors  int         `json:"server_errors"`
+	ErrorRate     float64     `json:"error_rate"`
+	ByStatus      map[int]int `json:"by_status"`
+}
+
+// WebSocketSummary describes the current WebSocket connections
+type WebSocketSummary struct {
+	ActiveClients int `json:"active_clients"`
+}
+
+// JobStatusSummary lists recent jobs together with counts per status
+type JobStatusSummary struct {
+	Counts map[models.JobStatus]int `json:"counts"`
+	Jobs   []*models.Job            `json:"jobs"`
+}
+
+// DashboardService aggregates data for the admin dashboard widgets
+type DashboardService interface {
+	// Reset drops the activity feed and edit statistics built from events
+	Projection
+	RecentEvents(limit int) []*models.Event
+	TopEditedProducts(limit int) []*EditedProduct
+	RecentlyUpdatedProducts(limit int) []*ProductSummary
+	ErrorRate(window time.Duration) *ErrorRateSummary
+	WebSocketClients() *WebSocketSummary
+	JobStatuses(limit int) (*JobStatusSummary, error)
+	ConsumerLags() []*ConsumerLag
+	LatencyObjectives() []*RouteLatency
+	DeprecationUsage() []*DeprecationUsage
+}
+
+// ConsumerLag describes how far an internal event subscriber is behind the
+// latest published sequence
+type ConsumerLag struct {
+	Consumer  string  `json:"consumer"`
+	Committed int64   `json:"committed_sequence"`
+	Latest    int64   `json:"latest_sequence"`
+	Lag       int64   `json:"lag"`
+	InFlight  int     `json:"in_flight"`
+	RateLimit float64 `json:"rate_limit,omitempty"` // Delivery limit in events per second, 0 when unlimited
+	Queued    int     `json:"queued"`               // Deliveries waiting for the rate limit
+}
+
+// ConsumerLagProvider reports the offsets of internal event subscribers
+type ConsumerLagProvider interface {
+	ConsumerLags() []*ConsumerLag
+}
+
+// RouteLatency describes a route's latency objective and how fast it is using up
+// its error budget. Requests, OverBudget and P95Ms cover the last 5 minutes;
+// BurnRates are keyed by window ("5m0s", "1h0m0s"), where 1 means the budget
+// would be used up exactly at the objective's target.
+type RouteLatency struct {
+	Route      string             `json:"route"`
+	BudgetMs   float64            `json:"budget_ms"`
+	Target     float64            `json:"target"`
+	Requests   int                `json:"requests"`
+	OverBudget int                `json:"over_budget"`
+	P95Ms      float64            `json:"p95_ms"`
+	BurnRates  map[string]float64 `json:"burn_rates"`
+	Breached   bool               `json:"breached"` // p95 is over the latency budget
+}
+
+// LatencyObjectiveProvider reports per-route latency against the configured objectives
+type LatencyObjectiveProvider interface {
+	LatencyObjectives() []*RouteLatency
+}
+
+// DeprecationUsage describes deprecated routes or a response field and how much
+// it is still used, so clients can be contacted before it is removed
+type DeprecationUsage struct {
+	ID          string           `json:"id"`
+	Routes      []string         `json:"routes"`          // Method and path template, e.g. "PUT /products/{id}"
+	Field       string           `json:"field,omitempty"` // Deprecated field of the routes' responses; empty when the whole routes are deprecated
+	Description string           `json:"description"`
+	Replacement string           `json:"replacement,omitempty"`
+	Since       time.Time        `json:"since"`
+	Sunset      *time.Time       `json:"sunset,omitempty"` // When the route or field will be removed, if decided
+	Requests    int64            `json:"requests"`         // Requests since the process started
+	LastUsed    *time.Time       `json:"last_used,omitempty"`
+	Clients     map[string]int64 `json:"clients"` // Requests per caller (principal subject, or user agent)
+}
+
+// DeprecationUsageProvider reports the usage of deprecated routes and fields
+type DeprecationUsageProvider interface {
+	DeprecationUsage() []*DeprecationUsage
+}
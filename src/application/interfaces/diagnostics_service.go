@@ -0,0 +1,57 @@
+package interfaces
+
+import (
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Check is a startup check of a dependency, e.g. that the repository answers
+type Check struct {
+	Name string
+	Run  func() error
+}
+
+// CheckResult is the outcome of a startup check
+type CheckResult struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// ErrorRateProvider summarizes response errors over a window
+type ErrorRateProvider interface {
+	ErrorRate(window time.Duration) *ErrorRateSummary
+}
+
+// DiagnosticsConfig describes how the service was started
+type DiagnosticsConfig struct {
+	Build *models.BuildInfo
+	// Backends names the implementation behind each component, e.g. "repository": "memory"
+	Backends map[string]string
+	// Features tells whether each optional feature is enabled, e.g. "admin_login"
+	Features map[string]bool
+	// Config is the configuration read from the environment. Secrets are redacted before it is reported.
+	Config map[string]string
+	Checks []Check
+}
+
+// Diagnostics lets operators verify a deployment without reading logs
+type Diagnostics struct {
+	StartedAt time.Time           `json:"started_at"`
+	Uptime    string              `json:"uptime"`
+	Build     *models.BuildInfo   `json:"build"`
+	Backends  map[string]string   `json:"backends"`
+	Features  map[string]bool     `json:"features"`
+	Config    map[string]string   `json:"config"`
+	Checks    []*CheckResult      `json:"checks"`
+	Errors    []*ErrorRateSummary `json:"errors"` // Response errors over recent windows
+}
+
+// DiagnosticsService defines the interface for startup self-checks and diagnostics
+type DiagnosticsService interface {
+	// SelfCheck runs the startup checks and keeps the results for Diagnostics
+	SelfCheck() []*CheckResult
+	Diagnostics() *Diagnostics
+}
@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// EditClaim is an edit session together with what the editor needs to know
+// about concurrent edits
+type EditClaim struct {
+	Session *models.EditSession `json:"session"`
+	// Version is the product's current version. An update based on an older
+	// version will fail with a conflict.
+	Version int64 `json:"version"`
+	// Others are the other sessions on the product, oldest first
+	Others []*models.EditSession `json:"others"`
+}
+
+// EditSessionService defines the interface for claiming products for editing
+type EditSessionService interface {
+	// Claim starts a session for editor that expires after ttl unless renewed.
+	// A ttl of zero uses the default.
+	Claim(ctx context.Context, productID, editor string, ttl time.Duration) (*EditClaim, error)
+	// Renew extends a session, e.g. on a heartbeat from the edit form
+	Renew(ctx context.Context, productID, sessionID string, ttl time.Duration) (*EditClaim, error)
+	// Release ends a session when the editor saves or leaves
+	Release(productID, sessionID string) error
+	// List returns the live sessions on a product, oldest first
+	List(ctx context.Context, productID string) ([]*models.EditSession, error)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// FacetService defines the interface for the filter counts of a product listing
+type FacetService interface {
+	// Facets counts the products matching the filter per market, currency,
+	// variant attribute value and price bucket
+	Facets(ctx context.Context, filter models.ProductFilter, request models.FacetRequest) (*models.Facets, error)
+}
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// ForecastInputs is a batch of forecasting inputs pushed by an external system
+type ForecastInputs struct {
+	Ledger     []models.LedgerEntry   `json:"ledger,omitempty"`
+	Velocities []models.SalesVelocity `json:"velocities,omitempty"`
+}
+
+// ForecastInputsResult reports how many inputs were stored
+type ForecastInputsResult struct {
+	Ledger     int `json:"ledger"`
+	Velocities int `json:"velocities"`
+}
+
+// Forecaster projects when a variant runs out of stock. Implementations are
+// plugged in by name, see the forecasting package.
+type Forecaster interface {
+	Name() string
+	// Window is how far back the ledger passed to Forecast reaches
+	Window() time.Duration
+	Forecast(input *models.ForecastInput) (*models.StockoutForecast, error)
+}
+
+// ForecastService defines the interface for inventory forecasting
+type ForecastService interface {
+	// PushInputs validates and stores a batch of ledger entries and velocities
+	PushInputs(inputs *ForecastInputs) (*ForecastInputsResult, error)
+	// Stockouts returns the forecasts of variants with inputs, soonest stockout
+	// first. A positive within only keeps stockouts before now+within.
+	Stockouts(ctx context.Context, within time.Duration, limit int) ([]*models.StockoutForecast, error)
+}
@@ -0,0 +1,73 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// ImportMode selects whether an import creates products or updates existing ones
+type ImportMode string
+
+const (
+	// ImportCreate creates a new product per record
+	ImportCreate ImportMode = "create"
+	// ImportUpdate merges each record into the existing product or variant with
+	// the record's SKU, e.g. supplier price and stock files
+	ImportUpdate ImportMode = "update"
+)
+
+// ImportRequest contains import records and the mapping that turns them into products
+type ImportRequest struct {
+	// Mapping maps target product fields to transformation expressions,
+	// e.g. {"sku": "{{upper .sku}}", "prices.SEK": "{{mul .price_eur 11.5}}"}
+	Mapping map[string]string   `json:"mapping"`
+	Records []map[string]string `json:"records"`
+	DryRun  bool                `json:"dry_run"`
+	Mode    ImportMode          `json:"mode,omitempty"` // Defaults to create
+	Source  string              `json:"source,omitempty"`
+	// Locale selects how the number and date columns are written, e.g. "de-DE"
+	// for "1.299,50" and "31.03.2024"
+	Locale string `json:"locale,omitempty"`
+	// Columns declares the type ("number" or "date") of record columns that are
+	// normalized before the mapping runs, e.g. {"price": "number"}
+	Columns map[string]string `json:"columns,omitempty"`
+}
+
+// ImportCellError describes a record cell that could not be normalized
+type ImportCellError struct {
+	Column string `json:"column"`
+	Value  string `json:"value"`
+	Error  string `json:"error"`
+}
+
+// ImportRowResult represents the outcome for a single import record
+type ImportRowResult struct {
+	Row       int                        `json:"row"`
+	ProductID string                     `json:"product_id,omitempty"`
+	Success   bool                       `json:"success"`
+	Error     string                     `json:"error,omitempty"`
+	Cells     []ImportCellError          `json:"cells,omitempty"` // Cells that could not be normalized
+	Warnings  []models.ValidationWarning `json:"warnings,omitempty"`
+	Product   *models.Product            `json:"product,omitempty"` // Set for dry runs
+}
+
+// ImportResult summarizes an import run
+type ImportResult struct {
+	JobID     string             `json:"job_id,omitempty"` // Not set for dry runs
+	Total     int                `json:"total"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	DryRun    bool               `json:"dry_run"`
+	Rows      []*ImportRowResult `json:"rows"`
+}
+
+// ImportService defines the interface for product imports
+type ImportService interface {
+	ImportProducts(ctx context.Context, req *ImportRequest) (*ImportResult, error)
+	// ImportCSV imports the rows of a CSV file with a header row, ignoring
+	// req.Records. Without a mapping, columns named after a target field are
+	// imported as they are.
+	ImportCSV(ctx context.Context, req *ImportRequest, file io.Reader) (*ImportResult, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// JobService defines the interface for reading batch and import jobs
+type JobService interface {
+	GetJob(id string) (*models.Job, error)
+	// ListJobs returns the most recent jobs, optionally only those of one type
+	ListJobs(jobType models.JobType, limit int) ([]*models.Job, error)
+}
@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MarketService defines the interface for market rollout and merchandising operations
+type MarketService interface {
+	// LaunchChecklist reports which products are blocked from launching in the market.
+	// An empty currency falls back to the market's default currency.
+	LaunchChecklist(ctx context.Context, market, currency string) (*models.LaunchChecklist, error)
+
+	// ListMarketProducts returns a page of the products listed in a market, in
+	// merchandising order for the category. The empty category is the full listing.
+	ListMarketProducts(ctx context.Context, market, category string, page, pageSize int) ([]*models.Product, int, error)
+	// ListMerchandising returns the manual positions of every listing in a market
+	ListMerchandising(market string) ([]*models.Merchandising, error)
+	// GetMerchandising returns the manual positions of a listing; a listing without pins is empty
+	GetMerchandising(market, category string) (*models.Merchandising, error)
+	// SetPins replaces every pin of a listing
+	SetPins(ctx context.Context, market, category string, pins []models.Pin) (*models.Merchandising, error)
+	// PinProduct places one product at a position in a listing
+	PinProduct(ctx context.Context, market, category, productID string, position int) (*models.Merchandising, error)
+	// UnpinProduct removes a product's pin, returning ErrPinNotFound if it has none
+	UnpinProduct(market, category, productID string) (*models.Merchandising, error)
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// MarketplaceService reports how products are synced to the configured marketplaces
+type MarketplaceService interface {
+	// Marketplaces returns the names of the configured marketplaces
+	Marketplaces() []string
+	// SyncStatuses returns the latest sync statuses for a marketplace, newest first,
+	// optionally only those in one state. Unknown marketplaces return ErrMarketplaceNotFound.
+	SyncStatuses(marketplace string, state models.SyncState, limit int) ([]*models.SyncStatus, error)
+}
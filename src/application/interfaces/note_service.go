@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// NoteService defines the interface for the internal notes merchandisers keep on products
+type NoteService interface {
+	// List returns the threads on a product, oldest first
+	List(ctx context.Context, productID string) ([]*models.NoteThread, error)
+	// Add writes a note. An empty threadID starts a new thread; a reply to a
+	// reply joins the thread of the note replied to.
+	Add(ctx context.Context, productID, threadID, author, body string) (*models.ProductNote, error)
+	// Edit replaces the body of a note; only its author may
+	Edit(productID, noteID, author, body string) (*models.ProductNote, error)
+	// Resolve marks a thread as settled, or reopens it
+	Resolve(productID, threadID string, resolved bool) (*models.ProductNote, error)
+	// Delete removes a note, and its replies when it starts a thread; only its author may
+	Delete(productID, noteID, author string) error
+}
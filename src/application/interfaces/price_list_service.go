@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// PriceListService defines the interface for price lists and price resolution
+type PriceListService interface {
+	// ListPriceLists returns every price list, ordered by ID
+	ListPriceLists() ([]*models.PriceList, error)
+	// GetPriceList returns a price list, or ErrPriceListNotFound
+	GetPriceList(id string) (*models.PriceList, error)
+	// CreatePriceList validates and stores a new list under a generated ID
+	CreatePriceList(ctx context.Context, list *models.PriceList) (*models.PriceList, error)
+	// UpdatePriceList replaces a stored list, or fails with ErrPriceListNotFound
+	UpdatePriceList(ctx context.Context, list *models.PriceList) (*models.PriceList, error)
+	// DeletePriceList removes a list, or fails with ErrPriceListNotFound
+	DeletePriceList(ctx context.Context, id string) error
+
+	// ResolvePrice returns the price of a product that applies to the query
+	ResolvePrice(ctx context.Context, productID string, query models.PriceQuery) (*models.ResolvedPrice, error)
+	// PublishScheduledChanges publishes a price.changed event for every
+	// product with a scheduled price that started or ended after since, up to
+	// and including until, and returns the number of events
+	PublishScheduledChanges(ctx context.Context, since, until time.Time) (int, error)
+}
@@ -1,24 +1,98 @@
 package interfaces
 
-import "github.com/jimmitjoo/ecom/src/domain/models"
+import (
+	"context"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
 
 // BatchResult represents the result of a batch operation
 type BatchResult struct {
-	ID      string `json:"id"`
-	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
+	ID       string                     `json:"id"`
+	Success  bool                       `json:"success"`
+	Error    string                     `json:"error,omitempty"`
+	Warnings []models.ValidationWarning `json:"warnings,omitempty"` // Non-fatal data quality issues
+}
+
+// JobRollbackResult represents the outcome of reverting a batch job
+type JobRollbackResult struct {
+	Job     *models.Job    `json:"job"`
+	Results []*BatchResult `json:"results"`
 }
 
-// ProductService defines the interface for product operations
+// ProductService defines the interface for product operations. Every method
+// takes the caller's context, which carries its deadline and trace and
+// cancels the repository calls it makes. Background jobs outlive it.
 type ProductService interface {
-	ListProducts(page, pageSize int) ([]*models.Product, int, error)
-	CreateProduct(product *models.Product) error
-	GetProduct(id string) (*models.Product, error)
-	UpdateProduct(product *models.Product) error
-	DeleteProduct(id string) error
+	// ListProducts returns a page of the products that match the filter
+	ListProducts(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error)
+	// ListProductsAfter returns up to limit matching products after an opaque
+	// cursor, from the start for an empty cursor, and the cursor of the next
+	// page, which is empty after the last page
+	ListProductsAfter(ctx context.Context, filter models.ProductFilter, cursor string, limit int) ([]*models.Product, string, error)
+	ListProductsAsOf(ctx context.Context, asOf time.Time, page, pageSize int) ([]*models.Product, int, error)
+	CreateProduct(ctx context.Context, product *models.Product) error
+	GetProduct(ctx context.Context, id string) (*models.Product, error)
+	// GetProductBySKU returns the product with the SKU, or models.ErrProductNotFound
+	GetProductBySKU(ctx context.Context, sku string) (*models.Product, error)
+	UpdateProduct(ctx context.Context, product *models.Product) error
+	// PatchProduct applies a patch document of the media type to a version of
+	// a product and saves the result through the same optimistic-lock path as
+	// UpdateProduct. It fails with models.ErrVersionConflict if the product is
+	// no longer at that version.
+	PatchProduct(ctx context.Context, id string, version int64, mediaType string, document []byte) (*models.Product, error)
+	// DeleteProduct removes a product permanently
+	DeleteProduct(ctx context.Context, id string) error
+	// SoftDeleteProduct marks a product as deleted, which hides it from reads
+	// and listings until it is restored
+	SoftDeleteProduct(ctx context.Context, id string) (*models.Product, error)
+	// RestoreProduct re-activates a soft-deleted product, or fails with
+	// models.ErrNotDeleted if it is not deleted
+	RestoreProduct(ctx context.Context, id string) (*models.Product, error)
+	CompareProducts(ctx context.Context, ids []string) (*models.ProductComparison, error)
+	RollbackProduct(ctx context.Context, id string, toVersion int64) (*models.Product, error)
+	// ReplayEvents returns a product's events from a version on, oldest
+	// first. A broken hash chain fails with models.ErrBrokenEventChain.
+	ReplayEvents(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error)
+	// ListProductVersions lists the versions of a product that can be
+	// reconstructed from its events, oldest first
+	ListProductVersions(ctx context.Context, id string) ([]*models.ProductVersion, error)
+	// GetProductVersion returns a product as it was at a version, or fails
+	// with models.ErrVersionNotFound
+	GetProductVersion(ctx context.Context, id string, version int64) (*models.Product, error)
+	AdjustStock(ctx context.Context, id string, adjustments []models.StockAdjustment) (*models.Product, error)
 
 	// Batch operations
-	BatchCreateProducts(products []*models.Product) ([]*BatchResult, error)
-	BatchUpdateProducts(products []*models.Product) ([]*BatchResult, error)
-	BatchDeleteProducts(ids []string) ([]*BatchResult, error)
+	BatchCreateProducts(ctx context.Context, products []*models.Product) ([]*BatchResult, error)
+	BatchUpdateProducts(ctx context.Context, products []*models.Product) ([]*BatchResult, error)
+	BatchDeleteProducts(ctx context.Context, ids []string) ([]*BatchResult, error)
+	RollbackJob(ctx context.Context, jobID string) (*JobRollbackResult, error)
+	// MigrateAttributes starts a background job that renames or remaps a variant attribute across the catalog
+	MigrateAttributes(ctx context.Context, migration *models.AttributeMigration) (*models.Job, error)
+	// PreviewTextReplacement lists the changes a text replacement would make without writing them
+	PreviewTextReplacement(ctx context.Context, replacement *models.TextReplacement) (*models.TextReplacementPreview, error)
+	// ReplaceText starts a background job that applies a text replacement to the selected products
+	ReplaceText(ctx context.Context, replacement *models.TextReplacement) (*models.Job, error)
+	// CopyMetadata starts a background job that copies one market's metadata to other markets of the selected products
+	CopyMetadata(ctx context.Context, metadataCopy *models.MetadataCopy) (*models.Job, error)
+
+	// Catalog cloning between environments
+	ExportCatalog(ctx context.Context, filter models.CatalogFilter, anonymizePrices bool) (*models.CatalogExport, error)
+	// ImportCatalog starts a background job that writes an exported catalog into this environment
+	ImportCatalog(ctx context.Context, req *CatalogImportRequest) (*models.Job, error)
+
+	// Tags
+	ListProductsByTags(ctx context.Context, tags []string, page, pageSize int) ([]*models.Product, int, error)
+	TagUsage(ctx context.Context) ([]*models.TagUsage, error)
+	// UpdateTags starts a background job that adds and removes tags on the selected products
+	UpdateTags(ctx context.Context, update *models.TagUpdate) (*models.Job, error)
+	DeleteProductsByTag(ctx context.Context, tag string) ([]*BatchResult, error)
+
+	// AssignCategories replaces the categories a product is listed in
+	AssignCategories(ctx context.Context, id string, categoryIDs []string) (*models.Product, error)
+	// UpdateImages replaces the images of a product at a version, which fails
+	// with ErrVersionConflict once the product has moved on. Images without an
+	// ID get one.
+	UpdateImages(ctx context.Context, id string, version int64, images []models.Image) (*models.Product, error)
 }
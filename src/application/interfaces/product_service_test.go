@@ -1,7 +1,9 @@
 package interfaces_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/jimmitjoo/ecom/src/application/interfaces"
 	"github.com/jimmitjoo/ecom/src/domain/models"
@@ -27,17 +29,27 @@ type MockProductService struct {
 	mock.Mock
 }
 
-func (m *MockProductService) ListProducts(page, pageSize int) ([]*models.Product, int, error) {
-	args := m.Called(page, pageSize)
+func (m *MockProductService) ListProducts(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(filter, page, pageSize)
 	return args.Get(0).([]*models.Product), args.Int(1), args.Error(2)
 }
 
-func (m *MockProductService) CreateProduct(product *models.Product) error {
+func (m *MockProductService) ListProductsAfter(ctx context.Context, filter models.ProductFilter, cursor string, limit int) ([]*models.Product, string, error) {
+	args := m.Called(filter, cursor, limit)
+	return args.Get(0).([]*models.Product), args.String(1), args.Error(2)
+}
+
+func (m *MockProductService) ListProductsAsOf(ctx context.Context, asOf time.Time, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(asOf, page, pageSize)
+	return args.Get(0).([]*models.Product), args.Int(1), args.Error(2)
+}
+
+func (m *MockProductService) CreateProduct(ctx context.Context, product *models.Product) error {
 	args := m.Called(product)
 	return args.Error(0)
 }
 
-func (m *MockProductService) GetProduct(id string) (*models.Product, error) {
+func (m *MockProductService) GetProduct(ctx context.Context, id string) (*models.Product, error) {
 	args := m.Called(id)
 	if p, ok := args.Get(0).(*models.Product); ok {
 		return p, args.Error(1)
@@ -45,27 +57,205 @@ func (m *MockProductService) GetProduct(id string) (*models.Product, error) {
 	return nil, args.Error(1)
 }
 
-func (m *MockProductService) UpdateProduct(product *models.Product) error {
+func (m *MockProductService) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	args := m.Called(sku)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) UpdateProduct(ctx context.Context, product *models.Product) error {
 	args := m.Called(product)
 	return args.Error(0)
 }
 
-func (m *MockProductService) DeleteProduct(id string) error {
+func (m *MockProductService) PatchProduct(ctx context.Context, id string, version int64, mediaType string, document []byte) (*models.Product, error) {
+	args := m.Called(id, version, mediaType, document)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) DeleteProduct(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockProductService) BatchCreateProducts(products []*models.Product) ([]*interfaces.BatchResult, error) {
+func (m *MockProductService) ReplayEvents(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
+	args := m.Called(productID, fromVersion)
+	if events, ok := args.Get(0).([]*models.Event); ok {
+		return events, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) ListProductVersions(ctx context.Context, id string) ([]*models.ProductVersion, error) {
+	args := m.Called(id)
+	if versions, ok := args.Get(0).([]*models.ProductVersion); ok {
+		return versions, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) GetProductVersion(ctx context.Context, id string, version int64) (*models.Product, error) {
+	args := m.Called(id, version)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) SoftDeleteProduct(ctx context.Context, id string) (*models.Product, error) {
+	args := m.Called(id)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) RestoreProduct(ctx context.Context, id string) (*models.Product, error) {
+	args := m.Called(id)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) CompareProducts(ctx context.Context, ids []string) (*models.ProductComparison, error) {
+	args := m.Called(ids)
+	if c, ok := args.Get(0).(*models.ProductComparison); ok {
+		return c, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) RollbackProduct(ctx context.Context, id string, toVersion int64) (*models.Product, error) {
+	args := m.Called(id, toVersion)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) AdjustStock(ctx context.Context, id string, adjustments []models.StockAdjustment) (*models.Product, error) {
+	args := m.Called(id, adjustments)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) RollbackJob(ctx context.Context, jobID string) (*interfaces.JobRollbackResult, error) {
+	args := m.Called(jobID)
+	if result, ok := args.Get(0).(*interfaces.JobRollbackResult); ok {
+		return result, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) MigrateAttributes(ctx context.Context, migration *models.AttributeMigration) (*models.Job, error) {
+	args := m.Called(migration)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) PreviewTextReplacement(ctx context.Context, replacement *models.TextReplacement) (*models.TextReplacementPreview, error) {
+	args := m.Called(replacement)
+	if preview, ok := args.Get(0).(*models.TextReplacementPreview); ok {
+		return preview, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) ReplaceText(ctx context.Context, replacement *models.TextReplacement) (*models.Job, error) {
+	args := m.Called(replacement)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) CopyMetadata(ctx context.Context, metadataCopy *models.MetadataCopy) (*models.Job, error) {
+	args := m.Called(metadataCopy)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) ExportCatalog(ctx context.Context, filter models.CatalogFilter, anonymizePrices bool) (*models.CatalogExport, error) {
+	args := m.Called(filter, anonymizePrices)
+	if export, ok := args.Get(0).(*models.CatalogExport); ok {
+		return export, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) ImportCatalog(ctx context.Context, req *interfaces.CatalogImportRequest) (*models.Job, error) {
+	args := m.Called(req)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) ListProductsByTags(ctx context.Context, tags []string, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(tags, page, pageSize)
+	return args.Get(0).([]*models.Product), args.Int(1), args.Error(2)
+}
+
+func (m *MockProductService) TagUsage(ctx context.Context) ([]*models.TagUsage, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.TagUsage), args.Error(1)
+}
+
+func (m *MockProductService) UpdateTags(ctx context.Context, update *models.TagUpdate) (*models.Job, error) {
+	args := m.Called(update)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) DeleteProductsByTag(ctx context.Context, tag string) ([]*interfaces.BatchResult, error) {
+	args := m.Called(tag)
+	if results, ok := args.Get(0).([]*interfaces.BatchResult); ok {
+		return results, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) AssignCategories(ctx context.Context, id string, categoryIDs []string) (*models.Product, error) {
+	args := m.Called(id, categoryIDs)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) UpdateImages(ctx context.Context, id string, version int64, images []models.Image) (*models.Product, error) {
+	args := m.Called(id, version, images)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) BatchCreateProducts(ctx context.Context, products []*models.Product) ([]*interfaces.BatchResult, error) {
 	args := m.Called(products)
 	return args.Get(0).([]*interfaces.BatchResult), args.Error(1)
 }
 
-func (m *MockProductService) BatchUpdateProducts(products []*models.Product) ([]*interfaces.BatchResult, error) {
+func (m *MockProductService) BatchUpdateProducts(ctx context.Context, products []*models.Product) ([]*interfaces.BatchResult, error) {
 	args := m.Called(products)
 	return args.Get(0).([]*interfaces.BatchResult), args.Error(1)
 }
 
-func (m *MockProductService) BatchDeleteProducts(ids []string) ([]*interfaces.BatchResult, error) {
+func (m *MockProductService) BatchDeleteProducts(ctx context.Context, ids []string) ([]*interfaces.BatchResult, error) {
 	args := m.Called(ids)
 	return args.Get(0).([]*interfaces.BatchResult), args.Error(1)
 }
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// PublicCatalogService serves the catalog to storefronts. Only published
+// products are returned; redacting internal fields is up to the caller.
+type PublicCatalogService interface {
+	// ListProducts returns a page of published products, optionally only those with every tag
+	ListProducts(ctx context.Context, tags []string, page, pageSize int) ([]*models.Product, int, error)
+	// GetProduct returns a published product, or ErrProductNotFound for drafts
+	GetProduct(ctx context.Context, id string) (*models.Product, error)
+	// ListMarketProducts returns a page of the products listed in a market, in merchandising order
+	ListMarketProducts(ctx context.Context, market, category string, page, pageSize int) ([]*models.Product, int, error)
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// RelationService defines the interface for links between products, such
+// as upsells and cross-sells
+type RelationService interface {
+	// ListRelations returns the relations from a product, of one type when
+	// relationType is set
+	ListRelations(ctx context.Context, productID string, relationType models.RelationType) ([]*models.ProductRelation, error)
+	// AddRelation links two existing products, replacing a relation of the
+	// same type between them
+	AddRelation(ctx context.Context, relation *models.ProductRelation) (*models.ProductRelation, error)
+	// RemoveRelation removes a relation, or fails with ErrRelationNotFound
+	RemoveRelation(productID, relatedID string, relationType models.RelationType) error
+	// RelatedProducts resolves the products linked from a product, of one
+	// type when relationType is set. Deleted products are left out.
+	RelatedProducts(ctx context.Context, productID string, relationType models.RelationType) ([]*models.RelatedProduct, error)
+}
@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// ReprocessRequest selects the products whose latest event is delivered again
+// and how fast. An empty filter selects every product.
+type ReprocessRequest struct {
+	// Consumer is the internal subscriber to deliver to, e.g. "marketplaces".
+	// Empty delivers to every tracked consumer.
+	Consumer     string     `json:"consumer,omitempty"`
+	ProductIDs   []string   `json:"product_ids,omitempty"`
+	SKUPrefix    string     `json:"sku_prefix,omitempty"`
+	Tag          string     `json:"tag,omitempty"`
+	UpdatedSince *time.Time `json:"updated_since,omitempty"`
+	// Rate is the number of events delivered per second
+	Rate float64 `json:"rate,omitempty"`
+}
+
+// EventRedeliverer hands stored events to internal consumers again
+type EventRedeliverer interface {
+	HasConsumer(name string) bool
+	Redeliver(consumer string, event *models.Event) (int, error)
+}
+
+// ReprocessService defines the interface for reprocessing products through event consumers
+type ReprocessService interface {
+	// StartReprocess starts a background job and returns it right away
+	StartReprocess(ctx context.Context, req *ReprocessRequest) (*models.Job, error)
+}
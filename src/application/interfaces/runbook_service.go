@@ -0,0 +1,94 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Projection is read state an internal consumer builds from events, such as
+// the dashboard's activity feed, which can be dropped and rebuilt
+type Projection interface {
+	// Reset drops the state, so delivering every stored event again rebuilds it
+	Reset()
+}
+
+// RunbookConfig configures what the runbook actions can act on
+type RunbookConfig struct {
+	// Projections are keyed by the name of the consumer that builds them
+	Projections map[string]Projection
+	// DeliveryConsumers names the consumer delivering to each kind of target,
+	// e.g. "marketplace" -> "marketplaces"
+	DeliveryConsumers map[string]string
+}
+
+// ResendRequest selects the failed deliveries to send again
+type ResendRequest struct {
+	// Targets are the delivery targets, e.g. "webhook:orders" or "marketplace:amazon"
+	Targets []string `json:"targets"`
+	// From and To bound when the delivery last failed; either may be left out
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// ProjectionRebuild is the result of rebuilding a projection
+type ProjectionRebuild struct {
+	Projection string `json:"projection"`
+	Events     int    `json:"events"` // Stored events delivered to the projection
+}
+
+// ResentDelivery is a failed delivery that was sent again
+type ResentDelivery struct {
+	ProductID string `json:"product_id"`
+	Target    string `json:"target"`
+	Version   int64  `json:"version"` // Failed version; the consumer receives the latest event
+}
+
+// DeliveryResend is the result of resending failed deliveries
+type DeliveryResend struct {
+	Resent []*ResentDelivery `json:"resent"`
+	// Errors are the deliveries that could not be handed to their consumer
+	Errors []string `json:"errors,omitempty"`
+}
+
+// ReleasedLock is a lock that was force-released
+type ReleasedLock struct {
+	ResourceID string    `json:"resource_id"`
+	Owner      string    `json:"owner"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// LockRelease is the result of releasing a product's stale locks
+type LockRelease struct {
+	ProductID string          `json:"product_id"`
+	Released  []*ReleasedLock `json:"released"`
+}
+
+// ChainVerification is the result of re-verifying a product's event chain
+type ChainVerification struct {
+	ProductID string `json:"product_id"`
+	Events    int    `json:"events"`
+	Valid     bool   `json:"valid"`
+	// Problem describes the first break in the chain when it is not valid
+	Problem string `json:"problem,omitempty"`
+}
+
+// RunbookService automates common recovery actions. Every action is recorded
+// in the audit trail with its actor, whether it succeeded or not.
+type RunbookService interface {
+	// RebuildProjection resets a projection and delivers every stored event to it again
+	RebuildProjection(ctx context.Context, name, actor string) (*models.RunbookRun, error)
+	// ResendFailedDeliveries hands the latest event of each product whose delivery
+	// to a target failed in the time range to the target's consumer again
+	ResendFailedDeliveries(ctx context.Context, req *ResendRequest, actor string) (*models.RunbookRun, error)
+	// ReleaseStaleLocks releases a product's write lock and edit sessions that
+	// were acquired more than olderThan ago
+	ReleaseStaleLocks(productID string, olderThan time.Duration, actor string) (*models.RunbookRun, error)
+	// VerifyEventChain checks a product's versions and hash chain, and that the
+	// stored product matches its latest event
+	VerifyEventChain(ctx context.Context, productID, actor string) (*models.RunbookRun, error)
+	// ListRuns returns the audit trail, newest first
+	ListRuns(limit int) ([]*models.RunbookRun, error)
+}
@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// SearchIndex stores the searchable text of products and ranks them against
+// queries, e.g. in memory or in Elasticsearch. Implementations are plugged
+// in by name, see the search package.
+type SearchIndex interface {
+	Name() string
+	// Index adds a product, or replaces what was indexed for it
+	Index(document *models.SearchDocument) error
+	// Remove removes a product; removing one that is not indexed is not an error
+	Remove(productID string) error
+	// Search returns a page of the matching products, most relevant first
+	Search(query models.SearchQuery) (*models.SearchHits, error)
+}
+
+// SearchService defines the interface for full-text product search
+type SearchService interface {
+	// Search returns a page of the products matching the query, most relevant
+	// first, and the number of matches
+	Search(ctx context.Context, query models.SearchQuery) ([]*models.ProductSearchResult, int, error)
+	// Reindex indexes every product again and returns how many were indexed
+	Reindex(ctx context.Context) (int, error)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// StockService defines the interface for managing the stock of product variants
+type StockService interface {
+	// GetStock returns the stock of a variant at every location
+	GetStock(ctx context.Context, productID, variantID string) (*models.VariantStock, error)
+	// SetStock sets the quantities of a variant at the listed locations in
+	// one adjustment; the other locations keep their quantities
+	SetStock(ctx context.Context, productID, variantID string, levels []models.StockLevel) (*models.VariantStock, error)
+	// AdjustStock applies adjustments across products. The adjustments of each
+	// product are applied together or not at all, with a result per product in
+	// the order the products first appear.
+	AdjustStock(ctx context.Context, adjustments []models.ProductStockAdjustment) ([]*BatchResult, error)
+}
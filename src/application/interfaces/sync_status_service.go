@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// ProductSyncStatus is the delivery status of a product across every downstream target
+type ProductSyncStatus struct {
+	ProductID string `json:"product_id"`
+	// Version is the product's current version; targets with a lower synced_version are behind
+	Version int64                `json:"version"`
+	Deleted bool                 `json:"deleted"`
+	Targets []*models.SyncStatus `json:"targets"`
+}
+
+// SyncStatusService reports how far products have been delivered to integrations
+// such as search, feeds, marketplaces and webhook endpoints
+type SyncStatusService interface {
+	// GetSyncStatus returns a product's status per target. Deleted products are
+	// still reported while targets have a status for them.
+	GetSyncStatus(ctx context.Context, productID string) (*ProductSyncStatus, error)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// WebhookService defines the interface for managing the webhook endpoints product events are delivered to
+type WebhookService interface {
+	// Register validates and stores a new endpoint. A secret is generated when
+	// none is given; the returned endpoint carries it.
+	Register(endpoint *models.WebhookEndpoint) (*models.WebhookEndpoint, error)
+	// List returns every endpoint, oldest first
+	List() ([]*models.WebhookEndpoint, error)
+	// Get returns an endpoint, or models.ErrWebhookNotFound
+	Get(id string) (*models.WebhookEndpoint, error)
+	// SetDisabled stops or resumes deliveries to an endpoint
+	SetDisabled(id string, disabled bool) (*models.WebhookEndpoint, error)
+	// Delete removes an endpoint and its delivery log
+	Delete(id string) error
+	// Deliveries returns up to limit delivery attempts of an endpoint, newest first
+	Deliveries(id string, limit int) ([]*models.WebhookDelivery, error)
+}
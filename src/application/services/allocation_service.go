@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// reserveAttempts is how often a reservation is allocated again when
+// concurrent reservations took the stock it was allocated from
+const reserveAttempts = 3
+
+// allocationService implements the AllocationService interface
+type allocationService struct {
+	repo     repositories.ProductRepository
+	products interfaces.ProductService
+	policies repositories.AllocationPolicyRepository
+}
+
+// NewAllocationService creates a new allocation service instance. Reservations
+// are written through the product service so they are published like any
+// other stock adjustment.
+func NewAllocationService(repo repositories.ProductRepository, products interfaces.ProductService, policies repositories.AllocationPolicyRepository) interfaces.AllocationService {
+	return &allocationService{
+		repo:     repo,
+		products: products,
+		policies: policies,
+	}
+}
+
+// ListPolicies returns the configured policies of a market
+func (s *allocationService) ListPolicies(market string) ([]*models.AllocationPolicy, error) {
+	return s.policies.ListByMarket(models.NormalizeMarket(market))
+}
+
+// GetPolicy resolves the policy of a channel, falling back to the market's and then the default
+func (s *allocationService) GetPolicy(market, channel string) (*models.AllocationPolicy, error) {
+	market = models.NormalizeMarket(market)
+	channel = models.NormalizeChannel(channel)
+
+	candidates := []string{channel}
+	if channel != "" {
+		candidates = append(candidates, "")
+	}
+	for _, candidate := range candidates {
+		policy, err := s.policies.Get(market, candidate)
+		if err == nil {
+			return policy, nil
+		}
+		if !errors.Is(err, models.ErrAllocationPolicyNotFound) {
+			return nil, err
+		}
+	}
+	return models.DefaultAllocationPolicy(market, channel), nil
+}
+
+// SetPolicy validates and stores a policy
+func (s *allocationService) SetPolicy(policy *models.AllocationPolicy) (*models.AllocationPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	policy.UpdatedAt = time.Now()
+	if err := s.policies.Save(policy); err != nil {
+		return nil, fmt.Errorf("failed to save allocation policy: %v", err)
+	}
+	return policy, nil
+}
+
+// DeletePolicy removes a policy so the channel falls back to the market's or the default
+func (s *allocationService) DeletePolicy(market, channel string) error {
+	return s.policies.Delete(models.NormalizeMarket(market), models.NormalizeChannel(channel))
+}
+
+// Availability applies the resolved policy to the product's current stock
+func (s *allocationService) Availability(ctx context.Context, productID, market, channel string) (*models.Availability, error) {
+	if models.NormalizeMarket(market) == "" {
+		return nil, fmt.Errorf("%w: market is required", models.ErrInvalidRequest)
+	}
+	product, err := s.repo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.GetPolicy(market, channel)
+	if err != nil {
+		return nil, err
+	}
+	availability := policy.Availability(product)
+	availability.Channel = models.NormalizeChannel(channel)
+	return availability, nil
+}
+
+// Reserve allocates against the current stock and reserves the result in one
+// stock adjustment. The adjustment fails rather than oversells when another
+// reservation got there first; the request is then allocated again from the
+// stock that is left.
+func (s *allocationService) Reserve(ctx context.Context, productID string, request *models.AllocationRequest) (*models.Allocation, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	policy, err := s.GetPolicy(request.Market, request.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		product, err := s.repo.GetByID(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+		allocation, err := policy.Allocate(product, request.Items, request.Destination)
+		if err != nil {
+			return nil, err
+		}
+		allocation.Channel = request.Channel
+
+		updated, err := s.products.AdjustStock(ctx, productID, allocation.StockAdjustments())
+		if errors.Is(err, models.ErrInsufficientStock) && attempt < reserveAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		allocation.Version = updated.Version
+		return allocation, nil
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+// setupAllocationService returns an allocation service and a product stocked at two warehouses
+func setupAllocationService(t *testing.T) (*allocationService, *models.Product) {
+	products, _, _ := setupProductService()
+	product := createValidProduct()
+	product.Variants = []models.Variant{{
+		ID:         "v1",
+		SKU:        "TEST-123-S",
+		Attributes: map[string]string{"size": "S"},
+		Stock:      []models.Stock{{LocationID: "wh1", Quantity: 2}, {LocationID: "wh2", Quantity: 3}},
+	}}
+	assert.NoError(t, products.CreateProduct(context.Background(), product))
+
+	service := NewAllocationService(products.repo, products, memory.NewAllocationPolicyRepository()).(*allocationService)
+	return service, product
+}
+
+func TestGetPolicyFallsBack(t *testing.T) {
+	service, _ := setupAllocationService(t)
+
+	// Nothing configured
+	policy, err := service.GetPolicy("se", "Web")
+	assert.NoError(t, err)
+	assert.True(t, policy.Default)
+	assert.Equal(t, "SE", policy.Market)
+	assert.Equal(t, "web", policy.Channel)
+
+	// The market-wide policy applies to every channel without one
+	_, err = service.SetPolicy(&models.AllocationPolicy{Market: "SE", Locations: []models.FulfillmentLocation{{ID: "wh2"}}})
+	assert.NoError(t, err)
+	policy, err = service.GetPolicy("SE", "web")
+	assert.NoError(t, err)
+	assert.False(t, policy.Default)
+	assert.Equal(t, "", policy.Channel)
+
+	// A channel's own policy wins
+	_, err = service.SetPolicy(&models.AllocationPolicy{Market: "SE", Channel: "web", SplitShipments: true})
+	assert.NoError(t, err)
+	policy, err = service.GetPolicy("SE", "web")
+	assert.NoError(t, err)
+	assert.Equal(t, "web", policy.Channel)
+
+	policies, err := service.ListPolicies("se")
+	assert.NoError(t, err)
+	assert.Len(t, policies, 2)
+
+	assert.NoError(t, service.DeletePolicy("se", "WEB"))
+	policy, _ = service.GetPolicy("SE", "web")
+	assert.Equal(t, "", policy.Channel)
+	assert.ErrorIs(t, service.DeletePolicy("SE", "web"), models.ErrAllocationPolicyNotFound)
+}
+
+func TestSetPolicyRejectsInvalidPolicies(t *testing.T) {
+	service, _ := setupAllocationService(t)
+
+	_, err := service.SetPolicy(&models.AllocationPolicy{Market: "SE", Strategy: models.AllocationNearest})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
+
+func TestAvailabilityUsesPolicy(t *testing.T) {
+	service, product := setupAllocationService(t)
+
+	availability, err := service.Availability(context.Background(), product.ID, "SE", "pos")
+	assert.NoError(t, err)
+	assert.Equal(t, "pos", availability.Channel)
+	assert.Equal(t, 5, availability.Variants[0].Available)
+
+	_, err = service.SetPolicy(&models.AllocationPolicy{Market: "SE", Channel: "pos", Locations: []models.FulfillmentLocation{{ID: "wh1"}}})
+	assert.NoError(t, err)
+	availability, err = service.Availability(context.Background(), product.ID, "SE", "pos")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, availability.Variants[0].Available)
+
+	_, err = service.Availability(context.Background(), "missing", "SE", "")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	_, err = service.Availability(context.Background(), product.ID, "", "")
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
+
+func TestReserveDecrementsAllocatedStock(t *testing.T) {
+	service, product := setupAllocationService(t)
+
+	allocation, err := service.Reserve(context.Background(), product.ID, &models.AllocationRequest{
+		Market:  "SE",
+		Channel: "web",
+		Items:   []models.AllocationItem{{VariantID: "v1", Quantity: 4}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, allocation.Shipments)
+	assert.Equal(t, "web", allocation.Channel)
+	assert.Equal(t, product.Version+1, allocation.Version)
+
+	stored, err := service.repo.GetByID(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, stored.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, 1, stored.Variants[0].Stock[1].Quantity)
+
+	// Without split shipments no single warehouse has enough
+	_, err = service.SetPolicy(&models.AllocationPolicy{Market: "SE"})
+	assert.NoError(t, err)
+	_, err = service.Reserve(context.Background(), product.ID, &models.AllocationRequest{Market: "SE", Items: []models.AllocationItem{{VariantID: "v1", Quantity: 2}}})
+	assert.ErrorIs(t, err, models.ErrInsufficientStock)
+}
+
+func TestConcurrentReservationsNeverOversell(t *testing.T) {
+	service, product := setupAllocationService(t)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			allocation, err := service.Reserve(context.Background(), product.ID, &models.AllocationRequest{
+				Market: "SE",
+				Items:  []models.AllocationItem{{VariantID: "v1", Quantity: 1}},
+			})
+			if err == nil {
+				mu.Lock()
+				reserved += allocation.Lines[0].Quantity
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 5, reserved)
+	stored, _ := service.repo.GetByID(context.Background(), product.ID)
+	assert.Equal(t, 0, stored.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, 0, stored.Variants[0].Stock[1].Quantity)
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// assetService implements the AssetService interface
+type assetService struct {
+	products interfaces.ProductService
+	storage  interfaces.AssetStorage
+}
+
+// NewAssetService creates a new asset service instance. Image changes are
+// saved through the product service as product updates; uploaded binaries
+// are kept in the storage.
+func NewAssetService(products interfaces.ProductService, storage interfaces.AssetStorage) interfaces.AssetService {
+	return &assetService{
+		products: products,
+		storage:  storage,
+	}
+}
+
+// AttachImage appends an image served from an external URL
+func (s *assetService) AttachImage(ctx context.Context, productID string, image *models.Image) (*models.Product, error) {
+	attached := *image
+	attached.ID = ""
+	attached.StorageKey = ""
+	if err := attached.Validate(); err != nil {
+		return nil, err
+	}
+	return s.appendImage(ctx, productID, attached)
+}
+
+// UploadImage stores an uploaded binary under products/<id>/ and appends it
+// as an image
+func (s *assetService) UploadImage(ctx context.Context, productID string, upload *interfaces.AssetUpload) (*models.Product, error) {
+	contentType := strings.ToLower(strings.TrimSpace(upload.ContentType))
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("%w: uploads must be images, got %q", models.ErrInvalidRequest, upload.ContentType)
+	}
+	if _, err := s.products.GetProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	image := upload.Image
+	image.ID = uuid.New().String()
+	image.ContentType = contentType
+	image.StorageKey = path.Join("products", productID, image.ID+strings.ToLower(path.Ext(upload.Filename)))
+	url, err := s.storage.Put(image.StorageKey, contentType, upload.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store image: %w", err)
+	}
+	image.URL = url
+	if err := image.Validate(); err != nil {
+		s.deleteStored(image.StorageKey)
+		return nil, err
+	}
+
+	product, err := s.appendImage(ctx, productID, image)
+	if err != nil {
+		// The image was never attached, so its binary is not needed
+		s.deleteStored(image.StorageKey)
+		return nil, err
+	}
+	return product, nil
+}
+
+// DetachImage removes an image and the binary of an uploaded one
+func (s *assetService) DetachImage(ctx context.Context, productID, imageID string) (*models.Product, error) {
+	product, err := s.products.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	index := product.ImageIndex(imageID)
+	if index < 0 {
+		return nil, models.ErrImageNotFound
+	}
+	detached := product.Images[index]
+	images := append(append([]models.Image{}, product.Images[:index]...), product.Images[index+1:]...)
+	updated, err := s.products.UpdateImages(ctx, productID, product.Version, images)
+	if err != nil {
+		return nil, err
+	}
+	if detached.StorageKey != "" {
+		s.deleteStored(detached.StorageKey)
+	}
+	return updated, nil
+}
+
+// ReorderImages puts the images in the order of the IDs
+func (s *assetService) ReorderImages(ctx context.Context, productID string, imageIDs []string) (*models.Product, error) {
+	product, err := s.products.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	images, err := product.ReorderImages(imageIDs)
+	if err != nil {
+		return nil, err
+	}
+	return s.products.UpdateImages(ctx, productID, product.Version, images)
+}
+
+// appendImage adds an image after the product's current images
+func (s *assetService) appendImage(ctx context.Context, productID string, image models.Image) (*models.Product, error) {
+	product, err := s.products.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(product.Images) >= models.MaxProductImages {
+		return nil, fmt.Errorf("%w: at most %d images per product", models.ErrInvalidRequest, models.MaxProductImages)
+	}
+	images := append(append([]models.Image{}, product.Images...), image)
+	return s.products.UpdateImages(ctx, productID, product.Version, images)
+}
+
+// deleteStored removes a binary that is no longer referenced. Failures leave
+// an orphaned binary behind but do not fail the request.
+func (s *assetService) deleteStored(key string) {
+	if err := s.storage.Delete(key); err != nil {
+		logging.Shared().Error("Failed to delete stored image",
+			zap.String("storage", s.storage.Name()),
+			zap.String("key", key),
+			zap.Error(err))
+	}
+}
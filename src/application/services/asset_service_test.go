@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockAssetStorage is a mock implementation of AssetStorage
+type MockAssetStorage struct {
+	mock.Mock
+}
+
+func (m *MockAssetStorage) Name() string {
+	return "mock"
+}
+
+func (m *MockAssetStorage) Put(key, contentType string, body io.Reader) (string, error) {
+	data, _ := io.ReadAll(body)
+	args := m.Called(key, contentType, string(data))
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAssetStorage) Delete(key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func setupAssetService(t *testing.T) (interfaces.AssetService, *productService, *MockAssetStorage, *models.Product) {
+	products, _, _ := setupProductService()
+	storage := new(MockAssetStorage)
+	product := createValidProduct()
+	assert.NoError(t, products.CreateProduct(context.Background(), product))
+	return NewAssetService(products, storage), products, storage, product
+}
+
+func TestAttachImage(t *testing.T) {
+	service, _, _, product := setupAssetService(t)
+
+	updated, err := service.AttachImage(context.Background(), product.ID, &models.Image{URL: " https://cdn.example.com/front.jpg ", AltTexts: map[string]string{"se": "Framsida"}})
+	assert.NoError(t, err)
+	assert.Len(t, updated.Images, 1)
+	assert.NotEmpty(t, updated.Images[0].ID)
+	assert.Equal(t, "https://cdn.example.com/front.jpg", updated.Images[0].URL)
+	assert.Equal(t, "Framsida", updated.Images[0].AltTextFor("SE"))
+
+	_, err = service.AttachImage(context.Background(), product.ID, &models.Image{URL: "/front.jpg"})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.AttachImage(context.Background(), "missing", &models.Image{URL: "https://cdn.example.com/front.jpg"})
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestUploadImage(t *testing.T) {
+	service, _, storage, product := setupAssetService(t)
+	storage.On("Put", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "products/"+product.ID+"/") && strings.HasSuffix(key, ".png")
+	}), "image/png", "binary").Return("https://media.example.com/front.png", nil)
+
+	updated, err := service.UploadImage(context.Background(), product.ID, &interfaces.AssetUpload{
+		Filename:    "Front.PNG",
+		ContentType: "image/png",
+		Body:        strings.NewReader("binary"),
+		Image:       models.Image{AltText: "Front"},
+	})
+	assert.NoError(t, err)
+	image := updated.Images[0]
+	assert.Equal(t, "https://media.example.com/front.png", image.URL)
+	assert.Equal(t, "image/png", image.ContentType)
+	assert.Equal(t, "products/"+product.ID+"/"+image.ID+".png", image.StorageKey)
+	assert.Equal(t, "Front", image.AltText)
+
+	_, err = service.UploadImage(context.Background(), product.ID, &interfaces.AssetUpload{Filename: "notes.txt", ContentType: "text/plain", Body: strings.NewReader("x")})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	storage.AssertNumberOfCalls(t, "Put", 1)
+}
+
+func TestUploadImageStorageFailure(t *testing.T) {
+	service, products, storage, product := setupAssetService(t)
+	storage.On("Put", mock.Anything, "image/jpeg", "binary").Return("", errors.New("disk full"))
+
+	_, err := service.UploadImage(context.Background(), product.ID, &interfaces.AssetUpload{Filename: "a.jpg", ContentType: "image/jpeg", Body: strings.NewReader("binary")})
+	assert.Error(t, err)
+	stored, _ := products.GetProduct(context.Background(), product.ID)
+	assert.Empty(t, stored.Images)
+}
+
+func TestDetachImage(t *testing.T) {
+	service, _, storage, product := setupAssetService(t)
+	storage.On("Put", mock.Anything, "image/png", "binary").Return("https://media.example.com/a.png", nil)
+	_, err := service.AttachImage(context.Background(), product.ID, &models.Image{URL: "https://cdn.example.com/front.jpg"})
+	assert.NoError(t, err)
+	updated, err := service.UploadImage(context.Background(), product.ID, &interfaces.AssetUpload{Filename: "a.png", ContentType: "image/png", Body: strings.NewReader("binary")})
+	assert.NoError(t, err)
+	external, uploaded := updated.Images[0], updated.Images[1]
+
+	// Only uploaded images have a binary to delete
+	storage.On("Delete", uploaded.StorageKey).Return(nil).Once()
+	updated, err = service.DetachImage(context.Background(), product.ID, uploaded.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Image{external}, updated.Images)
+
+	updated, err = service.DetachImage(context.Background(), product.ID, external.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, updated.Images)
+	storage.AssertExpectations(t)
+
+	_, err = service.DetachImage(context.Background(), product.ID, external.ID)
+	assert.ErrorIs(t, err, models.ErrImageNotFound)
+}
+
+func TestReorderImages(t *testing.T) {
+	service, _, _, product := setupAssetService(t)
+	for _, name := range []string{"a", "b", "c"} {
+		_, err := service.AttachImage(context.Background(), product.ID, &models.Image{URL: "https://cdn.example.com/" + name + ".jpg"})
+		assert.NoError(t, err)
+	}
+	current, err := service.AttachImage(context.Background(), product.ID, &models.Image{URL: "https://cdn.example.com/d.jpg"})
+	assert.NoError(t, err)
+	ids := []string{current.Images[3].ID, current.Images[0].ID, current.Images[2].ID, current.Images[1].ID}
+
+	updated, err := service.ReorderImages(context.Background(), product.ID, ids)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/d.jpg", updated.Images[0].URL)
+	assert.Equal(t, "https://cdn.example.com/b.jpg", updated.Images[3].URL)
+
+	_, err = service.ReorderImages(context.Background(), product.ID, ids[:3])
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.ReorderImages(context.Background(), product.ID, []string{ids[0], ids[1], ids[2], "missing"})
+	assert.ErrorIs(t, err, models.ErrImageNotFound)
+	_, err = service.ReorderImages(context.Background(), product.ID, []string{ids[0], ids[1], ids[2], ids[2]})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
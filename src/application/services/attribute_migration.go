@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// migrationPageSize is the page size used when scanning the catalog for a migration
+const migrationPageSize = 100
+
+// MigrateAttributes starts a job that applies an attribute migration to every
+// product in the background and returns the job right away. Each changed
+// product is written as a regular update tagged with the job, so subscribers
+// see the change and the job can be rolled back like a batch.
+func (s *productService) MigrateAttributes(ctx context.Context, migration *models.AttributeMigration) (*models.Job, error) {
+	if err := migration.Validate(); err != nil {
+		return nil, err
+	}
+
+	job, err := s.startJob(models.JobAttributeMigration, 0)
+	if err != nil {
+		return nil, err
+	}
+	started := *job
+
+	go s.runAttributeMigration(context.WithoutCancel(ctx), job, migration)
+	return &started, nil
+}
+
+// runAttributeMigration migrates the products that have the attribute and records the outcome on the job
+func (s *productService) runAttributeMigration(ctx context.Context, job *models.Job, migration *models.AttributeMigration) {
+	logger := logging.Shared().WithFields(zap.String("job_id", job.ID), zap.String("attribute", migration.Key))
+
+	ids, err := s.productsWithAttribute(ctx, migration.Key)
+	if err != nil {
+		logger.Error("Failed to scan catalog for attribute migration", zap.Error(err))
+		job.AddError(err.Error())
+		job.Complete(0, 1)
+		s.jobs.Update(job)
+		return
+	}
+
+	// Publish the total so progress is visible while the job runs
+	job.Total = len(ids)
+	s.jobs.Update(job)
+
+	results := make([]*interfaces.BatchResult, 0, len(ids))
+	for _, id := range ids {
+		result := &interfaces.BatchResult{ID: id, Success: true}
+		if err := s.migrateProduct(ctx, id, migration, job.ID); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			job.AddError(fmt.Sprintf("%s: %v", id, err))
+		}
+		results = append(results, result)
+	}
+
+	if err := s.finishJob(job, results); err != nil {
+		logger.Error("Failed to record attribute migration", zap.Error(err))
+		return
+	}
+	logger.Info("Attribute migration completed",
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("failed", job.Failed),
+	)
+}
+
+// productsWithAttribute returns the IDs of products with a variant that has the attribute.
+// The whole catalog is scanned before anything is written so updates cannot shift the pages.
+func (s *productService) productsWithAttribute(ctx context.Context, key string) ([]string, error) {
+	ids := make([]string, 0)
+	for page := 1; ; page++ {
+		products, total, err := s.repo.List(ctx, models.ProductFilter{}, page, migrationPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list products: %v", err)
+		}
+		for _, product := range products {
+			for _, variant := range product.Variants {
+				if _, exists := variant.Attributes[key]; exists {
+					ids = append(ids, product.ID)
+					break
+				}
+			}
+		}
+		if len(products) == 0 || page*migrationPageSize >= total {
+			return ids, nil
+		}
+	}
+}
+
+// migrateProduct applies the migration to the current state of a product and publishes the update
+func (s *productService) migrateProduct(ctx context.Context, id string, migration *models.AttributeMigration, jobID string) error {
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	migrated, changed, err := migration.Apply(current)
+	if err != nil || !changed {
+		return err
+	}
+	return s.publish(s.updateProduct(ctx, migrated, "attributes_migrated", jobID))
+}
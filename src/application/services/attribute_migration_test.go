@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func createProductWithColour(t *testing.T, service *productService, sku string, colours ...string) *models.Product {
+	product := createValidProduct()
+	product.SKU = sku
+	for i, colour := range colours {
+		product.Variants = append(product.Variants, models.Variant{
+			ID:         fmt.Sprintf("%s-v%d", sku, i),
+			SKU:        fmt.Sprintf("%s-%d", sku, i),
+			Attributes: map[string]string{"colour": colour},
+		})
+	}
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	return product
+}
+
+func waitForJob(t *testing.T, service *productService, jobID string) *models.Job {
+	var job *models.Job
+	assert.Eventually(t, func() bool {
+		job, _ = service.jobs.GetByID(jobID)
+		return job != nil && job.Status != models.JobStatusRunning
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestMigrateAttributes(t *testing.T) {
+	service, _, _ := setupProductService()
+	navy := createProductWithColour(t, service, "SHIRT", "Navy", "Red")
+	plain := createProductWithColour(t, service, "SOCK")
+
+	started, err := service.MigrateAttributes(context.Background(), &models.AttributeMigration{
+		Key:    "colour",
+		NewKey: "color",
+		Values: map[string]string{"Navy": "Dark Blue"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobAttributeMigration, started.Type)
+	assert.Equal(t, models.JobStatusRunning, started.Status)
+
+	job := waitForJob(t, service, started.ID)
+	assert.Equal(t, models.JobStatusCompleted, job.Status)
+	assert.Equal(t, 1, job.Total)
+	assert.Equal(t, 1, job.Succeeded)
+
+	migrated, err := service.GetProduct(context.Background(), navy.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), migrated.Version)
+	assert.Equal(t, map[string]string{"color": "Dark Blue"}, migrated.Variants[0].Attributes)
+	assert.Equal(t, map[string]string{"color": "Red"}, migrated.Variants[1].Attributes)
+
+	// The change is a regular update event tagged with the job
+	events, err := service.repo.GetEventsByProductID(context.Background(), navy.ID, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, models.EventProductUpdated, events[0].Type)
+		assert.Equal(t, job.ID, events[0].JobID)
+		assert.Equal(t, "attributes_migrated", events[0].Data.(*models.ProductEvent).Action)
+	}
+
+	untouched, err := service.GetProduct(context.Background(), plain.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), untouched.Version)
+}
+
+func TestMigrateAttributesRecordsConflicts(t *testing.T) {
+	service, _, _ := setupProductService()
+	createProductWithColour(t, service, "SHIRT", "Navy")
+	conflicting := createValidProduct()
+	conflicting.SKU = "JACKET"
+	conflicting.Variants = []models.Variant{{ID: "j1", SKU: "JACKET-1", Attributes: map[string]string{"colour": "Navy", "color": "Blue"}}}
+	assert.NoError(t, service.CreateProduct(context.Background(), conflicting))
+
+	started, err := service.MigrateAttributes(context.Background(), &models.AttributeMigration{Key: "colour", NewKey: "color"})
+	assert.NoError(t, err)
+
+	job := waitForJob(t, service, started.ID)
+	assert.Equal(t, 2, job.Total)
+	assert.Equal(t, 1, job.Succeeded)
+	assert.Equal(t, 1, job.Failed)
+	if assert.Len(t, job.Errors, 1) {
+		assert.Contains(t, job.Errors[0], conflicting.ID)
+	}
+}
+
+func TestMigrateAttributesCanBeRolledBack(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createProductWithColour(t, service, "SHIRT", "Navy")
+
+	started, err := service.MigrateAttributes(context.Background(), &models.AttributeMigration{Key: "colour", NewKey: "color"})
+	assert.NoError(t, err)
+	waitForJob(t, service, started.ID)
+
+	_, err = service.RollbackJob(context.Background(), started.ID)
+	assert.NoError(t, err)
+
+	reverted, err := service.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"colour": "Navy"}, reverted.Variants[0].Attributes)
+}
+
+func TestMigrateAttributesInvalid(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	_, err := service.MigrateAttributes(context.Background(), &models.AttributeMigration{Key: "colour"})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	jobs, _ := service.jobs.List(0)
+	assert.Empty(t, jobs)
+}
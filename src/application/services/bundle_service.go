@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// bundleService implements the BundleService interface
+type bundleService struct {
+	products interfaces.ProductService
+}
+
+// NewBundleService creates a new bundle service instance. Bundles are
+// computed from the current components on every call, so price and stock
+// changes of a component apply right away.
+func NewBundleService(products interfaces.ProductService) interfaces.BundleService {
+	return &bundleService{
+		products: products,
+	}
+}
+
+// GetBundle computes the price and availability of a bundle
+func (s *bundleService) GetBundle(ctx context.Context, productID string) (*models.BundleSummary, error) {
+	bundle, err := s.products.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.Bundle == nil {
+		return nil, models.ErrNotABundle
+	}
+
+	components := make(map[string]*models.Product, len(bundle.Bundle.Components))
+	for _, component := range bundle.Bundle.Components {
+		if _, loaded := components[component.ProductID]; loaded {
+			continue
+		}
+		product, err := s.products.GetProduct(ctx, component.ProductID)
+		if errors.Is(err, models.ErrProductNotFound) {
+			// Deleted components leave the bundle unavailable
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		components[component.ProductID] = product
+	}
+	return bundle.Bundle.Summarize(bundle, components), nil
+}
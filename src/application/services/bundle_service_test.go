@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// createBundle creates a bundle of two units of a stocked product's variant
+func createBundle(t *testing.T, service *productService, component *models.Product, strategy models.BundlePriceStrategy) *models.Product {
+	bundle := createValidProduct()
+	bundle.Bundle = &models.Bundle{
+		Components:    []models.BundleComponent{{ProductID: component.ID, VariantID: "v1", Quantity: 2}},
+		PriceStrategy: strategy,
+	}
+	if strategy == models.BundlePriceDiscount {
+		bundle.Bundle.DiscountPercent = 10
+	}
+	assert.NoError(t, service.CreateProduct(context.Background(), bundle))
+	return bundle
+}
+
+func TestGetBundle(t *testing.T) {
+	products, _, _ := setupProductService()
+	service := NewBundleService(products)
+	component := createStockedProduct(t, products, 5)
+	bundle := createBundle(t, products, component, models.BundlePriceDiscount)
+
+	summary, err := service.GetBundle(context.Background(), bundle.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Price{{Currency: "SEK", Amount: 180}}, summary.Prices)
+	assert.Equal(t, []models.Price{{Currency: "SEK", Amount: 200}}, summary.ComponentSum)
+	assert.Equal(t, 2, *summary.Available)
+
+	// Component stock changes apply right away
+	_, err = products.AdjustStock(context.Background(), component.ID, []models.StockAdjustment{{VariantID: "v1", LocationID: "wh1", Delta: -4}})
+	assert.NoError(t, err)
+	summary, err = service.GetBundle(context.Background(), bundle.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *summary.Available)
+
+	// A deleted component leaves the bundle unavailable
+	_, err = products.SoftDeleteProduct(context.Background(), component.ID)
+	assert.NoError(t, err)
+	summary, err = service.GetBundle(context.Background(), bundle.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, summary.Prices)
+	assert.False(t, summary.Components[0].Available)
+}
+
+func TestGetBundleErrors(t *testing.T) {
+	products, _, _ := setupProductService()
+	service := NewBundleService(products)
+	component := createStockedProduct(t, products, 5)
+
+	_, err := service.GetBundle(context.Background(), component.ID)
+	assert.ErrorIs(t, err, models.ErrNotABundle)
+	_, err = service.GetBundle(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestCreateBundleChecksComponents(t *testing.T) {
+	products, _, _ := setupProductService()
+	component := createStockedProduct(t, products, 5)
+	bundle := createBundle(t, products, component, "")
+	assert.Equal(t, models.BundlePriceSum, bundle.Bundle.PriceStrategy)
+
+	for name, components := range map[string][]models.BundleComponent{
+		"missing product": {{ProductID: "missing", Quantity: 1}},
+		"no variant":      {{ProductID: component.ID, Quantity: 1}},
+		"unknown variant": {{ProductID: component.ID, VariantID: "v9", Quantity: 1}},
+		"nested bundle":   {{ProductID: bundle.ID, Quantity: 1}},
+	} {
+		product := createValidProduct()
+		product.Bundle = &models.Bundle{Components: components}
+		assert.ErrorIs(t, products.CreateProduct(context.Background(), product), models.ErrInvalidRequest, name)
+	}
+
+	// Updates are checked the same way and recorded as a bundle change
+	updated := bundle.Clone()
+	updated.Bundle.Components[0].Quantity = 3
+	assert.NoError(t, products.UpdateProduct(context.Background(), updated))
+	events, err := products.repo.GetEventsByProductID(context.Background(), bundle.ID, 0)
+	assert.NoError(t, err)
+	changes := events[len(events)-1].Data.(*models.ProductEvent).Changes
+	assert.Equal(t, "bundle", changes[len(changes)-1].Field)
+
+	updated.Bundle.Components[0].ProductID = bundle.ID
+	assert.ErrorIs(t, products.UpdateProduct(context.Background(), updated), models.ErrInvalidRequest)
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// catalogPageSize is the page size used when scanning the catalog for an export
+const catalogPageSize = 100
+
+// ExportCatalog copies every product matching the filter. With anonymizePrices
+// the real prices never leave this environment.
+func (s *productService) ExportCatalog(ctx context.Context, filter models.CatalogFilter, anonymizePrices bool) (*models.CatalogExport, error) {
+	export := &models.CatalogExport{
+		ExportedAt: time.Now(),
+		Filter:     filter,
+		Products:   make([]*models.Product, 0),
+	}
+
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		products, total, err := s.repo.List(ctx, models.ProductFilter{}, page, catalogPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list products: %v", err)
+		}
+		for _, product := range products {
+			if filter.Matches(product) {
+				export.Products = append(export.Products, product.Clone())
+			}
+		}
+		if len(products) == 0 || page*catalogPageSize >= total {
+			break
+		}
+	}
+
+	if anonymizePrices {
+		export.AnonymizePrices()
+	}
+	return export, nil
+}
+
+// ImportCatalog starts a job that writes the products of an export in the
+// background and returns the job right away. Every write is a regular event
+// tagged with the job, so an import can be rolled back like a batch.
+func (s *productService) ImportCatalog(ctx context.Context, req *interfaces.CatalogImportRequest) (*models.Job, error) {
+	if req.Catalog == nil {
+		return nil, fmt.Errorf("%w: catalog is required", models.ErrInvalidRequest)
+	}
+	if req.AnonymizePrices && !req.Catalog.PricesAnonymized {
+		req.Catalog.AnonymizePrices()
+	}
+
+	job, err := s.startJob(models.JobCatalogImport, len(req.Catalog.Products))
+	if err != nil {
+		return nil, err
+	}
+	if req.Source != "" {
+		job.Source = req.Source
+		s.jobs.Update(job)
+	}
+	started := *job
+
+	go s.runCatalogImport(context.WithoutCancel(ctx), job, req)
+	return &started, nil
+}
+
+// runCatalogImport writes the exported products and records the outcome on the job
+func (s *productService) runCatalogImport(ctx context.Context, job *models.Job, req *interfaces.CatalogImportRequest) {
+	logger := logging.Shared().WithFields(zap.String("job_id", job.ID), zap.String("source", req.Source))
+
+	results := make([]*interfaces.BatchResult, 0, len(req.Catalog.Products))
+	for _, exported := range req.Catalog.Products {
+		result := &interfaces.BatchResult{ID: exported.ID, Success: true}
+		id, err := s.importCatalogProduct(ctx, exported, req.PreserveIDs, job.ID)
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			job.AddError(fmt.Sprintf("%s: %v", exported.SKU, err))
+		} else {
+			result.ID = id
+		}
+		results = append(results, result)
+	}
+
+	if err := s.finishJob(job, results); err != nil {
+		logger.Error("Failed to record catalog import", zap.Error(err))
+		return
+	}
+	logger.Info("Catalog import completed",
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("failed", job.Failed),
+		zap.Bool("preserve_ids", req.PreserveIDs),
+	)
+}
+
+// importCatalogProduct writes one exported product and returns its ID here.
+// The source's version, hash and timestamps are not kept: the product gets its
+// own history in this environment.
+func (s *productService) importCatalogProduct(ctx context.Context, exported *models.Product, preserveIDs bool, jobID string) (string, error) {
+	product := exported.Clone()
+	product.Version = 0
+	product.LastHash = ""
+
+	if !preserveIDs || product.ID == "" {
+		if err := models.ValidateNewProduct(product); err != nil {
+			return "", err
+		}
+		return product.ID, s.publish(s.createProduct(ctx, product, jobID))
+	}
+
+	if err := models.ValidateProduct(product); err != nil {
+		return "", err
+	}
+	current, err := s.repo.GetByID(ctx, product.ID)
+	if errors.Is(err, models.ErrProductNotFound) {
+		return product.ID, s.publish(s.insertProduct(ctx, product, jobID))
+	}
+	if err != nil {
+		return "", err
+	}
+	product.Version = current.Version
+	product.CreatedAt = current.CreatedAt
+	return product.ID, s.publish(s.updateProduct(ctx, product, "cloned", jobID))
+}
+
+// catalogCloneService implements the CatalogCloneService interface
+type catalogCloneService struct {
+	products interfaces.ProductService
+	sources  map[string]interfaces.CatalogSource
+}
+
+// NewCatalogCloneService creates a service that clones catalogs from the named environments
+func NewCatalogCloneService(products interfaces.ProductService, sources map[string]interfaces.CatalogSource) interfaces.CatalogCloneService {
+	return &catalogCloneService{
+		products: products,
+		sources:  sources,
+	}
+}
+
+// Sources returns the configured environment names in order
+func (s *catalogCloneService) Sources() []string {
+	names := make([]string, 0, len(s.sources))
+	for name := range s.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CloneCatalog fetches the export before starting the import so an unreachable
+// source is reported right away. Prices are anonymized by the source, so they
+// never leave it.
+func (s *catalogCloneService) CloneCatalog(ctx context.Context, req *interfaces.CatalogCloneRequest) (*models.Job, error) {
+	source, exists := s.sources[req.Source]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", models.ErrCatalogSourceNotFound, req.Source)
+	}
+
+	export, err := source.FetchCatalog(req.Filter, req.AnonymizePrices)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", models.ErrCatalogSourceFailed, req.Source, err)
+	}
+
+	return s.products.ImportCatalog(ctx, &interfaces.CatalogImportRequest{
+		Catalog:         export,
+		PreserveIDs:     req.PreserveIDs,
+		AnonymizePrices: req.AnonymizePrices,
+		Source:          req.Source,
+	})
+}
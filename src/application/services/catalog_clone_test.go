@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockCatalogSource is a mock for the CatalogSource interface
+type MockCatalogSource struct {
+	mock.Mock
+}
+
+func (m *MockCatalogSource) FetchCatalog(filter models.CatalogFilter, anonymizePrices bool) (*models.CatalogExport, error) {
+	args := m.Called(filter, anonymizePrices)
+	if export, ok := args.Get(0).(*models.CatalogExport); ok {
+		return export, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestExportCatalog(t *testing.T) {
+	service, _, _ := setupProductService()
+	shirt := createProductWithColour(t, service, "SHIRT-1", "Navy")
+	createProductWithColour(t, service, "SOCK-1", "Red")
+
+	export, err := service.ExportCatalog(context.Background(), models.CatalogFilter{SKUPrefix: "SHIRT"}, true)
+	assert.NoError(t, err)
+	assert.True(t, export.PricesAnonymized)
+	assert.Len(t, export.Products, 1)
+	assert.Equal(t, shirt.ID, export.Products[0].ID)
+	assert.NotEqual(t, 100.0, export.Products[0].Prices[0].Amount)
+
+	// Anonymizing the export must not touch the stored product
+	stored, err := service.GetProduct(context.Background(), shirt.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, stored.Prices[0].Amount)
+}
+
+func TestImportCatalog(t *testing.T) {
+	source, _, _ := setupProductService()
+	shirt := createProductWithColour(t, source, "SHIRT-1", "Navy")
+	sock := createProductWithColour(t, source, "SOCK-1", "Red")
+	export, err := source.ExportCatalog(context.Background(), models.CatalogFilter{}, false)
+	assert.NoError(t, err)
+
+	t.Run("new ids", func(t *testing.T) {
+		target, _, _ := setupProductService()
+		started, err := target.ImportCatalog(context.Background(), &interfaces.CatalogImportRequest{Catalog: export})
+		assert.NoError(t, err)
+		assert.Equal(t, models.JobCatalogImport, started.Type)
+
+		job := waitForJob(t, target, started.ID)
+		assert.Equal(t, 2, job.Succeeded)
+		_, err = target.GetProduct(context.Background(), shirt.ID)
+		assert.True(t, errors.Is(err, models.ErrProductNotFound))
+	})
+
+	t.Run("preserved ids", func(t *testing.T) {
+		target, _, _ := setupProductService()
+		existing := sock.Clone()
+		existing.Version = 0
+		existing.BaseTitle = "Old sock"
+		_, err := target.insertProduct(context.Background(), existing, "")
+		assert.NoError(t, err)
+
+		started, err := target.ImportCatalog(context.Background(), &interfaces.CatalogImportRequest{Catalog: export, PreserveIDs: true, AnonymizePrices: true, Source: "staging"})
+		assert.NoError(t, err)
+		assert.Equal(t, "staging", started.Source)
+
+		job := waitForJob(t, target, started.ID)
+		assert.Equal(t, 2, job.Succeeded)
+
+		created, err := target.GetProduct(context.Background(), shirt.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), created.Version)
+		assert.NotEqual(t, 100.0, created.Prices[0].Amount)
+
+		// Products that already exist are updated to the source's state
+		updated, err := target.GetProduct(context.Background(), sock.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), updated.Version)
+		assert.Equal(t, sock.BaseTitle, updated.BaseTitle)
+	})
+
+	t.Run("invalid products fail", func(t *testing.T) {
+		target, _, _ := setupProductService()
+		started, err := target.ImportCatalog(context.Background(), &interfaces.CatalogImportRequest{
+			Catalog: &models.CatalogExport{Products: []*models.Product{{SKU: "BROKEN"}}},
+		})
+		assert.NoError(t, err)
+		job := waitForJob(t, target, started.ID)
+		assert.Equal(t, 1, job.Failed)
+		assert.Contains(t, job.Errors[0], "BROKEN:")
+	})
+
+	_, err = source.ImportCatalog(context.Background(), &interfaces.CatalogImportRequest{})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
+
+func TestCloneCatalog(t *testing.T) {
+	target, _, _ := setupProductService()
+	staging := new(MockCatalogSource)
+	service := NewCatalogCloneService(target, map[string]interfaces.CatalogSource{"staging": staging, "prod": new(MockCatalogSource)})
+	assert.Equal(t, []string{"prod", "staging"}, service.Sources())
+
+	product := createValidProduct()
+	product.ID = "prod_1"
+	filter := models.CatalogFilter{SKUPrefix: "TEST"}
+	staging.On("FetchCatalog", filter, true).Return(&models.CatalogExport{PricesAnonymized: true, Products: []*models.Product{product}}, nil).Once()
+
+	started, err := service.CloneCatalog(context.Background(), &interfaces.CatalogCloneRequest{Source: "staging", Filter: filter, PreserveIDs: true, AnonymizePrices: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "staging", started.Source)
+	job := waitForJob(t, target, started.ID)
+	assert.Equal(t, 1, job.Succeeded)
+
+	cloned, err := target.GetProduct(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	// The source anonymized the prices already, so they are not changed again
+	assert.Equal(t, 100.0, cloned.Prices[0].Amount)
+
+	_, err = service.CloneCatalog(context.Background(), &interfaces.CatalogCloneRequest{Source: "unknown"})
+	assert.True(t, errors.Is(err, models.ErrCatalogSourceNotFound))
+
+	staging.On("FetchCatalog", models.CatalogFilter{}, false).Return(nil, errors.New("connection refused"))
+	_, err = service.CloneCatalog(context.Background(), &interfaces.CatalogCloneRequest{Source: "staging"})
+	assert.True(t, errors.Is(err, models.ErrCatalogSourceFailed))
+}
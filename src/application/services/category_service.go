@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// categoryService implements the CategoryService interface
+type categoryService struct {
+	categories repositories.CategoryRepository
+	products   interfaces.ProductService
+	publisher  events.EventPublisher
+
+	// mu serializes changes to the tree, so placements are checked against
+	// the tree they are saved in
+	mu sync.Mutex
+}
+
+// NewCategoryService creates a new category service instance. Changes to
+// categories are published as category events; assignments go through the
+// product service as product updates.
+func NewCategoryService(categories repositories.CategoryRepository, products interfaces.ProductService, publisher events.EventPublisher) interfaces.CategoryService {
+	return &categoryService{
+		categories: categories,
+		products:   products,
+		publisher:  publisher,
+	}
+}
+
+// ListCategories returns every category
+func (s *categoryService) ListCategories() ([]*models.Category, error) {
+	return s.categories.List()
+}
+
+// CategoryTree returns the categories as nested nodes
+func (s *categoryService) CategoryTree() ([]*models.CategoryNode, error) {
+	tree, err := s.tree()
+	if err != nil {
+		return nil, err
+	}
+	return tree.Nodes(), nil
+}
+
+// GetCategory returns a category
+func (s *categoryService) GetCategory(id string) (*models.Category, error) {
+	return s.categories.Get(id)
+}
+
+// CreateCategory stores a new category with a generated ID
+func (s *categoryService) CreateCategory(category *models.Category) (*models.Category, error) {
+	created := *category
+	created.ID = uuid.New().String()
+	if err := created.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.checkPlacement(&created); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	if err := s.categories.Save(&created); err != nil {
+		return nil, fmt.Errorf("failed to save category: %v", err)
+	}
+	s.publish(models.EventCategoryCreated, "created", &created)
+	return &created, nil
+}
+
+// UpdateCategory replaces a category, keeping its creation time
+func (s *categoryService) UpdateCategory(category *models.Category) (*models.Category, error) {
+	updated := *category
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, err := s.categories.Get(updated.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkPlacement(&updated); err != nil {
+		return nil, err
+	}
+	updated.CreatedAt = previous.CreatedAt
+	updated.UpdatedAt = time.Now()
+	if err := s.categories.Save(&updated); err != nil {
+		return nil, fmt.Errorf("failed to save category: %v", err)
+	}
+	action := "updated"
+	if previous.ParentID != updated.ParentID {
+		action = "moved"
+	}
+	s.publish(models.EventCategoryUpdated, action, &updated)
+	return &updated, nil
+}
+
+// DeleteCategory removes a category once nothing is below or in it
+func (s *categoryService) DeleteCategory(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tree, err := s.tree()
+	if err != nil {
+		return err
+	}
+	category, ok := tree.Get(id)
+	if !ok {
+		return models.ErrCategoryNotFound
+	}
+	if children := tree.Children(id); len(children) > 0 {
+		return fmt.Errorf("%w: %d subcategories", models.ErrCategoryInUse, len(children))
+	}
+	if _, total, err := s.products.ListProducts(ctx, models.ProductFilter{Categories: []string{id}}, 1, 1); err != nil {
+		return err
+	} else if total > 0 {
+		return fmt.Errorf("%w: %d products", models.ErrCategoryInUse, total)
+	}
+	if err := s.categories.Delete(id); err != nil {
+		return err
+	}
+	s.publish(models.EventCategoryDeleted, "deleted", category)
+	return nil
+}
+
+// AssignCategories replaces the categories of a product
+func (s *categoryService) AssignCategories(ctx context.Context, productID string, categoryIDs []string) (*models.Product, error) {
+	categoryIDs = models.NormalizeCategoryIDs(categoryIDs)
+	for _, id := range categoryIDs {
+		if _, err := s.categories.Get(id); err != nil {
+			return nil, fmt.Errorf("%w: category %s not found", models.ErrInvalidRequest, id)
+		}
+	}
+	return s.products.AssignCategories(ctx, productID, categoryIDs)
+}
+
+// ListProducts lists the products in a category, and optionally below it
+func (s *categoryService) ListProducts(ctx context.Context, categoryID string, includeSubcategories bool, page, pageSize int) ([]*models.Product, int, error) {
+	tree, err := s.tree()
+	if err != nil {
+		return nil, 0, err
+	}
+	if _, ok := tree.Get(categoryID); !ok {
+		return nil, 0, models.ErrCategoryNotFound
+	}
+	categories := []string{categoryID}
+	if includeSubcategories {
+		categories = tree.Descendants(categoryID)
+	}
+	return s.products.ListProducts(ctx, models.ProductFilter{Categories: categories}, page, pageSize)
+}
+
+// tree indexes every stored category
+func (s *categoryService) tree() (*models.CategoryTree, error) {
+	categories, err := s.categories.List()
+	if err != nil {
+		return nil, err
+	}
+	return models.NewCategoryTree(categories), nil
+}
+
+// checkPlacement checks a category against the stored tree
+func (s *categoryService) checkPlacement(category *models.Category) error {
+	tree, err := s.tree()
+	if err != nil {
+		return err
+	}
+	return tree.CheckPlacement(category)
+}
+
+// publish announces a change to a category. Category events have no product
+// version, so they are published without being stored.
+func (s *categoryService) publish(eventType models.EventType, action string, category *models.Category) {
+	s.publisher.Publish(&models.Event{
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		EntityID:      category.ID,
+		SchemaVersion: models.EventSchemaVersion,
+		Data: &models.CategoryEvent{
+			CategoryID: category.ID,
+			Action:     action,
+			Category:   category,
+		},
+		Timestamp: time.Now(),
+	})
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+// setupCategoryService returns a category service over a memory product service, and its publisher
+func setupCategoryService() (*categoryService, *productService, *MockEventPublisher) {
+	products, _, _ := setupProductService()
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.AnythingOfType("*models.Event")).Return(nil).Maybe()
+	service := NewCategoryService(memory.NewCategoryRepository(), products, publisher).(*categoryService)
+	return service, products, publisher
+}
+
+// publishedCategoryEvents returns the events of every Publish call
+func publishedCategoryEvents(publisher *MockEventPublisher) []*models.Event {
+	var published []*models.Event
+	for _, call := range publisher.Calls {
+		if call.Method == "Publish" {
+			published = append(published, call.Arguments.Get(0).(*models.Event))
+		}
+	}
+	return published
+}
+
+func createCategory(t *testing.T, service *categoryService, slug, parentID string) *models.Category {
+	category, err := service.CreateCategory(&models.Category{Slug: slug, ParentID: parentID, Names: map[string]string{"SE": slug}})
+	assert.NoError(t, err)
+	return category
+}
+
+func TestCreateCategory(t *testing.T) {
+	service, _, publisher := setupCategoryService()
+
+	clothing := createCategory(t, service, "Clothing", "")
+	assert.NotEmpty(t, clothing.ID)
+	assert.Equal(t, "clothing", clothing.Slug)
+	assert.False(t, clothing.CreatedAt.IsZero())
+	shirts := createCategory(t, service, "shirts", clothing.ID)
+
+	_, err := service.CreateCategory(&models.Category{Slug: "shirts", ParentID: clothing.ID, Names: map[string]string{"SE": "Skjortor"}})
+	assert.ErrorIs(t, err, models.ErrDuplicateCategorySlug)
+	_, err = service.CreateCategory(&models.Category{Slug: "shirts", ParentID: "missing", Names: map[string]string{"SE": "Skjortor"}})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	published := publishedCategoryEvents(publisher)
+	assert.Len(t, published, 2)
+	assert.Equal(t, models.EventCategoryCreated, published[1].Type)
+	assert.Equal(t, shirts.ID, published[1].EntityID)
+	data := published[1].Data.(*models.CategoryEvent)
+	assert.Equal(t, "created", data.Action)
+	assert.Equal(t, clothing.ID, data.Category.ParentID)
+	assert.NoError(t, models.ValidateEvent(published[1]))
+
+	tree, err := service.CategoryTree()
+	assert.NoError(t, err)
+	assert.Len(t, tree, 1)
+	assert.Equal(t, shirts.ID, tree[0].Children[0].ID)
+}
+
+func TestUpdateCategory(t *testing.T) {
+	service, _, publisher := setupCategoryService()
+	clothing := createCategory(t, service, "clothing", "")
+	sale := createCategory(t, service, "sale", "")
+	shirts := createCategory(t, service, "shirts", clothing.ID)
+
+	moved := *shirts
+	moved.ParentID = sale.ID
+	moved.Names = map[string]string{"SE": "Skjortor", "DE": "Hemden"}
+	updated, err := service.UpdateCategory(&moved)
+	assert.NoError(t, err)
+	assert.Equal(t, shirts.CreatedAt, updated.CreatedAt)
+	assert.Equal(t, sale.ID, updated.ParentID)
+
+	published := publishedCategoryEvents(publisher)
+	assert.Equal(t, models.EventCategoryUpdated, published[3].Type)
+	assert.Equal(t, "moved", published[3].Data.(*models.CategoryEvent).Action)
+
+	renamed := *updated
+	renamed.Names = map[string]string{"SE": "Skjortor"}
+	_, err = service.UpdateCategory(&renamed)
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", publishedCategoryEvents(publisher)[4].Data.(*models.CategoryEvent).Action)
+
+	// A category cannot be moved below its own subcategory
+	cycle := *sale
+	cycle.ParentID = shirts.ID
+	_, err = service.UpdateCategory(&cycle)
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	missing := *sale
+	missing.ID = "missing"
+	_, err = service.UpdateCategory(&missing)
+	assert.ErrorIs(t, err, models.ErrCategoryNotFound)
+}
+
+func TestDeleteCategory(t *testing.T) {
+	service, _, publisher := setupCategoryService()
+	clothing := createCategory(t, service, "clothing", "")
+	shirts := createCategory(t, service, "shirts", clothing.ID)
+	product := createValidProduct()
+	assert.NoError(t, service.products.CreateProduct(context.Background(), product))
+	_, err := service.AssignCategories(context.Background(), product.ID, []string{shirts.ID})
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, service.DeleteCategory(context.Background(), clothing.ID), models.ErrCategoryInUse)
+	assert.ErrorIs(t, service.DeleteCategory(context.Background(), shirts.ID), models.ErrCategoryInUse)
+
+	_, err = service.AssignCategories(context.Background(), product.ID, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, service.DeleteCategory(context.Background(), shirts.ID))
+	assert.NoError(t, service.DeleteCategory(context.Background(), clothing.ID))
+	assert.ErrorIs(t, service.DeleteCategory(context.Background(), clothing.ID), models.ErrCategoryNotFound)
+
+	published := publishedCategoryEvents(publisher)
+	last := published[len(published)-1]
+	assert.Equal(t, models.EventCategoryDeleted, last.Type)
+	assert.Equal(t, clothing.ID, last.EntityID)
+}
+
+func TestCategoryListProducts(t *testing.T) {
+	service, products, _ := setupCategoryService()
+	clothing := createCategory(t, service, "clothing", "")
+	shirts := createCategory(t, service, "shirts", clothing.ID)
+
+	coat := createValidProduct()
+	shirt := createValidProduct()
+	assert.NoError(t, products.CreateProduct(context.Background(), coat))
+	assert.NoError(t, products.CreateProduct(context.Background(), shirt))
+	_, err := service.AssignCategories(context.Background(), coat.ID, []string{clothing.ID})
+	assert.NoError(t, err)
+	_, err = service.AssignCategories(context.Background(), shirt.ID, []string{shirts.ID})
+	assert.NoError(t, err)
+
+	_, err = service.AssignCategories(context.Background(), shirt.ID, []string{"missing"})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	listed, total, err := service.ListProducts(context.Background(), clothing.ID, true, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, listed, 2)
+
+	listed, total, err = service.ListProducts(context.Background(), clothing.ID, false, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, coat.ID, listed[0].ID)
+
+	_, _, err = service.ListProducts(context.Background(), "missing", true, 1, 10)
+	assert.ErrorIs(t, err, models.ErrCategoryNotFound)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// DefaultRatesTTL is how long fetched exchange rates are used by default.
+// The ECB publishes new rates once per working day.
+const DefaultRatesTTL = time.Hour
+
+type currencyService struct {
+	provider interfaces.ExchangeRateProvider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	rates     *models.ExchangeRates
+	fetchedAt time.Time
+}
+
+// NewCurrencyService creates a currency service that fetches rates from the
+// provider at most once per ttl
+func NewCurrencyService(provider interfaces.ExchangeRateProvider, ttl time.Duration) interfaces.CurrencyService {
+	return &currencyService{
+		provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// Rates returns the cached rates, fetching them again once they are older
+// than the ttl. A failed fetch keeps serving the previous rates, which carry
+// the time they are from, so a provider outage does not stop conversions;
+// the provider is asked again after another ttl.
+func (s *currencyService) Rates() (*models.ExchangeRates, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rates != nil && s.now().Sub(s.fetchedAt) < s.ttl {
+		return s.rates, nil
+	}
+	rates, err := s.provider.Rates()
+	if err == nil {
+		err = rates.Validate()
+	}
+	if err != nil {
+		if s.rates != nil {
+			s.fetchedAt = s.now()
+			return s.rates, nil
+		}
+		return nil, err
+	}
+	s.rates, s.fetchedAt = rates, s.now()
+	return rates, nil
+}
+
+func (s *currencyService) Convert(amount float64, from, to string) (float64, error) {
+	rates, err := s.Rates()
+	if err != nil {
+		return 0, err
+	}
+	return rates.Convert(amount, from, to)
+}
+
+func (s *currencyService) DisplayProduct(product *models.Product, currency string) (*models.DisplayedProduct, error) {
+	if !models.IsCurrencyCode(currency) {
+		return nil, fmt.Errorf("%w: %q", models.ErrUnknownCurrency, currency)
+	}
+	rates, err := s.Rates()
+	if err != nil {
+		return nil, err
+	}
+	return product.Displayed(rates, currency)
+}
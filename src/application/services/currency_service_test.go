@@ -0,0 +1,91 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockExchangeRateProvider is a mock for the ExchangeRateProvider interface
+type MockExchangeRateProvider struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateProvider) Name() string {
+	return "mock"
+}
+
+func (m *MockExchangeRateProvider) Rates() (*models.ExchangeRates, error) {
+	args := m.Called()
+	if rates, ok := args.Get(0).(*models.ExchangeRates); ok {
+		return rates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func euroRates() *models.ExchangeRates {
+	return &models.ExchangeRates{Base: "EUR", Rates: map[string]float64{"SEK": 11.5, "NOK": 11.7}, AsOf: time.Now()}
+}
+
+func TestCurrencyServiceCachesRates(t *testing.T) {
+	provider := new(MockExchangeRateProvider)
+	provider.On("Rates").Return(euroRates(), nil).Once()
+	service := NewCurrencyService(provider, time.Hour).(*currencyService)
+	now := time.Now()
+	service.now = func() time.Time { return now }
+
+	amount, err := service.Convert(115, "SEK", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, amount)
+	amount, err = service.Convert(10, "eur", "nok")
+	assert.NoError(t, err)
+	assert.Equal(t, 117.0, amount)
+	provider.AssertNumberOfCalls(t, "Rates", 1)
+
+	// Expired rates are kept when the provider fails
+	now = now.Add(2 * time.Hour)
+	provider.On("Rates").Return(nil, errors.New("feed unavailable")).Once()
+	amount, err = service.Convert(115, "SEK", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, 10.0, amount)
+	provider.AssertNumberOfCalls(t, "Rates", 2)
+
+	_, err = service.Convert(10, "EUR", "USD")
+	assert.ErrorIs(t, err, models.ErrExchangeRateNotFound)
+}
+
+func TestCurrencyServiceRatesErrors(t *testing.T) {
+	provider := new(MockExchangeRateProvider)
+	provider.On("Rates").Return(nil, errors.New("feed unavailable")).Once()
+	service := NewCurrencyService(provider, time.Hour)
+
+	_, err := service.Rates()
+	assert.Error(t, err)
+
+	// Rates with unknown currencies are rejected
+	provider.On("Rates").Return(&models.ExchangeRates{Base: "EUR", Rates: map[string]float64{"KRONOR": 11}}, nil).Once()
+	_, err = service.Rates()
+	assert.ErrorIs(t, err, models.ErrUnknownCurrency)
+}
+
+func TestCurrencyServiceDisplayProduct(t *testing.T) {
+	provider := new(MockExchangeRateProvider)
+	provider.On("Rates").Return(euroRates(), nil)
+	service := NewCurrencyService(provider, time.Hour)
+	product := createValidProduct()
+
+	displayed, err := service.DisplayProduct(product, "eur")
+	assert.NoError(t, err)
+	assert.Equal(t, product, displayed.Product)
+	assert.Equal(t, "EUR", displayed.DisplayPrice.Currency)
+	assert.Equal(t, "SEK", displayed.DisplayPrice.ConvertedFrom)
+
+	_, err = service.DisplayProduct(product, "EURO")
+	assert.ErrorIs(t, err, models.ErrUnknownCurrency)
+	provider.AssertNumberOfCalls(t, "Rates", 1)
+}
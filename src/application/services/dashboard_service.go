@@ -0,0 +1,247 @@
+package services
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// recentEventCapacity is the number of events kept for the activity feed
+const recentEventCapacity = 500
+
+// dashboardService implements the DashboardService interface
+type dashboardService struct {
+	jobs         repositories.JobRepository
+	requests     interfaces.RequestStatsProvider
+	clients      interfaces.ClientCounter
+	consumers    interfaces.ConsumerLagProvider
+	latency      interfaces.LatencyObjectiveProvider
+	deprecations interfaces.DeprecationUsageProvider
+
+	mu     sync.RWMutex
+	recent []*models.Event // ring buffer of the latest events
+	next   int
+	edits  map[string]*interfaces.EditedProduct
+}
+
+// NewDashboardService creates a dashboard service that tracks product events from the publisher
+func NewDashboardService(publisher events.EventPublisher, jobs repositories.JobRepository, requests interfaces.RequestStatsProvider, clients interfaces.ClientCounter, consumers interfaces.ConsumerLagProvider, latency interfaces.LatencyObjectiveProvider, deprecations interfaces.DeprecationUsageProvider) interfaces.DashboardService {
+	s := &dashboardService{
+		jobs:         jobs,
+		requests:     requests,
+		clients:      clients,
+		consumers:    consumers,
+		latency:      latency,
+		deprecations: deprecations,
+		recent:       make([]*models.Event, 0, recentEventCapacity),
+		edits:        make(map[string]*interfaces.EditedProduct),
+	}
+
+	for _, eventType := range []models.EventType{
+		models.EventProductCreated,
+		models.EventProductUpdated,
+		models.EventProductDeleted,
+		models.EventProductRestored,
+	} {
+		publisher.Subscribe(eventType, s.recordEvent)
+	}
+
+	return s
+}
+
+// recordEvent adds an event to the activity feed and edit statistics
+func (s *dashboardService) recordEvent(event *models.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.recent) < recentEventCapacity {
+		s.recent = append(s.recent, event)
+	} else {
+		s.recent[s.next] = event
+	}
+	s.next = (s.next + 1) % recentEventCapacity
+
+	productEvent, ok := event.Data.(*models.ProductEvent)
+	if !ok {
+		return
+	}
+
+	switch event.Type {
+	case models.EventProductDeleted:
+		delete(s.edits, productEvent.ProductID)
+	case models.EventProductUpdated:
+		edit, exists := s.edits[productEvent.ProductID]
+		if !exists {
+			edit = &interfaces.EditedProduct{ProductID: productEvent.ProductID}
+			s.edits[productEvent.ProductID] = edit
+		}
+		edit.EditCount++
+		edit.LastEditedAt = event.Timestamp
+		if productEvent.Product != nil {
+			edit.SKU = productEvent.Product.SKU
+			edit.Title = productEvent.Product.BaseTitle
+		}
+	}
+}
+
+// Reset drops the activity feed and edit statistics, so a rebuild can replay them from the event log
+func (s *dashboardService) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recent = make([]*models.Event, 0, recentEventCapacity)
+	s.next = 0
+	s.edits = make(map[string]*interfaces.EditedProduct)
+}
+
+// RecentEvents returns the latest events, newest first
+func (s *dashboardService) RecentEvents(limit int) []*models.Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := len(s.recent)
+	if limit <= 0 || limit > count {
+		limit = count
+	}
+
+	result := make([]*models.Event, 0, limit)
+	for i := 1; i <= limit; i++ {
+		index := (s.next - i + count) % count
+		result = append(result, s.recent[index])
+	}
+	return result
+}
+
+// TopEditedProducts returns the products with the most updates, most recent edit breaking ties
+func (s *dashboardService) TopEditedProducts(limit int) []*interfaces.EditedProduct {
+	s.mu.RLock()
+	result := make([]*interfaces.EditedProduct, 0, len(s.edits))
+	for _, edit := range s.edits {
+		copied := *edit
+		result = append(result, &copied)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].EditCount != result[j].EditCount {
+			return result[i].EditCount > result[j].EditCount
+		}
+		return result[i].LastEditedAt.After(result[j].LastEditedAt)
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// RecentlyUpdatedProducts returns the latest state of recently created or updated products,
+// most recently changed first. Products whose latest event is a deletion are skipped.
+func (s *dashboardService) RecentlyUpdatedProducts(limit int) []*interfaces.ProductSummary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	count := len(s.recent)
+	seen := make(map[string]bool)
+	result := make([]*interfaces.ProductSummary, 0)
+
+	for i := 1; i <= count; i++ {
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+
+		event := s.recent[(s.next-i+count)%count]
+		productEvent, ok := event.Data.(*models.ProductEvent)
+		if !ok || seen[productEvent.ProductID] {
+			continue
+		}
+		seen[productEvent.ProductID] = true
+
+		if event.Type == models.EventProductDeleted || productEvent.Product == nil {
+			continue
+		}
+		result = append(result, &interfaces.ProductSummary{
+			ProductID: productEvent.ProductID,
+			SKU:       productEvent.Product.SKU,
+			Title:     productEvent.Product.BaseTitle,
+			Version:   productEvent.Product.Version,
+			UpdatedAt: event.Timestamp,
+		})
+	}
+	return result
+}
+
+// ErrorRate summarizes 4xx and 5xx responses within the given window
+func (s *dashboardService) ErrorRate(window time.Duration) *interfaces.ErrorRateSummary {
+	counts := s.requests.Counts(time.Now().Add(-window))
+
+	summary := &interfaces.ErrorRateSummary{
+		Window:   window.String(),
+		ByStatus: counts,
+	}
+	for status, count := range counts {
+		summary.TotalRequests += count
+		switch {
+		case status >= 500:
+			summary.ServerErrors += count
+		case status >= 400:
+			summary.ClientErrors += count
+		}
+	}
+	if summary.TotalRequests > 0 {
+		summary.ErrorRate = float64(summary.ServerErrors) / float64(summary.TotalRequests)
+	}
+	return summary
+}
+
+// WebSocketClients returns the number of connected WebSocket clients
+func (s *dashboardService) WebSocketClients() *interfaces.WebSocketSummary {
+	return &interfaces.WebSocketSummary{
+		ActiveClients: s.clients.ClientCount(),
+	}
+}
+
+// JobStatuses returns the most recent jobs and how many of them are in each status
+func (s *dashboardService) JobStatuses(limit int) (*interfaces.JobStatusSummary, error) {
+	jobs, err := s.jobs.List(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &interfaces.JobStatusSummary{
+		Counts: make(map[models.JobStatus]int),
+		Jobs:   jobs,
+	}
+	for _, job := range jobs {
+		summary.Counts[job.Status]++
+	}
+	return summary, nil
+}
+
+// ConsumerLags returns the offsets of the tracked event subscribers
+func (s *dashboardService) ConsumerLags() []*interfaces.ConsumerLag {
+	if s.consumers == nil {
+		return []*interfaces.ConsumerLag{}
+	}
+	return s.consumers.ConsumerLags()
+}
+
+// LatencyObjectives returns per-route latency against the configured objectives
+func (s *dashboardService) LatencyObjectives() []*interfaces.RouteLatency {
+	if s.latency == nil {
+		return []*interfaces.RouteLatency{}
+	}
+	return s.latency.LatencyObjectives()
+}
+
+// DeprecationUsage returns how much each deprecated route and field is still used
+func (s *dashboardService) DeprecationUsage() []*interfaces.DeprecationUsage {
+	if s.deprecations == nil {
+		return []*interfaces.DeprecationUsage{}
+	}
+	return s.deprecations.DeprecationUsage()
+}
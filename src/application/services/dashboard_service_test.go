@@ -0,0 +1,263 @@
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+type mockRequestStats struct {
+	counts map[int]int
+}
+
+func (m *mockRequestStats) Counts(since time.Time) map[int]int {
+	return m.counts
+}
+
+type mockClientCounter struct {
+	count int
+}
+
+func (m *mockClientCounter) ClientCount() int {
+	return m.count
+}
+
+type mockConsumerLags struct {
+	lags []*interfaces.ConsumerLag
+}
+
+func (m *mockConsumerLags) ConsumerLags() []*interfaces.ConsumerLag {
+	return m.lags
+}
+
+type mockLatencyObjectives struct {
+	routes []*interfaces.RouteLatency
+}
+
+func (m *mockLatencyObjectives) LatencyObjectives() []*interfaces.RouteLatency {
+	return m.routes
+}
+
+type mockDeprecationUsage struct {
+	usage []*interfaces.DeprecationUsage
+}
+
+func (m *mockDeprecationUsage) DeprecationUsage() []*interfaces.DeprecationUsage {
+	return m.usage
+}
+
+func setupDashboardService(counts map[int]int, clients int) (*dashboardService, *MockEventPublisher) {
+	publisher := new(MockEventPublisher)
+	publisher.On("Subscribe", mock.AnythingOfType("models.EventType"), mock.Anything).Return(nil)
+
+	service := NewDashboardService(
+		publisher,
+		memory.NewJobRepository(),
+		&mockRequestStats{counts: counts},
+		&mockClientCounter{count: clients},
+		&mockConsumerLags{lags: []*interfaces.ConsumerLag{{Consumer: "websocket", Committed: 3, Latest: 5, Lag: 2}}},
+		&mockLatencyObjectives{routes: []*interfaces.RouteLatency{{Route: "GET /products", BudgetMs: 100, P95Ms: 150, Breached: true}}},
+		&mockDeprecationUsage{usage: []*interfaces.DeprecationUsage{{ID: "old-field", Routes: []string{"GET /products"}, Requests: 7}}},
+	)
+	return service.(*dashboardService), publisher
+}
+
+func createDashboardEvent(eventType models.EventType, productID string, timestamp time.Time) *models.Event {
+	return &models.Event{
+		ID:       fmt.Sprintf("evt_%s_%d", productID, timestamp.UnixNano()),
+		Type:     eventType,
+		EntityID: productID,
+		Data: &models.ProductEvent{
+			ProductID: productID,
+			Action:    string(eventType),
+			Product:   &models.Product{ID: productID, SKU: "SKU-" + productID, BaseTitle: "Title " + productID},
+		},
+		Timestamp: timestamp,
+	}
+}
+
+func TestDashboardSubscribesToProductEvents(t *testing.T) {
+	_, publisher := setupDashboardService(nil, 0)
+
+	publisher.AssertCalled(t, "Subscribe", models.EventProductCreated, mock.Anything)
+	publisher.AssertCalled(t, "Subscribe", models.EventProductUpdated, mock.Anything)
+	publisher.AssertCalled(t, "Subscribe", models.EventProductDeleted, mock.Anything)
+}
+
+func TestDashboardRecentEvents(t *testing.T) {
+	service, _ := setupDashboardService(nil, 0)
+	base := time.Now()
+
+	for i := 0; i < 5; i++ {
+		service.recordEvent(createDashboardEvent(models.EventProductCreated, fmt.Sprintf("p%d", i), base.Add(time.Duration(i)*time.Second)))
+	}
+
+	events := service.RecentEvents(3)
+	assert.Len(t, events, 3)
+	assert.Equal(t, "p4", events[0].EntityID)
+	assert.Equal(t, "p3", events[1].EntityID)
+	assert.Equal(t, "p2", events[2].EntityID)
+
+	assert.Len(t, service.RecentEvents(0), 5)
+}
+
+func TestDashboardRecentEventsWrapsAround(t *testing.T) {
+	service, _ := setupDashboardService(nil, 0)
+	base := time.Now()
+
+	for i := 0; i < recentEventCapacity+10; i++ {
+		service.recordEvent(createDashboardEvent(models.EventProductCreated, fmt.Sprintf("p%d", i), base))
+	}
+
+	events := service.RecentEvents(recentEventCapacity + 100)
+	assert.Len(t, events, recentEventCapacity)
+	assert.Equal(t, fmt.Sprintf("p%d", recentEventCapacity+9), events[0].EntityID)
+	assert.Equal(t, "p10", events[len(events)-1].EntityID)
+}
+
+func TestDashboardReset(t *testing.T) {
+	service, _ := setupDashboardService(nil, 0)
+	service.recordEvent(createDashboardEvent(models.EventProductUpdated, "a", time.Now()))
+
+	service.Reset()
+
+	assert.Empty(t, service.RecentEvents(0))
+	assert.Empty(t, service.TopEditedProducts(0))
+
+	service.recordEvent(createDashboardEvent(models.EventProductUpdated, "b", time.Now()))
+	assert.Len(t, service.RecentEvents(0), 1)
+}
+
+func TestDashboardTopEditedProducts(t *testing.T) {
+	service, _ := setupDashboardService(nil, 0)
+	base := time.Now()
+
+	service.recordEvent(createDashboardEvent(models.EventProductUpdated, "a", base))
+	service.recordEvent(createDashboardEvent(models.EventProductUpdated, "b", base.Add(time.Second)))
+	service.recordEvent(createDashboardEvent(models.EventProductUpdated, "b", base.Add(2*time.Second)))
+	service.recordEvent(createDashboardEvent(models.EventProductUpdated, "c", base.Add(3*time.Second)))
+	service.recordEvent(createDashboardEvent(models.EventProductUpdated, "d", base.Add(4*time.Second)))
+	service.recordEvent(createDashboardEvent(models.EventProductDeleted, "d", base.Add(5*time.Second)))
+
+	top := service.TopEditedProducts(2)
+	assert.Len(t, top, 2)
+	assert.Equal(t, "b", top[0].ProductID)
+	assert.Equal(t, 2, top[0].EditCount)
+	assert.Equal(t, "SKU-b", top[0].SKU)
+	// Tie on edit count is broken by the most recent edit
+	assert.Equal(t, "c", top[1].ProductID)
+
+	// Deleted products are no longer listed
+	for _, edit := range service.TopEditedProducts(0) {
+		assert.NotEqual(t, "d", edit.ProductID)
+	}
+}
+
+func TestDashboardRecentlyUpdatedProducts(t *testing.T) {
+	service, _ := setupDashboardService(nil, 0)
+	base := time.Now()
+
+	service.recordEvent(createDashboardEvent(models.EventProductCreated, "a", base))
+	service.recordEvent(createDashboardEvent(models.EventProductCreated, "b", base.Add(time.Second)))
+	service.recordEvent(createDashboardEvent(models.EventProductUpdated, "a", base.Add(2*time.Second)))
+	service.recordEvent(createDashboardEvent(models.EventProductCreated, "c", base.Add(3*time.Second)))
+	service.recordEvent(createDashboardEvent(models.EventProductDeleted, "c", base.Add(4*time.Second)))
+
+	products := service.RecentlyUpdatedProducts(0)
+	assert.Len(t, products, 2)
+	assert.Equal(t, "a", products[0].ProductID)
+	assert.Equal(t, base.Add(2*time.Second), products[0].UpdatedAt)
+	assert.Equal(t, "b", products[1].ProductID)
+
+	assert.Len(t, service.RecentlyUpdatedProducts(1), 1)
+	assert.Empty(t, (&dashboardService{}).RecentlyUpdatedProducts(5))
+}
+
+func TestDashboardErrorRate(t *testing.T) {
+	service, _ := setupDashboardService(map[int]int{
+		http.StatusOK:                  15,
+		http.StatusNotFound:            3,
+		http.StatusInternalServerError: 2,
+	}, 0)
+
+	summary := service.ErrorRate(15 * time.Minute)
+	assert.Equal(t, "15m0s", summary.Window)
+	assert.Equal(t, 20, summary.TotalRequests)
+	assert.Equal(t, 3, summary.ClientErrors)
+	assert.Equal(t, 2, summary.ServerErrors)
+	assert.InDelta(t, 0.1, summary.ErrorRate, 0.0001)
+}
+
+func TestDashboardErrorRateWithoutTraffic(t *testing.T) {
+	service, _ := setupDashboardService(map[int]int{}, 0)
+
+	summary := service.ErrorRate(time.Minute)
+	assert.Equal(t, 0, summary.TotalRequests)
+	assert.Equal(t, 0.0, summary.ErrorRate)
+}
+
+func TestDashboardWebSocketClients(t *testing.T) {
+	service, _ := setupDashboardService(nil, 7)
+
+	assert.Equal(t, 7, service.WebSocketClients().ActiveClients)
+}
+
+func TestDashboardJobStatuses(t *testing.T) {
+	service, _ := setupDashboardService(nil, 0)
+
+	running := &models.Job{ID: "job_1", Status: models.JobStatusRunning, CreatedAt: time.Now()}
+	completed := &models.Job{ID: "job_2", Status: models.JobStatusCompleted, CreatedAt: time.Now().Add(time.Second)}
+	assert.NoError(t, service.jobs.Create(running))
+	assert.NoError(t, service.jobs.Create(completed))
+
+	summary, err := service.JobStatuses(10)
+	assert.NoError(t, err)
+	assert.Len(t, summary.Jobs, 2)
+	assert.Equal(t, 1, summary.Counts[models.JobStatusRunning])
+	assert.Equal(t, 1, summary.Counts[models.JobStatusCompleted])
+}
+
+func TestDashboardConsumerLags(t *testing.T) {
+	service, _ := setupDashboardService(nil, 0)
+
+	lags := service.ConsumerLags()
+	assert.Len(t, lags, 1)
+	assert.Equal(t, "websocket", lags[0].Consumer)
+	assert.Equal(t, int64(2), lags[0].Lag)
+}
+
+func TestDashboardConsumerLagsWithoutTracker(t *testing.T) {
+	publisher := new(MockEventPublisher)
+	publisher.On("Subscribe", mock.AnythingOfType("models.EventType"), mock.Anything).Return(nil)
+	service := NewDashboardService(publisher, memory.NewJobRepository(), &mockRequestStats{}, &mockClientCounter{}, nil, nil, nil)
+
+	assert.Empty(t, service.ConsumerLags())
+	assert.Empty(t, service.LatencyObjectives())
+	assert.Empty(t, service.DeprecationUsage())
+}
+
+func TestDashboardDeprecationUsage(t *testing.T) {
+	service, _ := setupDashboardService(nil, 0)
+
+	usage := service.DeprecationUsage()
+	assert.Len(t, usage, 1)
+	assert.Equal(t, "old-field", usage[0].ID)
+	assert.Equal(t, int64(7), usage[0].Requests)
+}
+
+func TestDashboardLatencyObjectives(t *testing.T) {
+	service, _ := setupDashboardService(nil, 0)
+
+	routes := service.LatencyObjectives()
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "GET /products", routes[0].Route)
+	assert.True(t, routes[0].Breached)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// fixedErrorRates reports no errors for every window
+type fixedErrorRates struct{}
+
+func (fixedErrorRates) ErrorRate(window time.Duration) *interfaces.ErrorRateSummary {
+	return &interfaces.ErrorRateSummary{Window: window.String(), ByStatus: map[int]int{}}
+}
+
+func TestDiagnostics(t *testing.T) {
+	service := NewDiagnosticsService(interfaces.DiagnosticsConfig{
+		Build:    &models.BuildInfo{Version: "v1.2.0", GoVersion: "go1.22"},
+		Backends: map[string]string{"repository": "memory"},
+		Features: map[string]bool{"admin_login": true},
+		Config: map[string]string{
+			"FORECASTER":          "velocity",
+			"OIDC_SESSION_SECRET": "very-secret",
+			"ADMIN_DOCS_PASSWORD": "hunter2",
+			"REPOSITORY_URL":      "postgres://ecom:hunter2@db:5432/ecom",
+		},
+		Checks: []interfaces.Check{
+			{Name: "repository", Run: func() error { return nil }},
+			{Name: "import_watch_dir", Run: func() error { return errors.New("not a directory") }},
+		},
+	}, fixedErrorRates{})
+
+	// Checks are only reported once they have run
+	assert.Empty(t, service.Diagnostics().Checks)
+
+	results := service.SelfCheck()
+	assert.Len(t, results, 2)
+	assert.True(t, results[0].OK)
+	assert.False(t, results[1].OK)
+	assert.Equal(t, "not a directory", results[1].Error)
+
+	diagnostics := service.Diagnostics()
+	assert.Equal(t, "v1.2.0", diagnostics.Build.Version)
+	assert.Equal(t, "memory", diagnostics.Backends["repository"])
+	assert.True(t, diagnostics.Features["admin_login"])
+	assert.Equal(t, results, diagnostics.Checks)
+	assert.Equal(t, "velocity", diagnostics.Config["FORECASTER"])
+	assert.Equal(t, "[redacted]", diagnostics.Config["OIDC_SESSION_SECRET"])
+	assert.Equal(t, "[redacted]", diagnostics.Config["ADMIN_DOCS_PASSWORD"])
+	assert.NotContains(t, diagnostics.Config["REPOSITORY_URL"], "hunter2")
+	assert.Contains(t, diagnostics.Config["REPOSITORY_URL"], "db:5432")
+	assert.Len(t, diagnostics.Errors, 3)
+	assert.Equal(t, "5m0s", diagnostics.Errors[0].Window)
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/locks"
+)
+
+const (
+	// DefaultEditSessionTTL is how long a session lasts without a renewal
+	DefaultEditSessionTTL = 2 * time.Minute
+	// MaxEditSessionTTL is the longest a session can be claimed or renewed for
+	MaxEditSessionTTL = 30 * time.Minute
+)
+
+// editSessionService keeps each session as a lock owned by the editor, so
+// sessions expire with their lock and the lock manager can list them
+type editSessionService struct {
+	repo  repositories.ProductRepository
+	locks locks.OwnedLockManager
+}
+
+// NewEditSessionService creates a new edit session service instance
+func NewEditSessionService(repo repositories.ProductRepository, lockManager locks.OwnedLockManager) interfaces.EditSessionService {
+	return &editSessionService{
+		repo:  repo,
+		locks: lockManager,
+	}
+}
+
+// Claim starts a new session on a product
+func (s *editSessionService) Claim(ctx context.Context, productID, editor string, ttl time.Duration) (*interfaces.EditClaim, error) {
+	if editor == "" {
+		return nil, fmt.Errorf("%w: editor is required", models.ErrInvalidRequest)
+	}
+	ttl, err := editSessionTTL(ttl)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.repo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID := uuid.New().String()
+	acquired, err := s.locks.AcquireOwnedLock(ctx, editSessionResource(productID, sessionID), editor, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return nil, models.ErrLockFailed
+	}
+	return s.claim(product, sessionID)
+}
+
+// Renew extends a live session
+func (s *editSessionService) Renew(ctx context.Context, productID, sessionID string, ttl time.Duration) (*interfaces.EditClaim, error) {
+	ttl, err := editSessionTTL(ttl)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.repo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.session(productID, sessionID); err != nil {
+		return nil, err
+	}
+	if err := s.locks.RefreshLock(editSessionResource(productID, sessionID), ttl); err != nil {
+		return nil, err
+	}
+	return s.claim(product, sessionID)
+}
+
+// Release ends a live session
+func (s *editSessionService) Release(productID, sessionID string) error {
+	if _, err := s.session(productID, sessionID); err != nil {
+		return err
+	}
+	return s.locks.ReleaseLock(editSessionResource(productID, sessionID))
+}
+
+// List returns the live sessions on a product
+func (s *editSessionService) List(ctx context.Context, productID string) ([]*models.EditSession, error) {
+	if _, err := s.repo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	return s.sessions(productID), nil
+}
+
+// claim describes a session and the other sessions on its product
+func (s *editSessionService) claim(product *models.Product, sessionID string) (*interfaces.EditClaim, error) {
+	claim := &interfaces.EditClaim{
+		Version: product.Version,
+		Others:  make([]*models.EditSession, 0),
+	}
+	for _, session := range s.sessions(product.ID) {
+		if session.ID == sessionID {
+			claim.Session = session
+		} else {
+			claim.Others = append(claim.Others, session)
+		}
+	}
+	if claim.Session == nil {
+		// The session expired between taking the lock and listing it
+		return nil, models.ErrEditSessionNotFound
+	}
+	return claim, nil
+}
+
+// session returns a live session on a product
+func (s *editSessionService) session(productID, sessionID string) (*models.EditSession, error) {
+	for _, session := range s.sessions(productID) {
+		if session.ID == sessionID {
+			return session, nil
+		}
+	}
+	return nil, models.ErrEditSessionNotFound
+}
+
+// sessions returns the live sessions on a product from the lock holders
+func (s *editSessionService) sessions(productID string) []*models.EditSession {
+	prefix := editSessionResource(productID, "")
+	holders := s.locks.Holders(prefix)
+	sessions := make([]*models.EditSession, 0, len(holders))
+	for _, holder := range holders {
+		sessions = append(sessions, &models.EditSession{
+			ID:        holder.ResourceID[len(prefix):],
+			ProductID: productID,
+			Editor:    holder.Owner,
+			ClaimedAt: holder.AcquiredAt,
+			ExpiresAt: holder.ExpiresAt,
+		})
+	}
+	return sessions
+}
+
+// editSessionResource is the lock a session is kept as. These never collide
+// with the per-product write locks, which are keyed by the bare product ID.
+func editSessionResource(productID, sessionID string) string {
+	return "edit-session:" + productID + ":" + sessionID
+}
+
+// editSessionTTL applies the default and checks the limit
+func editSessionTTL(ttl time.Duration) (time.Duration, error) {
+	switch {
+	case ttl == 0:
+		return DefaultEditSessionTTL, nil
+	case ttl < 0 || ttl > MaxEditSessionTTL:
+		return 0, fmt.Errorf("%w: ttl must be between 0 and %s", models.ErrInvalidRequest, MaxEditSessionTTL)
+	}
+	return ttl, nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/locks"
+)
+
+func setupEditSessionService(t *testing.T) (*editSessionService, *models.Product) {
+	productService, _, _ := setupProductService()
+	product := createProductWithColour(t, productService, "SHIRT-1", "Navy")
+
+	lockManager := locks.NewMemoryLockManager()
+	t.Cleanup(lockManager.Close)
+	return NewEditSessionService(productService.repo, lockManager).(*editSessionService), product
+}
+
+func TestClaimEditSession(t *testing.T) {
+	service, product := setupEditSessionService(t)
+
+	first, err := service.Claim(context.Background(), product.ID, "ada@example.com", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "ada@example.com", first.Session.Editor)
+	assert.Equal(t, product.ID, first.Session.ProductID)
+	assert.Equal(t, product.Version, first.Version)
+	assert.Empty(t, first.Others)
+	assert.WithinDuration(t, first.Session.ClaimedAt.Add(DefaultEditSessionTTL), first.Session.ExpiresAt, time.Millisecond)
+
+	// The second editor is told about the first, and the first sees both
+	second, err := service.Claim(context.Background(), product.ID, "grace@example.com", time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, second.Others, 1)
+	assert.Equal(t, first.Session.ID, second.Others[0].ID)
+
+	sessions, err := service.List(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+	assert.Equal(t, "ada@example.com", sessions[0].Editor)
+	assert.Equal(t, "grace@example.com", sessions[1].Editor)
+
+	// Released sessions are gone
+	assert.NoError(t, service.Release(product.ID, first.Session.ID))
+	sessions, err = service.List(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.ErrorIs(t, service.Release(product.ID, first.Session.ID), models.ErrEditSessionNotFound)
+}
+
+func TestRenewEditSession(t *testing.T) {
+	service, product := setupEditSessionService(t)
+
+	claim, err := service.Claim(context.Background(), product.ID, "ada@example.com", 20*time.Millisecond)
+	assert.NoError(t, err)
+
+	renewed, err := service.Renew(context.Background(), product.ID, claim.Session.ID, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, renewed.Session.ExpiresAt.After(claim.Session.ExpiresAt))
+	assert.Equal(t, claim.Session.ClaimedAt, renewed.Session.ClaimedAt)
+
+	// A session that is not renewed expires
+	short, err := service.Claim(context.Background(), product.ID, "grace@example.com", 10*time.Millisecond)
+	assert.NoError(t, err)
+	time.Sleep(20 * time.Millisecond)
+	_, err = service.Renew(context.Background(), product.ID, short.Session.ID, time.Minute)
+	assert.ErrorIs(t, err, models.ErrEditSessionNotFound)
+
+	sessions, err := service.List(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+}
+
+func TestClaimEditSessionValidation(t *testing.T) {
+	service, product := setupEditSessionService(t)
+
+	_, err := service.Claim(context.Background(), product.ID, "", 0)
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	_, err = service.Claim(context.Background(), product.ID, "ada@example.com", MaxEditSessionTTL+time.Second)
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	_, err = service.Claim(context.Background(), "missing", "ada@example.com", 0)
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+
+	_, err = service.List(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// facetPageSize is the number of products read per page while counting
+const facetPageSize = 500
+
+// facetService implements the FacetService interface
+type facetService struct {
+	products interfaces.ProductService
+}
+
+// NewFacetService creates a new facet service instance. Facets are counted
+// over the current matches on every call, so they follow the listing they
+// are shown next to.
+func NewFacetService(products interfaces.ProductService) interfaces.FacetService {
+	return &facetService{
+		products: products,
+	}
+}
+
+// Facets reads every matching product a page at a time and counts it
+func (s *facetService) Facets(ctx context.Context, filter models.ProductFilter, request models.FacetRequest) (*models.Facets, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	counter := models.NewFacetCounter(request)
+	for page := 1; ; page++ {
+		products, total, err := s.products.ListProducts(ctx, filter, page, facetPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			counter.Add(product)
+		}
+		if len(products) == 0 || page*facetPageSize >= total {
+			return counter.Facets(), nil
+		}
+	}
+}
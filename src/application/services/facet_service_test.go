@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestFacets(t *testing.T) {
+	products, _, _ := setupProductService()
+	service := NewFacetService(products)
+	for i := 0; i < facetPageSize+2; i++ {
+		product := createValidProduct()
+		product.Prices[0].Amount = float64(100 + i%2*400)
+		if i%2 == 1 {
+			product.Tags = []string{"sale"}
+		}
+		assert.NoError(t, products.CreateProduct(context.Background(), product))
+	}
+
+	// Every page of matches is counted
+	facets, err := service.Facets(context.Background(), models.ProductFilter{}, models.FacetRequest{Currency: "SEK", PriceBoundaries: []float64{0, 250}})
+	assert.NoError(t, err)
+	assert.Equal(t, facetPageSize+2, facets.Total)
+	assert.Equal(t, []models.FacetCount{{Value: "SE", Count: facetPageSize + 2}}, facets.Markets)
+	assert.Equal(t, facetPageSize/2+1, facets.PriceBuckets[0].Count)
+	assert.Equal(t, facetPageSize/2+1, facets.PriceBuckets[1].Count)
+
+	// Only the filtered products are counted
+	facets, err = service.Facets(context.Background(), models.ProductFilter{Tags: []string{"sale"}}, models.FacetRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, facetPageSize/2+1, facets.Total)
+
+	_, err = service.Facets(context.Background(), models.ProductFilter{}, models.FacetRequest{PriceBoundaries: []float64{10, 5}})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// forecastPageSize is the page size used when scanning the catalog for forecasts
+const forecastPageSize = 100
+
+// forecastService implements the ForecastService interface
+type forecastService struct {
+	products   repositories.ProductRepository
+	inputs     repositories.ForecastInputRepository
+	forecaster interfaces.Forecaster
+	now        func() time.Time
+}
+
+// NewForecastService creates a service that forecasts stockouts with the given forecaster
+func NewForecastService(products repositories.ProductRepository, inputs repositories.ForecastInputRepository, forecaster interfaces.Forecaster) interfaces.ForecastService {
+	return &forecastService{
+		products:   products,
+		inputs:     inputs,
+		forecaster: forecaster,
+		now:        time.Now,
+	}
+}
+
+// PushInputs stores the batch only if every input in it is valid
+func (s *forecastService) PushInputs(inputs *interfaces.ForecastInputs) (*interfaces.ForecastInputsResult, error) {
+	for i := range inputs.Ledger {
+		if err := inputs.Ledger[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+	for i := range inputs.Velocities {
+		if err := inputs.Velocities[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(inputs.Ledger) > 0 {
+		if err := s.inputs.AppendLedger(inputs.Ledger); err != nil {
+			return nil, fmt.Errorf("failed to store ledger: %v", err)
+		}
+	}
+	for _, velocity := range inputs.Velocities {
+		if velocity.AsOf.IsZero() {
+			velocity.AsOf = s.now()
+		}
+		if err := s.inputs.SaveVelocity(velocity); err != nil {
+			return nil, fmt.Errorf("failed to store velocity: %v", err)
+		}
+	}
+	return &interfaces.ForecastInputsResult{Ledger: len(inputs.Ledger), Velocities: len(inputs.Velocities)}, nil
+}
+
+// Stockouts forecasts every variant that has inputs. Variants nobody pushed
+// inputs for are left out, since there is nothing to base a forecast on.
+func (s *forecastService) Stockouts(ctx context.Context, within time.Duration, limit int) ([]*models.StockoutForecast, error) {
+	now := s.now()
+	since := now.Add(-s.forecaster.Window())
+
+	forecasts := make([]*models.StockoutForecast, 0)
+	for page := 1; ; page++ {
+		products, total, err := s.products.List(ctx, models.ProductFilter{}, page, forecastPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list products: %v", err)
+		}
+		for _, product := range products {
+			for _, variant := range product.Variants {
+				forecast, err := s.forecastVariant(product, variant, since, now)
+				if err != nil {
+					return nil, err
+				}
+				if forecast == nil {
+					continue
+				}
+				if within > 0 && (forecast.StockoutAt == nil || forecast.StockoutAt.After(now.Add(within))) {
+					continue
+				}
+				forecasts = append(forecasts, forecast)
+			}
+		}
+		if len(products) == 0 || page*forecastPageSize >= total {
+			break
+		}
+	}
+
+	sort.SliceStable(forecasts, func(i, j int) bool {
+		a, b := forecasts[i].StockoutAt, forecasts[j].StockoutAt
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		return forecasts[i].SKU < forecasts[j].SKU
+	})
+	if limit > 0 && len(forecasts) > limit {
+		forecasts = forecasts[:limit]
+	}
+	return forecasts, nil
+}
+
+// forecastVariant gathers a variant's inputs and runs the forecaster, or returns nil without inputs
+func (s *forecastService) forecastVariant(product *models.Product, variant models.Variant, since, now time.Time) (*models.StockoutForecast, error) {
+	ledger, err := s.inputs.Ledger(variant.ID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %v", err)
+	}
+	velocity, err := s.inputs.Velocity(variant.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read velocity: %v", err)
+	}
+	if len(ledger) == 0 && velocity == nil {
+		return nil, nil
+	}
+
+	onHand := 0
+	for _, stock := range variant.Stock {
+		onHand += stock.Quantity
+	}
+
+	forecast, err := s.forecaster.Forecast(&models.ForecastInput{
+		ProductID: product.ID,
+		VariantID: variant.ID,
+		SKU:       variant.SKU,
+		OnHand:    onHand,
+		Ledger:    ledger,
+		Velocity:  velocity,
+		Now:       now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("forecaster %s failed for %s: %v", s.forecaster.Name(), variant.SKU, err)
+	}
+	return forecast, nil
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/forecasting"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+func setupForecastService(t *testing.T, now time.Time) (*forecastService, *models.Product) {
+	repo := memory.NewProductRepository()
+	product := createValidProduct()
+	product.ID = "prod_1"
+	product.Variants = []models.Variant{
+		{ID: "v_fast", SKU: "FAST", Stock: []models.Stock{{LocationID: "wh1", Quantity: 6}, {LocationID: "wh2", Quantity: 4}}},
+		{ID: "v_slow", SKU: "SLOW", Stock: []models.Stock{{LocationID: "wh1", Quantity: 10}}},
+		{ID: "v_idle", SKU: "IDLE", Stock: []models.Stock{{LocationID: "wh1", Quantity: 10}}},
+		{ID: "v_none", SKU: "NONE", Stock: []models.Stock{{LocationID: "wh1", Quantity: 1}}},
+	}
+	assert.NoError(t, repo.Create(context.Background(), product))
+
+	service := NewForecastService(repo, memory.NewForecastInputRepository(), forecasting.NewVelocityForecaster(7*24*time.Hour)).(*forecastService)
+	service.now = func() time.Time { return now }
+	return service, product
+}
+
+func TestForecastStockouts(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	service, _ := setupForecastService(t, now)
+
+	result, err := service.PushInputs(&interfaces.ForecastInputs{
+		Ledger: []models.LedgerEntry{
+			{VariantID: "v_slow", Delta: -7, Reason: "sale", At: now.Add(-24 * time.Hour)},
+			{VariantID: "v_idle", Delta: 5, Reason: "restock", At: now.Add(-24 * time.Hour)},
+		},
+		Velocities: []models.SalesVelocity{{VariantID: "v_fast", UnitsPerDay: 5}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &interfaces.ForecastInputsResult{Ledger: 2, Velocities: 1}, result)
+
+	forecasts, err := service.Stockouts(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.Len(t, forecasts, 3) // v_none has no inputs
+
+	// Soonest stockout first, variants that are not selling last
+	assert.Equal(t, "v_fast", forecasts[0].VariantID)
+	assert.Equal(t, 10, forecasts[0].OnHand)
+	assert.Equal(t, now.Add(2*24*time.Hour), *forecasts[0].StockoutAt)
+	assert.Equal(t, "v_slow", forecasts[1].VariantID)
+	assert.Equal(t, now.Add(10*24*time.Hour), *forecasts[1].StockoutAt)
+	assert.Equal(t, "v_idle", forecasts[2].VariantID)
+	assert.Nil(t, forecasts[2].StockoutAt)
+
+	within, err := service.Stockouts(context.Background(), 7*24*time.Hour, 0)
+	assert.NoError(t, err)
+	assert.Len(t, within, 1)
+
+	limited, err := service.Stockouts(context.Background(), 0, 2)
+	assert.NoError(t, err)
+	assert.Len(t, limited, 2)
+}
+
+func TestForecastPushInputsValidation(t *testing.T) {
+	service, _ := setupForecastService(t, time.Now())
+
+	_, err := service.PushInputs(&interfaces.ForecastInputs{
+		Ledger:     []models.LedgerEntry{{VariantID: "v_fast", Delta: -1, At: time.Now()}},
+		Velocities: []models.SalesVelocity{{VariantID: "v_fast", UnitsPerDay: -1}},
+	})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	// Nothing from a rejected batch is stored
+	forecasts, err := service.Stockouts(context.Background(), 0, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, forecasts)
+}
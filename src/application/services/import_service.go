@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/imports"
+)
+
+// importIndexPageSize is the page size used when indexing the catalog by SKU
+const importIndexPageSize = 100
+
+// importChunkSize is the number of CSV rows written per batch
+const importChunkSize = 500
+
+// importService implements the ImportService interface
+type importService struct {
+	products interfaces.ProductService
+	jobs     repositories.JobRepository
+}
+
+// NewImportService creates a new import service that writes products through the
+// product service and records every import run as a job
+func NewImportService(products interfaces.ProductService, jobs repositories.JobRepository) interfaces.ImportService {
+	return &importService{
+		products: products,
+		jobs:     jobs,
+	}
+}
+
+// ImportProducts transforms each record with the mapping, validates the resulting
+// products and writes the valid ones as a batch. In create mode every record
+// becomes a new product; in update mode records are merged into the existing
+// products with matching SKUs. Dry runs only transform and validate.
+func (s *importService) ImportProducts(ctx context.Context, req *interfaces.ImportRequest) (*interfaces.ImportResult, error) {
+	read := false
+	return s.importRecords(ctx, req, len(req.Records), func(row int) ([]map[string]string, []*interfaces.ImportRowResult, error) {
+		if read {
+			return nil, nil, nil
+		}
+		read = true
+		return req.Records, newImportRows(row, len(req.Records)), nil
+	})
+}
+
+// ImportCSV imports the rows of a CSV file like ImportProducts, reading and
+// writing them in chunks of importChunkSize so the file is never held in
+// memory at once. Without a mapping, columns named after a target field are
+// imported as they are. Rows that cannot be parsed fail on their own.
+func (s *importService) ImportCSV(ctx context.Context, req *interfaces.ImportRequest, file io.Reader) (*interfaces.ImportResult, error) {
+	reader, err := imports.NewCSVReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidRequest, err)
+	}
+	if len(req.Mapping) == 0 {
+		req.Mapping = imports.ColumnMapping(reader.Columns())
+		if len(req.Mapping) == 0 {
+			return nil, fmt.Errorf("%w: no mapping given and no column is named after a product field", models.ErrInvalidMapping)
+		}
+	}
+
+	return s.importRecords(ctx, req, 0, func(row int) ([]map[string]string, []*interfaces.ImportRowResult, error) {
+		records := make([]map[string]string, 0, importChunkSize)
+		rows := make([]*interfaces.ImportRowResult, 0, importChunkSize)
+		for len(rows) < importChunkSize {
+			record, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			result := &interfaces.ImportRowResult{Row: row + len(rows)}
+			var rowErr *imports.RowError
+			if errors.As(err, &rowErr) {
+				result.Error = fmt.Sprintf("malformed CSV row: %v", rowErr.Err)
+			} else if err != nil {
+				return nil, nil, err
+			}
+			records = append(records, record)
+			rows = append(rows, result)
+		}
+		return records, rows, nil
+	})
+}
+
+// importChunk returns the next records of an import and their rows, numbered
+// from row. Rows that could not be read are already failed and have a nil
+// record. An empty chunk ends the import.
+type importChunk func(row int) ([]map[string]string, []*interfaces.ImportRowResult, error)
+
+// newImportRows creates the results of count rows numbered from row
+func newImportRows(row, count int) []*interfaces.ImportRowResult {
+	rows := make([]*interfaces.ImportRowResult, count)
+	for i := range rows {
+		rows[i] = &interfaces.ImportRowResult{Row: row + i}
+	}
+	return rows
+}
+
+// importRecords imports the chunks of records next returns as one import job.
+// total is the number of records when known up front.
+func (s *importService) importRecords(ctx context.Context, req *interfaces.ImportRequest, total int, next importChunk) (*interfaces.ImportResult, error) {
+	mapping, err := imports.CompileMapping(req.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = interfaces.ImportCreate
+	}
+	if mode != interfaces.ImportCreate && mode != interfaces.ImportUpdate {
+		return nil, fmt.Errorf("%w: unknown import mode %q", models.ErrInvalidRequest, mode)
+	}
+	locale, err := imports.LookupLocale(req.Locale)
+	if err != nil {
+		return nil, err
+	}
+	if err := imports.ValidateColumns(req.Columns); err != nil {
+		return nil, err
+	}
+
+	result := &interfaces.ImportResult{
+		DryRun: req.DryRun,
+		Rows:   make([]*interfaces.ImportRowResult, 0, total),
+	}
+
+	var job *models.Job
+	if !req.DryRun {
+		job = &models.Job{
+			ID:        "job_" + uuid.New().String(),
+			Type:      models.JobImport,
+			Status:    models.JobStatusRunning,
+			Total:     total,
+			Source:    req.Source,
+			CreatedAt: time.Now(),
+		}
+		if err := s.jobs.Create(job); err != nil {
+			return nil, fmt.Errorf("failed to create job: %v", err)
+		}
+		result.JobID = job.ID
+	}
+
+	if err := s.importChunks(ctx, mapping, locale, mode, req, next, result); err != nil {
+		if job != nil {
+			job.AddError(err.Error())
+			job.Complete(0, len(result.Rows))
+			s.jobs.Update(job)
+		}
+		return nil, err
+	}
+
+	result.Total = len(result.Rows)
+	for _, row := range result.Rows {
+		if row.Success {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+
+	if job != nil {
+		for _, row := range result.Rows {
+			if !row.Success {
+				job.AddError(fmt.Sprintf("row %d: %s", row.Row, row.Error))
+			}
+		}
+		job.Total = result.Total
+		job.Complete(result.Succeeded, result.Failed)
+		if err := s.jobs.Update(job); err != nil {
+			return nil, fmt.Errorf("failed to update job: %v", err)
+		}
+	}
+
+	return result, nil
+}
+
+// importChunks reads the chunks of an import and writes each as a batch,
+// adding their rows to the result
+func (s *importService) importChunks(ctx context.Context, mapping *imports.Mapping, locale *imports.Locale, mode interfaces.ImportMode, req *interfaces.ImportRequest, next importChunk, result *interfaces.ImportResult) error {
+	var index map[string]string
+	if mode == interfaces.ImportUpdate {
+		var err error
+		if index, err = s.skuIndex(ctx); err != nil {
+			return err
+		}
+	}
+
+	for {
+		records, rows, err := next(len(result.Rows) + 1)
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		result.Rows = append(result.Rows, rows...)
+
+		readRecords := make([]map[string]string, 0, len(records))
+		readRows := make([]*interfaces.ImportRowResult, 0, len(rows))
+		for i, record := range records {
+			if record != nil {
+				readRecords = append(readRecords, record)
+				readRows = append(readRows, rows[i])
+			}
+		}
+
+		if mode == interfaces.ImportUpdate {
+			err = s.importUpdates(ctx, mapping, locale, req, index, readRecords, readRows)
+		} else {
+			err = s.importCreates(ctx, mapping, locale, req, readRecords, readRows)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// importCreates creates one product per valid record
+func (s *importService) importCreates(ctx context.Context, mapping *imports.Mapping, locale *imports.Locale, req *interfaces.ImportRequest, records []map[string]string, rows []*interfaces.ImportRowResult) error {
+	valid := make([]*models.Product, 0, len(records))
+	validRows := make([]*interfaces.ImportRowResult, 0, len(records))
+
+	for i, record := range records {
+		row := rows[i]
+
+		record, ok := normalizeRecord(locale, req.Columns, record, row)
+		if !ok {
+			continue
+		}
+		product, err := mapping.Apply(record)
+		if err == nil {
+			err = models.ValidateNewProduct(product)
+		}
+		if err != nil {
+			row.Error = err.Error()
+			continue
+		}
+
+		if req.DryRun {
+			row.Success = true
+			row.Product = product
+			row.Warnings = models.CheckQuality(product)
+			continue
+		}
+		valid = append(valid, product)
+		validRows = append(validRows, row)
+	}
+
+	if len(valid) == 0 {
+		return nil
+	}
+	batchResults, err := s.products.BatchCreateProducts(ctx, valid)
+	if err != nil {
+		return err
+	}
+	for i, batchResult := range batchResults {
+		validRows[i].ProductID = batchResult.ID
+		validRows[i].Success = batchResult.Success
+		validRows[i].Error = batchResult.Error
+		validRows[i].Warnings = batchResult.Warnings
+	}
+	return nil
+}
+
+// importUpdates merges records into existing products. Several records may
+// update the same product, e.g. one stock row per variant; they are merged in
+// order and the product is written once.
+func (s *importService) importUpdates(ctx context.Context, mapping *imports.Mapping, locale *imports.Locale, req *interfaces.ImportRequest, index map[string]string, records []map[string]string, rows []*interfaces.ImportRowResult) error {
+	pending := make(map[string]*models.Product)
+	order := make([]string, 0)
+	rowsByProduct := make(map[string][]*interfaces.ImportRowResult)
+
+	for i, record := range records {
+		row := rows[i]
+
+		record, ok := normalizeRecord(locale, req.Columns, record, row)
+		if !ok {
+			continue
+		}
+		update, err := mapping.Apply(record)
+		if err != nil {
+			row.Error = err.Error()
+			continue
+		}
+		if update.SKU == "" {
+			row.Error = "sku is required to update a product"
+			continue
+		}
+		productID, exists := index[update.SKU]
+		if !exists {
+			row.Error = fmt.Sprintf("%v: no product with SKU %s", models.ErrProductNotFound, update.SKU)
+			continue
+		}
+
+		current, seen := pending[productID]
+		if !seen {
+			if current, err = s.products.GetProduct(ctx, productID); err != nil {
+				row.Error = err.Error()
+				continue
+			}
+		}
+		merged, err := imports.MergeUpdate(current, update)
+		if err == nil {
+			err = models.ValidateProduct(merged)
+		}
+		if err != nil {
+			row.Error = err.Error()
+			continue
+		}
+
+		if !seen {
+			order = append(order, productID)
+		}
+		pending[productID] = merged
+		rowsByProduct[productID] = append(rowsByProduct[productID], row)
+		row.ProductID = productID
+		if req.DryRun {
+			row.Success = true
+			row.Product = merged
+			row.Warnings = models.CheckQuality(merged)
+		}
+	}
+
+	if req.DryRun || len(order) == 0 {
+		return nil
+	}
+
+	products := make([]*models.Product, len(order))
+	for i, productID := range order {
+		products[i] = pending[productID]
+	}
+	batchResults, err := s.products.BatchUpdateProducts(ctx, products)
+	if err != nil {
+		return err
+	}
+	for i, batchResult := range batchResults {
+		for _, row := range rowsByProduct[order[i]] {
+			row.Success = batchResult.Success
+			row.Error = batchResult.Error
+			row.Warnings = batchResult.Warnings
+		}
+	}
+	return nil
+}
+
+// normalizeRecord converts the declared columns of a record to canonical values.
+// Cells that cannot be parsed fail the row and are listed on it.
+func normalizeRecord(locale *imports.Locale, columns map[string]string, record map[string]string, row *interfaces.ImportRowResult) (map[string]string, bool) {
+	normalized, cellErrors := locale.NormalizeRecord(record, columns)
+	if len(cellErrors) == 0 {
+		return normalized, true
+	}
+
+	messages := make([]string, len(cellErrors))
+	for i, cell := range cellErrors {
+		messages[i] = fmt.Sprintf("column %s: %s", cell.Column, cell.Error)
+	}
+	row.Cells = cellErrors
+	row.Error = strings.Join(messages, "; ")
+	return nil, false
+}
+
+// skuIndex maps every product and variant SKU in the catalog to its product ID
+func (s *importService) skuIndex(ctx context.Context) (map[string]string, error) {
+	index := make(map[string]string)
+	for page := 1; ; page++ {
+		products, total, err := s.products.ListProducts(ctx, models.ProductFilter{}, page, importIndexPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to index products: %v", err)
+		}
+		for _, product := range products {
+			index[product.SKU] = product.ID
+			for _, variant := range product.Variants {
+				if variant.SKU != "" {
+					index[variant.SKU] = product.ID
+				}
+			}
+		}
+		if len(products) == 0 || page*importIndexPageSize >= total {
+			return index, nil
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func createImportRequest() *interfaces.ImportRequest {
+	return &interfaces.ImportRequest{
+		Mapping: map[string]string{
+			"sku":               "{{upper .sku}}",
+			"base_title":        "{{.name}}",
+			"prices.SEK":        "{{.price}}",
+			"metadata.SE.title": "{{.name}}",
+		},
+		Records: []map[string]string{
+			{"sku": "shirt-1", "name": "Shirt", "price": "299"},
+			{"sku": "shirt-2", "price": "199"}, // missing title
+			{"sku": "shirt-3", "name": "Jacket", "price": "n/a"},
+		},
+	}
+}
+
+func TestImportProducts(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+
+	result, err := service.ImportProducts(context.Background(), createImportRequest())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+
+	assert.True(t, result.Rows[0].Success)
+	assert.Nil(t, result.Rows[0].Product)
+	assert.NotEmpty(t, result.Rows[0].Warnings)
+	assert.False(t, result.Rows[1].Success)
+	assert.Contains(t, result.Rows[1].Error, "base_title is required")
+	assert.False(t, result.Rows[2].Success)
+
+	created, err := productService.GetProduct(context.Background(), result.Rows[0].ProductID)
+	assert.NoError(t, err)
+	assert.Equal(t, "SHIRT-1", created.SKU)
+
+	// The import is recorded as a job with every row, next to the batch job that created the products
+	job, err := productService.jobs.GetByID(result.JobID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobImport, job.Type)
+	assert.Equal(t, 3, job.Total)
+	assert.Equal(t, 1, job.Succeeded)
+	assert.Equal(t, 2, job.Failed)
+	assert.Len(t, job.Errors, 2)
+	assert.Contains(t, job.Errors[0], "row 2:")
+
+	jobs, err := productService.jobs.List(10)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 2)
+}
+
+func TestImportProductsDryRun(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+
+	req := createImportRequest()
+	req.DryRun = true
+
+	result, err := service.ImportProducts(context.Background(), req)
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, "SHIRT-1", result.Rows[0].Product.SKU)
+	assert.NotEmpty(t, result.Rows[0].Warnings)
+	assert.Empty(t, result.Rows[0].ProductID)
+	assert.Empty(t, result.JobID)
+
+	products, total, err := productService.ListProducts(context.Background(), models.ProductFilter{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, products)
+}
+
+func TestImportProductsInvalidMapping(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+
+	req := createImportRequest()
+	req.Mapping["color"] = "{{.color}}"
+
+	_, err := service.ImportProducts(context.Background(), req)
+	assert.True(t, errors.Is(err, models.ErrInvalidMapping))
+}
+
+func createSupplierCatalog(t *testing.T, productService *productService) *models.Product {
+	product := createValidProduct()
+	product.SKU = "SHIRT-1"
+	product.Variants = []models.Variant{
+		{ID: "v1", SKU: "SHIRT-1-S", Attributes: map[string]string{"size": "S"}, Stock: []models.Stock{{LocationID: "wh1", Quantity: 1, Backorder: true}}},
+		{ID: "v2", SKU: "SHIRT-1-M", Attributes: map[string]string{"size": "M"}, Stock: []models.Stock{{LocationID: "wh1", Quantity: 1}}},
+	}
+	assert.NoError(t, productService.CreateProduct(context.Background(), product))
+	return product
+}
+
+func createSupplierRequest() *interfaces.ImportRequest {
+	return &interfaces.ImportRequest{
+		Mode: interfaces.ImportUpdate,
+		Mapping: map[string]string{
+			"sku":        "{{.article}}",
+			"prices.SEK": "{{.price}}",
+			"stock.wh1":  "{{.qty}}",
+		},
+		Records: []map[string]string{
+			{"article": "SHIRT-1-S", "price": "249", "qty": "12"},
+			{"article": "SHIRT-1-M", "qty": "7"},
+			{"article": "UNKNOWN-1", "qty": "3"},
+		},
+		Source: "supplier_stock.csv",
+	}
+}
+
+func TestImportProductsUpdateMode(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+	product := createSupplierCatalog(t, productService)
+
+	result, err := service.ImportProducts(context.Background(), createSupplierRequest())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, product.ID, result.Rows[0].ProductID)
+	assert.Contains(t, result.Rows[2].Error, "UNKNOWN-1")
+
+	updated, err := productService.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 249.0, updated.Prices[0].Amount)
+	assert.Equal(t, 12, updated.Variants[0].Stock[0].Quantity)
+	assert.True(t, updated.Variants[0].Stock[0].Backorder)
+	assert.Equal(t, 7, updated.Variants[1].Stock[0].Quantity)
+	assert.Equal(t, "Test Produkt", updated.BaseTitle)
+
+	// Both rows for the product are written as a single new version
+	assert.Equal(t, product.Version+1, updated.Version)
+
+	job, err := productService.jobs.GetByID(result.JobID)
+	assert.NoError(t, err)
+	assert.Equal(t, "supplier_stock.csv", job.Source)
+	assert.Equal(t, models.JobStatusCompleted, job.Status)
+}
+
+func TestImportProductsUpdateDryRun(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+	product := createSupplierCatalog(t, productService)
+
+	req := createSupplierRequest()
+	req.DryRun = true
+	result, err := service.ImportProducts(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, result.Rows[0].Product.Variants[0].Stock[0].Quantity)
+
+	unchanged, _ := productService.GetProduct(context.Background(), product.ID)
+	assert.Equal(t, product.Version, unchanged.Version)
+	assert.Equal(t, 1, unchanged.Variants[0].Stock[0].Quantity)
+}
+
+func TestImportProductsUnknownMode(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+
+	req := createImportRequest()
+	req.Mode = "upsert"
+	_, err := service.ImportProducts(context.Background(), req)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
+
+func TestImportProductsLocalizedColumns(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+	product := createSupplierCatalog(t, productService)
+
+	req := createSupplierRequest()
+	req.Locale = "sv-SE"
+	req.Columns = map[string]string{"price": "number", "qty": "number"}
+	req.Records = []map[string]string{
+		{"article": "SHIRT-1-S", "price": "1 249,50 kr", "qty": "1 200"},
+		{"article": "SHIRT-1-M", "price": "12.5", "qty": "tolv"},
+	}
+
+	result, err := service.ImportProducts(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+
+	// Every bad cell of the row is reported with its column
+	failed := result.Rows[1]
+	assert.Len(t, failed.Cells, 2)
+	assert.Equal(t, "price", failed.Cells[0].Column)
+	assert.Equal(t, "12.5", failed.Cells[0].Value)
+	assert.Equal(t, "qty", failed.Cells[1].Column)
+	assert.Contains(t, failed.Error, `column price: "12.5" is not a number in locale sv-SE`)
+
+	updated, err := productService.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1249.5, updated.Prices[0].Amount)
+	assert.Equal(t, 1200, updated.Variants[0].Stock[0].Quantity)
+}
+
+func TestImportProductsInvalidLocale(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+
+	req := createImportRequest()
+	req.Locale = "xx-XX"
+	_, err := service.ImportProducts(context.Background(), req)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	req = createImportRequest()
+	req.Columns = map[string]string{"price": "currency"}
+	_, err = service.ImportProducts(context.Background(), req)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
+
+func TestImportCSVInChunks(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+
+	var file strings.Builder
+	file.WriteString("sku,base_title,prices.SEK,metadata.SE.title,supplier_ref\n")
+	rows := 2*importChunkSize + 1
+	for i := 1; i <= rows; i++ {
+		if i == 3 {
+			file.WriteString("SHIRT-3,\"Shirt\"3\",299,Skjorta,ACME\n")
+			continue
+		}
+		fmt.Fprintf(&file, "SHIRT-%d,Shirt %d,299,Skjorta %d,ACME-%d\n", i, i, i, i)
+	}
+
+	// Without a mapping, columns named after a field are imported as they are
+	result, err := service.ImportCSV(context.Background(), &interfaces.ImportRequest{Source: "catalog.csv"}, strings.NewReader(file.String()))
+	assert.NoError(t, err)
+	assert.Equal(t, rows, result.Total)
+	assert.Equal(t, rows-1, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, rows, result.Rows[rows-1].Row)
+	assert.Contains(t, result.Rows[2].Error, "malformed CSV row")
+
+	created, err := productService.GetProduct(context.Background(), result.Rows[rows-1].ProductID)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("SHIRT-%d", rows), created.SKU)
+	assert.Equal(t, "Skjorta 1001", created.Metadata[0].Title)
+
+	// One import job, and one batch per chunk
+	job, err := productService.jobs.GetByID(result.JobID)
+	assert.NoError(t, err)
+	assert.Equal(t, rows, job.Total)
+	assert.Equal(t, "catalog.csv", job.Source)
+	jobs, err := productService.jobs.List(10)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 4)
+}
+
+func TestImportCSVWithMapping(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+	createSupplierCatalog(t, productService)
+
+	result, err := service.ImportCSV(context.Background(), &interfaces.ImportRequest{
+		Mode:    interfaces.ImportUpdate,
+		Mapping: map[string]string{"sku": "{{.article}}", "stock.wh1": "{{.qty}}"},
+		DryRun:  true,
+	}, strings.NewReader("article;qty\nSHIRT-1-M;7\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 7, result.Rows[0].Product.Variants[1].Stock[0].Quantity)
+}
+
+func TestImportCSVWithoutProductColumns(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewImportService(productService, productService.jobs)
+
+	_, err := service.ImportCSV(context.Background(), &interfaces.ImportRequest{}, strings.NewReader("article,qty\nSHIRT-1,7\n"))
+	assert.ErrorIs(t, err, models.ErrInvalidMapping)
+}
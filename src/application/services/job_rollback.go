@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// RollbackJob reverts every change made by a finished batch job. Each product
+// gets a compensating change tagged with a new rollback job: created products are
+// deleted, updated products get their previous state back and deleted products
+// are restored. Products modified after the job are left untouched and reported.
+func (s *productService) RollbackJob(ctx context.Context, jobID string) (*interfaces.JobRollbackResult, error) {
+	target, err := s.jobs.GetByID(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Status == models.JobStatusRunning {
+		return nil, fmt.Errorf("%w: %s", models.ErrJobNotFinished, jobID)
+	}
+
+	events, err := s.repo.GetEventsUntil(ctx, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load events: %v", err)
+	}
+
+	// Keep the job's event per product, in the order they were stored
+	jobEvents := make([]*models.Event, 0)
+	seen := make(map[string]bool)
+	for _, event := range events {
+		if event.JobID != jobID || seen[event.EntityID] {
+			continue
+		}
+		seen[event.EntityID] = true
+		jobEvents = append(jobEvents, event)
+	}
+
+	job, err := s.startJob(models.JobRollback, len(jobEvents))
+	if err != nil {
+		return nil, err
+	}
+	job.RollbackOf = jobID
+
+	results := make([]*interfaces.BatchResult, len(jobEvents))
+	compensations := make([]*models.Event, len(jobEvents))
+	for i, event := range jobEvents {
+		result := &interfaces.BatchResult{ID: event.EntityID, Success: true}
+		// Once the request is canceled the remaining events are not compensated
+		var compensation *models.Event
+		err := ctx.Err()
+		if err == nil {
+			compensation, err = s.compensate(ctx, event, job.ID)
+		}
+		if err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+		results[i] = result
+		compensations[i] = compensation
+	}
+	s.publishBatch(compensations, results)
+
+	if err := s.finishJob(job, results); err != nil {
+		return nil, err
+	}
+	return &interfaces.JobRollbackResult{Job: job, Results: results}, nil
+}
+
+// compensate applies the inverse of a single job event and returns the unpublished compensating event
+func (s *productService) compensate(ctx context.Context, event *models.Event, jobID string) (*models.Event, error) {
+	current, err := s.repo.GetByID(ctx, event.EntityID)
+	if err != nil && !errors.Is(err, models.ErrProductNotFound) {
+		return nil, err
+	}
+
+	switch event.Type {
+	case models.EventProductCreated, models.EventProductRestored:
+		if current == nil {
+			return nil, errors.New("product no longer exists")
+		}
+		if current.Version != event.Version {
+			return nil, errors.New("product was modified after the job")
+		}
+		return s.deleteProduct(ctx, current.ID, jobID)
+
+	case models.EventProductUpdated:
+		if current == nil {
+			return nil, errors.New("product no longer exists")
+		}
+		if current.Version != event.Version {
+			return nil, errors.New("product was modified after the job")
+		}
+		previous, err := s.productAtVersion(ctx, event.EntityID, event.Version-1)
+		if err != nil {
+			return nil, err
+		}
+		restored := previous.Clone()
+		restored.Version = current.Version
+		restored.CreatedAt = current.CreatedAt
+		return s.updateProduct(ctx, restored, "rolled_back", jobID)
+
+	case models.EventProductDeleted:
+		if current != nil {
+			return nil, errors.New("product was recreated after the job")
+		}
+		productEvent, ok := event.Data.(*models.ProductEvent)
+		if !ok || productEvent.Product == nil {
+			return nil, errors.New("delete event has no product state")
+		}
+		return s.restoreProduct(ctx, productEvent.Product, event.Version, jobID)
+	}
+
+	return nil, fmt.Errorf("unsupported event type %s", event.Type)
+}
+
+// productAtVersion returns the product state recorded by the event for the given version
+func (s *productService) productAtVersion(ctx context.Context, id string, version int64) (*models.Product, error) {
+	events, err := s.repo.GetEventsByProductID(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range events {
+		if event.Version != version || event.Type == models.EventProductDeleted {
+			continue
+		}
+		if productEvent, ok := event.Data.(*models.ProductEvent); ok && productEvent.Product != nil {
+			return productEvent.Product, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", models.ErrVersionNotFound, version)
+}
+
+// restoreProduct recreates a deleted product under its original ID and returns
+// the unpublished event. The restore continues the product's event chain after the delete event.
+func (s *productService) restoreProduct(ctx context.Context, deleted *models.Product, deleteVersion int64, jobID string) (*models.Event, error) {
+	product := deleted.Clone()
+	product.Version = deleteVersion + 1
+	product.UpdatedAt = time.Now()
+	product.LastHash = product.CalculateHash()
+
+	event := s.restoreEvent(product, deleted.LastHash, jobID)
+	err := s.repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, event); err != nil {
+			return err
+		}
+		return tx.Create(ctx, product)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// restoreEvent returns the unpublished event of a product that was brought
+// back after a delete event with the given hash
+func (s *productService) restoreEvent(product *models.Product, prevHash, jobID string) *models.Event {
+	return &models.Event{
+		ID:            uuid.New().String(),
+		Type:          models.EventProductRestored,
+		EntityID:      product.ID,
+		Version:       product.Version,
+		Sequence:      s.getNextSequence(),
+		SchemaVersion: models.EventSchemaVersion,
+		Data: &models.ProductEvent{
+			ProductID: product.ID,
+			Action:    "restored",
+			Product:   product.Clone(),
+			Version:   product.Version,
+			PrevHash:  prevHash,
+		},
+		JobID:     jobID,
+		Timestamp: time.Now(),
+	}
+}
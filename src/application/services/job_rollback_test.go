@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func createBatchProducts(t *testing.T, service *productService, count int) ([]*models.Product, string) {
+	products := make([]*models.Product, count)
+	for i := range products {
+		products[i] = createValidProduct()
+		products[i].SKU = fmt.Sprintf("BATCH-%d", i)
+	}
+	_, err := service.BatchCreateProducts(context.Background(), products)
+	assert.NoError(t, err)
+
+	jobs, err := service.jobs.List(1)
+	assert.NoError(t, err)
+	return products, jobs[0].ID
+}
+
+func TestBatchEventsTaggedWithJob(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	products, jobID := createBatchProducts(t, service, 2)
+
+	for _, product := range products {
+		events, err := service.repo.GetEventsByProductID(context.Background(), product.ID, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, jobID, events[0].JobID)
+	}
+}
+
+func TestRollbackJobCreate(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	products, jobID := createBatchProducts(t, service, 2)
+
+	result, err := service.RollbackJob(context.Background(), jobID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobRollback, result.Job.Type)
+	assert.Equal(t, jobID, result.Job.RollbackOf)
+	assert.Equal(t, 2, result.Job.Succeeded)
+
+	for _, product := range products {
+		_, err := service.GetProduct(context.Background(), product.ID)
+		assert.True(t, errors.Is(err, models.ErrProductNotFound))
+	}
+}
+
+func TestRollbackJobUpdate(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	products, _ := createBatchProducts(t, service, 2)
+	for _, product := range products {
+		product.BaseTitle = "Bad import"
+	}
+	_, err := service.BatchUpdateProducts(context.Background(), products)
+	assert.NoError(t, err)
+	jobs, _ := service.jobs.List(1)
+
+	// A later manual edit protects that product from the rollback
+	edited := products[1].Clone()
+	edited.BaseTitle = "Manual fix"
+	assert.NoError(t, service.UpdateProduct(context.Background(), edited))
+
+	result, err := service.RollbackJob(context.Background(), jobs[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Job.Succeeded)
+	assert.Equal(t, 1, result.Job.Failed)
+
+	reverted, err := service.GetProduct(context.Background(), products[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Produkt", reverted.BaseTitle)
+	assert.Equal(t, int64(3), reverted.Version)
+
+	untouched, err := service.GetProduct(context.Background(), products[1].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Manual fix", untouched.BaseTitle)
+
+	events, err := service.ReplayEvents(context.Background(), products[0].ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, result.Job.ID, events[len(events)-1].JobID)
+}
+
+func TestRollbackJobDelete(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	products, _ := createBatchProducts(t, service, 1)
+	_, err := service.BatchDeleteProducts(context.Background(), []string{products[0].ID})
+	assert.NoError(t, err)
+	jobs, _ := service.jobs.List(1)
+
+	result, err := service.RollbackJob(context.Background(), jobs[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Job.Succeeded)
+
+	restored, err := service.GetProduct(context.Background(), products[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, products[0].SKU, restored.SKU)
+	assert.Equal(t, int64(3), restored.Version)
+
+	// The restore continues the event chain after the delete
+	events, err := service.ReplayEvents(context.Background(), products[0].ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, models.EventProductRestored, events[2].Type)
+}
+
+func TestRollbackJobErrors(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	_, err := service.RollbackJob(context.Background(), "job_missing")
+	assert.True(t, errors.Is(err, models.ErrJobNotFound))
+
+	running := &models.Job{ID: "job_running", Type: models.JobBatchCreate, Status: models.JobStatusRunning, CreatedAt: time.Now()}
+	assert.NoError(t, service.jobs.Create(running))
+
+	_, err = service.RollbackJob(context.Background(), running.ID)
+	assert.True(t, errors.Is(err, models.ErrJobNotFinished))
+}
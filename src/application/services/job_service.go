@@ -0,0 +1,48 @@
+package services
+
+import (
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// jobService implements the JobService interface
+type jobService struct {
+	jobs repositories.JobRepository
+}
+
+// NewJobService creates a new job service instance
+func NewJobService(jobs repositories.JobRepository) interfaces.JobService {
+	return &jobService{
+		jobs: jobs,
+	}
+}
+
+// GetJob returns a job by ID
+func (s *jobService) GetJob(id string) (*models.Job, error) {
+	return s.jobs.GetByID(id)
+}
+
+// ListJobs returns the most recent jobs, newest first. The type filter is
+// applied before the limit.
+func (s *jobService) ListJobs(jobType models.JobType, limit int) ([]*models.Job, error) {
+	if jobType == "" {
+		return s.jobs.List(limit)
+	}
+
+	all, err := s.jobs.List(0)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*models.Job, 0)
+	for _, job := range all {
+		if job.Type != jobType {
+			continue
+		}
+		result = append(result, job)
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+func TestJobServiceGetJob(t *testing.T) {
+	jobs := memory.NewJobRepository()
+	service := NewJobService(jobs)
+	assert.NoError(t, jobs.Create(&models.Job{ID: "job_1", Type: models.JobImport, CreatedAt: time.Now()}))
+
+	job, err := service.GetJob("job_1")
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobImport, job.Type)
+
+	_, err = service.GetJob("missing")
+	assert.True(t, errors.Is(err, models.ErrJobNotFound))
+}
+
+func TestJobServiceListJobsByType(t *testing.T) {
+	jobs := memory.NewJobRepository()
+	service := NewJobService(jobs)
+	now := time.Now()
+	assert.NoError(t, jobs.Create(&models.Job{ID: "job_1", Type: models.JobImport, CreatedAt: now}))
+	assert.NoError(t, jobs.Create(&models.Job{ID: "job_2", Type: models.JobBatchUpdate, CreatedAt: now.Add(time.Second)}))
+	assert.NoError(t, jobs.Create(&models.Job{ID: "job_3", Type: models.JobImport, CreatedAt: now.Add(2 * time.Second)}))
+
+	all, err := service.ListJobs("", 10)
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	imported, err := service.ListJobs(models.JobImport, 10)
+	assert.NoError(t, err)
+	assert.Len(t, imported, 2)
+	assert.Equal(t, "job_3", imported[0].ID)
+
+	latest, err := service.ListJobs(models.JobImport, 1)
+	assert.NoError(t, err)
+	assert.Len(t, latest, 1)
+}
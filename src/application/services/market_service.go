@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// checklistPageSize is the page size used when scanning the catalog
+const checklistPageSize = 100
+
+// marketService implements the MarketService interface
+type marketService struct {
+	repo          repositories.ProductRepository
+	merchandising repositories.MerchandisingRepository
+	mu            sync.Mutex // Serializes pin changes so concurrent edits are not lost
+}
+
+// NewMarketService creates a new market service instance
+func NewMarketService(repo repositories.ProductRepository, merchandising repositories.MerchandisingRepository) interfaces.MarketService {
+	return &marketService{
+		repo:          repo,
+		merchandising: merchandising,
+	}
+}
+
+// LaunchChecklist checks every product in the catalog against the market's launch requirements
+func (s *marketService) LaunchChecklist(ctx context.Context, market, currency string) (*models.LaunchChecklist, error) {
+	market = strings.ToUpper(strings.TrimSpace(market))
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		defaultCurrency, ok := models.CurrencyForMarket(market)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", models.ErrUnknownMarketCurrency, market)
+		}
+		currency = defaultCurrency
+	}
+
+	checklist := &models.LaunchChecklist{
+		Market:   market,
+		Currency: currency,
+		Blocked:  make([]*models.BlockedProduct, 0),
+	}
+
+	for page := 1; ; page++ {
+		products, total, err := s.repo.List(ctx, models.ProductFilter{}, page, checklistPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list products: %v", err)
+		}
+
+		for _, product := range products {
+			blockers := models.CheckMarketLaunch(product, market, currency)
+			if len(blockers) == 0 {
+				checklist.ReadyCount++
+				continue
+			}
+			checklist.Blocked = append(checklist.Blocked, &models.BlockedProduct{
+				ProductID: product.ID,
+				SKU:       product.SKU,
+				Blockers:  blockers,
+			})
+		}
+
+		if len(products) == 0 || page*checklistPageSize >= total {
+			break
+		}
+	}
+
+	checklist.BlockedCount = len(checklist.Blocked)
+	checklist.TotalProducts = checklist.ReadyCount + checklist.BlockedCount
+	return checklist, nil
+}
+
+// ListMarketProducts arranges every product with metadata for the market and returns one page.
+// Drafts and products that lack compliance data the market requires are not listed.
+func (s *marketService) ListMarketProducts(ctx context.Context, market, category string, page, pageSize int) ([]*models.Product, int, error) {
+	market = strings.ToUpper(strings.TrimSpace(market))
+
+	listed := make([]*models.Product, 0)
+	for catalogPage := 1; ; catalogPage++ {
+		products, total, err := s.repo.List(ctx, models.ProductFilter{}, catalogPage, checklistPageSize)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list products: %v", err)
+		}
+		for _, product := range products {
+			if product.IsPublished() && product.MetadataForMarket(market) != nil && len(product.ComplianceIssues(market)) == 0 {
+				listed = append(listed, product)
+			}
+		}
+		if len(products) == 0 || catalogPage*checklistPageSize >= total {
+			break
+		}
+	}
+
+	merchandising, err := s.GetMerchandising(market, category)
+	if err != nil {
+		return nil, 0, err
+	}
+	arranged := merchandising.Arrange(listed)
+
+	start := (page - 1) * pageSize
+	if start >= len(arranged) {
+		return []*models.Product{}, len(arranged), nil
+	}
+	end := start + pageSize
+	if end > len(arranged) {
+		end = len(arranged)
+	}
+	return arranged[start:end], len(arranged), nil
+}
+
+// ListMerchandising returns the manual positions of every listing in a market
+func (s *marketService) ListMerchandising(market string) ([]*models.Merchandising, error) {
+	return s.merchandising.ListByMarket(strings.ToUpper(strings.TrimSpace(market)))
+}
+
+// GetMerchandising returns the manual positions of a listing
+func (s *marketService) GetMerchandising(market, category string) (*models.Merchandising, error) {
+	market = strings.ToUpper(strings.TrimSpace(market))
+	category = models.NormalizeCategory(category)
+
+	merchandising, err := s.merchandising.Get(market, category)
+	if errors.Is(err, models.ErrMerchandisingNotFound) {
+		return models.NewMerchandising(market, category), nil
+	}
+	return merchandising, err
+}
+
+// SetPins validates and stores a full set of pins for a listing
+func (s *marketService) SetPins(ctx context.Context, market, category string, pins []models.Pin) (*models.Merchandising, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merchandising, err := s.GetMerchandising(market, category)
+	if err != nil {
+		return nil, err
+	}
+	if pins == nil {
+		pins = make([]models.Pin, 0)
+	}
+	merchandising.Pins = pins
+	if err := merchandising.Validate(); err != nil {
+		return nil, err
+	}
+	for _, pin := range pins {
+		if err := s.checkListed(ctx, merchandising.Market, pin.ProductID); err != nil {
+			if errors.Is(err, models.ErrProductNotFound) {
+				return nil, fmt.Errorf("%w: product %s does not exist", models.ErrInvalidRequest, pin.ProductID)
+			}
+			return nil, err
+		}
+	}
+	return s.save(merchandising)
+}
+
+// PinProduct places one product in a listing, moving it if it was already pinned
+func (s *marketService) PinProduct(ctx context.Context, market, category, productID string, position int) (*models.Merchandising, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merchandising, err := s.GetMerchandising(market, category)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkListed(ctx, merchandising.Market, productID); err != nil {
+		return nil, err
+	}
+	if err := merchandising.Pin(productID, position); err != nil {
+		return nil, err
+	}
+	return s.save(merchandising)
+}
+
+// UnpinProduct removes a product's pin from a listing
+func (s *marketService) UnpinProduct(market, category, productID string) (*models.Merchandising, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merchandising, err := s.GetMerchandising(market, category)
+	if err != nil {
+		return nil, err
+	}
+	if !merchandising.Unpin(productID) {
+		return nil, models.ErrPinNotFound
+	}
+	return s.save(merchandising)
+}
+
+// checkListed verifies that a product exists and has metadata for the market
+func (s *marketService) checkListed(ctx context.Context, market, productID string) error {
+	product, err := s.repo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product.MetadataForMarket(market) == nil {
+		return fmt.Errorf("%w: product %s is not listed in %s", models.ErrInvalidRequest, productID, market)
+	}
+	return nil
+}
+
+// save stamps and stores the positions of a listing
+func (s *marketService) save(merchandising *models.Merchandising) (*models.Merchandising, error) {
+	merchandising.UpdatedAt = time.Now()
+	if err := s.merchandising.Save(merchandising); err != nil {
+		return nil, fmt.Errorf("failed to save merchandising: %v", err)
+	}
+	return merchandising, nil
+}
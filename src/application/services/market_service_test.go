@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+func TestLaunchChecklist(t *testing.T) {
+	repo := memory.NewProductRepository()
+	service := NewMarketService(repo, memory.NewMerchandisingRepository())
+
+	ready := createValidProduct()
+	ready.ID = "prod_ready"
+	ready.Variants = []models.Variant{{ID: "v1", SKU: "V1", Stock: []models.Stock{{LocationID: "wh1", Quantity: 1}}}}
+	ready.Images = []models.Image{{URL: "https://cdn.example.com/ready.jpg"}}
+	assert.NoError(t, repo.Create(context.Background(), ready))
+
+	blocked := createValidProduct()
+	blocked.ID = "prod_blocked"
+	assert.NoError(t, repo.Create(context.Background(), blocked))
+
+	checklist, err := service.LaunchChecklist(context.Background(), "se", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "SE", checklist.Market)
+	assert.Equal(t, "SEK", checklist.Currency)
+	assert.Equal(t, 2, checklist.TotalProducts)
+	assert.Equal(t, 1, checklist.ReadyCount)
+	assert.Equal(t, 1, checklist.BlockedCount)
+	assert.Equal(t, "prod_blocked", checklist.Blocked[0].ProductID)
+	assert.Len(t, checklist.Blocked[0].Blockers, 2) // no stock, no image
+}
+
+func TestLaunchChecklistScansAllPages(t *testing.T) {
+	repo := memory.NewProductRepository()
+	service := NewMarketService(repo, memory.NewMerchandisingRepository())
+
+	count := checklistPageSize + 5
+	for i := 0; i < count; i++ {
+		product := createValidProduct()
+		product.ID = fmt.Sprintf("prod_%d", i)
+		assert.NoError(t, repo.Create(context.Background(), product))
+	}
+
+	checklist, err := service.LaunchChecklist(context.Background(), "SE", "")
+	assert.NoError(t, err)
+	assert.Equal(t, count, checklist.TotalProducts)
+	assert.Equal(t, count, checklist.BlockedCount)
+}
+
+func TestLaunchChecklistUnknownMarket(t *testing.T) {
+	service := NewMarketService(memory.NewProductRepository(), memory.NewMerchandisingRepository())
+
+	_, err := service.LaunchChecklist(context.Background(), "XX", "")
+	assert.True(t, errors.Is(err, models.ErrUnknownMarketCurrency))
+
+	// An explicit currency works for markets without a default
+	checklist, err := service.LaunchChecklist(context.Background(), "XX", "usd")
+	assert.NoError(t, err)
+	assert.Equal(t, "USD", checklist.Currency)
+	assert.Empty(t, checklist.Blocked)
+}
+
+func createMarketProducts(t *testing.T, repo interface {
+	Create(context.Context, *models.Product) error
+}, ids ...string) {
+	for _, id := range ids {
+		product := createValidProduct()
+		product.ID = id
+		assert.NoError(t, repo.Create(context.Background(), product))
+	}
+}
+
+func TestListMarketProductsInMerchandisingOrder(t *testing.T) {
+	repo := memory.NewProductRepository()
+	service := NewMarketService(repo, memory.NewMerchandisingRepository())
+	createMarketProducts(t, repo, "prod_a", "prod_b", "prod_c")
+
+	unlisted := createValidProduct()
+	unlisted.ID = "prod_no"
+	unlisted.Metadata = []models.MarketMetadata{{Market: "NO", Title: "Produkt"}}
+	assert.NoError(t, repo.Create(context.Background(), unlisted))
+
+	_, err := service.PinProduct(context.Background(), "se", "Shirts", "prod_c", 1)
+	assert.NoError(t, err)
+
+	products, total, err := service.ListMarketProducts(context.Background(), "SE", "shirts", 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, "prod_c", products[0].ID)
+	assert.Len(t, products, 2)
+
+	// Other categories and the full listing keep their own order
+	products, _, err = service.ListMarketProducts(context.Background(), "SE", "", 1, 10)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "prod_c", products[0].ID)
+
+	products, total, err = service.ListMarketProducts(context.Background(), "SE", "shirts", 3, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Empty(t, products)
+}
+
+func TestListMarketProductsSkipsNonCompliantProducts(t *testing.T) {
+	repo := memory.NewProductRepository()
+	service := NewMarketService(repo, memory.NewMerchandisingRepository())
+	createMarketProducts(t, repo, "prod_a")
+
+	hazardous := createValidProduct()
+	hazardous.ID = "prod_hazardous"
+	hazardous.Compliance = &models.Compliance{HazardClass: "3", UNNumber: "UN1263"}
+	assert.NoError(t, repo.Create(context.Background(), hazardous))
+
+	products, total, err := service.ListMarketProducts(context.Background(), "SE", "", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "prod_a", products[0].ID)
+}
+
+func TestSetPins(t *testing.T) {
+	repo := memory.NewProductRepository()
+	service := NewMarketService(repo, memory.NewMerchandisingRepository())
+	createMarketProducts(t, repo, "prod_a", "prod_b")
+
+	merchandising, err := service.SetPins(context.Background(), "SE", "shirts", []models.Pin{{ProductID: "prod_b", Position: 1}, {ProductID: "prod_a", Position: 2}})
+	assert.NoError(t, err)
+	assert.False(t, merchandising.UpdatedAt.IsZero())
+
+	stored, err := service.GetMerchandising("SE", "shirts")
+	assert.NoError(t, err)
+	assert.Len(t, stored.Pins, 2)
+
+	listings, err := service.ListMerchandising("se")
+	assert.NoError(t, err)
+	assert.Len(t, listings, 1)
+
+	_, err = service.SetPins(context.Background(), "SE", "shirts", []models.Pin{{ProductID: "missing", Position: 1}})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+	_, err = service.SetPins(context.Background(), "NO", "shirts", []models.Pin{{ProductID: "prod_a", Position: 1}})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
+
+func TestPinAndUnpinProduct(t *testing.T) {
+	repo := memory.NewProductRepository()
+	service := NewMarketService(repo, memory.NewMerchandisingRepository())
+	createMarketProducts(t, repo, "prod_a")
+
+	_, err := service.PinProduct(context.Background(), "SE", "", "missing", 1)
+	assert.True(t, errors.Is(err, models.ErrProductNotFound))
+
+	merchandising, err := service.PinProduct(context.Background(), "SE", "", "prod_a", 4)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Pin{{ProductID: "prod_a", Position: 4}}, merchandising.Pins)
+
+	merchandising, err = service.UnpinProduct("SE", "", "prod_a")
+	assert.NoError(t, err)
+	assert.Empty(t, merchandising.Pins)
+
+	_, err = service.UnpinProduct("SE", "", "prod_a")
+	assert.True(t, errors.Is(err, models.ErrPinNotFound))
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// CopyMetadata starts a job that copies the metadata of a source market to the
+// target markets of the selected products in the background and returns the
+// job right away. Each changed product is written as a regular update tagged
+// with the job, so the copy can be rolled back.
+func (s *productService) CopyMetadata(ctx context.Context, metadataCopy *models.MetadataCopy) (*models.Job, error) {
+	if err := metadataCopy.Validate(); err != nil {
+		return nil, err
+	}
+
+	job, err := s.startJob(models.JobMetadataCopy, 0)
+	if err != nil {
+		return nil, err
+	}
+	started := *job
+
+	go s.runMetadataCopy(context.WithoutCancel(ctx), job, metadataCopy)
+	return &started, nil
+}
+
+// runMetadataCopy copies the metadata of the products it changes and records the outcome on the job
+func (s *productService) runMetadataCopy(ctx context.Context, job *models.Job, metadataCopy *models.MetadataCopy) {
+	logger := logging.Shared().WithFields(
+		zap.String("job_id", job.ID),
+		zap.String("source", metadataCopy.Source),
+		zap.Strings("targets", metadataCopy.Targets),
+	)
+
+	matched, err := s.scanCatalog(ctx, func(product *models.Product) bool {
+		if !metadataCopy.Filter.Matches(product) {
+			return false
+		}
+		_, changed := metadataCopy.Apply(product)
+		return changed
+	})
+	if err != nil {
+		logger.Error("Failed to scan catalog for metadata copy", zap.Error(err))
+		job.AddError(err.Error())
+		job.Complete(0, 1)
+		s.jobs.Update(job)
+		return
+	}
+
+	// Publish the total so progress is visible while the job runs
+	job.Total = len(matched)
+	s.jobs.Update(job)
+
+	results := make([]*interfaces.BatchResult, 0, len(matched))
+	for _, product := range matched {
+		result := &interfaces.BatchResult{ID: product.ID, Success: true}
+		if err := s.copyProductMetadata(ctx, product.ID, metadataCopy, job.ID); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			job.AddError(fmt.Sprintf("%s: %v", product.ID, err))
+		}
+		results = append(results, result)
+	}
+
+	if err := s.finishJob(job, results); err != nil {
+		logger.Error("Failed to record metadata copy", zap.Error(err))
+		return
+	}
+	logger.Info("Metadata copy completed",
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("failed", job.Failed),
+	)
+}
+
+// copyProductMetadata applies the copy to the current state of a product and publishes the update
+func (s *productService) copyProductMetadata(ctx context.Context, id string, metadataCopy *models.MetadataCopy, jobID string) error {
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	copied, changed := metadataCopy.Apply(current)
+	if !changed {
+		return nil
+	}
+	return s.publish(s.updateProduct(ctx, copied, "metadata_copied", jobID))
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyMetadata(t *testing.T) {
+	service, _, _ := setupProductService()
+	shirt := createTaggedProduct(t, service, "SHIRT-1", "launch")
+	sock := createTaggedProduct(t, service, "SOCK-1")
+
+	started, err := service.CopyMetadata(context.Background(), &models.MetadataCopy{
+		Source:  "se",
+		Targets: []string{"fi"},
+		Filter:  models.CatalogFilter{Tag: "launch"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobMetadataCopy, started.Type)
+
+	job := waitForJob(t, service, started.ID)
+	assert.Equal(t, models.JobStatusCompleted, job.Status)
+	assert.Equal(t, 1, job.Total)
+	assert.Equal(t, 1, job.Succeeded)
+
+	copied, err := service.GetProduct(context.Background(), shirt.ID)
+	assert.NoError(t, err)
+	if metadata := copied.MetadataForMarket("FI"); assert.NotNil(t, metadata) {
+		assert.Equal(t, "FI", metadata.Market)
+		assert.Equal(t, "Test Produkt", metadata.Title)
+		assert.Equal(t, "Test beskrivning", metadata.Description)
+	}
+	assert.Equal(t, int64(2), copied.Version)
+
+	events, err := service.repo.GetEventsByProductID(context.Background(), shirt.ID, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, job.ID, events[0].JobID)
+		assert.Equal(t, "metadata_copied", events[0].Data.(*models.ProductEvent).Action)
+	}
+
+	untouched, _ := service.GetProduct(context.Background(), sock.ID)
+	assert.Nil(t, untouched.MetadataForMarket("FI"))
+
+	_, err = service.RollbackJob(context.Background(), started.ID)
+	assert.NoError(t, err)
+	reverted, _ := service.GetProduct(context.Background(), shirt.ID)
+	assert.Nil(t, reverted.MetadataForMarket("FI"))
+}
+
+func TestCopyMetadataOnlyEmpty(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	product.Metadata = append(product.Metadata, models.MarketMetadata{Market: "FI", Title: "Testituote"})
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	started, err := service.CopyMetadata(context.Background(), &models.MetadataCopy{Source: "SE", Targets: []string{"FI"}, OnlyEmpty: true})
+	assert.NoError(t, err)
+	waitForJob(t, service, started.ID)
+
+	copied, _ := service.GetProduct(context.Background(), product.ID)
+	metadata := copied.MetadataForMarket("FI")
+	assert.Equal(t, "Testituote", metadata.Title, "filled fields are kept")
+	assert.Equal(t, "Test beskrivning", metadata.Description)
+}
+
+func TestCopyMetadataInvalid(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	_, err := service.CopyMetadata(context.Background(), &models.MetadataCopy{Source: "SE"})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	jobs, _ := service.jobs.List(10)
+	assert.Empty(t, jobs)
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// noteService implements the NoteService interface
+type noteService struct {
+	repo  repositories.ProductRepository
+	notes repositories.NoteRepository
+	mu    sync.Mutex // Serializes changes so a reply never outlives a deleted thread
+}
+
+// NewNoteService creates a new note service instance
+func NewNoteService(repo repositories.ProductRepository, notes repositories.NoteRepository) interfaces.NoteService {
+	return &noteService{
+		repo:  repo,
+		notes: notes,
+	}
+}
+
+// List groups the notes of a product into threads
+func (s *noteService) List(ctx context.Context, productID string) ([]*models.NoteThread, error) {
+	if _, err := s.repo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+	notes, err := s.notes.ListByProduct(productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %v", err)
+	}
+
+	threads := make([]*models.NoteThread, 0)
+	byID := make(map[string]*models.NoteThread)
+	for _, note := range notes {
+		if note.ThreadID == "" {
+			thread := &models.NoteThread{ProductNote: note, Replies: make([]*models.ProductNote, 0)}
+			threads = append(threads, thread)
+			byID[note.ID] = thread
+		}
+	}
+	for _, note := range notes {
+		if thread, exists := byID[note.ThreadID]; exists {
+			thread.Replies = append(thread.Replies, note)
+		}
+	}
+	return threads, nil
+}
+
+// Add writes a new thread or a reply
+func (s *noteService) Add(ctx context.Context, productID, threadID, author, body string) (*models.ProductNote, error) {
+	if author == "" {
+		return nil, fmt.Errorf("%w: author is required", models.ErrInvalidRequest)
+	}
+	body, err := models.ValidateNoteBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetByID(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if threadID != "" {
+		parent, err := s.notes.Get(productID, threadID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ThreadID != "" {
+			threadID = parent.ThreadID
+		}
+	}
+
+	now := time.Now()
+	note := &models.ProductNote{
+		ID:        uuid.New().String(),
+		ProductID: productID,
+		ThreadID:  threadID,
+		Author:    author,
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.notes.Save(note); err != nil {
+		return nil, fmt.Errorf("failed to save note: %v", err)
+	}
+	return note, nil
+}
+
+// Edit replaces the body of the author's own note
+func (s *noteService) Edit(productID, noteID, author, body string) (*models.ProductNote, error) {
+	body, err := models.ValidateNoteBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note, err := s.authored(productID, noteID, author)
+	if err != nil {
+		return nil, err
+	}
+	note.Body = body
+	note.UpdatedAt = time.Now()
+	if err := s.notes.Save(note); err != nil {
+		return nil, fmt.Errorf("failed to save note: %v", err)
+	}
+	return note, nil
+}
+
+// Resolve changes whether a thread is settled
+func (s *noteService) Resolve(productID, threadID string, resolved bool) (*models.ProductNote, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note, err := s.notes.Get(productID, threadID)
+	if err != nil {
+		return nil, err
+	}
+	if note.ThreadID != "" {
+		return nil, fmt.Errorf("%w: only threads can be resolved, %s is a reply", models.ErrInvalidRequest, threadID)
+	}
+	note.Resolved = resolved
+	note.UpdatedAt = time.Now()
+	if err := s.notes.Save(note); err != nil {
+		return nil, fmt.Errorf("failed to save note: %v", err)
+	}
+	return note, nil
+}
+
+// Delete removes the author's own note together with its replies
+func (s *noteService) Delete(productID, noteID, author string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	note, err := s.authored(productID, noteID, author)
+	if err != nil {
+		return err
+	}
+	ids := []string{note.ID}
+	if note.ThreadID == "" {
+		notes, err := s.notes.ListByProduct(productID)
+		if err != nil {
+			return fmt.Errorf("failed to list notes: %v", err)
+		}
+		for _, reply := range notes {
+			if reply.ThreadID == note.ID {
+				ids = append(ids, reply.ID)
+			}
+		}
+	}
+	return s.notes.Delete(productID, ids...)
+}
+
+// authored returns a note if it was written by author
+func (s *noteService) authored(productID, noteID, author string) (*models.ProductNote, error) {
+	note, err := s.notes.Get(productID, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if note.Author != author {
+		return nil, models.ErrNotNoteAuthor
+	}
+	return note, nil
+}
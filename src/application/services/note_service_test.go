@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+func setupNoteService(t *testing.T) (*noteService, *models.Product) {
+	productService, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, productService.CreateProduct(context.Background(), product))
+	return NewNoteService(productService.repo, memory.NewNoteRepository()).(*noteService), product
+}
+
+func TestNotesAreThreaded(t *testing.T) {
+	service, product := setupNoteService(t)
+
+	question, err := service.Add(context.Background(), product.ID, "", "ada@example.com", "  Is the EU size chart right?  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Is the EU size chart right?", question.Body)
+	assert.Empty(t, question.ThreadID)
+
+	answer, err := service.Add(context.Background(), product.ID, question.ID, "grace@example.com", "No, it runs small")
+	assert.NoError(t, err)
+	assert.Equal(t, question.ID, answer.ThreadID)
+
+	// Replying to a reply joins the same thread
+	followUp, err := service.Add(context.Background(), product.ID, answer.ID, "ada@example.com", "Fixed, thanks")
+	assert.NoError(t, err)
+	assert.Equal(t, question.ID, followUp.ThreadID)
+
+	other, err := service.Add(context.Background(), product.ID, "", "grace@example.com", "Photos for FI are missing")
+	assert.NoError(t, err)
+
+	threads, err := service.List(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Len(t, threads, 2)
+	assert.Equal(t, question.ID, threads[0].ID)
+	assert.Len(t, threads[0].Replies, 2)
+	assert.Equal(t, answer.ID, threads[0].Replies[0].ID)
+	assert.Equal(t, other.ID, threads[1].ID)
+	assert.Empty(t, threads[1].Replies)
+}
+
+func TestAddNoteValidates(t *testing.T) {
+	service, product := setupNoteService(t)
+
+	_, err := service.Add(context.Background(), product.ID, "", "", "body")
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.Add(context.Background(), product.ID, "", "ada", "   ")
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.Add(context.Background(), product.ID, "", "ada", strings.Repeat("å", models.MaxNoteLength+1))
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.Add(context.Background(), "missing", "", "ada", "body")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	_, err = service.Add(context.Background(), product.ID, "unknown", "ada", "body")
+	assert.ErrorIs(t, err, models.ErrNoteNotFound)
+	_, err = service.List(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestEditNoteOnlyByAuthor(t *testing.T) {
+	service, product := setupNoteService(t)
+	note, _ := service.Add(context.Background(), product.ID, "", "ada", "Draft copy")
+
+	_, err := service.Edit(product.ID, note.ID, "grace", "Final copy")
+	assert.ErrorIs(t, err, models.ErrNotNoteAuthor)
+
+	edited, err := service.Edit(product.ID, note.ID, "ada", "Final copy")
+	assert.NoError(t, err)
+	assert.Equal(t, "Final copy", edited.Body)
+	assert.True(t, edited.UpdatedAt.After(note.UpdatedAt) || edited.UpdatedAt.Equal(note.UpdatedAt))
+}
+
+func TestResolveThread(t *testing.T) {
+	service, product := setupNoteService(t)
+	question, _ := service.Add(context.Background(), product.ID, "", "ada", "Which supplier?")
+	reply, _ := service.Add(context.Background(), product.ID, question.ID, "grace", "Nordic Textiles")
+
+	resolved, err := service.Resolve(product.ID, question.ID, true)
+	assert.NoError(t, err)
+	assert.True(t, resolved.Resolved)
+
+	_, err = service.Resolve(product.ID, reply.ID, true)
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	reopened, err := service.Resolve(product.ID, question.ID, false)
+	assert.NoError(t, err)
+	assert.False(t, reopened.Resolved)
+}
+
+func TestDeleteThreadRemovesReplies(t *testing.T) {
+	service, product := setupNoteService(t)
+	question, _ := service.Add(context.Background(), product.ID, "", "ada", "Which supplier?")
+	reply, _ := service.Add(context.Background(), product.ID, question.ID, "grace", "Nordic Textiles")
+	service.Add(context.Background(), product.ID, "", "grace", "Another thread")
+
+	assert.ErrorIs(t, service.Delete(product.ID, question.ID, "grace"), models.ErrNotNoteAuthor)
+	assert.NoError(t, service.Delete(product.ID, question.ID, "ada"))
+
+	threads, _ := service.List(context.Background(), product.ID)
+	assert.Len(t, threads, 1)
+	_, err := service.notes.Get(product.ID, reply.ID)
+	assert.ErrorIs(t, err, models.ErrNoteNotFound)
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// priceListService implements the PriceListService interface
+type priceListService struct {
+	lists     repositories.PriceListRepository
+	products  repositories.ProductRepository
+	publisher events.EventPublisher
+
+	// mu serializes changes to lists, so each change event compares the
+	// list against the version it replaced
+	mu sync.Mutex
+}
+
+// NewPriceListService creates a new price list service instance. Changes to
+// lists are published as price.changed events of the affected products.
+func NewPriceListService(lists repositories.PriceListRepository, products repositories.ProductRepository, publisher events.EventPublisher) interfaces.PriceListService {
+	return &priceListService{
+		lists:     lists,
+		products:  products,
+		publisher: publisher,
+	}
+}
+
+// ListPriceLists returns every price list
+func (s *priceListService) ListPriceLists() ([]*models.PriceList, error) {
+	return s.lists.List()
+}
+
+// GetPriceList returns a price list
+func (s *priceListService) GetPriceList(id string) (*models.PriceList, error) {
+	return s.lists.Get(id)
+}
+
+// CreatePriceList stores a new list with a generated ID
+func (s *priceListService) CreatePriceList(ctx context.Context, list *models.PriceList) (*models.PriceList, error) {
+	created := *list
+	if err := s.validate(ctx, &created); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	created.ID = uuid.New().String()
+	created.CreatedAt = now
+	created.UpdatedAt = now
+	if err := s.lists.Save(&created); err != nil {
+		return nil, fmt.Errorf("failed to save price list: %v", err)
+	}
+	s.publishListChanges(ctx, "price_list_saved", nil, &created)
+	return &created, nil
+}
+
+// UpdatePriceList replaces a list, keeping its creation time
+func (s *priceListService) UpdatePriceList(ctx context.Context, list *models.PriceList) (*models.PriceList, error) {
+	updated := *list
+	if err := s.validate(ctx, &updated); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, err := s.lists.Get(updated.ID)
+	if err != nil {
+		return nil, err
+	}
+	updated.CreatedAt = previous.CreatedAt
+	updated.UpdatedAt = time.Now()
+	if err := s.lists.Save(&updated); err != nil {
+		return nil, fmt.Errorf("failed to save price list: %v", err)
+	}
+	s.publishListChanges(ctx, "price_list_saved", previous, &updated)
+	return &updated, nil
+}
+
+// DeletePriceList removes a list; its products fall back to the other lists
+func (s *priceListService) DeletePriceList(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, err := s.lists.Get(id)
+	if err != nil {
+		return err
+	}
+	if err := s.lists.Delete(id); err != nil {
+		return err
+	}
+	s.publishListChanges(ctx, "price_list_deleted", previous, nil)
+	return nil
+}
+
+// ResolvePrice resolves a product's price against every stored list
+func (s *priceListService) ResolvePrice(ctx context.Context, productID string, query models.PriceQuery) (*models.ResolvedPrice, error) {
+	if query.Currency == "" {
+		return nil, fmt.Errorf("%w: currency is required", models.ErrInvalidRequest)
+	}
+	if query.At.IsZero() {
+		query.At = time.Now()
+	}
+	product, err := s.products.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsDeleted() {
+		return nil, models.ErrProductNotFound
+	}
+	lists, err := s.lists.List()
+	if err != nil {
+		return nil, err
+	}
+	return product.ResolvePrice(lists, query)
+}
+
+// PublishScheduledChanges publishes an event per list and product whose
+// scheduled prices started or ended in the window
+func (s *priceListService) PublishScheduledChanges(ctx context.Context, since, until time.Time) (int, error) {
+	lists, err := s.lists.List()
+	if err != nil {
+		return 0, err
+	}
+
+	var pending []*models.Event
+	for _, list := range lists {
+		seen := make(map[string]bool)
+		for _, price := range list.Prices {
+			if seen[price.ProductID] || !price.ChangesBetween(since, until) {
+				continue
+			}
+			seen[price.ProductID] = true
+			pending = append(pending, s.priceEvent(ctx, price.ProductID, "price_schedule_reached", models.Change{
+				Field:    "price_lists." + list.ID,
+				OldValue: validPrices(list.PricesOf(price.ProductID), since),
+				NewValue: validPrices(list.PricesOf(price.ProductID), until),
+			}))
+		}
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+	published := len(pending)
+	for _, err := range s.publisher.PublishBatch(pending) {
+		if err != nil {
+			published--
+		}
+	}
+	return published, nil
+}
+
+// validate checks a list and that the products and variants it prices exist
+func (s *priceListService) validate(ctx context.Context, list *models.PriceList) error {
+	if err := list.Validate(); err != nil {
+		return err
+	}
+	products := make(map[string]*models.Product)
+	for _, price := range list.Prices {
+		product, checked := products[price.ProductID]
+		if !checked {
+			found, err := s.products.GetByID(ctx, price.ProductID)
+			if err != nil {
+				return fmt.Errorf("%w: product %s not found", models.ErrInvalidRequest, price.ProductID)
+			}
+			product = found
+			products[price.ProductID] = product
+		}
+		if price.VariantID != "" && !hasVariant(product, price.VariantID) {
+			return fmt.Errorf("%w: product %s has no variant %s", models.ErrInvalidRequest, price.ProductID, price.VariantID)
+		}
+	}
+	return nil
+}
+
+// publishListChanges publishes an event for every product whose prices in the
+// list changed. A change of the list's scope affects all of its products.
+func (s *priceListService) publishListChanges(ctx context.Context, action string, previous, current *models.PriceList) {
+	before, order := pricesByProduct(previous, nil)
+	after, order := pricesByProduct(current, order)
+	list := current
+	if list == nil {
+		list = previous
+	}
+	scopeChanged := previous == nil || current == nil || previous.Currency != current.Currency ||
+		previous.Market != current.Market || previous.CustomerGroup != current.CustomerGroup || previous.Priority != current.Priority
+
+	var pending []*models.Event
+	for _, productID := range order {
+		if !scopeChanged && reflect.DeepEqual(before[productID], after[productID]) {
+			continue
+		}
+		pending = append(pending, s.priceEvent(ctx, productID, action, models.Change{
+			Field:    "price_lists." + list.ID,
+			OldValue: before[productID],
+			NewValue: after[productID],
+		}))
+	}
+	if len(pending) > 0 {
+		s.publisher.PublishBatch(pending)
+	}
+}
+
+// pricesByProduct groups the prices of a list per product and appends the
+// products not yet in order to it
+func pricesByProduct(list *models.PriceList, order []string) (map[string][]models.ScheduledPrice, []string) {
+	known := make(map[string]bool, len(order))
+	for _, productID := range order {
+		known[productID] = true
+	}
+	prices := make(map[string][]models.ScheduledPrice)
+	if list == nil {
+		return prices, order
+	}
+	for _, price := range list.Prices {
+		if !known[price.ProductID] {
+			known[price.ProductID] = true
+			order = append(order, price.ProductID)
+		}
+		prices[price.ProductID] = append(prices[price.ProductID], price)
+	}
+	return prices, order
+}
+
+// priceEvent builds the price.changed event of a product at its current version
+func (s *priceListService) priceEvent(ctx context.Context, productID, action string, change models.Change) *models.Event {
+	data := &models.ProductEvent{
+		ProductID: productID,
+		Action:    action,
+		Changes:   []models.Change{change},
+	}
+	if product, err := s.products.GetByID(ctx, productID); err == nil {
+		data.Product = product.Clone()
+		data.Version = product.Version
+	}
+	return &models.Event{
+		ID:            uuid.New().String(),
+		Type:          models.EventPriceChanged,
+		EntityID:      productID,
+		Version:       data.Version,
+		SchemaVersion: models.EventSchemaVersion,
+		Data:          data,
+		Timestamp:     time.Now(),
+	}
+}
+
+// validPrices returns the prices that are valid at a time
+func validPrices(prices []models.ScheduledPrice, at time.Time) []models.ScheduledPrice {
+	var valid []models.ScheduledPrice
+	for _, price := range prices {
+		if price.ValidAt(at) {
+			valid = append(valid, price)
+		}
+	}
+	return valid
+}
+
+// hasVariant reports whether a product has a variant
+func hasVariant(product *models.Product, id string) bool {
+	for _, variant := range product.Variants {
+		if variant.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+// setupPriceListService returns a price list service, its publisher and a product with one variant
+func setupPriceListService(t *testing.T) (*priceListService, *MockEventPublisher, *models.Product) {
+	products, _, _ := setupProductService()
+	product := createStockedProduct(t, products, 1)
+
+	publisher := new(MockEventPublisher)
+	publisher.On("PublishBatch", mock.AnythingOfType("[]*models.Event")).Return(nil).Maybe()
+	service := NewPriceListService(memory.NewPriceListRepository(), products.repo, publisher).(*priceListService)
+	return service, publisher, product
+}
+
+// publishedPriceEvents returns the events of every PublishBatch call
+func publishedPriceEvents(publisher *MockEventPublisher) []*models.Event {
+	var published []*models.Event
+	for _, call := range publisher.Calls {
+		if call.Method == "PublishBatch" {
+			published = append(published, call.Arguments.Get(0).([]*models.Event)...)
+		}
+	}
+	return published
+}
+
+func TestCreatePriceListPublishesPriceChanged(t *testing.T) {
+	service, publisher, product := setupPriceListService(t)
+
+	list, err := service.CreatePriceList(context.Background(), &models.PriceList{Name: "B2B", Currency: "sek", CustomerGroup: "B2B", Prices: []models.ScheduledPrice{
+		{ProductID: product.ID, Amount: 80},
+		{ProductID: product.ID, VariantID: "v1", Amount: 75, ValidFrom: at(time.Now().Add(time.Hour))},
+	}})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, list.ID)
+	assert.Equal(t, "SEK", list.Currency)
+	assert.Equal(t, "b2b", list.CustomerGroup)
+
+	published := publishedPriceEvents(publisher)
+	assert.Len(t, published, 1)
+	assert.Equal(t, models.EventPriceChanged, published[0].Type)
+	assert.Equal(t, product.ID, published[0].EntityID)
+	data := published[0].Data.(*models.ProductEvent)
+	assert.Equal(t, "price_list_saved", data.Action)
+	assert.Equal(t, product.SKU, data.Product.SKU)
+	assert.Equal(t, "price_lists."+list.ID, data.Changes[0].Field)
+	assert.Len(t, data.Changes[0].NewValue, 2)
+
+	stored, err := service.GetPriceList(list.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, list.Prices, stored.Prices)
+}
+
+func TestCreatePriceListRejectsUnknownProductsAndVariants(t *testing.T) {
+	service, publisher, product := setupPriceListService(t)
+
+	_, err := service.CreatePriceList(context.Background(), &models.PriceList{Currency: "SEK", Prices: []models.ScheduledPrice{{ProductID: "missing", Amount: 1}}})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	_, err = service.CreatePriceList(context.Background(), &models.PriceList{Currency: "SEK", Prices: []models.ScheduledPrice{{ProductID: product.ID, VariantID: "v9", Amount: 1}}})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	publisher.AssertNotCalled(t, "PublishBatch", mock.Anything)
+}
+
+func TestUpdatePriceListOnlyPublishesChangedProducts(t *testing.T) {
+	service, publisher, product := setupPriceListService(t)
+	other := createValidProduct()
+	other.ID = "prod_other"
+	assert.NoError(t, service.products.Create(context.Background(), other))
+
+	list, err := service.CreatePriceList(context.Background(), &models.PriceList{Currency: "SEK", Prices: []models.ScheduledPrice{
+		{ProductID: product.ID, Amount: 80},
+		{ProductID: other.ID, Amount: 50},
+	}})
+	assert.NoError(t, err)
+	publisher.Calls = nil
+
+	list.Prices[1].Amount = 45
+	updated, err := service.UpdatePriceList(context.Background(), list)
+	assert.NoError(t, err)
+	assert.Equal(t, list.CreatedAt, updated.CreatedAt)
+	published := publishedPriceEvents(publisher)
+	assert.Len(t, published, 1)
+	assert.Equal(t, other.ID, published[0].EntityID)
+
+	// Deleting the list changes the price of every product in it
+	publisher.Calls = nil
+	assert.NoError(t, service.DeletePriceList(context.Background(), list.ID))
+	published = publishedPriceEvents(publisher)
+	assert.Len(t, published, 2)
+	assert.Equal(t, "price_list_deleted", published[0].Data.(*models.ProductEvent).Action)
+
+	_, err = service.UpdatePriceList(context.Background(), list)
+	assert.True(t, errors.Is(err, models.ErrPriceListNotFound))
+	assert.True(t, errors.Is(service.DeletePriceList(context.Background(), list.ID), models.ErrPriceListNotFound))
+}
+
+func TestResolvePriceUsesStoredLists(t *testing.T) {
+	service, _, product := setupPriceListService(t)
+	until := time.Now().Add(time.Hour)
+	_, err := service.CreatePriceList(context.Background(), &models.PriceList{Currency: "SEK", Market: "SE", Prices: []models.ScheduledPrice{
+		{ProductID: product.ID, Amount: 80, ValidTo: &until},
+	}})
+	assert.NoError(t, err)
+
+	price, err := service.ResolvePrice(context.Background(), product.ID, models.PriceQuery{Currency: "SEK", Market: "SE", VariantID: "v1"})
+	assert.NoError(t, err)
+	assert.Equal(t, 80.0, price.Amount)
+	assert.Equal(t, models.PriceSourcePriceList, price.Source)
+	assert.False(t, price.At.IsZero())
+
+	// After the scheduled price ends the product price applies again
+	price, err = service.ResolvePrice(context.Background(), product.ID, models.PriceQuery{Currency: "SEK", Market: "SE", At: until})
+	assert.NoError(t, err)
+	assert.Equal(t, product.Prices[0].Amount, price.Amount)
+	assert.Equal(t, models.PriceSourceProduct, price.Source)
+
+	_, err = service.ResolvePrice(context.Background(), product.ID, models.PriceQuery{})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+	_, err = service.ResolvePrice(context.Background(), "missing", models.PriceQuery{Currency: "SEK"})
+	assert.True(t, errors.Is(err, models.ErrProductNotFound))
+}
+
+func TestPublishScheduledChanges(t *testing.T) {
+	service, publisher, product := setupPriceListService(t)
+	start := time.Now().Add(time.Hour)
+	end := start.Add(24 * time.Hour)
+	list, err := service.CreatePriceList(context.Background(), &models.PriceList{Currency: "SEK", Prices: []models.ScheduledPrice{
+		{ProductID: product.ID, Amount: 60, ValidFrom: &start, ValidTo: &end},
+		{ProductID: product.ID, VariantID: "v1", Amount: 55, ValidFrom: &start, ValidTo: &end},
+	}})
+	assert.NoError(t, err)
+	publisher.Calls = nil
+
+	count, err := service.PublishScheduledChanges(context.Background(), time.Now(), start.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// One event per product and list, however many of its prices start
+	count, err = service.PublishScheduledChanges(context.Background(), start.Add(-time.Minute), start)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	published := publishedPriceEvents(publisher)
+	data := published[0].Data.(*models.ProductEvent)
+	assert.Equal(t, "price_schedule_reached", data.Action)
+	assert.Equal(t, "price_lists."+list.ID, data.Changes[0].Field)
+	assert.Nil(t, data.Changes[0].OldValue)
+	assert.Len(t, data.Changes[0].NewValue, 2)
+
+	count, err = service.PublishScheduledChanges(context.Background(), end.Add(-time.Minute), end)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+// at returns a pointer to a time
+func at(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// checkBundle validates the bundle of a product being written and checks
+// each component against the product it refers to
+func (s *productService) checkBundle(ctx context.Context, product *models.Product) error {
+	if err := product.ValidateBundle(); err != nil {
+		return err
+	}
+	if product.Bundle == nil {
+		return nil
+	}
+	for _, component := range product.Bundle.Components {
+		componentProduct, err := s.activeProduct(ctx, component.ProductID)
+		if errors.Is(err, models.ErrProductNotFound) {
+			return fmt.Errorf("%w: component %s not found", models.ErrInvalidRequest, component.ProductID)
+		}
+		if err != nil {
+			return err
+		}
+		if err := component.CheckComponent(product.ID, componentProduct); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// AssignCategories replaces the categories of a product and publishes the
+// change as an update with action categories_assigned. Whether the categories
+// exist is up to the caller.
+func (s *productService) AssignCategories(ctx context.Context, id string, categoryIDs []string) (*models.Product, error) {
+	categoryIDs = models.NormalizeCategoryIDs(categoryIDs)
+	if len(categoryIDs) > models.MaxProductCategories {
+		return nil, fmt.Errorf("%w: at most %d categories per product", models.ErrInvalidRequest, models.MaxProductCategories)
+	}
+	current, err := s.activeProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	assigned := current.Clone()
+	assigned.CategoryIDs = categoryIDs
+	if err := s.publish(s.updateProduct(ctx, assigned, "categories_assigned", "")); err != nil {
+		return nil, err
+	}
+	return assigned, nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignCategories(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	assigned, err := service.AssignCategories(context.Background(), product.ID, []string{" shirts", "sale", "shirts"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"shirts", "sale"}, assigned.CategoryIDs)
+	assert.Equal(t, product.Version+1, assigned.Version)
+
+	events, err := service.repo.GetEventsByProductID(context.Background(), product.ID, 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	data := events[1].Data.(*models.ProductEvent)
+	assert.Equal(t, "categories_assigned", data.Action)
+	assert.Equal(t, "category_ids", data.Changes[0].Field)
+
+	// An empty list removes the product from every category
+	assigned, err = service.AssignCategories(context.Background(), product.ID, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, assigned.CategoryIDs)
+}
+
+func TestAssignCategoriesErrors(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	tooMany := make([]string, models.MaxProductCategories+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("cat_%d", i)
+	}
+	_, err := service.AssignCategories(context.Background(), product.ID, tooMany)
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	_, err = service.AssignCategories(context.Background(), "missing", []string{"shirts"})
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// ListProductVersions lists the versions of a product that its verified event
+// chain can reconstruct, oldest first. Deletions carry the state they removed
+// instead of a version of their own and are left out.
+func (s *productService) ListProductVersions(ctx context.Context, id string) ([]*models.ProductVersion, error) {
+	events, err := s.ReplayEvents(ctx, id, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]*models.ProductVersion, 0, len(events))
+	for _, event := range events {
+		productEvent, ok := event.Data.(*models.ProductEvent)
+		if !ok || productEvent.Product == nil || event.Type == models.EventProductDeleted {
+			continue
+		}
+		versions = append(versions, &models.ProductVersion{
+			Version:   event.Version,
+			Type:      event.Type,
+			Action:    productEvent.Action,
+			JobID:     event.JobID,
+			Timestamp: event.Timestamp,
+		})
+	}
+	return versions, nil
+}
+
+// GetProductVersion reconstructs a product as it was at a version by replaying
+// its verified event chain up to that version. It fails with
+// models.ErrVersionNotFound for versions the chain does not contain.
+func (s *productService) GetProductVersion(ctx context.Context, id string, version int64) (*models.Product, error) {
+	events, err := s.ReplayEvents(ctx, id, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, event := range events {
+		if event.Version > version {
+			break
+		}
+		if event.Version != version || event.Type == models.EventProductDeleted {
+			continue
+		}
+		if productEvent, ok := event.Data.(*models.ProductEvent); ok && productEvent.Product != nil {
+			return productEvent.Product.Clone(), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", models.ErrVersionNotFound, version)
+}
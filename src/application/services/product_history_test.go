@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestListProductVersions(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	product.BaseTitle = "Second"
+	assert.NoError(t, service.UpdateProduct(context.Background(), product))
+	_, err := service.SoftDeleteProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	_, err = service.RestoreProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+
+	versions, err := service.ListProductVersions(context.Background(), product.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, versions, 3) {
+		assert.Equal(t, int64(1), versions[0].Version)
+		assert.Equal(t, models.EventProductCreated, versions[0].Type)
+		assert.Equal(t, "updated", versions[1].Action)
+		assert.Equal(t, int64(4), versions[2].Version)
+		assert.Equal(t, "restored", versions[2].Action)
+		assert.False(t, versions[2].Timestamp.IsZero())
+	}
+
+	_, err = service.ListProductVersions(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestGetProductVersion(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	original := product.BaseTitle
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	product.BaseTitle = "Second"
+	assert.NoError(t, service.UpdateProduct(context.Background(), product))
+
+	first, err := service.GetProductVersion(context.Background(), product.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, original, first.BaseTitle)
+	assert.Equal(t, int64(1), first.Version)
+	second, err := service.GetProductVersion(context.Background(), product.ID, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "Second", second.BaseTitle)
+
+	// A deletion has no state of its own
+	assert.NoError(t, service.DeleteProduct(context.Background(), product.ID))
+	_, err = service.GetProductVersion(context.Background(), product.ID, 3)
+	assert.ErrorIs(t, err, models.ErrVersionNotFound)
+	first, err = service.GetProductVersion(context.Background(), product.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, original, first.BaseTitle)
+
+	_, err = service.GetProductVersion(context.Background(), product.ID, 9)
+	assert.ErrorIs(t, err, models.ErrVersionNotFound)
+}
+
+func TestGetProductVersionVerifiesChain(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	assert.NoError(t, service.repo.StoreEvent(context.Background(), &models.Event{
+		ID:        "evt_gap",
+		Type:      models.EventProductUpdated,
+		EntityID:  product.ID,
+		Version:   3,
+		Data:      &models.ProductEvent{ProductID: product.ID, Product: product.Clone(), Version: 3, PrevHash: product.LastHash},
+		Timestamp: time.Now(),
+	}))
+
+	_, err := service.GetProductVersion(context.Background(), product.ID, 1)
+	assert.ErrorIs(t, err, models.ErrBrokenEventChain)
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// UpdateImages replaces the images of a product at a version and publishes
+// the change as an update with action images_updated. The images have been
+// validated by the caller.
+func (s *productService) UpdateImages(ctx context.Context, id string, version int64, images []models.Image) (*models.Product, error) {
+	if len(images) > models.MaxProductImages {
+		return nil, fmt.Errorf("%w: at most %d images per product", models.ErrInvalidRequest, models.MaxProductImages)
+	}
+	current, err := s.activeProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	updated := current.Clone()
+	updated.Version = version
+	updated.Images = images
+	if err := s.publish(s.updateProduct(ctx, updated, "images_updated", "")); err != nil {
+		return nil, err
+	}
+	// The stored version has the generated image IDs
+	return s.activeProduct(ctx, id)
+}
+
+// assignImageIDs gives images without an ID a new one, so they can be
+// detached and reordered by ID
+func assignImageIDs(images []models.Image) {
+	for i := range images {
+		if images[i].ID == "" {
+			images[i].ID = uuid.New().String()
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestUpdateImages(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	product.Images = []models.Image{{URL: "https://cdn.example.com/front.jpg"}}
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	stored, err := service.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, stored.Images[0].ID)
+
+	images := append(stored.Images, models.Image{URL: "https://cdn.example.com/back.jpg", AltTexts: map[string]string{"SE": "Baksida"}})
+	updated, err := service.UpdateImages(context.Background(), product.ID, stored.Version, images)
+	assert.NoError(t, err)
+	assert.Len(t, updated.Images, 2)
+	assert.Equal(t, stored.Images[0].ID, updated.Images[0].ID)
+	assert.NotEmpty(t, updated.Images[1].ID)
+	assert.Equal(t, stored.Version+1, updated.Version)
+
+	events, err := service.repo.GetEventsByProductID(context.Background(), product.ID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "images_updated", events[len(events)-1].Data.(*models.ProductEvent).Action)
+
+	// A stale version conflicts
+	_, err = service.UpdateImages(context.Background(), product.ID, stored.Version, nil)
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+
+	_, err = service.UpdateImages(context.Background(), product.ID, updated.Version, make([]models.Image, models.MaxProductImages+1))
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.UpdateImages(context.Background(), "missing", 1, nil)
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
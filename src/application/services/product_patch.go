@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/patch"
+)
+
+// PatchProduct applies a JSON Merge Patch or JSON Patch, chosen by media
+// type, to the stored product and saves the result as the next version. The
+// ID, version and timestamps cannot be patched. The patch applies to the given
+// version only and the update is made against it, so a concurrent update makes
+// it fail with models.ErrVersionConflict instead of being overwritten.
+func (s *productService) PatchProduct(ctx context.Context, id string, version int64, mediaType string, document []byte) (*models.Product, error) {
+	current, err := s.activeProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Version != version {
+		return nil, fmt.Errorf("%w: expected %d, got %d", models.ErrVersionConflict, current.Version, version)
+	}
+	original, err := json.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch mediaType {
+	case patch.MergePatchType:
+		patched, err = patch.Merge(original, document)
+	case patch.JSONPatchType:
+		patched, err = patch.Apply(original, document)
+	default:
+		return nil, fmt.Errorf("%w: unsupported patch type %q", models.ErrInvalidRequest, mediaType)
+	}
+	if errors.Is(err, patch.ErrInvalidPatch) {
+		return nil, fmt.Errorf("%w: %v", models.ErrInvalidRequest, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var product models.Product
+	if err := json.Unmarshal(patched, &product); err != nil {
+		return nil, fmt.Errorf("%w: patched product is invalid: %v", models.ErrInvalidRequest, err)
+	}
+	product.ID = current.ID
+	product.Version = current.Version
+	product.CreatedAt = current.CreatedAt
+	product.UpdatedAt = current.UpdatedAt
+	product.LastHash = current.LastHash
+	if err := models.ValidateProduct(&product); err != nil {
+		if !errors.Is(err, models.ErrInvalidRequest) {
+			err = fmt.Errorf("%w: %v", models.ErrInvalidRequest, err)
+		}
+		return nil, err
+	}
+
+	if err := s.UpdateProduct(ctx, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
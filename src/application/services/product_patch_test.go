@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/patch"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatchProductWithMergePatch(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	patched, err := service.PatchProduct(context.Background(), product.ID, product.Version, patch.MergePatchType,
+		[]byte(`{"base_title": "Patched", "description": null, "version": 99, "id": "other"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Patched", patched.BaseTitle)
+	assert.Equal(t, product.ID, patched.ID)
+	assert.Equal(t, int64(2), patched.Version)
+	assert.Equal(t, product.Prices, patched.Prices)
+	assert.Equal(t, product.CreatedAt, patched.CreatedAt)
+
+	stored, _ := service.GetProduct(context.Background(), product.ID)
+	assert.Equal(t, "Patched", stored.BaseTitle)
+	assert.Empty(t, stored.Description)
+}
+
+func TestPatchProductWithJSONPatch(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	patched, err := service.PatchProduct(context.Background(), product.ID, product.Version, patch.JSONPatchType, []byte(`[
+		{"op": "test", "path": "/prices/0/currency", "value": "SEK"},
+		{"op": "replace", "path": "/prices/0/amount", "value": 149},
+		{"op": "add", "path": "/tags", "value": ["Sale"]}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, 149.0, patched.Prices[0].Amount)
+	assert.Equal(t, []string{"sale"}, patched.Tags)
+
+	_, err = service.PatchProduct(context.Background(), product.ID, patched.Version, patch.JSONPatchType, []byte(`[{"op": "test", "path": "/base_title", "value": "Other"}]`))
+	assert.ErrorIs(t, err, patch.ErrTestFailed)
+}
+
+func TestPatchProductRequiresCurrentVersion(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	_, err := service.PatchProduct(context.Background(), product.ID, 1, patch.MergePatchType, []byte(`{"base_title": "First"}`))
+	assert.NoError(t, err)
+
+	// A patch based on version 1 would overwrite the first patch
+	_, err = service.PatchProduct(context.Background(), product.ID, 1, patch.MergePatchType, []byte(`{"description": "Second"}`))
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+
+	stored, _ := service.GetProduct(context.Background(), product.ID)
+	assert.Equal(t, "First", stored.BaseTitle)
+	assert.Equal(t, int64(2), stored.Version)
+}
+
+func TestPatchProductRevalidates(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	_, err := service.PatchProduct(context.Background(), product.ID, product.Version, patch.MergePatchType, []byte(`{"base_title": null}`))
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.PatchProduct(context.Background(), product.ID, product.Version, patch.MergePatchType, []byte(`{"prices": "free"}`))
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.PatchProduct(context.Background(), product.ID, product.Version, patch.JSONPatchType, []byte(`{"op": "remove"}`))
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.PatchProduct(context.Background(), product.ID, product.Version, "text/plain", []byte(`{}`))
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.PatchProduct(context.Background(), "missing", 1, patch.MergePatchType, []byte(`{}`))
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+
+	stored, _ := service.GetProduct(context.Background(), product.ID)
+	assert.Equal(t, int64(1), stored.Version)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,6 +17,9 @@ import (
 	"github.com/jimmitjoo/ecom/src/domain/models"
 	"github.com/jimmitjoo/ecom/src/domain/repositories"
 	"github.com/jimmitjoo/ecom/src/infrastructure/locks"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+	"github.com/jimmitjoo/ecom/src/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // productService implements the ProductService interface
@@ -22,27 +27,150 @@ type productService struct {
 	repo      repositories.ProductRepository
 	publisher events.EventPublisher
 	locks     locks.LockManager
+	jobs      repositories.JobRepository
 	sequence  atomic.Int64
 }
 
 // NewProductService creates a new product service instance
-func NewProductService(repo repositories.ProductRepository, publisher events.EventPublisher, lockManager locks.LockManager) interfaces.ProductService {
-	return &productService{
+func NewProductService(repo repositories.ProductRepository, publisher events.EventPublisher, lockManager locks.LockManager, jobs repositories.JobRepository) interfaces.ProductService {
+	s := &productService{
 		repo:      repo,
 		publisher: publisher,
 		locks:     lockManager,
+		jobs:      jobs,
 	}
+
+	// Continue numbering after the stored history so consumer offsets stay
+	// comparable across restarts
+	if stored, err := repo.GetEventsUntil(context.Background(), time.Now()); err == nil {
+		for _, event := range stored {
+			if event.Sequence > s.sequence.Load() {
+				s.sequence.Store(event.Sequence)
+			}
+		}
+	}
+	return s
+}
+
+// ListProducts retrieves the products that match the filter from the repository
+func (s *productService) ListProducts(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	filter = filter.Normalize()
+	if err := filter.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.List(ctx, filter, page, pageSize)
 }
 
-// ListProducts retrieves all products from the repository
-func (s *productService) ListProducts(page, pageSize int) ([]*models.Product, int, error) {
-	return s.repo.List(page, pageSize)
+// ListProductsAfter retrieves a page of matching products after a cursor from the repository
+func (s *productService) ListProductsAfter(ctx context.Context, filter models.ProductFilter, cursor string, limit int) ([]*models.Product, string, error) {
+	filter = filter.Normalize()
+	if err := filter.Validate(); err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		return nil, "", fmt.Errorf("%w: limit must be positive", models.ErrInvalidRequest)
+	}
+	var after *models.ProductCursor
+	if cursor != "" {
+		decoded, err := models.DecodeProductCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = decoded
+	}
+
+	products, next, err := s.repo.ListAfter(ctx, filter, after, limit)
+	if err != nil || next == nil {
+		return products, "", err
+	}
+	return products, next.Encode(), nil
+}
+
+// ListProductsAsOf returns the catalog as it existed at the given time.
+// Every stored event carries the full product state, so the latest event per
+// product at or before asOf acts as its snapshot and later events are ignored.
+func (s *productService) ListProductsAsOf(ctx context.Context, asOf time.Time, page, pageSize int) ([]*models.Product, int, error) {
+	events, err := s.repo.GetEventsUntil(ctx, asOf)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load events: %v", err)
+	}
+
+	latest := make(map[string]*models.Event)
+	for _, event := range events {
+		if current, exists := latest[event.EntityID]; !exists || event.Version >= current.Version {
+			latest[event.EntityID] = event
+		}
+	}
+
+	products := make([]*models.Product, 0, len(latest))
+	for _, event := range latest {
+		if event.Type == models.EventProductDeleted {
+			continue
+		}
+		productEvent, ok := event.Data.(*models.ProductEvent)
+		if !ok || productEvent.Product == nil {
+			continue
+		}
+		products = append(products, productEvent.Product)
+	}
+
+	// Use the same ordering as the live listing (newest first)
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].CreatedAt.After(products[j].CreatedAt)
+	})
+
+	total := len(products)
+	start := (page - 1) * pageSize
+	if start >= total {
+		return []*models.Product{}, total, nil
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+
+	return products[start:end], total, nil
 }
 
 // CreateProduct creates a new product and publishes a creation event
-func (s *productService) CreateProduct(product *models.Product) error {
-	// Generate unique ID and set timestamps
+func (s *productService) CreateProduct(ctx context.Context, product *models.Product) error {
+	if err := s.publish(s.createProduct(ctx, product, "")); err != nil {
+		return err
+	}
+	recordQualityWarnings(product)
+	return nil
+}
+
+// createProduct creates a new product and returns its unpublished event,
+// tagged with the job that caused it
+func (s *productService) createProduct(ctx context.Context, product *models.Product, jobID string) (*models.Event, error) {
+	// Generate unique ID
 	product.ID = "prod_" + uuid.New().String()
+	return s.insertProduct(ctx, product, jobID)
+}
+
+// insertProduct stores a new product under the ID it already has and returns
+// its unpublished event
+func (s *productService) insertProduct(ctx context.Context, product *models.Product, jobID string) (*models.Event, error) {
+	if err := product.ValidatePriceOverrides(); err != nil {
+		return nil, err
+	}
+	if err := product.Compliance.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkBundle(ctx, product); err != nil {
+		return nil, err
+	}
+	product.Tags = models.NormalizeTags(product.Tags)
+	product.CategoryIDs = models.NormalizeCategoryIDs(product.CategoryIDs)
+	assignImageIDs(product.Images)
+	release, err := s.reserveSKU(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Set timestamps
 	product.CreatedAt = time.Now()
 	product.UpdatedAt = time.Now()
 
@@ -52,11 +180,12 @@ func (s *productService) CreateProduct(product *models.Product) error {
 
 	// Create event first
 	event := &models.Event{
-		ID:       uuid.New().String(),
-		Type:     models.EventProductCreated,
-		EntityID: product.ID,
-		Version:  product.Version,
-		Sequence: s.getNextSequence(),
+		ID:            uuid.New().String(),
+		Type:          models.EventProductCreated,
+		EntityID:      product.ID,
+		Version:       product.Version,
+		Sequence:      s.getNextSequence(),
+		SchemaVersion: models.EventSchemaVersion,
 		Data: &models.ProductEvent{
 			ProductID: product.ID,
 			Action:    "created",
@@ -64,119 +193,263 @@ func (s *productService) CreateProduct(product *models.Product) error {
 			Version:   product.Version,
 			PrevHash:  "", // No previous version for new products
 		},
+		JobID:     jobID,
 		Timestamp: time.Now(),
 	}
 
-	// Store event first
-	if err := s.repo.StoreEvent(event); err != nil {
-		return err
+	// Store the event and the product together
+	err = s.repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, event); err != nil {
+			return err
+		}
+		return tx.Create(ctx, product)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Then create the product
-	if err := s.repo.Create(product); err != nil {
-		return err
+	return event, nil
+}
+
+// GetProduct retrieves a specific product by ID
+func (s *productService) GetProduct(ctx context.Context, id string) (*models.Product, error) {
+	return s.activeProduct(ctx, id)
+}
+
+// GetProductBySKU retrieves the product with a SKU
+func (s *productService) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	product, err := s.repo.GetBySKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsDeleted() {
+		return nil, models.ErrProductNotFound
 	}
+	return product, nil
+}
 
-	// Finally publish the event
-	return s.publisher.Publish(event)
+// reserveSKU locks the product's SKU for the rest of a write and fails with
+// ErrDuplicateSKU when another product has it. The repository enforces
+// uniqueness as well; checking under the lock before the event is stored keeps
+// two writes of one SKU from leaving an event for a product that was never
+// saved. The returned function releases the lock.
+func (s *productService) reserveSKU(ctx context.Context, product *models.Product) (func(), error) {
+	if product.SKU == "" {
+		return func() {}, nil
+	}
+	resource := "sku:" + product.SKU
+	acquired, err := s.acquireLock(ctx, resource)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrLockFailed, err)
+	}
+	if !acquired {
+		return nil, fmt.Errorf("%w: sku %s is being written", models.ErrLockFailed, product.SKU)
+	}
+	release := func() { s.locks.ReleaseLock(resource) }
+
+	existing, err := s.repo.GetBySKU(ctx, product.SKU)
+	if errors.Is(err, models.ErrProductNotFound) {
+		return release, nil
+	}
+	if err == nil && existing.ID != product.ID {
+		err = fmt.Errorf("%w: %s is used by %s", models.ErrDuplicateSKU, product.SKU, existing.ID)
+	}
+	if err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
 }
 
-// GetProduct retrieves a specific product by ID
-func (s *productService) GetProduct(id string) (*models.Product, error) {
-	return s.repo.GetByID(id)
+// CompareProducts builds an attribute-aligned comparison of the given products.
+// Duplicate IDs are ignored and the order of the first occurrence is kept.
+func (s *productService) CompareProducts(ctx context.Context, ids []string) (*models.ProductComparison, error) {
+	seen := make(map[string]bool, len(ids))
+	products := make([]*models.Product, 0, len(ids))
+
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		product, err := s.activeProduct(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, id)
+		}
+		products = append(products, product)
+	}
+
+	return models.CompareProducts(products), nil
 }
 
 // UpdateProduct updates an existing product and publishes an update event
-func (s *productService) UpdateProduct(product *models.Product) error {
+func (s *productService) UpdateProduct(ctx context.Context, product *models.Product) error {
+	if err := s.publish(s.updateProduct(ctx, product, "updated", "")); err != nil {
+		return err
+	}
+	recordQualityWarnings(product)
+	return nil
+}
+
+// RollbackProduct restores the state a product had at an earlier version. The
+// historical state is applied as a new version so the event chain stays intact.
+func (s *productService) RollbackProduct(ctx context.Context, id string, toVersion int64) (*models.Product, error) {
+	current, err := s.activeProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if toVersion < 1 || toVersion >= current.Version {
+		return nil, fmt.Errorf("%w: must be between 1 and %d", models.ErrInvalidRollbackVersion, current.Version-1)
+	}
+
+	// Replaying verifies the chain from the target version onwards
+	if _, err := s.ReplayEvents(ctx, id, toVersion); err != nil {
+		return nil, fmt.Errorf("failed to replay events: %w", err)
+	}
+
+	historical, err := s.productAtVersion(ctx, id, toVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	restored := historical.Clone()
+	restored.ID = current.ID
+	restored.Version = current.Version
+	restored.CreatedAt = current.CreatedAt
+
+	if err := s.publish(s.updateProduct(ctx, restored, "rolled_back", "")); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// updateProduct stores a new version of the product and returns its unpublished
+// update event with the given action, tagged with the job that caused it
+func (s *productService) updateProduct(ctx context.Context, product *models.Product, action, jobID string) (*models.Event, error) {
 	if product == nil {
-		return errors.New("product cannot be nil")
+		return nil, fmt.Errorf("%w: product cannot be nil", models.ErrInvalidRequest)
 	}
 
 	if product.ID == "" {
-		return errors.New("product ID cannot be empty")
+		return nil, fmt.Errorf("%w: product ID cannot be empty", models.ErrInvalidRequest)
+	}
+	if err := product.ValidatePriceOverrides(); err != nil {
+		return nil, err
+	}
+	if err := product.Compliance.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkBundle(ctx, product); err != nil {
+		return nil, err
 	}
-
-	ctx := context.Background()
 
 	// Try to lock the product
-	acquired, err := s.locks.AcquireLock(ctx, product.ID, 10*time.Second)
+	acquired, err := s.acquireLock(ctx, product.ID)
 	if err != nil {
-		return fmt.Errorf("failed to acquire lock: %v", err)
+		return nil, fmt.Errorf("%w: %v", models.ErrLockFailed, err)
 	}
 	if !acquired {
-		return errors.New("could not acquire lock for update")
+		return nil, fmt.Errorf("%w: product %s is being written", models.ErrLockFailed, product.ID)
 	}
 	defer s.locks.ReleaseLock(product.ID)
 
 	// Get current version
-	current, err := s.repo.GetByID(product.ID)
+	current, err := s.repo.GetByID(ctx, product.ID)
 	if err != nil {
-		return fmt.Errorf("failed to get current product: %v", err)
+		return nil, fmt.Errorf("failed to get current product: %w", err)
 	}
 
 	if current == nil {
-		return errors.New("product not found")
+		return nil, models.ErrProductNotFound
+	}
+	if current.IsDeleted() {
+		return nil, models.ErrProductNotFound
 	}
 
 	if product.Version != current.Version {
-		return fmt.Errorf("version conflict: expected %d, got %d", current.Version, product.Version)
+		return nil, fmt.Errorf("%w: expected %d, got %d", models.ErrVersionConflict, current.Version, product.Version)
 	}
 
 	// Create a copy of the product
 	updatedProduct := product.Clone()
+	updatedProduct.Tags = models.NormalizeTags(updatedProduct.Tags)
+	updatedProduct.CategoryIDs = models.NormalizeCategoryIDs(updatedProduct.CategoryIDs)
+	assignImageIDs(updatedProduct.Images)
 	updatedProduct.Version++
 	updatedProduct.UpdatedAt = time.Now()
 	updatedProduct.LastHash = updatedProduct.CalculateHash()
+	if updatedProduct.SKU != current.SKU {
+		release, err := s.reserveSKU(ctx, updatedProduct)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
 
 	// Create event
 	event := &models.Event{
-		ID:       uuid.New().String(),
-		Type:     models.EventProductUpdated,
-		EntityID: updatedProduct.ID,
-		Version:  updatedProduct.Version,
-		Sequence: s.getNextSequence(),
+		ID:            uuid.New().String(),
+		Type:          models.EventProductUpdated,
+		EntityID:      updatedProduct.ID,
+		Version:       updatedProduct.Version,
+		Sequence:      s.getNextSequence(),
+		SchemaVersion: models.EventSchemaVersion,
 		Data: &models.ProductEvent{
 			ProductID: updatedProduct.ID,
-			Action:    "updated",
+			Action:    action,
 			Product:   updatedProduct.Clone(),
 			Version:   updatedProduct.Version,
 			PrevHash:  current.LastHash,
 			Changes:   calculateChanges(current, updatedProduct),
 		},
+		JobID:     jobID,
 		Timestamp: time.Now(),
 	}
 
-	// Store event first
-	if err := s.repo.StoreEvent(event); err != nil {
-		return fmt.Errorf("failed to store event: %v", err)
-	}
-
-	// Update the product
-	if err := s.repo.Update(updatedProduct); err != nil {
-		return fmt.Errorf("failed to update product: %v", err)
+	// Store the event and the updated product together
+	err = s.repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, event); err != nil {
+			return fmt.Errorf("failed to store event: %w", err)
+		}
+		if err := tx.Update(ctx, updatedProduct); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Copy back the values
 	*product = *updatedProduct
 
-	return s.publisher.Publish(event)
+	return event, nil
 }
 
 // DeleteProduct removes a product and publishes a deletion event
-func (s *productService) DeleteProduct(id string) error {
+func (s *productService) DeleteProduct(ctx context.Context, id string) error {
+	return s.publish(s.deleteProduct(ctx, id, ""))
+}
+
+// deleteProduct removes a product and returns its unpublished event,
+// tagged with the job that caused it
+func (s *productService) deleteProduct(ctx context.Context, id, jobID string) (*models.Event, error) {
 	// Get product before deletion for event data
-	product, err := s.repo.GetByID(id)
+	product, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Create deletion event
 	event := &models.Event{
-		ID:       uuid.New().String(),
-		Type:     models.EventProductDeleted,
-		EntityID: id,
-		Version:  product.Version + 1,
-		Sequence: s.getNextSequence(),
+		ID:            uuid.New().String(),
+		Type:          models.EventProductDeleted,
+		EntityID:      id,
+		Version:       product.Version + 1,
+		Sequence:      s.getNextSequence(),
+		SchemaVersion: models.EventSchemaVersion,
 		Data: &models.ProductEvent{
 			ProductID: id,
 			Action:    "deleted",
@@ -184,26 +457,33 @@ func (s *productService) DeleteProduct(id string) error {
 			Version:   product.Version + 1,
 			PrevHash:  product.LastHash, // Use current hash as prev hash
 		},
+		JobID:     jobID,
 		Timestamp: time.Now(),
 	}
 
-	// Store event first
-	if err := s.repo.StoreEvent(event); err != nil {
-		return err
-	}
-
-	// Then delete the product
-	if err := s.repo.Delete(id); err != nil {
-		return err
+	// Store the event and delete the product together
+	err = s.repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, event); err != nil {
+			return err
+		}
+		return tx.Delete(ctx, id)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Finally publish the event
-	return s.publisher.Publish(event)
+	return event, nil
 }
 
 // BatchCreateProducts creates multiple products in parallel
-func (s *productService) BatchCreateProducts(products []*models.Product) ([]*interfaces.BatchResult, error) {
+func (s *productService) BatchCreateProducts(ctx context.Context, products []*models.Product) ([]*interfaces.BatchResult, error) {
+	job, err := s.startJob(models.JobBatchCreate, len(products))
+	if err != nil {
+		return nil, err
+	}
+
 	results := make([]*interfaces.BatchResult, len(products))
+	events := make([]*models.Event, len(products))
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
@@ -212,8 +492,13 @@ func (s *productService) BatchCreateProducts(products []*models.Product) ([]*int
 		go func(index int, p *models.Product) {
 			defer wg.Done()
 
-			// Create the product first
-			err := s.CreateProduct(p)
+			// Products not written yet when the request is canceled fail
+			// with its error
+			var event *models.Event
+			err := ctx.Err()
+			if err == nil {
+				event, err = s.createProduct(ctx, p, job.ID)
+			}
 
 			mu.Lock()
 			results[index] = &interfaces.BatchResult{
@@ -222,18 +507,28 @@ func (s *productService) BatchCreateProducts(products []*models.Product) ([]*int
 			}
 			if err != nil {
 				results[index].Error = err.Error()
+			} else {
+				results[index].Warnings = recordQualityWarnings(p)
 			}
+			events[index] = event
 			mu.Unlock()
 		}(i, product)
 	}
 
 	wg.Wait()
-	return results, nil
+	s.publishBatch(events, results)
+	return results, s.finishJob(job, results)
 }
 
 // BatchUpdateProducts updates multiple products in parallel
-func (s *productService) BatchUpdateProducts(products []*models.Product) ([]*interfaces.BatchResult, error) {
+func (s *productService) BatchUpdateProducts(ctx context.Context, products []*models.Product) ([]*interfaces.BatchResult, error) {
+	job, err := s.startJob(models.JobBatchUpdate, len(products))
+	if err != nil {
+		return nil, err
+	}
+
 	results := make([]*interfaces.BatchResult, len(products))
+	events := make([]*models.Event, len(products))
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
@@ -242,7 +537,11 @@ func (s *productService) BatchUpdateProducts(products []*models.Product) ([]*int
 		go func(index int, p *models.Product) {
 			defer wg.Done()
 
-			err := s.UpdateProduct(p)
+			var event *models.Event
+			err := ctx.Err()
+			if err == nil {
+				event, err = s.updateProduct(ctx, p, "updated", job.ID)
+			}
 
 			mu.Lock()
 			results[index] = &interfaces.BatchResult{
@@ -251,18 +550,28 @@ func (s *productService) BatchUpdateProducts(products []*models.Product) ([]*int
 			}
 			if err != nil {
 				results[index].Error = err.Error()
+			} else {
+				results[index].Warnings = recordQualityWarnings(p)
 			}
+			events[index] = event
 			mu.Unlock()
 		}(i, product)
 	}
 
 	wg.Wait()
-	return results, nil
+	s.publishBatch(events, results)
+	return results, s.finishJob(job, results)
 }
 
 // BatchDeleteProducts deletes multiple products in parallel
-func (s *productService) BatchDeleteProducts(ids []string) ([]*interfaces.BatchResult, error) {
+func (s *productService) BatchDeleteProducts(ctx context.Context, ids []string) ([]*interfaces.BatchResult, error) {
+	job, err := s.startJob(models.JobBatchDelete, len(ids))
+	if err != nil {
+		return nil, err
+	}
+
 	results := make([]*interfaces.BatchResult, len(ids))
+	events := make([]*models.Event, len(ids))
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
@@ -271,9 +580,16 @@ func (s *productService) BatchDeleteProducts(ids []string) ([]*interfaces.BatchR
 		go func(index int, productID string) {
 			defer wg.Done()
 			result := &interfaces.BatchResult{ID: productID}
+			if err := ctx.Err(); err != nil {
+				result.Error = err.Error()
+				mu.Lock()
+				results[index] = result
+				mu.Unlock()
+				return
+			}
 
 			// Get product before deletion for event data
-			product, err := s.repo.GetByID(productID)
+			product, err := s.repo.GetByID(ctx, productID)
 			if err != nil {
 				result.Success = false
 				result.Error = "Failed to find product"
@@ -283,33 +599,147 @@ func (s *productService) BatchDeleteProducts(ids []string) ([]*interfaces.BatchR
 				return
 			}
 
-			if err := s.repo.Delete(productID); err != nil {
-				result.Success = false
-				result.Error = "Failed to delete product"
-			} else {
-				result.Success = true
-				// Publish event for each successfully deleted product
-				event := &models.Event{
-					ID:   uuid.New().String(),
-					Type: models.EventProductDeleted,
-					Data: &models.ProductEvent{
-						ProductID: productID,
-						Action:    "deleted",
-						Product:   product, // Include product data in event
-					},
-					Timestamp: time.Now(),
+			event := &models.Event{
+				ID:            uuid.New().String(),
+				Type:          models.EventProductDeleted,
+				EntityID:      productID,
+				Version:       product.Version + 1,
+				Sequence:      s.getNextSequence(),
+				SchemaVersion: models.EventSchemaVersion,
+				Data: &models.ProductEvent{
+					ProductID: productID,
+					Action:    "deleted",
+					Product:   product, // Include product data in event
+					Version:   product.Version + 1,
+					PrevHash:  product.LastHash,
+				},
+				JobID:     job.ID,
+				Timestamp: time.Now(),
+			}
+
+			// Store the event with the deletion so historical listings see it
+			err = s.repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+				if err := tx.StoreEvent(ctx, event); err != nil {
+					result.Error = "Failed to store delete event"
+					return err
 				}
-				s.publisher.Publish(event)
+				return tx.Delete(ctx, productID)
+			})
+			result.Success = err == nil
+			if err != nil && result.Error == "" {
+				result.Error = "Failed to delete product"
 			}
 
 			mu.Lock()
 			results[index] = result
+			if result.Success {
+				// Publish event for each successfully deleted product
+				events[index] = event
+			}
 			mu.Unlock()
 		}(i, id)
 	}
 
 	wg.Wait()
-	return results, nil
+	s.publishBatch(events, results)
+	return results, s.finishJob(job, results)
+}
+
+// startJob registers a running job for a batch operation
+func (s *productService) startJob(jobType models.JobType, total int) (*models.Job, error) {
+	job := &models.Job{
+		ID:        "job_" + uuid.New().String(),
+		Type:      jobType,
+		Status:    models.JobStatusRunning,
+		Total:     total,
+		CreatedAt: time.Now(),
+	}
+	if err := s.jobs.Create(job); err != nil {
+		return nil, fmt.Errorf("failed to create job: %v", err)
+	}
+	return job, nil
+}
+
+// finishJob records the outcome of a batch operation on its job
+func (s *productService) finishJob(job *models.Job, results []*interfaces.BatchResult) error {
+	succeeded := 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+		}
+	}
+	job.Complete(succeeded, len(results)-succeeded)
+
+	if err := s.jobs.Update(job); err != nil {
+		return fmt.Errorf("failed to update job: %v", err)
+	}
+	return nil
+}
+
+// publish publishes the event returned by a write helper unless the write failed
+func (s *productService) publish(event *models.Event, err error) error {
+	if err != nil {
+		return err
+	}
+	defer observePublish(time.Now(), event)
+	_, span := tracing.Start(context.Background(), "event.publish",
+		attribute.String("event.id", event.ID),
+		attribute.String("event.type", string(event.Type)),
+		attribute.String("product.id", event.EntityID),
+		attribute.Int64("product.version", event.Version),
+	)
+	err = s.publisher.Publish(event)
+	tracing.End(span, err)
+	return err
+}
+
+// acquireLock tries to take the write lock of a resource and records the
+// attempt in a lock.acquire span
+func (s *productService) acquireLock(ctx context.Context, resource string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "lock.acquire", attribute.String("lock.resource", resource))
+	acquired, err := s.locks.AcquireLock(ctx, resource, 10*time.Second)
+	span.SetAttributes(attribute.Bool("lock.acquired", acquired))
+	tracing.End(span, err)
+	return acquired, err
+}
+
+// observePublish records how long handing events to the publisher took in
+// event_processing_duration_seconds, per event type. A batch is timed once,
+// with each event recorded at the duration of the whole batch.
+func observePublish(start time.Time, events ...*models.Event) {
+	duration := time.Since(start).Seconds()
+	for _, event := range events {
+		metrics.EventProcessingDuration.WithLabelValues(string(event.Type)).Observe(duration)
+	}
+}
+
+// publishBatch publishes the events of a batch in one call. events is aligned
+// with results and nil for failed items; publish failures are recorded on the
+// matching result.
+func (s *productService) publishBatch(events []*models.Event, results []*interfaces.BatchResult) {
+	pending := make([]*models.Event, 0, len(events))
+	indexes := make([]int, 0, len(events))
+	for i, event := range events {
+		if event != nil {
+			pending = append(pending, event)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	start := time.Now()
+	_, span := tracing.Start(context.Background(), "event.publish_batch", attribute.Int("event.count", len(pending)))
+	errs := s.publisher.PublishBatch(pending)
+	span.End()
+	observePublish(start, pending...)
+	for i, err := range errs {
+		if err != nil {
+			results[indexes[i]].Success = false
+			results[indexes[i]].Error = fmt.Sprintf("failed to publish event: %v", err)
+		}
+	}
 }
 
 // Helper function for publishing events
@@ -320,8 +750,9 @@ func (s *productService) publishEvent(eventType models.EventType, action string,
 	}
 
 	event := &models.Event{
-		ID:   uuid.New().String(),
-		Type: eventType,
+		ID:            uuid.New().String(),
+		Type:          eventType,
+		SchemaVersion: models.EventSchemaVersion,
 		Data: &models.ProductEvent{
 			ProductID: productID,
 			Action:    action,
@@ -332,27 +763,45 @@ func (s *productService) publishEvent(eventType models.EventType, action string,
 	s.publisher.Publish(event)
 }
 
-func (s *productService) ReplayEvents(productID string, fromVersion int64) ([]*models.Event, error) {
-	events, err := s.repo.GetEventsByProductID(productID, fromVersion)
+// ReplayEvents returns a product's events from a version on, oldest first,
+// after verifying that they form an unbroken hash chain
+func (s *productService) ReplayEvents(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
+	events, err := s.repo.GetEventsByProductID(ctx, productID, fromVersion)
 	if err != nil {
 		return nil, err
 	}
 
 	if len(events) == 0 {
-		return events, nil
+		// Products loaded from a catalog snapshot exist without events
+		if _, err := s.repo.GetByID(ctx, productID); err != nil {
+			return nil, err
+		}
+		return []*models.Event{}, nil
 	}
 
-	// Sort events by version
+	sortEventsByVersion(events)
+	if err := verifyEventChain(events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// sortEventsByVersion sorts a product's events oldest first
+func sortEventsByVersion(events []*models.Event) {
 	sort.Slice(events, func(i, j int) bool {
 		return events[i].Version < events[j].Version
 	})
+}
 
-	// Verify event chain
+// verifyEventChain checks that events sorted by version follow each other
+// without gaps and that each event's prev hash is the hash of the one before.
+// Failures wrap models.ErrBrokenEventChain.
+func verifyEventChain(events []*models.Event) error {
 	for i := 0; i < len(events); i++ {
 		curr := events[i]
 		currEvent, ok := curr.Data.(*models.ProductEvent)
 		if !ok {
-			return nil, errors.New("invalid event data")
+			return fmt.Errorf("%w: invalid event data", models.ErrBrokenEventChain)
 		}
 
 		if i == 0 {
@@ -360,12 +809,12 @@ func (s *productService) ReplayEvents(productID string, fromVersion int64) ([]*m
 			if curr.Type == models.EventProductCreated {
 				// Create-event should not have a PrevHash
 				if currEvent.PrevHash != "" {
-					return nil, errors.New("create event should not have prev hash")
+					return fmt.Errorf("%w: create event should not have prev hash", models.ErrBrokenEventChain)
 				}
 			} else {
 				// If the first event is not create, verify that it has a PrevHash
 				if currEvent.PrevHash == "" {
-					return nil, errors.New("non-create event must have prev hash")
+					return fmt.Errorf("%w: non-create event must have prev hash", models.ErrBrokenEventChain)
 				}
 			}
 			continue
@@ -375,23 +824,22 @@ func (s *productService) ReplayEvents(productID string, fromVersion int64) ([]*m
 		prev := events[i-1]
 		prevEvent, ok := prev.Data.(*models.ProductEvent)
 		if !ok {
-			return nil, errors.New("invalid event data")
+			return fmt.Errorf("%w: invalid event data", models.ErrBrokenEventChain)
 		}
 
 		// Check versions
 		if curr.Version != prev.Version+1 {
-			return nil, fmt.Errorf("event chain broken: curr version %d, prev version %d",
-				curr.Version, prev.Version)
+			return fmt.Errorf("%w: curr version %d, prev version %d",
+				models.ErrBrokenEventChain, curr.Version, prev.Version)
 		}
 
 		// Verify hash chain
 		if currEvent.PrevHash != prevEvent.Product.LastHash {
-			return nil, fmt.Errorf("event chain integrity violated: expected hash %s, got %s",
-				prevEvent.Product.LastHash, currEvent.PrevHash)
+			return fmt.Errorf("%w: integrity violated: expected hash %s, got %s",
+				models.ErrBrokenEventChain, prevEvent.Product.LastHash, currEvent.PrevHash)
 		}
 	}
-
-	return events, nil
+	return nil
 }
 
 func calculateChanges(old, new *models.Product) []models.Change {
@@ -405,11 +853,42 @@ func calculateChanges(old, new *models.Product) []models.Change {
 			NewValue: new.BaseTitle,
 		})
 	}
+	if strings.Join(old.Tags, ",") != strings.Join(new.Tags, ",") {
+		changes = append(changes, models.Change{
+			Field:    "tags",
+			OldValue: old.Tags,
+			NewValue: new.Tags,
+		})
+	}
+	if strings.Join(old.CategoryIDs, ",") != strings.Join(new.CategoryIDs, ",") {
+		changes = append(changes, models.Change{
+			Field:    "category_ids",
+			OldValue: old.CategoryIDs,
+			NewValue: new.CategoryIDs,
+		})
+	}
+	if !reflect.DeepEqual(old.Bundle, new.Bundle) {
+		changes = append(changes, models.Change{
+			Field:    "bundle",
+			OldValue: old.Bundle,
+			NewValue: new.Bundle,
+		})
+	}
 	// Add more field comparisons...
 
 	return changes
 }
 
+// recordQualityWarnings checks a written product for soft validation issues and
+// counts them. The write has already succeeded; the warnings are only reported.
+func recordQualityWarnings(product *models.Product) []models.ValidationWarning {
+	warnings := models.CheckQuality(product)
+	for _, warning := range warnings {
+		metrics.ValidationWarnings.WithLabelValues(string(warning.Code)).Inc()
+	}
+	return warnings
+}
+
 func (s *productService) getNextSequence() int64 {
 	return s.sequence.Add(1)
 }
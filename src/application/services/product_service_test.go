@@ -2,13 +2,24 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
 	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/locks"
 	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
 )
 
@@ -22,6 +33,14 @@ func (m *MockEventPublisher) Publish(event *models.Event) error {
 	return args.Error(0)
 }
 
+func (m *MockEventPublisher) PublishBatch(events []*models.Event) []error {
+	args := m.Called(events)
+	if errs, ok := args.Get(0).([]error); ok {
+		return errs
+	}
+	return make([]error, len(events))
+}
+
 func (m *MockEventPublisher) Subscribe(eventType models.EventType, handler func(*models.Event)) error {
 	args := m.Called(eventType, handler)
 	return args.Error(0)
@@ -52,10 +71,13 @@ func (m *MockLockManager) RefreshLock(key string, ttl time.Duration) error {
 	return args.Error(0)
 }
 
+// testSKUs numbers the SKUs of test products, which must be unique
+var testSKUs atomic.Int64
+
 func createValidProduct() *models.Product {
 	return &models.Product{
 		BaseTitle: "Test Produkt",
-		SKU:       "TEST-123",
+		SKU:       fmt.Sprintf("TEST-%d", testSKUs.Add(1)),
 		Prices: []models.Price{
 			{
 				Amount:   100,
@@ -72,12 +94,20 @@ func createValidProduct() *models.Product {
 	}
 }
 
+// allowSKULocks lets writes lock SKUs in tests that count product locks
+func allowSKULocks(lockManager *MockLockManager) {
+	isSKU := mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "sku:") })
+	lockManager.On("AcquireLock", mock.Anything, isSKU, mock.AnythingOfType("time.Duration")).Return(true, nil).Maybe()
+	lockManager.On("ReleaseLock", isSKU).Return(nil).Maybe()
+}
+
 func setupProductService() (*productService, *MockEventPublisher, *MockLockManager) {
 	repo := memory.NewProductRepository()
 	publisher := new(MockEventPublisher)
 	lockManager := new(MockLockManager)
 
 	publisher.On("Publish", mock.AnythingOfType("*models.Event")).Return(nil).Maybe()
+	publisher.On("PublishBatch", mock.AnythingOfType("[]*models.Event")).Return(nil).Maybe()
 	lockManager.On("AcquireLock", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(true, nil).Maybe()
 	lockManager.On("ReleaseLock", mock.AnythingOfType("string")).Return(nil).Maybe()
 
@@ -85,6 +115,7 @@ func setupProductService() (*productService, *MockEventPublisher, *MockLockManag
 		repo:      repo,
 		publisher: publisher,
 		locks:     lockManager,
+		jobs:      memory.NewJobRepository(),
 	}, publisher, lockManager
 }
 
@@ -92,7 +123,7 @@ func TestCreateProduct(t *testing.T) {
 	service, publisher, _ := setupProductService()
 
 	product := createValidProduct()
-	err := service.CreateProduct(product)
+	err := service.CreateProduct(context.Background(), product)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, product.ID)
@@ -103,14 +134,46 @@ func TestCreateProduct(t *testing.T) {
 	publisher.AssertExpectations(t)
 }
 
+func TestCreateAndUpdateProductValidatePriceOverrides(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	product := createValidProduct()
+	product.Variants = []models.Variant{{ID: "v1", SKU: "TEST-123-XL", Prices: []models.Price{{Currency: "NOK", Amount: 150}}}}
+	assert.ErrorIs(t, service.CreateProduct(context.Background(), product), models.ErrInvalidRequest)
+
+	product.Variants[0].Prices = []models.Price{{Currency: "SEK", Amount: 150}}
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	update := product.Clone()
+	update.Variants[0].Prices = append(update.Variants[0].Prices, models.Price{Currency: "SEK", Amount: 160})
+	assert.ErrorIs(t, service.UpdateProduct(context.Background(), update), models.ErrInvalidRequest)
+
+	stored, err := service.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), stored.Version)
+	assert.Equal(t, []models.Price{{Currency: "SEK", Amount: 150}}, stored.Variants[0].Prices)
+}
+
+func TestCreateProductValidatesCompliance(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	product := createValidProduct()
+	product.Compliance = &models.Compliance{HazardClass: "flammable"}
+	assert.ErrorIs(t, service.CreateProduct(context.Background(), product), models.ErrInvalidRequest)
+
+	// Incomplete data is accepted; it only blocks the markets that require it
+	product.Compliance = &models.Compliance{HazardClass: "3", MinimumAge: 18}
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+}
+
 func TestGetProduct(t *testing.T) {
 	service, publisher, _ := setupProductService()
 
 	product := createValidProduct()
-	err := service.CreateProduct(product)
+	err := service.CreateProduct(context.Background(), product)
 	assert.NoError(t, err)
 
-	retrieved, err := service.GetProduct(product.ID)
+	retrieved, err := service.GetProduct(context.Background(), product.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, product.ID, retrieved.ID)
 	assert.Equal(t, product.BaseTitle, retrieved.BaseTitle)
@@ -124,19 +187,20 @@ func TestUpdateProduct(t *testing.T) {
 
 	// Återställ standard mock-förväntningar
 	lockManager.ExpectedCalls = nil
+	allowSKULocks(lockManager)
 	lockManager.On("AcquireLock", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(true, nil).Once()
 	lockManager.On("ReleaseLock", mock.AnythingOfType("string")).Return(nil).Once()
 
 	product := createValidProduct()
-	err := service.CreateProduct(product)
+	err := service.CreateProduct(context.Background(), product)
 	assert.NoError(t, err)
 
 	product.BaseTitle = "Uppdaterad Produkt"
-	err = service.UpdateProduct(product)
+	err = service.UpdateProduct(context.Background(), product)
 	assert.NoError(t, err)
 	assert.Equal(t, int64(2), product.Version)
 
-	updated, err := service.GetProduct(product.ID)
+	updated, err := service.GetProduct(context.Background(), product.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, "Uppdaterad Produkt", updated.BaseTitle)
 
@@ -144,17 +208,88 @@ func TestUpdateProduct(t *testing.T) {
 	lockManager.AssertExpectations(t)
 }
 
+// failingWrites is a repository whose product updates and deletes fail
+type failingWrites struct {
+	repositories.ProductRepository
+}
+
+func (r *failingWrites) Update(ctx context.Context, product *models.Product) error {
+	return errors.New("disk full")
+}
+
+func (r *failingWrites) Delete(ctx context.Context, id string) error {
+	return errors.New("disk full")
+}
+
+func (r *failingWrites) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	return r.ProductRepository.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		return fn(&failingWrites{ProductRepository: tx})
+	})
+}
+
+func TestFailedWritesStoreNoEvent(t *testing.T) {
+	service, _, _ := setupProductService()
+	service.repo = &failingWrites{ProductRepository: service.repo}
+
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	update := product.Clone()
+	update.BaseTitle = "Uppdaterad Produkt"
+	assert.Error(t, service.UpdateProduct(context.Background(), update))
+	assert.Error(t, service.DeleteProduct(context.Background(), product.ID))
+
+	events, err := service.repo.GetEventsByProductID(context.Background(), product.ID, 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1, "only the creation is recorded")
+	stored, err := service.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), stored.Version)
+}
+
+func TestCreateProductRejectsDuplicateSKU(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	first := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), first))
+	duplicate := createValidProduct()
+	duplicate.SKU = first.SKU
+	assert.ErrorIs(t, service.CreateProduct(context.Background(), duplicate), models.ErrDuplicateSKU)
+
+	// The rejected product left no event behind
+	events, err := service.repo.GetEventsUntil(context.Background(), time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestUpdateProductRejectsTakenSKU(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	first := createValidProduct()
+	second := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), first))
+	assert.NoError(t, service.CreateProduct(context.Background(), second))
+
+	second.SKU = first.SKU
+	assert.ErrorIs(t, service.UpdateProduct(context.Background(), second), models.ErrDuplicateSKU)
+
+	second.SKU = "RENAMED"
+	assert.NoError(t, service.UpdateProduct(context.Background(), second))
+	found, err := service.GetProductBySKU(context.Background(), "RENAMED")
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, found.ID)
+}
+
 func TestDeleteProduct(t *testing.T) {
 	service, publisher, _ := setupProductService()
 
 	product := createValidProduct()
-	err := service.CreateProduct(product)
+	err := service.CreateProduct(context.Background(), product)
 	assert.NoError(t, err)
 
-	err = service.DeleteProduct(product.ID)
+	err = service.DeleteProduct(context.Background(), product.ID)
 	assert.NoError(t, err)
 
-	_, err = service.GetProduct(product.ID)
+	_, err = service.GetProduct(context.Background(), product.ID)
 	assert.Error(t, err)
 
 	publisher.AssertExpectations(t)
@@ -170,11 +305,11 @@ func TestListProducts(t *testing.T) {
 
 	for i, p := range products {
 		p.SKU = p.SKU + "-" + string(rune('A'+i))
-		err := service.CreateProduct(p)
+		err := service.CreateProduct(context.Background(), p)
 		assert.NoError(t, err)
 	}
 
-	listed, total, err := service.ListProducts(1, 10)
+	listed, total, err := service.ListProducts(context.Background(), models.ProductFilter{}, 1, 10)
 	assert.NoError(t, err)
 	assert.Len(t, listed, 2)
 	assert.Equal(t, 2, total)
@@ -182,6 +317,51 @@ func TestListProducts(t *testing.T) {
 	publisher.AssertExpectations(t)
 }
 
+func TestListProductsFiltered(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	products := []*models.Product{createValidProduct(), createValidProduct()}
+	for i, p := range products {
+		p.SKU = p.SKU + "-" + string(rune('A'+i))
+		assert.NoError(t, service.CreateProduct(context.Background(), p))
+	}
+
+	listed, total, err := service.ListProducts(context.Background(), models.ProductFilter{SKU: products[1].SKU}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, products[1].ID, listed[0].ID)
+
+	minPrice, maxPrice := 200.0, 100.0
+	_, _, err = service.ListProducts(context.Background(), models.ProductFilter{MinPrice: &minPrice, MaxPrice: &maxPrice}, 1, 10)
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
+
+func TestListProductsAfter(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	for i := 0; i < 3; i++ {
+		p := createValidProduct()
+		p.SKU = p.SKU + "-" + string(rune('A'+i))
+		assert.NoError(t, service.CreateProduct(context.Background(), p))
+	}
+
+	first, cursor, err := service.ListProductsAfter(context.Background(), models.ProductFilter{}, "", 2)
+	assert.NoError(t, err)
+	assert.Len(t, first, 2)
+	assert.NotEmpty(t, cursor)
+
+	rest, cursor, err := service.ListProductsAfter(context.Background(), models.ProductFilter{}, cursor, 2)
+	assert.NoError(t, err)
+	assert.Len(t, rest, 1)
+	assert.Empty(t, cursor)
+	assert.NotContains(t, []string{first[0].ID, first[1].ID}, rest[0].ID)
+
+	_, _, err = service.ListProductsAfter(context.Background(), models.ProductFilter{}, "garbage", 2)
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, _, err = service.ListProductsAfter(context.Background(), models.ProductFilter{}, "", 0)
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
+
 func TestBatchCreateProducts(t *testing.T) {
 	service, publisher, _ := setupProductService()
 
@@ -196,7 +376,7 @@ func TestBatchCreateProducts(t *testing.T) {
 		p.SKU = p.SKU + "-" + string(rune('A'+i))
 	}
 
-	results, err := service.BatchCreateProducts(products)
+	results, err := service.BatchCreateProducts(context.Background(), products)
 	assert.NoError(t, err)
 	assert.Len(t, results, len(products))
 
@@ -205,7 +385,7 @@ func TestBatchCreateProducts(t *testing.T) {
 		assert.Empty(t, result.Error)
 
 		// Verifiera att produkten skapades i repository
-		stored, err := service.GetProduct(result.ID)
+		stored, err := service.GetProduct(context.Background(), result.ID)
 		if assert.NoError(t, err, "Should be able to fetch created product") {
 			assert.Equal(t, products[i].SKU, stored.SKU)
 			assert.NotEmpty(t, stored.ID)
@@ -217,11 +397,37 @@ func TestBatchCreateProducts(t *testing.T) {
 	publisher.AssertExpectations(t)
 }
 
+func TestBatchCreateProductsReturnsQualityWarnings(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	complete := createValidProduct()
+	complete.SKU = "TEST-COMPLETE"
+	complete.Description = "A sturdy everyday shirt in organic cotton with a relaxed fit."
+	complete.Metadata[0].Keywords = "shirt, cotton"
+	complete.Metadata[0].Description = ""
+
+	results, err := service.BatchCreateProducts(context.Background(), []*models.Product{createValidProduct(), complete})
+	assert.NoError(t, err)
+
+	// Warnings never fail the write
+	assert.True(t, results[0].Success)
+	codes := make([]models.ValidationWarningCode, 0)
+	for _, warning := range results[0].Warnings {
+		codes = append(codes, warning.Code)
+	}
+	assert.Contains(t, codes, models.WarningShortDescription)
+	assert.Contains(t, codes, models.WarningMissingKeywords)
+
+	assert.True(t, results[1].Success)
+	assert.Empty(t, results[1].Warnings)
+}
+
 func TestBatchUpdateProducts(t *testing.T) {
 	service, publisher, lockManager := setupProductService()
 
 	// Återställ standard mock-förväntningar för locks
 	lockManager.ExpectedCalls = nil
+	allowSKULocks(lockManager)
 
 	products := []*models.Product{
 		createValidProduct(),
@@ -231,7 +437,7 @@ func TestBatchUpdateProducts(t *testing.T) {
 	// Skapa produkterna först
 	for i, p := range products {
 		p.SKU = p.SKU + "-" + string(rune('A'+i))
-		err := service.CreateProduct(p)
+		err := service.CreateProduct(context.Background(), p)
 		assert.NoError(t, err)
 	}
 
@@ -246,7 +452,7 @@ func TestBatchUpdateProducts(t *testing.T) {
 		lockManager.On("ReleaseLock", mock.AnythingOfType("string")).Return(nil).Once()
 	}
 
-	results, err := service.BatchUpdateProducts(products)
+	results, err := service.BatchUpdateProducts(context.Background(), products)
 	assert.NoError(t, err)
 	assert.Len(t, results, len(products))
 
@@ -255,7 +461,7 @@ func TestBatchUpdateProducts(t *testing.T) {
 		assert.Empty(t, result.Error)
 
 		// Verify the update
-		updated, err := service.GetProduct(products[i].ID)
+		updated, err := service.GetProduct(context.Background(), products[i].ID)
 		assert.NoError(t, err)
 		assert.Contains(t, updated.BaseTitle, "Uppdaterad")
 	}
@@ -275,12 +481,12 @@ func TestBatchDeleteProducts(t *testing.T) {
 	var ids []string
 	for i, p := range products {
 		p.SKU = p.SKU + "-" + string(rune('A'+i))
-		err := service.CreateProduct(p)
+		err := service.CreateProduct(context.Background(), p)
 		assert.NoError(t, err)
 		ids = append(ids, p.ID)
 	}
 
-	results, err := service.BatchDeleteProducts(ids)
+	results, err := service.BatchDeleteProducts(context.Background(), ids)
 	assert.NoError(t, err)
 	assert.Len(t, results, 2)
 
@@ -290,13 +496,42 @@ func TestBatchDeleteProducts(t *testing.T) {
 	}
 
 	for _, id := range ids {
-		_, err := service.GetProduct(id)
+		_, err := service.GetProduct(context.Background(), id)
 		assert.Error(t, err)
 	}
 
 	publisher.AssertExpectations(t)
 }
 
+func TestBatchOperationsStopWhenCanceled(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	created, err := service.BatchCreateProducts(ctx, []*models.Product{createValidProduct()})
+	assert.NoError(t, err)
+	updated, err := service.BatchUpdateProducts(ctx, []*models.Product{product.Clone()})
+	assert.NoError(t, err)
+	deleted, err := service.BatchDeleteProducts(ctx, []string{product.ID})
+	assert.NoError(t, err)
+	for _, results := range [][]*interfaces.BatchResult{created, updated, deleted} {
+		assert.Len(t, results, 1)
+		assert.False(t, results[0].Success)
+		assert.Equal(t, context.Canceled.Error(), results[0].Error)
+	}
+
+	stored, err := service.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, product.Version, stored.Version)
+
+	_, err = service.ExportCatalog(ctx, models.CatalogFilter{}, false)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
 func TestUpdateProductVersionConflict(t *testing.T) {
 	service, publisher, lockManager := setupProductService()
 
@@ -306,7 +541,7 @@ func TestUpdateProductVersionConflict(t *testing.T) {
 	lockManager.On("ReleaseLock", mock.AnythingOfType("string")).Return(nil)
 
 	product := createValidProduct()
-	err := service.CreateProduct(product)
+	err := service.CreateProduct(context.Background(), product)
 	assert.NoError(t, err)
 
 	// Create a copy of the product with the old version
@@ -314,12 +549,12 @@ func TestUpdateProductVersionConflict(t *testing.T) {
 
 	// Update the original product
 	product.BaseTitle = "First update"
-	err = service.UpdateProduct(product)
+	err = service.UpdateProduct(context.Background(), product)
 	assert.NoError(t, err)
 
 	// Try to update with the old copy
 	conflictProduct.BaseTitle = "Second update"
-	err = service.UpdateProduct(&conflictProduct)
+	err = service.UpdateProduct(context.Background(), &conflictProduct)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "version conflict")
 
@@ -332,15 +567,16 @@ func TestUpdateProductLockFailure(t *testing.T) {
 
 	// Reset mock and set new expectation for lock failure
 	lockManager.ExpectedCalls = nil
+	allowSKULocks(lockManager)
 	lockManager.On("AcquireLock", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).Return(false, nil)
 
 	product := createValidProduct()
-	err := service.CreateProduct(product)
+	err := service.CreateProduct(context.Background(), product)
 	assert.NoError(t, err)
 
-	err = service.UpdateProduct(product)
+	err = service.UpdateProduct(context.Background(), product)
 	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "could not acquire lock")
+	assert.ErrorIs(t, err, models.ErrLockFailed)
 
 	publisher.AssertExpectations(t)
 	lockManager.AssertExpectations(t)
@@ -355,7 +591,7 @@ func TestReplayEvents(t *testing.T) {
 	lockManager.On("ReleaseLock", mock.AnythingOfType("string")).Return(nil)
 
 	product := createValidProduct()
-	err := service.CreateProduct(product)
+	err := service.CreateProduct(context.Background(), product)
 	assert.NoError(t, err)
 
 	// Save the create event hash
@@ -369,14 +605,14 @@ func TestReplayEvents(t *testing.T) {
 		t.Logf("Before update %d - Hash: %s", i+1, prevHash)
 
 		product.BaseTitle = product.BaseTitle + " Updated"
-		err = service.UpdateProduct(product)
+		err = service.UpdateProduct(context.Background(), product)
 		assert.NoError(t, err)
 
 		t.Logf("After update %d - Hash: %s", i+1, product.LastHash)
 		t.Logf("Product state after update %d: %+v", i+1, product)
 	}
 
-	events, err := service.ReplayEvents(product.ID, 1)
+	events, err := service.ReplayEvents(context.Background(), product.ID, 1)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, events)
 	assert.Len(t, events, 4) // Should have create + 3 update events
@@ -407,3 +643,258 @@ func TestReplayEvents(t *testing.T) {
 	publisher.AssertExpectations(t)
 	lockManager.AssertExpectations(t)
 }
+
+func TestReplayEventsDetectsBrokenChain(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	// An event whose prev hash is not the hash of the version before it
+	assert.NoError(t, service.repo.StoreEvent(context.Background(), &models.Event{
+		ID:       "evt_forged",
+		Type:     models.EventProductUpdated,
+		EntityID: product.ID,
+		Version:  2,
+		Data: &models.ProductEvent{
+			ProductID: product.ID,
+			Product:   product.Clone(),
+			Version:   2,
+			PrevHash:  "forged",
+		},
+		Timestamp: time.Now(),
+	}))
+
+	_, err := service.ReplayEvents(context.Background(), product.ID, 1)
+	assert.ErrorIs(t, err, models.ErrBrokenEventChain)
+	assert.Contains(t, err.Error(), "forged")
+}
+
+func TestReplayEventsOfUnknownProduct(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	events, err := service.ReplayEvents(context.Background(), product.ID, 5)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	_, err = service.ReplayEvents(context.Background(), "missing", 1)
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestBatchOperationsRecordJobs(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	products := []*models.Product{createValidProduct(), createValidProduct()}
+	_, err := service.BatchCreateProducts(context.Background(), products)
+	assert.NoError(t, err)
+
+	_, err = service.BatchDeleteProducts(context.Background(), []string{products[0].ID, "nonexistent"})
+	assert.NoError(t, err)
+
+	jobs, err := service.jobs.List(0)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 2)
+
+	byType := make(map[models.JobType]*models.Job)
+	for _, job := range jobs {
+		byType[job.Type] = job
+	}
+
+	created := byType[models.JobBatchCreate]
+	if assert.NotNil(t, created) {
+		assert.Equal(t, models.JobStatusCompleted, created.Status)
+		assert.Equal(t, 2, created.Total)
+		assert.Equal(t, 2, created.Succeeded)
+		assert.NotNil(t, created.CompletedAt)
+	}
+
+	deleted := byType[models.JobBatchDelete]
+	if assert.NotNil(t, deleted) {
+		assert.Equal(t, 1, deleted.Succeeded)
+		assert.Equal(t, 1, deleted.Failed)
+	}
+}
+
+func TestCompareProducts(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	first := createValidProduct()
+	second := createValidProduct()
+	second.SKU = "TEST-456"
+	assert.NoError(t, service.CreateProduct(context.Background(), first))
+	assert.NoError(t, service.CreateProduct(context.Background(), second))
+
+	comparison, err := service.CompareProducts(context.Background(), []string{second.ID, first.ID, second.ID})
+	assert.NoError(t, err)
+	assert.Len(t, comparison.Products, 2)
+	assert.Equal(t, second.ID, comparison.Products[0].ID)
+	assert.Equal(t, first.ID, comparison.Products[1].ID)
+
+	_, err = service.CompareProducts(context.Background(), []string{first.ID, "nonexistent"})
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	assert.Contains(t, err.Error(), "nonexistent")
+}
+
+func TestListProductsAsOf(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	first := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), first))
+	time.Sleep(5 * time.Millisecond)
+	afterCreate := time.Now()
+	time.Sleep(5 * time.Millisecond)
+
+	update := first.Clone()
+	update.BaseTitle = "Renamed"
+	assert.NoError(t, service.UpdateProduct(context.Background(), update))
+
+	second := createValidProduct()
+	second.SKU = "TEST-456"
+	assert.NoError(t, service.CreateProduct(context.Background(), second))
+	time.Sleep(5 * time.Millisecond)
+	afterUpdate := time.Now()
+	time.Sleep(5 * time.Millisecond)
+
+	_, err := service.BatchDeleteProducts(context.Background(), []string{first.ID})
+	assert.NoError(t, err)
+
+	// Before the update only the original product existed
+	products, total, err := service.ListProductsAsOf(context.Background(), afterCreate, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "Test Produkt", products[0].BaseTitle)
+	assert.Equal(t, int64(1), products[0].Version)
+
+	// After the update both products existed, newest first
+	products, total, err = service.ListProductsAsOf(context.Background(), afterUpdate, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, second.ID, products[0].ID)
+	assert.Equal(t, "Renamed", products[1].BaseTitle)
+
+	// The deletion removes the product from later views
+	products, total, err = service.ListProductsAsOf(context.Background(), time.Now(), 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, second.ID, products[0].ID)
+
+	// Before anything was created the catalog was empty
+	products, total, err = service.ListProductsAsOf(context.Background(), afterCreate.Add(-time.Hour), 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, products)
+}
+
+func TestRollbackProduct(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	for _, title := range []string{"Second", "Third"} {
+		product.BaseTitle = title
+		assert.NoError(t, service.UpdateProduct(context.Background(), product))
+	}
+	assert.Equal(t, int64(3), product.Version)
+
+	restored, err := service.RollbackProduct(context.Background(), product.ID, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Produkt", restored.BaseTitle)
+	assert.Equal(t, int64(4), restored.Version)
+
+	current, err := service.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Test Produkt", current.BaseTitle)
+
+	// The rollback is a new forward version and the chain still verifies
+	events, err := service.ReplayEvents(context.Background(), product.ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 4)
+	assert.Equal(t, "rolled_back", events[3].Data.(*models.ProductEvent).Action)
+}
+
+func TestRollbackProductInvalidVersion(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	product.BaseTitle = "Second"
+	assert.NoError(t, service.UpdateProduct(context.Background(), product))
+
+	for _, version := range []int64{0, 2, 5} {
+		_, err := service.RollbackProduct(context.Background(), product.ID, version)
+		assert.True(t, errors.Is(err, models.ErrInvalidRollbackVersion), "version %d", version)
+	}
+
+	_, err := service.RollbackProduct(context.Background(), "missing", 1)
+	assert.True(t, errors.Is(err, models.ErrProductNotFound))
+}
+
+func TestBatchCreatePublishesOnce(t *testing.T) {
+	service, publisher, _ := setupProductService()
+	publisher.ExpectedCalls = nil
+	publisher.On("PublishBatch", mock.AnythingOfType("[]*models.Event")).
+		Return([]error{nil, errors.New("broker unavailable")}).Once()
+
+	products := []*models.Product{createValidProduct(), createValidProduct()}
+	results, err := service.BatchCreateProducts(context.Background(), products)
+	assert.NoError(t, err)
+
+	publisher.AssertNumberOfCalls(t, "PublishBatch", 1)
+	publisher.AssertNotCalled(t, "Publish", mock.Anything)
+
+	// Publish failures are reported on the matching item
+	assert.True(t, results[0].Success)
+	assert.False(t, results[1].Success)
+	assert.Contains(t, results[1].Error, "broker unavailable")
+}
+
+func TestNewProductServiceContinuesStoredSequence(t *testing.T) {
+	repo := memory.NewProductRepository()
+	assert.NoError(t, repo.StoreEvent(context.Background(), &models.Event{
+		ID:        "evt_existing",
+		Type:      models.EventProductCreated,
+		EntityID:  "prod_existing",
+		Sequence:  41,
+		Timestamp: time.Now(),
+		Data:      &models.ProductEvent{ProductID: "prod_existing"},
+	}))
+
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.AnythingOfType("*models.Event")).Return(nil)
+
+	service := NewProductService(repo, publisher, locks.NewMemoryLockManager(), memory.NewJobRepository())
+	assert.NoError(t, service.CreateProduct(context.Background(), createValidProduct()))
+
+	published := publisher.Calls[0].Arguments.Get(0).(*models.Event)
+	assert.Equal(t, int64(42), published.Sequence)
+}
+
+func TestWritesTraceLocksAndPublishing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	defer func() {
+		tp.Shutdown(context.Background())
+		otel.SetTracerProvider(previous)
+	}()
+
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	var names []string
+	for _, span := range exporter.GetSpans() {
+		names = append(names, span.Name)
+		if span.Name == "event.publish" {
+			assert.Contains(t, span.Attributes, attribute.String("product.id", product.ID))
+		}
+		if span.Name == "lock.acquire" {
+			assert.Contains(t, span.Attributes, attribute.Bool("lock.acquired", true))
+		}
+	}
+	assert.Contains(t, names, "lock.acquire")
+	assert.Contains(t, names, "event.publish")
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// SoftDeleteProduct marks a product as deleted and publishes a deletion event.
+// The product stays stored, keeps its SKU and can be restored, but reads,
+// updates and listings treat it as deleted. The event has the same shape as
+// that of a permanent delete, so consumers drop the product either way.
+func (s *productService) SoftDeleteProduct(ctx context.Context, id string) (*models.Product, error) {
+	release, err := s.lockProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	current, err := s.activeProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	deleted := current.Clone()
+	deleted.Version++
+	deleted.UpdatedAt = now
+	deleted.DeletedAt = &now
+	// LastHash stays the hash of the last live state, which the deletion event
+	// carries, so a restore continues the chain from it
+
+	event := &models.Event{
+		ID:            uuid.New().String(),
+		Type:          models.EventProductDeleted,
+		EntityID:      id,
+		Version:       deleted.Version,
+		Sequence:      s.getNextSequence(),
+		SchemaVersion: models.EventSchemaVersion,
+		Data: &models.ProductEvent{
+			ProductID: id,
+			Action:    "soft_deleted",
+			Product:   current,
+			Version:   deleted.Version,
+			PrevHash:  current.LastHash,
+		},
+		Timestamp: now,
+	}
+
+	err = s.repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, event); err != nil {
+			return err
+		}
+		if err := tx.Update(ctx, deleted); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.publish(event, nil); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// RestoreProduct re-activates a soft-deleted product as the next version and
+// publishes a restore event. It fails with models.ErrNotDeleted for products
+// that are not deleted.
+func (s *productService) RestoreProduct(ctx context.Context, id string) (*models.Product, error) {
+	release, err := s.lockProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	deleted, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted.IsDeleted() {
+		return nil, fmt.Errorf("%w: %s", models.ErrNotDeleted, id)
+	}
+
+	product := deleted.Clone()
+	product.DeletedAt = nil
+	product.Version++
+	product.UpdatedAt = time.Now()
+	product.LastHash = product.CalculateHash()
+
+	event := s.restoreEvent(product, deleted.LastHash, "")
+	err = s.repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, event); err != nil {
+			return err
+		}
+		if err := tx.Update(ctx, product); err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.publish(event, nil); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// activeProduct returns a stored product that is not soft-deleted. Deleted
+// products are reported as not found.
+func (s *productService) activeProduct(ctx context.Context, id string) (*models.Product, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsDeleted() {
+		return nil, models.ErrProductNotFound
+	}
+	return product, nil
+}
+
+// lockProduct takes the product's write lock without waiting and returns the
+// function that releases it
+func (s *productService) lockProduct(ctx context.Context, id string) (func(), error) {
+	acquired, err := s.acquireLock(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", models.ErrLockFailed, err)
+	}
+	if !acquired {
+		return nil, fmt.Errorf("%w: product %s is being written", models.ErrLockFailed, id)
+	}
+	return func() { s.locks.ReleaseLock(id) }, nil
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestSoftDeleteProduct(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+
+	deleted, err := service.SoftDeleteProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.True(t, deleted.IsDeleted())
+	assert.Equal(t, int64(2), deleted.Version)
+	assert.Equal(t, product.LastHash, deleted.LastHash)
+
+	// The product is kept, but hidden from reads, writes and listings
+	stored, err := service.repo.GetByID(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.True(t, stored.IsDeleted())
+	_, err = service.GetProduct(context.Background(), product.ID)
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	_, err = service.GetProductBySKU(context.Background(), product.SKU)
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	_, err = service.SoftDeleteProduct(context.Background(), product.ID)
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	update := stored.Clone()
+	update.BaseTitle = "Changed"
+	assert.ErrorIs(t, service.UpdateProduct(context.Background(), update), models.ErrProductNotFound)
+
+	listed, total, err := service.ListProducts(context.Background(), models.ProductFilter{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, listed)
+	assert.Zero(t, total)
+	listed, total, err = service.ListProducts(context.Background(), models.ProductFilter{IncludeDeleted: true}, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, listed, 1)
+	assert.Equal(t, 1, total)
+
+	// The SKU stays with the deleted product so it can be restored
+	other := createValidProduct()
+	other.SKU = product.SKU
+	assert.ErrorIs(t, service.CreateProduct(context.Background(), other), models.ErrDuplicateSKU)
+
+	events, err := service.ReplayEvents(context.Background(), product.ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, models.EventProductDeleted, events[1].Type)
+	assert.Equal(t, "soft_deleted", events[1].Data.(*models.ProductEvent).Action)
+
+	_, err = service.SoftDeleteProduct(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestRestoreProduct(t *testing.T) {
+	service, publisher, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	_, err := service.SoftDeleteProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+
+	restored, err := service.RestoreProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.False(t, restored.IsDeleted())
+	assert.Equal(t, int64(3), restored.Version)
+	assert.Equal(t, product.BaseTitle, restored.BaseTitle)
+
+	stored, err := service.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, restored.Version, stored.Version)
+
+	// The restore continues the event chain after the soft delete
+	events, err := service.ReplayEvents(context.Background(), product.ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, models.EventProductRestored, events[2].Type)
+	publisher.AssertCalled(t, "Publish", events[2])
+
+	_, err = service.RestoreProduct(context.Background(), product.ID)
+	assert.ErrorIs(t, err, models.ErrNotDeleted)
+	_, err = service.RestoreProduct(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestDeleteProductRemovesSoftDeletedProduct(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createValidProduct()
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	_, err := service.SoftDeleteProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.DeleteProduct(context.Background(), product.ID))
+	_, err = service.repo.GetByID(context.Background(), product.ID)
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	_, err = service.RestoreProduct(context.Background(), product.ID)
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+
+	events, err := service.ReplayEvents(context.Background(), product.ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// tagPageSize is the page size used when scanning the catalog for tags
+const tagPageSize = 100
+
+// ListProductsByTags returns a page of the products that have every one of the tags
+func (s *productService) ListProductsByTags(ctx context.Context, tags []string, page, pageSize int) ([]*models.Product, int, error) {
+	return s.ListProducts(ctx, models.ProductFilter{Tags: tags}, page, pageSize)
+}
+
+// TagUsage counts the products per tag, most used first
+func (s *productService) TagUsage(ctx context.Context) ([]*models.TagUsage, error) {
+	counts := make(map[string]int)
+	if _, err := s.scanCatalog(ctx, func(product *models.Product) bool {
+		for _, tag := range product.Tags {
+			counts[tag]++
+		}
+		return false
+	}); err != nil {
+		return nil, err
+	}
+
+	usage := make([]*models.TagUsage, 0, len(counts))
+	for tag, count := range counts {
+		usage = append(usage, &models.TagUsage{Tag: tag, Products: count})
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Products != usage[j].Products {
+			return usage[i].Products > usage[j].Products
+		}
+		return usage[i].Tag < usage[j].Tag
+	})
+	return usage, nil
+}
+
+// UpdateTags starts a job that adds and removes tags on the selected products
+// and returns the job right away. Each changed product is written as a regular
+// update tagged with the job, so the change can be rolled back like a batch.
+func (s *productService) UpdateTags(ctx context.Context, update *models.TagUpdate) (*models.Job, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	job, err := s.startJob(models.JobTagUpdate, len(update.ProductIDs))
+	if err != nil {
+		return nil, err
+	}
+	started := *job
+
+	go s.runTagUpdate(context.WithoutCancel(ctx), job, update)
+	return &started, nil
+}
+
+// DeleteProductsByTag deletes every product with the tag as one batch job
+func (s *productService) DeleteProductsByTag(ctx context.Context, tag string) ([]*interfaces.BatchResult, error) {
+	if models.NormalizeTag(tag) == "" {
+		return nil, fmt.Errorf("%w: tag is required", models.ErrInvalidRequest)
+	}
+	ids, err := s.productsWithTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	return s.BatchDeleteProducts(ctx, ids)
+}
+
+// runTagUpdate applies a tag update to each selected product and records the outcome on the job
+func (s *productService) runTagUpdate(ctx context.Context, job *models.Job, update *models.TagUpdate) {
+	logger := logging.Shared().WithFields(zap.String("job_id", job.ID))
+
+	ids := update.ProductIDs
+	if len(ids) == 0 {
+		tagged, err := s.productsWithTag(ctx, update.Tag)
+		if err != nil {
+			logger.Error("Failed to scan catalog for tag update", zap.Error(err))
+			job.AddError(err.Error())
+			job.Complete(0, 1)
+			s.jobs.Update(job)
+			return
+		}
+		ids = tagged
+
+		// Publish the total so progress is visible while the job runs
+		job.Total = len(ids)
+		s.jobs.Update(job)
+	}
+
+	results := make([]*interfaces.BatchResult, 0, len(ids))
+	for _, id := range ids {
+		result := &interfaces.BatchResult{ID: id, Success: true}
+		if err := s.tagProduct(ctx, id, update, job.ID); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			job.AddError(fmt.Sprintf("%s: %v", id, err))
+		}
+		results = append(results, result)
+	}
+
+	if err := s.finishJob(job, results); err != nil {
+		logger.Error("Failed to record tag update", zap.Error(err))
+		return
+	}
+	logger.Info("Tag update completed",
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("failed", job.Failed),
+	)
+}
+
+// tagProduct applies the update to the current state of a product and publishes the change
+func (s *productService) tagProduct(ctx context.Context, id string, update *models.TagUpdate, jobID string) error {
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	tagged, changed, err := update.Apply(current)
+	if err != nil || !changed {
+		return err
+	}
+	return s.publish(s.updateProduct(ctx, tagged, "tags_updated", jobID))
+}
+
+// productsWithTag returns the IDs of the products with a tag
+func (s *productService) productsWithTag(ctx context.Context, tag string) ([]string, error) {
+	products, err := s.scanCatalog(ctx, func(product *models.Product) bool {
+		return product.HasTags(tag)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+	return ids, nil
+}
+
+// scanCatalog returns the products that match, in repository order. The whole
+// catalog is scanned before anything is written so updates cannot shift the pages.
+func (s *productService) scanCatalog(ctx context.Context, match func(product *models.Product) bool) ([]*models.Product, error) {
+	matched := make([]*models.Product, 0)
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		products, total, err := s.repo.List(ctx, models.ProductFilter{}, page, tagPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list products: %v", err)
+		}
+		for _, product := range products {
+			if match(product) {
+				matched = append(matched, product)
+			}
+		}
+		if len(products) == 0 || page*tagPageSize >= total {
+			return matched, nil
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTaggedProduct(t *testing.T, service *productService, sku string, tags ...string) *models.Product {
+	product := createValidProduct()
+	product.SKU = sku
+	product.Tags = tags
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	return product
+}
+
+func TestCreateProductNormalizesTags(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createTaggedProduct(t, service, "SHIRT", " Summer ", "sale", "SALE", "")
+
+	stored, err := service.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sale", "summer"}, stored.Tags)
+}
+
+func TestListProductsByTags(t *testing.T) {
+	service, _, _ := setupProductService()
+	shirt := createTaggedProduct(t, service, "SHIRT", "summer", "sale")
+	createTaggedProduct(t, service, "COAT", "winter", "sale")
+	dress := createTaggedProduct(t, service, "DRESS", "summer", "sale")
+	createTaggedProduct(t, service, "SOCK", "summer")
+
+	products, total, err := service.ListProductsByTags(context.Background(), []string{"Sale", "summer"}, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	if assert.Len(t, products, 1) {
+		assert.Contains(t, []string{shirt.ID, dress.ID}, products[0].ID)
+	}
+
+	products, total, err = service.ListProductsByTags(context.Background(), []string{"sale", "summer"}, 3, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Empty(t, products)
+
+	products, total, err = service.ListProductsByTags(context.Background(), nil, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Len(t, products, 4)
+}
+
+func TestTagUsage(t *testing.T) {
+	service, _, _ := setupProductService()
+	createTaggedProduct(t, service, "SHIRT", "summer", "sale")
+	createTaggedProduct(t, service, "COAT", "winter", "sale")
+	createTaggedProduct(t, service, "SOCK")
+
+	usage, err := service.TagUsage(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.TagUsage{
+		{Tag: "sale", Products: 2},
+		{Tag: "summer", Products: 1},
+		{Tag: "winter", Products: 1},
+	}, usage)
+}
+
+func TestUpdateTagsByTag(t *testing.T) {
+	service, _, _ := setupProductService()
+	shirt := createTaggedProduct(t, service, "SHIRT", "summer")
+	coat := createTaggedProduct(t, service, "COAT", "winter")
+
+	started, err := service.UpdateTags(context.Background(), &models.TagUpdate{Tag: "Summer", Add: []string{"Sale"}, Remove: []string{"summer"}})
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobTagUpdate, started.Type)
+
+	job := waitForJob(t, service, started.ID)
+	assert.Equal(t, models.JobStatusCompleted, job.Status)
+	assert.Equal(t, 1, job.Total)
+	assert.Equal(t, 1, job.Succeeded)
+
+	tagged, err := service.GetProduct(context.Background(), shirt.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sale"}, tagged.Tags)
+	assert.Equal(t, int64(2), tagged.Version)
+
+	events, err := service.repo.GetEventsByProductID(context.Background(), shirt.ID, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, job.ID, events[0].JobID)
+		assert.Equal(t, "tags_updated", events[0].Data.(*models.ProductEvent).Action)
+	}
+
+	untouched, err := service.GetProduct(context.Background(), coat.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), untouched.Version)
+}
+
+func TestUpdateTagsByIDs(t *testing.T) {
+	service, _, _ := setupProductService()
+	shirt := createTaggedProduct(t, service, "SHIRT")
+	coat := createTaggedProduct(t, service, "COAT", "clearance")
+
+	started, err := service.UpdateTags(context.Background(), &models.TagUpdate{ProductIDs: []string{shirt.ID, coat.ID, "missing"}, Add: []string{"clearance"}})
+	assert.NoError(t, err)
+
+	job := waitForJob(t, service, started.ID)
+	assert.Equal(t, 3, job.Total)
+	assert.Equal(t, 2, job.Succeeded)
+	assert.Equal(t, 1, job.Failed)
+
+	tagged, _ := service.GetProduct(context.Background(), shirt.ID)
+	assert.Equal(t, []string{"clearance"}, tagged.Tags)
+
+	// Products that already had the tag are not rewritten
+	unchanged, _ := service.GetProduct(context.Background(), coat.ID)
+	assert.Equal(t, int64(1), unchanged.Version)
+}
+
+func TestUpdateTagsCanBeRolledBack(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createTaggedProduct(t, service, "SHIRT", "summer")
+
+	started, err := service.UpdateTags(context.Background(), &models.TagUpdate{ProductIDs: []string{product.ID}, Remove: []string{"summer"}})
+	assert.NoError(t, err)
+	waitForJob(t, service, started.ID)
+
+	_, err = service.RollbackJob(context.Background(), started.ID)
+	assert.NoError(t, err)
+
+	reverted, err := service.GetProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"summer"}, reverted.Tags)
+}
+
+func TestUpdateTagsInvalid(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	_, err := service.UpdateTags(context.Background(), &models.TagUpdate{Add: []string{"sale"}})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	_, err = service.UpdateTags(context.Background(), &models.TagUpdate{Tag: "sale"})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	jobs, _ := service.jobs.List(0)
+	assert.Empty(t, jobs)
+}
+
+func TestDeleteProductsByTag(t *testing.T) {
+	service, _, _ := setupProductService()
+	createTaggedProduct(t, service, "SHIRT", "discontinued")
+	createTaggedProduct(t, service, "COAT", "discontinued", "winter")
+	kept := createTaggedProduct(t, service, "SOCK", "winter")
+
+	results, err := service.DeleteProductsByTag(context.Background(), "Discontinued")
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.True(t, result.Success)
+	}
+
+	products, total, err := service.ListProducts(context.Background(), models.ProductFilter{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, products, 1) {
+		assert.Equal(t, kept.ID, products[0].ID)
+	}
+
+	_, err = service.DeleteProductsByTag(context.Background(), " ")
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
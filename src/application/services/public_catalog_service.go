@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// publicCatalogService implements the PublicCatalogService interface
+type publicCatalogService struct {
+	repo    repositories.ProductRepository
+	markets interfaces.MarketService
+}
+
+// NewPublicCatalogService creates a new public catalog service instance
+func NewPublicCatalogService(repo repositories.ProductRepository, markets interfaces.MarketService) interfaces.PublicCatalogService {
+	return &publicCatalogService{
+		repo:    repo,
+		markets: markets,
+	}
+}
+
+// ListProducts returns one page of the published products with the tags; the
+// repository filters out the drafts
+func (s *publicCatalogService) ListProducts(ctx context.Context, tags []string, page, pageSize int) ([]*models.Product, int, error) {
+	filter := models.ProductFilter{Tags: tags, PublishedOnly: true}
+	products, total, err := s.repo.List(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list products: %v", err)
+	}
+	return products, total, nil
+}
+
+// GetProduct returns a published product. Drafts are reported as not found so
+// their existence is not revealed.
+func (s *publicCatalogService) GetProduct(ctx context.Context, id string) (*models.Product, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !product.IsPublished() {
+		return nil, models.ErrProductNotFound
+	}
+	return product, nil
+}
+
+// ListMarketProducts returns the market listing, which never includes drafts
+func (s *publicCatalogService) ListMarketProducts(ctx context.Context, market, category string, page, pageSize int) ([]*models.Product, int, error) {
+	return s.markets.ListMarketProducts(ctx, market, category, page, pageSize)
+}
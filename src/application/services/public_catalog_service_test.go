@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+func setupPublicCatalog() (repositories.ProductRepository, interfaces.PublicCatalogService) {
+	repo := memory.NewProductRepository()
+	service := NewPublicCatalogService(repo, NewMarketService(repo, memory.NewMerchandisingRepository()))
+	return repo, service
+}
+
+func createPublicProduct(t *testing.T, repo repositories.ProductRepository, id string, draft bool, tags ...string) {
+	product := createValidProduct()
+	product.ID = id
+	product.CreatedAt = time.Now() // keeps the catalog order, and so the pages, stable
+	product.Draft = draft
+	product.Tags = tags
+	assert.NoError(t, repo.Create(context.Background(), product))
+}
+
+func TestPublicListProductsHidesDrafts(t *testing.T) {
+	repo, service := setupPublicCatalog()
+	createPublicProduct(t, repo, "prod_live", false, "summer")
+	createPublicProduct(t, repo, "prod_draft", true, "summer")
+	createPublicProduct(t, repo, "prod_other", false)
+
+	products, total, err := service.ListProducts(context.Background(), nil, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	for _, product := range products {
+		assert.False(t, product.Draft)
+	}
+
+	products, total, err = service.ListProducts(context.Background(), []string{"Summer"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "prod_live", products[0].ID)
+}
+
+func TestPublicListProductsPages(t *testing.T) {
+	repo, service := setupPublicCatalog()
+	count := 103
+	for i := 0; i < count; i++ {
+		createPublicProduct(t, repo, fmt.Sprintf("prod_%d", i), i%2 == 1)
+	}
+
+	products, total, err := service.ListProducts(context.Background(), nil, 6, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, (count+1)/2, total)
+	assert.Len(t, products, 2)
+
+	products, _, err = service.ListProducts(context.Background(), nil, 100, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, products)
+}
+
+// listRecorder records the filters the catalog is listed with
+type listRecorder struct {
+	repositories.ProductRepository
+	filters []models.ProductFilter
+}
+
+func (r *listRecorder) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	r.filters = append(r.filters, filter)
+	return r.ProductRepository.List(ctx, filter, page, pageSize)
+}
+
+func TestPublicListProductsFiltersInRepository(t *testing.T) {
+	repo := &listRecorder{ProductRepository: memory.NewProductRepository()}
+	service := NewPublicCatalogService(repo, NewMarketService(repo, memory.NewMerchandisingRepository()))
+	createPublicProduct(t, repo, "prod_live", false, "summer")
+	createPublicProduct(t, repo, "prod_draft", true, "summer")
+
+	products, total, err := service.ListProducts(context.Background(), []string{"summer"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, products, 1)
+	assert.Equal(t, []models.ProductFilter{{Tags: []string{"summer"}, PublishedOnly: true}}, repo.filters)
+}
+
+func TestPublicGetProduct(t *testing.T) {
+	repo, service := setupPublicCatalog()
+	createPublicProduct(t, repo, "prod_live", false)
+	createPublicProduct(t, repo, "prod_draft", true)
+
+	product, err := service.GetProduct(context.Background(), "prod_live")
+	assert.NoError(t, err)
+	assert.Equal(t, "prod_live", product.ID)
+
+	_, err = service.GetProduct(context.Background(), "prod_draft")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+
+	_, err = service.GetProduct(context.Background(), "prod_missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestPublicListMarketProductsHidesDrafts(t *testing.T) {
+	repo, service := setupPublicCatalog()
+	createPublicProduct(t, repo, "prod_live", false)
+	createPublicProduct(t, repo, "prod_draft", true)
+
+	products, total, err := service.ListMarketProducts(context.Background(), "SE", "", 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "prod_live", products[0].ID)
+}
+
+// contextRepository fails reads once their context is done, as the database repositories do
+type contextRepository struct {
+	repositories.ProductRepository
+}
+
+func (r contextRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	return r.ProductRepository.List(ctx, filter, page, pageSize)
+}
+
+func TestPublicListProductsStopsWithRequest(t *testing.T) {
+	repo := contextRepository{memory.NewProductRepository()}
+	service := NewPublicCatalogService(repo, NewMarketService(repo, memory.NewMerchandisingRepository()))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := service.ListProducts(ctx, nil, 1, 10)
+	assert.ErrorContains(t, err, context.Canceled.Error())
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// relationService implements the RelationService interface
+type relationService struct {
+	relations repositories.RelationRepository
+	products  interfaces.ProductService
+}
+
+// NewRelationService creates a relation service that removes the relations
+// of products deleted for good, as their product.deleted events arrive
+func NewRelationService(relations repositories.RelationRepository, products interfaces.ProductService, publisher events.EventPublisher) interfaces.RelationService {
+	s := &relationService{
+		relations: relations,
+		products:  products,
+	}
+	publisher.Subscribe(models.EventProductDeleted, s.handleDeleted)
+	return s
+}
+
+// ListRelations returns the relations from a product
+func (s *relationService) ListRelations(ctx context.Context, productID string, relationType models.RelationType) ([]*models.ProductRelation, error) {
+	if err := checkRelationType(relationType); err != nil {
+		return nil, err
+	}
+	if _, err := s.products.GetProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+	relations, err := s.relations.ListByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	return filterRelations(relations, relationType), nil
+}
+
+// AddRelation links two products. Replacing a relation keeps its creation time.
+func (s *relationService) AddRelation(ctx context.Context, relation *models.ProductRelation) (*models.ProductRelation, error) {
+	added := *relation
+	if err := added.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.products.GetProduct(ctx, added.ProductID); err != nil {
+		return nil, err
+	}
+	if _, err := s.products.GetProduct(ctx, added.RelatedID); err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			return nil, fmt.Errorf("%w: related product %s not found", models.ErrInvalidRequest, added.RelatedID)
+		}
+		return nil, err
+	}
+
+	existing, err := s.relations.ListByProduct(added.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	added.CreatedAt = time.Now()
+	replaced := false
+	for _, r := range existing {
+		if r.RelatedID == added.RelatedID && r.Type == added.Type {
+			added.CreatedAt = r.CreatedAt
+			replaced = true
+		}
+	}
+	if !replaced && len(existing) >= models.MaxProductRelations {
+		return nil, fmt.Errorf("%w: at most %d relations per product", models.ErrInvalidRequest, models.MaxProductRelations)
+	}
+	if err := s.relations.Save(&added); err != nil {
+		return nil, fmt.Errorf("failed to save relation: %v", err)
+	}
+	return &added, nil
+}
+
+// RemoveRelation removes a relation
+func (s *relationService) RemoveRelation(productID, relatedID string, relationType models.RelationType) error {
+	return s.relations.Delete(productID, relatedID, relationType)
+}
+
+// RelatedProducts resolves the products linked from a product, in the order
+// of its relations
+func (s *relationService) RelatedProducts(ctx context.Context, productID string, relationType models.RelationType) ([]*models.RelatedProduct, error) {
+	relations, err := s.ListRelations(ctx, productID, relationType)
+	if err != nil {
+		return nil, err
+	}
+	related := make([]*models.RelatedProduct, 0, len(relations))
+	for _, relation := range relations {
+		product, err := s.products.GetProduct(ctx, relation.RelatedID)
+		if errors.Is(err, models.ErrProductNotFound) {
+			// Soft-deleted products keep their relations until they are restored
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		related = append(related, &models.RelatedProduct{
+			Type:     relation.Type,
+			Position: relation.Position,
+			Product:  product,
+		})
+	}
+	return related, nil
+}
+
+// handleDeleted removes the relations from and to a product deleted for
+// good. Soft deletes keep them, so restored products come back linked.
+func (s *relationService) handleDeleted(event *models.Event) {
+	productEvent, ok := event.Data.(*models.ProductEvent)
+	if !ok || productEvent.Action != "deleted" {
+		return
+	}
+	if _, err := s.relations.DeleteByProduct(productEvent.ProductID); err != nil {
+		logging.Shared().Error("Failed to remove relations of deleted product",
+			zap.String("product_id", productEvent.ProductID), zap.Error(err))
+	}
+}
+
+// checkRelationType accepts known relation types and the empty type for all
+func checkRelationType(relationType models.RelationType) error {
+	if relationType != "" && !relationType.IsValid() {
+		return fmt.Errorf("%w: unknown relation type %q", models.ErrInvalidRequest, relationType)
+	}
+	return nil
+}
+
+// filterRelations keeps the relations of a type, or all without one
+func filterRelations(relations []*models.ProductRelation, relationType models.RelationType) []*models.ProductRelation {
+	if relationType == "" {
+		return relations
+	}
+	filtered := make([]*models.ProductRelation, 0, len(relations))
+	for _, relation := range relations {
+		if relation.Type == relationType {
+			filtered = append(filtered, relation)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+// setupRelationService returns a relation service over a memory product
+// service, with the product service's publisher to replay its events from
+func setupRelationService(t *testing.T) (*relationService, *productService, *MockEventPublisher) {
+	products, productPublisher, _ := setupProductService()
+	publisher := new(MockEventPublisher)
+	publisher.On("Subscribe", models.EventProductDeleted, mock.Anything).Return(nil)
+	service := NewRelationService(memory.NewRelationRepository(), products, publisher).(*relationService)
+	publisher.AssertExpectations(t)
+	return service, products, productPublisher
+}
+
+func createRelatedProducts(t *testing.T, products *productService, count int) []*models.Product {
+	created := make([]*models.Product, count)
+	for i := range created {
+		created[i] = createValidProduct()
+		assert.NoError(t, products.CreateProduct(context.Background(), created[i]))
+	}
+	return created
+}
+
+func TestAddRelation(t *testing.T) {
+	service, products, _ := setupRelationService(t)
+	linked := createRelatedProducts(t, products, 3)
+	shirt, tie, belt := linked[0], linked[1], linked[2]
+
+	relation, err := service.AddRelation(context.Background(), &models.ProductRelation{ProductID: shirt.ID, RelatedID: tie.ID, Type: "cross_sell", Position: 2})
+	assert.NoError(t, err)
+	assert.False(t, relation.CreatedAt.IsZero())
+	_, err = service.AddRelation(context.Background(), &models.ProductRelation{ProductID: shirt.ID, RelatedID: belt.ID, Type: models.RelationCrossSell, Position: 1})
+	assert.NoError(t, err)
+	_, err = service.AddRelation(context.Background(), &models.ProductRelation{ProductID: shirt.ID, RelatedID: tie.ID, Type: models.RelationUpsell})
+	assert.NoError(t, err)
+
+	// Adding the same link again replaces it and keeps its creation time
+	replaced, err := service.AddRelation(context.Background(), &models.ProductRelation{ProductID: shirt.ID, RelatedID: tie.ID, Type: models.RelationCrossSell, Position: 3})
+	assert.NoError(t, err)
+	assert.Equal(t, relation.CreatedAt, replaced.CreatedAt)
+
+	relations, err := service.ListRelations(context.Background(), shirt.ID, models.RelationCrossSell)
+	assert.NoError(t, err)
+	if assert.Len(t, relations, 2) {
+		assert.Equal(t, belt.ID, relations[0].RelatedID)
+		assert.Equal(t, 3, relations[1].Position)
+	}
+	relations, err = service.ListRelations(context.Background(), shirt.ID, "")
+	assert.NoError(t, err)
+	assert.Len(t, relations, 3)
+
+	// Relations point one way
+	relations, err = service.ListRelations(context.Background(), tie.ID, "")
+	assert.NoError(t, err)
+	assert.Empty(t, relations)
+}
+
+func TestAddRelationErrors(t *testing.T) {
+	service, products, _ := setupRelationService(t)
+	shirt := createRelatedProducts(t, products, 1)[0]
+
+	_, err := service.AddRelation(context.Background(), &models.ProductRelation{ProductID: shirt.ID, RelatedID: "missing", Type: models.RelationRelated})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.AddRelation(context.Background(), &models.ProductRelation{ProductID: "missing", RelatedID: shirt.ID, Type: models.RelationRelated})
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	_, err = service.AddRelation(context.Background(), &models.ProductRelation{ProductID: shirt.ID, RelatedID: shirt.ID, Type: models.RelationRelated})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	_, err = service.ListRelations(context.Background(), shirt.ID, "similar")
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	assert.ErrorIs(t, service.RemoveRelation(shirt.ID, "missing", models.RelationRelated), models.ErrRelationNotFound)
+}
+
+func TestRelatedProducts(t *testing.T) {
+	service, products, _ := setupRelationService(t)
+	linked := createRelatedProducts(t, products, 3)
+	shirt, tie, belt := linked[0], linked[1], linked[2]
+	for _, relation := range []*models.ProductRelation{
+		{ProductID: shirt.ID, RelatedID: tie.ID, Type: models.RelationCrossSell, Position: 2},
+		{ProductID: shirt.ID, RelatedID: belt.ID, Type: models.RelationCrossSell, Position: 1},
+		{ProductID: shirt.ID, RelatedID: belt.ID, Type: models.RelationUpsell},
+	} {
+		_, err := service.AddRelation(context.Background(), relation)
+		assert.NoError(t, err)
+	}
+
+	related, err := service.RelatedProducts(context.Background(), shirt.ID, models.RelationCrossSell)
+	assert.NoError(t, err)
+	if assert.Len(t, related, 2) {
+		assert.Equal(t, belt.SKU, related[0].Product.SKU)
+		assert.Equal(t, tie.SKU, related[1].Product.SKU)
+	}
+
+	// Soft-deleted products are hidden but stay linked
+	_, err = products.SoftDeleteProduct(context.Background(), tie.ID)
+	assert.NoError(t, err)
+	related, err = service.RelatedProducts(context.Background(), shirt.ID, models.RelationCrossSell)
+	assert.NoError(t, err)
+	assert.Len(t, related, 1)
+	relations, _ := service.ListRelations(context.Background(), shirt.ID, models.RelationCrossSell)
+	assert.Len(t, relations, 2)
+
+	_, err = service.RelatedProducts(context.Background(), "missing", "")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestRelationsRemovedWithDeletedProduct(t *testing.T) {
+	service, products, productPublisher := setupRelationService(t)
+	linked := createRelatedProducts(t, products, 3)
+	shirt, tie, belt := linked[0], linked[1], linked[2]
+	for _, relation := range []*models.ProductRelation{
+		{ProductID: shirt.ID, RelatedID: tie.ID, Type: models.RelationCrossSell},
+		{ProductID: shirt.ID, RelatedID: belt.ID, Type: models.RelationCrossSell},
+		{ProductID: tie.ID, RelatedID: shirt.ID, Type: models.RelationRelated},
+	} {
+		_, err := service.AddRelation(context.Background(), relation)
+		assert.NoError(t, err)
+	}
+
+	_, err := products.SoftDeleteProduct(context.Background(), tie.ID)
+	assert.NoError(t, err)
+	assert.NoError(t, products.DeleteProduct(context.Background(), tie.ID))
+	for _, call := range productPublisher.Calls {
+		if call.Method == "Publish" {
+			service.handleDeleted(call.Arguments.Get(0).(*models.Event))
+		}
+	}
+
+	relations, err := service.ListRelations(context.Background(), shirt.ID, "")
+	assert.NoError(t, err)
+	if assert.Len(t, relations, 1) {
+		assert.Equal(t, belt.ID, relations[0].RelatedID)
+	}
+	removed, _ := service.relations.DeleteByProduct(tie.ID)
+	assert.Zero(t, removed)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultReprocessRate is the delivery rate used when a request sets none, in events per second
+	defaultReprocessRate = 10
+	// maxReprocessRate caps the delivery rate a request may ask for
+	maxReprocessRate = 1000
+	// reprocessPageSize is the page size used when scanning the catalog, and how
+	// often a running job's counts are saved
+	reprocessPageSize = 100
+)
+
+// reprocessService implements the ReprocessService interface
+type reprocessService struct {
+	products    repositories.ProductRepository
+	jobs        repositories.JobRepository
+	redeliverer interfaces.EventRedeliverer
+}
+
+// NewReprocessService creates a service that delivers the latest event of
+// selected products to internal consumers again, at a limited rate
+func NewReprocessService(products repositories.ProductRepository, jobs repositories.JobRepository, redeliverer interfaces.EventRedeliverer) interfaces.ReprocessService {
+	return &reprocessService{
+		products:    products,
+		jobs:        jobs,
+		redeliverer: redeliverer,
+	}
+}
+
+// StartReprocess validates the request, records a reprocess job and works
+// through the selected products in the background
+func (s *reprocessService) StartReprocess(ctx context.Context, req *interfaces.ReprocessRequest) (*models.Job, error) {
+	rate := req.Rate
+	if rate == 0 {
+		rate = defaultReprocessRate
+	}
+	if rate < 0 || rate > maxReprocessRate {
+		return nil, fmt.Errorf("%w: rate must be between 0 and %d events per second", models.ErrInvalidRequest, maxReprocessRate)
+	}
+	if req.Consumer != "" && !s.redeliverer.HasConsumer(req.Consumer) {
+		return nil, fmt.Errorf("%w: %s", models.ErrConsumerNotFound, req.Consumer)
+	}
+
+	job := &models.Job{
+		ID:        "job_" + uuid.New().String(),
+		Type:      models.JobReprocess,
+		Status:    models.JobStatusRunning,
+		Source:    req.Consumer,
+		CreatedAt: time.Now(),
+	}
+	if err := s.jobs.Create(job); err != nil {
+		return nil, fmt.Errorf("failed to create job: %v", err)
+	}
+	started := *job
+
+	go s.run(context.WithoutCancel(ctx), job, req, time.Duration(float64(time.Second)/rate))
+	return &started, nil
+}
+
+// run delivers one event per interval and records progress on the job
+func (s *reprocessService) run(ctx context.Context, job *models.Job, req *interfaces.ReprocessRequest, interval time.Duration) {
+	logger := logging.Shared().WithFields(zap.String("job_id", job.ID), zap.String("consumer", req.Consumer))
+
+	ids, err := s.selectProducts(ctx, req)
+	if err != nil {
+		logger.Error("Failed to select products to reprocess", zap.Error(err))
+		job.AddError(err.Error())
+		job.Complete(0, 1)
+		s.jobs.Update(job)
+		return
+	}
+	job.Total = len(ids)
+	s.jobs.Update(job)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for i, id := range ids {
+		if i > 0 {
+			<-ticker.C
+		}
+		if err := s.reprocess(ctx, req.Consumer, id); err != nil {
+			job.Failed++
+			job.AddError(fmt.Sprintf("%s: %v", id, err))
+		} else {
+			job.Succeeded++
+		}
+		if (i+1)%reprocessPageSize == 0 {
+			s.jobs.Update(job)
+		}
+	}
+
+	job.Complete(job.Succeeded, job.Failed)
+	if err := s.jobs.Update(job); err != nil {
+		logger.Error("Failed to record reprocess job", zap.Error(err))
+		return
+	}
+	logger.Info("Reprocessing completed",
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("failed", job.Failed),
+	)
+}
+
+// reprocess delivers the latest event of a product, which for a deleted product is its deletion
+func (s *reprocessService) reprocess(ctx context.Context, consumer, id string) error {
+	events, err := s.products.GetEventsByProductID(ctx, id, 0)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return models.ErrProductNotFound
+	}
+	_, err = s.redeliverer.Redeliver(consumer, events[len(events)-1])
+	return err
+}
+
+// selectProducts returns the IDs matching the request's filter. Listed IDs are
+// used as given so deleted products can be reprocessed too.
+func (s *reprocessService) selectProducts(ctx context.Context, req *interfaces.ReprocessRequest) ([]string, error) {
+	if len(req.ProductIDs) > 0 {
+		return req.ProductIDs, nil
+	}
+
+	ids := make([]string, 0)
+	for page := 1; ; page++ {
+		products, total, err := s.products.List(ctx, models.ProductFilter{}, page, reprocessPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list products: %v", err)
+		}
+		for _, product := range products {
+			if req.SKUPrefix != "" && !strings.HasPrefix(product.SKU, req.SKUPrefix) {
+				continue
+			}
+			if req.Tag != "" && !product.HasTags(req.Tag) {
+				continue
+			}
+			if req.UpdatedSince != nil && product.UpdatedAt.Before(*req.UpdatedSince) {
+				continue
+			}
+			ids = append(ids, product.ID)
+		}
+		if len(products) == 0 || page*reprocessPageSize >= total {
+			return ids, nil
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// recordingRedeliverer records the events handed to it
+type recordingRedeliverer struct {
+	mu        sync.Mutex
+	consumers []string
+	events    []*models.Event
+}
+
+func (r *recordingRedeliverer) HasConsumer(name string) bool {
+	for _, consumer := range r.consumers {
+		if consumer == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *recordingRedeliverer) Redeliver(consumer string, event *models.Event) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return 1, nil
+}
+
+func (r *recordingRedeliverer) delivered() []*models.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*models.Event(nil), r.events...)
+}
+
+func TestStartReprocess(t *testing.T) {
+	productService, _, _ := setupProductService()
+	shirt := createProductWithColour(t, productService, "SHIRT-1", "Navy")
+	createProductWithColour(t, productService, "SOCK-1", "Red")
+	shirt.BaseTitle = "Updated shirt"
+	assert.NoError(t, productService.UpdateProduct(context.Background(), shirt))
+
+	redeliverer := &recordingRedeliverer{consumers: []string{"marketplaces"}}
+	service := NewReprocessService(productService.repo, productService.jobs, redeliverer)
+
+	started, err := service.StartReprocess(context.Background(), &interfaces.ReprocessRequest{
+		Consumer:  "marketplaces",
+		SKUPrefix: "SHIRT",
+		Rate:      maxReprocessRate,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobReprocess, started.Type)
+	assert.Equal(t, "marketplaces", started.Source)
+
+	job := waitForJob(t, productService, started.ID)
+	assert.Equal(t, 1, job.Total)
+	assert.Equal(t, 1, job.Succeeded)
+
+	// Only the latest event of the matching product is delivered
+	delivered := redeliverer.delivered()
+	assert.Len(t, delivered, 1)
+	assert.Equal(t, shirt.ID, delivered[0].EntityID)
+	assert.Equal(t, models.EventProductUpdated, delivered[0].Type)
+}
+
+func TestStartReprocessFilters(t *testing.T) {
+	productService, _, _ := setupProductService()
+	shirt := createProductWithColour(t, productService, "SHIRT-1", "Navy")
+	createProductWithColour(t, productService, "SHIRT-2", "Red")
+	createTaggedProduct(t, productService, "SOCK-1", "summer")
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name string
+		req  *interfaces.ReprocessRequest
+		want int
+	}{
+		{"everything", &interfaces.ReprocessRequest{}, 3},
+		{"listed ids", &interfaces.ReprocessRequest{ProductIDs: []string{shirt.ID}}, 1},
+		{"unknown id fails", &interfaces.ReprocessRequest{ProductIDs: []string{"missing"}}, 0},
+		{"updated since", &interfaces.ReprocessRequest{UpdatedSince: &future}, 0},
+		{"tag", &interfaces.ReprocessRequest{Tag: "Summer"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redeliverer := &recordingRedeliverer{}
+			service := NewReprocessService(productService.repo, productService.jobs, redeliverer)
+			tt.req.Rate = maxReprocessRate
+
+			started, err := service.StartReprocess(context.Background(), tt.req)
+			assert.NoError(t, err)
+			job := waitForJob(t, productService, started.ID)
+			assert.Equal(t, tt.want, job.Succeeded)
+			assert.Len(t, redeliverer.delivered(), tt.want)
+		})
+	}
+}
+
+func TestStartReprocessValidation(t *testing.T) {
+	productService, _, _ := setupProductService()
+	service := NewReprocessService(productService.repo, productService.jobs, &recordingRedeliverer{})
+
+	_, err := service.StartReprocess(context.Background(), &interfaces.ReprocessRequest{Rate: maxReprocessRate + 1})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	_, err = service.StartReprocess(context.Background(), &interfaces.ReprocessRequest{Rate: -1})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	_, err = service.StartReprocess(context.Background(), &interfaces.ReprocessRequest{Consumer: "unknown"})
+	assert.True(t, errors.Is(err, models.ErrConsumerNotFound))
+}
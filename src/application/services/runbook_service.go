@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/locks"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// DefaultStaleLockAge is how long a lock must have been held before a release
+// that sets no age treats it as stale. Product writes hold their lock for
+// seconds, so a write lock this old belongs to a writer that is gone.
+const DefaultStaleLockAge = time.Minute
+
+// runbookService implements the RunbookService interface
+type runbookService struct {
+	products    repositories.ProductRepository
+	redeliverer interfaces.EventRedeliverer
+	statuses    repositories.SyncStatusRepository
+	locks       locks.OwnedLockManager
+	runs        repositories.RunbookRunRepository
+	config      interfaces.RunbookConfig
+}
+
+// NewRunbookService creates a service for the recovery actions on-call engineers
+// would otherwise need database access for
+func NewRunbookService(products repositories.ProductRepository, redeliverer interfaces.EventRedeliverer, statuses repositories.SyncStatusRepository, lockManager locks.OwnedLockManager, runs repositories.RunbookRunRepository, config interfaces.RunbookConfig) interfaces.RunbookService {
+	return &runbookService{
+		products:    products,
+		redeliverer: redeliverer,
+		statuses:    statuses,
+		locks:       lockManager,
+		runs:        runs,
+		config:      config,
+	}
+}
+
+// record runs an action and adds it to the audit trail and the log, whether it
+// succeeded or not. Requests without an actor are refused before anything runs.
+func (s *runbookService) record(action, actor, subject string, params map[string]interface{}, act func() (interface{}, error)) (*models.RunbookRun, error) {
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return nil, fmt.Errorf("%w: actor is required", models.ErrInvalidRequest)
+	}
+
+	run := &models.RunbookRun{
+		ID:        uuid.New().String(),
+		Action:    action,
+		Actor:     actor,
+		Subject:   subject,
+		Params:    params,
+		StartedAt: time.Now(),
+	}
+	result, err := act()
+	run.FinishedAt = time.Now()
+	run.Result = result
+	run.Succeeded = err == nil
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	logger := logging.Shared().WithFields(
+		zap.String("runbook_run_id", run.ID),
+		zap.String("action", action),
+		zap.String("actor", actor),
+		zap.String("subject", subject),
+		zap.Any("params", params),
+	)
+	if err != nil {
+		logger.Warn("Runbook action failed", zap.Error(err))
+	} else {
+		logger.Info("Runbook action completed", zap.Any("result", result))
+	}
+	if saveErr := s.runs.Save(run); saveErr != nil {
+		logger.Error("Failed to record runbook run", zap.Error(saveErr))
+	}
+	return run, err
+}
+
+// RebuildProjection resets a projection and replays the event log into it
+func (s *runbookService) RebuildProjection(ctx context.Context, name, actor string) (*models.RunbookRun, error) {
+	return s.record(models.RunbookRebuildProjection, actor, name, nil, func() (interface{}, error) {
+		projection, exists := s.config.Projections[name]
+		if !exists || !s.redeliverer.HasConsumer(name) {
+			return nil, fmt.Errorf("%w: %s", models.ErrProjectionNotFound, name)
+		}
+		events, err := s.products.GetEventsUntil(ctx, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		// Events published while replaying reach the projection twice at most,
+		// which the projections tolerate as they tolerate at-least-once delivery
+		projection.Reset()
+		rebuild := &interfaces.ProjectionRebuild{Projection: name}
+		for _, event := range events {
+			if _, err := s.redeliverer.Redeliver(name, event); err != nil {
+				return rebuild, err
+			}
+			rebuild.Events++
+		}
+		return rebuild, nil
+	})
+}
+
+// ResendFailedDeliveries redelivers the latest event of every product whose
+// delivery to one of the targets last failed within the range
+func (s *runbookService) ResendFailedDeliveries(ctx context.Context, req *interfaces.ResendRequest, actor string) (*models.RunbookRun, error) {
+	params := map[string]interface{}{"targets": req.Targets}
+	if req.From != nil {
+		params["from"] = *req.From
+	}
+	if req.To != nil {
+		params["to"] = *req.To
+	}
+
+	return s.record(models.RunbookResendDeliveries, actor, strings.Join(req.Targets, ","), params, func() (interface{}, error) {
+		if len(req.Targets) == 0 {
+			return nil, fmt.Errorf("%w: at least one target is required", models.ErrInvalidRequest)
+		}
+		if req.From != nil && req.To != nil && req.To.Before(*req.From) {
+			return nil, fmt.Errorf("%w: to is before from", models.ErrInvalidRequest)
+		}
+		consumers := make(map[string]string, len(req.Targets))
+		for _, target := range req.Targets {
+			kind, _, _ := strings.Cut(target, ":")
+			consumer, exists := s.config.DeliveryConsumers[kind]
+			if !exists {
+				return nil, fmt.Errorf("%w: no consumer delivers to %s", models.ErrInvalidRequest, target)
+			}
+			consumers[target] = consumer
+		}
+
+		resend := &interfaces.DeliveryResend{Resent: make([]*interfaces.ResentDelivery, 0)}
+		for _, target := range req.Targets {
+			statuses, err := s.statuses.ListByTarget(target, 0)
+			if err != nil {
+				return resend, err
+			}
+			for _, status := range statuses {
+				if status.State != models.SyncStateFailed ||
+					(req.From != nil && status.UpdatedAt.Before(*req.From)) ||
+					(req.To != nil && status.UpdatedAt.After(*req.To)) {
+					continue
+				}
+				if err := s.redeliverLatest(ctx, consumers[target], status.ProductID); err != nil {
+					resend.Errors = append(resend.Errors, fmt.Sprintf("%s to %s: %v", status.ProductID, target, err))
+					continue
+				}
+				resend.Resent = append(resend.Resent, &interfaces.ResentDelivery{
+					ProductID: status.ProductID,
+					Target:    target,
+					Version:   status.Version,
+				})
+			}
+		}
+		return resend, nil
+	})
+}
+
+// redeliverLatest hands a product's latest event, which for a deleted product is its deletion, to a consumer
+func (s *runbookService) redeliverLatest(ctx context.Context, consumer, productID string) error {
+	events, err := s.products.GetEventsByProductID(ctx, productID, 0)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return models.ErrProductNotFound
+	}
+	_, err = s.redeliverer.Redeliver(consumer, events[len(events)-1])
+	return err
+}
+
+// ReleaseStaleLocks releases the product's locks held for longer than olderThan
+func (s *runbookService) ReleaseStaleLocks(productID string, olderThan time.Duration, actor string) (*models.RunbookRun, error) {
+	if olderThan == 0 {
+		olderThan = DefaultStaleLockAge
+	}
+	params := map[string]interface{}{"older_than": olderThan.String()}
+
+	return s.record(models.RunbookReleaseLocks, actor, productID, params, func() (interface{}, error) {
+		if olderThan < 0 {
+			return nil, fmt.Errorf("%w: older_than cannot be negative", models.ErrInvalidRequest)
+		}
+
+		// The product's write lock is keyed by its ID alone, edit sessions by prefix
+		holders := make([]locks.LockHolder, 0)
+		for _, holder := range s.locks.Holders(productID) {
+			if holder.ResourceID == productID {
+				holders = append(holders, holder)
+			}
+		}
+		holders = append(holders, s.locks.Holders(editSessionResource(productID, ""))...)
+
+		release := &interfaces.LockRelease{ProductID: productID, Released: make([]*interfaces.ReleasedLock, 0)}
+		cutoff := time.Now().Add(-olderThan)
+		for _, holder := range holders {
+			if holder.AcquiredAt.After(cutoff) {
+				continue
+			}
+			if err := s.locks.ReleaseLock(holder.ResourceID); err != nil {
+				return release, err
+			}
+			release.Released = append(release.Released, &interfaces.ReleasedLock{
+				ResourceID: holder.ResourceID,
+				Owner:      holder.Owner,
+				AcquiredAt: holder.AcquiredAt,
+				ExpiresAt:  holder.ExpiresAt,
+			})
+		}
+		return release, nil
+	})
+}
+
+// VerifyEventChain re-verifies the stored events of a product. A broken chain
+// is a successful run with an invalid result, not an error.
+func (s *runbookService) VerifyEventChain(ctx context.Context, productID, actor string) (*models.RunbookRun, error) {
+	return s.record(models.RunbookVerifyEventChain, actor, productID, nil, func() (interface{}, error) {
+		events, err := s.products.GetEventsByProductID(ctx, productID, 0)
+		if err != nil {
+			return nil, err
+		}
+		product, err := s.products.GetByID(ctx, productID)
+		if err != nil && !errors.Is(err, models.ErrProductNotFound) {
+			return nil, err
+		}
+		if len(events) == 0 && product == nil {
+			return nil, models.ErrProductNotFound
+		}
+
+		// Products loaded from a catalog snapshot have no events before it
+		verification := &interfaces.ChainVerification{ProductID: productID, Events: len(events), Valid: true}
+		sorted := append([]*models.Event(nil), events...)
+		sortEventsByVersion(sorted)
+		if err := verifyEventChain(sorted); err != nil {
+			verification.Valid = false
+			verification.Problem = err.Error()
+			return verification, nil
+		}
+		if len(sorted) == 0 {
+			return verification, nil
+		}
+
+		latest := sorted[len(sorted)-1]
+		latestEvent, _ := latest.Data.(*models.ProductEvent)
+		switch {
+		case latest.Type == models.EventProductDeleted && product != nil && !product.IsDeleted():
+			verification.Valid = false
+			verification.Problem = "product is stored although its latest event deleted it"
+		case latest.Type != models.EventProductDeleted && product == nil:
+			verification.Valid = false
+			verification.Problem = fmt.Sprintf("product is missing although its latest event is %s", latest.Type)
+		case latest.Type != models.EventProductDeleted && product.IsDeleted():
+			verification.Valid = false
+			verification.Problem = fmt.Sprintf("product is soft-deleted although its latest event is %s", latest.Type)
+		case product != nil && latestEvent.Product != nil && product.LastHash != latestEvent.Product.LastHash:
+			verification.Valid = false
+			verification.Problem = fmt.Sprintf("stored product hash %s differs from the latest event's %s", product.LastHash, latestEvent.Product.LastHash)
+		}
+		return verification, nil
+	})
+}
+
+// ListRuns returns the audit trail
+func (s *runbookService) ListRuns(limit int) ([]*models.RunbookRun, error) {
+	return s.runs.List(limit)
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/locks"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+// countingProjection counts how often it was reset
+type countingProjection struct {
+	resets int
+}
+
+func (p *countingProjection) Reset() {
+	p.resets++
+}
+
+func setupRunbookService(t *testing.T) (*productService, *runbookService, *recordingRedeliverer, *locks.MemoryLockManager) {
+	productService, _, _ := setupProductService()
+	redeliverer := &recordingRedeliverer{consumers: []string{"dashboard", "marketplaces"}}
+	lockManager := locks.NewMemoryLockManager()
+	t.Cleanup(lockManager.Close)
+
+	service := NewRunbookService(productService.repo, redeliverer, memory.NewSyncStatusRepository(), lockManager, memory.NewRunbookRunRepository(), interfaces.RunbookConfig{
+		Projections:       map[string]interfaces.Projection{"dashboard": &countingProjection{}},
+		DeliveryConsumers: map[string]string{models.SyncTargetMarketplace: "marketplaces"},
+	})
+	return productService, service.(*runbookService), redeliverer, lockManager
+}
+
+func TestRunbookRequiresActor(t *testing.T) {
+	_, service, _, _ := setupRunbookService(t)
+
+	_, err := service.VerifyEventChain(context.Background(), "prod_1", " ")
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	runs, _ := service.ListRuns(0)
+	assert.Empty(t, runs)
+}
+
+func TestRunbookRebuildProjection(t *testing.T) {
+	productService, service, redeliverer, _ := setupRunbookService(t)
+	product := createProductWithColour(t, productService, "SHIRT-1", "Navy")
+	product.BaseTitle = "Updated shirt"
+	assert.NoError(t, productService.UpdateProduct(context.Background(), product))
+
+	run, err := service.RebuildProjection(context.Background(), "dashboard", "oncall@example.com")
+	assert.NoError(t, err)
+	assert.True(t, run.Succeeded)
+	assert.Equal(t, models.RunbookRebuildProjection, run.Action)
+	assert.Equal(t, 2, run.Result.(*interfaces.ProjectionRebuild).Events)
+	assert.Len(t, redeliverer.delivered(), 2)
+	assert.Equal(t, 1, service.config.Projections["dashboard"].(*countingProjection).resets)
+
+	_, err = service.RebuildProjection(context.Background(), "marketplaces", "oncall@example.com")
+	assert.ErrorIs(t, err, models.ErrProjectionNotFound)
+
+	// Failed runs are audited too
+	runs, _ := service.ListRuns(0)
+	assert.Len(t, runs, 2)
+	assert.False(t, runs[0].Succeeded)
+	assert.Equal(t, "oncall@example.com", runs[0].Actor)
+	assert.NotEmpty(t, runs[0].Error)
+}
+
+func TestRunbookResendFailedDeliveries(t *testing.T) {
+	productService, service, redeliverer, _ := setupRunbookService(t)
+	failed := createProductWithColour(t, productService, "SHIRT-1", "Navy")
+	old := createProductWithColour(t, productService, "SHIRT-2", "Navy")
+	synced := createProductWithColour(t, productService, "SHIRT-3", "Navy")
+
+	now := time.Now()
+	target := models.SyncTarget(models.SyncTargetMarketplace, "amazon")
+	service.statuses.Save(&models.SyncStatus{ProductID: failed.ID, Target: target, State: models.SyncStateFailed, Version: 1, UpdatedAt: now})
+	service.statuses.Save(&models.SyncStatus{ProductID: old.ID, Target: target, State: models.SyncStateFailed, Version: 1, UpdatedAt: now.Add(-2 * time.Hour)})
+	service.statuses.Save(&models.SyncStatus{ProductID: synced.ID, Target: target, State: models.SyncStateSynced, Version: 1, UpdatedAt: now})
+
+	from := now.Add(-time.Hour)
+	run, err := service.ResendFailedDeliveries(context.Background(), &interfaces.ResendRequest{Targets: []string{target}, From: &from}, "oncall")
+	assert.NoError(t, err)
+	resend := run.Result.(*interfaces.DeliveryResend)
+	assert.Len(t, resend.Resent, 1)
+	assert.Equal(t, failed.ID, resend.Resent[0].ProductID)
+	assert.Empty(t, resend.Errors)
+	assert.Equal(t, failed.ID, redeliverer.delivered()[0].EntityID)
+}
+
+func TestRunbookResendFailedDeliveriesValidation(t *testing.T) {
+	_, service, _, _ := setupRunbookService(t)
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name string
+		req  *interfaces.ResendRequest
+	}{
+		{"no targets", &interfaces.ResendRequest{}},
+		{"unknown target kind", &interfaces.ResendRequest{Targets: []string{"webhook:orders"}}},
+		{"range backwards", &interfaces.ResendRequest{Targets: []string{"marketplace:amazon"}, From: &now, To: &earlier}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ResendFailedDeliveries(context.Background(), tt.req, "oncall")
+			assert.ErrorIs(t, err, models.ErrInvalidRequest)
+		})
+	}
+}
+
+func TestRunbookReleaseStaleLocks(t *testing.T) {
+	_, service, _, lockManager := setupRunbookService(t)
+	ctx := context.Background()
+	lockManager.AcquireOwnedLock(ctx, "prod_1", "writer", time.Hour)
+	lockManager.AcquireOwnedLock(ctx, editSessionResource("prod_1", "s1"), "ada", time.Hour)
+	lockManager.AcquireOwnedLock(ctx, "prod_10", "writer", time.Hour)
+
+	// Nothing is older than a minute yet
+	run, err := service.ReleaseStaleLocks("prod_1", 0, "oncall")
+	assert.NoError(t, err)
+	assert.Empty(t, run.Result.(*interfaces.LockRelease).Released)
+
+	run, err = service.ReleaseStaleLocks("prod_1", time.Nanosecond, "oncall")
+	assert.NoError(t, err)
+	assert.Len(t, run.Result.(*interfaces.LockRelease).Released, 2)
+	assert.Empty(t, lockManager.Holders("edit-session:prod_1:"))
+	// Locks of other products sharing the prefix are kept
+	remaining := lockManager.Holders("prod_1")
+	assert.Len(t, remaining, 1)
+	assert.Equal(t, "prod_10", remaining[0].ResourceID)
+
+	_, err = service.ReleaseStaleLocks("prod_1", -time.Second, "oncall")
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
+
+func TestRunbookVerifyEventChain(t *testing.T) {
+	productService, service, _, _ := setupRunbookService(t)
+	product := createProductWithColour(t, productService, "SHIRT-1", "Navy")
+	product.BaseTitle = "Updated shirt"
+	assert.NoError(t, productService.UpdateProduct(context.Background(), product))
+
+	run, err := service.VerifyEventChain(context.Background(), product.ID, "oncall")
+	assert.NoError(t, err)
+	verification := run.Result.(*interfaces.ChainVerification)
+	assert.True(t, verification.Valid)
+	assert.Equal(t, 2, verification.Events)
+
+	// A product changed behind the event log's back no longer matches its latest event
+	stored, _ := productService.repo.GetByID(context.Background(), product.ID)
+	tampered := *stored
+	tampered.LastHash = "tampered"
+	productService.repo.Update(context.Background(), &tampered)
+
+	run, err = service.VerifyEventChain(context.Background(), product.ID, "oncall")
+	assert.NoError(t, err)
+	verification = run.Result.(*interfaces.ChainVerification)
+	assert.False(t, verification.Valid)
+	assert.Contains(t, verification.Problem, "tampered")
+
+	_, err = service.VerifyEventChain(context.Background(), "missing", "oncall")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestRunbookVerifyEventChainOfSoftDeletedProduct(t *testing.T) {
+	productService, service, _, _ := setupRunbookService(t)
+	product := createProductWithColour(t, productService, "SHIRT-1", "Navy")
+
+	_, err := productService.SoftDeleteProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	run, err := service.VerifyEventChain(context.Background(), product.ID, "oncall")
+	assert.NoError(t, err)
+	assert.True(t, run.Result.(*interfaces.ChainVerification).Valid)
+
+	_, err = productService.RestoreProduct(context.Background(), product.ID)
+	assert.NoError(t, err)
+	run, err = service.VerifyEventChain(context.Background(), product.ID, "oncall")
+	assert.NoError(t, err)
+	verification := run.Result.(*interfaces.ChainVerification)
+	assert.True(t, verification.Valid, verification.Problem)
+	assert.Equal(t, 3, verification.Events)
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// reindexPageSize is the number of products read per page while reindexing
+const reindexPageSize = 500
+
+// searchService implements the SearchService interface
+type searchService struct {
+	index    interfaces.SearchIndex
+	products interfaces.ProductService
+	statuses repositories.SyncStatusRepository
+
+	// mu serializes event handling, so the sync status of a product is
+	// updated by one event at a time
+	mu sync.Mutex
+}
+
+// NewSearchService creates a search service that keeps the index in sync
+// with the products as their events arrive, recording the outcome as the
+// search sync status of each product. Each event indexes the current state
+// of the product, so events that arrive out of order do not leave a stale
+// version behind.
+func NewSearchService(index interfaces.SearchIndex, products interfaces.ProductService, statuses repositories.SyncStatusRepository, publisher events.EventPublisher) interfaces.SearchService {
+	s := &searchService{
+		index:    index,
+		products: products,
+		statuses: statuses,
+	}
+	for _, eventType := range []models.EventType{models.EventProductCreated, models.EventProductUpdated, models.EventProductDeleted, models.EventProductRestored} {
+		publisher.Subscribe(eventType, s.handleEvent)
+	}
+	return s
+}
+
+// Search ranks the products in the index and loads the page of hits.
+// Products deleted since they were indexed are left out.
+func (s *searchService) Search(ctx context.Context, query models.SearchQuery) ([]*models.ProductSearchResult, int, error) {
+	if err := query.Validate(); err != nil {
+		return nil, 0, err
+	}
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = 10
+	}
+	if query.PageSize > models.MaxSearchPageSize {
+		query.PageSize = models.MaxSearchPageSize
+	}
+
+	hits, err := s.index.Search(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	results := make([]*models.ProductSearchResult, 0, len(hits.Hits))
+	for _, hit := range hits.Hits {
+		product, err := s.products.GetProduct(ctx, hit.ProductID)
+		if errors.Is(err, models.ErrProductNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		results = append(results, &models.ProductSearchResult{Score: hit.Score, Product: product})
+	}
+	return results, hits.Total, nil
+}
+
+// Reindex indexes every product that is not deleted
+func (s *searchService) Reindex(ctx context.Context) (int, error) {
+	indexed := 0
+	for page := 1; ; page++ {
+		products, total, err := s.products.ListProducts(ctx, models.ProductFilter{}, page, reindexPageSize)
+		if err != nil {
+			return indexed, err
+		}
+		for _, product := range products {
+			if err := s.index.Index(product.SearchDocument()); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+		if len(products) == 0 || page*reindexPageSize >= total {
+			return indexed, nil
+		}
+	}
+}
+
+// handleEvent indexes the product an event is about, or removes it once it
+// is deleted
+func (s *searchService) handleEvent(event *models.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	logger := logging.Shared().WithFields(
+		zap.String("index", s.index.Name()),
+		zap.String("product_id", event.EntityID),
+	)
+
+	product, err := s.products.GetProduct(context.Background(), event.EntityID)
+	deleted := errors.Is(err, models.ErrProductNotFound)
+	if err != nil && !deleted {
+		logger.Error("Failed to read product for search index", zap.Error(err))
+		return
+	}
+	version := event.Version
+	if !deleted {
+		version = product.Version
+	}
+
+	status, err := s.statuses.Get(event.EntityID, models.SyncTargetSearch)
+	if errors.Is(err, models.ErrSyncStatusNotFound) {
+		status = models.NewSyncStatus(event.EntityID, models.SyncTargetSearch)
+	} else if err != nil {
+		logger.Error("Failed to read search sync status", zap.Error(err))
+		return
+	}
+	if status.Handled(version) {
+		return
+	}
+	status.Begin(version, time.Now())
+
+	state := models.SyncStateSynced
+	if deleted {
+		state = models.SyncStateRemoved
+		err = s.index.Remove(event.EntityID)
+	} else {
+		err = s.index.Index(product.SearchDocument())
+	}
+	if err != nil {
+		status.Fail(err, time.Now())
+		logger.Warn("Failed to update search index", zap.Error(err))
+	} else {
+		status.Succeed(state, time.Now())
+	}
+	if err := s.statuses.Save(status); err != nil {
+		logger.Error("Failed to save search sync status", zap.Error(err))
+	}
+}
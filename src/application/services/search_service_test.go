@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+	"github.com/jimmitjoo/ecom/src/infrastructure/search"
+)
+
+func setupSearchService(t *testing.T) (*searchService, *productService) {
+	products, _, _ := setupProductService()
+	publisher := new(MockEventPublisher)
+	publisher.On("Subscribe", mock.AnythingOfType("models.EventType"), mock.Anything).Return(nil)
+	analyzers, err := search.NewAnalyzers(nil)
+	assert.NoError(t, err)
+	service := NewSearchService(search.NewMemoryIndex(analyzers), products, memory.NewSyncStatusRepository(), publisher).(*searchService)
+	publisher.AssertNumberOfCalls(t, "Subscribe", 4)
+	return service, products
+}
+
+// lastEvent returns the latest stored event of a product
+func lastEvent(t *testing.T, products *productService, productID string) *models.Event {
+	events, err := products.repo.GetEventsByProductID(context.Background(), productID, 0)
+	assert.NoError(t, err)
+	return events[len(events)-1]
+}
+
+func TestSearchFollowsProductEvents(t *testing.T) {
+	service, products := setupSearchService(t)
+	shirt := createTitledProduct(t, products, "TOP-1", "Blue shirt")
+	service.handleEvent(lastEvent(t, products, shirt.ID))
+
+	results, total, err := service.Search(context.Background(), models.SearchQuery{Text: " shirt "})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, shirt.ID, results[0].Product.ID)
+	assert.Greater(t, results[0].Score, 0.0)
+	status, err := service.statuses.Get(shirt.ID, models.SyncTargetSearch)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStateSynced, status.State)
+	assert.Equal(t, int64(1), status.SyncedVersion)
+
+	// Updates replace the indexed text
+	shirt.BaseTitle = "Red blouse"
+	assert.NoError(t, products.UpdateProduct(context.Background(), shirt))
+	service.handleEvent(lastEvent(t, products, shirt.ID))
+	_, total, err = service.Search(context.Background(), models.SearchQuery{Text: "shirt"})
+	assert.NoError(t, err)
+	assert.Zero(t, total)
+
+	// Deleted products are removed
+	assert.NoError(t, products.DeleteProduct(context.Background(), shirt.ID))
+	service.handleEvent(lastEvent(t, products, shirt.ID))
+	_, total, err = service.Search(context.Background(), models.SearchQuery{Text: "blouse"})
+	assert.NoError(t, err)
+	assert.Zero(t, total)
+	status, err = service.statuses.Get(shirt.ID, models.SyncTargetSearch)
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStateRemoved, status.State)
+}
+
+func TestSearchIgnoresStaleEvents(t *testing.T) {
+	service, products := setupSearchService(t)
+	shirt := createTitledProduct(t, products, "TOP-1", "Blue shirt")
+	created := lastEvent(t, products, shirt.ID)
+	shirt.BaseTitle = "Red blouse"
+	assert.NoError(t, products.UpdateProduct(context.Background(), shirt))
+	service.handleEvent(lastEvent(t, products, shirt.ID))
+
+	// The create event arrives late; the index keeps the current version
+	service.handleEvent(created)
+	status, err := service.statuses.Get(shirt.ID, models.SyncTargetSearch)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), status.SyncedVersion)
+	assert.Equal(t, 1, status.Attempts)
+}
+
+func TestSearchSkipsProductsDeletedSinceIndexed(t *testing.T) {
+	service, products := setupSearchService(t)
+	shirt := createTitledProduct(t, products, "SHIRT-1", "Blue shirt")
+	socks := createTitledProduct(t, products, "SOCKS-1", "Blue socks")
+	indexed, err := service.Reindex(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, indexed)
+
+	assert.NoError(t, products.DeleteProduct(context.Background(), socks.ID))
+	results, _, err := service.Search(context.Background(), models.SearchQuery{Text: "blue", PageSize: 1000})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, shirt.ID, results[0].Product.ID)
+}
+
+func TestSearchValidatesQuery(t *testing.T) {
+	service, _ := setupSearchService(t)
+
+	_, _, err := service.Search(context.Background(), models.SearchQuery{Text: "  "})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
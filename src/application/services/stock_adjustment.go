@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+const (
+	// stockLockWait is how long a stock adjustment waits for a product that is being changed
+	stockLockWait = 2 * time.Second
+	// stockLockRetry is the pause between lock attempts
+	stockLockRetry = 5 * time.Millisecond
+)
+
+// AdjustStock applies stock adjustments to a product in one conditional update,
+// records the change as a product.updated event and publishes a stock.changed
+// event after it. Decrements that would drop
+// a location without backorders below zero fail with models.ErrInsufficientStock
+// and leave the product unchanged.
+func (s *productService) AdjustStock(ctx context.Context, id string, adjustments []models.StockAdjustment) (*models.Product, error) {
+	if len(adjustments) == 0 {
+		return nil, fmt.Errorf("%w: no stock adjustments", models.ErrInvalidRequest)
+	}
+	for _, adjustment := range adjustments {
+		if adjustment.VariantID == "" || adjustment.LocationID == "" {
+			return nil, fmt.Errorf("%w: variant_id and location_id are required", models.ErrInvalidRequest)
+		}
+	}
+
+	// Reservations on a popular product arrive concurrently, so wait for the
+	// lock instead of failing. The lock keeps the event chain in version order;
+	// the repository's compare-and-set is what prevents overselling.
+	if err := s.waitForLock(ctx, id, stockLockWait); err != nil {
+		return nil, err
+	}
+	defer s.locks.ReleaseLock(id)
+	if _, err := s.activeProduct(ctx, id); err != nil {
+		return nil, err
+	}
+
+	// Store the event with the adjustment, so the history never misses one
+	var updated *models.Product
+	var event *models.Event
+	err := s.repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		previous, adjusted, err := tx.AdjustStock(ctx, id, adjustments)
+		if err != nil {
+			return err
+		}
+		updated = adjusted
+
+		event = &models.Event{
+			ID:            uuid.New().String(),
+			Type:          models.EventProductUpdated,
+			EntityID:      updated.ID,
+			Version:       updated.Version,
+			Sequence:      s.getNextSequence(),
+			SchemaVersion: models.EventSchemaVersion,
+			Data: &models.ProductEvent{
+				ProductID: updated.ID,
+				Action:    "stock_adjusted",
+				Product:   updated.Clone(),
+				Version:   updated.Version,
+				PrevHash:  previous.LastHash,
+				Changes:   stockChanges(previous, updated, adjustments),
+			},
+			Timestamp: time.Now(),
+		}
+		if err := tx.StoreEvent(ctx, event); err != nil {
+			return fmt.Errorf("failed to store event: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.publish(event, nil)
+
+	// The stock.changed event is only published; the stored product.updated
+	// event already records the change in the product's history
+	stockEvent := *event
+	stockEvent.ID = uuid.New().String()
+	stockEvent.Type = models.EventStockChanged
+	stockEvent.Sequence = s.getNextSequence()
+	s.publish(&stockEvent, nil)
+
+	return updated, nil
+}
+
+// stockChanges records the old and new quantity of every adjusted location
+func stockChanges(previous, updated *models.Product, adjustments []models.StockAdjustment) []models.Change {
+	changes := make([]models.Change, 0, len(adjustments))
+	seen := make(map[string]bool)
+	for _, adjustment := range adjustments {
+		field := fmt.Sprintf("variants.%s.stock.%s", adjustment.VariantID, adjustment.LocationID)
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
+		changes = append(changes, models.Change{
+			Field:    field,
+			OldValue: stockQuantity(previous, adjustment.VariantID, adjustment.LocationID),
+			NewValue: stockQuantity(updated, adjustment.VariantID, adjustment.LocationID),
+		})
+	}
+	return changes
+}
+
+// stockQuantity returns the quantity of a variant at a location, 0 if it has no entry
+func stockQuantity(product *models.Product, variantID, locationID string) int {
+	for _, variant := range product.Variants {
+		if variant.ID != variantID {
+			continue
+		}
+		for _, stock := range variant.Stock {
+			if stock.LocationID == locationID {
+				return stock.Quantity
+			}
+		}
+	}
+	return 0
+}
+
+// waitForLock retries acquiring the product lock until it succeeds or wait has passed
+func (s *productService) waitForLock(ctx context.Context, id string, wait time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+
+	for {
+		acquired, err := s.acquireLock(ctx, id)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return models.ErrLockFailed
+			}
+			return fmt.Errorf("%w: %v", models.ErrLockFailed, err)
+		}
+		if acquired {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return models.ErrLockFailed
+		case <-time.After(stockLockRetry):
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/locks"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+func createStockedProduct(t *testing.T, service *productService, quantity int) *models.Product {
+	product := createValidProduct()
+	product.Variants = []models.Variant{{
+		ID:         "v1",
+		SKU:        "TEST-123-S",
+		Attributes: map[string]string{"size": "S"},
+		Stock:      []models.Stock{{LocationID: "wh1", Quantity: quantity}},
+	}}
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	return product
+}
+
+func TestAdjustStock(t *testing.T) {
+	service, publisher, _ := setupProductService()
+	product := createStockedProduct(t, service, 5)
+
+	updated, err := service.AdjustStock(context.Background(), product.ID, []models.StockAdjustment{{VariantID: "v1", LocationID: "wh1", Delta: -2}})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, updated.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, product.Version+1, updated.Version)
+
+	events, err := service.repo.GetEventsByProductID(context.Background(), product.ID, 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	data := events[1].Data.(*models.ProductEvent)
+	assert.Equal(t, "stock_adjusted", data.Action)
+	assert.Equal(t, product.LastHash, data.PrevHash)
+	assert.Equal(t, []models.Change{{Field: "variants.v1.stock.wh1", OldValue: 5, NewValue: 3}}, data.Changes)
+
+	// product.created, product.updated and stock.changed
+	publisher.AssertNumberOfCalls(t, "Publish", 3)
+	published := publisher.Calls[2].Arguments.Get(0).(*models.Event)
+	assert.Equal(t, models.EventStockChanged, published.Type)
+	assert.Equal(t, updated.Version, published.Version)
+	assert.NotEqual(t, events[1].ID, published.ID)
+	assert.Equal(t, data.Changes, published.Data.(*models.ProductEvent).Changes)
+
+	// The stored history still verifies after the adjustment
+	_, err = service.ReplayEvents(context.Background(), product.ID, 0)
+	assert.NoError(t, err)
+}
+
+func TestAdjustStockInsufficient(t *testing.T) {
+	service, _, _ := setupProductService()
+	product := createStockedProduct(t, service, 1)
+
+	_, err := service.AdjustStock(context.Background(), product.ID, []models.StockAdjustment{{VariantID: "v1", LocationID: "wh1", Delta: -2}})
+	assert.True(t, errors.Is(err, models.ErrInsufficientStock))
+
+	stored, _ := service.GetProduct(context.Background(), product.ID)
+	assert.Equal(t, 1, stored.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, product.Version, stored.Version)
+}
+
+func TestAdjustStockInvalidRequest(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	_, err := service.AdjustStock(context.Background(), "prod_1", nil)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	_, err = service.AdjustStock(context.Background(), "prod_1", []models.StockAdjustment{{VariantID: "v1", Delta: -1}})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
+
+func TestAdjustStockConcurrentReservations(t *testing.T) {
+	publisher := new(MockEventPublisher)
+	publisher.On("Publish", mock.AnythingOfType("*models.Event")).Return(nil)
+	service := NewProductService(memory.NewProductRepository(), publisher, locks.NewMemoryLockManager(), memory.NewJobRepository()).(*productService)
+	product := createStockedProduct(t, service, 10)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved, insufficient := 0, 0
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.AdjustStock(context.Background(), product.ID, []models.StockAdjustment{{VariantID: "v1", LocationID: "wh1", Delta: -1}})
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				reserved++
+			case errors.Is(err, models.ErrInsufficientStock):
+				insufficient++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, reserved)
+	assert.Equal(t, 20, insufficient)
+	stored, _ := service.GetProduct(context.Background(), product.ID)
+	assert.Equal(t, 0, stored.Variants[0].Stock[0].Quantity)
+
+	_, err := service.ReplayEvents(context.Background(), product.ID, 0)
+	assert.NoError(t, err)
+}
+
+func TestWaitForLockTimesOut(t *testing.T) {
+	service, _, _ := setupProductService()
+	lockManager := new(MockLockManager)
+	lockManager.On("AcquireLock", mock.Anything, "prod_1", mock.AnythingOfType("time.Duration")).Return(false, nil)
+	service.locks = lockManager
+
+	err := service.waitForLock(context.Background(), "prod_1", 20*time.Millisecond)
+	assert.True(t, errors.Is(err, models.ErrLockFailed))
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// maxStockAdjustments is the largest number of adjustments in one AdjustStock call
+const maxStockAdjustments = 1000
+
+// stockService implements the StockService interface
+type stockService struct {
+	products interfaces.ProductService
+}
+
+// NewStockService creates a new stock service instance. Every change goes
+// through the product service's stock adjustment, which prevents negative
+// stock and publishes the change.
+func NewStockService(products interfaces.ProductService) interfaces.StockService {
+	return &stockService{
+		products: products,
+	}
+}
+
+// GetStock returns the stock of a variant of a product
+func (s *stockService) GetStock(ctx context.Context, productID, variantID string) (*models.VariantStock, error) {
+	product, err := s.products.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	return product.VariantStock(variantID)
+}
+
+// SetStock sets a variant's quantities as one adjustment, so the new levels
+// replace whatever concurrent adjustments left behind
+func (s *stockService) SetStock(ctx context.Context, productID, variantID string, levels []models.StockLevel) (*models.VariantStock, error) {
+	if len(levels) == 0 {
+		return nil, fmt.Errorf("%w: no stock levels", models.ErrInvalidRequest)
+	}
+	seen := make(map[string]bool, len(levels))
+	adjustments := make([]models.StockAdjustment, len(levels))
+	for i, level := range levels {
+		if seen[level.LocationID] {
+			return nil, fmt.Errorf("%w: location %s is listed twice", models.ErrInvalidRequest, level.LocationID)
+		}
+		seen[level.LocationID] = true
+		quantity := level.Quantity
+		adjustments[i] = models.StockAdjustment{VariantID: variantID, LocationID: level.LocationID, Quantity: &quantity}
+	}
+
+	updated, err := s.products.AdjustStock(ctx, productID, adjustments)
+	if err != nil {
+		return nil, err
+	}
+	return updated.VariantStock(variantID)
+}
+
+// AdjustStock groups the adjustments per product and applies each group as
+// one stock adjustment. A failed product does not stop the others.
+func (s *stockService) AdjustStock(ctx context.Context, adjustments []models.ProductStockAdjustment) ([]*interfaces.BatchResult, error) {
+	if len(adjustments) == 0 || len(adjustments) > maxStockAdjustments {
+		return nil, fmt.Errorf("%w: between 1 and %d adjustments are required", models.ErrInvalidRequest, maxStockAdjustments)
+	}
+
+	var order []string
+	groups := make(map[string][]models.StockAdjustment)
+	for _, adjustment := range adjustments {
+		if adjustment.ProductID == "" {
+			return nil, fmt.Errorf("%w: product_id is required", models.ErrInvalidRequest)
+		}
+		if _, ok := groups[adjustment.ProductID]; !ok {
+			order = append(order, adjustment.ProductID)
+		}
+		groups[adjustment.ProductID] = append(groups[adjustment.ProductID], adjustment.StockAdjustment)
+	}
+
+	results := make([]*interfaces.BatchResult, len(order))
+	for i, productID := range order {
+		results[i] = &interfaces.BatchResult{ID: productID, Success: true}
+		if _, err := s.products.AdjustStock(ctx, productID, groups[productID]); err != nil {
+			results[i].Success = false
+			results[i].Error = err.Error()
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestStockServiceGetAndSetStock(t *testing.T) {
+	products, _, _ := setupProductService()
+	product := createStockedProduct(t, products, 5)
+	service := NewStockService(products)
+
+	stock, err := service.GetStock(context.Background(), product.ID, "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Stock{{LocationID: "wh1", Quantity: 5}}, stock.Stock)
+	assert.Equal(t, product.Version, stock.Version)
+
+	stock, err = service.SetStock(context.Background(), product.ID, "v1", []models.StockLevel{{LocationID: "wh1", Quantity: 2}, {LocationID: "wh2", Quantity: 8}})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Stock{{LocationID: "wh1", Quantity: 2}, {LocationID: "wh2", Quantity: 8}}, stock.Stock)
+	assert.Equal(t, product.Version+1, stock.Version)
+
+	_, err = service.GetStock(context.Background(), product.ID, "missing")
+	assert.True(t, errors.Is(err, models.ErrVariantNotFound))
+	_, err = service.GetStock(context.Background(), "missing", "v1")
+	assert.True(t, errors.Is(err, models.ErrProductNotFound))
+}
+
+func TestStockServiceSetStockRejectsNegativeAndDuplicates(t *testing.T) {
+	products, _, _ := setupProductService()
+	product := createStockedProduct(t, products, 5)
+	service := NewStockService(products)
+
+	_, err := service.SetStock(context.Background(), product.ID, "v1", []models.StockLevel{{LocationID: "wh1", Quantity: -1}})
+	assert.True(t, errors.Is(err, models.ErrInsufficientStock))
+
+	_, err = service.SetStock(context.Background(), product.ID, "v1", []models.StockLevel{{LocationID: "wh1", Quantity: 1}, {LocationID: "wh1", Quantity: 2}})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	_, err = service.SetStock(context.Background(), product.ID, "v1", nil)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	stored, _ := products.GetProduct(context.Background(), product.ID)
+	assert.Equal(t, 5, stored.Variants[0].Stock[0].Quantity)
+}
+
+func TestStockServiceAdjustStockPerProduct(t *testing.T) {
+	products, _, _ := setupProductService()
+	first := createStockedProduct(t, products, 5)
+	second := createStockedProduct(t, products, 1)
+	service := NewStockService(products)
+
+	results, err := service.AdjustStock(context.Background(), []models.ProductStockAdjustment{
+		{ProductID: first.ID, StockAdjustment: models.StockAdjustment{VariantID: "v1", LocationID: "wh1", Delta: -2}},
+		{ProductID: second.ID, StockAdjustment: models.StockAdjustment{VariantID: "v1", LocationID: "wh1", Delta: 3}},
+		{ProductID: first.ID, StockAdjustment: models.StockAdjustment{VariantID: "v1", LocationID: "wh1", Delta: -1}},
+		{ProductID: second.ID, StockAdjustment: models.StockAdjustment{VariantID: "v1", LocationID: "wh1", Delta: -5}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, first.ID, results[0].ID)
+	assert.True(t, results[0].Success)
+	assert.Equal(t, second.ID, results[1].ID)
+	assert.False(t, results[1].Success)
+	assert.Contains(t, results[1].Error, "insufficient")
+
+	// Both adjustments of the first product applied, none of the second's
+	stored, _ := products.GetProduct(context.Background(), first.ID)
+	assert.Equal(t, 2, stored.Variants[0].Stock[0].Quantity)
+	stored, _ = products.GetProduct(context.Background(), second.ID)
+	assert.Equal(t, 1, stored.Variants[0].Stock[0].Quantity)
+}
+
+func TestStockServiceAdjustStockInvalidRequest(t *testing.T) {
+	products, _, _ := setupProductService()
+	service := NewStockService(products)
+
+	_, err := service.AdjustStock(context.Background(), nil)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	_, err = service.AdjustStock(context.Background(), []models.ProductStockAdjustment{{StockAdjustment: models.StockAdjustment{VariantID: "v1", LocationID: "wh1", Delta: 1}}})
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// syncStatusService implements the SyncStatusService interface
+type syncStatusService struct {
+	products repositories.ProductRepository
+	statuses repositories.SyncStatusRepository
+}
+
+// NewSyncStatusService creates a new sync status service instance
+func NewSyncStatusService(products repositories.ProductRepository, statuses repositories.SyncStatusRepository) interfaces.SyncStatusService {
+	return &syncStatusService{
+		products: products,
+		statuses: statuses,
+	}
+}
+
+// GetSyncStatus returns a product's status per target alongside its current version
+func (s *syncStatusService) GetSyncStatus(ctx context.Context, productID string) (*interfaces.ProductSyncStatus, error) {
+	statuses, err := s.statuses.ListByProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &interfaces.ProductSyncStatus{ProductID: productID, Targets: statuses}
+	product, err := s.products.GetByID(ctx, productID)
+	switch {
+	case err == nil:
+		result.Version = product.Version
+	case errors.Is(err, models.ErrProductNotFound) && len(statuses) > 0:
+		result.Deleted = true
+	default:
+		return nil, err
+	}
+	return result, nil
+}
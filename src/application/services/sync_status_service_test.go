@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+func TestSyncStatusServiceGetSyncStatus(t *testing.T) {
+	products := memory.NewProductRepository()
+	statuses := memory.NewSyncStatusRepository()
+	service := NewSyncStatusService(products, statuses)
+
+	product := createValidProduct()
+	product.ID = "prod_1"
+	product.Version = 3
+	assert.NoError(t, products.Create(context.Background(), product))
+	assert.NoError(t, statuses.Save(&models.SyncStatus{ProductID: "prod_1", Target: "search", State: models.SyncStateSynced, SyncedVersion: 3}))
+	assert.NoError(t, statuses.Save(&models.SyncStatus{ProductID: "prod_1", Target: "marketplace:amazon", State: models.SyncStateFailed, SyncedVersion: 2}))
+
+	status, err := service.GetSyncStatus(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), status.Version)
+	assert.False(t, status.Deleted)
+	assert.Len(t, status.Targets, 2)
+	assert.Equal(t, "marketplace:amazon", status.Targets[0].Target)
+}
+
+func TestSyncStatusServiceDeletedProducts(t *testing.T) {
+	statuses := memory.NewSyncStatusRepository()
+	service := NewSyncStatusService(memory.NewProductRepository(), statuses)
+
+	_, err := service.GetSyncStatus(context.Background(), "prod_1")
+	assert.True(t, errors.Is(err, models.ErrProductNotFound))
+
+	// Targets that still know about a deleted product keep it visible
+	assert.NoError(t, statuses.Save(&models.SyncStatus{ProductID: "prod_1", Target: "marketplace:zalando", State: models.SyncStateRemoved}))
+	status, err := service.GetSyncStatus(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.True(t, status.Deleted)
+	assert.Len(t, status.Targets, 1)
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// PreviewTextReplacement shows what a replacement would change without writing anything
+func (s *productService) PreviewTextReplacement(ctx context.Context, replacement *models.TextReplacement) (*models.TextReplacementPreview, error) {
+	if err := replacement.Validate(); err != nil {
+		return nil, err
+	}
+
+	preview := &models.TextReplacementPreview{Products: make([]*models.TextReplacementDiff, 0)}
+	if _, err := s.scanCatalog(ctx, func(product *models.Product) bool {
+		if !replacement.Filter.Matches(product) {
+			return false
+		}
+		_, changes := replacement.Apply(product)
+		if len(changes) == 0 {
+			return false
+		}
+		preview.Matched++
+		if len(preview.Products) < models.MaxTextReplacementPreview {
+			preview.Products = append(preview.Products, &models.TextReplacementDiff{
+				ProductID: product.ID,
+				SKU:       product.SKU,
+				Changes:   changes,
+			})
+		}
+		return false
+	}); err != nil {
+		return nil, err
+	}
+	preview.Truncated = preview.Matched > len(preview.Products)
+	return preview, nil
+}
+
+// ReplaceText starts a job that applies a replacement to the selected products
+// in the background and returns the job right away. Each changed product is
+// written as a regular update tagged with the job, so the job can be rolled back.
+func (s *productService) ReplaceText(ctx context.Context, replacement *models.TextReplacement) (*models.Job, error) {
+	if err := replacement.Validate(); err != nil {
+		return nil, err
+	}
+
+	job, err := s.startJob(models.JobTextReplace, 0)
+	if err != nil {
+		return nil, err
+	}
+	started := *job
+
+	go s.runTextReplacement(context.WithoutCancel(ctx), job, replacement)
+	return &started, nil
+}
+
+// runTextReplacement rewrites the products the replacement changes and records the outcome on the job
+func (s *productService) runTextReplacement(ctx context.Context, job *models.Job, replacement *models.TextReplacement) {
+	logger := logging.Shared().WithFields(zap.String("job_id", job.ID), zap.String("find", replacement.Find))
+
+	matched, err := s.scanCatalog(ctx, func(product *models.Product) bool {
+		if !replacement.Filter.Matches(product) {
+			return false
+		}
+		_, changes := replacement.Apply(product)
+		return len(changes) > 0
+	})
+	if err != nil {
+		logger.Error("Failed to scan catalog for text replacement", zap.Error(err))
+		job.AddError(err.Error())
+		job.Complete(0, 1)
+		s.jobs.Update(job)
+		return
+	}
+
+	// Publish the total so progress is visible while the job runs
+	job.Total = len(matched)
+	s.jobs.Update(job)
+
+	results := make([]*interfaces.BatchResult, 0, len(matched))
+	for _, product := range matched {
+		result := &interfaces.BatchResult{ID: product.ID, Success: true}
+		if err := s.replaceProductText(ctx, product.ID, replacement, job.ID); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+			job.AddError(fmt.Sprintf("%s: %v", product.ID, err))
+		}
+		results = append(results, result)
+	}
+
+	if err := s.finishJob(job, results); err != nil {
+		logger.Error("Failed to record text replacement", zap.Error(err))
+		return
+	}
+	logger.Info("Text replacement completed",
+		zap.Int("succeeded", job.Succeeded),
+		zap.Int("failed", job.Failed),
+	)
+}
+
+// replaceProductText applies the replacement to the current state of a product and publishes the update
+func (s *productService) replaceProductText(ctx context.Context, id string, replacement *models.TextReplacement, jobID string) error {
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	replaced, changes := replacement.Apply(current)
+	if len(changes) == 0 {
+		return nil
+	}
+	return s.publish(s.updateProduct(ctx, replaced, "text_replaced", jobID))
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTitledProduct(t *testing.T, service *productService, sku, title string) *models.Product {
+	product := createValidProduct()
+	product.SKU = sku
+	product.BaseTitle = title
+	assert.NoError(t, service.CreateProduct(context.Background(), product))
+	return product
+}
+
+func TestPreviewTextReplacement(t *testing.T) {
+	service, _, _ := setupProductService()
+	shirt := createTitledProduct(t, service, "SHIRT-1", "Colour shirt")
+	createTitledProduct(t, service, "SOCK-1", "Colour sock")
+	createTitledProduct(t, service, "SHIRT-2", "Plain shirt")
+
+	preview, err := service.PreviewTextReplacement(context.Background(), &models.TextReplacement{
+		Find:    "Colour",
+		Replace: "Color",
+		Fields:  []string{models.TextFieldBaseTitle},
+		Filter:  models.CatalogFilter{SKUPrefix: "SHIRT"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, preview.Matched)
+	assert.False(t, preview.Truncated)
+	if assert.Len(t, preview.Products, 1) {
+		assert.Equal(t, shirt.ID, preview.Products[0].ProductID)
+		assert.Equal(t, []models.TextChange{{Field: "base_title", Old: "Colour shirt", New: "Color shirt"}}, preview.Products[0].Changes)
+	}
+
+	// Nothing is written by a preview
+	stored, _ := service.GetProduct(context.Background(), shirt.ID)
+	assert.Equal(t, "Colour shirt", stored.BaseTitle)
+	assert.Equal(t, int64(1), stored.Version)
+}
+
+func TestReplaceText(t *testing.T) {
+	service, _, _ := setupProductService()
+	shirt := createTitledProduct(t, service, "SHIRT-1", "Colour shirt")
+	plain := createTitledProduct(t, service, "SHIRT-2", "Plain shirt")
+
+	started, err := service.ReplaceText(context.Background(), &models.TextReplacement{Find: "colour", Replace: "color", IgnoreCase: true})
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobTextReplace, started.Type)
+
+	job := waitForJob(t, service, started.ID)
+	assert.Equal(t, models.JobStatusCompleted, job.Status)
+	assert.Equal(t, 1, job.Total)
+	assert.Equal(t, 1, job.Succeeded)
+
+	replaced, err := service.GetProduct(context.Background(), shirt.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "color shirt", replaced.BaseTitle)
+	assert.Equal(t, int64(2), replaced.Version)
+
+	events, err := service.repo.GetEventsByProductID(context.Background(), shirt.ID, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.Equal(t, models.EventProductUpdated, events[0].Type)
+		assert.Equal(t, job.ID, events[0].JobID)
+		assert.Equal(t, "text_replaced", events[0].Data.(*models.ProductEvent).Action)
+	}
+
+	untouched, _ := service.GetProduct(context.Background(), plain.ID)
+	assert.Equal(t, int64(1), untouched.Version)
+
+	_, err = service.RollbackJob(context.Background(), started.ID)
+	assert.NoError(t, err)
+	reverted, _ := service.GetProduct(context.Background(), shirt.ID)
+	assert.Equal(t, "Colour shirt", reverted.BaseTitle)
+}
+
+func TestReplaceTextInvalid(t *testing.T) {
+	service, _, _ := setupProductService()
+
+	_, err := service.ReplaceText(context.Background(), &models.TextReplacement{Find: "[", Regex: true})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.PreviewTextReplacement(context.Background(), &models.TextReplacement{})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	jobs, _ := service.jobs.List(0)
+	assert.Empty(t, jobs)
+}
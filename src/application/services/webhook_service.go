@@ -0,0 +1,99 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// webhookService implements the WebhookService interface
+type webhookService struct {
+	webhooks repositories.WebhookRepository
+}
+
+// NewWebhookService creates a new webhook service instance
+func NewWebhookService(webhooks repositories.WebhookRepository) interfaces.WebhookService {
+	return &webhookService{
+		webhooks: webhooks,
+	}
+}
+
+// Register stores a new endpoint with a generated ID
+func (s *webhookService) Register(endpoint *models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	registered := *endpoint
+	if registered.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %v", err)
+		}
+		registered.Secret = secret
+	}
+	if err := registered.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	registered.ID = uuid.New().String()
+	registered.Disabled = false
+	registered.CreatedAt = now
+	registered.UpdatedAt = now
+	if err := s.webhooks.Save(&registered); err != nil {
+		return nil, fmt.Errorf("failed to save webhook endpoint: %v", err)
+	}
+	return &registered, nil
+}
+
+// List returns every endpoint
+func (s *webhookService) List() ([]*models.WebhookEndpoint, error) {
+	return s.webhooks.List()
+}
+
+// Get returns an endpoint
+func (s *webhookService) Get(id string) (*models.WebhookEndpoint, error) {
+	return s.webhooks.Get(id)
+}
+
+// SetDisabled stops or resumes deliveries to an endpoint
+func (s *webhookService) SetDisabled(id string, disabled bool) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.webhooks.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Disabled == disabled {
+		return endpoint, nil
+	}
+	endpoint.Disabled = disabled
+	endpoint.UpdatedAt = time.Now()
+	if err := s.webhooks.Save(endpoint); err != nil {
+		return nil, fmt.Errorf("failed to save webhook endpoint: %v", err)
+	}
+	return endpoint, nil
+}
+
+// Delete removes an endpoint
+func (s *webhookService) Delete(id string) error {
+	return s.webhooks.Delete(id)
+}
+
+// Deliveries returns the latest delivery attempts of an endpoint
+func (s *webhookService) Deliveries(id string, limit int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.webhooks.Get(id); err != nil {
+		return nil, err
+	}
+	return s.webhooks.ListDeliveries(id, limit)
+}
+
+// generateWebhookSecret returns 32 random bytes, hex-encoded
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+func TestRegisterWebhook(t *testing.T) {
+	service := NewWebhookService(memory.NewWebhookRepository())
+
+	endpoint, err := service.Register(&models.WebhookEndpoint{
+		URL:        "https://erp.example.com/hooks",
+		EventTypes: []models.EventType{models.EventProductUpdated},
+		Disabled:   true,
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, endpoint.ID)
+	assert.Len(t, endpoint.Secret, 64)
+	assert.False(t, endpoint.Disabled)
+
+	stored, err := service.Get(endpoint.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, endpoint.Secret, stored.Secret)
+
+	_, err = service.Register(&models.WebhookEndpoint{URL: "https://erp.example.com/hooks", Secret: "short"})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	_, err = service.Register(&models.WebhookEndpoint{URL: "https://erp.example.com/hooks", EventTypes: []models.EventType{"order.created"}})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+
+	endpoints, err := service.List()
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 1)
+}
+
+func TestDisableAndDeleteWebhook(t *testing.T) {
+	repo := memory.NewWebhookRepository()
+	service := NewWebhookService(repo)
+	endpoint, err := service.Register(&models.WebhookEndpoint{URL: "https://erp.example.com/hooks"})
+	assert.NoError(t, err)
+
+	disabled, err := service.SetDisabled(endpoint.ID, true)
+	assert.NoError(t, err)
+	assert.True(t, disabled.Disabled)
+	stored, _ := service.Get(endpoint.ID)
+	assert.True(t, stored.Disabled)
+
+	assert.NoError(t, repo.AddDelivery(&models.WebhookDelivery{ID: "d1", EndpointID: endpoint.ID}))
+	deliveries, err := service.Deliveries(endpoint.ID, 10)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+
+	assert.NoError(t, service.Delete(endpoint.ID))
+	_, err = service.Deliveries(endpoint.ID, 10)
+	assert.ErrorIs(t, err, models.ErrWebhookNotFound)
+	_, err = service.SetDisabled(endpoint.ID, false)
+	assert.ErrorIs(t, err, models.ErrWebhookNotFound)
+	assert.ErrorIs(t, service.Delete(endpoint.ID), models.ErrWebhookNotFound)
+}
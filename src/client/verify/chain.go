@@ -0,0 +1,92 @@
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// ErrBrokenChain is returned when an event does not follow from the one before it
+var ErrBrokenChain = errors.New("event chain is broken")
+
+// Event is a product event as delivered by webhooks and the WebSocket stream
+type Event struct {
+	ID            string              `json:"id"`
+	Type          models.EventType    `json:"type"`
+	EntityID      string              `json:"entity_id"`
+	Version       int64               `json:"version"`
+	Sequence      int64               `json:"sequence"`
+	SchemaVersion int                 `json:"schema_version"`
+	Data          models.ProductEvent `json:"data"`
+	JobID         string              `json:"job_id,omitempty"`
+	Timestamp     time.Time           `json:"timestamp"`
+}
+
+// DecodeEvent parses a single event, e.g. a verified webhook body. Events in a
+// schema version the client does not read fail with ErrUnsupportedSchema.
+func DecodeEvent(data []byte) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("invalid event: %v", err)
+	}
+	if err := CheckEventSchema(&event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// VerifyEvent checks that the product carried by an event hashes to its last_hash,
+// i.e. that the state was not altered after the event was written
+func VerifyEvent(event *Event) error {
+	product := event.Data.Product
+	if product == nil {
+		return fmt.Errorf("%w: event %s has no product", ErrBrokenChain, event.ID)
+	}
+	if event.Type == models.EventProductDeleted {
+		// A deletion carries the state that was removed, which must be the state it follows
+		if product.LastHash != event.Data.PrevHash {
+			return fmt.Errorf("%w: deletion %s does not carry the state it removes", ErrBrokenChain, event.ID)
+		}
+	} else if event.Data.Version != product.Version {
+		return fmt.Errorf("%w: event %s is version %d but carries version %d", ErrBrokenChain, event.ID, event.Data.Version, product.Version)
+	}
+	if hash := product.CalculateHash(); hash != product.LastHash {
+		return fmt.Errorf("%w: product in event %s hashes to %s, not %s", ErrBrokenChain, event.ID, hash, product.LastHash)
+	}
+	return nil
+}
+
+// VerifyChain checks every event and that each one links to the previous event
+// of the same product: versions increase by one and prev_hash is the previous
+// state's hash. Events must be in version order per product; products may be
+// interleaved. A chain may start at any version, but a chain starting at
+// version 1 must have an empty prev_hash.
+func VerifyChain(events []*Event) error {
+	previous := make(map[string]*Event)
+	for _, event := range events {
+		if err := VerifyEvent(event); err != nil {
+			return err
+		}
+
+		last, seen := previous[event.EntityID]
+		switch {
+		case seen && event.Data.Version != last.Data.Version+1:
+			return fmt.Errorf("%w: %s version %d follows version %d", ErrBrokenChain, event.EntityID, event.Data.Version, last.Data.Version)
+		case seen && event.Data.PrevHash != stateHash(last):
+			return fmt.Errorf("%w: %s version %d does not link to version %d", ErrBrokenChain, event.EntityID, event.Data.Version, last.Data.Version)
+		case !seen && event.Data.Version == 1 && event.Data.PrevHash != "":
+			return fmt.Errorf("%w: %s version 1 has a previous hash", ErrBrokenChain, event.EntityID)
+		}
+		previous[event.EntityID] = event
+	}
+	return nil
+}
+
+// stateHash is the hash of the state an event leaves the product in. After a
+// deletion that is the deleted state, which a restore links to.
+func stateHash(event *Event) string {
+	return event.Data.Product.LastHash
+}
@@ -0,0 +1,85 @@
+package verify
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// productChain builds the created, updated and deleted events of a product the
+// way the service writes them, then round-trips them through JSON like a consumer receives them
+func productChain(t *testing.T) []*Event {
+	product := &models.Product{
+		ID:        "prod_1",
+		SKU:       "SHIRT-1",
+		BaseTitle: "Shirt",
+		Prices:    []models.Price{{Currency: "SEK", Amount: 299.5}},
+		Variants: []models.Variant{{
+			ID: "v1", SKU: "SHIRT-1-M",
+			Attributes: map[string]string{"size": "M", "color": "blue"},
+			Stock:      []models.Stock{{LocationID: "wh1", Quantity: 3}},
+		}},
+		Metadata:  []models.MarketMetadata{{Market: "SE", Title: "Skjorta <ny>"}},
+		CreatedAt: time.Now(),
+		Version:   1,
+	}
+	product.LastHash = product.CalculateHash()
+	created := &models.Event{ID: "evt_1", Type: models.EventProductCreated, EntityID: product.ID, Version: 1,
+		Data: &models.ProductEvent{ProductID: product.ID, Action: "created", Product: product.Clone(), Version: 1}}
+
+	updated := product.Clone()
+	updated.Prices[0].Amount = 249
+	updated.Version = 2
+	updated.LastHash = updated.CalculateHash()
+	update := &models.Event{ID: "evt_2", Type: models.EventProductUpdated, EntityID: product.ID, Version: 2,
+		Data: &models.ProductEvent{ProductID: product.ID, Action: "updated", Product: updated.Clone(), Version: 2, PrevHash: product.LastHash}}
+
+	deletion := &models.Event{ID: "evt_3", Type: models.EventProductDeleted, EntityID: product.ID, Version: 3,
+		Data: &models.ProductEvent{ProductID: product.ID, Action: "deleted", Product: updated.Clone(), Version: 3, PrevHash: updated.LastHash}}
+
+	events := make([]*Event, 0, 3)
+	for _, event := range []*models.Event{created, update, deletion} {
+		data, err := json.Marshal(event)
+		assert.NoError(t, err)
+		decoded, err := DecodeEvent(data)
+		assert.NoError(t, err)
+		events = append(events, decoded)
+	}
+	return events
+}
+
+func TestVerifyChain(t *testing.T) {
+	assert.NoError(t, VerifyChain(productChain(t)))
+}
+
+func TestVerifyChainDetectsTamperedState(t *testing.T) {
+	events := productChain(t)
+	events[1].Data.Product.Prices[0].Amount = 1
+
+	err := VerifyChain(events)
+	assert.ErrorIs(t, err, ErrBrokenChain)
+	assert.Contains(t, err.Error(), "evt_2")
+}
+
+func TestVerifyChainDetectsMissingEvent(t *testing.T) {
+	events := productChain(t)
+
+	err := VerifyChain([]*Event{events[0], events[2]})
+	assert.ErrorIs(t, err, ErrBrokenChain)
+	assert.Contains(t, err.Error(), "version 3 follows version 1")
+}
+
+func TestVerifyChainDetectsBrokenLink(t *testing.T) {
+	events := productChain(t)
+	events[1].Data.PrevHash = "forged"
+
+	assert.ErrorIs(t, VerifyChain(events), ErrBrokenChain)
+}
+
+func TestVerifyChainStartingMidway(t *testing.T) {
+	events := productChain(t)
+	assert.NoError(t, VerifyChain(events[1:]))
+}
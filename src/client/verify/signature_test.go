@@ -0,0 +1,68 @@
+package verify
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("whsec_test")
+	body := []byte(`{"id":"evt_1"}`)
+	header := Sign(secret, time.Now(), body)
+
+	assert.NoError(t, VerifySignature(secret, header, body, DefaultTolerance))
+	assert.ErrorIs(t, VerifySignature([]byte("other"), header, body, DefaultTolerance), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature(secret, header, []byte(`{"id":"evt_2"}`), DefaultTolerance), ErrInvalidSignature)
+}
+
+func TestVerifySignatureKnownValue(t *testing.T) {
+	// Fixed vector so implementations in other languages can check themselves against it
+	header := Sign([]byte("secret"), time.Unix(1700000000, 0), []byte("{}"))
+	assert.Equal(t, "t=1700000000,v1=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163", header)
+}
+
+func TestVerifySignatureTolerance(t *testing.T) {
+	secret := []byte("whsec_test")
+	body := []byte("{}")
+	header := Sign(secret, time.Now().Add(-10*time.Minute), body)
+
+	assert.ErrorIs(t, VerifySignature(secret, header, body, DefaultTolerance), ErrSignatureExpired)
+	assert.NoError(t, VerifySignature(secret, header, body, 0))
+}
+
+func TestVerifySignatureDuringSecretRotation(t *testing.T) {
+	body := []byte("{}")
+	now := time.Now()
+	oldHeader := Sign([]byte("old"), now, body)
+	newHeader := Sign([]byte("new"), now, body)
+	header := oldHeader + "," + newHeader[len("t=1700000000,"):]
+
+	assert.NoError(t, VerifySignature([]byte("old"), header, body, DefaultTolerance))
+	assert.NoError(t, VerifySignature([]byte("new"), header, body, DefaultTolerance))
+}
+
+func TestVerifySignatureMalformed(t *testing.T) {
+	secret := []byte("whsec_test")
+	assert.ErrorIs(t, VerifySignature(secret, "", nil, 0), ErrMissingSignature)
+	assert.ErrorIs(t, VerifySignature(secret, "v1=abc", nil, 0), ErrInvalidSignature)
+	assert.ErrorIs(t, VerifySignature(secret, "t=soon,v1=abc", nil, 0), ErrInvalidSignature)
+}
+
+func TestVerifyRequest(t *testing.T) {
+	secret := []byte("whsec_test")
+	body := []byte(`{"id":"evt_1"}`)
+
+	req := httptest.NewRequest("POST", "/hooks/catalog", bytes.NewReader(body))
+	req.Header.Set(SignatureHeader, Sign(secret, time.Now(), body))
+	verified, err := VerifyRequest(req, secret)
+	assert.NoError(t, err)
+	assert.Equal(t, body, verified)
+
+	req = httptest.NewRequest("POST", "/hooks/catalog", bytes.NewReader(body))
+	_, err = VerifyRequest(req, secret)
+	assert.ErrorIs(t, err, ErrMissingSignature)
+}
@@ -0,0 +1,109 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// SupportedSchemaVersions are the event schema versions this client reads
+var SupportedSchemaVersions = []int{1}
+
+var (
+	// ErrIncompatibleServer is returned when the server and client share no event schema version
+	ErrIncompatibleServer = errors.New("server event schema is not compatible")
+	// ErrUnsupportedSchema is returned for an event in a schema version the client does not read
+	ErrUnsupportedSchema = errors.New("unsupported event schema version")
+)
+
+// Compatibility is the outcome of negotiating with a server
+type Compatibility struct {
+	Server *models.VersionInfo
+	// Schema is the newest event schema version both sides read
+	Schema int
+	// Warnings describe mismatches that do not break the client yet, e.g. a
+	// server that publishes a newer schema than the client reads
+	Warnings []string
+}
+
+// FetchVersion reads GET /version from the server at baseURL
+func FetchVersion(ctx context.Context, client *http.Client, baseURL string) (*models.VersionInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+"/version", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch server version: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch server version: status %d", resp.StatusCode)
+	}
+
+	var info models.VersionInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("invalid server version: %v", err)
+	}
+	return &info, nil
+}
+
+// Negotiate fetches the server version and checks it with CheckCompatibility
+func Negotiate(ctx context.Context, client *http.Client, baseURL string) (*Compatibility, error) {
+	info, err := FetchVersion(ctx, client, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return CheckCompatibility(info)
+}
+
+// CheckCompatibility picks the newest event schema version both the server and
+// this client read. It fails with ErrIncompatibleServer when there is none,
+// and warns when the server publishes events the client cannot read.
+func CheckCompatibility(info *models.VersionInfo) (*Compatibility, error) {
+	compatibility := &Compatibility{Server: info}
+	for _, version := range info.EventSchema.Supported {
+		if supportsSchema(version) && version > compatibility.Schema {
+			compatibility.Schema = version
+		}
+	}
+	if compatibility.Schema == 0 {
+		return compatibility, fmt.Errorf("%w: server %s reads versions %v, client reads %v",
+			ErrIncompatibleServer, info.Version, info.EventSchema.Supported, SupportedSchemaVersions)
+	}
+
+	if current := info.EventSchema.Current; !supportsSchema(current) {
+		compatibility.Warnings = append(compatibility.Warnings, fmt.Sprintf(
+			"server %s publishes event schema %d, client reads %v: upgrade the client before the server's older schemas are dropped",
+			info.Version, current, SupportedSchemaVersions))
+	}
+	return compatibility, nil
+}
+
+// CheckEventSchema returns ErrUnsupportedSchema for an event this client cannot
+// read. Events without a schema version are version 1.
+func CheckEventSchema(event *Event) error {
+	version := event.SchemaVersion
+	if version == 0 {
+		version = 1
+	}
+	if !supportsSchema(version) {
+		return fmt.Errorf("%w: event %s is version %d, client reads %v", ErrUnsupportedSchema, event.ID, version, SupportedSchemaVersions)
+	}
+	return nil
+}
+
+// supportsSchema reports whether the client reads an event schema version
+func supportsSchema(version int) bool {
+	for _, supported := range SupportedSchemaVersions {
+		if supported == version {
+			return true
+		}
+	}
+	return false
+}
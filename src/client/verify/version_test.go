@@ -0,0 +1,68 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func versionInfo(current int, supported ...int) *models.VersionInfo {
+	return &models.VersionInfo{
+		BuildInfo:   &models.BuildInfo{Version: "v1.4.0"},
+		EventSchema: models.EventSchemaCompatibility{Current: current, Supported: supported},
+	}
+}
+
+func TestCheckCompatibility(t *testing.T) {
+	defer func(supported []int) { SupportedSchemaVersions = supported }(SupportedSchemaVersions)
+	SupportedSchemaVersions = []int{1, 2}
+
+	compatibility, err := CheckCompatibility(versionInfo(2, 1, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, compatibility.Schema)
+	assert.Empty(t, compatibility.Warnings)
+
+	// An older server is read in its schema
+	compatibility, err = CheckCompatibility(versionInfo(1, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, compatibility.Schema)
+	assert.Empty(t, compatibility.Warnings)
+
+	// A newer server still reading an old schema works, with a warning
+	compatibility, err = CheckCompatibility(versionInfo(3, 2, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, compatibility.Schema)
+	assert.Len(t, compatibility.Warnings, 1)
+
+	_, err = CheckCompatibility(versionInfo(4, 3, 4))
+	assert.ErrorIs(t, err, ErrIncompatibleServer)
+}
+
+func TestNegotiate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/version", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(versionInfo(1, 1))
+	}))
+	defer server.Close()
+
+	compatibility, err := Negotiate(context.Background(), server.Client(), server.URL+"/")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, compatibility.Schema)
+	assert.Equal(t, "v1.4.0", compatibility.Server.Version)
+}
+
+func TestCheckEventSchema(t *testing.T) {
+	assert.NoError(t, CheckEventSchema(&Event{ID: "e1", SchemaVersion: 1}))
+	assert.NoError(t, CheckEventSchema(&Event{ID: "e1"}))
+	assert.ErrorIs(t, CheckEventSchema(&Event{ID: "e1", SchemaVersion: 2}), ErrUnsupportedSchema)
+
+	_, err := DecodeEvent([]byte(`{"id": "e1", "schema_version": 2}`))
+	assert.ErrorIs(t, err, ErrUnsupportedSchema)
+}
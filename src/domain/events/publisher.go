@@ -7,6 +7,10 @@ type EventPublisher interface {
 	// Publish sends an event to all subscribers
 	Publish(event *models.Event) error
 
+	// PublishBatch sends several events in one call. The returned slice is
+	// aligned with events and holds nil for each event published successfully.
+	PublishBatch(events []*models.Event) []error
+
 	// Subscribe registers a handler for a specific event type
 	Subscribe(eventType models.EventType, handler func(*models.Event)) error
 
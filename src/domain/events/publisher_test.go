@@ -23,6 +23,14 @@ func (m *MockEventPublisher) Publish(event *models.Event) error {
 	return nil
 }
 
+func (m *MockEventPublisher) PublishBatch(events []*models.Event) []error {
+	m.publishCalled = true
+	if len(events) > 0 {
+		m.lastEvent = events[len(events)-1]
+	}
+	return make([]error, len(events))
+}
+
 func (m *MockEventPublisher) Subscribe(eventType models.EventType, handler func(*models.Event)) error {
 	m.subscribeCalled = true
 	m.lastEventType = eventType
@@ -0,0 +1,401 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AllocationStrategy decides which locations fulfil a requested quantity first
+type AllocationStrategy string
+
+const (
+	// AllocationPriority takes stock from the policy's locations in the listed order
+	AllocationPriority AllocationStrategy = "priority"
+	// AllocationNearest takes stock from the locations closest to the destination first
+	AllocationNearest AllocationStrategy = "nearest"
+)
+
+// earthRadiusKm is the mean radius of the earth, used for distances between coordinates
+const earthRadiusKm = 6371.0
+
+// Coordinates is a position on the earth in decimal degrees
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// FulfillmentLocation is a stock location a policy may ship from
+type FulfillmentLocation struct {
+	ID          string       `json:"id"`                    // Matches Stock.LocationID
+	Coordinates *Coordinates `json:"coordinates,omitempty"` // Required by the nearest strategy
+}
+
+// AllocationPolicy configures how orders in a market, or in one sales channel
+// of it, are fulfilled from the stock locations
+type AllocationPolicy struct {
+	Market         string                `json:"market"`
+	Channel        string                `json:"channel,omitempty"` // Sales channel, e.g. "web" or "pos"; empty for every channel of the market
+	Strategy       AllocationStrategy    `json:"strategy"`
+	Locations      []FulfillmentLocation `json:"locations,omitempty"` // Locations that may fulfil orders, in priority order; every stocked location when empty
+	SplitShipments bool                  `json:"split_shipments"`     // Whether one order may ship from several locations
+	Default        bool                  `json:"default,omitempty"`   // Set when no policy is configured and the default applies
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// DefaultAllocationPolicy is the policy of markets without one: every stocked
+// location in the order the product lists them, split shipments allowed
+func DefaultAllocationPolicy(market, channel string) *AllocationPolicy {
+	return &AllocationPolicy{
+		Market:         NormalizeMarket(market),
+		Channel:        NormalizeChannel(channel),
+		Strategy:       AllocationPriority,
+		SplitShipments: true,
+		Default:        true,
+	}
+}
+
+// NormalizeMarket returns the canonical form of a market code
+func NormalizeMarket(market string) string {
+	return strings.ToUpper(strings.TrimSpace(market))
+}
+
+// NormalizeChannel returns the canonical form of a sales channel
+func NormalizeChannel(channel string) string {
+	return strings.ToLower(strings.TrimSpace(channel))
+}
+
+// Validate normalizes the policy and checks that its locations can be ranked
+// by the strategy
+func (p *AllocationPolicy) Validate() error {
+	p.Market = NormalizeMarket(p.Market)
+	p.Channel = NormalizeChannel(p.Channel)
+	if p.Market == "" {
+		return fmt.Errorf("%w: market is required", ErrInvalidRequest)
+	}
+	if p.Strategy == "" {
+		p.Strategy = AllocationPriority
+	}
+	if p.Strategy != AllocationPriority && p.Strategy != AllocationNearest {
+		return fmt.Errorf("%w: unknown strategy %q, expected %s or %s", ErrInvalidRequest, p.Strategy, AllocationPriority, AllocationNearest)
+	}
+	if p.Strategy == AllocationNearest && len(p.Locations) == 0 {
+		return fmt.Errorf("%w: the nearest strategy needs the locations and their coordinates", ErrInvalidRequest)
+	}
+
+	seen := make(map[string]bool, len(p.Locations))
+	for _, location := range p.Locations {
+		if strings.TrimSpace(location.ID) == "" {
+			return fmt.Errorf("%w: locations need an id", ErrInvalidRequest)
+		}
+		if seen[location.ID] {
+			return fmt.Errorf("%w: location %s is listed more than once", ErrInvalidRequest, location.ID)
+		}
+		seen[location.ID] = true
+		if location.Coordinates == nil {
+			if p.Strategy == AllocationNearest {
+				return fmt.Errorf("%w: location %s needs coordinates for the nearest strategy", ErrInvalidRequest, location.ID)
+			}
+			continue
+		}
+		if err := location.Coordinates.Validate(); err != nil {
+			return fmt.Errorf("%w (location %s)", err, location.ID)
+		}
+	}
+	p.Default = false
+	return nil
+}
+
+// Validate checks that the coordinates are on the earth
+func (c *Coordinates) Validate() error {
+	if c.Latitude < -90 || c.Latitude > 90 || c.Longitude < -180 || c.Longitude > 180 {
+		return fmt.Errorf("%w: coordinates must be a latitude of -90 to 90 and a longitude of -180 to 180", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// DistanceKm returns the great-circle distance to other in kilometres
+func (c *Coordinates) DistanceKm(other *Coordinates) float64 {
+	lat1 := c.Latitude * math.Pi / 180
+	lat2 := other.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (other.Longitude - c.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// AllocationItem is a quantity of one variant to fulfil
+type AllocationItem struct {
+	VariantID string `json:"variant_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// AllocationRequest asks for stock of a product to be allocated and reserved
+type AllocationRequest struct {
+	Market      string           `json:"market"`
+	Channel     string           `json:"channel,omitempty"`
+	Items       []AllocationItem `json:"items"`
+	Destination *Coordinates     `json:"destination,omitempty"` // Where the order ships to; ranks locations for the nearest strategy
+}
+
+// Validate normalizes the request and checks the items
+func (r *AllocationRequest) Validate() error {
+	r.Market = NormalizeMarket(r.Market)
+	r.Channel = NormalizeChannel(r.Channel)
+	if r.Market == "" {
+		return fmt.Errorf("%w: market is required", ErrInvalidRequest)
+	}
+	if len(r.Items) == 0 {
+		return fmt.Errorf("%w: no items to allocate", ErrInvalidRequest)
+	}
+	for _, item := range r.Items {
+		if item.VariantID == "" {
+			return fmt.Errorf("%w: variant_id is required", ErrInvalidRequest)
+		}
+		if item.Quantity < 1 {
+			return fmt.Errorf("%w: quantity of %s must be 1 or more", ErrInvalidRequest, item.VariantID)
+		}
+	}
+	if r.Destination != nil {
+		return r.Destination.Validate()
+	}
+	return nil
+}
+
+// AllocationLine is the quantity of one variant taken from one location
+type AllocationLine struct {
+	VariantID  string `json:"variant_id"`
+	LocationID string `json:"location_id"`
+	Quantity   int    `json:"quantity"`
+}
+
+// Allocation is the outcome of allocating a request: which locations ship what
+type Allocation struct {
+	ProductID string             `json:"product_id"`
+	Market    string             `json:"market"`
+	Channel   string             `json:"channel,omitempty"`
+	Strategy  AllocationStrategy `json:"strategy"`
+	Lines     []AllocationLine   `json:"lines"`
+	Shipments int                `json:"shipments"`         // Number of locations the order ships from
+	Version   int64              `json:"version,omitempty"` // Version of the product once the stock is reserved
+}
+
+// StockAdjustments returns the decrements that reserve the allocated stock
+func (a *Allocation) StockAdjustments() []StockAdjustment {
+	adjustments := make([]StockAdjustment, 0, len(a.Lines))
+	for _, line := range a.Lines {
+		adjustments = append(adjustments, StockAdjustment{
+			VariantID:  line.VariantID,
+			LocationID: line.LocationID,
+			Delta:      -line.Quantity,
+		})
+	}
+	return adjustments
+}
+
+// Allocate decides which locations fulfil the items. One location that can
+// ship everything is preferred, even when split shipments are allowed; only
+// then are items split over the locations in strategy order. Locations with
+// backorders can always fulfil the rest. It fails with ErrInsufficientStock
+// when the eligible locations cannot cover the items, and with
+// ErrVariantNotFound for unknown variants. Without a destination the nearest
+// strategy falls back to the listed order.
+func (p *AllocationPolicy) Allocate(product *Product, items []AllocationItem, destination *Coordinates) (*Allocation, error) {
+	wanted := mergeItems(items)
+	for _, item := range wanted {
+		if findVariant(product.Variants, item.VariantID) == nil {
+			return nil, fmt.Errorf("%w: %s", ErrVariantNotFound, item.VariantID)
+		}
+	}
+
+	allocation := &Allocation{
+		ProductID: product.ID,
+		Market:    p.Market,
+		Channel:   p.Channel,
+		Strategy:  p.Strategy,
+		Lines:     make([]AllocationLine, 0, len(wanted)),
+	}
+	locations := p.rank(product, destination)
+
+	for _, location := range locations {
+		if !canFulfil(product, location, wanted) {
+			continue
+		}
+		for _, item := range wanted {
+			allocation.Lines = append(allocation.Lines, AllocationLine{VariantID: item.VariantID, LocationID: location, Quantity: item.Quantity})
+		}
+		allocation.Shipments = 1
+		return allocation, nil
+	}
+	if !p.SplitShipments {
+		return nil, fmt.Errorf("%w: no single location can fulfil the order and split shipments are not allowed", ErrInsufficientStock)
+	}
+
+	shipping := make(map[string]bool)
+	for _, item := range wanted {
+		remaining := item.Quantity
+		for _, location := range locations {
+			take := available(product, item.VariantID, location)
+			if take > remaining {
+				take = remaining
+			}
+			if take <= 0 {
+				continue
+			}
+			allocation.Lines = append(allocation.Lines, AllocationLine{VariantID: item.VariantID, LocationID: location, Quantity: take})
+			shipping[location] = true
+			remaining -= take
+			if remaining == 0 {
+				break
+			}
+		}
+		if remaining > 0 {
+			return nil, fmt.Errorf("%w: variant %s is short %d of %d in %s", ErrInsufficientStock, item.VariantID, remaining, item.Quantity, p.Market)
+		}
+	}
+	allocation.Shipments = len(shipping)
+	return allocation, nil
+}
+
+// LocationQuantity is the stock of a variant at one location
+type LocationQuantity struct {
+	LocationID string `json:"location_id"`
+	Quantity   int    `json:"quantity"`
+	Backorder  bool   `json:"backorder,omitempty"`
+}
+
+// VariantAvailability is how much of a variant one order can get
+type VariantAvailability struct {
+	VariantID string             `json:"variant_id"`
+	SKU       string             `json:"sku"`
+	Available int                `json:"available"`           // Total of the eligible locations, or the best single one without split shipments
+	Backorder bool               `json:"backorder,omitempty"` // An eligible location takes orders beyond its stock
+	Locations []LocationQuantity `json:"locations"`
+}
+
+// Availability is the stock of a product that a market or channel can sell
+type Availability struct {
+	ProductID      string                `json:"product_id"`
+	Market         string                `json:"market"`
+	Channel        string                `json:"channel,omitempty"`
+	Strategy       AllocationStrategy    `json:"strategy"`
+	SplitShipments bool                  `json:"split_shipments"`
+	Variants       []VariantAvailability `json:"variants"`
+}
+
+// Availability reports how much of each variant of the product the policy can
+// allocate to one order, from the eligible locations in priority order
+func (p *AllocationPolicy) Availability(product *Product) *Availability {
+	availability := &Availability{
+		ProductID:      product.ID,
+		Market:         p.Market,
+		Channel:        p.Channel,
+		Strategy:       p.Strategy,
+		SplitShipments: p.SplitShipments,
+		Variants:       make([]VariantAvailability, 0, len(product.Variants)),
+	}
+	locations := p.rank(product, nil)
+
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		entry := VariantAvailability{VariantID: variant.ID, SKU: variant.SKU, Locations: make([]LocationQuantity, 0)}
+		for _, location := range locations {
+			stock := findStock(variant, location)
+			if stock == nil {
+				continue
+			}
+			entry.Locations = append(entry.Locations, LocationQuantity{LocationID: location, Quantity: stock.Quantity, Backorder: stock.Backorder})
+			entry.Backorder = entry.Backorder || stock.Backorder
+			if stock.Quantity <= 0 {
+				continue
+			}
+			if p.SplitShipments {
+				entry.Available += stock.Quantity
+			} else if stock.Quantity > entry.Available {
+				entry.Available = stock.Quantity
+			}
+		}
+		availability.Variants = append(availability.Variants, entry)
+	}
+	return availability
+}
+
+// rank returns the IDs of the locations eligible to ship, best first
+func (p *AllocationPolicy) rank(product *Product, destination *Coordinates) []string {
+	if len(p.Locations) == 0 {
+		return stockedLocations(product)
+	}
+
+	locations := append([]FulfillmentLocation(nil), p.Locations...)
+	if p.Strategy == AllocationNearest && destination != nil {
+		sort.SliceStable(locations, func(i, j int) bool {
+			return locations[i].Coordinates.DistanceKm(destination) < locations[j].Coordinates.DistanceKm(destination)
+		})
+	}
+	ids := make([]string, len(locations))
+	for i, location := range locations {
+		ids[i] = location.ID
+	}
+	return ids
+}
+
+// stockedLocations returns every location the product has stock entries at,
+// in the order they first appear
+func stockedLocations(product *Product) []string {
+	seen := make(map[string]bool)
+	locations := make([]string, 0)
+	for _, variant := range product.Variants {
+		for _, stock := range variant.Stock {
+			if !seen[stock.LocationID] {
+				seen[stock.LocationID] = true
+				locations = append(locations, stock.LocationID)
+			}
+		}
+	}
+	return locations
+}
+
+// mergeItems adds up the quantities requested of the same variant, keeping the first order
+func mergeItems(items []AllocationItem) []AllocationItem {
+	merged := make([]AllocationItem, 0, len(items))
+	index := make(map[string]int, len(items))
+	for _, item := range items {
+		if i, exists := index[item.VariantID]; exists {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.VariantID] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// canFulfil reports whether one location can ship all items
+func canFulfil(product *Product, location string, items []AllocationItem) bool {
+	for _, item := range items {
+		if available(product, item.VariantID, location) < item.Quantity {
+			return false
+		}
+	}
+	return true
+}
+
+// available returns how much of a variant a location can ship; backorders are unlimited
+func available(product *Product, variantID, location string) int {
+	variant := findVariant(product.Variants, variantID)
+	if variant == nil {
+		return 0
+	}
+	stock := findStock(variant, location)
+	switch {
+	case stock == nil:
+		return 0
+	case stock.Backorder:
+		return math.MaxInt32
+	case stock.Quantity < 0:
+		return 0
+	}
+	return stock.Quantity
+}
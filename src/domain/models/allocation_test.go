@@ -0,0 +1,180 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// allocationProduct has a shirt stocked in Stockholm, Gothenburg and Malmö
+func allocationProduct() *Product {
+	return &Product{
+		ID: "prod_1",
+		Variants: []Variant{
+			{ID: "v1", SKU: "SHIRT-S", Stock: []Stock{
+				{LocationID: "sto", Quantity: 2},
+				{LocationID: "got", Quantity: 5},
+				{LocationID: "mmo", Quantity: 1},
+			}},
+			{ID: "v2", SKU: "SHIRT-M", Stock: []Stock{
+				{LocationID: "got", Quantity: 1},
+				{LocationID: "mmo", Quantity: 0, Backorder: true},
+			}},
+		},
+	}
+}
+
+var (
+	stockholm  = &Coordinates{Latitude: 59.33, Longitude: 18.07}
+	gothenburg = &Coordinates{Latitude: 57.71, Longitude: 11.97}
+	malmo      = &Coordinates{Latitude: 55.60, Longitude: 13.00}
+)
+
+func TestAllocationPolicyValidate(t *testing.T) {
+	policy := &AllocationPolicy{Market: " se ", Channel: " Web ", Default: true}
+	assert.NoError(t, policy.Validate())
+	assert.Equal(t, "SE", policy.Market)
+	assert.Equal(t, "web", policy.Channel)
+	assert.Equal(t, AllocationPriority, policy.Strategy)
+	assert.False(t, policy.Default)
+
+	invalid := []*AllocationPolicy{
+		{},
+		{Market: "SE", Strategy: "cheapest"},
+		{Market: "SE", Strategy: AllocationNearest},
+		{Market: "SE", Strategy: AllocationNearest, Locations: []FulfillmentLocation{{ID: "sto"}}},
+		{Market: "SE", Locations: []FulfillmentLocation{{ID: "sto"}, {ID: "sto"}}},
+		{Market: "SE", Locations: []FulfillmentLocation{{ID: " "}}},
+		{Market: "SE", Locations: []FulfillmentLocation{{ID: "sto", Coordinates: &Coordinates{Latitude: 91}}}},
+	}
+	for _, policy := range invalid {
+		assert.ErrorIs(t, policy.Validate(), ErrInvalidRequest, "%+v", policy)
+	}
+}
+
+func TestAllocationRequestValidate(t *testing.T) {
+	request := &AllocationRequest{Market: "se", Items: []AllocationItem{{VariantID: "v1", Quantity: 1}}}
+	assert.NoError(t, request.Validate())
+	assert.Equal(t, "SE", request.Market)
+
+	invalid := []*AllocationRequest{
+		{Items: []AllocationItem{{VariantID: "v1", Quantity: 1}}},
+		{Market: "SE"},
+		{Market: "SE", Items: []AllocationItem{{Quantity: 1}}},
+		{Market: "SE", Items: []AllocationItem{{VariantID: "v1"}}},
+		{Market: "SE", Items: []AllocationItem{{VariantID: "v1", Quantity: 1}}, Destination: &Coordinates{Longitude: 200}},
+	}
+	for _, request := range invalid {
+		assert.ErrorIs(t, request.Validate(), ErrInvalidRequest, "%+v", request)
+	}
+}
+
+func TestAllocatePriorityPrefersOneLocation(t *testing.T) {
+	policy := DefaultAllocationPolicy("SE", "")
+
+	// Stockholm comes first but only Gothenburg has everything
+	allocation, err := policy.Allocate(allocationProduct(), []AllocationItem{
+		{VariantID: "v1", Quantity: 3},
+		{VariantID: "v2", Quantity: 1},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, allocation.Shipments)
+	assert.Equal(t, []AllocationLine{
+		{VariantID: "v1", LocationID: "got", Quantity: 3},
+		{VariantID: "v2", LocationID: "got", Quantity: 1},
+	}, allocation.Lines)
+}
+
+func TestAllocateSplitsInPriorityOrder(t *testing.T) {
+	policy := &AllocationPolicy{
+		Market:         "SE",
+		Locations:      []FulfillmentLocation{{ID: "mmo"}, {ID: "sto"}, {ID: "got"}},
+		SplitShipments: true,
+	}
+	assert.NoError(t, policy.Validate())
+
+	allocation, err := policy.Allocate(allocationProduct(), []AllocationItem{
+		{VariantID: "v1", Quantity: 4},
+		{VariantID: "v1", Quantity: 3},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, allocation.Shipments)
+	assert.Equal(t, []AllocationLine{
+		{VariantID: "v1", LocationID: "mmo", Quantity: 1},
+		{VariantID: "v1", LocationID: "sto", Quantity: 2},
+		{VariantID: "v1", LocationID: "got", Quantity: 4},
+	}, allocation.Lines)
+	assert.Equal(t, []StockAdjustment{
+		{VariantID: "v1", LocationID: "mmo", Delta: -1},
+		{VariantID: "v1", LocationID: "sto", Delta: -2},
+		{VariantID: "v1", LocationID: "got", Delta: -4},
+	}, allocation.StockAdjustments())
+
+	// More than all locations hold together
+	_, err = policy.Allocate(allocationProduct(), []AllocationItem{{VariantID: "v1", Quantity: 9}}, nil)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+}
+
+func TestAllocateWithoutSplitShipments(t *testing.T) {
+	policy := &AllocationPolicy{Market: "SE", Locations: []FulfillmentLocation{{ID: "sto"}, {ID: "mmo"}}}
+	assert.NoError(t, policy.Validate())
+
+	_, err := policy.Allocate(allocationProduct(), []AllocationItem{{VariantID: "v1", Quantity: 3}}, nil)
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+
+	// Gothenburg is not eligible; Malmö takes backorders
+	allocation, err := policy.Allocate(allocationProduct(), []AllocationItem{{VariantID: "v2", Quantity: 4}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []AllocationLine{{VariantID: "v2", LocationID: "mmo", Quantity: 4}}, allocation.Lines)
+}
+
+func TestAllocateNearest(t *testing.T) {
+	policy := &AllocationPolicy{
+		Market:   "SE",
+		Strategy: AllocationNearest,
+		Locations: []FulfillmentLocation{
+			{ID: "sto", Coordinates: stockholm},
+			{ID: "got", Coordinates: gothenburg},
+			{ID: "mmo", Coordinates: malmo},
+		},
+	}
+	assert.NoError(t, policy.Validate())
+
+	// Lund is next to Malmö
+	lund := &Coordinates{Latitude: 55.70, Longitude: 13.19}
+	allocation, err := policy.Allocate(allocationProduct(), []AllocationItem{{VariantID: "v1", Quantity: 1}}, lund)
+	assert.NoError(t, err)
+	assert.Equal(t, "mmo", allocation.Lines[0].LocationID)
+
+	// Without a destination the listed order applies
+	allocation, err = policy.Allocate(allocationProduct(), []AllocationItem{{VariantID: "v1", Quantity: 1}}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "sto", allocation.Lines[0].LocationID)
+}
+
+func TestAllocateUnknownVariant(t *testing.T) {
+	_, err := DefaultAllocationPolicy("SE", "").Allocate(allocationProduct(), []AllocationItem{{VariantID: "missing", Quantity: 1}}, nil)
+	assert.ErrorIs(t, err, ErrVariantNotFound)
+}
+
+func TestAvailability(t *testing.T) {
+	split := DefaultAllocationPolicy("SE", "web")
+	availability := split.Availability(allocationProduct())
+	assert.Equal(t, "web", availability.Channel)
+	assert.Len(t, availability.Variants, 2)
+	assert.Equal(t, 8, availability.Variants[0].Available)
+	assert.Len(t, availability.Variants[0].Locations, 3)
+	assert.Equal(t, 1, availability.Variants[1].Available)
+	assert.True(t, availability.Variants[1].Backorder)
+
+	single := &AllocationPolicy{Market: "SE", Locations: []FulfillmentLocation{{ID: "sto"}, {ID: "mmo"}}}
+	availability = single.Availability(allocationProduct())
+	assert.Equal(t, 2, availability.Variants[0].Available)
+	assert.Equal(t, []LocationQuantity{{LocationID: "sto", Quantity: 2}, {LocationID: "mmo", Quantity: 1}}, availability.Variants[0].Locations)
+	assert.Equal(t, 0, availability.Variants[1].Available)
+}
+
+func TestCoordinatesDistanceKm(t *testing.T) {
+	assert.InDelta(t, 398, stockholm.DistanceKm(gothenburg), 5)
+	assert.Equal(t, 0.0, malmo.DistanceKm(malmo))
+}
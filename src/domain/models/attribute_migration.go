@@ -0,0 +1,58 @@
+package models
+
+import "fmt"
+
+// AttributeMigration renames a variant attribute key and/or remaps its values
+// across the catalog, e.g. "colour" to "color" with "Navy" becoming "Dark Blue"
+type AttributeMigration struct {
+	Key    string            `json:"key"`               // Attribute to migrate
+	NewKey string            `json:"new_key,omitempty"` // New name for the attribute, if it is renamed
+	Values map[string]string `json:"values,omitempty"`  // Old value to new value; values not listed are kept
+}
+
+// Validate checks that the migration changes something
+func (m *AttributeMigration) Validate() error {
+	if m.Key == "" {
+		return fmt.Errorf("%w: key is required", ErrInvalidRequest)
+	}
+	if (m.NewKey == "" || m.NewKey == m.Key) && len(m.Values) == 0 {
+		return fmt.Errorf("%w: new_key or values is required", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// Apply returns a copy of the product with the migration applied to every
+// variant, and whether anything changed. A variant that already has the new key
+// with a different value is a conflict and fails the whole product.
+func (m *AttributeMigration) Apply(p *Product) (*Product, bool, error) {
+	migrated := p.Clone()
+	changed := false
+
+	targetKey := m.Key
+	if m.NewKey != "" {
+		targetKey = m.NewKey
+	}
+
+	for i := range migrated.Variants {
+		attributes := migrated.Variants[i].Attributes
+		value, exists := attributes[m.Key]
+		if !exists {
+			continue
+		}
+		if mapped, remapped := m.Values[value]; remapped {
+			value = mapped
+		}
+
+		if targetKey != m.Key {
+			if existing, taken := attributes[targetKey]; taken && existing != value {
+				return nil, false, fmt.Errorf("variant %s already has %s=%q", migrated.Variants[i].ID, targetKey, existing)
+			}
+			delete(attributes, m.Key)
+		} else if attributes[m.Key] == value {
+			continue
+		}
+		attributes[targetKey] = value
+		changed = true
+	}
+	return migrated, changed, nil
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func migrationProduct() *Product {
+	return &Product{
+		ID: "prod_1",
+		Variants: []Variant{
+			{ID: "v1", Attributes: map[string]string{"colour": "Navy", "size": "M"}},
+			{ID: "v2", Attributes: map[string]string{"colour": "Red", "size": "L"}},
+			{ID: "v3", Attributes: map[string]string{"size": "XL"}},
+		},
+	}
+}
+
+func TestAttributeMigrationValidate(t *testing.T) {
+	assert.ErrorIs(t, (&AttributeMigration{NewKey: "color"}).Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, (&AttributeMigration{Key: "colour"}).Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, (&AttributeMigration{Key: "colour", NewKey: "colour"}).Validate(), ErrInvalidRequest)
+	assert.NoError(t, (&AttributeMigration{Key: "colour", NewKey: "color"}).Validate())
+	assert.NoError(t, (&AttributeMigration{Key: "color", Values: map[string]string{"Navy": "Dark Blue"}}).Validate())
+}
+
+func TestAttributeMigrationRenamesAndRemaps(t *testing.T) {
+	product := migrationProduct()
+	migration := &AttributeMigration{Key: "colour", NewKey: "color", Values: map[string]string{"Navy": "Dark Blue"}}
+
+	migrated, changed, err := migration.Apply(product)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"color": "Dark Blue", "size": "M"}, migrated.Variants[0].Attributes)
+	assert.Equal(t, map[string]string{"color": "Red", "size": "L"}, migrated.Variants[1].Attributes)
+	assert.Equal(t, map[string]string{"size": "XL"}, migrated.Variants[2].Attributes)
+
+	// The original product is not modified
+	assert.Equal(t, "Navy", product.Variants[0].Attributes["colour"])
+}
+
+func TestAttributeMigrationRemapOnly(t *testing.T) {
+	migration := &AttributeMigration{Key: "colour", Values: map[string]string{"Green": "Olive"}}
+
+	_, changed, err := migration.Apply(migrationProduct())
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	migration.Values["Red"] = "Crimson"
+	migrated, changed, err := migration.Apply(migrationProduct())
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "Navy", migrated.Variants[0].Attributes["colour"])
+	assert.Equal(t, "Crimson", migrated.Variants[1].Attributes["colour"])
+}
+
+func TestAttributeMigrationConflict(t *testing.T) {
+	product := migrationProduct()
+	product.Variants[0].Attributes["color"] = "Blue"
+
+	_, _, err := (&AttributeMigration{Key: "colour", NewKey: "color"}).Apply(product)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "v1")
+
+	// The same value under both keys is merged
+	product.Variants[0].Attributes["color"] = "Navy"
+	migrated, changed, err := (&AttributeMigration{Key: "colour", NewKey: "color"}).Apply(product)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"color": "Navy", "size": "M"}, migrated.Variants[0].Attributes)
+}
@@ -0,0 +1,23 @@
+package models
+
+// BuildInfo identifies the build of the running service
+type BuildInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	Modified   bool   `json:"modified,omitempty"` // Built from a tree with uncommitted changes
+	GoVersion  string `json:"go_version"`
+}
+
+// VersionInfo is the build together with the event formats it is compatible
+// with, so services can check each other during rolling upgrades
+type VersionInfo struct {
+	*BuildInfo
+	EventSchema EventSchemaCompatibility `json:"event_schema"`
+}
+
+// EventSchemaCompatibility lists the event schema versions of a build
+type EventSchemaCompatibility struct {
+	Current   int   `json:"current"`   // Version of the events the build publishes
+	Supported []int `json:"supported"` // Versions the build reads
+}
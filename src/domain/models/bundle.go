@@ -0,0 +1,247 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BundlePriceStrategy decides how the price of a bundle is computed
+type BundlePriceStrategy string
+
+const (
+	// BundlePriceSum prices a bundle at the sum of its components
+	BundlePriceSum BundlePriceStrategy = "sum"
+	// BundlePriceFixed prices a bundle at its own product prices
+	BundlePriceFixed BundlePriceStrategy = "fixed"
+	// BundlePriceDiscount prices a bundle at the sum of its components less a percentage
+	BundlePriceDiscount BundlePriceStrategy = "percentage_discount"
+)
+
+// MaxBundleComponents is the largest number of components a bundle can have
+const MaxBundleComponents = 50
+
+// BundleComponent is a product, or one variant of it, in a bundle
+type BundleComponent struct {
+	ProductID string `json:"product_id"`
+	VariantID string `json:"variant_id,omitempty"` // Required when the product has variants
+	Quantity  int    `json:"quantity"`             // Units in one bundle
+}
+
+// Bundle makes a product a bundle of other products. Its stock is derived
+// from the components, so a bundle has no variants of its own.
+type Bundle struct {
+	Components      []BundleComponent   `json:"components"`
+	PriceStrategy   BundlePriceStrategy `json:"price_strategy,omitempty"`   // sum (default), fixed or percentage_discount
+	DiscountPercent float64             `json:"discount_percent,omitempty"` // Off the component sum with percentage_discount
+}
+
+// Validate normalizes the bundle and checks its own fields. Whether the
+// components exist is checked against the catalog.
+func (b *Bundle) Validate() error {
+	if b == nil {
+		return nil
+	}
+	b.PriceStrategy = BundlePriceStrategy(strings.ToLower(strings.TrimSpace(string(b.PriceStrategy))))
+	if b.PriceStrategy == "" {
+		b.PriceStrategy = BundlePriceSum
+	}
+	switch b.PriceStrategy {
+	case BundlePriceSum, BundlePriceFixed:
+		if b.DiscountPercent != 0 {
+			return fmt.Errorf("%w: discount_percent only applies to percentage_discount bundles", ErrInvalidRequest)
+		}
+	case BundlePriceDiscount:
+		if b.DiscountPercent <= 0 || b.DiscountPercent >= 100 {
+			return fmt.Errorf("%w: discount_percent must be above 0 and below 100", ErrInvalidRequest)
+		}
+	default:
+		return fmt.Errorf("%w: unknown price_strategy %q", ErrInvalidRequest, b.PriceStrategy)
+	}
+
+	if len(b.Components) == 0 || len(b.Components) > MaxBundleComponents {
+		return fmt.Errorf("%w: a bundle needs 1 to %d components", ErrInvalidRequest, MaxBundleComponents)
+	}
+	seen := make(map[BundleComponent]bool, len(b.Components))
+	for i := range b.Components {
+		component := &b.Components[i]
+		component.ProductID = strings.TrimSpace(component.ProductID)
+		component.VariantID = strings.TrimSpace(component.VariantID)
+		if component.ProductID == "" {
+			return fmt.Errorf("%w: components need a product_id", ErrInvalidRequest)
+		}
+		if component.Quantity < 1 {
+			return fmt.Errorf("%w: component %s needs a quantity of at least 1", ErrInvalidRequest, component.ProductID)
+		}
+		key := BundleComponent{ProductID: component.ProductID, VariantID: component.VariantID}
+		if seen[key] {
+			return fmt.Errorf("%w: component %s is listed more than once", ErrInvalidRequest, component.ProductID)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// ValidateBundle checks the bundle of a bundle product, which cannot have
+// variants of its own
+func (p *Product) ValidateBundle() error {
+	if p.Bundle == nil {
+		return nil
+	}
+	if len(p.Variants) > 0 {
+		return fmt.Errorf("%w: bundles take their stock from the components and cannot have variants", ErrInvalidRequest)
+	}
+	return p.Bundle.Validate()
+}
+
+// Clone creates a deep copy of the bundle
+func (b *Bundle) Clone() *Bundle {
+	if b == nil {
+		return nil
+	}
+	clone := *b
+	clone.Components = append([]BundleComponent(nil), b.Components...)
+	return &clone
+}
+
+// CheckComponent checks a component against its product: the product is not
+// the bundle or a bundle itself, and the variant exists and is given when the
+// product has variants
+func (c BundleComponent) CheckComponent(bundleID string, product *Product) error {
+	if product.ID == bundleID {
+		return fmt.Errorf("%w: a bundle cannot contain itself", ErrInvalidRequest)
+	}
+	if product.Bundle != nil {
+		return fmt.Errorf("%w: component %s is a bundle; bundles cannot be nested", ErrInvalidRequest, product.ID)
+	}
+	if c.VariantID == "" {
+		if len(product.Variants) > 0 {
+			return fmt.Errorf("%w: component %s has variants; set a variant_id", ErrInvalidRequest, product.ID)
+		}
+		return nil
+	}
+	if findVariant(product.Variants, c.VariantID) == nil {
+		return fmt.Errorf("%w: component %s has no variant %s", ErrInvalidRequest, product.ID, c.VariantID)
+	}
+	return nil
+}
+
+// BundleSummary is the computed price and availability of a bundle
+type BundleSummary struct {
+	ProductID     string                `json:"product_id"`
+	PriceStrategy BundlePriceStrategy   `json:"price_strategy"`
+	Prices        []Price               `json:"prices"`                  // Bundle price per currency
+	ComponentSum  []Price               `json:"component_sum,omitempty"` // Sum of the component prices per currency
+	Available     *int                  `json:"available"`               // Bundles the component stock covers; null when no component tracks stock
+	Components    []BundleComponentInfo `json:"components"`
+}
+
+// BundleComponentInfo is a component with its resolved prices and stock
+type BundleComponentInfo struct {
+	BundleComponent
+	SKU       string  `json:"sku,omitempty"`
+	Prices    []Price `json:"prices,omitempty"` // Unit price per currency
+	InStock   *int    `json:"in_stock"`         // Units across locations; null when untracked or backordered
+	Available bool    `json:"available"`        // False when the product or variant no longer exists
+}
+
+// Summarize computes the price and availability of a bundle product from
+// its components, keyed by product ID. Components missing from the map make
+// the bundle unavailable; prices are only summed in currencies every
+// component has a price in.
+func (b *Bundle) Summarize(bundle *Product, products map[string]*Product) *BundleSummary {
+	summary := &BundleSummary{
+		ProductID:     bundle.ID,
+		PriceStrategy: b.PriceStrategy,
+		Components:    make([]BundleComponentInfo, 0, len(b.Components)),
+	}
+	if summary.PriceStrategy == "" {
+		summary.PriceStrategy = BundlePriceSum
+	}
+
+	// Each currency is summed with how many components were priced in it,
+	// in the order the currencies first appear
+	sums := make(map[string]float64)
+	priced := make(map[string]int)
+	var currencies []string
+	complete := true
+	for _, component := range b.Components {
+		info := BundleComponentInfo{BundleComponent: component}
+		product, found := products[component.ProductID]
+		var variant *Variant
+		if found && component.VariantID != "" {
+			variant = findVariant(product.Variants, component.VariantID)
+			found = variant != nil
+		}
+		if !found {
+			zero := 0
+			summary.Available = &zero
+			complete = false
+			summary.Components = append(summary.Components, info)
+			continue
+		}
+
+		info.Available = true
+		info.SKU = product.SKU
+		if variant != nil {
+			info.SKU = variant.SKU
+			info.Prices = product.VariantPrices(variant)
+		} else {
+			info.Prices = append([]Price(nil), product.Prices...)
+		}
+		for _, price := range info.Prices {
+			currency := strings.ToUpper(price.Currency)
+			if priced[currency] == 0 {
+				currencies = append(currencies, currency)
+			}
+			sums[currency] += price.Amount * float64(component.Quantity)
+			priced[currency]++
+		}
+
+		if variant != nil {
+			if units, tracked := variantUnits(variant); tracked {
+				info.InStock = &units
+				if bundles := units / component.Quantity; summary.Available == nil || bundles < *summary.Available {
+					summary.Available = &bundles
+				}
+			}
+		}
+		summary.Components = append(summary.Components, info)
+	}
+
+	if complete {
+		for _, currency := range currencies {
+			if priced[currency] == len(b.Components) {
+				summary.ComponentSum = append(summary.ComponentSum, Price{Currency: currency, Amount: RoundToMinorUnits(sums[currency], currency)})
+			}
+		}
+	}
+	switch summary.PriceStrategy {
+	case BundlePriceFixed:
+		summary.Prices = append([]Price(nil), bundle.Prices...)
+	case BundlePriceDiscount:
+		for _, sum := range summary.ComponentSum {
+			summary.Prices = append(summary.Prices, Price{Currency: sum.Currency, Amount: RoundToMinorUnits(sum.Amount*(100-b.DiscountPercent)/100, sum.Currency)})
+		}
+	default:
+		summary.Prices = append([]Price(nil), summary.ComponentSum...)
+	}
+	if summary.Prices == nil {
+		summary.Prices = []Price{}
+	}
+	return summary
+}
+
+// variantUnits counts the units of a variant in stock across its locations.
+// A variant with a location that takes backorders is not limited by stock.
+func variantUnits(variant *Variant) (int, bool) {
+	units := 0
+	for _, stock := range variant.Stock {
+		if stock.Backorder {
+			return 0, false
+		}
+		if stock.Quantity > 0 {
+			units += stock.Quantity
+		}
+	}
+	return units, true
+}
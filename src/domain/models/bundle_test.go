@@ -0,0 +1,97 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundleValidate(t *testing.T) {
+	bundle := &Bundle{Components: []BundleComponent{{ProductID: " shirt ", VariantID: "v1", Quantity: 2}, {ProductID: "tie", Quantity: 1}}}
+	assert.NoError(t, bundle.Validate())
+	assert.Equal(t, BundlePriceSum, bundle.PriceStrategy)
+	assert.Equal(t, "shirt", bundle.Components[0].ProductID)
+
+	tests := map[string]Bundle{
+		"no components":     {},
+		"product":           {Components: []BundleComponent{{Quantity: 1}}},
+		"quantity":          {Components: []BundleComponent{{ProductID: "tie"}}},
+		"duplicate":         {Components: []BundleComponent{{ProductID: "tie", Quantity: 1}, {ProductID: "tie", Quantity: 2}}},
+		"strategy":          {PriceStrategy: "cheapest", Components: []BundleComponent{{ProductID: "tie", Quantity: 1}}},
+		"discount":          {PriceStrategy: BundlePriceDiscount, DiscountPercent: 100, Components: []BundleComponent{{ProductID: "tie", Quantity: 1}}},
+		"discount with sum": {DiscountPercent: 10, Components: []BundleComponent{{ProductID: "tie", Quantity: 1}}},
+	}
+	for name, bundle := range tests {
+		assert.ErrorIs(t, bundle.Validate(), ErrInvalidRequest, name)
+	}
+
+	product := &Product{Bundle: bundle, Variants: []Variant{{ID: "v1"}}}
+	assert.ErrorIs(t, product.ValidateBundle(), ErrInvalidRequest)
+}
+
+func TestBundleComponentCheckComponent(t *testing.T) {
+	shirt := &Product{ID: "shirt", Variants: []Variant{{ID: "v1"}}}
+	tie := &Product{ID: "tie"}
+
+	assert.NoError(t, BundleComponent{ProductID: "shirt", VariantID: "v1"}.CheckComponent("set", shirt))
+	assert.NoError(t, BundleComponent{ProductID: "tie"}.CheckComponent("set", tie))
+	assert.ErrorIs(t, BundleComponent{ProductID: "shirt"}.CheckComponent("set", shirt), ErrInvalidRequest)
+	assert.ErrorIs(t, BundleComponent{ProductID: "shirt", VariantID: "v9"}.CheckComponent("set", shirt), ErrInvalidRequest)
+	assert.ErrorIs(t, BundleComponent{ProductID: "tie"}.CheckComponent("tie", tie), ErrInvalidRequest)
+	assert.ErrorIs(t, BundleComponent{ProductID: "set"}.CheckComponent("other", &Product{ID: "set", Bundle: &Bundle{}}), ErrInvalidRequest)
+}
+
+// bundleComponents returns a shirt with two sizes and a tie without variants
+func bundleComponents() map[string]*Product {
+	return map[string]*Product{
+		"shirt": {ID: "shirt", SKU: "SHIRT", Prices: []Price{{Currency: "SEK", Amount: 299}, {Currency: "EUR", Amount: 26}}, Variants: []Variant{
+			{ID: "s", SKU: "SHIRT-S", Stock: []Stock{{LocationID: "wh1", Quantity: 5}, {LocationID: "wh2", Quantity: 2}}},
+			{ID: "l", SKU: "SHIRT-L", Prices: []Price{{Currency: "SEK", Amount: 349}}, Stock: []Stock{{LocationID: "wh1", Backorder: true}}},
+		}},
+		"tie": {ID: "tie", SKU: "TIE", Prices: []Price{{Currency: "SEK", Amount: 199.5}}},
+	}
+}
+
+func TestBundleSummarize(t *testing.T) {
+	set := &Product{ID: "set", Prices: []Price{{Currency: "SEK", Amount: 699}}, Bundle: &Bundle{
+		Components: []BundleComponent{{ProductID: "shirt", VariantID: "s", Quantity: 2}, {ProductID: "tie", Quantity: 1}},
+	}}
+
+	summary := set.Bundle.Summarize(set, bundleComponents())
+	assert.Equal(t, BundlePriceSum, summary.PriceStrategy)
+	// EUR is left out, the tie has no price in it
+	assert.Equal(t, []Price{{Currency: "SEK", Amount: 797.5}}, summary.Prices)
+	assert.Equal(t, summary.Prices, summary.ComponentSum)
+	// 7 small shirts make 3 bundles of 2; the tie has no stock to count
+	if assert.NotNil(t, summary.Available) {
+		assert.Equal(t, 3, *summary.Available)
+	}
+	assert.Equal(t, "SHIRT-S", summary.Components[0].SKU)
+	assert.Equal(t, 7, *summary.Components[0].InStock)
+	assert.Nil(t, summary.Components[1].InStock)
+
+	set.Bundle.PriceStrategy = BundlePriceDiscount
+	set.Bundle.DiscountPercent = 15
+	summary = set.Bundle.Summarize(set, bundleComponents())
+	assert.Equal(t, []Price{{Currency: "SEK", Amount: 677.88}}, summary.Prices)
+
+	set.Bundle.PriceStrategy = BundlePriceFixed
+	summary = set.Bundle.Summarize(set, bundleComponents())
+	assert.Equal(t, []Price{{Currency: "SEK", Amount: 699}}, summary.Prices)
+}
+
+func TestBundleSummarizeAvailability(t *testing.T) {
+	set := &Product{ID: "set", Bundle: &Bundle{Components: []BundleComponent{{ProductID: "shirt", VariantID: "l", Quantity: 1}}}}
+
+	// Backorders do not limit the bundle
+	summary := set.Bundle.Summarize(set, bundleComponents())
+	assert.Nil(t, summary.Available)
+	assert.Equal(t, []Price{{Currency: "SEK", Amount: 349}, {Currency: "EUR", Amount: 26}}, summary.Prices)
+
+	// A deleted component makes the bundle unavailable and unpriced
+	set.Bundle.Components = append(set.Bundle.Components, BundleComponent{ProductID: "gone", Quantity: 1})
+	summary = set.Bundle.Summarize(set, bundleComponents())
+	assert.Equal(t, 0, *summary.Available)
+	assert.Empty(t, summary.Prices)
+	assert.False(t, summary.Components[1].Available)
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+	"time"
+)
+
+// CatalogFilter selects the products of a catalog export. An empty filter selects every product.
+type CatalogFilter struct {
+	IDs       []string `json:"ids,omitempty"`
+	SKUPrefix string   `json:"sku_prefix,omitempty"`
+	Market    string   `json:"market,omitempty"` // Only products with metadata for the market
+	Tag       string   `json:"tag,omitempty"`    // Only products with the tag
+}
+
+// Matches reports whether a product is selected by the filter
+func (f CatalogFilter) Matches(p *Product) bool {
+	if len(f.IDs) > 0 {
+		listed := false
+		for _, id := range f.IDs {
+			if id == p.ID {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			return false
+		}
+	}
+	if f.SKUPrefix != "" && !strings.HasPrefix(p.SKU, f.SKUPrefix) {
+		return false
+	}
+	if f.Market != "" && p.MetadataForMarket(f.Market) == nil {
+		return false
+	}
+	if f.Tag != "" && !p.HasTags(f.Tag) {
+		return false
+	}
+	return true
+}
+
+// CatalogExport is a portable copy of the selected products of one environment,
+// which another environment can import
+type CatalogExport struct {
+	ExportedAt       time.Time     `json:"exported_at"`
+	Filter           CatalogFilter `json:"filter"`
+	PricesAnonymized bool          `json:"prices_anonymized"`
+	Products         []*Product    `json:"products"`
+}
+
+// AnonymizePrices replaces every price of the export with a made-up amount
+func (e *CatalogExport) AnonymizePrices() {
+	for _, product := range e.Products {
+		for i := range product.Prices {
+			product.Prices[i].Amount = anonymizedAmount(product.SKU, product.Prices[i].Currency)
+		}
+	}
+	e.PricesAnonymized = true
+}
+
+// anonymizedAmount derives a price between 10 and 1000 from the SKU and currency,
+// so repeated clones into a test environment get the same prices
+func anonymizedAmount(sku, currency string) float64 {
+	sum := sha256.Sum256([]byte(sku + "/" + currency))
+	cents := 1000 + binary.BigEndian.Uint64(sum[:8])%99000
+	return float64(cents) / 100
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCatalogFilterMatches(t *testing.T) {
+	product := &Product{ID: "prod_1", SKU: "SHIRT-1", Metadata: []MarketMetadata{{Market: "SE", Title: "Skjorta"}}}
+
+	tests := []struct {
+		name   string
+		filter CatalogFilter
+		want   bool
+	}{
+		{"empty filter", CatalogFilter{}, true},
+		{"listed id", CatalogFilter{IDs: []string{"prod_2", "prod_1"}}, true},
+		{"other ids", CatalogFilter{IDs: []string{"prod_2"}}, false},
+		{"sku prefix", CatalogFilter{SKUPrefix: "SHIRT"}, true},
+		{"other sku prefix", CatalogFilter{SKUPrefix: "SOCK"}, false},
+		{"market", CatalogFilter{Market: "se"}, true},
+		{"other market", CatalogFilter{Market: "NO"}, false},
+		{"all conditions", CatalogFilter{IDs: []string{"prod_1"}, SKUPrefix: "SHIRT", Market: "NO"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.Matches(product))
+		})
+	}
+}
+
+func TestCatalogExportAnonymizePrices(t *testing.T) {
+	export := &CatalogExport{Products: []*Product{
+		{SKU: "SHIRT-1", Prices: []Price{{Currency: "SEK", Amount: 299}, {Currency: "EUR", Amount: 29}}},
+	}}
+	again := &CatalogExport{Products: []*Product{
+		{SKU: "SHIRT-1", Prices: []Price{{Currency: "SEK", Amount: 399}}},
+	}}
+
+	export.AnonymizePrices()
+	again.AnonymizePrices()
+
+	prices := export.Products[0].Prices
+	assert.True(t, export.PricesAnonymized)
+	assert.NotEqual(t, 299.0, prices[0].Amount)
+	assert.NotEqual(t, prices[0].Amount, prices[1].Amount)
+	// The same SKU and currency always get the same amount
+	assert.Equal(t, prices[0].Amount, again.Products[0].Prices[0].Amount)
+	for _, price := range prices {
+		assert.GreaterOrEqual(t, price.Amount, 10.0)
+		assert.Less(t, price.Amount, 1000.0)
+	}
+}
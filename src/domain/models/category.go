@@ -0,0 +1,228 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxCategoryDepth is the deepest a category can be nested, counting the root as 1
+const MaxCategoryDepth = 10
+
+// MaxProductCategories is the largest number of categories a product can be in
+const MaxProductCategories = 50
+
+// categorySlug matches lower-case words of letters and digits joined by hyphens
+var categorySlug = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Category is a node of the product taxonomy. Categories without a parent
+// are roots; slugs are unique among the children of a parent, so a path of
+// slugs such as clothing/t-shirts names one category.
+type Category struct {
+	ID        string            `json:"id"`
+	Slug      string            `json:"slug" example:"t-shirts"`
+	ParentID  string            `json:"parent_id,omitempty"`
+	Names     map[string]string `json:"names"`              // Per market, e.g. {"SE": "T-shirts", "DE": "T-Shirts"}
+	Position  int               `json:"position,omitempty"` // Order among the siblings, lowest first
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// CategoryNode is a category with its children, as returned by the tree listing
+type CategoryNode struct {
+	*Category
+	Children []*CategoryNode `json:"children,omitempty"`
+}
+
+// CategoryEvent contains the data of category events
+type CategoryEvent struct {
+	CategoryID string    `json:"category_id"`
+	Action     string    `json:"action"`
+	Category   *Category `json:"category"`
+}
+
+// Validate normalizes the category and checks its own fields. Whether the
+// parent exists and the slug is free is checked against the other categories.
+func (c *Category) Validate() error {
+	c.Slug = strings.ToLower(strings.TrimSpace(c.Slug))
+	c.ParentID = strings.TrimSpace(c.ParentID)
+	if !categorySlug.MatchString(c.Slug) || len(c.Slug) > 100 {
+		return fmt.Errorf("%w: slug must be lower-case letters, digits and hyphens, at most 100 characters", ErrInvalidRequest)
+	}
+	if c.ParentID != "" && c.ParentID == c.ID {
+		return fmt.Errorf("%w: a category cannot be its own parent", ErrInvalidRequest)
+	}
+	if len(c.Names) == 0 {
+		return fmt.Errorf("%w: at least one market name is required", ErrInvalidRequest)
+	}
+	names := make(map[string]string, len(c.Names))
+	for market, name := range c.Names {
+		market = NormalizeMarket(market)
+		name = strings.TrimSpace(name)
+		if market == "" || name == "" {
+			return fmt.Errorf("%w: names need a market and a name", ErrInvalidRequest)
+		}
+		names[market] = name
+	}
+	c.Names = names
+	return nil
+}
+
+// NameFor returns the name of the category in a market
+func (c *Category) NameFor(market string) (string, bool) {
+	name, ok := c.Names[NormalizeMarket(market)]
+	return name, ok
+}
+
+// CategoryTree indexes categories by ID and parent
+type CategoryTree struct {
+	byID     map[string]*Category
+	children map[string][]*Category
+}
+
+// NewCategoryTree indexes the categories. Children are ordered by position, then slug.
+func NewCategoryTree(categories []*Category) *CategoryTree {
+	tree := &CategoryTree{
+		byID:     make(map[string]*Category, len(categories)),
+		children: make(map[string][]*Category),
+	}
+	for _, category := range categories {
+		tree.byID[category.ID] = category
+		tree.children[category.ParentID] = append(tree.children[category.ParentID], category)
+	}
+	for _, siblings := range tree.children {
+		sort.Slice(siblings, func(i, j int) bool {
+			if siblings[i].Position != siblings[j].Position {
+				return siblings[i].Position < siblings[j].Position
+			}
+			return siblings[i].Slug < siblings[j].Slug
+		})
+	}
+	return tree
+}
+
+// Get returns a category by ID
+func (t *CategoryTree) Get(id string) (*Category, bool) {
+	category, ok := t.byID[id]
+	return category, ok
+}
+
+// Children returns the direct children of a category; the empty ID has the roots
+func (t *CategoryTree) Children(id string) []*Category {
+	return t.children[id]
+}
+
+// Descendants returns the IDs of a category and every category below it
+func (t *CategoryTree) Descendants(id string) []string {
+	ids := []string{id}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range t.children[ids[i]] {
+			ids = append(ids, child.ID)
+		}
+	}
+	return ids
+}
+
+// Path returns the slugs from the root down to a category, e.g. "clothing/t-shirts"
+func (t *CategoryTree) Path(id string) string {
+	var slugs []string
+	for category, ok := t.byID[id]; ok && len(slugs) <= MaxCategoryDepth; category, ok = t.byID[category.ParentID] {
+		slugs = append([]string{category.Slug}, slugs...)
+	}
+	return strings.Join(slugs, "/")
+}
+
+// Nodes returns the categories as nested nodes, starting at the roots
+func (t *CategoryTree) Nodes() []*CategoryNode {
+	return t.nodes("")
+}
+
+func (t *CategoryTree) nodes(parentID string) []*CategoryNode {
+	children := t.children[parentID]
+	nodes := make([]*CategoryNode, 0, len(children))
+	for _, child := range children {
+		nodes = append(nodes, &CategoryNode{Category: child, Children: t.nodes(child.ID)})
+	}
+	return nodes
+}
+
+// CheckPlacement checks that a category can be saved in the tree: its parent
+// exists, it does not become an ancestor of itself, the tree stays at most
+// MaxCategoryDepth deep and no sibling has its slug
+func (t *CategoryTree) CheckPlacement(category *Category) error {
+	depth := 1
+	for parentID := category.ParentID; parentID != ""; depth++ {
+		if parentID == category.ID {
+			return fmt.Errorf("%w: a category cannot be moved below itself", ErrInvalidRequest)
+		}
+		parent, ok := t.byID[parentID]
+		if !ok {
+			return fmt.Errorf("%w: parent category %s not found", ErrInvalidRequest, parentID)
+		}
+		if depth >= MaxCategoryDepth {
+			return fmt.Errorf("%w: categories can be nested at most %d deep", ErrInvalidRequest, MaxCategoryDepth)
+		}
+		parentID = parent.ParentID
+	}
+	if depth+t.height(category.ID)-1 > MaxCategoryDepth {
+		return fmt.Errorf("%w: categories can be nested at most %d deep", ErrInvalidRequest, MaxCategoryDepth)
+	}
+	for _, sibling := range t.children[category.ParentID] {
+		if sibling.ID != category.ID && sibling.Slug == category.Slug {
+			return fmt.Errorf("%w: %s already has a child with slug %s", ErrDuplicateCategorySlug, parentOrRoot(category.ParentID), category.Slug)
+		}
+	}
+	return nil
+}
+
+// height returns the levels of a category and the categories below it
+func (t *CategoryTree) height(id string) int {
+	if _, ok := t.byID[id]; !ok {
+		return 1
+	}
+	height := 0
+	for _, child := range t.children[id] {
+		if h := t.height(child.ID); h > height {
+			height = h
+		}
+	}
+	return height + 1
+}
+
+// parentOrRoot names a parent in errors
+func parentOrRoot(parentID string) string {
+	if parentID == "" {
+		return "the root"
+	}
+	return "category " + parentID
+}
+
+// NormalizeCategoryIDs trims category IDs and drops empty and repeated ones,
+// keeping the order. Without IDs left it returns nil.
+func NormalizeCategoryIDs(ids []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		normalized = append(normalized, id)
+	}
+	return normalized
+}
+
+// InCategory reports whether the product is in one of the categories
+func (p *Product) InCategory(ids ...string) bool {
+	for _, assigned := range p.CategoryIDs {
+		for _, id := range ids {
+			if assigned == id {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryValidate(t *testing.T) {
+	category := &Category{Slug: " T-Shirts ", Names: map[string]string{"se ": " T-shirts "}}
+	assert.NoError(t, category.Validate())
+	assert.Equal(t, "t-shirts", category.Slug)
+	assert.Equal(t, map[string]string{"SE": "T-shirts"}, category.Names)
+
+	name, ok := category.NameFor("se")
+	assert.True(t, ok)
+	assert.Equal(t, "T-shirts", name)
+
+	tests := map[string]Category{
+		"slug":         {Slug: "t shirts", Names: map[string]string{"SE": "T-shirts"}},
+		"empty slug":   {Names: map[string]string{"SE": "T-shirts"}},
+		"own parent":   {ID: "cat_1", ParentID: "cat_1", Slug: "shirts", Names: map[string]string{"SE": "Skjortor"}},
+		"no names":     {Slug: "shirts"},
+		"empty name":   {Slug: "shirts", Names: map[string]string{"SE": " "}},
+		"empty market": {Slug: "shirts", Names: map[string]string{"": "Skjortor"}},
+	}
+	for name, category := range tests {
+		assert.ErrorIs(t, category.Validate(), ErrInvalidRequest, name)
+	}
+}
+
+// testCategoryTree is clothing > (shirts > t-shirts, trousers) and shoes
+func testCategoryTree() *CategoryTree {
+	return NewCategoryTree([]*Category{
+		{ID: "tshirts", ParentID: "shirts", Slug: "t-shirts"},
+		{ID: "trousers", ParentID: "clothing", Slug: "trousers", Position: 2},
+		{ID: "shirts", ParentID: "clothing", Slug: "shirts", Position: 1},
+		{ID: "shoes", Slug: "shoes"},
+		{ID: "clothing", Slug: "clothing"},
+	})
+}
+
+func TestCategoryTree(t *testing.T) {
+	tree := testCategoryTree()
+
+	assert.Equal(t, []string{"clothing", "shirts", "trousers", "tshirts"}, tree.Descendants("clothing"))
+	assert.Equal(t, []string{"tshirts"}, tree.Descendants("tshirts"))
+	assert.Equal(t, "clothing/shirts/t-shirts", tree.Path("tshirts"))
+	assert.Empty(t, tree.Path("unknown"))
+
+	nodes := tree.Nodes()
+	assert.Len(t, nodes, 2)
+	assert.Equal(t, "clothing", nodes[0].Slug)
+	assert.Equal(t, "shirts", nodes[0].Children[0].Slug)
+	assert.Equal(t, "t-shirts", nodes[0].Children[0].Children[0].Slug)
+	assert.Equal(t, "trousers", nodes[0].Children[1].Slug)
+}
+
+func TestCategoryTreeCheckPlacement(t *testing.T) {
+	tree := testCategoryTree()
+
+	assert.NoError(t, tree.CheckPlacement(&Category{ID: "new", ParentID: "shirts", Slug: "polo"}))
+	assert.NoError(t, tree.CheckPlacement(&Category{ID: "shirts", ParentID: "shoes", Slug: "shirts"}))
+	// A category may keep its own slug
+	assert.NoError(t, tree.CheckPlacement(&Category{ID: "trousers", ParentID: "clothing", Slug: "trousers"}))
+
+	assert.ErrorIs(t, tree.CheckPlacement(&Category{ID: "new", ParentID: "missing", Slug: "polo"}), ErrInvalidRequest)
+	assert.ErrorIs(t, tree.CheckPlacement(&Category{ID: "clothing", ParentID: "tshirts", Slug: "clothing"}), ErrInvalidRequest)
+	assert.ErrorIs(t, tree.CheckPlacement(&Category{ID: "new", ParentID: "clothing", Slug: "shirts"}), ErrDuplicateCategorySlug)
+	assert.ErrorIs(t, tree.CheckPlacement(&Category{ID: "new", Slug: "shoes"}), ErrDuplicateCategorySlug)
+}
+
+func TestCategoryTreeCheckPlacementDepth(t *testing.T) {
+	categories := []*Category{{ID: "c1", Slug: "c1"}}
+	for i := 2; i <= MaxCategoryDepth; i++ {
+		categories = append(categories, &Category{ID: "c" + string(rune('0'+i)), ParentID: categories[i-2].ID, Slug: "c"})
+	}
+	tree := NewCategoryTree(categories)
+	deepest := categories[len(categories)-1].ID
+
+	assert.ErrorIs(t, tree.CheckPlacement(&Category{ID: "new", ParentID: deepest, Slug: "too-deep"}), ErrInvalidRequest)
+	// Moving a subtree counts the levels below it
+	assert.ErrorIs(t, tree.CheckPlacement(&Category{ID: "c2", ParentID: "c3", Slug: "c"}), ErrInvalidRequest)
+	assert.ErrorIs(t, tree.CheckPlacement(&Category{ID: "c2", ParentID: "other", Slug: "c"}), ErrInvalidRequest)
+	assert.NoError(t, tree.CheckPlacement(&Category{ID: "new", ParentID: categories[len(categories)-2].ID, Slug: "fits"}))
+}
+
+func TestNormalizeCategoryIDs(t *testing.T) {
+	assert.Equal(t, []string{"b", "a"}, NormalizeCategoryIDs([]string{" b", "a", "", "b"}))
+	assert.Nil(t, NormalizeCategoryIDs([]string{" "}))
+}
@@ -0,0 +1,166 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// ComparedProduct identifies a product column in a comparison
+type ComparedProduct struct {
+	ID        string   `json:"id"`
+	SKU       string   `json:"sku"`
+	BaseTitle string   `json:"base_title"`
+	Markets   []string `json:"markets"`
+}
+
+// PriceRow compares the price in one currency across products.
+// Amounts are aligned with ProductComparison.Products; nil means no price in that currency.
+// With variant price overrides Amounts holds the lowest variant price and
+// MaxAmounts the highest; they are equal when the price does not vary.
+type PriceRow struct {
+	Currency   string     `json:"currency"`
+	Amounts    []*float64 `json:"amounts"`
+	MaxAmounts []*float64 `json:"max_amounts"`
+	Shared     bool       `json:"shared"`
+}
+
+// AttributeRow compares one variant attribute across products.
+// Values are aligned with ProductComparison.Products and hold the distinct values of all variants.
+type AttributeRow struct {
+	Name   string     `json:"name"`
+	Values [][]string `json:"values"`
+	Shared bool       `json:"shared"`
+}
+
+// StockSummary holds the total stock of a product across variants and locations
+type StockSummary struct {
+	Quantity int  `json:"quantity"`
+	InStock  bool `json:"in_stock"`
+}
+
+// ProductComparison is an attribute-aligned comparison matrix of several products
+type ProductComparison struct {
+	Products   []ComparedProduct `json:"products"`
+	Prices     []PriceRow        `json:"prices"`
+	Attributes []AttributeRow    `json:"attributes"`
+	Stock      []StockSummary    `json:"stock"`
+}
+
+// CompareProducts builds a comparison matrix where every row is aligned with the given products.
+// Attribute names are unified case-insensitively so "Color" and "color" end up on the same row.
+func CompareProducts(products []*Product) *ProductComparison {
+	comparison := &ProductComparison{
+		Products: make([]ComparedProduct, len(products)),
+		Stock:    make([]StockSummary, len(products)),
+	}
+
+	prices := make(map[string][]*float64)
+	maxPrices := make(map[string][]*float64)
+	attributes := make(map[string][]map[string]bool)
+
+	for i, product := range products {
+		markets := make([]string, 0, len(product.Metadata))
+		for _, metadata := range product.Metadata {
+			markets = append(markets, metadata.Market)
+		}
+		comparison.Products[i] = ComparedProduct{
+			ID:        product.ID,
+			SKU:       product.SKU,
+			BaseTitle: product.BaseTitle,
+			Markets:   markets,
+		}
+
+		for _, price := range product.Prices {
+			currency := strings.ToUpper(price.Currency)
+			if prices[currency] == nil {
+				prices[currency] = make([]*float64, len(products))
+				maxPrices[currency] = make([]*float64, len(products))
+			}
+			lowest, highest, _ := product.PriceRange(currency)
+			prices[currency][i] = &lowest
+			maxPrices[currency][i] = &highest
+		}
+
+		for _, variant := range product.Variants {
+			for name, value := range variant.Attributes {
+				key := normalizeAttributeName(name)
+				if attributes[key] == nil {
+					attributes[key] = make([]map[string]bool, len(products))
+				}
+				if attributes[key][i] == nil {
+					attributes[key][i] = make(map[string]bool)
+				}
+				attributes[key][i][strings.TrimSpace(value)] = true
+			}
+
+			for _, stock := range variant.Stock {
+				comparison.Stock[i].Quantity += stock.Quantity
+			}
+		}
+		comparison.Stock[i].InStock = comparison.Stock[i].Quantity > 0
+	}
+
+	comparison.Prices = make([]PriceRow, 0, len(prices))
+	for currency, amounts := range prices {
+		comparison.Prices = append(comparison.Prices, PriceRow{
+			Currency:   currency,
+			Amounts:    amounts,
+			MaxAmounts: maxPrices[currency],
+			Shared:     sharedAmounts(amounts) && sharedAmounts(maxPrices[currency]),
+		})
+	}
+	sort.Slice(comparison.Prices, func(i, j int) bool {
+		return comparison.Prices[i].Currency < comparison.Prices[j].Currency
+	})
+
+	comparison.Attributes = make([]AttributeRow, 0, len(attributes))
+	for name, perProduct := range attributes {
+		values := make([][]string, len(products))
+		for i, set := range perProduct {
+			values[i] = sortedKeys(set)
+		}
+		comparison.Attributes = append(comparison.Attributes, AttributeRow{
+			Name:   name,
+			Values: values,
+			Shared: sharedValues(values),
+		})
+	}
+	sort.Slice(comparison.Attributes, func(i, j int) bool {
+		return comparison.Attributes[i].Name < comparison.Attributes[j].Name
+	})
+
+	return comparison
+}
+
+func normalizeAttributeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sharedAmounts reports whether every product has the same price
+func sharedAmounts(amounts []*float64) bool {
+	for _, amount := range amounts {
+		if amount == nil || *amount != *amounts[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// sharedValues reports whether every product has the same, non-empty set of values
+func sharedValues(values [][]string) bool {
+	for _, v := range values {
+		if len(v) == 0 || strings.Join(v, "\x00") != strings.Join(values[0], "\x00") {
+			return false
+		}
+	}
+	return true
+}
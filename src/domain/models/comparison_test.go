@@ -0,0 +1,95 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createComparisonProducts() []*Product {
+	return []*Product{
+		{
+			ID:        "prod_1",
+			SKU:       "SHIRT-1",
+			BaseTitle: "Shirt",
+			Prices:    []Price{{Currency: "SEK", Amount: 299}, {Currency: "EUR", Amount: 29}},
+			Metadata:  []MarketMetadata{{Market: "SE", Title: "Tröja"}},
+			Variants: []Variant{
+				{ID: "v1", SKU: "SHIRT-1-S", Attributes: map[string]string{"Color": "blue", "size": "S"}, Stock: []Stock{{LocationID: "wh1", Quantity: 2}}},
+				{ID: "v2", SKU: "SHIRT-1-M", Attributes: map[string]string{"color": "blue", "size": "M"}, Stock: []Stock{{LocationID: "wh1", Quantity: 3}}},
+			},
+		},
+		{
+			ID:        "prod_2",
+			SKU:       "SHIRT-2",
+			BaseTitle: "Other shirt",
+			Prices:    []Price{{Currency: "SEK", Amount: 299}},
+			Metadata:  []MarketMetadata{{Market: "SE", Title: "Annan tröja"}, {Market: "NO", Title: "Genser"}},
+			Variants: []Variant{
+				{ID: "v3", SKU: "SHIRT-2-L", Attributes: map[string]string{"color ": "blue", "size": "L", "material": "cotton"}},
+			},
+		},
+	}
+}
+
+func TestCompareProductsColumns(t *testing.T) {
+	comparison := CompareProducts(createComparisonProducts())
+
+	assert.Len(t, comparison.Products, 2)
+	assert.Equal(t, "prod_1", comparison.Products[0].ID)
+	assert.Equal(t, []string{"SE", "NO"}, comparison.Products[1].Markets)
+}
+
+func TestCompareProductsPrices(t *testing.T) {
+	comparison := CompareProducts(createComparisonProducts())
+
+	assert.Len(t, comparison.Prices, 2)
+
+	eur := comparison.Prices[0]
+	assert.Equal(t, "EUR", eur.Currency)
+	assert.Equal(t, 29.0, *eur.Amounts[0])
+	assert.Nil(t, eur.Amounts[1])
+	assert.False(t, eur.Shared)
+
+	sek := comparison.Prices[1]
+	assert.Equal(t, "SEK", sek.Currency)
+	assert.True(t, sek.Shared)
+}
+
+func TestCompareProductsVariantPriceRange(t *testing.T) {
+	products := createComparisonProducts()
+	products[0].Variants[1].Prices = []Price{{Currency: "SEK", Amount: 349}}
+
+	sek := CompareProducts(products).Prices[1]
+	assert.Equal(t, 299.0, *sek.Amounts[0])
+	assert.Equal(t, 349.0, *sek.MaxAmounts[0])
+	assert.Equal(t, 299.0, *sek.MaxAmounts[1])
+	assert.False(t, sek.Shared)
+}
+
+func TestCompareProductsAttributesAreUnified(t *testing.T) {
+	comparison := CompareProducts(createComparisonProducts())
+
+	rows := make(map[string]AttributeRow)
+	for _, row := range comparison.Attributes {
+		rows[row.Name] = row
+	}
+	assert.Len(t, rows, 3)
+
+	// "Color", "color" and "color " end up on the same row
+	assert.Equal(t, [][]string{{"blue"}, {"blue"}}, rows["color"].Values)
+	assert.True(t, rows["color"].Shared)
+
+	assert.Equal(t, [][]string{{"M", "S"}, {"L"}}, rows["size"].Values)
+	assert.False(t, rows["size"].Shared)
+
+	assert.Equal(t, [][]string{{}, {"cotton"}}, rows["material"].Values)
+	assert.False(t, rows["material"].Shared)
+}
+
+func TestCompareProductsStock(t *testing.T) {
+	comparison := CompareProducts(createComparisonProducts())
+
+	assert.Equal(t, StockSummary{Quantity: 5, InStock: true}, comparison.Stock[0])
+	assert.Equal(t, StockSummary{Quantity: 0, InStock: false}, comparison.Stock[1])
+}
@@ -0,0 +1,107 @@
+package models
+
+import "strings"
+
+// LaunchBlockerCode identifies why a product cannot launch in a market
+type LaunchBlockerCode string
+
+const (
+	BlockerMissingTranslation LaunchBlockerCode = "missing_translation"
+	BlockerMissingPrice       LaunchBlockerCode = "missing_price"
+	BlockerNoStock            LaunchBlockerCode = "no_stock"
+	BlockerNoImage            LaunchBlockerCode = "no_image"
+)
+
+// LaunchBlocker describes a single reason a product is blocked from launch
+type LaunchBlocker struct {
+	Code   LaunchBlockerCode `json:"code"`
+	Reason string            `json:"reason"`
+}
+
+// BlockedProduct lists the blockers for one product
+type BlockedProduct struct {
+	ProductID string          `json:"product_id"`
+	SKU       string          `json:"sku"`
+	Blockers  []LaunchBlocker `json:"blockers"`
+}
+
+// LaunchChecklist summarizes launch readiness of the catalog for a market
+type LaunchChecklist struct {
+	Market        string            `json:"market"`
+	Currency      string            `json:"currency"`
+	TotalProducts int               `json:"total_products"`
+	ReadyCount    int               `json:"ready_count"`
+	BlockedCount  int               `json:"blocked_count"`
+	Blocked       []*BlockedProduct `json:"blocked"`
+}
+
+// CheckMarketLaunch returns the reasons the product cannot launch in the market.
+// An empty result means the product is ready.
+func CheckMarketLaunch(p *Product, market, currency string) []LaunchBlocker {
+	blockers := make([]LaunchBlocker, 0)
+
+	if metadata := p.MetadataForMarket(market); metadata == nil {
+		blockers = append(blockers, LaunchBlocker{
+			Code:   BlockerMissingTranslation,
+			Reason: "no metadata for market " + market,
+		})
+	} else if strings.TrimSpace(metadata.Title) == "" {
+		blockers = append(blockers, LaunchBlocker{
+			Code:   BlockerMissingTranslation,
+			Reason: "title is empty for market " + market,
+		})
+	}
+
+	// Every variant must resolve to a price, so a zero product price is fine
+	// when each variant overrides it
+	if lowest, _, priced := p.PriceRange(currency); !priced || lowest <= 0 {
+		blockers = append(blockers, LaunchBlocker{
+			Code:   BlockerMissingPrice,
+			Reason: "no price in " + strings.ToUpper(currency),
+		})
+	}
+
+	if p.TotalStock() == 0 {
+		blockers = append(blockers, LaunchBlocker{
+			Code:   BlockerNoStock,
+			Reason: "no variant has stock",
+		})
+	}
+
+	if len(p.Images) == 0 {
+		blockers = append(blockers, LaunchBlocker{
+			Code:   BlockerNoImage,
+			Reason: "product has no images",
+		})
+	}
+
+	for _, issue := range p.ComplianceIssues(market) {
+		blockers = append(blockers, LaunchBlocker{
+			Code:   BlockerMissingCompliance,
+			Reason: issue,
+		})
+	}
+
+	return blockers
+}
+
+// MetadataForMarket returns the product metadata for a market, or nil if missing
+func (p *Product) MetadataForMarket(market string) *MarketMetadata {
+	for i := range p.Metadata {
+		if strings.EqualFold(p.Metadata[i].Market, market) {
+			return &p.Metadata[i]
+		}
+	}
+	return nil
+}
+
+// TotalStock returns the stock quantity summed over all variants and locations
+func (p *Product) TotalStock() int {
+	total := 0
+	for _, variant := range p.Variants {
+		for _, stock := range variant.Stock {
+			total += stock.Quantity
+		}
+	}
+	return total
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createLaunchReadyProduct() *Product {
+	return &Product{
+		ID:        "prod_1",
+		SKU:       "SHIRT-1",
+		BaseTitle: "Shirt",
+		Prices:    []Price{{Currency: "SEK", Amount: 299}},
+		Metadata:  []MarketMetadata{{Market: "SE", Title: "Tröja"}},
+		Variants: []Variant{
+			{ID: "v1", SKU: "SHIRT-1-S", Stock: []Stock{{LocationID: "wh1", Quantity: 2}}},
+		},
+		Images: []Image{{URL: "https://cdn.example.com/shirt.jpg"}},
+	}
+}
+
+func blockerCodes(blockers []LaunchBlocker) []LaunchBlockerCode {
+	codes := make([]LaunchBlockerCode, len(blockers))
+	for i, blocker := range blockers {
+		codes[i] = blocker.Code
+	}
+	return codes
+}
+
+func TestCheckMarketLaunchReady(t *testing.T) {
+	product := createLaunchReadyProduct()
+
+	assert.Empty(t, CheckMarketLaunch(product, "SE", "SEK"))
+	assert.Empty(t, CheckMarketLaunch(product, "se", "sek"))
+}
+
+func TestCheckMarketLaunchBlockers(t *testing.T) {
+	product := createLaunchReadyProduct()
+
+	// Another market lacks both translation and price
+	assert.Equal(t,
+		[]LaunchBlockerCode{BlockerMissingTranslation, BlockerMissingPrice},
+		blockerCodes(CheckMarketLaunch(product, "NO", "NOK")))
+
+	product.Metadata[0].Title = " "
+	product.Prices[0].Amount = 0
+	product.Variants[0].Stock[0].Quantity = 0
+	product.Images = nil
+
+	blockers := CheckMarketLaunch(product, "SE", "SEK")
+	assert.Equal(t,
+		[]LaunchBlockerCode{BlockerMissingTranslation, BlockerMissingPrice, BlockerNoStock, BlockerNoImage},
+		blockerCodes(blockers))
+	for _, blocker := range blockers {
+		assert.NotEmpty(t, blocker.Reason)
+	}
+}
+
+func TestCheckMarketLaunchResolvesVariantPrices(t *testing.T) {
+	product := createLaunchReadyProduct()
+	product.Prices[0].Amount = 0
+	product.Variants = append(product.Variants, Variant{ID: "v2", SKU: "SHIRT-1-XL"})
+
+	// The XL variant falls back to the zero product price
+	product.Variants[0].Prices = []Price{{Currency: "SEK", Amount: 279}}
+	assert.Equal(t, []LaunchBlockerCode{BlockerMissingPrice}, blockerCodes(CheckMarketLaunch(product, "SE", "SEK")))
+
+	product.Variants[1].Prices = []Price{{Currency: "SEK", Amount: 319}}
+	assert.Empty(t, CheckMarketLaunch(product, "SE", "SEK"))
+}
+
+func TestCheckMarketLaunchRequiresCompliance(t *testing.T) {
+	product := createLaunchReadyProduct()
+	product.Compliance = &Compliance{RequiredCertifications: map[string][]string{"SE": {"CE"}}}
+
+	blockers := CheckMarketLaunch(product, "SE", "SEK")
+	assert.Equal(t, []LaunchBlocker{{Code: BlockerMissingCompliance, Reason: "certification CE is required in SE"}}, blockers)
+
+	product.Compliance.Certifications = []Certification{{Name: "CE", Reference: "DoC-1"}}
+	assert.Empty(t, CheckMarketLaunch(product, "SE", "SEK"))
+}
+
+func TestCurrencyForMarket(t *testing.T) {
+	currency, ok := CurrencyForMarket("se")
+	assert.True(t, ok)
+	assert.Equal(t, "SEK", currency)
+
+	_, ok = CurrencyForMarket("XX")
+	assert.False(t, ok)
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BlockerMissingCompliance is reported for products that lack the compliance data a market requires
+const BlockerMissingCompliance LaunchBlockerCode = "missing_compliance"
+
+// MaxMinimumAge is the highest age restriction a product can have
+const MaxMinimumAge = 21
+
+var (
+	hazardClassPattern = regexp.MustCompile(`^[1-9](\.[1-6])?$`)
+	unNumberPattern    = regexp.MustCompile(`^UN[0-9]{4}$`)
+)
+
+// energyLabelMarkets are the markets where energy-labelled products must be
+// registered (EPREL in the EU/EEA) and show their label
+var energyLabelMarkets = map[string]bool{
+	"SE": true, "NO": true, "DK": true, "FI": true, "DE": true, "NL": true, "FR": true, "GB": true,
+}
+
+// Compliance holds the regulatory data of a product
+type Compliance struct {
+	MinimumAge             int                 `json:"minimum_age,omitempty"`             // Buyers must be at least this old, e.g. 18 for alcohol
+	HazardClass            string              `json:"hazard_class,omitempty"`            // UN dangerous goods class, e.g. "3" or "2.1"
+	UNNumber               string              `json:"un_number,omitempty"`               // UN number of the hazardous substance, e.g. "UN1263"
+	SafetyDataSheetURL     string              `json:"safety_data_sheet_url,omitempty"`   // Required for hazardous goods
+	EnergyLabel            *EnergyLabel        `json:"energy_label,omitempty"`            // Set for products that carry an energy label
+	RequiredCertifications map[string][]string `json:"required_certifications,omitempty"` // Certifications per market, e.g. {"DE": ["CE", "GS"]}
+	Certifications         []Certification     `json:"certifications,omitempty"`          // Certifications the product holds
+}
+
+// EnergyLabel is the energy efficiency label of a product
+type EnergyLabel struct {
+	Class          string `json:"class"`                     // A to G
+	RegistrationID string `json:"registration_id,omitempty"` // EPREL registration number
+	LabelURL       string `json:"label_url,omitempty"`
+}
+
+// Certification is a certificate the product holds, e.g. CE
+type Certification struct {
+	Name       string     `json:"name"`
+	Reference  string     `json:"reference"` // Certificate or declaration number
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+// Validate checks that the compliance data is well-formed. Whether it is complete
+// for a market is checked by Issues.
+func (c *Compliance) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.MinimumAge < 0 || c.MinimumAge > MaxMinimumAge {
+		return fmt.Errorf("%w: minimum_age must be between 0 and %d", ErrInvalidRequest, MaxMinimumAge)
+	}
+	if c.HazardClass != "" && !hazardClassPattern.MatchString(c.HazardClass) {
+		return fmt.Errorf("%w: hazard_class %q is not a UN class such as 3 or 2.1", ErrInvalidRequest, c.HazardClass)
+	}
+	if c.UNNumber != "" && !unNumberPattern.MatchString(c.UNNumber) {
+		return fmt.Errorf("%w: un_number %q must look like UN1263", ErrInvalidRequest, c.UNNumber)
+	}
+	if c.UNNumber != "" && c.HazardClass == "" {
+		return fmt.Errorf("%w: un_number requires a hazard_class", ErrInvalidRequest)
+	}
+	if c.EnergyLabel != nil && (len(c.EnergyLabel.Class) != 1 || c.EnergyLabel.Class < "A" || c.EnergyLabel.Class > "G") {
+		return fmt.Errorf("%w: energy label class must be A to G", ErrInvalidRequest)
+	}
+	seen := make(map[string]bool, len(c.Certifications))
+	for _, certification := range c.Certifications {
+		if strings.TrimSpace(certification.Name) == "" {
+			return fmt.Errorf("%w: certifications need a name", ErrInvalidRequest)
+		}
+		if seen[certification.Name] {
+			return fmt.Errorf("%w: certification %s is listed more than once", ErrInvalidRequest, certification.Name)
+		}
+		seen[certification.Name] = true
+	}
+	for market, names := range c.RequiredCertifications {
+		for _, name := range names {
+			if strings.TrimSpace(name) == "" {
+				return fmt.Errorf("%w: required certification for %s needs a name", ErrInvalidRequest, market)
+			}
+		}
+	}
+	return nil
+}
+
+// Issues returns the compliance data the product lacks to be sold in the market
+// at the given time. An empty result means the product is compliant.
+func (c *Compliance) Issues(market string, at time.Time) []string {
+	issues := make([]string, 0)
+	if c == nil {
+		return issues
+	}
+	market = strings.ToUpper(market)
+
+	if c.HazardClass != "" {
+		if c.UNNumber == "" {
+			issues = append(issues, "hazardous goods need a UN number")
+		}
+		if c.SafetyDataSheetURL == "" {
+			issues = append(issues, "hazardous goods need a safety data sheet")
+		}
+	}
+
+	if c.EnergyLabel != nil && energyLabelMarkets[market] {
+		if c.EnergyLabel.RegistrationID == "" {
+			issues = append(issues, "energy label needs a registration ID in "+market)
+		}
+		if c.EnergyLabel.LabelURL == "" {
+			issues = append(issues, "energy label needs a label URL in "+market)
+		}
+	}
+
+	for _, name := range c.requiredCertifications(market) {
+		certification := c.certification(name)
+		switch {
+		case certification == nil || certification.Reference == "":
+			issues = append(issues, fmt.Sprintf("certification %s is required in %s", name, market))
+		case certification.ValidUntil != nil && certification.ValidUntil.Before(at):
+			issues = append(issues, fmt.Sprintf("certification %s expired on %s", name, certification.ValidUntil.Format("2006-01-02")))
+		}
+	}
+	return issues
+}
+
+// requiredCertifications returns the sorted certifications a market requires
+func (c *Compliance) requiredCertifications(market string) []string {
+	names := make([]string, 0)
+	for required, list := range c.RequiredCertifications {
+		if strings.EqualFold(required, market) {
+			names = append(names, list...)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// certification returns the held certification with the name, ignoring case
+func (c *Compliance) certification(name string) *Certification {
+	for i := range c.Certifications {
+		if strings.EqualFold(c.Certifications[i].Name, name) {
+			return &c.Certifications[i]
+		}
+	}
+	return nil
+}
+
+// Clone creates a deep copy of the compliance data
+func (c *Compliance) Clone() *Compliance {
+	if c == nil {
+		return nil
+	}
+	clone := *c
+	if c.EnergyLabel != nil {
+		label := *c.EnergyLabel
+		clone.EnergyLabel = &label
+	}
+	if c.RequiredCertifications != nil {
+		clone.RequiredCertifications = make(map[string][]string, len(c.RequiredCertifications))
+		for market, names := range c.RequiredCertifications {
+			clone.RequiredCertifications[market] = append([]string(nil), names...)
+		}
+	}
+	if c.Certifications != nil {
+		clone.Certifications = make([]Certification, len(c.Certifications))
+		copy(clone.Certifications, c.Certifications)
+	}
+	return &clone
+}
+
+// ComplianceIssues returns the compliance data the product lacks for a market right now
+func (p *Product) ComplianceIssues(market string) []string {
+	return p.Compliance.Issues(market, time.Now())
+}
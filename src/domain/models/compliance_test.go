@@ -0,0 +1,108 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComplianceValidate(t *testing.T) {
+	var none *Compliance
+	assert.NoError(t, none.Validate())
+
+	valid := &Compliance{
+		MinimumAge:  18,
+		HazardClass: "2.1",
+		UNNumber:    "UN1950",
+		EnergyLabel: &EnergyLabel{Class: "B"},
+		Certifications: []Certification{
+			{Name: "CE", Reference: "DoC-2024-17"},
+		},
+		RequiredCertifications: map[string][]string{"DE": {"CE"}},
+	}
+	assert.NoError(t, valid.Validate())
+
+	tests := []struct {
+		name       string
+		compliance *Compliance
+	}{
+		{"negative age", &Compliance{MinimumAge: -1}},
+		{"age too high", &Compliance{MinimumAge: 30}},
+		{"unknown hazard class", &Compliance{HazardClass: "10"}},
+		{"malformed UN number", &Compliance{HazardClass: "3", UNNumber: "1263"}},
+		{"UN number without class", &Compliance{UNNumber: "UN1263"}},
+		{"energy class out of range", &Compliance{EnergyLabel: &EnergyLabel{Class: "A+"}}},
+		{"unnamed certification", &Compliance{Certifications: []Certification{{Reference: "x"}}}},
+		{"duplicate certification", &Compliance{Certifications: []Certification{{Name: "CE"}, {Name: "CE"}}}},
+		{"unnamed requirement", &Compliance{RequiredCertifications: map[string][]string{"DE": {""}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.compliance.Validate(), ErrInvalidRequest)
+		})
+	}
+}
+
+func TestComplianceIssues(t *testing.T) {
+	expiry := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	compliance := &Compliance{
+		HazardClass:            "3",
+		EnergyLabel:            &EnergyLabel{Class: "C", RegistrationID: "123456"},
+		RequiredCertifications: map[string][]string{"DE": {"GS", "CE"}, "US": {"UL"}},
+		Certifications: []Certification{
+			{Name: "ce", Reference: "DoC-1", ValidUntil: &expiry},
+			{Name: "UL", Reference: "E12345"},
+		},
+	}
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []string{
+		"hazardous goods need a UN number",
+		"hazardous goods need a safety data sheet",
+		"energy label needs a label URL in DE",
+		"certification GS is required in DE",
+	}, compliance.Issues("de", before))
+
+	// Energy labels are only required where labelling applies
+	assert.Equal(t, []string{
+		"hazardous goods need a UN number",
+		"hazardous goods need a safety data sheet",
+	}, compliance.Issues("US", before))
+
+	compliance.HazardClass = ""
+	compliance.EnergyLabel = nil
+	compliance.Certifications = append(compliance.Certifications, Certification{Name: "GS", Reference: "GS-9"})
+	assert.Empty(t, compliance.Issues("DE", before))
+	assert.Equal(t, []string{"certification CE expired on 2024-06-30"}, compliance.Issues("DE", expiry.Add(time.Hour)))
+
+	var none *Compliance
+	assert.Empty(t, none.Issues("DE", before))
+}
+
+func TestComplianceClone(t *testing.T) {
+	original := &Compliance{
+		EnergyLabel:            &EnergyLabel{Class: "A"},
+		RequiredCertifications: map[string][]string{"DE": {"CE"}},
+		Certifications:         []Certification{{Name: "CE", Reference: "1"}},
+	}
+	clone := original.Clone()
+	clone.EnergyLabel.Class = "B"
+	clone.RequiredCertifications["DE"][0] = "GS"
+	clone.Certifications[0].Reference = "2"
+
+	assert.Equal(t, "A", original.EnergyLabel.Class)
+	assert.Equal(t, "CE", original.RequiredCertifications["DE"][0])
+	assert.Equal(t, "1", original.Certifications[0].Reference)
+
+	var none *Compliance
+	assert.Nil(t, none.Clone())
+}
+
+func TestComplianceIsHashed(t *testing.T) {
+	product := &Product{ID: "prod_1", SKU: "WINE-1"}
+	plain := product.CalculateHash()
+
+	product.Compliance = &Compliance{MinimumAge: 18}
+	assert.NotEqual(t, plain, product.CalculateHash())
+}
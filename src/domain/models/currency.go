@@ -0,0 +1,206 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// currencyMinorUnits maps the active ISO 4217 currency codes to the number of
+// digits after the decimal separator. Funds, precious metals and testing codes
+// without minor units are left out since products are not priced in them.
+var currencyMinorUnits = minorUnitTable(map[int]string{
+	0: "BIF CLP DJF GNF ISK JPY KMF KRW PYG RWF UGX UYI VND VUV XAF XOF XPF",
+	2: "AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BMD BND BOB BOV BRL BSD BTN BWP BYN BZD " +
+		"CAD CDF CHE CHF CHW CNY COP COU CRC CUP CVE CZK DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP " +
+		"GMD GTQ GYD HKD HNL HTG HUF IDR ILS INR IRR JMD KES KGS KHR KPW KYD KZT LAK LBP LKR LRD LSL MAD MDL " +
+		"MGA MKD MMK MNT MOP MRU MUR MVR MWK MXN MXV MYR MZN NAD NGN NIO NOK NPR NZD PAB PEN PGK PHP PKR PLN " +
+		"QAR RON RSD RUB SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN SVC SYP SZL THB TJS TMT TOP TRY TTD " +
+		"TWD TZS UAH USD USN UZS VED VES WST XCD XCG YER ZAR ZMW ZWG",
+	3: "BHD IQD JOD KWD LYD OMR TND",
+	4: "CLF UYW",
+})
+
+// minorUnitTable expands space separated codes per number of minor units
+func minorUnitTable(codes map[int]string) map[string]int {
+	table := make(map[string]int)
+	for units, list := range codes {
+		for _, code := range strings.Fields(list) {
+			table[code] = units
+		}
+	}
+	return table
+}
+
+// IsCurrencyCode reports whether code is an active ISO 4217 currency code,
+// ignoring case
+func IsCurrencyCode(code string) bool {
+	_, ok := currencyMinorUnits[strings.ToUpper(code)]
+	return ok
+}
+
+// RoundToMinorUnits rounds an amount to the minor units of its currency, e.g.
+// cents for EUR and whole yen for JPY
+func RoundToMinorUnits(amount float64, currency string) float64 {
+	units, ok := currencyMinorUnits[strings.ToUpper(currency)]
+	if !ok {
+		units = 2
+	}
+	scale := math.Pow10(units)
+	return math.Round(amount*scale) / scale
+}
+
+// ExchangeRates are the rates of a provider at a point in time, as units of
+// each currency per unit of the base currency
+type ExchangeRates struct {
+	Base   string             `json:"base" example:"EUR"`
+	Rates  map[string]float64 `json:"rates"`
+	AsOf   time.Time          `json:"as_of"`
+	Source string             `json:"source" example:"ecb"`
+}
+
+// Validate checks that the base and every rate currency are ISO 4217 codes
+// and that the rates are positive. Codes are normalized to upper case.
+func (r *ExchangeRates) Validate() error {
+	r.Base = strings.ToUpper(strings.TrimSpace(r.Base))
+	if !IsCurrencyCode(r.Base) {
+		return fmt.Errorf("%w: base %q", ErrUnknownCurrency, r.Base)
+	}
+	rates := make(map[string]float64, len(r.Rates))
+	for currency, rate := range r.Rates {
+		code := strings.ToUpper(strings.TrimSpace(currency))
+		if !IsCurrencyCode(code) {
+			return fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+		}
+		if !(rate > 0) || math.IsInf(rate, 0) {
+			return fmt.Errorf("%w: the %s rate must be positive", ErrInvalidRequest, code)
+		}
+		rates[code] = rate
+	}
+	r.Rates = rates
+	return nil
+}
+
+// Rate returns the rate to convert an amount from one currency to another,
+// crossing over the base currency when neither is the base
+func (r *ExchangeRates) Rate(from, to string) (float64, error) {
+	fromRate, err := r.baseRate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := r.baseRate(to)
+	if err != nil {
+		return 0, err
+	}
+	return toRate / fromRate, nil
+}
+
+// baseRate returns the units of a currency per unit of the base currency
+func (r *ExchangeRates) baseRate(currency string) (float64, error) {
+	code := strings.ToUpper(currency)
+	if !IsCurrencyCode(code) {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	if code == r.Base {
+		return 1, nil
+	}
+	rate, ok := r.Rates[code]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrExchangeRateNotFound, code)
+	}
+	return rate, nil
+}
+
+// Convert converts an amount between currencies, rounded to the minor units
+// of the target currency
+func (r *ExchangeRates) Convert(amount float64, from, to string) (float64, error) {
+	rate, err := r.Rate(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return RoundToMinorUnits(amount*rate, to), nil
+}
+
+// DisplayPrice is a price shown in a currency the shopper asked for. Prices
+// the product has in that currency are shown as they are; other prices are
+// converted from one of the product's currencies.
+type DisplayPrice struct {
+	Currency       string     `json:"currency" example:"EUR"`
+	Amount         float64    `json:"amount" example:"26.05"`
+	ConvertedFrom  string     `json:"converted_from,omitempty" example:"SEK"`
+	OriginalAmount float64    `json:"original_amount,omitempty" example:"299"`
+	Rate           float64    `json:"rate,omitempty"`
+	RatesAsOf      *time.Time `json:"rates_as_of,omitempty"`
+}
+
+// DisplayPrice returns a price list in the currency. A price in the currency
+// is used unconverted; otherwise the price in the base currency is converted,
+// or else the first price with a rate.
+func (r *ExchangeRates) DisplayPrice(prices []Price, currency string) (*DisplayPrice, error) {
+	code := strings.ToUpper(currency)
+	if !IsCurrencyCode(code) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCurrency, currency)
+	}
+	for _, price := range prices {
+		if strings.EqualFold(price.Currency, code) {
+			return &DisplayPrice{Currency: code, Amount: price.Amount}, nil
+		}
+	}
+
+	candidates := make([]Price, 0, len(prices))
+	for _, price := range prices {
+		if strings.EqualFold(price.Currency, r.Base) {
+			candidates = append([]Price{price}, candidates...)
+		} else {
+			candidates = append(candidates, price)
+		}
+	}
+	for _, price := range candidates {
+		rate, err := r.Rate(price.Currency, code)
+		if err != nil {
+			continue
+		}
+		asOf := r.AsOf
+		return &DisplayPrice{
+			Currency:       code,
+			Amount:         RoundToMinorUnits(price.Amount*rate, code),
+			ConvertedFrom:  strings.ToUpper(price.Currency),
+			OriginalAmount: price.Amount,
+			Rate:           rate,
+			RatesAsOf:      &asOf,
+		}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrExchangeRateNotFound, code)
+}
+
+// DisplayedProduct is a product with its prices shown in a display currency
+type DisplayedProduct struct {
+	*Product
+	DisplayPrice         *DisplayPrice           `json:"display_price"`
+	VariantDisplayPrices map[string]DisplayPrice `json:"variant_display_prices,omitempty"` // Per variant ID, for variants that override a price
+}
+
+// Displayed returns the product with its prices shown in the currency
+func (p *Product) Displayed(rates *ExchangeRates, currency string) (*DisplayedProduct, error) {
+	price, err := rates.DisplayPrice(p.Prices, currency)
+	if err != nil {
+		return nil, err
+	}
+	displayed := &DisplayedProduct{Product: p, DisplayPrice: price}
+	for i := range p.Variants {
+		variant := &p.Variants[i]
+		if len(variant.Prices) == 0 {
+			continue
+		}
+		price, err := rates.DisplayPrice(p.VariantPrices(variant), currency)
+		if err != nil {
+			return nil, err
+		}
+		if displayed.VariantDisplayPrices == nil {
+			displayed.VariantDisplayPrices = make(map[string]DisplayPrice)
+		}
+		displayed.VariantDisplayPrices[variant.ID] = *price
+	}
+	return displayed, nil
+}
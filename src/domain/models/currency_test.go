@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsCurrencyCode(t *testing.T) {
+	for _, code := range []string{"SEK", "eur", "JPY", "KWD", "CLF"} {
+		assert.True(t, IsCurrencyCode(code), code)
+	}
+	for _, code := range []string{"", "KR", "ABC", "XAU", "EURO", "sek "} {
+		assert.False(t, IsCurrencyCode(code), code)
+	}
+}
+
+func TestRoundToMinorUnits(t *testing.T) {
+	assert.Equal(t, 10.13, RoundToMinorUnits(10.125, "EUR"))
+	assert.Equal(t, 1013.0, RoundToMinorUnits(1012.6, "jpy"))
+	assert.Equal(t, 1.235, RoundToMinorUnits(1.2345, "KWD"))
+}
+
+func TestExchangeRatesConvert(t *testing.T) {
+	rates := &ExchangeRates{Base: "eur", Rates: map[string]float64{"sek": 11.5, "NOK": 11.75, "JPY": 160}}
+	assert.NoError(t, rates.Validate())
+	assert.Equal(t, "EUR", rates.Base)
+
+	amount, err := rates.Convert(10, "EUR", "SEK")
+	assert.NoError(t, err)
+	assert.Equal(t, 115.0, amount)
+
+	// Neither currency is the base, so the rate crosses over EUR
+	amount, err = rates.Convert(115, "SEK", "NOK")
+	assert.NoError(t, err)
+	assert.Equal(t, 117.5, amount)
+
+	amount, err = rates.Convert(1, "SEK", "JPY")
+	assert.NoError(t, err)
+	assert.Equal(t, 14.0, amount)
+
+	_, err = rates.Convert(10, "EUR", "USD")
+	assert.ErrorIs(t, err, ErrExchangeRateNotFound)
+	_, err = rates.Convert(10, "EUR", "KRONOR")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestExchangeRatesValidate(t *testing.T) {
+	tests := map[string]ExchangeRates{
+		"base":          {Base: "EURO"},
+		"rate currency": {Base: "EUR", Rates: map[string]float64{"KR": 11}},
+		"zero rate":     {Base: "EUR", Rates: map[string]float64{"SEK": 0}},
+	}
+	for name, rates := range tests {
+		assert.Error(t, rates.Validate(), name)
+	}
+}
+
+func TestProductDisplayed(t *testing.T) {
+	asOf := time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC)
+	rates := &ExchangeRates{Base: "EUR", Rates: map[string]float64{"SEK": 11.5, "NOK": 11.75}, AsOf: asOf}
+	product := &Product{
+		Prices: []Price{{Currency: "NOK", Amount: 235}, {Currency: "EUR", Amount: 20}},
+		Variants: []Variant{
+			{ID: "v1"},
+			{ID: "v2", Prices: []Price{{Currency: "EUR", Amount: 25}}},
+		},
+	}
+
+	// The product's own price in the currency is shown unconverted
+	displayed, err := product.Displayed(rates, "nok")
+	assert.NoError(t, err)
+	assert.Equal(t, &DisplayPrice{Currency: "NOK", Amount: 235}, displayed.DisplayPrice)
+
+	// Other currencies are converted from the base currency price first
+	displayed, err = product.Displayed(rates, "SEK")
+	assert.NoError(t, err)
+	assert.Equal(t, &DisplayPrice{Currency: "SEK", Amount: 230, ConvertedFrom: "EUR", OriginalAmount: 20, Rate: 11.5, RatesAsOf: &asOf}, displayed.DisplayPrice)
+	assert.Equal(t, map[string]DisplayPrice{
+		"v2": {Currency: "SEK", Amount: 287.5, ConvertedFrom: "EUR", OriginalAmount: 25, Rate: 11.5, RatesAsOf: &asOf},
+	}, displayed.VariantDisplayPrices)
+
+	_, err = product.Displayed(rates, "USD")
+	assert.ErrorIs(t, err, ErrExchangeRateNotFound)
+}
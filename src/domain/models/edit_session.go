@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// EditSession is an admin user's claim on a product they are editing. Sessions
+// are advisory: they never block writes, they let admin UIs warn about other
+// editors before an update fails with a version conflict.
+type EditSession struct {
+	ID        string    `json:"id"`
+	ProductID string    `json:"product_id"`
+	Editor    string    `json:"editor"`
+	ClaimedAt time.Time `json:"claimed_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
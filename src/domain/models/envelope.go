@@ -0,0 +1,26 @@
+package models
+
+// Envelope is the response body of API version 2 and later. Every response has
+// the same shape: the payload under data, pagination under meta and failures
+// under errors.
+type Envelope struct {
+	Data   interface{}     `json:"data"`
+	Meta   *EnvelopeMeta   `json:"meta,omitempty"`
+	Errors []EnvelopeError `json:"errors,omitempty"`
+}
+
+// EnvelopeMeta describes the page of a listing
+type EnvelopeMeta struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	TotalItems int `json:"total_items"`
+	TotalPages int `json:"total_pages"`
+}
+
+// EnvelopeError is one failure of a request
+type EnvelopeError struct {
+	Status  int    `json:"status"`          // HTTP status of the response
+	Code    string `json:"code"`            // Machine-readable error code, see APIError
+	Field   string `json:"field,omitempty"` // The request field that failed validation
+	Message string `json:"message"`
+}
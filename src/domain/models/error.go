@@ -2,27 +2,134 @@ package models
 
 import "errors"
 
+// Error kinds. Most domain errors are of a kind, which decides how they are
+// reported, e.g. errors.Is(err, ErrNotFound) holds for ErrProductNotFound and
+// every other error of something that does not exist.
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrConflict    = errors.New("conflict")
+	ErrValidation  = errors.New("validation failed")
+	ErrLockTimeout = errors.New("lock timeout")
+)
+
+// domainError is a domain error of a kind
+type domainError struct {
+	kind    error
+	message string
+}
+
+// newError creates a domain error of the kind
+func newError(kind error, message string) error {
+	return &domainError{kind: kind, message: message}
+}
+
+func (e *domainError) Error() string {
+	return e.message
+}
+
+// Is reports whether the error is of the kind
+func (e *domainError) Is(target error) bool {
+	return target == e.kind
+}
+
 // Common domain errors
 var (
 	// Repository errors
-	ErrProductNotFound = errors.New("product not found")
-	ErrVersionConflict = errors.New("version conflict")
-	ErrInvalidProduct  = errors.New("invalid product")
-	ErrLockFailed      = errors.New("failed to acquire lock")
+	ErrProductNotFound = newError(ErrNotFound, "product not found")
+	ErrVersionConflict = newError(ErrConflict, "version conflict")
+	ErrDuplicateSKU    = newError(ErrConflict, "sku is already used by another product")
+	ErrNotDeleted      = newError(ErrConflict, "product is not deleted")
+	ErrInvalidProduct  = newError(ErrValidation, "invalid product")
+	ErrLockFailed      = newError(ErrLockTimeout, "failed to acquire lock")
+	ErrJobNotFound     = newError(ErrNotFound, "job not found")
+	ErrJobNotFinished  = newError(ErrConflict, "job has not finished")
+
+	// Event errors
+	ErrConsumerNotFound    = newError(ErrNotFound, "event consumer not found")
+	ErrProjectionNotFound  = newError(ErrNotFound, "projection not found")
+	ErrOutboxEntryNotFound = newError(ErrNotFound, "outbox entry not found")
+
+	// History errors
+	ErrVersionNotFound        = newError(ErrNotFound, "version not found")
+	ErrInvalidRollbackVersion = newError(ErrValidation, "invalid rollback version")
+	ErrBrokenEventChain       = errors.New("event chain broken")
+
+	// Import errors
+	ErrInvalidMapping = newError(ErrValidation, "invalid import mapping")
+
+	// Stock errors
+	ErrInsufficientStock = newError(ErrConflict, "insufficient stock")
+	ErrVariantNotFound   = newError(ErrNotFound, "variant not found")
+
+	// Sync errors
+	ErrSyncStatusNotFound  = newError(ErrNotFound, "sync status not found")
+	ErrMarketplaceNotFound = newError(ErrNotFound, "marketplace not found")
+
+	// Catalog cloning errors
+	ErrCatalogSourceNotFound = newError(ErrNotFound, "catalog source not found")
+	ErrCatalogSourceFailed   = errors.New("catalog source failed")
+
+	// Market errors
+	ErrUnknownMarketCurrency = errors.New("no default currency for market")
+
+	// Merchandising errors
+	ErrMerchandisingNotFound = newError(ErrNotFound, "merchandising not found")
+	ErrPinNotFound           = newError(ErrNotFound, "product is not pinned")
+
+	// Allocation errors
+	ErrAllocationPolicyNotFound = newError(ErrNotFound, "allocation policy not found")
+
+	// Pricing errors
+	ErrPriceListNotFound = newError(ErrNotFound, "price list not found")
+	ErrPriceNotFound     = newError(ErrNotFound, "no price in the currency")
+
+	// Category errors
+	ErrCategoryNotFound      = newError(ErrNotFound, "category not found")
+	ErrDuplicateCategorySlug = newError(ErrConflict, "slug is already used by a sibling category")
+	ErrCategoryInUse         = newError(ErrConflict, "category has subcategories or products")
+
+	// Media errors
+	ErrImageNotFound = newError(ErrNotFound, "image not found")
+
+	// Bundle errors
+	ErrNotABundle = errors.New("product is not a bundle")
+
+	// Relation errors
+	ErrRelationNotFound = newError(ErrNotFound, "product relation not found")
+
+	// Currency errors
+	ErrUnknownCurrency      = errors.New("unknown currency code")
+	ErrExchangeRateNotFound = newError(ErrNotFound, "no exchange rate for the currency")
+
+	// Edit session errors
+	ErrEditSessionNotFound = newError(ErrNotFound, "edit session not found")
+
+	// Webhook errors
+	ErrWebhookNotFound = newError(ErrNotFound, "webhook endpoint not found")
+
+	// Note errors
+	ErrNoteNotFound  = newError(ErrNotFound, "note not found")
+	ErrNotNoteAuthor = errors.New("only the author can change a note")
 
 	// API errors
 	ErrInvalidRequest = errors.New("invalid request")
 	ErrInternalError  = errors.New("internal server error")
 )
 
-// APIError represents an error response from the API
+// APIError is the body of every error response. Status stays under "code"
+// for the clients that read it before error codes existed.
 type APIError struct {
-	Message string `json:"message"`
+	Status  int          `json:"code" example:"404"`                     // HTTP status of the response
+	Code    string       `json:"error_code" example:"product_not_found"` // Machine-readable error code
+	Message string       `json:"message" example:"Product not found"`
+	Errors  []FieldError `json:"errors,omitempty"` // The failing fields of a validation error
 }
 
 // NewAPIError creates a new API error
-func NewAPIError(message string) *APIError {
+func NewAPIError(status int, code, message string) *APIError {
 	return &APIError{
+		Status:  status,
+		Code:    code,
 		Message: message,
 	}
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorKinds(t *testing.T) {
+	wrapped := fmt.Errorf("get prod_1: %w", ErrProductNotFound)
+	assert.ErrorIs(t, wrapped, ErrProductNotFound)
+	assert.ErrorIs(t, wrapped, ErrNotFound)
+	assert.NotErrorIs(t, wrapped, ErrConflict)
+	assert.Equal(t, "get prod_1: product not found", wrapped.Error())
+
+	assert.ErrorIs(t, ErrVersionConflict, ErrConflict)
+	assert.ErrorIs(t, ErrInvalidMapping, ErrValidation)
+	assert.ErrorIs(t, fmt.Errorf("%w: sku SKU-1 is being written", ErrLockFailed), ErrLockTimeout)
+	assert.NotErrorIs(t, ErrCategoryNotFound, ErrProductNotFound)
+	assert.NotErrorIs(t, ErrInvalidRequest, ErrValidation)
+
+	invalid := &ValidationError{Fields: []FieldError{{Field: "sku", Tag: "required", Message: "sku is required"}}}
+	assert.ErrorIs(t, invalid, ErrValidation)
+	assert.ErrorIs(t, invalid, ErrInvalidRequest)
+}
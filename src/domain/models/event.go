@@ -1,7 +1,10 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -9,20 +12,49 @@ import (
 type EventType string
 
 const (
-	EventProductCreated EventType = "product.created"
-	EventProductUpdated EventType = "product.updated"
-	EventProductDeleted EventType = "product.deleted"
+	EventProductCreated  EventType = "product.created"
+	EventProductUpdated  EventType = "product.updated"
+	EventProductDeleted  EventType = "product.deleted"
+	EventProductRestored EventType = "product.restored"
+	// EventStockChanged follows the product.updated event of a stock
+	// adjustment, with the same data, for consumers that only track stock
+	EventStockChanged EventType = "stock.changed"
+	// EventPriceChanged is published when a price list changes a product's
+	// price or one of its scheduled prices starts or ends
+	EventPriceChanged EventType = "price.changed"
+	// Category events carry CategoryEvent data; assigning a product to
+	// categories is a product.updated event with action categories_assigned
+	EventCategoryCreated EventType = "category.created"
+	EventCategoryUpdated EventType = "category.updated"
+	EventCategoryDeleted EventType = "category.deleted"
 )
 
+// IsCategoryEvent reports whether events of the type carry CategoryEvent data
+func (t EventType) IsCategoryEvent() bool {
+	return strings.HasPrefix(string(t), "category.")
+}
+
+// EventSchemaVersion is the version of the event format this build publishes.
+// It is raised when a change to events would break consumers, e.g. a field is
+// removed or changes type; added fields keep the version.
+const EventSchemaVersion = 1
+
+// SupportedEventSchemaVersions are the event formats this build reads, e.g.
+// events stored by an earlier build
+var SupportedEventSchemaVersions = []int{1}
+
 // Event represents a domain event
 type Event struct {
-	ID        string      `json:"id"`
-	Type      EventType   `json:"type"`
-	EntityID  string      `json:"entity_id"`
-	Version   int64       `json:"version"`
-	Sequence  int64       `json:"sequence"`
-	Data      interface{} `json:"data"`
-	Timestamp time.Time   `json:"timestamp"`
+	ID       string    `json:"id"`
+	Type     EventType `json:"type"`
+	EntityID string    `json:"entity_id"`
+	Version  int64     `json:"version"`
+	Sequence int64     `json:"sequence"`
+	// SchemaVersion is the event format; events stored before it was recorded are version 1
+	SchemaVersion int         `json:"schema_version"`
+	Data          interface{} `json:"data"`
+	JobID         string      `json:"job_id,omitempty"` // Batch job that caused the event, if any
+	Timestamp     time.Time   `json:"timestamp"`
 }
 
 // ProductEvent contains product-specific event data
@@ -69,6 +101,39 @@ func ValidateEvent(event *Event) error {
 			return errors.New("product is required in product event except for delete events")
 		}
 	}
+	if categoryEvent, ok := event.Data.(*CategoryEvent); ok {
+		if categoryEvent.CategoryID == "" {
+			return errors.New("category ID is required in category event")
+		}
+		if categoryEvent.Category == nil {
+			return errors.New("category is required in category event")
+		}
+	}
 
 	return nil
 }
+
+// DecodeEvent decodes an event encoded as JSON with its data typed by the
+// event type: CategoryEvent for category events and ProductEvent otherwise
+func DecodeEvent(encoded []byte) (*Event, error) {
+	var envelope struct {
+		Event
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(encoded, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid event: %v", err)
+	}
+	event := envelope.Event
+	event.Data = nil
+	if len(envelope.Data) > 0 && string(envelope.Data) != "null" {
+		var data interface{} = &ProductEvent{}
+		if event.Type.IsCategoryEvent() {
+			data = &CategoryEvent{}
+		}
+		if err := json.Unmarshal(envelope.Data, data); err != nil {
+			return nil, fmt.Errorf("invalid event %s data: %v", event.ID, err)
+		}
+		event.Data = data
+	}
+	return &event, nil
+}
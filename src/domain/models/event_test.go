@@ -0,0 +1,27 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	for _, event := range []*Event{
+		{ID: "evt_1", Type: EventProductUpdated, EntityID: "prod_1", Version: 2, Data: &ProductEvent{ProductID: "prod_1", Action: "updated", Product: &Product{ID: "prod_1"}}},
+		{ID: "evt_2", Type: EventCategoryCreated, EntityID: "cat_1", Data: &CategoryEvent{CategoryID: "cat_1", Action: "created"}},
+		{ID: "evt_3", Type: EventProductDeleted, EntityID: "prod_1"},
+	} {
+		encoded, err := json.Marshal(event)
+		assert.NoError(t, err)
+		decoded, err := DecodeEvent(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, event, decoded)
+	}
+
+	_, err := DecodeEvent([]byte(`{"id": "evt_1", "type": "product.updated", "data": []}`))
+	assert.Error(t, err)
+	_, err = DecodeEvent([]byte("{"))
+	assert.Error(t, err)
+}
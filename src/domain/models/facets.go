@@ -0,0 +1,228 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// MaxPriceBuckets is the largest number of price buckets a facet request can ask for
+const MaxPriceBuckets = 20
+
+// autoPriceBuckets is about how many buckets are made when no boundaries are given
+const autoPriceBuckets = 5
+
+// FacetCount is the number of products with a value
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// PriceBucket is the number of products priced from Min up to, but not
+// including, Max. The last bucket of explicit boundaries has no Max.
+type PriceBucket struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int      `json:"count"`
+}
+
+// Facets are the counts a storefront renders its filters from. Each count
+// is of products, so a product with two blue variants counts once for blue.
+type Facets struct {
+	Total      int                     `json:"total"`
+	Markets    []FacetCount            `json:"markets"`
+	Currencies []FacetCount            `json:"currencies"`
+	Attributes map[string][]FacetCount `json:"attributes"` // Variant attribute values by attribute, e.g. "size"
+	// PriceBuckets count the product prices in Currency, when the
+	// request has a currency
+	Currency     string        `json:"currency,omitempty"`
+	PriceBuckets []PriceBucket `json:"price_buckets,omitempty"`
+}
+
+// FacetRequest selects how prices are bucketed
+type FacetRequest struct {
+	// Currency the price buckets are counted in; without one there are no buckets
+	Currency string
+	// PriceBoundaries are the lower bounds of the buckets in ascending order,
+	// e.g. 0, 100, 500. Without them about five buckets of a round width
+	// cover the prices.
+	PriceBoundaries []float64
+}
+
+// Validate normalizes the request and checks the boundaries
+func (r *FacetRequest) Validate() error {
+	r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+	if r.Currency != "" && !IsCurrencyCode(r.Currency) {
+		return fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidRequest)
+	}
+	if len(r.PriceBoundaries) > MaxPriceBuckets {
+		return fmt.Errorf("%w: at most %d price buckets", ErrInvalidRequest, MaxPriceBuckets)
+	}
+	for i, boundary := range r.PriceBoundaries {
+		if boundary < 0 || (i > 0 && boundary <= r.PriceBoundaries[i-1]) {
+			return fmt.Errorf("%w: price buckets must be ascending amounts of at least 0", ErrInvalidRequest)
+		}
+	}
+	return nil
+}
+
+// FacetCounter counts the facets of products added one at a time
+type FacetCounter struct {
+	request    FacetRequest
+	total      int
+	markets    map[string]int
+	currencies map[string]int
+	attributes map[string]map[string]int
+	prices     []float64
+}
+
+// NewFacetCounter creates a counter for a validated request
+func NewFacetCounter(request FacetRequest) *FacetCounter {
+	return &FacetCounter{
+		request:    request,
+		markets:    make(map[string]int),
+		currencies: make(map[string]int),
+		attributes: make(map[string]map[string]int),
+	}
+}
+
+// Add counts a product
+func (c *FacetCounter) Add(product *Product) {
+	c.total++
+	countOnce(c.markets, func(add func(string)) {
+		for _, metadata := range product.Metadata {
+			add(NormalizeMarket(metadata.Market))
+		}
+	})
+	countOnce(c.currencies, func(add func(string)) {
+		for _, price := range product.Prices {
+			add(strings.ToUpper(price.Currency))
+		}
+	})
+
+	values := make(map[string]map[string]bool)
+	for _, variant := range product.Variants {
+		for name, value := range variant.Attributes {
+			if values[name] == nil {
+				values[name] = make(map[string]bool)
+			}
+			values[name][value] = true
+		}
+	}
+	for name, seen := range values {
+		if c.attributes[name] == nil {
+			c.attributes[name] = make(map[string]int)
+		}
+		for value := range seen {
+			c.attributes[name][value]++
+		}
+	}
+
+	if c.request.Currency == "" {
+		return
+	}
+	for _, price := range product.Prices {
+		if strings.EqualFold(price.Currency, c.request.Currency) {
+			c.prices = append(c.prices, price.Amount)
+			break
+		}
+	}
+}
+
+// Facets returns the counts of the products added so far
+func (c *FacetCounter) Facets() *Facets {
+	facets := &Facets{
+		Total:      c.total,
+		Markets:    sortedCounts(c.markets),
+		Currencies: sortedCounts(c.currencies),
+		Attributes: make(map[string][]FacetCount, len(c.attributes)),
+		Currency:   c.request.Currency,
+	}
+	for name, counts := range c.attributes {
+		facets.Attributes[name] = sortedCounts(counts)
+	}
+	if c.request.Currency != "" {
+		facets.PriceBuckets = c.priceBuckets()
+	}
+	return facets
+}
+
+// priceBuckets counts the prices into the requested or automatic buckets
+func (c *FacetCounter) priceBuckets() []PriceBucket {
+	var buckets []PriceBucket
+	if len(c.request.PriceBoundaries) > 0 {
+		for i, boundary := range c.request.PriceBoundaries {
+			bucket := PriceBucket{Min: boundary}
+			if i+1 < len(c.request.PriceBoundaries) {
+				upper := c.request.PriceBoundaries[i+1]
+				bucket.Max = &upper
+			}
+			buckets = append(buckets, bucket)
+		}
+	} else {
+		if len(c.prices) == 0 {
+			return []PriceBucket{}
+		}
+		low, high := c.prices[0], c.prices[0]
+		for _, price := range c.prices {
+			low, high = math.Min(low, price), math.Max(high, price)
+		}
+		step := roundStep((high - low) / autoPriceBuckets)
+		base := math.Floor(low/step) * step
+		for i := 0; base+float64(i)*step <= high; i++ {
+			upper := base + float64(i+1)*step
+			buckets = append(buckets, PriceBucket{Min: base + float64(i)*step, Max: &upper})
+		}
+	}
+
+	for _, price := range c.prices {
+		for i := range buckets {
+			if price >= buckets[i].Min && (buckets[i].Max == nil || price < *buckets[i].Max) {
+				buckets[i].Count++
+				break
+			}
+		}
+	}
+	return buckets
+}
+
+// roundStep rounds a bucket width up to 1, 2 or 5 times a power of ten
+func roundStep(width float64) float64 {
+	if width <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(width)))
+	for _, factor := range []float64{1, 2, 5} {
+		if width <= factor*magnitude {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// countOnce adds one to each distinct value the function adds
+func countOnce(counts map[string]int, values func(add func(string))) {
+	seen := make(map[string]bool)
+	values(func(value string) {
+		if !seen[value] {
+			seen[value] = true
+			counts[value]++
+		}
+	})
+}
+
+// sortedCounts orders counts by count, most first, then by value
+func sortedCounts(counts map[string]int) []FacetCount {
+	sorted := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		sorted = append(sorted, FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Value < sorted[j].Value
+	})
+	return sorted
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func facetProducts() []*Product {
+	return []*Product{
+		{
+			Prices:   []Price{{Currency: "SEK", Amount: 120}, {Currency: "EUR", Amount: 11}},
+			Metadata: []MarketMetadata{{Market: "SE"}, {Market: "fi"}},
+			Variants: []Variant{
+				{Attributes: map[string]string{"size": "M", "color": "blue"}},
+				{Attributes: map[string]string{"size": "L", "color": "blue"}},
+			},
+		},
+		{
+			Prices:   []Price{{Currency: "SEK", Amount: 480}},
+			Metadata: []MarketMetadata{{Market: "SE"}},
+			Variants: []Variant{{Attributes: map[string]string{"size": "M", "color": "red"}}},
+		},
+		{
+			Prices:   []Price{{Currency: "sek", Amount: 999}},
+			Metadata: []MarketMetadata{{Market: "SE"}, {Market: "SE"}},
+		},
+	}
+}
+
+func TestFacetCounter(t *testing.T) {
+	counter := NewFacetCounter(FacetRequest{Currency: "SEK", PriceBoundaries: []float64{0, 100, 500}})
+	for _, product := range facetProducts() {
+		counter.Add(product)
+	}
+	facets := counter.Facets()
+
+	assert.Equal(t, 3, facets.Total)
+	assert.Equal(t, []FacetCount{{"SE", 3}, {"FI", 1}}, facets.Markets)
+	assert.Equal(t, []FacetCount{{"SEK", 3}, {"EUR", 1}}, facets.Currencies)
+	// A product counts once per value, however many variants have it
+	assert.Equal(t, []FacetCount{{"blue", 1}, {"red", 1}}, facets.Attributes["color"])
+	assert.Equal(t, []FacetCount{{"M", 2}, {"L", 1}}, facets.Attributes["size"])
+
+	hundred, fiveHundred := 100.0, 500.0
+	assert.Equal(t, []PriceBucket{
+		{Min: 0, Max: &hundred, Count: 0},
+		{Min: 100, Max: &fiveHundred, Count: 2},
+		{Min: 500, Count: 1},
+	}, facets.PriceBuckets)
+}
+
+func TestFacetCounterAutomaticBuckets(t *testing.T) {
+	counter := NewFacetCounter(FacetRequest{Currency: "SEK"})
+	for _, product := range facetProducts() {
+		counter.Add(product)
+	}
+	buckets := counter.Facets().PriceBuckets
+
+	// 120 to 999 in five buckets rounds up to a width of 200
+	assert.Len(t, buckets, 5)
+	assert.Equal(t, 0.0, buckets[0].Min)
+	assert.Equal(t, 200.0, *buckets[0].Max)
+	assert.Equal(t, 1, buckets[0].Count)
+	assert.Equal(t, 1, buckets[2].Count)
+	assert.Equal(t, 800.0, buckets[4].Min)
+	assert.Equal(t, 1, buckets[4].Count)
+
+	// Without a currency there are no buckets
+	counter = NewFacetCounter(FacetRequest{})
+	counter.Add(facetProducts()[0])
+	assert.Nil(t, counter.Facets().PriceBuckets)
+	assert.Empty(t, NewFacetCounter(FacetRequest{Currency: "SEK"}).Facets().PriceBuckets)
+}
+
+func TestRoundStep(t *testing.T) {
+	assert.Equal(t, 200.0, roundStep(175.8))
+	assert.Equal(t, 5.0, roundStep(3))
+	assert.Equal(t, 10.0, roundStep(6))
+	assert.Equal(t, 0.5, roundStep(0.42))
+	assert.Equal(t, 1.0, roundStep(0))
+}
+
+func TestFacetRequestValidate(t *testing.T) {
+	request := &FacetRequest{Currency: " sek ", PriceBoundaries: []float64{0, 100}}
+	assert.NoError(t, request.Validate())
+	assert.Equal(t, "SEK", request.Currency)
+
+	for name, request := range map[string]FacetRequest{
+		"currency":   {Currency: "KRONOR"},
+		"descending": {PriceBoundaries: []float64{100, 50}},
+		"repeated":   {PriceBoundaries: []float64{100, 100}},
+		"negative":   {PriceBoundaries: []float64{-1, 100}},
+		"too many":   {PriceBoundaries: make([]float64, MaxPriceBuckets+1)},
+	} {
+		assert.ErrorIs(t, request.Validate(), ErrInvalidRequest, name)
+	}
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// LedgerEntry is one stock movement of a variant reported by an external system,
+// e.g. a sale (negative delta) or a delivery from a supplier (positive delta)
+type LedgerEntry struct {
+	VariantID  string    `json:"variant_id"`
+	LocationID string    `json:"location_id,omitempty"`
+	Delta      int       `json:"delta"`
+	Reason     string    `json:"reason,omitempty"` // e.g. sale, return, restock
+	At         time.Time `json:"at"`
+}
+
+// SalesVelocity is the sales rate of a variant as computed by an external system
+type SalesVelocity struct {
+	VariantID   string    `json:"variant_id"`
+	UnitsPerDay float64   `json:"units_per_day"`
+	AsOf        time.Time `json:"as_of"`
+}
+
+// ForecastInput is everything a forecaster gets for one variant
+type ForecastInput struct {
+	ProductID string
+	VariantID string
+	SKU       string
+	OnHand    int            // Current stock over all locations
+	Ledger    []LedgerEntry  // Movements within the forecast window, oldest first
+	Velocity  *SalesVelocity // Latest pushed velocity, if any
+	Now       time.Time
+}
+
+// StockoutForecast is the projected stockout of a variant. A variant that is
+// not selling has no stockout date.
+type StockoutForecast struct {
+	ProductID   string     `json:"product_id"`
+	VariantID   string     `json:"variant_id"`
+	SKU         string     `json:"sku"`
+	OnHand      int        `json:"on_hand"`
+	UnitsPerDay float64    `json:"units_per_day"`
+	DaysOfCover *float64   `json:"days_of_cover,omitempty"`
+	StockoutAt  *time.Time `json:"stockout_at,omitempty"`
+	Forecaster  string     `json:"forecaster"`
+}
+
+// Validate checks that a ledger entry names a variant and a time
+func (e *LedgerEntry) Validate() error {
+	if e.VariantID == "" {
+		return fmt.Errorf("%w: ledger entries need a variant_id", ErrInvalidRequest)
+	}
+	if e.At.IsZero() {
+		return fmt.Errorf("%w: ledger entry for %s needs a time", ErrInvalidRequest, e.VariantID)
+	}
+	return nil
+}
+
+// Validate checks that a velocity names a variant and is not negative
+func (v *SalesVelocity) Validate() error {
+	if v.VariantID == "" {
+		return fmt.Errorf("%w: velocities need a variant_id", ErrInvalidRequest)
+	}
+	if v.UnitsPerDay < 0 {
+		return fmt.Errorf("%w: velocity for %s is negative", ErrInvalidRequest, v.VariantID)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerEntryValidate(t *testing.T) {
+	assert.NoError(t, (&LedgerEntry{VariantID: "v1", Delta: -2, At: time.Now()}).Validate())
+	assert.True(t, errors.Is((&LedgerEntry{Delta: -2, At: time.Now()}).Validate(), ErrInvalidRequest))
+	assert.True(t, errors.Is((&LedgerEntry{VariantID: "v1", Delta: -2}).Validate(), ErrInvalidRequest))
+}
+
+func TestSalesVelocityValidate(t *testing.T) {
+	assert.NoError(t, (&SalesVelocity{VariantID: "v1", UnitsPerDay: 0}).Validate())
+	assert.True(t, errors.Is((&SalesVelocity{UnitsPerDay: 3}).Validate(), ErrInvalidRequest))
+	assert.True(t, errors.Is((&SalesVelocity{VariantID: "v1", UnitsPerDay: -1}).Validate(), ErrInvalidRequest))
+}
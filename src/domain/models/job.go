@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// JobType defines the kind of work a job performs
+type JobType string
+
+const (
+	JobBatchCreate        JobType = "batch.create"
+	JobBatchUpdate        JobType = "batch.update"
+	JobBatchDelete        JobType = "batch.delete"
+	JobRollback           JobType = "job.rollback"
+	JobImport             JobType = "import"
+	JobAttributeMigration JobType = "attribute.migration"
+	JobReprocess          JobType = "reprocess"
+	JobCatalogImport      JobType = "catalog.import"
+	JobTextReplace        JobType = "text.replace"
+	JobMetadataCopy       JobType = "metadata.copy"
+)
+
+// maxJobErrors caps the number of error messages kept on a job
+const maxJobErrors = 100
+
+// JobStatus defines the lifecycle state of a job
+type JobStatus string
+
+const (
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// Job tracks the progress and outcome of a batch operation
+type Job struct {
+	ID          string     `json:"id"`
+	Type        JobType    `json:"type"`
+	Status      JobStatus  `json:"status"`
+	Total       int        `json:"total"`
+	Succeeded   int        `json:"succeeded"`
+	Failed      int        `json:"failed"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	RollbackOf  string     `json:"rollback_of,omitempty"` // Job reverted by a rollback job
+	Source      string     `json:"source,omitempty"`      // Where an import came from, e.g. a file name
+	Errors      []string   `json:"errors,omitempty"`
+}
+
+// AddError records an error message, keeping only the first maxJobErrors
+func (j *Job) AddError(message string) {
+	if len(j.Errors) < maxJobErrors {
+		j.Errors = append(j.Errors, message)
+	}
+}
+
+// Complete marks the job as finished and derives its final status from the counts
+func (j *Job) Complete(succeeded, failed int) {
+	now := time.Now()
+	j.Succeeded = succeeded
+	j.Failed = failed
+	j.CompletedAt = &now
+
+	if failed > 0 && succeeded == 0 {
+		j.Status = JobStatusFailed
+	} else {
+		j.Status = JobStatusCompleted
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+)
+
+func TestJob_Complete(t *testing.T) {
+	tests := []struct {
+		name      string
+		succeeded int
+		failed    int
+		want      JobStatus
+	}{
+		{"all succeeded", 3, 0, JobStatusCompleted},
+		{"partially failed", 2, 1, JobStatusCompleted},
+		{"all failed", 0, 3, JobStatusFailed},
+		{"empty batch", 0, 0, JobStatusCompleted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &Job{ID: "job_1", Status: JobStatusRunning}
+			job.Complete(tt.succeeded, tt.failed)
+
+			if job.Status != tt.want {
+				t.Errorf("Complete() status = %v, want %v", job.Status, tt.want)
+			}
+			if job.CompletedAt == nil {
+				t.Error("Complete() did not set CompletedAt")
+			}
+		})
+	}
+}
+
+func TestJob_AddErrorIsCapped(t *testing.T) {
+	job := &Job{ID: "job_1"}
+	for i := 0; i < maxJobErrors+10; i++ {
+		job.AddError("row failed")
+	}
+
+	if len(job.Errors) != maxJobErrors {
+		t.Errorf("AddError() kept %d errors, want %d", len(job.Errors), maxJobErrors)
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import "strings"
+
+// marketCurrencies maps market codes to the currency products are sold in
+var marketCurrencies = map[string]string{
+	"SE": "SEK",
+	"NO": "NOK",
+	"DK": "DKK",
+	"FI": "EUR",
+	"DE": "EUR",
+	"NL": "EUR",
+	"FR": "EUR",
+	"GB": "GBP",
+	"US": "USD",
+}
+
+// CurrencyForMarket returns the default currency for a market
+func CurrencyForMarket(market string) (string, bool) {
+	currency, ok := marketCurrencies[strings.ToUpper(market)]
+	return currency, ok
+}
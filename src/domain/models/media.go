@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MaxProductImages is the largest number of images a product can have
+const MaxProductImages = 100
+
+// AltTextFor returns the alt text of the image in a market, falling back to
+// the default alt text
+func (i Image) AltTextFor(market string) string {
+	if text, ok := i.AltTexts[NormalizeMarket(market)]; ok {
+		return text
+	}
+	return i.AltText
+}
+
+// Validate normalizes an image being attached and checks its fields
+func (i *Image) Validate() error {
+	i.URL = strings.TrimSpace(i.URL)
+	parsed, err := url.Parse(i.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidRequest)
+	}
+	i.AltText = strings.TrimSpace(i.AltText)
+	if len(i.AltTexts) == 0 {
+		i.AltTexts = nil
+		return nil
+	}
+	texts := make(map[string]string, len(i.AltTexts))
+	for market, text := range i.AltTexts {
+		market = NormalizeMarket(market)
+		if market == "" {
+			return fmt.Errorf("%w: alt_texts need a market", ErrInvalidRequest)
+		}
+		if text = strings.TrimSpace(text); text != "" {
+			texts[market] = text
+		}
+	}
+	i.AltTexts = texts
+	return nil
+}
+
+// ImageIndex returns the position of an image among the product's images, or -1
+func (p *Product) ImageIndex(id string) int {
+	for i, image := range p.Images {
+		if image.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// ReorderImages returns the product's images in the order of the IDs,
+// which must list every image once
+func (p *Product) ReorderImages(ids []string) ([]Image, error) {
+	if len(ids) != len(p.Images) {
+		return nil, fmt.Errorf("%w: list all %d image IDs", ErrInvalidRequest, len(p.Images))
+	}
+	ordered := make([]Image, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		index := p.ImageIndex(id)
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %s", ErrImageNotFound, id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("%w: image %s is listed more than once", ErrInvalidRequest, id)
+		}
+		seen[id] = true
+		ordered = append(ordered, p.Images[index])
+	}
+	return ordered, nil
+}
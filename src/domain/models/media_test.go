@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageValidate(t *testing.T) {
+	image := &Image{URL: " https://cdn.example.com/a.jpg ", AltText: " Front ", AltTexts: map[string]string{"se ": " Framsida ", "DE": " "}}
+	assert.NoError(t, image.Validate())
+	assert.Equal(t, "https://cdn.example.com/a.jpg", image.URL)
+	assert.Equal(t, map[string]string{"SE": "Framsida"}, image.AltTexts)
+
+	assert.Equal(t, "Framsida", image.AltTextFor("se"))
+	assert.Equal(t, "Front", image.AltTextFor("DE"))
+
+	tests := map[string]Image{
+		"relative":     {URL: "/a.jpg"},
+		"scheme":       {URL: "ftp://cdn.example.com/a.jpg"},
+		"empty":        {},
+		"empty market": {URL: "https://cdn.example.com/a.jpg", AltTexts: map[string]string{"": "Front"}},
+	}
+	for name, image := range tests {
+		assert.ErrorIs(t, image.Validate(), ErrInvalidRequest, name)
+	}
+}
+
+func TestProductReorderImages(t *testing.T) {
+	product := &Product{Images: []Image{{ID: "a"}, {ID: "b"}, {ID: "c"}}}
+	assert.Equal(t, 1, product.ImageIndex("b"))
+	assert.Equal(t, -1, product.ImageIndex("d"))
+
+	images, err := product.ReorderImages([]string{"c", "a", "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Image{{ID: "c"}, {ID: "a"}, {ID: "b"}}, images)
+
+	_, err = product.ReorderImages([]string{"c", "a"})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = product.ReorderImages([]string{"c", "a", "a"})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = product.ReorderImages([]string{"c", "a", "d"})
+	assert.ErrorIs(t, err, ErrImageNotFound)
+}
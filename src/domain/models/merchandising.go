@@ -0,0 +1,158 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxPins caps the number of pinned products per listing
+const MaxPins = 200
+
+// Pin fixes a product to a slot in a market listing
+type Pin struct {
+	ProductID string `json:"product_id"`
+	Position  int    `json:"position"` // 1-based slot in the listing
+}
+
+// Merchandising holds the manual positions of a market listing. Category
+// scopes the pins to one curated page; the empty category is the market's
+// full listing.
+type Merchandising struct {
+	Market    string    `json:"market"`
+	Category  string    `json:"category,omitempty"`
+	Pins      []Pin     `json:"pins"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewMerchandising creates a listing without pins
+func NewMerchandising(market, category string) *Merchandising {
+	return &Merchandising{Market: market, Category: category, Pins: make([]Pin, 0)}
+}
+
+// NormalizeCategory returns the canonical form of a category slug
+func NormalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// Validate checks that every pin has its own product and slot
+func (m *Merchandising) Validate() error {
+	if len(m.Pins) > MaxPins {
+		return fmt.Errorf("%w: at most %d products can be pinned", ErrInvalidRequest, MaxPins)
+	}
+	products := make(map[string]bool, len(m.Pins))
+	positions := make(map[int]string, len(m.Pins))
+	for _, pin := range m.Pins {
+		if pin.ProductID == "" {
+			return fmt.Errorf("%w: product_id is required", ErrInvalidRequest)
+		}
+		if pin.Position < 1 {
+			return fmt.Errorf("%w: position of %s must be 1 or more", ErrInvalidRequest, pin.ProductID)
+		}
+		if products[pin.ProductID] {
+			return fmt.Errorf("%w: %s is pinned more than once", ErrInvalidRequest, pin.ProductID)
+		}
+		if holder, taken := positions[pin.Position]; taken {
+			return fmt.Errorf("%w: position %d is taken by %s", ErrInvalidRequest, pin.Position, holder)
+		}
+		products[pin.ProductID] = true
+		positions[pin.Position] = pin.ProductID
+	}
+	return nil
+}
+
+// Pin places a product at a position, moving it if it was already pinned.
+// A position held by another product is a conflict.
+func (m *Merchandising) Pin(productID string, position int) error {
+	pins := make([]Pin, 0, len(m.Pins)+1)
+	for _, pin := range m.Pins {
+		if pin.ProductID != productID {
+			pins = append(pins, pin)
+		}
+	}
+	pins = append(pins, Pin{ProductID: productID, Position: position})
+
+	pinned := &Merchandising{Pins: pins}
+	if err := pinned.Validate(); err != nil {
+		return err
+	}
+	m.Pins = pinned.sortedPins()
+	return nil
+}
+
+// Unpin removes a product's pin and reports whether it was pinned
+func (m *Merchandising) Unpin(productID string) bool {
+	for i, pin := range m.Pins {
+		if pin.ProductID == productID {
+			m.Pins = append(m.Pins[:i:i], m.Pins[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Arrange orders the products of a listing: pinned products take their slots
+// and the others fill the remaining slots oldest first, with the ID breaking
+// ties so pages stay stable. Pins beyond the end of the listing follow the
+// other products in position order; pins for products that are not listed
+// are ignored.
+func (m *Merchandising) Arrange(products []*Product) []*Product {
+	listed := make(map[string]*Product, len(products))
+	for _, product := range products {
+		listed[product.ID] = product
+	}
+
+	slots := make(map[int]*Product)
+	pinned := make(map[string]bool)
+	for _, pin := range m.sortedPins() {
+		if product, exists := listed[pin.ProductID]; exists {
+			slots[pin.Position] = product
+			pinned[pin.ProductID] = true
+		}
+	}
+
+	rest := make([]*Product, 0, len(products))
+	for _, product := range products {
+		if !pinned[product.ID] {
+			rest = append(rest, product)
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		if !rest[i].CreatedAt.Equal(rest[j].CreatedAt) {
+			return rest[i].CreatedAt.Before(rest[j].CreatedAt)
+		}
+		return rest[i].ID < rest[j].ID
+	})
+
+	arranged := make([]*Product, 0, len(products))
+	for slot := 1; len(arranged) < len(products); slot++ {
+		if product, exists := slots[slot]; exists {
+			arranged = append(arranged, product)
+			delete(slots, slot)
+			continue
+		}
+		if len(rest) > 0 {
+			arranged = append(arranged, rest[0])
+			rest = rest[1:]
+			continue
+		}
+		// Only pins past the end remain; close the gaps
+		for _, pin := range m.sortedPins() {
+			if product, exists := slots[pin.Position]; exists {
+				arranged = append(arranged, product)
+				delete(slots, pin.Position)
+			}
+		}
+	}
+	return arranged
+}
+
+// sortedPins returns the pins in position order
+func (m *Merchandising) sortedPins() []Pin {
+	pins := append([]Pin(nil), m.Pins...)
+	sort.Slice(pins, func(i, j int) bool {
+		return pins[i].Position < pins[j].Position
+	})
+	return pins
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func listedProducts(ids ...string) []*Product {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	products := make([]*Product, len(ids))
+	for i, id := range ids {
+		products[i] = &Product{ID: id, CreatedAt: created.Add(time.Duration(i) * time.Hour)}
+	}
+	return products
+}
+
+func productIDs(products []*Product) []string {
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	return ids
+}
+
+func TestMerchandisingArrange(t *testing.T) {
+	products := listedProducts("a", "b", "c", "d", "e")
+
+	m := NewMerchandising("SE", "shirts")
+	m.Pins = []Pin{{ProductID: "e", Position: 1}, {ProductID: "c", Position: 3}}
+	assert.Equal(t, []string{"e", "a", "c", "b", "d"}, productIDs(m.Arrange(products)))
+
+	// Pins past the end follow the other products, and unlisted products are ignored
+	m.Pins = []Pin{{ProductID: "a", Position: 9}, {ProductID: "b", Position: 7}, {ProductID: "gone", Position: 2}}
+	assert.Equal(t, []string{"c", "d", "e", "b", "a"}, productIDs(m.Arrange(products)))
+}
+
+func TestMerchandisingArrangeIsStable(t *testing.T) {
+	products := listedProducts("b", "a", "c")
+	for _, product := range products {
+		product.CreatedAt = products[0].CreatedAt
+	}
+
+	m := NewMerchandising("SE", "")
+	assert.Equal(t, []string{"a", "b", "c"}, productIDs(m.Arrange(products)))
+	assert.Equal(t, []string{"a", "b", "c"}, productIDs(m.Arrange([]*Product{products[2], products[0], products[1]})))
+}
+
+func TestMerchandisingValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		pins []Pin
+	}{
+		{"missing product", []Pin{{Position: 1}}},
+		{"position below one", []Pin{{ProductID: "a", Position: 0}}},
+		{"duplicate product", []Pin{{ProductID: "a", Position: 1}, {ProductID: "a", Position: 2}}},
+		{"duplicate position", []Pin{{ProductID: "a", Position: 1}, {ProductID: "b", Position: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Merchandising{Pins: tt.pins}
+			assert.True(t, errors.Is(m.Validate(), ErrInvalidRequest))
+		})
+	}
+}
+
+func TestMerchandisingPinAndUnpin(t *testing.T) {
+	m := NewMerchandising("SE", "")
+	assert.NoError(t, m.Pin("a", 3))
+	assert.NoError(t, m.Pin("b", 1))
+	assert.Equal(t, []Pin{{ProductID: "b", Position: 1}, {ProductID: "a", Position: 3}}, m.Pins)
+
+	// Pinning again moves the product; taking another product's slot is a conflict
+	assert.NoError(t, m.Pin("a", 2))
+	assert.Equal(t, []Pin{{ProductID: "b", Position: 1}, {ProductID: "a", Position: 2}}, m.Pins)
+	assert.True(t, errors.Is(m.Pin("c", 1), ErrInvalidRequest))
+
+	assert.True(t, m.Unpin("b"))
+	assert.False(t, m.Unpin("b"))
+	assert.Equal(t, []Pin{{ProductID: "a", Position: 2}}, m.Pins)
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Metadata fields a copy can include
+const (
+	MetadataFieldTitle       = "title"
+	MetadataFieldDescription = "description"
+	MetadataFieldKeywords    = "keywords"
+)
+
+// metadataFields are the fields copied when none are listed
+var metadataFields = []string{MetadataFieldTitle, MetadataFieldDescription, MetadataFieldKeywords}
+
+// MetadataCopy copies the metadata of one market to other markets for the
+// products selected by the filter, e.g. to start a new market from the texts
+// of a market with the same language
+type MetadataCopy struct {
+	Source    string        `json:"source"`               // Market to copy from, e.g. SE
+	Targets   []string      `json:"targets"`              // Markets to copy to, e.g. ["FI"]
+	Fields    []string      `json:"fields,omitempty"`     // Fields to copy; all metadata fields when empty
+	OnlyEmpty bool          `json:"only_empty,omitempty"` // Only fill fields that are empty in the target market
+	Filter    CatalogFilter `json:"filter"`
+}
+
+// Validate checks the copy and normalizes its market codes
+func (c *MetadataCopy) Validate() error {
+	c.Source = strings.ToUpper(strings.TrimSpace(c.Source))
+	if c.Source == "" {
+		return fmt.Errorf("%w: source is required", ErrInvalidRequest)
+	}
+
+	targets := make([]string, 0, len(c.Targets))
+	seen := make(map[string]bool, len(c.Targets))
+	for _, target := range c.Targets {
+		target = strings.ToUpper(strings.TrimSpace(target))
+		if target == "" || seen[target] {
+			continue
+		}
+		if target == c.Source {
+			return fmt.Errorf("%w: target %s is the source market", ErrInvalidRequest, target)
+		}
+		seen[target] = true
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("%w: at least one target is required", ErrInvalidRequest)
+	}
+	c.Targets = targets
+
+	for _, field := range c.Fields {
+		if !isMetadataField(field) {
+			return fmt.Errorf("%w: unknown field %q, expected one of %s", ErrInvalidRequest, field, strings.Join(metadataFields, ", "))
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of the product with the source metadata copied to the
+// targets, and whether anything changed. Products without source metadata are
+// left alone. A target market the product lacks is only added when the title
+// is copied, since metadata needs a title. Validate must have been called first.
+func (c *MetadataCopy) Apply(p *Product) (*Product, bool) {
+	source := p.MetadataForMarket(c.Source)
+	if source == nil {
+		return p, false
+	}
+
+	copied := p.Clone()
+	changed := false
+	for _, target := range c.Targets {
+		metadata := copied.MetadataForMarket(target)
+		if metadata == nil {
+			if !c.copies(MetadataFieldTitle) {
+				continue
+			}
+			copied.Metadata = append(copied.Metadata, MarketMetadata{Market: target})
+			metadata = &copied.Metadata[len(copied.Metadata)-1]
+		}
+
+		for _, field := range c.fields() {
+			var from, to *string
+			switch field {
+			case MetadataFieldTitle:
+				from, to = &source.Title, &metadata.Title
+			case MetadataFieldDescription:
+				from, to = &source.Description, &metadata.Description
+			case MetadataFieldKeywords:
+				from, to = &source.Keywords, &metadata.Keywords
+			}
+			if *to == *from || (c.OnlyEmpty && *to != "") {
+				continue
+			}
+			*to = *from
+			changed = true
+		}
+	}
+
+	if !changed {
+		return p, false
+	}
+	return copied, true
+}
+
+// fields returns the fields to copy
+func (c *MetadataCopy) fields() []string {
+	if len(c.Fields) == 0 {
+		return metadataFields
+	}
+	return c.Fields
+}
+
+// copies reports whether a field is copied
+func (c *MetadataCopy) copies(field string) bool {
+	for _, copied := range c.fields() {
+		if copied == field {
+			return true
+		}
+	}
+	return false
+}
+
+// isMetadataField reports whether a field can be copied
+func isMetadataField(field string) bool {
+	for _, known := range metadataFields {
+		if field == known {
+			return true
+		}
+	}
+	return false
+}
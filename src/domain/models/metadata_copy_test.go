@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createMetadataProduct() *Product {
+	return &Product{
+		ID:  "prod_1",
+		SKU: "SHIRT-1",
+		Metadata: []MarketMetadata{
+			{Market: "SE", Title: "Tröja", Description: "En tröja", Keywords: "tröja"},
+			{Market: "FI", Title: "Paita"},
+		},
+	}
+}
+
+func TestMetadataCopyValidate(t *testing.T) {
+	assert.ErrorIs(t, (&MetadataCopy{Targets: []string{"FI"}}).Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, (&MetadataCopy{Source: "SE"}).Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, (&MetadataCopy{Source: "SE", Targets: []string{"se"}}).Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, (&MetadataCopy{Source: "SE", Targets: []string{"FI"}, Fields: []string{"sku"}}).Validate(), ErrInvalidRequest)
+
+	metadataCopy := &MetadataCopy{Source: " se", Targets: []string{"fi", "FI", " ", "ax"}}
+	assert.NoError(t, metadataCopy.Validate())
+	assert.Equal(t, "SE", metadataCopy.Source)
+	assert.Equal(t, []string{"FI", "AX"}, metadataCopy.Targets)
+}
+
+func TestMetadataCopyApply(t *testing.T) {
+	product := createMetadataProduct()
+	metadataCopy := &MetadataCopy{Source: "SE", Targets: []string{"FI", "AX"}}
+	assert.NoError(t, metadataCopy.Validate())
+
+	copied, changed := metadataCopy.Apply(product)
+	assert.True(t, changed)
+	assert.Equal(t, MarketMetadata{Market: "FI", Title: "Tröja", Description: "En tröja", Keywords: "tröja"}, *copied.MetadataForMarket("FI"))
+	assert.Equal(t, MarketMetadata{Market: "AX", Title: "Tröja", Description: "En tröja", Keywords: "tröja"}, *copied.MetadataForMarket("AX"))
+	assert.Equal(t, "Paita", product.MetadataForMarket("FI").Title, "the original product must not be changed")
+	assert.Nil(t, product.MetadataForMarket("AX"))
+}
+
+func TestMetadataCopyOnlyEmpty(t *testing.T) {
+	metadataCopy := &MetadataCopy{Source: "SE", Targets: []string{"FI"}, OnlyEmpty: true}
+	assert.NoError(t, metadataCopy.Validate())
+
+	copied, changed := metadataCopy.Apply(createMetadataProduct())
+	assert.True(t, changed)
+	assert.Equal(t, MarketMetadata{Market: "FI", Title: "Paita", Description: "En tröja", Keywords: "tröja"}, *copied.MetadataForMarket("FI"))
+
+	// Nothing is left to fill the second time
+	_, changed = metadataCopy.Apply(copied)
+	assert.False(t, changed)
+}
+
+func TestMetadataCopySelectedFields(t *testing.T) {
+	metadataCopy := &MetadataCopy{Source: "SE", Targets: []string{"FI", "AX"}, Fields: []string{MetadataFieldKeywords}}
+	assert.NoError(t, metadataCopy.Validate())
+
+	copied, changed := metadataCopy.Apply(createMetadataProduct())
+	assert.True(t, changed)
+	assert.Equal(t, MarketMetadata{Market: "FI", Title: "Paita", Keywords: "tröja"}, *copied.MetadataForMarket("FI"))
+	assert.Nil(t, copied.MetadataForMarket("AX"), "a market without a title is not added")
+}
+
+func TestMetadataCopyWithoutSource(t *testing.T) {
+	metadataCopy := &MetadataCopy{Source: "DK", Targets: []string{"FI"}}
+	assert.NoError(t, metadataCopy.Validate())
+
+	product := createMetadataProduct()
+	copied, changed := metadataCopy.Apply(product)
+	assert.False(t, changed)
+	assert.Same(t, product, copied)
+}
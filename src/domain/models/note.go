@@ -0,0 +1,42 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxNoteLength caps the length of a note in characters
+const MaxNoteLength = 4000
+
+// ProductNote is an internal comment on a product, for merchandisers to
+// coordinate in the catalog tool. Notes are kept apart from the product, so
+// they never appear in product responses, events or exports.
+type ProductNote struct {
+	ID        string    `json:"id"`
+	ProductID string    `json:"product_id"`
+	ThreadID  string    `json:"thread_id,omitempty"` // The note this one replies to; empty for the note starting a thread
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Resolved  bool      `json:"resolved,omitempty"` // Set on a thread once its question is settled
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NoteThread is a note and its replies, oldest first
+type NoteThread struct {
+	*ProductNote
+	Replies []*ProductNote `json:"replies"`
+}
+
+// ValidateNoteBody trims a note and checks its length
+func ValidateNoteBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: body is required", ErrInvalidRequest)
+	}
+	if length := len([]rune(body)); length > MaxNoteLength {
+		return "", fmt.Errorf("%w: body is %d characters, at most %d are allowed", ErrInvalidRequest, length, MaxNoteLength)
+	}
+	return body, nil
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// OutboxState describes where an event is in the outbox
+type OutboxState string
+
+const (
+	OutboxPending   OutboxState = "pending"   // Waiting to be published, possibly after failed attempts
+	OutboxDelivered OutboxState = "delivered" // Published to every subscriber
+	OutboxFailed    OutboxState = "failed"    // Gave up after the maximum number of attempts
+)
+
+// OutboxEntry is an event that was committed and is published by the outbox relay
+type OutboxEntry struct {
+	Event       *Event      `json:"event"`
+	State       OutboxState `json:"state"`
+	Attempts    int         `json:"attempts"`
+	LastError   string      `json:"last_error,omitempty"`
+	NextAttempt time.Time   `json:"next_attempt"`
+	CreatedAt   time.Time   `json:"created_at"`
+	DeliveredAt *time.Time  `json:"delivered_at,omitempty"`
+}
+
+// OutboxStats counts the entries of an outbox by state
+type OutboxStats struct {
+	Pending   int `json:"pending"`
+	Delivered int `json:"delivered"`
+	Failed    int `json:"failed"`
+}
@@ -0,0 +1,244 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxPriceListPrices is the largest number of prices in one price list
+const MaxPriceListPrices = 10000
+
+// Price sources of a resolved price
+const (
+	PriceSourcePriceList = "price_list"
+	PriceSourceVariant   = "variant"
+	PriceSourceProduct   = "product"
+)
+
+// PriceList overrides product prices in one currency, for a market, a
+// customer group or both. A list without a market applies in every market,
+// one without a customer group to every customer.
+type PriceList struct {
+	ID            string           `json:"id"`
+	Name          string           `json:"name"`
+	Currency      string           `json:"currency" example:"SEK"`
+	Market        string           `json:"market,omitempty" example:"SE"`
+	CustomerGroup string           `json:"customer_group,omitempty" example:"b2b"`
+	Priority      int              `json:"priority"` // Higher wins between lists that are equally specific
+	Prices        []ScheduledPrice `json:"prices"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+}
+
+// ScheduledPrice is the price of a product, or of one of its variants, while
+// it is valid. ValidFrom is inclusive and ValidTo exclusive; either may be
+// left out for a price that is valid from now or until further notice.
+type ScheduledPrice struct {
+	ProductID string     `json:"product_id"`
+	VariantID string     `json:"variant_id,omitempty"` // Empty for every variant without its own price
+	Amount    float64    `json:"amount"`
+	ValidFrom *time.Time `json:"valid_from,omitempty"`
+	ValidTo   *time.Time `json:"valid_to,omitempty"`
+}
+
+// PriceQuery describes whose price is asked for and when
+type PriceQuery struct {
+	Currency      string
+	Market        string
+	CustomerGroup string
+	VariantID     string
+	At            time.Time
+}
+
+// ResolvedPrice is the price that applies to a query, and where it came from
+type ResolvedPrice struct {
+	ProductID   string     `json:"product_id"`
+	VariantID   string     `json:"variant_id,omitempty"`
+	Currency    string     `json:"currency"`
+	Amount      float64    `json:"amount"`
+	Source      string     `json:"source"` // price_list, variant or product
+	PriceListID string     `json:"price_list_id,omitempty"`
+	ValidFrom   *time.Time `json:"valid_from,omitempty"`
+	ValidTo     *time.Time `json:"valid_to,omitempty"`
+	At          time.Time  `json:"at"`
+}
+
+// NormalizeCustomerGroup returns the canonical form of a customer group
+func NormalizeCustomerGroup(group string) string {
+	return strings.ToLower(strings.TrimSpace(group))
+}
+
+// Validate normalizes the list and checks its prices. A product or variant may
+// have several prices in a list as long as their periods do not overlap.
+func (l *PriceList) Validate() error {
+	l.Currency = strings.ToUpper(strings.TrimSpace(l.Currency))
+	l.Market = NormalizeMarket(l.Market)
+	l.CustomerGroup = NormalizeCustomerGroup(l.CustomerGroup)
+	if !IsCurrencyCode(l.Currency) {
+		return fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidRequest)
+	}
+	if len(l.Prices) > MaxPriceListPrices {
+		return fmt.Errorf("%w: at most %d prices per list", ErrInvalidRequest, MaxPriceListPrices)
+	}
+
+	periods := make(map[string][]ScheduledPrice)
+	for _, price := range l.Prices {
+		if price.ProductID == "" {
+			return fmt.Errorf("%w: product_id is required", ErrInvalidRequest)
+		}
+		if price.Amount < 0 {
+			return fmt.Errorf("%w: product %s has a negative price", ErrInvalidRequest, price.ProductID)
+		}
+		if price.ValidFrom != nil && price.ValidTo != nil && !price.ValidTo.After(*price.ValidFrom) {
+			return fmt.Errorf("%w: valid_to must be after valid_from", ErrInvalidRequest)
+		}
+		key := price.ProductID + "/" + price.VariantID
+		for _, other := range periods[key] {
+			if price.overlaps(other) {
+				return fmt.Errorf("%w: product %s has overlapping prices", ErrInvalidRequest, price.ProductID)
+			}
+		}
+		periods[key] = append(periods[key], price)
+	}
+	return nil
+}
+
+// Applies reports whether the list applies to a market and customer group
+func (l *PriceList) Applies(currency, market, customerGroup string) bool {
+	return strings.EqualFold(l.Currency, currency) &&
+		(l.Market == "" || l.Market == NormalizeMarket(market)) &&
+		(l.CustomerGroup == "" || l.CustomerGroup == NormalizeCustomerGroup(customerGroup))
+}
+
+// PriceAt returns the price of a variant that is valid at a time: the
+// variant's own price if the list has one, otherwise the product's
+func (l *PriceList) PriceAt(productID, variantID string, at time.Time) (ScheduledPrice, bool) {
+	var productPrice *ScheduledPrice
+	for i, price := range l.Prices {
+		if price.ProductID != productID || !price.ValidAt(at) {
+			continue
+		}
+		if variantID != "" && price.VariantID == variantID {
+			return price, true
+		}
+		if price.VariantID == "" {
+			productPrice = &l.Prices[i]
+		}
+	}
+	if productPrice != nil {
+		return *productPrice, true
+	}
+	return ScheduledPrice{}, false
+}
+
+// PricesOf returns the prices the list has for a product
+func (l *PriceList) PricesOf(productID string) []ScheduledPrice {
+	var prices []ScheduledPrice
+	for _, price := range l.Prices {
+		if price.ProductID == productID {
+			prices = append(prices, price)
+		}
+	}
+	return prices
+}
+
+// specificity ranks lists for a customer group above lists for a market,
+// and both above lists for everyone
+func (l *PriceList) specificity() int {
+	rank := 0
+	if l.CustomerGroup != "" {
+		rank += 2
+	}
+	if l.Market != "" {
+		rank++
+	}
+	return rank
+}
+
+// ValidAt reports whether the price is valid at a time
+func (p ScheduledPrice) ValidAt(at time.Time) bool {
+	return (p.ValidFrom == nil || !at.Before(*p.ValidFrom)) && (p.ValidTo == nil || at.Before(*p.ValidTo))
+}
+
+// ChangesBetween reports whether the price starts or ends after since and
+// at or before until
+func (p ScheduledPrice) ChangesBetween(since, until time.Time) bool {
+	within := func(t *time.Time) bool {
+		return t != nil && t.After(since) && !t.After(until)
+	}
+	return within(p.ValidFrom) || within(p.ValidTo)
+}
+
+// overlaps reports whether two prices are valid at the same time
+func (p ScheduledPrice) overlaps(other ScheduledPrice) bool {
+	startsBeforeOtherEnds := p.ValidFrom == nil || other.ValidTo == nil || p.ValidFrom.Before(*other.ValidTo)
+	otherStartsBeforeEnd := other.ValidFrom == nil || p.ValidTo == nil || other.ValidFrom.Before(*p.ValidTo)
+	return startsBeforeOtherEnds && otherStartsBeforeEnd
+}
+
+// SortPriceLists orders lists the way ResolvePrice consults them: most
+// specific first, then by descending priority, then by ID
+func SortPriceLists(lists []*PriceList) {
+	sort.SliceStable(lists, func(i, j int) bool {
+		if a, b := lists[i].specificity(), lists[j].specificity(); a != b {
+			return a > b
+		}
+		if lists[i].Priority != lists[j].Priority {
+			return lists[i].Priority > lists[j].Priority
+		}
+		return lists[i].ID < lists[j].ID
+	})
+}
+
+// ResolvePrice picks the price of a product for a query. The first applicable
+// price list with a price valid at the time wins, in the order SortPriceLists
+// gives; without one the variant's or the product's own price applies.
+func (p *Product) ResolvePrice(lists []*PriceList, query PriceQuery) (*ResolvedPrice, error) {
+	var variant *Variant
+	if query.VariantID != "" {
+		if variant = findVariant(p.Variants, query.VariantID); variant == nil {
+			return nil, fmt.Errorf("%w: %s", ErrVariantNotFound, query.VariantID)
+		}
+	}
+	resolved := &ResolvedPrice{
+		ProductID: p.ID,
+		VariantID: query.VariantID,
+		Currency:  strings.ToUpper(query.Currency),
+		At:        query.At,
+	}
+
+	candidates := make([]*PriceList, 0, len(lists))
+	for _, list := range lists {
+		if list.Applies(query.Currency, query.Market, query.CustomerGroup) {
+			candidates = append(candidates, list)
+		}
+	}
+	SortPriceLists(candidates)
+	for _, list := range candidates {
+		if price, ok := list.PriceAt(p.ID, query.VariantID, query.At); ok {
+			resolved.Amount = price.Amount
+			resolved.Source = PriceSourcePriceList
+			resolved.PriceListID = list.ID
+			resolved.ValidFrom = price.ValidFrom
+			resolved.ValidTo = price.ValidTo
+			return resolved, nil
+		}
+	}
+
+	price, ok := p.PriceFor(variant, query.Currency)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPriceNotFound, resolved.Currency)
+	}
+	resolved.Amount = price.Amount
+	resolved.Source = PriceSourceProduct
+	if variant != nil {
+		for _, own := range variant.Prices {
+			if strings.EqualFold(own.Currency, query.Currency) {
+				resolved.Source = PriceSourceVariant
+			}
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pricedProduct() *Product {
+	return &Product{
+		ID:     "prod_1",
+		Prices: []Price{{Currency: "SEK", Amount: 100}},
+		Variants: []Variant{
+			{ID: "v1", SKU: "SHIRT-S"},
+			{ID: "v2", SKU: "SHIRT-XXL", Prices: []Price{{Currency: "SEK", Amount: 120}}},
+		},
+	}
+}
+
+func at(value string) *time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return &t
+}
+
+func TestPriceListValidate(t *testing.T) {
+	list := &PriceList{Currency: " sek ", Market: "se", CustomerGroup: " B2B ", Prices: []ScheduledPrice{
+		{ProductID: "prod_1", Amount: 90, ValidTo: at("2024-06-01T00:00:00Z")},
+		{ProductID: "prod_1", Amount: 80, ValidFrom: at("2024-06-01T00:00:00Z")},
+		{ProductID: "prod_1", VariantID: "v1", Amount: 70},
+	}}
+	assert.NoError(t, list.Validate())
+	assert.Equal(t, "SEK", list.Currency)
+	assert.Equal(t, "SE", list.Market)
+	assert.Equal(t, "b2b", list.CustomerGroup)
+
+	for name, list := range map[string]*PriceList{
+		"currency":   {Currency: "KR"},
+		"product":    {Currency: "SEK", Prices: []ScheduledPrice{{Amount: 1}}},
+		"negative":   {Currency: "SEK", Prices: []ScheduledPrice{{ProductID: "prod_1", Amount: -1}}},
+		"empty span": {Currency: "SEK", Prices: []ScheduledPrice{{ProductID: "prod_1", ValidFrom: at("2024-06-01T00:00:00Z"), ValidTo: at("2024-06-01T00:00:00Z")}}},
+		"overlap": {Currency: "SEK", Prices: []ScheduledPrice{
+			{ProductID: "prod_1", Amount: 90, ValidTo: at("2024-06-02T00:00:00Z")},
+			{ProductID: "prod_1", Amount: 80, ValidFrom: at("2024-06-01T00:00:00Z")},
+		}},
+	} {
+		assert.True(t, errors.Is(list.Validate(), ErrInvalidRequest), name)
+	}
+}
+
+func TestResolvePriceFallsBackToProductPrices(t *testing.T) {
+	product := pricedProduct()
+	now := time.Now()
+
+	price, err := product.ResolvePrice(nil, PriceQuery{Currency: "sek", VariantID: "v1", At: now})
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, price.Amount)
+	assert.Equal(t, PriceSourceProduct, price.Source)
+
+	price, err = product.ResolvePrice(nil, PriceQuery{Currency: "SEK", VariantID: "v2", At: now})
+	assert.NoError(t, err)
+	assert.Equal(t, 120.0, price.Amount)
+	assert.Equal(t, PriceSourceVariant, price.Source)
+
+	_, err = product.ResolvePrice(nil, PriceQuery{Currency: "EUR", At: now})
+	assert.True(t, errors.Is(err, ErrPriceNotFound))
+	_, err = product.ResolvePrice(nil, PriceQuery{Currency: "SEK", VariantID: "v9", At: now})
+	assert.True(t, errors.Is(err, ErrVariantNotFound))
+}
+
+func TestResolvePricePicksMostSpecificList(t *testing.T) {
+	product := pricedProduct()
+	lists := []*PriceList{
+		{ID: "everyone", Currency: "SEK", Prices: []ScheduledPrice{{ProductID: "prod_1", Amount: 95}}},
+		{ID: "sweden", Currency: "SEK", Market: "SE", Prices: []ScheduledPrice{{ProductID: "prod_1", Amount: 90}}},
+		{ID: "b2b", Currency: "SEK", CustomerGroup: "b2b", Prices: []ScheduledPrice{{ProductID: "prod_1", Amount: 80}}},
+		{ID: "b2b-campaign", Currency: "SEK", CustomerGroup: "b2b", Priority: 10, Prices: []ScheduledPrice{
+			{ProductID: "prod_1", Amount: 60, ValidFrom: at("2024-06-01T00:00:00Z"), ValidTo: at("2024-07-01T00:00:00Z")},
+		}},
+		{ID: "euro", Currency: "EUR", Prices: []ScheduledPrice{{ProductID: "prod_1", Amount: 9}}},
+	}
+
+	cases := []struct {
+		query    PriceQuery
+		list     string
+		expected float64
+	}{
+		{PriceQuery{Currency: "SEK", At: *at("2024-05-01T00:00:00Z")}, "everyone", 95},
+		{PriceQuery{Currency: "SEK", Market: "se", At: *at("2024-05-01T00:00:00Z")}, "sweden", 90},
+		{PriceQuery{Currency: "SEK", Market: "SE", CustomerGroup: "B2B", At: *at("2024-05-01T00:00:00Z")}, "b2b", 80},
+		// The campaign outranks the b2b list while it runs, and ends exclusively
+		{PriceQuery{Currency: "SEK", CustomerGroup: "b2b", At: *at("2024-06-01T00:00:00Z")}, "b2b-campaign", 60},
+		{PriceQuery{Currency: "SEK", CustomerGroup: "b2b", At: *at("2024-07-01T00:00:00Z")}, "b2b", 80},
+	}
+	for _, c := range cases {
+		price, err := product.ResolvePrice(lists, c.query)
+		assert.NoError(t, err)
+		assert.Equal(t, c.list, price.PriceListID, c.query)
+		assert.Equal(t, c.expected, price.Amount, c.query)
+		assert.Equal(t, PriceSourcePriceList, price.Source)
+	}
+}
+
+func TestResolvePricePrefersVariantPriceInList(t *testing.T) {
+	product := pricedProduct()
+	lists := []*PriceList{{ID: "sale", Currency: "SEK", Prices: []ScheduledPrice{
+		{ProductID: "prod_1", Amount: 75},
+		{ProductID: "prod_1", VariantID: "v2", Amount: 85},
+	}}}
+
+	price, err := product.ResolvePrice(lists, PriceQuery{Currency: "SEK", VariantID: "v2", At: time.Now()})
+	assert.NoError(t, err)
+	assert.Equal(t, 85.0, price.Amount)
+
+	price, err = product.ResolvePrice(lists, PriceQuery{Currency: "SEK", VariantID: "v1", At: time.Now()})
+	assert.NoError(t, err)
+	assert.Equal(t, 75.0, price.Amount)
+}
+
+func TestScheduledPriceChangesBetween(t *testing.T) {
+	price := ScheduledPrice{ValidFrom: at("2024-06-01T00:00:00Z"), ValidTo: at("2024-07-01T00:00:00Z")}
+
+	assert.True(t, price.ChangesBetween(*at("2024-05-31T23:59:00Z"), *at("2024-06-01T00:00:00Z")))
+	assert.False(t, price.ChangesBetween(*at("2024-06-01T00:00:00Z"), *at("2024-06-02T00:00:00Z")))
+	assert.True(t, price.ChangesBetween(*at("2024-06-30T00:00:00Z"), *at("2024-07-01T00:00:00Z")))
+	assert.False(t, ScheduledPrice{}.ChangesBetween(time.Time{}, time.Now()))
+}
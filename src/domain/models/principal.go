@@ -0,0 +1,36 @@
+package models
+
+// Authentication methods a principal can be established with
+const (
+	AuthMethodOIDC   = "oidc"
+	AuthMethodAPIKey = "api_key"
+	AuthMethodJWT    = "jwt"
+)
+
+// Roles of product API callers, in increasing order of access. Each role
+// includes the access of the roles before it.
+const (
+	RoleViewer = "viewer"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Subject string   `json:"subject"` // Stable ID from the identity provider
+	Name    string   `json:"name,omitempty"`
+	Email   string   `json:"email,omitempty"`
+	Groups  []string `json:"groups,omitempty"`
+	Roles   []string `json:"roles"`
+	Method  string   `json:"method"`
+}
+
+// HasRole reports whether the principal was granted a role
+func (p *Principal) HasRole(role string) bool {
+	for _, granted := range p.Roles {
+		if granted == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalHasRole(t *testing.T) {
+	principal := &Principal{Subject: "user-1", Roles: []string{"editor", "viewer"}}
+	assert.True(t, principal.HasRole("editor"))
+	assert.False(t, principal.HasRole("admin"))
+	assert.False(t, (&Principal{}).HasRole("viewer"))
+}
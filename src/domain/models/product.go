@@ -11,14 +11,16 @@ import (
 
 // Price represents a price for a specific market
 type Price struct {
-	Currency string  `json:"currency" validate:"required,len=3"`
+	Currency string  `json:"currency" validate:"required,currency"` // ISO 4217 code
 	Amount   float64 `json:"amount" validate:"required,gte=0"`
 }
 
-// Stock represents inventory for a specific location
+// Stock represents inventory for a specific location.
+// Quantity may only go negative when Backorder is enabled.
 type Stock struct {
 	LocationID string `json:"location_id" validate:"required"`
-	Quantity   int    `json:"quantity" validate:"gte=0"`
+	Quantity   int    `json:"quantity"`
+	Backorder  bool   `json:"backorder,omitempty"`
 }
 
 // Variant represents product variants
@@ -26,7 +28,8 @@ type Variant struct {
 	ID         string            `json:"id" validate:"required"`
 	SKU        string            `json:"sku" validate:"required"`
 	Attributes map[string]string `json:"attributes" validate:"required"` // e.g. {"size": "XL", "color": "blue"}
-	Stock      []Stock           `json:"stock"`
+	Stock      []Stock           `json:"stock" validate:"dive"`
+	Prices     []Price           `json:"prices,omitempty" validate:"dive"` // Overrides of the product price per currency
 }
 
 // MarketMetadata contains market-specific information
@@ -37,6 +40,25 @@ type MarketMetadata struct {
 	Keywords    string `json:"keywords"`
 }
 
+// Image references a product image. The order of a product's images is
+// their sort order; the first is the main image.
+type Image struct {
+	ID          string            `json:"id,omitempty"` // Generated when the image is saved
+	URL         string            `json:"url" validate:"required,url"`
+	AltText     string            `json:"alt_text,omitempty"`     // Default alt text
+	AltTexts    map[string]string `json:"alt_texts,omitempty"`    // Per market, e.g. {"SE": "Blå skjorta"}
+	ContentType string            `json:"content_type,omitempty"` // Set for uploaded images
+	StorageKey  string            `json:"storage_key,omitempty"`  // Key of an uploaded image in the asset storage
+}
+
+// Sourcing is the internal purchasing data of a product. It is never shown to
+// public callers.
+type Sourcing struct {
+	Supplier    string  `json:"supplier,omitempty"`
+	SupplierSKU string  `json:"supplier_sku,omitempty"`
+	Costs       []Price `json:"costs,omitempty" validate:"dive"` // Purchase price per currency
+}
+
 // Product is the main product structure
 type Product struct {
 	ID          string           `json:"id" validate:"required"`
@@ -46,15 +68,66 @@ type Product struct {
 	Prices      []Price          `json:"prices" validate:"required,dive"`
 	Variants    []Variant        `json:"variants" validate:"dive"`
 	Metadata    []MarketMetadata `json:"metadata" validate:"required,dive"`
+	Images      []Image          `json:"images,omitempty" validate:"max=100,dive"`
+	Tags        []string         `json:"tags,omitempty" validate:"max=50,dive,max=64"` // Free-form labels, e.g. "spring-2025"
+	CategoryIDs []string         `json:"category_ids,omitempty" validate:"max=50"`     // Categories the product is listed in
+	Compliance  *Compliance      `json:"compliance,omitempty"`                         // Age restriction, hazardous goods, energy label and certifications
+	Bundle      *Bundle          `json:"bundle,omitempty"`                             // Components and pricing of a bundle product
+	Sourcing    *Sourcing        `json:"sourcing,omitempty"`                           // Supplier and cost, internal only
+	Draft       bool             `json:"draft,omitempty"`                              // Drafts are hidden from the public API and storefront listings
 	CreatedAt   time.Time        `json:"created_at"`
 	UpdatedAt   time.Time        `json:"updated_at"`
-	Version     int64            `json:"version"`   // Version number for optimistic locking
-	LastHash    string           `json:"last_hash"` // Hash of last known state
+	DeletedAt   *time.Time       `json:"deleted_at,omitempty"` // Set while the product is soft-deleted
+	Version     int64            `json:"version"`              // Version number for optimistic locking
+	LastHash    string           `json:"last_hash"`            // Hash of last known state
 }
 
+// ValidateProduct validates a product before it is saved. Failures are
+// returned as a *ValidationError listing the fields.
 func ValidateProduct(product *Product) error {
+	return validateProduct(product, newValidator().Struct(product))
+}
+
+// ValidateNewProduct validates a product before creation, when the ID is not yet assigned
+func ValidateNewProduct(product *Product) error {
+	return validateProduct(product, newValidator().StructExcept(product, "ID"))
+}
+
+// validateProduct checks the rules the struct validator cannot express once
+// the struct rules passed
+func validateProduct(product *Product, structErr error) error {
+	if structErr != nil {
+		return newValidationError(structErr)
+	}
+	if err := product.ValidatePriceOverrides(); err != nil {
+		return ruleError("variants", "price_override", err)
+	}
+	if err := product.Compliance.Validate(); err != nil {
+		return ruleError("compliance", "compliance", err)
+	}
+	if err := product.ValidateBundle(); err != nil {
+		return ruleError("bundle", "bundle", err)
+	}
+	return nil
+}
+
+// newValidator creates a validator with the product-specific rules registered
+func newValidator() *validator.Validate {
 	validate := validator.New()
-	return validate.Struct(product)
+	validate.RegisterTagNameFunc(jsonFieldName)
+	validate.RegisterStructValidation(validateStock, Stock{})
+	validate.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return IsCurrencyCode(fl.Field().String())
+	})
+	return validate
+}
+
+// validateStock rejects negative quantities unless the location allows backorders
+func validateStock(sl validator.StructLevel) {
+	stock := sl.Current().Interface().(Stock)
+	if stock.Quantity < 0 && !stock.Backorder {
+		sl.ReportError(stock.Quantity, "quantity", "Quantity", "gte", "0")
+	}
 }
 
 // CalculateHash generates a hash of the product's current state
@@ -68,6 +141,13 @@ func (p *Product) CalculateHash() string {
 		Prices      []Price          `json:"prices"`
 		Variants    []Variant        `json:"variants"`
 		Metadata    []MarketMetadata `json:"metadata"`
+		Images      []Image          `json:"images,omitempty"`
+		Tags        []string         `json:"tags,omitempty"`
+		CategoryIDs []string         `json:"category_ids,omitempty"`
+		Compliance  *Compliance      `json:"compliance,omitempty"`
+		Bundle      *Bundle          `json:"bundle,omitempty"`
+		Sourcing    *Sourcing        `json:"sourcing,omitempty"`
+		Draft       bool             `json:"draft,omitempty"`
 		Version     int64            `json:"version"`
 	}{
 		ID:          p.ID,
@@ -77,6 +157,13 @@ func (p *Product) CalculateHash() string {
 		Prices:      p.Prices,
 		Variants:    p.Variants,
 		Metadata:    p.Metadata,
+		Images:      p.Images,
+		Tags:        p.Tags,
+		CategoryIDs: p.CategoryIDs,
+		Compliance:  p.Compliance,
+		Bundle:      p.Bundle,
+		Sourcing:    p.Sourcing,
+		Draft:       p.Draft,
 		Version:     p.Version,
 	}
 
@@ -105,12 +192,64 @@ func (p *Product) Clone() *Product {
 
 	if p.Variants != nil {
 		clone.Variants = make([]Variant, len(p.Variants))
-		copy(clone.Variants, p.Variants)
+		for i, variant := range p.Variants {
+			clone.Variants[i] = variant
+			if variant.Attributes != nil {
+				clone.Variants[i].Attributes = make(map[string]string, len(variant.Attributes))
+				for key, value := range variant.Attributes {
+					clone.Variants[i].Attributes[key] = value
+				}
+			}
+			if variant.Stock != nil {
+				clone.Variants[i].Stock = make([]Stock, len(variant.Stock))
+				copy(clone.Variants[i].Stock, variant.Stock)
+			}
+			if variant.Prices != nil {
+				clone.Variants[i].Prices = make([]Price, len(variant.Prices))
+				copy(clone.Variants[i].Prices, variant.Prices)
+			}
+		}
+	}
+
+	if p.Images != nil {
+		clone.Images = make([]Image, len(p.Images))
+		for i, image := range p.Images {
+			clone.Images[i] = image
+			if image.AltTexts != nil {
+				clone.Images[i].AltTexts = make(map[string]string, len(image.AltTexts))
+				for market, text := range image.AltTexts {
+					clone.Images[i].AltTexts[market] = text
+				}
+			}
+		}
+	}
+
+	if p.Tags != nil {
+		clone.Tags = make([]string, len(p.Tags))
+		copy(clone.Tags, p.Tags)
+	}
+
+	if p.CategoryIDs != nil {
+		clone.CategoryIDs = make([]string, len(p.CategoryIDs))
+		copy(clone.CategoryIDs, p.CategoryIDs)
+	}
+
+	clone.Compliance = p.Compliance.Clone()
+	clone.Bundle = p.Bundle.Clone()
+
+	if p.Sourcing != nil {
+		sourcing := *p.Sourcing
+		sourcing.Costs = append([]Price(nil), p.Sourcing.Costs...)
+		clone.Sourcing = &sourcing
 	}
 
 	// Copy timestamps and hash
 	clone.CreatedAt = p.CreatedAt
 	clone.UpdatedAt = p.UpdatedAt
+	if p.DeletedAt != nil {
+		deletedAt := *p.DeletedAt
+		clone.DeletedAt = &deletedAt
+	}
 	clone.LastHash = p.LastHash
 
 	return &clone
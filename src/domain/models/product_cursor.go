@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ProductCursor marks a position in a product listing, which is ordered newest
+// first and by ID among products created at the same time. A page after a
+// cursor starts with the first product past that position, so products
+// created between two pages do not shift or repeat the later pages.
+type ProductCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// SortForListing sorts products in listing order: newest first, then by ID
+func SortForListing(products []*Product) {
+	sort.Slice(products, func(i, j int) bool {
+		if !products[i].CreatedAt.Equal(products[j].CreatedAt) {
+			return products[i].CreatedAt.After(products[j].CreatedAt)
+		}
+		return products[i].ID < products[j].ID
+	})
+}
+
+// CursorFor returns the cursor positioned at a product
+func CursorFor(product *Product) *ProductCursor {
+	return &ProductCursor{CreatedAt: product.CreatedAt, ID: product.ID}
+}
+
+// Follows reports whether a product comes after the cursor in listing order
+func (c *ProductCursor) Follows(product *Product) bool {
+	if c == nil {
+		return true
+	}
+	if !product.CreatedAt.Equal(c.CreatedAt) {
+		return product.CreatedAt.Before(c.CreatedAt)
+	}
+	return product.ID > c.ID
+}
+
+// Encode returns the cursor as an opaque URL-safe string
+func (c *ProductCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeProductCursor parses a cursor returned by Encode
+func DecodeProductCursor(text string) (*ProductCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidRequest)
+	}
+	var cursor ProductCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" || cursor.CreatedAt.IsZero() {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidRequest)
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductCursorRoundTrip(t *testing.T) {
+	cursor := &ProductCursor{CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 123456789, time.UTC), ID: "prod_1"}
+	decoded, err := DecodeProductCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, "prod_1", decoded.ID)
+
+	for _, text := range []string{"not a cursor!", "bm90IGpzb24", "e30"} {
+		_, err := DecodeProductCursor(text)
+		assert.ErrorIs(t, err, ErrInvalidRequest, text)
+	}
+}
+
+func TestProductCursorFollowsListingOrder(t *testing.T) {
+	now := time.Now()
+	products := []*Product{
+		{ID: "b", CreatedAt: now},
+		{ID: "c", CreatedAt: now.Add(-time.Second)},
+		{ID: "a", CreatedAt: now},
+		{ID: "d", CreatedAt: now.Add(time.Second)},
+	}
+	SortForListing(products)
+	ids := make([]string, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	assert.Equal(t, []string{"d", "a", "b", "c"}, ids)
+
+	cursor := CursorFor(products[1])
+	assert.False(t, cursor.Follows(products[0]))
+	assert.False(t, cursor.Follows(products[1]))
+	assert.True(t, cursor.Follows(products[2]))
+	assert.True(t, cursor.Follows(products[3]))
+
+	var start *ProductCursor
+	assert.True(t, start.Follows(products[0]))
+}
@@ -0,0 +1,141 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ProductFilter selects the products a listing returns. Every set field must
+// match; the zero filter matches every product that is not soft-deleted.
+type ProductFilter struct {
+	SKU      string // The product SKU or the SKU of one of its variants
+	Market   string // Products with metadata for the market
+	Currency string // Products with a price in the currency
+	// MinPrice and MaxPrice bound the product price, inclusive. With a
+	// Currency only the price in that currency counts, otherwise any price.
+	MinPrice       *float64
+	MaxPrice       *float64
+	Title          string    // Case-insensitive substring of the base title or a market title
+	Tags           []string  // Products with every one of the tags
+	Categories     []string  // Products in at least one of the categories
+	CreatedAfter   time.Time // Products created after this time
+	CreatedBefore  time.Time // Products created before this time
+	IncludeDeleted bool      // Soft-deleted products match too
+	PublishedOnly  bool      // Drafts do not match
+}
+
+// Normalize returns the filter with trimmed values, upper-case market and
+// currency codes and normalized tags, the form repositories compare with
+func (f ProductFilter) Normalize() ProductFilter {
+	f.SKU = strings.TrimSpace(f.SKU)
+	f.Market = strings.ToUpper(strings.TrimSpace(f.Market))
+	f.Currency = strings.ToUpper(strings.TrimSpace(f.Currency))
+	f.Title = strings.TrimSpace(f.Title)
+	f.Tags = NormalizeTags(f.Tags)
+	f.Categories = NormalizeCategoryIDs(f.Categories)
+	return f
+}
+
+// IsZero reports whether the filter matches every product that is not soft-deleted
+func (f ProductFilter) IsZero() bool {
+	return f.SKU == "" && f.Market == "" && f.Currency == "" &&
+		f.MinPrice == nil && f.MaxPrice == nil && f.Title == "" && len(f.Tags) == 0 && len(f.Categories) == 0 &&
+		f.CreatedAfter.IsZero() && f.CreatedBefore.IsZero() && !f.IncludeDeleted && !f.PublishedOnly
+}
+
+// Validate rejects filters no product can match because of a contradiction
+func (f ProductFilter) Validate() error {
+	if f.MinPrice != nil && *f.MinPrice < 0 {
+		return fmt.Errorf("%w: min_price cannot be negative", ErrInvalidRequest)
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil && *f.MinPrice > *f.MaxPrice {
+		return fmt.Errorf("%w: min_price cannot be above max_price", ErrInvalidRequest)
+	}
+	if f.Currency != "" && !IsCurrencyCode(f.Currency) {
+		return fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidRequest)
+	}
+	if !f.CreatedAfter.IsZero() && !f.CreatedBefore.IsZero() && !f.CreatedAfter.Before(f.CreatedBefore) {
+		return fmt.Errorf("%w: created_after must be before created_before", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// Matches reports whether a product passes a normalized filter
+func (f ProductFilter) Matches(product *Product) bool {
+	if product.IsDeleted() && !f.IncludeDeleted {
+		return false
+	}
+	if f.PublishedOnly && product.Draft {
+		return false
+	}
+	if f.SKU != "" && !product.hasSKU(f.SKU) {
+		return false
+	}
+	if f.Market != "" && product.MetadataForMarket(f.Market) == nil {
+		return false
+	}
+	if (f.Currency != "" || f.MinPrice != nil || f.MaxPrice != nil) && !f.matchesPrice(product) {
+		return false
+	}
+	if f.Title != "" && !product.hasTitle(f.Title) {
+		return false
+	}
+	if len(f.Tags) > 0 && !product.HasTags(f.Tags...) {
+		return false
+	}
+	if len(f.Categories) > 0 && !product.InCategory(f.Categories...) {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && !product.CreatedAt.After(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !product.CreatedAt.Before(f.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// matchesPrice reports whether one of the product prices is in the currency and range
+func (f ProductFilter) matchesPrice(product *Product) bool {
+	for _, price := range product.Prices {
+		if f.Currency != "" && !strings.EqualFold(price.Currency, f.Currency) {
+			continue
+		}
+		if f.MinPrice != nil && price.Amount < *f.MinPrice {
+			continue
+		}
+		if f.MaxPrice != nil && price.Amount > *f.MaxPrice {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// hasSKU reports whether the product or one of its variants has the SKU
+func (p *Product) hasSKU(sku string) bool {
+	if p.SKU == sku {
+		return true
+	}
+	for _, variant := range p.Variants {
+		if variant.SKU == sku {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTitle reports whether the base title or a market title contains the text, ignoring case
+func (p *Product) hasTitle(text string) bool {
+	text = strings.ToLower(text)
+	if strings.Contains(strings.ToLower(p.BaseTitle), text) {
+		return true
+	}
+	for _, metadata := range p.Metadata {
+		if strings.Contains(strings.ToLower(metadata.Title), text) {
+			return true
+		}
+	}
+	return false
+}
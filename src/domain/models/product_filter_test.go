@@ -0,0 +1,102 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func filterTestProduct() *Product {
+	return &Product{
+		SKU:         "SHIRT-1",
+		BaseTitle:   "Linen Shirt",
+		Prices:      []Price{{Currency: "SEK", Amount: 499}, {Currency: "EUR", Amount: 45}},
+		Variants:    []Variant{{ID: "v1", SKU: "SHIRT-1-XL"}},
+		Metadata:    []MarketMetadata{{Market: "SE", Title: "Linneskjorta"}},
+		Tags:        []string{"sale", "summer"},
+		CategoryIDs: []string{"cat_shirts"},
+		CreatedAt:   time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestProductFilterMatches(t *testing.T) {
+	product := filterTestProduct()
+	price := func(amount float64) *float64 { return &amount }
+
+	tests := []struct {
+		name    string
+		filter  ProductFilter
+		matches bool
+	}{
+		{"zero filter", ProductFilter{}, true},
+		{"product sku", ProductFilter{SKU: "SHIRT-1"}, true},
+		{"variant sku", ProductFilter{SKU: "SHIRT-1-XL"}, true},
+		{"other sku", ProductFilter{SKU: "SHIRT-2"}, false},
+		{"market", ProductFilter{Market: "se"}, true},
+		{"missing market", ProductFilter{Market: "NO"}, false},
+		{"currency", ProductFilter{Currency: "eur"}, true},
+		{"missing currency", ProductFilter{Currency: "NOK"}, false},
+		{"price in any currency", ProductFilter{MinPrice: price(400), MaxPrice: price(500)}, true},
+		{"price in currency", ProductFilter{Currency: "EUR", MinPrice: price(400)}, false},
+		{"price bounds are inclusive", ProductFilter{Currency: "SEK", MinPrice: price(499), MaxPrice: price(499)}, true},
+		{"base title", ProductFilter{Title: "LINEN"}, true},
+		{"market title", ProductFilter{Title: "skjorta"}, true},
+		{"other title", ProductFilter{Title: "trousers"}, false},
+		{"tags", ProductFilter{Tags: []string{"Summer", "sale"}}, true},
+		{"missing tag", ProductFilter{Tags: []string{"winter"}}, false},
+		{"one of the categories", ProductFilter{Categories: []string{"cat_trousers", "cat_shirts"}}, true},
+		{"other category", ProductFilter{Categories: []string{"cat_trousers"}}, false},
+		{"created after", ProductFilter{CreatedAfter: product.CreatedAt.Add(-time.Hour)}, true},
+		{"created after is exclusive", ProductFilter{CreatedAfter: product.CreatedAt}, false},
+		{"created before", ProductFilter{CreatedBefore: product.CreatedAt.Add(time.Hour)}, true},
+		{"created before is exclusive", ProductFilter{CreatedBefore: product.CreatedAt}, false},
+		{"every field must match", ProductFilter{SKU: "SHIRT-1", Market: "NO"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := tt.filter.Normalize()
+			assert.Equal(t, tt.matches, filter.Matches(product))
+		})
+	}
+}
+
+func TestProductFilterExcludesDeletedProducts(t *testing.T) {
+	product := filterTestProduct()
+	deletedAt := time.Now()
+	product.DeletedAt = &deletedAt
+
+	assert.False(t, ProductFilter{}.Matches(product))
+	assert.False(t, ProductFilter{SKU: "SHIRT-1"}.Matches(product))
+	assert.True(t, ProductFilter{IncludeDeleted: true}.Matches(product))
+	assert.True(t, ProductFilter{}.IsZero())
+	assert.False(t, ProductFilter{IncludeDeleted: true}.IsZero())
+}
+
+func TestProductFilterPublishedOnly(t *testing.T) {
+	product := filterTestProduct()
+	assert.True(t, ProductFilter{PublishedOnly: true}.Matches(product))
+	product.Draft = true
+	assert.True(t, ProductFilter{}.Matches(product))
+	assert.False(t, ProductFilter{PublishedOnly: true}.Matches(product))
+	assert.False(t, ProductFilter{PublishedOnly: true}.IsZero())
+}
+
+func TestProductFilterNormalize(t *testing.T) {
+	filter := ProductFilter{SKU: " SKU-1 ", Market: "se ", Currency: "sek", Title: " shirt", Tags: []string{"Sale", "sale"}}.Normalize()
+	assert.Equal(t, ProductFilter{SKU: "SKU-1", Market: "SE", Currency: "SEK", Title: "shirt", Tags: []string{"sale"}}, filter)
+	assert.False(t, filter.IsZero())
+	assert.True(t, ProductFilter{Tags: []string{" "}}.Normalize().IsZero())
+}
+
+func TestProductFilterValidate(t *testing.T) {
+	low, high, negative := 10.0, 20.0, -1.0
+	now := time.Now()
+
+	assert.NoError(t, ProductFilter{}.Validate())
+	assert.NoError(t, ProductFilter{Currency: "SEK", MinPrice: &low, MaxPrice: &high, CreatedAfter: now, CreatedBefore: now.Add(time.Hour)}.Validate())
+	assert.ErrorIs(t, ProductFilter{MinPrice: &high, MaxPrice: &low}.Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, ProductFilter{MinPrice: &negative}.Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, ProductFilter{Currency: "KRONOR"}.Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, ProductFilter{CreatedAfter: now, CreatedBefore: now}.Validate(), ErrInvalidRequest)
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// ProductVersion describes a version in a product's history that can be
+// reconstructed from its event
+type ProductVersion struct {
+	Version   int64     `json:"version"`
+	Type      EventType `json:"type"`   // The event that produced the version
+	Action    string    `json:"action"` // e.g. updated, rolled_back or restored
+	JobID     string    `json:"job_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidationWarningCode identifies a data quality issue that does not block a write
+type ValidationWarningCode string
+
+const (
+	WarningShortDescription ValidationWarningCode = "short_description"
+	WarningMissingKeywords  ValidationWarningCode = "missing_keywords"
+	WarningMissingAltText   ValidationWarningCode = "missing_alt_text"
+)
+
+// MinDescriptionLength is the number of characters below which a description is reported as short
+const MinDescriptionLength = 50
+
+// ValidationWarning describes a non-fatal validation issue on a product field
+type ValidationWarning struct {
+	Field   string                `json:"field"`
+	Code    ValidationWarningCode `json:"code"`
+	Message string                `json:"message"`
+}
+
+// CheckQuality returns the soft validation issues of a product. Unlike
+// ValidateProduct these never reject a write; they are reported back so
+// integrations can improve their data over time.
+func CheckQuality(p *Product) []ValidationWarning {
+	warnings := make([]ValidationWarning, 0)
+
+	if length := utf8.RuneCountInString(strings.TrimSpace(p.Description)); length < MinDescriptionLength {
+		warnings = append(warnings, ValidationWarning{
+			Field:   "description",
+			Code:    WarningShortDescription,
+			Message: fmt.Sprintf("description has %d characters, at least %d recommended", length, MinDescriptionLength),
+		})
+	}
+
+	for _, metadata := range p.Metadata {
+		field := fmt.Sprintf("metadata[%s]", metadata.Market)
+		if strings.TrimSpace(metadata.Keywords) == "" {
+			warnings = append(warnings, ValidationWarning{
+				Field:   field + ".keywords",
+				Code:    WarningMissingKeywords,
+				Message: "no keywords for market " + metadata.Market,
+			})
+		}
+		// An empty market description falls back to the base description, checked above
+		if length := utf8.RuneCountInString(strings.TrimSpace(metadata.Description)); length > 0 && length < MinDescriptionLength {
+			warnings = append(warnings, ValidationWarning{
+				Field:   field + ".description",
+				Code:    WarningShortDescription,
+				Message: fmt.Sprintf("description for market %s has %d characters, at least %d recommended", metadata.Market, length, MinDescriptionLength),
+			})
+		}
+	}
+
+	for i, image := range p.Images {
+		if strings.TrimSpace(image.AltText) == "" && len(image.AltTexts) == 0 {
+			warnings = append(warnings, ValidationWarning{
+				Field:   fmt.Sprintf("images[%d].alt_text", i),
+				Code:    WarningMissingAltText,
+				Message: "image has no alt text",
+			})
+		}
+	}
+
+	return warnings
+}
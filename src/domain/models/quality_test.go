@@ -0,0 +1,48 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createQualityProduct() *Product {
+	return &Product{
+		ID:          "prod_1",
+		SKU:         "SHIRT-1",
+		BaseTitle:   "Shirt",
+		Description: strings.Repeat("Soft organic cotton. ", 3),
+		Metadata:    []MarketMetadata{{Market: "SE", Title: "Tröja", Keywords: "tröja, bomull"}},
+		Images:      []Image{{URL: "https://cdn.example.com/shirt.jpg", AltText: "Blue shirt"}},
+	}
+}
+
+func TestCheckQualityClean(t *testing.T) {
+	assert.Empty(t, CheckQuality(createQualityProduct()))
+}
+
+func TestCheckQualityWarnings(t *testing.T) {
+	product := createQualityProduct()
+	product.Description = "Shirt"
+	product.Metadata = append(product.Metadata, MarketMetadata{Market: "DE", Title: "Hemd", Description: "Ein Hemd"})
+	product.Images = append(product.Images, Image{URL: "https://cdn.example.com/back.jpg"})
+
+	warnings := CheckQuality(product)
+	assert.Equal(t, []ValidationWarning{
+		{Field: "description", Code: WarningShortDescription, Message: "description has 5 characters, at least 50 recommended"},
+		{Field: "metadata[DE].keywords", Code: WarningMissingKeywords, Message: "no keywords for market DE"},
+		{Field: "metadata[DE].description", Code: WarningShortDescription, Message: "description for market DE has 8 characters, at least 50 recommended"},
+		{Field: "images[1].alt_text", Code: WarningMissingAltText, Message: "image has no alt text"},
+	}, warnings)
+}
+
+func TestCheckQualityCountsCharacters(t *testing.T) {
+	product := createQualityProduct()
+	// 49 multi-byte characters are still short even though they take more bytes
+	product.Description = strings.Repeat("å", MinDescriptionLength-1)
+
+	warnings := CheckQuality(product)
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, WarningShortDescription, warnings[0].Code)
+}
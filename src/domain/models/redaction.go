@@ -0,0 +1,49 @@
+package models
+
+// RolePublic is the role of storefront callers. They only see published
+// products, without internal fields such as sourcing.
+const RolePublic = "public"
+
+// CanViewInternal reports whether the principal may see internal product
+// fields. Anonymous callers and callers with only the public role may not.
+func (p *Principal) CanViewInternal() bool {
+	if p == nil {
+		return false
+	}
+	for _, role := range p.Roles {
+		if role != RolePublic {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPublished reports whether the product is visible outside the catalog team
+func (p *Product) IsPublished() bool {
+	return !p.Draft && !p.IsDeleted()
+}
+
+// IsDeleted reports whether the product is soft-deleted
+func (p *Product) IsDeleted() bool {
+	return p.DeletedAt != nil
+}
+
+// Redacted returns the product as the principal may see it: the product itself
+// for internal callers, otherwise a copy with the internal fields removed
+func (p *Product) Redacted(principal *Principal) *Product {
+	if principal.CanViewInternal() {
+		return p
+	}
+	redacted := p.Clone()
+	redacted.Sourcing = nil
+	return redacted
+}
+
+// RedactProducts redacts every product for the principal
+func RedactProducts(products []*Product, principal *Principal) []*Product {
+	redacted := make([]*Product, len(products))
+	for i, product := range products {
+		redacted[i] = product.Redacted(principal)
+	}
+	return redacted
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrincipalCanViewInternal(t *testing.T) {
+	var anonymous *Principal
+	assert.False(t, anonymous.CanViewInternal())
+	assert.False(t, (&Principal{Roles: []string{RolePublic}}).CanViewInternal())
+	assert.False(t, (&Principal{}).CanViewInternal())
+	assert.True(t, (&Principal{Roles: []string{"editor"}}).CanViewInternal())
+}
+
+func TestProductRedacted(t *testing.T) {
+	product := &Product{
+		ID:        "prod-1",
+		BaseTitle: "Shirt",
+		Sourcing: &Sourcing{
+			Supplier:    "Acme Textiles",
+			SupplierSKU: "AC-991",
+			Costs:       []Price{{Currency: "SEK", Amount: 42}},
+		},
+	}
+
+	redacted := product.Redacted(&Principal{Roles: []string{RolePublic}})
+	assert.Nil(t, redacted.Sourcing)
+	assert.Equal(t, "Shirt", redacted.BaseTitle)
+	assert.NotNil(t, product.Sourcing, "the stored product must not be changed")
+
+	assert.Nil(t, product.Redacted(nil).Sourcing)
+	assert.Same(t, product, product.Redacted(&Principal{Roles: []string{"admin"}}))
+
+	redactedList := RedactProducts([]*Product{product}, nil)
+	assert.Len(t, redactedList, 1)
+	assert.Nil(t, redactedList[0].Sourcing)
+}
+
+func TestProductCloneCopiesSourcing(t *testing.T) {
+	product := &Product{Sourcing: &Sourcing{Supplier: "Acme", Costs: []Price{{Currency: "SEK", Amount: 42}}}}
+	clone := product.Clone()
+	clone.Sourcing.Costs[0].Amount = 50
+	clone.Sourcing.Supplier = "Other"
+	assert.Equal(t, 42.0, product.Sourcing.Costs[0].Amount)
+	assert.Equal(t, "Acme", product.Sourcing.Supplier)
+}
+
+func TestProductIsPublished(t *testing.T) {
+	assert.True(t, (&Product{}).IsPublished())
+	assert.False(t, (&Product{Draft: true}).IsPublished())
+}
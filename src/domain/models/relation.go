@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RelationType names how a product relates to another
+type RelationType string
+
+const (
+	// RelationRelated links a similar product, e.g. "you may also like"
+	RelationRelated RelationType = "related"
+	// RelationUpsell links a more expensive alternative
+	RelationUpsell RelationType = "upsell"
+	// RelationCrossSell links a product bought together with this one
+	RelationCrossSell RelationType = "cross_sell"
+	// RelationBundle links a product sold in a bundle with this one
+	RelationBundle RelationType = "bundle"
+	// RelationVariantOf links a product that is another variant of the same
+	// item, kept as a separate product
+	RelationVariantOf RelationType = "variant_of"
+)
+
+// RelationTypes lists every relation type
+var RelationTypes = []RelationType{RelationRelated, RelationUpsell, RelationCrossSell, RelationBundle, RelationVariantOf}
+
+// MaxProductRelations is the largest number of relations a product can have
+const MaxProductRelations = 200
+
+// IsValid reports whether the relation type is known
+func (t RelationType) IsValid() bool {
+	for _, known := range RelationTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ProductRelation links a product to another. Relations point one way: an
+// upsell from a shirt to a jacket does not make the shirt an upsell of the
+// jacket.
+type ProductRelation struct {
+	ProductID string       `json:"product_id"`
+	RelatedID string       `json:"related_id"`
+	Type      RelationType `json:"type" example:"cross_sell"`
+	Position  int          `json:"position,omitempty"` // Order among the relations of the type, lowest first
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// Validate normalizes the relation and checks its fields
+func (r *ProductRelation) Validate() error {
+	r.ProductID = strings.TrimSpace(r.ProductID)
+	r.RelatedID = strings.TrimSpace(r.RelatedID)
+	r.Type = RelationType(strings.ToLower(strings.TrimSpace(string(r.Type))))
+	if r.ProductID == "" || r.RelatedID == "" {
+		return fmt.Errorf("%w: related_id is required", ErrInvalidRequest)
+	}
+	if r.ProductID == r.RelatedID {
+		return fmt.Errorf("%w: a product cannot be related to itself", ErrInvalidRequest)
+	}
+	if !r.Type.IsValid() {
+		return fmt.Errorf("%w: unknown relation type %q", ErrInvalidRequest, r.Type)
+	}
+	return nil
+}
+
+// RelatedProduct is a linked product, as returned by the related listing
+type RelatedProduct struct {
+	Type     RelationType `json:"type"`
+	Position int          `json:"position,omitempty"`
+	Product  *Product     `json:"product"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductRelationValidate(t *testing.T) {
+	relation := &ProductRelation{ProductID: "prod_1", RelatedID: " prod_2 ", Type: "Cross_Sell"}
+	assert.NoError(t, relation.Validate())
+	assert.Equal(t, "prod_2", relation.RelatedID)
+	assert.Equal(t, RelationCrossSell, relation.Type)
+
+	tests := map[string]ProductRelation{
+		"related id": {ProductID: "prod_1", Type: RelationUpsell},
+		"self":       {ProductID: "prod_1", RelatedID: "prod_1", Type: RelationUpsell},
+		"type":       {ProductID: "prod_1", RelatedID: "prod_2", Type: "similar"},
+	}
+	for name, relation := range tests {
+		assert.ErrorIs(t, relation.Validate(), ErrInvalidRequest, name)
+	}
+}
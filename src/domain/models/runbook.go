@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Runbook actions on-call engineers run through the admin API
+const (
+	RunbookRebuildProjection = "rebuild_projection"
+	RunbookResendDeliveries  = "resend_failed_deliveries"
+	RunbookReleaseLocks      = "release_stale_locks"
+	RunbookVerifyEventChain  = "verify_event_chain"
+)
+
+// RunbookRun is the audit record of one runbook action, kept whether it
+// succeeded or not
+type RunbookRun struct {
+	ID     string `json:"id"`
+	Action string `json:"action"`
+	Actor  string `json:"actor"`
+	// Subject is what the action ran against: a projection, product or delivery target
+	Subject    string                 `json:"subject"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+	Succeeded  bool                   `json:"succeeded"`
+	Error      string                 `json:"error,omitempty"`
+	Result     interface{}            `json:"result,omitempty"`
+	StartedAt  time.Time              `json:"started_at"`
+	FinishedAt time.Time              `json:"finished_at"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxSearchQueryLength is the longest search text accepted, in characters
+const MaxSearchQueryLength = 200
+
+// MaxSearchPageSize is the largest page of search results
+const MaxSearchPageSize = 100
+
+// SearchQuery is a full-text search of the products
+type SearchQuery struct {
+	Text     string // Words to match, e.g. "blue shirt"
+	Market   string // Searches the texts of the market with its analyzer; empty searches every text
+	Page     int
+	PageSize int
+}
+
+// Validate normalizes the query and checks the text
+func (q *SearchQuery) Validate() error {
+	q.Text = strings.TrimSpace(q.Text)
+	q.Market = NormalizeMarket(q.Market)
+	if q.Text == "" {
+		return fmt.Errorf("%w: search text is required", ErrInvalidRequest)
+	}
+	if utf8.RuneCountInString(q.Text) > MaxSearchQueryLength {
+		return fmt.Errorf("%w: search text is at most %d characters", ErrInvalidRequest, MaxSearchQueryLength)
+	}
+	return nil
+}
+
+// SearchHit is a product matching a search, with its relevance score
+type SearchHit struct {
+	ProductID string  `json:"product_id"`
+	Score     float64 `json:"score"`
+}
+
+// SearchHits is a page of search hits, best first, and the number of matches
+type SearchHits struct {
+	Hits  []SearchHit
+	Total int
+}
+
+// ProductSearchResult is a product found by a search
+type ProductSearchResult struct {
+	Score   float64  `json:"score"`
+	Product *Product `json:"product"`
+}
+
+// SearchDocument holds the searchable text of a product, with the texts of
+// each market it has metadata for kept apart so they can be analyzed in the
+// market's language
+type SearchDocument struct {
+	ProductID   string
+	SKUs        []string // The product SKU first, then the variant SKUs
+	Title       string
+	Description string
+	Tags        []string
+	Markets     map[string]MarketText
+}
+
+// MarketText is the searchable text of a product in a market
+type MarketText struct {
+	Title       string
+	Description string
+	Keywords    string
+}
+
+// SearchDocument returns the searchable text of the product
+func (p *Product) SearchDocument() *SearchDocument {
+	document := &SearchDocument{
+		ProductID:   p.ID,
+		SKUs:        []string{p.SKU},
+		Title:       p.BaseTitle,
+		Description: p.Description,
+		Tags:        append([]string(nil), p.Tags...),
+		Markets:     make(map[string]MarketText, len(p.Metadata)),
+	}
+	for _, variant := range p.Variants {
+		document.SKUs = append(document.SKUs, variant.SKU)
+	}
+	for _, metadata := range p.Metadata {
+		document.Markets[NormalizeMarket(metadata.Market)] = MarketText{
+			Title:       metadata.Title,
+			Description: metadata.Description,
+			Keywords:    metadata.Keywords,
+		}
+	}
+	return document
+}
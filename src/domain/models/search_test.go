@@ -0,0 +1,39 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchQueryValidate(t *testing.T) {
+	query := &SearchQuery{Text: " blue shirt ", Market: " se"}
+	assert.NoError(t, query.Validate())
+	assert.Equal(t, "blue shirt", query.Text)
+	assert.Equal(t, "SE", query.Market)
+
+	assert.ErrorIs(t, (&SearchQuery{Text: " "}).Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, (&SearchQuery{Text: strings.Repeat("å", MaxSearchQueryLength+1)}).Validate(), ErrInvalidRequest)
+	assert.NoError(t, (&SearchQuery{Text: strings.Repeat("å", MaxSearchQueryLength)}).Validate())
+}
+
+func TestProductSearchDocument(t *testing.T) {
+	product := &Product{
+		ID:          "p1",
+		SKU:         "SHIRT-1",
+		BaseTitle:   "Shirt",
+		Description: "Cotton",
+		Tags:        []string{"summer"},
+		Variants:    []Variant{{ID: "v1", SKU: "SHIRT-1-S"}},
+		Metadata:    []MarketMetadata{{Market: "se", Title: "Skjorta", Keywords: "bomull"}},
+	}
+	assert.Equal(t, &SearchDocument{
+		ProductID:   "p1",
+		SKUs:        []string{"SHIRT-1", "SHIRT-1-S"},
+		Title:       "Shirt",
+		Description: "Cotton",
+		Tags:        []string{"summer"},
+		Markets:     map[string]MarketText{"SE": {Title: "Skjorta", Keywords: "bomull"}},
+	}, product.SearchDocument())
+}
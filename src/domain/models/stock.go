@@ -0,0 +1,121 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// StockAdjustment changes the quantity of one variant at one location.
+// A negative Delta is a decrement, for example a reservation. Quantity sets
+// the quantity instead, for example after a stock count.
+type StockAdjustment struct {
+	VariantID  string `json:"variant_id" validate:"required"`
+	LocationID string `json:"location_id" validate:"required"`
+	Delta      int    `json:"delta"`
+	Quantity   *int   `json:"quantity,omitempty"`
+}
+
+// ProductStockAdjustment is a stock adjustment of a variant of a product,
+// used where one request adjusts several products
+type ProductStockAdjustment struct {
+	ProductID string `json:"product_id" validate:"required"`
+	StockAdjustment
+}
+
+// StockLevel is the quantity a variant is set to at a location
+type StockLevel struct {
+	LocationID string `json:"location_id" validate:"required"`
+	Quantity   int    `json:"quantity"`
+}
+
+// VariantStock is the stock of one variant at every location it has an entry for
+type VariantStock struct {
+	ProductID string  `json:"product_id"`
+	VariantID string  `json:"variant_id"`
+	Version   int64   `json:"version"` // Product version the stock was read at
+	Stock     []Stock `json:"stock"`
+}
+
+// VariantStock returns the stock of a variant, or ErrVariantNotFound
+func (p *Product) VariantStock(variantID string) (*VariantStock, error) {
+	variant := findVariant(p.Variants, variantID)
+	if variant == nil {
+		return nil, fmt.Errorf("%w: %s", ErrVariantNotFound, variantID)
+	}
+	return &VariantStock{
+		ProductID: p.ID,
+		VariantID: variant.ID,
+		Version:   p.Version,
+		Stock:     append([]Stock{}, variant.Stock...),
+	}, nil
+}
+
+// ApplyStockAdjustments applies all adjustments to the product or none of them,
+// in order, so a later adjustment of the same location acts on the earlier result.
+// It fails with ErrInsufficientStock if any quantity would drop below zero at a
+// location without backorders, and with ErrVariantNotFound for unknown variants.
+// Adjusting a location the variant has no stock entry for creates one.
+func (p *Product) ApplyStockAdjustments(adjustments []StockAdjustment) error {
+	variants := make([]Variant, len(p.Variants))
+	for i, variant := range p.Variants {
+		variants[i] = variant
+		variants[i].Stock = append([]Stock(nil), variant.Stock...)
+	}
+
+	for _, adjustment := range adjustments {
+		variant := findVariant(variants, adjustment.VariantID)
+		if variant == nil {
+			return fmt.Errorf("%w: %s", ErrVariantNotFound, adjustment.VariantID)
+		}
+
+		stock := findStock(variant, adjustment.LocationID)
+		if stock == nil {
+			variant.Stock = append(variant.Stock, Stock{LocationID: adjustment.LocationID})
+			stock = &variant.Stock[len(variant.Stock)-1]
+		}
+
+		quantity := stock.Quantity + adjustment.Delta
+		if adjustment.Quantity != nil {
+			quantity = *adjustment.Quantity
+		}
+		if quantity < 0 && !stock.Backorder {
+			return fmt.Errorf("%w: variant %s at %s has %d, requested %d",
+				ErrInsufficientStock, adjustment.VariantID, adjustment.LocationID, stock.Quantity, stock.Quantity-quantity)
+		}
+		stock.Quantity = quantity
+	}
+
+	p.Variants = variants
+	return nil
+}
+
+// WithStockAdjustments returns the next version of the product with the adjustments
+// applied, leaving the receiver untouched
+func (p *Product) WithStockAdjustments(adjustments []StockAdjustment, now time.Time) (*Product, error) {
+	next := p.Clone()
+	if err := next.ApplyStockAdjustments(adjustments); err != nil {
+		return nil, err
+	}
+	next.Version++
+	next.UpdatedAt = now
+	next.LastHash = next.CalculateHash()
+	return next, nil
+}
+
+func findVariant(variants []Variant, id string) *Variant {
+	for i := range variants {
+		if variants[i].ID == id {
+			return &variants[i]
+		}
+	}
+	return nil
+}
+
+func findStock(variant *Variant, locationID string) *Stock {
+	for i := range variant.Stock {
+		if variant.Stock[i].LocationID == locationID {
+			return &variant.Stock[i]
+		}
+	}
+	return nil
+}
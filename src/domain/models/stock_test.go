@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stockProduct() *Product {
+	return &Product{
+		ID: "prod_1",
+		Variants: []Variant{
+			{ID: "v1", SKU: "SHIRT-S", Stock: []Stock{{LocationID: "wh1", Quantity: 3}}},
+			{ID: "v2", SKU: "SHIRT-M", Stock: []Stock{{LocationID: "wh1", Quantity: 0, Backorder: true}}},
+		},
+	}
+}
+
+func TestApplyStockAdjustments(t *testing.T) {
+	product := stockProduct()
+
+	err := product.ApplyStockAdjustments([]StockAdjustment{
+		{VariantID: "v1", LocationID: "wh1", Delta: -2},
+		{VariantID: "v1", LocationID: "wh2", Delta: 5},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, product.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, Stock{LocationID: "wh2", Quantity: 5}, product.Variants[0].Stock[1])
+}
+
+func TestApplyStockAdjustmentsInsufficientStock(t *testing.T) {
+	product := stockProduct()
+	original := product.Variants[0].Stock
+
+	err := product.ApplyStockAdjustments([]StockAdjustment{
+		{VariantID: "v1", LocationID: "wh1", Delta: -2},
+		{VariantID: "v1", LocationID: "wh1", Delta: -2},
+	})
+	assert.True(t, errors.Is(err, ErrInsufficientStock))
+
+	// Nothing is applied when one adjustment fails
+	assert.Equal(t, 3, product.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, 3, original[0].Quantity)
+}
+
+func TestApplyStockAdjustmentsBackorder(t *testing.T) {
+	product := stockProduct()
+
+	err := product.ApplyStockAdjustments([]StockAdjustment{{VariantID: "v2", LocationID: "wh1", Delta: -4}})
+	assert.NoError(t, err)
+	assert.Equal(t, -4, product.Variants[1].Stock[0].Quantity)
+}
+
+func TestApplyStockAdjustmentsUnknownVariant(t *testing.T) {
+	err := stockProduct().ApplyStockAdjustments([]StockAdjustment{{VariantID: "missing", LocationID: "wh1", Delta: 1}})
+	assert.True(t, errors.Is(err, ErrVariantNotFound))
+}
+
+func TestApplyStockAdjustmentsNewLocationCannotGoNegative(t *testing.T) {
+	err := stockProduct().ApplyStockAdjustments([]StockAdjustment{{VariantID: "v1", LocationID: "wh9", Delta: -1}})
+	assert.True(t, errors.Is(err, ErrInsufficientStock))
+}
+
+func TestApplyStockAdjustmentsSetsQuantity(t *testing.T) {
+	product := stockProduct()
+	seven, negative := 7, -2
+
+	err := product.ApplyStockAdjustments([]StockAdjustment{
+		{VariantID: "v1", LocationID: "wh1", Quantity: &seven},
+		{VariantID: "v1", LocationID: "wh1", Delta: -1},
+		{VariantID: "v2", LocationID: "wh1", Quantity: &negative},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 6, product.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, -2, product.Variants[1].Stock[0].Quantity)
+
+	err = stockProduct().ApplyStockAdjustments([]StockAdjustment{{VariantID: "v1", LocationID: "wh1", Quantity: &negative}})
+	assert.True(t, errors.Is(err, ErrInsufficientStock))
+}
+
+func TestVariantStock(t *testing.T) {
+	product := stockProduct()
+	product.Version = 4
+
+	stock, err := product.VariantStock("v1")
+	assert.NoError(t, err)
+	assert.Equal(t, &VariantStock{ProductID: "prod_1", VariantID: "v1", Version: 4, Stock: []Stock{{LocationID: "wh1", Quantity: 3}}}, stock)
+
+	// The result is a copy
+	stock.Stock[0].Quantity = 0
+	assert.Equal(t, 3, product.Variants[0].Stock[0].Quantity)
+
+	_, err = product.VariantStock("missing")
+	assert.True(t, errors.Is(err, ErrVariantNotFound))
+}
+
+func TestValidateProductRejectsNegativeStockWithoutBackorder(t *testing.T) {
+	product := &Product{
+		ID: "prod_1", SKU: "SKU", BaseTitle: "Title", Prices: []Price{}, Metadata: []MarketMetadata{},
+		Variants: []Variant{{ID: "v1", SKU: "V1", Attributes: map[string]string{}, Stock: []Stock{{LocationID: "wh1", Quantity: -1}}}},
+	}
+	assert.Error(t, ValidateProduct(product))
+
+	product.Variants[0].Stock[0].Backorder = true
+	assert.NoError(t, ValidateProduct(product))
+}
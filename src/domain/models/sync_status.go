@@ -0,0 +1,107 @@
+package models
+
+import "time"
+
+// SyncState describes the outcome of the last attempt to deliver a product to a downstream target
+type SyncState string
+
+const (
+	SyncStateSynced  SyncState = "synced"
+	SyncStateFailed  SyncState = "failed"
+	SyncStateSkipped SyncState = "skipped" // The product is not part of what the target receives
+	SyncStateRemoved SyncState = "removed"
+)
+
+// Kinds of downstream targets. A target is named "<kind>:<name>", e.g.
+// "marketplace:amazon" or "webhook:<endpoint id>", or just the kind when there is only one.
+const (
+	SyncTargetSearch      = "search"
+	SyncTargetFeed        = "feed"
+	SyncTargetMarketplace = "marketplace"
+	SyncTargetWebhook     = "webhook"
+)
+
+// maxSyncErrors caps the number of delivery errors kept per product and target
+const maxSyncErrors = 10
+
+// SyncTarget returns the target name for a kind of target and an optional instance name
+func SyncTarget(kind, name string) string {
+	if name == "" {
+		return kind
+	}
+	return kind + ":" + name
+}
+
+// DeliveryError is a failed attempt to deliver a product version to a target
+type DeliveryError struct {
+	Version int64     `json:"version"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+// SyncStatus records how far a product has been delivered to one downstream target
+type SyncStatus struct {
+	ProductID     string          `json:"product_id"`
+	Target        string          `json:"target"`
+	State         SyncState       `json:"state"`
+	Version       int64           `json:"version"`        // Product version of the last attempt
+	SyncedVersion int64           `json:"synced_version"` // Last product version the target accepted
+	Error         string          `json:"error,omitempty"`
+	Errors        []DeliveryError `json:"errors,omitempty"` // Most recent delivery errors, oldest first
+	Attempts      int             `json:"attempts"`         // Attempts made for the current version
+	UpdatedAt     time.Time       `json:"updated_at"`
+	SyncedAt      *time.Time      `json:"synced_at,omitempty"`
+}
+
+// NewSyncStatus creates the status of a product that has not been delivered to a target yet
+func NewSyncStatus(productID, target string) *SyncStatus {
+	return &SyncStatus{ProductID: productID, Target: target}
+}
+
+// Handled reports whether a version needs no delivery: it is older than the last
+// attempt, or it is the last attempt and that did not fail
+func (s *SyncStatus) Handled(version int64) bool {
+	if s.Version != version {
+		return s.Version > version
+	}
+	return s.State != "" && s.State != SyncStateFailed
+}
+
+// Listed reports whether the target currently holds a version of the product.
+// A listing stays live until it is removed, even when delivering a later version failed.
+func (s *SyncStatus) Listed() bool {
+	return s.SyncedVersion > 0 && s.State != SyncStateSkipped && s.State != SyncStateRemoved
+}
+
+// Begin starts an attempt to deliver a version
+func (s *SyncStatus) Begin(version int64, at time.Time) {
+	if s.Version == version {
+		s.Attempts++
+	} else {
+		s.Attempts = 1
+	}
+	s.Version = version
+	s.UpdatedAt = at
+}
+
+// Succeed records the outcome of a successful attempt
+func (s *SyncStatus) Succeed(state SyncState, at time.Time) {
+	s.State = state
+	s.Error = ""
+	s.UpdatedAt = at
+	if state == SyncStateSynced {
+		s.SyncedVersion = s.Version
+		s.SyncedAt = &at
+	}
+}
+
+// Fail records a failed attempt, keeping only the last maxSyncErrors errors
+func (s *SyncStatus) Fail(err error, at time.Time) {
+	s.State = SyncStateFailed
+	s.Error = err.Error()
+	s.UpdatedAt = at
+	s.Errors = append(s.Errors, DeliveryError{Version: s.Version, Message: err.Error(), At: at})
+	if len(s.Errors) > maxSyncErrors {
+		s.Errors = append([]DeliveryError(nil), s.Errors[len(s.Errors)-maxSyncErrors:]...)
+	}
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncTarget(t *testing.T) {
+	assert.Equal(t, "search", SyncTarget(SyncTargetSearch, ""))
+	assert.Equal(t, "marketplace:amazon", SyncTarget(SyncTargetMarketplace, "amazon"))
+}
+
+func TestSyncStatusLifecycle(t *testing.T) {
+	status := NewSyncStatus("prod_1", "search")
+	assert.False(t, status.Handled(1))
+	assert.False(t, status.Listed())
+
+	now := time.Now()
+	status.Begin(1, now)
+	status.Fail(errors.New("timeout"), now)
+	assert.Equal(t, SyncStateFailed, status.State)
+	assert.Equal(t, "timeout", status.Error)
+	assert.False(t, status.Handled(1), "failed versions are retried")
+
+	status.Begin(1, now)
+	status.Succeed(SyncStateSynced, now)
+	assert.Equal(t, 2, status.Attempts)
+	assert.Equal(t, int64(1), status.SyncedVersion)
+	assert.Empty(t, status.Error)
+	assert.Len(t, status.Errors, 1, "the error history is kept after a success")
+	assert.True(t, status.Handled(1))
+	assert.True(t, status.Listed())
+
+	status.Begin(2, now)
+	assert.Equal(t, 1, status.Attempts)
+	status.Succeed(SyncStateSkipped, now)
+	assert.Equal(t, int64(1), status.SyncedVersion)
+	assert.False(t, status.Listed())
+	assert.True(t, status.Handled(1), "older versions are never delivered")
+}
+
+func TestSyncStatusKeepsRecentErrors(t *testing.T) {
+	status := NewSyncStatus("prod_1", "search")
+	for i := 1; i <= maxSyncErrors+5; i++ {
+		status.Begin(int64(i), time.Now())
+		status.Fail(fmt.Errorf("attempt %d", i), time.Now())
+	}
+
+	assert.Len(t, status.Errors, maxSyncErrors)
+	assert.Equal(t, "attempt 6", status.Errors[0].Message)
+	assert.Equal(t, int64(15), status.Errors[maxSyncErrors-1].Version)
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// JobTagUpdate is the job type of bulk tag changes
+const JobTagUpdate JobType = "tags.update"
+
+const (
+	// MaxTags is the number of tags a product can have
+	MaxTags = 50
+	// MaxTagLength is the longest tag in characters
+	MaxTagLength = 64
+)
+
+// NormalizeTag trims and lowercases a tag so "Spring-2025 " and "spring-2025" are the same tag
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags normalizes tags, drops empty and duplicate ones and sorts them
+func NormalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+// HasTags reports whether the product has every one of the tags
+func (p *Product) HasTags(tags ...string) bool {
+	for _, tag := range tags {
+		found := false
+		for _, own := range p.Tags {
+			if own == NormalizeTag(tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// TagUsage is how many products have a tag
+type TagUsage struct {
+	Tag      string `json:"tag"`
+	Products int    `json:"products"`
+}
+
+// TagUpdate adds and removes tags on the selected products: the listed
+// products, or every product with Tag
+type TagUpdate struct {
+	ProductIDs []string `json:"product_ids,omitempty"`
+	Tag        string   `json:"tag,omitempty"`
+	Add        []string `json:"add,omitempty"`
+	Remove     []string `json:"remove,omitempty"`
+}
+
+// Validate checks that the update selects products and changes something
+func (u *TagUpdate) Validate() error {
+	if len(u.ProductIDs) == 0 && NormalizeTag(u.Tag) == "" {
+		return fmt.Errorf("%w: product_ids or tag is required", ErrInvalidRequest)
+	}
+	if len(u.ProductIDs) > 0 && u.Tag != "" {
+		return fmt.Errorf("%w: product_ids and tag cannot be combined", ErrInvalidRequest)
+	}
+	if len(NormalizeTags(u.Add)) == 0 && len(NormalizeTags(u.Remove)) == 0 {
+		return fmt.Errorf("%w: add or remove is required", ErrInvalidRequest)
+	}
+	for _, tag := range u.Add {
+		if len(NormalizeTag(tag)) > MaxTagLength {
+			return fmt.Errorf("%w: tag %q is longer than %d characters", ErrInvalidRequest, tag, MaxTagLength)
+		}
+	}
+	return nil
+}
+
+// Apply returns a copy of the product with the tags added and removed, and
+// whether anything changed. A tag both added and removed is removed.
+func (u *TagUpdate) Apply(p *Product) (*Product, bool, error) {
+	removed := make(map[string]bool)
+	for _, tag := range NormalizeTags(u.Remove) {
+		removed[tag] = true
+	}
+
+	tags := make([]string, 0, len(p.Tags)+len(u.Add))
+	for _, tag := range append(append([]string{}, p.Tags...), u.Add...) {
+		if !removed[NormalizeTag(tag)] {
+			tags = append(tags, tag)
+		}
+	}
+	tags = NormalizeTags(tags)
+	if len(tags) > MaxTags {
+		return nil, false, fmt.Errorf("product would have %d tags, the limit is %d", len(tags), MaxTags)
+	}
+	if strings.Join(tags, "\x00") == strings.Join(p.Tags, "\x00") {
+		return p, false, nil
+	}
+
+	updated := p.Clone()
+	updated.Tags = tags
+	return updated, true, nil
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	assert.Equal(t, []string{"sale", "spring-2025"}, NormalizeTags([]string{" Spring-2025", "sale", "SALE", ""}))
+	assert.Nil(t, NormalizeTags([]string{" ", ""}))
+	assert.Nil(t, NormalizeTags(nil))
+}
+
+func TestProductHasTags(t *testing.T) {
+	product := &Product{Tags: []string{"sale", "spring-2025"}}
+	assert.True(t, product.HasTags("sale"))
+	assert.True(t, product.HasTags("Spring-2025", "sale"))
+	assert.False(t, product.HasTags("sale", "winter"))
+	assert.True(t, product.HasTags())
+}
+
+func TestTagUpdateValidate(t *testing.T) {
+	assert.NoError(t, (&TagUpdate{Tag: "spring-2025", Add: []string{"published"}}).Validate())
+	assert.NoError(t, (&TagUpdate{ProductIDs: []string{"p1"}, Remove: []string{"draft"}}).Validate())
+
+	tests := []struct {
+		name   string
+		update TagUpdate
+	}{
+		{"no selection", TagUpdate{Add: []string{"a"}}},
+		{"both selections", TagUpdate{ProductIDs: []string{"p1"}, Tag: "a", Add: []string{"b"}}},
+		{"no change", TagUpdate{Tag: "a", Add: []string{" "}}},
+		{"long tag", TagUpdate{Tag: "a", Add: []string{strings.Repeat("x", MaxTagLength+1)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.update.Validate(), ErrInvalidRequest)
+		})
+	}
+}
+
+func TestTagUpdateApply(t *testing.T) {
+	product := &Product{ID: "p1", Tags: []string{"draft", "spring-2025"}}
+
+	updated, changed, err := (&TagUpdate{Add: []string{"Published"}, Remove: []string{"draft"}}).Apply(product)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"published", "spring-2025"}, updated.Tags)
+	assert.Equal(t, []string{"draft", "spring-2025"}, product.Tags, "the product is not modified")
+
+	_, changed, err = (&TagUpdate{Add: []string{"draft"}, Remove: []string{"winter"}}).Apply(product)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	many := make([]string, MaxTags)
+	for i := range many {
+		many[i] = strings.Repeat("t", i+1)
+	}
+	_, _, err = (&TagUpdate{Add: many}).Apply(product)
+	assert.Error(t, err)
+}
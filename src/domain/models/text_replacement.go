@@ -0,0 +1,174 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Text fields a replacement can change. The metadata fields apply to every
+// market, or only to the filter's market when it has one.
+const (
+	TextFieldBaseTitle           = "base_title"
+	TextFieldDescription         = "description"
+	TextFieldMetadataTitle       = "metadata.title"
+	TextFieldMetadataDescription = "metadata.description"
+	TextFieldMetadataKeywords    = "metadata.keywords"
+)
+
+// textFields are the fields replaced when none are listed
+var textFields = []string{
+	TextFieldBaseTitle,
+	TextFieldDescription,
+	TextFieldMetadataTitle,
+	TextFieldMetadataDescription,
+	TextFieldMetadataKeywords,
+}
+
+// MaxTextReplacementPreview caps the number of products listed in a preview
+const MaxTextReplacementPreview = 100
+
+// TextReplacement replaces a string or regular expression in the text fields
+// of the products selected by the filter
+type TextReplacement struct {
+	Find       string        `json:"find"`
+	Replace    string        `json:"replace"`
+	Regex      bool          `json:"regex,omitempty"`       // Find is a Go regular expression and Replace may use $1 or ${name}
+	IgnoreCase bool          `json:"ignore_case,omitempty"` // Match regardless of case
+	Fields     []string      `json:"fields,omitempty"`      // Fields to change; all text fields when empty
+	Filter     CatalogFilter `json:"filter"`
+	DryRun     bool          `json:"dry_run,omitempty"` // Preview the changes without writing them
+
+	pattern *regexp.Regexp
+}
+
+// TextChange is one field changed by a replacement
+type TextChange struct {
+	Field string `json:"field"` // e.g. "base_title" or "metadata[SE].keywords"
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// TextReplacementDiff lists the changes to one product
+type TextReplacementDiff struct {
+	ProductID string       `json:"product_id"`
+	SKU       string       `json:"sku"`
+	Changes   []TextChange `json:"changes"`
+}
+
+// TextReplacementPreview is the outcome of a dry run. Matched counts every
+// product that would change; Products lists the first of them.
+type TextReplacementPreview struct {
+	Matched   int                    `json:"matched"`
+	Products  []*TextReplacementDiff `json:"products"`
+	Truncated bool                   `json:"truncated"`
+}
+
+// Validate checks the replacement and compiles its pattern
+func (r *TextReplacement) Validate() error {
+	if r.Find == "" {
+		return fmt.Errorf("%w: find is required", ErrInvalidRequest)
+	}
+	for _, field := range r.Fields {
+		if !isTextField(field) {
+			return fmt.Errorf("%w: unknown field %q, expected one of %s", ErrInvalidRequest, field, strings.Join(textFields, ", "))
+		}
+	}
+
+	expression := r.Find
+	if !r.Regex {
+		expression = regexp.QuoteMeta(expression)
+	}
+	if r.IgnoreCase {
+		expression = "(?i)" + expression
+	}
+	pattern, err := regexp.Compile(expression)
+	if err != nil {
+		return fmt.Errorf("%w: invalid regular expression: %v", ErrInvalidRequest, err)
+	}
+	r.pattern = pattern
+	return nil
+}
+
+// Apply returns a copy of the product with the replacement applied and the
+// fields it changed. The product is returned unchanged when nothing matched.
+// Validate must have been called first.
+func (r *TextReplacement) Apply(p *Product) (*Product, []TextChange) {
+	replaced := p.Clone()
+	changes := make([]TextChange, 0)
+
+	replace := func(field string, value *string) {
+		updated := r.replace(*value)
+		if updated == *value {
+			return
+		}
+		changes = append(changes, TextChange{Field: field, Old: *value, New: updated})
+		*value = updated
+	}
+
+	for _, field := range r.fields() {
+		switch field {
+		case TextFieldBaseTitle:
+			replace(field, &replaced.BaseTitle)
+		case TextFieldDescription:
+			replace(field, &replaced.Description)
+		default:
+			for i := range replaced.Metadata {
+				metadata := &replaced.Metadata[i]
+				if r.Filter.Market != "" && !strings.EqualFold(metadata.Market, r.Filter.Market) {
+					continue
+				}
+				name := strings.TrimPrefix(field, "metadata.")
+				label := fmt.Sprintf("metadata[%s].%s", metadata.Market, name)
+				switch field {
+				case TextFieldMetadataTitle:
+					replace(label, &metadata.Title)
+				case TextFieldMetadataDescription:
+					replace(label, &metadata.Description)
+				case TextFieldMetadataKeywords:
+					replace(label, &metadata.Keywords)
+				}
+			}
+		}
+	}
+
+	if len(changes) == 0 {
+		return p, changes
+	}
+	return replaced, changes
+}
+
+// replace applies the pattern to one value
+func (r *TextReplacement) replace(value string) string {
+	if r.Regex {
+		return r.pattern.ReplaceAllString(value, r.Replace)
+	}
+	return r.pattern.ReplaceAllLiteralString(value, r.Replace)
+}
+
+// fields returns the fields to change, in a fixed order
+func (r *TextReplacement) fields() []string {
+	if len(r.Fields) == 0 {
+		return textFields
+	}
+	selected := make([]string, 0, len(r.Fields))
+	for _, field := range textFields {
+		for _, listed := range r.Fields {
+			if listed == field {
+				selected = append(selected, field)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// isTextField reports whether a field can be replaced in
+func isTextField(field string) bool {
+	for _, known := range textFields {
+		if field == known {
+			return true
+		}
+	}
+	return false
+}
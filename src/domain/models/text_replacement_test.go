@@ -0,0 +1,89 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createTextProduct() *Product {
+	return &Product{
+		ID:          "prod_1",
+		SKU:         "SHIRT-1",
+		BaseTitle:   "Colour shirt",
+		Description: "A shirt in a bright colour",
+		Metadata: []MarketMetadata{
+			{Market: "SE", Title: "Tröja", Keywords: "colour, shirt"},
+			{Market: "GB", Title: "Colour shirt", Keywords: "colour"},
+		},
+	}
+}
+
+func TestTextReplacementValidate(t *testing.T) {
+	assert.ErrorIs(t, (&TextReplacement{}).Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, (&TextReplacement{Find: "a", Fields: []string{"sku"}}).Validate(), ErrInvalidRequest)
+	assert.ErrorIs(t, (&TextReplacement{Find: "(", Regex: true}).Validate(), ErrInvalidRequest)
+
+	// A literal find is never read as a pattern
+	assert.NoError(t, (&TextReplacement{Find: "("}).Validate())
+}
+
+func TestTextReplacementApply(t *testing.T) {
+	product := createTextProduct()
+	replacement := &TextReplacement{Find: "colour", Replace: "color", IgnoreCase: true}
+	assert.NoError(t, replacement.Validate())
+
+	replaced, changes := replacement.Apply(product)
+	assert.Equal(t, "color shirt", replaced.BaseTitle)
+	assert.Equal(t, "A shirt in a bright color", replaced.Description)
+	assert.Equal(t, "color, shirt", replaced.Metadata[0].Keywords)
+	assert.Equal(t, []TextChange{
+		{Field: "base_title", Old: "Colour shirt", New: "color shirt"},
+		{Field: "description", Old: "A shirt in a bright colour", New: "A shirt in a bright color"},
+		{Field: "metadata[GB].title", Old: "Colour shirt", New: "color shirt"},
+		{Field: "metadata[SE].keywords", Old: "colour, shirt", New: "color, shirt"},
+		{Field: "metadata[GB].keywords", Old: "colour", New: "color"},
+	}, changes)
+
+	// The original is left alone
+	assert.Equal(t, "Colour shirt", product.BaseTitle)
+}
+
+func TestTextReplacementFieldsAndMarket(t *testing.T) {
+	replacement := &TextReplacement{
+		Find:    "colour",
+		Replace: "color",
+		Fields:  []string{TextFieldMetadataKeywords},
+		Filter:  CatalogFilter{Market: "gb"},
+	}
+	assert.NoError(t, replacement.Validate())
+
+	replaced, changes := replacement.Apply(createTextProduct())
+	assert.Equal(t, []TextChange{{Field: "metadata[GB].keywords", Old: "colour", New: "color"}}, changes)
+	assert.Equal(t, "colour, shirt", replaced.Metadata[0].Keywords)
+	assert.Equal(t, "Colour shirt", replaced.BaseTitle)
+}
+
+func TestTextReplacementRegex(t *testing.T) {
+	replacement := &TextReplacement{Find: `(\w+) shirt`, Replace: "shirt in $1", Regex: true, Fields: []string{TextFieldBaseTitle}}
+	assert.NoError(t, replacement.Validate())
+
+	replaced, _ := replacement.Apply(createTextProduct())
+	assert.Equal(t, "shirt in Colour", replaced.BaseTitle)
+
+	// Literal replacements keep $ signs as they are
+	literal := &TextReplacement{Find: "Colour", Replace: "$1", Fields: []string{TextFieldBaseTitle}}
+	assert.NoError(t, literal.Validate())
+	replaced, _ = literal.Apply(createTextProduct())
+	assert.Equal(t, "$1 shirt", replaced.BaseTitle)
+}
+
+func TestTextReplacementWithoutMatches(t *testing.T) {
+	product := createTextProduct()
+	replacement := &TextReplacement{Find: "trousers", Replace: "pants"}
+	assert.NoError(t, replacement.Validate())
+
+	replaced, changes := replacement.Apply(product)
+	assert.Empty(t, changes)
+	assert.Same(t, product, replaced)
+}
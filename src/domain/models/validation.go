@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError is one field of a request that failed validation
+type FieldError struct {
+	Field   string `json:"field" example:"variants[0].sku"` // JSON path of the field
+	Tag     string `json:"tag" example:"required"`          // The rule the field broke
+	Message string `json:"message" example:"variants[0].sku is required"`
+}
+
+// ValidationError lists every field of a product that failed validation. It
+// is of kind ErrValidation and wraps ErrInvalidRequest, so callers that only
+// check for that still match.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Message
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidRequest, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidRequest
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// newValidationError converts the errors of the struct validator into a
+// ValidationError. Other errors are returned as they are.
+func newValidationError(err error) error {
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return err
+	}
+	fields := make([]FieldError, len(invalid))
+	for i, fieldErr := range invalid {
+		fields[i] = fieldErrorFor(fieldErr)
+	}
+	return &ValidationError{Fields: fields}
+}
+
+// ruleError describes a failed rule that is checked outside the struct
+// validator, e.g. price overrides, as a ValidationError on one field
+func ruleError(field, tag string, err error) error {
+	message := strings.TrimPrefix(err.Error(), ErrInvalidRequest.Error()+": ")
+	return &ValidationError{Fields: []FieldError{{Field: field, Tag: tag, Message: message}}}
+}
+
+// fieldErrorFor describes one validator error with the JSON path of its field
+func fieldErrorFor(err validator.FieldError) FieldError {
+	field := err.Namespace()
+	if i := strings.Index(field, "."); i >= 0 {
+		field = field[i+1:] // Drop the name of the validated struct
+	}
+	return FieldError{
+		Field:   field,
+		Tag:     err.Tag(),
+		Message: field + " " + ruleMessage(err),
+	}
+}
+
+// ruleMessage explains a failed rule in words
+func ruleMessage(err validator.FieldError) string {
+	counted := err.Kind() == reflect.Slice || err.Kind() == reflect.Map || err.Kind() == reflect.Array
+	switch err.Tag() {
+	case "required":
+		return "is required"
+	case "len":
+		if counted {
+			return fmt.Sprintf("must have exactly %s items", err.Param())
+		}
+		return fmt.Sprintf("must be exactly %s characters", err.Param())
+	case "gte", "min":
+		if counted {
+			return fmt.Sprintf("must have at least %s items", err.Param())
+		}
+		if err.Kind() == reflect.String {
+			return fmt.Sprintf("must be at least %s characters", err.Param())
+		}
+		return fmt.Sprintf("must be at least %s", err.Param())
+	case "lte", "max":
+		if counted {
+			return fmt.Sprintf("must have at most %s items", err.Param())
+		}
+		if err.Kind() == reflect.String {
+			return fmt.Sprintf("must be at most %s characters", err.Param())
+		}
+		return fmt.Sprintf("must be at most %s", err.Param())
+	case "url":
+		return "must be a valid URL"
+	case "currency":
+		return "must be an ISO 4217 currency code"
+	default:
+		return fmt.Sprintf("failed the %s rule", err.Tag())
+	}
+}
+
+// jsonFieldName names struct fields in validation errors as they are named in JSON
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validProduct() *Product {
+	return &Product{
+		ID:        "prod_1",
+		SKU:       "SKU-1",
+		BaseTitle: "Shirt",
+		Prices:    []Price{{Currency: "SEK", Amount: 100}},
+		Metadata:  []MarketMetadata{{Market: "SE", Title: "Skjorta"}},
+	}
+}
+
+func TestValidateProductListsFailingFields(t *testing.T) {
+	product := validProduct()
+	product.BaseTitle = ""
+	product.Variants = []Variant{{ID: "v1", Attributes: map[string]string{"size": "M"}, Stock: []Stock{{LocationID: "wh", Quantity: -1}}}}
+	product.Images = []Image{{URL: "not a url"}}
+
+	err := ValidateProduct(product)
+
+	var invalid *ValidationError
+	assert.True(t, errors.As(err, &invalid))
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	assert.Equal(t, []FieldError{
+		{Field: "base_title", Tag: "required", Message: "base_title is required"},
+		{Field: "variants[0].sku", Tag: "required", Message: "variants[0].sku is required"},
+		{Field: "variants[0].stock[0].quantity", Tag: "gte", Message: "variants[0].stock[0].quantity must be at least 0"},
+		{Field: "images[0].url", Tag: "url", Message: "images[0].url must be a valid URL"},
+	}, invalid.Fields)
+	assert.Equal(t, "invalid request: base_title is required; variants[0].sku is required; "+
+		"variants[0].stock[0].quantity must be at least 0; images[0].url must be a valid URL", err.Error())
+}
+
+func TestValidateProductCountsItems(t *testing.T) {
+	product := validProduct()
+	product.Tags = make([]string, 51)
+	for i := range product.Tags {
+		product.Tags[i] = "tag"
+	}
+
+	var invalid *ValidationError
+	assert.True(t, errors.As(ValidateProduct(product), &invalid))
+	assert.Equal(t, []FieldError{{Field: "tags", Tag: "max", Message: "tags must have at most 50 items"}}, invalid.Fields)
+}
+
+func TestValidateProductReportsRuleFailuresByField(t *testing.T) {
+	product := validProduct()
+	product.Variants = []Variant{{ID: "v1", SKU: "SKU-1-M", Attributes: map[string]string{"size": "M"}, Prices: []Price{{Currency: "NOK", Amount: 90}}}}
+
+	var invalid *ValidationError
+	assert.True(t, errors.As(ValidateProduct(product), &invalid))
+	assert.Equal(t, []FieldError{{
+		Field:   "variants",
+		Tag:     "price_override",
+		Message: "variant v1 overrides NOK, which the product has no price in",
+	}}, invalid.Fields)
+}
+
+func TestValidateNewProductSkipsID(t *testing.T) {
+	product := validProduct()
+	product.ID = ""
+
+	assert.NoError(t, ValidateNewProduct(product))
+	assert.Error(t, ValidateProduct(product))
+}
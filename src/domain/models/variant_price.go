@@ -0,0 +1,91 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidatePriceOverrides checks the variant prices: a variant may only override
+// a currency the product has a price in, once per currency, so every variant
+// always resolves to a price in each of the product's currencies
+func (p *Product) ValidatePriceOverrides() error {
+	for _, variant := range p.Variants {
+		seen := make(map[string]bool, len(variant.Prices))
+		for _, price := range variant.Prices {
+			currency := strings.ToUpper(price.Currency)
+			if !p.hasCurrency(currency) {
+				return fmt.Errorf("%w: variant %s overrides %s, which the product has no price in", ErrInvalidRequest, variant.ID, currency)
+			}
+			if seen[currency] {
+				return fmt.Errorf("%w: variant %s overrides %s more than once", ErrInvalidRequest, variant.ID, currency)
+			}
+			if price.Amount < 0 {
+				return fmt.Errorf("%w: variant %s has a negative %s price", ErrInvalidRequest, variant.ID, currency)
+			}
+			seen[currency] = true
+		}
+	}
+	return nil
+}
+
+// PriceFor resolves the price of a variant in a currency: the variant's
+// override if it has one, otherwise the product price. A nil variant resolves
+// to the product price.
+func (p *Product) PriceFor(variant *Variant, currency string) (Price, bool) {
+	if variant != nil {
+		for _, price := range variant.Prices {
+			if strings.EqualFold(price.Currency, currency) {
+				return price, true
+			}
+		}
+	}
+	for _, price := range p.Prices {
+		if strings.EqualFold(price.Currency, currency) {
+			return price, true
+		}
+	}
+	return Price{}, false
+}
+
+// VariantPrices returns the resolved price of a variant in each of the product's currencies
+func (p *Product) VariantPrices(variant *Variant) []Price {
+	prices := make([]Price, 0, len(p.Prices))
+	for _, base := range p.Prices {
+		price, _ := p.PriceFor(variant, base.Currency)
+		prices = append(prices, price)
+	}
+	return prices
+}
+
+// PriceRange returns the lowest and highest resolved variant price in a
+// currency. Products without variants range over the product price only.
+func (p *Product) PriceRange(currency string) (min, max float64, ok bool) {
+	if len(p.Variants) == 0 {
+		price, found := p.PriceFor(nil, currency)
+		return price.Amount, price.Amount, found
+	}
+	for i := range p.Variants {
+		price, found := p.PriceFor(&p.Variants[i], currency)
+		if !found {
+			continue
+		}
+		if !ok || price.Amount < min {
+			min = price.Amount
+		}
+		if !ok || price.Amount > max {
+			max = price.Amount
+		}
+		ok = true
+	}
+	return min, max, ok
+}
+
+// hasCurrency reports whether the product has a price in the currency
+func (p *Product) hasCurrency(currency string) bool {
+	for _, price := range p.Prices {
+		if strings.EqualFold(price.Currency, currency) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createSizePricedProduct() *Product {
+	return &Product{
+		ID:     "prod_1",
+		SKU:    "SHIRT-1",
+		Prices: []Price{{Currency: "SEK", Amount: 299}, {Currency: "EUR", Amount: 29}},
+		Variants: []Variant{
+			{ID: "v1", SKU: "SHIRT-1-S"},
+			{ID: "v2", SKU: "SHIRT-1-XXL", Prices: []Price{{Currency: "sek", Amount: 349}}},
+		},
+	}
+}
+
+func TestPriceForPrefersVariantOverride(t *testing.T) {
+	product := createSizePricedProduct()
+
+	price, ok := product.PriceFor(&product.Variants[1], "SEK")
+	assert.True(t, ok)
+	assert.Equal(t, 349.0, price.Amount)
+
+	// Currencies without an override fall back to the product price
+	price, ok = product.PriceFor(&product.Variants[1], "EUR")
+	assert.True(t, ok)
+	assert.Equal(t, 29.0, price.Amount)
+
+	price, ok = product.PriceFor(&product.Variants[0], "SEK")
+	assert.True(t, ok)
+	assert.Equal(t, 299.0, price.Amount)
+
+	_, ok = product.PriceFor(nil, "NOK")
+	assert.False(t, ok)
+}
+
+func TestVariantPrices(t *testing.T) {
+	product := createSizePricedProduct()
+
+	assert.Equal(t,
+		[]Price{{Currency: "sek", Amount: 349}, {Currency: "EUR", Amount: 29}},
+		product.VariantPrices(&product.Variants[1]))
+}
+
+func TestPriceRange(t *testing.T) {
+	product := createSizePricedProduct()
+
+	min, max, ok := product.PriceRange("SEK")
+	assert.True(t, ok)
+	assert.Equal(t, 299.0, min)
+	assert.Equal(t, 349.0, max)
+
+	min, max, ok = product.PriceRange("EUR")
+	assert.True(t, ok)
+	assert.Equal(t, min, max)
+
+	product.Variants = nil
+	min, _, ok = product.PriceRange("SEK")
+	assert.True(t, ok)
+	assert.Equal(t, 299.0, min)
+
+	_, _, ok = product.PriceRange("NOK")
+	assert.False(t, ok)
+}
+
+func TestValidatePriceOverrides(t *testing.T) {
+	assert.NoError(t, createSizePricedProduct().ValidatePriceOverrides())
+
+	tests := []struct {
+		name   string
+		prices []Price
+	}{
+		{"currency without product price", []Price{{Currency: "NOK", Amount: 399}}},
+		{"duplicate currency", []Price{{Currency: "SEK", Amount: 349}, {Currency: "sek", Amount: 359}}},
+		{"negative amount", []Price{{Currency: "SEK", Amount: -1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := createSizePricedProduct()
+			product.Variants[1].Prices = tt.prices
+			assert.ErrorIs(t, product.ValidatePriceOverrides(), ErrInvalidRequest)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MinWebhookSecretLength is the shortest secret a webhook endpoint may sign with
+const MinWebhookSecretLength = 16
+
+// WebhookEventTypes are the events webhook endpoints can subscribe to
+var WebhookEventTypes = []EventType{
+	EventProductCreated,
+	EventProductUpdated,
+	EventProductDeleted,
+	EventProductRestored,
+	EventStockChanged,
+}
+
+// WebhookAuth describes how deliveries to a receiver are authenticated, in
+// addition to the signature
+type WebhookAuth struct {
+	Type         string   `json:"type"`
+	TokenURL     string   `json:"token_url"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes,omitempty"`
+	Audience     string   `json:"audience,omitempty"`
+}
+
+// WebhookEndpoint is a URL product events are delivered to. The secret signs
+// every delivery and is only returned when the endpoint is registered.
+type WebhookEndpoint struct {
+	ID          string       `json:"id"`
+	URL         string       `json:"url" example:"https://erp.example.com/hooks/catalog"`
+	EventTypes  []EventType  `json:"event_types"` // Empty subscribes to every event type
+	Description string       `json:"description,omitempty"`
+	Secret      string       `json:"-"`
+	Auth        *WebhookAuth `json:"-"`
+	Disabled    bool         `json:"disabled"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// Subscribes reports whether the endpoint receives events of a type
+func (e *WebhookEndpoint) Subscribes(eventType EventType) bool {
+	if len(e.EventTypes) == 0 {
+		return true
+	}
+	for _, subscribed := range e.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the URL, event types and secret of an endpoint
+func (e *WebhookEndpoint) Validate() error {
+	parsed, err := url.Parse(e.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidRequest)
+	}
+	for _, eventType := range e.EventTypes {
+		if !isWebhookEventType(eventType) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidRequest, eventType)
+		}
+	}
+	if len(e.Secret) < MinWebhookSecretLength {
+		return fmt.Errorf("%w: secret must be at least %d characters", ErrInvalidRequest, MinWebhookSecretLength)
+	}
+	if e.Auth != nil && strings.TrimSpace(e.Auth.Type) == "" {
+		return fmt.Errorf("%w: auth type is required", ErrInvalidRequest)
+	}
+	return nil
+}
+
+func isWebhookEventType(eventType EventType) bool {
+	for _, known := range WebhookEventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one attempt to deliver an event to a webhook endpoint
+type WebhookDelivery struct {
+	ID         string     `json:"id"`
+	EndpointID string     `json:"endpoint_id"`
+	EventID    string     `json:"event_id"`
+	EventType  EventType  `json:"event_type"`
+	ProductID  string     `json:"product_id"`
+	Version    int64      `json:"version"`
+	Attempt    int        `json:"attempt"`
+	StatusCode int        `json:"status_code,omitempty"` // 0 when no response was received
+	Error      string     `json:"error,omitempty"`
+	Succeeded  bool       `json:"succeeded"`
+	DurationMs int64      `json:"duration_ms"`
+	NextRetry  *time.Time `json:"next_retry,omitempty"` // Set when the attempt failed and will be retried
+	At         time.Time  `json:"at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookEndpointSubscribes(t *testing.T) {
+	all := &WebhookEndpoint{}
+	assert.True(t, all.Subscribes(EventProductDeleted))
+
+	updates := &WebhookEndpoint{EventTypes: []EventType{EventProductUpdated}}
+	assert.True(t, updates.Subscribes(EventProductUpdated))
+	assert.False(t, updates.Subscribes(EventProductCreated))
+}
+
+func TestWebhookEndpointValidate(t *testing.T) {
+	valid := WebhookEndpoint{URL: "https://erp.example.com/hooks", Secret: "0123456789abcdef"}
+	assert.NoError(t, valid.Validate())
+
+	for name, endpoint := range map[string]WebhookEndpoint{
+		"relative url":       {URL: "/hooks", Secret: valid.Secret},
+		"other scheme":       {URL: "ftp://erp.example.com", Secret: valid.Secret},
+		"unknown event type": {URL: valid.URL, Secret: valid.Secret, EventTypes: []EventType{"order.created"}},
+		"short secret":       {URL: valid.URL, Secret: "short"},
+		"auth without type":  {URL: valid.URL, Secret: valid.Secret, Auth: &WebhookAuth{}},
+	} {
+		assert.ErrorIs(t, endpoint.Validate(), ErrInvalidRequest, name)
+	}
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// AllocationPolicyRepository stores the allocation policies of markets and their channels
+type AllocationPolicyRepository interface {
+	// Get returns the policy configured for a market and channel, or ErrAllocationPolicyNotFound.
+	// The empty channel is the market-wide policy.
+	Get(market, channel string) (*models.AllocationPolicy, error)
+	// Save creates or replaces the policy for the policy's market and channel
+	Save(policy *models.AllocationPolicy) error
+	// Delete removes a policy, returning ErrAllocationPolicyNotFound if there is none
+	Delete(market, channel string) error
+	// ListByMarket returns every policy of a market, ordered by channel
+	ListByMarket(market string) ([]*models.AllocationPolicy, error)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// CategoryRepository stores the category taxonomy
+type CategoryRepository interface {
+	// Get returns a category, or ErrCategoryNotFound
+	Get(id string) (*models.Category, error)
+	// Save creates or replaces a category
+	Save(category *models.Category) error
+	// Delete removes a category, or returns ErrCategoryNotFound
+	Delete(id string) error
+	// List returns every category, ordered by ID
+	List() ([]*models.Category, error)
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// ForecastInputRepository stores the stock ledger and sales velocities pushed for forecasting
+type ForecastInputRepository interface {
+	// AppendLedger adds stock movements; entries may arrive out of order
+	AppendLedger(entries []models.LedgerEntry) error
+	// SaveVelocity replaces the velocity of the velocity's variant unless a newer one is stored
+	SaveVelocity(velocity models.SalesVelocity) error
+	// Ledger returns a variant's movements at or after since, oldest first
+	Ledger(variantID string, since time.Time) ([]models.LedgerEntry, error)
+	// Velocity returns a variant's latest velocity, or nil if none was pushed
+	Velocity(variantID string) (*models.SalesVelocity, error)
+}
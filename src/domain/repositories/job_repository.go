@@ -0,0 +1,11 @@
+package repositories
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// JobRepository defines the interface for job storage
+type JobRepository interface {
+	Create(job *models.Job) error
+	GetByID(id string) (*models.Job, error)
+	Update(job *models.Job) error
+	List(limit int) ([]*models.Job, error)
+}
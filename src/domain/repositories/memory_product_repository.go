@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+	"sort"
 	"sync"
 	"time"
 
@@ -20,10 +22,13 @@ func NewMemoryProductRepository() *MemoryProductRepository {
 	}
 }
 
-func (r *MemoryProductRepository) Create(product *models.Product) error {
+func (r *MemoryProductRepository) Create(ctx context.Context, product *models.Product) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if r.skuTaken(product) {
+		return models.ErrDuplicateSKU
+	}
 	if product.CreatedAt.IsZero() {
 		product.CreatedAt = time.Now()
 	}
@@ -31,7 +36,32 @@ func (r *MemoryProductRepository) Create(product *models.Product) error {
 	return nil
 }
 
-func (r *MemoryProductRepository) GetByID(id string) (*models.Product, error) {
+func (r *MemoryProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, product := range r.products {
+		if sku != "" && product.SKU == sku {
+			return product, nil
+		}
+	}
+	return nil, models.ErrProductNotFound
+}
+
+// skuTaken reports whether another product has the product's SKU
+func (r *MemoryProductRepository) skuTaken(product *models.Product) bool {
+	if product.SKU == "" {
+		return false
+	}
+	for id, other := range r.products {
+		if id != product.ID && other.SKU == product.SKU {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryProductRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -41,18 +71,38 @@ func (r *MemoryProductRepository) GetByID(id string) (*models.Product, error) {
 	return nil, models.ErrProductNotFound
 }
 
-func (r *MemoryProductRepository) Update(product *models.Product) error {
+func (r *MemoryProductRepository) Update(ctx context.Context, product *models.Product) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, exists := r.products[product.ID]; !exists {
 		return models.ErrProductNotFound
 	}
+	if r.skuTaken(product) {
+		return models.ErrDuplicateSKU
+	}
 	r.products[product.ID] = product
 	return nil
 }
 
-func (r *MemoryProductRepository) Delete(id string) error {
+// AdjustStock applies stock adjustments while holding the repository lock
+func (r *MemoryProductRepository) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (*models.Product, *models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, exists := r.products[productID]
+	if !exists {
+		return nil, nil, models.ErrProductNotFound
+	}
+	updated, err := current.WithStockAdjustments(adjustments, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	r.products[productID] = updated
+	return current, updated.Clone(), nil
+}
+
+func (r *MemoryProductRepository) Delete(ctx context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -63,13 +113,16 @@ func (r *MemoryProductRepository) Delete(id string) error {
 	return nil
 }
 
-func (r *MemoryProductRepository) List(page, pageSize int) ([]*models.Product, int, error) {
+func (r *MemoryProductRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	filter = filter.Normalize()
 	allProducts := make([]*models.Product, 0, len(r.products))
 	for _, product := range r.products {
-		allProducts = append(allProducts, product)
+		if filter.Matches(product) {
+			allProducts = append(allProducts, product)
+		}
 	}
 
 	total := len(allProducts)
@@ -87,7 +140,26 @@ func (r *MemoryProductRepository) List(page, pageSize int) ([]*models.Product, i
 	return allProducts[start:end], total, nil
 }
 
-func (r *MemoryProductRepository) StoreEvent(event *models.Event) error {
+func (r *MemoryProductRepository) ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) ([]*models.Product, *models.ProductCursor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	filter = filter.Normalize()
+	products := make([]*models.Product, 0)
+	for _, product := range r.products {
+		if filter.Matches(product) && after.Follows(product) {
+			products = append(products, product)
+		}
+	}
+	models.SortForListing(products)
+
+	if limit <= 0 || len(products) <= limit {
+		return products, nil, nil
+	}
+	return products[:limit], models.CursorFor(products[limit-1]), nil
+}
+
+func (r *MemoryProductRepository) StoreEvent(ctx context.Context, event *models.Event) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -98,7 +170,7 @@ func (r *MemoryProductRepository) StoreEvent(event *models.Event) error {
 	return nil
 }
 
-func (r *MemoryProductRepository) GetEventsByProductID(productID string, fromVersion int64) ([]*models.Event, error) {
+func (r *MemoryProductRepository) GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -115,3 +187,67 @@ func (r *MemoryProductRepository) GetEventsByProductID(productID string, fromVer
 	}
 	return result, nil
 }
+
+func (r *MemoryProductRepository) GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*models.Event, 0)
+	for _, events := range r.events {
+		for _, e := range events {
+			if !e.Timestamp.After(until) {
+				result = append(result, e)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result, nil
+}
+
+// WithTx runs fn in a transaction. Its writes are applied at once under the
+// repository lock. A transaction whose products were changed by another write
+// since it first wrote them fails with models.ErrVersionConflict and applies
+// nothing.
+func (r *MemoryProductRepository) WithTx(ctx context.Context, fn func(tx ProductRepository) error) error {
+	return RunStagedTx(ctx, r, fn, r.commit)
+}
+
+// commit applies the staged writes and events under the repository lock
+func (r *MemoryProductRepository) commit(tx *StagedTx) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	owner := func(sku string) *models.Product {
+		for _, product := range r.products {
+			if product.SKU == sku {
+				return product
+			}
+		}
+		return nil
+	}
+	for id, product := range tx.Writes() {
+		if r.products[id] != tx.Base(id) {
+			return models.ErrVersionConflict
+		}
+		if product != nil && tx.SKUTaken(product, owner) {
+			return models.ErrDuplicateSKU
+		}
+	}
+
+	for id, product := range tx.Writes() {
+		if product == nil {
+			delete(r.products, id)
+			continue
+		}
+		if product.CreatedAt.IsZero() {
+			product.CreatedAt = time.Now()
+		}
+		r.products[id] = product
+	}
+	for _, event := range tx.Events() {
+		r.events[event.EntityID] = append(r.events[event.EntityID], event)
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// MerchandisingRepository stores the manual positions of market listings
+type MerchandisingRepository interface {
+	// Get returns the positions of a market listing, or ErrMerchandisingNotFound
+	Get(market, category string) (*models.Merchandising, error)
+	// Save creates or replaces the positions for the listing's market and category
+	Save(merchandising *models.Merchandising) error
+	// ListByMarket returns the positions of every listing in a market, ordered by category
+	ListByMarket(market string) ([]*models.Merchandising, error)
+}
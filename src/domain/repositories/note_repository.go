@@ -0,0 +1,15 @@
+package repositories
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// NoteRepository stores the internal notes on products
+type NoteRepository interface {
+	// Save creates or replaces a note
+	Save(note *models.ProductNote) error
+	// Get returns a note of a product, or ErrNoteNotFound
+	Get(productID, noteID string) (*models.ProductNote, error)
+	// Delete removes notes of a product; unknown IDs are ignored
+	Delete(productID string, noteIDs ...string) error
+	// ListByProduct returns every note of a product, oldest first
+	ListByProduct(productID string) ([]*models.ProductNote, error)
+}
@@ -0,0 +1,12 @@
+package repositories
+
+// OffsetRepository stores the last processed event sequence per consumer so
+// subscribers can resume where they left off after a restart
+type OffsetRepository interface {
+	// Get returns the committed sequence for a consumer, or 0 if it has none
+	Get(consumer string) (int64, error)
+	// Commit records that the consumer has processed every event up to sequence
+	Commit(consumer string, sequence int64) error
+	// List returns the committed sequence for every known consumer
+	List() (map[string]int64, error)
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// OutboxRepository stores committed events until the outbox relay has
+// published them
+type OutboxRepository interface {
+	// Add queues events as pending, in the given order; an event already in
+	// the outbox is not queued again
+	Add(events ...*models.Event) error
+	// Pending returns up to limit pending entries, all for 0, in the order
+	// they were added, including those waiting for a retry
+	Pending(limit int) ([]*models.OutboxEntry, error)
+	// MarkDelivered records that an event was published
+	MarkDelivered(eventID string, at time.Time) error
+	// MarkAttemptFailed records a failed attempt; the entry is retried at
+	// retryAt, or marked failed when retryAt is zero
+	MarkAttemptFailed(eventID string, reason string, retryAt time.Time) error
+	// Stats counts the entries by state
+	Stats() (models.OutboxStats, error)
+	// Prune removes delivered entries delivered before the given time and
+	// returns how many it removed
+	Prune(before time.Time) (int, error)
+}
+
+// OutboxStore is implemented by product repositories that keep an event
+// outbox in their own storage
+type OutboxStore interface {
+	// Outbox returns the outbox and from then on queues every event StoreEvent
+	// stores in it, in the same transaction, so an event is pending exactly
+	// when its write was committed. Events stored before are not queued.
+	Outbox() OutboxRepository
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// PriceListRepository stores price lists
+type PriceListRepository interface {
+	// Get returns a price list, or ErrPriceListNotFound
+	Get(id string) (*models.PriceList, error)
+	// Save creates or replaces a price list
+	Save(list *models.PriceList) error
+	// Delete removes a price list, or returns ErrPriceListNotFound
+	Delete(id string) error
+	// List returns every price list, ordered by ID
+	List() ([]*models.PriceList, error)
+}
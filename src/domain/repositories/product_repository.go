@@ -1,14 +1,50 @@
 package repositories
 
-import "github.com/jimmitjoo/ecom/src/domain/models"
+import (
+	"context"
+	"time"
 
-// ProductRepository defines the interface for product storage
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// ProductRepository defines the interface for product storage. Backends
+// doing I/O give up when the context is canceled or past its deadline.
 type ProductRepository interface {
-	Create(product *models.Product) error
-	GetByID(id string) (*models.Product, error)
-	Update(product *models.Product) error
-	Delete(id string) error
-	List(page, pageSize int) ([]*models.Product, int, error)
-	GetEventsByProductID(productID string, fromVersion int64) ([]*models.Event, error)
-	StoreEvent(event *models.Event) error
+	// Create and Update fail with models.ErrDuplicateSKU when another product
+	// has the SKU. Products without a SKU are not checked.
+	Create(ctx context.Context, product *models.Product) error
+	GetByID(ctx context.Context, id string) (*models.Product, error)
+	// GetBySKU returns the product with the SKU, or models.ErrProductNotFound
+	GetBySKU(ctx context.Context, sku string) (*models.Product, error)
+	Update(ctx context.Context, product *models.Product) error
+	Delete(ctx context.Context, id string) error
+
+	// List returns a page of the products that match the filter, newest
+	// first, and how many match in total. Implementations normalize the filter.
+	List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error)
+
+	// ListAfter returns up to limit matching products that follow the cursor
+	// in listing order, from the start for a nil cursor, and the cursor of the
+	// next page, which is nil after the last page
+	ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) ([]*models.Product, *models.ProductCursor, error)
+
+	GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error)
+	StoreEvent(ctx context.Context, event *models.Event) error
+	GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error)
+
+	// AdjustStock applies stock adjustments as a single compare-and-set on the
+	// stored product: either all adjustments succeed against the current
+	// quantities or nothing changes. Decrements that would leave a location
+	// without backorders below zero fail with models.ErrInsufficientStock.
+	// Persistent backends must do the check and the write atomically (a
+	// conditional UPDATE or a transaction with a row lock), never as a separate
+	// read followed by a write. It returns the product before and after.
+	AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (previous, updated *models.Product, err error)
+
+	// WithTx runs fn in a transaction. The writes fn makes through tx, and
+	// only those, are committed together when it returns nil and discarded
+	// when it returns an error, which WithTx returns. Reads through tx see
+	// its own writes. Calling WithTx on tx joins the running transaction, and
+	// tx must not be used after fn returns.
+	WithTx(ctx context.Context, fn func(tx ProductRepository) error) error
 }
@@ -1,8 +1,10 @@
 package repositories_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/jimmitjoo/ecom/src/domain/models"
 	"github.com/jimmitjoo/ecom/src/domain/repositories"
@@ -15,12 +17,12 @@ type MockProductRepository struct {
 	mock.Mock
 }
 
-func (m *MockProductRepository) Create(product *models.Product) error {
+func (m *MockProductRepository) Create(ctx context.Context, product *models.Product) error {
 	args := m.Called(product)
 	return args.Error(0)
 }
 
-func (m *MockProductRepository) GetByID(id string) (*models.Product, error) {
+func (m *MockProductRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
 	args := m.Called(id)
 	if p, ok := args.Get(0).(*models.Product); ok {
 		return p, args.Error(1)
@@ -28,31 +30,63 @@ func (m *MockProductRepository) GetByID(id string) (*models.Product, error) {
 	return nil, args.Error(1)
 }
 
-func (m *MockProductRepository) Update(product *models.Product) error {
+func (m *MockProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	args := m.Called(sku)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, product *models.Product) error {
 	args := m.Called(product)
 	return args.Error(0)
 }
 
-func (m *MockProductRepository) Delete(id string) error {
+func (m *MockProductRepository) Delete(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockProductRepository) List(page, pageSize int) ([]*models.Product, int, error) {
-	args := m.Called(page, pageSize)
+func (m *MockProductRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(filter, page, pageSize)
 	return args.Get(0).([]*models.Product), args.Int(1), args.Error(2)
 }
 
-func (m *MockProductRepository) GetEventsByProductID(productID string, fromVersion int64) ([]*models.Event, error) {
+func (m *MockProductRepository) GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
 	args := m.Called(productID, fromVersion)
 	return args.Get(0).([]*models.Event), args.Error(1)
 }
 
-func (m *MockProductRepository) StoreEvent(event *models.Event) error {
+func (m *MockProductRepository) ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) ([]*models.Product, *models.ProductCursor, error) {
+	args := m.Called(filter, after, limit)
+	next, _ := args.Get(1).(*models.ProductCursor)
+	return args.Get(0).([]*models.Product), next, args.Error(2)
+}
+
+func (m *MockProductRepository) StoreEvent(ctx context.Context, event *models.Event) error {
 	args := m.Called(event)
 	return args.Error(0)
 }
 
+func (m *MockProductRepository) GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error) {
+	args := m.Called(until)
+	return args.Get(0).([]*models.Event), args.Error(1)
+}
+
+func (m *MockProductRepository) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (*models.Product, *models.Product, error) {
+	args := m.Called(productID, adjustments)
+	previous, _ := args.Get(0).(*models.Product)
+	updated, _ := args.Get(1).(*models.Product)
+	return previous, updated, args.Error(2)
+}
+
+// WithTx runs fn on the mock itself, so the calls in a transaction are
+// expected like any other
+func (m *MockProductRepository) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	return fn(m)
+}
+
 // TestProductRepositoryInterface verifies that the interface is implemented correctly
 func TestProductRepositoryInterface(t *testing.T) {
 	var _ repositories.ProductRepository = &MockProductRepository{}
@@ -81,24 +115,24 @@ func TestMockRepositoryUsage(t *testing.T) {
 
 	// Test Create
 	repo.On("Create", product).Return(nil)
-	err := repo.Create(product)
+	err := repo.Create(context.Background(), product)
 	assert.NoError(t, err)
 
 	// Test GetByID
 	repo.On("GetByID", product.ID).Return(product, nil)
-	retrieved, err := repo.GetByID(product.ID)
+	retrieved, err := repo.GetByID(context.Background(), product.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, product.ID, retrieved.ID)
 
 	// Test GetByID with not found error
 	repo.On("GetByID", "nonexistent").Return(nil, models.ErrProductNotFound)
-	_, err = repo.GetByID("nonexistent")
+	_, err = repo.GetByID(context.Background(), "nonexistent")
 	assert.ErrorIs(t, err, models.ErrProductNotFound)
 
 	// Test List
 	products := []*models.Product{product}
-	repo.On("List", 1, 10).Return(products, 1, nil)
-	listed, _, err := repo.List(1, 10)
+	repo.On("List", models.ProductFilter{}, 1, 10).Return(products, 1, nil)
+	listed, _, err := repo.List(context.Background(), models.ProductFilter{}, 1, 10)
 	assert.NoError(t, err)
 	assert.Len(t, listed, 1)
 
@@ -115,18 +149,120 @@ func TestMockRepositoryUsage(t *testing.T) {
 	}
 
 	repo.On("StoreEvent", event).Return(nil)
-	err = repo.StoreEvent(event)
+	err = repo.StoreEvent(context.Background(), event)
 	assert.NoError(t, err)
 
 	events := []*models.Event{event}
 	repo.On("GetEventsByProductID", product.ID, int64(1)).Return(events, nil)
-	retrieved_events, err := repo.GetEventsByProductID(product.ID, 1)
+	retrieved_events, err := repo.GetEventsByProductID(context.Background(), product.ID, 1)
 	assert.NoError(t, err)
 	assert.Len(t, retrieved_events, 1)
 
 	repo.AssertExpectations(t)
 }
 
+// TestMemoryRepositorySKUUniqueness verifies that SKUs identify one product
+func TestMemoryRepositorySKUUniqueness(t *testing.T) {
+	repo := repositories.NewMemoryProductRepository()
+	assert.NoError(t, repo.Create(context.Background(), &models.Product{ID: "prod_1", SKU: "SKU-1"}))
+	assert.NoError(t, repo.Create(context.Background(), &models.Product{ID: "prod_2", SKU: "SKU-2"}))
+
+	assert.ErrorIs(t, repo.Create(context.Background(), &models.Product{ID: "prod_3", SKU: "SKU-1"}), models.ErrDuplicateSKU)
+	assert.ErrorIs(t, repo.Update(context.Background(), &models.Product{ID: "prod_2", SKU: "SKU-1"}), models.ErrDuplicateSKU)
+	assert.NoError(t, repo.Update(context.Background(), &models.Product{ID: "prod_1", SKU: "SKU-1", BaseTitle: "Renamed"}))
+
+	found, err := repo.GetBySKU(context.Background(), "SKU-2")
+	assert.NoError(t, err)
+	assert.Equal(t, "prod_2", found.ID)
+	_, err = repo.GetBySKU(context.Background(), "SKU-3")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+// TestMemoryRepositoryTransactions verifies that a transaction's writes are
+// applied together, and not at all when it fails
+func TestMemoryRepositoryTransactions(t *testing.T) {
+	ctx := context.Background()
+	repo := repositories.NewMemoryProductRepository()
+	assert.NoError(t, repo.Create(ctx, &models.Product{ID: "prod_1", SKU: "SKU-1"}))
+
+	failed := errors.New("failed")
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		assert.NoError(t, tx.StoreEvent(ctx, &models.Event{ID: "evt_1", EntityID: "prod_1", Version: 2}))
+		assert.NoError(t, tx.Delete(ctx, "prod_1"))
+		_, err := tx.GetByID(ctx, "prod_1")
+		assert.ErrorIs(t, err, models.ErrProductNotFound)
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	_, err = repo.GetByID(ctx, "prod_1")
+	assert.NoError(t, err)
+	events, err := repo.GetEventsByProductID(ctx, "prod_1", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	assert.NoError(t, repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, &models.Event{ID: "evt_1", EntityID: "prod_1", Version: 2}); err != nil {
+			return err
+		}
+		return tx.Delete(ctx, "prod_1")
+	}))
+	_, err = repo.GetByID(ctx, "prod_1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	events, err = repo.GetEventsByProductID(ctx, "prod_1", 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+// TestMemoryRepositoryTransactionConflicts verifies that a transaction
+// fails without applying its writes when another write changed its products
+func TestMemoryRepositoryTransactionConflicts(t *testing.T) {
+	ctx := context.Background()
+	repo := repositories.NewMemoryProductRepository()
+	assert.NoError(t, repo.Create(ctx, &models.Product{ID: "prod_1", SKU: "SKU-1"}))
+	assert.NoError(t, repo.Create(ctx, &models.Product{ID: "prod_2", SKU: "SKU-2"}))
+
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.Update(ctx, &models.Product{ID: "prod_1", SKU: "SKU-1", BaseTitle: "Staged"}); err != nil {
+			return err
+		}
+		if err := tx.StoreEvent(ctx, &models.Event{ID: "evt_1", EntityID: "prod_1", Version: 2}); err != nil {
+			return err
+		}
+		return repo.Update(ctx, &models.Product{ID: "prod_1", SKU: "SKU-1", BaseTitle: "Changed"})
+	})
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+	stored, err := repo.GetByID(ctx, "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Changed", stored.BaseTitle)
+	events, err := repo.GetEventsByProductID(ctx, "prod_1", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	// Writes to other products are kept
+	assert.NoError(t, repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.Delete(ctx, "prod_1"); err != nil {
+			return err
+		}
+		return repo.Update(ctx, &models.Product{ID: "prod_2", SKU: "SKU-2", BaseTitle: "Changed"})
+	}))
+	_, err = repo.GetByID(ctx, "prod_1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	stored, err = repo.GetByID(ctx, "prod_2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Changed", stored.BaseTitle)
+
+	// Another product takes the SKU before the transaction commits
+	err = repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.Create(ctx, &models.Product{ID: "prod_3", SKU: "SKU-3"}); err != nil {
+			return err
+		}
+		return repo.Create(ctx, &models.Product{ID: "prod_4", SKU: "SKU-3"})
+	})
+	assert.ErrorIs(t, err, models.ErrDuplicateSKU)
+	_, err = repo.GetByID(ctx, "prod_3")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
 // TestRepositoryErrorHandling verifies that repository errors are handled correctly
 func TestRepositoryErrorHandling(t *testing.T) {
 	repo := new(MockProductRepository)
@@ -142,7 +278,7 @@ func TestRepositoryErrorHandling(t *testing.T) {
 		{
 			name: "Product not found",
 			operation: func() error {
-				_, err := repo.GetByID("nonexistent")
+				_, err := repo.GetByID(context.Background(), "nonexistent")
 				return err
 			},
 			err: models.ErrProductNotFound,
@@ -152,7 +288,7 @@ func TestRepositoryErrorHandling(t *testing.T) {
 			operation: func() error {
 				product := &models.Product{ID: "test_1", Version: 1}
 				repo.On("Update", product).Return(models.ErrVersionConflict)
-				return repo.Update(product)
+				return repo.Update(context.Background(), product)
 			},
 			err: models.ErrVersionConflict,
 		},
@@ -210,12 +346,12 @@ func TestEventHandling(t *testing.T) {
 	repo.On("GetEventsByProductID", product.ID, int64(2)).Return(events[1:], nil)
 
 	// Get all events
-	allEvents, err := repo.GetEventsByProductID(product.ID, 1)
+	allEvents, err := repo.GetEventsByProductID(context.Background(), product.ID, 1)
 	assert.NoError(t, err)
 	assert.Len(t, allEvents, 2)
 
 	// Get events from version 2
-	laterEvents, err := repo.GetEventsByProductID(product.ID, 2)
+	laterEvents, err := repo.GetEventsByProductID(context.Background(), product.ID, 2)
 	assert.NoError(t, err)
 	assert.Len(t, laterEvents, 1)
 
@@ -0,0 +1,17 @@
+package repositories
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// RelationRepository stores the relations between products
+type RelationRepository interface {
+	// Save creates or replaces the relation of its type between two products
+	Save(relation *models.ProductRelation) error
+	// Delete removes a relation, or returns ErrRelationNotFound
+	Delete(productID, relatedID string, relationType models.RelationType) error
+	// ListByProduct returns the relations from a product, ordered by type,
+	// position and related ID
+	ListByProduct(productID string) ([]*models.ProductRelation, error)
+	// DeleteByProduct removes every relation from and to a product and
+	// returns how many were removed
+	DeleteByProduct(productID string) (int, error)
+}
@@ -0,0 +1,11 @@
+package repositories
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// RunbookRunRepository stores the audit trail of runbook actions
+type RunbookRunRepository interface {
+	// Save records a run
+	Save(run *models.RunbookRun) error
+	// List returns the latest runs, newest first. A limit of zero returns every kept run.
+	List(limit int) ([]*models.RunbookRun, error)
+}
@@ -0,0 +1,263 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// StagedTx is a transaction for the in-memory repositories. Writes are staged
+// and applied by the repository's commit; reads see the staged writes over
+// the repository's stored state. Unlike the repositories, a transaction is
+// not safe for concurrent use.
+type StagedTx struct {
+	repo   ProductRepository
+	base   map[string]*models.Product // Stored product of each written ID when first written, nil if absent
+	writes map[string]*models.Product // Staged products by ID, nil if deleted
+	events []*models.Event
+}
+
+// RunStagedTx runs fn in a transaction over repo and passes the transaction
+// to commit when fn succeeds. commit applies the writes at once, failing with
+// models.ErrVersionConflict when a written product is no longer its Base.
+func RunStagedTx(ctx context.Context, repo ProductRepository, fn func(tx ProductRepository) error, commit func(tx *StagedTx) error) error {
+	tx := &StagedTx{
+		repo:   repo,
+		base:   make(map[string]*models.Product),
+		writes: make(map[string]*models.Product),
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return commit(tx)
+}
+
+// Writes returns the staged products by ID, nil for a delete
+func (tx *StagedTx) Writes() map[string]*models.Product {
+	return tx.writes
+}
+
+// Base returns the product stored under the ID when the transaction first
+// wrote it, nil if there was none
+func (tx *StagedTx) Base(id string) *models.Product {
+	return tx.base[id]
+}
+
+// Events returns the staged events in the order they were stored
+func (tx *StagedTx) Events() []*models.Event {
+	return tx.events
+}
+
+// SKUs returns the SKUs the staged writes claim or release, empty ones included
+func (tx *StagedTx) SKUs() []string {
+	skus := make([]string, 0, 2*len(tx.writes))
+	for id, product := range tx.writes {
+		if product != nil {
+			skus = append(skus, product.SKU)
+		}
+		if base := tx.base[id]; base != nil {
+			skus = append(skus, base.SKU)
+		}
+	}
+	return skus
+}
+
+// SKUTaken reports whether another product has the product's SKU, staged or
+// stored. owner returns the stored product with a SKU, or nil.
+func (tx *StagedTx) SKUTaken(product *models.Product, owner func(sku string) *models.Product) bool {
+	if product.SKU == "" {
+		return false
+	}
+	for id, other := range tx.writes {
+		if id != product.ID && other != nil && other.SKU == product.SKU {
+			return true
+		}
+	}
+	other := owner(product.SKU)
+	if other == nil || other.ID == product.ID || other.SKU != product.SKU {
+		return false
+	}
+	_, written := tx.writes[other.ID]
+	return !written
+}
+
+// stage records a write of a product, nil for a delete
+func (tx *StagedTx) stage(ctx context.Context, id string, product *models.Product) {
+	if _, written := tx.writes[id]; !written {
+		stored, _ := tx.repo.GetByID(ctx, id)
+		tx.base[id] = stored
+	}
+	tx.writes[id] = product
+}
+
+// checkSKU fails with models.ErrDuplicateSKU when the product's SKU is taken
+func (tx *StagedTx) checkSKU(ctx context.Context, product *models.Product) error {
+	taken := tx.SKUTaken(product, func(sku string) *models.Product {
+		stored, _ := tx.repo.GetBySKU(ctx, sku)
+		return stored
+	})
+	if taken {
+		return models.ErrDuplicateSKU
+	}
+	return nil
+}
+
+func (tx *StagedTx) Create(ctx context.Context, product *models.Product) error {
+	if err := tx.checkSKU(ctx, product); err != nil {
+		return err
+	}
+	tx.stage(ctx, product.ID, product)
+	return nil
+}
+
+func (tx *StagedTx) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	if product, written := tx.writes[id]; written {
+		if product == nil {
+			return nil, models.ErrProductNotFound
+		}
+		return product, nil
+	}
+	return tx.repo.GetByID(ctx, id)
+}
+
+func (tx *StagedTx) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	for _, product := range tx.writes {
+		if product != nil && sku != "" && product.SKU == sku {
+			return product, nil
+		}
+	}
+	product, err := tx.repo.GetBySKU(ctx, sku)
+	if err != nil {
+		return nil, err
+	}
+	if _, written := tx.writes[product.ID]; written {
+		return nil, models.ErrProductNotFound
+	}
+	return product, nil
+}
+
+func (tx *StagedTx) Update(ctx context.Context, product *models.Product) error {
+	if _, err := tx.GetByID(ctx, product.ID); err != nil {
+		return err
+	}
+	if err := tx.checkSKU(ctx, product); err != nil {
+		return err
+	}
+	tx.stage(ctx, product.ID, product)
+	return nil
+}
+
+func (tx *StagedTx) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (*models.Product, *models.Product, error) {
+	current, err := tx.GetByID(ctx, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	updated, err := current.WithStockAdjustments(adjustments, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	tx.stage(ctx, productID, updated)
+	return current, updated.Clone(), nil
+}
+
+func (tx *StagedTx) Delete(ctx context.Context, id string) error {
+	if _, err := tx.GetByID(ctx, id); err != nil {
+		return err
+	}
+	tx.stage(ctx, id, nil)
+	return nil
+}
+
+func (tx *StagedTx) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	products, err := tx.matching(ctx, filter, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(products)
+	start := (page - 1) * pageSize
+	if start >= total {
+		return []*models.Product{}, total, nil
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return products[start:end], total, nil
+}
+
+func (tx *StagedTx) ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) ([]*models.Product, *models.ProductCursor, error) {
+	products, err := tx.matching(ctx, filter, after)
+	if err != nil {
+		return nil, nil, err
+	}
+	if limit <= 0 || len(products) <= limit {
+		return products, nil, nil
+	}
+	return products[:limit], models.CursorFor(products[limit-1]), nil
+}
+
+// matching merges the staged products into the stored ones that match
+func (tx *StagedTx) matching(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor) ([]*models.Product, error) {
+	filter = filter.Normalize()
+	stored, _, err := tx.repo.ListAfter(ctx, filter, after, 0)
+	if err != nil {
+		return nil, err
+	}
+	products := make([]*models.Product, 0, len(stored))
+	for _, product := range stored {
+		if _, written := tx.writes[product.ID]; !written {
+			products = append(products, product)
+		}
+	}
+	for _, product := range tx.writes {
+		if product != nil && filter.Matches(product) && after.Follows(product) {
+			products = append(products, product)
+		}
+	}
+	models.SortForListing(products)
+	return products, nil
+}
+
+func (tx *StagedTx) StoreEvent(ctx context.Context, event *models.Event) error {
+	tx.events = append(tx.events, event)
+	return nil
+}
+
+func (tx *StagedTx) GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
+	events, err := tx.repo.GetEventsByProductID(ctx, productID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range tx.events {
+		if event.EntityID == productID && event.Version >= fromVersion {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (tx *StagedTx) GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error) {
+	events, err := tx.repo.GetEventsUntil(ctx, until)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range tx.events {
+		if !event.Timestamp.After(until) {
+			events = append(events, event)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Timestamp.Equal(events[j].Timestamp) {
+			return events[i].Timestamp.Before(events[j].Timestamp)
+		}
+		return events[i].Sequence < events[j].Sequence
+	})
+	return events, nil
+}
+
+// WithTx joins the running transaction
+func (tx *StagedTx) WithTx(ctx context.Context, fn func(tx ProductRepository) error) error {
+	return fn(tx)
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// SyncStatusRepository stores the delivery status of products per downstream target
+type SyncStatusRepository interface {
+	// Get returns the status of a product for a target, or ErrSyncStatusNotFound
+	Get(productID, target string) (*models.SyncStatus, error)
+	// Save creates or replaces the status for the status' product and target
+	Save(status *models.SyncStatus) error
+	// ListByProduct returns the status of a product for every target it was synced to
+	ListByProduct(productID string) ([]*models.SyncStatus, error)
+	// ListByTarget returns the most recently updated statuses for a target, newest first
+	ListByTarget(target string, limit int) ([]*models.SyncStatus, error)
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import "github.com/jimmitjoo/ecom/src/domain/models"
+
+// WebhookRepository stores webhook endpoints and their delivery logs
+type WebhookRepository interface {
+	// Save creates or replaces an endpoint
+	Save(endpoint *models.WebhookEndpoint) error
+	// Get returns an endpoint, or ErrWebhookNotFound
+	Get(id string) (*models.WebhookEndpoint, error)
+	// List returns every endpoint, oldest first
+	List() ([]*models.WebhookEndpoint, error)
+	// Delete removes an endpoint and its delivery log, or returns ErrWebhookNotFound
+	Delete(id string) error
+	// AddDelivery appends an attempt to the delivery log of its endpoint.
+	// Implementations may drop the oldest attempts of an endpoint.
+	AddDelivery(delivery *models.WebhookDelivery) error
+	// ListDeliveries returns up to limit attempts of an endpoint, newest first, all for 0
+	ListDeliveries(endpointID string, limit int) ([]*models.WebhookDelivery, error)
+}
@@ -0,0 +1,62 @@
+//go:build grpc
+
+package main
+
+import (
+	"log"
+	"net"
+	"time"
+
+	grpclib "google.golang.org/grpc"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/infrastructure/events/tracking"
+	grpcAPI "github.com/jimmitjoo/ecom/src/infrastructure/grpc"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+)
+
+// grpcLinked reports whether this binary was built with -tags grpc
+const grpcLinked = true
+
+// grpcShutdownTimeout bounds the graceful stop; event streams only end when
+// their clients cancel, so they are cut off after it
+const grpcShutdownTimeout = 10 * time.Second
+
+// serveGRPC serves the product service over gRPC on addr and returns a
+// function that stops the server gracefully. Calls need the credentials and
+// roles the REST API needs, or none when no authenticators are given.
+func serveGRPC(addr string, service interfaces.ProductService, tracker *tracking.Tracker, authenticators []middleware.Authenticator) func() {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("Failed to listen for gRPC on %s: %v", addr, err)
+	}
+
+	var options []grpclib.ServerOption
+	if len(authenticators) > 0 {
+		auth := grpcAPI.NewAuthenticator(middleware.DefaultAccessPolicy(), authenticators...)
+		options = append(options, grpclib.UnaryInterceptor(auth.UnaryInterceptor()), grpclib.StreamInterceptor(auth.StreamInterceptor()))
+	} else {
+		log.Printf("gRPC API open to anonymous callers: AUTH_JWT_SECRET, AUTH_JWT_PUBLIC_KEY_FILE and API_KEYS not set")
+	}
+	server := grpclib.NewServer(options...)
+	grpcAPI.NewServer(service, grpcAPI.NewEventBroadcaster(tracker.Consumer("grpc"))).Register(server)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Printf("gRPC server stopped: %v", err)
+		}
+	}()
+
+	log.Printf("gRPC server starting on %s", addr)
+	return func() {
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(grpcShutdownTimeout):
+			server.Stop()
+		}
+	}
+}
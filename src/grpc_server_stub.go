@@ -0,0 +1,17 @@
+//go:build !grpc
+
+package main
+
+import (
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/infrastructure/events/tracking"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+)
+
+// grpcLinked reports whether this binary was built with -tags grpc
+const grpcLinked = false
+
+// serveGRPC does nothing: the gRPC server is only linked with -tags grpc
+func serveGRPC(addr string, service interfaces.ProductService, tracker *tracking.Tracker, authenticators []middleware.Authenticator) func() {
+	return func() {}
+}
@@ -0,0 +1,170 @@
+package apidocs
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	httpSwagger "github.com/swaggo/http-swagger"
+	"github.com/swaggo/swag"
+)
+
+// Instance names under which the documentation variants are registered with swag
+const (
+	PublicInstance = "public"
+	AdminInstance  = "admin"
+)
+
+// uiPrefix is the path prefix the shared Swagger UI asset handler is bound to
+const uiPrefix = "/swagger/"
+
+// spec serves a pre-rendered swagger document
+type spec struct {
+	doc string
+}
+
+func (s *spec) ReadDoc() string {
+	return s.doc
+}
+
+var (
+	registerOnce sync.Once
+	registerErr  error
+)
+
+// RegisterVariants derives the public and admin documentation from the generated spec
+// and registers them as separate swag instances. Repeated calls are no-ops.
+func RegisterVariants() error {
+	registerOnce.Do(func() {
+		full, err := swag.ReadDoc()
+		if err != nil {
+			registerErr = err
+			return
+		}
+
+		public, err := PublicSpec([]byte(full))
+		if err != nil {
+			registerErr = err
+			return
+		}
+
+		swag.Register(PublicInstance, &spec{doc: string(public)})
+		swag.Register(AdminInstance, &spec{doc: full})
+	})
+	return registerErr
+}
+
+// PublicSpec strips every operation that is not part of the public read API:
+// only GET and HEAD operations outside /admin are kept, and definitions that are
+// no longer referenced are removed
+func PublicSpec(full []byte) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(full, &doc); err != nil {
+		return nil, err
+	}
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	for path, item := range paths {
+		operations, ok := item.(map[string]interface{})
+		if !ok || strings.HasPrefix(path, "/admin") {
+			delete(paths, path)
+			continue
+		}
+
+		for method := range operations {
+			if method != "get" && method != "head" && method != "parameters" {
+				delete(operations, method)
+			}
+		}
+		if !hasOperation(operations) {
+			delete(paths, path)
+		}
+	}
+
+	if definitions, ok := doc["definitions"].(map[string]interface{}); ok {
+		doc["definitions"] = referencedDefinitions(paths, definitions)
+	}
+
+	return json.MarshalIndent(doc, "", "    ")
+}
+
+// hasOperation reports whether a path item still contains an operation
+func hasOperation(operations map[string]interface{}) bool {
+	for method := range operations {
+		if method != "parameters" {
+			return true
+		}
+	}
+	return false
+}
+
+// referencedDefinitions returns the definitions reachable from the given paths
+func referencedDefinitions(paths, definitions map[string]interface{}) map[string]interface{} {
+	kept := make(map[string]interface{})
+	queue := collectRefs(paths, nil)
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if _, done := kept[name]; done {
+			continue
+		}
+		definition, exists := definitions[name]
+		if !exists {
+			continue
+		}
+		kept[name] = definition
+		queue = collectRefs(definition, queue)
+	}
+	return kept
+}
+
+// collectRefs appends the names of all definitions referenced within node
+func collectRefs(node interface{}, refs []string) []string {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				refs = append(refs, strings.TrimPrefix(ref, "#/definitions/"))
+				continue
+			}
+			refs = collectRefs(value, refs)
+		}
+	case []interface{}:
+		for _, value := range v {
+			refs = collectRefs(value, refs)
+		}
+	}
+	return refs
+}
+
+// Handler serves the Swagger UI and spec of a documentation variant mounted at prefix.
+// http-swagger binds its embedded UI assets to the prefix of the first handler that
+// serves a request, so requests are rewritten onto uiPrefix to let several variants
+// share those assets.
+func Handler(prefix, instanceName string) http.Handler {
+	ui := httpSwagger.Handler(
+		httpSwagger.URL(prefix+"doc.json"),
+		httpSwagger.InstanceName(instanceName),
+		httpSwagger.DeepLinking(true),
+		httpSwagger.DocExpansion("none"),
+		httpSwagger.DomID("swagger-ui"),
+	)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == prefix {
+			http.Redirect(w, r, prefix+"index.html", http.StatusMovedPermanently)
+			return
+		}
+
+		if prefix != uiPrefix {
+			r = r.Clone(r.Context())
+			r.URL.Path = uiPrefix + strings.TrimPrefix(r.URL.Path, prefix)
+			r.URL.RawPath = ""
+			r.RequestURI = uiPrefix + strings.TrimPrefix(r.RequestURI, prefix)
+		}
+
+		ui.ServeHTTP(w, r)
+	})
+}
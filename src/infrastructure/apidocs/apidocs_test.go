@@ -0,0 +1,117 @@
+package apidocs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggo/swag"
+
+	_ "github.com/jimmitjoo/ecom/docs"
+)
+
+const testSpec = `{
+	"swagger": "2.0",
+	"paths": {
+		"/products": {
+			"get": {"responses": {"200": {"schema": {"$ref": "#/definitions/models.Product"}}}},
+			"post": {"responses": {"400": {"schema": {"$ref": "#/definitions/models.APIError"}}}}
+		},
+		"/products/batch": {
+			"delete": {"responses": {"200": {"description": "OK"}}}
+		},
+		"/admin/dashboard/jobs": {
+			"get": {"responses": {"200": {"schema": {"$ref": "#/definitions/models.Job"}}}}
+		}
+	},
+	"definitions": {
+		"models.Product": {"properties": {"prices": {"items": {"$ref": "#/definitions/models.Price"}}}},
+		"models.Price": {"type": "object"},
+		"models.APIError": {"type": "object"},
+		"models.Job": {"type": "object"}
+	}
+}`
+
+func TestPublicSpec(t *testing.T) {
+	public, err := PublicSpec([]byte(testSpec))
+	assert.NoError(t, err)
+
+	var doc struct {
+		Paths       map[string]map[string]interface{} `json:"paths"`
+		Definitions map[string]interface{}            `json:"definitions"`
+	}
+	assert.NoError(t, json.Unmarshal(public, &doc))
+
+	// Only read operations outside /admin remain
+	assert.Len(t, doc.Paths, 1)
+	assert.Contains(t, doc.Paths["/products"], "get")
+	assert.NotContains(t, doc.Paths["/products"], "post")
+	assert.NotContains(t, doc.Paths, "/products/batch")
+	assert.NotContains(t, doc.Paths, "/admin/dashboard/jobs")
+
+	// Definitions are pruned to those still referenced, including nested references
+	assert.Contains(t, doc.Definitions, "models.Product")
+	assert.Contains(t, doc.Definitions, "models.Price")
+	assert.NotContains(t, doc.Definitions, "models.APIError")
+	assert.NotContains(t, doc.Definitions, "models.Job")
+}
+
+func TestPublicSpecInvalidJSON(t *testing.T) {
+	_, err := PublicSpec([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestRegisterVariants(t *testing.T) {
+	assert.NoError(t, RegisterVariants())
+
+	public, err := swag.ReadDoc(PublicInstance)
+	assert.NoError(t, err)
+	assert.NotContains(t, public, `"post"`)
+	assert.NotContains(t, public, `"delete"`)
+
+	admin, err := swag.ReadDoc(AdminInstance)
+	assert.NoError(t, err)
+	assert.Contains(t, admin, `"/products/batch"`)
+}
+
+func TestHandlerVariantsShareAssets(t *testing.T) {
+	assert.NoError(t, RegisterVariants())
+	public := Handler("/swagger/", PublicInstance)
+	admin := Handler("/admin/swagger/", AdminInstance)
+
+	// Serve the public UI first so it binds the shared asset handler
+	for _, tc := range []struct {
+		handler http.Handler
+		url     string
+	}{
+		{public, "/swagger/swagger-ui.css"},
+		{admin, "/admin/swagger/swagger-ui.css"},
+		{admin, "/admin/swagger/index.html"},
+	} {
+		w := httptest.NewRecorder()
+		tc.handler.ServeHTTP(w, httptest.NewRequest("GET", tc.url, nil))
+		assert.Equal(t, http.StatusOK, w.Code, tc.url)
+	}
+
+	w := httptest.NewRecorder()
+	admin.ServeHTTP(w, httptest.NewRequest("GET", "/admin/swagger/doc.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"/products/batch"`)
+
+	w = httptest.NewRecorder()
+	public.ServeHTTP(w, httptest.NewRequest("GET", "/swagger/doc.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), `"/products/batch"`)
+}
+
+func TestHandlerRedirectsToIndex(t *testing.T) {
+	admin := Handler("/admin/swagger/", AdminInstance)
+
+	w := httptest.NewRecorder()
+	admin.ServeHTTP(w, httptest.NewRequest("GET", "/admin/swagger/", nil))
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/admin/swagger/index.html", w.Header().Get("Location"))
+}
@@ -0,0 +1,65 @@
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Set at build time with
+//
+//	go build -ldflags "-X github.com/jimmitjoo/ecom/src/infrastructure/buildinfo.Version=v1.4.0 -X github.com/jimmitjoo/ecom/src/infrastructure/buildinfo.Commit=$(git rev-parse HEAD)"
+//
+// Values that are not set fall back to what the Go toolchain embeds.
+var (
+	Version    string
+	Commit     string
+	CommitTime string
+)
+
+// Get returns the build of the running binary
+func Get() *models.BuildInfo {
+	info := &models.BuildInfo{
+		Version:   "dev",
+		GoVersion: runtime.Version(),
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		if version := build.Main.Version; version != "" && version != "(devel)" {
+			info.Version = version
+		}
+		for _, setting := range build.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Commit = setting.Value
+			case "vcs.time":
+				info.CommitTime = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+
+	if Version != "" {
+		info.Version = Version
+	}
+	if Commit != "" {
+		info.Commit = Commit
+	}
+	if CommitTime != "" {
+		info.CommitTime = CommitTime
+	}
+	return info
+}
+
+// GetVersion returns the build with the event schema versions it is compatible with
+func GetVersion() *models.VersionInfo {
+	return &models.VersionInfo{
+		BuildInfo: Get(),
+		EventSchema: models.EventSchemaCompatibility{
+			Current:   models.EventSchemaVersion,
+			Supported: models.SupportedEventSchemaVersions,
+		},
+	}
+}
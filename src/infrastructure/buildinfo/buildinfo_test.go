@@ -0,0 +1,34 @@
+package buildinfo
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestGet(t *testing.T) {
+	info := Get()
+	assert.NotEmpty(t, info.Version)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+}
+
+func TestGetWithLinkerFlags(t *testing.T) {
+	Version, Commit, CommitTime = "v1.4.0", "abc123", "2024-03-01T10:00:00Z"
+	defer func() { Version, Commit, CommitTime = "", "", "" }()
+
+	info := Get()
+	assert.Equal(t, "v1.4.0", info.Version)
+	assert.Equal(t, "abc123", info.Commit)
+	assert.Equal(t, "2024-03-01T10:00:00Z", info.CommitTime)
+	assert.False(t, info.Modified)
+}
+
+func TestGetVersion(t *testing.T) {
+	version := GetVersion()
+	assert.Equal(t, models.EventSchemaVersion, version.EventSchema.Current)
+	assert.Contains(t, version.EventSchema.Supported, models.EventSchemaVersion)
+	assert.NotEmpty(t, version.Version)
+}
@@ -0,0 +1,18 @@
+package cache
+
+import (
+	"encoding/json"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// EncodeProduct returns the JSON document served for a product. The handler
+// and the warmer both encode through it, so warmed entries are byte for byte
+// what a cache miss would have produced.
+func EncodeProduct(product *models.Product) ([]byte, error) {
+	data, err := json.Marshal(product)
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
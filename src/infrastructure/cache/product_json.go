@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultProductJSONCapacity is the number of products kept by default
+const DefaultProductJSONCapacity = 10000
+
+// productJSONEntry is the encoded form of one product version
+type productJSONEntry struct {
+	id      string
+	version int64
+	hash    string
+	data    []byte
+}
+
+// ProductJSONCache keeps the marshalled JSON of recently served products.
+// Entries are keyed by product ID and only match the exact version and hash
+// they were encoded from, so any change to the product invalidates them.
+// The least recently used entry is evicted when the cache is full.
+type ProductJSONCache struct {
+	capacity int
+	entries  map[string]*list.Element
+	lru      *list.List
+	mu       sync.Mutex
+}
+
+// NewProductJSONCache creates a cache holding up to capacity products
+func NewProductJSONCache(capacity int) *ProductJSONCache {
+	if capacity < 1 {
+		capacity = DefaultProductJSONCapacity
+	}
+	return &ProductJSONCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Get returns the cached JSON for the product version, if present
+func (c *ProductJSONCache) Get(id string, version int64, hash string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[id]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*productJSONEntry)
+	if entry.version != version || entry.hash != hash {
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return entry.data, true
+}
+
+// Put stores the JSON for a product version, replacing any older version
+func (c *ProductJSONCache) Put(id string, version int64, hash string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(id, version, hash, data)
+}
+
+// Refresh stores the JSON for a product version unless a newer version is
+// already cached, so a late event cannot replace a fresher entry
+func (c *ProductJSONCache) Refresh(id string, version int64, hash string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[id]; ok && element.Value.(*productJSONEntry).version > version {
+		return
+	}
+	c.put(id, version, hash, data)
+}
+
+// put stores an entry; the caller holds the lock
+func (c *ProductJSONCache) put(id string, version int64, hash string, data []byte) {
+	if element, ok := c.entries[id]; ok {
+		element.Value = &productJSONEntry{id: id, version: version, hash: hash, data: data}
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[id] = c.lru.PushFront(&productJSONEntry{id: id, version: version, hash: hash, data: data})
+	if c.lru.Len() > c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*productJSONEntry).id)
+	}
+}
+
+// Remove drops the cached JSON for a product
+func (c *ProductJSONCache) Remove(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[id]; ok {
+		c.lru.Remove(element)
+		delete(c.entries, id)
+	}
+}
+
+// Len returns the number of cached products
+func (c *ProductJSONCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
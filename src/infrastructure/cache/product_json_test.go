@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProductJSONCacheVersioning(t *testing.T) {
+	c := NewProductJSONCache(10)
+
+	c.Put("prod_1", 1, "hash1", []byte(`{"version":1}`))
+
+	data, ok := c.Get("prod_1", 1, "hash1")
+	assert.True(t, ok)
+	assert.Equal(t, `{"version":1}`, string(data))
+
+	// A new version or hash misses
+	_, ok = c.Get("prod_1", 2, "hash2")
+	assert.False(t, ok)
+	_, ok = c.Get("prod_1", 1, "other")
+	assert.False(t, ok)
+
+	// Storing the new version replaces the old one
+	c.Put("prod_1", 2, "hash2", []byte(`{"version":2}`))
+	assert.Equal(t, 1, c.Len())
+	_, ok = c.Get("prod_1", 1, "hash1")
+	assert.False(t, ok)
+
+	c.Remove("prod_1")
+	_, ok = c.Get("prod_1", 2, "hash2")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestProductJSONCacheEviction(t *testing.T) {
+	c := NewProductJSONCache(2)
+
+	c.Put("a", 1, "", []byte("a"))
+	c.Put("b", 1, "", []byte("b"))
+	c.Get("a", 1, "") // a is now the most recently used
+	c.Put("c", 1, "", []byte("c"))
+
+	assert.Equal(t, 2, c.Len())
+	_, ok := c.Get("b", 1, "")
+	assert.False(t, ok)
+	_, ok = c.Get("a", 1, "")
+	assert.True(t, ok)
+	_, ok = c.Get("c", 1, "")
+	assert.True(t, ok)
+
+	assert.Equal(t, DefaultProductJSONCapacity, NewProductJSONCache(0).capacity)
+}
+
+func TestProductJSONCacheRefresh(t *testing.T) {
+	c := NewProductJSONCache(10)
+	c.Refresh("prod_1", 2, "hash2", []byte("v2"))
+	c.Refresh("prod_1", 1, "hash1", []byte("v1"))
+
+	data, ok := c.Get("prod_1", 2, "hash2")
+	assert.True(t, ok)
+	assert.Equal(t, "v2", string(data))
+
+	c.Refresh("prod_1", 3, "hash3", []byte("v3"))
+	_, ok = c.Get("prod_1", 3, "hash3")
+	assert.True(t, ok)
+}
@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+// DefaultWarmTopN is the number of most requested products kept warm by default
+const DefaultWarmTopN = 100
+
+// minWarmRequests is the decayed request count below which a product is forgotten
+const minWarmRequests = 0.5
+
+// Warmer keeps the JSON of the most requested products in the cache across
+// updates. Without it the first read after an update pays for encoding the
+// product again, which for hot products during a campaign is every read after
+// every price or stock change. The warmer counts requests per product, decays
+// the counts periodically so the ranking follows current traffic, and re-encodes
+// the top products as soon as their update events arrive.
+type Warmer struct {
+	cache *ProductJSONCache
+	topN  int
+
+	mu       sync.Mutex
+	requests map[string]float64
+	hot      map[string]bool
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWarmer creates a warmer keeping the topN most requested products warm in the cache
+func NewWarmer(cache *ProductJSONCache, topN int) *Warmer {
+	if topN < 1 {
+		topN = DefaultWarmTopN
+	}
+	return &Warmer{
+		cache:    cache,
+		topN:     topN,
+		requests: make(map[string]float64),
+		hot:      make(map[string]bool),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// RecordRequest counts a read of a product
+func (w *Warmer) RecordRequest(id string) {
+	w.mu.Lock()
+	w.requests[id]++
+	w.mu.Unlock()
+}
+
+// Hot returns the products currently kept warm, most requested first
+func (w *Warmer) Hot() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	hot := make([]string, 0, len(w.hot))
+	for id := range w.hot {
+		hot = append(hot, id)
+	}
+	w.sortByRequests(hot)
+	return hot
+}
+
+// Subscribe warms the cache from the product events of a publisher
+func (w *Warmer) Subscribe(publisher events.EventPublisher) {
+	for _, eventType := range []models.EventType{
+		models.EventProductCreated,
+		models.EventProductUpdated,
+		models.EventProductDeleted,
+		models.EventProductRestored,
+	} {
+		publisher.Subscribe(eventType, w.HandleEvent)
+	}
+}
+
+// HandleEvent re-encodes a hot product from its event. Deleted products are
+// dropped from the cache whether they are hot or not.
+func (w *Warmer) HandleEvent(event *models.Event) {
+	productEvent, ok := event.Data.(*models.ProductEvent)
+	if !ok {
+		return
+	}
+	if event.Type == models.EventProductDeleted {
+		w.cache.Remove(productEvent.ProductID)
+		return
+	}
+	if productEvent.Product == nil {
+		return
+	}
+
+	w.mu.Lock()
+	hot := w.hot[productEvent.Product.ID]
+	w.mu.Unlock()
+	if !hot {
+		return
+	}
+
+	product := productEvent.Product
+	data, err := EncodeProduct(product)
+	if err != nil {
+		return
+	}
+	w.cache.Refresh(product.ID, product.Version, product.LastHash, data)
+	metrics.ProductJSONCacheWarms.Inc()
+}
+
+// Start re-ranks the products and decays their request counts every interval
+func (w *Warmer) Start(interval time.Duration) {
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				w.rank()
+			}
+		}
+	}()
+}
+
+// Stop ends the periodic ranking
+func (w *Warmer) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+// rank picks the hot products from the requests since the last ranking and
+// halves every count, so a product that stops being requested cools down
+// within a few intervals
+func (w *Warmer) rank() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ids := make([]string, 0, len(w.requests))
+	for id := range w.requests {
+		ids = append(ids, id)
+	}
+	w.sortByRequests(ids)
+	if len(ids) > w.topN {
+		ids = ids[:w.topN]
+	}
+	w.hot = make(map[string]bool, len(ids))
+	for _, id := range ids {
+		w.hot[id] = true
+	}
+
+	for id, count := range w.requests {
+		if count /= 2; count < minWarmRequests {
+			delete(w.requests, id)
+		} else {
+			w.requests[id] = count
+		}
+	}
+}
+
+// sortByRequests orders products by request count, ties by ID; the caller holds the lock
+func (w *Warmer) sortByRequests(ids []string) {
+	sort.Slice(ids, func(i, j int) bool {
+		if w.requests[ids[i]] != w.requests[ids[j]] {
+			return w.requests[ids[i]] > w.requests[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+}
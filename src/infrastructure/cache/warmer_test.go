@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+func productEvent(eventType models.EventType, id string, version int64) *models.Event {
+	product := &models.Product{ID: id, BaseTitle: fmt.Sprintf("%s v%d", id, version), Version: version}
+	product.LastHash = product.CalculateHash()
+	return &models.Event{
+		Type:     eventType,
+		EntityID: id,
+		Version:  version,
+		Data:     &models.ProductEvent{ProductID: id, Product: product, Version: version},
+	}
+}
+
+func TestWarmerRanksMostRequested(t *testing.T) {
+	warmer := NewWarmer(NewProductJSONCache(10), 2)
+	for i := 0; i < 5; i++ {
+		warmer.RecordRequest("a")
+	}
+	for i := 0; i < 3; i++ {
+		warmer.RecordRequest("b")
+	}
+	warmer.RecordRequest("c")
+
+	assert.Empty(t, warmer.Hot(), "nothing is hot before the first ranking")
+	warmer.rank()
+	assert.Equal(t, []string{"a", "b"}, warmer.Hot())
+
+	// Counts decay, so products that are no longer requested cool down
+	for i := 0; i < 10; i++ {
+		warmer.RecordRequest("c")
+	}
+	warmer.rank()
+	assert.Equal(t, []string{"c", "a"}, warmer.Hot())
+
+	for i := 0; i < 5; i++ {
+		warmer.rank()
+	}
+	assert.Empty(t, warmer.Hot())
+	assert.Empty(t, warmer.requests)
+
+	assert.Equal(t, DefaultWarmTopN, NewWarmer(NewProductJSONCache(1), 0).topN)
+}
+
+func TestWarmerRefreshesHotProducts(t *testing.T) {
+	cache := NewProductJSONCache(10)
+	warmer := NewWarmer(cache, 1)
+	warmer.RecordRequest("hot")
+	warmer.rank()
+	warms := testutil.ToFloat64(metrics.ProductJSONCacheWarms)
+
+	updated := productEvent(models.EventProductUpdated, "hot", 2)
+	warmer.HandleEvent(updated)
+	product := updated.Data.(*models.ProductEvent).Product
+	data, hit := cache.Get("hot", 2, product.LastHash)
+	assert.True(t, hit)
+	expected, _ := EncodeProduct(product)
+	assert.Equal(t, expected, data)
+	assert.Equal(t, warms+1, testutil.ToFloat64(metrics.ProductJSONCacheWarms))
+
+	// A late event for an older version does not replace the fresh entry
+	warmer.HandleEvent(productEvent(models.EventProductUpdated, "hot", 1))
+	_, hit = cache.Get("hot", 2, product.LastHash)
+	assert.True(t, hit)
+
+	// Cold products are left for the next read to encode
+	warmer.HandleEvent(productEvent(models.EventProductUpdated, "cold", 2))
+	assert.Equal(t, 1, cache.Len())
+
+	warmer.HandleEvent(productEvent(models.EventProductDeleted, "hot", 3))
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestWarmerStartStop(t *testing.T) {
+	warmer := NewWarmer(NewProductJSONCache(10), 1)
+	warmer.RecordRequest("a")
+	warmer.Start(5 * time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		hot := warmer.Hot()
+		return len(hot) == 1 && hot[0] == "a"
+	}, time.Second, 5*time.Millisecond)
+	warmer.Stop()
+	warmer.Stop()
+}
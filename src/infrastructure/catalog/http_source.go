@@ -0,0 +1,91 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// requestTimeout bounds a catalog export, which may hold the whole catalog
+const requestTimeout = 5 * time.Minute
+
+// SourceConfig configures another environment to clone catalogs from
+type SourceConfig struct {
+	BaseURL string `json:"base_url"` // e.g. https://staging.example.com
+	Token   string `json:"token"`    // Sent as a bearer token to the admin API
+}
+
+// HTTPSource fetches catalog exports from another environment's admin API
+type HTTPSource struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+// NewHTTPSource creates a source for the environment at the configured URL
+func NewHTTPSource(config SourceConfig) *HTTPSource {
+	return &HTTPSource{
+		baseURL: strings.TrimRight(config.BaseURL, "/"),
+		token:   config.Token,
+		http:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// FetchCatalog requests GET /admin/catalog/export with the filter as query parameters
+func (s *HTTPSource) FetchCatalog(filter models.CatalogFilter, anonymizePrices bool) (*models.CatalogExport, error) {
+	query := url.Values{}
+	if len(filter.IDs) > 0 {
+		query.Set("ids", strings.Join(filter.IDs, ","))
+	}
+	if filter.SKUPrefix != "" {
+		query.Set("sku_prefix", filter.SKUPrefix)
+	}
+	if filter.Market != "" {
+		query.Set("market", filter.Market)
+	}
+	if filter.Tag != "" {
+		query.Set("tag", filter.Tag)
+	}
+	if anonymizePrices {
+		query.Set("anonymize_prices", "true")
+	}
+
+	endpoint := s.baseURL + "/admin/catalog/export"
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("export returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var export models.CatalogExport
+	if err := json.NewDecoder(resp.Body).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid catalog export: %v", err)
+	}
+	if anonymizePrices && !export.PricesAnonymized {
+		return nil, fmt.Errorf("source did not anonymize prices")
+	}
+	return &export, nil
+}
@@ -0,0 +1,60 @@
+package catalog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestHTTPSourceFetchCatalog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/admin/catalog/export", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "SHIRT", r.URL.Query().Get("sku_prefix"))
+		assert.Equal(t, "prod_1,prod_2", r.URL.Query().Get("ids"))
+		assert.Equal(t, "true", r.URL.Query().Get("anonymize_prices"))
+
+		json.NewEncoder(w).Encode(&models.CatalogExport{
+			PricesAnonymized: true,
+			Products:         []*models.Product{{ID: "prod_1", SKU: "SHIRT-1"}},
+		})
+	}))
+	defer server.Close()
+
+	source := NewHTTPSource(SourceConfig{BaseURL: server.URL + "/", Token: "secret"})
+	export, err := source.FetchCatalog(models.CatalogFilter{IDs: []string{"prod_1", "prod_2"}, SKUPrefix: "SHIRT"}, true)
+	assert.NoError(t, err)
+	assert.Len(t, export.Products, 1)
+}
+
+func TestHTTPSourceFetchCatalogErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"error status", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		}},
+		{"invalid body", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html>"))
+		}},
+		{"prices not anonymized", func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(&models.CatalogExport{})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			_, err := NewHTTPSource(SourceConfig{BaseURL: server.URL}).FetchCatalog(models.CatalogFilter{}, true)
+			assert.Error(t, err)
+		})
+	}
+}
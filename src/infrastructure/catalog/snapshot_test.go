@@ -0,0 +1,104 @@
+package catalog
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	memoryRepo "github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+func testSnapshot() *models.CatalogExport {
+	return &models.CatalogExport{Products: []*models.Product{
+		{ID: "prod_1", SKU: "SHIRT-1", Version: 7, LastHash: "abc"},
+		{ID: "prod_2", SKU: "SHIRT-2"},
+	}}
+}
+
+func TestLoadSnapshotFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "catalog.json")
+	data, _ := json.Marshal(testSnapshot())
+	assert.NoError(t, os.WriteFile(path, data, 0o644))
+
+	snapshot, err := LoadSnapshot(path)
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Products, 2)
+	assert.Equal(t, int64(7), snapshot.Products[0].Version)
+}
+
+func TestLoadSnapshotGzipFromURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/snapshots/catalog.json.gz", r.URL.Path)
+		gz := gzip.NewWriter(w)
+		json.NewEncoder(gz).Encode(testSnapshot())
+		gz.Close()
+	}))
+	defer server.Close()
+
+	snapshot, err := LoadSnapshot(server.URL + "/snapshots/catalog.json.gz?X-Amz-Signature=abc")
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Products, 2)
+}
+
+func TestLoadSnapshotErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	invalid := filepath.Join(t.TempDir(), "catalog.json")
+	assert.NoError(t, os.WriteFile(invalid, []byte("<html>"), 0o644))
+
+	for _, location := range []string{server.URL + "/catalog.json", filepath.Join(t.TempDir(), "missing.json"), invalid} {
+		_, err := LoadSnapshot(location)
+		assert.Error(t, err, location)
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	repo := memoryRepo.NewProductRepository()
+
+	count, err := RestoreSnapshot(context.Background(), repo, testSnapshot())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	kept, _ := repo.GetByID(context.Background(), "prod_1")
+	assert.Equal(t, int64(7), kept.Version)
+	assert.Equal(t, "abc", kept.LastHash)
+
+	started, _ := repo.GetByID(context.Background(), "prod_2")
+	assert.Equal(t, int64(1), started.Version)
+	assert.Equal(t, started.CalculateHash(), started.LastHash)
+
+	events, _ := repo.GetEventsByProductID(context.Background(), "prod_1", 0)
+	assert.Empty(t, events)
+}
+
+func TestRestoreSnapshotRejectsInvalidProducts(t *testing.T) {
+	tests := []struct {
+		name     string
+		products []*models.Product
+	}{
+		{"missing ID", []*models.Product{{SKU: "SHIRT-1"}}},
+		{"duplicate ID", []*models.Product{{ID: "prod_1"}, {ID: "prod_1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memoryRepo.NewProductRepository()
+			_, err := RestoreSnapshot(context.Background(), repo, &models.CatalogExport{Products: tt.products})
+			assert.Error(t, err)
+			_, total, _ := repo.List(context.Background(), models.ProductFilter{}, 1, 10)
+			assert.Zero(t, total)
+		})
+	}
+}
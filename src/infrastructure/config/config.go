@@ -0,0 +1,375 @@
+// Package config loads the service's core settings from, in increasing order
+// of precedence, defaults, a YAML file, environment variables and command-line
+// flags. Feature-specific settings, e.g. OIDC or Kafka, are still read from
+// the environment where they are wired.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds the core settings
+type Config struct {
+	Server     ServerConfig     `yaml:"server"`
+	Repository RepositoryConfig `yaml:"repository"`
+	Locks      LocksConfig      `yaml:"locks"`
+	Events     EventsConfig     `yaml:"events"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Log        LogConfig        `yaml:"log"`
+}
+
+// ServerConfig configures the listeners
+type ServerConfig struct {
+	Addr        string   `yaml:"addr"`         // HTTP address, env SERVER_ADDR, flag -addr
+	GRPCAddr    string   `yaml:"grpc_addr"`    // gRPC address, env GRPC_ADDR, flag -grpc-addr
+	CORSOrigins []string `yaml:"cors_origins"` // env CORS_ALLOWED_ORIGINS, flag -cors-origins, comma separated
+	// ShutdownTimeout bounds draining requests and flushing events on
+	// SIGINT/SIGTERM, env SHUTDOWN_TIMEOUT, flag -shutdown-timeout
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// MaxBodyBytes is the largest request body read, env MAX_BODY_BYTES,
+	// flag -max-body-bytes. Multipart uploads have their own limits.
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+	// MaxBatchSize is the most items in one batch request, env
+	// MAX_BATCH_SIZE, flag -max-batch-size
+	MaxBatchSize int `yaml:"max_batch_size"`
+}
+
+// RepositoryConfig selects the product repository
+type RepositoryConfig struct {
+	Backend string `yaml:"backend"` // memory, postgres or bolt, env REPOSITORY, flag -repository
+}
+
+// LocksConfig selects the lock manager
+type LocksConfig struct {
+	Backend string `yaml:"backend"` // memory, env LOCK_BACKEND, flag -locks
+}
+
+// EventsConfig selects the event publisher
+type EventsConfig struct {
+	Publisher string `yaml:"publisher"` // memory or kafka, env EVENT_PUBLISHER, flag -event-publisher
+}
+
+// RateLimitConfig sizes the per-client token bucket
+type RateLimitConfig struct {
+	Rate  float64 `yaml:"rate"`  // Tokens per second, env RATE_LIMIT_RATE, flag -rate-limit
+	Burst float64 `yaml:"burst"` // Bucket size, env RATE_LIMIT_BURST, flag -rate-limit-burst
+	// Routes are stricter limits on single routes, env RATE_LIMIT_ROUTES as
+	// comma separated "[METHOD ]/route=per_second[:burst]"
+	Routes []RoutePolicyConfig `yaml:"routes"`
+}
+
+// RoutePolicyConfig limits one route template, for every method when Method
+// is empty. Burst defaults to Rate.
+type RoutePolicyConfig struct {
+	Method string  `yaml:"method"`
+	Route  string  `yaml:"route"`
+	Rate   float64 `yaml:"rate"`
+	Burst  float64 `yaml:"burst"`
+}
+
+// LogConfig configures logging
+type LogConfig struct {
+	// debug, info, warn or error, env LOG_LEVEL, flag -log-level. Unset, it is
+	// debug with GO_ENV=development and info otherwise.
+	Level string `yaml:"level"`
+	// Access configures the access log
+	Access AccessLogConfig `yaml:"access"`
+}
+
+// AccessLogConfig configures the access log written for every request
+type AccessLogConfig struct {
+	Enabled bool `yaml:"enabled"` // env ACCESS_LOG, flag -access-log
+	// SampleRate is the share of requests to SampledRoutes that are logged,
+	// env ACCESS_LOG_SAMPLE_RATE, flag -access-log-sample-rate
+	SampleRate float64 `yaml:"sample_rate"`
+	// SampledRoutes are route templates such as /products, env
+	// ACCESS_LOG_SAMPLED_ROUTES, flag -access-log-sampled-routes, comma separated
+	SampledRoutes []string `yaml:"sampled_routes"`
+}
+
+// Backends each setting accepts
+var (
+	repositoryBackends = []string{"memory", "postgres", "bolt"}
+	lockBackends       = []string{"memory"}
+	eventPublishers    = []string{"memory", "kafka"}
+)
+
+// Default returns the settings used when nothing is configured
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:            ":8080",
+			GRPCAddr:        ":9090",
+			CORSOrigins:     []string{"*"},
+			ShutdownTimeout: 15 * time.Second,
+			MaxBodyBytes:    10 << 20,
+			MaxBatchSize:    1000,
+		},
+		Repository: RepositoryConfig{Backend: "memory"},
+		Locks:      LocksConfig{Backend: "memory"},
+		Events:     EventsConfig{Publisher: "memory"},
+		RateLimit:  RateLimitConfig{Rate: 10, Burst: 10},
+		Log:        LogConfig{Access: AccessLogConfig{Enabled: true, SampleRate: 1}},
+	}
+}
+
+// Load reads the settings. The YAML file is named by the -config flag or
+// CONFIG_FILE; args are the command-line arguments without the program name.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	config := Default()
+
+	flags := flag.NewFlagSet("ecom", flag.ContinueOnError)
+	file := flags.String("config", getenv("CONFIG_FILE"), "YAML configuration file")
+	addr := flags.String("addr", "", "HTTP listen address")
+	grpcAddr := flags.String("grpc-addr", "", "gRPC listen address")
+	corsOrigins := flags.String("cors-origins", "", "Comma separated allowed CORS origins")
+	shutdownTimeout := flags.Duration("shutdown-timeout", 0, "Time to drain requests and flush events on shutdown")
+	maxBodyBytes := flags.Int64("max-body-bytes", 0, "Largest request body in bytes")
+	maxBatchSize := flags.Int("max-batch-size", 0, "Most items in one batch request")
+	repository := flags.String("repository", "", "Product repository backend: memory, postgres or bolt")
+	lockBackend := flags.String("locks", "", "Lock manager backend: memory")
+	eventPublisher := flags.String("event-publisher", "", "Event publisher: memory or kafka")
+	rate := flags.Float64("rate-limit", 0, "Requests per second per client")
+	burst := flags.Float64("rate-limit-burst", 0, "Request burst per client")
+	level := flags.String("log-level", "", "Log level: debug, info, warn or error")
+	accessLog := flags.Bool("access-log", false, "Log every request")
+	accessSampleRate := flags.Float64("access-log-sample-rate", 0, "Share of requests to sampled routes that are logged")
+	accessSampledRoutes := flags.String("access-log-sampled-routes", "", "Comma separated route templates whose requests are sampled")
+	if err := flags.Parse(args); err != nil {
+		return config, err
+	}
+
+	if *file != "" {
+		if err := loadFile(&config, *file); err != nil {
+			return config, err
+		}
+	}
+	if err := applyEnv(&config, getenv); err != nil {
+		return config, err
+	}
+
+	// Only flags given on the command line override the file and environment
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "addr":
+			config.Server.Addr = *addr
+		case "grpc-addr":
+			config.Server.GRPCAddr = *grpcAddr
+		case "cors-origins":
+			config.Server.CORSOrigins = splitList(*corsOrigins)
+		case "shutdown-timeout":
+			config.Server.ShutdownTimeout = *shutdownTimeout
+		case "max-body-bytes":
+			config.Server.MaxBodyBytes = *maxBodyBytes
+		case "max-batch-size":
+			config.Server.MaxBatchSize = *maxBatchSize
+		case "repository":
+			config.Repository.Backend = *repository
+		case "locks":
+			config.Locks.Backend = *lockBackend
+		case "event-publisher":
+			config.Events.Publisher = *eventPublisher
+		case "rate-limit":
+			config.RateLimit.Rate = *rate
+		case "rate-limit-burst":
+			config.RateLimit.Burst = *burst
+		case "log-level":
+			config.Log.Level = *level
+		case "access-log":
+			config.Log.Access.Enabled = *accessLog
+		case "access-log-sample-rate":
+			config.Log.Access.SampleRate = *accessSampleRate
+		case "access-log-sampled-routes":
+			config.Log.Access.SampledRoutes = splitList(*accessSampledRoutes)
+		}
+	})
+
+	return config, config.Validate()
+}
+
+// loadFile overlays the settings in a YAML file; fields it leaves out keep their value
+func loadFile(config *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file: %v", err)
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return fmt.Errorf("invalid configuration file %s: %v", path, err)
+	}
+	return nil
+}
+
+// applyEnv overlays the settings set in the environment
+func applyEnv(config *Config, getenv func(string) string) error {
+	texts := map[string]*string{
+		"SERVER_ADDR":     &config.Server.Addr,
+		"GRPC_ADDR":       &config.Server.GRPCAddr,
+		"REPOSITORY":      &config.Repository.Backend,
+		"LOCK_BACKEND":    &config.Locks.Backend,
+		"EVENT_PUBLISHER": &config.Events.Publisher,
+		"LOG_LEVEL":       &config.Log.Level,
+	}
+	for key, target := range texts {
+		if value := getenv(key); value != "" {
+			*target = value
+		}
+	}
+	if origins := getenv("CORS_ALLOWED_ORIGINS"); origins != "" {
+		config.Server.CORSOrigins = splitList(origins)
+	}
+	if routes := getenv("ACCESS_LOG_SAMPLED_ROUTES"); routes != "" {
+		config.Log.Access.SampledRoutes = splitList(routes)
+	}
+	if value := getenv("ACCESS_LOG"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid ACCESS_LOG %q", value)
+		}
+		config.Log.Access.Enabled = enabled
+	}
+
+	if value := getenv("RATE_LIMIT_ROUTES"); value != "" {
+		routes, err := parseRoutePolicies(value)
+		if err != nil {
+			return err
+		}
+		config.RateLimit.Routes = routes
+	}
+
+	if value := getenv("SHUTDOWN_TIMEOUT"); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid SHUTDOWN_TIMEOUT %q", value)
+		}
+		config.Server.ShutdownTimeout = timeout
+	}
+	if value := getenv("MAX_BODY_BYTES"); value != "" {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid MAX_BODY_BYTES %q", value)
+		}
+		config.Server.MaxBodyBytes = limit
+	}
+	if value := getenv("MAX_BATCH_SIZE"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid MAX_BATCH_SIZE %q", value)
+		}
+		config.Server.MaxBatchSize = size
+	}
+
+	numbers := map[string]*float64{
+		"RATE_LIMIT_RATE":        &config.RateLimit.Rate,
+		"RATE_LIMIT_BURST":       &config.RateLimit.Burst,
+		"ACCESS_LOG_SAMPLE_RATE": &config.Log.Access.SampleRate,
+	}
+	for key, target := range numbers {
+		if value := getenv(key); value != "" {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("invalid %s %q", key, value)
+			}
+			*target = parsed
+		}
+	}
+	return nil
+}
+
+// Validate checks that backends are known and limits are positive
+func (c Config) Validate() error {
+	if c.Server.Addr == "" {
+		return fmt.Errorf("server address is required")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		return fmt.Errorf("shutdown timeout must be positive")
+	}
+	if c.Server.MaxBodyBytes <= 0 || c.Server.MaxBatchSize <= 0 {
+		return fmt.Errorf("max body bytes and max batch size must be positive, got %d and %d", c.Server.MaxBodyBytes, c.Server.MaxBatchSize)
+	}
+	if err := oneOf("repository backend", c.Repository.Backend, repositoryBackends); err != nil {
+		return err
+	}
+	if err := oneOf("lock backend", c.Locks.Backend, lockBackends); err != nil {
+		return err
+	}
+	if err := oneOf("event publisher", c.Events.Publisher, eventPublishers); err != nil {
+		return err
+	}
+	if c.RateLimit.Rate <= 0 || c.RateLimit.Burst < 1 {
+		return fmt.Errorf("rate limit needs a positive rate and a burst of at least 1, got %v and %v", c.RateLimit.Rate, c.RateLimit.Burst)
+	}
+	for _, route := range c.RateLimit.Routes {
+		if !strings.HasPrefix(route.Route, "/") {
+			return fmt.Errorf("rate limit route %q must start with /", route.Route)
+		}
+		if route.Rate <= 0 || (route.Burst != 0 && route.Burst < 1) {
+			return fmt.Errorf("rate limit for route %s needs a positive rate and a burst of at least 1, got %v and %v", route.Route, route.Rate, route.Burst)
+		}
+	}
+	if _, err := zapcore.ParseLevel(c.Log.Level); c.Log.Level != "" && err != nil {
+		return fmt.Errorf("invalid log level %q", c.Log.Level)
+	}
+	if c.Log.Access.SampleRate < 0 || c.Log.Access.SampleRate > 1 {
+		return fmt.Errorf("access log sample rate must be between 0 and 1, got %v", c.Log.Access.SampleRate)
+	}
+	return nil
+}
+
+// oneOf checks that a setting has one of the accepted values
+func oneOf(name, value string, accepted []string) error {
+	for _, candidate := range accepted {
+		if value == candidate {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown %s %q, expected one of %s", name, value, strings.Join(accepted, ", "))
+}
+
+// splitList splits a comma-separated setting, dropping empty entries
+func splitList(value string) []string {
+	entries := make([]string, 0)
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// parseRoutePolicies parses RATE_LIMIT_ROUTES, e.g.
+// "POST /products/batch=1:5,/products/search=20"
+func parseRoutePolicies(value string) ([]RoutePolicyConfig, error) {
+	var routes []RoutePolicyConfig
+	for _, entry := range splitList(value) {
+		target, limit, ok := strings.Cut(entry, "=")
+		fields := strings.Fields(target)
+		if !ok || len(fields) == 0 || len(fields) > 2 {
+			return nil, fmt.Errorf("invalid RATE_LIMIT_ROUTES entry %q, expected [METHOD ]/route=per_second[:burst]", entry)
+		}
+		var route RoutePolicyConfig
+		if len(fields) == 2 {
+			route.Method = strings.ToUpper(fields[0])
+		}
+		route.Route = fields[len(fields)-1]
+		rate, burst, hasBurst := strings.Cut(strings.TrimSpace(limit), ":")
+		var err error
+		if route.Rate, err = strconv.ParseFloat(rate, 64); err != nil {
+			return nil, fmt.Errorf("invalid rate %q in RATE_LIMIT_ROUTES entry %q", rate, entry)
+		}
+		if hasBurst {
+			if route.Burst, err = strconv.ParseFloat(burst, 64); err != nil {
+				return nil, fmt.Errorf("invalid burst %q in RATE_LIMIT_ROUTES entry %q", burst, entry)
+			}
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// env returns a getenv reading from a map
+func env(values map[string]string) func(string) string {
+	return func(key string) string {
+		return values[key]
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "ecom.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	config, err := Load(nil, env(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, Default(), config)
+	assert.Equal(t, ":8080", config.Server.Addr)
+	assert.Equal(t, []string{"*"}, config.Server.CORSOrigins)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeFile(t, `
+server:
+  addr: ":8000"
+  cors_origins: ["https://admin.example.com"]
+  shutdown_timeout: 45s
+  max_body_bytes: 2048
+  max_batch_size: 50
+repository:
+  backend: postgres
+rate_limit:
+  rate: 50
+  burst: 100
+log:
+  level: warn
+  access:
+    sample_rate: 0.5
+    sampled_routes: ["/products"]
+`)
+
+	config, err := Load([]string{"-config", path, "-log-level", "debug"}, env(map[string]string{
+		"SERVER_ADDR":            ":8001",
+		"RATE_LIMIT_RATE":        "20",
+		"ACCESS_LOG_SAMPLE_RATE": "0.1",
+		"MAX_BATCH_SIZE":         "20",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, ":8001", config.Server.Addr, "environment overrides the file")
+	assert.Equal(t, []string{"https://admin.example.com"}, config.Server.CORSOrigins)
+	assert.Equal(t, 45*time.Second, config.Server.ShutdownTimeout)
+	assert.Equal(t, int64(2048), config.Server.MaxBodyBytes)
+	assert.Equal(t, 20, config.Server.MaxBatchSize)
+	assert.Equal(t, "postgres", config.Repository.Backend)
+	assert.Equal(t, 20.0, config.RateLimit.Rate)
+	assert.Equal(t, 100.0, config.RateLimit.Burst)
+	assert.Equal(t, "debug", config.Log.Level, "flags override the file")
+	assert.Equal(t, AccessLogConfig{Enabled: true, SampleRate: 0.1, SampledRoutes: []string{"/products"}}, config.Log.Access)
+	assert.Equal(t, "memory", config.Locks.Backend, "unset settings keep their default")
+}
+
+func TestLoadFileFromEnvironment(t *testing.T) {
+	path := writeFile(t, "events:\n  publisher: kafka\n")
+
+	config, err := Load([]string{"-addr", ":9000", "-cors-origins", "https://a.example.com, https://b.example.com", "-access-log=false"}, env(map[string]string{"CONFIG_FILE": path, "ACCESS_LOG": "true"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka", config.Events.Publisher)
+	assert.Equal(t, ":9000", config.Server.Addr)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, config.Server.CORSOrigins)
+	assert.False(t, config.Log.Access.Enabled)
+}
+
+func TestLoadRoutePolicies(t *testing.T) {
+	path := writeFile(t, `
+rate_limit:
+  routes:
+    - method: POST
+      route: /products/batch
+      rate: 1
+      burst: 5
+`)
+	config, err := Load([]string{"-config", path}, env(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []RoutePolicyConfig{{Method: "POST", Route: "/products/batch", Rate: 1, Burst: 5}}, config.RateLimit.Routes)
+
+	config, err = Load([]string{"-config", path}, env(map[string]string{"RATE_LIMIT_ROUTES": "post /products/batch=0.5:2, /products/search=20"}))
+	assert.NoError(t, err)
+	assert.Equal(t, []RoutePolicyConfig{
+		{Method: "POST", Route: "/products/batch", Rate: 0.5, Burst: 2},
+		{Route: "/products/search", Rate: 20},
+	}, config.RateLimit.Routes, "the environment replaces the file's routes")
+}
+
+func TestLoadRejectsInvalidSettings(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		env  map[string]string
+	}{
+		"Unknown repository":    {env: map[string]string{"REPOSITORY": "mongo"}},
+		"Unknown lock backend":  {args: []string{"-locks", "redis"}},
+		"Unknown publisher":     {env: map[string]string{"EVENT_PUBLISHER": "nats"}},
+		"Zero rate":             {args: []string{"-rate-limit", "0"}},
+		"Unparsable rate":       {env: map[string]string{"RATE_LIMIT_BURST": "lots"}},
+		"Unknown log level":     {env: map[string]string{"LOG_LEVEL": "loud"}},
+		"Zero shutdown timeout": {env: map[string]string{"SHUTDOWN_TIMEOUT": "0s"}},
+		"Zero body limit":       {args: []string{"-max-body-bytes", "0"}},
+		"Unparsable batch size": {env: map[string]string{"MAX_BATCH_SIZE": "many"}},
+		"Invalid access log":    {env: map[string]string{"ACCESS_LOG": "sometimes"}},
+		"Sample rate above 1":   {args: []string{"-access-log-sample-rate", "2"}},
+		"Unknown flag":          {args: []string{"-port", "80"}},
+		"Missing file":          {args: []string{"-config", "/nonexistent/ecom.yaml"}},
+		"Invalid file":          {args: []string{"-config", writeFile(t, "server: [")}},
+		"Invalid route limit":   {env: map[string]string{"RATE_LIMIT_ROUTES": "POST /products/batch"}},
+		"Relative route":        {env: map[string]string{"RATE_LIMIT_ROUTES": "POST products=1"}},
+		"Zero route rate":       {args: []string{"-config", writeFile(t, "rate_limit:\n  routes:\n    - route: /products\n      rate: 0\n")}},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Load(tc.args, env(tc.env))
+			assert.Error(t, err)
+		})
+	}
+}
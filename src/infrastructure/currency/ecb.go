@@ -0,0 +1,93 @@
+package currency
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// DefaultECBURL is the ECB feed of the euro foreign exchange reference rates,
+// published once per working day around 16:00 CET
+const DefaultECBURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// requestTimeout bounds a fetch of the feed
+const requestTimeout = 10 * time.Second
+
+// ecbEnvelope is the part of the ECB feed the rates are read from:
+// <Cube><Cube time="2024-05-17"><Cube currency="USD" rate="1.0866"/>...
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string  `xml:"currency,attr"`
+			Rate     float64 `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ECBProvider fetches the daily euro reference rates of the European Central Bank
+type ECBProvider struct {
+	url  string
+	http *http.Client
+}
+
+// NewECBProvider creates a provider reading the feed at url, or the ECB feed when empty
+func NewECBProvider(url string) *ECBProvider {
+	if url == "" {
+		url = DefaultECBURL
+	}
+	return &ECBProvider{
+		url:  url,
+		http: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (p *ECBProvider) Name() string {
+	return ProviderECB
+}
+
+// Rates fetches the feed. The rates are per euro and dated the day they were
+// published; currencies that are not ISO 4217 codes are skipped.
+func (p *ECBProvider) Rates() (*models.ExchangeRates, error) {
+	resp, err := p.http.Get(p.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("ECB feed returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("invalid ECB feed: %v", err)
+	}
+	if len(envelope.Days) == 0 || len(envelope.Days[0].Rates) == 0 {
+		return nil, fmt.Errorf("ECB feed has no rates")
+	}
+	day := envelope.Days[0]
+	asOf, err := time.Parse("2006-01-02", day.Time)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ECB feed date %q", day.Time)
+	}
+
+	rates := &models.ExchangeRates{
+		Base:   "EUR",
+		Rates:  make(map[string]float64, len(day.Rates)),
+		AsOf:   asOf,
+		Source: ProviderECB,
+	}
+	for _, rate := range day.Rates {
+		if models.IsCurrencyCode(rate.Currency) && rate.Rate > 0 {
+			rates.Rates[strings.ToUpper(rate.Currency)] = rate.Rate
+		}
+	}
+	return rates, nil
+}
@@ -0,0 +1,64 @@
+package currency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const ecbFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender><gesmes:name>European Central Bank</gesmes:name></gesmes:Sender>
+	<Cube>
+		<Cube time="2024-05-17">
+			<Cube currency="USD" rate="1.0866"/>
+			<Cube currency="SEK" rate="11.6175"/>
+			<Cube currency="XYZ" rate="2"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestECBProviderRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(ecbFeed))
+	}))
+	defer server.Close()
+
+	rates, err := NewECBProvider(server.URL).Rates()
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", rates.Base)
+	assert.Equal(t, ProviderECB, rates.Source)
+	assert.Equal(t, time.Date(2024, 5, 17, 0, 0, 0, 0, time.UTC), rates.AsOf)
+	assert.Equal(t, map[string]float64{"USD": 1.0866, "SEK": 11.6175}, rates.Rates)
+}
+
+func TestECBProviderRatesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"error status", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+		}},
+		{"invalid feed", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<Envelope><Cube>"))
+		}},
+		{"no rates", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<Envelope><Cube></Cube></Envelope>"))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			_, err := NewECBProvider(server.URL).Rates()
+			assert.Error(t, err)
+		})
+	}
+}
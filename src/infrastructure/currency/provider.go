@@ -0,0 +1,100 @@
+// Package currency provides the exchange rates prices are converted with:
+// fixed rates from configuration or the daily reference rates of the ECB.
+package currency
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Provider names
+const (
+	ProviderStatic = "static"
+	ProviderECB    = "ecb"
+)
+
+// ProviderConfig selects and configures an exchange rate provider
+type ProviderConfig struct {
+	Name   string // static (default) or ecb
+	Base   string // Base currency of static rates, EUR by default
+	Rates  string // Static rates per unit of the base, e.g. "SEK=11.52,NOK=11.71"
+	ECBURL string // Overrides the ECB daily feed URL
+}
+
+// NewProvider creates the configured provider
+func NewProvider(config ProviderConfig) (interfaces.ExchangeRateProvider, error) {
+	switch config.Name {
+	case "", ProviderStatic:
+		base := config.Base
+		if base == "" {
+			base = "EUR"
+		}
+		rates, err := ParseRates(config.Rates)
+		if err != nil {
+			return nil, err
+		}
+		return NewStaticProvider(base, rates)
+	case ProviderECB:
+		return NewECBProvider(config.ECBURL), nil
+	}
+	return nil, fmt.Errorf("unknown exchange rate provider %q, expected static or ecb", config.Name)
+}
+
+// ParseRates parses comma separated CURRENCY=rate pairs. An empty string has no rates.
+func ParseRates(spec string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		currency, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid exchange rate %q, expected CURRENCY=rate", pair)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate %q: %v", pair, err)
+		}
+		rates[strings.TrimSpace(currency)] = rate
+	}
+	return rates, nil
+}
+
+// StaticProvider serves fixed rates, e.g. the rates a finance team sets per season
+type StaticProvider struct {
+	rates models.ExchangeRates
+}
+
+// NewStaticProvider creates a provider of fixed rates per unit of the base currency
+func NewStaticProvider(base string, rates map[string]float64) (*StaticProvider, error) {
+	provider := &StaticProvider{rates: models.ExchangeRates{
+		Base:   base,
+		Rates:  rates,
+		AsOf:   time.Now().UTC(),
+		Source: ProviderStatic,
+	}}
+	if err := provider.rates.Validate(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+func (p *StaticProvider) Name() string {
+	return ProviderStatic
+}
+
+// Rates returns a copy of the configured rates, as of when they were loaded
+func (p *StaticProvider) Rates() (*models.ExchangeRates, error) {
+	rates := p.rates
+	rates.Rates = make(map[string]float64, len(p.rates.Rates))
+	for currency, rate := range p.rates.Rates {
+		rates.Rates[currency] = rate
+	}
+	return &rates, nil
+}
@@ -0,0 +1,51 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestParseRates(t *testing.T) {
+	rates, err := ParseRates(" SEK=11.52, nok = 11.71,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"SEK": 11.52, "nok": 11.71}, rates)
+
+	rates, err = ParseRates("")
+	assert.NoError(t, err)
+	assert.Empty(t, rates)
+
+	_, err = ParseRates("SEK")
+	assert.Error(t, err)
+	_, err = ParseRates("SEK=many")
+	assert.Error(t, err)
+}
+
+func TestNewProvider(t *testing.T) {
+	provider, err := NewProvider(ProviderConfig{Rates: "SEK=11.52,nok=11.71"})
+	assert.NoError(t, err)
+	assert.Equal(t, ProviderStatic, provider.Name())
+
+	rates, err := provider.Rates()
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", rates.Base)
+	assert.Equal(t, map[string]float64{"SEK": 11.52, "NOK": 11.71}, rates.Rates)
+
+	// Callers get a copy of the rates
+	rates.Rates["SEK"] = 1
+	rates, _ = provider.Rates()
+	assert.Equal(t, 11.52, rates.Rates["SEK"])
+
+	provider, err = NewProvider(ProviderConfig{Name: ProviderECB})
+	assert.NoError(t, err)
+	assert.Equal(t, ProviderECB, provider.Name())
+
+	_, err = NewProvider(ProviderConfig{Name: "fixer"})
+	assert.Error(t, err)
+	_, err = NewProvider(ProviderConfig{Base: "SEK", Rates: "KRONOR=1"})
+	assert.ErrorIs(t, err, models.ErrUnknownCurrency)
+	_, err = NewProvider(ProviderConfig{Rates: "SEK=0"})
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+}
@@ -0,0 +1,25 @@
+package deprecation
+
+import "time"
+
+// Registered lists everything deprecated in the API. Add an entry here when a
+// route or field is deprecated, and remove it together with the code after the
+// sunset, once the dashboard shows no more use.
+var Registered = []Deprecation{
+	{
+		ID:          "success-response-envelope",
+		Routes:      []string{"PUT /products/{id}", "POST /products/{id}/rollback", "POST /products/{id}/stock"},
+		Field:       "success",
+		Description: `Responses are wrapped in {"success": true, "data": ...}`,
+		Replacement: "Send API-Version: 2 to get the product under data in the envelope every endpoint uses",
+		Since:       time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC),
+		MaxVersion:  1,
+	},
+	{
+		ID:          "unversioned-paths",
+		LegacyPaths: true,
+		Description: "Routes are served without a version prefix",
+		Replacement: "Prefix the path with /v1, or /v2 for the envelope, e.g. /v1/products",
+		Since:       time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC),
+	},
+}
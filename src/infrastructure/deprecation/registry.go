@@ -0,0 +1,174 @@
+package deprecation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+// maxTrackedClients caps the callers counted per deprecation; further callers
+// are counted under otherClients
+const maxTrackedClients = 50
+
+// otherClients is the client name requests past maxTrackedClients are counted under
+const otherClients = "other"
+
+// Deprecation marks routes, or one field of their responses, as deprecated
+type Deprecation struct {
+	ID          string    // Stable name used in metrics, e.g. "success-response-envelope"
+	Routes      []string  // Method and path template, e.g. "PUT /products/{id}"
+	Field       string    // Deprecated response field; empty when the whole routes are deprecated
+	Description string    // What is deprecated
+	Replacement string    // What clients should use instead
+	Since       time.Time // When the deprecation was announced
+	Sunset      time.Time // When the route or field will be removed; zero while undecided
+	Link        string    // Documentation of the migration, if any
+	MaxVersion  int       // Last API version the deprecation applies to; zero for every version
+	// LegacyPaths deprecates requesting the routes without a /v1 or /v2
+	// prefix. Without Routes it covers every versioned route.
+	LegacyPaths bool
+}
+
+// usage counts the requests to one deprecation
+type usage struct {
+	deprecation Deprecation
+	requests    int64
+	lastUsed    time.Time
+	clients     map[string]int64
+}
+
+// Registry is the central list of deprecations. It looks them up per route
+// and counts how often, and by whom, each is still used.
+type Registry struct {
+	entries []*usage
+	legacy  []*usage // deprecations of every legacy path
+	byRoute map[string][]*usage
+	byID    map[string]*usage
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+// NewRegistry creates a registry of the deprecations, rejecting duplicate IDs,
+// malformed routes and sunsets before the announcement
+func NewRegistry(deprecations ...Deprecation) (*Registry, error) {
+	r := &Registry{
+		byRoute: make(map[string][]*usage),
+		byID:    make(map[string]*usage),
+		now:     time.Now,
+	}
+	for _, deprecation := range deprecations {
+		if deprecation.ID == "" {
+			return nil, fmt.Errorf("deprecation of %v has no id", deprecation.Routes)
+		}
+		if _, exists := r.byID[deprecation.ID]; exists {
+			return nil, fmt.Errorf("deprecation %s is registered twice", deprecation.ID)
+		}
+		if len(deprecation.Routes) == 0 && !deprecation.LegacyPaths {
+			return nil, fmt.Errorf("deprecation %s has no routes", deprecation.ID)
+		}
+		for _, route := range deprecation.Routes {
+			if method, path, ok := strings.Cut(route, " "); !ok || method == "" || !strings.HasPrefix(path, "/") {
+				return nil, fmt.Errorf("deprecation %s: route %q must look like \"GET /products/{id}\"", deprecation.ID, route)
+			}
+		}
+		if deprecation.Since.IsZero() {
+			return nil, fmt.Errorf("deprecation %s has no since date", deprecation.ID)
+		}
+		if !deprecation.Sunset.IsZero() && deprecation.Sunset.Before(deprecation.Since) {
+			return nil, fmt.Errorf("deprecation %s: sunset is before since", deprecation.ID)
+		}
+
+		entry := &usage{deprecation: deprecation, clients: make(map[string]int64)}
+		r.entries = append(r.entries, entry)
+		r.byID[deprecation.ID] = entry
+		if len(deprecation.Routes) == 0 {
+			r.legacy = append(r.legacy, entry)
+		}
+		for _, route := range deprecation.Routes {
+			r.byRoute[route] = append(r.byRoute[route], entry)
+		}
+	}
+	return r, nil
+}
+
+// Lookup returns the deprecations of a route, such as "PUT /products/{id}"
+func (r *Registry) Lookup(route string) []Deprecation {
+	return r.lookup(route, false)
+}
+
+// LookupLegacyPath returns the deprecations of a route requested without a
+// version prefix: those of Lookup and those of legacy paths
+func (r *Registry) LookupLegacyPath(route string) []Deprecation {
+	return r.lookup(route, true)
+}
+
+func (r *Registry) lookup(route string, legacyPath bool) []Deprecation {
+	deprecations := make([]Deprecation, 0)
+	if legacyPath {
+		for _, entry := range r.legacy {
+			deprecations = append(deprecations, entry.deprecation)
+		}
+	}
+	for _, entry := range r.byRoute[route] {
+		if legacyPath || !entry.deprecation.LegacyPaths {
+			deprecations = append(deprecations, entry.deprecation)
+		}
+	}
+	return deprecations
+}
+
+// Record counts a request to a deprecation by a client
+func (r *Registry) Record(id, client string) {
+	entry, ok := r.byID[id]
+	if !ok {
+		return
+	}
+	metrics.DeprecatedRequests.WithLabelValues(id).Inc()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry.requests++
+	entry.lastUsed = r.now()
+	if _, tracked := entry.clients[client]; !tracked && len(entry.clients) >= maxTrackedClients {
+		client = otherClients
+	}
+	entry.clients[client]++
+}
+
+// DeprecationUsage returns every deprecation with its usage, in registration order
+func (r *Registry) DeprecationUsage() []*interfaces.DeprecationUsage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := make([]*interfaces.DeprecationUsage, 0, len(r.entries))
+	for _, entry := range r.entries {
+		deprecation := entry.deprecation
+		item := &interfaces.DeprecationUsage{
+			ID:          deprecation.ID,
+			Routes:      append([]string(nil), deprecation.Routes...),
+			Field:       deprecation.Field,
+			Description: deprecation.Description,
+			Replacement: deprecation.Replacement,
+			Since:       deprecation.Since,
+			Requests:    entry.requests,
+			Clients:     make(map[string]int64, len(entry.clients)),
+		}
+		if !deprecation.Sunset.IsZero() {
+			sunset := deprecation.Sunset
+			item.Sunset = &sunset
+		}
+		if !entry.lastUsed.IsZero() {
+			lastUsed := entry.lastUsed
+			item.LastUsed = &lastUsed
+		}
+		for client, count := range entry.clients {
+			item.Clients[client] = count
+		}
+		report = append(report, item)
+	}
+	return report
+}
@@ -0,0 +1,101 @@
+package deprecation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+var testSince = time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNewRegistryValidates(t *testing.T) {
+	valid := Deprecation{ID: "a", Routes: []string{"GET /a"}, Since: testSince}
+	_, err := NewRegistry(valid)
+	assert.NoError(t, err)
+
+	invalid := map[string][]Deprecation{
+		"no id":          {{Routes: []string{"GET /a"}, Since: testSince}},
+		"duplicate id":   {valid, valid},
+		"no routes":      {{ID: "a", Since: testSince}},
+		"bad route":      {{ID: "a", Routes: []string{"/a"}, Since: testSince}},
+		"no since":       {{ID: "a", Routes: []string{"GET /a"}}},
+		"sunset earlier": {{ID: "a", Routes: []string{"GET /a"}, Since: testSince, Sunset: testSince.AddDate(0, -1, 0)}},
+	}
+	for name, deprecations := range invalid {
+		_, err := NewRegistry(deprecations...)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRegistryLookupLegacyPath(t *testing.T) {
+	registry, err := NewRegistry(
+		Deprecation{ID: "unversioned", LegacyPaths: true, Since: testSince},
+		Deprecation{ID: "legacy-export", Routes: []string{"GET /export"}, LegacyPaths: true, Since: testSince},
+		Deprecation{ID: "wrapper", Routes: []string{"GET /export"}, Field: "success", Since: testSince},
+	)
+	assert.NoError(t, err)
+
+	ids := func(deprecations []Deprecation) []string {
+		var result []string
+		for _, d := range deprecations {
+			result = append(result, d.ID)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"wrapper"}, ids(registry.Lookup("GET /export")))
+	assert.Equal(t, []string{"unversioned", "legacy-export", "wrapper"}, ids(registry.LookupLegacyPath("GET /export")))
+	assert.Equal(t, []string{"unversioned"}, ids(registry.LookupLegacyPath("GET /other")))
+	assert.Empty(t, registry.Lookup("GET /other"))
+}
+
+func TestRegisteredDeprecationsAreValid(t *testing.T) {
+	_, err := NewRegistry(Registered...)
+	assert.NoError(t, err)
+}
+
+func TestRegistryLookupAndUsage(t *testing.T) {
+	registry, err := NewRegistry(
+		Deprecation{ID: "wrapper", Routes: []string{"PUT /a/{id}", "POST /a/{id}/undo"}, Field: "success", Since: testSince},
+		Deprecation{ID: "legacy", Routes: []string{"GET /legacy"}, Since: testSince, Sunset: testSince.AddDate(0, 3, 0)},
+	)
+	assert.NoError(t, err)
+	now := testSince.Add(time.Hour)
+	registry.now = func() time.Time { return now }
+
+	assert.Len(t, registry.Lookup("POST /a/{id}/undo"), 1)
+	assert.Empty(t, registry.Lookup("GET /a/{id}"))
+
+	before := testutil.ToFloat64(metrics.DeprecatedRequests.WithLabelValues("wrapper"))
+	registry.Record("wrapper", "shop")
+	registry.Record("wrapper", "shop")
+	registry.Record("unknown", "shop")
+	assert.Equal(t, before+2, testutil.ToFloat64(metrics.DeprecatedRequests.WithLabelValues("wrapper")))
+
+	usage := registry.DeprecationUsage()
+	assert.Len(t, usage, 2)
+	assert.Equal(t, "wrapper", usage[0].ID)
+	assert.Equal(t, int64(2), usage[0].Requests)
+	assert.Equal(t, now, *usage[0].LastUsed)
+	assert.Nil(t, usage[0].Sunset)
+	assert.Equal(t, map[string]int64{"shop": 2}, usage[0].Clients)
+	assert.Nil(t, usage[1].LastUsed)
+	assert.Equal(t, testSince.AddDate(0, 3, 0), *usage[1].Sunset)
+}
+
+func TestRegistryCapsTrackedClients(t *testing.T) {
+	registry, _ := NewRegistry(Deprecation{ID: "legacy", Routes: []string{"GET /legacy"}, Since: testSince})
+	for i := 0; i < maxTrackedClients+5; i++ {
+		registry.Record("legacy", fmt.Sprintf("client-%d", i))
+	}
+	registry.Record("legacy", "client-0")
+
+	clients := registry.DeprecationUsage()[0].Clients
+	assert.Len(t, clients, maxTrackedClients+1)
+	assert.Equal(t, int64(5), clients[otherClients])
+	assert.Equal(t, int64(2), clients["client-0"])
+}
@@ -0,0 +1,80 @@
+//go:build kafka
+
+package kafka
+
+import (
+	"context"
+
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// NewWriter creates a writer on github.com/segmentio/kafka-go that waits for
+// every in-sync replica and hashes keys to partitions
+func NewWriter(config Config) (Writer, error) {
+	return &clientWriter{writer: &kafkago.Writer{
+		Addr:         kafkago.TCP(config.Brokers...),
+		Balancer:     &kafkago.Hash{},
+		RequiredAcks: kafkago.RequireAll,
+		// Retries are done by the publisher, with backoff
+		MaxAttempts: 1,
+	}}, nil
+}
+
+// NewReader creates a consumer group reader over every event topic
+func NewReader(config Config) (Reader, error) {
+	return &clientReader{reader: kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers:     config.Brokers,
+		GroupID:     config.GroupID,
+		GroupTopics: config.Topics(),
+	})}, nil
+}
+
+type clientWriter struct {
+	writer *kafkago.Writer
+}
+
+func (w *clientWriter) WriteMessages(ctx context.Context, messages ...Message) error {
+	converted := make([]kafkago.Message, len(messages))
+	for i, message := range messages {
+		headers := make([]kafkago.Header, len(message.Headers))
+		for j, header := range message.Headers {
+			headers[j] = kafkago.Header{Key: header.Key, Value: header.Value}
+		}
+		converted[i] = kafkago.Message{Topic: message.Topic, Key: message.Key, Value: message.Value, Headers: headers}
+	}
+	return w.writer.WriteMessages(ctx, converted...)
+}
+
+func (w *clientWriter) Close() error {
+	return w.writer.Close()
+}
+
+type clientReader struct {
+	reader *kafkago.Reader
+}
+
+func (r *clientReader) FetchMessage(ctx context.Context) (Message, error) {
+	fetched, err := r.reader.FetchMessage(ctx)
+	if err != nil {
+		return Message{}, err
+	}
+	headers := make([]Header, len(fetched.Headers))
+	for i, header := range fetched.Headers {
+		headers[i] = Header{Key: header.Key, Value: header.Value}
+	}
+	return Message{Topic: fetched.Topic, Key: fetched.Key, Value: fetched.Value, Headers: headers, raw: fetched}, nil
+}
+
+func (r *clientReader) CommitMessages(ctx context.Context, messages ...Message) error {
+	raw := make([]kafkago.Message, 0, len(messages))
+	for _, message := range messages {
+		if fetched, ok := message.raw.(kafkago.Message); ok {
+			raw = append(raw, fetched)
+		}
+	}
+	return r.reader.CommitMessages(ctx, raw...)
+}
+
+func (r *clientReader) Close() error {
+	return r.reader.Close()
+}
@@ -0,0 +1,18 @@
+//go:build !kafka
+
+package kafka
+
+import "errors"
+
+// ErrClientNotLinked is returned when the binary was built without -tags kafka
+var ErrClientNotLinked = errors.New("built without a Kafka client: add github.com/segmentio/kafka-go and build with -tags kafka")
+
+// NewWriter fails: no Kafka client is linked
+func NewWriter(config Config) (Writer, error) {
+	return nil, ErrClientNotLinked
+}
+
+// NewReader fails: no Kafka client is linked
+func NewReader(config Config) (Reader, error) {
+	return nil, ErrClientNotLinked
+}
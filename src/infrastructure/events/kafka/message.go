@@ -0,0 +1,181 @@
+// Package kafka publishes product events to Kafka so external systems can
+// consume them, and lets Go consumers read them back in a consumer group.
+//
+// The package talks to Kafka through the Writer and Reader interfaces. The
+// client behind them is linked by building with -tags kafka (see client.go);
+// tests and other clients supply their own.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Headers set on every message
+const (
+	HeaderEventType     = "event-type"
+	HeaderEventID       = "event-id"
+	HeaderSchemaVersion = "schema-version"
+)
+
+// Header is a message header
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Message is a Kafka record
+type Message struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers []Header
+
+	// raw is the client's own message, needed to commit it
+	raw interface{}
+}
+
+// Header returns the value of a header, or the empty string
+func (m Message) Header(key string) string {
+	for _, header := range m.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// Writer produces messages. WriteMessages writes all messages or fails.
+type Writer interface {
+	WriteMessages(ctx context.Context, messages ...Message) error
+	Close() error
+}
+
+// Reader fetches messages for a consumer group and commits their offsets
+type Reader interface {
+	FetchMessage(ctx context.Context) (Message, error)
+	CommitMessages(ctx context.Context, messages ...Message) error
+	Close() error
+}
+
+// Config configures topics, the consumer group and retries
+type Config struct {
+	Brokers []string
+	// Topic is the single topic for every event, or the prefix of the topic of
+	// each event type when TopicPerType is set, e.g. "ecom" -> "ecom.product.created"
+	Topic        string
+	TopicPerType bool
+	// GroupID is the consumer group of a Subscriber
+	GroupID string
+	// Retries is how often a failed write or commit is tried again, with
+	// RetryBackoff doubling between attempts
+	Retries      int
+	RetryBackoff time.Duration
+}
+
+// EventTypes are the event types published to Kafka
+var EventTypes = []models.EventType{
+	models.EventProductCreated,
+	models.EventProductUpdated,
+	models.EventProductDeleted,
+	models.EventProductRestored,
+	models.EventStockChanged,
+	models.EventPriceChanged,
+	models.EventCategoryCreated,
+	models.EventCategoryUpdated,
+	models.EventCategoryDeleted,
+}
+
+// TopicFor returns the topic events of a type are published to
+func (c Config) TopicFor(eventType models.EventType) string {
+	if c.TopicPerType {
+		return c.Topic + "." + string(eventType)
+	}
+	return c.Topic
+}
+
+// Topics returns every topic events are published to
+func (c Config) Topics() []string {
+	if !c.TopicPerType {
+		return []string{c.Topic}
+	}
+	topics := make([]string, len(EventTypes))
+	for i, eventType := range EventTypes {
+		topics[i] = c.TopicFor(eventType)
+	}
+	return topics
+}
+
+// retry runs an operation until it succeeds or the retries are used up
+func (c Config) retry(ctx context.Context, operation func() error) error {
+	backoff := c.RetryBackoff
+	if backoff <= 0 {
+		backoff = 100 * time.Millisecond
+	}
+	err := operation()
+	for attempt := 0; err != nil && attempt < c.Retries; attempt++ {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		err = operation()
+	}
+	return err
+}
+
+// Encode turns an event into a message keyed by the product ID, so every event
+// of a product lands on the same partition and is consumed in order
+func Encode(config Config, event *models.Event) (Message, error) {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Topic: config.TopicFor(event.Type),
+		Key:   []byte(event.EntityID),
+		Value: value,
+		Headers: []Header{
+			{Key: HeaderEventType, Value: []byte(event.Type)},
+			{Key: HeaderEventID, Value: []byte(event.ID)},
+			{Key: HeaderSchemaVersion, Value: []byte(strconv.Itoa(event.SchemaVersion))},
+		},
+	}, nil
+}
+
+// Decode turns a message back into an event with product event data, or
+// category event data for category events
+func Decode(message Message) (*models.Event, error) {
+	var envelope struct {
+		models.Event
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(message.Value, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid event message: %v", err)
+	}
+	event := envelope.Event
+	if eventType := message.Header(HeaderEventType); eventType != "" && event.Type == "" {
+		event.Type = models.EventType(eventType)
+	}
+	event.Data = nil
+	if len(envelope.Data) > 0 && string(envelope.Data) != "null" {
+		var data interface{} = &models.ProductEvent{}
+		if event.Type.IsCategoryEvent() {
+			data = &models.CategoryEvent{}
+		}
+		if err := json.Unmarshal(envelope.Data, data); err != nil {
+			return nil, fmt.Errorf("invalid event message: %v", err)
+		}
+		event.Data = data
+	}
+	if err := models.ValidateEvent(&event); err != nil {
+		return nil, fmt.Errorf("invalid event message: %v", err)
+	}
+	return &event, nil
+}
@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func createTestEvent(eventType models.EventType) *models.Event {
+	return &models.Event{
+		ID:            "evt_1",
+		Type:          eventType,
+		EntityID:      "prod_1",
+		Version:       2,
+		Sequence:      7,
+		SchemaVersion: models.EventSchemaVersion,
+		Data: &models.ProductEvent{
+			ProductID: "prod_1",
+			Action:    "updated",
+			Product:   &models.Product{ID: "prod_1", SKU: "SHIRT-1"},
+			Version:   2,
+		},
+		Timestamp: time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestTopics(t *testing.T) {
+	single := Config{Topic: "product-events"}
+	assert.Equal(t, "product-events", single.TopicFor(models.EventProductDeleted))
+	assert.Equal(t, []string{"product-events"}, single.Topics())
+
+	perType := Config{Topic: "ecom", TopicPerType: true}
+	assert.Equal(t, "ecom.product.created", perType.TopicFor(models.EventProductCreated))
+	assert.Len(t, perType.Topics(), len(EventTypes))
+}
+
+func TestEncodeDecode(t *testing.T) {
+	event := createTestEvent(models.EventProductUpdated)
+
+	message, err := Encode(Config{Topic: "product-events"}, event)
+	assert.NoError(t, err)
+	assert.Equal(t, "product-events", message.Topic)
+	assert.Equal(t, "prod_1", string(message.Key))
+	assert.Equal(t, "product.updated", message.Header(HeaderEventType))
+	assert.Equal(t, "evt_1", message.Header(HeaderEventID))
+	assert.Equal(t, "1", message.Header(HeaderSchemaVersion))
+
+	decoded, err := Decode(message)
+	assert.NoError(t, err)
+	assert.Equal(t, event.ID, decoded.ID)
+	assert.Equal(t, event.Sequence, decoded.Sequence)
+	assert.True(t, event.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, "SHIRT-1", decoded.Data.(*models.ProductEvent).Product.SKU)
+}
+
+func TestEncodeDecodeCategoryEvent(t *testing.T) {
+	event := &models.Event{
+		ID:       "evt_2",
+		Type:     models.EventCategoryCreated,
+		EntityID: "cat_1",
+		Data: &models.CategoryEvent{
+			CategoryID: "cat_1",
+			Action:     "created",
+			Category:   &models.Category{ID: "cat_1", Slug: "shirts", Names: map[string]string{"SE": "Skjortor"}},
+		},
+	}
+
+	message, err := Encode(Config{Topic: "product-events"}, event)
+	assert.NoError(t, err)
+	decoded, err := Decode(message)
+	assert.NoError(t, err)
+	assert.Equal(t, "shirts", decoded.Data.(*models.CategoryEvent).Category.Slug)
+}
+
+func TestDecodeRejectsInvalidMessages(t *testing.T) {
+	for _, value := range []string{`{`, `{"id":"evt_1","type":"product.updated"}`} {
+		_, err := Decode(Message{Value: []byte(value)})
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,107 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// writeTimeout bounds one write to Kafka including its retries
+const writeTimeout = 30 * time.Second
+
+// Publisher sends events to the subscribers of a local publisher and writes
+// them to Kafka. Local subscribers, such as the dashboard and WebSocket relay,
+// need every event on every instance, so they are not served through a
+// consumer group; Kafka feeds systems outside the service.
+type Publisher struct {
+	local  events.EventPublisher
+	writer Writer
+	config Config
+}
+
+// NewPublisher creates a publisher that writes to Kafka besides publishing locally
+func NewPublisher(local events.EventPublisher, writer Writer, config Config) *Publisher {
+	return &Publisher{
+		local:  local,
+		writer: writer,
+		config: config,
+	}
+}
+
+// Publish sends an event to the local subscribers and writes it to Kafka,
+// retrying failed writes
+func (p *Publisher) Publish(event *models.Event) error {
+	if err := p.local.Publish(event); err != nil {
+		return err
+	}
+	message, err := Encode(p.config, event)
+	if err != nil {
+		return err
+	}
+	return p.write(message)
+}
+
+// PublishBatch publishes events locally and writes them to Kafka in one call
+func (p *Publisher) PublishBatch(batch []*models.Event) []error {
+	errs := p.local.PublishBatch(batch)
+	messages := make([]Message, 0, len(batch))
+	indexes := make([]int, 0, len(batch))
+	for i, event := range batch {
+		if errs[i] != nil {
+			continue
+		}
+		message, err := Encode(p.config, event)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		messages = append(messages, message)
+		indexes = append(indexes, i)
+	}
+	if len(messages) == 0 {
+		return errs
+	}
+	if err := p.write(messages...); err != nil {
+		for _, i := range indexes {
+			errs[i] = err
+		}
+	}
+	return errs
+}
+
+// write writes messages to Kafka, retrying as configured
+func (p *Publisher) write(messages ...Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	err := p.config.retry(ctx, func() error {
+		return p.writer.WriteMessages(ctx, messages...)
+	})
+	if err != nil {
+		logging.Shared().Error("Failed to write events to Kafka",
+			zap.Int("events", len(messages)),
+			zap.String("topic", messages[0].Topic),
+			zap.Error(err),
+		)
+	}
+	return err
+}
+
+// Subscribe registers a local handler for a specific event type
+func (p *Publisher) Subscribe(eventType models.EventType, handler func(*models.Event)) error {
+	return p.local.Subscribe(eventType, handler)
+}
+
+// Unsubscribe removes a local handler for a specific event type
+func (p *Publisher) Unsubscribe(eventType models.EventType, handler func(*models.Event)) error {
+	return p.local.Unsubscribe(eventType, handler)
+}
+
+// Close closes the Kafka writer
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
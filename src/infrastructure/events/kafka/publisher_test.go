@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/events/memory"
+)
+
+// fakeWriter records written messages and fails the first failures writes
+type fakeWriter struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	written  []Message
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, messages ...Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.attempts++
+	if w.failures > 0 {
+		w.failures--
+		return errors.New("broker unavailable")
+	}
+	w.written = append(w.written, messages...)
+	return nil
+}
+
+func (w *fakeWriter) Close() error {
+	return nil
+}
+
+func TestPublisherWritesToKafkaAndLocally(t *testing.T) {
+	writer := &fakeWriter{}
+	publisher := NewPublisher(memory.NewMemoryEventPublisher(), writer, Config{Topic: "ecom", TopicPerType: true})
+	received := make(chan *models.Event, 1)
+	publisher.Subscribe(models.EventProductUpdated, func(event *models.Event) { received <- event })
+
+	assert.NoError(t, publisher.Publish(createTestEvent(models.EventProductUpdated)))
+
+	select {
+	case event := <-received:
+		assert.Equal(t, "evt_1", event.ID)
+	case <-time.After(time.Second):
+		t.Fatal("local subscriber did not receive the event")
+	}
+	assert.Len(t, writer.written, 1)
+	assert.Equal(t, "ecom.product.updated", writer.written[0].Topic)
+}
+
+func TestPublisherRetriesWrites(t *testing.T) {
+	writer := &fakeWriter{failures: 2}
+	publisher := NewPublisher(memory.NewMemoryEventPublisher(), writer, Config{Topic: "ecom", Retries: 2, RetryBackoff: time.Millisecond})
+
+	assert.NoError(t, publisher.Publish(createTestEvent(models.EventProductUpdated)))
+	assert.Equal(t, 3, writer.attempts)
+
+	writer.failures = 5
+	assert.Error(t, publisher.Publish(createTestEvent(models.EventProductUpdated)))
+}
+
+func TestPublisherPublishBatch(t *testing.T) {
+	writer := &fakeWriter{}
+	publisher := NewPublisher(memory.NewMemoryEventPublisher(), writer, Config{Topic: "ecom"})
+
+	errs := publisher.PublishBatch([]*models.Event{createTestEvent(models.EventProductCreated), createTestEvent(models.EventProductDeleted)})
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Len(t, writer.written, 2)
+	assert.Equal(t, 1, writer.attempts)
+
+	writer.failures = 1
+	errs = publisher.PublishBatch([]*models.Event{createTestEvent(models.EventProductCreated)})
+	assert.Error(t, errs[0])
+}
@@ -0,0 +1,111 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// Subscriber consumes events from Kafka as a member of a consumer group, for
+// consumers running outside this service. Every event is handled by one member
+// of the group; its offset is committed once its handlers returned, so
+// delivery is at least once.
+type Subscriber struct {
+	reader   Reader
+	config   Config
+	handlers map[models.EventType][]func(*models.Event)
+	mu       sync.RWMutex
+}
+
+// NewSubscriber creates a subscriber reading from the reader's consumer group
+func NewSubscriber(reader Reader, config Config) *Subscriber {
+	return &Subscriber{
+		reader:   reader,
+		config:   config,
+		handlers: make(map[models.EventType][]func(*models.Event)),
+	}
+}
+
+// Subscribe registers a handler for a specific event type
+func (s *Subscriber) Subscribe(eventType models.EventType, handler func(*models.Event)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[eventType] = append(s.handlers[eventType], handler)
+	return nil
+}
+
+// Unsubscribe removes a handler for a specific event type
+func (s *Subscriber) Unsubscribe(eventType models.EventType, handler func(*models.Event)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	handlers := make([]func(*models.Event), 0)
+	for _, h := range s.handlers[eventType] {
+		if reflect.ValueOf(h).Pointer() != reflect.ValueOf(handler).Pointer() {
+			handlers = append(handlers, h)
+		}
+	}
+	if len(handlers) > 0 {
+		s.handlers[eventType] = handlers
+	} else {
+		delete(s.handlers, eventType)
+	}
+	return nil
+}
+
+// Run fetches and handles messages until the context is cancelled, which
+// returns nil, or fetching or committing fails for good. Messages that are
+// not events are logged and committed so they do not block the partition.
+func (s *Subscriber) Run(ctx context.Context) error {
+	logger := logging.Shared().WithFields(zap.String("group_id", s.config.GroupID))
+	for {
+		message, err := s.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}
+
+		if event, err := Decode(message); err != nil {
+			logger.Warn("Skipping invalid Kafka message",
+				zap.String("topic", message.Topic),
+				zap.String("key", string(message.Key)),
+				zap.Error(err),
+			)
+		} else {
+			s.handle(event)
+		}
+
+		if err := s.config.retry(ctx, func() error {
+			return s.reader.CommitMessages(ctx, message)
+		}); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// handle runs the handlers of an event's type in order
+func (s *Subscriber) handle(event *models.Event) {
+	s.mu.RLock()
+	handlers := make([]func(*models.Event), len(s.handlers[event.Type]))
+	copy(handlers, s.handlers[event.Type])
+	s.mu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// Close closes the Kafka reader
+func (s *Subscriber) Close() error {
+	return s.reader.Close()
+}
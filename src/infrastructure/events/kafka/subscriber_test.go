@@ -0,0 +1,77 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// fakeReader hands out queued messages, then blocks until the context ends
+type fakeReader struct {
+	mu        sync.Mutex
+	messages  []Message
+	committed []Message
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (Message, error) {
+	r.mu.Lock()
+	if len(r.messages) > 0 {
+		message := r.messages[0]
+		r.messages = r.messages[1:]
+		r.mu.Unlock()
+		return message, nil
+	}
+	r.mu.Unlock()
+	<-ctx.Done()
+	return Message{}, ctx.Err()
+}
+
+func (r *fakeReader) CommitMessages(ctx context.Context, messages ...Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.committed = append(r.committed, messages...)
+	return nil
+}
+
+func (r *fakeReader) Close() error {
+	return nil
+}
+
+func TestSubscriberHandlesAndCommits(t *testing.T) {
+	config := Config{Topic: "ecom", GroupID: "search-indexer"}
+	updated, _ := Encode(config, createTestEvent(models.EventProductUpdated))
+	deleted, _ := Encode(config, createTestEvent(models.EventProductDeleted))
+	invalid := Message{Topic: "ecom", Value: []byte("not json")}
+	reader := &fakeReader{messages: []Message{updated, invalid, deleted}}
+	subscriber := NewSubscriber(reader, config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handled := make([]models.EventType, 0)
+	subscriber.Subscribe(models.EventProductUpdated, func(event *models.Event) {
+		handled = append(handled, event.Type)
+	})
+	subscriber.Subscribe(models.EventProductDeleted, func(event *models.Event) {
+		handled = append(handled, event.Type)
+		cancel()
+	})
+
+	assert.NoError(t, subscriber.Run(ctx))
+	assert.Equal(t, []models.EventType{models.EventProductUpdated, models.EventProductDeleted}, handled)
+	// The invalid message is committed so it does not block the partition
+	assert.Len(t, reader.committed, 3)
+}
+
+func TestSubscriberUnsubscribe(t *testing.T) {
+	subscriber := NewSubscriber(&fakeReader{}, Config{})
+	calls := 0
+	handler := func(event *models.Event) { calls++ }
+	subscriber.Subscribe(models.EventProductUpdated, handler)
+	subscriber.Unsubscribe(models.EventProductUpdated, handler)
+
+	subscriber.handle(createTestEvent(models.EventProductUpdated))
+	assert.Zero(t, calls)
+}
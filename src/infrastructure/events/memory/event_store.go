@@ -1,94 +1,273 @@
 package memory
 
 import (
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/jimmitjoo/ecom/src/domain/models"
 )
 
-// MemoryEventStore implements an in-memory event store
+// DefaultSnapshotInterval is the number of versions between the snapshots
+// NewMemoryEventStore takes
+const DefaultSnapshotInterval = 100
+
+// MemoryEventStore implements an in-memory event store.
+// Events are kept in per-entity append-only slices indexed by entity ID, so
+// replaying one entity costs O(events of entity) rather than O(all events).
+// A global append-only log keeps the storage order for time-based scans.
+//
+// Every event carries the full product state, so a snapshot is the state of
+// one event together with its position in the entity's slice. Reads from a
+// version start at the latest snapshot at or before it instead of the first
+// event, and Compact drops the events before each entity's latest snapshot.
 type MemoryEventStore struct {
-	events []*models.Event
-	mu     sync.RWMutex
+	entities map[string]*entityEvents
+	log      []*models.Event // all events in storage order
+	interval int64           // versions between automatic snapshots, 0 for none
+	mu       sync.RWMutex
+}
+
+// entityEvents holds the events kept for one entity and its snapshots
+type entityEvents struct {
+	events     []*models.Event // in storage order, from the first event not compacted
+	dropped    int             // events removed from the front by compaction
+	maxVersion int64
+	snapshots  []snapshot // in version order
+}
+
+// snapshot is the state of an entity at a version. position counts every event
+// stored for the entity before the snapshot's event, including dropped ones.
+type snapshot struct {
+	product  *models.Product
+	version  int64
+	position int
 }
 
-// NewMemoryEventStore creates a new in-memory event store
+// NewMemoryEventStore creates a new in-memory event store that takes a
+// snapshot every DefaultSnapshotInterval versions
 func NewMemoryEventStore() *MemoryEventStore {
+	return NewMemoryEventStoreWithSnapshots(DefaultSnapshotInterval)
+}
+
+// NewMemoryEventStoreWithSnapshots creates an in-memory event store that takes
+// a snapshot every interval versions per entity; 0 or less takes none
+func NewMemoryEventStoreWithSnapshots(interval int64) *MemoryEventStore {
+	if interval < 0 {
+		interval = 0
+	}
 	return &MemoryEventStore{
-		events: make([]*models.Event, 0),
+		entities: make(map[string]*entityEvents),
+		log:      make([]*models.Event, 0),
+		interval: interval,
 	}
 }
 
-// StoreEvent stores an event in memory
-func (s *MemoryEventStore) StoreEvent(event *models.Event) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	// Create a deep copy of the event before storing it
+// copyEvent creates a deep copy of an event so callers cannot modify stored data
+func copyEvent(event *models.Event) *models.Event {
 	eventCopy := *event
 	if productEvent, ok := event.Data.(*models.ProductEvent); ok {
 		productEventCopy := *productEvent
-		productEventCopy.Product = productEvent.Product.Clone()
+		if productEvent.Product != nil {
+			productEventCopy.Product = productEvent.Product.Clone()
+		}
 		eventCopy.Data = &productEventCopy
 	}
+	return &eventCopy
+}
 
-	s.events = append(s.events, &eventCopy)
+// StoreEvent stores an event in memory and snapshots the entity when the
+// event is the snapshot interval past its latest snapshot
+func (s *MemoryEventStore) StoreEvent(event *models.Event) error {
+	// Create a deep copy of the event before storing it
+	eventCopy := copyEvent(event)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entity := s.entity(event.EntityID)
+	position := entity.dropped + len(entity.events)
+	entity.events = append(entity.events, eventCopy)
+	s.log = append(s.log, eventCopy)
+
+	// Only an event newer than every stored one can be a snapshot, so no
+	// event before its position has a later version
+	if eventCopy.Version <= entity.maxVersion {
+		return nil
+	}
+	entity.maxVersion = eventCopy.Version
+	if s.interval > 0 && eventCopy.Version-entity.latestSnapshotVersion() >= s.interval {
+		if product := snapshotState(eventCopy); product != nil {
+			entity.snapshots = append(entity.snapshots, snapshot{product: product, version: eventCopy.Version, position: position})
+		}
+	}
 	return nil
 }
 
-// GetEvents returns all events for a specific entity from a given version
+// GetEvents returns all events for a specific entity from a given version.
+// The scan starts at the latest snapshot at or before the version.
 func (s *MemoryEventStore) GetEvents(entityID string, fromVersion int64) ([]*models.Event, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	entity, exists := s.entities[entityID]
+	if !exists {
+		return nil, nil
+	}
+
 	var filteredEvents []*models.Event
-	for _, event := range s.events {
-		if event.EntityID == entityID && event.Version >= fromVersion {
-			// Create a deep copy of the event before storing it
-			eventCopy := *event
-			if productEvent, ok := event.Data.(*models.ProductEvent); ok {
-				productEventCopy := *productEvent
-				productEventCopy.Product = productEvent.Product.Clone()
-				eventCopy.Data = &productEventCopy
-			}
-			filteredEvents = append(filteredEvents, &eventCopy)
+	for _, event := range entity.events[entity.startFor(fromVersion):] {
+		if event.Version >= fromVersion {
+			filteredEvents = append(filteredEvents, copyEvent(event))
+		}
+	}
+
+	return filteredEvents, nil
+}
+
+// GetEventsUntil returns all events recorded at or before the given time in storage order
+func (s *MemoryEventStore) GetEventsUntil(until time.Time) ([]*models.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	filteredEvents := make([]*models.Event, 0)
+	for _, event := range s.log {
+		if !event.Timestamp.After(until) {
+			filteredEvents = append(filteredEvents, copyEvent(event))
 		}
 	}
 
 	return filteredEvents, nil
 }
 
-// GetSnapshot returns the latest snapshot for an entity
+// GetSnapshot returns the latest snapshot for an entity and its version, or
+// nil and 0 if the entity has none
 func (s *MemoryEventStore) GetSnapshot(entityID string) (*models.Product, int64, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var latestEvent *models.Event
-	var latestVersion int64
+	entity, exists := s.entities[entityID]
+	if !exists || len(entity.snapshots) == 0 {
+		return nil, 0, nil
+	}
+	latest := entity.snapshots[len(entity.snapshots)-1]
+	return latest.product.Clone(), latest.version, nil
+}
+
+// CreateSnapshot stores a snapshot of the entity state at a version that has
+// a stored event, in addition to the automatic ones
+func (s *MemoryEventStore) CreateSnapshot(entityID string, product *models.Product, version int64) error {
+	if product == nil {
+		return fmt.Errorf("snapshot of %s has no product", entityID)
+	}
 
-	// Find the latest event for this entity
-	for _, event := range s.events {
-		if event.EntityID == entityID && event.Version > latestVersion {
-			latestEvent = event
-			latestVersion = event.Version
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entity, exists := s.entities[entityID]
+	if !exists {
+		return fmt.Errorf("no events stored for %s", entityID)
+	}
+	if version <= entity.latestSnapshotVersion() {
+		return nil
+	}
+	// Reads from the snapshot skip the events before it, so none of them may
+	// have a later version
+	position := -1
+	for i, event := range entity.events {
+		if event.Version == version {
+			position = entity.dropped + i
+			break
+		}
+		if event.Version > version {
+			return fmt.Errorf("version %d of %s was stored after a later version", version, entityID)
 		}
 	}
+	if position < 0 {
+		return fmt.Errorf("no event for version %d of %s", version, entityID)
+	}
+	entity.snapshots = append(entity.snapshots, snapshot{product: product.Clone(), version: version, position: position})
+	return nil
+}
 
-	if latestEvent == nil {
-		return nil, 0, nil
+// Compact drops the events stored before each entity's latest snapshot and
+// returns how many were dropped. The snapshot's own event is kept, so the
+// remaining events still form a hash chain; history before it is gone.
+func (s *MemoryEventStore) Compact() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dropped := make(map[*models.Event]bool)
+	for _, entity := range s.entities {
+		if len(entity.snapshots) == 0 {
+			continue
+		}
+		latest := entity.snapshots[len(entity.snapshots)-1]
+		cut := latest.position - entity.dropped
+		if cut <= 0 {
+			continue
+		}
+		for _, event := range entity.events[:cut] {
+			dropped[event] = true
+		}
+		entity.events = append([]*models.Event(nil), entity.events[cut:]...)
+		entity.dropped += cut
+		entity.snapshots = []snapshot{latest}
+	}
+	if len(dropped) == 0 {
+		return 0
 	}
 
-	// Convert event data to product
-	if productEvent, ok := latestEvent.Data.(*models.ProductEvent); ok {
-		return productEvent.Product, latestEvent.Version, nil
+	log := make([]*models.Event, 0, len(s.log)-len(dropped))
+	for _, event := range s.log {
+		if !dropped[event] {
+			log = append(log, event)
+		}
 	}
+	s.log = log
+	return len(dropped)
+}
 
-	return nil, 0, nil
+// entity returns the events of an entity, creating them on first use
+func (s *MemoryEventStore) entity(entityID string) *entityEvents {
+	entity, exists := s.entities[entityID]
+	if !exists {
+		entity = &entityEvents{}
+		s.entities[entityID] = entity
+	}
+	return entity
 }
 
-// CreateSnapshot stores a snapshot of the entity state
-func (s *MemoryEventStore) CreateSnapshot(entityID string, product *models.Product, version int64) error {
-	// In a memory implementation, we don't need to save snapshots
-	// because we always have all events available
-	return nil
+// latestSnapshotVersion returns the version of the latest snapshot, 0 without one
+func (e *entityEvents) latestSnapshotVersion() int64 {
+	if len(e.snapshots) == 0 {
+		return 0
+	}
+	return e.snapshots[len(e.snapshots)-1].version
+}
+
+// startFor returns the index in events to scan from for events at or after a
+// version: the position of the latest snapshot at or before the version
+func (e *entityEvents) startFor(fromVersion int64) int {
+	start := 0
+	for i := len(e.snapshots) - 1; i >= 0; i-- {
+		if e.snapshots[i].version <= fromVersion {
+			start = e.snapshots[i].position - e.dropped
+			break
+		}
+	}
+	if start < 0 {
+		return 0
+	}
+	return start
+}
+
+// snapshotState returns the product state an event recorded, or nil for
+// events without one of their own such as deletions
+func snapshotState(event *models.Event) *models.Product {
+	productEvent, ok := event.Data.(*models.ProductEvent)
+	if !ok || productEvent.Product == nil || event.Type == models.EventProductDeleted {
+		return nil
+	}
+	return productEvent.Product
 }
@@ -170,3 +170,216 @@ func TestConcurrentAccess(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, events, 10)
 }
+
+func TestGetEventsUntil(t *testing.T) {
+	store := NewMemoryEventStore()
+	start := time.Now()
+
+	for i, offset := range []time.Duration{0, time.Minute, 2 * time.Minute} {
+		event := createTestEvent("prod_1", int64(i+1), models.EventProductUpdated, "")
+		event.Timestamp = start.Add(offset)
+		assert.NoError(t, store.StoreEvent(event))
+	}
+
+	events, err := store.GetEventsUntil(start.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(1), events[0].Version)
+	assert.Equal(t, int64(2), events[1].Version)
+
+	events, err = store.GetEventsUntil(start.Add(-time.Second))
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestGetEventsOnlyReturnsEntity(t *testing.T) {
+	store := NewMemoryEventStore()
+
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, store.StoreEvent(createTestEvent("prod_a", int64(i), models.EventProductUpdated, "")))
+		assert.NoError(t, store.StoreEvent(createTestEvent("prod_b", int64(i), models.EventProductUpdated, "")))
+	}
+
+	events, err := store.GetEvents("prod_a", 2)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	for _, event := range events {
+		assert.Equal(t, "prod_a", event.EntityID)
+	}
+
+	events, err = store.GetEvents("prod_missing", 1)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	assert.NoError(t, store.CreateSnapshot("prod_b", createTestEvent("prod_b", 3, models.EventProductUpdated, "").Data.(*models.ProductEvent).Product, 3))
+	snapshot, version, err := store.GetSnapshot("prod_b")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+	assert.Equal(t, "prod_b", snapshot.ID)
+
+	snapshot, version, err = store.GetSnapshot("prod_a")
+	assert.NoError(t, err)
+	assert.Nil(t, snapshot)
+	assert.Zero(t, version)
+}
+
+func TestStoreEventTakesSnapshots(t *testing.T) {
+	store := NewMemoryEventStoreWithSnapshots(3)
+
+	for v := int64(1); v <= 7; v++ {
+		assert.NoError(t, store.StoreEvent(createTestEvent("prod_1", v, models.EventProductUpdated, "")))
+	}
+
+	snapshot, version, err := store.GetSnapshot("prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), version)
+	assert.Equal(t, int64(6), snapshot.Version)
+
+	// The snapshot is a copy
+	snapshot.BaseTitle = "Modified Title"
+	snapshot, _, _ = store.GetSnapshot("prod_1")
+	assert.Equal(t, "Test Product", snapshot.BaseTitle)
+
+	// Deletions carry no state of their own and are not snapshotted
+	assert.NoError(t, store.StoreEvent(createTestEvent("prod_1", 9, models.EventProductDeleted, "")))
+	_, version, _ = store.GetSnapshot("prod_1")
+	assert.Equal(t, int64(6), version)
+
+	store = NewMemoryEventStoreWithSnapshots(0)
+	for v := int64(1); v <= 7; v++ {
+		assert.NoError(t, store.StoreEvent(createTestEvent("prod_1", v, models.EventProductUpdated, "")))
+	}
+	snapshot, _, _ = store.GetSnapshot("prod_1")
+	assert.Nil(t, snapshot)
+}
+
+func TestGetEventsFromSnapshot(t *testing.T) {
+	store := NewMemoryEventStoreWithSnapshots(2)
+
+	// Version 2 is stored after version 3, so no snapshot may be taken at it:
+	// reads from it would skip version 3
+	for _, v := range []int64{1, 3, 2, 4, 5, 6} {
+		assert.NoError(t, store.StoreEvent(createTestEvent("prod_1", v, models.EventProductUpdated, "")))
+	}
+
+	for from := int64(1); from <= 7; from++ {
+		events, err := store.GetEvents("prod_1", from)
+		assert.NoError(t, err)
+		for _, event := range events {
+			assert.GreaterOrEqual(t, event.Version, from)
+		}
+		assert.Len(t, events, int(max(0, 7-from)), "For fromVersion %d", from)
+	}
+}
+
+func TestCreateSnapshot(t *testing.T) {
+	store := NewMemoryEventStoreWithSnapshots(0)
+	for _, v := range []int64{1, 3, 2} {
+		assert.NoError(t, store.StoreEvent(createTestEvent("prod_1", v, models.EventProductUpdated, "")))
+	}
+	product := createTestEvent("prod_1", 2, models.EventProductUpdated, "").Data.(*models.ProductEvent).Product
+
+	assert.Error(t, store.CreateSnapshot("prod_missing", product, 1))
+	assert.Error(t, store.CreateSnapshot("prod_1", nil, 1))
+	assert.Error(t, store.CreateSnapshot("prod_1", product, 4))
+	// Version 2 was stored after version 3, so reads from it could not skip
+	// the events before it
+	assert.Error(t, store.CreateSnapshot("prod_1", product, 2))
+
+	assert.NoError(t, store.CreateSnapshot("prod_1", product, 3))
+	_, version, _ := store.GetSnapshot("prod_1")
+	assert.Equal(t, int64(3), version)
+
+	// Older versions than the latest snapshot are already covered
+	assert.NoError(t, store.CreateSnapshot("prod_1", product, 1))
+	_, version, _ = store.GetSnapshot("prod_1")
+	assert.Equal(t, int64(3), version)
+}
+
+func TestCompact(t *testing.T) {
+	store := NewMemoryEventStoreWithSnapshots(4)
+	prevHash := ""
+	for v := int64(1); v <= 10; v++ {
+		event := createTestEvent("prod_1", v, models.EventProductUpdated, prevHash)
+		prevHash = event.Data.(*models.ProductEvent).Product.LastHash
+		assert.NoError(t, store.StoreEvent(event))
+	}
+	for v := int64(1); v <= 2; v++ {
+		assert.NoError(t, store.StoreEvent(createTestEvent("prod_2", v, models.EventProductUpdated, "")))
+	}
+
+	// prod_1 is snapshotted at versions 4 and 8; prod_2 has no snapshot
+	assert.Equal(t, 7, store.Compact())
+	assert.Zero(t, store.Compact())
+
+	events, err := store.GetEvents("prod_1", 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, int64(8), events[0].Version)
+	// The kept events still chain onto the snapshot's event
+	for i := 1; i < len(events); i++ {
+		assert.Equal(t, events[i-1].Data.(*models.ProductEvent).Product.LastHash, events[i].Data.(*models.ProductEvent).PrevHash)
+	}
+
+	events, err = store.GetEvents("prod_2", 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+
+	all, err := store.GetEventsUntil(time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, all, 5)
+
+	// Snapshots keep working on the compacted events
+	for v := int64(11); v <= 12; v++ {
+		assert.NoError(t, store.StoreEvent(createTestEvent("prod_1", v, models.EventProductUpdated, "")))
+	}
+	_, version, _ := store.GetSnapshot("prod_1")
+	assert.Equal(t, int64(12), version)
+	events, _ = store.GetEvents("prod_1", 12)
+	assert.Len(t, events, 1)
+	assert.Equal(t, 4, store.Compact())
+	events, _ = store.GetEvents("prod_1", 1)
+	assert.Len(t, events, 1)
+}
+
+// benchmarkGetEvents replays one entity with 10 events while the store holds
+// the given number of events for other entities
+func benchmarkGetEvents(b *testing.B, otherEvents int) {
+	store := NewMemoryEventStore()
+	for i := 0; i < otherEvents; i++ {
+		store.StoreEvent(createTestEvent(fmt.Sprintf("other_%d", i%1000), int64(i/1000+1), models.EventProductUpdated, ""))
+	}
+	for v := int64(1); v <= 10; v++ {
+		store.StoreEvent(createTestEvent("target", v, models.EventProductUpdated, ""))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if events, _ := store.GetEvents("target", 1); len(events) != 10 {
+			b.Fatalf("expected 10 events, got %d", len(events))
+		}
+	}
+}
+
+func BenchmarkGetEvents1K(b *testing.B)   { benchmarkGetEvents(b, 1_000) }
+func BenchmarkGetEvents10K(b *testing.B)  { benchmarkGetEvents(b, 10_000) }
+func BenchmarkGetEvents100K(b *testing.B) { benchmarkGetEvents(b, 100_000) }
+
+// benchmarkGetLatestEvents reads the events after the latest version of one
+// entity with the given number of versions
+func benchmarkGetLatestEvents(b *testing.B, versions int64) {
+	store := NewMemoryEventStore()
+	for v := int64(1); v <= versions; v++ {
+		store.StoreEvent(createTestEvent("target", v, models.EventProductUpdated, ""))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if events, _ := store.GetEvents("target", versions); len(events) != 1 {
+			b.Fatalf("expected 1 event, got %d", len(events))
+		}
+	}
+}
+
+func BenchmarkGetLatestEvents1K(b *testing.B)  { benchmarkGetLatestEvents(b, 1_000) }
+func BenchmarkGetLatestEvents10K(b *testing.B) { benchmarkGetLatestEvents(b, 10_000) }
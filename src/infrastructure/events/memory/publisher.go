@@ -34,6 +34,19 @@ func (p *MemoryEventPublisher) Publish(event *models.Event) error {
 	return nil
 }
 
+// PublishBatch sends each event to the handlers registered for its type
+func (p *MemoryEventPublisher) PublishBatch(events []*models.Event) []error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, event := range events {
+		for _, handler := range p.handlers[event.Type] {
+			go handler(event)
+		}
+	}
+	return make([]error, len(events))
+}
+
 // Subscribe registers a new handler for a specific event type
 func (p *MemoryEventPublisher) Subscribe(eventType models.EventType, handler func(*models.Event)) error {
 	p.mu.Lock()
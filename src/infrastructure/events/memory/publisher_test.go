@@ -395,3 +395,36 @@ func TestConcurrentSubscribeUnsubscribe(t *testing.T) {
 	assert.True(t, totalEvents >= 0, "Should handle concurrent subscribe/unsubscribe safely")
 	mu.Unlock()
 }
+
+func TestPublishBatch(t *testing.T) {
+	publisher := NewMemoryEventPublisher()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	received := make(map[string]bool)
+
+	handler := func(event *models.Event) {
+		mu.Lock()
+		received[event.ID] = true
+		mu.Unlock()
+		wg.Done()
+	}
+	assert.NoError(t, publisher.Subscribe(models.EventProductCreated, handler))
+	assert.NoError(t, publisher.Subscribe(models.EventProductUpdated, handler))
+
+	created := createTestProductEvent()
+	updated := createTestProductEvent()
+	updated.ID = "test_event_2"
+	updated.Type = models.EventProductUpdated
+	deleted := createTestProductEvent()
+	deleted.ID = "test_event_3"
+	deleted.Type = models.EventProductDeleted // no subscribers
+
+	wg.Add(2)
+	errs := publisher.PublishBatch([]*models.Event{created, updated, deleted})
+	assert.Equal(t, []error{nil, nil, nil}, errs)
+
+	wg.Wait()
+	mu.Lock()
+	assert.Equal(t, map[string]bool{"test_event_1": true, "test_event_2": true}, received)
+	mu.Unlock()
+}
@@ -0,0 +1,231 @@
+package outbox
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+// Options configures the relay of a Publisher; zero values use the defaults
+type Options struct {
+	PollInterval time.Duration // How often entries waiting for a retry are checked, default 1s
+	MaxAttempts  int           // Attempts before an event is marked failed, default 10
+	BaseBackoff  time.Duration // Wait after the first failed attempt, doubled per attempt, default 1s
+	MaxBackoff   time.Duration // Longest wait between attempts, default 5m
+	Retention    time.Duration // How long delivered entries are kept, default 1h
+}
+
+func (o Options) withDefaults() Options {
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 10
+	}
+	if o.BaseBackoff <= 0 {
+		o.BaseBackoff = time.Second
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 5 * time.Minute
+	}
+	if o.Retention <= 0 {
+		o.Retention = time.Hour
+	}
+	return o
+}
+
+// Publisher is an event publisher that relays events from an outbox instead
+// of publishing them in the caller. Product repositories implementing
+// repositories.OutboxStore commit stored events to the outbox with the write
+// storing them; Publish adds other events and only fails when the outbox
+// cannot store them. A relay goroutine publishes the pending events to the
+// inner publisher in order, retries failed attempts with a doubling backoff
+// and marks the events delivered. A product's events are never published out
+// of order: while one waits for a retry, the later ones wait with it.
+type Publisher struct {
+	store   repositories.OutboxRepository
+	inner   events.EventPublisher
+	options Options
+
+	relayMu  sync.Mutex // Serializes relay passes
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPublisher wraps a publisher with an outbox kept in the given repository
+func NewPublisher(store repositories.OutboxRepository, inner events.EventPublisher, options Options) *Publisher {
+	return &Publisher{
+		store:   store,
+		inner:   inner,
+		options: options.withDefaults(),
+		wake:    make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Publish commits the event to the outbox, unless its write already did,
+// and wakes the relay
+func (p *Publisher) Publish(event *models.Event) error {
+	if err := p.store.Add(event); err != nil {
+		return err
+	}
+	p.notify()
+	return nil
+}
+
+// PublishBatch commits the events their writes did not commit to the outbox
+// together and wakes the relay
+func (p *Publisher) PublishBatch(events []*models.Event) []error {
+	errs := make([]error, len(events))
+	if err := p.store.Add(events...); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	p.notify()
+	return errs
+}
+
+// Subscribe registers a handler on the inner publisher
+func (p *Publisher) Subscribe(eventType models.EventType, handler func(*models.Event)) error {
+	return p.inner.Subscribe(eventType, handler)
+}
+
+// Unsubscribe removes a handler from the inner publisher
+func (p *Publisher) Unsubscribe(eventType models.EventType, handler func(*models.Event)) error {
+	return p.inner.Unsubscribe(eventType, handler)
+}
+
+// Start runs the relay until Stop. It publishes events as soon as they are
+// committed and checks for retries every poll interval, starting with the
+// events left pending from before.
+func (p *Publisher) Start() {
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(p.options.PollInterval)
+		defer ticker.Stop()
+
+		p.Relay()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-p.wake:
+				p.Relay()
+			case <-ticker.C:
+				p.Relay()
+			}
+		}
+	}()
+}
+
+// Stop ends the relay after a last pass over the due events. Events still
+// pending stay in the outbox.
+func (p *Publisher) Stop() {
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.done
+	p.Relay()
+}
+
+// Relay publishes the due pending events once, oldest first, and returns
+// how many were delivered
+func (p *Publisher) Relay() int {
+	p.relayMu.Lock()
+	defer p.relayMu.Unlock()
+
+	logger := logging.Shared()
+	pending, err := p.store.Pending(0)
+	if err != nil {
+		logger.Error("Failed to read the event outbox", zap.Error(err))
+		return 0
+	}
+
+	now := time.Now()
+	delivered := 0
+	blocked := make(map[string]bool) // Entities with an earlier event still pending
+	for _, entry := range pending {
+		entityID := entry.Event.EntityID
+		if blocked[entityID] {
+			continue
+		}
+		if entry.NextAttempt.After(now) {
+			blocked[entityID] = true
+			continue
+		}
+
+		if err := p.inner.Publish(entry.Event); err != nil {
+			blocked[entityID] = true
+			p.failed(entry, err)
+			continue
+		}
+		if err := p.store.MarkDelivered(entry.Event.ID, time.Now()); err != nil {
+			logger.Error("Failed to mark outbox event delivered", zap.String("event_id", entry.Event.ID), zap.Error(err))
+		}
+		metrics.OutboxPublishAttempts.WithLabelValues("delivered").Inc()
+		delivered++
+	}
+
+	if _, err := p.store.Prune(now.Add(-p.options.Retention)); err != nil {
+		logger.Error("Failed to prune the event outbox", zap.Error(err))
+	}
+	if stats, err := p.store.Stats(); err == nil {
+		metrics.OutboxPendingEvents.Set(float64(stats.Pending))
+	}
+	return delivered
+}
+
+// failed records a failed attempt and schedules the retry, or gives the event
+// up after the last attempt
+func (p *Publisher) failed(entry *models.OutboxEntry, err error) {
+	attempts := entry.Attempts + 1
+	logger := logging.Shared().WithFields(
+		zap.String("event_id", entry.Event.ID),
+		zap.String("entity_id", entry.Event.EntityID),
+		zap.Int("attempts", attempts),
+		zap.Error(err),
+	)
+
+	var retryAt time.Time
+	if attempts < p.options.MaxAttempts {
+		retryAt = time.Now().Add(p.backoff(attempts))
+		metrics.OutboxPublishAttempts.WithLabelValues("retried").Inc()
+		logger.Warn("Failed to publish outbox event, retrying", zap.Time("retry_at", retryAt))
+	} else {
+		metrics.OutboxPublishAttempts.WithLabelValues("failed").Inc()
+		logger.Error("Gave up publishing outbox event")
+	}
+	if err := p.store.MarkAttemptFailed(entry.Event.ID, err.Error(), retryAt); err != nil {
+		logger.Error("Failed to record outbox attempt", zap.NamedError("record_error", err))
+	}
+}
+
+// backoff returns the wait after the given number of failed attempts
+func (p *Publisher) backoff(attempts int) time.Duration {
+	wait := p.options.BaseBackoff
+	for i := 1; i < attempts && wait < p.options.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > p.options.MaxBackoff {
+		return p.options.MaxBackoff
+	}
+	return wait
+}
+
+// notify wakes the relay without blocking when a wake-up is already queued
+func (p *Publisher) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
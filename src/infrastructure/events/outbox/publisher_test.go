@@ -0,0 +1,146 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	eventsMemory "github.com/jimmitjoo/ecom/src/infrastructure/events/memory"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyPublisher fails the first attempts at each event, as many as failures,
+// and records the events it published
+type flakyPublisher struct {
+	events.EventPublisher
+	failures  int
+	mu        sync.Mutex
+	attempts  map[string]int
+	published []string
+}
+
+func newFlakyPublisher(failures int) *flakyPublisher {
+	return &flakyPublisher{
+		EventPublisher: eventsMemory.NewMemoryEventPublisher(),
+		failures:       failures,
+		attempts:       make(map[string]int),
+	}
+}
+
+func (p *flakyPublisher) Publish(event *models.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts[event.ID]++
+	if p.attempts[event.ID] <= p.failures {
+		return errors.New("broker down")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+func (p *flakyPublisher) publishedIDs() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.published...)
+}
+
+func testEvent(id, entityID string) *models.Event {
+	return &models.Event{ID: id, Type: models.EventProductUpdated, EntityID: entityID, Timestamp: time.Now()}
+}
+
+func TestPublisherRelaysCommittedEvents(t *testing.T) {
+	store := memory.NewOutboxRepository()
+	inner := newFlakyPublisher(0)
+	publisher := NewPublisher(store, inner, Options{})
+
+	assert.NoError(t, publisher.Publish(testEvent("evt_1", "prod_1")))
+	assert.Empty(t, publisher.PublishBatch([]*models.Event{testEvent("evt_2", "prod_1"), testEvent("evt_3", "prod_2")})[0])
+	assert.Empty(t, inner.publishedIDs())
+
+	publisher.Start()
+	assert.Eventually(t, func() bool { return len(inner.publishedIDs()) == 3 }, time.Second, time.Millisecond)
+	publisher.Stop()
+	assert.Equal(t, []string{"evt_1", "evt_2", "evt_3"}, inner.publishedIDs())
+
+	stats, _ := store.Stats()
+	assert.Equal(t, models.OutboxStats{Delivered: 3}, stats)
+}
+
+func TestPublisherRelaysEventsCommittedWithTheirWrite(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewShardedProductRepository(4)
+	inner := newFlakyPublisher(0)
+	publisher := NewPublisher(repo.Outbox(), inner, Options{})
+
+	// A write whose transaction failed leaves nothing to publish, and a
+	// committed one is relayed even when the caller never publishes it
+	failed := errors.New("failed")
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		assert.NoError(t, tx.StoreEvent(ctx, testEvent("evt_failed", "prod_1")))
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	assert.NoError(t, repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		return tx.StoreEvent(ctx, testEvent("evt_1", "prod_1"))
+	}))
+	assert.Equal(t, 1, publisher.Relay())
+
+	// Publishing the event after its write does not queue it again
+	assert.NoError(t, publisher.Publish(testEvent("evt_1", "prod_1")))
+	assert.Zero(t, publisher.Relay())
+	assert.Equal(t, []string{"evt_1"}, inner.publishedIDs())
+}
+
+func TestPublisherRetriesInOrder(t *testing.T) {
+	store := memory.NewOutboxRepository()
+	inner := newFlakyPublisher(1)
+	publisher := NewPublisher(store, inner, Options{BaseBackoff: time.Millisecond})
+
+	assert.NoError(t, publisher.Publish(testEvent("evt_1", "prod_1")))
+	assert.NoError(t, publisher.Publish(testEvent("evt_2", "prod_1")))
+
+	// The first event fails, and the second waits for it
+	assert.Zero(t, publisher.Relay())
+	pending, _ := store.Pending(0)
+	assert.Len(t, pending, 2)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "broker down", pending[0].LastError)
+	assert.Zero(t, pending[1].Attempts)
+
+	time.Sleep(2 * time.Millisecond)
+	assert.Equal(t, 1, publisher.Relay())
+	time.Sleep(2 * time.Millisecond)
+	assert.Equal(t, 1, publisher.Relay())
+	assert.Equal(t, []string{"evt_1", "evt_2"}, inner.publishedIDs())
+}
+
+func TestPublisherGivesUpAfterMaxAttempts(t *testing.T) {
+	store := memory.NewOutboxRepository()
+	inner := newFlakyPublisher(5)
+	publisher := NewPublisher(store, inner, Options{MaxAttempts: 2, BaseBackoff: time.Millisecond})
+
+	assert.NoError(t, publisher.Publish(testEvent("evt_1", "prod_1")))
+	publisher.Relay()
+	time.Sleep(2 * time.Millisecond)
+	publisher.Relay()
+
+	stats, _ := store.Stats()
+	assert.Equal(t, models.OutboxStats{Failed: 1}, stats)
+	assert.Empty(t, inner.publishedIDs())
+}
+
+func TestPublisherBackoff(t *testing.T) {
+	publisher := NewPublisher(memory.NewOutboxRepository(), newFlakyPublisher(0), Options{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	assert.Equal(t, time.Second, publisher.backoff(1))
+	assert.Equal(t, 2*time.Second, publisher.backoff(2))
+	assert.Equal(t, 4*time.Second, publisher.backoff(3))
+	assert.Equal(t, 5*time.Second, publisher.backoff(4))
+	assert.Equal(t, 5*time.Second, publisher.backoff(40))
+}
@@ -0,0 +1,167 @@
+package tracking
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+// RateLimit caps how many events per second are delivered to one consumer.
+// Burst deliveries may go out back to back after the consumer was idle.
+type RateLimit struct {
+	PerSecond float64
+	Burst     int
+}
+
+// ParseRateLimits parses a list of per-consumer limits such as
+// "marketplaces:5,websocket:200:50", i.e. name:per_second[:burst]
+func ParseRateLimits(value string) (map[string]RateLimit, error) {
+	limits := make(map[string]RateLimit)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid rate limit %q, expected name:per_second[:burst]", entry)
+		}
+		perSecond, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || perSecond <= 0 {
+			return nil, fmt.Errorf("invalid rate %q for consumer %s", parts[1], parts[0])
+		}
+		limit := RateLimit{PerSecond: perSecond, Burst: 1}
+		if len(parts) == 3 {
+			if limit.Burst, err = strconv.Atoi(parts[2]); err != nil || limit.Burst < 1 {
+				return nil, fmt.Errorf("invalid burst %q for consumer %s", parts[2], parts[0])
+			}
+		}
+		limits[parts[0]] = limit
+	}
+	return limits, nil
+}
+
+// throttle is the delivery queue of one rate-limited consumer. Deliveries are
+// queued without blocking the publisher and sent by the throttle's own worker,
+// so a consumer with a low limit only ever delays itself.
+type throttle struct {
+	name string
+
+	mu    sync.Mutex
+	limit RateLimit
+	queue []queuedDelivery
+	wake  chan struct{}
+
+	// Token bucket, only used by the worker
+	tokens float64
+	last   time.Time
+}
+
+// queuedDelivery is one handler call waiting for its turn
+type queuedDelivery struct {
+	deliver  func()
+	queuedAt time.Time
+}
+
+// newThrottle creates the queue of a consumer and starts its worker
+func newThrottle(name string, limit RateLimit) *throttle {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	t := &throttle{
+		name:   name,
+		limit:  limit,
+		wake:   make(chan struct{}, 1),
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+	}
+	go t.run()
+	return t
+}
+
+// setLimit changes the limit; queued deliveries go out at the new rate
+func (t *throttle) setLimit(limit RateLimit) {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	t.mu.Lock()
+	t.limit = limit
+	t.mu.Unlock()
+}
+
+// enqueue queues a delivery and returns right away
+func (t *throttle) enqueue(deliver func()) {
+	t.mu.Lock()
+	t.queue = append(t.queue, queuedDelivery{deliver: deliver, queuedAt: time.Now()})
+	queued := len(t.queue)
+	t.mu.Unlock()
+
+	metrics.EventConsumerQueued.WithLabelValues(t.name).Set(float64(queued))
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// queued returns the number of deliveries waiting
+func (t *throttle) queued() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.queue)
+}
+
+// rateLimit returns the current limit
+func (t *throttle) rateLimit() RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.limit
+}
+
+// run delivers queued events in order, at most at the consumer's rate
+func (t *throttle) run() {
+	for {
+		t.mu.Lock()
+		if len(t.queue) == 0 {
+			t.mu.Unlock()
+			<-t.wake
+			continue
+		}
+		limit := t.limit
+		t.mu.Unlock()
+
+		t.take(limit)
+
+		t.mu.Lock()
+		next := t.queue[0]
+		t.queue[0] = queuedDelivery{}
+		t.queue = t.queue[1:]
+		queued := len(t.queue)
+		t.mu.Unlock()
+
+		metrics.EventConsumerQueued.WithLabelValues(t.name).Set(float64(queued))
+		metrics.EventConsumerQueueWait.WithLabelValues(t.name).Observe(time.Since(next.queuedAt).Seconds())
+		next.deliver()
+	}
+}
+
+// take waits until the bucket holds a token and consumes it
+func (t *throttle) take(limit RateLimit) {
+	now := time.Now()
+	t.tokens += now.Sub(t.last).Seconds() * limit.PerSecond
+	if burst := float64(limit.Burst); t.tokens > burst {
+		t.tokens = burst
+	}
+	t.last = now
+
+	if t.tokens < 1 {
+		delay := time.Duration((1 - t.tokens) / limit.PerSecond * float64(time.Second))
+		time.Sleep(delay)
+		t.last = t.last.Add(delay)
+		t.tokens = 1
+	}
+	t.tokens--
+}
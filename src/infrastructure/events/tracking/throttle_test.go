@@ -0,0 +1,125 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	eventsMemory "github.com/jimmitjoo/ecom/src/infrastructure/events/memory"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := ParseRateLimits("marketplaces:5, websocket:200:50,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]RateLimit{
+		"marketplaces": {PerSecond: 5, Burst: 1},
+		"websocket":    {PerSecond: 200, Burst: 50},
+	}, limits)
+
+	limits, err = ParseRateLimits("")
+	assert.NoError(t, err)
+	assert.Empty(t, limits)
+
+	for _, invalid := range []string{"marketplaces", ":5", "marketplaces:fast", "marketplaces:0", "marketplaces:5:0", "a:1:2:3"} {
+		_, err := ParseRateLimits(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestThrottledConsumerDoesNotDelayOthers(t *testing.T) {
+	offsets := memory.NewOffsetRepository()
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), offsets)
+	tracker.SetRateLimit("marketplaces", RateLimit{PerSecond: 20, Burst: 1})
+
+	slow := make(chan time.Time, 5)
+	tracker.Consumer("marketplaces").Subscribe(models.EventProductUpdated, func(event *models.Event) {
+		slow <- time.Now()
+	})
+	fast := make(chan int64, 5)
+	tracker.Consumer("websocket").Subscribe(models.EventProductUpdated, func(event *models.Event) {
+		fast <- event.Sequence
+	})
+
+	start := time.Now()
+	for sequence := int64(1); sequence <= 5; sequence++ {
+		assert.NoError(t, tracker.Publish(testEvent(models.EventProductUpdated, sequence)))
+	}
+
+	// The unthrottled consumer gets everything right away
+	for i := 0; i < 5; i++ {
+		<-fast
+	}
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+
+	// The throttled one is paced at 20 per second: 4 intervals of 50ms after the first
+	var last time.Time
+	for i := 0; i < 5; i++ {
+		last = <-slow
+	}
+	assert.GreaterOrEqual(t, last.Sub(start), 190*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		offset, _ := offsets.Get("marketplaces")
+		return offset == 5
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestConsumerLagsReportQueuedDeliveries(t *testing.T) {
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), memory.NewOffsetRepository())
+	tracker.SetRateLimit("audit", RateLimit{PerSecond: 1})
+
+	delivered := make(chan int64, 3)
+	tracker.Consumer("audit").Subscribe(models.EventProductCreated, func(event *models.Event) {
+		delivered <- event.Sequence
+	})
+
+	tracker.Publish(testEvent(models.EventProductCreated, 1))
+	assert.Equal(t, int64(1), <-delivered)
+	tracker.Publish(testEvent(models.EventProductCreated, 2))
+	tracker.Publish(testEvent(models.EventProductCreated, 3))
+
+	// The first delivery used the burst, the others wait for the limit
+	assert.Eventually(t, func() bool {
+		lag := lagFor(t, tracker, "audit")
+		return lag.Queued == 2 && lag.Committed == 1
+	}, time.Second, 5*time.Millisecond)
+	lag := lagFor(t, tracker, "audit")
+	assert.Equal(t, 1.0, lag.RateLimit)
+	assert.Equal(t, int64(2), lag.Lag)
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.EventConsumerLag.WithLabelValues("audit")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.EventConsumerQueued.WithLabelValues("audit")))
+
+	// Raising the limit drains the queue faster
+	tracker.SetRateLimit("audit", RateLimit{PerSecond: 1000})
+	assert.ElementsMatch(t, []int64{2, 3}, []int64{<-delivered, <-delivered})
+}
+
+func TestThrottledConsumerResumesThroughQueue(t *testing.T) {
+	offsets := memory.NewOffsetRepository()
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), offsets)
+	tracker.SetRateLimit("dashboard", RateLimit{PerSecond: 100, Burst: 1})
+
+	replayed := make(chan int64, 2)
+	tracker.Consumer("dashboard").Subscribe(models.EventProductCreated, func(event *models.Event) {
+		replayed <- event.Sequence
+	})
+
+	deliveries, err := tracker.Resume(context.Background(), &stubEventLog{events: []*models.Event{
+		testEvent(models.EventProductCreated, 1),
+		testEvent(models.EventProductCreated, 2),
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, deliveries)
+	assert.Equal(t, int64(1), <-replayed)
+	assert.Equal(t, int64(2), <-replayed)
+
+	assert.Eventually(t, func() bool {
+		offset, _ := offsets.Get("dashboard")
+		return offset == 2
+	}, time.Second, 5*time.Millisecond)
+}
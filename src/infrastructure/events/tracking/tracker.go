@@ -0,0 +1,457 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+// EventLog is the stored event history a consumer is resumed from
+type EventLog interface {
+	GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error)
+}
+
+// Tracker gives named subscribers at-least-once delivery on top of an event
+// publisher. Events are registered as in flight for every subscribed consumer
+// before they are dispatched, and a consumer's offset only advances past a
+// sequence once every lower in-flight sequence has been handled. After a
+// restart Resume replays the stored events above each committed offset.
+// Consumers with a rate limit get their own delivery queue, so a slow or
+// throttled consumer never holds up the others.
+type Tracker struct {
+	inner   events.EventPublisher
+	offsets repositories.OffsetRepository
+
+	mu        sync.Mutex
+	latest    int64
+	consumers map[string]*consumer
+}
+
+// consumer holds the delivery state of one named subscriber
+type consumer struct {
+	name      string
+	committed int64
+	handled   int64 // highest sequence handled so far
+	inFlight  map[int64]int
+	handlers  map[models.EventType][]func(*models.Event)
+	throttle  *throttle // nil when deliveries are not rate limited
+}
+
+// NewTracker wraps a publisher and stores consumer offsets in the given repository
+func NewTracker(inner events.EventPublisher, offsets repositories.OffsetRepository) *Tracker {
+	return &Tracker{
+		inner:     inner,
+		offsets:   offsets,
+		consumers: make(map[string]*consumer),
+	}
+}
+
+// Publish records the event as in flight for its consumers and dispatches it
+func (t *Tracker) Publish(event *models.Event) error {
+	t.dispatching(event)
+	if err := t.inner.Publish(event); err != nil {
+		t.release(event)
+		return err
+	}
+	return nil
+}
+
+// PublishBatch records every event as in flight and dispatches them together
+func (t *Tracker) PublishBatch(events []*models.Event) []error {
+	for _, event := range events {
+		t.dispatching(event)
+	}
+	errs := t.inner.PublishBatch(events)
+	for i, err := range errs {
+		if err != nil {
+			t.release(events[i])
+		}
+	}
+	return errs
+}
+
+// Subscribe registers an untracked handler on the underlying publisher
+func (t *Tracker) Subscribe(eventType models.EventType, handler func(*models.Event)) error {
+	return t.inner.Subscribe(eventType, handler)
+}
+
+// Unsubscribe removes an untracked handler from the underlying publisher
+func (t *Tracker) Unsubscribe(eventType models.EventType, handler func(*models.Event)) error {
+	return t.inner.Unsubscribe(eventType, handler)
+}
+
+// Consumer returns a publisher whose subscriptions are tracked under the given name
+func (t *Tracker) Consumer(name string) events.EventPublisher {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.consumer(name)
+	return &consumerPublisher{
+		tracker: t,
+		name:    name,
+		wrapped: make(map[subscription]func(*models.Event)),
+	}
+}
+
+// SetRateLimit limits how fast events are delivered to a consumer. Deliveries
+// beyond the limit are queued in order, including replays and redeliveries.
+func (t *Tracker) SetRateLimit(name string, limit RateLimit) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c := t.consumer(name)
+	if c.throttle == nil {
+		c.throttle = newThrottle(name, limit)
+		return
+	}
+	c.throttle.setLimit(limit)
+}
+
+// deliver runs a delivery for a consumer now, or queues it when the consumer is rate limited
+func (t *Tracker) deliver(name string, delivery func()) {
+	t.mu.Lock()
+	var queue *throttle
+	if c, exists := t.consumers[name]; exists {
+		queue = c.throttle
+	}
+	t.mu.Unlock()
+
+	if queue == nil {
+		delivery()
+		return
+	}
+	queue.enqueue(delivery)
+}
+
+// consumer returns the state for a name, loading its committed offset on first use.
+// Callers must hold t.mu.
+func (t *Tracker) consumer(name string) *consumer {
+	c, exists := t.consumers[name]
+	if !exists {
+		committed, _ := t.offsets.Get(name)
+		c = &consumer{
+			name:      name,
+			committed: committed,
+			handled:   committed,
+			inFlight:  make(map[int64]int),
+			handlers:  make(map[models.EventType][]func(*models.Event)),
+		}
+		t.consumers[name] = c
+	}
+	return c
+}
+
+// dispatching marks an event as in flight for every consumer subscribed to its type
+func (t *Tracker) dispatching(event *models.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if event.Sequence > t.latest {
+		t.latest = event.Sequence
+	}
+	for _, c := range t.consumers {
+		if count := len(c.handlers[event.Type]); count > 0 {
+			c.inFlight[event.Sequence] += count
+		}
+		t.recordLag(c)
+	}
+}
+
+// recordLag exports a consumer's lag. Callers must hold t.mu.
+func (t *Tracker) recordLag(c *consumer) {
+	lag := t.latest - c.committed
+	if lag < 0 {
+		lag = 0
+	}
+	metrics.EventConsumerLag.WithLabelValues(c.name).Set(float64(lag))
+}
+
+// release drops in-flight entries for an event the publisher failed to dispatch
+func (t *Tracker) release(event *models.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, c := range t.consumers {
+		if count := len(c.handlers[event.Type]); count > 0 {
+			c.done(event.Sequence, count)
+		}
+	}
+}
+
+// handled marks one delivery of an event as processed and commits the new offset
+func (t *Tracker) handled(name string, sequence int64) {
+	t.mu.Lock()
+	c := t.consumers[name]
+	if sequence > c.handled {
+		c.handled = sequence
+	}
+	c.done(sequence, 1)
+	offset := c.offset()
+	if offset <= c.committed {
+		t.mu.Unlock()
+		return
+	}
+	c.committed = offset
+	t.recordLag(c)
+	t.mu.Unlock()
+
+	// A failed commit only means the events are delivered again after a restart
+	_ = t.offsets.Commit(name, offset)
+}
+
+// done removes count deliveries of a sequence from the in-flight set
+func (c *consumer) done(sequence int64, count int) {
+	remaining, exists := c.inFlight[sequence]
+	if !exists {
+		return
+	}
+	if remaining <= count {
+		delete(c.inFlight, sequence)
+		return
+	}
+	c.inFlight[sequence] = remaining - count
+}
+
+// offset is the highest sequence below which nothing is still in flight
+func (c *consumer) offset() int64 {
+	offset := c.handled
+	for sequence := range c.inFlight {
+		if sequence-1 < offset {
+			offset = sequence - 1
+		}
+	}
+	return offset
+}
+
+// Resume replays every stored event above each consumer's committed offset
+// to its handlers and returns the number of deliveries made
+func (t *Tracker) Resume(ctx context.Context, log EventLog) (int, error) {
+	stored, err := log.GetEventsUntil(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	type delivery struct {
+		name    string
+		event   *models.Event
+		handler func(*models.Event)
+	}
+
+	t.mu.Lock()
+	deliveries := make([]delivery, 0)
+	for _, event := range stored {
+		if event.Sequence > t.latest {
+			t.latest = event.Sequence
+		}
+	}
+	for _, c := range t.consumers {
+		for _, event := range stored {
+			if event.Sequence <= c.committed {
+				continue
+			}
+			for _, handler := range c.handlers[event.Type] {
+				c.inFlight[event.Sequence]++
+				deliveries = append(deliveries, delivery{name: c.name, event: event, handler: handler})
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	for _, d := range deliveries {
+		d := d
+		t.deliver(d.name, func() {
+			d.handler(d.event)
+			t.handled(d.name, d.event.Sequence)
+		})
+	}
+	return len(deliveries), nil
+}
+
+// HasConsumer reports whether a consumer with the given name is tracked
+func (t *Tracker) HasConsumer(name string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, exists := t.consumers[name]
+	return exists
+}
+
+// Redeliver hands a stored event to the handlers of one consumer again, or of
+// every consumer when name is empty, and returns the number of deliveries. It is
+// meant for reprocessing after a consumer bug was fixed, so offsets are left alone:
+// the event was already committed when it was first handled.
+func (t *Tracker) Redeliver(name string, event *models.Event) (int, error) {
+	t.mu.Lock()
+	handlers := make(map[string][]func(*models.Event))
+	if name != "" {
+		c, exists := t.consumers[name]
+		if !exists {
+			t.mu.Unlock()
+			return 0, fmt.Errorf("%w: %s", models.ErrConsumerNotFound, name)
+		}
+		handlers[name] = append(handlers[name], c.handlers[event.Type]...)
+	} else {
+		for _, c := range t.consumers {
+			handlers[c.name] = append(handlers[c.name], c.handlers[event.Type]...)
+		}
+	}
+	t.mu.Unlock()
+
+	count := 0
+	for consumer, list := range handlers {
+		for _, handler := range list {
+			handler := handler
+			t.deliver(consumer, func() { handler(event) })
+			count++
+		}
+	}
+	return count, nil
+}
+
+// flushInterval is how often Flush checks for deliveries still in flight
+const flushInterval = 10 * time.Millisecond
+
+// Flush waits until every delivery in flight has been handled, including
+// deliveries queued for rate limited consumers. It returns an error naming the
+// deliveries left when the context ends first; their offsets stay uncommitted,
+// so they are replayed after a restart.
+func (t *Tracker) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		pending := t.pending()
+		if pending == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d event deliveries still in flight: %w", pending, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// pending counts the deliveries in flight over all consumers
+func (t *Tracker) pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	pending := 0
+	for _, c := range t.consumers {
+		for _, count := range c.inFlight {
+			pending += count
+		}
+	}
+	return pending
+}
+
+// ConsumerLags reports every tracked consumer's committed offset against the latest sequence
+func (t *Tracker) ConsumerLags() []*interfaces.ConsumerLag {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]*interfaces.ConsumerLag, 0, len(t.consumers))
+	for _, c := range t.consumers {
+		inFlight := 0
+		for _, count := range c.inFlight {
+			inFlight += count
+		}
+		lag := t.latest - c.committed
+		if lag < 0 {
+			lag = 0
+		}
+		consumerLag := &interfaces.ConsumerLag{
+			Consumer:  c.name,
+			Committed: c.committed,
+			Latest:    t.latest,
+			Lag:       lag,
+			InFlight:  inFlight,
+		}
+		if c.throttle != nil {
+			consumerLag.RateLimit = c.throttle.rateLimit().PerSecond
+			consumerLag.Queued = c.throttle.queued()
+		}
+		result = append(result, consumerLag)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Consumer < result[j].Consumer
+	})
+	return result
+}
+
+// consumerPublisher is the view of a tracker handed to one named subscriber
+type consumerPublisher struct {
+	tracker *Tracker
+	name    string
+
+	mu      sync.Mutex
+	wrapped map[subscription]func(*models.Event)
+}
+
+// subscription identifies a handler registered for an event type
+type subscription struct {
+	eventType models.EventType
+	handler   uintptr
+}
+
+// Publish forwards to the tracker
+func (p *consumerPublisher) Publish(event *models.Event) error {
+	return p.tracker.Publish(event)
+}
+
+// PublishBatch forwards to the tracker
+func (p *consumerPublisher) PublishBatch(events []*models.Event) []error {
+	return p.tracker.PublishBatch(events)
+}
+
+// Subscribe registers a handler whose deliveries advance this consumer's offset
+func (p *consumerPublisher) Subscribe(eventType models.EventType, handler func(*models.Event)) error {
+	wrapped := func(event *models.Event) {
+		p.tracker.deliver(p.name, func() {
+			handler(event)
+			p.tracker.handled(p.name, event.Sequence)
+		})
+	}
+
+	p.tracker.mu.Lock()
+	c := p.tracker.consumer(p.name)
+	c.handlers[eventType] = append(c.handlers[eventType], handler)
+	p.tracker.mu.Unlock()
+
+	p.mu.Lock()
+	p.wrapped[subscription{eventType, reflect.ValueOf(handler).Pointer()}] = wrapped
+	p.mu.Unlock()
+
+	return p.tracker.inner.Subscribe(eventType, wrapped)
+}
+
+// Unsubscribe removes a handler previously registered through this consumer
+func (p *consumerPublisher) Unsubscribe(eventType models.EventType, handler func(*models.Event)) error {
+	key := subscription{eventType, reflect.ValueOf(handler).Pointer()}
+
+	p.mu.Lock()
+	wrapped, exists := p.wrapped[key]
+	delete(p.wrapped, key)
+	p.mu.Unlock()
+	if !exists {
+		return nil
+	}
+
+	p.tracker.mu.Lock()
+	c := p.tracker.consumers[p.name]
+	handlers := c.handlers[eventType]
+	for i, h := range handlers {
+		if reflect.ValueOf(h).Pointer() == key.handler {
+			c.handlers[eventType] = append(handlers[:i:i], handlers[i+1:]...)
+			break
+		}
+	}
+	p.tracker.mu.Unlock()
+
+	return p.tracker.inner.Unsubscribe(eventType, wrapped)
+}
@@ -0,0 +1,246 @@
+package tracking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	eventsMemory "github.com/jimmitjoo/ecom/src/infrastructure/events/memory"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubEventLog struct {
+	events []*models.Event
+}
+
+func (l *stubEventLog) GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error) {
+	return l.events, nil
+}
+
+func testEvent(eventType models.EventType, sequence int64) *models.Event {
+	return &models.Event{
+		ID:        "evt",
+		Type:      eventType,
+		EntityID:  "prod_1",
+		Sequence:  sequence,
+		Timestamp: time.Now(),
+	}
+}
+
+func lagFor(t *testing.T, tracker *Tracker, name string) *interfaces.ConsumerLag {
+	for _, lag := range tracker.ConsumerLags() {
+		if lag.Consumer == name {
+			return lag
+		}
+	}
+	t.Fatalf("consumer %s is not tracked", name)
+	return nil
+}
+
+func TestTrackerCommitsHandledEvents(t *testing.T) {
+	offsets := memory.NewOffsetRepository()
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), offsets)
+
+	received := make(chan *models.Event, 2)
+	tracker.Consumer("websocket").Subscribe(models.EventProductCreated, func(event *models.Event) {
+		received <- event
+	})
+
+	assert.NoError(t, tracker.Publish(testEvent(models.EventProductCreated, 1)))
+	assert.NoError(t, tracker.Publish(testEvent(models.EventProductCreated, 2)))
+	<-received
+	<-received
+
+	assert.Eventually(t, func() bool {
+		offset, _ := offsets.Get("websocket")
+		return offset == 2
+	}, time.Second, 10*time.Millisecond)
+
+	lag := lagFor(t, tracker, "websocket")
+	assert.Equal(t, int64(2), lag.Latest)
+	assert.Equal(t, int64(0), lag.Lag)
+}
+
+func TestTrackerHoldsOffsetBehindSlowEvent(t *testing.T) {
+	offsets := memory.NewOffsetRepository()
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), offsets)
+
+	release := make(chan struct{})
+	handled := make(chan int64, 2)
+	tracker.Consumer("projection").Subscribe(models.EventProductUpdated, func(event *models.Event) {
+		if event.Sequence == 1 {
+			<-release
+		}
+		handled <- event.Sequence
+	})
+
+	tracker.Publish(testEvent(models.EventProductUpdated, 1))
+	tracker.Publish(testEvent(models.EventProductUpdated, 2))
+	assert.Equal(t, int64(2), <-handled)
+
+	// Sequence 2 is done but 1 is still in flight, so nothing is committed yet
+	lag := lagFor(t, tracker, "projection")
+	assert.Equal(t, int64(0), lag.Committed)
+	assert.Equal(t, int64(2), lag.Lag)
+	assert.Equal(t, 1, lag.InFlight)
+
+	close(release)
+	assert.Equal(t, int64(1), <-handled)
+	assert.Eventually(t, func() bool {
+		return lagFor(t, tracker, "projection").Committed == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTrackerResumeReplaysFromCommittedOffset(t *testing.T) {
+	offsets := memory.NewOffsetRepository()
+	assert.NoError(t, offsets.Commit("dashboard", 2))
+
+	// A fresh tracker simulates the process coming back after a restart
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), offsets)
+	replayed := make([]int64, 0)
+	tracker.Consumer("dashboard").Subscribe(models.EventProductCreated, func(event *models.Event) {
+		replayed = append(replayed, event.Sequence)
+	})
+
+	log := &stubEventLog{events: []*models.Event{
+		testEvent(models.EventProductCreated, 1),
+		testEvent(models.EventProductCreated, 2),
+		testEvent(models.EventProductCreated, 3),
+		testEvent(models.EventProductDeleted, 4),
+		testEvent(models.EventProductCreated, 5),
+	}}
+
+	deliveries, err := tracker.Resume(context.Background(), log)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, deliveries)
+	assert.Equal(t, []int64{3, 5}, replayed)
+
+	offset, _ := offsets.Get("dashboard")
+	assert.Equal(t, int64(5), offset)
+
+	// The consumer never subscribed to deletions, but the latest sequence still counts them
+	lag := lagFor(t, tracker, "dashboard")
+	assert.Equal(t, int64(5), lag.Latest)
+	assert.Equal(t, int64(0), lag.Lag)
+}
+
+func TestTrackerIgnoresUnsubscribedTypes(t *testing.T) {
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), memory.NewOffsetRepository())
+	tracker.Consumer("websocket").Subscribe(models.EventProductCreated, func(event *models.Event) {})
+
+	tracker.Publish(testEvent(models.EventProductDeleted, 1))
+
+	lag := lagFor(t, tracker, "websocket")
+	assert.Equal(t, 0, lag.InFlight)
+	assert.Equal(t, int64(1), lag.Lag)
+}
+
+func TestTrackerPublishBatch(t *testing.T) {
+	offsets := memory.NewOffsetRepository()
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), offsets)
+
+	received := make(chan struct{}, 3)
+	tracker.Consumer("websocket").Subscribe(models.EventProductCreated, func(event *models.Event) {
+		received <- struct{}{}
+	})
+
+	errs := tracker.PublishBatch([]*models.Event{
+		testEvent(models.EventProductCreated, 1),
+		testEvent(models.EventProductCreated, 2),
+		testEvent(models.EventProductCreated, 3),
+	})
+	assert.Len(t, errs, 3)
+	for i := 0; i < 3; i++ {
+		<-received
+	}
+
+	assert.Eventually(t, func() bool {
+		offset, _ := offsets.Get("websocket")
+		return offset == 3
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTrackerUnsubscribe(t *testing.T) {
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), memory.NewOffsetRepository())
+	consumer := tracker.Consumer("websocket")
+
+	calls := make(chan struct{}, 1)
+	handler := func(event *models.Event) { calls <- struct{}{} }
+	consumer.Subscribe(models.EventProductCreated, handler)
+	assert.NoError(t, consumer.Unsubscribe(models.EventProductCreated, handler))
+
+	tracker.Publish(testEvent(models.EventProductCreated, 1))
+	select {
+	case <-calls:
+		t.Fatal("handler called after unsubscribe")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, 0, lagFor(t, tracker, "websocket").InFlight)
+}
+
+func TestConsumerLagsSortedByName(t *testing.T) {
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), memory.NewOffsetRepository())
+	tracker.Consumer("websocket")
+	tracker.Consumer("dashboard")
+
+	lags := tracker.ConsumerLags()
+	assert.Len(t, lags, 2)
+	assert.Equal(t, "dashboard", lags[0].Consumer)
+	assert.Equal(t, "websocket", lags[1].Consumer)
+}
+
+func TestTrackerRedeliver(t *testing.T) {
+	offsets := memory.NewOffsetRepository()
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), offsets)
+	assert.NoError(t, offsets.Commit("marketplaces", 5))
+
+	marketplaces := make([]int64, 0)
+	tracker.Consumer("marketplaces").Subscribe(models.EventProductUpdated, func(event *models.Event) {
+		marketplaces = append(marketplaces, event.Sequence)
+	})
+	dashboard := 0
+	tracker.Consumer("dashboard").Subscribe(models.EventProductUpdated, func(event *models.Event) {
+		dashboard++
+	})
+
+	deliveries, err := tracker.Redeliver("marketplaces", testEvent(models.EventProductUpdated, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deliveries)
+	assert.Equal(t, []int64{3}, marketplaces)
+	assert.Equal(t, 0, dashboard)
+
+	// Reprocessing an old event does not move the committed offset
+	offset, _ := offsets.Get("marketplaces")
+	assert.Equal(t, int64(5), offset)
+
+	deliveries, err = tracker.Redeliver("", testEvent(models.EventProductUpdated, 4))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, deliveries)
+	assert.Equal(t, 1, dashboard)
+
+	_, err = tracker.Redeliver("search", testEvent(models.EventProductUpdated, 4))
+	assert.ErrorIs(t, err, models.ErrConsumerNotFound)
+}
+
+func TestTrackerFlushWaitsForDeliveries(t *testing.T) {
+	tracker := NewTracker(eventsMemory.NewMemoryEventPublisher(), memory.NewOffsetRepository())
+
+	release := make(chan struct{})
+	tracker.Consumer("projection").Subscribe(models.EventProductUpdated, func(event *models.Event) {
+		<-release
+	})
+	tracker.Publish(testEvent(models.EventProductUpdated, 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := tracker.Flush(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "1 event deliveries still in flight")
+
+	close(release)
+	assert.NoError(t, tracker.Flush(context.Background()))
+	assert.Equal(t, int64(1), lagFor(t, tracker, "projection").Committed)
+}
@@ -0,0 +1,134 @@
+// Package exports writes products as spreadsheet rows, one column per
+// field with prices and market metadata flattened into columns named like
+// the import mapping targets, so exported files can be imported again.
+package exports
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Column is a column of an export
+type Column struct {
+	Name   string
+	Number bool // Written as a number cell in formats that type cells
+}
+
+// metadataAttributes are the market metadata columns per market
+var metadataAttributes = []string{"title", "description", "keywords"}
+
+// Layout decides the columns of an export. Collect each product first so
+// every currency and market gets its columns, then write the rows.
+type Layout struct {
+	currencies map[string]bool
+	markets    map[string]bool
+
+	// Sorted codes, computed once the rows are written
+	sortedCurrencies []string
+	sortedMarkets    []string
+}
+
+// NewLayout creates a layout without price or metadata columns
+func NewLayout() *Layout {
+	return &Layout{currencies: make(map[string]bool), markets: make(map[string]bool)}
+}
+
+// Collect adds the currencies and markets of a product to the layout
+func (l *Layout) Collect(product *models.Product) {
+	for _, price := range product.Prices {
+		l.currencies[price.Currency] = true
+	}
+	for _, metadata := range product.Metadata {
+		l.markets[metadata.Market] = true
+	}
+	l.sortedCurrencies, l.sortedMarkets = nil, nil
+}
+
+// codes returns the collected currencies and markets in order
+func (l *Layout) codes() ([]string, []string) {
+	if l.sortedCurrencies == nil {
+		l.sortedCurrencies = sortedCodes(l.currencies)
+		l.sortedMarkets = sortedCodes(l.markets)
+	}
+	return l.sortedCurrencies, l.sortedMarkets
+}
+
+// Columns returns the columns in the order Row writes them: the product
+// fields, a prices.<CURRENCY> column per currency and metadata.<MARKET>.<attribute>
+// columns per market, both sorted by code
+func (l *Layout) Columns() []Column {
+	columns := []Column{
+		{Name: "id"},
+		{Name: "sku"},
+		{Name: "base_title"},
+		{Name: "description"},
+		{Name: "tags"},
+		{Name: "draft"},
+		{Name: "version", Number: true},
+		{Name: "created_at"},
+		{Name: "updated_at"},
+	}
+	currencies, markets := l.codes()
+	for _, currency := range currencies {
+		columns = append(columns, Column{Name: "prices." + currency, Number: true})
+	}
+	for _, market := range markets {
+		for _, attribute := range metadataAttributes {
+			columns = append(columns, Column{Name: "metadata." + market + "." + attribute})
+		}
+	}
+	return columns
+}
+
+// Row returns the cells of a product. Prices and metadata in currencies and
+// markets the layout has not collected are left out.
+func (l *Layout) Row(product *models.Product) []string {
+	row := []string{
+		product.ID,
+		product.SKU,
+		product.BaseTitle,
+		product.Description,
+		strings.Join(product.Tags, ","),
+		strconv.FormatBool(product.Draft),
+		strconv.FormatInt(product.Version, 10),
+		product.CreatedAt.UTC().Format(time.RFC3339),
+		product.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+
+	currencies, markets := l.codes()
+	prices := make(map[string]float64, len(product.Prices))
+	for _, price := range product.Prices {
+		prices[price.Currency] = price.Amount
+	}
+	for _, currency := range currencies {
+		cell := ""
+		if amount, ok := prices[currency]; ok {
+			cell = strconv.FormatFloat(amount, 'f', -1, 64)
+		}
+		row = append(row, cell)
+	}
+
+	metadata := make(map[string]models.MarketMetadata, len(product.Metadata))
+	for _, entry := range product.Metadata {
+		metadata[entry.Market] = entry
+	}
+	for _, market := range markets {
+		entry := metadata[market]
+		row = append(row, entry.Title, entry.Description, entry.Keywords)
+	}
+	return row
+}
+
+// sortedCodes returns the codes of a set in order
+func sortedCodes(set map[string]bool) []string {
+	codes := make([]string, 0, len(set))
+	for code := range set {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,55 @@
+package exports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func exportProducts() []*models.Product {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []*models.Product{
+		{
+			ID: "prod_1", SKU: "SHIRT-1", BaseTitle: "Shirt", Tags: []string{"summer", "cotton"}, Version: 3,
+			Prices:    []models.Price{{Currency: "SEK", Amount: 299.5}, {Currency: "EUR", Amount: 29}},
+			Metadata:  []models.MarketMetadata{{Market: "SE", Title: "Skjorta", Keywords: "bomull"}},
+			CreatedAt: created, UpdatedAt: created,
+		},
+		{
+			ID: "prod_2", SKU: "JACKET-1", BaseTitle: "Jacket", Draft: true, Version: 1,
+			Prices:    []models.Price{{Currency: "SEK", Amount: 999}},
+			Metadata:  []models.MarketMetadata{{Market: "DE", Title: "Jacke", Description: "Warm"}},
+			CreatedAt: created, UpdatedAt: created,
+		},
+	}
+}
+
+func TestLayoutFlattensPricesAndMetadata(t *testing.T) {
+	layout := NewLayout()
+	products := exportProducts()
+	for _, product := range products {
+		layout.Collect(product)
+	}
+
+	names := make([]string, 0)
+	for _, column := range layout.Columns() {
+		names = append(names, column.Name)
+	}
+	assert.Equal(t, []string{
+		"id", "sku", "base_title", "description", "tags", "draft", "version", "created_at", "updated_at",
+		"prices.EUR", "prices.SEK",
+		"metadata.DE.title", "metadata.DE.description", "metadata.DE.keywords",
+		"metadata.SE.title", "metadata.SE.description", "metadata.SE.keywords",
+	}, names)
+
+	assert.Equal(t, []string{
+		"prod_1", "SHIRT-1", "Shirt", "", "summer,cotton", "false", "3", "2024-03-01T12:00:00Z", "2024-03-01T12:00:00Z",
+		"29", "299.5",
+		"", "", "",
+		"Skjorta", "", "bomull",
+	}, layout.Row(products[0]))
+	assert.Equal(t, []string{"", "999"}, layout.Row(products[1])[9:11])
+}
@@ -0,0 +1,72 @@
+package exports
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// Export formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// RowWriter writes the rows of an export. Rows may be buffered until Flush or
+// Close; Close completes the file but leaves the underlying writer open.
+type RowWriter interface {
+	WriteHeader(columns []Column) error
+	WriteRow(cells []string) error
+	Flush() error
+	Close() error
+}
+
+// NewRowWriter creates the writer of a format
+func NewRowWriter(format string, w io.Writer) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w), nil
+	}
+	return nil, fmt.Errorf("unknown export format %q, expected csv or xlsx", format)
+}
+
+// ContentType returns the media type of a format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// CSVWriter writes comma separated rows with a header row
+type CSVWriter struct {
+	writer *csv.Writer
+}
+
+// NewCSVWriter creates a CSV writer
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{writer: csv.NewWriter(w)}
+}
+
+func (w *CSVWriter) WriteHeader(columns []Column) error {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return w.writer.Write(names)
+}
+
+func (w *CSVWriter) WriteRow(cells []string) error {
+	return w.writer.Write(cells)
+}
+
+func (w *CSVWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *CSVWriter) Close() error {
+	return w.Flush()
+}
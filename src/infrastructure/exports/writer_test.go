@@ -0,0 +1,28 @@
+package exports
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewRowWriter(FormatCSV, &out)
+	assert.NoError(t, err)
+
+	assert.NoError(t, writer.WriteHeader([]Column{{Name: "sku"}, {Name: "prices.SEK", Number: true}}))
+	assert.NoError(t, writer.WriteRow([]string{"SHIRT-1", "299"}))
+	assert.Empty(t, out.String(), "rows are buffered until flushed")
+	assert.NoError(t, writer.Flush())
+	assert.NoError(t, writer.WriteRow([]string{"JACKET, warm", ""}))
+	assert.NoError(t, writer.Close())
+
+	assert.Equal(t, "sku,prices.SEK\nSHIRT-1,299\n\"JACKET, warm\",\n", out.String())
+}
+
+func TestNewRowWriterUnknownFormat(t *testing.T) {
+	_, err := NewRowWriter("ods", &bytes.Buffer{})
+	assert.Error(t, err)
+}
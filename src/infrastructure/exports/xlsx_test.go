@@ -0,0 +1,81 @@
+package exports
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sheetRows reads the cells of the worksheet of a workbook
+func sheetRows(t *testing.T, workbook []byte) [][]xlsxCell {
+	archive, err := zip.NewReader(bytes.NewReader(workbook), int64(len(workbook)))
+	assert.NoError(t, err)
+
+	names := make([]string, 0)
+	var sheet []byte
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			content, err := file.Open()
+			assert.NoError(t, err)
+			sheet, err = io.ReadAll(content)
+			assert.NoError(t, err)
+		}
+	}
+	assert.Contains(t, names, "[Content_Types].xml")
+	assert.Contains(t, names, "xl/workbook.xml")
+
+	var parsed struct {
+		Rows []struct {
+			Cells []xlsxCell `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	assert.NoError(t, xml.Unmarshal(sheet, &parsed))
+	rows := make([][]xlsxCell, len(parsed.Rows))
+	for i, row := range parsed.Rows {
+		rows[i] = row.Cells
+	}
+	return rows
+}
+
+type xlsxCell struct {
+	Ref    string `xml:"r,attr"`
+	Type   string `xml:"t,attr"`
+	Value  string `xml:"v"`
+	Inline string `xml:"is>t"`
+}
+
+func TestXLSXWriter(t *testing.T) {
+	var out bytes.Buffer
+	writer, err := NewRowWriter(FormatXLSX, &out)
+	assert.NoError(t, err)
+
+	assert.NoError(t, writer.WriteHeader([]Column{{Name: "sku"}, {Name: "prices.SEK", Number: true}, {Name: "title"}}))
+	assert.NoError(t, writer.WriteRow([]string{"00123", "299.5", "Shirt <XL> & more"}))
+	assert.NoError(t, writer.Flush())
+	assert.NoError(t, writer.WriteRow([]string{"SHIRT-2", "", "Shirt"}))
+	assert.NoError(t, writer.Close())
+
+	rows := sheetRows(t, out.Bytes())
+	assert.Len(t, rows, 3)
+	assert.Equal(t, xlsxCell{Ref: "B1", Type: "inlineStr", Inline: "prices.SEK"}, rows[0][1])
+	assert.Equal(t, []xlsxCell{
+		{Ref: "A2", Type: "inlineStr", Inline: "00123"},
+		{Ref: "B2", Value: "299.5"},
+		{Ref: "C2", Type: "inlineStr", Inline: "Shirt <XL> & more"},
+	}, rows[1])
+	// Empty cells are left out
+	assert.Equal(t, "C3", rows[2][1].Ref)
+}
+
+func TestColumnName(t *testing.T) {
+	assert.Equal(t, "A", columnName(0))
+	assert.Equal(t, "Z", columnName(25))
+	assert.Equal(t, "AA", columnName(26))
+	assert.Equal(t, "AZ", columnName(51))
+	assert.Equal(t, "BA", columnName(52))
+}
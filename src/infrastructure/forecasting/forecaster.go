@@ -0,0 +1,52 @@
+package forecasting
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+)
+
+// DefaultForecaster is used when no forecaster is configured
+const DefaultForecaster = "velocity"
+
+// Factory creates a forecaster
+type Factory func() (interfaces.Forecaster, error)
+
+var (
+	forecastersMu sync.RWMutex
+	forecasters   = map[string]Factory{
+		DefaultForecaster: func() (interfaces.Forecaster, error) {
+			return NewVelocityForecaster(DefaultWindow), nil
+		},
+	}
+)
+
+// Register makes a forecaster available by name, e.g. one that calls a
+// purchasing team's demand planning model
+func Register(name string, factory Factory) {
+	forecastersMu.Lock()
+	defer forecastersMu.Unlock()
+	forecasters[name] = factory
+}
+
+// New creates the forecaster registered under name. An empty name selects the default.
+func New(name string) (interfaces.Forecaster, error) {
+	if name == "" {
+		name = DefaultForecaster
+	}
+	forecastersMu.RLock()
+	factory, exists := forecasters[name]
+	names := make([]string, 0, len(forecasters))
+	for registered := range forecasters {
+		names = append(names, registered)
+	}
+	forecastersMu.RUnlock()
+	if !exists {
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown forecaster %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return factory()
+}
@@ -0,0 +1,26 @@
+package forecasting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+)
+
+func TestNewForecaster(t *testing.T) {
+	forecaster, err := New("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultForecaster, forecaster.Name())
+
+	_, err = New("prophet")
+	assert.Error(t, err)
+
+	Register("fixed", func() (interfaces.Forecaster, error) {
+		return NewVelocityForecaster(time.Hour), nil
+	})
+	forecaster, err = New("fixed")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, forecaster.Window())
+}
@@ -0,0 +1,75 @@
+package forecasting
+
+import (
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// DefaultWindow is how much of the ledger the velocity forecaster looks at
+const DefaultWindow = 28 * 24 * time.Hour
+
+// VelocityForecaster assumes a variant keeps selling at its current rate. It
+// uses the pushed velocity while it is younger than the window, and otherwise
+// the decrements in the ledger averaged over the window.
+type VelocityForecaster struct {
+	window time.Duration
+}
+
+// NewVelocityForecaster creates a forecaster that averages the ledger over window
+func NewVelocityForecaster(window time.Duration) *VelocityForecaster {
+	return &VelocityForecaster{window: window}
+}
+
+// Name returns "velocity"
+func (f *VelocityForecaster) Name() string {
+	return DefaultForecaster
+}
+
+// Window returns how far back the ledger is used
+func (f *VelocityForecaster) Window() time.Duration {
+	return f.window
+}
+
+// Forecast divides the stock on hand by the daily sales rate
+func (f *VelocityForecaster) Forecast(input *models.ForecastInput) (*models.StockoutForecast, error) {
+	forecast := &models.StockoutForecast{
+		ProductID:   input.ProductID,
+		VariantID:   input.VariantID,
+		SKU:         input.SKU,
+		OnHand:      input.OnHand,
+		UnitsPerDay: f.unitsPerDay(input),
+		Forecaster:  f.Name(),
+	}
+
+	if input.OnHand <= 0 {
+		days := 0.0
+		forecast.DaysOfCover = &days
+		forecast.StockoutAt = &input.Now
+		return forecast, nil
+	}
+	if forecast.UnitsPerDay <= 0 {
+		return forecast, nil
+	}
+
+	days := float64(input.OnHand) / forecast.UnitsPerDay
+	stockoutAt := input.Now.Add(time.Duration(days * float64(24*time.Hour)))
+	forecast.DaysOfCover = &days
+	forecast.StockoutAt = &stockoutAt
+	return forecast, nil
+}
+
+// unitsPerDay prefers a recent pushed velocity over the ledger average
+func (f *VelocityForecaster) unitsPerDay(input *models.ForecastInput) float64 {
+	if input.Velocity != nil && input.Now.Sub(input.Velocity.AsOf) < f.window {
+		return input.Velocity.UnitsPerDay
+	}
+
+	sold := 0
+	for _, entry := range input.Ledger {
+		if entry.Delta < 0 {
+			sold -= entry.Delta
+		}
+	}
+	return float64(sold) / (f.window.Hours() / 24)
+}
@@ -0,0 +1,64 @@
+package forecasting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestVelocityForecasterUsesPushedVelocity(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	forecaster := NewVelocityForecaster(7 * 24 * time.Hour)
+
+	forecast, err := forecaster.Forecast(&models.ForecastInput{
+		VariantID: "v1",
+		OnHand:    20,
+		Velocity:  &models.SalesVelocity{VariantID: "v1", UnitsPerDay: 4, AsOf: now.Add(-time.Hour)},
+		Ledger:    []models.LedgerEntry{{VariantID: "v1", Delta: -70, At: now.Add(-time.Hour)}},
+		Now:       now,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, forecast.UnitsPerDay)
+	assert.Equal(t, 5.0, *forecast.DaysOfCover)
+	assert.Equal(t, now.Add(5*24*time.Hour), *forecast.StockoutAt)
+	assert.Equal(t, "velocity", forecast.Forecaster)
+}
+
+func TestVelocityForecasterFallsBackToLedger(t *testing.T) {
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	forecaster := NewVelocityForecaster(7 * 24 * time.Hour)
+
+	// A stale velocity is ignored; 14 units sold and a restock over 7 days is 2 a day
+	forecast, err := forecaster.Forecast(&models.ForecastInput{
+		VariantID: "v1",
+		OnHand:    10,
+		Velocity:  &models.SalesVelocity{VariantID: "v1", UnitsPerDay: 50, AsOf: now.Add(-30 * 24 * time.Hour)},
+		Ledger: []models.LedgerEntry{
+			{VariantID: "v1", Delta: -10, At: now.Add(-72 * time.Hour)},
+			{VariantID: "v1", Delta: 25, At: now.Add(-48 * time.Hour)},
+			{VariantID: "v1", Delta: -4, At: now.Add(-24 * time.Hour)},
+		},
+		Now: now,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, forecast.UnitsPerDay)
+	assert.Equal(t, now.Add(5*24*time.Hour), *forecast.StockoutAt)
+}
+
+func TestVelocityForecasterEdgeCases(t *testing.T) {
+	now := time.Now()
+	forecaster := NewVelocityForecaster(DefaultWindow)
+
+	// Not selling: no stockout date
+	forecast, _ := forecaster.Forecast(&models.ForecastInput{OnHand: 5, Now: now})
+	assert.Nil(t, forecast.StockoutAt)
+	assert.Nil(t, forecast.DaysOfCover)
+
+	// Already out of stock
+	forecast, _ = forecaster.Forecast(&models.ForecastInput{OnHand: 0, Now: now})
+	assert.Equal(t, now, *forecast.StockoutAt)
+	assert.Equal(t, 0.0, *forecast.DaysOfCover)
+}
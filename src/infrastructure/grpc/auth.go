@@ -0,0 +1,119 @@
+//go:build grpc
+
+package grpc
+
+import (
+	"context"
+	"net/http"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/grpc/productpb"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+)
+
+// route is the REST route a method mirrors
+type route struct {
+	method string
+	path   string
+}
+
+// routes name the methods as the REST routes they mirror, so the access policy
+// of the REST API decides the role a call needs. Methods missing here need the
+// policy's write role.
+var routes = map[string]route{
+	productpb.ProductService_ListProducts_FullMethodName:        {http.MethodGet, "/products"},
+	productpb.ProductService_GetProduct_FullMethodName:          {http.MethodGet, "/products/{id}"},
+	productpb.ProductService_CreateProduct_FullMethodName:       {http.MethodPost, "/products"},
+	productpb.ProductService_UpdateProduct_FullMethodName:       {http.MethodPut, "/products/{id}"},
+	productpb.ProductService_DeleteProduct_FullMethodName:       {http.MethodDelete, "/products/{id}"},
+	productpb.ProductService_BatchCreateProducts_FullMethodName: {http.MethodPost, "/products/batch"},
+	productpb.ProductService_BatchUpdateProducts_FullMethodName: {http.MethodPut, "/products/batch"},
+	productpb.ProductService_BatchDeleteProducts_FullMethodName: {http.MethodDelete, "/products/batch"},
+	productpb.ProductService_StreamEvents_FullMethodName:        {http.MethodGet, "/ws"},
+}
+
+// Authenticator rejects calls the REST API would reject: the authenticators
+// read the credentials from the call's metadata, e.g. "authorization" for
+// bearer tokens and "x-api-key" for API keys, and the access policy decides
+// the role the caller needs
+type Authenticator struct {
+	policy         middleware.AccessPolicy
+	authenticators []middleware.Authenticator
+}
+
+// NewAuthenticator creates an authenticator of calls with the REST API's
+// authenticators and access policy
+func NewAuthenticator(policy middleware.AccessPolicy, authenticators ...middleware.Authenticator) *Authenticator {
+	return &Authenticator{policy: policy, authenticators: authenticators}
+}
+
+// UnaryInterceptor authenticates and authorizes unary calls
+func (a *Authenticator) UnaryInterceptor() grpclib.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor authenticates and authorizes streaming calls
+func (a *Authenticator) StreamInterceptor() grpclib.StreamServerInterceptor {
+	return func(srv interface{}, stream grpclib.ServerStream, info *grpclib.StreamServerInfo, handler grpclib.StreamHandler) error {
+		ctx, err := a.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authorize returns the call's context with its caller, like RequireAuth and
+// Authorize do for requests, or the status to fail the call with
+func (a *Authenticator) authorize(ctx context.Context, fullMethod string) (context.Context, error) {
+	request := requestFromMetadata(ctx)
+	for _, authenticator := range a.authenticators {
+		principal, err := authenticator.Authenticate(request)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+		}
+		if principal == nil {
+			continue
+		}
+
+		route := routes[fullMethod]
+		if !middleware.HasAccess(principal, a.policy.RequiredRole(route.method, route.path)) {
+			return nil, status.Error(codes.PermissionDenied, "Forbidden")
+		}
+		return middleware.WithCaller(ctx, principal), nil
+	}
+	return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+}
+
+// requestFromMetadata returns a request carrying the call's metadata as
+// headers, for the authenticators of the REST API
+func requestFromMetadata(ctx context.Context) *http.Request {
+	header := make(http.Header)
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	return (&http.Request{Header: header}).WithContext(ctx)
+}
+
+// authenticatedStream is a stream whose context carries the caller
+type authenticatedStream struct {
+	grpclib.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,58 @@
+//go:build grpc
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/grpc/productpb"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+)
+
+func TestUnaryInterceptor(t *testing.T) {
+	authenticator := NewAuthenticator(middleware.DefaultAccessPolicy(),
+		middleware.NewAPIKeyAuthenticator([]string{"feed-key"}, models.RoleViewer))
+	interceptor := authenticator.UnaryInterceptor()
+
+	var caller *models.Principal
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		caller = middleware.PrincipalFromContext(ctx)
+		return nil, nil
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		key          string
+		expectedCode codes.Code
+	}{
+		{"Viewer reads", productpb.ProductService_ListProducts_FullMethodName, "feed-key", codes.OK},
+		{"Viewer writes", productpb.ProductService_CreateProduct_FullMethodName, "feed-key", codes.PermissionDenied},
+		{"Unknown API key", productpb.ProductService_GetProduct_FullMethodName, "other", codes.Unauthenticated},
+		{"Anonymous", productpb.ProductService_GetProduct_FullMethodName, "", codes.Unauthenticated},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			caller = nil
+			ctx := context.Background()
+			if tc.key != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", tc.key))
+			}
+			_, err := interceptor(ctx, nil, &grpclib.UnaryServerInfo{FullMethod: tc.method}, handler)
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			if tc.expectedCode == codes.OK {
+				assert.Equal(t, models.AuthMethodAPIKey, caller.Method)
+			} else {
+				assert.Nil(t, caller)
+			}
+		})
+	}
+}
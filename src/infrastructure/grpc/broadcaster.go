@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// streamBuffer is how many events a stream may fall behind before it is closed
+const streamBuffer = 256
+
+// ErrStreamBehind is the reason a stream is closed when its client fell behind
+var ErrStreamBehind = errors.New("event stream fell behind")
+
+// EventFilter selects events for a stream; empty fields match every event
+type EventFilter struct {
+	Types     []models.EventType
+	ProductID string
+}
+
+// matches reports whether an event passes the filter
+func (f EventFilter) matches(event *models.Event) bool {
+	if f.ProductID != "" && event.EntityID != f.ProductID {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, eventType := range f.Types {
+		if event.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// EventBroadcaster subscribes to the publisher once and fans events out to the
+// open streams, so a streaming call never blocks publishing
+type EventBroadcaster struct {
+	mu      sync.RWMutex
+	streams map[*EventStream]bool
+}
+
+// EventStream receives the events of one streaming call
+type EventStream struct {
+	filter EventFilter
+	events chan *models.Event
+	err    error
+}
+
+// NewEventBroadcaster creates a broadcaster for the product events of a publisher
+func NewEventBroadcaster(publisher events.EventPublisher) *EventBroadcaster {
+	b := &EventBroadcaster{streams: make(map[*EventStream]bool)}
+	for _, eventType := range []models.EventType{
+		models.EventProductCreated,
+		models.EventProductUpdated,
+		models.EventProductDeleted,
+		models.EventProductRestored,
+	} {
+		publisher.Subscribe(eventType, b.broadcast)
+	}
+	return b
+}
+
+// Open starts a stream of the events passing the filter
+func (b *EventBroadcaster) Open(filter EventFilter) *EventStream {
+	stream := &EventStream{filter: filter, events: make(chan *models.Event, streamBuffer)}
+	b.mu.Lock()
+	b.streams[stream] = true
+	b.mu.Unlock()
+	return stream
+}
+
+// Close stops a stream; its channel is closed once pending events are read
+func (b *EventBroadcaster) Close(stream *EventStream) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.streams[stream] {
+		delete(b.streams, stream)
+		close(stream.events)
+	}
+}
+
+// StreamCount returns the number of open streams
+func (b *EventBroadcaster) StreamCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.streams)
+}
+
+// broadcast hands an event to every matching stream, closing streams whose buffer is full
+func (b *EventBroadcaster) broadcast(event *models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for stream := range b.streams {
+		if !stream.filter.matches(event) {
+			continue
+		}
+		select {
+		case stream.events <- event:
+		default:
+			stream.err = ErrStreamBehind
+			delete(b.streams, stream)
+			close(stream.events)
+		}
+	}
+}
+
+// Events returns the stream's events. The channel is closed when the stream is
+// closed; Err then tells whether it fell behind.
+func (s *EventStream) Events() <-chan *models.Event {
+	return s.events
+}
+
+// Err returns ErrStreamBehind once a stream was closed for falling behind
+func (s *EventStream) Err() error {
+	return s.err
+}
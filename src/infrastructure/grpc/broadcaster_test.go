@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// recordingPublisher keeps the subscribed handlers so tests can publish synchronously
+type recordingPublisher struct {
+	handlers map[models.EventType][]func(*models.Event)
+}
+
+func newRecordingPublisher() *recordingPublisher {
+	return &recordingPublisher{handlers: make(map[models.EventType][]func(*models.Event))}
+}
+
+func (p *recordingPublisher) Publish(event *models.Event) error {
+	for _, handler := range p.handlers[event.Type] {
+		handler(event)
+	}
+	return nil
+}
+
+func (p *recordingPublisher) PublishBatch(events []*models.Event) []error {
+	errs := make([]error, len(events))
+	for i, event := range events {
+		errs[i] = p.Publish(event)
+	}
+	return errs
+}
+
+func (p *recordingPublisher) Subscribe(eventType models.EventType, handler func(*models.Event)) error {
+	p.handlers[eventType] = append(p.handlers[eventType], handler)
+	return nil
+}
+
+func (p *recordingPublisher) Unsubscribe(eventType models.EventType, handler func(*models.Event)) error {
+	return nil
+}
+
+func TestEventBroadcasterFiltersEvents(t *testing.T) {
+	publisher := newRecordingPublisher()
+	broadcaster := NewEventBroadcaster(publisher)
+	all := broadcaster.Open(EventFilter{})
+	updates := broadcaster.Open(EventFilter{Types: []models.EventType{models.EventProductUpdated}, ProductID: "prod_1"})
+
+	publisher.Publish(&models.Event{ID: "evt_1", Type: models.EventProductCreated, EntityID: "prod_1"})
+	publisher.Publish(&models.Event{ID: "evt_2", Type: models.EventProductUpdated, EntityID: "prod_2"})
+	publisher.Publish(&models.Event{ID: "evt_3", Type: models.EventProductUpdated, EntityID: "prod_1"})
+
+	assert.Len(t, all.Events(), 3)
+	assert.Len(t, updates.Events(), 1)
+	assert.Equal(t, "evt_3", (<-updates.Events()).ID)
+
+	broadcaster.Close(updates)
+	broadcaster.Close(updates)
+	_, open := <-updates.Events()
+	assert.False(t, open)
+	assert.NoError(t, updates.Err())
+	assert.Equal(t, 1, broadcaster.StreamCount())
+}
+
+func TestEventBroadcasterClosesStreamsThatFallBehind(t *testing.T) {
+	publisher := newRecordingPublisher()
+	broadcaster := NewEventBroadcaster(publisher)
+	stream := broadcaster.Open(EventFilter{})
+
+	for i := 0; i <= streamBuffer; i++ {
+		publisher.Publish(&models.Event{Type: models.EventProductUpdated, EntityID: "prod_1"})
+	}
+
+	received := 0
+	for range stream.Events() {
+		received++
+	}
+	assert.Equal(t, streamBuffer, received)
+	assert.ErrorIs(t, stream.Err(), ErrStreamBehind)
+	assert.Zero(t, broadcaster.StreamCount())
+}
@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// toDocument converts a value to the JSON document the REST API returns for it
+func toDocument(value interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(fields)
+}
+
+// productFromDocument decodes a product from its JSON document, ignoring
+// unknown fields like the REST handlers do
+func productFromDocument(document *structpb.Struct) (*models.Product, error) {
+	if document == nil {
+		return nil, fmt.Errorf("%w: product is required", models.ErrInvalidRequest)
+	}
+	data, err := document.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var product models.Product
+	if err := json.Unmarshal(data, &product); err != nil {
+		return nil, fmt.Errorf("%w: invalid product: %v", models.ErrInvalidRequest, err)
+	}
+	return &product, nil
+}
@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestProductDocumentRoundTrip(t *testing.T) {
+	product := &models.Product{
+		ID:        "prod_1",
+		SKU:       "SHIRT-1",
+		BaseTitle: "Basic T-shirt",
+		Prices:    []models.Price{{Currency: "SEK", Amount: 199}},
+		Tags:      []string{"summer"},
+		Version:   3,
+		CreatedAt: time.Date(2024, 2, 20, 12, 0, 0, 0, time.UTC),
+	}
+
+	document, err := toDocument(product)
+	assert.NoError(t, err)
+	assert.Equal(t, "SHIRT-1", document.Fields["sku"].GetStringValue())
+
+	decoded, err := productFromDocument(document)
+	assert.NoError(t, err)
+	assert.Equal(t, product, decoded)
+}
+
+func TestProductFromDocumentRejectsInvalidProducts(t *testing.T) {
+	_, err := productFromDocument(nil)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+
+	document, _ := structpb.NewStruct(map[string]interface{}{"version": "three"})
+	_, err = productFromDocument(document)
+	assert.True(t, errors.Is(err, models.ErrInvalidRequest))
+}
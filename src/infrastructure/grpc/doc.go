@@ -0,0 +1,15 @@
+// Package grpc serves the product API over gRPC next to the REST handlers,
+// on its own port. The service is defined in proto/product.proto.
+//
+// The server in server.go and its interceptors in auth.go are only built with
+// -tags grpc. The stubs in productpb are generated from the proto file and
+// committed; regenerate them after changing it (needs protoc, protoc-gen-go
+// and protoc-gen-go-grpc):
+//
+//	go generate ./src/infrastructure/grpc
+//	go build -tags grpc ./src
+//
+// The conversions and the event broadcaster it uses are built either way.
+package grpc
+
+//go:generate protoc -I proto --go_out=productpb --go_opt=paths=source_relative --go-grpc_out=productpb --go-grpc_opt=paths=source_relative proto/product.proto
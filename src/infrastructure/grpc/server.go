@@ -0,0 +1,277 @@
+//go:build grpc
+
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/grpc/productpb"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// Server implements productpb.ProductServiceServer on the product service
+type Server struct {
+	productpb.UnimplementedProductServiceServer
+	service     interfaces.ProductService
+	broadcaster *EventBroadcaster
+}
+
+// NewServer creates a gRPC server for the product service
+func NewServer(service interfaces.ProductService, broadcaster *EventBroadcaster) *Server {
+	return &Server{service: service, broadcaster: broadcaster}
+}
+
+// Register adds the product service to a gRPC server
+func (s *Server) Register(server *grpclib.Server) {
+	productpb.RegisterProductServiceServer(server, s)
+}
+
+// ListProducts returns a page of products
+func (s *Server) ListProducts(ctx context.Context, req *productpb.ListProductsRequest) (*productpb.ListProductsResponse, error) {
+	page, pageSize := int(req.GetPage()), int(req.GetPageSize())
+	if page <= 0 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+
+	products, total, err := s.service.ListProducts(ctx, models.ProductFilter{}, page, pageSize)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	response := &productpb.ListProductsResponse{
+		Products: make([]*productpb.Product, 0, len(products)),
+		Total:    int32(total),
+		Page:     int32(page),
+		PageSize: int32(pageSize),
+	}
+	for _, product := range products {
+		message, err := toProduct(product)
+		if err != nil {
+			return nil, statusError(err)
+		}
+		response.Products = append(response.Products, message)
+	}
+	return response, nil
+}
+
+// GetProduct returns a product by ID
+func (s *Server) GetProduct(ctx context.Context, req *productpb.GetProductRequest) (*productpb.Product, error) {
+	product, err := s.service.GetProduct(ctx, req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toProduct(product)
+}
+
+// CreateProduct creates a product; the ID is assigned by the service
+func (s *Server) CreateProduct(ctx context.Context, req *productpb.CreateProductRequest) (*productpb.Product, error) {
+	product, err := productFromDocument(req.GetProduct().GetDocument())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if err := s.service.CreateProduct(ctx, product); err != nil {
+		return nil, statusError(err)
+	}
+	return toProduct(product)
+}
+
+// UpdateProduct replaces a product, keeping its creation time like PUT
+// /products/{id}. The expected version plays the part of If-Match: the update
+// fails its precondition if the product was saved at another version.
+func (s *Server) UpdateProduct(ctx context.Context, req *productpb.UpdateProductRequest) (*productpb.Product, error) {
+	expected := req.GetExpectedVersion()
+	if expected <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "expected_version is required")
+	}
+	existing, err := s.service.GetProduct(ctx, req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	if existing.Version != expected {
+		return nil, status.Errorf(codes.FailedPrecondition, "product is at version %d, not %d", existing.Version, expected)
+	}
+	product, err := productFromDocument(req.GetProduct().GetDocument())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	product.ID = existing.ID
+	// The service rejects the update if another one was saved since the check
+	product.Version = expected
+	product.CreatedAt = existing.CreatedAt
+	product.UpdatedAt = time.Now()
+
+	if err := s.service.UpdateProduct(ctx, product); err != nil {
+		return nil, statusError(err)
+	}
+	return toProduct(product)
+}
+
+// DeleteProduct soft-deletes a product, or removes it for good when permanent
+func (s *Server) DeleteProduct(ctx context.Context, req *productpb.DeleteProductRequest) (*productpb.DeleteProductResponse, error) {
+	var err error
+	if req.GetPermanent() {
+		err = s.service.DeleteProduct(ctx, req.GetId())
+	} else {
+		_, err = s.service.SoftDeleteProduct(ctx, req.GetId())
+	}
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &productpb.DeleteProductResponse{}, nil
+}
+
+// BatchCreateProducts creates products, reporting the outcome per product
+func (s *Server) BatchCreateProducts(ctx context.Context, req *productpb.BatchProductsRequest) (*productpb.BatchResponse, error) {
+	products, err := productsFromMessages(req.GetProducts())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	results, err := s.service.BatchCreateProducts(ctx, products)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toBatchResponse(results), nil
+}
+
+// BatchUpdateProducts updates products, reporting the outcome per product
+func (s *Server) BatchUpdateProducts(ctx context.Context, req *productpb.BatchProductsRequest) (*productpb.BatchResponse, error) {
+	products, err := productsFromMessages(req.GetProducts())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	results, err := s.service.BatchUpdateProducts(ctx, products)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toBatchResponse(results), nil
+}
+
+// BatchDeleteProducts deletes products, reporting the outcome per product
+func (s *Server) BatchDeleteProducts(ctx context.Context, req *productpb.BatchDeleteProductsRequest) (*productpb.BatchResponse, error) {
+	results, err := s.service.BatchDeleteProducts(ctx, req.GetIds())
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return toBatchResponse(results), nil
+}
+
+// StreamEvents sends matching events as they are published until the client cancels
+func (s *Server) StreamEvents(req *productpb.StreamEventsRequest, stream productpb.ProductService_StreamEventsServer) error {
+	filter := EventFilter{ProductID: req.GetProductId()}
+	for _, eventType := range req.GetTypes() {
+		filter.Types = append(filter.Types, models.EventType(eventType))
+	}
+
+	events := s.broadcaster.Open(filter)
+	defer s.broadcaster.Close(events)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, open := <-events.Events():
+			if !open {
+				return status.Error(codes.ResourceExhausted, events.Err().Error())
+			}
+			message, err := toEvent(event)
+			if err != nil {
+				logging.Shared().Error("Failed to convert event for gRPC stream",
+					zap.String("event_id", event.ID),
+					zap.Error(err),
+				)
+				continue
+			}
+			if err := stream.Send(message); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// statusError maps service errors to gRPC status codes as the REST handlers map them to HTTP statuses
+func statusError(err error) error {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, models.ErrValidation), errors.Is(err, models.ErrInvalidRequest):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, models.ErrLockTimeout):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, models.ErrDuplicateSKU):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, models.ErrConflict):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// toProduct converts a product to its message
+func toProduct(product *models.Product) (*productpb.Product, error) {
+	document, err := toDocument(product)
+	if err != nil {
+		return nil, err
+	}
+	return &productpb.Product{Document: document}, nil
+}
+
+// productsFromMessages decodes the products of a batch request
+func productsFromMessages(messages []*productpb.Product) ([]*models.Product, error) {
+	products := make([]*models.Product, 0, len(messages))
+	for _, message := range messages {
+		product, err := productFromDocument(message.GetDocument())
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+// toBatchResponse converts batch results to their message
+func toBatchResponse(results []*interfaces.BatchResult) *productpb.BatchResponse {
+	response := &productpb.BatchResponse{Results: make([]*productpb.BatchResult, 0, len(results))}
+	for _, result := range results {
+		message := &productpb.BatchResult{Id: result.ID, Success: result.Success, Error: result.Error}
+		for _, warning := range result.Warnings {
+			message.Warnings = append(message.Warnings, &productpb.ValidationWarning{
+				Field:   warning.Field,
+				Code:    string(warning.Code),
+				Message: warning.Message,
+			})
+		}
+		response.Results = append(response.Results, message)
+	}
+	return response
+}
+
+// toEvent converts an event to its message; data is the event's JSON data
+func toEvent(event *models.Event) (*productpb.Event, error) {
+	message := &productpb.Event{
+		Id:        event.ID,
+		Type:      string(event.Type),
+		EntityId:  event.EntityID,
+		Version:   event.Version,
+		Sequence:  event.Sequence,
+		Timestamp: timestamppb.New(event.Timestamp),
+	}
+	if event.Data != nil {
+		data, err := toDocument(event.Data)
+		if err != nil {
+			return nil, err
+		}
+		message.Data = data
+	}
+	return message, nil
+}
@@ -0,0 +1,87 @@
+//go:build grpc
+
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/application/services"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	eventmem "github.com/jimmitjoo/ecom/src/infrastructure/events/memory"
+	"github.com/jimmitjoo/ecom/src/infrastructure/grpc/productpb"
+	"github.com/jimmitjoo/ecom/src/infrastructure/locks"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+func setupServer() (*Server, interfaces.ProductService) {
+	publisher := eventmem.NewMemoryEventPublisher()
+	service := services.NewProductService(memory.NewProductRepository(), publisher, locks.NewMemoryLockManager(), memory.NewJobRepository())
+	return NewServer(service, NewEventBroadcaster(publisher)), service
+}
+
+func createProduct(t *testing.T, server *Server) *models.Product {
+	document, err := toDocument(&models.Product{
+		SKU:       "SHIRT-1",
+		BaseTitle: "Basic T-shirt",
+		Prices:    []models.Price{{Currency: "SEK", Amount: 199}},
+		Metadata:  []models.MarketMetadata{{Market: "SE", Title: "Basic T-shirt", Description: "Cotton"}},
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	message, err := server.CreateProduct(context.Background(), &productpb.CreateProductRequest{Product: &productpb.Product{Document: document}})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	product, err := productFromDocument(message.GetDocument())
+	assert.NoError(t, err)
+	return product
+}
+
+func TestUpdateProductChecksExpectedVersion(t *testing.T) {
+	server, _ := setupServer()
+	ctx := context.Background()
+	product := createProduct(t, server)
+	product.BaseTitle = "Renamed"
+	document, _ := toDocument(product)
+
+	_, err := server.UpdateProduct(ctx, &productpb.UpdateProductRequest{Id: product.ID, Product: &productpb.Product{Document: document}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = server.UpdateProduct(ctx, &productpb.UpdateProductRequest{Id: product.ID, Product: &productpb.Product{Document: document}, ExpectedVersion: product.Version + 1})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	message, err := server.UpdateProduct(ctx, &productpb.UpdateProductRequest{Id: product.ID, Product: &productpb.Product{Document: document}, ExpectedVersion: product.Version})
+	assert.NoError(t, err)
+	updated, _ := productFromDocument(message.GetDocument())
+	assert.Equal(t, "Renamed", updated.BaseTitle)
+	assert.Equal(t, product.Version+1, updated.Version)
+
+	// The version read before the update is stale now
+	_, err = server.UpdateProduct(ctx, &productpb.UpdateProductRequest{Id: product.ID, Product: &productpb.Product{Document: document}, ExpectedVersion: product.Version})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestDeleteProductSoftDeletesUnlessPermanent(t *testing.T) {
+	server, service := setupServer()
+	ctx := context.Background()
+
+	product := createProduct(t, server)
+	_, err := server.DeleteProduct(ctx, &productpb.DeleteProductRequest{Id: product.ID})
+	assert.NoError(t, err)
+	_, err = server.GetProduct(ctx, &productpb.GetProductRequest{Id: product.ID})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = service.RestoreProduct(ctx, product.ID)
+	assert.NoError(t, err)
+
+	_, err = server.DeleteProduct(ctx, &productpb.DeleteProductRequest{Id: product.ID, Permanent: true})
+	assert.NoError(t, err)
+	_, err = service.RestoreProduct(ctx, product.ID)
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
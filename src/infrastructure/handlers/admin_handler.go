@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+const (
+	defaultDashboardLimit = 20
+	maxDashboardLimit     = 500
+	defaultErrorWindow    = 15 * time.Minute
+)
+
+// AdminHandler serves the read endpoints behind the admin dashboard widgets
+type AdminHandler struct {
+	dashboard interfaces.DashboardService
+}
+
+// NewAdminHandler creates a new admin handler instance
+func NewAdminHandler(dashboard interfaces.DashboardService) *AdminHandler {
+	return &AdminHandler{
+		dashboard: dashboard,
+	}
+}
+
+// limitParam reads the optional limit query parameter
+func limitParam(r *http.Request) int {
+	limit := defaultDashboardLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	if limit > maxDashboardLimit {
+		limit = maxDashboardLimit
+	}
+	return limit
+}
+
+// RecentEvents godoc
+// @Summary Recent events feed
+// @Description Returns the latest product events, newest first
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Maximum number of events" default(20)
+// @Success 200 {array} models.Event
+// @Router /admin/dashboard/events [get]
+func (h *AdminHandler) RecentEvents(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, h.dashboard.RecentEvents(limitParam(r)))
+}
+
+// TopEditedProducts godoc
+// @Summary Top recently edited products
+// @Description Returns the products with the most recent updates, ordered by edit count
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Maximum number of products" default(20)
+// @Success 200 {array} interfaces.EditedProduct
+// @Router /admin/dashboard/top-edited [get]
+func (h *AdminHandler) TopEditedProducts(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, h.dashboard.TopEditedProducts(limitParam(r)))
+}
+
+// ErrorRate godoc
+// @Summary Error-rate summary
+// @Description Returns response counts per status and the server error rate for a time window
+// @Tags admin
+// @Produce json
+// @Param window query string false "Time window, e.g. 15m or 1h" default(15m)
+// @Success 200 {object} interfaces.ErrorRateSummary
+// @Failure 400 {object} models.APIError
+// @Router /admin/dashboard/errors [get]
+func (h *AdminHandler) ErrorRate(w http.ResponseWriter, r *http.Request) {
+	window := defaultErrorWindow
+	if windowStr := r.URL.Query().Get("window"); windowStr != "" {
+		parsed, err := time.ParseDuration(windowStr)
+		if err != nil || parsed <= 0 {
+			respond.Error(w, http.StatusBadRequest, "Invalid window duration")
+			return
+		}
+		window = parsed
+	}
+
+	respond.JSON(w, http.StatusOK, h.dashboard.ErrorRate(window))
+}
+
+// WebSocketClients godoc
+// @Summary WebSocket client count
+// @Description Returns the number of connected WebSocket clients
+// @Tags admin
+// @Produce json
+// @Success 200 {object} interfaces.WebSocketSummary
+// @Router /admin/dashboard/websocket [get]
+func (h *AdminHandler) WebSocketClients(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, h.dashboard.WebSocketClients())
+}
+
+// JobStatuses godoc
+// @Summary Job statuses
+// @Description Returns the most recent batch jobs and counts per status
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Maximum number of jobs" default(20)
+// @Success 200 {object} interfaces.JobStatusSummary
+// @Failure 500 {object} models.APIError
+// @Router /admin/dashboard/jobs [get]
+func (h *AdminHandler) JobStatuses(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.dashboard.JobStatuses(limitParam(r))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to fetch job statuses")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, summary)
+}
+
+// ConsumerLags godoc
+// @Summary Event consumer lag
+// @Description Returns each internal event subscriber's committed sequence and how far it trails the latest published sequence
+// @Tags admin
+// @Produce json
+// @Success 200 {array} interfaces.ConsumerLag
+// @Router /admin/dashboard/consumers [get]
+func (h *AdminHandler) ConsumerLags(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, h.dashboard.ConsumerLags())
+}
+
+// LatencyObjectives godoc
+// @Summary Route latency objectives
+// @Description Returns each route's latency budget, p95 over the last 5 minutes and error budget burn rates
+// @Tags admin
+// @Produce json
+// @Success 200 {array} interfaces.RouteLatency
+// @Router /admin/dashboard/latency [get]
+func (h *AdminHandler) LatencyObjectives(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, h.dashboard.LatencyObjectives())
+}
+
+// DeprecationUsage godoc
+// @Summary Deprecation usage
+// @Description Returns every deprecated route and field with its planned sunset and how often, and by which callers, it was used since the process started
+// @Tags admin
+// @Produce json
+// @Success 200 {array} interfaces.DeprecationUsage
+// @Router /admin/dashboard/deprecations [get]
+func (h *AdminHandler) DeprecationUsage(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, h.dashboard.DeprecationUsage())
+}
@@ -0,0 +1,240 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockDashboardService is a mock for the DashboardService interface
+type MockDashboardService struct {
+	mock.Mock
+}
+
+func (m *MockDashboardService) Reset() {
+	m.Called()
+}
+
+func (m *MockDashboardService) RecentEvents(limit int) []*models.Event {
+	args := m.Called(limit)
+	return args.Get(0).([]*models.Event)
+}
+
+func (m *MockDashboardService) TopEditedProducts(limit int) []*interfaces.EditedProduct {
+	args := m.Called(limit)
+	return args.Get(0).([]*interfaces.EditedProduct)
+}
+
+func (m *MockDashboardService) RecentlyUpdatedProducts(limit int) []*interfaces.ProductSummary {
+	args := m.Called(limit)
+	return args.Get(0).([]*interfaces.ProductSummary)
+}
+
+func (m *MockDashboardService) ErrorRate(window time.Duration) *interfaces.ErrorRateSummary {
+	args := m.Called(window)
+	return args.Get(0).(*interfaces.ErrorRateSummary)
+}
+
+func (m *MockDashboardService) WebSocketClients() *interfaces.WebSocketSummary {
+	args := m.Called()
+	return args.Get(0).(*interfaces.WebSocketSummary)
+}
+
+func (m *MockDashboardService) JobStatuses(limit int) (*interfaces.JobStatusSummary, error) {
+	args := m.Called(limit)
+	if s, ok := args.Get(0).(*interfaces.JobStatusSummary); ok {
+		return s, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDashboardService) ConsumerLags() []*interfaces.ConsumerLag {
+	args := m.Called()
+	return args.Get(0).([]*interfaces.ConsumerLag)
+}
+
+func (m *MockDashboardService) LatencyObjectives() []*interfaces.RouteLatency {
+	args := m.Called()
+	return args.Get(0).([]*interfaces.RouteLatency)
+}
+
+func (m *MockDashboardService) DeprecationUsage() []*interfaces.DeprecationUsage {
+	args := m.Called()
+	return args.Get(0).([]*interfaces.DeprecationUsage)
+}
+
+func TestAdminRecentEvents(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := NewAdminHandler(mockService)
+
+	events := []*models.Event{{ID: "evt_1", Type: models.EventProductCreated}}
+	mockService.On("RecentEvents", 5).Return(events)
+
+	req := httptest.NewRequest("GET", "/admin/dashboard/events?limit=5", nil)
+	w := httptest.NewRecorder()
+	handler.RecentEvents(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []*models.Event
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Len(t, response, 1)
+	assert.Equal(t, "evt_1", response[0].ID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAdminLimitDefaultsAndCaps(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("TopEditedProducts", defaultDashboardLimit).Return([]*interfaces.EditedProduct{}).Once()
+	mockService.On("TopEditedProducts", maxDashboardLimit).Return([]*interfaces.EditedProduct{}).Once()
+
+	for _, url := range []string{"/admin/dashboard/top-edited?limit=abc", "/admin/dashboard/top-edited?limit=100000"} {
+		w := httptest.NewRecorder()
+		handler.TopEditedProducts(w, httptest.NewRequest("GET", url, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAdminErrorRate(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := NewAdminHandler(mockService)
+
+	summary := &interfaces.ErrorRateSummary{Window: "1h0m0s", TotalRequests: 10, ServerErrors: 1, ErrorRate: 0.1}
+	mockService.On("ErrorRate", time.Hour).Return(summary)
+
+	w := httptest.NewRecorder()
+	handler.ErrorRate(w, httptest.NewRequest("GET", "/admin/dashboard/errors?window=1h", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response interfaces.ErrorRateSummary
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 10, response.TotalRequests)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAdminErrorRateInvalidWindow(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := NewAdminHandler(mockService)
+
+	w := httptest.NewRecorder()
+	handler.ErrorRate(w, httptest.NewRequest("GET", "/admin/dashboard/errors?window=soon", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ErrorRate", mock.Anything)
+}
+
+func TestAdminWebSocketClients(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("WebSocketClients").Return(&interfaces.WebSocketSummary{ActiveClients: 3})
+
+	w := httptest.NewRecorder()
+	handler.WebSocketClients(w, httptest.NewRequest("GET", "/admin/dashboard/websocket", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"active_clients":3}`, w.Body.String())
+}
+
+func TestAdminJobStatuses(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := NewAdminHandler(mockService)
+
+	summary := &interfaces.JobStatusSummary{
+		Counts: map[models.JobStatus]int{models.JobStatusCompleted: 1},
+		Jobs:   []*models.Job{{ID: "job_1", Status: models.JobStatusCompleted}},
+	}
+	mockService.On("JobStatuses", defaultDashboardLimit).Return(summary, nil)
+
+	w := httptest.NewRecorder()
+	handler.JobStatuses(w, httptest.NewRequest("GET", "/admin/dashboard/jobs", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response interfaces.JobStatusSummary
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 1, response.Counts[models.JobStatusCompleted])
+}
+
+func TestAdminJobStatusesError(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("JobStatuses", defaultDashboardLimit).Return(nil, errors.New("storage down"))
+
+	w := httptest.NewRecorder()
+	handler.JobStatuses(w, httptest.NewRequest("GET", "/admin/dashboard/jobs", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestAdminConsumerLags(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := NewAdminHandler(mockService)
+
+	lags := []*interfaces.ConsumerLag{
+		{Consumer: "dashboard", Committed: 10, Latest: 10},
+		{Consumer: "websocket", Committed: 7, Latest: 10, Lag: 3, InFlight: 2},
+	}
+	mockService.On("ConsumerLags").Return(lags)
+
+	w := httptest.NewRecorder()
+	handler.ConsumerLags(w, httptest.NewRequest("GET", "/admin/dashboard/consumers", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []*interfaces.ConsumerLag
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Len(t, response, 2)
+	assert.Equal(t, int64(3), response[1].Lag)
+	assert.Equal(t, 2, response[1].InFlight)
+}
+
+func TestAdminLatencyObjectives(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("LatencyObjectives").Return([]*interfaces.RouteLatency{
+		{Route: "GET /products/{id}", BudgetMs: 100, Target: 0.99, Requests: 40, P95Ms: 150, Breached: true, BurnRates: map[string]float64{"5m0s": 12.5}},
+	})
+
+	w := httptest.NewRecorder()
+	handler.LatencyObjectives(w, httptest.NewRequest("GET", "/admin/dashboard/latency", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []*interfaces.RouteLatency
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Len(t, response, 1)
+	assert.True(t, response[0].Breached)
+	assert.Equal(t, 12.5, response[0].BurnRates["5m0s"])
+}
+
+func TestAdminDeprecationUsage(t *testing.T) {
+	mockService := new(MockDashboardService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("DeprecationUsage").Return([]*interfaces.DeprecationUsage{
+		{ID: "success-response-envelope", Routes: []string{"PUT /products/{id}"}, Field: "success", Requests: 3, Clients: map[string]int64{"shop-sync/1.0": 3}},
+	})
+
+	w := httptest.NewRecorder()
+	handler.DeprecationUsage(w, httptest.NewRequest("GET", "/admin/dashboard/deprecations", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []*interfaces.DeprecationUsage
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Len(t, response, 1)
+	assert.Equal(t, int64(3), response[0].Clients["shop-sync/1.0"])
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// AllocationHandler handles allocation policy, availability and reservation requests
+type AllocationHandler struct {
+	service interfaces.AllocationService
+}
+
+// NewAllocationHandler creates a new allocation handler instance
+func NewAllocationHandler(service interfaces.AllocationService) *AllocationHandler {
+	return &AllocationHandler{
+		service: service,
+	}
+}
+
+// ListPolicies godoc
+// @Summary List allocation policies
+// @Description Returns the allocation policies configured for a market and its sales channels
+// @Tags allocation
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Success 200 {array} models.AllocationPolicy
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/allocation-policies [get]
+func (h *AllocationHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	policies, err := h.service.ListPolicies(mux.Vars(r)["market"])
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to list allocation policies")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, policies)
+}
+
+// GetPolicy godoc
+// @Summary Get the allocation policy
+// @Description Returns the policy that applies to a sales channel: the channel's own, else the market's, else the default (every stocked location in listed order, split shipments allowed)
+// @Tags allocation
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Param channel query string false "Sales channel, e.g. web; empty for the market-wide policy"
+// @Success 200 {object} models.AllocationPolicy
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/allocation-policy [get]
+func (h *AllocationHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	policy, err := h.service.GetPolicy(mux.Vars(r)["market"], r.URL.Query().Get("channel"))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to fetch allocation policy")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, policy)
+}
+
+// SetPolicy godoc
+// @Summary Set the allocation policy
+// @Description Creates or replaces the allocation policy of a market or one of its sales channels. The nearest strategy needs coordinates for every location.
+// @Tags allocation
+// @Accept json
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Param channel query string false "Sales channel, e.g. web; empty for the market-wide policy"
+// @Param policy body models.AllocationPolicy true "Policy; market and channel are taken from the path and query"
+// @Success 200 {object} models.AllocationPolicy
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/allocation-policy [put]
+func (h *AllocationHandler) SetPolicy(w http.ResponseWriter, r *http.Request) {
+	var policy models.AllocationPolicy
+	if err := decodeJSON(r, &policy); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	policy.Market = mux.Vars(r)["market"]
+	policy.Channel = r.URL.Query().Get("channel")
+
+	saved, err := h.service.SetPolicy(&policy)
+	if err != nil {
+		h.writeAllocationError(w, err, "Failed to save allocation policy")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, saved)
+}
+
+// DeletePolicy godoc
+// @Summary Delete an allocation policy
+// @Description Removes a configured policy; the channel falls back to the market's policy, and the market to the default
+// @Tags allocation
+// @Param market path string true "Market code, e.g. SE"
+// @Param channel query string false "Sales channel, e.g. web; empty for the market-wide policy"
+// @Success 204
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/allocation-policy [delete]
+func (h *AllocationHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeletePolicy(mux.Vars(r)["market"], r.URL.Query().Get("channel")); err != nil {
+		h.writeAllocationError(w, err, "Failed to delete allocation policy")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Availability godoc
+// @Summary Product availability
+// @Description Reports how much of each variant one order in the market and channel can get from the locations its allocation policy allows
+// @Tags allocation
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param market query string true "Market code, e.g. SE"
+// @Param channel query string false "Sales channel, e.g. web"
+// @Success 200 {object} models.Availability
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/availability [get]
+func (h *AllocationHandler) Availability(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	availability, err := h.service.Availability(r.Context(), mux.Vars(r)["id"], query.Get("market"), query.Get("channel"))
+	if err != nil {
+		h.writeAllocationError(w, err, "Failed to determine availability")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, availability)
+}
+
+// Reserve godoc
+// @Summary Reserve stock
+// @Description Allocates the items to stock locations under the market's or channel's allocation policy and reserves them in one stock adjustment. Nothing is reserved when the allowed locations cannot cover every item.
+// @Tags allocation
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param request body models.AllocationRequest true "Items to reserve"
+// @Success 201 {object} models.Allocation
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 423 {object} models.APIError "The product is being written"
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/reservations [post]
+func (h *AllocationHandler) Reserve(w http.ResponseWriter, r *http.Request) {
+	var request models.AllocationRequest
+	if err := decodeJSON(r, &request); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	allocation, err := h.service.Reserve(r.Context(), mux.Vars(r)["id"], &request)
+	if err != nil {
+		h.writeAllocationError(w, err, "Failed to reserve stock")
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, allocation)
+}
+
+// writeAllocationError maps allocation errors to status codes
+func (h *AllocationHandler) writeAllocationError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidRequest), errors.Is(err, models.ErrVariantNotFound):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, models.ErrProductNotFound), errors.Is(err, models.ErrAllocationPolicyNotFound):
+		respond.Failure(w, http.StatusNotFound, err, err.Error())
+	default:
+		respond.ServiceError(w, err, fallback)
+	}
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockAllocationService is a mock for the AllocationService interface
+type MockAllocationService struct {
+	mock.Mock
+}
+
+func (m *MockAllocationService) ListPolicies(market string) ([]*models.AllocationPolicy, error) {
+	args := m.Called(market)
+	if policies, ok := args.Get(0).([]*models.AllocationPolicy); ok {
+		return policies, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAllocationService) GetPolicy(market, channel string) (*models.AllocationPolicy, error) {
+	args := m.Called(market, channel)
+	if policy, ok := args.Get(0).(*models.AllocationPolicy); ok {
+		return policy, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAllocationService) SetPolicy(policy *models.AllocationPolicy) (*models.AllocationPolicy, error) {
+	args := m.Called(policy)
+	if saved, ok := args.Get(0).(*models.AllocationPolicy); ok {
+		return saved, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAllocationService) DeletePolicy(market, channel string) error {
+	args := m.Called(market, channel)
+	return args.Error(0)
+}
+
+func (m *MockAllocationService) Availability(ctx context.Context, productID, market, channel string) (*models.Availability, error) {
+	args := m.Called(productID, market, channel)
+	if availability, ok := args.Get(0).(*models.Availability); ok {
+		return availability, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAllocationService) Reserve(ctx context.Context, productID string, request *models.AllocationRequest) (*models.Allocation, error) {
+	args := m.Called(productID, request)
+	if allocation, ok := args.Get(0).(*models.Allocation); ok {
+		return allocation, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestGetAllocationPolicy(t *testing.T) {
+	mockService := new(MockAllocationService)
+	handler := NewAllocationHandler(mockService)
+	policy := models.DefaultAllocationPolicy("SE", "web")
+	mockService.On("GetPolicy", "SE", "web").Return(policy, nil)
+
+	req := httptest.NewRequest("GET", "/markets/SE/allocation-policy?channel=web", nil)
+	req = mux.SetURLVars(req, map[string]string{"market": "SE"})
+	w := httptest.NewRecorder()
+
+	handler.GetPolicy(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.AllocationPolicy
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.True(t, response.Default)
+	assert.Equal(t, models.AllocationPriority, response.Strategy)
+	mockService.AssertExpectations(t)
+}
+
+func TestListAllocationPolicies(t *testing.T) {
+	mockService := new(MockAllocationService)
+	handler := NewAllocationHandler(mockService)
+	mockService.On("ListPolicies", "SE").Return([]*models.AllocationPolicy{{Market: "SE", Channel: "web"}}, nil)
+
+	req := httptest.NewRequest("GET", "/markets/SE/allocation-policies", nil)
+	req = mux.SetURLVars(req, map[string]string{"market": "SE"})
+	w := httptest.NewRecorder()
+
+	handler.ListPolicies(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []*models.AllocationPolicy
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Len(t, response, 1)
+}
+
+func TestSetAllocationPolicy(t *testing.T) {
+	mockService := new(MockAllocationService)
+	handler := NewAllocationHandler(mockService)
+
+	mockService.On("SetPolicy", mock.MatchedBy(func(policy *models.AllocationPolicy) bool {
+		return policy.Market == "SE" && policy.Channel == "pos" && policy.Strategy == models.AllocationNearest && len(policy.Locations) == 1
+	})).Return(&models.AllocationPolicy{Market: "SE", Channel: "pos", Strategy: models.AllocationNearest}, nil)
+
+	body := `{"market":"NO","strategy":"nearest","locations":[{"id":"sto","coordinates":{"latitude":59.3,"longitude":18.1}}]}`
+	req := httptest.NewRequest("PUT", "/markets/SE/allocation-policy?channel=pos", strings.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"market": "SE"})
+	w := httptest.NewRecorder()
+
+	handler.SetPolicy(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSetAllocationPolicyErrors(t *testing.T) {
+	mockService := new(MockAllocationService)
+	handler := NewAllocationHandler(mockService)
+	mockService.On("SetPolicy", mock.Anything).Return(nil, fmt.Errorf("%w: unknown strategy", models.ErrInvalidRequest))
+
+	for body, code := range map[string]int{`{`: http.StatusBadRequest, `{"strategy":"cheapest"}`: http.StatusBadRequest} {
+		req := httptest.NewRequest("PUT", "/markets/SE/allocation-policy", strings.NewReader(body))
+		req = mux.SetURLVars(req, map[string]string{"market": "SE"})
+		w := httptest.NewRecorder()
+
+		handler.SetPolicy(w, req)
+
+		assert.Equal(t, code, w.Code, body)
+	}
+}
+
+func TestDeleteAllocationPolicy(t *testing.T) {
+	mockService := new(MockAllocationService)
+	handler := NewAllocationHandler(mockService)
+	mockService.On("DeletePolicy", "SE", "web").Return(nil)
+	mockService.On("DeletePolicy", "SE", "").Return(models.ErrAllocationPolicyNotFound)
+
+	req := httptest.NewRequest("DELETE", "/markets/SE/allocation-policy?channel=web", nil)
+	req = mux.SetURLVars(req, map[string]string{"market": "SE"})
+	w := httptest.NewRecorder()
+	handler.DeletePolicy(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	req = httptest.NewRequest("DELETE", "/markets/SE/allocation-policy", nil)
+	req = mux.SetURLVars(req, map[string]string{"market": "SE"})
+	w = httptest.NewRecorder()
+	handler.DeletePolicy(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestAvailability(t *testing.T) {
+	mockService := new(MockAllocationService)
+	handler := NewAllocationHandler(mockService)
+	availability := &models.Availability{ProductID: "prod_1", Market: "SE", Variants: []models.VariantAvailability{{VariantID: "v1", Available: 4}}}
+	mockService.On("Availability", "prod_1", "SE", "web").Return(availability, nil)
+	mockService.On("Availability", "missing", "SE", "").Return(nil, models.ErrProductNotFound)
+
+	req := httptest.NewRequest("GET", "/products/prod_1/availability?market=SE&channel=web", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "prod_1"})
+	w := httptest.NewRecorder()
+	handler.Availability(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.Availability
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, 4, response.Variants[0].Available)
+
+	req = httptest.NewRequest("GET", "/products/missing/availability?market=SE", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "missing"})
+	w = httptest.NewRecorder()
+	handler.Availability(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestReserve(t *testing.T) {
+	mockService := new(MockAllocationService)
+	handler := NewAllocationHandler(mockService)
+	allocation := &models.Allocation{
+		ProductID: "prod_1",
+		Market:    "SE",
+		Lines:     []models.AllocationLine{{VariantID: "v1", LocationID: "wh1", Quantity: 2}},
+		Shipments: 1,
+		Version:   3,
+	}
+	mockService.On("Reserve", "prod_1", &models.AllocationRequest{
+		Market: "SE",
+		Items:  []models.AllocationItem{{VariantID: "v1", Quantity: 2}},
+	}).Return(allocation, nil)
+
+	body := `{"market":"SE","items":[{"variant_id":"v1","quantity":2}]}`
+	req := httptest.NewRequest("POST", "/products/prod_1/reservations", strings.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "prod_1"})
+	w := httptest.NewRecorder()
+
+	handler.Reserve(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response models.Allocation
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, *allocation, response)
+	mockService.AssertExpectations(t)
+}
+
+func TestReserveErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"invalid request", fmt.Errorf("%w: no items", models.ErrInvalidRequest), http.StatusBadRequest},
+		{"unknown variant", models.ErrVariantNotFound, http.StatusBadRequest},
+		{"unknown product", models.ErrProductNotFound, http.StatusNotFound},
+		{"insufficient stock", models.ErrInsufficientStock, http.StatusConflict},
+		{"service failure", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockAllocationService)
+			handler := NewAllocationHandler(mockService)
+			mockService.On("Reserve", "prod_1", mock.Anything).Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/products/prod_1/reservations", strings.NewReader(`{"market":"SE"}`))
+			req = mux.SetURLVars(req, map[string]string{"id": "prod_1"})
+			w := httptest.NewRecorder()
+
+			handler.Reserve(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// DefaultMaxUploadBytes is the largest image upload accepted unless configured otherwise
+const DefaultMaxUploadBytes = 10 << 20
+
+// maxAssetFieldBytes is the largest form field accepted before the uploaded file
+const maxAssetFieldBytes = 64 << 10
+
+// AssetHandler handles the images of products
+type AssetHandler struct {
+	service        interfaces.AssetService
+	maxUploadBytes int64
+}
+
+// NewAssetHandler creates a new asset handler instance. Uploads larger than
+// maxUploadBytes are rejected; zero uses DefaultMaxUploadBytes.
+func NewAssetHandler(service interfaces.AssetService, maxUploadBytes int64) *AssetHandler {
+	if maxUploadBytes <= 0 {
+		maxUploadBytes = DefaultMaxUploadBytes
+	}
+	return &AssetHandler{
+		service:        service,
+		maxUploadBytes: maxUploadBytes,
+	}
+}
+
+// AddImage godoc
+// @Summary Add a product image
+// @Description Appends an image to the product. A JSON body attaches an image served from an external URL. A multipart/form-data body uploads the image in its file field to the media storage instead, with alt_text and alt_texts (a JSON object of alt texts per market) as form fields sent before it.
+// @Tags media
+// @Accept json
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param image body models.Image true "Image with an absolute URL"
+// @Success 201 {object} models.Product
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 413 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/images [post]
+func (h *AssetHandler) AddImage(w http.ResponseWriter, r *http.Request) {
+	productID := mux.Vars(r)["id"]
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		h.uploadImage(w, r, productID)
+		return
+	}
+
+	var image models.Image
+	if err := decodeJSON(r, &image); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	product, err := h.service.AttachImage(r.Context(), productID, &image)
+	h.writeImageResult(w, http.StatusCreated, product, err, "Failed to add image")
+}
+
+// uploadImage streams the file field of a multipart body to the asset
+// service; the alt texts are read from the form fields before it
+func (h *AssetHandler) uploadImage(w http.ResponseWriter, r *http.Request, productID string) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadBytes)
+	parts, err := r.MultipartReader()
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "Invalid multipart body")
+		return
+	}
+
+	upload := &interfaces.AssetUpload{}
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			respond.Error(w, http.StatusBadRequest, "An image is required in the file field")
+			return
+		}
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respond.Failure(w, http.StatusRequestEntityTooLarge, err, "Image is too large")
+			return
+		}
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, "Invalid multipart body")
+			return
+		}
+
+		switch part.FormName() {
+		case "file":
+			upload.Filename = part.FileName()
+			upload.ContentType = part.Header.Get("Content-Type")
+			upload.Body = part
+			product, err := h.service.UploadImage(r.Context(), productID, upload)
+			h.writeImageResult(w, http.StatusCreated, product, err, "Failed to upload image")
+			return
+		case "alt_text", "alt_texts":
+			value, err := io.ReadAll(io.LimitReader(part, maxAssetFieldBytes))
+			if err != nil {
+				respond.Error(w, http.StatusBadRequest, "Invalid multipart body")
+				return
+			}
+			if part.FormName() == "alt_text" {
+				upload.Image.AltText = string(value)
+			} else if err := json.Unmarshal(value, &upload.Image.AltTexts); err != nil {
+				respond.Error(w, http.StatusBadRequest, "Invalid alt_texts field")
+				return
+			}
+		}
+	}
+}
+
+// DeleteImage godoc
+// @Summary Remove a product image
+// @Description Detaches the image from the product; the binary of an uploaded image is deleted from the media storage
+// @Tags media
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param image_id path string true "Image ID"
+// @Success 200 {object} models.Product
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/images/{image_id} [delete]
+func (h *AssetHandler) DeleteImage(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	product, err := h.service.DetachImage(r.Context(), vars["id"], vars["image_id"])
+	h.writeImageResult(w, http.StatusOK, product, err, "Failed to remove image")
+}
+
+// ReorderImages godoc
+// @Summary Reorder product images
+// @Description Puts the images in the order of the IDs, which must list every image of the product once. The first image is the main image.
+// @Tags media
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param order body object true "Image IDs, e.g. {\"image_ids\": [\"img_2\", \"img_1\"]}"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/images/order [put]
+func (h *AssetHandler) ReorderImages(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ImageIDs []string `json:"image_ids"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	product, err := h.service.ReorderImages(r.Context(), mux.Vars(r)["id"], req.ImageIDs)
+	h.writeImageResult(w, http.StatusOK, product, err, "Failed to reorder images")
+}
+
+// writeImageResult writes the updated product, or maps the error to a status code
+func (h *AssetHandler) writeImageResult(w http.ResponseWriter, code int, product *models.Product, err error, fallback string) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil:
+		respond.JSON(w, code, product)
+	case errors.Is(err, models.ErrInvalidRequest):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, models.ErrProductNotFound), errors.Is(err, models.ErrImageNotFound):
+		respond.Failure(w, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, models.ErrVersionConflict):
+		respond.Failure(w, http.StatusConflict, err, err.Error())
+	case errors.As(err, &tooLarge):
+		respond.Failure(w, http.StatusRequestEntityTooLarge, err, "Image is too large")
+	default:
+		respond.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockAssetService is a mock for the AssetService interface
+type MockAssetService struct {
+	mock.Mock
+}
+
+func (m *MockAssetService) AttachImage(ctx context.Context, productID string, image *models.Image) (*models.Product, error) {
+	args := m.Called(productID, image)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAssetService) UploadImage(ctx context.Context, productID string, upload *interfaces.AssetUpload) (*models.Product, error) {
+	// The body is read here, while the multipart reader is still open
+	data, err := io.ReadAll(upload.Body)
+	if err != nil {
+		return nil, err
+	}
+	args := m.Called(productID, upload.Filename, upload.ContentType, string(data), upload.Image)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAssetService) DetachImage(ctx context.Context, productID, imageID string) (*models.Product, error) {
+	args := m.Called(productID, imageID)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockAssetService) ReorderImages(ctx context.Context, productID string, imageIDs []string) (*models.Product, error) {
+	args := m.Called(productID, imageIDs)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func assetRouter(handler *AssetHandler) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/images", handler.AddImage).Methods("POST")
+	router.HandleFunc("/products/{id}/images/order", handler.ReorderImages).Methods("PUT")
+	router.HandleFunc("/products/{id}/images/{image_id}", handler.DeleteImage).Methods("DELETE")
+	return router
+}
+
+// uploadRequest builds a multipart upload of data as an image/png file
+func uploadRequest(t *testing.T, fields map[string]string, data string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		assert.NoError(t, writer.WriteField(name, value))
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="front.png"`)
+	header.Set("Content-Type", "image/png")
+	part, err := writer.CreatePart(header)
+	assert.NoError(t, err)
+	part.Write([]byte(data))
+	assert.NoError(t, writer.Close())
+
+	req := httptest.NewRequest("POST", "/products/p1/images", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestAttachImage(t *testing.T) {
+	mockService := new(MockAssetService)
+	mockService.On("AttachImage", "p1", &models.Image{URL: "https://cdn.example.com/a.jpg", AltText: "Front"}).
+		Return(&models.Product{ID: "p1", Images: []models.Image{{ID: "img1", URL: "https://cdn.example.com/a.jpg"}}}, nil)
+
+	w := httptest.NewRecorder()
+	body := `{"url":"https://cdn.example.com/a.jpg","alt_text":"Front"}`
+	assetRouter(NewAssetHandler(mockService, 0)).ServeHTTP(w, httptest.NewRequest("POST", "/products/p1/images", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":"img1"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestUploadImage(t *testing.T) {
+	mockService := new(MockAssetService)
+	mockService.On("UploadImage", "p1", "front.png", "image/png", "binary", models.Image{AltText: "Front", AltTexts: map[string]string{"SE": "Framsida"}}).
+		Return(&models.Product{ID: "p1"}, nil)
+
+	w := httptest.NewRecorder()
+	req := uploadRequest(t, map[string]string{"alt_text": "Front", "alt_texts": `{"SE":"Framsida"}`}, "binary")
+	assetRouter(NewAssetHandler(mockService, 0)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestUploadImageTooLarge(t *testing.T) {
+	mockService := new(MockAssetService)
+
+	w := httptest.NewRecorder()
+	assetRouter(NewAssetHandler(mockService, 512)).ServeHTTP(w, uploadRequest(t, nil, strings.Repeat("x", 1024)))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestUploadImageRequiresFile(t *testing.T) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("alt_text", "Front")
+	writer.Close()
+	req := httptest.NewRequest("POST", "/products/p1/images", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	w := httptest.NewRecorder()
+	assetRouter(NewAssetHandler(new(MockAssetService), 0)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestImageErrors(t *testing.T) {
+	mockService := new(MockAssetService)
+	mockService.On("DetachImage", "p1", "missing").Return(nil, models.ErrImageNotFound)
+	mockService.On("ReorderImages", "p1", []string{"b", "a"}).Return(nil, models.ErrVersionConflict)
+	mockService.On("ReorderImages", "p1", []string{"a"}).Return(nil, models.ErrInvalidRequest)
+	router := assetRouter(NewAssetHandler(mockService, 0))
+
+	tests := []struct {
+		method, path, body string
+		code               int
+	}{
+		{"DELETE", "/products/p1/images/missing", "", http.StatusNotFound},
+		{"PUT", "/products/p1/images/order", `{"image_ids":["b","a"]}`, http.StatusConflict},
+		{"PUT", "/products/p1/images/order", `{"image_ids":["a"]}`, http.StatusBadRequest},
+		{"PUT", "/products/p1/images/order", `{`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+		assert.Equal(t, tt.code, w.Code, tt.method+" "+tt.path)
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// BundleHandler handles the computed prices and stock of bundle products
+type BundleHandler struct {
+	service interfaces.BundleService
+}
+
+// NewBundleHandler creates a new bundle handler instance
+func NewBundleHandler(service interfaces.BundleService) *BundleHandler {
+	return &BundleHandler{
+		service: service,
+	}
+}
+
+// GetBundle godoc
+// @Summary Get a bundle's price and availability
+// @Description Computes the bundle price per currency with its price strategy (sum, fixed or percentage_discount) and the number of bundles the component stock covers, from the current components
+// @Tags products
+// @Produce json
+// @Param id path string true "Bundle product ID"
+// @Success 200 {object} models.BundleSummary
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/bundle [get]
+func (h *BundleHandler) GetBundle(w http.ResponseWriter, r *http.Request) {
+	summary, err := h.service.GetBundle(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) || errors.Is(err, models.ErrNotABundle) {
+			respond.Failure(w, http.StatusNotFound, err, err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to compute bundle")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, summary)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockBundleService is a mock for the BundleService interface
+type MockBundleService struct {
+	mock.Mock
+}
+
+func (m *MockBundleService) GetBundle(ctx context.Context, productID string) (*models.BundleSummary, error) {
+	args := m.Called(productID)
+	if summary, ok := args.Get(0).(*models.BundleSummary); ok {
+		return summary, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestGetBundle(t *testing.T) {
+	mockService := new(MockBundleService)
+	available := 3
+	mockService.On("GetBundle", "set").Return(&models.BundleSummary{
+		ProductID:     "set",
+		PriceStrategy: models.BundlePriceSum,
+		Prices:        []models.Price{{Currency: "SEK", Amount: 797.5}},
+		Available:     &available,
+	}, nil)
+	mockService.On("GetBundle", "shirt").Return(nil, models.ErrNotABundle)
+	mockService.On("GetBundle", "broken").Return(nil, assert.AnError)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/bundle", NewBundleHandler(mockService).GetBundle)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/products/set/bundle", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var summary models.BundleSummary
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&summary))
+	assert.Equal(t, 3, *summary.Available)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/products/shirt/bundle", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/products/broken/bundle", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockService.AssertExpectations(t)
+}
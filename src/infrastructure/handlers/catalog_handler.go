@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// CatalogHandler exports catalogs and clones them between environments
+type CatalogHandler struct {
+	products interfaces.ProductService
+	cloner   interfaces.CatalogCloneService
+}
+
+// NewCatalogHandler creates a new catalog handler instance
+func NewCatalogHandler(products interfaces.ProductService, cloner interfaces.CatalogCloneService) *CatalogHandler {
+	return &CatalogHandler{
+		products: products,
+		cloner:   cloner,
+	}
+}
+
+// ExportCatalog godoc
+// @Summary Export the catalog
+// @Description Returns the products matching the filter in a form another environment can import
+// @Tags catalog
+// @Produce json
+// @Param ids query string false "Comma-separated product IDs"
+// @Param sku_prefix query string false "Only products whose SKU starts with this prefix"
+// @Param market query string false "Only products with metadata for this market"
+// @Param tag query string false "Only products with this tag"
+// @Param anonymize_prices query bool false "Replace prices with made-up amounts"
+// @Success 200 {object} models.CatalogExport
+// @Failure 500 {object} models.APIError
+// @Router /admin/catalog/export [get]
+func (h *CatalogHandler) ExportCatalog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := models.CatalogFilter{
+		SKUPrefix: query.Get("sku_prefix"),
+		Market:    query.Get("market"),
+		Tag:       query.Get("tag"),
+	}
+	if ids := query.Get("ids"); ids != "" {
+		filter.IDs = strings.Split(ids, ",")
+	}
+	anonymize, _ := strconv.ParseBool(query.Get("anonymize_prices"))
+
+	export, err := h.products.ExportCatalog(r.Context(), filter, anonymize)
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to export catalog")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, export)
+}
+
+// ImportCatalog godoc
+// @Summary Import an exported catalog
+// @Description Starts a background job that writes the products of a catalog export, keeping their IDs if asked to
+// @Tags catalog
+// @Accept json
+// @Produce json
+// @Param request body interfaces.CatalogImportRequest true "Catalog export and options"
+// @Success 202 {object} models.Job
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/catalog/import [post]
+func (h *CatalogHandler) ImportCatalog(w http.ResponseWriter, r *http.Request) {
+	var req interfaces.CatalogImportRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	job, err := h.products.ImportCatalog(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRequest) {
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to start catalog import")
+		return
+	}
+	h.writeJob(w, job)
+}
+
+// ListSources godoc
+// @Summary List catalog sources
+// @Description Returns the environments catalogs can be cloned from
+// @Tags catalog
+// @Produce json
+// @Success 200 {array} string
+// @Router /admin/catalog/sources [get]
+func (h *CatalogHandler) ListSources(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, h.cloner.Sources())
+}
+
+// CloneCatalog godoc
+// @Summary Clone a catalog from another environment
+// @Description Fetches the filtered catalog export of a configured environment and starts importing it here, e.g. staging to prod, or prod to test with prices anonymized by the source
+// @Tags catalog
+// @Accept json
+// @Produce json
+// @Param request body interfaces.CatalogCloneRequest true "Source, filter and options"
+// @Success 202 {object} models.Job
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 502 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/catalog/clone [post]
+func (h *CatalogHandler) CloneCatalog(w http.ResponseWriter, r *http.Request) {
+	var req interfaces.CatalogCloneRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	job, err := h.cloner.CloneCatalog(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrCatalogSourceNotFound):
+			respond.Failure(w, http.StatusNotFound, err, err.Error())
+		case errors.Is(err, models.ErrCatalogSourceFailed):
+			respond.Failure(w, http.StatusBadGateway, err, err.Error())
+		case errors.Is(err, models.ErrInvalidRequest):
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+		default:
+			respond.Error(w, http.StatusInternalServerError, "Failed to start catalog clone")
+		}
+		return
+	}
+	h.writeJob(w, job)
+}
+
+// writeJob responds with a started job and where to follow it
+func (h *CatalogHandler) writeJob(w http.ResponseWriter, job *models.Job) {
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	respond.JSON(w, http.StatusAccepted, job)
+}
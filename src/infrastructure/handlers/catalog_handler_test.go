@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockCatalogCloneService is a mock for the CatalogCloneService interface
+type MockCatalogCloneService struct {
+	mock.Mock
+}
+
+func (m *MockCatalogCloneService) Sources() []string {
+	return m.Called().Get(0).([]string)
+}
+
+func (m *MockCatalogCloneService) CloneCatalog(ctx context.Context, req *interfaces.CatalogCloneRequest) (*models.Job, error) {
+	args := m.Called(req)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestCatalogHandlerExport(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewCatalogHandler(mockService, new(MockCatalogCloneService))
+	filter := models.CatalogFilter{IDs: []string{"prod_1", "prod_2"}, SKUPrefix: "SHIRT", Market: "SE"}
+	mockService.On("ExportCatalog", filter, true).Return(&models.CatalogExport{
+		Filter:           filter,
+		PricesAnonymized: true,
+		Products:         []*models.Product{{ID: "prod_1"}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	handler.ExportCatalog(w, httptest.NewRequest("GET", "/admin/catalog/export?ids=prod_1,prod_2&sku_prefix=SHIRT&market=SE&anonymize_prices=true", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var export models.CatalogExport
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&export))
+	assert.True(t, export.PricesAnonymized)
+	assert.Len(t, export.Products, 1)
+}
+
+func TestCatalogHandlerImport(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewCatalogHandler(mockService, new(MockCatalogCloneService))
+	mockService.On("ImportCatalog", mock.MatchedBy(func(req *interfaces.CatalogImportRequest) bool {
+		return req.PreserveIDs && len(req.Catalog.Products) == 1
+	})).Return(&models.Job{ID: "job_1", Type: models.JobCatalogImport}, nil)
+
+	body := `{"catalog": {"products": [{"id": "prod_1"}]}, "preserve_ids": true}`
+	w := httptest.NewRecorder()
+	handler.ImportCatalog(w, httptest.NewRequest("POST", "/admin/catalog/import", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/jobs/job_1", w.Header().Get("Location"))
+
+	w = httptest.NewRecorder()
+	handler.ImportCatalog(w, httptest.NewRequest("POST", "/admin/catalog/import", strings.NewReader("{")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestCatalogHandlerClone(t *testing.T) {
+	tests := []struct {
+		name     string
+		job      *models.Job
+		err      error
+		wantCode int
+	}{
+		{"started", &models.Job{ID: "job_1", Type: models.JobCatalogImport, Source: "staging"}, nil, http.StatusAccepted},
+		{"unknown source", nil, fmt.Errorf("%w: qa", models.ErrCatalogSourceNotFound), http.StatusNotFound},
+		{"source down", nil, fmt.Errorf("%w: staging: connection refused", models.ErrCatalogSourceFailed), http.StatusBadGateway},
+		{"internal", nil, errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cloner := new(MockCatalogCloneService)
+			handler := NewCatalogHandler(new(MockProductService), cloner)
+			cloner.On("CloneCatalog", &interfaces.CatalogCloneRequest{Source: "staging", AnonymizePrices: true}).Return(tt.job, tt.err)
+
+			w := httptest.NewRecorder()
+			handler.CloneCatalog(w, httptest.NewRequest("POST", "/admin/catalog/clone", strings.NewReader(`{"source": "staging", "anonymize_prices": true}`)))
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestCatalogHandlerListSources(t *testing.T) {
+	cloner := new(MockCatalogCloneService)
+	handler := NewCatalogHandler(new(MockProductService), cloner)
+	cloner.On("Sources").Return([]string{"prod", "staging"})
+
+	w := httptest.NewRecorder()
+	handler.ListSources(w, httptest.NewRequest("GET", "/admin/catalog/sources", nil))
+
+	var sources []string
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&sources))
+	assert.Equal(t, []string{"prod", "staging"}, sources)
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// CategoryHandler handles the category taxonomy and category listings
+type CategoryHandler struct {
+	service interfaces.CategoryService
+}
+
+// NewCategoryHandler creates a new category handler instance
+func NewCategoryHandler(service interfaces.CategoryService) *CategoryHandler {
+	return &CategoryHandler{
+		service: service,
+	}
+}
+
+// ListCategories godoc
+// @Summary List categories
+// @Description Returns every category ordered by ID, or with tree=true the roots with their subcategories nested under them, ordered by position and slug
+// @Tags categories
+// @Produce json
+// @Param tree query bool false "Nest subcategories under their parents"
+// @Success 200 {array} models.Category
+// @Failure 500 {object} models.APIError
+// @Router /categories [get]
+func (h *CategoryHandler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	var categories interface{}
+	var err error
+	if nested, _ := strconv.ParseBool(r.URL.Query().Get("tree")); nested {
+		categories, err = h.service.CategoryTree()
+	} else {
+		categories, err = h.service.ListCategories()
+	}
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to list categories")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, categories)
+}
+
+// GetCategory godoc
+// @Summary Get a category
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Success 200 {object} models.Category
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /categories/{id} [get]
+func (h *CategoryHandler) GetCategory(w http.ResponseWriter, r *http.Request) {
+	category, err := h.service.GetCategory(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeCategoryError(w, err, "Failed to fetch category")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, category)
+}
+
+// CreateCategory godoc
+// @Summary Create a category
+// @Description Creates a category, below parent_id if set. Slugs are unique among siblings and categories nest at most 10 deep. Publishes category.created.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param category body models.Category true "Category; the ID is generated"
+// @Success 201 {object} models.Category
+// @Failure 400 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /categories [post]
+func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var category models.Category
+	if err := decodeJSON(r, &category); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	created, err := h.service.CreateCategory(&category)
+	if err != nil {
+		h.writeCategoryError(w, err, "Failed to create category")
+		return
+	}
+
+	w.Header().Set("Location", "/categories/"+created.ID)
+	respond.JSON(w, http.StatusCreated, created)
+}
+
+// UpdateCategory godoc
+// @Summary Replace a category
+// @Description Replaces the slug, names, position and parent of a category. Changing the parent moves the category with its subcategories. Publishes category.updated, with action moved when the parent changed.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param category body models.Category true "Category; the ID is taken from the path"
+// @Success 200 {object} models.Category
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /categories/{id} [put]
+func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	var category models.Category
+	if err := decodeJSON(r, &category); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	category.ID = mux.Vars(r)["id"]
+
+	updated, err := h.service.UpdateCategory(&category)
+	if err != nil {
+		h.writeCategoryError(w, err, "Failed to update category")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, updated)
+}
+
+// DeleteCategory godoc
+// @Summary Delete a category
+// @Description Removes a category that has no subcategories and no products. Publishes category.deleted.
+// @Tags categories
+// @Param id path string true "Category ID"
+// @Success 204
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /categories/{id} [delete]
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteCategory(r.Context(), mux.Vars(r)["id"]); err != nil {
+		h.writeCategoryError(w, err, "Failed to delete category")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListCategoryProducts godoc
+// @Summary List products in a category
+// @Description Returns the products in a category and, unless include_subcategories=false, in every category below it
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID"
+// @Param include_subcategories query bool false "Include products in subcategories" default(true)
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10)
+// @Success 200 {array} models.Product
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /categories/{id}/products [get]
+func (h *CategoryHandler) ListCategoryProducts(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+	includeSubcategories := true
+	if raw := r.URL.Query().Get("include_subcategories"); raw != "" {
+		include, err := strconv.ParseBool(raw)
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, "include_subcategories must be true or false")
+			return
+		}
+		includeSubcategories = include
+	}
+
+	products, total, err := h.service.ListProducts(r.Context(), mux.Vars(r)["id"], includeSubcategories, page, pageSize)
+	if err != nil {
+		h.writeCategoryError(w, err, "Failed to list category products")
+		return
+	}
+
+	response := struct {
+		Data       []*models.Product `json:"data"`
+		Page       int               `json:"page"`
+		PageSize   int               `json:"page_size"`
+		TotalItems int               `json:"total_items"`
+		TotalPages int               `json:"total_pages"`
+	}{
+		Data:       products,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}
+
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// AssignCategories godoc
+// @Summary Assign a product to categories
+// @Description Replaces the categories of a product; every category must exist. An empty list removes the product from all categories. Publishes product.updated with action categories_assigned.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param assignment body object true "Category IDs, e.g. {\"category_ids\": [\"...\"]}"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/categories [put]
+func (h *CategoryHandler) AssignCategories(w http.ResponseWriter, r *http.Request) {
+	var assignment struct {
+		CategoryIDs []string `json:"category_ids"`
+	}
+	if err := decodeJSON(r, &assignment); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	product, err := h.service.AssignCategories(r.Context(), mux.Vars(r)["id"], assignment.CategoryIDs)
+	if err != nil {
+		h.writeCategoryError(w, err, "Failed to assign categories")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, product)
+}
+
+// writeCategoryError maps category errors to status codes
+func (h *CategoryHandler) writeCategoryError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidRequest):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, models.ErrCategoryNotFound), errors.Is(err, models.ErrProductNotFound):
+		respond.Failure(w, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, models.ErrDuplicateCategorySlug), errors.Is(err, models.ErrCategoryInUse), errors.Is(err, models.ErrVersionConflict):
+		respond.Failure(w, http.StatusConflict, err, err.Error())
+	default:
+		respond.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockCategoryService is a mock for the CategoryService interface
+type MockCategoryService struct {
+	mock.Mock
+}
+
+func (m *MockCategoryService) ListCategories() ([]*models.Category, error) {
+	args := m.Called()
+	if categories, ok := args.Get(0).([]*models.Category); ok {
+		return categories, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryService) CategoryTree() ([]*models.CategoryNode, error) {
+	args := m.Called()
+	if nodes, ok := args.Get(0).([]*models.CategoryNode); ok {
+		return nodes, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryService) GetCategory(id string) (*models.Category, error) {
+	args := m.Called(id)
+	if category, ok := args.Get(0).(*models.Category); ok {
+		return category, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryService) CreateCategory(category *models.Category) (*models.Category, error) {
+	args := m.Called(category)
+	if created, ok := args.Get(0).(*models.Category); ok {
+		return created, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryService) UpdateCategory(category *models.Category) (*models.Category, error) {
+	args := m.Called(category)
+	if updated, ok := args.Get(0).(*models.Category); ok {
+		return updated, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryService) DeleteCategory(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockCategoryService) AssignCategories(ctx context.Context, productID string, categoryIDs []string) (*models.Product, error) {
+	args := m.Called(productID, categoryIDs)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCategoryService) ListProducts(ctx context.Context, categoryID string, includeSubcategories bool, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(categoryID, includeSubcategories, page, pageSize)
+	if products, ok := args.Get(0).([]*models.Product); ok {
+		return products, args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+func TestListCategories(t *testing.T) {
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	shirts := &models.Category{ID: "shirts", ParentID: "clothing", Slug: "shirts"}
+	clothing := &models.Category{ID: "clothing", Slug: "clothing"}
+	mockService.On("ListCategories").Return([]*models.Category{clothing, shirts}, nil)
+	mockService.On("CategoryTree").Return([]*models.CategoryNode{{Category: clothing, Children: []*models.CategoryNode{{Category: shirts}}}}, nil)
+
+	w := httptest.NewRecorder()
+	handler.ListCategories(w, httptest.NewRequest("GET", "/categories", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var flat []models.Category
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&flat))
+	assert.Len(t, flat, 2)
+
+	w = httptest.NewRecorder()
+	handler.ListCategories(w, httptest.NewRequest("GET", "/categories?tree=true", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var nested []struct {
+		Slug     string `json:"slug"`
+		Children []struct {
+			Slug string `json:"slug"`
+		} `json:"children"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&nested))
+	assert.Len(t, nested, 1)
+	assert.Equal(t, "shirts", nested[0].Children[0].Slug)
+	mockService.AssertExpectations(t)
+}
+
+func TestCreateCategory(t *testing.T) {
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	mockService.On("CreateCategory", mock.MatchedBy(func(category *models.Category) bool {
+		return category.Slug == "shirts" && category.ParentID == "clothing" && category.Names["SE"] == "Skjortor"
+	})).Return(&models.Category{ID: "cat_1", Slug: "shirts"}, nil)
+
+	w := httptest.NewRecorder()
+	handler.CreateCategory(w, httptest.NewRequest("POST", "/categories", strings.NewReader(`{"slug":"shirts","parent_id":"clothing","names":{"SE":"Skjortor"}}`)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/categories/cat_1", w.Header().Get("Location"))
+	mockService.AssertExpectations(t)
+}
+
+func TestCategoryErrors(t *testing.T) {
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	mockService.On("CreateCategory", mock.Anything).Return(nil, fmt.Errorf("%w: the root already has a child with slug shirts", models.ErrDuplicateCategorySlug))
+	mockService.On("UpdateCategory", mock.Anything).Return(nil, models.ErrCategoryNotFound)
+	mockService.On("DeleteCategory", "clothing").Return(fmt.Errorf("%w: 2 subcategories", models.ErrCategoryInUse))
+	mockService.On("AssignCategories", "prod_1", []string{"missing"}).Return(nil, fmt.Errorf("%w: category missing not found", models.ErrInvalidRequest))
+	mockService.On("ListProducts", "missing", true, 1, 10).Return(nil, 0, models.ErrCategoryNotFound)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/categories", handler.CreateCategory).Methods("POST")
+	router.HandleFunc("/categories/{id}", handler.UpdateCategory).Methods("PUT")
+	router.HandleFunc("/categories/{id}", handler.DeleteCategory).Methods("DELETE")
+	router.HandleFunc("/categories/{id}/products", handler.ListCategoryProducts).Methods("GET")
+	router.HandleFunc("/products/{id}/categories", handler.AssignCategories).Methods("PUT")
+
+	for _, c := range []struct {
+		method, path, body string
+		code               int
+	}{
+		{"POST", "/categories", `{`, http.StatusBadRequest},
+		{"POST", "/categories", `{"slug":"shirts"}`, http.StatusConflict},
+		{"PUT", "/categories/missing", `{"slug":"shirts"}`, http.StatusNotFound},
+		{"DELETE", "/categories/clothing", ``, http.StatusConflict},
+		{"PUT", "/products/prod_1/categories", `{"category_ids":["missing"]}`, http.StatusBadRequest},
+		{"GET", "/categories/missing/products", ``, http.StatusNotFound},
+		{"GET", "/categories/clothing/products?include_subcategories=maybe", ``, http.StatusBadRequest},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(c.method, c.path, strings.NewReader(c.body)))
+		assert.Equal(t, c.code, w.Code, c.method+" "+c.path)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestListCategoryProducts(t *testing.T) {
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	mockService.On("ListProducts", "clothing", false, 2, 1).Return([]*models.Product{{ID: "prod_2"}}, 3, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/categories/{id}/products", handler.ListCategoryProducts)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/categories/clothing/products?include_subcategories=false&page=2&size=1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data       []models.Product `json:"data"`
+		TotalItems int              `json:"total_items"`
+		TotalPages int              `json:"total_pages"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "prod_2", response.Data[0].ID)
+	assert.Equal(t, 3, response.TotalPages)
+	mockService.AssertExpectations(t)
+}
+
+func TestAssignCategories(t *testing.T) {
+	mockService := new(MockCategoryService)
+	handler := NewCategoryHandler(mockService)
+	mockService.On("AssignCategories", "prod_1", []string{"shirts", "sale"}).
+		Return(&models.Product{ID: "prod_1", CategoryIDs: []string{"shirts", "sale"}}, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/categories", handler.AssignCategories)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/products/prod_1/categories", strings.NewReader(`{"category_ids":["shirts","sale"]}`)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var product models.Product
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&product))
+	assert.Equal(t, []string{"shirts", "sale"}, product.CategoryIDs)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// DiagnosticsHandler reports how the service is running
+type DiagnosticsHandler struct {
+	service interfaces.DiagnosticsService
+}
+
+// NewDiagnosticsHandler creates a new diagnostics handler instance
+func NewDiagnosticsHandler(service interfaces.DiagnosticsService) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		service: service,
+	}
+}
+
+// GetDiagnostics godoc
+// @Summary Get diagnostics
+// @Description Reports the build, the backend behind each component, which optional features are enabled, the configuration read from the environment with secrets redacted, the startup self-check results and recent response error counts
+// @Tags admin
+// @Produce json
+// @Success 200 {object} interfaces.Diagnostics
+// @Router /admin/diagnostics [get]
+func (h *DiagnosticsHandler) GetDiagnostics(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, h.service.Diagnostics())
+}
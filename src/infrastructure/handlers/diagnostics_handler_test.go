@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockDiagnosticsService is a mock for the DiagnosticsService interface
+type MockDiagnosticsService struct {
+	mock.Mock
+}
+
+func (m *MockDiagnosticsService) SelfCheck() []*interfaces.CheckResult {
+	return m.Called().Get(0).([]*interfaces.CheckResult)
+}
+
+func (m *MockDiagnosticsService) Diagnostics() *interfaces.Diagnostics {
+	return m.Called().Get(0).(*interfaces.Diagnostics)
+}
+
+func TestDiagnosticsHandlerGetDiagnostics(t *testing.T) {
+	mockService := new(MockDiagnosticsService)
+	handler := NewDiagnosticsHandler(mockService)
+	mockService.On("Diagnostics").Return(&interfaces.Diagnostics{
+		Build:    &models.BuildInfo{Version: "v1.2.0"},
+		Backends: map[string]string{"repository": "memory"},
+		Features: map[string]bool{"admin_login": false},
+		Checks:   []*interfaces.CheckResult{{Name: "repository", OK: true}},
+	})
+
+	w := httptest.NewRecorder()
+	handler.GetDiagnostics(w, httptest.NewRequest("GET", "/admin/diagnostics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var diagnostics interfaces.Diagnostics
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&diagnostics))
+	assert.Equal(t, "v1.2.0", diagnostics.Build.Version)
+	assert.Equal(t, "memory", diagnostics.Backends["repository"])
+	assert.True(t, diagnostics.Checks[0].OK)
+}
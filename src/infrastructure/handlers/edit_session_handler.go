@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// EditSessionRequest claims or renews an edit session
+type EditSessionRequest struct {
+	// Editor names the user editing. Ignored when the caller is logged in, the
+	// session is then claimed for the logged in user.
+	Editor string `json:"editor,omitempty"`
+	// TTLSeconds is how long the session lasts without a renewal, default 120
+	TTLSeconds int `json:"ttl_seconds,omitempty"`
+}
+
+// EditSessionHandler lets admin UIs claim products for editing and see who else is editing
+type EditSessionHandler struct {
+	service interfaces.EditSessionService
+}
+
+// NewEditSessionHandler creates a new edit session handler instance
+func NewEditSessionHandler(service interfaces.EditSessionService) *EditSessionHandler {
+	return &EditSessionHandler{
+		service: service,
+	}
+}
+
+// writeSessionError maps edit session errors to responses
+func (h *EditSessionHandler) writeSessionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidRequest):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, models.ErrProductNotFound):
+		respond.Failure(w, http.StatusNotFound, err, "Product not found")
+	case errors.Is(err, models.ErrEditSessionNotFound):
+		respond.Failure(w, http.StatusNotFound, err, "Edit session not found or expired")
+	default:
+		respond.Error(w, http.StatusInternalServerError, "Failed to update edit session")
+	}
+}
+
+// decodeRequest reads an optional request body
+func (h *EditSessionHandler) decodeRequest(r *http.Request) (*EditSessionRequest, error) {
+	var req EditSessionRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if principal := middleware.PrincipalFromContext(r.Context()); principal != nil {
+		req.Editor = principal.Email
+		if req.Editor == "" {
+			req.Editor = principal.Subject
+		}
+	}
+	return &req, nil
+}
+
+// ClaimEditSession godoc
+// @Summary Claim a product for editing
+// @Description Starts an edit session that expires unless renewed and returns the other editors of the product. Sessions never block updates; they let admin UIs warn about concurrent editors before a version conflict.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param request body EditSessionRequest false "Editor and session length"
+// @Success 201 {object} interfaces.EditClaim
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/products/{id}/edit-sessions [post]
+func (h *EditSessionHandler) ClaimEditSession(w http.ResponseWriter, r *http.Request) {
+	req, err := h.decodeRequest(r)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	claim, err := h.service.Claim(r.Context(), mux.Vars(r)["id"], req.Editor, time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		h.writeSessionError(w, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, claim)
+}
+
+// ListEditSessions godoc
+// @Summary List edit sessions
+// @Description Returns the live edit sessions on a product, oldest first
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {array} models.EditSession
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/products/{id}/edit-sessions [get]
+func (h *EditSessionHandler) ListEditSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := h.service.List(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.writeSessionError(w, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, sessions)
+}
+
+// RenewEditSession godoc
+// @Summary Renew an edit session
+// @Description Extends an edit session, e.g. on a heartbeat from the edit form, and returns the product's current version and other editors
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param session path string true "Session ID"
+// @Param request body EditSessionRequest false "Session length"
+// @Success 200 {object} interfaces.EditClaim
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/products/{id}/edit-sessions/{session} [put]
+func (h *EditSessionHandler) RenewEditSession(w http.ResponseWriter, r *http.Request) {
+	req, err := h.decodeRequest(r)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	claim, err := h.service.Renew(r.Context(), vars["id"], vars["session"], time.Duration(req.TTLSeconds)*time.Second)
+	if err != nil {
+		h.writeSessionError(w, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, claim)
+}
+
+// ReleaseEditSession godoc
+// @Summary Release an edit session
+// @Description Ends an edit session when the editor saves or leaves the form
+// @Tags products
+// @Param id path string true "Product ID"
+// @Param session path string true "Session ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/products/{id}/edit-sessions/{session} [delete]
+func (h *EditSessionHandler) ReleaseEditSession(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.service.Release(vars["id"], vars["session"]); err != nil {
+		h.writeSessionError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
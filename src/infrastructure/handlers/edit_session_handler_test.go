@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+)
+
+// MockEditSessionService is a mock for the EditSessionService interface
+type MockEditSessionService struct {
+	mock.Mock
+}
+
+func (m *MockEditSessionService) Claim(ctx context.Context, productID, editor string, ttl time.Duration) (*interfaces.EditClaim, error) {
+	args := m.Called(productID, editor, ttl)
+	if claim, ok := args.Get(0).(*interfaces.EditClaim); ok {
+		return claim, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockEditSessionService) Renew(ctx context.Context, productID, sessionID string, ttl time.Duration) (*interfaces.EditClaim, error) {
+	args := m.Called(productID, sessionID, ttl)
+	if claim, ok := args.Get(0).(*interfaces.EditClaim); ok {
+		return claim, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockEditSessionService) Release(productID, sessionID string) error {
+	return m.Called(productID, sessionID).Error(0)
+}
+
+func (m *MockEditSessionService) List(ctx context.Context, productID string) ([]*models.EditSession, error) {
+	args := m.Called(productID)
+	if sessions, ok := args.Get(0).([]*models.EditSession); ok {
+		return sessions, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestEditSessionHandlerClaim(t *testing.T) {
+	mockService := new(MockEditSessionService)
+	handler := NewEditSessionHandler(mockService)
+	claim := &interfaces.EditClaim{
+		Session: &models.EditSession{ID: "s2", ProductID: "prod_1", Editor: "grace"},
+		Version: 3,
+		Others:  []*models.EditSession{{ID: "s1", ProductID: "prod_1", Editor: "ada"}},
+	}
+	mockService.On("Claim", "prod_1", "grace", 5*time.Minute).Return(claim, nil)
+
+	req := httptest.NewRequest("POST", "/admin/products/prod_1/edit-sessions", strings.NewReader(`{"editor": "grace", "ttl_seconds": 300}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "prod_1"})
+	w := httptest.NewRecorder()
+	handler.ClaimEditSession(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response interfaces.EditClaim
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, int64(3), response.Version)
+	assert.Equal(t, "ada", response.Others[0].Editor)
+}
+
+func TestEditSessionHandlerClaimAsPrincipal(t *testing.T) {
+	mockService := new(MockEditSessionService)
+	handler := NewEditSessionHandler(mockService)
+	mockService.On("Claim", "prod_1", "ada@example.com", time.Duration(0)).Return(&interfaces.EditClaim{
+		Session: &models.EditSession{ID: "s1", ProductID: "prod_1", Editor: "ada@example.com"},
+	}, nil)
+
+	// A logged in user cannot claim in someone else's name
+	req := httptest.NewRequest("POST", "/admin/products/prod_1/edit-sessions", strings.NewReader(`{"editor": "grace"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "prod_1"})
+	req = req.WithContext(middleware.WithPrincipal(req.Context(), &models.Principal{Subject: "user-1", Email: "ada@example.com"}))
+	w := httptest.NewRecorder()
+	handler.ClaimEditSession(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestEditSessionHandlerRenewAndRelease(t *testing.T) {
+	mockService := new(MockEditSessionService)
+	handler := NewEditSessionHandler(mockService)
+	vars := map[string]string{"id": "prod_1", "session": "s1"}
+	mockService.On("Renew", "prod_1", "s1", time.Duration(0)).Return(&interfaces.EditClaim{
+		Session: &models.EditSession{ID: "s1", ProductID: "prod_1", Editor: "ada"},
+	}, nil)
+	mockService.On("Release", "prod_1", "s1").Return(nil)
+	mockService.On("List", "prod_1").Return([]*models.EditSession{{ID: "s1", Editor: "ada"}}, nil)
+
+	w := httptest.NewRecorder()
+	handler.RenewEditSession(w, mux.SetURLVars(httptest.NewRequest("PUT", "/admin/products/prod_1/edit-sessions/s1", nil), vars))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ListEditSessions(w, mux.SetURLVars(httptest.NewRequest("GET", "/admin/products/prod_1/edit-sessions", nil), vars))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var sessions []*models.EditSession
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&sessions))
+	assert.Len(t, sessions, 1)
+
+	w = httptest.NewRecorder()
+	handler.ReleaseEditSession(w, mux.SetURLVars(httptest.NewRequest("DELETE", "/admin/products/prod_1/edit-sessions/s1", nil), vars))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestEditSessionHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"invalid ttl", models.ErrInvalidRequest, http.StatusBadRequest},
+		{"unknown product", models.ErrProductNotFound, http.StatusNotFound},
+		{"expired session", models.ErrEditSessionNotFound, http.StatusNotFound},
+		{"internal", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockEditSessionService)
+			handler := NewEditSessionHandler(mockService)
+			mockService.On("Renew", "prod_1", "s1", time.Duration(0)).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("PUT", "/admin/products/prod_1/edit-sessions/s1", nil)
+			handler.RenewEditSession(w, mux.SetURLVars(req, map[string]string{"id": "prod_1", "session": "s1"}))
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+
+	handler := NewEditSessionHandler(new(MockEditSessionService))
+	w := httptest.NewRecorder()
+	handler.ClaimEditSession(w, httptest.NewRequest("POST", "/admin/products/prod_1/edit-sessions", strings.NewReader("{")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// productCacheControl lets clients keep product responses but revalidate them
+// before every use, so a new version is never served from a stale copy. The
+// responses depend on the caller's credentials, so shared caches must not
+// keep them.
+const productCacheControl = "private, no-cache"
+
+// productETag returns the entity tag of a product, its version in quotes
+func productETag(product *models.Product) string {
+	return `"` + strconv.FormatInt(product.Version, 10) + `"`
+}
+
+// ifMatches reports whether an If-Match header lists the product's entity tag
+// or is "*". Weak tags never match, as If-Match compares strongly.
+func ifMatches(header string, product *models.Product) bool {
+	etag := productETag(product)
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfMatch lets a request change the product only if its If-Match header
+// matches the current version. Otherwise it writes 428 Precondition Required
+// for a missing header or 412 Precondition Failed with the current ETag, and
+// returns false.
+func (h *ProductHandler) checkIfMatch(w http.ResponseWriter, r *http.Request, product *models.Product) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		respond.Error(w, http.StatusPreconditionRequired, "If-Match with the product's ETag is required")
+		return false
+	}
+	if !ifMatches(header, product) {
+		w.Header().Set("ETag", productETag(product))
+		respond.Error(w, http.StatusPreconditionFailed, "Product has changed since it was read; fetch it again for the current ETag")
+		return false
+	}
+	return true
+}
+
+// listingETag returns a weak entity tag of a listing page from the IDs and
+// versions of its products and the page's position, such as the page number,
+// size and total. It is weak since equal tags promise the same products, not
+// the same bytes.
+func listingETag(products []*models.Product, position ...string) string {
+	hash := sha256.New()
+	for _, product := range products {
+		hash.Write([]byte(product.ID + ":" + strconv.FormatInt(product.Version, 10) + "\n"))
+	}
+	hash.Write([]byte(strings.Join(position, ",")))
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// ifNoneMatches reports whether an If-None-Match header lists the entity tag
+// or is "*". If-None-Match compares weakly, so W/ prefixes are ignored.
+func ifNoneMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(header, ",") {
+		if tag = strings.TrimSpace(tag); tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// notModified reports whether the client's copy of a response is current.
+// If-None-Match decides when present; otherwise If-Modified-Since is checked
+// against the modification time, unless that is zero.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		return ifNoneMatches(header, etag)
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.IsZero() {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
+
+// setValidators sets the caching headers of a response
+func setValidators(w http.ResponseWriter, etag string, modified time.Time) {
+	w.Header().Set("Cache-Control", productCacheControl)
+	w.Header().Set("ETag", etag)
+	if !modified.IsZero() {
+		w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// checkProductNotModified sets the caching headers of a product response and
+// writes 304 Not Modified if the client has the current version. The ETag
+// is the product version, so every change to the product invalidates copies.
+func checkProductNotModified(w http.ResponseWriter, r *http.Request, product *models.Product) bool {
+	setValidators(w, productETag(product), product.UpdatedAt)
+	if !notModified(r, productETag(product), product.UpdatedAt) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// checkListingNotModified sets the caching headers of a listing page and
+// writes 304 Not Modified if the client has the current page. Last-Modified
+// is the newest update on the page, but a product leaving the page does not
+// move it forward, so only If-None-Match can make a listing not modified.
+// Pages in a display currency change with the rates and get no validators.
+func checkListingNotModified(w http.ResponseWriter, r *http.Request, products []*models.Product, position ...string) bool {
+	if r.URL.Query().Get("display_currency") != "" {
+		w.Header().Set("Cache-Control", productCacheControl)
+		return false
+	}
+	var newest time.Time
+	for _, product := range products {
+		if product.UpdatedAt.After(newest) {
+			newest = product.UpdatedAt
+		}
+	}
+	etag := listingETag(products, position...)
+	setValidators(w, etag, newest)
+	if !notModified(r, etag, time.Time{}) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
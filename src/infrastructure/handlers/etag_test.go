@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestIfNoneMatches(t *testing.T) {
+	assert.True(t, ifNoneMatches(`"3"`, `"3"`))
+	assert.True(t, ifNoneMatches(`"1", W/"3"`, `"3"`))
+	assert.True(t, ifNoneMatches(`"abc"`, `W/"abc"`))
+	assert.True(t, ifNoneMatches("*", `"3"`))
+	assert.False(t, ifNoneMatches(`"2"`, `"3"`))
+}
+
+func TestNotModified(t *testing.T) {
+	modified := time.Date(2024, 5, 17, 10, 30, 15, 0, time.UTC)
+	r := httptest.NewRequest("GET", "/products/1", nil)
+	assert.False(t, notModified(r, `"3"`, modified))
+
+	// If-None-Match decides over If-Modified-Since
+	r.Header.Set("If-Modified-Since", "Fri, 17 May 2024 10:30:15 GMT")
+	assert.True(t, notModified(r, `"3"`, modified))
+	r.Header.Set("If-None-Match", `"2"`)
+	assert.False(t, notModified(r, `"3"`, modified))
+
+	// Without a modification time If-Modified-Since is ignored
+	r.Header.Del("If-None-Match")
+	assert.False(t, notModified(r, `"3"`, time.Time{}))
+	r.Header.Set("If-Modified-Since", "yesterday")
+	assert.False(t, notModified(r, `"3"`, modified))
+}
+
+func TestListingETag(t *testing.T) {
+	products := []*models.Product{{ID: "1", Version: 1}, {ID: "2", Version: 1}}
+	etag := listingETag(products, "1", "10")
+	assert.Equal(t, etag, listingETag(products, "1", "10"))
+	assert.NotEqual(t, etag, listingETag(products, "2", "10"))
+	assert.NotEqual(t, etag, listingETag([]*models.Product{products[1], products[0]}, "1", "10"))
+	assert.NotEqual(t, etag, listingETag([]*models.Product{{ID: "1", Version: 2}, products[1]}, "1", "10"))
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/exports"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// exportPageSize is the number of products read and written at a time
+const exportPageSize = 500
+
+// ExportHandler streams the catalog as spreadsheet files
+type ExportHandler struct {
+	service interfaces.ProductService
+}
+
+// NewExportHandler creates a new export handler instance
+func NewExportHandler(service interfaces.ProductService) *ExportHandler {
+	return &ExportHandler{
+		service: service,
+	}
+}
+
+// ExportProducts godoc
+// @Summary Export products as CSV or Excel
+// @Description Streams every product matching the filters, one row per product with a column per field, price currency and market metadata attribute. Column names match the import mapping targets, so the file can be imported again.
+// @Tags products
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "csv (default) or xlsx"
+// @Param sku query string false "Product or variant SKU"
+// @Param market query string false "Only products with metadata for this market"
+// @Param currency query string false "Only products with a price in this currency"
+// @Param tag query string false "Only products with this tag"
+// @Success 200 {file} file
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/export [get]
+func (h *ExportHandler) ExportProducts(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exports.FormatCSV
+	}
+	if format != exports.FormatCSV && format != exports.FormatXLSX {
+		respond.Error(w, http.StatusBadRequest, "format must be csv or xlsx")
+		return
+	}
+	filter, err := productFilterFromQuery(r.URL.Query())
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// A first pass collects the currencies and markets, which become columns
+	layout := exports.NewLayout()
+	err = h.eachPage(r.Context(), filter, func(products []*models.Product) error {
+		for _, product := range products {
+			layout.Collect(product)
+		}
+		return nil
+	})
+	if errors.Is(err, models.ErrInvalidRequest) {
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to export products", zap.Error(err))
+		respond.Error(w, http.StatusInternalServerError, "Failed to export products")
+		return
+	}
+
+	w.Header().Set("Content-Type", exports.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="products.`+format+`"`)
+	writer, _ := exports.NewRowWriter(format, w)
+	flusher, _ := w.(http.Flusher)
+
+	// The second pass writes the rows page by page. Once the first rows are
+	// sent a failure can only cut the file short, so it is logged.
+	rows := 0
+	err = writer.WriteHeader(layout.Columns())
+	if err == nil {
+		err = h.eachPage(r.Context(), filter, func(products []*models.Product) error {
+			for _, product := range products {
+				if err := writer.WriteRow(layout.Row(product)); err != nil {
+					return err
+				}
+			}
+			rows += len(products)
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+			return nil
+		})
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		logger.Error("Export cut short", zap.Error(err), zap.Int("rows", rows))
+		return
+	}
+	logger.Debug("Products exported", zap.String("format", format), zap.Int("rows", rows))
+}
+
+// eachPage calls fn with every page of the products matching the filter
+func (h *ExportHandler) eachPage(ctx context.Context, filter models.ProductFilter, fn func([]*models.Product) error) error {
+	cursor := ""
+	for {
+		products, next, err := h.service.ListProductsAfter(ctx, filter, cursor, exportPageSize)
+		if err != nil {
+			return err
+		}
+		if err := fn(products); err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
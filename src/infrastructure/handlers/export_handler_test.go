@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// exportPages sets up two pages of products, the second with a currency the first lacks
+func exportPages(mockService *MockProductService, filter models.ProductFilter) {
+	mockService.On("ListProductsAfter", filter, "", exportPageSize).Return([]*models.Product{
+		{ID: "prod_1", SKU: "SHIRT-1", BaseTitle: "Shirt", Prices: []models.Price{{Currency: "SEK", Amount: 299}}},
+	}, "cursor_1", nil)
+	mockService.On("ListProductsAfter", filter, "cursor_1", exportPageSize).Return([]*models.Product{
+		{ID: "prod_2", SKU: "JACKET-1", BaseTitle: "Jacket", Prices: []models.Price{{Currency: "EUR", Amount: 99}}},
+	}, "", nil)
+}
+
+func TestExportProductsCSV(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewExportHandler(mockService)
+	exportPages(mockService, models.ProductFilter{Market: "SE", Tags: []string{"summer"}})
+
+	w := httptest.NewRecorder()
+	handler.ExportProducts(w, httptest.NewRequest("GET", "/products/export?market=se&tag=summer", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="products.csv"`, w.Header().Get("Content-Disposition"))
+	assert.True(t, w.Flushed)
+
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+	assert.Equal(t, []string{"prices.EUR", "prices.SEK"}, rows[0][9:11])
+	assert.Equal(t, []string{"prod_1", "SHIRT-1", "Shirt"}, rows[1][:3])
+	assert.Equal(t, []string{"", "299"}, rows[1][9:11])
+	assert.Equal(t, []string{"99", ""}, rows[2][9:11])
+	mockService.AssertNumberOfCalls(t, "ListProductsAfter", 4)
+}
+
+func TestExportProductsXLSX(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewExportHandler(mockService)
+	exportPages(mockService, models.ProductFilter{})
+
+	w := httptest.NewRecorder()
+	handler.ExportProducts(w, httptest.NewRequest("GET", "/products/export?format=xlsx", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", w.Header().Get("Content-Type"))
+	archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	assert.NoError(t, err)
+	assert.Len(t, archive.File, 5)
+}
+
+func TestExportProductsBadRequest(t *testing.T) {
+	for name, target := range map[string]string{
+		"unknown format": "/products/export?format=ods",
+		"invalid filter": "/products/export?min_price=cheap",
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewExportHandler(mockService)
+
+			w := httptest.NewRecorder()
+			handler.ExportProducts(w, httptest.NewRequest("GET", target, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "ListProductsAfter", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestExportProductsListFailure(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewExportHandler(mockService)
+	mockService.On("ListProductsAfter", models.ProductFilter{}, "", exportPageSize).Return([]*models.Product(nil), "", errors.New("database down"))
+
+	w := httptest.NewRecorder()
+	handler.ExportProducts(w, httptest.NewRequest("GET", "/products/export", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+}
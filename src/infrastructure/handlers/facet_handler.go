@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// FacetHandler handles the filter counts of product listings
+type FacetHandler struct {
+	service interfaces.FacetService
+}
+
+// NewFacetHandler creates a new facet handler instance
+func NewFacetHandler(service interfaces.FacetService) *FacetHandler {
+	return &FacetHandler{
+		service: service,
+	}
+}
+
+// ProductFacets godoc
+// @Summary Count products per filter value
+// @Description Counts the products matching the same filters as GET /products per market, currency, variant attribute value (e.g. size and color) and, with a currency, price bucket. A product counts once per value. Without price_buckets about five buckets of a round width cover the prices.
+// @Tags products
+// @Produce json
+// @Param tag query []string false "Only products with every one of these tags" collectionFormat(multi)
+// @Param category_id query []string false "Only products in at least one of these categories" collectionFormat(multi)
+// @Param market query string false "Only products with metadata for this market"
+// @Param currency query string false "Only products with a price in this currency, which the price buckets count"
+// @Param min_price query number false "Only products with a price of at least this amount, in currency if given"
+// @Param max_price query number false "Only products with a price of at most this amount, in currency if given"
+// @Param title query string false "Only products whose base or market title contains this text, ignoring case"
+// @Param price_buckets query string false "Lower bounds of the price buckets, e.g. 0,100,500"
+// @Success 200 {object} models.Facets
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/facets [get]
+func (h *FacetHandler) ProductFacets(w http.ResponseWriter, r *http.Request) {
+	filter, err := productFilterFromQuery(r.URL.Query())
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	request := models.FacetRequest{Currency: filter.Currency}
+	if text := r.URL.Query().Get("price_buckets"); text != "" {
+		for _, field := range strings.Split(text, ",") {
+			boundary, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				respond.Error(w, http.StatusBadRequest, "price_buckets must be comma separated amounts")
+				return
+			}
+			request.PriceBoundaries = append(request.PriceBoundaries, boundary)
+		}
+	}
+
+	facets, err := h.service.Facets(r.Context(), filter, request)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRequest) {
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to count facets")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, facets)
+}
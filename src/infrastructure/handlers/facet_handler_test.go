@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockFacetService is a mock for the FacetService interface
+type MockFacetService struct {
+	mock.Mock
+}
+
+func (m *MockFacetService) Facets(ctx context.Context, filter models.ProductFilter, request models.FacetRequest) (*models.Facets, error) {
+	args := m.Called(filter, request)
+	if facets, ok := args.Get(0).(*models.Facets); ok {
+		return facets, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestProductFacets(t *testing.T) {
+	mockService := new(MockFacetService)
+	mockService.On("Facets",
+		models.ProductFilter{Currency: "SEK", Tags: []string{"sale"}},
+		models.FacetRequest{Currency: "SEK", PriceBoundaries: []float64{0, 100, 500}},
+	).Return(&models.Facets{Total: 2, Currency: "SEK"}, nil)
+
+	w := httptest.NewRecorder()
+	NewFacetHandler(mockService).ProductFacets(w, httptest.NewRequest("GET", "/products/facets?currency=sek&tag=sale&price_buckets=0,100,+500", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":2`)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductFacetsErrors(t *testing.T) {
+	mockService := new(MockFacetService)
+	mockService.On("Facets", models.ProductFilter{}, models.FacetRequest{PriceBoundaries: []float64{5, 1}}).Return(nil, models.ErrInvalidRequest)
+	mockService.On("Facets", models.ProductFilter{}, models.FacetRequest{}).Return(nil, errors.New("database unavailable"))
+	handler := NewFacetHandler(mockService)
+
+	for path, code := range map[string]int{
+		"/products/facets?price_buckets=0,cheap": http.StatusBadRequest,
+		"/products/facets?min_price=low":         http.StatusBadRequest,
+		"/products/facets?price_buckets=5,1":     http.StatusBadRequest,
+		"/products/facets":                       http.StatusInternalServerError,
+	} {
+		w := httptest.NewRecorder()
+		handler.ProductFacets(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, code, w.Code, path)
+	}
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// ForecastHandler accepts forecasting inputs and serves projected stockouts
+type ForecastHandler struct {
+	service interfaces.ForecastService
+}
+
+// NewForecastHandler creates a new forecast handler instance
+func NewForecastHandler(service interfaces.ForecastService) *ForecastHandler {
+	return &ForecastHandler{
+		service: service,
+	}
+}
+
+// PushInputs godoc
+// @Summary Push forecasting inputs
+// @Description Stores stock ledger entries and sales velocities from external systems, e.g. the POS or a BI tool. A batch with any invalid input is rejected as a whole.
+// @Tags forecasting
+// @Accept json
+// @Produce json
+// @Param inputs body interfaces.ForecastInputs true "Ledger entries and velocities"
+// @Success 200 {object} interfaces.ForecastInputsResult
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/forecasting/inputs [post]
+func (h *ForecastHandler) PushInputs(w http.ResponseWriter, r *http.Request) {
+	var inputs interfaces.ForecastInputs
+	if err := decodeJSON(r, &inputs); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	result, err := h.service.PushInputs(&inputs)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRequest) {
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to store forecasting inputs")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, result)
+}
+
+// Stockouts godoc
+// @Summary Projected stockouts
+// @Description Returns the projected stockout date per variant with forecasting inputs, soonest first. Variants that are not selling have no date and come last.
+// @Tags forecasting
+// @Produce json
+// @Param days query int false "Only variants projected to run out within this many days"
+// @Param limit query int false "Maximum number of variants" default(20)
+// @Success 200 {array} models.StockoutForecast
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/forecasting/stockouts [get]
+func (h *ForecastHandler) Stockouts(w http.ResponseWriter, r *http.Request) {
+	var within time.Duration
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil || days <= 0 {
+			respond.Error(w, http.StatusBadRequest, "days must be a positive number")
+			return
+		}
+		within = time.Duration(days) * 24 * time.Hour
+	}
+
+	forecasts, err := h.service.Stockouts(r.Context(), within, limitParam(r))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to forecast stockouts")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, forecasts)
+}
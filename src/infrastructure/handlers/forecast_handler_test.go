@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockForecastService is a mock for the ForecastService interface
+type MockForecastService struct {
+	mock.Mock
+}
+
+func (m *MockForecastService) PushInputs(inputs *interfaces.ForecastInputs) (*interfaces.ForecastInputsResult, error) {
+	args := m.Called(inputs)
+	if result, ok := args.Get(0).(*interfaces.ForecastInputsResult); ok {
+		return result, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockForecastService) Stockouts(ctx context.Context, within time.Duration, limit int) ([]*models.StockoutForecast, error) {
+	args := m.Called(within, limit)
+	if forecasts, ok := args.Get(0).([]*models.StockoutForecast); ok {
+		return forecasts, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestForecastHandlerPushInputs(t *testing.T) {
+	mockService := new(MockForecastService)
+	handler := NewForecastHandler(mockService)
+	mockService.On("PushInputs", mock.MatchedBy(func(inputs *interfaces.ForecastInputs) bool {
+		return len(inputs.Ledger) == 1 && inputs.Velocities[0].UnitsPerDay == 2.5
+	})).Return(&interfaces.ForecastInputsResult{Ledger: 1, Velocities: 1}, nil)
+
+	body := `{"ledger": [{"variant_id": "v1", "delta": -2, "reason": "sale", "at": "2024-03-01T10:00:00Z"}], "velocities": [{"variant_id": "v1", "units_per_day": 2.5}]}`
+	w := httptest.NewRecorder()
+	handler.PushInputs(w, httptest.NewRequest("POST", "/admin/forecasting/inputs", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var result interfaces.ForecastInputsResult
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+	assert.Equal(t, 1, result.Velocities)
+}
+
+func TestForecastHandlerPushInvalidInputs(t *testing.T) {
+	mockService := new(MockForecastService)
+	handler := NewForecastHandler(mockService)
+	mockService.On("PushInputs", mock.Anything).Return(nil, fmt.Errorf("%w: ledger entries need a variant_id", models.ErrInvalidRequest))
+
+	w := httptest.NewRecorder()
+	handler.PushInputs(w, httptest.NewRequest("POST", "/admin/forecasting/inputs", strings.NewReader(`{"ledger": [{}]}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestForecastHandlerStockouts(t *testing.T) {
+	mockService := new(MockForecastService)
+	handler := NewForecastHandler(mockService)
+	stockoutAt := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+	mockService.On("Stockouts", 14*24*time.Hour, 5).Return([]*models.StockoutForecast{
+		{VariantID: "v1", SKU: "FAST", OnHand: 10, UnitsPerDay: 5, StockoutAt: &stockoutAt, Forecaster: "velocity"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	handler.Stockouts(w, httptest.NewRequest("GET", "/admin/forecasting/stockouts?days=14&limit=5", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var forecasts []*models.StockoutForecast
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&forecasts))
+	assert.Equal(t, stockoutAt, *forecasts[0].StockoutAt)
+
+	w = httptest.NewRecorder()
+	handler.Stockouts(w, httptest.NewRequest("GET", "/admin/forecasting/stockouts?days=soon", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
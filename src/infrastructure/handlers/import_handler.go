@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// maxImportRecords is the maximum number of records accepted in one import request
+const maxImportRecords = 1000
+
+// MaxCSVImportBytes is the largest multipart body accepted by a CSV import
+const MaxCSVImportBytes = 64 << 20
+
+// maxImportFieldBytes is the largest form field accepted before the CSV file
+const maxImportFieldBytes = 1 << 20
+
+// ImportHandler handles product import requests
+type ImportHandler struct {
+	service interfaces.ImportService
+}
+
+// NewImportHandler creates a new import handler instance
+func NewImportHandler(service interfaces.ImportService) *ImportHandler {
+	return &ImportHandler{
+		service: service,
+	}
+}
+
+// ImportProducts godoc
+// @Summary Import products with a field mapping
+// @Description Transforms flat records into products using per-field template expressions (e.g. "{{upper .sku}}", "{{mul .price_eur 11.5}}"). In create mode the products are created as a batch job; in update mode each record is merged into the product or variant with the same SKU. Set dry_run to preview the result. A multipart/form-data body imports the CSV file in its file field instead, with the other request fields as form fields sent before it; without a mapping, columns named after a target field are imported as they are.
+// @Tags products
+// @Accept json
+// @Accept mpfd
+// @Produce json
+// @Param request body interfaces.ImportRequest true "Records and mapping"
+// @Success 200 {object} interfaces.ImportResult
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/import [post]
+func (h *ImportHandler) ImportProducts(w http.ResponseWriter, r *http.Request) {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		h.importCSV(w, r)
+		return
+	}
+
+	var req interfaces.ImportRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if len(req.Mapping) == 0 {
+		respond.Error(w, http.StatusBadRequest, "Mapping is required")
+		return
+	}
+	if len(req.Records) == 0 || len(req.Records) > maxImportRecords {
+		respond.Error(w, http.StatusBadRequest, "Between 1 and 1000 records are required")
+		return
+	}
+
+	result, err := h.service.ImportProducts(r.Context(), &req)
+	h.writeResult(w, result, err)
+}
+
+// importCSV streams the file field of a multipart body into the import
+// service. The request fields are read from the form fields before it, so
+// the file itself is never buffered.
+func (h *ImportHandler) importCSV(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxCSVImportBytes)
+	parts, err := r.MultipartReader()
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "Invalid multipart body")
+		return
+	}
+
+	var req interfaces.ImportRequest
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			respond.Error(w, http.StatusBadRequest, "A CSV file is required in the file field")
+			return
+		}
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, "Invalid multipart body")
+			return
+		}
+
+		if part.FormName() == "file" {
+			if req.Source == "" {
+				req.Source = part.FileName()
+			}
+			result, err := h.service.ImportCSV(r.Context(), &req, part)
+			h.writeResult(w, result, err)
+			return
+		}
+
+		value, err := io.ReadAll(io.LimitReader(part, maxImportFieldBytes))
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, "Invalid multipart body")
+			return
+		}
+		if err := setImportField(&req, part.FormName(), string(value)); err != nil {
+			respond.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+}
+
+// setImportField sets the request field a form field names. mapping and
+// columns are JSON objects like in JSON requests; unknown fields are ignored.
+func setImportField(req *interfaces.ImportRequest, name, value string) error {
+	var err error
+	switch name {
+	case "mapping":
+		err = json.Unmarshal([]byte(value), &req.Mapping)
+	case "columns":
+		err = json.Unmarshal([]byte(value), &req.Columns)
+	case "mode":
+		req.Mode = interfaces.ImportMode(value)
+	case "locale":
+		req.Locale = value
+	case "source":
+		req.Source = value
+	case "dry_run":
+		req.DryRun, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return fmt.Errorf("Invalid %s field", name)
+	}
+	return nil
+}
+
+// writeResult writes the result of an import, or the error that ended it
+func (h *ImportHandler) writeResult(w http.ResponseWriter, result *interfaces.ImportResult, err error) {
+	var tooLarge *http.MaxBytesError
+	switch {
+	case err == nil:
+		respond.JSON(w, http.StatusOK, result)
+	case errors.Is(err, models.ErrInvalidMapping) || errors.Is(err, models.ErrInvalidRequest):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.As(err, &tooLarge):
+		respond.Failure(w, http.StatusRequestEntityTooLarge, err, "CSV file is too large")
+	default:
+		respond.Error(w, http.StatusInternalServerError, "Failed to import products")
+	}
+}
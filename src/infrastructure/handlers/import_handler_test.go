@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockImportService is a mock for the ImportService interface
+type MockImportService struct {
+	mock.Mock
+}
+
+func (m *MockImportService) ImportProducts(ctx context.Context, req *interfaces.ImportRequest) (*interfaces.ImportResult, error) {
+	args := m.Called(req)
+	if result, ok := args.Get(0).(*interfaces.ImportResult); ok {
+		return result, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockImportService) ImportCSV(ctx context.Context, req *interfaces.ImportRequest, file io.Reader) (*interfaces.ImportResult, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	args := m.Called(req, string(content))
+	if result, ok := args.Get(0).(*interfaces.ImportResult); ok {
+		return result, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func newImportRequest(t *testing.T, req interfaces.ImportRequest) *http.Request {
+	body, err := json.Marshal(req)
+	assert.NoError(t, err)
+	return httptest.NewRequest("POST", "/products/import", bytes.NewBuffer(body))
+}
+
+func TestImportProducts(t *testing.T) {
+	mockService := new(MockImportService)
+	handler := NewImportHandler(mockService)
+
+	result := &interfaces.ImportResult{Total: 1, Succeeded: 1, Rows: []*interfaces.ImportRowResult{{Row: 1, ProductID: "prod_1", Success: true}}}
+	mockService.On("ImportProducts", mock.AnythingOfType("*interfaces.ImportRequest")).Return(result, nil)
+
+	w := httptest.NewRecorder()
+	handler.ImportProducts(w, newImportRequest(t, interfaces.ImportRequest{
+		Mapping: map[string]string{"sku": "{{upper .sku}}"},
+		Records: []map[string]string{{"sku": "a"}},
+	}))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response interfaces.ImportResult
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "prod_1", response.Rows[0].ProductID)
+	mockService.AssertExpectations(t)
+}
+
+func TestImportProductsBadRequest(t *testing.T) {
+	tests := map[string]interfaces.ImportRequest{
+		"missing mapping": {Records: []map[string]string{{"sku": "a"}}},
+		"missing records": {Mapping: map[string]string{"sku": "{{.sku}}"}},
+	}
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(MockImportService)
+			handler := NewImportHandler(mockService)
+
+			w := httptest.NewRecorder()
+			handler.ImportProducts(w, newImportRequest(t, req))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "ImportProducts", mock.Anything)
+		})
+	}
+}
+
+func TestImportProductsInvalidMapping(t *testing.T) {
+	mockService := new(MockImportService)
+	handler := NewImportHandler(mockService)
+	mockService.On("ImportProducts", mock.Anything).Return(nil, fmt.Errorf("%w: unknown target field color", models.ErrInvalidMapping))
+
+	w := httptest.NewRecorder()
+	handler.ImportProducts(w, newImportRequest(t, interfaces.ImportRequest{
+		Mapping: map[string]string{"color": "{{.color}}"},
+		Records: []map[string]string{{"color": "blue"}},
+	}))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown target field color")
+}
+
+// newCSVImportRequest builds a multipart import with the form fields before the file
+func newCSVImportRequest(t *testing.T, fields map[string]string, file string) *http.Request {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		assert.NoError(t, form.WriteField(name, value))
+	}
+	if file != "" {
+		part, err := form.CreateFormFile("file", "catalog.csv")
+		assert.NoError(t, err)
+		part.Write([]byte(file))
+	}
+	assert.NoError(t, form.Close())
+
+	req := httptest.NewRequest("POST", "/products/import", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req
+}
+
+func TestImportProductsCSV(t *testing.T) {
+	mockService := new(MockImportService)
+	handler := NewImportHandler(mockService)
+
+	result := &interfaces.ImportResult{Total: 1, Succeeded: 1, Rows: []*interfaces.ImportRowResult{{Row: 1, ProductID: "prod_1", Success: true}}}
+	expected := &interfaces.ImportRequest{
+		Mapping: map[string]string{"sku": "{{.article}}"},
+		Mode:    interfaces.ImportUpdate,
+		DryRun:  true,
+		Source:  "catalog.csv",
+	}
+	mockService.On("ImportCSV", expected, "article\nSHIRT-1\n").Return(result, nil)
+
+	w := httptest.NewRecorder()
+	handler.ImportProducts(w, newCSVImportRequest(t, map[string]string{
+		"mapping": `{"sku": "{{.article}}"}`,
+		"mode":    "update",
+		"dry_run": "true",
+	}, "article\nSHIRT-1\n"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "prod_1")
+	mockService.AssertExpectations(t)
+}
+
+func TestImportProductsCSVBadRequest(t *testing.T) {
+	tests := map[string]*http.Request{
+		"missing file":    newCSVImportRequest(t, map[string]string{"mode": "update"}, ""),
+		"invalid mapping": newCSVImportRequest(t, map[string]string{"mapping": "sku"}, "sku\nA\n"),
+		"invalid dry run": newCSVImportRequest(t, map[string]string{"dry_run": "maybe"}, "sku\nA\n"),
+	}
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(MockImportService)
+			handler := NewImportHandler(mockService)
+
+			w := httptest.NewRecorder()
+			handler.ImportProducts(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "ImportCSV", mock.Anything, mock.Anything)
+		})
+	}
+}
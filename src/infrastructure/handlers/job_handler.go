@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// JobHandler serves the status of batch and import jobs
+type JobHandler struct {
+	service interfaces.JobService
+}
+
+// NewJobHandler creates a new job handler instance
+func NewJobHandler(service interfaces.JobService) *JobHandler {
+	return &JobHandler{
+		service: service,
+	}
+}
+
+// ListJobs godoc
+// @Summary List jobs
+// @Description Returns the most recent batch and import jobs, newest first
+// @Tags jobs
+// @Produce json
+// @Param type query string false "Only jobs of this type, e.g. import"
+// @Param limit query int false "Maximum number of jobs" default(20)
+// @Success 200 {array} models.Job
+// @Failure 500 {object} models.APIError
+// @Router /jobs [get]
+func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.service.ListJobs(models.JobType(r.URL.Query().Get("type")), limitParam(r))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to list jobs")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, jobs)
+}
+
+// GetJob godoc
+// @Summary Get a job
+// @Description Returns the status, counts and errors of a batch or import job
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} models.Job
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /jobs/{id} [get]
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	job, err := h.service.GetJob(id)
+	if err != nil {
+		if errors.Is(err, models.ErrJobNotFound) {
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Job with ID '%s' not found", id))
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to fetch job")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, job)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockJobService is a mock for the JobService interface
+type MockJobService struct {
+	mock.Mock
+}
+
+func (m *MockJobService) GetJob(id string) (*models.Job, error) {
+	args := m.Called(id)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockJobService) ListJobs(jobType models.JobType, limit int) ([]*models.Job, error) {
+	args := m.Called(jobType, limit)
+	if jobs, ok := args.Get(0).([]*models.Job); ok {
+		return jobs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestJobHandlerGetJob(t *testing.T) {
+	mockService := new(MockJobService)
+	handler := NewJobHandler(mockService)
+	mockService.On("GetJob", "job_1").Return(&models.Job{ID: "job_1", Type: models.JobImport, Source: "stock.csv"}, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/jobs/{id}", handler.GetJob)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/jobs/job_1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var job models.Job
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&job))
+	assert.Equal(t, "stock.csv", job.Source)
+}
+
+func TestJobHandlerGetJobErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"not found", models.ErrJobNotFound, http.StatusNotFound},
+		{"storage failure", errors.New("storage down"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockJobService)
+			handler := NewJobHandler(mockService)
+			mockService.On("GetJob", "job_1").Return(nil, tt.err)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/jobs/{id}", handler.GetJob)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/jobs/job_1", nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestJobHandlerListJobs(t *testing.T) {
+	mockService := new(MockJobService)
+	handler := NewJobHandler(mockService)
+	mockService.On("ListJobs", models.JobImport, 5).Return([]*models.Job{{ID: "job_1"}}, nil)
+
+	w := httptest.NewRecorder()
+	handler.ListJobs(w, httptest.NewRequest("GET", "/jobs?type=import&limit=5", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var jobs []*models.Job
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&jobs))
+	assert.Len(t, jobs, 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestJobHandlerListJobsError(t *testing.T) {
+	mockService := new(MockJobService)
+	handler := NewJobHandler(mockService)
+	mockService.On("ListJobs", models.JobType(""), defaultDashboardLimit).Return(nil, errors.New("storage down"))
+
+	w := httptest.NewRecorder()
+	handler.ListJobs(w, httptest.NewRequest("GET", "/jobs", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// errTrailingData is returned by decodeJSON for bodies with more than one JSON value
+var errTrailingData = errors.New("unexpected data after the JSON value")
+
+// decodeJSON decodes the request body into v. Unknown fields and data after
+// the value are errors, so misspelled fields are not silently dropped. An
+// empty body is io.EOF.
+func decodeJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return err
+		}
+		return errTrailingData
+	}
+	return nil
+}
+
+// writeDecodeError writes the error of decodeJSON: 413 Request Entity Too
+// Large for bodies over the limit and 400 Bad Request naming the problem
+// otherwise
+func writeDecodeError(w http.ResponseWriter, err error) {
+	status, code, message := http.StatusBadRequest, respond.CodeInvalidJSON, "Invalid JSON data"
+	var tooLarge *http.MaxBytesError
+	var syntax *json.SyntaxError
+	var mismatch *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &tooLarge):
+		status, code = http.StatusRequestEntityTooLarge, respond.CodeRequestTooLarge
+		message = fmt.Sprintf("Request body is larger than %d bytes", tooLarge.Limit)
+	case errors.Is(err, io.EOF):
+		message = "Request body is empty"
+	case errors.As(err, &syntax):
+		message = fmt.Sprintf("Invalid JSON data: syntax error at offset %d", syntax.Offset)
+	case errors.As(err, &mismatch) && mismatch.Field != "":
+		message = fmt.Sprintf("Invalid JSON data: field %s cannot be a JSON %s", mismatch.Field, mismatch.Value)
+	case errors.As(err, &mismatch):
+		message = fmt.Sprintf("Invalid JSON data: body cannot be a JSON %s", mismatch.Value)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		message = "Invalid JSON data: unknown field " + strings.TrimPrefix(err.Error(), "json: unknown field ")
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, errTrailingData):
+		message = "Invalid JSON data: " + err.Error()
+	}
+
+	respond.ErrorCode(w, status, code, message)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type request struct {
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	tests := map[string]struct {
+		body     string
+		limit    int64
+		wantCode int
+		wantMsg  string
+	}{
+		"Valid":         {body: `{"name": "a", "count": 1}`, wantCode: http.StatusOK},
+		"Empty":         {body: ``, wantCode: http.StatusBadRequest, wantMsg: "Request body is empty"},
+		"Unknown field": {body: `{"name": "a", "cuont": 1}`, wantCode: http.StatusBadRequest, wantMsg: `Invalid JSON data: unknown field "cuont"`},
+		"Wrong type":    {body: `{"count": "one"}`, wantCode: http.StatusBadRequest, wantMsg: "Invalid JSON data: field count cannot be a JSON string"},
+		"Wrong body":    {body: `[1]`, wantCode: http.StatusBadRequest, wantMsg: "Invalid JSON data: body cannot be a JSON array"},
+		"Syntax":        {body: `{"name": }`, wantCode: http.StatusBadRequest, wantMsg: "Invalid JSON data: syntax error at offset 10"},
+		"Truncated":     {body: `{"name": "a"`, wantCode: http.StatusBadRequest, wantMsg: "Invalid JSON data: unexpected EOF"},
+		"Trailing data": {body: `{"name": "a"} {"name": "b"}`, wantCode: http.StatusBadRequest, wantMsg: "Invalid JSON data: unexpected data after the JSON value"},
+		"Too large":     {body: `{"name": "` + strings.Repeat("a", 64) + `"}`, limit: 16, wantCode: http.StatusRequestEntityTooLarge, wantMsg: "Request body is larger than 16 bytes"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/", strings.NewReader(tt.body))
+			if tt.limit > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, tt.limit)
+			}
+
+			var req request
+			err := decodeJSON(r, &req)
+			if tt.wantCode == http.StatusOK {
+				assert.NoError(t, err)
+				assert.Equal(t, request{Name: "a", Count: 1}, req)
+				return
+			}
+			writeDecodeError(w, err)
+			assert.Equal(t, tt.wantCode, w.Code)
+			var response models.APIError
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantCode, response.Status)
+			assert.Equal(t, tt.wantMsg, response.Message)
+		})
+	}
+}
+
+func BenchmarkEncodeJSON(b *testing.B) {
+	products := newBenchProductService(10).products
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		respond.Encode(httptest.NewRecorder(), products)
+	}
+}
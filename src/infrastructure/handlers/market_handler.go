@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// MarketHandler handles market rollout requests
+type MarketHandler struct {
+	service interfaces.MarketService
+}
+
+// NewMarketHandler creates a new market handler instance
+func NewMarketHandler(service interfaces.MarketService) *MarketHandler {
+	return &MarketHandler{
+		service: service,
+	}
+}
+
+// LaunchChecklist godoc
+// @Summary Market launch checklist
+// @Description Lists products blocked from launching in a market (missing translation, price, stock or image) with reasons
+// @Tags markets
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Param currency query string false "Currency to require a price in (defaults to the market currency)"
+// @Success 200 {object} models.LaunchChecklist
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/launch-checklist [get]
+func (h *MarketHandler) LaunchChecklist(w http.ResponseWriter, r *http.Request) {
+	market := mux.Vars(r)["market"]
+
+	checklist, err := h.service.LaunchChecklist(r.Context(), market, r.URL.Query().Get("currency"))
+	if err != nil {
+		if errors.Is(err, models.ErrUnknownMarketCurrency) {
+			respond.Failure(w, http.StatusBadRequest, err, "Unknown market currency, pass the currency query parameter")
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to build launch checklist")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, checklist)
+}
+
+// ListMarketProducts godoc
+// @Summary List products in a market
+// @Description Returns the products with metadata for the market. Pinned products take their merchandising positions for the category; the others follow oldest first, so pages are stable.
+// @Tags markets
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Param category query string false "Curated category whose pins apply; empty for the full listing"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10)
+// @Success 200 {array} models.Product
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/products [get]
+func (h *MarketHandler) ListMarketProducts(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+
+	products, total, err := h.service.ListMarketProducts(r.Context(), mux.Vars(r)["market"], r.URL.Query().Get("category"), page, pageSize)
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to list market products")
+		return
+	}
+
+	response := struct {
+		Data       []*models.Product `json:"data"`
+		Page       int               `json:"page"`
+		PageSize   int               `json:"page_size"`
+		TotalItems int               `json:"total_items"`
+		TotalPages int               `json:"total_pages"`
+	}{
+		Data:       products,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}
+
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// ListMerchandising godoc
+// @Summary List merchandising positions
+// @Description Returns the pinned products of every curated listing in a market
+// @Tags markets
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Success 200 {array} models.Merchandising
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/merchandising [get]
+func (h *MarketHandler) ListMerchandising(w http.ResponseWriter, r *http.Request) {
+	listings, err := h.service.ListMerchandising(mux.Vars(r)["market"])
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to list merchandising")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, listings)
+}
+
+// GetPins godoc
+// @Summary Get merchandising positions
+// @Description Returns the pinned products of one listing
+// @Tags markets
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Param category query string false "Curated category; empty for the full listing"
+// @Success 200 {object} models.Merchandising
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/merchandising/pins [get]
+func (h *MarketHandler) GetPins(w http.ResponseWriter, r *http.Request) {
+	merchandising, err := h.service.GetMerchandising(mux.Vars(r)["market"], r.URL.Query().Get("category"))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to fetch merchandising")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, merchandising)
+}
+
+// SetPins godoc
+// @Summary Replace merchandising positions
+// @Description Replaces every pin of one listing. Each product needs metadata for the market and a slot of its own.
+// @Tags markets
+// @Accept json
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Param category query string false "Curated category; empty for the full listing"
+// @Param pins body models.Merchandising true "Pins; market and category are taken from the path and query"
+// @Success 200 {object} models.Merchandising
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/merchandising/pins [put]
+func (h *MarketHandler) SetPins(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Pins []models.Pin `json:"pins"`
+	}
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	merchandising, err := h.service.SetPins(r.Context(), mux.Vars(r)["market"], r.URL.Query().Get("category"), req.Pins)
+	if err != nil {
+		h.writeMerchandisingError(w, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, merchandising)
+}
+
+// PinProduct godoc
+// @Summary Pin a product
+// @Description Places a product at a 1-based position in one listing, moving it if it was already pinned. A position held by another product is rejected.
+// @Tags markets
+// @Accept json
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Param id path string true "Product ID"
+// @Param category query string false "Curated category; empty for the full listing"
+// @Param pin body models.Pin true "Position; product_id is taken from the path"
+// @Success 200 {object} models.Merchandising
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/merchandising/pins/{id} [put]
+func (h *MarketHandler) PinProduct(w http.ResponseWriter, r *http.Request) {
+	var pin models.Pin
+	if err := decodeJSON(r, &pin); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	merchandising, err := h.service.PinProduct(r.Context(), vars["market"], r.URL.Query().Get("category"), vars["id"], pin.Position)
+	if err != nil {
+		h.writeMerchandisingError(w, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, merchandising)
+}
+
+// UnpinProduct godoc
+// @Summary Unpin a product
+// @Description Removes a product's pin from one listing; it falls back to the default order
+// @Tags markets
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Param id path string true "Product ID"
+// @Param category query string false "Curated category; empty for the full listing"
+// @Success 200 {object} models.Merchandising
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /markets/{market}/merchandising/pins/{id} [delete]
+func (h *MarketHandler) UnpinProduct(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	merchandising, err := h.service.UnpinProduct(vars["market"], r.URL.Query().Get("category"), vars["id"])
+	if err != nil {
+		h.writeMerchandisingError(w, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, merchandising)
+}
+
+// writeMerchandisingError maps merchandising errors to status codes
+func (h *MarketHandler) writeMerchandisingError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidRequest):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, models.ErrProductNotFound), errors.Is(err, models.ErrPinNotFound):
+		respond.Failure(w, http.StatusNotFound, err, err.Error())
+	default:
+		respond.Error(w, http.StatusInternalServerError, "Failed to update merchandising")
+	}
+}
+
+// pageParams reads the optional page and size query parameters
+func pageParams(r *http.Request) (int, int) {
+	page, pageSize := 1, 10
+	if p, err := strconv.Atoi(r.URL.Query().Get("page")); err == nil && p > 0 {
+		page = p
+	}
+	if s, err := strconv.Atoi(r.URL.Query().Get("size")); err == nil && s > 0 {
+		pageSize = s
+	}
+	return page, pageSize
+}
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockMarketService is a mock for the MarketService interface
+type MockMarketService struct {
+	mock.Mock
+}
+
+func (m *MockMarketService) LaunchChecklist(ctx context.Context, market, currency string) (*models.LaunchChecklist, error) {
+	args := m.Called(market, currency)
+	if checklist, ok := args.Get(0).(*models.LaunchChecklist); ok {
+		return checklist, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMarketService) ListMarketProducts(ctx context.Context, market, category string, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(market, category, page, pageSize)
+	if products, ok := args.Get(0).([]*models.Product); ok {
+		return products, args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+func (m *MockMarketService) ListMerchandising(market string) ([]*models.Merchandising, error) {
+	args := m.Called(market)
+	if listings, ok := args.Get(0).([]*models.Merchandising); ok {
+		return listings, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMarketService) GetMerchandising(market, category string) (*models.Merchandising, error) {
+	args := m.Called(market, category)
+	if merchandising, ok := args.Get(0).(*models.Merchandising); ok {
+		return merchandising, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMarketService) SetPins(ctx context.Context, market, category string, pins []models.Pin) (*models.Merchandising, error) {
+	args := m.Called(market, category, pins)
+	if merchandising, ok := args.Get(0).(*models.Merchandising); ok {
+		return merchandising, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMarketService) PinProduct(ctx context.Context, market, category, productID string, position int) (*models.Merchandising, error) {
+	args := m.Called(market, category, productID, position)
+	if merchandising, ok := args.Get(0).(*models.Merchandising); ok {
+		return merchandising, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockMarketService) UnpinProduct(market, category, productID string) (*models.Merchandising, error) {
+	args := m.Called(market, category, productID)
+	if merchandising, ok := args.Get(0).(*models.Merchandising); ok {
+		return merchandising, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestLaunchChecklist(t *testing.T) {
+	mockService := new(MockMarketService)
+	handler := NewMarketHandler(mockService)
+
+	checklist := &models.LaunchChecklist{
+		Market:        "SE",
+		Currency:      "SEK",
+		TotalProducts: 1,
+		BlockedCount:  1,
+		Blocked: []*models.BlockedProduct{{
+			ProductID: "prod_1",
+			Blockers:  []models.LaunchBlocker{{Code: models.BlockerNoImage, Reason: "product has no images"}},
+		}},
+	}
+	mockService.On("LaunchChecklist", "SE", "").Return(checklist, nil)
+
+	req := httptest.NewRequest("GET", "/markets/SE/launch-checklist", nil)
+	req = mux.SetURLVars(req, map[string]string{"market": "SE"})
+	w := httptest.NewRecorder()
+
+	handler.LaunchChecklist(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.LaunchChecklist
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, *checklist, response)
+	mockService.AssertExpectations(t)
+}
+
+func TestLaunchChecklistErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"unknown market currency", fmt.Errorf("%w: XX", models.ErrUnknownMarketCurrency), http.StatusBadRequest},
+		{"service failure", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMarketService)
+			handler := NewMarketHandler(mockService)
+			mockService.On("LaunchChecklist", "XX", "").Return(nil, tt.err)
+
+			req := httptest.NewRequest("GET", "/markets/XX/launch-checklist", nil)
+			req = mux.SetURLVars(req, map[string]string{"market": "XX"})
+			w := httptest.NewRecorder()
+
+			handler.LaunchChecklist(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestListMarketProducts(t *testing.T) {
+	mockService := new(MockMarketService)
+	handler := NewMarketHandler(mockService)
+	products := []*models.Product{{ID: "prod_pinned"}, {ID: "prod_other"}}
+	mockService.On("ListMarketProducts", "SE", "shirts", 2, 2).Return(products, 5, nil)
+
+	req := httptest.NewRequest("GET", "/markets/SE/products?category=shirts&page=2&size=2", nil)
+	req = mux.SetURLVars(req, map[string]string{"market": "SE"})
+	w := httptest.NewRecorder()
+	handler.ListMarketProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data       []*models.Product `json:"data"`
+		TotalPages int               `json:"total_pages"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "prod_pinned", response.Data[0].ID)
+	assert.Equal(t, 3, response.TotalPages)
+}
+
+func TestSetPins(t *testing.T) {
+	mockService := new(MockMarketService)
+	handler := NewMarketHandler(mockService)
+	pins := []models.Pin{{ProductID: "prod_1", Position: 1}}
+	mockService.On("SetPins", "SE", "shirts", pins).Return(&models.Merchandising{Market: "SE", Category: "shirts", Pins: pins}, nil)
+
+	req := httptest.NewRequest("PUT", "/markets/SE/merchandising/pins?category=shirts", strings.NewReader(`{"pins": [{"product_id": "prod_1", "position": 1}]}`))
+	req = mux.SetURLVars(req, map[string]string{"market": "SE"})
+	w := httptest.NewRecorder()
+	handler.SetPins(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.Merchandising
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, pins, response.Pins)
+}
+
+func TestPinProductErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"position taken", fmt.Errorf("%w: position 1 is taken", models.ErrInvalidRequest), http.StatusBadRequest},
+		{"unknown product", models.ErrProductNotFound, http.StatusNotFound},
+		{"service failure", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMarketService)
+			handler := NewMarketHandler(mockService)
+			mockService.On("PinProduct", "SE", "", "prod_1", 1).Return(nil, tt.err)
+
+			req := httptest.NewRequest("PUT", "/markets/SE/merchandising/pins/prod_1", strings.NewReader(`{"position": 1}`))
+			req = mux.SetURLVars(req, map[string]string{"market": "SE", "id": "prod_1"})
+			w := httptest.NewRecorder()
+			handler.PinProduct(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestUnpinProductNotPinned(t *testing.T) {
+	mockService := new(MockMarketService)
+	handler := NewMarketHandler(mockService)
+	mockService.On("UnpinProduct", "SE", "", "prod_1").Return(nil, models.ErrPinNotFound)
+
+	req := httptest.NewRequest("DELETE", "/markets/SE/merchandising/pins/prod_1", nil)
+	req = mux.SetURLVars(req, map[string]string{"market": "SE", "id": "prod_1"})
+	w := httptest.NewRecorder()
+	handler.UnpinProduct(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
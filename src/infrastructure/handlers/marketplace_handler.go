@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// MarketplaceHandler serves the sync status of marketplace exports
+type MarketplaceHandler struct {
+	service interfaces.MarketplaceService
+}
+
+// NewMarketplaceHandler creates a new marketplace handler instance
+func NewMarketplaceHandler(service interfaces.MarketplaceService) *MarketplaceHandler {
+	return &MarketplaceHandler{
+		service: service,
+	}
+}
+
+// ListMarketplaces godoc
+// @Summary List marketplaces
+// @Description Returns the names of the marketplaces products are exported to
+// @Tags marketplaces
+// @Produce json
+// @Success 200 {array} string
+// @Router /marketplaces [get]
+func (h *MarketplaceHandler) ListMarketplaces(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, h.service.Marketplaces())
+}
+
+// SyncStatuses godoc
+// @Summary Marketplace sync status
+// @Description Returns the latest per-product sync statuses for a marketplace, newest first
+// @Tags marketplaces
+// @Produce json
+// @Param marketplace path string true "Marketplace name, e.g. amazon"
+// @Param state query string false "Only statuses in this state: synced, failed, skipped or removed"
+// @Param limit query int false "Maximum number of statuses" default(20)
+// @Success 200 {array} models.SyncStatus
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /marketplaces/{marketplace}/sync-status [get]
+func (h *MarketplaceHandler) SyncStatuses(w http.ResponseWriter, r *http.Request) {
+	marketplace := mux.Vars(r)["marketplace"]
+
+	statuses, err := h.service.SyncStatuses(marketplace, models.SyncState(r.URL.Query().Get("state")), limitParam(r))
+	if err != nil {
+		if errors.Is(err, models.ErrMarketplaceNotFound) {
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Marketplace '%s' not found", marketplace))
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to fetch sync statuses")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, statuses)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockMarketplaceService is a mock for the MarketplaceService interface
+type MockMarketplaceService struct {
+	mock.Mock
+}
+
+func (m *MockMarketplaceService) Marketplaces() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *MockMarketplaceService) SyncStatuses(marketplace string, state models.SyncState, limit int) ([]*models.SyncStatus, error) {
+	args := m.Called(marketplace, state, limit)
+	if statuses, ok := args.Get(0).([]*models.SyncStatus); ok {
+		return statuses, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestMarketplaceHandlerListMarketplaces(t *testing.T) {
+	mockService := new(MockMarketplaceService)
+	mockService.On("Marketplaces").Return([]string{"amazon", "zalando"})
+	handler := NewMarketplaceHandler(mockService)
+
+	w := httptest.NewRecorder()
+	handler.ListMarketplaces(w, httptest.NewRequest("GET", "/marketplaces", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var names []string
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&names))
+	assert.Equal(t, []string{"amazon", "zalando"}, names)
+}
+
+func TestMarketplaceHandlerSyncStatuses(t *testing.T) {
+	mockService := new(MockMarketplaceService)
+	mockService.On("SyncStatuses", "amazon", models.SyncStateFailed, 5).Return([]*models.SyncStatus{
+		{ProductID: "prod_1", Target: "marketplace:amazon", State: models.SyncStateFailed, Error: "missing EUR price"},
+	}, nil)
+	handler := NewMarketplaceHandler(mockService)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/marketplaces/{marketplace}/sync-status", handler.SyncStatuses)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/marketplaces/amazon/sync-status?state=failed&limit=5", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var statuses []*models.SyncStatus
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&statuses))
+	assert.Len(t, statuses, 1)
+	assert.Equal(t, "missing EUR price", statuses[0].Error)
+}
+
+func TestMarketplaceHandlerSyncStatusesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"unknown marketplace", models.ErrMarketplaceNotFound, http.StatusNotFound},
+		{"repository failure", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockMarketplaceService)
+			mockService.On("SyncStatuses", "ebay", models.SyncState(""), 20).Return(nil, tt.err)
+			handler := NewMarketplaceHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/marketplaces/{marketplace}/sync-status", handler.SyncStatuses)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/marketplaces/ebay/sync-status", nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
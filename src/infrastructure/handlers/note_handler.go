@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// NoteRequest writes or edits a note
+type NoteRequest struct {
+	// Author names the user writing. Ignored when the caller is logged in, the
+	// note is then written by the logged in user.
+	Author string `json:"author,omitempty"`
+	Body   string `json:"body"`
+	// ThreadID is the note replied to; empty starts a new thread
+	ThreadID string `json:"thread_id,omitempty"`
+}
+
+// NoteResolution settles or reopens a thread
+type NoteResolution struct {
+	Resolved bool `json:"resolved"`
+}
+
+// NoteHandler lets merchandisers keep internal notes and questions on products
+type NoteHandler struct {
+	service interfaces.NoteService
+}
+
+// NewNoteHandler creates a new note handler instance
+func NewNoteHandler(service interfaces.NoteService) *NoteHandler {
+	return &NoteHandler{
+		service: service,
+	}
+}
+
+// writeNoteError maps note errors to responses
+func (h *NoteHandler) writeNoteError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidRequest):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, models.ErrProductNotFound):
+		respond.Failure(w, http.StatusNotFound, err, "Product not found")
+	case errors.Is(err, models.ErrNoteNotFound):
+		respond.Failure(w, http.StatusNotFound, err, "Note not found")
+	case errors.Is(err, models.ErrNotNoteAuthor):
+		respond.Failure(w, http.StatusForbidden, err, err.Error())
+	default:
+		respond.Error(w, http.StatusInternalServerError, "Failed to update notes")
+	}
+}
+
+// author returns the logged in user, or the author the caller named
+func (h *NoteHandler) author(r *http.Request, named string) string {
+	return callerName(r, named)
+}
+
+// callerName returns the logged in user's email, else their subject, or the
+// name the caller gave when nobody is logged in
+func callerName(r *http.Request, named string) string {
+	if principal := middleware.PrincipalFromContext(r.Context()); principal != nil {
+		if principal.Email != "" {
+			return principal.Email
+		}
+		return principal.Subject
+	}
+	return named
+}
+
+// ListNotes godoc
+// @Summary List product notes
+// @Description Returns the internal note threads on a product with their replies, oldest first. Notes are never part of product responses.
+// @Tags notes
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {array} models.NoteThread
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/products/{id}/notes [get]
+func (h *NoteHandler) ListNotes(w http.ResponseWriter, r *http.Request) {
+	threads, err := h.service.List(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.writeNoteError(w, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, threads)
+}
+
+// AddNote godoc
+// @Summary Add a product note
+// @Description Starts a thread on a product, or replies to one with thread_id
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param note body NoteRequest true "Note"
+// @Success 201 {object} models.ProductNote
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/products/{id}/notes [post]
+func (h *NoteHandler) AddNote(w http.ResponseWriter, r *http.Request) {
+	var req NoteRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	note, err := h.service.Add(r.Context(), mux.Vars(r)["id"], req.ThreadID, h.author(r, req.Author), req.Body)
+	if err != nil {
+		h.writeNoteError(w, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, note)
+}
+
+// EditNote godoc
+// @Summary Edit a product note
+// @Description Replaces the body of a note; only its author may
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param note path string true "Note ID"
+// @Param request body NoteRequest true "New body"
+// @Success 200 {object} models.ProductNote
+// @Failure 400 {object} models.APIError
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/products/{id}/notes/{note} [put]
+func (h *NoteHandler) EditNote(w http.ResponseWriter, r *http.Request) {
+	var req NoteRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	note, err := h.service.Edit(vars["id"], vars["note"], h.author(r, req.Author), req.Body)
+	if err != nil {
+		h.writeNoteError(w, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, note)
+}
+
+// ResolveNote godoc
+// @Summary Resolve a note thread
+// @Description Marks a thread as settled, or reopens it. Anyone may resolve a thread.
+// @Tags notes
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param note path string true "ID of the note starting the thread"
+// @Param resolution body NoteResolution true "Whether the thread is resolved"
+// @Success 200 {object} models.ProductNote
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/products/{id}/notes/{note}/resolution [put]
+func (h *NoteHandler) ResolveNote(w http.ResponseWriter, r *http.Request) {
+	var resolution NoteResolution
+	if err := decodeJSON(r, &resolution); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	note, err := h.service.Resolve(vars["id"], vars["note"], resolution.Resolved)
+	if err != nil {
+		h.writeNoteError(w, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, note)
+}
+
+// DeleteNote godoc
+// @Summary Delete a product note
+// @Description Removes a note, and its replies when it starts a thread; only its author may
+// @Tags notes
+// @Param id path string true "Product ID"
+// @Param note path string true "Note ID"
+// @Param author query string false "Author of the note, when not logged in"
+// @Success 204 "No Content"
+// @Failure 403 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/products/{id}/notes/{note} [delete]
+func (h *NoteHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.service.Delete(vars["id"], vars["note"], h.author(r, r.URL.Query().Get("author"))); err != nil {
+		h.writeNoteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+)
+
+// MockNoteService is a mock for the NoteService interface
+type MockNoteService struct {
+	mock.Mock
+}
+
+func (m *MockNoteService) List(ctx context.Context, productID string) ([]*models.NoteThread, error) {
+	args := m.Called(productID)
+	if threads, ok := args.Get(0).([]*models.NoteThread); ok {
+		return threads, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteService) Add(ctx context.Context, productID, threadID, author, body string) (*models.ProductNote, error) {
+	args := m.Called(productID, threadID, author, body)
+	if note, ok := args.Get(0).(*models.ProductNote); ok {
+		return note, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteService) Edit(productID, noteID, author, body string) (*models.ProductNote, error) {
+	args := m.Called(productID, noteID, author, body)
+	if note, ok := args.Get(0).(*models.ProductNote); ok {
+		return note, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteService) Resolve(productID, threadID string, resolved bool) (*models.ProductNote, error) {
+	args := m.Called(productID, threadID, resolved)
+	if note, ok := args.Get(0).(*models.ProductNote); ok {
+		return note, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockNoteService) Delete(productID, noteID, author string) error {
+	return m.Called(productID, noteID, author).Error(0)
+}
+
+func TestNoteHandlerList(t *testing.T) {
+	mockService := new(MockNoteService)
+	handler := NewNoteHandler(mockService)
+	threads := []*models.NoteThread{{
+		ProductNote: &models.ProductNote{ID: "n1", ProductID: "prod_1", Author: "ada", Body: "Which supplier?"},
+		Replies:     []*models.ProductNote{{ID: "n2", ProductID: "prod_1", ThreadID: "n1", Author: "grace", Body: "Nordic Textiles"}},
+	}}
+	mockService.On("List", "prod_1").Return(threads, nil)
+
+	req := httptest.NewRequest("GET", "/admin/products/prod_1/notes", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "prod_1"})
+	w := httptest.NewRecorder()
+
+	handler.ListNotes(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []map[string]interface{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "n1", response[0]["id"])
+	assert.Len(t, response[0]["replies"], 1)
+}
+
+func TestNoteHandlerAddUsesLoggedInUser(t *testing.T) {
+	mockService := new(MockNoteService)
+	handler := NewNoteHandler(mockService)
+	note := &models.ProductNote{ID: "n2", ProductID: "prod_1", ThreadID: "n1", Author: "ada@example.com", Body: "Fixed"}
+	mockService.On("Add", "prod_1", "n1", "ada@example.com", "Fixed").Return(note, nil)
+
+	req := httptest.NewRequest("POST", "/admin/products/prod_1/notes", strings.NewReader(`{"author":"someone","body":"Fixed","thread_id":"n1"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "prod_1"})
+	req = req.WithContext(middleware.WithPrincipal(req.Context(), &models.Principal{Subject: "user-1", Email: "ada@example.com"}))
+	w := httptest.NewRecorder()
+
+	handler.AddNote(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestNoteHandlerEdit(t *testing.T) {
+	mockService := new(MockNoteService)
+	handler := NewNoteHandler(mockService)
+	mockService.On("Edit", "prod_1", "n1", "grace", "Changed").Return(nil, models.ErrNotNoteAuthor)
+
+	req := httptest.NewRequest("PUT", "/admin/products/prod_1/notes/n1", strings.NewReader(`{"author":"grace","body":"Changed"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "prod_1", "note": "n1"})
+	w := httptest.NewRecorder()
+
+	handler.EditNote(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestNoteHandlerResolve(t *testing.T) {
+	mockService := new(MockNoteService)
+	handler := NewNoteHandler(mockService)
+	mockService.On("Resolve", "prod_1", "n1", true).Return(&models.ProductNote{ID: "n1", Resolved: true}, nil)
+
+	req := httptest.NewRequest("PUT", "/admin/products/prod_1/notes/n1/resolution", strings.NewReader(`{"resolved":true}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "prod_1", "note": "n1"})
+	w := httptest.NewRecorder()
+
+	handler.ResolveNote(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.ProductNote
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.True(t, response.Resolved)
+}
+
+func TestNoteHandlerDelete(t *testing.T) {
+	mockService := new(MockNoteService)
+	handler := NewNoteHandler(mockService)
+	mockService.On("Delete", "prod_1", "n1", "ada").Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/admin/products/prod_1/notes/n1?author=ada", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "prod_1", "note": "n1"})
+	w := httptest.NewRecorder()
+
+	handler.DeleteNote(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestNoteHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+	}{
+		{"invalid JSON", `{`, nil, http.StatusBadRequest},
+		{"invalid note", `{"body":""}`, models.ErrInvalidRequest, http.StatusBadRequest},
+		{"unknown product", `{"body":"x"}`, models.ErrProductNotFound, http.StatusNotFound},
+		{"unknown thread", `{"body":"x","thread_id":"n9"}`, models.ErrNoteNotFound, http.StatusNotFound},
+		{"service failure", `{"body":"x"}`, errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockNoteService)
+			handler := NewNoteHandler(mockService)
+			mockService.On("Add", "prod_1", mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/admin/products/prod_1/notes", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "prod_1"})
+			w := httptest.NewRecorder()
+
+			handler.AddNote(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
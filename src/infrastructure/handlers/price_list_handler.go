@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// PriceListHandler handles price list and price resolution requests
+type PriceListHandler struct {
+	service interfaces.PriceListService
+}
+
+// NewPriceListHandler creates a new price list handler instance
+func NewPriceListHandler(service interfaces.PriceListService) *PriceListHandler {
+	return &PriceListHandler{
+		service: service,
+	}
+}
+
+// ListPriceLists godoc
+// @Summary List price lists
+// @Description Returns every price list with its prices, ordered by ID
+// @Tags pricing
+// @Produce json
+// @Success 200 {array} models.PriceList
+// @Failure 500 {object} models.APIError
+// @Router /price-lists [get]
+func (h *PriceListHandler) ListPriceLists(w http.ResponseWriter, r *http.Request) {
+	lists, err := h.service.ListPriceLists()
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to list price lists")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, lists)
+}
+
+// GetPriceList godoc
+// @Summary Get a price list
+// @Tags pricing
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Success 200 {object} models.PriceList
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /price-lists/{id} [get]
+func (h *PriceListHandler) GetPriceList(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.GetPriceList(mux.Vars(r)["id"])
+	if err != nil {
+		h.writePricingError(w, err, "Failed to fetch price list")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, list)
+}
+
+// CreatePriceList godoc
+// @Summary Create a price list
+// @Description Creates a price list in one currency, scoped to a market and/or customer group. Each price applies to a product, or one of its variants, from valid_from (inclusive) to valid_to (exclusive). Every product whose price changes gets a price.changed event.
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param list body models.PriceList true "Price list; the ID is generated"
+// @Success 201 {object} models.PriceList
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /price-lists [post]
+func (h *PriceListHandler) CreatePriceList(w http.ResponseWriter, r *http.Request) {
+	var list models.PriceList
+	if err := decodeJSON(r, &list); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	created, err := h.service.CreatePriceList(r.Context(), &list)
+	if err != nil {
+		h.writePricingError(w, err, "Failed to create price list")
+		return
+	}
+
+	w.Header().Set("Location", "/price-lists/"+created.ID)
+	respond.JSON(w, http.StatusCreated, created)
+}
+
+// UpdatePriceList godoc
+// @Summary Replace a price list
+// @Description Replaces the scope and prices of a price list. Products whose prices in the list changed get a price.changed event.
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Price list ID"
+// @Param list body models.PriceList true "Price list; the ID is taken from the path"
+// @Success 200 {object} models.PriceList
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /price-lists/{id} [put]
+func (h *PriceListHandler) UpdatePriceList(w http.ResponseWriter, r *http.Request) {
+	var list models.PriceList
+	if err := decodeJSON(r, &list); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	list.ID = mux.Vars(r)["id"]
+
+	updated, err := h.service.UpdatePriceList(r.Context(), &list)
+	if err != nil {
+		h.writePricingError(w, err, "Failed to update price list")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, updated)
+}
+
+// DeletePriceList godoc
+// @Summary Delete a price list
+// @Description Removes a price list; its products fall back to the other lists and their own prices
+// @Tags pricing
+// @Param id path string true "Price list ID"
+// @Success 204
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /price-lists/{id} [delete]
+func (h *PriceListHandler) DeletePriceList(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeletePriceList(r.Context(), mux.Vars(r)["id"]); err != nil {
+		h.writePricingError(w, err, "Failed to delete price list")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResolvePrice godoc
+// @Summary Resolve a product price
+// @Description Returns the price that applies right now, or at a given time: the most specific price list with a valid price (customer group before market before neither, then by descending priority), else the variant's or the product's own price
+// @Tags pricing
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param currency query string true "Currency, e.g. SEK"
+// @Param market query string false "Market code, e.g. SE"
+// @Param customer_group query string false "Customer group, e.g. b2b"
+// @Param variant_id query string false "Variant ID"
+// @Param at query string false "RFC 3339 time to resolve at, default now"
+// @Success 200 {object} models.ResolvedPrice
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/price [get]
+func (h *PriceListHandler) ResolvePrice(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	query := models.PriceQuery{
+		Currency:      values.Get("currency"),
+		Market:        values.Get("market"),
+		CustomerGroup: values.Get("customer_group"),
+		VariantID:     values.Get("variant_id"),
+	}
+	if raw := values.Get("at"); raw != "" {
+		at, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, "at must be an RFC 3339 time")
+			return
+		}
+		query.At = at
+	}
+
+	price, err := h.service.ResolvePrice(r.Context(), mux.Vars(r)["id"], query)
+	if err != nil {
+		h.writePricingError(w, err, "Failed to resolve price")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, price)
+}
+
+// writePricingError maps pricing errors to status codes
+func (h *PriceListHandler) writePricingError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidRequest), errors.Is(err, models.ErrVariantNotFound):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, models.ErrProductNotFound), errors.Is(err, models.ErrPriceListNotFound), errors.Is(err, models.ErrPriceNotFound):
+		respond.Failure(w, http.StatusNotFound, err, err.Error())
+	default:
+		respond.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
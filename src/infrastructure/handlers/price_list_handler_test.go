@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockPriceListService is a mock for the PriceListService interface
+type MockPriceListService struct {
+	mock.Mock
+}
+
+func (m *MockPriceListService) ListPriceLists() ([]*models.PriceList, error) {
+	args := m.Called()
+	if lists, ok := args.Get(0).([]*models.PriceList); ok {
+		return lists, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPriceListService) GetPriceList(id string) (*models.PriceList, error) {
+	args := m.Called(id)
+	if list, ok := args.Get(0).(*models.PriceList); ok {
+		return list, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPriceListService) CreatePriceList(ctx context.Context, list *models.PriceList) (*models.PriceList, error) {
+	args := m.Called(list)
+	if created, ok := args.Get(0).(*models.PriceList); ok {
+		return created, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPriceListService) UpdatePriceList(ctx context.Context, list *models.PriceList) (*models.PriceList, error) {
+	args := m.Called(list)
+	if updated, ok := args.Get(0).(*models.PriceList); ok {
+		return updated, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPriceListService) DeletePriceList(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPriceListService) ResolvePrice(ctx context.Context, productID string, query models.PriceQuery) (*models.ResolvedPrice, error) {
+	args := m.Called(productID, query)
+	if price, ok := args.Get(0).(*models.ResolvedPrice); ok {
+		return price, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPriceListService) PublishScheduledChanges(ctx context.Context, since, until time.Time) (int, error) {
+	args := m.Called(since, until)
+	return args.Int(0), args.Error(1)
+}
+
+func TestCreatePriceList(t *testing.T) {
+	mockService := new(MockPriceListService)
+	handler := NewPriceListHandler(mockService)
+	mockService.On("CreatePriceList", mock.MatchedBy(func(list *models.PriceList) bool {
+		return list.Currency == "SEK" && list.CustomerGroup == "b2b" && len(list.Prices) == 1 && list.Prices[0].ValidFrom != nil
+	})).Return(&models.PriceList{ID: "pl_1", Currency: "SEK"}, nil)
+
+	body := `{"name":"B2B","currency":"SEK","customer_group":"b2b","prices":[{"product_id":"prod_1","amount":80,"valid_from":"2024-06-01T00:00:00Z"}]}`
+	w := httptest.NewRecorder()
+	handler.CreatePriceList(w, httptest.NewRequest("POST", "/price-lists", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "/price-lists/pl_1", w.Header().Get("Location"))
+	mockService.AssertExpectations(t)
+}
+
+func TestPriceListErrors(t *testing.T) {
+	mockService := new(MockPriceListService)
+	handler := NewPriceListHandler(mockService)
+	mockService.On("CreatePriceList", mock.Anything).Return(nil, fmt.Errorf("%w: currency must be an ISO 4217 code", models.ErrInvalidRequest))
+	mockService.On("UpdatePriceList", mock.Anything).Return(nil, models.ErrPriceListNotFound)
+	mockService.On("DeletePriceList", "missing").Return(models.ErrPriceListNotFound)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/price-lists", handler.CreatePriceList).Methods("POST")
+	router.HandleFunc("/price-lists/{id}", handler.UpdatePriceList).Methods("PUT")
+	router.HandleFunc("/price-lists/{id}", handler.DeletePriceList).Methods("DELETE")
+
+	for _, c := range []struct {
+		method, path, body string
+		code               int
+	}{
+		{"POST", "/price-lists", `{`, http.StatusBadRequest},
+		{"POST", "/price-lists", `{"currency":"KR"}`, http.StatusBadRequest},
+		{"PUT", "/price-lists/missing", `{"currency":"SEK"}`, http.StatusNotFound},
+		{"DELETE", "/price-lists/missing", ``, http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(c.method, c.path, strings.NewReader(c.body)))
+		assert.Equal(t, c.code, w.Code, c.method+" "+c.path)
+	}
+}
+
+func TestUpdatePriceListTakesIDFromPath(t *testing.T) {
+	mockService := new(MockPriceListService)
+	handler := NewPriceListHandler(mockService)
+	mockService.On("UpdatePriceList", mock.MatchedBy(func(list *models.PriceList) bool {
+		return list.ID == "pl_1"
+	})).Return(&models.PriceList{ID: "pl_1", Currency: "SEK"}, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/price-lists/{id}", handler.UpdatePriceList)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/price-lists/pl_1", strings.NewReader(`{"id":"other","currency":"SEK"}`)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestResolvePrice(t *testing.T) {
+	mockService := new(MockPriceListService)
+	handler := NewPriceListHandler(mockService)
+	when, _ := time.Parse(time.RFC3339, "2024-06-01T12:00:00Z")
+	query := models.PriceQuery{Currency: "SEK", Market: "SE", CustomerGroup: "b2b", VariantID: "v1", At: when}
+	mockService.On("ResolvePrice", "prod_1", query).
+		Return(&models.ResolvedPrice{ProductID: "prod_1", Currency: "SEK", Amount: 80, Source: models.PriceSourcePriceList, PriceListID: "pl_1"}, nil)
+	mockService.On("ResolvePrice", "prod_1", models.PriceQuery{Currency: "EUR"}).Return(nil, fmt.Errorf("%w: EUR", models.ErrPriceNotFound))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/price", handler.ResolvePrice)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/products/prod_1/price?currency=SEK&market=SE&customer_group=b2b&variant_id=v1&at=2024-06-01T12:00:00Z", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var price models.ResolvedPrice
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&price))
+	assert.Equal(t, "pl_1", price.PriceListID)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/products/prod_1/price?currency=EUR", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/products/prod_1/price?currency=SEK&at=tomorrow", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
@@ -1,38 +1,72 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jimmitjoo/ecom/src/application/interfaces"
 	"github.com/jimmitjoo/ecom/src/domain/models"
 
-	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/infrastructure/cache"
 	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+	"github.com/jimmitjoo/ecom/src/infrastructure/patch"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
 	"go.uber.org/zap"
 )
 
+// maxCompareProducts is the maximum number of products in one comparison
+const maxCompareProducts = 10
+
+// DefaultMaxBatchSize is the most products in one batch request unless configured otherwise
+const DefaultMaxBatchSize = 1000
+
 // ProductHandler handles HTTP requests for product operations
 type ProductHandler struct {
-	service interfaces.ProductService
+	service   interfaces.ProductService
+	jsonCache *cache.ProductJSONCache // encoded products served by GetProduct
+	warmer    *cache.Warmer           // keeps hot products encoded across updates; nil when disabled
+
+	currencies interfaces.CurrencyService // converts prices for display_currency; nil when disabled
+
+	maxBatchSize int // most products in one batch request
 }
 
 // NewProductHandler creates a new product handler instance
 func NewProductHandler(service interfaces.ProductService) *ProductHandler {
 	return &ProductHandler{
-		service: service,
+		service:      service,
+		jsonCache:    cache.NewProductJSONCache(cache.DefaultProductJSONCapacity),
+		maxBatchSize: DefaultMaxBatchSize,
 	}
 }
 
-// writeError is a helper function to write error responses
-func (h *ProductHandler) writeError(w http.ResponseWriter, code int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(models.NewAPIError(message))
+// LimitBatchSize sets the most products in one batch request; larger batches
+// are rejected with 400 Bad Request before any product is touched
+func (h *ProductHandler) LimitBatchSize(size int) {
+	h.maxBatchSize = size
+}
+
+// EnableCacheWarming counts GetProduct requests and returns a warmer that keeps
+// the topN most requested products encoded in the handler's cache. The warmer
+// still needs to be subscribed to product events and started.
+func (h *ProductHandler) EnableCacheWarming(topN int) *cache.Warmer {
+	h.warmer = cache.NewWarmer(h.jsonCache, topN)
+	return h.warmer
+}
+
+// EnableCurrencyConversion lets product reads show prices in the currency of
+// the display_currency query parameter
+func (h *ProductHandler) EnableCurrencyConversion(currencies interfaces.CurrencyService) {
+	h.currencies = currencies
 }
 
 // ListProducts godoc
@@ -41,16 +75,30 @@ func (h *ProductHandler) writeError(w http.ResponseWriter, code int, message str
 // @Tags products
 // @Accept json
 // @Produce json
+// @Param as_of query string false "List the catalog as it existed at this RFC 3339 timestamp"
+// @Param tag query []string false "Only products with every one of these tags" collectionFormat(multi)
+// @Param category_id query []string false "Only products in at least one of these categories" collectionFormat(multi)
+// @Param sku query string false "Only the product with this product or variant SKU"
+// @Param market query string false "Only products with metadata for this market"
+// @Param currency query string false "Only products with a price in this currency"
+// @Param min_price query number false "Only products with a price of at least this amount, in currency if given"
+// @Param max_price query number false "Only products with a price of at most this amount, in currency if given"
+// @Param title query string false "Only products whose base or market title contains this text, ignoring case"
+// @Param created_after query string false "Only products created after this RFC 3339 timestamp"
+// @Param created_before query string false "Only products created before this RFC 3339 timestamp"
+// @Param include_deleted query bool false "Include soft-deleted products"
+// @Param cursor query string false "Opaque cursor from next_cursor; switches to cursor pagination"
+// @Param limit query int false "Page size in cursor pagination, default 10"
+// @Param display_currency query string false "Also show each price converted to this ISO 4217 currency"
+// @Param If-None-Match header string false "ETag of a cached copy of the page; 304 is returned while it is current"
 // @Success 200 {array} models.Product
-// @Failure 500 {object} handlers.ErrorResponse
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
 // @Router /products [get]
 func (h *ProductHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
-	// Create a unique request ID
-	requestID := uuid.New().String()
-
-	// Create a logger with request context
-	logger, _ := logging.NewLogger()
-	logger = logger.WithRequestID(requestID)
+	// Use the logger carrying the request ID
+	logger := logging.FromContext(r.Context())
 
 	// Log the start of request processing
 	logger.Debug("Processing request",
@@ -73,16 +121,66 @@ func (h *ProductHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Optional point in time for historical listings
+	var asOf time.Time
+	if asOfStr := r.URL.Query().Get("as_of"); asOfStr != "" {
+		parsed, err := time.Parse(time.RFC3339, asOfStr)
+		if err != nil {
+			logger.Warn("Invalid as_of parameter", zap.String("as_of", asOfStr))
+			respond.Error(w, http.StatusBadRequest, "as_of must be an RFC 3339 timestamp")
+			return
+		}
+		asOf = parsed
+	}
+
+	// Optional filters, applied by the repository
+	filter, err := productFilterFromQuery(r.URL.Query())
+	if err != nil {
+		logger.Warn("Invalid filter parameter", zap.Error(err))
+		respond.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !filter.IsZero() && !asOf.IsZero() {
+		respond.Error(w, http.StatusBadRequest, "tag and other filters cannot be combined with as_of")
+		return
+	}
+	if err := h.checkDisplayCurrency(r); err != nil {
+		respond.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Cursor pagination, selected by cursor or limit, pages from a position
+	// instead of an offset
+	if query := r.URL.Query(); query.Has("cursor") || query.Has("limit") {
+		if query.Has("page") || query.Has("size") || !asOf.IsZero() {
+			respond.Error(w, http.StatusBadRequest, "cursor and limit cannot be combined with page, size or as_of")
+			return
+		}
+		h.listProductsAfter(w, r, logger, filter)
+		return
+	}
+
 	startTime := time.Now()
-	products, total, err := h.service.ListProducts(page, pageSize)
+	var products []*models.Product
+	var total int
+	if asOf.IsZero() {
+		products, total, err = h.service.ListProducts(r.Context(), filter, page, pageSize)
+	} else {
+		products, total, err = h.service.ListProductsAsOf(r.Context(), asOf, page, pageSize)
+	}
+	countOperation("list", err)
 	duration := time.Since(startTime)
 
+	if errors.Is(err, models.ErrInvalidRequest) {
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to fetch products",
 			zap.Error(err),
 			zap.Duration("duration", duration),
 		)
-		h.writeError(w, http.StatusInternalServerError, "Failed to fetch products")
+		respond.Error(w, http.StatusInternalServerError, "Failed to fetch products")
 		return
 	}
 
@@ -94,22 +192,138 @@ func (h *ProductHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
 		zap.Duration("duration", duration),
 	)
 
+	if checkListingNotModified(w, r, products, strconv.Itoa(page), strconv.Itoa(pageSize), strconv.Itoa(total)) {
+		return
+	}
+	data, ok := h.displayProducts(w, r, products)
+	if !ok {
+		return
+	}
 	response := struct {
-		Data       []*models.Product `json:"data"`
-		Page       int               `json:"page"`
-		PageSize   int               `json:"page_size"`
-		TotalItems int               `json:"total_items"`
-		TotalPages int               `json:"total_pages"`
+		Data       interface{} `json:"data"`
+		Page       int         `json:"page"`
+		PageSize   int         `json:"page_size"`
+		TotalItems int         `json:"total_items"`
+		TotalPages int         `json:"total_pages"`
 	}{
-		Data:       products,
+		Data:       data,
 		Page:       page,
 		PageSize:   pageSize,
 		TotalItems: total,
 		TotalPages: (total + pageSize - 1) / pageSize,
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// listProductsAfter writes a page of products after the request's cursor
+func (h *ProductHandler) listProductsAfter(w http.ResponseWriter, r *http.Request, logger *logging.Logger, filter models.ProductFilter) {
+	limit := 10
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 {
+			respond.Error(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = l
+	}
+
+	startTime := time.Now()
+	products, next, err := h.service.ListProductsAfter(r.Context(), filter, r.URL.Query().Get("cursor"), limit)
+	countOperation("list", err)
+	duration := time.Since(startTime)
+
+	if errors.Is(err, models.ErrInvalidRequest) {
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to fetch products",
+			zap.Error(err),
+			zap.Duration("duration", duration),
+		)
+		respond.Error(w, http.StatusInternalServerError, "Failed to fetch products")
+		return
+	}
+
+	logger.Debug("Request completed successfully",
+		zap.Int("product_count", len(products)),
+		zap.Int("limit", limit),
+		zap.Bool("last_page", next == ""),
+		zap.Duration("duration", duration),
+	)
+
+	if checkListingNotModified(w, r, products, strconv.Itoa(limit), next) {
+		return
+	}
+	data, ok := h.displayProducts(w, r, products)
+	if !ok {
+		return
+	}
+	response := struct {
+		Data       interface{} `json:"data"`
+		Limit      int         `json:"limit"`
+		NextCursor string      `json:"next_cursor,omitempty"`
+	}{
+		Data:       data,
+		Limit:      limit,
+		NextCursor: next,
+	}
+
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// productFilterFromQuery reads the listing filters from query parameters. Tags
+// may be given as tag=a&tag=b or tag=a,b; products need every tag. Category
+// IDs are given the same way; products need one of them.
+func productFilterFromQuery(query url.Values) (models.ProductFilter, error) {
+	filter := models.ProductFilter{
+		SKU:      query.Get("sku"),
+		Market:   query.Get("market"),
+		Currency: query.Get("currency"),
+		Title:    query.Get("title"),
+	}
+	for _, value := range query["tag"] {
+		filter.Tags = append(filter.Tags, strings.Split(value, ",")...)
+	}
+	for _, value := range query["category_id"] {
+		filter.Categories = append(filter.Categories, strings.Split(value, ",")...)
+	}
+
+	prices := []struct {
+		name  string
+		bound **float64
+	}{{"min_price", &filter.MinPrice}, {"max_price", &filter.MaxPrice}}
+	for _, price := range prices {
+		if text := query.Get(price.name); text != "" {
+			amount, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be a number", price.name)
+			}
+			*price.bound = &amount
+		}
+	}
+	times := []struct {
+		name  string
+		bound *time.Time
+	}{{"created_after", &filter.CreatedAfter}, {"created_before", &filter.CreatedBefore}}
+	for _, t := range times {
+		if text := query.Get(t.name); text != "" {
+			parsed, err := time.Parse(time.RFC3339, text)
+			if err != nil {
+				return filter, fmt.Errorf("%s must be an RFC 3339 timestamp", t.name)
+			}
+			*t.bound = parsed
+		}
+	}
+	if text := query.Get("include_deleted"); text != "" {
+		includeDeleted, err := strconv.ParseBool(text)
+		if err != nil {
+			return filter, errors.New("include_deleted must be true or false")
+		}
+		filter.IncludeDeleted = includeDeleted
+	}
+	return filter.Normalize(), nil
 }
 
 // CreateProduct godoc
@@ -120,12 +334,12 @@ func (h *ProductHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param product body models.Product true "Product details"
 // @Success 201 {object} models.Product
-// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 400,409 {object} models.APIError
+// @Failure 422 {object} models.APIError
+// @Failure 423 {object} models.APIError "The SKU is being written"
 // @Router /products [post]
 func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
-	requestID := uuid.New().String()
-	logger, _ := logging.NewLogger()
-	logger = logger.WithRequestID(requestID)
+	logger := logging.FromContext(r.Context())
 
 	logger.Debug("Processing create product request",
 		zap.String("method", r.Method),
@@ -135,22 +349,39 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 
 	startTime := time.Now()
 	var product models.Product
-	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
+	if err := decodeJSON(r, &product); err != nil {
 		logger.Error("Failed to decode request body",
 			zap.Error(err),
 			zap.Duration("duration", time.Since(startTime)),
 		)
-		h.writeError(w, http.StatusBadRequest, "Invalid JSON data")
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := models.ValidateNewProduct(&product); err != nil {
+		logger.Info("Rejected invalid product",
+			zap.Error(err),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+		if !h.sendValidationError(w, err) {
+			respond.Error(w, http.StatusBadRequest, err.Error())
+		}
 		return
 	}
 
-	if err := h.service.CreateProduct(&product); err != nil {
+	err := h.service.CreateProduct(r.Context(), &product)
+	countOperation("create", err)
+	if err != nil {
 		logger.Error("Failed to create product",
 			zap.Error(err),
 			zap.String("product_id", product.ID),
 			zap.Duration("duration", time.Since(startTime)),
 		)
-		h.writeError(w, http.StatusInternalServerError, "Failed to create product")
+		if errors.Is(err, models.ErrInvalidRequest) {
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		respond.ServiceError(w, err, "Failed to create product")
 		return
 	}
 
@@ -160,9 +391,8 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 		zap.Duration("duration", time.Since(startTime)),
 	)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(product)
+	setWarningHeaders(w, models.CheckQuality(&product))
+	respond.JSON(w, http.StatusCreated, product)
 }
 
 // GetProduct godoc
@@ -172,13 +402,15 @@ func (h *ProductHandler) CreateProduct(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param display_currency query string false "Also show the prices converted to this ISO 4217 currency"
+// @Param If-None-Match header string false "ETag of a cached copy; 304 is returned while the version is current"
+// @Param If-Modified-Since header string false "Time of a cached copy; 304 is returned if the product has not changed since"
 // @Success 200 {object} models.Product
-// @Failure 404 {object} handlers.ErrorResponse
+// @Success 304 "The cached copy is current"
+// @Failure 400,404 {object} models.APIError
 // @Router /products/{id} [get]
 func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
-	requestID := uuid.New().String()
-	logger, _ := logging.NewLogger()
-	logger = logger.WithRequestID(requestID)
+	logger := logging.FromContext(r.Context())
 
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -191,14 +423,19 @@ func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 	)
 
 	startTime := time.Now()
-	product, err := h.service.GetProduct(id)
+	product, err := h.service.GetProduct(r.Context(), id)
+	countOperation("get", err)
 	if err != nil {
 		logger.Error("Failed to fetch product",
 			zap.Error(err),
 			zap.String("product_id", id),
 			zap.Duration("duration", time.Since(startTime)),
 		)
-		h.writeError(w, http.StatusNotFound, fmt.Sprintf("Product with ID '%s' not found", id))
+		if errors.Is(err, models.ErrProductNotFound) {
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with ID '%s' not found", id))
+			return
+		}
+		respond.ServiceError(w, err, "Failed to fetch product")
 		return
 	}
 
@@ -207,8 +444,174 @@ func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 		zap.Duration("duration", time.Since(startTime)),
 	)
 
+	if h.warmer != nil {
+		h.warmer.RecordRequest(product.ID)
+	}
+	h.writeProduct(w, r, product)
+}
+
+// GetProductBySKU godoc
+// @Summary Get a product by SKU
+// @Description Fetches the product with the given SKU; SKUs are unique across products
+// @Tags products
+// @Produce json
+// @Param sku path string true "Product SKU"
+// @Param display_currency query string false "Also show the prices converted to this ISO 4217 currency"
+// @Success 200 {object} models.Product
+// @Failure 400,404 {object} models.APIError
+// @Router /products/sku/{sku} [get]
+func (h *ProductHandler) GetProductBySKU(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+	sku := mux.Vars(r)["sku"]
+
+	product, err := h.service.GetProductBySKU(r.Context(), sku)
+	countOperation("get_by_sku", err)
+	if err != nil {
+		if !errors.Is(err, models.ErrProductNotFound) {
+			logger.Error("Failed to fetch product by SKU", zap.Error(err), zap.String("sku", sku))
+			respond.ServiceError(w, err, "Failed to fetch product")
+			return
+		}
+		respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with SKU '%s' not found", sku))
+		return
+	}
+	h.writeProduct(w, r, product)
+}
+
+// writeProduct writes a product with its ETag and Last-Modified, or 304 Not
+// Modified when the client has the current version, serving the encoded
+// document from the cache while the version is unchanged. Products shown in a
+// display currency are encoded per request and always sent in full, since the
+// rates change independently.
+func (h *ProductHandler) writeProduct(w http.ResponseWriter, r *http.Request, product *models.Product) {
+	if r.URL.Query().Get("display_currency") != "" {
+		if err := h.checkDisplayCurrency(r); err != nil {
+			respond.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		displayed, ok := h.displayProducts(w, r, []*models.Product{product})
+		if !ok {
+			return
+		}
+		w.Header().Set("Cache-Control", productCacheControl)
+		respond.JSON(w, http.StatusOK, displayed.([]*models.DisplayedProduct)[0])
+		return
+	}
+
+	if checkProductNotModified(w, r, product) {
+		return
+	}
+	data, hit := h.jsonCache.Get(product.ID, product.Version, product.LastHash)
+	if hit {
+		metrics.ProductJSONCacheRequests.WithLabelValues("hit").Inc()
+	} else {
+		metrics.ProductJSONCacheRequests.WithLabelValues("miss").Inc()
+		var err error
+		if data, err = cache.EncodeProduct(product); err != nil {
+			respond.Error(w, http.StatusInternalServerError, "Failed to encode product")
+			return
+		}
+		h.jsonCache.Put(product.ID, product.Version, product.LastHash, data)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(product)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
+
+// checkDisplayCurrency rejects a display_currency that is not an ISO 4217
+// code or cannot be served since conversion is disabled
+func (h *ProductHandler) checkDisplayCurrency(r *http.Request) error {
+	currency := r.URL.Query().Get("display_currency")
+	if currency == "" {
+		return nil
+	}
+	if h.currencies == nil {
+		return fmt.Errorf("display_currency is not supported, currency conversion is disabled")
+	}
+	if !models.IsCurrencyCode(currency) {
+		return fmt.Errorf("display_currency must be an ISO 4217 code")
+	}
+	return nil
+}
+
+// displayProducts returns the products to write: as they are, or shown in the
+// display_currency of the request. The error is written when they cannot be
+// shown in the currency.
+func (h *ProductHandler) displayProducts(w http.ResponseWriter, r *http.Request, products []*models.Product) (interface{}, bool) {
+	currency := r.URL.Query().Get("display_currency")
+	if currency == "" {
+		return products, true
+	}
+	displayed := make([]*models.DisplayedProduct, len(products))
+	for i, product := range products {
+		var err error
+		if displayed[i], err = h.currencies.DisplayProduct(product, currency); err != nil {
+			if errors.Is(err, models.ErrUnknownCurrency) || errors.Is(err, models.ErrExchangeRateNotFound) {
+				respond.Failure(w, http.StatusBadRequest, err, err.Error())
+				return nil, false
+			}
+			logging.FromContext(r.Context()).Error("Failed to fetch exchange rates", zap.Error(err))
+			respond.Error(w, http.StatusServiceUnavailable, "Exchange rates are unavailable")
+			return nil, false
+		}
+	}
+	return displayed, true
+}
+
+// CompareProducts godoc
+// @Summary Compare products
+// @Description Returns an attribute-aligned comparison matrix with prices per currency, variant attributes and stock
+// @Tags products
+// @Produce json
+// @Param ids query string true "Comma-separated product IDs (2-10)"
+// @Success 200 {object} models.ProductComparison
+// @Failure 400,404 {object} models.APIError
+// @Router /products/compare [get]
+func (h *ProductHandler) CompareProducts(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	ids := make([]string, 0)
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	logger.Debug("Processing compare products request",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.Strings("product_ids", ids),
+		zap.String("remote_addr", r.RemoteAddr),
+	)
+
+	if len(ids) < 2 || len(ids) > maxCompareProducts {
+		respond.Error(w, http.StatusBadRequest, fmt.Sprintf("Between 2 and %d product IDs are required", maxCompareProducts))
+		return
+	}
+
+	startTime := time.Now()
+	comparison, err := h.service.CompareProducts(r.Context(), ids)
+	if err != nil {
+		logger.Error("Failed to compare products",
+			zap.Error(err),
+			zap.Strings("product_ids", ids),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+		if errors.Is(err, models.ErrProductNotFound) {
+			respond.Failure(w, http.StatusNotFound, err, err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to compare products")
+		return
+	}
+
+	logger.Debug("Products compared successfully",
+		zap.Int("product_count", len(comparison.Products)),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	respond.JSON(w, http.StatusOK, comparison)
 }
 
 // UpdateProduct godoc
@@ -218,14 +621,16 @@ func (h *ProductHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param If-Match header string true "ETag of the product version the update is based on"
 // @Param product body models.Product true "Updated product details"
 // @Success 200 {object} models.Product
-// @Failure 400,404 {object} handlers.ErrorResponse
+// @Failure 400,404,409,412,428 {object} models.APIError
+// @Failure 422 {object} models.APIError
+// @Failure 423 {object} models.APIError "The product is being written"
+// @Failure 500 {object} models.APIError
 // @Router /products/{id} [put]
 func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
-	requestID := uuid.New().String()
-	logger, _ := logging.NewLogger()
-	logger = logger.WithRequestID(requestID)
+	logger := logging.FromContext(r.Context())
 
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -238,44 +643,72 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	)
 
 	startTime := time.Now()
-	existingProduct, err := h.service.GetProduct(id)
+	existingProduct, err := h.service.GetProduct(r.Context(), id)
 	if err != nil {
-		logger.Error("Product not found for update",
+		logger.Error("Failed to get product for update",
 			zap.Error(err),
 			zap.String("product_id", id),
 			zap.Duration("duration", time.Since(startTime)),
 		)
-		h.sendError(w, http.StatusNotFound, fmt.Sprintf("Product with ID '%s' not found", id))
+		if errors.Is(err, models.ErrProductNotFound) {
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with ID '%s' not found", id))
+			return
+		}
+		respond.ServiceError(w, err, "Failed to get product")
+		return
+	}
+	if !h.checkIfMatch(w, r, existingProduct) {
 		return
 	}
 
 	var updatedProduct models.Product
-	if err := json.NewDecoder(r.Body).Decode(&updatedProduct); err != nil {
+	if err := decodeJSON(r, &updatedProduct); err != nil {
 		logger.Error("Failed to decode update request body",
 			zap.Error(err),
 			zap.String("product_id", id),
 			zap.Duration("duration", time.Since(startTime)),
 		)
-		h.sendError(w, http.StatusBadRequest, "Invalid JSON data")
+		writeDecodeError(w, err)
 		return
 	}
 
 	// Use ID from URL, not from request body
 	updatedProduct.ID = id
-	// Keep version from existing product
+	// Update the version If-Match was checked against; the service rejects
+	// the update if another one was saved in between
 	updatedProduct.Version = existingProduct.Version
 	// Keep created_at from existing product
 	updatedProduct.CreatedAt = existingProduct.CreatedAt
 	// Update updated_at to now
 	updatedProduct.UpdatedAt = time.Now()
 
-	if err := h.service.UpdateProduct(&updatedProduct); err != nil {
+	if err := models.ValidateProduct(&updatedProduct); err != nil {
+		logger.Info("Rejected invalid product update",
+			zap.Error(err),
+			zap.String("product_id", id),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+		if !h.sendValidationError(w, err) {
+			respond.Error(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	err = h.service.UpdateProduct(r.Context(), &updatedProduct)
+	countOperation("update", err)
+	if err != nil {
 		logger.Error("Failed to update product",
 			zap.Error(err),
 			zap.String("product_id", id),
 			zap.Duration("duration", time.Since(startTime)),
 		)
-		h.sendError(w, http.StatusInternalServerError, fmt.Sprintf("Failed to update product: %v", err))
+		// The update was conditional on If-Match, so a version saved since
+		// the check fails its precondition rather than conflicting
+		if errors.Is(err, models.ErrVersionConflict) {
+			respond.Failure(w, http.StatusPreconditionFailed, err, "Product has changed since it was read; fetch it again for the current ETag")
+			return
+		}
+		respond.ServiceError(w, err, "Failed to update product")
 		return
 	}
 
@@ -285,27 +718,392 @@ func (h *ProductHandler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
 		zap.Duration("duration", time.Since(startTime)),
 	)
 
-	h.sendSuccess(w, http.StatusOK, updatedProduct)
+	setWarningHeaders(w, models.CheckQuality(&updatedProduct))
+	w.Header().Set("ETag", productETag(&updatedProduct))
+	respond.JSON(w, http.StatusOK, SuccessResponse{Success: true, Data: updatedProduct})
+}
+
+// PatchProduct godoc
+// @Summary Partially update a product
+// @Description Applies a JSON Merge Patch (application/merge-patch+json, or application/json) or a JSON Patch (application/json-patch+json) to the current product, validates the result and saves it as the next version. id, version and timestamps cannot be patched.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param If-Match header string true "ETag of the product version the patch is based on"
+// @Param patch body object true "Merge patch object or array of JSON Patch operations"
+// @Success 200 {object} models.Product
+// @Failure 400,404,409,412,415,428 {object} models.APIError
+// @Failure 422 {object} models.APIError
+// @Failure 423 {object} models.APIError "The product is being written"
+// @Failure 500 {object} models.APIError
+// @Router /products/{id} [patch]
+func (h *ProductHandler) PatchProduct(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	id := mux.Vars(r)["id"]
+
+	logger.Debug("Processing patch product request",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.String("product_id", id),
+		zap.String("remote_addr", r.RemoteAddr),
+	)
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != patch.MergePatchType && mediaType != patch.JSONPatchType && mediaType != "application/json") {
+		respond.Error(w, http.StatusUnsupportedMediaType, fmt.Sprintf("Content-Type must be %s or %s", patch.MergePatchType, patch.JSONPatchType))
+		return
+	}
+	if mediaType == "application/json" {
+		mediaType = patch.MergePatchType
+	}
+
+	document, err := io.ReadAll(r.Body)
+	if err != nil {
+		respond.Error(w, http.StatusBadRequest, "Failed to read request body")
+		return
+	}
+
+	startTime := time.Now()
+	current, err := h.service.GetProduct(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with ID '%s' not found", id))
+			return
+		}
+		respond.ServiceError(w, err, "Failed to get product")
+		return
+	}
+	if !h.checkIfMatch(w, r, current) {
+		return
+	}
+
+	product, err := h.service.PatchProduct(r.Context(), id, current.Version, mediaType, document)
+	countOperation("patch", err)
+	if err != nil {
+		logger.Error("Failed to patch product",
+			zap.Error(err),
+			zap.String("product_id", id),
+			zap.String("patch_type", mediaType),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+		if h.sendValidationError(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, models.ErrProductNotFound):
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with ID '%s' not found", id))
+		case errors.Is(err, models.ErrInvalidRequest):
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+		case errors.Is(err, models.ErrVersionConflict):
+			respond.Failure(w, http.StatusPreconditionFailed, err, "Product has changed since it was read; fetch it again for the current ETag")
+		case errors.Is(err, patch.ErrTestFailed):
+			respond.Failure(w, http.StatusConflict, err, err.Error())
+		default:
+			respond.ServiceError(w, err, "Failed to patch product")
+		}
+		return
+	}
+
+	logger.Info("Product patched successfully",
+		zap.String("product_id", id),
+		zap.String("patch_type", mediaType),
+		zap.Int64("version", product.Version),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	setWarningHeaders(w, models.CheckQuality(product))
+	w.Header().Set("ETag", productETag(product))
+	respond.JSON(w, http.StatusOK, product)
+}
+
+// RollbackProduct godoc
+// @Summary Roll back a product to an earlier version
+// @Description Reconstructs the product state at to_version from the event history and applies it as a new version
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param to_version query int true "Version to restore"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/rollback [post]
+func (h *ProductHandler) RollbackProduct(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	id := mux.Vars(r)["id"]
+
+	logger.Debug("Processing rollback product request",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.String("product_id", id),
+		zap.String("remote_addr", r.RemoteAddr),
+	)
+
+	toVersion, err := strconv.ParseInt(r.URL.Query().Get("to_version"), 10, 64)
+	if err != nil || toVersion < 1 {
+		respond.Error(w, http.StatusBadRequest, "to_version must be a positive integer")
+		return
+	}
+
+	startTime := time.Now()
+	product, err := h.service.RollbackProduct(r.Context(), id, toVersion)
+	countOperation("rollback", err)
+	if err != nil {
+		logger.Error("Failed to roll back product",
+			zap.Error(err),
+			zap.String("product_id", id),
+			zap.Int64("to_version", toVersion),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+		switch {
+		case errors.Is(err, models.ErrProductNotFound):
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with ID '%s' not found", id))
+		case errors.Is(err, models.ErrVersionNotFound):
+			respond.Failure(w, http.StatusNotFound, err, err.Error())
+		case errors.Is(err, models.ErrInvalidRollbackVersion):
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+		default:
+			respond.Error(w, http.StatusInternalServerError, "Failed to roll back product")
+		}
+		return
+	}
+
+	logger.Info("Product rolled back successfully",
+		zap.String("product_id", id),
+		zap.Int64("to_version", toVersion),
+		zap.Int64("version", product.Version),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	respond.JSON(w, http.StatusOK, SuccessResponse{Success: true, Data: product})
+}
+
+// ProductEvents godoc
+// @Summary List the events of a product
+// @Description Returns the product's events from from_version on, oldest first, after verifying their hash chain
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param from_version query int false "First version to return, default 1"
+// @Success 200 {array} models.Event
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError "The event chain is broken"
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/events [get]
+func (h *ProductHandler) ProductEvents(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	id := mux.Vars(r)["id"]
+
+	logger.Debug("Processing product events request",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.String("product_id", id),
+		zap.String("remote_addr", r.RemoteAddr),
+	)
+
+	fromVersion := int64(1)
+	if text := r.URL.Query().Get("from_version"); text != "" {
+		parsed, err := strconv.ParseInt(text, 10, 64)
+		if err != nil || parsed < 1 {
+			respond.Error(w, http.StatusBadRequest, "from_version must be a positive integer")
+			return
+		}
+		fromVersion = parsed
+	}
+
+	startTime := time.Now()
+	events, err := h.service.ReplayEvents(r.Context(), id, fromVersion)
+	if err != nil {
+		logger.Error("Failed to replay product events",
+			zap.Error(err),
+			zap.String("product_id", id),
+			zap.Int64("from_version", fromVersion),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+		h.sendHistoryError(w, id, err)
+		return
+	}
+
+	logger.Debug("Product events replayed successfully",
+		zap.String("product_id", id),
+		zap.Int("events", len(events)),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	respond.JSON(w, http.StatusOK, events)
+}
+
+// ListProductVersions godoc
+// @Summary List the versions of a product
+// @Description Lists the versions that can be reconstructed from the product's verified events, oldest first
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {array} models.ProductVersion
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError "The event chain is broken"
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/versions [get]
+func (h *ProductHandler) ListProductVersions(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	versions, err := h.service.ListProductVersions(r.Context(), id)
+	if err != nil {
+		h.sendHistoryError(w, id, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, versions)
+}
+
+// GetProductVersion godoc
+// @Summary Get a product as it was at a version
+// @Description Replays the product's events up to the version and returns the product as it looked then
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param version path int true "Product version"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError "The event chain is broken"
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/versions/{version} [get]
+func (h *ProductHandler) GetProductVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	version, err := strconv.ParseInt(vars["version"], 10, 64)
+	if err != nil || version < 1 {
+		respond.Error(w, http.StatusBadRequest, "version must be a positive integer")
+		return
+	}
+
+	product, err := h.service.GetProductVersion(r.Context(), id, version)
+	if err != nil {
+		h.sendHistoryError(w, id, err)
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, product)
+}
+
+// sendHistoryError responds to a failure to read a product's history
+func (h *ProductHandler) sendHistoryError(w http.ResponseWriter, id string, err error) {
+	switch {
+	case errors.Is(err, models.ErrProductNotFound):
+		respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with ID '%s' not found", id))
+	case errors.Is(err, models.ErrVersionNotFound):
+		respond.Failure(w, http.StatusNotFound, err, err.Error())
+	case errors.Is(err, models.ErrBrokenEventChain):
+		respond.Failure(w, http.StatusConflict, err, err.Error())
+	default:
+		respond.Error(w, http.StatusInternalServerError, "Failed to read product history")
+	}
+}
+
+// RestoreProduct godoc
+// @Summary Restore a soft-deleted product
+// @Description Re-activates a soft-deleted product as a new version and publishes a product.restored event
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} models.Product
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError "The product is not deleted"
+// @Failure 423 {object} models.APIError "The product is being written"
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/restore [post]
+func (h *ProductHandler) RestoreProduct(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	id := mux.Vars(r)["id"]
+
+	logger.Debug("Processing restore product request",
+		zap.String("method", r.Method),
+		zap.String("path", r.URL.Path),
+		zap.String("product_id", id),
+		zap.String("remote_addr", r.RemoteAddr),
+	)
+
+	startTime := time.Now()
+	product, err := h.service.RestoreProduct(r.Context(), id)
+	countOperation("restore", err)
+	if err != nil {
+		logger.Error("Failed to restore product",
+			zap.Error(err),
+			zap.String("product_id", id),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+		switch {
+		case errors.Is(err, models.ErrProductNotFound):
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with ID '%s' not found", id))
+		default:
+			respond.ServiceError(w, err, "Failed to restore product")
+		}
+		return
+	}
+	h.jsonCache.Remove(id)
+
+	logger.Info("Product restored successfully",
+		zap.String("product_id", id),
+		zap.Int64("version", product.Version),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	h.writeProduct(w, r, product)
 }
 
 // DeleteProduct godoc
 // @Summary Delete a product
-// @Description Deletes a product with the given ID
+// @Description Soft-deletes the product with the given ID, so it can be restored, or removes it for good with permanent=true
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param permanent query bool false "Remove the product and not just mark it as deleted"
 // @Success 204 "No Content"
-// @Failure 404 {object} handlers.ErrorResponse
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 423 {object} models.APIError "The product is being written"
 // @Router /products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	if err := h.service.DeleteProduct(id); err != nil {
-		h.writeError(w, http.StatusNotFound, fmt.Sprintf("Product with ID '%s' not found", id))
+	permanent := false
+	if text := r.URL.Query().Get("permanent"); text != "" {
+		parsed, err := strconv.ParseBool(text)
+		if err != nil {
+			respond.Error(w, http.StatusBadRequest, "permanent must be true or false")
+			return
+		}
+		permanent = parsed
+	}
+
+	var err error
+	if permanent {
+		err = h.service.DeleteProduct(r.Context(), id)
+		countOperation("delete", err)
+	} else {
+		_, err = h.service.SoftDeleteProduct(r.Context(), id)
+		countOperation("soft_delete", err)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrProductNotFound):
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with ID '%s' not found", id))
+		default:
+			respond.ServiceError(w, err, "Failed to delete product")
+		}
 		return
 	}
+	h.jsonCache.Remove(id)
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -318,13 +1116,12 @@ func (h *ProductHandler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param products body []models.Product true "Array of products to create"
 // @Success 201 {array} models.Product "Array of created products"
-// @Failure 400 {object} models.APIError "Invalid JSON data"
+// @Failure 400 {object} models.APIError "Invalid JSON data or too many products"
+// @Failure 413 {object} models.APIError "Request body too large"
 // @Failure 500 {object} models.APIError "Internal server error"
 // @Router /products/batch [post]
 func (h *ProductHandler) BatchCreateProducts(w http.ResponseWriter, r *http.Request) {
-	requestID := uuid.New().String()
-	logger, _ := logging.NewLogger()
-	logger = logger.WithRequestID(requestID)
+	logger := logging.FromContext(r.Context())
 
 	logger.Debug("Processing batch create request",
 		zap.String("method", r.Method),
@@ -334,23 +1131,29 @@ func (h *ProductHandler) BatchCreateProducts(w http.ResponseWriter, r *http.Requ
 
 	startTime := time.Now()
 	var products []*models.Product
-	if err := json.NewDecoder(r.Body).Decode(&products); err != nil {
+	if err := decodeJSON(r, &products); err != nil {
 		logger.Error("Failed to decode batch create request",
 			zap.Error(err),
 			zap.Duration("duration", time.Since(startTime)),
 		)
-		h.writeError(w, http.StatusBadRequest, "Invalid JSON data")
+		writeDecodeError(w, err)
+		return
+	}
+
+	if !h.checkBatchSize(w, len(products)) {
 		return
 	}
 
-	results, err := h.service.BatchCreateProducts(products)
+	metrics.BatchOperationSize.Observe(float64(len(products)))
+	results, err := h.service.BatchCreateProducts(r.Context(), products)
+	countOperation("batch_create", err)
 	if err != nil {
 		logger.Error("Batch create operation failed",
 			zap.Error(err),
 			zap.Int("product_count", len(products)),
 			zap.Duration("duration", time.Since(startTime)),
 		)
-		h.writeError(w, http.StatusInternalServerError, "Failed to create products")
+		respond.Error(w, http.StatusInternalServerError, "Failed to create products")
 		return
 	}
 
@@ -360,9 +1163,7 @@ func (h *ProductHandler) BatchCreateProducts(w http.ResponseWriter, r *http.Requ
 		zap.Duration("duration", time.Since(startTime)),
 	)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(results)
+	respond.JSON(w, http.StatusCreated, results)
 }
 
 // BatchUpdateProducts godoc
@@ -373,25 +1174,31 @@ func (h *ProductHandler) BatchCreateProducts(w http.ResponseWriter, r *http.Requ
 // @Produce json
 // @Param products body []models.Product true "Array of products to update with their IDs and new data"
 // @Success 200 {array} models.Product "Array of updated products"
-// @Failure 400 {object} models.APIError "Invalid JSON data or validation errors"
+// @Failure 400 {object} models.APIError "Invalid JSON data, validation errors or too many products"
 // @Failure 404 {object} models.APIError "One or more products not found"
+// @Failure 413 {object} models.APIError "Request body too large"
 // @Failure 500 {object} models.APIError "Internal server error"
 // @Router /products/batch [put]
 func (h *ProductHandler) BatchUpdateProducts(w http.ResponseWriter, r *http.Request) {
 	var products []*models.Product
-	if err := json.NewDecoder(r.Body).Decode(&products); err != nil {
-		h.writeError(w, http.StatusBadRequest, "Invalid JSON data")
+	if err := decodeJSON(r, &products); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
-	results, err := h.service.BatchUpdateProducts(products)
+	if !h.checkBatchSize(w, len(products)) {
+		return
+	}
+
+	metrics.BatchOperationSize.Observe(float64(len(products)))
+	results, err := h.service.BatchUpdateProducts(r.Context(), products)
+	countOperation("batch_update", err)
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Failed to update products")
+		respond.Error(w, http.StatusInternalServerError, "Failed to update products")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
+	respond.JSON(w, http.StatusOK, results)
 }
 
 // BatchDeleteProducts godoc
@@ -402,43 +1209,318 @@ func (h *ProductHandler) BatchUpdateProducts(w http.ResponseWriter, r *http.Requ
 // @Produce json
 // @Param productIDs body []string true "Array of product IDs to delete"
 // @Success 200 {object} map[string]string "Map of product IDs to deletion status"
-// @Failure 400 {object} models.APIError "Invalid JSON data"
+// @Failure 400 {object} models.APIError "Invalid JSON data or too many product IDs"
 // @Failure 404 {object} models.APIError "One or more products not found"
+// @Failure 413 {object} models.APIError "Request body too large"
 // @Failure 500 {object} models.APIError "Internal server error"
 // @Router /products/batch [delete]
 func (h *ProductHandler) BatchDeleteProducts(w http.ResponseWriter, r *http.Request) {
 	var productIDs []string
-	if err := json.NewDecoder(r.Body).Decode(&productIDs); err != nil {
-		h.writeError(w, http.StatusBadRequest, "Invalid JSON data")
+	if err := decodeJSON(r, &productIDs); err != nil {
+		writeDecodeError(w, err)
 		return
 	}
 
-	results, err := h.service.BatchDeleteProducts(productIDs)
+	if !h.checkBatchSize(w, len(productIDs)) {
+		return
+	}
+
+	metrics.BatchOperationSize.Observe(float64(len(productIDs)))
+	results, err := h.service.BatchDeleteProducts(r.Context(), productIDs)
+	countOperation("batch_delete", err)
 	if err != nil {
-		h.writeError(w, http.StatusInternalServerError, "Failed to delete products")
+		respond.Error(w, http.StatusInternalServerError, "Failed to delete products")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
+	respond.JSON(w, http.StatusOK, results)
 }
 
-func (h *ProductHandler) sendError(w http.ResponseWriter, code int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(ErrorResponse{
-		Code:    code,
-		Message: message,
-	})
+// AdjustStock godoc
+// @Summary Adjust product stock
+// @Description Applies stock deltas per variant and location in one conditional update. The request fails without changes if any location without backorders would go below zero.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param adjustments body []models.StockAdjustment true "Stock adjustments"
+// @Success 200 {object} models.Product
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 423 {object} models.APIError "The product is being written"
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/stock [post]
+func (h *ProductHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	id := mux.Vars(r)["id"]
+
+	var adjustments []models.StockAdjustment
+	if err := decodeJSON(r, &adjustments); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	startTime := time.Now()
+	product, err := h.service.AdjustStock(r.Context(), id, adjustments)
+	countOperation("adjust_stock", err)
+	if err != nil {
+		logger.Warn("Failed to adjust stock",
+			zap.Error(err),
+			zap.String("product_id", id),
+			zap.Int("adjustments", len(adjustments)),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+		switch {
+		case errors.Is(err, models.ErrProductNotFound):
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with ID '%s' not found", id))
+		case errors.Is(err, models.ErrVariantNotFound), errors.Is(err, models.ErrInvalidRequest):
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+		default:
+			respond.ServiceError(w, err, "Failed to adjust stock")
+		}
+		return
+	}
+
+	logger.Info("Stock adjusted",
+		zap.String("product_id", id),
+		zap.Int("adjustments", len(adjustments)),
+		zap.Int64("version", product.Version),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	respond.JSON(w, http.StatusOK, SuccessResponse{Success: true, Data: product})
 }
 
-func (h *ProductHandler) sendSuccess(w http.ResponseWriter, code int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	if data != nil {
-		json.NewEncoder(w).Encode(SuccessResponse{
-			Success: true,
-			Data:    data,
-		})
+// RollbackJob godoc
+// @Summary Roll back a batch job
+// @Description Reverts all changes made by a finished batch job as new compensating changes, recorded as a rollback job
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} interfaces.JobRollbackResult
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /jobs/{id}/rollback [post]
+func (h *ProductHandler) RollbackJob(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	id := mux.Vars(r)["id"]
+
+	startTime := time.Now()
+	result, err := h.service.RollbackJob(r.Context(), id)
+	if err != nil {
+		logger.Error("Failed to roll back job",
+			zap.Error(err),
+			zap.String("job_id", id),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+		switch {
+		case errors.Is(err, models.ErrJobNotFound):
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Job with ID '%s' not found", id))
+		case errors.Is(err, models.ErrJobNotFinished):
+			respond.Failure(w, http.StatusConflict, err, err.Error())
+		default:
+			respond.Error(w, http.StatusInternalServerError, "Failed to roll back job")
+		}
+		return
+	}
+
+	logger.Info("Job rolled back",
+		zap.String("job_id", id),
+		zap.String("rollback_job_id", result.Job.ID),
+		zap.Int("succeeded", result.Job.Succeeded),
+		zap.Int("failed", result.Job.Failed),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+
+	respond.JSON(w, http.StatusOK, result)
+}
+
+// ReplaceText godoc
+// @Summary Search and replace text across the catalog
+// @Description Replaces a string or regular expression in titles, descriptions and keywords of the filtered products. With dry_run the changes are returned as a preview; otherwise a background job writes each changed product as an update event tagged with the job.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param replacement body models.TextReplacement true "Text replacement"
+// @Success 200 {object} models.TextReplacementPreview
+// @Success 202 {object} models.Job
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/text/replace [post]
+func (h *ProductHandler) ReplaceText(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	var replacement models.TextReplacement
+	if err := decodeJSON(r, &replacement); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if replacement.DryRun {
+		preview, err := h.service.PreviewTextReplacement(r.Context(), &replacement)
+		if err != nil {
+			h.textReplacementError(w, err)
+			return
+		}
+		respond.JSON(w, http.StatusOK, preview)
+		return
+	}
+
+	job, err := h.service.ReplaceText(r.Context(), &replacement)
+	if err != nil {
+		logger.Error("Failed to start text replacement", zap.Error(err), zap.String("find", replacement.Find))
+		h.textReplacementError(w, err)
+		return
+	}
+
+	logger.Info("Text replacement started",
+		zap.String("job_id", job.ID),
+		zap.String("find", replacement.Find),
+		zap.Bool("regex", replacement.Regex),
+	)
+
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	respond.JSON(w, http.StatusAccepted, job)
+}
+
+// textReplacementError maps a text replacement error to its response
+func (h *ProductHandler) textReplacementError(w http.ResponseWriter, err error) {
+	if errors.Is(err, models.ErrInvalidRequest) {
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+		return
+	}
+	respond.Error(w, http.StatusInternalServerError, "Failed to replace text")
+}
+
+// MigrateAttributes godoc
+// @Summary Rename or remap a variant attribute
+// @Description Starts a background job that renames an attribute key and/or remaps its values on every variant, e.g. colour to color and Navy to Dark Blue. Each changed product is written as an update event tagged with the job.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param migration body models.AttributeMigration true "Attribute migration"
+// @Success 202 {object} models.Job
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/attributes/migrate [post]
+func (h *ProductHandler) MigrateAttributes(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	var migration models.AttributeMigration
+	if err := decodeJSON(r, &migration); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	job, err := h.service.MigrateAttributes(r.Context(), &migration)
+	if err != nil {
+		logger.Error("Failed to start attribute migration",
+			zap.Error(err),
+			zap.String("attribute", migration.Key),
+		)
+		if errors.Is(err, models.ErrInvalidRequest) {
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to start attribute migration")
+		return
+	}
+
+	logger.Info("Attribute migration started",
+		zap.String("job_id", job.ID),
+		zap.String("attribute", migration.Key),
+		zap.String("new_key", migration.NewKey),
+		zap.Int("value_mappings", len(migration.Values)),
+	)
+
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	respond.JSON(w, http.StatusAccepted, job)
+}
+
+// CopyMetadata godoc
+// @Summary Copy metadata between markets
+// @Description Starts a background job that copies the titles, descriptions and keywords of a source market to target markets of the filtered products, optionally only where the target is empty. Each changed product is written as an update event tagged with the job, so the copy can be undone with POST /jobs/{id}/rollback.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param copy body models.MetadataCopy true "Metadata copy"
+// @Success 202 {object} models.Job
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/metadata/copy [post]
+func (h *ProductHandler) CopyMetadata(w http.ResponseWriter, r *http.Request) {
+	logger := logging.FromContext(r.Context())
+
+	var metadataCopy models.MetadataCopy
+	if err := decodeJSON(r, &metadataCopy); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	job, err := h.service.CopyMetadata(r.Context(), &metadataCopy)
+	if err != nil {
+		logger.Error("Failed to start metadata copy",
+			zap.Error(err),
+			zap.String("source", metadataCopy.Source),
+		)
+		if errors.Is(err, models.ErrInvalidRequest) {
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to start metadata copy")
+		return
+	}
+
+	logger.Info("Metadata copy started",
+		zap.String("job_id", job.ID),
+		zap.String("source", metadataCopy.Source),
+		zap.Strings("targets", metadataCopy.Targets),
+		zap.Bool("only_empty", metadataCopy.OnlyEmpty),
+	)
+
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	respond.JSON(w, http.StatusAccepted, job)
+}
+
+// setWarningHeaders reports soft validation issues on a successful write as
+// Warning headers, so clients can pick them up without the body changing shape
+func setWarningHeaders(w http.ResponseWriter, warnings []models.ValidationWarning) {
+	for _, warning := range warnings {
+		w.Header().Add("Warning", fmt.Sprintf("199 - %q", warning.Field+": "+warning.Message))
+	}
+}
+
+// countOperation counts a product operation in product_operations_total by
+// whether the service call succeeded
+func countOperation(operation string, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	metrics.ProductOperations.WithLabelValues(operation, status).Inc()
+}
+
+// checkBatchSize writes 400 Bad Request for a batch over the limit and
+// reports whether the batch may go on
+func (h *ProductHandler) checkBatchSize(w http.ResponseWriter, size int) bool {
+	if size <= h.maxBatchSize {
+		return true
+	}
+	respond.ErrorCode(w, http.StatusBadRequest, respond.CodeBatchTooLarge, fmt.Sprintf("Batch has %d items, the maximum is %d", size, h.maxBatchSize))
+	return false
+}
+
+// sendValidationError writes a 422 listing the failing fields when err is a
+// validation error, and reports whether it did
+func (h *ProductHandler) sendValidationError(w http.ResponseWriter, err error) bool {
+	var invalid *models.ValidationError
+	if !errors.As(err, &invalid) {
+		return false
 	}
+	respond.ValidationFailed(w, invalid.Fields)
+	return true
 }
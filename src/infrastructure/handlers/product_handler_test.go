@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -14,6 +18,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/jimmitjoo/ecom/src/application/interfaces"
 	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/patch"
 )
 
 // MockProductService is a mock for the ProductService interface
@@ -21,17 +26,27 @@ type MockProductService struct {
 	mock.Mock
 }
 
-func (m *MockProductService) ListProducts(page, pageSize int) ([]*models.Product, int, error) {
-	args := m.Called(page, pageSize)
+func (m *MockProductService) ListProducts(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(filter, page, pageSize)
 	return args.Get(0).([]*models.Product), args.Int(1), args.Error(2)
 }
 
-func (m *MockProductService) CreateProduct(product *models.Product) error {
+func (m *MockProductService) ListProductsAfter(ctx context.Context, filter models.ProductFilter, cursor string, limit int) ([]*models.Product, string, error) {
+	args := m.Called(filter, cursor, limit)
+	return args.Get(0).([]*models.Product), args.String(1), args.Error(2)
+}
+
+func (m *MockProductService) ListProductsAsOf(ctx context.Context, asOf time.Time, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(asOf, page, pageSize)
+	return args.Get(0).([]*models.Product), args.Int(1), args.Error(2)
+}
+
+func (m *MockProductService) CreateProduct(ctx context.Context, product *models.Product) error {
 	args := m.Called(product)
 	return args.Error(0)
 }
 
-func (m *MockProductService) GetProduct(id string) (*models.Product, error) {
+func (m *MockProductService) GetProduct(ctx context.Context, id string) (*models.Product, error) {
 	args := m.Called(id)
 	if p, ok := args.Get(0).(*models.Product); ok {
 		return p, args.Error(1)
@@ -39,27 +54,205 @@ func (m *MockProductService) GetProduct(id string) (*models.Product, error) {
 	return nil, args.Error(1)
 }
 
-func (m *MockProductService) UpdateProduct(product *models.Product) error {
+func (m *MockProductService) GetProductBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	args := m.Called(sku)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) UpdateProduct(ctx context.Context, product *models.Product) error {
 	args := m.Called(product)
 	return args.Error(0)
 }
 
-func (m *MockProductService) DeleteProduct(id string) error {
+func (m *MockProductService) PatchProduct(ctx context.Context, id string, version int64, mediaType string, document []byte) (*models.Product, error) {
+	args := m.Called(id, version, mediaType, document)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) DeleteProduct(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
-func (m *MockProductService) BatchCreateProducts(products []*models.Product) ([]*interfaces.BatchResult, error) {
+func (m *MockProductService) ReplayEvents(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
+	args := m.Called(productID, fromVersion)
+	if events, ok := args.Get(0).([]*models.Event); ok {
+		return events, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) ListProductVersions(ctx context.Context, id string) ([]*models.ProductVersion, error) {
+	args := m.Called(id)
+	if versions, ok := args.Get(0).([]*models.ProductVersion); ok {
+		return versions, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) GetProductVersion(ctx context.Context, id string, version int64) (*models.Product, error) {
+	args := m.Called(id, version)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) SoftDeleteProduct(ctx context.Context, id string) (*models.Product, error) {
+	args := m.Called(id)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) RestoreProduct(ctx context.Context, id string) (*models.Product, error) {
+	args := m.Called(id)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) CompareProducts(ctx context.Context, ids []string) (*models.ProductComparison, error) {
+	args := m.Called(ids)
+	if c, ok := args.Get(0).(*models.ProductComparison); ok {
+		return c, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) RollbackProduct(ctx context.Context, id string, toVersion int64) (*models.Product, error) {
+	args := m.Called(id, toVersion)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) AdjustStock(ctx context.Context, id string, adjustments []models.StockAdjustment) (*models.Product, error) {
+	args := m.Called(id, adjustments)
+	if p, ok := args.Get(0).(*models.Product); ok {
+		return p, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) RollbackJob(ctx context.Context, jobID string) (*interfaces.JobRollbackResult, error) {
+	args := m.Called(jobID)
+	if result, ok := args.Get(0).(*interfaces.JobRollbackResult); ok {
+		return result, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) MigrateAttributes(ctx context.Context, migration *models.AttributeMigration) (*models.Job, error) {
+	args := m.Called(migration)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) PreviewTextReplacement(ctx context.Context, replacement *models.TextReplacement) (*models.TextReplacementPreview, error) {
+	args := m.Called(replacement)
+	if preview, ok := args.Get(0).(*models.TextReplacementPreview); ok {
+		return preview, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) ReplaceText(ctx context.Context, replacement *models.TextReplacement) (*models.Job, error) {
+	args := m.Called(replacement)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) CopyMetadata(ctx context.Context, metadataCopy *models.MetadataCopy) (*models.Job, error) {
+	args := m.Called(metadataCopy)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) ExportCatalog(ctx context.Context, filter models.CatalogFilter, anonymizePrices bool) (*models.CatalogExport, error) {
+	args := m.Called(filter, anonymizePrices)
+	if export, ok := args.Get(0).(*models.CatalogExport); ok {
+		return export, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) ImportCatalog(ctx context.Context, req *interfaces.CatalogImportRequest) (*models.Job, error) {
+	args := m.Called(req)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) ListProductsByTags(ctx context.Context, tags []string, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(tags, page, pageSize)
+	return args.Get(0).([]*models.Product), args.Int(1), args.Error(2)
+}
+
+func (m *MockProductService) TagUsage(ctx context.Context) ([]*models.TagUsage, error) {
+	args := m.Called()
+	return args.Get(0).([]*models.TagUsage), args.Error(1)
+}
+
+func (m *MockProductService) UpdateTags(ctx context.Context, update *models.TagUpdate) (*models.Job, error) {
+	args := m.Called(update)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) DeleteProductsByTag(ctx context.Context, tag string) ([]*interfaces.BatchResult, error) {
+	args := m.Called(tag)
+	if results, ok := args.Get(0).([]*interfaces.BatchResult); ok {
+		return results, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) AssignCategories(ctx context.Context, id string, categoryIDs []string) (*models.Product, error) {
+	args := m.Called(id, categoryIDs)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) UpdateImages(ctx context.Context, id string, version int64, images []models.Image) (*models.Product, error) {
+	args := m.Called(id, version, images)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockProductService) BatchCreateProducts(ctx context.Context, products []*models.Product) ([]*interfaces.BatchResult, error) {
 	args := m.Called(products)
 	return args.Get(0).([]*interfaces.BatchResult), args.Error(1)
 }
 
-func (m *MockProductService) BatchUpdateProducts(products []*models.Product) ([]*interfaces.BatchResult, error) {
+func (m *MockProductService) BatchUpdateProducts(ctx context.Context, products []*models.Product) ([]*interfaces.BatchResult, error) {
 	args := m.Called(products)
 	return args.Get(0).([]*interfaces.BatchResult), args.Error(1)
 }
 
-func (m *MockProductService) BatchDeleteProducts(ids []string) ([]*interfaces.BatchResult, error) {
+func (m *MockProductService) BatchDeleteProducts(ctx context.Context, ids []string) ([]*interfaces.BatchResult, error) {
 	args := m.Called(ids)
 	return args.Get(0).([]*interfaces.BatchResult), args.Error(1)
 }
@@ -88,7 +281,7 @@ func TestListProducts(t *testing.T) {
 	}
 	totalItems := 10
 
-	mockService.On("ListProducts", 1, 10).Return(products, totalItems, nil)
+	mockService.On("ListProducts", models.ProductFilter{}, 1, 10).Return(products, totalItems, nil)
 
 	req := httptest.NewRequest("GET", "/products", nil)
 	w := httptest.NewRecorder()
@@ -126,7 +319,7 @@ func TestListProductsWithCustomPagination(t *testing.T) {
 	}
 	totalItems := 20
 
-	mockService.On("ListProducts", 2, 5).Return(products, totalItems, nil)
+	mockService.On("ListProducts", models.ProductFilter{}, 2, 5).Return(products, totalItems, nil)
 
 	req := httptest.NewRequest("GET", "/products?page=2&size=5", nil)
 	w := httptest.NewRecorder()
@@ -177,6 +370,70 @@ func TestCreateProduct(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestCreateProductRejectsInvalidPriceOverrides(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("CreateProduct", mock.AnythingOfType("*models.Product")).
+		Return(fmt.Errorf("%w: variant v1 overrides NOK, which the product has no price in", models.ErrInvalidRequest))
+
+	body, _ := json.Marshal(createTestProduct())
+	req := httptest.NewRequest("POST", "/products", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateProduct(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "overrides NOK")
+}
+
+func TestCreateProductRejectsInvalidFields(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	product := createTestProduct()
+	product.SKU = ""
+	product.Prices[0].Currency = "SEKR"
+	body, _ := json.Marshal(product)
+	req := httptest.NewRequest("POST", "/products", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateProduct(w, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var response models.APIError
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Status)
+	assert.Equal(t, "validation_failed", response.Code)
+	assert.Equal(t, []models.FieldError{
+		{Field: "sku", Tag: "required", Message: "sku is required"},
+		{Field: "prices[0].currency", Tag: "currency", Message: "prices[0].currency must be an ISO 4217 currency code"},
+	}, response.Errors)
+	mockService.AssertNotCalled(t, "CreateProduct", mock.Anything)
+}
+
+func TestCreateProductReturnsQualityWarnings(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	product := createTestProduct()
+	product.Metadata[0].Keywords = "test"
+	mockService.On("CreateProduct", mock.AnythingOfType("*models.Product")).Return(nil)
+
+	body, _ := json.Marshal(product)
+	req := httptest.NewRequest("POST", "/products", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	handler.CreateProduct(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	warnings := w.Header().Values("Warning")
+	if assert.Len(t, warnings, 2) {
+		assert.Contains(t, warnings[0], `199 - "description:`)
+		assert.Contains(t, warnings[1], `199 - "metadata[SE].description:`)
+	}
+}
+
 func TestGetProduct(t *testing.T) {
 	mockService := new(MockProductService)
 	handler := NewProductHandler(mockService)
@@ -196,10 +453,247 @@ func TestGetProduct(t *testing.T) {
 	err := json.NewDecoder(w.Body).Decode(&response)
 	assert.NoError(t, err)
 	assert.Equal(t, product.ID, response.ID)
+	assert.Equal(t, productETag(product), w.Header().Get("ETag"))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGetProductBySKU(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	product := createTestProduct()
+	mockService.On("GetProductBySKU", product.SKU).Return(product, nil)
+	mockService.On("GetProductBySKU", "MISSING").Return(nil, models.ErrProductNotFound)
+
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/products/sku/"+product.SKU, nil), map[string]string{"sku": product.SKU})
+	w := httptest.NewRecorder()
+	handler.GetProductBySKU(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.Product
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, product.ID, response.ID)
+	assert.Equal(t, productETag(product), w.Header().Get("ETag"))
+
+	req = mux.SetURLVars(httptest.NewRequest("GET", "/products/sku/MISSING", nil), map[string]string{"sku": "MISSING"})
+	w = httptest.NewRecorder()
+	handler.GetProductBySKU(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetProductErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"product not found", models.ErrProductNotFound, http.StatusNotFound},
+		{"product locked", models.ErrLockFailed, http.StatusLocked},
+		{"service failure", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			mockService.On("GetProduct", "test_prod_1").Return(nil, tt.err)
+			mockService.On("GetProductBySKU", "SKU-1").Return(nil, tt.err)
+
+			req := mux.SetURLVars(httptest.NewRequest("GET", "/products/test_prod_1", nil), map[string]string{"id": "test_prod_1"})
+			w := httptest.NewRecorder()
+			handler.GetProduct(w, req)
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.NotContains(t, w.Body.String(), "boom")
+
+			req = mux.SetURLVars(httptest.NewRequest("GET", "/products/sku/SKU-1", nil), map[string]string{"sku": "SKU-1"})
+			w = httptest.NewRecorder()
+			handler.GetProductBySKU(w, req)
+			assert.Equal(t, tt.wantCode, w.Code)
+			assert.NotContains(t, w.Body.String(), "boom")
+		})
+	}
+}
+
+func TestGetProductNotModified(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	product := createTestProduct()
+	product.Version = 3
+	product.UpdatedAt = time.Date(2024, 5, 17, 10, 30, 15, 500, time.UTC)
+	mockService.On("GetProduct", product.ID).Return(product, nil)
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := mux.SetURLVars(httptest.NewRequest("GET", "/products/"+product.ID, nil), map[string]string{"id": product.ID})
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		handler.GetProduct(w, req)
+		return w
+	}
+
+	w := get("", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	assert.Equal(t, "Fri, 17 May 2024 10:30:15 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+
+	w = get("If-None-Match", `"3"`)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+
+	// The copy of an older version is replaced
+	w = get("If-None-Match", `"2"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Body.String())
+
+	assert.Equal(t, http.StatusNotModified, get("If-Modified-Since", "Fri, 17 May 2024 10:30:15 GMT").Code)
+	assert.Equal(t, http.StatusOK, get("If-Modified-Since", "Fri, 17 May 2024 10:30:14 GMT").Code)
+}
+
+func TestListProductsNotModified(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	products := []*models.Product{{ID: "1", Version: 1}, {ID: "2", Version: 4}}
+	changed := []*models.Product{{ID: "1", Version: 2}, {ID: "2", Version: 4}}
+	mockService.On("ListProducts", models.ProductFilter{}, 1, 10).Return(products, 2, nil).Once()
+	mockService.On("ListProducts", models.ProductFilter{}, 1, 10).Return(products, 2, nil).Once()
+	mockService.On("ListProducts", models.ProductFilter{}, 1, 10).Return(changed, 2, nil).Once()
+	mockService.On("ListProducts", models.ProductFilter{}, 2, 10).Return(products, 2, nil).Once()
+
+	list := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", target, nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		w := httptest.NewRecorder()
+		handler.ListProducts(w, req)
+		return w
+	}
 
+	w := list("/products", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`))
+
+	assert.Equal(t, http.StatusNotModified, list("/products", etag).Code)
+	// A new version of a product on the page changes the tag
+	assert.Equal(t, http.StatusOK, list("/products", etag).Code)
+	// So does the position of the page
+	assert.Equal(t, http.StatusOK, list("/products?page=2", etag).Code)
 	mockService.AssertExpectations(t)
 }
 
+// MockCurrencyService is a mock for the CurrencyService interface
+type MockCurrencyService struct {
+	mock.Mock
+}
+
+func (m *MockCurrencyService) Rates() (*models.ExchangeRates, error) {
+	args := m.Called()
+	if rates, ok := args.Get(0).(*models.ExchangeRates); ok {
+		return rates, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockCurrencyService) Convert(amount float64, from, to string) (float64, error) {
+	args := m.Called(amount, from, to)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCurrencyService) DisplayProduct(product *models.Product, currency string) (*models.DisplayedProduct, error) {
+	args := m.Called(product, currency)
+	if displayed, ok := args.Get(0).(*models.DisplayedProduct); ok {
+		return displayed, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestGetProductInDisplayCurrency(t *testing.T) {
+	mockService := new(MockProductService)
+	currencies := new(MockCurrencyService)
+	handler := NewProductHandler(mockService)
+	handler.EnableCurrencyConversion(currencies)
+
+	product := createTestProduct()
+	mockService.On("GetProduct", product.ID).Return(product, nil)
+	currencies.On("DisplayProduct", product, "EUR").Return(&models.DisplayedProduct{
+		Product:      product,
+		DisplayPrice: &models.DisplayPrice{Currency: "EUR", Amount: 8.7, ConvertedFrom: "SEK", OriginalAmount: 100, Rate: 0.087},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/products/"+product.ID+"?display_currency=EUR", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": product.ID})
+	w := httptest.NewRecorder()
+
+	handler.GetProduct(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		ID           string              `json:"id"`
+		DisplayPrice models.DisplayPrice `json:"display_price"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, product.ID, response.ID)
+	assert.Equal(t, 8.7, response.DisplayPrice.Amount)
+	assert.Equal(t, "SEK", response.DisplayPrice.ConvertedFrom)
+	currencies.AssertExpectations(t)
+}
+
+func TestGetProductInDisplayCurrencyErrors(t *testing.T) {
+	product := createTestProduct()
+	tests := []struct {
+		name     string
+		currency string
+		enabled  bool
+		err      error
+		code     int
+	}{
+		{"disabled", "EUR", false, nil, http.StatusBadRequest},
+		{"unknown code", "EURO", true, nil, http.StatusBadRequest},
+		{"no rate", "USD", true, models.ErrExchangeRateNotFound, http.StatusBadRequest},
+		{"rates unavailable", "USD", true, errors.New("feed unavailable"), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			currencies := new(MockCurrencyService)
+			handler := NewProductHandler(mockService)
+			if tt.enabled {
+				handler.EnableCurrencyConversion(currencies)
+			}
+			mockService.On("GetProduct", product.ID).Return(product, nil)
+			currencies.On("DisplayProduct", product, tt.currency).Return(nil, tt.err)
+
+			req := httptest.NewRequest("GET", "/products/"+product.ID+"?display_currency="+tt.currency, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": product.ID})
+			w := httptest.NewRecorder()
+
+			handler.GetProduct(w, req)
+
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+}
+
+func TestCreateProductRejectsDuplicateSKU(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	mockService.On("CreateProduct", mock.AnythingOfType("*models.Product")).
+		Return(fmt.Errorf("%w: TEST-123 is used by prod_1", models.ErrDuplicateSKU))
+
+	body, _ := json.Marshal(createTestProduct())
+	w := httptest.NewRecorder()
+	handler.CreateProduct(w, httptest.NewRequest("POST", "/products", bytes.NewBuffer(body)))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "prod_1")
+}
+
 func TestUpdateProduct(t *testing.T) {
 	mockService := new(MockProductService)
 	handler := NewProductHandler(mockService)
@@ -213,14 +707,9 @@ func TestUpdateProduct(t *testing.T) {
 		UpdatedAt: time.Now(),
 	}
 
-	updatedProduct := &models.Product{
-		ID:        "test_prod_1",
-		SKU:       "TEST-123",
-		BaseTitle: "Updated Title",
-		Version:   1,
-		CreatedAt: existingProduct.CreatedAt,
-		UpdatedAt: time.Now(),
-	}
+	updatedProduct := createTestProduct()
+	updatedProduct.BaseTitle = "Updated Title"
+	updatedProduct.Version = 1
 
 	// Mock GetProduct call
 	mockService.On("GetProduct", "test_prod_1").Return(existingProduct, nil)
@@ -231,6 +720,7 @@ func TestUpdateProduct(t *testing.T) {
 	// Create request body
 	body, _ := json.Marshal(updatedProduct)
 	req, _ := http.NewRequest("PUT", "/products/test_prod_1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1"`)
 
 	// Set up Gorilla Mux router to handle URL parameters
 	router := mux.NewRouter()
@@ -243,24 +733,190 @@ func TestUpdateProduct(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
-func TestDeleteProduct(t *testing.T) {
+func TestUpdateProductPreconditions(t *testing.T) {
+	existing := &models.Product{ID: "test_prod_1", BaseTitle: "Original Title", Version: 3}
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+	}{
+		{"missing If-Match", "", http.StatusPreconditionRequired},
+		{"stale version", `"2"`, http.StatusPreconditionFailed},
+		{"weak tag", `W/"3"`, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			mockService.On("GetProduct", "test_prod_1").Return(existing, nil)
+
+			req := httptest.NewRequest("PUT", "/products/test_prod_1", strings.NewReader(`{"base_title": "Updated"}`))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			req = mux.SetURLVars(req, map[string]string{"id": "test_prod_1"})
+			w := httptest.NewRecorder()
+			handler.UpdateProduct(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			mockService.AssertNotCalled(t, "UpdateProduct", mock.Anything)
+		})
+	}
+}
+
+func TestUpdateProductRejectsInvalidFields(t *testing.T) {
 	mockService := new(MockProductService)
 	handler := NewProductHandler(mockService)
+	mockService.On("GetProduct", "test_prod_1").Return(&models.Product{ID: "test_prod_1", Version: 3}, nil)
 
-	productID := "test_prod_1"
-	mockService.On("DeleteProduct", productID).Return(nil)
-
-	req := httptest.NewRequest("DELETE", "/products/"+productID, nil)
-	req = mux.SetURLVars(req, map[string]string{"id": productID})
+	req := httptest.NewRequest("PUT", "/products/test_prod_1", strings.NewReader(`{"base_title": "Updated"}`))
+	req.Header.Set("If-Match", `"3"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "test_prod_1"})
 	w := httptest.NewRecorder()
+	handler.UpdateProduct(w, req)
 
-	handler.DeleteProduct(w, req)
-
-	assert.Equal(t, http.StatusNoContent, w.Code)
-	mockService.AssertExpectations(t)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Contains(t, w.Body.String(), `"field":"metadata"`)
+	mockService.AssertNotCalled(t, "UpdateProduct", mock.Anything)
 }
 
-func TestBatchCreateProducts(t *testing.T) {
+func TestUpdateProductConcurrentUpdate(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	mockService.On("GetProduct", "test_prod_1").Return(&models.Product{ID: "test_prod_1", Version: 3}, nil)
+	mockService.On("UpdateProduct", mock.AnythingOfType("*models.Product")).Return(fmt.Errorf("%w: expected 4, got 3", models.ErrVersionConflict))
+
+	body, _ := json.Marshal(createTestProduct())
+	req := httptest.NewRequest("PUT", "/products/test_prod_1", bytes.NewBuffer(body))
+	req.Header.Set("If-Match", `"1", "3"`)
+	req = mux.SetURLVars(req, map[string]string{"id": "test_prod_1"})
+	w := httptest.NewRecorder()
+	handler.UpdateProduct(w, req)
+
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+}
+
+func TestUpdateProductErrors(t *testing.T) {
+	invalid := &models.ValidationError{Fields: []models.FieldError{{Field: "sku", Tag: "required", Message: "sku is required"}}}
+	tests := []struct {
+		name     string
+		getErr   error
+		err      error
+		wantCode int
+		wantErr  string
+	}{
+		{"product not found", models.ErrProductNotFound, nil, http.StatusNotFound, "product_not_found"},
+		{"lookup failure", errors.New("boom"), nil, http.StatusInternalServerError, "internal_error"},
+		{"deleted meanwhile", nil, models.ErrProductNotFound, http.StatusNotFound, "product_not_found"},
+		{"invalid request", nil, fmt.Errorf("%w: hazard_class is unknown", models.ErrInvalidRequest), http.StatusBadRequest, "invalid_request"},
+		{"validation failure", nil, invalid, http.StatusUnprocessableEntity, "validation_failed"},
+		{"duplicate sku", nil, fmt.Errorf("%w: SKU-1 is used by prod_2", models.ErrDuplicateSKU), http.StatusConflict, "duplicate_sku"},
+		{"product locked", nil, fmt.Errorf("%w: product test_prod_1 is being written", models.ErrLockFailed), http.StatusLocked, "lock_failed"},
+		{"service failure", nil, errors.New("boom"), http.StatusInternalServerError, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			if tt.getErr != nil {
+				mockService.On("GetProduct", "test_prod_1").Return(nil, tt.getErr)
+			} else {
+				mockService.On("GetProduct", "test_prod_1").Return(&models.Product{ID: "test_prod_1", Version: 1}, nil)
+				mockService.On("UpdateProduct", mock.AnythingOfType("*models.Product")).Return(tt.err)
+			}
+
+			body, _ := json.Marshal(createTestProduct())
+			req := httptest.NewRequest("PUT", "/products/test_prod_1", bytes.NewBuffer(body))
+			req.Header.Set("If-Match", `"1"`)
+			req = mux.SetURLVars(req, map[string]string{"id": "test_prod_1"})
+			w := httptest.NewRecorder()
+			handler.UpdateProduct(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+			var response models.APIError
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.wantErr, response.Code)
+			assert.NotContains(t, response.Message, "boom")
+		})
+	}
+}
+
+func TestDeleteProduct(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	productID := "test_prod_1"
+	deletedAt := time.Now()
+	mockService.On("SoftDeleteProduct", productID).Return(&models.Product{ID: productID, DeletedAt: &deletedAt}, nil)
+
+	req := httptest.NewRequest("DELETE", "/products/"+productID, nil)
+	req = mux.SetURLVars(req, map[string]string{"id": productID})
+	w := httptest.NewRecorder()
+
+	handler.DeleteProduct(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "DeleteProduct", productID)
+}
+
+func TestDeleteProductPermanently(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	productID := "test_prod_1"
+	mockService.On("DeleteProduct", productID).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/products/"+productID+"?permanent=true", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": productID})
+	w := httptest.NewRecorder()
+
+	handler.DeleteProduct(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestDeleteProductErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		err      error
+		wantCode int
+	}{
+		{"invalid permanent flag", "?permanent=sometimes", nil, http.StatusBadRequest},
+		{"product not found", "", models.ErrProductNotFound, http.StatusNotFound},
+		{"product locked", "", models.ErrLockFailed, http.StatusLocked},
+		{"service failure", "", errors.New("boom"), http.StatusInternalServerError},
+		{"permanent product not found", "?permanent=true", models.ErrProductNotFound, http.StatusNotFound},
+		{"permanent product locked", "?permanent=true", models.ErrLockFailed, http.StatusLocked},
+		{"permanent service failure", "?permanent=true", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			switch {
+			case tt.err == nil:
+			case tt.query == "?permanent=true":
+				mockService.On("DeleteProduct", "test_prod_1").Return(tt.err)
+			default:
+				mockService.On("SoftDeleteProduct", "test_prod_1").Return(nil, tt.err)
+			}
+
+			req := httptest.NewRequest("DELETE", "/products/test_prod_1"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "test_prod_1"})
+			w := httptest.NewRecorder()
+			handler.DeleteProduct(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestBatchCreateProducts(t *testing.T) {
 	mockService := new(MockProductService)
 	handler := NewProductHandler(mockService)
 
@@ -310,6 +966,25 @@ func TestBatchUpdateProducts(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestBatchSizeLimit(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	handler.LimitBatchSize(2)
+
+	body, _ := json.Marshal([]string{"prod_1", "prod_2", "prod_3"})
+	req := httptest.NewRequest("DELETE", "/products/batch", bytes.NewBuffer(body))
+	w := httptest.NewRecorder()
+
+	handler.BatchDeleteProducts(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response models.APIError
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "batch_too_large", response.Code)
+	assert.Equal(t, "Batch has 3 items, the maximum is 2", response.Message)
+	mockService.AssertNotCalled(t, "BatchDeleteProducts", mock.Anything)
+}
+
 func TestBatchDeleteProducts(t *testing.T) {
 	mockService := new(MockProductService)
 	handler := NewProductHandler(mockService)
@@ -334,3 +1009,900 @@ func TestBatchDeleteProducts(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestCompareProducts(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	comparison := &models.ProductComparison{
+		Products: []models.ComparedProduct{{ID: "a"}, {ID: "b"}},
+	}
+	mockService.On("CompareProducts", []string{"a", "b"}).Return(comparison, nil)
+
+	req := httptest.NewRequest("GET", "/products/compare?ids=a,%20b,", nil)
+	w := httptest.NewRecorder()
+
+	handler.CompareProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response models.ProductComparison
+	err := json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Len(t, response.Products, 2)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCompareProductsInvalidIDs(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	for _, url := range []string{"/products/compare", "/products/compare?ids=a", "/products/compare?ids=1,2,3,4,5,6,7,8,9,10,11"} {
+		w := httptest.NewRecorder()
+		handler.CompareProducts(w, httptest.NewRequest("GET", url, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+
+	mockService.AssertNotCalled(t, "CompareProducts", mock.Anything)
+}
+
+func TestCompareProductsNotFound(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	mockService.On("CompareProducts", []string{"a", "missing"}).
+		Return(nil, fmt.Errorf("%w: %s", models.ErrProductNotFound, "missing"))
+
+	w := httptest.NewRecorder()
+	handler.CompareProducts(w, httptest.NewRequest("GET", "/products/compare?ids=a,missing", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "missing")
+}
+
+func TestListProductsAsOf(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	asOf := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+	products := []*models.Product{{ID: "1", BaseTitle: "Product 1"}}
+	mockService.On("ListProductsAsOf", asOf, 1, 10).Return(products, 1, nil)
+
+	req := httptest.NewRequest("GET", "/products?as_of=2024-03-31T23:59:59Z", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "ListProducts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListProductsByTags(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	products := []*models.Product{{ID: "1", BaseTitle: "Product 1", Tags: []string{"sale", "spring-2025"}}}
+	mockService.On("ListProducts", models.ProductFilter{Tags: []string{"sale", "spring-2025"}}, 1, 10).Return(products, 1, nil)
+
+	req := httptest.NewRequest("GET", "/products?tag=Spring-2025&tag=sale", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+
+	// Tags cannot be combined with a historical listing
+	req = httptest.NewRequest("GET", "/products?tag=sale&as_of=2024-03-31T23:59:59Z", nil)
+	w = httptest.NewRecorder()
+	handler.ListProducts(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListProductsByCategories(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	products := []*models.Product{{ID: "1", CategoryIDs: []string{"shirts"}}}
+	mockService.On("ListProducts", models.ProductFilter{Categories: []string{"shirts", "sale"}}, 1, 10).Return(products, 1, nil)
+
+	w := httptest.NewRecorder()
+	handler.ListProducts(w, httptest.NewRequest("GET", "/products?category_id=shirts,sale&category_id=shirts", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestListProductsWithFilters(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	minPrice, maxPrice := 100.0, 250.5
+	filter := models.ProductFilter{
+		SKU:            "SKU-1",
+		Market:         "SE",
+		Currency:       "SEK",
+		MinPrice:       &minPrice,
+		MaxPrice:       &maxPrice,
+		Title:          "shirt",
+		CreatedAfter:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		IncludeDeleted: true,
+	}
+	products := []*models.Product{{ID: "1", BaseTitle: "T-shirt"}}
+	mockService.On("ListProducts", filter, 1, 10).Return(products, 1, nil)
+
+	req := httptest.NewRequest("GET", "/products?sku=SKU-1&market=se&currency=sek&min_price=100&max_price=250.5&title=shirt&created_after=2024-01-01T00:00:00Z&include_deleted=true", nil)
+	w := httptest.NewRecorder()
+	handler.ListProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestListProductsInvalidFilters(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	mockService.On("ListProducts", mock.Anything, 1, 10).Return([]*models.Product(nil), 0, fmt.Errorf("%w: min_price cannot be above max_price", models.ErrInvalidRequest))
+
+	for _, query := range []string{
+		"min_price=cheap",
+		"created_before=yesterday",
+		"include_deleted=maybe",
+		"sku=SKU-1&as_of=2024-03-31T23:59:59Z",
+		"include_deleted=true&as_of=2024-03-31T23:59:59Z",
+		"min_price=10&max_price=5",
+	} {
+		req := httptest.NewRequest("GET", "/products?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ListProducts(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNumberOfCalls(t, "ListProducts", 1)
+}
+
+func TestListProductsWithCursor(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	products := []*models.Product{{ID: "3"}, {ID: "2"}}
+	mockService.On("ListProductsAfter", models.ProductFilter{Market: "SE"}, "abc", 2).Return(products, "def", nil)
+
+	req := httptest.NewRequest("GET", "/products?cursor=abc&limit=2&market=SE", nil)
+	w := httptest.NewRecorder()
+	handler.ListProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data       []*models.Product `json:"data"`
+		Limit      int               `json:"limit"`
+		NextCursor string            `json:"next_cursor"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, 2, response.Limit)
+	assert.Equal(t, "def", response.NextCursor)
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "ListProducts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListProductsInDisplayCurrency(t *testing.T) {
+	mockService := new(MockProductService)
+	currencies := new(MockCurrencyService)
+	handler := NewProductHandler(mockService)
+	handler.EnableCurrencyConversion(currencies)
+
+	products := []*models.Product{{ID: "1"}, {ID: "2"}}
+	mockService.On("ListProducts", models.ProductFilter{}, 1, 10).Return(products, 2, nil)
+	for _, product := range products {
+		currencies.On("DisplayProduct", product, "eur").Return(&models.DisplayedProduct{
+			Product:      product,
+			DisplayPrice: &models.DisplayPrice{Currency: "EUR", Amount: 10},
+		}, nil)
+	}
+
+	req := httptest.NewRequest("GET", "/products?display_currency=eur", nil)
+	w := httptest.NewRecorder()
+	handler.ListProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data []models.DisplayedProduct `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Len(t, response.Data, 2)
+	assert.Equal(t, "2", response.Data[1].ID)
+	assert.Equal(t, 10.0, response.Data[1].DisplayPrice.Amount)
+
+	// Unknown codes are rejected before the products are listed
+	req = httptest.NewRequest("GET", "/products?display_currency=kronor", nil)
+	w = httptest.NewRecorder()
+	handler.ListProducts(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNumberOfCalls(t, "ListProducts", 1)
+}
+
+func TestListProductsInvalidCursor(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	mockService.On("ListProductsAfter", models.ProductFilter{}, "garbage", 10).Return([]*models.Product(nil), "", fmt.Errorf("%w: invalid cursor", models.ErrInvalidRequest))
+
+	for _, query := range []string{
+		"cursor=garbage",
+		"limit=0",
+		"limit=ten",
+		"cursor=abc&page=2",
+		"limit=5&as_of=2024-03-31T23:59:59Z",
+	} {
+		req := httptest.NewRequest("GET", "/products?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.ListProducts(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockService.AssertNumberOfCalls(t, "ListProductsAfter", 1)
+}
+
+func TestListProductsInvalidAsOf(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	req := httptest.NewRequest("GET", "/products?as_of=yesterday", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListProducts(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "ListProductsAsOf", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPatchProduct(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	current := &models.Product{ID: "test_prod_1", BaseTitle: "Original", Version: 2}
+	patched := &models.Product{ID: "test_prod_1", BaseTitle: "Patched", Version: 3}
+	body := []byte(`{"base_title":"Patched"}`)
+	mockService.On("GetProduct", "test_prod_1").Return(current, nil)
+	mockService.On("PatchProduct", "test_prod_1", int64(2), "application/merge-patch+json", body).Return(patched, nil)
+
+	for _, contentType := range []string{"application/merge-patch+json", "application/json; charset=utf-8"} {
+		req := httptest.NewRequest("PATCH", "/products/test_prod_1", bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("If-Match", `"2"`)
+		req = mux.SetURLVars(req, map[string]string{"id": "test_prod_1"})
+		w := httptest.NewRecorder()
+		handler.PatchProduct(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, contentType)
+		var response models.Product
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		assert.Equal(t, "Patched", response.BaseTitle)
+		assert.Equal(t, `"3"`, w.Header().Get("ETag"))
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestPatchProductErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		err         error
+		status      int
+	}{
+		{"unsupported media type", "text/plain", nil, http.StatusUnsupportedMediaType},
+		{"not found", "application/json-patch+json", models.ErrProductNotFound, http.StatusNotFound},
+		{"invalid patch", "application/json-patch+json", fmt.Errorf("%w: invalid patch", models.ErrInvalidRequest), http.StatusBadRequest},
+		{"invalid field", "application/merge-patch+json", &models.ValidationError{Fields: []models.FieldError{{Field: "sku", Tag: "required", Message: "sku is required"}}}, http.StatusUnprocessableEntity},
+		{"failed test", "application/json-patch+json", fmt.Errorf("operation 0 (test /sku): %w", patch.ErrTestFailed), http.StatusConflict},
+		{"concurrent update", "application/json-patch+json", fmt.Errorf("%w: expected 3, got 2", models.ErrVersionConflict), http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			mockService.On("GetProduct", "test_prod_1").Return(&models.Product{ID: "test_prod_1", Version: 2}, nil)
+			mockService.On("PatchProduct", "test_prod_1", int64(2), tt.contentType, mock.Anything).Return(nil, tt.err)
+
+			req := httptest.NewRequest("PATCH", "/products/test_prod_1", bytes.NewReader([]byte(`[]`)))
+			req.Header.Set("Content-Type", tt.contentType)
+			req.Header.Set("If-Match", "*")
+			req = mux.SetURLVars(req, map[string]string{"id": "test_prod_1"})
+			w := httptest.NewRecorder()
+			handler.PatchProduct(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestPatchProductRequiresIfMatch(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	mockService.On("GetProduct", "test_prod_1").Return(&models.Product{ID: "test_prod_1", Version: 2}, nil)
+
+	for ifMatch, status := range map[string]int{"": http.StatusPreconditionRequired, `"1"`: http.StatusPreconditionFailed} {
+		req := httptest.NewRequest("PATCH", "/products/test_prod_1", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		req = mux.SetURLVars(req, map[string]string{"id": "test_prod_1"})
+		w := httptest.NewRecorder()
+		handler.PatchProduct(w, req)
+
+		assert.Equal(t, status, w.Code, ifMatch)
+		if status == http.StatusPreconditionFailed {
+			assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+		}
+	}
+	mockService.AssertNotCalled(t, "PatchProduct", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRollbackProduct(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	restored := &models.Product{ID: "test_prod_1", BaseTitle: "Original Title", Version: 4}
+	mockService.On("RollbackProduct", "test_prod_1", int64(2)).Return(restored, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/rollback", handler.RollbackProduct)
+
+	req := httptest.NewRequest("POST", "/products/test_prod_1/rollback?to_version=2", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Original Title")
+	mockService.AssertExpectations(t)
+}
+
+func TestRollbackProductErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		err      error
+		wantCode int
+	}{
+		{"missing version", "", nil, http.StatusBadRequest},
+		{"invalid version", "?to_version=abc", nil, http.StatusBadRequest},
+		{"product not found", "?to_version=1", models.ErrProductNotFound, http.StatusNotFound},
+		{"version not found", "?to_version=1", fmt.Errorf("%w: 1", models.ErrVersionNotFound), http.StatusNotFound},
+		{"not an earlier version", "?to_version=1", fmt.Errorf("%w: must be between 1 and 0", models.ErrInvalidRollbackVersion), http.StatusBadRequest},
+		{"service failure", "?to_version=1", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			if tt.err != nil {
+				mockService.On("RollbackProduct", "test_prod_1", int64(1)).Return(nil, tt.err)
+			}
+
+			router := mux.NewRouter()
+			router.HandleFunc("/products/{id}/rollback", handler.RollbackProduct)
+
+			req := httptest.NewRequest("POST", "/products/test_prod_1/rollback"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
+
+func TestProductEvents(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	events := []*models.Event{{ID: "evt_2", Type: models.EventProductUpdated, EntityID: "test_prod_1", Version: 2}}
+	mockService.On("ReplayEvents", "test_prod_1", int64(2)).Return(events, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/events", handler.ProductEvents)
+
+	req := httptest.NewRequest("GET", "/products/test_prod_1/events?from_version=2", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []map[string]interface{}
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	assert.Len(t, response, 1)
+	assert.Equal(t, "evt_2", response[0]["id"])
+	mockService.AssertExpectations(t)
+}
+
+func TestProductEventsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		err      error
+		wantCode int
+	}{
+		{"invalid version", "?from_version=abc", nil, http.StatusBadRequest},
+		{"version below one", "?from_version=0", nil, http.StatusBadRequest},
+		{"product not found", "", models.ErrProductNotFound, http.StatusNotFound},
+		{"broken chain", "", fmt.Errorf("%w: curr version 3, prev version 1", models.ErrBrokenEventChain), http.StatusConflict},
+		{"service failure", "", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			if tt.err != nil {
+				mockService.On("ReplayEvents", "test_prod_1", int64(1)).Return(nil, tt.err)
+			}
+
+			router := mux.NewRouter()
+			router.HandleFunc("/products/{id}/events", handler.ProductEvents)
+
+			req := httptest.NewRequest("GET", "/products/test_prod_1/events"+tt.query, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
+
+func TestListProductVersions(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	versions := []*models.ProductVersion{
+		{Version: 1, Type: models.EventProductCreated, Action: "created"},
+		{Version: 2, Type: models.EventProductUpdated, Action: "updated"},
+	}
+	mockService.On("ListProductVersions", "test_prod_1").Return(versions, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/versions", handler.ListProductVersions)
+
+	req := httptest.NewRequest("GET", "/products/test_prod_1/versions", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response []*models.ProductVersion
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	assert.Len(t, response, 2)
+	assert.Equal(t, "updated", response[1].Action)
+	mockService.AssertExpectations(t)
+}
+
+func TestGetProductVersion(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	historical := &models.Product{ID: "test_prod_1", BaseTitle: "Original Title", Version: 2}
+	mockService.On("GetProductVersion", "test_prod_1", int64(2)).Return(historical, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/versions/{version}", handler.GetProductVersion)
+
+	req := httptest.NewRequest("GET", "/products/test_prod_1/versions/2", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Original Title")
+	mockService.AssertExpectations(t)
+}
+
+func TestGetProductVersionErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		err      error
+		wantCode int
+	}{
+		{"invalid version", "abc", nil, http.StatusBadRequest},
+		{"version below one", "0", nil, http.StatusBadRequest},
+		{"product not found", "1", models.ErrProductNotFound, http.StatusNotFound},
+		{"version not found", "1", fmt.Errorf("%w: 1", models.ErrVersionNotFound), http.StatusNotFound},
+		{"broken chain", "1", fmt.Errorf("%w: curr version 3, prev version 1", models.ErrBrokenEventChain), http.StatusConflict},
+		{"service failure", "1", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			if tt.err != nil {
+				mockService.On("GetProductVersion", "test_prod_1", int64(1)).Return(nil, tt.err)
+			}
+
+			router := mux.NewRouter()
+			router.HandleFunc("/products/{id}/versions/{version}", handler.GetProductVersion)
+
+			req := httptest.NewRequest("GET", "/products/test_prod_1/versions/"+tt.version, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
+
+func TestRestoreProduct(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	restored := &models.Product{ID: "test_prod_1", BaseTitle: "Back again", Version: 3}
+	mockService.On("RestoreProduct", "test_prod_1").Return(restored, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/restore", handler.RestoreProduct)
+
+	req := httptest.NewRequest("POST", "/products/test_prod_1/restore", nil)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Back again")
+	assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestRestoreProductErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"product not found", models.ErrProductNotFound, http.StatusNotFound},
+		{"product not deleted", fmt.Errorf("%w: test_prod_1", models.ErrNotDeleted), http.StatusConflict},
+		{"product locked", models.ErrLockFailed, http.StatusLocked},
+		{"service failure", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			mockService.On("RestoreProduct", "test_prod_1").Return(nil, tt.err)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/products/{id}/restore", handler.RestoreProduct)
+
+			req := httptest.NewRequest("POST", "/products/test_prod_1/restore", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
+
+func TestAdjustStock(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+
+	adjustments := []models.StockAdjustment{{VariantID: "v1", LocationID: "wh1", Delta: -2}}
+	updated := &models.Product{ID: "test_prod_1", Version: 3, Variants: []models.Variant{
+		{ID: "v1", Stock: []models.Stock{{LocationID: "wh1", Quantity: 1}}},
+	}}
+	mockService.On("AdjustStock", "test_prod_1", adjustments).Return(updated, nil)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/stock", handler.AdjustStock)
+
+	body, _ := json.Marshal(adjustments)
+	req := httptest.NewRequest("POST", "/products/test_prod_1/stock", bytes.NewBuffer(body))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"quantity":1`)
+	mockService.AssertExpectations(t)
+}
+
+func TestAdjustStockErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+	}{
+		{"invalid json", "{", nil, http.StatusBadRequest},
+		{"product not found", "[]", models.ErrProductNotFound, http.StatusNotFound},
+		{"unknown variant", "[]", fmt.Errorf("%w: v9", models.ErrVariantNotFound), http.StatusBadRequest},
+		{"invalid request", "[]", fmt.Errorf("%w: no stock adjustments", models.ErrInvalidRequest), http.StatusBadRequest},
+		{"insufficient stock", "[]", fmt.Errorf("%w: variant v1 at wh1 has 0, requested 1", models.ErrInsufficientStock), http.StatusConflict},
+		{"lock timeout", "[]", models.ErrLockFailed, http.StatusLocked},
+		{"service failure", "[]", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			if tt.err != nil {
+				mockService.On("AdjustStock", "test_prod_1", []models.StockAdjustment{}).Return(nil, tt.err)
+			}
+
+			router := mux.NewRouter()
+			router.HandleFunc("/products/{id}/stock", handler.AdjustStock)
+
+			req := httptest.NewRequest("POST", "/products/test_prod_1/stock", bytes.NewBufferString(tt.body))
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+		})
+	}
+}
+
+func TestRollbackJob(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *interfaces.JobRollbackResult
+		err      error
+		wantCode int
+	}{
+		{"success", &interfaces.JobRollbackResult{Job: &models.Job{ID: "job_2", RollbackOf: "job_1"}}, nil, http.StatusOK},
+		{"job not found", nil, models.ErrJobNotFound, http.StatusNotFound},
+		{"job running", nil, fmt.Errorf("%w: job_1", models.ErrJobNotFinished), http.StatusConflict},
+		{"service failure", nil, errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			mockService.On("RollbackJob", "job_1").Return(tt.result, tt.err)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/jobs/{id}/rollback", handler.RollbackJob)
+
+			req := httptest.NewRequest("POST", "/jobs/job_1/rollback", nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestMigrateAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		job      *models.Job
+		err      error
+		wantCode int
+	}{
+		{"started", &models.Job{ID: "job_1", Type: models.JobAttributeMigration, Status: models.JobStatusRunning}, nil, http.StatusAccepted},
+		{"invalid migration", nil, fmt.Errorf("%w: new_key or values is required", models.ErrInvalidRequest), http.StatusBadRequest},
+		{"service failure", nil, errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			migration := &models.AttributeMigration{Key: "colour", NewKey: "color"}
+			mockService.On("MigrateAttributes", migration).Return(tt.job, tt.err)
+
+			body, _ := json.Marshal(migration)
+			req := httptest.NewRequest("POST", "/admin/attributes/migrate", bytes.NewBuffer(body))
+			rr := httptest.NewRecorder()
+			handler.MigrateAttributes(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.job != nil {
+				assert.Equal(t, "/jobs/job_1", rr.Header().Get("Location"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCopyMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		job      *models.Job
+		err      error
+		wantCode int
+	}{
+		{"started", &models.Job{ID: "job_1", Type: models.JobMetadataCopy, Status: models.JobStatusRunning}, nil, http.StatusAccepted},
+		{"invalid copy", nil, fmt.Errorf("%w: at least one target is required", models.ErrInvalidRequest), http.StatusBadRequest},
+		{"service failure", nil, errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			metadataCopy := &models.MetadataCopy{Source: "SE", Targets: []string{"FI"}, OnlyEmpty: true}
+			mockService.On("CopyMetadata", metadataCopy).Return(tt.job, tt.err)
+
+			body, _ := json.Marshal(metadataCopy)
+			req := httptest.NewRequest("POST", "/products/metadata/copy", bytes.NewBuffer(body))
+			rr := httptest.NewRecorder()
+			handler.CopyMetadata(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.job != nil {
+				assert.Equal(t, "/jobs/job_1", rr.Header().Get("Location"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReplaceText(t *testing.T) {
+	tests := []struct {
+		name     string
+		job      *models.Job
+		err      error
+		wantCode int
+	}{
+		{"started", &models.Job{ID: "job_1", Type: models.JobTextReplace, Status: models.JobStatusRunning}, nil, http.StatusAccepted},
+		{"invalid pattern", nil, fmt.Errorf("%w: invalid regular expression", models.ErrInvalidRequest), http.StatusBadRequest},
+		{"service failure", nil, errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockProductService)
+			handler := NewProductHandler(mockService)
+			mockService.On("ReplaceText", mock.AnythingOfType("*models.TextReplacement")).Return(tt.job, tt.err)
+
+			body := `{"find": "colour", "replace": "color"}`
+			req := httptest.NewRequest("POST", "/admin/text/replace", strings.NewReader(body))
+			rr := httptest.NewRecorder()
+			handler.ReplaceText(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			if tt.job != nil {
+				assert.Equal(t, "/jobs/job_1", rr.Header().Get("Location"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReplaceTextDryRun(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewProductHandler(mockService)
+	preview := &models.TextReplacementPreview{
+		Matched: 1,
+		Products: []*models.TextReplacementDiff{{
+			ProductID: "prod_1",
+			Changes:   []models.TextChange{{Field: "base_title", Old: "Colour shirt", New: "Color shirt"}},
+		}},
+	}
+	mockService.On("PreviewTextReplacement", mock.MatchedBy(func(r *models.TextReplacement) bool {
+		return r.DryRun && r.Find == "Colour"
+	})).Return(preview, nil)
+
+	body := `{"find": "Colour", "replace": "Color", "dry_run": true}`
+	req := httptest.NewRequest("POST", "/admin/text/replace", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	handler.ReplaceText(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var response models.TextReplacementPreview
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	assert.Equal(t, 1, response.Matched)
+	assert.Equal(t, "Color shirt", response.Products[0].Changes[0].New)
+	mockService.AssertNotCalled(t, "ReplaceText", mock.Anything)
+}
+
+// benchProductService serves fixed data without mock bookkeeping so benchmarks
+// measure the handler itself
+type benchProductService struct {
+	MockProductService
+	products []*models.Product
+}
+
+func (s *benchProductService) GetProduct(ctx context.Context, id string) (*models.Product, error) {
+	return s.products[0], nil
+}
+
+func (s *benchProductService) ListProducts(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	return s.products, len(s.products), nil
+}
+
+func newBenchProductService(count int) *benchProductService {
+	products := make([]*models.Product, count)
+	for i := range products {
+		products[i] = &models.Product{
+			ID:        fmt.Sprintf("prod_%d", i),
+			SKU:       fmt.Sprintf("SKU-%d", i),
+			BaseTitle: "Benchmark product",
+			Prices:    []models.Price{{Currency: "SEK", Amount: 100}, {Currency: "EUR", Amount: 10}},
+			Metadata:  []models.MarketMetadata{{Market: "SE", Title: "Produkt", Description: "Beskrivning"}},
+			Version:   1,
+		}
+	}
+	return &benchProductService{products: products}
+}
+
+func BenchmarkGetProduct(b *testing.B) {
+	handler := NewProductHandler(newBenchProductService(1))
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/products/prod_0", nil), map[string]string{"id": "prod_0"})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		handler.GetProduct(httptest.NewRecorder(), req)
+	}
+}
+
+func BenchmarkListProducts(b *testing.B) {
+	handler := NewProductHandler(newBenchProductService(10))
+	req := httptest.NewRequest("GET", "/products", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		handler.ListProducts(httptest.NewRecorder(), req)
+	}
+}
+
+func TestGetProductServesCachedJSON(t *testing.T) {
+	service := newBenchProductService(1)
+	handler := NewProductHandler(service)
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/products/prod_0", nil), map[string]string{"id": "prod_0"})
+
+	first := httptest.NewRecorder()
+	handler.GetProduct(first, req)
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, 1, handler.jsonCache.Len())
+
+	// Same version is served from the cache with identical bytes
+	second := httptest.NewRecorder()
+	handler.GetProduct(second, req)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+
+	// A new version is encoded again
+	updated := service.products[0].Clone()
+	updated.BaseTitle = "Changed"
+	updated.UpdateVersion()
+	service.products[0] = updated
+
+	third := httptest.NewRecorder()
+	handler.GetProduct(third, req)
+	assert.Contains(t, third.Body.String(), "Changed")
+
+	var product models.Product
+	assert.NoError(t, json.Unmarshal(third.Body.Bytes(), &product))
+	assert.Equal(t, updated.Version, product.Version)
+}
+
+func TestGetProductWithCacheWarming(t *testing.T) {
+	service := newBenchProductService(1)
+	handler := NewProductHandler(service)
+	warmer := handler.EnableCacheWarming(1)
+	req := mux.SetURLVars(httptest.NewRequest("GET", "/products/prod_0", nil), map[string]string{"id": "prod_0"})
+
+	handler.GetProduct(httptest.NewRecorder(), req)
+	handler.GetProduct(httptest.NewRecorder(), req)
+
+	// The product becomes hot on the first ranking
+	warmer.Start(time.Millisecond)
+	defer warmer.Stop()
+	assert.Eventually(t, func() bool { return len(warmer.Hot()) == 1 }, time.Second, time.Millisecond)
+
+	// The update event re-encodes the product before the next read
+	updated := service.products[0].Clone()
+	updated.BaseTitle = "Warmed"
+	updated.UpdateVersion()
+	service.products[0] = updated
+	warmer.HandleEvent(&models.Event{
+		Type: models.EventProductUpdated,
+		Data: &models.ProductEvent{ProductID: updated.ID, Product: updated, Version: updated.Version},
+	})
+	data, hit := handler.jsonCache.Get(updated.ID, updated.Version, updated.LastHash)
+	assert.True(t, hit)
+
+	rr := httptest.NewRecorder()
+	handler.GetProduct(rr, req)
+	assert.Equal(t, string(data), rr.Body.String())
+}
@@ -5,6 +5,8 @@ import (
 	"runtime"
 	"runtime/pprof"
 	"time"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
 )
 
 // ProfilingHandler handles performance profiling endpoints
@@ -18,7 +20,7 @@ func NewProfilingHandler() *ProfilingHandler {
 // CPUProfile handles CPU profiling requests
 func (h *ProfilingHandler) CPUProfile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respond.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -34,7 +36,7 @@ func (h *ProfilingHandler) CPUProfile(w http.ResponseWriter, r *http.Request) {
 // HeapProfile handles heap memory profiling requests
 func (h *ProfilingHandler) HeapProfile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respond.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -46,7 +48,7 @@ func (h *ProfilingHandler) HeapProfile(w http.ResponseWriter, r *http.Request) {
 // GoroutineProfile handles goroutine profiling requests
 func (h *ProfilingHandler) GoroutineProfile(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		respond.Error(w, http.StatusMethodNotAllowed, "Method not allowed")
 		return
 	}
 
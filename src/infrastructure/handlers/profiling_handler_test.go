@@ -45,7 +45,7 @@ func TestProfilingHandler(t *testing.T) {
 			endpoint:       "/debug/pprof/cpu",
 			method:         http.MethodPost,
 			expectedCode:   http.StatusMethodNotAllowed,
-			expectedHeader: "text/plain; charset=utf-8",
+			expectedHeader: "application/json",
 		},
 	}
 
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// PublicHandler serves the read-only catalog to storefronts. Responses are
+// redacted for the caller, so public API keys and anonymous callers never see
+// internal fields.
+type PublicHandler struct {
+	service interfaces.PublicCatalogService
+}
+
+// NewPublicHandler creates a new public catalog handler instance
+func NewPublicHandler(service interfaces.PublicCatalogService) *PublicHandler {
+	return &PublicHandler{
+		service: service,
+	}
+}
+
+// writePage writes a page of products, redacted for the caller
+func (h *PublicHandler) writePage(w http.ResponseWriter, r *http.Request, products []*models.Product, page, pageSize, total int) {
+	response := struct {
+		Data       []*models.Product `json:"data"`
+		Page       int               `json:"page"`
+		PageSize   int               `json:"page_size"`
+		TotalItems int               `json:"total_items"`
+		TotalPages int               `json:"total_pages"`
+	}{
+		Data:       models.RedactProducts(products, middleware.PrincipalFromContext(r.Context())),
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}
+
+	respond.JSON(w, http.StatusOK, response)
+}
+
+// ListProducts godoc
+// @Summary List published products
+// @Description Returns published products without internal fields such as sourcing. Requires an X-API-Key header when public API keys are configured.
+// @Tags public
+// @Produce json
+// @Param tag query []string false "Only products with every one of these tags" collectionFormat(multi)
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10)
+// @Success 200 {array} models.Product
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {object} models.APIError
+// @Router /public/products [get]
+func (h *PublicHandler) ListProducts(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+
+	var tags []string
+	for _, value := range r.URL.Query()["tag"] {
+		tags = append(tags, strings.Split(value, ",")...)
+	}
+
+	products, total, err := h.service.ListProducts(r.Context(), tags, page, pageSize)
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to fetch products")
+		return
+	}
+	h.writePage(w, r, products, page, pageSize, total)
+}
+
+// GetProduct godoc
+// @Summary Get a published product
+// @Description Returns a published product without internal fields. Drafts are not found.
+// @Tags public
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} models.Product
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /public/products/{id} [get]
+func (h *PublicHandler) GetProduct(w http.ResponseWriter, r *http.Request) {
+	product, err := h.service.GetProduct(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			respond.Failure(w, http.StatusNotFound, err, "Product not found")
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to fetch product")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, product.Redacted(middleware.PrincipalFromContext(r.Context())))
+}
+
+// ListMarketProducts godoc
+// @Summary List published products in a market
+// @Description Returns the storefront listing of a market in merchandising order, without internal fields
+// @Tags public
+// @Produce json
+// @Param market path string true "Market code, e.g. SE"
+// @Param category query string false "Curated category whose pins apply; empty for the full listing"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size" default(10)
+// @Success 200 {array} models.Product
+// @Failure 401 {string} string "Unauthorized"
+// @Failure 500 {object} models.APIError
+// @Router /public/markets/{market}/products [get]
+func (h *PublicHandler) ListMarketProducts(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+
+	products, total, err := h.service.ListMarketProducts(r.Context(), mux.Vars(r)["market"], r.URL.Query().Get("category"), page, pageSize)
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to list market products")
+		return
+	}
+	h.writePage(w, r, products, page, pageSize, total)
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+)
+
+// MockPublicCatalogService is a mock for the PublicCatalogService interface
+type MockPublicCatalogService struct {
+	mock.Mock
+}
+
+func (m *MockPublicCatalogService) ListProducts(ctx context.Context, tags []string, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(tags, page, pageSize)
+	if products, ok := args.Get(0).([]*models.Product); ok {
+		return products, args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+func (m *MockPublicCatalogService) GetProduct(ctx context.Context, id string) (*models.Product, error) {
+	args := m.Called(id)
+	if product, ok := args.Get(0).(*models.Product); ok {
+		return product, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockPublicCatalogService) ListMarketProducts(ctx context.Context, market, category string, page, pageSize int) ([]*models.Product, int, error) {
+	args := m.Called(market, category, page, pageSize)
+	if products, ok := args.Get(0).([]*models.Product); ok {
+		return products, args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+func sourcedProduct(id string) *models.Product {
+	return &models.Product{
+		ID:        id,
+		BaseTitle: "Shirt",
+		Sourcing:  &models.Sourcing{Supplier: "Acme Textiles", Costs: []models.Price{{Currency: "SEK", Amount: 42}}},
+	}
+}
+
+func setupPublicRouter(service *MockPublicCatalogService) *mux.Router {
+	handler := NewPublicHandler(service)
+	router := mux.NewRouter()
+	router.HandleFunc("/public/products", handler.ListProducts).Methods("GET")
+	router.HandleFunc("/public/products/{id}", handler.GetProduct).Methods("GET")
+	router.HandleFunc("/public/markets/{market}/products", handler.ListMarketProducts).Methods("GET")
+	return router
+}
+
+func TestPublicListProductsRedactsForPublicCallers(t *testing.T) {
+	service := new(MockPublicCatalogService)
+	service.On("ListProducts", []string{"summer", "sale"}, 2, 5).Return([]*models.Product{sourcedProduct("prod-1")}, 6, nil)
+
+	req := httptest.NewRequest("GET", "/public/products?tag=summer,sale&page=2&size=5", nil)
+	req = req.WithContext(middleware.WithPrincipal(req.Context(), &models.Principal{Subject: "api-key:1", Roles: []string{models.RolePublic}}))
+	rr := httptest.NewRecorder()
+	setupPublicRouter(service).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "sourcing")
+	assert.NotContains(t, rr.Body.String(), "Acme")
+
+	var response struct {
+		Data       []*models.Product `json:"data"`
+		TotalPages int               `json:"total_pages"`
+	}
+	assert.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+	assert.Len(t, response.Data, 1)
+	assert.Equal(t, 2, response.TotalPages)
+	service.AssertExpectations(t)
+}
+
+func TestPublicGetProduct(t *testing.T) {
+	service := new(MockPublicCatalogService)
+	service.On("GetProduct", "prod-1").Return(sourcedProduct("prod-1"), nil)
+	service.On("GetProduct", "prod-draft").Return(nil, models.ErrProductNotFound)
+	service.On("GetProduct", "prod-broken").Return(nil, errors.New("storage down"))
+	router := setupPublicRouter(service)
+
+	tests := []struct {
+		name           string
+		id             string
+		principal      *models.Principal
+		expectedStatus int
+		expectSourcing bool
+	}{
+		{"Anonymous", "prod-1", nil, http.StatusOK, false},
+		{"Internal caller", "prod-1", &models.Principal{Subject: "user-1", Roles: []string{"admin"}}, http.StatusOK, true},
+		{"Draft", "prod-draft", nil, http.StatusNotFound, false},
+		{"Service error", "prod-broken", nil, http.StatusInternalServerError, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/public/products/"+tc.id, nil)
+			if tc.principal != nil {
+				req = req.WithContext(middleware.WithPrincipal(req.Context(), tc.principal))
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedStatus == http.StatusOK {
+				var product models.Product
+				assert.NoError(t, json.NewDecoder(rr.Body).Decode(&product))
+				assert.Equal(t, tc.expectSourcing, product.Sourcing != nil)
+			}
+		})
+	}
+}
+
+func TestPublicListMarketProducts(t *testing.T) {
+	service := new(MockPublicCatalogService)
+	service.On("ListMarketProducts", "SE", "shoes", 1, 10).Return([]*models.Product{sourcedProduct("prod-1")}, 1, nil)
+	service.On("ListMarketProducts", "NO", "", 1, 10).Return(nil, 0, errors.New("storage down"))
+	router := setupPublicRouter(service)
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/public/markets/SE/products?category=shoes", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "sourcing")
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/public/markets/NO/products", nil))
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	service.AssertExpectations(t)
+}
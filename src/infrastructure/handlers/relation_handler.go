@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// RelationHandler handles links between products and the related product listing
+type RelationHandler struct {
+	service interfaces.RelationService
+}
+
+// NewRelationHandler creates a new relation handler instance
+func NewRelationHandler(service interfaces.RelationService) *RelationHandler {
+	return &RelationHandler{
+		service: service,
+	}
+}
+
+// ListRelations godoc
+// @Summary List product relations
+// @Description Returns the relations from a product, ordered by type, position and related product ID
+// @Tags relations
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param type query string false "Only relations of this type: related, upsell, cross_sell, bundle or variant_of"
+// @Success 200 {array} models.ProductRelation
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/relations [get]
+func (h *RelationHandler) ListRelations(w http.ResponseWriter, r *http.Request) {
+	relations, err := h.service.ListRelations(r.Context(), mux.Vars(r)["id"], models.RelationType(r.URL.Query().Get("type")))
+	if err != nil {
+		h.writeRelationError(w, err, "Failed to list relations")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, relations)
+}
+
+// AddRelation godoc
+// @Summary Link a product to another
+// @Description Adds a relation of a type from the product to another product, or replaces the relation of that type between them
+// @Tags relations
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param relation body models.ProductRelation true "Relation; the product ID is taken from the path"
+// @Success 201 {object} models.ProductRelation
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/relations [post]
+func (h *RelationHandler) AddRelation(w http.ResponseWriter, r *http.Request) {
+	var relation models.ProductRelation
+	if err := decodeJSON(r, &relation); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	relation.ProductID = mux.Vars(r)["id"]
+
+	added, err := h.service.AddRelation(r.Context(), &relation)
+	if err != nil {
+		h.writeRelationError(w, err, "Failed to add relation")
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, added)
+}
+
+// RemoveRelation godoc
+// @Summary Remove a product relation
+// @Tags relations
+// @Param id path string true "Product ID"
+// @Param type path string true "Relation type"
+// @Param related path string true "Related product ID"
+// @Success 204
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/relations/{type}/{related} [delete]
+func (h *RelationHandler) RemoveRelation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	if err := h.service.RemoveRelation(vars["id"], vars["related"], models.RelationType(vars["type"])); err != nil {
+		h.writeRelationError(w, err, "Failed to remove relation")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RelatedProducts godoc
+// @Summary List related products
+// @Description Returns the products linked from a product with their relation type, in one call. Deleted products are left out.
+// @Tags relations
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param type query string false "Only products linked with this relation type"
+// @Success 200 {array} models.RelatedProduct
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/related [get]
+func (h *RelationHandler) RelatedProducts(w http.ResponseWriter, r *http.Request) {
+	related, err := h.service.RelatedProducts(r.Context(), mux.Vars(r)["id"], models.RelationType(r.URL.Query().Get("type")))
+	if err != nil {
+		h.writeRelationError(w, err, "Failed to list related products")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, related)
+}
+
+// writeRelationError maps relation errors to status codes
+func (h *RelationHandler) writeRelationError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidRequest):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, models.ErrProductNotFound), errors.Is(err, models.ErrRelationNotFound):
+		respond.Failure(w, http.StatusNotFound, err, err.Error())
+	default:
+		respond.Error(w, http.StatusInternalServerError, fallback)
+	}
+}
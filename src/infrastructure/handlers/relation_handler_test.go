@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockRelationService is a mock for the RelationService interface
+type MockRelationService struct {
+	mock.Mock
+}
+
+func (m *MockRelationService) ListRelations(ctx context.Context, productID string, relationType models.RelationType) ([]*models.ProductRelation, error) {
+	args := m.Called(productID, relationType)
+	if relations, ok := args.Get(0).([]*models.ProductRelation); ok {
+		return relations, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRelationService) AddRelation(ctx context.Context, relation *models.ProductRelation) (*models.ProductRelation, error) {
+	args := m.Called(relation)
+	if added, ok := args.Get(0).(*models.ProductRelation); ok {
+		return added, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRelationService) RemoveRelation(productID, relatedID string, relationType models.RelationType) error {
+	args := m.Called(productID, relatedID, relationType)
+	return args.Error(0)
+}
+
+func (m *MockRelationService) RelatedProducts(ctx context.Context, productID string, relationType models.RelationType) ([]*models.RelatedProduct, error) {
+	args := m.Called(productID, relationType)
+	if related, ok := args.Get(0).([]*models.RelatedProduct); ok {
+		return related, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func relationRouter(handler *RelationHandler) *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/relations", handler.ListRelations).Methods("GET")
+	router.HandleFunc("/products/{id}/relations", handler.AddRelation).Methods("POST")
+	router.HandleFunc("/products/{id}/relations/{type}/{related}", handler.RemoveRelation).Methods("DELETE")
+	router.HandleFunc("/products/{id}/related", handler.RelatedProducts).Methods("GET")
+	return router
+}
+
+func TestAddRelationTakesProductFromPath(t *testing.T) {
+	mockService := new(MockRelationService)
+	mockService.On("AddRelation", &models.ProductRelation{ProductID: "shirt", RelatedID: "tie", Type: models.RelationCrossSell, Position: 1}).
+		Return(&models.ProductRelation{ProductID: "shirt", RelatedID: "tie", Type: models.RelationCrossSell, Position: 1}, nil)
+
+	w := httptest.NewRecorder()
+	body := `{"product_id":"other","related_id":"tie","type":"cross_sell","position":1}`
+	relationRouter(NewRelationHandler(mockService)).ServeHTTP(w, httptest.NewRequest("POST", "/products/shirt/relations", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRelatedProducts(t *testing.T) {
+	mockService := new(MockRelationService)
+	mockService.On("RelatedProducts", "shirt", models.RelationUpsell).
+		Return([]*models.RelatedProduct{{Type: models.RelationUpsell, Product: &models.Product{ID: "jacket"}}}, nil)
+
+	w := httptest.NewRecorder()
+	relationRouter(NewRelationHandler(mockService)).ServeHTTP(w, httptest.NewRequest("GET", "/products/shirt/related?type=upsell", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var related []models.RelatedProduct
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&related))
+	if assert.Len(t, related, 1) {
+		assert.Equal(t, "jacket", related[0].Product.ID)
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestRelationErrors(t *testing.T) {
+	mockService := new(MockRelationService)
+	mockService.On("ListRelations", "missing", models.RelationType("")).Return(nil, models.ErrProductNotFound)
+	mockService.On("ListRelations", "shirt", models.RelationType("similar")).Return(nil, fmt.Errorf("%w: unknown relation type", models.ErrInvalidRequest))
+	mockService.On("AddRelation", mock.Anything).Return(nil, fmt.Errorf("%w: related product tie not found", models.ErrInvalidRequest))
+	mockService.On("RemoveRelation", "shirt", "tie", models.RelationUpsell).Return(models.ErrRelationNotFound)
+	mockService.On("RelatedProducts", "shirt", models.RelationType("")).Return(nil, assert.AnError)
+	router := relationRouter(NewRelationHandler(mockService))
+
+	for _, c := range []struct {
+		method, path, body string
+		code               int
+	}{
+		{"GET", "/products/missing/relations", ``, http.StatusNotFound},
+		{"GET", "/products/shirt/relations?type=similar", ``, http.StatusBadRequest},
+		{"POST", "/products/shirt/relations", `{`, http.StatusBadRequest},
+		{"POST", "/products/shirt/relations", `{"related_id":"tie","type":"upsell"}`, http.StatusBadRequest},
+		{"DELETE", "/products/shirt/relations/upsell/tie", ``, http.StatusNotFound},
+		{"GET", "/products/shirt/related", ``, http.StatusInternalServerError},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(c.method, c.path, strings.NewReader(c.body)))
+		assert.Equal(t, c.code, w.Code, c.method+" "+c.path)
+	}
+	mockService.AssertExpectations(t)
+}
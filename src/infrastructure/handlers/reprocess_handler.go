@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// ReprocessHandler starts rate-limited reprocessing of product events
+type ReprocessHandler struct {
+	service interfaces.ReprocessService
+}
+
+// NewReprocessHandler creates a new reprocess handler instance
+func NewReprocessHandler(service interfaces.ReprocessService) *ReprocessHandler {
+	return &ReprocessHandler{
+		service: service,
+	}
+}
+
+// StartReprocess godoc
+// @Summary Reprocess products through event consumers
+// @Description Starts a background job that delivers the latest event of each selected product to an internal consumer again, at a limited rate. Use it after fixing a bug in a consumer. Offsets are not changed.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param request body interfaces.ReprocessRequest true "Products, consumer and rate"
+// @Success 202 {object} models.Job
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/reprocess [post]
+func (h *ReprocessHandler) StartReprocess(w http.ResponseWriter, r *http.Request) {
+	var req interfaces.ReprocessRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	job, err := h.service.StartReprocess(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidRequest):
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+		case errors.Is(err, models.ErrConsumerNotFound):
+			respond.Failure(w, http.StatusNotFound, err, err.Error())
+		default:
+			respond.Error(w, http.StatusInternalServerError, "Failed to start reprocessing")
+		}
+		return
+	}
+
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	respond.JSON(w, http.StatusAccepted, job)
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockReprocessService is a mock for the ReprocessService interface
+type MockReprocessService struct {
+	mock.Mock
+}
+
+func (m *MockReprocessService) StartReprocess(ctx context.Context, req *interfaces.ReprocessRequest) (*models.Job, error) {
+	args := m.Called(req)
+	if job, ok := args.Get(0).(*models.Job); ok {
+		return job, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestReprocessHandlerStartReprocess(t *testing.T) {
+	mockService := new(MockReprocessService)
+	handler := NewReprocessHandler(mockService)
+	mockService.On("StartReprocess", mock.MatchedBy(func(req *interfaces.ReprocessRequest) bool {
+		return req.Consumer == "marketplaces" && req.SKUPrefix == "SHIRT" && req.Rate == 5
+	})).Return(&models.Job{ID: "job_1", Type: models.JobReprocess, Status: models.JobStatusRunning}, nil)
+
+	body := `{"consumer": "marketplaces", "sku_prefix": "SHIRT", "rate": 5}`
+	w := httptest.NewRecorder()
+	handler.StartReprocess(w, httptest.NewRequest("POST", "/admin/reprocess", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/jobs/job_1", w.Header().Get("Location"))
+	var job models.Job
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&job))
+	assert.Equal(t, models.JobReprocess, job.Type)
+}
+
+func TestReprocessHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"invalid rate", models.ErrInvalidRequest, http.StatusBadRequest},
+		{"unknown consumer", models.ErrConsumerNotFound, http.StatusNotFound},
+		{"internal", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockReprocessService)
+			handler := NewReprocessHandler(mockService)
+			mockService.On("StartReprocess", mock.Anything).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			handler.StartReprocess(w, httptest.NewRequest("POST", "/admin/reprocess", strings.NewReader(`{}`)))
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+
+	handler := NewReprocessHandler(new(MockReprocessService))
+	w := httptest.NewRecorder()
+	handler.StartReprocess(w, httptest.NewRequest("POST", "/admin/reprocess", strings.NewReader("{")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
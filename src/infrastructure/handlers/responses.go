@@ -1,11 +1,5 @@
 package handlers
 
-// ErrorResponse represents an API error
-type ErrorResponse struct {
-	Code    int    `json:"code" example:"400"`
-	Message string `json:"message" example:"Invalid request"`
-}
-
 // SuccessResponse represents a successful API response
 type SuccessResponse struct {
 	Success bool        `json:"success" example:"true"`
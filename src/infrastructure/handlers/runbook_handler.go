@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// RunbookRequest names who runs a runbook action
+type RunbookRequest struct {
+	// Actor names the on-call engineer. Ignored when the caller is logged in,
+	// the action is then run by the logged in user.
+	Actor string `json:"actor,omitempty"`
+}
+
+// ResendDeliveriesRequest selects the failed deliveries to send again
+type ResendDeliveriesRequest struct {
+	RunbookRequest
+	interfaces.ResendRequest
+}
+
+// LockReleaseRequest sets how old a product's locks must be to be released
+type LockReleaseRequest struct {
+	RunbookRequest
+	// OlderThanSeconds is how long a lock must have been held, default 60
+	OlderThanSeconds int `json:"older_than_seconds,omitempty"`
+}
+
+// RunbookHandler exposes recovery actions for on-call engineers
+type RunbookHandler struct {
+	service interfaces.RunbookService
+}
+
+// NewRunbookHandler creates a new runbook handler instance
+func NewRunbookHandler(service interfaces.RunbookService) *RunbookHandler {
+	return &RunbookHandler{
+		service: service,
+	}
+}
+
+// decode reads an optional request body; logged in callers may send none
+func (h *RunbookHandler) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := decodeJSON(r, v); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return false
+	}
+	return true
+}
+
+// writeRun writes the audit record of a run, or maps the error of a failed one
+func (h *RunbookHandler) writeRun(w http.ResponseWriter, run *models.RunbookRun, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrInvalidRequest):
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+		case errors.Is(err, models.ErrProjectionNotFound):
+			respond.Failure(w, http.StatusNotFound, err, err.Error())
+		case errors.Is(err, models.ErrProductNotFound):
+			respond.Failure(w, http.StatusNotFound, err, "Product not found")
+		default:
+			respond.Error(w, http.StatusInternalServerError, "Runbook action failed: "+err.Error())
+		}
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, run)
+}
+
+// RebuildProjection godoc
+// @Summary Rebuild a projection
+// @Description Drops a projection built by an internal consumer, such as the dashboard, and delivers every stored event to it again. The run is recorded in the audit trail.
+// @Tags runbook
+// @Accept json
+// @Produce json
+// @Param name path string true "Projection, e.g. dashboard"
+// @Param request body RunbookRequest false "Actor, when not logged in"
+// @Success 200 {object} models.RunbookRun
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/runbook/projections/{name}/rebuild [post]
+func (h *RunbookHandler) RebuildProjection(w http.ResponseWriter, r *http.Request) {
+	var req RunbookRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	run, err := h.service.RebuildProjection(r.Context(), mux.Vars(r)["name"], callerName(r, req.Actor))
+	h.writeRun(w, run, err)
+}
+
+// ResendFailedDeliveries godoc
+// @Summary Resend failed deliveries
+// @Description Delivers the latest event of every product whose delivery to one of the targets last failed between from and to again, through the consumer of the target. The run is recorded in the audit trail.
+// @Tags runbook
+// @Accept json
+// @Produce json
+// @Param request body ResendDeliveriesRequest true "Targets and time range"
+// @Success 200 {object} models.RunbookRun
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/runbook/deliveries/resend [post]
+func (h *RunbookHandler) ResendFailedDeliveries(w http.ResponseWriter, r *http.Request) {
+	var req ResendDeliveriesRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	run, err := h.service.ResendFailedDeliveries(r.Context(), &req.ResendRequest, callerName(r, req.Actor))
+	h.writeRun(w, run, err)
+}
+
+// ReleaseStaleLocks godoc
+// @Summary Release a product's stale locks
+// @Description Force-releases the product's write lock and edit sessions held for longer than older_than_seconds, e.g. after a writer crashed. The run is recorded in the audit trail.
+// @Tags runbook
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param request body LockReleaseRequest false "Minimum lock age and actor"
+// @Success 200 {object} models.RunbookRun
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/runbook/products/{id}/locks/release [post]
+func (h *RunbookHandler) ReleaseStaleLocks(w http.ResponseWriter, r *http.Request) {
+	var req LockReleaseRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	olderThan := time.Duration(req.OlderThanSeconds) * time.Second
+	run, err := h.service.ReleaseStaleLocks(mux.Vars(r)["id"], olderThan, callerName(r, req.Actor))
+	h.writeRun(w, run, err)
+}
+
+// VerifyEventChain godoc
+// @Summary Re-verify a product's event chain
+// @Description Checks that the product's events follow each other without gaps, that their hashes chain, and that the stored product matches the latest event. A broken chain is reported in the result with valid false. The run is recorded in the audit trail.
+// @Tags runbook
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param request body RunbookRequest false "Actor, when not logged in"
+// @Success 200 {object} models.RunbookRun
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /admin/runbook/products/{id}/event-chain/verify [post]
+func (h *RunbookHandler) VerifyEventChain(w http.ResponseWriter, r *http.Request) {
+	var req RunbookRequest
+	if !h.decode(w, r, &req) {
+		return
+	}
+	run, err := h.service.VerifyEventChain(r.Context(), mux.Vars(r)["id"], callerName(r, req.Actor))
+	h.writeRun(w, run, err)
+}
+
+// ListRuns godoc
+// @Summary List runbook runs
+// @Description Returns the audit trail of runbook actions, newest first
+// @Tags runbook
+// @Produce json
+// @Param limit query int false "Maximum number of runs (default 50)"
+// @Success 200 {array} models.RunbookRun
+// @Failure 500 {object} models.APIError
+// @Router /admin/runbook/runs [get]
+func (h *RunbookHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 50
+	}
+
+	runs, err := h.service.ListRuns(limit)
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to list runbook runs")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, runs)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+)
+
+// MockRunbookService is a mock for the RunbookService interface
+type MockRunbookService struct {
+	mock.Mock
+}
+
+func (m *MockRunbookService) run(args mock.Arguments) (*models.RunbookRun, error) {
+	if run, ok := args.Get(0).(*models.RunbookRun); ok {
+		return run, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRunbookService) RebuildProjection(ctx context.Context, name, actor string) (*models.RunbookRun, error) {
+	return m.run(m.Called(name, actor))
+}
+
+func (m *MockRunbookService) ResendFailedDeliveries(ctx context.Context, req *interfaces.ResendRequest, actor string) (*models.RunbookRun, error) {
+	return m.run(m.Called(req, actor))
+}
+
+func (m *MockRunbookService) ReleaseStaleLocks(productID string, olderThan time.Duration, actor string) (*models.RunbookRun, error) {
+	return m.run(m.Called(productID, olderThan, actor))
+}
+
+func (m *MockRunbookService) VerifyEventChain(ctx context.Context, productID, actor string) (*models.RunbookRun, error) {
+	return m.run(m.Called(productID, actor))
+}
+
+func (m *MockRunbookService) ListRuns(limit int) ([]*models.RunbookRun, error) {
+	args := m.Called(limit)
+	if runs, ok := args.Get(0).([]*models.RunbookRun); ok {
+		return runs, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestRunbookHandlerRebuildProjectionUsesLoggedInUser(t *testing.T) {
+	mockService := new(MockRunbookService)
+	handler := NewRunbookHandler(mockService)
+	run := &models.RunbookRun{ID: "run_1", Action: models.RunbookRebuildProjection, Actor: "ada@example.com", Succeeded: true}
+	mockService.On("RebuildProjection", "dashboard", "ada@example.com").Return(run, nil)
+
+	// Logged in callers need no body
+	req := httptest.NewRequest("POST", "/admin/runbook/projections/dashboard/rebuild", nil)
+	req = mux.SetURLVars(req, map[string]string{"name": "dashboard"})
+	req = req.WithContext(middleware.WithPrincipal(req.Context(), &models.Principal{Subject: "user-1", Email: "ada@example.com"}))
+	w := httptest.NewRecorder()
+
+	handler.RebuildProjection(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.RunbookRun
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "run_1", response.ID)
+	mockService.AssertExpectations(t)
+}
+
+func TestRunbookHandlerResendFailedDeliveries(t *testing.T) {
+	mockService := new(MockRunbookService)
+	handler := NewRunbookHandler(mockService)
+	mockService.On("ResendFailedDeliveries", mock.MatchedBy(func(req *interfaces.ResendRequest) bool {
+		return len(req.Targets) == 1 && req.Targets[0] == "marketplace:amazon" && req.From != nil && req.To == nil
+	}), "grace").Return(&models.RunbookRun{ID: "run_2"}, nil)
+
+	body := `{"actor":"grace","targets":["marketplace:amazon"],"from":"2024-03-01T00:00:00Z"}`
+	req := httptest.NewRequest("POST", "/admin/runbook/deliveries/resend", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.ResendFailedDeliveries(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRunbookHandlerReleaseStaleLocks(t *testing.T) {
+	mockService := new(MockRunbookService)
+	handler := NewRunbookHandler(mockService)
+	mockService.On("ReleaseStaleLocks", "prod_1", 5*time.Minute, "grace").Return(&models.RunbookRun{ID: "run_3"}, nil)
+
+	req := httptest.NewRequest("POST", "/admin/runbook/products/prod_1/locks/release", strings.NewReader(`{"actor":"grace","older_than_seconds":300}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "prod_1"})
+	w := httptest.NewRecorder()
+
+	handler.ReleaseStaleLocks(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestRunbookHandlerVerifyEventChainErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		err      error
+		wantCode int
+	}{
+		{"invalid JSON", `{`, nil, http.StatusBadRequest},
+		{"no actor", `{}`, fmt.Errorf("%w: actor is required", models.ErrInvalidRequest), http.StatusBadRequest},
+		{"unknown product", `{"actor":"grace"}`, models.ErrProductNotFound, http.StatusNotFound},
+		{"service failure", `{"actor":"grace"}`, errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockRunbookService)
+			handler := NewRunbookHandler(mockService)
+			mockService.On("VerifyEventChain", "prod_1", mock.Anything).Return(nil, tt.err)
+
+			req := httptest.NewRequest("POST", "/admin/runbook/products/prod_1/event-chain/verify", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "prod_1"})
+			w := httptest.NewRecorder()
+
+			handler.VerifyEventChain(w, req)
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
+
+func TestRunbookHandlerListRuns(t *testing.T) {
+	mockService := new(MockRunbookService)
+	handler := NewRunbookHandler(mockService)
+	mockService.On("ListRuns", 10).Return([]*models.RunbookRun{{ID: "run_1"}}, nil)
+
+	req := httptest.NewRequest("GET", "/admin/runbook/runs?limit=10", nil)
+	w := httptest.NewRecorder()
+
+	handler.ListRuns(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response []models.RunbookRun
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Len(t, response, 1)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// SearchHandler handles full-text product search
+type SearchHandler struct {
+	service interfaces.SearchService
+}
+
+// NewSearchHandler creates a new search handler instance
+func NewSearchHandler(service interfaces.SearchService) *SearchHandler {
+	return &SearchHandler{
+		service: service,
+	}
+}
+
+// SearchProducts godoc
+// @Summary Search products
+// @Description Full-text search over SKUs, titles, descriptions, keywords and tags, most relevant first. Any of the words matches; SKU and title matches weigh more than descriptions. With a market only products with metadata for it are searched, with the market's language analyzer, so e.g. "skjortor" finds "skjorta" in SE.
+// @Tags products
+// @Produce json
+// @Param q query string true "Words to search for"
+// @Param market query string false "Search the texts of a market, e.g. SE"
+// @Param page query int false "Page number" default(1)
+// @Param size query int false "Page size, at most 100" default(10)
+// @Success 200 {array} models.ProductSearchResult
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/search [get]
+func (h *SearchHandler) SearchProducts(w http.ResponseWriter, r *http.Request) {
+	page, pageSize := pageParams(r)
+	if pageSize > models.MaxSearchPageSize {
+		pageSize = models.MaxSearchPageSize
+	}
+	query := models.SearchQuery{
+		Text:     r.URL.Query().Get("q"),
+		Market:   r.URL.Query().Get("market"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	results, total, err := h.service.Search(r.Context(), query)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRequest) {
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to search products")
+		return
+	}
+
+	response := struct {
+		Data       []*models.ProductSearchResult `json:"data"`
+		Page       int                           `json:"page"`
+		PageSize   int                           `json:"page_size"`
+		TotalItems int                           `json:"total_items"`
+		TotalPages int                           `json:"total_pages"`
+	}{
+		Data:       results,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: total,
+		TotalPages: (total + pageSize - 1) / pageSize,
+	}
+
+	respond.JSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockSearchService is a mock for the SearchService interface
+type MockSearchService struct {
+	mock.Mock
+}
+
+func (m *MockSearchService) Search(ctx context.Context, query models.SearchQuery) ([]*models.ProductSearchResult, int, error) {
+	args := m.Called(query)
+	if results, ok := args.Get(0).([]*models.ProductSearchResult); ok {
+		return results, args.Int(1), args.Error(2)
+	}
+	return nil, args.Int(1), args.Error(2)
+}
+
+func (m *MockSearchService) Reindex(ctx context.Context) (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func TestSearchProducts(t *testing.T) {
+	mockService := new(MockSearchService)
+	mockService.On("Search", models.SearchQuery{Text: "blue shirt", Market: "SE", Page: 2, PageSize: 100}).
+		Return([]*models.ProductSearchResult{{Score: 1.5, Product: &models.Product{ID: "p1"}}}, 101, nil)
+
+	w := httptest.NewRecorder()
+	NewSearchHandler(mockService).SearchProducts(w, httptest.NewRequest("GET", "/products/search?q=blue+shirt&market=SE&page=2&size=500", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		Data       []*models.ProductSearchResult `json:"data"`
+		TotalPages int                           `json:"total_pages"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 1.5, response.Data[0].Score)
+	assert.Equal(t, "p1", response.Data[0].Product.ID)
+	assert.Equal(t, 2, response.TotalPages)
+}
+
+func TestSearchProductsErrors(t *testing.T) {
+	mockService := new(MockSearchService)
+	mockService.On("Search", mock.MatchedBy(func(q models.SearchQuery) bool { return q.Text == "" })).
+		Return(nil, 0, fmt.Errorf("%w: search text is required", models.ErrInvalidRequest))
+	mockService.On("Search", mock.MatchedBy(func(q models.SearchQuery) bool { return q.Text == "shirt" })).
+		Return(nil, 0, errors.New("connection refused"))
+	handler := NewSearchHandler(mockService)
+
+	w := httptest.NewRecorder()
+	handler.SearchProducts(w, httptest.NewRequest("GET", "/products/search", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.SearchProducts(w, httptest.NewRequest("GET", "/products/search?q=shirt", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "connection refused")
+}
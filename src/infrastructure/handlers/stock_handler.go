@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// StockHandler handles variant stock requests
+type StockHandler struct {
+	service interfaces.StockService
+}
+
+// NewStockHandler creates a new stock handler instance
+func NewStockHandler(service interfaces.StockService) *StockHandler {
+	return &StockHandler{
+		service: service,
+	}
+}
+
+// GetStock godoc
+// @Summary Get variant stock
+// @Description Returns the stock of a variant at every location it has an entry for, with the product version it was read at
+// @Tags stock
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param vid path string true "Variant ID"
+// @Success 200 {object} models.VariantStock
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/variants/{vid}/stock [get]
+func (h *StockHandler) GetStock(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	stock, err := h.service.GetStock(r.Context(), vars["id"], vars["vid"])
+	if err != nil {
+		h.writeStockError(w, err, "Failed to fetch stock")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, stock)
+}
+
+// SetStock godoc
+// @Summary Set variant stock
+// @Description Sets the quantities of a variant at the listed locations in one conditional update, e.g. after a stock count. Locations that are not listed keep their quantities. Nothing changes if a location without backorders would be set below zero.
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param vid path string true "Variant ID"
+// @Param levels body []models.StockLevel true "Quantities per location"
+// @Success 200 {object} models.VariantStock
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 409 {object} models.APIError
+// @Failure 423 {object} models.APIError "The product is being written"
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/variants/{vid}/stock [put]
+func (h *StockHandler) SetStock(w http.ResponseWriter, r *http.Request) {
+	var levels []models.StockLevel
+	if err := decodeJSON(r, &levels); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	stock, err := h.service.SetStock(r.Context(), vars["id"], vars["vid"], levels)
+	countOperation("set_stock", err)
+	if err != nil {
+		h.writeStockError(w, err, "Failed to set stock")
+		return
+	}
+
+	logging.FromContext(r.Context()).Info("Stock set",
+		zap.String("product_id", stock.ProductID),
+		zap.String("variant_id", stock.VariantID),
+		zap.Int("locations", len(levels)),
+		zap.Int64("version", stock.Version),
+	)
+
+	respond.JSON(w, http.StatusOK, stock)
+}
+
+// AdjustStock godoc
+// @Summary Adjust stock across products
+// @Description Applies stock deltas, or absolute quantities, to variants of several products. The adjustments of each product are applied in one conditional update or not at all, so a product that would go below zero at a location without backorders fails without affecting the others. Each product gets a result, in the order the products first appear.
+// @Tags stock
+// @Accept json
+// @Produce json
+// @Param adjustments body []models.ProductStockAdjustment true "Stock adjustments"
+// @Success 200 {array} interfaces.BatchResult
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /stock/adjustments [post]
+func (h *StockHandler) AdjustStock(w http.ResponseWriter, r *http.Request) {
+	var adjustments []models.ProductStockAdjustment
+	if err := decodeJSON(r, &adjustments); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	results, err := h.service.AdjustStock(r.Context(), adjustments)
+	countOperation("adjust_stock_batch", err)
+	if err != nil {
+		h.writeStockError(w, err, "Failed to adjust stock")
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Success {
+			failed++
+		}
+	}
+	logging.FromContext(r.Context()).Info("Stock adjusted across products",
+		zap.Int("products", len(results)),
+		zap.Int("failed", failed),
+	)
+
+	respond.JSON(w, http.StatusOK, results)
+}
+
+// writeStockError maps stock errors to status codes
+func (h *StockHandler) writeStockError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidRequest), errors.Is(err, models.ErrVariantNotFound):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, models.ErrProductNotFound):
+		respond.Failure(w, http.StatusNotFound, err, err.Error())
+	default:
+		respond.ServiceError(w, err, fallback)
+	}
+}
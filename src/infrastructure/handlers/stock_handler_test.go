@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockStockService is a mock for the StockService interface
+type MockStockService struct {
+	mock.Mock
+}
+
+func (m *MockStockService) GetStock(ctx context.Context, productID, variantID string) (*models.VariantStock, error) {
+	args := m.Called(productID, variantID)
+	if stock, ok := args.Get(0).(*models.VariantStock); ok {
+		return stock, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStockService) SetStock(ctx context.Context, productID, variantID string, levels []models.StockLevel) (*models.VariantStock, error) {
+	args := m.Called(productID, variantID, levels)
+	if stock, ok := args.Get(0).(*models.VariantStock); ok {
+		return stock, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockStockService) AdjustStock(ctx context.Context, adjustments []models.ProductStockAdjustment) ([]*interfaces.BatchResult, error) {
+	args := m.Called(adjustments)
+	if results, ok := args.Get(0).([]*interfaces.BatchResult); ok {
+		return results, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestGetVariantStock(t *testing.T) {
+	mockService := new(MockStockService)
+	handler := NewStockHandler(mockService)
+	stock := &models.VariantStock{ProductID: "prod_1", VariantID: "v1", Version: 2, Stock: []models.Stock{{LocationID: "wh1", Quantity: 4}}}
+	mockService.On("GetStock", "prod_1", "v1").Return(stock, nil)
+	mockService.On("GetStock", "prod_1", "v9").Return(nil, fmt.Errorf("%w: v9", models.ErrVariantNotFound))
+	mockService.On("GetStock", "missing", "v1").Return(nil, models.ErrProductNotFound)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/variants/{vid}/stock", handler.GetStock)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/products/prod_1/variants/v1/stock", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.VariantStock
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, *stock, response)
+
+	for path, code := range map[string]int{
+		"/products/prod_1/variants/v9/stock":  http.StatusBadRequest,
+		"/products/missing/variants/v1/stock": http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		assert.Equal(t, code, w.Code, path)
+	}
+}
+
+func TestSetVariantStock(t *testing.T) {
+	mockService := new(MockStockService)
+	handler := NewStockHandler(mockService)
+	levels := []models.StockLevel{{LocationID: "wh1", Quantity: 7}}
+	mockService.On("SetStock", "prod_1", "v1", levels).
+		Return(&models.VariantStock{ProductID: "prod_1", VariantID: "v1", Version: 3, Stock: []models.Stock{{LocationID: "wh1", Quantity: 7}}}, nil)
+	mockService.On("SetStock", "prod_1", "v2", mock.Anything).Return(nil, fmt.Errorf("%w: variant v2 at wh1 has 0, requested 1", models.ErrInsufficientStock))
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/variants/{vid}/stock", handler.SetStock)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/products/prod_1/variants/v1/stock", strings.NewReader(`[{"location_id":"wh1","quantity":7}]`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.VariantStock
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, int64(3), response.Version)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/products/prod_1/variants/v2/stock", strings.NewReader(`[{"location_id":"wh1","quantity":-1}]`)))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("PUT", "/products/prod_1/variants/v1/stock", strings.NewReader(`{`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAdjustStockAcrossProducts(t *testing.T) {
+	mockService := new(MockStockService)
+	handler := NewStockHandler(mockService)
+	adjustments := []models.ProductStockAdjustment{
+		{ProductID: "prod_1", StockAdjustment: models.StockAdjustment{VariantID: "v1", LocationID: "wh1", Delta: -1}},
+		{ProductID: "prod_2", StockAdjustment: models.StockAdjustment{VariantID: "v1", LocationID: "wh1", Delta: 2}},
+	}
+	mockService.On("AdjustStock", adjustments).Return([]*interfaces.BatchResult{
+		{ID: "prod_1", Success: true},
+		{ID: "prod_2", Error: "insufficient stock"},
+	}, nil)
+
+	body := `[{"product_id":"prod_1","variant_id":"v1","location_id":"wh1","delta":-1},{"product_id":"prod_2","variant_id":"v1","location_id":"wh1","delta":2}]`
+	w := httptest.NewRecorder()
+	handler.AdjustStock(w, httptest.NewRequest("POST", "/stock/adjustments", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var results []*interfaces.BatchResult
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&results))
+	assert.Len(t, results, 2)
+	assert.False(t, results[1].Success)
+	mockService.AssertExpectations(t)
+}
+
+func TestAdjustStockAcrossProductsInvalidRequest(t *testing.T) {
+	mockService := new(MockStockService)
+	handler := NewStockHandler(mockService)
+	mockService.On("AdjustStock", mock.Anything).Return(nil, fmt.Errorf("%w: product_id is required", models.ErrInvalidRequest))
+
+	for _, body := range []string{`{`, `[{"variant_id":"v1","location_id":"wh1","delta":1}]`} {
+		w := httptest.NewRecorder()
+		handler.AdjustStock(w, httptest.NewRequest("POST", "/stock/adjustments", strings.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
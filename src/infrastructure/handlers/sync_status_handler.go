@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// SyncStatusHandler serves how far products have been delivered to downstream integrations
+type SyncStatusHandler struct {
+	service interfaces.SyncStatusService
+}
+
+// NewSyncStatusHandler creates a new sync status handler instance
+func NewSyncStatusHandler(service interfaces.SyncStatusService) *SyncStatusHandler {
+	return &SyncStatusHandler{
+		service: service,
+	}
+}
+
+// GetSyncStatus godoc
+// @Summary Product sync status
+// @Description Returns the last synced version and recent delivery errors of a product for every downstream target (search, feeds, marketplaces, webhook endpoints)
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} interfaces.ProductSyncStatus
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/{id}/sync-status [get]
+func (h *SyncStatusHandler) GetSyncStatus(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	status, err := h.service.GetSyncStatus(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, models.ErrProductNotFound) {
+			respond.Failure(w, http.StatusNotFound, err, fmt.Sprintf("Product with ID '%s' not found", id))
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to fetch sync status")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockSyncStatusService is a mock for the SyncStatusService interface
+type MockSyncStatusService struct {
+	mock.Mock
+}
+
+func (m *MockSyncStatusService) GetSyncStatus(ctx context.Context, productID string) (*interfaces.ProductSyncStatus, error) {
+	args := m.Called(productID)
+	if status, ok := args.Get(0).(*interfaces.ProductSyncStatus); ok {
+		return status, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestSyncStatusHandlerGetSyncStatus(t *testing.T) {
+	mockService := new(MockSyncStatusService)
+	mockService.On("GetSyncStatus", "prod_1").Return(&interfaces.ProductSyncStatus{
+		ProductID: "prod_1",
+		Version:   4,
+		Targets: []*models.SyncStatus{
+			{ProductID: "prod_1", Target: "marketplace:amazon", State: models.SyncStateFailed, SyncedVersion: 3, Error: "missing EUR price"},
+		},
+	}, nil)
+	handler := NewSyncStatusHandler(mockService)
+
+	router := mux.NewRouter()
+	router.HandleFunc("/products/{id}/sync-status", handler.GetSyncStatus)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/products/prod_1/sync-status", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var status interfaces.ProductSyncStatus
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&status))
+	assert.Equal(t, int64(4), status.Version)
+	assert.Len(t, status.Targets, 1)
+	assert.Equal(t, "missing EUR price", status.Targets[0].Error)
+}
+
+func TestSyncStatusHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"not found", models.ErrProductNotFound, http.StatusNotFound},
+		{"repository failure", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSyncStatusService)
+			mockService.On("GetSyncStatus", "prod_1").Return(nil, tt.err)
+			handler := NewSyncStatusHandler(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/products/{id}/sync-status", handler.GetSyncStatus)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/products/prod_1/sync-status", nil))
+
+			assert.Equal(t, tt.wantCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// TagHandler manages product tags and runs operations on tagged products
+type TagHandler struct {
+	service interfaces.ProductService
+}
+
+// NewTagHandler creates a new tag handler instance
+func NewTagHandler(service interfaces.ProductService) *TagHandler {
+	return &TagHandler{
+		service: service,
+	}
+}
+
+// ListTags godoc
+// @Summary List tags
+// @Description Returns every tag in use with the number of products that have it, most used first
+// @Tags products
+// @Produce json
+// @Success 200 {array} models.TagUsage
+// @Failure 500 {object} models.APIError
+// @Router /tags [get]
+func (h *TagHandler) ListTags(w http.ResponseWriter, r *http.Request) {
+	usage, err := h.service.TagUsage(r.Context())
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to count tags")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, usage)
+}
+
+// UpdateTags godoc
+// @Summary Add and remove tags in bulk
+// @Description Starts a background job that adds and removes tags on the listed products, or on every product with a tag. Each changed product is written as an update tagged with the job, so the change can be undone with POST /jobs/{id}/rollback.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param update body models.TagUpdate true "Products and tags"
+// @Success 202 {object} models.Job
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /products/tags [post]
+func (h *TagHandler) UpdateTags(w http.ResponseWriter, r *http.Request) {
+	var update models.TagUpdate
+	if err := decodeJSON(r, &update); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	job, err := h.service.UpdateTags(r.Context(), &update)
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRequest) {
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to start tag update")
+		return
+	}
+
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	respond.JSON(w, http.StatusAccepted, job)
+}
+
+// DeleteTaggedProducts godoc
+// @Summary Delete tagged products
+// @Description Deletes every product with the tag as one batch job, which can be undone with POST /jobs/{id}/rollback
+// @Tags products
+// @Produce json
+// @Param tag path string true "Tag"
+// @Success 200 {array} interfaces.BatchResult
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /tags/{tag}/products [delete]
+func (h *TagHandler) DeleteTaggedProducts(w http.ResponseWriter, r *http.Request) {
+	results, err := h.service.DeleteProductsByTag(r.Context(), mux.Vars(r)["tag"])
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidRequest) {
+			respond.Failure(w, http.StatusBadRequest, err, err.Error())
+			return
+		}
+		respond.Error(w, http.StatusInternalServerError, "Failed to delete tagged products")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, results)
+}
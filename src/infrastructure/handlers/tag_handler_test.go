@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestTagHandlerListTags(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewTagHandler(mockService)
+	mockService.On("TagUsage").Return([]*models.TagUsage{{Tag: "spring-2025", Products: 3}, {Tag: "sale", Products: 1}}, nil)
+
+	w := httptest.NewRecorder()
+	handler.ListTags(w, httptest.NewRequest("GET", "/tags", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var usage []*models.TagUsage
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&usage))
+	assert.Equal(t, "spring-2025", usage[0].Tag)
+	assert.Equal(t, 3, usage[0].Products)
+}
+
+func TestTagHandlerUpdateTags(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewTagHandler(mockService)
+	mockService.On("UpdateTags", mock.MatchedBy(func(update *models.TagUpdate) bool {
+		return update.Tag == "spring-2025" && update.Add[0] == "published"
+	})).Return(&models.Job{ID: "job_1", Type: models.JobTagUpdate, Status: models.JobStatusRunning}, nil)
+
+	body := `{"tag": "spring-2025", "add": ["published"]}`
+	w := httptest.NewRecorder()
+	handler.UpdateTags(w, httptest.NewRequest("POST", "/products/tags", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, "/jobs/job_1", w.Header().Get("Location"))
+
+	// Invalid updates are rejected before a job starts
+	mockService.On("UpdateTags", mock.Anything).Return(nil, models.ErrInvalidRequest)
+	w = httptest.NewRecorder()
+	handler.UpdateTags(w, httptest.NewRequest("POST", "/products/tags", strings.NewReader(`{"tag": "x"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.UpdateTags(w, httptest.NewRequest("POST", "/products/tags", strings.NewReader(`{`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTagHandlerDeleteTaggedProducts(t *testing.T) {
+	mockService := new(MockProductService)
+	handler := NewTagHandler(mockService)
+	mockService.On("DeleteProductsByTag", "clearance").Return([]*interfaces.BatchResult{{ID: "prod_1", Success: true}}, nil)
+	mockService.On("DeleteProductsByTag", "broken").Return(nil, errors.New("boom"))
+
+	req := mux.SetURLVars(httptest.NewRequest("DELETE", "/tags/clearance/products", nil), map[string]string{"tag": "clearance"})
+	w := httptest.NewRecorder()
+	handler.DeleteTaggedProducts(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var results []*interfaces.BatchResult
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&results))
+	assert.True(t, results[0].Success)
+
+	req = mux.SetURLVars(httptest.NewRequest("DELETE", "/tags/broken/products", nil), map[string]string{"tag": "broken"})
+	w = httptest.NewRecorder()
+	handler.DeleteTaggedProducts(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// VersionHandler reports the running build
+type VersionHandler struct {
+	info *models.VersionInfo
+}
+
+// NewVersionHandler creates a new version handler instance
+func NewVersionHandler(info *models.VersionInfo) *VersionHandler {
+	return &VersionHandler{
+		info: info,
+	}
+}
+
+// GetVersion godoc
+// @Summary Get the build version
+// @Description Returns the version and commit of the running build and the event schema versions it publishes and reads. Clients compare these with their own to detect incompatible builds during rolling upgrades.
+// @Tags system
+// @Produce json
+// @Success 200 {object} models.VersionInfo
+// @Router /version [get]
+func (h *VersionHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, h.info)
+}
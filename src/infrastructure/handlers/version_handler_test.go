@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestVersionHandlerGetVersion(t *testing.T) {
+	handler := NewVersionHandler(&models.VersionInfo{
+		BuildInfo:   &models.BuildInfo{Version: "v1.4.0", Commit: "abc123", GoVersion: "go1.22"},
+		EventSchema: models.EventSchemaCompatibility{Current: 1, Supported: []int{1}},
+	})
+
+	w := httptest.NewRecorder()
+	handler.GetVersion(w, httptest.NewRequest("GET", "/version", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string]interface{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+	assert.Equal(t, "v1.4.0", body["version"])
+	assert.Equal(t, "abc123", body["commit"])
+	assert.Equal(t, map[string]interface{}{"current": float64(1), "supported": []interface{}{float64(1)}}, body["event_schema"])
+}
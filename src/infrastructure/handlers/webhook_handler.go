@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// WebhookRequest registers a webhook endpoint
+type WebhookRequest struct {
+	URL string `json:"url" example:"https://erp.example.com/hooks/catalog"`
+	// EventTypes are the events delivered; empty subscribes to every product event
+	EventTypes  []models.EventType `json:"event_types,omitempty"`
+	Description string             `json:"description,omitempty"`
+	// Secret signs the deliveries, at least 16 characters; generated when empty
+	Secret string `json:"secret,omitempty"`
+	// Auth adds a bearer token to the deliveries, see Webhook Authentication
+	Auth *models.WebhookAuth `json:"auth,omitempty"`
+}
+
+// WebhookRegistration is a registered endpoint with its signing secret, which
+// is not returned again
+type WebhookRegistration struct {
+	*models.WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// WebhookState disables or re-enables an endpoint
+type WebhookState struct {
+	Disabled bool `json:"disabled"`
+}
+
+// WebhookHandler manages the webhook endpoints product events are delivered to
+type WebhookHandler struct {
+	service interfaces.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler instance
+func NewWebhookHandler(service interfaces.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		service: service,
+	}
+}
+
+// writeWebhookError maps webhook errors to responses
+func (h *WebhookHandler) writeWebhookError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrInvalidRequest):
+		respond.Failure(w, http.StatusBadRequest, err, err.Error())
+	case errors.Is(err, models.ErrWebhookNotFound):
+		respond.Failure(w, http.StatusNotFound, err, "Webhook endpoint not found")
+	default:
+		respond.Error(w, http.StatusInternalServerError, message)
+	}
+}
+
+// RegisterWebhook godoc
+// @Summary Register a webhook endpoint
+// @Description Registers a URL that product events are POSTed to as JSON, signed with the secret in X-Ecom-Signature. The secret is only returned here.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body WebhookRequest true "Endpoint"
+// @Success 201 {object} WebhookRegistration
+// @Failure 400 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /webhooks [post]
+func (h *WebhookHandler) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	var req WebhookRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	endpoint, err := h.service.Register(&models.WebhookEndpoint{
+		URL:         req.URL,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		Secret:      req.Secret,
+		Auth:        req.Auth,
+	})
+	if err != nil {
+		h.writeWebhookError(w, err, "Failed to register webhook endpoint")
+		return
+	}
+
+	respond.JSON(w, http.StatusCreated, WebhookRegistration{WebhookEndpoint: endpoint, Secret: endpoint.Secret})
+}
+
+// ListWebhooks godoc
+// @Summary List webhook endpoints
+// @Description Returns every registered endpoint, oldest first, without secrets
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} models.WebhookEndpoint
+// @Failure 500 {object} models.APIError
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	endpoints, err := h.service.List()
+	if err != nil {
+		h.writeWebhookError(w, err, "Failed to list webhook endpoints")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, endpoints)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook endpoint
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Endpoint ID"
+// @Success 200 {object} models.WebhookEndpoint
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	endpoint, err := h.service.Get(mux.Vars(r)["id"])
+	if err != nil {
+		h.writeWebhookError(w, err, "Failed to fetch webhook endpoint")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, endpoint)
+}
+
+// SetWebhookState godoc
+// @Summary Disable or enable a webhook endpoint
+// @Description A disabled endpoint receives no deliveries, and its scheduled retries are dropped. Events published while it was disabled are not delivered when it is enabled again.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Endpoint ID"
+// @Param state body WebhookState true "Whether the endpoint is disabled"
+// @Success 200 {object} models.WebhookEndpoint
+// @Failure 400 {object} models.APIError
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /webhooks/{id}/state [put]
+func (h *WebhookHandler) SetWebhookState(w http.ResponseWriter, r *http.Request) {
+	var state WebhookState
+	if err := decodeJSON(r, &state); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	endpoint, err := h.service.SetDisabled(mux.Vars(r)["id"], state.Disabled)
+	if err != nil {
+		h.writeWebhookError(w, err, "Failed to update webhook endpoint")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, endpoint)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook endpoint
+// @Description Removes an endpoint and its delivery log
+// @Tags webhooks
+// @Param id path string true "Endpoint ID"
+// @Success 204 "No Content"
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.Delete(mux.Vars(r)["id"]); err != nil {
+		h.writeWebhookError(w, err, "Failed to delete webhook endpoint")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary Webhook delivery log
+// @Description Returns the latest delivery attempts to an endpoint, newest first, with the response status, error and next retry of each
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Endpoint ID"
+// @Param limit query int false "Maximum number of attempts" default(20)
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 404 {object} models.APIError
+// @Failure 500 {object} models.APIError
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.service.Deliveries(mux.Vars(r)["id"], limitParam(r))
+	if err != nil {
+		h.writeWebhookError(w, err, "Failed to fetch webhook deliveries")
+		return
+	}
+
+	respond.JSON(w, http.StatusOK, deliveries)
+}
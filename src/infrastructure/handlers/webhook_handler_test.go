@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockWebhookService is a mock for the WebhookService interface
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) Register(endpoint *models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	args := m.Called(endpoint)
+	if registered, ok := args.Get(0).(*models.WebhookEndpoint); ok {
+		return registered, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookService) List() ([]*models.WebhookEndpoint, error) {
+	args := m.Called()
+	if endpoints, ok := args.Get(0).([]*models.WebhookEndpoint); ok {
+		return endpoints, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookService) Get(id string) (*models.WebhookEndpoint, error) {
+	args := m.Called(id)
+	if endpoint, ok := args.Get(0).(*models.WebhookEndpoint); ok {
+		return endpoint, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookService) SetDisabled(id string, disabled bool) (*models.WebhookEndpoint, error) {
+	args := m.Called(id, disabled)
+	if endpoint, ok := args.Get(0).(*models.WebhookEndpoint); ok {
+		return endpoint, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockWebhookService) Delete(id string) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *MockWebhookService) Deliveries(id string, limit int) ([]*models.WebhookDelivery, error) {
+	args := m.Called(id, limit)
+	if deliveries, ok := args.Get(0).([]*models.WebhookDelivery); ok {
+		return deliveries, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func TestWebhookHandlerRegisterReturnsSecretOnce(t *testing.T) {
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+	registered := &models.WebhookEndpoint{ID: "wh_1", URL: "https://erp.example.com/hooks", Secret: "0123456789abcdef"}
+	mockService.On("Register", mock.MatchedBy(func(endpoint *models.WebhookEndpoint) bool {
+		return endpoint.URL == "https://erp.example.com/hooks" &&
+			len(endpoint.EventTypes) == 1 && endpoint.EventTypes[0] == models.EventProductUpdated &&
+			endpoint.Secret == "0123456789abcdef"
+	})).Return(registered, nil)
+	mockService.On("Get", "wh_1").Return(registered, nil)
+
+	req := httptest.NewRequest("POST", "/webhooks", strings.NewReader(`{"url":"https://erp.example.com/hooks","event_types":["product.updated"],"secret":"0123456789abcdef"}`))
+	w := httptest.NewRecorder()
+	handler.RegisterWebhook(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	var response map[string]interface{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, "wh_1", response["id"])
+	assert.Equal(t, "0123456789abcdef", response["secret"])
+
+	req = httptest.NewRequest("GET", "/webhooks/wh_1", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "wh_1"})
+	w = httptest.NewRecorder()
+	handler.GetWebhook(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "0123456789abcdef")
+}
+
+func TestWebhookHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(*WebhookHandler) http.HandlerFunc
+		method  string
+		path    string
+		body    string
+		setup   func(*MockWebhookService)
+		status  int
+	}{
+		{
+			name:    "invalid JSON",
+			handler: func(h *WebhookHandler) http.HandlerFunc { return h.RegisterWebhook },
+			method:  "POST",
+			path:    "/webhooks",
+			body:    "{",
+			setup:   func(m *MockWebhookService) {},
+			status:  http.StatusBadRequest,
+		},
+		{
+			name:    "invalid endpoint",
+			handler: func(h *WebhookHandler) http.HandlerFunc { return h.RegisterWebhook },
+			method:  "POST",
+			path:    "/webhooks",
+			body:    `{"url":"ftp://example.com"}`,
+			setup: func(m *MockWebhookService) {
+				m.On("Register", mock.Anything).Return(nil, fmt.Errorf("%w: url must be an absolute http or https URL", models.ErrInvalidRequest))
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:    "unknown endpoint",
+			handler: func(h *WebhookHandler) http.HandlerFunc { return h.SetWebhookState },
+			method:  "PUT",
+			path:    "/webhooks/wh_missing/state",
+			body:    `{"disabled":true}`,
+			setup: func(m *MockWebhookService) {
+				m.On("SetDisabled", "wh_missing", true).Return(nil, models.ErrWebhookNotFound)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:    "failed delete",
+			handler: func(h *WebhookHandler) http.HandlerFunc { return h.DeleteWebhook },
+			method:  "DELETE",
+			path:    "/webhooks/wh_1",
+			setup: func(m *MockWebhookService) {
+				m.On("Delete", "wh_1").Return(fmt.Errorf("storage down"))
+			},
+			status: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockWebhookService)
+			tt.setup(mockService)
+
+			router := mux.NewRouter()
+			router.HandleFunc("/webhooks", tt.handler(NewWebhookHandler(mockService)))
+			router.HandleFunc("/webhooks/{id}", tt.handler(NewWebhookHandler(mockService)))
+			router.HandleFunc("/webhooks/{id}/state", tt.handler(NewWebhookHandler(mockService)))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.status, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandlerDisableAndDeliveries(t *testing.T) {
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+	mockService.On("SetDisabled", "wh_1", true).Return(&models.WebhookEndpoint{ID: "wh_1", Disabled: true}, nil)
+	mockService.On("Deliveries", "wh_1", 5).Return([]*models.WebhookDelivery{{ID: "d1", EndpointID: "wh_1", StatusCode: 503}}, nil)
+
+	req := httptest.NewRequest("PUT", "/webhooks/wh_1/state", strings.NewReader(`{"disabled":true}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "wh_1"})
+	w := httptest.NewRecorder()
+	handler.SetWebhookState(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var endpoint models.WebhookEndpoint
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&endpoint))
+	assert.True(t, endpoint.Disabled)
+
+	req = httptest.NewRequest("GET", "/webhooks/wh_1/deliveries?limit=5", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "wh_1"})
+	w = httptest.NewRecorder()
+	handler.ListWebhookDeliveries(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var deliveries []models.WebhookDelivery
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&deliveries))
+	assert.Equal(t, 503, deliveries[0].StatusCode)
+}
@@ -1,18 +1,26 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"math/rand"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
 	"github.com/jimmitjoo/ecom/src/domain/events"
 	"github.com/jimmitjoo/ecom/src/domain/models"
 
-	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+	"github.com/jimmitjoo/ecom/src/infrastructure/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
@@ -24,17 +32,109 @@ var upgrader = websocket.Upgrader{
 	},
 }
 
+// SnapshotMode selects what a client receives right after connecting
+type SnapshotMode string
+
+const (
+	SnapshotNone     SnapshotMode = "none"
+	SnapshotEvents   SnapshotMode = "events"
+	SnapshotProducts SnapshotMode = "products"
+)
+
+// maxSnapshotLimit caps the number of items a client may request in a snapshot
+const maxSnapshotLimit = 500
+
+// SnapshotConfig holds the default snapshot behaviour for new connections
+type SnapshotConfig struct {
+	Mode  SnapshotMode
+	Limit int
+}
+
+// SnapshotProvider supplies the initial state sent to newly connected clients
+type SnapshotProvider interface {
+	RecentEvents(limit int) []*models.Event
+	RecentlyUpdatedProducts(limit int) []*interfaces.ProductSummary
+}
+
+// SnapshotFrame is the first message sent to a client when snapshots are enabled
+type SnapshotFrame struct {
+	Type     string                       `json:"type"`
+	Mode     SnapshotMode                 `json:"mode"`
+	Events   []*models.Event              `json:"events,omitempty"`
+	Products []*interfaces.ProductSummary `json:"products,omitempty"`
+}
+
+// ReconnectPolicy controls the reconnect hint sent to clients on a planned shutdown.
+// Each client is told to wait After plus its own share of Spread, so reconnects
+// arrive spread out over the window instead of all at once.
+type ReconnectPolicy struct {
+	After  time.Duration
+	Spread time.Duration
+}
+
+// ReconnectFrame is the last message sent to a client before a planned shutdown
+type ReconnectFrame struct {
+	Type             string `json:"type"`
+	ReconnectAfterMs int64  `json:"reconnect_after_ms"`
+}
+
+// shutdownWriteTimeout bounds how long a slow client can delay the shutdown notice
+const shutdownWriteTimeout = time.Second
+
+// DefaultMaxMissedPongs is the number of unanswered pings after which a client is dropped
+const DefaultMaxMissedPongs = 2
+
+// pingWriteTimeout bounds how long a slow client can delay a keepalive ping
+const pingWriteTimeout = time.Second
+
+// KeepaliveConfig controls the pings sent to detect stale connections. Every
+// PingInterval each client is pinged, and a client that has not answered its
+// last MaxMissedPongs pings with a pong is dropped.
+type KeepaliveConfig struct {
+	PingInterval   time.Duration
+	MaxMissedPongs int
+}
+
+// readTimeout is how long a connection may stay silent before reads fail
+func (c KeepaliveConfig) readTimeout() time.Duration {
+	return c.PingInterval * time.Duration(c.MaxMissedPongs+1)
+}
+
+// webSocketEventTypes are the events forwarded to clients
+var webSocketEventTypes = []models.EventType{
+	models.EventProductCreated,
+	models.EventProductUpdated,
+	models.EventProductDeleted,
+	models.EventProductRestored,
+	models.EventStockChanged,
+	models.EventPriceChanged,
+	models.EventCategoryCreated,
+	models.EventCategoryUpdated,
+	models.EventCategoryDeleted,
+}
+
 type WebSocketHandler struct {
-	clients   map[*websocket.Conn]bool
+	clients   map[*websocket.Conn]*wsClient
 	publisher events.EventPublisher
 	mu        sync.RWMutex
-	writeMu   sync.Mutex // New mutex for write operations
+	sendQueue SendQueueConfig
+
+	snapshots      SnapshotProvider
+	snapshotConfig SnapshotConfig
+
+	keepalive     KeepaliveConfig
+	stopKeepalive chan struct{}
+	keepaliveDone chan struct{}
+
+	closing    bool
+	retryAfter time.Duration
 }
 
 func NewWebSocketHandler(publisher events.EventPublisher) *WebSocketHandler {
 	handler := &WebSocketHandler{
-		clients:   make(map[*websocket.Conn]bool),
+		clients:   make(map[*websocket.Conn]*wsClient),
 		publisher: publisher,
+		sendQueue: SendQueueConfig{Size: DefaultSendQueueSize, Overflow: OverflowDisconnect},
 	}
 
 	// Subscribe to all product events
@@ -43,22 +143,204 @@ func NewWebSocketHandler(publisher events.EventPublisher) *WebSocketHandler {
 	return handler
 }
 
-// writeMessage is a thread-safe wrapper for writing to a WebSocket connection
-func (h *WebSocketHandler) writeMessage(conn *websocket.Conn, messageType int, data []byte) error {
-	h.writeMu.Lock()
-	defer h.writeMu.Unlock()
-	return conn.WriteMessage(messageType, data)
+// EnableSnapshots makes new connections receive a snapshot frame before live events.
+// Clients can override the configured defaults with the snapshot and snapshot_limit query parameters.
+func (h *WebSocketHandler) EnableSnapshots(provider SnapshotProvider, config SnapshotConfig) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.snapshots = provider
+	h.snapshotConfig = config
+}
+
+// EnableKeepalive pings every client each config.PingInterval and drops the
+// clients that miss config.MaxMissedPongs pongs in a row. Reads on connections
+// that stay silent for longer fail as well, so stale connections are cleaned
+// up even when no event is written to them. An interval of 0 disables it.
+func (h *WebSocketHandler) EnableKeepalive(config KeepaliveConfig) {
+	if config.PingInterval <= 0 {
+		return
+	}
+	if config.MaxMissedPongs <= 0 {
+		config.MaxMissedPongs = DefaultMaxMissedPongs
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.stopKeepalive != nil {
+		return
+	}
+	h.keepalive = config
+	h.stopKeepalive = make(chan struct{})
+	h.keepaliveDone = make(chan struct{})
+	go h.runKeepalive(config.PingInterval, h.stopKeepalive, h.keepaliveDone)
+}
+
+// runKeepalive pings the clients until stop is closed
+func (h *WebSocketHandler) runKeepalive(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			h.pingClients()
+		}
+	}
+}
+
+// pingClients drops the clients that missed too many pongs and pings the
+// rest. It returns the number of clients dropped.
+func (h *WebSocketHandler) pingClients() int {
+	h.mu.Lock()
+	maxMissed := h.keepalive.MaxMissedPongs
+	var stale, alive []*websocket.Conn
+	for conn, client := range h.clients {
+		if client.missedPongs >= maxMissed {
+			stale = append(stale, conn)
+			h.forgetClient(conn)
+			continue
+		}
+		client.missedPongs++
+		alive = append(alive, conn)
+	}
+	remaining := len(h.clients)
+	h.mu.Unlock()
+
+	metrics.WebSocketClientsReaped.Add(float64(len(stale)))
+	for _, conn := range stale {
+		conn.Close()
+	}
+	if len(stale) > 0 {
+		logging.Shared().Info("Dropped stale WebSocket clients",
+			zap.Int("dropped", len(stale)),
+			zap.Int("max_missed_pongs", maxMissed),
+			zap.Int("remaining_clients", remaining),
+		)
+	}
+
+	for _, conn := range alive {
+		if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(pingWriteTimeout)); err != nil {
+			log.Printf("Failed to send ping: %v", err)
+			h.removeClient(conn)
+			conn.Close()
+		}
+	}
+	return len(stale)
+}
+
+// pongReceived resets the missed pongs of a client
+func (h *WebSocketHandler) pongReceived(conn *websocket.Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if client, exists := h.clients[conn]; exists {
+		client.missedPongs = 0
+	}
+}
+
+// extendReadDeadline gives a connection another read timeout to send
+// something, when keepalive is enabled
+func (h *WebSocketHandler) extendReadDeadline(conn *websocket.Conn, config KeepaliveConfig) error {
+	if config.PingInterval <= 0 {
+		return nil
+	}
+	return conn.SetReadDeadline(time.Now().Add(config.readTimeout()))
+}
+
+// stopKeepalives stops the keepalive pings and waits for a round in progress
+func (h *WebSocketHandler) stopKeepalives() {
+	h.mu.Lock()
+	stop, done := h.stopKeepalive, h.keepaliveDone
+	h.stopKeepalive = nil
+	h.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// removeClient forgets a client and returns the number of clients left
+func (h *WebSocketHandler) removeClient(conn *websocket.Conn) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.forgetClient(conn)
+	return len(h.clients)
+}
+
+// forgetClient unregisters a client and closes its send queue, which stops
+// its writer. Callers hold h.mu for writing.
+func (h *WebSocketHandler) forgetClient(conn *websocket.Conn) {
+	client, exists := h.clients[conn]
+	if !exists {
+		return
+	}
+	delete(h.clients, conn)
+	close(client.send)
+	metrics.ActiveWebSocketConnections.Dec()
+}
+
+// snapshotFor resolves the snapshot settings for a connection request
+func (h *WebSocketHandler) snapshotFor(r *http.Request) (SnapshotProvider, SnapshotConfig) {
+	h.mu.RLock()
+	provider, config := h.snapshots, h.snapshotConfig
+	h.mu.RUnlock()
+
+	query := r.URL.Query()
+	switch mode := SnapshotMode(query.Get("snapshot")); mode {
+	case SnapshotNone, SnapshotEvents, SnapshotProducts:
+		config.Mode = mode
+	}
+	if limit, err := strconv.Atoi(query.Get("snapshot_limit")); err == nil && limit > 0 {
+		config.Limit = limit
+	}
+	if config.Limit <= 0 || config.Limit > maxSnapshotLimit {
+		config.Limit = maxSnapshotLimit
+	}
+	return provider, config
+}
+
+// buildSnapshot creates the snapshot frame for the given settings, or nil if none should be sent
+func buildSnapshot(provider SnapshotProvider, config SnapshotConfig) *SnapshotFrame {
+	if provider == nil {
+		return nil
+	}
+
+	frame := &SnapshotFrame{Type: "snapshot", Mode: config.Mode}
+	switch config.Mode {
+	case SnapshotEvents:
+		frame.Events = provider.RecentEvents(config.Limit)
+	case SnapshotProducts:
+		frame.Products = provider.RecentlyUpdatedProducts(config.Limit)
+	default:
+		return nil
+	}
+	return frame
+}
+
+// ClientCount returns the number of currently connected clients
+func (h *WebSocketHandler) ClientCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
 }
 
 func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
-	requestID := uuid.New().String()
-	logger, _ := logging.NewLogger()
-	logger = logger.WithRequestID(requestID)
+	logger := logging.FromContext(r.Context())
 
 	logger.Debug("New WebSocket connection attempt",
 		zap.String("remote_addr", r.RemoteAddr),
 	)
 
+	h.mu.RLock()
+	closing, retryAfter := h.closing, h.retryAfter
+	h.mu.RUnlock()
+	if closing {
+		w.Header().Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+		respond.Error(w, http.StatusServiceUnavailable, "Server is restarting")
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		logger.Error("WebSocket upgrade failed",
@@ -68,10 +350,27 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	// Queue the snapshot before registering so it is the first frame the client sees
+	provider, snapshotConfig := h.snapshotFor(r)
+	client := h.newClient(conn)
+	if frame := buildSnapshot(provider, snapshotConfig); frame != nil {
+		if data, err := json.Marshal(frame); err != nil {
+			logger.Error("Failed to send snapshot",
+				zap.Error(err),
+				zap.String("remote_addr", r.RemoteAddr),
+			)
+		} else {
+			client.enqueue(data)
+		}
+	}
+	go h.writePump(client)
+
 	h.mu.Lock()
-	h.clients[conn] = true
+	h.clients[conn] = client
 	clientCount := len(h.clients)
+	keepalive := h.keepalive
 	h.mu.Unlock()
+	metrics.ActiveWebSocketConnections.Inc()
 
 	logger.Info("New WebSocket client connected",
 		zap.String("remote_addr", r.RemoteAddr),
@@ -80,10 +379,7 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 
 	// Clean up client when connection closes
 	defer func() {
-		h.mu.Lock()
-		delete(h.clients, conn)
-		clientCount := len(h.clients)
-		h.mu.Unlock()
+		clientCount := h.removeClient(conn)
 		conn.Close()
 
 		logger.Info("WebSocket client disconnected",
@@ -92,9 +388,19 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 		)
 	}()
 
+	// Reads fail once the client stops answering pings
+	h.extendReadDeadline(conn, keepalive)
+	conn.SetPongHandler(func(string) error {
+		h.pongReceived(conn)
+		return h.extendReadDeadline(conn, keepalive)
+	})
+
 	// Keep connection open and handle messages
 	for {
-		messageType, _, err := conn.ReadMessage()
+		messageType, data, err := conn.ReadMessage()
+		if err == nil {
+			err = h.extendReadDeadline(conn, keepalive)
+		}
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("Websocket error: %v", err)
@@ -102,23 +408,155 @@ func (h *WebSocketHandler) HandleWebSocket(w http.ResponseWriter, r *http.Reques
 			break
 		}
 
-		if messageType == websocket.PingMessage {
-			if err := h.writeMessage(conn, websocket.PongMessage, nil); err != nil {
+		switch messageType {
+		case websocket.PingMessage:
+			if err := client.write(websocket.PongMessage, nil); err != nil {
 				log.Printf("Failed to send pong: %v", err)
-				break
+				return
+			}
+		case websocket.TextMessage:
+			if err := h.handleClientMessage(client, data); err != nil {
+				log.Printf("Failed to answer client message: %v", err)
+				return
 			}
 		}
 	}
 }
 
-func (h *WebSocketHandler) subscribeToEvents() {
-	eventTypes := []models.EventType{
-		models.EventProductCreated,
-		models.EventProductUpdated,
-		models.EventProductDeleted,
+// handleClientMessage applies a subscription message to the connection's
+// filter and queues the resulting filter, or an error frame, as the answer
+func (h *WebSocketHandler) handleClientMessage(client *wsClient, data []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.clients[client.conn]; !exists {
+		return nil
 	}
 
-	for _, eventType := range eventTypes {
+	var reply interface{}
+	if message, err := parseSubscriptionMessage(data); err != nil {
+		reply = &ErrorFrame{Type: "error", Error: err.Error()}
+	} else {
+		client.filter.apply(message)
+		reply = client.filter.frame()
+	}
+	frame, err := json.Marshal(reply)
+	if err != nil {
+		return err
+	}
+	if !client.enqueue(frame) {
+		return fmt.Errorf("send queue full")
+	}
+	return nil
+}
+
+// Shutdown tells every connected client to reconnect later and closes the connections
+// with a service restart close frame. New connections are refused from this point on.
+// It returns the number of clients that were notified.
+func (h *WebSocketHandler) Shutdown(policy ReconnectPolicy) int {
+	h.stopKeepalives()
+
+	h.mu.Lock()
+	h.closing = true
+	h.retryAfter = policy.After + policy.Spread
+	clients := make([]*wsClient, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mu.Unlock()
+
+	delays := reconnectDelays(len(clients), policy)
+	notified := 0
+	for i, client := range clients {
+		if err := sendReconnect(client, delays[i]); err != nil {
+			log.Printf("Failed to send reconnect hint: %v", err)
+			client.conn.Close()
+			continue
+		}
+		notified++
+	}
+
+	logging.Shared().Info("WebSocket clients asked to reconnect",
+		zap.Int("notified", notified),
+		zap.Int("total_clients", len(clients)),
+		zap.Duration("reconnect_after", policy.After),
+		zap.Duration("spread", policy.Spread),
+	)
+	return notified
+}
+
+// drainInterval is how often Drain checks for clients still connected
+const drainInterval = 10 * time.Millisecond
+
+// Drain waits for the clients told to reconnect by Shutdown to complete the
+// close handshake, and closes the connections still open when the context
+// ends. It returns the number of connections it closed.
+func (h *WebSocketHandler) Drain(ctx context.Context) int {
+	ticker := time.NewTicker(drainInterval)
+	defer ticker.Stop()
+	for h.ClientCount() > 0 {
+		select {
+		case <-ctx.Done():
+			h.mu.Lock()
+			clients := make([]*websocket.Conn, 0, len(h.clients))
+			for client := range h.clients {
+				clients = append(clients, client)
+				h.forgetClient(client)
+			}
+			h.mu.Unlock()
+			for _, client := range clients {
+				client.Close()
+			}
+			return len(clients)
+		case <-ticker.C:
+		}
+	}
+	return 0
+}
+
+// sendReconnect writes the reconnect frame followed by a close frame carrying
+// the same hint, ahead of the messages still queued for the client
+func sendReconnect(client *wsClient, delay time.Duration) error {
+	frame, err := json.Marshal(&ReconnectFrame{Type: "reconnect", ReconnectAfterMs: delay.Milliseconds()})
+	if err != nil {
+		return err
+	}
+
+	client.writeMu.Lock()
+	defer client.writeMu.Unlock()
+	conn := client.conn
+
+	deadline := time.Now().Add(shutdownWriteTimeout)
+	conn.SetWriteDeadline(deadline)
+	if err := conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+		return err
+	}
+	reason := fmt.Sprintf(`{"reconnect_after_ms":%d}`, delay.Milliseconds())
+	return conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseServiceRestart, reason), deadline)
+}
+
+// reconnectDelays gives each of n clients a delay within [After, After+Spread].
+// The window is cut into n equal slots with a random point in each, so delays are
+// jittered but still evenly spread, and the slots are shuffled across clients.
+func reconnectDelays(n int, policy ReconnectPolicy) []time.Duration {
+	delays := make([]time.Duration, n)
+	if n == 0 {
+		return delays
+	}
+	slot := policy.Spread / time.Duration(n)
+	for i := range delays {
+		delays[i] = policy.After + slot*time.Duration(i)
+		if slot > 0 {
+			delays[i] += time.Duration(rand.Int63n(int64(slot)))
+		}
+	}
+	rand.Shuffle(n, func(i, j int) {
+		delays[i], delays[j] = delays[j], delays[i]
+	})
+	return delays
+}
+
+func (h *WebSocketHandler) subscribeToEvents() {
+	for _, eventType := range webSocketEventTypes {
 		h.publisher.Subscribe(eventType, func(event *models.Event) {
 			h.broadcastEvent(event)
 		})
@@ -126,7 +564,13 @@ func (h *WebSocketHandler) subscribeToEvents() {
 }
 
 func (h *WebSocketHandler) broadcastEvent(event *models.Event) {
-	logger, _ := logging.NewLogger()
+	logger := logging.Shared()
+	_, span := tracing.Start(context.Background(), "websocket.broadcast",
+		attribute.String("event.id", event.ID),
+		attribute.String("event.type", string(event.Type)),
+		attribute.String("product.id", event.EntityID),
+	)
+	defer span.End()
 
 	startTime := time.Now()
 	data, err := json.Marshal(event)
@@ -139,44 +583,50 @@ func (h *WebSocketHandler) broadcastEvent(event *models.Event) {
 		return
 	}
 
+	// Only clients whose subscriptions match the event receive it. Messages are
+	// queued per client, so a slow client does not hold up the others.
 	h.mu.RLock()
 	clientCount := len(h.clients)
-	h.mu.RUnlock()
-
-	logger.Debug("Starting event broadcast",
-		zap.String("event_type", string(event.Type)),
-		zap.String("event_id", event.ID),
-		zap.Int("client_count", clientCount),
-	)
-
-	successCount := 0
-	failCount := 0
-
-	h.mu.RLock()
-	clients := make([]*websocket.Conn, 0, len(h.clients))
-	for client := range h.clients {
-		clients = append(clients, client)
+	queuedCount := 0
+	droppedCount := 0
+	var overflowed []*websocket.Conn
+	for conn, client := range h.clients {
+		if !client.filter.matches(event) {
+			continue
+		}
+		if client.enqueue(data) {
+			queuedCount++
+			continue
+		}
+		if client.overflow == OverflowDrop {
+			metrics.WebSocketQueueOverflows.WithLabelValues("dropped").Inc()
+			droppedCount++
+		} else {
+			overflowed = append(overflowed, conn)
+		}
 	}
 	h.mu.RUnlock()
 
-	for _, client := range clients {
-		if err := h.writeMessage(client, websocket.TextMessage, data); err != nil {
-			log.Printf("Failed to send message to client: %v", err)
-			h.mu.Lock()
-			delete(h.clients, client)
-			h.mu.Unlock()
-			client.Close()
-			failCount++
-		} else {
-			successCount++
-		}
+	for _, conn := range overflowed {
+		log.Printf("Disconnecting WebSocket client with a full send queue")
+		metrics.WebSocketQueueOverflows.WithLabelValues("disconnected").Inc()
+		h.removeClient(conn)
+		conn.Close()
 	}
 
+	span.SetAttributes(
+		attribute.Int("websocket.clients", clientCount),
+		attribute.Int("websocket.queued", queuedCount),
+		attribute.Int("websocket.dropped", droppedCount),
+		attribute.Int("websocket.disconnected", len(overflowed)),
+	)
 	logger.Info("Event broadcast completed",
 		zap.String("event_type", string(event.Type)),
 		zap.String("event_id", event.ID),
-		zap.Int("success_count", successCount),
-		zap.Int("fail_count", failCount),
+		zap.Int("client_count", clientCount),
+		zap.Int("queued_count", queuedCount),
+		zap.Int("dropped_count", droppedCount),
+		zap.Int("disconnected_count", len(overflowed)),
 		zap.Duration("duration", time.Since(startTime)),
 	)
 }
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -10,8 +11,11 @@ import (
 	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
 	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -33,6 +37,14 @@ func (m *MockEventPublisher) Publish(event *models.Event) error {
 	return args.Error(0)
 }
 
+func (m *MockEventPublisher) PublishBatch(events []*models.Event) []error {
+	args := m.Called(events)
+	if errs, ok := args.Get(0).([]error); ok {
+		return errs
+	}
+	return make([]error, len(events))
+}
+
 func (m *MockEventPublisher) Subscribe(eventType models.EventType, handler func(*models.Event)) error {
 	m.Called(eventType, handler)
 	m.mu.Lock()
@@ -63,6 +75,12 @@ func setupWebSocketTest() (*WebSocketHandler, *MockEventPublisher) {
 		models.EventProductCreated,
 		models.EventProductUpdated,
 		models.EventProductDeleted,
+		models.EventProductRestored,
+		models.EventStockChanged,
+		models.EventPriceChanged,
+		models.EventCategoryCreated,
+		models.EventCategoryUpdated,
+		models.EventCategoryDeleted,
 	}
 
 	for _, eventType := range eventTypes {
@@ -165,3 +183,315 @@ func TestWebSocketClientDisconnect(t *testing.T) {
 	assert.Equal(t, 0, numClients)
 	mockPublisher.AssertExpectations(t)
 }
+
+func TestWebSocketClientCount(t *testing.T) {
+	handler, _ := setupWebSocketTest()
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws1, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer ws1.Close()
+
+	ws2, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer ws2.Close()
+
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, 2, handler.ClientCount())
+}
+
+type mockSnapshotProvider struct {
+	events   []*models.Event
+	products []*interfaces.ProductSummary
+	limits   []int
+	mu       sync.Mutex
+}
+
+func (m *mockSnapshotProvider) RecentEvents(limit int) []*models.Event {
+	m.mu.Lock()
+	m.limits = append(m.limits, limit)
+	m.mu.Unlock()
+	return m.events
+}
+
+func (m *mockSnapshotProvider) RecentlyUpdatedProducts(limit int) []*interfaces.ProductSummary {
+	m.mu.Lock()
+	m.limits = append(m.limits, limit)
+	m.mu.Unlock()
+	return m.products
+}
+
+func readSnapshot(t *testing.T, ws *websocket.Conn) *SnapshotFrame {
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	_, message, err := ws.ReadMessage()
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	var frame SnapshotFrame
+	assert.NoError(t, json.Unmarshal(message, &frame))
+	return &frame
+}
+
+func TestWebSocketSnapshotOnConnect(t *testing.T) {
+	handler, _ := setupWebSocketTest()
+	provider := &mockSnapshotProvider{
+		events:   []*models.Event{{ID: "evt_1", Type: models.EventProductUpdated}},
+		products: []*interfaces.ProductSummary{{ProductID: "prod_1", Version: 2}},
+	}
+	handler.EnableSnapshots(provider, SnapshotConfig{Mode: SnapshotEvents, Limit: 10})
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	// Default configuration sends the latest events
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer ws.Close()
+
+	frame := readSnapshot(t, ws)
+	if assert.NotNil(t, frame) {
+		assert.Equal(t, "snapshot", frame.Type)
+		assert.Equal(t, SnapshotEvents, frame.Mode)
+		assert.Len(t, frame.Events, 1)
+		assert.Empty(t, frame.Products)
+	}
+
+	// Clients can ask for product summaries instead
+	ws2, _, err := websocket.DefaultDialer.Dial(url+"?snapshot=products&snapshot_limit=3", nil)
+	assert.NoError(t, err)
+	defer ws2.Close()
+
+	frame = readSnapshot(t, ws2)
+	if assert.NotNil(t, frame) {
+		assert.Equal(t, SnapshotProducts, frame.Mode)
+		assert.Equal(t, "prod_1", frame.Products[0].ProductID)
+	}
+
+	provider.mu.Lock()
+	assert.Equal(t, []int{10, 3}, provider.limits)
+	provider.mu.Unlock()
+}
+
+func TestWebSocketSnapshotBeforeLiveEvents(t *testing.T) {
+	handler, mockPublisher := setupWebSocketTest()
+	handler.EnableSnapshots(&mockSnapshotProvider{}, SnapshotConfig{Mode: SnapshotEvents, Limit: 5})
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer ws.Close()
+
+	time.Sleep(100 * time.Millisecond)
+	mockPublisher.triggerHandler(models.EventProductCreated, &models.Event{ID: "live_1", Type: models.EventProductCreated})
+
+	frame := readSnapshot(t, ws)
+	if assert.NotNil(t, frame) {
+		assert.Equal(t, "snapshot", frame.Type)
+	}
+
+	_, message, err := ws.ReadMessage()
+	assert.NoError(t, err)
+	assert.Contains(t, string(message), "live_1")
+}
+
+func TestWebSocketSnapshotDisabled(t *testing.T) {
+	handler, mockPublisher := setupWebSocketTest()
+	handler.EnableSnapshots(&mockSnapshotProvider{}, SnapshotConfig{Mode: SnapshotEvents})
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?snapshot=none", nil)
+	assert.NoError(t, err)
+	defer ws.Close()
+
+	time.Sleep(100 * time.Millisecond)
+	mockPublisher.triggerHandler(models.EventProductCreated, &models.Event{ID: "live_1", Type: models.EventProductCreated})
+
+	// The first frame is the live event, not a snapshot
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	_, message, err := ws.ReadMessage()
+	assert.NoError(t, err)
+	assert.Contains(t, string(message), "live_1")
+}
+
+func TestWebSocketShutdownSendsReconnectHint(t *testing.T) {
+	handler, _ := setupWebSocketTest()
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer ws.Close()
+
+	assert.Eventually(t, func() bool { return handler.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+
+	policy := ReconnectPolicy{After: 2 * time.Second, Spread: 10 * time.Second}
+	assert.Equal(t, 1, handler.Shutdown(policy))
+
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	_, message, err := ws.ReadMessage()
+	assert.NoError(t, err)
+	var frame ReconnectFrame
+	assert.NoError(t, json.Unmarshal(message, &frame))
+	assert.Equal(t, "reconnect", frame.Type)
+	assert.GreaterOrEqual(t, frame.ReconnectAfterMs, int64(2000))
+	assert.LessOrEqual(t, frame.ReconnectAfterMs, int64(12000))
+
+	_, _, err = ws.ReadMessage()
+	closeErr, ok := err.(*websocket.CloseError)
+	if assert.True(t, ok, "expected a close frame, got %v", err) {
+		assert.Equal(t, websocket.CloseServiceRestart, closeErr.Code)
+		assert.Contains(t, closeErr.Text, "reconnect_after_ms")
+	}
+}
+
+func TestWebSocketDrainClosesLingeringClients(t *testing.T) {
+	handler, _ := setupWebSocketTest()
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer ws.Close()
+	assert.Eventually(t, func() bool { return handler.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+
+	// The client never reads the close frame, so the connection stays open until the deadline
+	handler.Shutdown(ReconnectPolicy{After: time.Second})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, 1, handler.Drain(ctx))
+	assert.Equal(t, 0, handler.ClientCount())
+	assert.Equal(t, 0, handler.Drain(context.Background()))
+}
+
+func TestWebSocketRefusesConnectionsAfterShutdown(t *testing.T) {
+	handler, _ := setupWebSocketTest()
+	handler.Shutdown(ReconnectPolicy{After: time.Second, Spread: 4 * time.Second})
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.Error(t, err)
+	if assert.NotNil(t, resp) {
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "5", resp.Header.Get("Retry-After"))
+	}
+}
+
+func TestReconnectDelaysAreSpread(t *testing.T) {
+	policy := ReconnectPolicy{After: time.Second, Spread: 10 * time.Second}
+	delays := reconnectDelays(10, policy)
+	assert.Len(t, delays, 10)
+
+	// Every one-second slot of the window holds exactly one client
+	slots := make(map[time.Duration]int)
+	for _, delay := range delays {
+		assert.GreaterOrEqual(t, delay, policy.After)
+		assert.Less(t, delay, policy.After+policy.Spread)
+		slots[(delay-policy.After)/time.Second]++
+	}
+	assert.Len(t, slots, 10)
+
+	assert.Empty(t, reconnectDelays(0, policy))
+	assert.Equal(t, []time.Duration{time.Second}, reconnectDelays(1, ReconnectPolicy{After: time.Second}))
+}
+
+func TestWebSocketPingsDropClientsMissingPongs(t *testing.T) {
+	handler, _ := setupWebSocketTest()
+	handler.keepalive = KeepaliveConfig{PingInterval: time.Hour, MaxMissedPongs: 1}
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	silent, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer silent.Close()
+
+	// The gorilla client answers pings while it reads
+	responsive, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer responsive.Close()
+	go func() {
+		for {
+			if _, _, err := responsive.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	assert.Eventually(t, func() bool { return handler.ClientCount() == 2 }, time.Second, 10*time.Millisecond)
+	reaped := testutil.ToFloat64(metrics.WebSocketClientsReaped)
+
+	assert.Equal(t, 0, handler.pingClients())
+	assert.Eventually(t, func() bool {
+		handler.mu.RLock()
+		defer handler.mu.RUnlock()
+		for _, client := range handler.clients {
+			if client.missedPongs == 0 {
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 1, handler.pingClients())
+	assert.Equal(t, 1, handler.ClientCount())
+	assert.Equal(t, reaped+1, testutil.ToFloat64(metrics.WebSocketClientsReaped))
+
+	// The dropped connection is closed
+	silent.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err = silent.ReadMessage()
+	assert.Error(t, err)
+}
+
+func TestWebSocketKeepaliveReapsStaleClients(t *testing.T) {
+	handler, _ := setupWebSocketTest()
+	handler.EnableKeepalive(KeepaliveConfig{PingInterval: 20 * time.Millisecond, MaxMissedPongs: 2})
+	defer handler.stopKeepalives()
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer ws.Close()
+
+	assert.Eventually(t, func() bool { return handler.ClientCount() == 1 }, time.Second, 5*time.Millisecond)
+	assert.Eventually(t, func() bool { return handler.ClientCount() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestWebSocketConnectionsUpdateGauge(t *testing.T) {
+	handler, _ := setupWebSocketTest()
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	before := testutil.ToFloat64(metrics.ActiveWebSocketConnections)
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.ActiveWebSocketConnections) == before+1
+	}, time.Second, 5*time.Millisecond)
+	ws.Close()
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.ActiveWebSocketConnections) == before
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+// OverflowPolicy selects what happens to a message for a client whose send queue is full
+type OverflowPolicy string
+
+const (
+	// OverflowDisconnect closes the connection, so the client reconnects and
+	// catches up from a snapshot instead of silently missing events
+	OverflowDisconnect OverflowPolicy = "disconnect"
+	// OverflowDrop drops the message and keeps the client connected
+	OverflowDrop OverflowPolicy = "drop"
+)
+
+// DefaultSendQueueSize is the number of messages queued per client by default
+const DefaultSendQueueSize = 256
+
+// sendWriteTimeout bounds how long writing one message to a client may take
+const sendWriteTimeout = 10 * time.Second
+
+// SendQueueConfig sizes the outbound queue of each client. Every client has
+// its own queue and writer, so a slow client only delays itself; once its
+// queue is full, Overflow decides what happens to it.
+type SendQueueConfig struct {
+	Size     int
+	Overflow OverflowPolicy
+}
+
+// wsClient is the state kept per connected client
+type wsClient struct {
+	conn        *websocket.Conn
+	filter      *eventFilter
+	missedPongs int // pings sent since the last pong
+
+	send     chan []byte // closed when the client is forgotten
+	overflow OverflowPolicy
+	writeMu  sync.Mutex // serializes the writer with shutdown frames
+}
+
+// SetSendQueue configures the send queues of clients that connect from now on
+func (h *WebSocketHandler) SetSendQueue(config SendQueueConfig) {
+	if config.Size <= 0 {
+		config.Size = DefaultSendQueueSize
+	}
+	if config.Overflow != OverflowDrop {
+		config.Overflow = OverflowDisconnect
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sendQueue = config
+}
+
+// newClient creates the state of a new connection with the configured queue
+func (h *WebSocketHandler) newClient(conn *websocket.Conn) *wsClient {
+	h.mu.RLock()
+	config := h.sendQueue
+	h.mu.RUnlock()
+	return &wsClient{
+		conn:     conn,
+		filter:   newEventFilter(),
+		send:     make(chan []byte, config.Size),
+		overflow: config.Overflow,
+	}
+}
+
+// enqueue queues a message for the client without waiting and reports whether
+// it fit. Callers hold h.mu, so the queue is not closed meanwhile.
+func (c *wsClient) enqueue(message []byte) bool {
+	metrics.WebSocketQueuedMessages.Inc()
+	select {
+	case c.send <- message:
+		return true
+	default:
+		metrics.WebSocketQueuedMessages.Dec()
+		return false
+	}
+}
+
+// write writes one message to the connection
+func (c *wsClient) write(messageType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(sendWriteTimeout))
+	return c.conn.WriteMessage(messageType, data)
+}
+
+// writePump writes the queued messages of a client until its queue is closed.
+// After a failed write the client is dropped and the rest of its queue discarded.
+func (h *WebSocketHandler) writePump(client *wsClient) {
+	failed := false
+	for message := range client.send {
+		metrics.WebSocketQueuedMessages.Dec()
+		if failed {
+			continue
+		}
+		if err := client.write(websocket.TextMessage, message); err != nil {
+			log.Printf("Failed to send message to client: %v", err)
+			failed = true
+			h.removeClient(client.conn)
+			client.conn.Close()
+		}
+	}
+}
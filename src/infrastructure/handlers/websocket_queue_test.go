@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+// addStalledClient registers a client whose writer never runs, as if its
+// connection had stopped reading, and returns the client end of the connection
+func addStalledClient(t *testing.T, handler *WebSocketHandler, queueSize int, overflow OverflowPolicy) (*wsClient, *websocket.Conn) {
+	accepted := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if assert.NoError(t, err) {
+			accepted <- conn
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	remote, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	assert.NoError(t, err)
+	t.Cleanup(func() { remote.Close() })
+
+	conn := <-accepted
+	client := &wsClient{conn: conn, filter: newEventFilter(), send: make(chan []byte, queueSize), overflow: overflow}
+	handler.mu.Lock()
+	handler.clients[conn] = client
+	handler.mu.Unlock()
+	metrics.ActiveWebSocketConnections.Inc()
+	return client, remote
+}
+
+func TestWebSocketSlowClientDoesNotStallOthers(t *testing.T) {
+	handler, mockPublisher := setupWebSocketTest()
+	handler.SetSendQueue(SendQueueConfig{Size: 1, Overflow: OverflowDrop})
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	assert.NoError(t, err)
+	defer ws.Close()
+	assert.Eventually(t, func() bool { return handler.ClientCount() == 1 }, time.Second, 5*time.Millisecond)
+
+	stalled, _ := addStalledClient(t, handler, 1, OverflowDrop)
+	dropped := testutil.ToFloat64(metrics.WebSocketQueueOverflows.WithLabelValues("dropped"))
+
+	for _, id := range []string{"evt_1", "evt_2", "evt_3"} {
+		mockPublisher.triggerHandler(models.EventProductCreated, &models.Event{ID: id, Type: models.EventProductCreated})
+
+		var received models.Event
+		readFrame(t, ws, &received)
+		assert.Equal(t, id, received.ID)
+	}
+
+	// The stalled client keeps its first message and the rest are dropped
+	assert.Len(t, stalled.send, 1)
+	assert.Equal(t, dropped+2, testutil.ToFloat64(metrics.WebSocketQueueOverflows.WithLabelValues("dropped")))
+	assert.Equal(t, 2, handler.ClientCount())
+}
+
+func TestWebSocketDisconnectsClientsWithFullQueues(t *testing.T) {
+	handler, mockPublisher := setupWebSocketTest()
+	_, remote := addStalledClient(t, handler, 1, OverflowDisconnect)
+	disconnected := testutil.ToFloat64(metrics.WebSocketQueueOverflows.WithLabelValues("disconnected"))
+
+	mockPublisher.triggerHandler(models.EventProductCreated, &models.Event{ID: "evt_1", Type: models.EventProductCreated})
+	assert.Equal(t, 1, handler.ClientCount())
+	mockPublisher.triggerHandler(models.EventProductCreated, &models.Event{ID: "evt_2", Type: models.EventProductCreated})
+	assert.Equal(t, 0, handler.ClientCount())
+	assert.Equal(t, disconnected+1, testutil.ToFloat64(metrics.WebSocketQueueOverflows.WithLabelValues("disconnected")))
+
+	remote.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := remote.ReadMessage()
+	assert.Error(t, err)
+}
+
+func TestWebSocketQueueDepthGauge(t *testing.T) {
+	handler, mockPublisher := setupWebSocketTest()
+	stalled, _ := addStalledClient(t, handler, 4, OverflowDisconnect)
+	before := testutil.ToFloat64(metrics.WebSocketQueuedMessages)
+
+	mockPublisher.triggerHandler(models.EventProductCreated, &models.Event{ID: "evt_1", Type: models.EventProductCreated})
+	mockPublisher.triggerHandler(models.EventProductCreated, &models.Event{ID: "evt_2", Type: models.EventProductCreated})
+	assert.Equal(t, before+2, testutil.ToFloat64(metrics.WebSocketQueuedMessages))
+
+	// Forgetting the client closes its queue; the writer discards what is left
+	handler.removeClient(stalled.conn)
+	stalled.conn.Close()
+	handler.writePump(stalled)
+	assert.Equal(t, before, testutil.ToFloat64(metrics.WebSocketQueuedMessages))
+}
+
+func TestSetSendQueueDefaults(t *testing.T) {
+	handler, _ := setupWebSocketTest()
+	handler.SetSendQueue(SendQueueConfig{Overflow: "unknown"})
+	assert.Equal(t, SendQueueConfig{Size: DefaultSendQueueSize, Overflow: OverflowDisconnect}, handler.sendQueue)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Subscription actions clients send over /ws
+const (
+	ActionSubscribe   = "subscribe"
+	ActionUnsubscribe = "unsubscribe"
+)
+
+// SubscriptionMessage is a message a client sends to change which events it
+// receives. Each list adds to (subscribe) or removes from (unsubscribe) the
+// connection's filter.
+type SubscriptionMessage struct {
+	Action      string             `json:"action"`
+	EventTypes  []models.EventType `json:"event_types,omitempty"`
+	ProductIDs  []string           `json:"product_ids,omitempty"`
+	SKUPrefixes []string           `json:"sku_prefixes,omitempty"`
+}
+
+// SubscriptionFrame confirms a subscription message with the connection's filter after it
+type SubscriptionFrame struct {
+	Type        string             `json:"type"`
+	EventTypes  []models.EventType `json:"event_types"`
+	ProductIDs  []string           `json:"product_ids"`
+	SKUPrefixes []string           `json:"sku_prefixes"`
+}
+
+// ErrorFrame reports a client message that could not be applied
+type ErrorFrame struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// eventFilter selects the events forwarded to one connection. A connection
+// receives every event until its first subscribe message. After that an event
+// must have one of the subscribed types, if any, and belong to one of the
+// subscribed products or SKU prefixes, if any; when nothing is left
+// subscribed, nothing is forwarded.
+type eventFilter struct {
+	filtered    bool
+	eventTypes  map[models.EventType]bool
+	productIDs  map[string]bool
+	skuPrefixes map[string]bool
+}
+
+func newEventFilter() *eventFilter {
+	return &eventFilter{
+		eventTypes:  make(map[models.EventType]bool),
+		productIDs:  make(map[string]bool),
+		skuPrefixes: make(map[string]bool),
+	}
+}
+
+// parseSubscriptionMessage decodes and validates a client message
+func parseSubscriptionMessage(data []byte) (*SubscriptionMessage, error) {
+	var message SubscriptionMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, fmt.Errorf("invalid message: %v", err)
+	}
+	if message.Action != ActionSubscribe && message.Action != ActionUnsubscribe {
+		return nil, fmt.Errorf("unknown action %q, expected %s or %s", message.Action, ActionSubscribe, ActionUnsubscribe)
+	}
+	for _, eventType := range message.EventTypes {
+		if !knownEventType(eventType) {
+			return nil, fmt.Errorf("unknown event type %q", eventType)
+		}
+	}
+	return &message, nil
+}
+
+// apply adds or removes the values of a message
+func (f *eventFilter) apply(message *SubscriptionMessage) {
+	subscribe := message.Action == ActionSubscribe
+	if subscribe {
+		f.filtered = true
+	}
+	for _, eventType := range message.EventTypes {
+		setMember(f.eventTypes, eventType, subscribe)
+	}
+	for _, id := range message.ProductIDs {
+		setMember(f.productIDs, id, subscribe)
+	}
+	for _, prefix := range message.SKUPrefixes {
+		setMember(f.skuPrefixes, prefix, subscribe)
+	}
+}
+
+// matches reports whether an event is forwarded to the connection
+func (f *eventFilter) matches(event *models.Event) bool {
+	if !f.filtered {
+		return true
+	}
+	if len(f.eventTypes) == 0 && len(f.productIDs) == 0 && len(f.skuPrefixes) == 0 {
+		return false
+	}
+	if len(f.eventTypes) > 0 && !f.eventTypes[event.Type] {
+		return false
+	}
+	if len(f.productIDs) == 0 && len(f.skuPrefixes) == 0 {
+		return true
+	}
+	if f.productIDs[event.EntityID] {
+		return true
+	}
+	productEvent, ok := event.Data.(*models.ProductEvent)
+	if !ok {
+		return false
+	}
+	if f.productIDs[productEvent.ProductID] {
+		return true
+	}
+	if productEvent.Product == nil {
+		return false
+	}
+	for prefix := range f.skuPrefixes {
+		if strings.HasPrefix(productEvent.Product.SKU, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// frame describes the filter in sorted order
+func (f *eventFilter) frame() *SubscriptionFrame {
+	frame := &SubscriptionFrame{
+		Type:        "subscription",
+		EventTypes:  make([]models.EventType, 0, len(f.eventTypes)),
+		ProductIDs:  sortedKeys(f.productIDs),
+		SKUPrefixes: sortedKeys(f.skuPrefixes),
+	}
+	for eventType := range f.eventTypes {
+		frame.EventTypes = append(frame.EventTypes, eventType)
+	}
+	sort.Slice(frame.EventTypes, func(i, j int) bool { return frame.EventTypes[i] < frame.EventTypes[j] })
+	return frame
+}
+
+func knownEventType(eventType models.EventType) bool {
+	for _, known := range webSocketEventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+func setMember[K comparable](set map[K]bool, key K, member bool) {
+	if member {
+		set[key] = true
+	} else {
+		delete(set, key)
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func productEventFor(id, sku string, eventType models.EventType) *models.Event {
+	return &models.Event{
+		ID:       "evt_" + id + "_" + string(eventType),
+		Type:     eventType,
+		EntityID: id,
+		Data: &models.ProductEvent{
+			ProductID: id,
+			Product:   &models.Product{ID: id, SKU: sku},
+		},
+	}
+}
+
+func TestEventFilterMatchesEverythingUntilSubscribed(t *testing.T) {
+	filter := newEventFilter()
+	assert.True(t, filter.matches(productEventFor("prod_1", "SHOE-1", models.EventProductCreated)))
+
+	filter.apply(&SubscriptionMessage{Action: ActionUnsubscribe, ProductIDs: []string{"prod_1"}})
+	assert.True(t, filter.matches(productEventFor("prod_1", "SHOE-1", models.EventProductCreated)))
+}
+
+func TestEventFilterCombinesTypesWithProducts(t *testing.T) {
+	filter := newEventFilter()
+	filter.apply(&SubscriptionMessage{
+		Action:      ActionSubscribe,
+		EventTypes:  []models.EventType{models.EventProductUpdated},
+		ProductIDs:  []string{"prod_1"},
+		SKUPrefixes: []string{"SHOE-"},
+	})
+
+	assert.True(t, filter.matches(productEventFor("prod_1", "HAT-1", models.EventProductUpdated)))
+	assert.True(t, filter.matches(productEventFor("prod_2", "SHOE-2", models.EventProductUpdated)))
+	assert.False(t, filter.matches(productEventFor("prod_3", "HAT-3", models.EventProductUpdated)))
+	assert.False(t, filter.matches(productEventFor("prod_1", "HAT-1", models.EventProductCreated)))
+}
+
+func TestEventFilterTypesAloneMatchAnyProduct(t *testing.T) {
+	filter := newEventFilter()
+	filter.apply(&SubscriptionMessage{Action: ActionSubscribe, EventTypes: []models.EventType{models.EventProductDeleted}})
+
+	assert.True(t, filter.matches(productEventFor("prod_1", "HAT-1", models.EventProductDeleted)))
+	assert.False(t, filter.matches(productEventFor("prod_1", "HAT-1", models.EventProductUpdated)))
+}
+
+func TestEventFilterUnsubscribingEverythingMatchesNothing(t *testing.T) {
+	filter := newEventFilter()
+	filter.apply(&SubscriptionMessage{Action: ActionSubscribe, SKUPrefixes: []string{"SHOE-"}})
+	filter.apply(&SubscriptionMessage{Action: ActionUnsubscribe, SKUPrefixes: []string{"SHOE-"}})
+
+	assert.False(t, filter.matches(productEventFor("prod_1", "SHOE-1", models.EventProductCreated)))
+	assert.Equal(t, &SubscriptionFrame{
+		Type:        "subscription",
+		EventTypes:  []models.EventType{},
+		ProductIDs:  []string{},
+		SKUPrefixes: []string{},
+	}, filter.frame())
+}
+
+func TestParseSubscriptionMessage(t *testing.T) {
+	message, err := parseSubscriptionMessage([]byte(`{"action": "subscribe", "event_types": ["product.created"], "product_ids": ["prod_1"]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &SubscriptionMessage{
+		Action:     ActionSubscribe,
+		EventTypes: []models.EventType{models.EventProductCreated},
+		ProductIDs: []string{"prod_1"},
+	}, message)
+
+	_, err = parseSubscriptionMessage([]byte(`{"action": "listen"}`))
+	assert.ErrorContains(t, err, "unknown action")
+	_, err = parseSubscriptionMessage([]byte(`{"action": "subscribe", "event_types": ["order.created"]}`))
+	assert.ErrorContains(t, err, "unknown event type")
+	_, err = parseSubscriptionMessage([]byte(`not json`))
+	assert.ErrorContains(t, err, "invalid message")
+}
+
+func readFrame(t *testing.T, ws *websocket.Conn, frame interface{}) {
+	ws.SetReadDeadline(time.Now().Add(time.Second))
+	_, message, err := ws.ReadMessage()
+	if assert.NoError(t, err) {
+		assert.NoError(t, json.Unmarshal(message, frame))
+	}
+}
+
+func TestWebSocketForwardsOnlySubscribedEvents(t *testing.T) {
+	handler, mockPublisher := setupWebSocketTest()
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	filtered, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer filtered.Close()
+	everything, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer everything.Close()
+
+	assert.NoError(t, filtered.WriteJSON(&SubscriptionMessage{Action: ActionSubscribe, SKUPrefixes: []string{"SHOE-"}}))
+	var subscription SubscriptionFrame
+	readFrame(t, filtered, &subscription)
+	assert.Equal(t, "subscription", subscription.Type)
+	assert.Equal(t, []string{"SHOE-"}, subscription.SKUPrefixes)
+
+	hat := productEventFor("prod_1", "HAT-1", models.EventProductCreated)
+	shoe := productEventFor("prod_2", "SHOE-2", models.EventProductCreated)
+	mockPublisher.triggerHandler(models.EventProductCreated, hat)
+	mockPublisher.triggerHandler(models.EventProductCreated, shoe)
+
+	var received models.Event
+	readFrame(t, filtered, &received)
+	assert.Equal(t, shoe.ID, received.ID)
+
+	readFrame(t, everything, &received)
+	assert.Equal(t, hat.ID, received.ID)
+	readFrame(t, everything, &received)
+	assert.Equal(t, shoe.ID, received.ID)
+}
+
+func TestWebSocketRepliesWithErrorToInvalidMessages(t *testing.T) {
+	handler, _ := setupWebSocketTest()
+
+	server := httptest.NewServer(http.HandlerFunc(handler.HandleWebSocket))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	ws, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	defer ws.Close()
+
+	assert.NoError(t, ws.WriteMessage(websocket.TextMessage, []byte(`{"action": "listen"}`)))
+	var frame ErrorFrame
+	readFrame(t, ws, &frame)
+	assert.Equal(t, "error", frame.Type)
+	assert.Contains(t, frame.Error, "unknown action")
+
+	// The connection stays open and keeps its filter
+	assert.Equal(t, 1, handler.ClientCount())
+}
@@ -0,0 +1,210 @@
+package imports
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Column types that records can declare for normalization
+const (
+	ColumnNumber = "number"
+	ColumnDate   = "date"
+)
+
+// canonicalDateLayout is the format dates are normalized to
+const canonicalDateLayout = "2006-01-02"
+
+// Locale describes how numbers and dates are written in an import file
+type Locale struct {
+	Name        string
+	Decimal     string   // Decimal separator
+	Groups      []string // Accepted thousands separators
+	DateLayouts []string // Accepted date layouts, tried in order
+}
+
+// lenientLocale is used when a file has no locale: "." and "," are both read as
+// the decimal separator and thousands separators are not accepted
+var lenientLocale *Locale
+
+// locales are the supported per-file locales
+var locales = map[string]*Locale{
+	"en-US": {Name: "en-US", Decimal: ".", Groups: []string{","}, DateLayouts: []string{"01/02/2006", "1/2/2006", canonicalDateLayout}},
+	"en-GB": {Name: "en-GB", Decimal: ".", Groups: []string{","}, DateLayouts: []string{"02/01/2006", "2/1/2006", canonicalDateLayout}},
+	"sv-SE": {Name: "sv-SE", Decimal: ",", Groups: []string{" ", "\u00a0", "\u202f"}, DateLayouts: []string{canonicalDateLayout, "2006-1-2"}},
+	"de-DE": {Name: "de-DE", Decimal: ",", Groups: []string{".", " "}, DateLayouts: []string{"02.01.2006", "2.1.2006", canonicalDateLayout}},
+	"fr-FR": {Name: "fr-FR", Decimal: ",", Groups: []string{" ", "\u00a0", "\u202f"}, DateLayouts: []string{"02/01/2006", "2/1/2006", canonicalDateLayout}},
+	"nl-NL": {Name: "nl-NL", Decimal: ",", Groups: []string{"."}, DateLayouts: []string{"02-01-2006", "2-1-2006", canonicalDateLayout}},
+}
+
+// LookupLocale returns a supported locale such as "sv-SE". An empty name keeps
+// the lenient default.
+func LookupLocale(name string) (*Locale, error) {
+	if name == "" {
+		return lenientLocale, nil
+	}
+	if locale, exists := locales[name]; exists {
+		return locale, nil
+	}
+	names := make([]string, 0, len(locales))
+	for known := range locales {
+		names = append(names, known)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("%w: unknown locale %q, expected one of %s", models.ErrInvalidRequest, name, strings.Join(names, ", "))
+}
+
+// ParseNumber parses a number written in the locale. Currency symbols and codes
+// around the number, e.g. "kr", "€" or "SEK", are ignored. Thousands separators
+// must group three digits so a misplaced decimal separator is not read as one.
+func (l *Locale) ParseNumber(value string) (float64, error) {
+	s := trimCurrency(value)
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = trimCurrency(strings.TrimPrefix(s, "-"))
+	}
+
+	var f float64
+	var err error
+	if l == nil {
+		f, err = strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	} else {
+		f, err = l.parseGrouped(s)
+	}
+	if err != nil || s == "" {
+		if l == nil {
+			return 0, fmt.Errorf("%q is not a number", value)
+		}
+		return 0, fmt.Errorf("%q is not a number in locale %s", value, l.Name)
+	}
+	if negative {
+		f = -f
+	}
+	return f, nil
+}
+
+// parseGrouped parses digits with the locale's separators
+func (l *Locale) parseGrouped(s string) (float64, error) {
+	integer, fraction, hasFraction := strings.Cut(s, l.Decimal)
+	if hasFraction && (fraction == "" || !isDigits(fraction)) {
+		return 0, fmt.Errorf("invalid fraction")
+	}
+
+	groups := []string{integer}
+	for _, separator := range l.Groups {
+		if strings.Contains(integer, separator) {
+			groups = strings.Split(integer, separator)
+			break
+		}
+	}
+	for i, group := range groups {
+		if !isDigits(group) || (len(groups) > 1 && (len(group) > 3 || (i > 0 && len(group) != 3))) {
+			return 0, fmt.Errorf("invalid digit grouping")
+		}
+	}
+
+	canonical := strings.Join(groups, "")
+	if hasFraction {
+		canonical += "." + fraction
+	}
+	return strconv.ParseFloat(canonical, 64)
+}
+
+// ParseDate parses a date written in one of the locale's layouts
+func (l *Locale) ParseDate(value string) (time.Time, error) {
+	layouts := []string{canonicalDateLayout}
+	name := "ISO 8601"
+	if l != nil {
+		layouts = l.DateLayouts
+		name = "locale " + l.Name
+	}
+	s := strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date in %s", value, name)
+}
+
+// NormalizeRecord rewrites the declared number and date columns of a record into
+// canonical values ("1299.5", "2024-03-31") so mapping expressions never see
+// localized formats. Empty cells are left empty. Every cell that cannot be
+// parsed is reported; the record is only usable when none are.
+func (l *Locale) NormalizeRecord(record map[string]string, columns map[string]string) (map[string]string, []interfaces.ImportCellError) {
+	if len(columns) == 0 {
+		return record, nil
+	}
+
+	normalized := make(map[string]string, len(record))
+	for column, value := range record {
+		normalized[column] = value
+	}
+
+	names := make([]string, 0, len(columns))
+	for column := range columns {
+		names = append(names, column)
+	}
+	sort.Strings(names)
+
+	var cellErrors []interfaces.ImportCellError
+	for _, column := range names {
+		value := strings.TrimSpace(record[column])
+		if value == "" {
+			continue
+		}
+		switch columns[column] {
+		case ColumnNumber:
+			f, err := l.ParseNumber(value)
+			if err != nil {
+				cellErrors = append(cellErrors, interfaces.ImportCellError{Column: column, Value: record[column], Error: err.Error()})
+				continue
+			}
+			normalized[column] = strconv.FormatFloat(f, 'f', -1, 64)
+		case ColumnDate:
+			t, err := l.ParseDate(value)
+			if err != nil {
+				cellErrors = append(cellErrors, interfaces.ImportCellError{Column: column, Value: record[column], Error: err.Error()})
+				continue
+			}
+			normalized[column] = t.Format(canonicalDateLayout)
+		}
+	}
+	return normalized, cellErrors
+}
+
+// ValidateColumns checks that every declared column type is known
+func ValidateColumns(columns map[string]string) error {
+	for column, columnType := range columns {
+		if columnType != ColumnNumber && columnType != ColumnDate {
+			return fmt.Errorf("%w: column %s has unknown type %q, expected number or date", models.ErrInvalidRequest, column, columnType)
+		}
+	}
+	return nil
+}
+
+// trimCurrency strips currency symbols, codes and whitespace around a number
+func trimCurrency(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsLetter(r) || unicode.Is(unicode.Sc, r)
+	})
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package imports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustLocale(t *testing.T, name string) *Locale {
+	locale, err := LookupLocale(name)
+	assert.NoError(t, err)
+	return locale
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		locale string
+		value  string
+		want   float64
+	}{
+		{"de-DE", "1.299,50", 1299.5},
+		{"de-DE", "1.299,50 €", 1299.5},
+		{"de-DE", "12,5", 12.5},
+		{"sv-SE", "1 299,50 kr", 1299.5},
+		{"sv-SE", "1 299", 1299},
+		{"sv-SE", "-49,90 SEK", -49.9},
+		{"fr-FR", "2 500,00 €", 2500},
+		{"en-US", "$1,299.50", 1299.5},
+		{"en-US", "-$5", -5},
+		{"en-GB", "£12.00", 12},
+		{"", "12,5", 12.5},
+		{"", "12.5", 12.5},
+	}
+	for _, tt := range tests {
+		got, err := mustLocale(t, tt.locale).ParseNumber(tt.value)
+		if assert.NoError(t, err, "%s %q", tt.locale, tt.value) {
+			assert.Equal(t, tt.want, got, "%s %q", tt.locale, tt.value)
+		}
+	}
+}
+
+func TestParseNumberRejectsAmbiguousValues(t *testing.T) {
+	tests := []struct {
+		locale string
+		value  string
+	}{
+		{"en-US", "12,5"},     // a decimal comma, not a thousands separator
+		{"de-DE", "1,299.50"}, // written in another locale
+		{"de-DE", "12.34"},
+		{"sv-SE", "kr"},
+		{"", "1 299"},
+		{"", "n/a"},
+	}
+	for _, tt := range tests {
+		_, err := mustLocale(t, tt.locale).ParseNumber(tt.value)
+		assert.Error(t, err, "%s %q", tt.locale, tt.value)
+	}
+
+	_, err := mustLocale(t, "en-US").ParseNumber("12,5")
+	assert.EqualError(t, err, `"12,5" is not a number in locale en-US`)
+}
+
+func TestParseDate(t *testing.T) {
+	want := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	tests := map[string]string{
+		"en-GB": "31/03/2024",
+		"en-US": "03/31/2024",
+		"de-DE": "31.03.2024",
+		"fr-FR": "31/03/2024",
+		"nl-NL": "31-03-2024",
+		"sv-SE": "2024-03-31",
+		"":      "2024-03-31",
+	}
+	for locale, value := range tests {
+		got, err := mustLocale(t, locale).ParseDate(value)
+		if assert.NoError(t, err, locale) {
+			assert.Equal(t, want, got, locale)
+		}
+	}
+
+	_, err := mustLocale(t, "en-US").ParseDate("31/03/2024")
+	assert.EqualError(t, err, `"31/03/2024" is not a date in locale en-US`)
+}
+
+func TestLookupLocaleUnknown(t *testing.T) {
+	_, err := LookupLocale("xx-XX")
+	assert.ErrorIs(t, err, models.ErrInvalidRequest)
+	assert.Contains(t, err.Error(), "sv-SE")
+}
+
+func TestNormalizeRecord(t *testing.T) {
+	locale := mustLocale(t, "de-DE")
+	record := map[string]string{"sku": "A-1", "price": "1.299,50 €", "stock": "", "available": "31.03.2024"}
+	columns := map[string]string{"price": ColumnNumber, "stock": ColumnNumber, "available": ColumnDate}
+
+	normalized, cellErrors := locale.NormalizeRecord(record, columns)
+	assert.Empty(t, cellErrors)
+	assert.Equal(t, map[string]string{"sku": "A-1", "price": "1299.5", "stock": "", "available": "2024-03-31"}, normalized)
+	assert.Equal(t, "1.299,50 €", record["price"], "the input record is not modified")
+}
+
+func TestNormalizeRecordReportsEveryCell(t *testing.T) {
+	locale := mustLocale(t, "en-GB")
+	record := map[string]string{"price": "12,5", "available": "2024/31/03"}
+	columns := map[string]string{"price": ColumnNumber, "available": ColumnDate}
+
+	_, cellErrors := locale.NormalizeRecord(record, columns)
+	if assert.Len(t, cellErrors, 2) {
+		assert.Equal(t, "available", cellErrors[0].Column)
+		assert.Equal(t, "2024/31/03", cellErrors[0].Value)
+		assert.Equal(t, "price", cellErrors[1].Column)
+		assert.Contains(t, cellErrors[1].Error, "en-GB")
+	}
+}
+
+func TestValidateColumns(t *testing.T) {
+	assert.NoError(t, ValidateColumns(map[string]string{"price": "number", "date": "date"}))
+	assert.ErrorIs(t, ValidateColumns(map[string]string{"price": "money"}), models.ErrInvalidRequest)
+}
@@ -0,0 +1,244 @@
+package imports
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Mapping target fields. Prices, market metadata and stock take a suffix, e.g.
+// "prices.SEK", "metadata.SE.title" or "stock.wh1". Stock quantities belong to
+// the variant whose SKU is the record's sku.
+const (
+	FieldSKU         = "sku"
+	FieldBaseTitle   = "base_title"
+	FieldDescription = "description"
+	pricePrefix      = "prices."
+	metadataPrefix   = "metadata."
+	stockPrefix      = "stock."
+)
+
+// transformFuncs are the helpers available inside mapping expressions
+var transformFuncs = template.FuncMap{
+	"upper":   strings.ToUpper,
+	"lower":   strings.ToLower,
+	"trim":    strings.TrimSpace,
+	"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"default": func(fallback, value string) string {
+		if strings.TrimSpace(value) == "" {
+			return fallback
+		}
+		return value
+	},
+	"join": func(sep string, values ...string) string {
+		parts := make([]string, 0, len(values))
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				parts = append(parts, v)
+			}
+		}
+		return strings.Join(parts, sep)
+	},
+	"mul": func(a, b interface{}) (float64, error) {
+		return binaryOp(a, b, func(x, y float64) float64 { return x * y })
+	},
+	"div": divide,
+	"add": func(a, b interface{}) (float64, error) {
+		return binaryOp(a, b, func(x, y float64) float64 { return x + y })
+	},
+	"round": func(places int, v interface{}) (float64, error) { return round(places, v) },
+}
+
+// Mapping is a compiled set of field expressions used to turn import records into products
+type Mapping struct {
+	fields []string
+	exprs  map[string]*template.Template
+}
+
+// CompileMapping parses the expression for each target field. Expressions use
+// Go template syntax with the record columns as fields, e.g. "{{upper .sku}}".
+func CompileMapping(fields map[string]string) (*Mapping, error) {
+	m := &Mapping{exprs: make(map[string]*template.Template, len(fields))}
+
+	for field, expr := range fields {
+		if err := validateTarget(field); err != nil {
+			return nil, fmt.Errorf("%w: %v", models.ErrInvalidMapping, err)
+		}
+		tmpl, err := template.New(field).Funcs(transformFuncs).Option("missingkey=zero").Parse(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s: %v", models.ErrInvalidMapping, field, err)
+		}
+		m.fields = append(m.fields, field)
+		m.exprs[field] = tmpl
+	}
+	sort.Strings(m.fields)
+
+	return m, nil
+}
+
+// ColumnMapping maps every column named after a target field, such as
+// "base_title" or "prices.SEK", to its value unchanged. It lets spreadsheets
+// whose headers already use the field names be imported without a mapping.
+func ColumnMapping(columns []string) map[string]string {
+	fields := make(map[string]string)
+	for _, column := range columns {
+		if validateTarget(column) == nil {
+			fields[column] = fmt.Sprintf("{{index . %q}}", column)
+		}
+	}
+	return fields
+}
+
+// Apply evaluates the mapping against a record and builds the resulting product
+func (m *Mapping) Apply(record map[string]string) (*models.Product, error) {
+	product := &models.Product{}
+
+	for _, field := range m.fields {
+		var out strings.Builder
+		if err := m.exprs[field].Execute(&out, record); err != nil {
+			return nil, fmt.Errorf("field %s: %v", field, err)
+		}
+		if err := setField(product, field, strings.TrimSpace(out.String())); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range product.Variants {
+		product.Variants[i].SKU = product.SKU
+	}
+	return product, nil
+}
+
+// validateTarget checks that a mapping target is a known product field
+func validateTarget(field string) error {
+	switch {
+	case field == FieldSKU, field == FieldBaseTitle, field == FieldDescription:
+		return nil
+	case strings.HasPrefix(field, pricePrefix):
+		if len(strings.TrimPrefix(field, pricePrefix)) != 3 {
+			return fmt.Errorf("field %s: currency must be a 3 letter code", field)
+		}
+		return nil
+	case strings.HasPrefix(field, metadataPrefix):
+		parts := strings.Split(strings.TrimPrefix(field, metadataPrefix), ".")
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("field %s: expected metadata.<market>.<title|description|keywords>", field)
+		}
+		switch parts[1] {
+		case "title", "description", "keywords":
+			return nil
+		}
+		return fmt.Errorf("field %s: unknown metadata attribute %s", field, parts[1])
+	case strings.HasPrefix(field, stockPrefix):
+		if strings.TrimPrefix(field, stockPrefix) == "" {
+			return fmt.Errorf("field %s: expected stock.<location>", field)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown target field %s", field)
+}
+
+// setField assigns an evaluated value to the product. Empty values are skipped.
+func setField(product *models.Product, field, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	switch {
+	case field == FieldSKU:
+		product.SKU = value
+	case field == FieldBaseTitle:
+		product.BaseTitle = value
+	case field == FieldDescription:
+		product.Description = value
+	case strings.HasPrefix(field, pricePrefix):
+		amount, err := toNumber(value)
+		if err != nil {
+			return fmt.Errorf("field %s: %v", field, err)
+		}
+		product.Prices = append(product.Prices, models.Price{
+			Currency: strings.ToUpper(strings.TrimPrefix(field, pricePrefix)),
+			Amount:   amount,
+		})
+	case strings.HasPrefix(field, metadataPrefix):
+		parts := strings.Split(strings.TrimPrefix(field, metadataPrefix), ".")
+		metadata := product.MetadataForMarket(parts[0])
+		if metadata == nil {
+			product.Metadata = append(product.Metadata, models.MarketMetadata{Market: strings.ToUpper(parts[0])})
+			metadata = &product.Metadata[len(product.Metadata)-1]
+		}
+		switch parts[1] {
+		case "title":
+			metadata.Title = value
+		case "description":
+			metadata.Description = value
+		case "keywords":
+			metadata.Keywords = value
+		}
+	case strings.HasPrefix(field, stockPrefix):
+		quantity, err := toNumber(value)
+		if err != nil {
+			return fmt.Errorf("field %s: %v", field, err)
+		}
+		if quantity != math.Trunc(quantity) {
+			return fmt.Errorf("field %s: quantity must be a whole number", field)
+		}
+		if len(product.Variants) == 0 {
+			product.Variants = []models.Variant{{}}
+		}
+		product.Variants[0].Stock = append(product.Variants[0].Stock, models.Stock{
+			LocationID: strings.TrimPrefix(field, stockPrefix),
+			Quantity:   int(quantity),
+		})
+	}
+	return nil
+}
+
+// toNumber converts template arguments (record strings or numbers) to float64
+func toNumber(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int:
+		return float64(n), nil
+	case string:
+		return lenientLocale.ParseNumber(n)
+	}
+	return 0, fmt.Errorf("unsupported number type %T", v)
+}
+
+func binaryOp(a, b interface{}, op func(x, y float64) float64) (float64, error) {
+	x, err := toNumber(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := toNumber(b)
+	if err != nil {
+		return 0, err
+	}
+	return op(x, y), nil
+}
+
+func divide(a, b interface{}) (float64, error) {
+	y, err := toNumber(b)
+	if err != nil {
+		return 0, err
+	}
+	if y == 0 {
+		return 0, fmt.Errorf("division by zero")
+	}
+	return binaryOp(a, y, func(x, y float64) float64 { return x / y })
+}
+
+func round(places int, v interface{}) (float64, error) {
+	x, err := toNumber(v)
+	if err != nil {
+		return 0, err
+	}
+	factor := math.Pow(10, float64(places))
+	return math.Round(x*factor) / factor, nil
+}
@@ -0,0 +1,111 @@
+package imports
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestMappingApply(t *testing.T) {
+	mapping, err := CompileMapping(map[string]string{
+		"sku":                  "{{upper .sku}}",
+		"base_title":           `{{join " " .brand .name}}`,
+		"prices.SEK":           "{{round 2 (mul .price_eur 11.5)}}",
+		"prices.EUR":           "{{.price_eur}}",
+		"metadata.SE.title":    `{{default .name .name_sv}}`,
+		"metadata.SE.keywords": `{{replace ";" "," .tags}}`,
+		"description":          "{{.missing}}",
+	})
+	assert.NoError(t, err)
+
+	product, err := mapping.Apply(map[string]string{
+		"sku":       "shirt-1",
+		"brand":     "Acme",
+		"name":      "Shirt",
+		"price_eur": "29,90",
+		"tags":      "cotton;blue",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "SHIRT-1", product.SKU)
+	assert.Equal(t, "Acme Shirt", product.BaseTitle)
+	assert.Empty(t, product.Description)
+	assert.Equal(t, []models.Price{{Currency: "EUR", Amount: 29.9}, {Currency: "SEK", Amount: 343.85}}, product.Prices)
+	assert.Equal(t, []models.MarketMetadata{{Market: "SE", Title: "Shirt", Keywords: "cotton,blue"}}, product.Metadata)
+}
+
+func TestMappingApplyStock(t *testing.T) {
+	mapping, err := CompileMapping(map[string]string{
+		"sku":       "{{.article}}",
+		"stock.wh1": "{{.qty_main}}",
+		"stock.wh2": "{{default \"0\" .qty_outlet}}",
+	})
+	assert.NoError(t, err)
+
+	product, err := mapping.Apply(map[string]string{"article": "SHIRT-1-S", "qty_main": "12"})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Variant{{
+		SKU:   "SHIRT-1-S",
+		Stock: []models.Stock{{LocationID: "wh1", Quantity: 12}, {LocationID: "wh2", Quantity: 0}},
+	}}, product.Variants)
+
+	_, err = mapping.Apply(map[string]string{"article": "SHIRT-1-S", "qty_main": "1.5"})
+	assert.Error(t, err)
+}
+
+func TestMappingApplyErrors(t *testing.T) {
+	mapping, err := CompileMapping(map[string]string{"prices.SEK": "{{.price}}"})
+	assert.NoError(t, err)
+
+	_, err = mapping.Apply(map[string]string{"price": "cheap"})
+	assert.Error(t, err)
+
+	mapping, err = CompileMapping(map[string]string{"prices.SEK": "{{div .price .units}}"})
+	assert.NoError(t, err)
+
+	_, err = mapping.Apply(map[string]string{"price": "10", "units": "0"})
+	assert.Error(t, err)
+}
+
+func TestCompileMappingInvalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"unknown field":       {"color": "{{.color}}"},
+		"bad currency":        {"prices.KRONA": "{{.price}}"},
+		"bad metadata":        {"metadata.SE": "{{.title}}"},
+		"unknown metadata":    {"metadata.SE.slug": "{{.slug}}"},
+		"missing location":    {"stock.": "{{.qty}}"},
+		"template error":      {"sku": "{{upper .sku"},
+		"unknown template fn": {"sku": "{{shout .sku}}"},
+	}
+
+	for name, fields := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := CompileMapping(fields)
+			assert.True(t, errors.Is(err, models.ErrInvalidMapping))
+		})
+	}
+}
+
+func TestColumnMapping(t *testing.T) {
+	fields := ColumnMapping([]string{"sku", "base_title", "prices.SEK", "metadata.SE.title", "stock.wh1", "supplier_ref"})
+	assert.NotContains(t, fields, "supplier_ref")
+
+	mapping, err := CompileMapping(fields)
+	assert.NoError(t, err)
+	product, err := mapping.Apply(map[string]string{
+		"sku":               "SHIRT-1",
+		"base_title":        "Shirt",
+		"prices.SEK":        "299",
+		"metadata.SE.title": "Skjorta",
+		"stock.wh1":         "4",
+		"supplier_ref":      "ACME-1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Shirt", product.BaseTitle)
+	assert.Equal(t, []models.Price{{Currency: "SEK", Amount: 299}}, product.Prices)
+	assert.Equal(t, "Skjorta", product.Metadata[0].Title)
+	assert.Equal(t, 4, product.Variants[0].Stock[0].Quantity)
+}
@@ -0,0 +1,94 @@
+package imports
+
+import (
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MergeUpdate applies the fields of a mapped update record to an existing
+// product and returns the result, leaving existing untouched. Mapped prices
+// replace the price in the same currency, metadata is merged per market and
+// stock quantities are set per location on the variant whose SKU matches the
+// record. A record carrying the product SKU may set stock only if the
+// product has exactly one variant.
+func MergeUpdate(existing, update *models.Product) (*models.Product, error) {
+	merged := existing.Clone()
+
+	if update.BaseTitle != "" {
+		merged.BaseTitle = update.BaseTitle
+	}
+	if update.Description != "" {
+		merged.Description = update.Description
+	}
+
+	for _, price := range update.Prices {
+		replaced := false
+		for i := range merged.Prices {
+			if merged.Prices[i].Currency == price.Currency {
+				merged.Prices[i].Amount = price.Amount
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Prices = append(merged.Prices, price)
+		}
+	}
+
+	for _, metadata := range update.Metadata {
+		target := merged.MetadataForMarket(metadata.Market)
+		if target == nil {
+			merged.Metadata = append(merged.Metadata, metadata)
+			continue
+		}
+		if metadata.Title != "" {
+			target.Title = metadata.Title
+		}
+		if metadata.Description != "" {
+			target.Description = metadata.Description
+		}
+		if metadata.Keywords != "" {
+			target.Keywords = metadata.Keywords
+		}
+	}
+
+	for _, variantUpdate := range update.Variants {
+		if len(variantUpdate.Stock) == 0 {
+			continue
+		}
+		variant := variantForSKU(merged, variantUpdate.SKU)
+		if variant == nil {
+			return nil, fmt.Errorf("%w: no single variant with SKU %s", models.ErrVariantNotFound, variantUpdate.SKU)
+		}
+		for _, stock := range variantUpdate.Stock {
+			setStock(variant, stock)
+		}
+	}
+
+	return merged, nil
+}
+
+// variantForSKU finds the variant a record's SKU refers to
+func variantForSKU(product *models.Product, sku string) *models.Variant {
+	for i := range product.Variants {
+		if product.Variants[i].SKU == sku {
+			return &product.Variants[i]
+		}
+	}
+	if product.SKU == sku && len(product.Variants) == 1 {
+		return &product.Variants[0]
+	}
+	return nil
+}
+
+// setStock sets the quantity at a location, keeping its backorder setting
+func setStock(variant *models.Variant, stock models.Stock) {
+	for i := range variant.Stock {
+		if variant.Stock[i].LocationID == stock.LocationID {
+			variant.Stock[i].Quantity = stock.Quantity
+			return
+		}
+	}
+	variant.Stock = append(variant.Stock, stock)
+}
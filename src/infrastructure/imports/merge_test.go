@@ -0,0 +1,68 @@
+package imports
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func createMergeProduct() *models.Product {
+	return &models.Product{
+		ID:        "prod_1",
+		SKU:       "SHIRT-1",
+		BaseTitle: "Shirt",
+		Prices:    []models.Price{{Currency: "SEK", Amount: 299}},
+		Metadata:  []models.MarketMetadata{{Market: "SE", Title: "Skjorta", Description: "Bomull"}},
+		Variants: []models.Variant{
+			{ID: "v1", SKU: "SHIRT-1-S", Stock: []models.Stock{{LocationID: "wh1", Quantity: 2, Backorder: true}}},
+			{ID: "v2", SKU: "SHIRT-1-M", Stock: []models.Stock{{LocationID: "wh1", Quantity: 4}}},
+		},
+		Version: 3,
+	}
+}
+
+func TestMergeUpdate(t *testing.T) {
+	existing := createMergeProduct()
+	update := &models.Product{
+		SKU:      "SHIRT-1-M",
+		Prices:   []models.Price{{Currency: "SEK", Amount: 249}, {Currency: "EUR", Amount: 22}},
+		Metadata: []models.MarketMetadata{{Market: "SE", Title: "Ny skjorta"}},
+		Variants: []models.Variant{{SKU: "SHIRT-1-M", Stock: []models.Stock{{LocationID: "wh1", Quantity: 9}, {LocationID: "wh2", Quantity: 1}}}},
+	}
+
+	merged, err := MergeUpdate(existing, update)
+	assert.NoError(t, err)
+	assert.Equal(t, "Shirt", merged.BaseTitle)
+	assert.Equal(t, []models.Price{{Currency: "SEK", Amount: 249}, {Currency: "EUR", Amount: 22}}, merged.Prices)
+	assert.Equal(t, []models.MarketMetadata{{Market: "SE", Title: "Ny skjorta", Description: "Bomull"}}, merged.Metadata)
+	assert.Equal(t, []models.Stock{{LocationID: "wh1", Quantity: 9}, {LocationID: "wh2", Quantity: 1}}, merged.Variants[1].Stock)
+	assert.Equal(t, int64(3), merged.Version)
+
+	// The existing product is left untouched
+	assert.Equal(t, 299.0, existing.Prices[0].Amount)
+	assert.Equal(t, 4, existing.Variants[1].Stock[0].Quantity)
+}
+
+func TestMergeUpdateKeepsBackorder(t *testing.T) {
+	update := &models.Product{Variants: []models.Variant{{SKU: "SHIRT-1-S", Stock: []models.Stock{{LocationID: "wh1", Quantity: 0}}}}}
+
+	merged, err := MergeUpdate(createMergeProduct(), update)
+	assert.NoError(t, err)
+	assert.Equal(t, models.Stock{LocationID: "wh1", Quantity: 0, Backorder: true}, merged.Variants[0].Stock[0])
+}
+
+func TestMergeUpdateProductSKUNeedsSingleVariant(t *testing.T) {
+	update := &models.Product{Variants: []models.Variant{{SKU: "SHIRT-1", Stock: []models.Stock{{LocationID: "wh1", Quantity: 5}}}}}
+
+	_, err := MergeUpdate(createMergeProduct(), update)
+	assert.True(t, errors.Is(err, models.ErrVariantNotFound))
+
+	single := createMergeProduct()
+	single.Variants = single.Variants[:1]
+	merged, err := MergeUpdate(single, update)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, merged.Variants[0].Stock[0].Quantity)
+}
@@ -0,0 +1,157 @@
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseCSV reads a CSV file with a header row into import records keyed by
+// column name. The delimiter is detected from the header, since suppliers
+// deliver both comma and semicolon separated files.
+func ParseCSV(r io.Reader) ([]map[string]string, error) {
+	reader, err := NewCSVReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]map[string]string, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CSV: %v", err)
+		}
+		records = append(records, record)
+	}
+}
+
+// RowError reports a CSV row that could not be parsed. Reading can go on
+// with the rows after it.
+type RowError struct {
+	Row int // 1 for the first row after the header
+	Err error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Row, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// CSVReader reads the records of a CSV file with a header row one at a time,
+// so large files are never held in memory at once
+type CSVReader struct {
+	reader  *csv.Reader
+	columns []string
+	row     int
+}
+
+// NewCSVReader reads the header of a CSV file, detecting its delimiter like ParseCSV
+func NewCSVReader(r io.Reader) (*CSVReader, error) {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("failed to read CSV: %v", err)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = detectDelimiter(header)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1 // Supplier files often drop trailing empty columns
+	reader.ReuseRecord = true
+
+	columns, err := reader.Read()
+	if err == io.EOF {
+		return &CSVReader{reader: reader}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV header: %v", err)
+	}
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))
+	}
+	return &CSVReader{reader: reader, columns: names}, nil
+}
+
+// Columns returns the column names of the header row
+func (c *CSVReader) Columns() []string {
+	return c.columns
+}
+
+// Read returns the next record, or io.EOF after the last one. Rows that cannot
+// be parsed are returned as a *RowError; other errors end the file.
+func (c *CSVReader) Read() (map[string]string, error) {
+	if c.columns == nil {
+		return nil, io.EOF
+	}
+	row, err := c.reader.Read()
+	if err == io.EOF {
+		return nil, err
+	}
+	c.row++
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return nil, &RowError{Row: c.row, Err: parseErr.Err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	record := make(map[string]string, len(c.columns))
+	for i, column := range c.columns {
+		if i < len(row) {
+			record[column] = row[i]
+		}
+	}
+	return record, nil
+}
+
+// detectDelimiter picks the most frequent candidate delimiter in the first line
+func detectDelimiter(data []byte) rune {
+	line := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line = data[:i]
+	}
+
+	best, bestCount := ',', 0
+	for _, candidate := range []rune{',', ';', '\t', '|'} {
+		if count := bytes.Count(line, []byte(string(candidate))); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
+
+// ParseJSON reads a JSON array of flat objects into import records.
+// Non-string values are formatted as text so mappings can treat every column alike.
+func ParseJSON(r io.Reader) ([]map[string]string, error) {
+	var rows []map[string]interface{}
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	if err := decoder.Decode(&rows); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %v", err)
+	}
+
+	records := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		record := make(map[string]string, len(row))
+		for key, value := range row {
+			if value != nil {
+				record[key] = fmt.Sprint(value)
+			}
+		}
+		records[i] = record
+	}
+	return records, nil
+}
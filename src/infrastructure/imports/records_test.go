@@ -0,0 +1,71 @@
+package imports
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCSV(t *testing.T) {
+	records, err := ParseCSV(strings.NewReader("sku,price,qty\nSHIRT-1,\"29,90\",4\nSHIRT-2,19.90\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"sku": "SHIRT-1", "price": "29,90", "qty": "4"},
+		{"sku": "SHIRT-2", "price": "19.90"},
+	}, records)
+}
+
+func TestParseCSVDetectsSemicolonAndBOM(t *testing.T) {
+	records, err := ParseCSV(strings.NewReader("\ufeffsku; price\nSHIRT-1;29,90\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"sku": "SHIRT-1", "price": "29,90"}}, records)
+}
+
+func TestParseCSVEmpty(t *testing.T) {
+	records, err := ParseCSV(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestParseJSON(t *testing.T) {
+	records, err := ParseJSON(strings.NewReader(`[{"sku": "SHIRT-1", "price": 29.9, "qty": 4, "note": null}]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"sku": "SHIRT-1", "price": "29.9", "qty": "4"}}, records)
+
+	_, err = ParseJSON(strings.NewReader(`{"sku": "SHIRT-1"}`))
+	assert.Error(t, err)
+}
+
+func TestCSVReaderStreamsRecords(t *testing.T) {
+	reader, err := NewCSVReader(strings.NewReader("sku,prices.SEK\nSHIRT-1,299\nSHIRT-2,\"19\"9\"\nSHIRT-3,399\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sku", "prices.SEK"}, reader.Columns())
+
+	record, err := reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"sku": "SHIRT-1", "prices.SEK": "299"}, record)
+
+	// A malformed row is reported with its number and reading goes on
+	_, err = reader.Read()
+	var rowErr *RowError
+	if assert.ErrorAs(t, err, &rowErr) {
+		assert.Equal(t, 2, rowErr.Row)
+	}
+
+	record, err = reader.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, "SHIRT-3", record["sku"])
+
+	_, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestCSVReaderEmpty(t *testing.T) {
+	reader, err := NewCSVReader(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Empty(t, reader.Columns())
+	_, err = reader.Read()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,270 @@
+package imports
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// FileSource is where supplier files are picked up from. An SFTP drop folder
+// can be served by any implementation that lists, opens and archives files.
+type FileSource interface {
+	// List returns the names of files that are ready to be imported
+	List() ([]string, error)
+	// Open returns the contents of a listed file
+	Open(name string) (io.ReadCloser, error)
+	// Finish archives a file so it is not imported again
+	Finish(name string, failed bool) error
+}
+
+// DirectorySource picks up files from a local directory, such as the directory an
+// SFTP server writes uploads to. Imported files are moved to the processed or
+// failed subdirectory.
+type DirectorySource struct {
+	dir     string
+	pattern string
+	minAge  time.Duration
+}
+
+// NewDirectorySource watches dir for files matching pattern (e.g. "*.csv").
+// Files modified within minAge are skipped so uploads still in progress are not read.
+func NewDirectorySource(dir, pattern string, minAge time.Duration) (*DirectorySource, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid file pattern %q: %v", pattern, err)
+	}
+	for _, sub := range []string{"processed", "failed"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to prepare import directory: %v", err)
+		}
+	}
+	return &DirectorySource{dir: dir, pattern: pattern, minAge: minAge}, nil
+}
+
+// List returns matching files that have not changed for minAge, oldest first
+func (s *DirectorySource) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type file struct {
+		name    string
+		modTime time.Time
+	}
+	files := make([]file, 0)
+	cutoff := time.Now().Add(-s.minAge)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if matched, _ := filepath.Match(s.pattern, entry.Name()); !matched {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		files = append(files, file{name: entry.Name(), modTime: info.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.name
+	}
+	return names, nil
+}
+
+// Open opens a file in the watched directory
+func (s *DirectorySource) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.dir, filepath.Base(name)))
+}
+
+// Finish moves a file to processed/ or failed/, prefixed with the time it was imported
+func (s *DirectorySource) Finish(name string, failed bool) error {
+	sub := "processed"
+	if failed {
+		sub = "failed"
+	}
+	name = filepath.Base(name)
+	target := filepath.Join(s.dir, sub, time.Now().UTC().Format("20060102T150405Z")+"_"+name)
+	return os.Rename(filepath.Join(s.dir, name), target)
+}
+
+// WatcherConfig describes how picked up files are imported
+type WatcherConfig struct {
+	Mapping  map[string]string
+	Mode     interfaces.ImportMode
+	Locale   string            // How numbers and dates are written in the supplier's files
+	Columns  map[string]string // Column types normalized before mapping
+	Interval time.Duration
+}
+
+// Watcher polls a file source on a schedule and runs every file through the
+// import pipeline. Each file becomes an import job; files that cannot be read
+// are recorded as failed jobs so suppliers' deliveries are all visible in the jobs API.
+type Watcher struct {
+	source  FileSource
+	service interfaces.ImportService
+	jobs    repositories.JobRepository
+	config  WatcherConfig
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewWatcher creates a watcher; the mapping and locale are checked up front so a broken configuration fails at startup
+func NewWatcher(source FileSource, service interfaces.ImportService, jobs repositories.JobRepository, config WatcherConfig) (*Watcher, error) {
+	if _, err := CompileMapping(config.Mapping); err != nil {
+		return nil, err
+	}
+	if _, err := LookupLocale(config.Locale); err != nil {
+		return nil, err
+	}
+	if err := ValidateColumns(config.Columns); err != nil {
+		return nil, err
+	}
+	if config.Interval <= 0 {
+		config.Interval = time.Minute
+	}
+	return &Watcher{
+		source:  source,
+		service: service,
+		jobs:    jobs,
+		config:  config,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+// Start polls the source in the background until Stop is called
+func (w *Watcher) Start() {
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(w.config.Interval)
+		defer ticker.Stop()
+
+		w.Poll()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				w.Poll()
+			}
+		}
+	}()
+}
+
+// Stop ends polling and waits for a running poll to finish
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+// Poll imports every file that is currently ready and returns the resulting job IDs
+func (w *Watcher) Poll() []string {
+	logger := logging.Shared()
+
+	names, err := w.source.List()
+	if err != nil {
+		logger.Error("Failed to list import files", zap.Error(err))
+		return nil
+	}
+
+	jobIDs := make([]string, 0, len(names))
+	for _, name := range names {
+		jobID, failed := w.importFile(name)
+		if jobID != "" {
+			jobIDs = append(jobIDs, jobID)
+		}
+		if err := w.source.Finish(name, failed); err != nil {
+			logger.Error("Failed to archive import file", zap.String("file", name), zap.Error(err))
+		}
+	}
+	return jobIDs
+}
+
+// importFile imports a single file and reports whether it failed as a whole
+func (w *Watcher) importFile(name string) (string, bool) {
+	logger := logging.Shared().WithFields(zap.String("file", name))
+
+	records, err := w.readRecords(name)
+	if err != nil {
+		logger.Error("Failed to read import file", zap.Error(err))
+		return w.recordFailure(name, err), true
+	}
+
+	result, err := w.service.ImportProducts(context.Background(), &interfaces.ImportRequest{
+		Mapping: w.config.Mapping,
+		Records: records,
+		Mode:    w.config.Mode,
+		Source:  name,
+		Locale:  w.config.Locale,
+		Columns: w.config.Columns,
+	})
+	if err != nil {
+		logger.Error("Failed to import file", zap.Error(err))
+		if result != nil {
+			return result.JobID, true
+		}
+		return w.recordFailure(name, err), true
+	}
+
+	logger.Info("Imported file",
+		zap.String("job_id", result.JobID),
+		zap.Int("succeeded", result.Succeeded),
+		zap.Int("failed", result.Failed),
+	)
+	return result.JobID, result.Succeeded == 0 && result.Failed > 0
+}
+
+// readRecords parses a file by its extension
+func (w *Watcher) readRecords(name string) ([]map[string]string, error) {
+	file, err := w.source.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		return ParseJSON(file)
+	}
+	return ParseCSV(file)
+}
+
+// recordFailure stores a failed import job for a file that never reached the pipeline
+func (w *Watcher) recordFailure(name string, cause error) string {
+	job := &models.Job{
+		ID:        "job_" + uuid.New().String(),
+		Type:      models.JobImport,
+		Status:    models.JobStatusRunning,
+		Source:    name,
+		CreatedAt: time.Now(),
+	}
+	job.AddError(cause.Error())
+	job.Complete(0, 0)
+	job.Status = models.JobStatusFailed
+
+	if err := w.jobs.Create(job); err != nil {
+		logging.Shared().Error("Failed to record import job", zap.String("file", name), zap.Error(err))
+		return ""
+	}
+	return job.ID
+}
@@ -0,0 +1,172 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+type stubImportService struct {
+	mu       sync.Mutex
+	requests []*interfaces.ImportRequest
+	result   *interfaces.ImportResult
+	err      error
+}
+
+func (s *stubImportService) ImportProducts(ctx context.Context, req *interfaces.ImportRequest) (*interfaces.ImportResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	return s.result, s.err
+}
+
+func (s *stubImportService) ImportCSV(ctx context.Context, req *interfaces.ImportRequest, file io.Reader) (*interfaces.ImportResult, error) {
+	return nil, errors.New("not used by the watcher")
+}
+
+var watcherMapping = map[string]string{"sku": "{{.sku}}", "stock.wh1": "{{.qty}}"}
+
+func writeImportFile(t *testing.T, dir, name, content string, age time.Duration) {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	modTime := time.Now().Add(-age)
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestDirectorySourceListsSettledFiles(t *testing.T) {
+	dir := t.TempDir()
+	source, err := NewDirectorySource(dir, "*.csv", time.Minute)
+	assert.NoError(t, err)
+
+	writeImportFile(t, dir, "newer.csv", "sku\n", 2*time.Minute)
+	writeImportFile(t, dir, "older.csv", "sku\n", 3*time.Minute)
+	writeImportFile(t, dir, "uploading.csv", "sku\n", 0)
+	writeImportFile(t, dir, "notes.txt", "hello", 3*time.Minute)
+
+	names, err := source.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"older.csv", "newer.csv"}, names)
+}
+
+func TestDirectorySourceFinishArchivesFiles(t *testing.T) {
+	dir := t.TempDir()
+	source, err := NewDirectorySource(dir, "*.csv", 0)
+	assert.NoError(t, err)
+	writeImportFile(t, dir, "ok.csv", "sku\n", time.Minute)
+	writeImportFile(t, dir, "bad.csv", "sku\n", time.Minute)
+
+	assert.NoError(t, source.Finish("ok.csv", false))
+	assert.NoError(t, source.Finish("bad.csv", true))
+
+	processed, _ := filepath.Glob(filepath.Join(dir, "processed", "*_ok.csv"))
+	failed, _ := filepath.Glob(filepath.Join(dir, "failed", "*_bad.csv"))
+	assert.Len(t, processed, 1)
+	assert.Len(t, failed, 1)
+
+	names, err := source.List()
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestWatcherPollImportsFiles(t *testing.T) {
+	dir := t.TempDir()
+	source, _ := NewDirectorySource(dir, "*", 0)
+	writeImportFile(t, dir, "stock.csv", "sku;qty\nSHIRT-1-S;12\n", time.Minute)
+	writeImportFile(t, dir, "prices.json", `[{"sku": "SHIRT-1-M", "qty": 3}]`, time.Minute)
+
+	service := &stubImportService{result: &interfaces.ImportResult{JobID: "job_1", Succeeded: 1}}
+	watcher, err := NewWatcher(source, service, memory.NewJobRepository(), WatcherConfig{
+		Mapping: watcherMapping,
+		Mode:    interfaces.ImportUpdate,
+	})
+	assert.NoError(t, err)
+
+	jobIDs := watcher.Poll()
+	assert.Equal(t, []string{"job_1", "job_1"}, jobIDs)
+	assert.Len(t, service.requests, 2)
+
+	bySource := make(map[string]*interfaces.ImportRequest)
+	for _, req := range service.requests {
+		bySource[req.Source] = req
+		assert.Equal(t, interfaces.ImportUpdate, req.Mode)
+		assert.Equal(t, watcherMapping, req.Mapping)
+	}
+	assert.Equal(t, []map[string]string{{"sku": "SHIRT-1-S", "qty": "12"}}, bySource["stock.csv"].Records)
+	assert.Equal(t, []map[string]string{{"sku": "SHIRT-1-M", "qty": "3"}}, bySource["prices.json"].Records)
+
+	processed, _ := filepath.Glob(filepath.Join(dir, "processed", "*"))
+	assert.Len(t, processed, 2)
+}
+
+func TestWatcherRecordsUnreadableFilesAsFailedJobs(t *testing.T) {
+	dir := t.TempDir()
+	source, _ := NewDirectorySource(dir, "*.json", 0)
+	writeImportFile(t, dir, "broken.json", "{not json", time.Minute)
+
+	jobs := memory.NewJobRepository()
+	service := &stubImportService{}
+	watcher, err := NewWatcher(source, service, jobs, WatcherConfig{Mapping: watcherMapping})
+	assert.NoError(t, err)
+
+	jobIDs := watcher.Poll()
+	assert.Len(t, jobIDs, 1)
+	assert.Empty(t, service.requests)
+
+	job, err := jobs.GetByID(jobIDs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobImport, job.Type)
+	assert.Equal(t, models.JobStatusFailed, job.Status)
+	assert.Equal(t, "broken.json", job.Source)
+	assert.Len(t, job.Errors, 1)
+
+	failed, _ := filepath.Glob(filepath.Join(dir, "failed", "*_broken.json"))
+	assert.Len(t, failed, 1)
+}
+
+func TestWatcherImportErrorMovesFileToFailed(t *testing.T) {
+	dir := t.TempDir()
+	source, _ := NewDirectorySource(dir, "*.csv", 0)
+	writeImportFile(t, dir, "stock.csv", "sku,qty\nSHIRT-1,1\n", time.Minute)
+
+	jobs := memory.NewJobRepository()
+	watcher, _ := NewWatcher(source, &stubImportService{err: errors.New("storage down")}, jobs, WatcherConfig{Mapping: watcherMapping})
+
+	jobIDs := watcher.Poll()
+	assert.Len(t, jobIDs, 1)
+	failed, _ := filepath.Glob(filepath.Join(dir, "failed", "*_stock.csv"))
+	assert.Len(t, failed, 1)
+}
+
+func TestNewWatcherRejectsInvalidMapping(t *testing.T) {
+	source, _ := NewDirectorySource(t.TempDir(), "*.csv", 0)
+	_, err := NewWatcher(source, &stubImportService{}, memory.NewJobRepository(), WatcherConfig{Mapping: map[string]string{"color": "{{.color}}"}})
+	assert.True(t, errors.Is(err, models.ErrInvalidMapping))
+}
+
+func TestWatcherStartAndStop(t *testing.T) {
+	dir := t.TempDir()
+	source, _ := NewDirectorySource(dir, "*.csv", 0)
+	writeImportFile(t, dir, "stock.csv", "sku,qty\nSHIRT-1,1\n", time.Minute)
+
+	service := &stubImportService{result: &interfaces.ImportResult{JobID: "job_1", Succeeded: 1}}
+	watcher, _ := NewWatcher(source, service, memory.NewJobRepository(), WatcherConfig{Mapping: watcherMapping, Interval: 10 * time.Millisecond})
+
+	watcher.Start()
+	assert.Eventually(t, func() bool {
+		service.mu.Lock()
+		defer service.mu.Unlock()
+		return len(service.requests) == 1
+	}, time.Second, 10*time.Millisecond)
+	watcher.Stop()
+}
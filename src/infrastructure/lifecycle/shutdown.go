@@ -0,0 +1,52 @@
+// Package lifecycle stops the service's components in order on shutdown
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// Step is one part of shutting down, e.g. draining the HTTP server
+type Step struct {
+	Name string
+	Stop func(ctx context.Context) error
+}
+
+// Shutdown runs the steps in order, sharing one deadline. A failing step is
+// logged and does not stop the later steps; once the deadline passed, the
+// remaining steps still run with the expired context so they can cut their
+// work short. The failures are returned joined.
+func Shutdown(ctx context.Context, steps ...Step) error {
+	logger := logging.Shared()
+	var errs []error
+	for _, step := range steps {
+		start := time.Now()
+		if err := step.Stop(ctx); err != nil {
+			logger.Error("Shutdown step failed",
+				zap.String("step", step.Name),
+				zap.Duration("duration", time.Since(start)),
+				zap.Error(err),
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", step.Name, err))
+			continue
+		}
+		logger.Info("Shutdown step done",
+			zap.String("step", step.Name),
+			zap.Duration("duration", time.Since(start)),
+		)
+	}
+	return errors.Join(errs...)
+}
+
+// Func turns a function without context or error into a step
+func Func(name string, stop func()) Step {
+	return Step{Name: name, Stop: func(context.Context) error {
+		stop()
+		return nil
+	}}
+}
@@ -0,0 +1,41 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownRunsStepsInOrder(t *testing.T) {
+	var order []string
+	step := func(name string, err error) Step {
+		return Step{Name: name, Stop: func(ctx context.Context) error {
+			order = append(order, name)
+			return err
+		}}
+	}
+
+	err := Shutdown(context.Background(),
+		Func("watcher", func() { order = append(order, "watcher") }),
+		step("http", errors.New("drain timed out")),
+		step("events", nil),
+	)
+
+	assert.Equal(t, []string{"watcher", "http", "events"}, order)
+	assert.EqualError(t, err, "http: drain timed out")
+}
+
+func TestShutdownRunsStepsAfterTheDeadline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ran := false
+	err := Shutdown(ctx, Step{Name: "locks", Stop: func(ctx context.Context) error {
+		ran = true
+		return ctx.Err()
+	}})
+	assert.True(t, ran)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -2,6 +2,8 @@ package locks
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -12,6 +14,22 @@ type LockManager interface {
 	RefreshLock(resourceID string, ttl time.Duration) error
 }
 
+// LockHolder describes a held lock
+type LockHolder struct {
+	ResourceID string
+	Owner      string
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+}
+
+// OwnedLockManager is a lock manager that records who holds each lock and can
+// list the holders, e.g. to show who is editing a product
+type OwnedLockManager interface {
+	LockManager
+	AcquireOwnedLock(ctx context.Context, resourceID, owner string, ttl time.Duration) (bool, error)
+	Holders(prefix string) []LockHolder
+}
+
 type MemoryLockManager struct {
 	locks    map[string]*Lock
 	mu       sync.RWMutex
@@ -19,9 +37,10 @@ type MemoryLockManager struct {
 }
 
 type Lock struct {
-	owner     string
-	expiresAt time.Time
-	mu        sync.Mutex
+	owner      string
+	acquiredAt time.Time
+	expiresAt  time.Time
+	mu         sync.Mutex
 }
 
 func NewMemoryLockManager() *MemoryLockManager {
@@ -34,6 +53,11 @@ func NewMemoryLockManager() *MemoryLockManager {
 }
 
 func (m *MemoryLockManager) AcquireLock(ctx context.Context, resourceID string, ttl time.Duration) (bool, error) {
+	return m.AcquireOwnedLock(ctx, resourceID, "owner", ttl)
+}
+
+// AcquireOwnedLock acquires a lock on behalf of owner, as reported by Holders
+func (m *MemoryLockManager) AcquireOwnedLock(ctx context.Context, resourceID, owner string, ttl time.Duration) (bool, error) {
 	if err := ctx.Err(); err != nil {
 		return false, err
 	}
@@ -50,8 +74,9 @@ func (m *MemoryLockManager) AcquireLock(ctx context.Context, resourceID string,
 	}
 
 	m.locks[resourceID] = &Lock{
-		owner:     "owner",
-		expiresAt: now.Add(ttl),
+		owner:      owner,
+		acquiredAt: now,
+		expiresAt:  now.Add(ttl),
 	}
 	return true, nil
 }
@@ -74,6 +99,32 @@ func (m *MemoryLockManager) RefreshLock(resourceID string, ttl time.Duration) er
 	return nil
 }
 
+// Holders returns the unexpired locks whose resource ID starts with prefix, oldest first
+func (m *MemoryLockManager) Holders(prefix string) []LockHolder {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+	holders := make([]LockHolder, 0)
+	for id, lock := range m.locks {
+		if strings.HasPrefix(id, prefix) && now.Before(lock.expiresAt) {
+			holders = append(holders, LockHolder{
+				ResourceID: id,
+				Owner:      lock.owner,
+				AcquiredAt: lock.acquiredAt,
+				ExpiresAt:  lock.expiresAt,
+			})
+		}
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if !holders[i].AcquiredAt.Equal(holders[j].AcquiredAt) {
+			return holders[i].AcquiredAt.Before(holders[j].AcquiredAt)
+		}
+		return holders[i].ResourceID < holders[j].ResourceID
+	})
+	return holders
+}
+
 func (m *MemoryLockManager) cleanupExpiredLocks() {
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
@@ -169,3 +169,39 @@ func TestContextCancellation(t *testing.T) {
 	_, err = manager.AcquireLock(ctx, resourceID, time.Second)
 	assert.Error(t, err, "Should fail when context is cancelled")
 }
+
+func TestLockHolders(t *testing.T) {
+	manager := NewMemoryLockManager()
+	defer manager.Close()
+	ctx := context.Background()
+
+	acquired, err := manager.AcquireOwnedLock(ctx, "edit:p1:a", "ada", time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	acquired, err = manager.AcquireOwnedLock(ctx, "edit:p1:b", "grace", time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	_, err = manager.AcquireOwnedLock(ctx, "edit:p2:c", "alan", time.Second)
+	assert.NoError(t, err)
+
+	// A held lock keeps its owner
+	acquired, err = manager.AcquireOwnedLock(ctx, "edit:p1:a", "grace", time.Second)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	holders := manager.Holders("edit:p1:")
+	assert.Len(t, holders, 2)
+	assert.Equal(t, "ada", holders[0].Owner)
+	assert.Equal(t, "edit:p1:a", holders[0].ResourceID)
+	assert.Equal(t, "grace", holders[1].Owner)
+	assert.True(t, holders[0].ExpiresAt.After(holders[0].AcquiredAt))
+
+	// Released and expired locks are not listed
+	assert.NoError(t, manager.ReleaseLock("edit:p1:a"))
+	_, err = manager.AcquireOwnedLock(ctx, "edit:p1:d", "linus", time.Millisecond)
+	assert.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+	holders = manager.Holders("edit:p1:")
+	assert.Len(t, holders, 1)
+	assert.Equal(t, "grace", holders[0].Owner)
+}
@@ -19,8 +19,8 @@ func NewDevelopmentLogger() (*Logger, error) {
 	config.DisableCaller = false
 	config.DisableStacktrace = false
 
-	// Set minimum log level to Debug in development mode
-	config.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	// Debug in development mode unless SetLevel chose another level
+	config.Level = level
 
 	zapLogger, err := config.Build(
 		zap.AddCaller(),
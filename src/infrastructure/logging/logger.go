@@ -3,8 +3,10 @@ package logging
 import (
 	"context"
 	"os"
+	"sync"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type contextKey string
@@ -16,6 +18,42 @@ type Logger struct {
 	*zap.Logger
 }
 
+var (
+	sharedOnce   sync.Once
+	sharedLogger *Logger
+
+	// level is the minimum level of loggers built by NewLogger; SetLevel
+	// changes it for loggers already built too
+	level = zap.NewAtomicLevelAt(defaultLevel())
+)
+
+// defaultLevel is debug in development and info otherwise
+func defaultLevel() zapcore.Level {
+	if os.Getenv("GO_ENV") == "development" {
+		return zap.DebugLevel
+	}
+	return zap.InfoLevel
+}
+
+// SetLevel sets the minimum level logged, e.g. "warn"
+func SetLevel(text string) error {
+	return level.UnmarshalText([]byte(text))
+}
+
+// Shared returns a process-wide logger built once by NewLogger. Request
+// handlers derive their loggers from it with WithRequestID instead of
+// building a new zap logger per request.
+func Shared() *Logger {
+	sharedOnce.Do(func() {
+		logger, err := NewLogger()
+		if err != nil {
+			logger = &Logger{Logger: zap.NewNop()}
+		}
+		sharedLogger = logger
+	})
+	return sharedLogger
+}
+
 // NewLogger creates a new structured logger
 func NewLogger() (*Logger, error) {
 	env := os.Getenv("GO_ENV")
@@ -30,6 +68,7 @@ func NewLogger() (*Logger, error) {
 // NewProductionLogger creates a new production logger
 func NewProductionLogger() (*Logger, error) {
 	config := zap.NewProductionConfig()
+	config.Level = level
 	zapLogger, err := config.Build()
 	if err != nil {
 		return nil, err
@@ -50,14 +89,24 @@ func FromContext(ctx context.Context) *Logger {
 	return &Logger{Logger: zap.NewNop()}
 }
 
+// FromContextOrShared retrieves logger from context, or the shared logger if
+// the context has none
+func FromContextOrShared(ctx context.Context) *Logger {
+	if logger, ok := ctx.Value(loggerKey).(*Logger); ok {
+		return logger
+	}
+	return Shared()
+}
+
 // WithFields adds fields to the logger
 func (l *Logger) WithFields(fields ...zap.Field) *Logger {
 	return &Logger{Logger: l.Logger.With(fields...)}
 }
 
-// WithRequestID adds request ID to the logger
+// WithRequestID adds request ID to the logger. The field is encoded lazily,
+// so requests that log nothing at the enabled level do not pay for it.
 func (l *Logger) WithRequestID(requestID string) *Logger {
-	return l.WithFields(zap.String("request_id", requestID))
+	return &Logger{Logger: l.Logger.WithLazy(zap.String("request_id", requestID))}
 }
 
 // WithTraceID adds trace ID to the logger
@@ -151,3 +151,58 @@ func TestLoggerConcurrency(t *testing.T) {
 	}
 	assert.Len(t, ids, numGoroutines)
 }
+
+func TestSharedLogger(t *testing.T) {
+	logger := Shared()
+	assert.NotNil(t, logger)
+	assert.Same(t, logger, Shared())
+
+	// Derived loggers do not modify the shared one
+	assert.NotSame(t, logger, logger.WithRequestID("req_1"))
+}
+
+func TestWithRequestIDIsLogged(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	logger := (&Logger{Logger: zap.New(core)}).WithRequestID("req_1")
+
+	logger.Debug("below level")
+	logger.Info("request handled")
+
+	logs := recorded.All()
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "req_1", logs[0].ContextMap()["request_id"])
+}
+
+func BenchmarkNewLoggerPerRequest(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		logger, _ := NewLogger()
+		logger.WithRequestID("req_1").Debug("request")
+	}
+}
+
+func BenchmarkSharedLoggerPerRequest(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Shared().WithRequestID("req_1").Debug("request")
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	logger, err := NewProductionLogger()
+	assert.NoError(t, err)
+	defer SetLevel(defaultLevel().String())
+
+	assert.NoError(t, SetLevel("warn"))
+	assert.False(t, logger.Core().Enabled(zap.InfoLevel), "loggers already built follow the level")
+	assert.True(t, logger.Core().Enabled(zap.WarnLevel))
+
+	assert.Error(t, SetLevel("loud"))
+}
+
+func TestFromContextOrShared(t *testing.T) {
+	assert.Same(t, Shared(), FromContextOrShared(context.Background()))
+
+	logger := &Logger{Logger: zap.NewNop()}
+	assert.Same(t, logger, FromContextOrShared(WithContext(context.Background(), logger)))
+}
@@ -0,0 +1,118 @@
+package marketplaces
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// AmazonListing is a listings item in the shape of the Selling Partner Listings API.
+// Amazon lists every variant as its own SKU.
+type AmazonListing struct {
+	SKU          string                 `json:"sku"`
+	ProductType  string                 `json:"productType"`
+	Requirements string                 `json:"requirements"`
+	Attributes   map[string]interface{} `json:"attributes"`
+}
+
+// Amazon exports products to Amazon. Categories are mapped to Amazon product types.
+type Amazon struct {
+	config Config
+	client *apiClient
+}
+
+// NewAmazon creates an Amazon adapter
+func NewAmazon(config Config) *Amazon {
+	return &Amazon{config: config, client: newAPIClient(config)}
+}
+
+// Name returns "amazon"
+func (a *Amazon) Name() string {
+	return "amazon"
+}
+
+// Build creates one listing per variant, or a single listing for products without variants
+func (a *Amazon) Build(product *models.Product) (interface{}, error) {
+	productType, err := a.config.category(product)
+	if err != nil {
+		return nil, err
+	}
+	price, err := a.config.price(product, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.config.checkCompliance(product); err != nil {
+		return nil, err
+	}
+	title, description := a.config.metadata(product)
+
+	newListing := func(sku string, quantity int, price models.Price) *AmazonListing {
+		return &AmazonListing{
+			SKU:          sku,
+			ProductType:  productType,
+			Requirements: "LISTING",
+			Attributes: map[string]interface{}{
+				"item_name":           title,
+				"product_description": description,
+				"list_price": []map[string]interface{}{
+					{"currency": price.Currency, "value": price.Amount},
+				},
+				"fulfillment_availability": []map[string]interface{}{
+					{"fulfillment_channel_code": "DEFAULT", "quantity": quantity},
+				},
+			},
+		}
+	}
+
+	if len(product.Variants) == 0 {
+		if len(a.config.RequiredAttributes) > 0 {
+			return nil, fmt.Errorf("product %s has no variants with the required attributes", product.SKU)
+		}
+		return []*AmazonListing{newListing(product.SKU, 0, price)}, nil
+	}
+
+	listings := make([]*AmazonListing, 0, len(product.Variants))
+	for i, variant := range product.Variants {
+		if err := a.config.checkAttributes(variant); err != nil {
+			return nil, err
+		}
+		variantPrice, _ := a.config.price(product, &product.Variants[i])
+		listing := newListing(variant.SKU, availableQuantity(variant), variantPrice)
+		listing.Attributes["parent_sku"] = product.SKU
+		for name, value := range variant.Attributes {
+			listing.Attributes[name] = value
+		}
+		listings = append(listings, listing)
+	}
+	return listings, nil
+}
+
+// Push puts every listing of a product
+func (a *Amazon) Push(payload interface{}) error {
+	listings, ok := payload.([]*AmazonListing)
+	if !ok {
+		return fmt.Errorf("unexpected Amazon payload %T", payload)
+	}
+	for _, listing := range listings {
+		if err := a.client.send(http.MethodPut, "/listings/items/"+url.PathEscape(listing.SKU), listing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Remove deletes the listings of a product and all its variants
+func (a *Amazon) Remove(product *models.Product) error {
+	skus := []string{product.SKU}
+	for _, variant := range product.Variants {
+		skus = append(skus, variant.SKU)
+	}
+	for _, sku := range skus {
+		if err := a.client.send(http.MethodDelete, "/listings/items/"+url.PathEscape(sku), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package marketplaces
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func amazonConfig() Config {
+	return Config{
+		Market:             "US",
+		Currency:           "EUR",
+		Categories:         map[string]string{"SHOE-": "SHOES"},
+		RequiredAttributes: []string{"size"},
+	}
+}
+
+func TestAmazonBuildListsEveryVariant(t *testing.T) {
+	payload, err := NewAmazon(amazonConfig()).Build(testProduct())
+	assert.NoError(t, err)
+
+	listings := payload.([]*AmazonListing)
+	assert.Len(t, listings, 2)
+	assert.Equal(t, "SHOE-001-42", listings[0].SKU)
+	assert.Equal(t, "SHOES", listings[0].ProductType)
+	assert.Equal(t, "Running shoe", listings[0].Attributes["item_name"])
+	assert.Equal(t, "SHOE-001", listings[0].Attributes["parent_sku"])
+	assert.Equal(t, "42", listings[0].Attributes["size"])
+	assert.Equal(t, []map[string]interface{}{{"currency": "EUR", "value": 89.0}}, listings[0].Attributes["list_price"])
+	assert.Equal(t, []map[string]interface{}{{"fulfillment_channel_code": "DEFAULT", "quantity": 3}}, listings[0].Attributes["fulfillment_availability"])
+}
+
+func TestAmazonBuildUsesVariantPrices(t *testing.T) {
+	product := testProduct()
+	product.Variants[1].Prices = []models.Price{{Currency: "EUR", Amount: 99}}
+
+	payload, err := NewAmazon(amazonConfig()).Build(product)
+	assert.NoError(t, err)
+
+	listings := payload.([]*AmazonListing)
+	assert.Equal(t, []map[string]interface{}{{"currency": "EUR", "value": 89.0}}, listings[0].Attributes["list_price"])
+	assert.Equal(t, []map[string]interface{}{{"currency": "EUR", "value": 99.0}}, listings[1].Attributes["list_price"])
+}
+
+func TestAmazonBuildRequirements(t *testing.T) {
+	amazon := NewAmazon(amazonConfig())
+
+	product := testProduct()
+	delete(product.Variants[1].Attributes, "size")
+	_, err := amazon.Build(product)
+	assert.EqualError(t, err, "variant SHOE-001-43 is missing required attributes: size")
+
+	product = testProduct()
+	product.Prices = product.Prices[:1]
+	_, err = amazon.Build(product)
+	assert.EqualError(t, err, "missing EUR price")
+
+	product = testProduct()
+	product.SKU = "HAT-001"
+	_, err = amazon.Build(product)
+	assert.ErrorIs(t, err, ErrNotListed)
+}
+
+func TestAmazonPushAndRemove(t *testing.T) {
+	var mu sync.Mutex
+	requests := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	config := amazonConfig()
+	config.BaseURL = server.URL
+	amazon := NewAmazon(config)
+
+	payload, err := amazon.Build(testProduct())
+	assert.NoError(t, err)
+	assert.NoError(t, amazon.Push(payload))
+	assert.NoError(t, amazon.Remove(testProduct()))
+
+	assert.Equal(t, []string{
+		"PUT /listings/items/SHOE-001-42",
+		"PUT /listings/items/SHOE-001-43",
+		"DELETE /listings/items/SHOE-001",
+		"DELETE /listings/items/SHOE-001-42",
+		"DELETE /listings/items/SHOE-001-43",
+	}, requests)
+}
@@ -0,0 +1,180 @@
+package marketplaces
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// ErrNotListed is returned by Build for products outside the catalog subset a marketplace receives
+var ErrNotListed = errors.New("product is not listed on the marketplace")
+
+// requestTimeout bounds a single call to a marketplace API
+const requestTimeout = 10 * time.Second
+
+// Marketplace transforms products into a marketplace's own payload format and
+// delivers them through its API
+type Marketplace interface {
+	// Name identifies the marketplace, e.g. "amazon"
+	Name() string
+	// Build maps a product to the marketplace payload. It returns ErrNotListed for
+	// products the marketplace should not receive, and an error describing what
+	// is missing for products that do not meet its attribute requirements.
+	Build(product *models.Product) (interface{}, error)
+	// Push creates or replaces the listing built for a product
+	Push(payload interface{}) error
+	// Remove takes a product's listing down
+	Remove(product *models.Product) error
+}
+
+// Config describes how products are exported to one marketplace
+type Config struct {
+	BaseURL string `json:"base_url"`
+	Token   string `json:"token"`
+	// Market selects the market metadata used for titles and descriptions
+	Market   string `json:"market"`
+	Currency string `json:"currency"`
+	// Categories maps SKU prefixes to marketplace categories. The longest matching
+	// prefix wins; products matching no prefix are not exported. An empty prefix
+	// matches every product.
+	Categories map[string]string `json:"categories"`
+	// RequiredAttributes lists the variant attributes the marketplace requires, e.g. "size"
+	RequiredAttributes []string `json:"required_attributes"`
+}
+
+// category returns the marketplace category for a product
+func (c Config) category(product *models.Product) (string, error) {
+	prefixes := make([]string, 0, len(c.Categories))
+	for prefix := range c.Categories {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(product.SKU, prefix) {
+			return c.Categories[prefix], nil
+		}
+	}
+	return "", ErrNotListed
+}
+
+// price returns the price of a variant in the configured currency: its override,
+// or the product price. A nil variant gives the product price.
+func (c Config) price(product *models.Product, variant *models.Variant) (models.Price, error) {
+	if price, ok := product.PriceFor(variant, c.Currency); ok {
+		return price, nil
+	}
+	return models.Price{}, fmt.Errorf("missing %s price", c.Currency)
+}
+
+// metadata returns the product's title and description for the configured market,
+// falling back to the base title and description
+func (c Config) metadata(product *models.Product) (string, string) {
+	for _, metadata := range product.Metadata {
+		if metadata.Market == c.Market {
+			description := metadata.Description
+			if description == "" {
+				description = product.Description
+			}
+			return metadata.Title, description
+		}
+	}
+	return product.BaseTitle, product.Description
+}
+
+// checkAttributes reports the required attributes a variant lacks
+func (c Config) checkAttributes(variant models.Variant) error {
+	missing := make([]string, 0)
+	for _, attribute := range c.RequiredAttributes {
+		if variant.Attributes[attribute] == "" {
+			missing = append(missing, attribute)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("variant %s is missing required attributes: %s", variant.SKU, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// checkCompliance reports the compliance data the configured market requires that a product lacks
+func (c Config) checkCompliance(product *models.Product) error {
+	if issues := product.ComplianceIssues(c.Market); len(issues) > 0 {
+		return fmt.Errorf("product %s is not compliant in %s: %s", product.SKU, c.Market, strings.Join(issues, "; "))
+	}
+	return nil
+}
+
+// availableQuantity sums a variant's stock over all locations; backordered
+// locations never count as negative availability
+func availableQuantity(variant models.Variant) int {
+	quantity := 0
+	for _, stock := range variant.Stock {
+		if stock.Quantity > 0 {
+			quantity += stock.Quantity
+		}
+	}
+	return quantity
+}
+
+// apiClient sends JSON requests to a marketplace API
+type apiClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func newAPIClient(config Config) *apiClient {
+	return &apiClient{
+		baseURL: strings.TrimRight(config.BaseURL, "/"),
+		token:   config.Token,
+		http:    &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// send performs a request and fails on any non-2xx response. Deleting a
+// listing that no longer exists counts as success.
+func (c *apiClient) send(method, path string, body interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode payload: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	if method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(message)))
+}
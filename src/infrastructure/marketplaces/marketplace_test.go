@@ -0,0 +1,112 @@
+package marketplaces
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// testProduct returns a product with two sized variants, priced in SEK and EUR
+func testProduct() *models.Product {
+	return &models.Product{
+		ID:          "prod_1",
+		SKU:         "SHOE-001",
+		BaseTitle:   "Running shoe",
+		Description: "A light running shoe",
+		Version:     1,
+		Prices: []models.Price{
+			{Currency: "SEK", Amount: 999},
+			{Currency: "EUR", Amount: 89},
+		},
+		Metadata: []models.MarketMetadata{
+			{Market: "DE", Title: "Laufschuh"},
+		},
+		Variants: []models.Variant{
+			{
+				ID:         "var_1",
+				SKU:        "SHOE-001-42",
+				Attributes: map[string]string{"size": "42", "color": "black"},
+				Stock: []models.Stock{
+					{LocationID: "sto", Quantity: 3},
+					{LocationID: "got", Quantity: -2, Backorder: true},
+				},
+			},
+			{
+				ID:         "var_2",
+				SKU:        "SHOE-001-43",
+				Attributes: map[string]string{"size": "43", "color": "black"},
+				Stock:      []models.Stock{{LocationID: "sto", Quantity: 5}},
+			},
+		},
+	}
+}
+
+func TestConfigCategoryUsesLongestPrefix(t *testing.T) {
+	config := Config{Categories: map[string]string{
+		"SHOE-":    "SHOES",
+		"SHOE-001": "RUNNING_SHOES",
+	}}
+
+	category, err := config.category(testProduct())
+	assert.NoError(t, err)
+	assert.Equal(t, "RUNNING_SHOES", category)
+
+	product := testProduct()
+	product.SKU = "HAT-001"
+	_, err = config.category(product)
+	assert.ErrorIs(t, err, ErrNotListed)
+
+	config.Categories[""] = "MISC"
+	category, err = config.category(product)
+	assert.NoError(t, err)
+	assert.Equal(t, "MISC", category)
+}
+
+func TestConfigMetadataFallsBackToBase(t *testing.T) {
+	title, description := Config{Market: "DE"}.metadata(testProduct())
+	assert.Equal(t, "Laufschuh", title)
+	assert.Equal(t, "A light running shoe", description)
+
+	title, _ = Config{Market: "US"}.metadata(testProduct())
+	assert.Equal(t, "Running shoe", title)
+}
+
+func TestConfigCheckAttributes(t *testing.T) {
+	config := Config{RequiredAttributes: []string{"size", "material"}}
+	err := config.checkAttributes(testProduct().Variants[0])
+	assert.EqualError(t, err, "variant SHOE-001-42 is missing required attributes: material")
+}
+
+func TestAvailableQuantityIgnoresBackorders(t *testing.T) {
+	assert.Equal(t, 3, availableQuantity(testProduct().Variants[0]))
+}
+
+func TestAPIClientSend(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch r.Method {
+		case http.MethodPut:
+			json.NewDecoder(r.Body).Decode(&received)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			http.Error(w, "rejected", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := newAPIClient(Config{BaseURL: server.URL + "/", Token: "secret"})
+	assert.NoError(t, client.send(http.MethodPut, "/items/1", map[string]string{"sku": "A"}))
+	assert.Equal(t, "A", received["sku"])
+
+	// Removing a listing that is already gone is not an error
+	assert.NoError(t, client.send(http.MethodDelete, "/items/1", nil))
+
+	err := client.send(http.MethodPost, "/items", nil)
+	assert.EqualError(t, err, "POST /items returned 400: rejected")
+}
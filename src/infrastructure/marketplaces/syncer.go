@@ -0,0 +1,181 @@
+package marketplaces
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// syncLockStripes is the number of locks products are spread over so events
+// for the same product are synced one at a time
+const syncLockStripes = 64
+
+// Target returns the sync status target name for a marketplace
+func Target(marketplace string) string {
+	return models.SyncTarget(models.SyncTargetMarketplace, marketplace)
+}
+
+// Syncer keeps marketplaces up to date from product events and records the
+// outcome per product and marketplace
+type Syncer struct {
+	marketplaces map[string]Marketplace
+	names        []string
+	statuses     repositories.SyncStatusRepository
+	locks        [syncLockStripes]sync.Mutex
+}
+
+// NewSyncer creates a syncer for the given marketplaces
+func NewSyncer(statuses repositories.SyncStatusRepository, marketplaces ...Marketplace) *Syncer {
+	s := &Syncer{
+		marketplaces: make(map[string]Marketplace, len(marketplaces)),
+		statuses:     statuses,
+	}
+	for _, marketplace := range marketplaces {
+		s.marketplaces[marketplace.Name()] = marketplace
+		s.names = append(s.names, marketplace.Name())
+	}
+	sort.Strings(s.names)
+	return s
+}
+
+// Subscribe delivers product events from the publisher to the marketplaces
+func (s *Syncer) Subscribe(publisher events.EventPublisher) {
+	for _, eventType := range []models.EventType{
+		models.EventProductCreated,
+		models.EventProductUpdated,
+		models.EventProductDeleted,
+		models.EventProductRestored,
+	} {
+		publisher.Subscribe(eventType, s.HandleEvent)
+	}
+}
+
+// HandleEvent syncs the product in a product event to every marketplace
+func (s *Syncer) HandleEvent(event *models.Event) {
+	productEvent, ok := event.Data.(*models.ProductEvent)
+	if !ok || productEvent.Product == nil {
+		return
+	}
+
+	lock := s.lockFor(productEvent.ProductID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	deleted := event.Type == models.EventProductDeleted
+	version := productEvent.Product.Version
+	if deleted {
+		version = event.Version
+	}
+	for _, name := range s.names {
+		s.sync(s.marketplaces[name], productEvent.Product, version, deleted)
+	}
+}
+
+// sync delivers one product version to one marketplace
+func (s *Syncer) sync(marketplace Marketplace, product *models.Product, version int64, deleted bool) {
+	target := Target(marketplace.Name())
+	logger := logging.Shared().WithFields(
+		zap.String("marketplace", marketplace.Name()),
+		zap.String("product_id", product.ID),
+		zap.Int64("version", version),
+	)
+
+	status, err := s.statuses.Get(product.ID, target)
+	if errors.Is(err, models.ErrSyncStatusNotFound) {
+		if deleted {
+			return // Never sent to the marketplace, so there is nothing to remove
+		}
+		status = models.NewSyncStatus(product.ID, target)
+	} else if err != nil {
+		logger.Error("Failed to read marketplace sync status", zap.Error(err))
+		return
+	}
+	// Events are delivered concurrently and replayed after restarts, so older
+	// versions and versions that were already delivered are ignored
+	if status.Handled(version) {
+		return
+	}
+	listed := status.Listed()
+	status.Begin(version, time.Now())
+
+	state := models.SyncStateSynced
+	switch {
+	case deleted:
+		state = models.SyncStateRemoved
+		if listed {
+			err = marketplace.Remove(product)
+		}
+	default:
+		var payload interface{}
+		payload, err = marketplace.Build(product)
+		switch {
+		case errors.Is(err, ErrNotListed):
+			// The product left the exported subset, so its listing is taken down
+			state = models.SyncStateSkipped
+			err = nil
+			if listed {
+				err = marketplace.Remove(product)
+			}
+		case err == nil:
+			err = marketplace.Push(payload)
+		default:
+			err = fmt.Errorf("%w: %v", models.ErrInvalidProduct, err)
+		}
+	}
+
+	if err != nil {
+		status.Fail(err, time.Now())
+		logger.Warn("Marketplace sync failed", zap.Error(err))
+	} else {
+		status.Succeed(state, time.Now())
+	}
+
+	if err := s.statuses.Save(status); err != nil {
+		logger.Error("Failed to save marketplace sync status", zap.Error(err))
+	}
+}
+
+// lockFor returns the lock serialising syncs of a product
+func (s *Syncer) lockFor(productID string) *sync.Mutex {
+	hash := fnv.New32a()
+	hash.Write([]byte(productID))
+	return &s.locks[hash.Sum32()%syncLockStripes]
+}
+
+// Marketplaces returns the names of the configured marketplaces
+func (s *Syncer) Marketplaces() []string {
+	return append([]string(nil), s.names...)
+}
+
+// SyncStatuses returns the latest sync statuses for a marketplace, newest first,
+// optionally only those in one state
+func (s *Syncer) SyncStatuses(marketplace string, state models.SyncState, limit int) ([]*models.SyncStatus, error) {
+	if _, exists := s.marketplaces[marketplace]; !exists {
+		return nil, fmt.Errorf("%w: %s", models.ErrMarketplaceNotFound, marketplace)
+	}
+
+	statuses, err := s.statuses.ListByTarget(Target(marketplace), 0)
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]*models.SyncStatus, 0, len(statuses))
+	for _, status := range statuses {
+		if state != "" && status.State != state {
+			continue
+		}
+		filtered = append(filtered, status)
+		if limit > 0 && len(filtered) == limit {
+			break
+		}
+	}
+	return filtered, nil
+}
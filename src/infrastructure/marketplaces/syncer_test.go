@@ -0,0 +1,190 @@
+package marketplaces
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	eventsMemory "github.com/jimmitjoo/ecom/src/infrastructure/events/memory"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeMarketplace lists products whose SKU starts with "SHOE-" and records calls
+type fakeMarketplace struct {
+	name    string
+	pushErr error
+	pushes  int
+	removed int
+}
+
+func (m *fakeMarketplace) Name() string { return m.name }
+
+func (m *fakeMarketplace) Build(product *models.Product) (interface{}, error) {
+	return NewZalando(zalandoConfig()).Build(product)
+}
+
+func (m *fakeMarketplace) Push(payload interface{}) error {
+	if m.pushErr != nil {
+		return m.pushErr
+	}
+	m.pushes++
+	return nil
+}
+
+func (m *fakeMarketplace) Remove(product *models.Product) error {
+	m.removed++
+	return nil
+}
+
+func productEvent(eventType models.EventType, product *models.Product) *models.Event {
+	return &models.Event{
+		ID:       "evt",
+		Type:     eventType,
+		EntityID: product.ID,
+		Version:  product.Version,
+		Data:     &models.ProductEvent{ProductID: product.ID, Product: product},
+	}
+}
+
+func TestSyncerRecordsSyncedVersion(t *testing.T) {
+	statuses := memory.NewSyncStatusRepository()
+	marketplace := &fakeMarketplace{name: "zalando"}
+	syncer := NewSyncer(statuses, marketplace)
+
+	product := testProduct()
+	syncer.HandleEvent(productEvent(models.EventProductCreated, product))
+
+	status, err := statuses.Get("prod_1", "marketplace:zalando")
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStateSynced, status.State)
+	assert.Equal(t, int64(1), status.SyncedVersion)
+	assert.NotNil(t, status.SyncedAt)
+
+	// Replaying the same version does not push it again
+	syncer.HandleEvent(productEvent(models.EventProductCreated, product))
+	assert.Equal(t, 1, marketplace.pushes)
+}
+
+func TestSyncerIgnoresOlderVersions(t *testing.T) {
+	statuses := memory.NewSyncStatusRepository()
+	marketplace := &fakeMarketplace{name: "zalando"}
+	syncer := NewSyncer(statuses, marketplace)
+
+	newer := testProduct()
+	newer.Version = 3
+	syncer.HandleEvent(productEvent(models.EventProductUpdated, newer))
+	syncer.HandleEvent(productEvent(models.EventProductUpdated, testProduct()))
+
+	assert.Equal(t, 1, marketplace.pushes)
+	status, _ := statuses.Get("prod_1", "marketplace:zalando")
+	assert.Equal(t, int64(3), status.SyncedVersion)
+}
+
+func TestSyncerRecordsFailures(t *testing.T) {
+	statuses := memory.NewSyncStatusRepository()
+	marketplace := &fakeMarketplace{name: "zalando", pushErr: errors.New("PUT /product-models/SHOE-001 returned 503")}
+	syncer := NewSyncer(statuses, marketplace)
+
+	product := testProduct()
+	syncer.HandleEvent(productEvent(models.EventProductCreated, product))
+	syncer.HandleEvent(productEvent(models.EventProductCreated, product))
+
+	status, _ := statuses.Get("prod_1", "marketplace:zalando")
+	assert.Equal(t, models.SyncStateFailed, status.State)
+	assert.Equal(t, "PUT /product-models/SHOE-001 returned 503", status.Error)
+	assert.Equal(t, 2, status.Attempts)
+	assert.Len(t, status.Errors, 2)
+	assert.Equal(t, int64(0), status.SyncedVersion)
+
+	// Products that do not meet the marketplace requirements fail without being pushed
+	product = testProduct()
+	product.ID = "prod_2"
+	product.Variants = nil
+	syncer.HandleEvent(productEvent(models.EventProductCreated, product))
+	status, _ = statuses.Get("prod_2", "marketplace:zalando")
+	assert.Equal(t, models.SyncStateFailed, status.State)
+	assert.Contains(t, status.Error, "invalid product")
+}
+
+func TestSyncerRemovesProductsLeavingTheSubset(t *testing.T) {
+	statuses := memory.NewSyncStatusRepository()
+	marketplace := &fakeMarketplace{name: "zalando"}
+	syncer := NewSyncer(statuses, marketplace)
+
+	product := testProduct()
+	syncer.HandleEvent(productEvent(models.EventProductCreated, product))
+
+	product = testProduct()
+	product.SKU = "HAT-001"
+	product.Version = 2
+	syncer.HandleEvent(productEvent(models.EventProductUpdated, product))
+
+	status, _ := statuses.Get("prod_1", "marketplace:zalando")
+	assert.Equal(t, models.SyncStateSkipped, status.State)
+	assert.Equal(t, 1, marketplace.removed)
+
+	// Products that were never listed are only recorded as skipped
+	product.ID = "prod_2"
+	syncer.HandleEvent(productEvent(models.EventProductCreated, product))
+	status, _ = statuses.Get("prod_2", "marketplace:zalando")
+	assert.Equal(t, models.SyncStateSkipped, status.State)
+	assert.Equal(t, 1, marketplace.removed)
+}
+
+func TestSyncerRemovesDeletedProducts(t *testing.T) {
+	statuses := memory.NewSyncStatusRepository()
+	marketplace := &fakeMarketplace{name: "zalando"}
+	syncer := NewSyncer(statuses, marketplace)
+
+	product := testProduct()
+	syncer.HandleEvent(productEvent(models.EventProductCreated, product))
+
+	deleted := productEvent(models.EventProductDeleted, product)
+	deleted.Version = 2
+	syncer.HandleEvent(deleted)
+
+	status, _ := statuses.Get("prod_1", "marketplace:zalando")
+	assert.Equal(t, models.SyncStateRemoved, status.State)
+	assert.Equal(t, int64(2), status.Version)
+	assert.Equal(t, 1, marketplace.removed)
+}
+
+func TestSyncerSubscribe(t *testing.T) {
+	publisher := eventsMemory.NewMemoryEventPublisher()
+	statuses := memory.NewSyncStatusRepository()
+	syncer := NewSyncer(statuses, &fakeMarketplace{name: "zalando"})
+	syncer.Subscribe(publisher)
+
+	publisher.Publish(productEvent(models.EventProductCreated, testProduct()))
+	assert.Eventually(t, func() bool {
+		status, err := statuses.Get("prod_1", "marketplace:zalando")
+		return err == nil && status.State == models.SyncStateSynced
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestSyncStatusesFiltersByState(t *testing.T) {
+	statuses := memory.NewSyncStatusRepository()
+	syncer := NewSyncer(statuses, &fakeMarketplace{name: "zalando"}, &fakeMarketplace{name: "amazon"})
+	assert.Equal(t, []string{"amazon", "zalando"}, syncer.Marketplaces())
+
+	listed := testProduct()
+	unlisted := testProduct()
+	unlisted.ID = "prod_2"
+	unlisted.SKU = "HAT-001"
+	syncer.HandleEvent(productEvent(models.EventProductCreated, listed))
+	syncer.HandleEvent(productEvent(models.EventProductCreated, unlisted))
+
+	all, err := syncer.SyncStatuses("zalando", "", 0)
+	assert.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	skipped, err := syncer.SyncStatuses("zalando", models.SyncStateSkipped, 0)
+	assert.NoError(t, err)
+	assert.Len(t, skipped, 1)
+	assert.Equal(t, "prod_2", skipped[0].ProductID)
+
+	_, err = syncer.SyncStatuses("ebay", "", 0)
+	assert.ErrorIs(t, err, models.ErrMarketplaceNotFound)
+}
@@ -0,0 +1,112 @@
+package marketplaces
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// ZalandoProductModel is a product model with one article per variant, the shape
+// Zalando's partner API expects. Categories are mapped to Zalando outlines.
+type ZalandoProductModel struct {
+	MerchantProductModelID string            `json:"merchant_product_model_id"`
+	Outline                string            `json:"outline"`
+	Name                   string            `json:"name"`
+	Description            string            `json:"description"`
+	Price                  ZalandoPrice      `json:"price"`
+	Articles               []*ZalandoArticle `json:"articles"`
+}
+
+// ZalandoPrice is a price in Zalando's format
+type ZalandoPrice struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// ZalandoArticle is a single sellable size or colour of a product model.
+// Price is only set when the article differs from the model price.
+type ZalandoArticle struct {
+	MerchantSKU string            `json:"merchant_sku"`
+	Attributes  map[string]string `json:"attributes"`
+	Quantity    int               `json:"quantity"`
+	Price       *ZalandoPrice     `json:"price,omitempty"`
+}
+
+// Zalando exports products to Zalando
+type Zalando struct {
+	config Config
+	client *apiClient
+}
+
+// NewZalando creates a Zalando adapter
+func NewZalando(config Config) *Zalando {
+	return &Zalando{config: config, client: newAPIClient(config)}
+}
+
+// Name returns "zalando"
+func (z *Zalando) Name() string {
+	return "zalando"
+}
+
+// Build creates the product model. Zalando only sells articles, so products need at least one variant.
+func (z *Zalando) Build(product *models.Product) (interface{}, error) {
+	outline, err := z.config.category(product)
+	if err != nil {
+		return nil, err
+	}
+	if len(product.Variants) == 0 {
+		return nil, fmt.Errorf("product %s has no variants to sell as articles", product.SKU)
+	}
+	price, err := z.config.price(product, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := z.config.checkCompliance(product); err != nil {
+		return nil, err
+	}
+	name, description := z.config.metadata(product)
+
+	model := &ZalandoProductModel{
+		MerchantProductModelID: product.SKU,
+		Outline:                outline,
+		Name:                   name,
+		Description:            description,
+		Price:                  ZalandoPrice{Amount: price.Amount, Currency: price.Currency},
+		Articles:               make([]*ZalandoArticle, 0, len(product.Variants)),
+	}
+	for i, variant := range product.Variants {
+		if err := z.config.checkAttributes(variant); err != nil {
+			return nil, err
+		}
+		attributes := make(map[string]string, len(variant.Attributes))
+		for key, value := range variant.Attributes {
+			attributes[key] = value
+		}
+		article := &ZalandoArticle{
+			MerchantSKU: variant.SKU,
+			Attributes:  attributes,
+			Quantity:    availableQuantity(variant),
+		}
+		if variantPrice, _ := z.config.price(product, &product.Variants[i]); variantPrice.Amount != price.Amount {
+			article.Price = &ZalandoPrice{Amount: variantPrice.Amount, Currency: variantPrice.Currency}
+		}
+		model.Articles = append(model.Articles, article)
+	}
+	return model, nil
+}
+
+// Push puts the product model
+func (z *Zalando) Push(payload interface{}) error {
+	model, ok := payload.(*ZalandoProductModel)
+	if !ok {
+		return fmt.Errorf("unexpected Zalando payload %T", payload)
+	}
+	return z.client.send(http.MethodPut, "/product-models/"+url.PathEscape(model.MerchantProductModelID), model)
+}
+
+// Remove deletes the product model
+func (z *Zalando) Remove(product *models.Product) error {
+	return z.client.send(http.MethodDelete, "/product-models/"+url.PathEscape(product.SKU), nil)
+}
@@ -0,0 +1,87 @@
+package marketplaces
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func zalandoConfig() Config {
+	return Config{
+		Market:             "DE",
+		Currency:           "EUR",
+		Categories:         map[string]string{"SHOE-": "shoes"},
+		RequiredAttributes: []string{"size", "color"},
+	}
+}
+
+func TestZalandoBuildProductModel(t *testing.T) {
+	payload, err := NewZalando(zalandoConfig()).Build(testProduct())
+	assert.NoError(t, err)
+
+	model := payload.(*ZalandoProductModel)
+	assert.Equal(t, "SHOE-001", model.MerchantProductModelID)
+	assert.Equal(t, "shoes", model.Outline)
+	assert.Equal(t, "Laufschuh", model.Name)
+	assert.Equal(t, ZalandoPrice{Amount: 89, Currency: "EUR"}, model.Price)
+	assert.Len(t, model.Articles, 2)
+	assert.Equal(t, "SHOE-001-43", model.Articles[1].MerchantSKU)
+	assert.Equal(t, 5, model.Articles[1].Quantity)
+}
+
+func TestZalandoBuildPricesArticlesThatDiffer(t *testing.T) {
+	product := testProduct()
+	product.Variants[1].Prices = []models.Price{{Currency: "EUR", Amount: 99}, {Currency: "SEK", Amount: 1099}}
+
+	payload, err := NewZalando(zalandoConfig()).Build(product)
+	assert.NoError(t, err)
+
+	model := payload.(*ZalandoProductModel)
+	assert.Nil(t, model.Articles[0].Price)
+	assert.Equal(t, &ZalandoPrice{Amount: 99, Currency: "EUR"}, model.Articles[1].Price)
+}
+
+func TestZalandoRequiresCompliance(t *testing.T) {
+	product := testProduct()
+	product.Compliance = &models.Compliance{RequiredCertifications: map[string][]string{"DE": {"CE"}}}
+
+	_, err := NewZalando(zalandoConfig()).Build(product)
+	assert.ErrorContains(t, err, "certification CE is required in DE")
+}
+
+func TestZalandoRequiresVariants(t *testing.T) {
+	product := testProduct()
+	product.Variants = nil
+	_, err := NewZalando(zalandoConfig()).Build(product)
+	assert.EqualError(t, err, "product SHOE-001 has no variants to sell as articles")
+}
+
+func TestZalandoPushAndRemove(t *testing.T) {
+	var model ZalandoProductModel
+	methods := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/product-models/SHOE-001", r.URL.Path)
+		methods = append(methods, r.Method)
+		if r.Method == http.MethodPut {
+			json.NewDecoder(r.Body).Decode(&model)
+		}
+	}))
+	defer server.Close()
+
+	config := zalandoConfig()
+	config.BaseURL = server.URL
+	zalando := NewZalando(config)
+
+	payload, err := zalando.Build(testProduct())
+	assert.NoError(t, err)
+	assert.NoError(t, zalando.Push(payload))
+	assert.NoError(t, zalando.Remove(testProduct()))
+
+	assert.Equal(t, []string{http.MethodPut, http.MethodDelete}, methods)
+	assert.Equal(t, "shoes", model.Outline)
+	assert.Len(t, model.Articles, 2)
+}
@@ -0,0 +1,83 @@
+package media
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Defaults of local storage
+const (
+	DefaultDir     = "media"
+	DefaultBaseURL = "http://localhost:8080/media"
+)
+
+// LocalStorage keeps binaries in a directory, to be served from BaseURL.
+// The server mounts the directory at /media/ when local storage is used.
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+// NewLocalStorage creates a storage writing to dir, which is created if needed
+func NewLocalStorage(dir, baseURL string) (*LocalStorage, error) {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %v", err)
+	}
+	return &LocalStorage{dir: dir, baseURL: strings.TrimSuffix(baseURL, "/")}, nil
+}
+
+func (s *LocalStorage) Name() string {
+	return StorageLocal
+}
+
+// Dir returns the directory binaries are written to
+func (s *LocalStorage) Dir() string {
+	return s.dir
+}
+
+// Put writes the binary to a temporary file first, so a partial upload is
+// never served
+func (s *LocalStorage) Put(key, contentType string, body io.Reader) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	target := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+	if _, err := io.Copy(file, body); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(file.Name(), target); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/" + key, nil
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key))); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package media
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	storage, err := NewLocalStorage(dir, "https://shop.example.com/media/")
+	assert.NoError(t, err)
+
+	url, err := storage.Put("products/p1/a.png", "image/png", strings.NewReader("binary"))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://shop.example.com/media/products/p1/a.png", url)
+	data, err := os.ReadFile(filepath.Join(dir, "products", "p1", "a.png"))
+	assert.NoError(t, err)
+	assert.Equal(t, "binary", string(data))
+
+	// No temporary files are left behind
+	entries, _ := os.ReadDir(filepath.Join(dir, "products", "p1"))
+	assert.Len(t, entries, 1)
+
+	assert.NoError(t, storage.Delete("products/p1/a.png"))
+	_, err = os.Stat(filepath.Join(dir, "products", "p1", "a.png"))
+	assert.True(t, os.IsNotExist(err))
+	assert.NoError(t, storage.Delete("products/p1/a.png"))
+
+	_, err = storage.Put("../escape.png", "image/png", strings.NewReader("binary"))
+	assert.Error(t, err)
+	assert.Error(t, storage.Delete("../escape.png"))
+}
+
+func TestLocalStorageDefaults(t *testing.T) {
+	storage, err := NewLocalStorage(filepath.Join(t.TempDir(), "media"), "")
+	assert.NoError(t, err)
+	url, err := storage.Put("a.png", "image/png", strings.NewReader("binary"))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultBaseURL+"/a.png", url)
+}
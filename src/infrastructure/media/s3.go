@@ -0,0 +1,195 @@
+package media
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds a request to the bucket
+const requestTimeout = 30 * time.Second
+
+// S3Storage keeps binaries in an S3 bucket. Requests are signed with AWS
+// Signature Version 4, so S3 compatible stores such as MinIO work as well.
+type S3Storage struct {
+	bucket          string
+	region          string
+	endpoint        string // Scheme and host requests go to
+	pathStyle       bool   // Whether the bucket is the first path segment rather than part of the host
+	baseURL         string
+	accessKeyID     string
+	secretAccessKey string
+	http            *http.Client
+	now             func() time.Time
+}
+
+// NewS3Storage creates a storage for the bucket. Without an endpoint the
+// bucket is addressed on AWS as https://<bucket>.s3.<region>.amazonaws.com.
+func NewS3Storage(config StorageConfig) (*S3Storage, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("s3 media storage needs a bucket")
+	}
+	if config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 media storage needs an access key ID and a secret access key")
+	}
+	storage := &S3Storage{
+		bucket:          config.Bucket,
+		region:          config.Region,
+		accessKeyID:     config.AccessKeyID,
+		secretAccessKey: config.SecretAccessKey,
+		http:            &http.Client{Timeout: requestTimeout},
+		now:             time.Now,
+	}
+	if storage.region == "" {
+		storage.region = "us-east-1"
+	}
+	if config.Endpoint != "" {
+		endpoint, err := url.Parse(config.Endpoint)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+		}
+		storage.endpoint = endpoint.Scheme + "://" + endpoint.Host
+		storage.pathStyle = true
+	} else {
+		storage.endpoint = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", storage.bucket, storage.region)
+	}
+	storage.baseURL = strings.TrimSuffix(config.BaseURL, "/")
+	if storage.baseURL == "" {
+		storage.baseURL = storage.endpoint
+		if storage.pathStyle {
+			storage.baseURL += "/" + storage.bucket
+		}
+	}
+	return storage, nil
+}
+
+func (s *S3Storage) Name() string {
+	return StorageS3
+}
+
+// Put uploads the binary as an object. The body is read into memory to sign
+// its hash; uploads are bounded by the handler.
+func (s *S3Storage) Put(key, contentType string, body io.Reader) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	request, err := http.NewRequest(http.MethodPut, s.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	if err := s.do(request, data, http.StatusOK); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/" + escapePath(key), nil
+}
+
+// Delete removes the object; S3 answers deletes of missing keys with 204 too
+func (s *S3Storage) Delete(key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+	return s.do(request, nil, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+// objectURL returns the URL requests for an object go to
+func (s *S3Storage) objectURL(key string) string {
+	if s.pathStyle {
+		return s.endpoint + "/" + escapePath(s.bucket) + "/" + escapePath(key)
+	}
+	return s.endpoint + "/" + escapePath(key)
+}
+
+// do signs and sends a request, expecting one of the status codes
+func (s *S3Storage) do(request *http.Request, payload []byte, expected ...int) error {
+	s.sign(request, payload)
+	response, err := s.http.Do(request)
+	if err != nil {
+		return fmt.Errorf("s3 %s failed: %v", request.Method, err)
+	}
+	defer response.Body.Close()
+	for _, code := range expected {
+		if response.StatusCode == code {
+			return nil
+		}
+	}
+	message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+	return fmt.Errorf("s3 %s returned %d: %s", request.Method, response.StatusCode, strings.TrimSpace(string(message)))
+}
+
+// sign adds the Signature Version 4 authorization of the request, signing
+// the host, the payload hash and the request time
+func (s *S3Storage) sign(request *http.Request, payload []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+	request.Header.Set("X-Amz-Date", amzDate)
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		request.URL.RawQuery,
+		"host:" + request.URL.Host + "\n" +
+			"x-amz-content-sha256:" + payloadHash + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	request.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// escapePath escapes each segment of a key the way Signature Version 4
+// expects: everything but unreserved characters is percent-encoded
+func escapePath(key string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '.', c == '_', c == '~', c == '/':
+			escaped.WriteByte(c)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
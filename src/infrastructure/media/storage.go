@@ -0,0 +1,49 @@
+// Package media provides the storages uploaded product images are kept in:
+// a directory on local disk or an S3 compatible bucket.
+package media
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+)
+
+// Storage names
+const (
+	StorageLocal = "local"
+	StorageS3    = "s3"
+)
+
+// StorageConfig selects and configures an asset storage
+type StorageConfig struct {
+	Name    string // local (default) or s3
+	Dir     string // Directory of local storage, "media" by default
+	BaseURL string // URL stored binaries are served from; local storage defaults to DefaultBaseURL
+
+	Bucket          string // S3 bucket
+	Region          string // S3 region, us-east-1 by default
+	Endpoint        string // S3 compatible endpoint such as http://localhost:9000, addressed path-style
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+// NewStorage creates the configured storage
+func NewStorage(config StorageConfig) (interfaces.AssetStorage, error) {
+	switch config.Name {
+	case "", StorageLocal:
+		return NewLocalStorage(config.Dir, config.BaseURL)
+	case StorageS3:
+		return NewS3Storage(config)
+	}
+	return nil, fmt.Errorf("unknown media storage %q, expected local or s3", config.Name)
+}
+
+// checkKey rejects keys that would escape the storage, such as "../x" or "/x"
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package media
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStorage(t *testing.T) {
+	storage, err := NewStorage(StorageConfig{Dir: t.TempDir()})
+	assert.NoError(t, err)
+	assert.Equal(t, StorageLocal, storage.Name())
+
+	storage, err = NewStorage(StorageConfig{Name: StorageS3, Bucket: "assets", AccessKeyID: "id", SecretAccessKey: "secret"})
+	assert.NoError(t, err)
+	assert.Equal(t, StorageS3, storage.Name())
+
+	_, err = NewStorage(StorageConfig{Name: StorageS3})
+	assert.Error(t, err)
+	_, err = NewStorage(StorageConfig{Name: "ftp"})
+	assert.Error(t, err)
+}
+
+func TestCheckKey(t *testing.T) {
+	assert.NoError(t, checkKey("products/p1/a.png"))
+	for _, key := range []string{"", "/etc/passwd", "../a.png", "products/../../a.png", "products//a.png", ".."} {
+		assert.Error(t, checkKey(key), key)
+	}
+}
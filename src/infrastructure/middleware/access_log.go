@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"math/rand"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+)
+
+// AccessLogOptions selects the requests written to the access log
+type AccessLogOptions struct {
+	// SampleRate is the share of requests to SampledRoutes that are logged,
+	// between 0 and 1
+	SampleRate float64
+	// SampledRoutes are the route templates, such as "/products", whose
+	// requests are sampled; requests to other routes are always logged
+	SampledRoutes []string
+}
+
+// AccessLogMiddleware logs every completed request as one "HTTP request"
+// entry with its method, path, route, status, duration, body sizes and
+// client IP. Requests to sampled routes are logged at the sample rate, except
+// responses with a 5xx status which are always logged. Added after
+// RequestLoggerMiddleware, entries carry the request ID. It must be added with
+// Router.Use so the matched route is known.
+func AccessLogMiddleware(options AccessLogOptions) func(http.Handler) http.Handler {
+	sampled := make(map[string]bool, len(options.SampledRoutes))
+	for _, route := range options.SampledRoutes {
+		sampled[route] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r)
+
+			status := sw.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			template := ""
+			if route := mux.CurrentRoute(r); route != nil {
+				template, _ = route.GetPathTemplate()
+			}
+			if sampled[template] && status < http.StatusInternalServerError && rand.Float64() >= options.SampleRate {
+				return
+			}
+
+			logging.FromContextOrShared(r.Context()).Info("HTTP request",
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("route", template),
+				zap.Int("status", status),
+				zap.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+				zap.Int64("request_bytes", r.ContentLength),
+				zap.Int("response_bytes", sw.bytes),
+				zap.String("client_ip", clientIP(r)),
+			)
+		})
+	}
+}
+
+// clientIP returns the address of the connection a request arrived on
+// without its port
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+)
+
+// accessLogRouter serves the product routes behind the request logger and
+// access log, recording the entries logged
+func accessLogRouter(options AccessLogOptions) (*mux.Router, *observer.ObservedLogs) {
+	core, recorded := observer.New(zap.InfoLevel)
+	router := mux.NewRouter()
+	router.Use(RequestLoggerMiddleware(&logging.Logger{Logger: zap.New(core)}))
+	router.Use(AccessLogMiddleware(options))
+	router.HandleFunc("/products", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"data":[]}`))
+	}).Methods("GET", "POST")
+	router.HandleFunc("/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	return router, recorded
+}
+
+func TestAccessLogMiddlewareLogsRequests(t *testing.T) {
+	router, recorded := accessLogRouter(AccessLogOptions{})
+
+	req := httptest.NewRequest("POST", "/products", strings.NewReader(`{"sku":"SKU-1"}`))
+	req.RemoteAddr = "192.168.1.1:51234"
+	req.Header.Set(RequestIDHeader, "req-123")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := recorded.FilterMessage("HTTP request").All()
+	if assert.Len(t, entries, 1) {
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "POST", fields["method"])
+		assert.Equal(t, "/products", fields["path"])
+		assert.Equal(t, "/products", fields["route"])
+		assert.Equal(t, int64(http.StatusOK), fields["status"])
+		assert.Equal(t, int64(15), fields["request_bytes"])
+		assert.Equal(t, int64(11), fields["response_bytes"])
+		assert.Equal(t, "192.168.1.1", fields["client_ip"])
+		assert.Equal(t, "req-123", fields["request_id"])
+		assert.Contains(t, fields, "duration_ms")
+	}
+}
+
+func TestAccessLogMiddlewareSamplesRoutes(t *testing.T) {
+	router, recorded := accessLogRouter(AccessLogOptions{SampleRate: 0, SampledRoutes: []string{"/products"}})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/products", nil))
+	assert.Empty(t, recorded.All(), "sampled route is skipped at rate 0")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/products?fail=1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/products/prod_1", nil))
+
+	entries := recorded.All()
+	if assert.Len(t, entries, 2, "server errors and other routes are always logged") {
+		assert.Equal(t, int64(http.StatusInternalServerError), entries[0].ContextMap()["status"])
+		assert.Equal(t, "/products/{id}", entries[1].ContextMap()["route"])
+	}
+}
+
+func TestAccessLogMiddlewareFullSampleRate(t *testing.T) {
+	router, recorded := accessLogRouter(AccessLogOptions{SampleRate: 1, SampledRoutes: []string{"/products"}})
+
+	for i := 0; i < 3; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/products", nil))
+	}
+	assert.Len(t, recorded.All(), 3)
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// APIKeyHeader is the header API keys are sent in
+const APIKeyHeader = "X-API-Key"
+
+// ErrInvalidAPIKey is returned for requests with an unknown API key
+var ErrInvalidAPIKey = errors.New("invalid API key")
+
+// APIKeyAuthenticator establishes callers from a static list of API keys, e.g.
+// the keys handed out to storefronts. Every key grants the same roles.
+type APIKeyAuthenticator struct {
+	keys  [][]byte
+	roles []string
+}
+
+// NewAPIKeyAuthenticator creates an authenticator accepting the keys, granting the roles
+func NewAPIKeyAuthenticator(keys []string, roles ...string) *APIKeyAuthenticator {
+	authenticator := &APIKeyAuthenticator{roles: roles}
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			authenticator.keys = append(authenticator.keys, []byte(key))
+		}
+	}
+	return authenticator
+}
+
+// Authenticate returns the caller of a request with a known API key, nil
+// without a key and ErrInvalidAPIKey for any other key
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*models.Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+
+	// Compare against every key so the time taken does not reveal which one matched
+	matched := 0
+	for _, known := range a.keys {
+		matched |= subtle.ConstantTimeCompare([]byte(key), known)
+	}
+	if matched != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// The subject identifies the key in logs without revealing it
+	sum := sha256.Sum256([]byte(key))
+	return &models.Principal{
+		Subject: "api-key:" + hex.EncodeToString(sum[:])[:12],
+		Roles:   append([]string(nil), a.roles...),
+		Method:  models.AuthMethodAPIKey,
+	}, nil
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	authenticator := NewAPIKeyAuthenticator([]string{"storefront-key", " ", "app-key"}, models.RolePublic)
+
+	req := httptest.NewRequest("GET", "/public/products", nil)
+	principal, err := authenticator.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Nil(t, principal, "requests without a key are left to the next authenticator")
+
+	req.Header.Set(APIKeyHeader, "app-key")
+	principal, err = authenticator.Authenticate(req)
+	assert.NoError(t, err)
+	if assert.NotNil(t, principal) {
+		assert.True(t, strings.HasPrefix(principal.Subject, "api-key:"))
+		assert.NotContains(t, principal.Subject, "app-key")
+		assert.Equal(t, []string{models.RolePublic}, principal.Roles)
+		assert.Equal(t, models.AuthMethodAPIKey, principal.Method)
+	}
+
+	req.Header.Set(APIKeyHeader, "storefront")
+	principal, err = authenticator.Authenticate(req)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+	assert.Nil(t, principal)
+}
+
+func TestRequireAuthWithAPIKey(t *testing.T) {
+	var seen *models.Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = PrincipalFromContext(r.Context())
+	})
+	handler := RequireAuth("/public/", nil, NewAPIKeyAuthenticator([]string{"storefront-key"}, models.RolePublic))(next)
+
+	tests := []struct {
+		name           string
+		key            string
+		expectedStatus int
+	}{
+		{"Known key", "storefront-key", http.StatusOK},
+		{"Unknown key", "guess", http.StatusUnauthorized},
+		{"No key", "", http.StatusUnauthorized},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			seen = nil
+			req := httptest.NewRequest("GET", "/public/products", nil)
+			if tc.key != "" {
+				req.Header.Set(APIKeyHeader, tc.key)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedStatus == http.StatusOK {
+				assert.True(t, seen.HasRole(models.RolePublic))
+			}
+		})
+	}
+}
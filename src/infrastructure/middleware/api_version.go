@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// APIVersionHeader is the request header a client picks its API version with.
+// Responses repeat the version they were written in.
+const APIVersionHeader = "API-Version"
+
+// API versions. Version 1 is the original, per-endpoint response shapes and
+// stays the default so existing clients are unaffected; version 2 wraps every
+// response in models.Envelope.
+const (
+	APIVersion1      = 1
+	APIVersion2      = 2
+	LatestAPIVersion = APIVersion2
+)
+
+// RequestedAPIVersion returns the API version a request asks for, defaulting
+// to version 1. Unknown versions are an error.
+func RequestedAPIVersion(r *http.Request) (int, error) {
+	value := strings.TrimSpace(r.Header.Get(APIVersionHeader))
+	if value == "" {
+		return APIVersion1, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil || version < APIVersion1 || version > LatestAPIVersion {
+		return 0, fmt.Errorf("unsupported %s %q, expected 1 to %d", APIVersionHeader, value, LatestAPIVersion)
+	}
+	return version, nil
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestedAPIVersion(t *testing.T) {
+	req := httptest.NewRequest("GET", "/products", nil)
+	version, err := RequestedAPIVersion(req)
+	assert.NoError(t, err)
+	assert.Equal(t, APIVersion1, version)
+
+	req.Header.Set(APIVersionHeader, " 2 ")
+	version, err = RequestedAPIVersion(req)
+	assert.NoError(t, err)
+	assert.Equal(t, APIVersion2, version)
+
+	for _, invalid := range []string{"0", "3", "v2", "2.0"} {
+		req.Header.Set(APIVersionHeader, invalid)
+		_, err := RequestedAPIVersion(req)
+		assert.Error(t, err, invalid)
+	}
+}
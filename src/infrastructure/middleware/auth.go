@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+	"go.uber.org/zap"
+)
+
+type contextKey string
+
+const principalKey = contextKey("principal")
+
+// Authenticator establishes the caller of a request from one kind of
+// credential, e.g. an OIDC session cookie. It returns nil without an error
+// when the request carries no credential of its kind, so the next
+// authenticator can try.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*models.Principal, error)
+}
+
+// WithPrincipal adds the authenticated caller to a context
+func WithPrincipal(ctx context.Context, principal *models.Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// PrincipalFromContext returns the authenticated caller, or nil for anonymous requests
+func PrincipalFromContext(ctx context.Context) *models.Principal {
+	principal, _ := ctx.Value(principalKey).(*models.Principal)
+	return principal
+}
+
+// RequireAuth rejects requests to paths under prefix unless one of the
+// authenticators establishes a principal, which handlers can read with
+// PrincipalFromContext. The request context also gets a logger carrying the
+// caller, read with logging.FromContext. Paths under an exempt prefix are
+// passed through, e.g. routes with their own authentication.
+func RequireAuth(prefix string, exempt []string, authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !strings.HasPrefix(r.URL.Path, prefix) || hasAnyPrefix(r.URL.Path, exempt) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, authenticator := range authenticators {
+				principal, err := authenticator.Authenticate(r)
+				if err != nil {
+					respond.Error(w, http.StatusUnauthorized, "Unauthorized")
+					return
+				}
+				if principal != nil {
+					next.ServeHTTP(w, r.WithContext(WithCaller(r.Context(), principal)))
+					return
+				}
+			}
+			respond.Error(w, http.StatusUnauthorized, "Unauthorized")
+		})
+	}
+}
+
+// WithCaller adds the principal and a logger identifying it to a context. The
+// logger is derived from the request's logger, so it keeps the request ID.
+func WithCaller(ctx context.Context, principal *models.Principal) context.Context {
+	logger := logging.FromContextOrShared(ctx).WithUserID(principal.Subject).WithFields(zap.String("auth_method", principal.Method))
+	return logging.WithContext(WithPrincipal(ctx, principal), logger)
+}
+
+// hasAnyPrefix reports whether a path starts with one of the prefixes
+func hasAnyPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+)
+
+// DefaultExempt are the paths that never need product API credentials: the
+// public documentation, the version probe, the metrics scrape, the login
+// flow, the admin documentation and public API, which authenticate callers
+// themselves, and the product images in local media storage
+var DefaultExempt = []string{"/swagger/", "/version", "/metrics", "/auth/", "/admin/swagger/", "/public/", "/media/"}
+
+// Config configures authentication of the product API
+type Config struct {
+	// JWT enables bearer tokens when set
+	JWT *JWTConfig
+	// APIKeys are accepted in the X-API-Key header, each granting APIKeyRoles
+	APIKeys     []string
+	APIKeyRoles []string
+	// Exempt are path prefixes opted out of authentication besides DefaultExempt
+	Exempt []string
+}
+
+// Middleware returns middleware rejecting requests without a valid bearer
+// token or API key, except to exempt paths. The principal is added to the
+// request context together with a logger carrying the caller's ID.
+func Middleware(config Config) (func(http.Handler) http.Handler, error) {
+	authenticators, err := config.Authenticators()
+	if err != nil {
+		return nil, err
+	}
+	return middleware.RequireAuth("/", config.ExemptPaths(), authenticators...), nil
+}
+
+// Authenticators returns the bearer token and API key authenticators of the
+// configuration, for routes that accept them next to their own credentials
+func (c Config) Authenticators() ([]middleware.Authenticator, error) {
+	var authenticators []middleware.Authenticator
+	if c.JWT != nil {
+		jwt, err := NewJWTAuthenticator(*c.JWT)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, jwt)
+	}
+	if len(c.APIKeys) > 0 {
+		authenticators = append(authenticators, middleware.NewAPIKeyAuthenticator(c.APIKeys, c.APIKeyRoles...))
+	}
+	if len(authenticators) == 0 {
+		return nil, errors.New("auth: configure a JWT key or API keys")
+	}
+	return authenticators, nil
+}
+
+// ExemptPaths returns DefaultExempt and the configured exemptions
+func (c Config) ExemptPaths() []string {
+	return append(append([]string(nil), DefaultExempt...), c.Exempt...)
+}
@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+)
+
+func TestMiddleware(t *testing.T) {
+	authMiddleware, err := Middleware(Config{
+		JWT:         &JWTConfig{Secret: testSecret},
+		APIKeys:     []string{"feed-key"},
+		APIKeyRoles: []string{"viewer"},
+		Exempt:      []string{"/ws"},
+	})
+	assert.NoError(t, err)
+
+	var method string
+	handler := authMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = ""
+		if principal := middleware.PrincipalFromContext(r.Context()); principal != nil {
+			method = principal.Method
+		}
+	}))
+
+	tests := []struct {
+		name           string
+		path           string
+		header         string
+		value          string
+		expectedStatus int
+		expectedMethod string
+	}{
+		{"Bearer token", "/products", "Authorization", "Bearer " + signHS256(t, testSecret, "HS256", validClaims()), http.StatusOK, "jwt"},
+		{"API key", "/products", middleware.APIKeyHeader, "feed-key", http.StatusOK, "api_key"},
+		{"Unknown API key", "/products", middleware.APIKeyHeader, "other", http.StatusUnauthorized, ""},
+		{"Anonymous", "/products", "", "", http.StatusUnauthorized, ""},
+		{"Swagger", "/swagger/index.html", "", "", http.StatusOK, ""},
+		{"Admin API", "/admin/diagnostics", "", "", http.StatusUnauthorized, ""},
+		{"Admin API with API key", "/admin/diagnostics", middleware.APIKeyHeader, "feed-key", http.StatusOK, "api_key"},
+		{"Configured exemption", "/ws", "", "", http.StatusOK, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			method = ""
+			req := httptest.NewRequest("GET", tc.path, nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			assert.Equal(t, tc.expectedMethod, method)
+		})
+	}
+}
+
+func TestMiddlewareNeedsCredentials(t *testing.T) {
+	_, err := Middleware(Config{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+// Package auth authenticates callers of the product API with JWT bearer
+// tokens and static API keys. Both are middleware.Authenticators, so the
+// principal they establish is read with middleware.PrincipalFromContext.
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// defaultLeeway is how far the issuer's clock may be ahead of or behind ours
+const defaultLeeway = time.Minute
+
+// ErrInvalidToken is returned for bearer tokens that fail verification
+var ErrInvalidToken = errors.New("invalid bearer token")
+
+// JWTConfig configures bearer token verification. Exactly one of Secret
+// (HS256) and PublicKey (RS256) is set; tokens signed with any other
+// algorithm are rejected.
+type JWTConfig struct {
+	Secret    []byte
+	PublicKey *rsa.PublicKey
+	// Issuer and Audience are checked against iss and aud when set
+	Issuer   string
+	Audience string
+	// RolesClaim names the claim holding the caller's roles, default "roles"
+	RolesClaim string
+	Leeway     time.Duration
+}
+
+// JWTAuthenticator establishes callers from signed bearer tokens
+type JWTAuthenticator struct {
+	config JWTConfig
+	now    func() time.Time
+}
+
+// NewJWTAuthenticator creates an authenticator verifying tokens as configured
+func NewJWTAuthenticator(config JWTConfig) (*JWTAuthenticator, error) {
+	if (len(config.Secret) == 0) == (config.PublicKey == nil) {
+		return nil, errors.New("auth: set either a JWT secret or a public key")
+	}
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+	if config.Leeway == 0 {
+		config.Leeway = defaultLeeway
+	}
+	return &JWTAuthenticator{config: config, now: time.Now}, nil
+}
+
+// ParsePublicKey reads an RSA public key from PEM, as a PKIX or PKCS #1 key
+func ParsePublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("auth: no PEM block in public key")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("auth: invalid public key: %v", err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("auth: public key is not an RSA key")
+	}
+	return key, nil
+}
+
+// Authenticate returns the caller of a request with a valid bearer token, nil
+// without one and an error wrapping ErrInvalidToken for any other token
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*models.Principal, error) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return nil, nil
+	}
+
+	claims, err := a.verify(strings.TrimSpace(token))
+	if err != nil {
+		return nil, err
+	}
+	sub, _ := claims["sub"].(string)
+	name, _ := claims["name"].(string)
+	email, _ := claims["email"].(string)
+	return &models.Principal{
+		Subject: sub,
+		Name:    name,
+		Email:   email,
+		Roles:   stringList(claims[a.config.RolesClaim]),
+		Method:  models.AuthMethodJWT,
+	}, nil
+}
+
+// verify checks a token's signature, expiry, issuer and audience, and returns its claims
+func (a *JWTAuthenticator) verify(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+	if err := a.verifySignature(header.Alg, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	now := a.now()
+	exp, hasExp := claims["exp"].(float64)
+	if !hasExp || now.Add(-a.config.Leeway).Unix() >= int64(exp) {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(a.config.Leeway).Unix() < int64(nbf) {
+		return nil, fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	if a.config.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != a.config.Issuer {
+			return nil, fmt.Errorf("%w: issued by %q", ErrInvalidToken, iss)
+		}
+	}
+	if a.config.Audience != "" && !hasAudience(claims["aud"], a.config.Audience) {
+		return nil, fmt.Errorf("%w: not issued for this API", ErrInvalidToken)
+	}
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+// verifySignature checks the signature with the configured key. The algorithm
+// must be the one the key is for, so an HS256 token cannot be signed with the
+// RSA public key as its secret.
+func (a *JWTAuthenticator) verifySignature(alg, signed string, signature []byte) error {
+	switch {
+	case alg == "HS256" && len(a.config.Secret) > 0:
+		mac := hmac.New(sha256.New, a.config.Secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	case alg == "RS256" && a.config.PublicKey != nil:
+		digest := sha256.Sum256([]byte(signed))
+		if err := rsa.VerifyPKCS1v15(a.config.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, alg)
+}
+
+// decodeSegment decodes a base64url JSON segment of a token
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("%w: malformed segment", ErrInvalidToken)
+	}
+	return nil
+}
+
+// hasAudience reports whether an aud claim, a string or a list, names the audience
+func hasAudience(aud interface{}, audience string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == audience
+	case []interface{}:
+		for _, entry := range aud {
+			if entry == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// stringList reads a claim that holds a list of strings, or a single string
+func stringList(claim interface{}) []string {
+	switch claim := claim.(type) {
+	case string:
+		return []string{claim}
+	case []interface{}:
+		values := make([]string, 0, len(claim))
+		for _, entry := range claim {
+			if value, ok := entry.(string); ok {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+	return []string{}
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+var testSecret = []byte("test-secret")
+
+// signHS256 builds a token signed with a secret
+func signHS256(t *testing.T, secret []byte, alg string, claims map[string]interface{}) string {
+	t.Helper()
+	signed := encodeSegment(t, map[string]string{"alg": alg, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signRS256 builds a token signed with an RSA key
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims map[string]interface{}) string {
+	t.Helper()
+	signed := encodeSegment(t, map[string]string{"alg": "RS256", "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func encodeSegment(t *testing.T, value interface{}) string {
+	data, err := json.Marshal(value)
+	assert.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "svc-feed",
+		"email": "feed@example.com",
+		"iss":   "https://auth.example.com",
+		"aud":   []string{"catalog"},
+		"exp":   float64(time.Now().Add(time.Hour).Unix()),
+		"roles": []string{"editor"},
+	}
+}
+
+func TestJWTAuthenticatorHS256(t *testing.T) {
+	authenticator, err := NewJWTAuthenticator(JWTConfig{Secret: testSecret, Issuer: "https://auth.example.com", Audience: "catalog"})
+	assert.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/products", nil)
+	req.Header.Set("Authorization", "Bearer "+signHS256(t, testSecret, "HS256", validClaims()))
+	principal, err := authenticator.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, &models.Principal{
+		Subject: "svc-feed",
+		Email:   "feed@example.com",
+		Roles:   []string{"editor"},
+		Method:  models.AuthMethodJWT,
+	}, principal)
+}
+
+func TestJWTAuthenticatorWithoutToken(t *testing.T) {
+	authenticator, _ := NewJWTAuthenticator(JWTConfig{Secret: testSecret})
+
+	for _, header := range []string{"", "Basic YWxpY2U6c2VjcmV0"} {
+		req := httptest.NewRequest("GET", "/products", nil)
+		req.Header.Set("Authorization", header)
+		principal, err := authenticator.Authenticate(req)
+		assert.NoError(t, err)
+		assert.Nil(t, principal)
+	}
+}
+
+func TestJWTAuthenticatorRejectsInvalidTokens(t *testing.T) {
+	authenticator, _ := NewJWTAuthenticator(JWTConfig{Secret: testSecret, Issuer: "https://auth.example.com", Audience: "catalog"})
+
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+	tests := map[string]string{
+		"Malformed":           "not-a-token",
+		"Wrong secret":        signHS256(t, []byte("other"), "HS256", validClaims()),
+		"Unsigned":            signHS256(t, testSecret, "none", validClaims()),
+		"Expired":             signHS256(t, testSecret, "HS256", with("exp", float64(time.Now().Add(-time.Hour).Unix()))),
+		"No expiry":           signHS256(t, testSecret, "HS256", with("exp", nil)),
+		"Not valid yet":       signHS256(t, testSecret, "HS256", with("nbf", float64(time.Now().Add(time.Hour).Unix()))),
+		"Other issuer":        signHS256(t, testSecret, "HS256", with("iss", "https://evil.example.com")),
+		"Other audience":      signHS256(t, testSecret, "HS256", with("aud", "billing")),
+		"No subject":          signHS256(t, testSecret, "HS256", with("sub", nil)),
+		"RS256 without a key": signHS256(t, testSecret, "RS256", validClaims()),
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/products", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			principal, err := authenticator.Authenticate(req)
+			assert.True(t, errors.Is(err, ErrInvalidToken), err)
+			assert.Nil(t, principal)
+		})
+	}
+}
+
+func TestJWTAuthenticatorRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	publicKey, err := ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	assert.NoError(t, err)
+
+	authenticator, _ := NewJWTAuthenticator(JWTConfig{PublicKey: publicKey})
+	req := httptest.NewRequest("GET", "/products", nil)
+	req.Header.Set("Authorization", "Bearer "+signRS256(t, key, validClaims()))
+	principal, err := authenticator.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "svc-feed", principal.Subject)
+
+	// The public key must not work as an HS256 secret
+	req.Header.Set("Authorization", "Bearer "+signHS256(t, der, "HS256", validClaims()))
+	_, err = authenticator.Authenticate(req)
+	assert.True(t, errors.Is(err, ErrInvalidToken))
+}
+
+func TestNewJWTAuthenticatorNeedsOneKey(t *testing.T) {
+	_, err := NewJWTAuthenticator(JWTConfig{})
+	assert.Error(t, err)
+	_, err = NewJWTAuthenticator(JWTConfig{Secret: testSecret, PublicKey: &rsa.PublicKey{}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"go.uber.org/zap"
+)
+
+// headerAuthenticator accepts requests with a known X-Test-User header
+type headerAuthenticator struct{}
+
+func (headerAuthenticator) Authenticate(r *http.Request) (*models.Principal, error) {
+	switch user := r.Header.Get("X-Test-User"); user {
+	case "":
+		return nil, nil
+	case "alice":
+		return &models.Principal{Subject: user, Roles: []string{"admin"}}, nil
+	default:
+		return nil, errors.New("unknown user")
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	var seen *models.Principal
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RequireAuth("/admin/", []string{"/admin/swagger/"}, headerAuthenticator{})(next)
+
+	tests := []struct {
+		name           string
+		path           string
+		user           string
+		expectedStatus int
+		expectedUser   string
+	}{
+		{"Authenticated", "/admin/dashboard/jobs", "alice", http.StatusOK, "alice"},
+		{"Anonymous", "/admin/dashboard/jobs", "", http.StatusUnauthorized, ""},
+		{"Invalid credential", "/admin/dashboard/jobs", "mallory", http.StatusUnauthorized, ""},
+		{"Other prefix", "/products", "", http.StatusOK, ""},
+		{"Exempt prefix", "/admin/swagger/index.html", "", http.StatusOK, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			seen = nil
+			req := httptest.NewRequest("GET", tc.path, nil)
+			if tc.user != "" {
+				req.Header.Set("X-Test-User", tc.user)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedUser != "" {
+				assert.Equal(t, tc.expectedUser, seen.Subject)
+			} else {
+				assert.Nil(t, seen)
+			}
+		})
+	}
+}
+
+func TestRequireAuthAddsCallerLogger(t *testing.T) {
+	var logged bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Without a logger in the context FromContext returns a no-op logger
+		logged = logging.FromContext(r.Context()).Core().Enabled(zap.ErrorLevel)
+	})
+	handler := RequireAuth("/admin/", nil, headerAuthenticator{})(next)
+
+	req := httptest.NewRequest("GET", "/admin/dashboard/jobs", nil)
+	req.Header.Set("X-Test-User", "alice")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, logged)
+}
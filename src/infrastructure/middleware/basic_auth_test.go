@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicAuthMiddleware(t *testing.T) {
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := BasicAuthMiddleware("admin-docs", "admin", "secret")(nextHandler)
+
+	tests := []struct {
+		name           string
+		setAuth        bool
+		username       string
+		password       string
+		expectedStatus int
+	}{
+		{"Valid credentials", true, "admin", "secret", http.StatusOK},
+		{"Wrong password", true, "admin", "wrong", http.StatusUnauthorized},
+		{"Wrong username", true, "root", "secret", http.StatusUnauthorized},
+		{"Missing credentials", false, "", "", http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/admin/swagger/index.html", nil)
+			if tc.setAuth {
+				req.SetBasicAuth(tc.username, tc.password)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="admin-docs"`, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// BodyLimitMiddleware caps request bodies at maxBytes, or at the limit of
+// their route in routes, keyed by "METHOD /path/{template}", for endpoints
+// accepting larger uploads such as images and CSV files. Requests announcing
+// a larger Content-Length get 413 Request Entity Too Large right away; other
+// bodies fail to read past the limit, which handlers decoding them report as
+// 413.
+func BodyLimitMiddleware(maxBytes int64, routes map[string]int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := maxBytes
+			if routeLimit, ok := routes[r.Method+" "+routeTemplate(r)]; ok {
+				limit = routeLimit
+			}
+			if r.ContentLength > limit {
+				respond.Error(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body is larger than %d bytes", limit))
+				return
+			}
+			if r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, limit)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	handler := BodyLimitMiddleware(8, map[string]int64{"POST /products/{id}/images": 64})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, serve(httptest.NewRequest("POST", "/products", strings.NewReader("[1,2]"))).Code)
+
+	// A declared length over the limit is rejected before the handler
+	rec := serve(httptest.NewRequest("POST", "/products", strings.NewReader(`["a","b","c"]`)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.JSONEq(t, `{"code":413,"error_code":"request_too_large","message":"Request body is larger than 8 bytes"}`, rec.Body.String())
+
+	// Bodies without a length stop reading at the limit
+	req := httptest.NewRequest("POST", "/products", io.NopCloser(strings.NewReader(`["a","b","c"]`)))
+	req.ContentLength = -1
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(req).Code)
+
+	// A multipart content type gets no limit of its own
+	req = httptest.NewRequest("POST", "/products", strings.NewReader(strings.Repeat("x", 64)))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(req).Code)
+	req = httptest.NewRequest("POST", "/products", io.NopCloser(strings.NewReader(strings.Repeat("x", 64))))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	req.ContentLength = -1
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(req).Code)
+}
+
+func TestBodyLimitMiddlewareRouteLimits(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(BodyLimitMiddleware(8, map[string]int64{"POST /products/{id}/images": 64}))
+	read := func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	router.HandleFunc("/products/{id}/images", read).Methods("POST")
+	router.HandleFunc("/products/{id}/images/order", read).Methods("PUT")
+
+	serve := func(method, path string, size int) int {
+		req := httptest.NewRequest(method, path, io.NopCloser(strings.NewReader(strings.Repeat("x", size))))
+		req.ContentLength = -1
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Uploads are read up to their route's limit
+	assert.Equal(t, http.StatusOK, serve("POST", "/products/prod_1/images", 64))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve("POST", "/products/prod_1/images", 65))
+	// Other routes keep the default limit
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve("PUT", "/products/prod_1/images/order", 64))
+}
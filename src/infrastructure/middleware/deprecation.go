@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/infrastructure/deprecation"
+)
+
+// DeprecationMiddleware announces the deprecations of the matched route and
+// counts their use. Deprecated routes get a Deprecation header (RFC 9745) and,
+// once a removal date is set, a Sunset header (RFC 8594); deprecated response
+// fields get a Warning header, since the route itself stays. Both link to the
+// migration notes when there are any. Deprecations limited to older API
+// versions are skipped for clients already on a newer one. Requests to legacy
+// paths, see VersionedPathMiddleware, also get the deprecations of legacy
+// paths and a successor-version Link to the versioned path. It must be added
+// with Router.Use so the matched route is known.
+func DeprecationMiddleware(registry *deprecation.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := mux.CurrentRoute(r)
+			if route == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			template, err := route.GetPathTemplate()
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			client := deprecationClient(r)
+			version, _ := RequestedAPIVersion(r)
+			deprecations := registry.Lookup(r.Method + " " + template)
+			if IsLegacyPath(r.Context()) {
+				deprecations = registry.LookupLegacyPath(r.Method + " " + template)
+				if len(deprecations) > 0 {
+					w.Header().Add("Link", fmt.Sprintf(`</v%d%s>; rel="successor-version"`, version, r.URL.RequestURI()))
+				}
+			}
+			for _, d := range deprecations {
+				if d.MaxVersion != 0 && version > d.MaxVersion {
+					continue
+				}
+				header := w.Header()
+				if d.Field == "" {
+					header.Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+					if !d.Sunset.IsZero() {
+						header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+					}
+				} else {
+					message := fmt.Sprintf("%s is deprecated: %s", d.Field, d.Description)
+					if d.Replacement != "" {
+						message += ". " + d.Replacement
+					}
+					header.Add("Warning", fmt.Sprintf("299 - %q", message))
+				}
+				if d.Link != "" {
+					header.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"`, d.Link))
+				}
+				registry.Record(d.ID, client)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// deprecationClient names the caller of a request for the usage report: the
+// authenticated principal, or the user agent for anonymous callers
+func deprecationClient(r *http.Request) string {
+	if principal := PrincipalFromContext(r.Context()); principal != nil {
+		return principal.Subject
+	}
+	if agent := r.UserAgent(); agent != "" {
+		return agent
+	}
+	return "unknown"
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/deprecation"
+)
+
+func TestDeprecationMiddleware(t *testing.T) {
+	since := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	registry, err := deprecation.NewRegistry(
+		deprecation.Deprecation{
+			ID:     "legacy-export",
+			Routes: []string{"GET /export"},
+			Since:  since,
+			Sunset: time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC),
+			Link:   "https://docs.example.com/migrations/export",
+		},
+		deprecation.Deprecation{
+			ID:          "envelope",
+			Routes:      []string{"PUT /items/{id}"},
+			Field:       "success",
+			Description: "responses are wrapped",
+			Replacement: "Read data",
+			Since:       since,
+		},
+	)
+	assert.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.Use(DeprecationMiddleware(registry))
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/export", ok).Methods("GET")
+	router.HandleFunc("/items/{id}", ok).Methods("PUT", "GET")
+
+	// A deprecated route
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/export", nil)
+	req.Header.Set("User-Agent", "feed-builder/2.1")
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, "@1790812800", rr.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", rr.Header().Get("Sunset"))
+	assert.Equal(t, `<https://docs.example.com/migrations/export>; rel="deprecation"`, rr.Header().Get("Link"))
+
+	// A deprecated field of a route that stays
+	rr = httptest.NewRecorder()
+	req = httptest.NewRequest("PUT", "/items/1", nil)
+	req = req.WithContext(WithPrincipal(req.Context(), &models.Principal{Subject: "api-key:abc"}))
+	router.ServeHTTP(rr, req)
+	assert.Empty(t, rr.Header().Get("Deprecation"))
+	assert.Equal(t, `299 - "success is deprecated: responses are wrapped. Read data"`, rr.Header().Get("Warning"))
+
+	// Other methods on the same path are not deprecated
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/items/1", nil))
+	assert.Empty(t, rr.Header().Get("Warning"))
+
+	usage := registry.DeprecationUsage()
+	assert.Equal(t, int64(1), usage[0].Requests)
+	assert.Equal(t, map[string]int64{"feed-builder/2.1": 1}, usage[0].Clients)
+	assert.Equal(t, map[string]int64{"api-key:abc": 1}, usage[1].Clients)
+}
+
+func TestDeprecationMiddlewareSkipsNewerAPIVersions(t *testing.T) {
+	registry, err := deprecation.NewRegistry(deprecation.Deprecation{
+		ID:          "envelope",
+		Routes:      []string{"PUT /items/{id}"},
+		Field:       "success",
+		Description: "responses are wrapped",
+		Since:       time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+		MaxVersion:  APIVersion1,
+	})
+	assert.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.Use(DeprecationMiddleware(registry))
+	router.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {}).Methods("PUT")
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("PUT", "/items/1", nil)
+	req.Header.Set(APIVersionHeader, "2")
+	router.ServeHTTP(rr, req)
+	assert.Empty(t, rr.Header().Get("Warning"))
+	assert.Equal(t, int64(0), registry.DeprecationUsage()[0].Requests)
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("PUT", "/items/1", nil))
+	assert.NotEmpty(t, rr.Header().Get("Warning"))
+	assert.Equal(t, int64(1), registry.DeprecationUsage()[0].Requests)
+}
+
+func TestDeprecationMiddlewareLegacyPaths(t *testing.T) {
+	registry, err := deprecation.NewRegistry(deprecation.Deprecation{
+		ID:          "unversioned-paths",
+		LegacyPaths: true,
+		Since:       time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+		Sunset:      time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC),
+	})
+	assert.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.Use(DeprecationMiddleware(registry))
+	router.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+	handler := VersionedPathMiddleware([]string{"items"})(router)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/items/1?fields=id", nil))
+	assert.Equal(t, "@1790812800", rr.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", rr.Header().Get("Sunset"))
+	assert.Equal(t, `</v1/items/1?fields=id>; rel="successor-version"`, rr.Header().Get("Link"))
+
+	// The successor keeps the version picked with the header
+	rr = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/items/1", nil)
+	req.Header.Set(APIVersionHeader, "2")
+	handler.ServeHTTP(rr, req)
+	assert.Equal(t, `</v2/items/1>; rel="successor-version"`, rr.Header().Get("Link"))
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/v1/items/1", nil))
+	assert.Empty(t, rr.Header().Get("Deprecation"))
+	assert.Empty(t, rr.Header().Get("Link"))
+	assert.Equal(t, int64(2), registry.DeprecationUsage()[0].Requests)
+}
@@ -0,0 +1,177 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// EnvelopeMiddleware serves API version 2 by rewriting the response of the
+// wrapped handler into models.Envelope. Handlers keep writing the version 1
+// shapes, so both versions are served by the same code:
+//
+//   - errors ({"code", "message"} or a plain text body) become errors
+//   - {"success": true, "data": ...} becomes data
+//   - listings ({"data", "page", "total_items", ...}) become data and meta
+//   - any other JSON body becomes data as it is
+//
+// Requests without an API-Version header get version 1 unchanged. Bodies that
+// are not JSON, such as documentation pages and file exports, and WebSocket
+// upgrades pass through in both versions; successful ones are streamed.
+func EnvelopeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", APIVersionHeader)
+
+		version, err := RequestedAPIVersion(r)
+		if err != nil {
+			writeEnvelope(w, http.StatusBadRequest, &models.Envelope{
+				Errors: []models.EnvelopeError{{Status: http.StatusBadRequest, Code: respond.CodeInvalidRequest, Message: err.Error()}},
+			})
+			return
+		}
+		w.Header().Set(APIVersionHeader, strconv.Itoa(version))
+		if version == APIVersion1 || strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferedWriter{target: w, header: make(http.Header)}
+		next.ServeHTTP(bw, r)
+		if bw.streaming {
+			return
+		}
+
+		status := bw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		envelope := toEnvelope(status, bw.header.Get("Content-Type"), bw.body.Bytes())
+		bw.copyHeader()
+		if envelope == nil {
+			w.WriteHeader(status)
+			w.Write(bw.body.Bytes())
+			return
+		}
+		writeEnvelope(w, status, envelope)
+	})
+}
+
+// bufferedWriter holds a response back so it can be rewritten. Successful
+// responses that are not JSON are never rewritten, so they are streamed to
+// the target as soon as they are written instead.
+type bufferedWriter struct {
+	target    http.ResponseWriter
+	header    http.Header
+	status    int
+	body      bytes.Buffer
+	streaming bool
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.streaming {
+		contentType := w.header.Get("Content-Type")
+		if w.status >= http.StatusBadRequest || contentType == "" || strings.HasPrefix(contentType, "application/json") {
+			return w.body.Write(b)
+		}
+		w.copyHeader()
+		w.target.WriteHeader(w.status)
+		w.streaming = true
+	}
+	return w.target.Write(b)
+}
+
+// Flush forwards flushes of streamed responses
+func (w *bufferedWriter) Flush() {
+	if flusher, ok := w.target.(http.Flusher); w.streaming && ok {
+		flusher.Flush()
+	}
+}
+
+// copyHeader copies the held back header to the target. The length is left
+// out since rewriting changes it.
+func (w *bufferedWriter) copyHeader() {
+	for key, values := range w.header {
+		if key == "Content-Length" {
+			continue
+		}
+		w.target.Header()[key] = values
+	}
+}
+
+// toEnvelope converts a version 1 response into an envelope, or returns nil
+// when the response is passed through as it is
+func toEnvelope(status int, contentType string, body []byte) *models.Envelope {
+	trimmed := bytes.TrimSpace(body)
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		return nil
+	}
+	isJSON := strings.HasPrefix(contentType, "application/json") && json.Valid(trimmed)
+
+	if status >= http.StatusBadRequest {
+		message := strings.TrimSpace(string(trimmed))
+		code := respond.StatusCode(status)
+		if isJSON {
+			var failure models.APIError
+			if json.Unmarshal(trimmed, &failure) == nil && failure.Message != "" {
+				message = failure.Message
+			}
+			if failure.Code != "" {
+				code = failure.Code
+			}
+			if len(failure.Errors) > 0 {
+				errs := make([]models.EnvelopeError, len(failure.Errors))
+				for i, field := range failure.Errors {
+					errs[i] = models.EnvelopeError{Status: status, Code: code, Field: field.Field, Message: field.Message}
+				}
+				return &models.Envelope{Errors: errs}
+			}
+		}
+		if message == "" {
+			message = http.StatusText(status)
+		}
+		return &models.Envelope{Errors: []models.EnvelopeError{{Status: status, Code: code, Message: message}}}
+	}
+
+	if len(trimmed) == 0 || !isJSON {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(trimmed, &fields) == nil {
+		if _, ok := fields["success"]; ok && len(fields) == 2 && fields["data"] != nil {
+			return &models.Envelope{Data: fields["data"]}
+		}
+		if _, ok := fields["total_items"]; ok && fields["data"] != nil {
+			var meta models.EnvelopeMeta
+			if json.Unmarshal(trimmed, &meta) == nil {
+				return &models.Envelope{Data: fields["data"], Meta: &meta}
+			}
+		}
+	}
+	return &models.Envelope{Data: json.RawMessage(trimmed)}
+}
+
+// writeEnvelope writes an envelope as the response body
+func writeEnvelope(w http.ResponseWriter, status int, envelope *models.Envelope) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(APIVersionHeader, strconv.Itoa(APIVersion2))
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(envelope)
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// serveEnvelope runs a handler behind the envelope middleware
+func serveEnvelope(handler http.HandlerFunc, version string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/products", nil)
+	if version != "" {
+		req.Header.Set(APIVersionHeader, version)
+	}
+	EnvelopeMiddleware(handler).ServeHTTP(rr, req)
+	return rr
+}
+
+// writeJSON returns a handler writing a JSON body with the status
+func writeJSON(status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}
+}
+
+func TestEnvelopeMiddlewareKeepsVersion1(t *testing.T) {
+	rr := serveEnvelope(writeJSON(http.StatusOK, `{"success":true,"data":{"id":"1"}}`), "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"success":true,"data":{"id":"1"}}`, rr.Body.String())
+	assert.Equal(t, "1", rr.Header().Get(APIVersionHeader))
+	assert.Equal(t, APIVersionHeader, rr.Header().Get("Vary"))
+}
+
+func TestEnvelopeMiddlewareWrapsVersion2(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		status   int
+		expected string
+	}{
+		{
+			name:     "bare resource",
+			handler:  writeJSON(http.StatusOK, `{"id":"1","sku":"SKU-1"}`),
+			status:   http.StatusOK,
+			expected: `{"data":{"id":"1","sku":"SKU-1"}}`,
+		},
+		{
+			name:     "success wrapper",
+			handler:  writeJSON(http.StatusOK, `{"success":true,"data":{"id":"1"}}`),
+			status:   http.StatusOK,
+			expected: `{"data":{"id":"1"}}`,
+		},
+		{
+			name:     "listing",
+			handler:  writeJSON(http.StatusOK, `{"data":[{"id":"1"}],"page":2,"page_size":1,"total_items":3,"total_pages":3}`),
+			status:   http.StatusOK,
+			expected: `{"data":[{"id":"1"}],"meta":{"page":2,"page_size":1,"total_items":3,"total_pages":3}}`,
+		},
+		{
+			name:     "array",
+			handler:  writeJSON(http.StatusAccepted, `[{"id":"1","success":true}]`),
+			status:   http.StatusAccepted,
+			expected: `{"data":[{"id":"1","success":true}]}`,
+		},
+		{
+			name:     "JSON error",
+			handler:  writeJSON(http.StatusNotFound, `{"code":404,"error_code":"product_not_found","message":"Product not found"}`),
+			status:   http.StatusNotFound,
+			expected: `{"data":null,"errors":[{"status":404,"code":"product_not_found","message":"Product not found"}]}`,
+		},
+		{
+			name:     "validation error",
+			handler:  writeJSON(http.StatusUnprocessableEntity, `{"code":422,"error_code":"validation_failed","message":"Validation failed","errors":[{"field":"sku","tag":"required","message":"sku is required"}]}`),
+			status:   http.StatusUnprocessableEntity,
+			expected: `{"data":null,"errors":[{"status":422,"code":"validation_failed","field":"sku","message":"sku is required"}]}`,
+		},
+		{
+			name: "plain text error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			},
+			status:   http.StatusUnauthorized,
+			expected: `{"data":null,"errors":[{"status":401,"code":"unauthorized","message":"Unauthorized"}]}`,
+		},
+		{
+			name: "error without body",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte("\n"))
+			},
+			status:   http.StatusTooManyRequests,
+			expected: `{"data":null,"errors":[{"status":429,"code":"rate_limited","message":"Too Many Requests"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serveEnvelope(tt.handler, "2")
+			assert.Equal(t, tt.status, rr.Code)
+			assert.JSONEq(t, tt.expected, rr.Body.String())
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			assert.Equal(t, "2", rr.Header().Get(APIVersionHeader))
+		})
+	}
+}
+
+func TestEnvelopeMiddlewarePassesThrough(t *testing.T) {
+	// Responses without a body
+	rr := serveEnvelope(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		w.WriteHeader(http.StatusNoContent)
+	}, "2")
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	assert.Empty(t, rr.Body.String())
+	assert.Equal(t, `"abc"`, rr.Header().Get("ETag"))
+
+	// Bodies that are not JSON
+	rr = serveEnvelope(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html></html>"))
+	}, "2")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "<html></html>", rr.Body.String())
+	assert.Equal(t, "text/html", rr.Header().Get("Content-Type"))
+}
+
+func TestEnvelopeMiddlewareStreamsFiles(t *testing.T) {
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/products/export", nil)
+	req.Header.Set(APIVersionHeader, "2")
+	EnvelopeMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Write([]byte("sku\n"))
+		w.(http.Flusher).Flush()
+
+		// The first rows reach the client before the handler is done
+		assert.Equal(t, "sku\n", rr.Body.String())
+		assert.True(t, rr.Flushed)
+		w.Write([]byte("SHIRT-1\n"))
+	})).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "sku\nSHIRT-1\n", rr.Body.String())
+	assert.Equal(t, "text/csv", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "2", rr.Header().Get(APIVersionHeader))
+}
+
+func TestEnvelopeMiddlewareRejectsUnknownVersions(t *testing.T) {
+	called := false
+	rr := serveEnvelope(func(w http.ResponseWriter, r *http.Request) { called = true }, "9")
+	assert.False(t, called)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	var body struct {
+		Errors []struct {
+			Status  int    `json:"status"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(rr.Body.Bytes(), &body))
+	assert.Len(t, body.Errors, 1)
+	assert.Equal(t, http.StatusBadRequest, body.Errors[0].Status)
+	assert.Contains(t, body.Errors[0].Message, "unsupported API-Version")
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// LatencyRecorder receives the duration of every completed request per route
+type LatencyRecorder interface {
+	RecordLatency(route string, duration time.Duration)
+}
+
+// LatencyMiddleware records how long each request took under its route name, the
+// method and path template such as "GET /products/{id}". It must be added with
+// Router.Use so the matched route is known. WebSocket upgrades are not recorded
+// since their duration is the lifetime of the connection.
+func LatencyMiddleware(recorder LatencyRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r)
+
+			if sw.status == http.StatusSwitchingProtocols {
+				return
+			}
+			route := mux.CurrentRoute(r)
+			if route == nil {
+				return
+			}
+			template, err := route.GetPathTemplate()
+			if err != nil {
+				return
+			}
+			recorder.RecordLatency(r.Method+" "+template, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockLatencyRecorder struct {
+	mu     sync.Mutex
+	routes []string
+}
+
+func (m *mockLatencyRecorder) RecordLatency(route string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routes = append(m.routes, route)
+}
+
+func TestLatencyMiddlewareRecordsRouteTemplate(t *testing.T) {
+	recorder := &mockLatencyRecorder{}
+	router := mux.NewRouter()
+	router.Use(LatencyMiddleware(recorder))
+	router.HandleFunc("/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}).Methods("GET")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/products/prod_1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/products/prod_2", nil))
+	// Unmatched requests never reach route middleware
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/unknown", nil))
+
+	assert.Equal(t, []string{"GET /products/{id}", "GET /products/{id}"}, recorder.routes)
+}
+
+func TestLatencyMiddlewareSkipsUpgrades(t *testing.T) {
+	recorder := &mockLatencyRecorder{}
+	router := mux.NewRouter()
+	router.Use(LatencyMiddleware(recorder))
+	router.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusSwitchingProtocols)
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ws", nil))
+	assert.Empty(t, recorder.routes)
+}
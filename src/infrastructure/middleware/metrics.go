@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+// MetricsMiddleware records the duration of each request in the
+// http_request_duration_seconds histogram by route template, method and
+// status. Like LatencyMiddleware it must be added with Router.Use, and
+// WebSocket upgrades and requests that matched no route are not recorded.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+
+		if sw.status == http.StatusSwitchingProtocols {
+			return
+		}
+		route := mux.CurrentRoute(r)
+		if route == nil {
+			return
+		}
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return
+		}
+		status := sw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		metrics.HTTPRequestDuration.WithLabelValues(template, r.Method, strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+)
+
+// requestCount returns how many requests were recorded for a route, method and status
+func requestCount(t *testing.T, route, method, status string) uint64 {
+	observer, err := metrics.HTTPRequestDuration.GetMetricWithLabelValues(route, method, status)
+	assert.NoError(t, err)
+	metric := &dto.Metric{}
+	assert.NoError(t, observer.(prometheus.Histogram).Write(metric))
+	return metric.Histogram.GetSampleCount()
+}
+
+func TestMetricsMiddlewareRecordsRouteAndStatus(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(MetricsMiddleware)
+	router.HandleFunc("/metrics-test/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("ok"))
+	}).Methods("GET")
+	router.HandleFunc("/metrics-test-ws", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusSwitchingProtocols)
+	})
+
+	found := requestCount(t, "/metrics-test/{id}", "GET", "200")
+	missing := requestCount(t, "/metrics-test/{id}", "GET", "404")
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics-test/prod_1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics-test/prod_2", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics-test/missing", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/metrics-test-ws", nil))
+
+	assert.Equal(t, found+2, requestCount(t, "/metrics-test/{id}", "GET", "200"))
+	assert.Equal(t, missing+1, requestCount(t, "/metrics-test/{id}", "GET", "404"))
+	assert.Zero(t, requestCount(t, "/metrics-test-ws", "GET", "101"))
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// legacyPathKey marks requests to a versioned resource without a version prefix
+const legacyPathKey = contextKey("legacy_path")
+
+// VersionedPathMiddleware serves the resources, top-level path segments such
+// as "products", under /v1 and /v2. The prefix is removed before routing, so
+// routes, their templates and everything keyed on them stay the same, and
+// picks the API version like the API-Version header, which it overrides.
+// Requests to the resources without a prefix are legacy aliases, served as
+// before and marked for DeprecationMiddleware. It must wrap the router, since
+// routes are matched before Router.Use middleware runs.
+func VersionedPathMiddleware(resources []string) func(http.Handler) http.Handler {
+	versioned := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		versioned[resource] = true
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			version, rest, ok := splitPathVersion(r.URL.Path)
+			if ok && versioned[pathResource(rest)] {
+				r = r.Clone(r.Context())
+				r.URL.Path = rest
+				if r.URL.RawPath != "" {
+					_, r.URL.RawPath, _ = splitPathVersion(r.URL.RawPath)
+				}
+				r.Header.Set(APIVersionHeader, strconv.Itoa(version))
+			} else if versioned[pathResource(r.URL.Path)] {
+				r = r.WithContext(context.WithValue(r.Context(), legacyPathKey, true))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsLegacyPath reports whether a request reached a versioned resource
+// without a version prefix
+func IsLegacyPath(ctx context.Context) bool {
+	legacy, _ := ctx.Value(legacyPathKey).(bool)
+	return legacy
+}
+
+// splitPathVersion splits "/v2/products" into version 2 and "/products".
+// Paths without a known version prefix are not split.
+func splitPathVersion(path string) (int, string, bool) {
+	segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	for version := APIVersion1; version <= LatestAPIVersion; version++ {
+		if segment == "v"+strconv.Itoa(version) {
+			return version, "/" + rest, true
+		}
+	}
+	return 0, path, false
+}
+
+// pathResource returns the first segment of a path
+func pathResource(path string) string {
+	resource, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return resource
+}
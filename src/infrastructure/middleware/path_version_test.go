@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionedPathMiddleware(t *testing.T) {
+	type served struct {
+		template string
+		version  int
+		legacy   bool
+	}
+	var got served
+	router := mux.NewRouter()
+	record := func(w http.ResponseWriter, r *http.Request) {
+		template, _ := mux.CurrentRoute(r).GetPathTemplate()
+		version, _ := RequestedAPIVersion(r)
+		got = served{template: template, version: version, legacy: IsLegacyPath(r.Context())}
+	}
+	router.HandleFunc("/products/{id}", record)
+	router.HandleFunc("/metrics", record)
+	handler := VersionedPathMiddleware([]string{"products"})(router)
+
+	tests := []struct {
+		path     string
+		header   string
+		wantCode int
+		want     served
+	}{
+		{path: "/v1/products/p1", wantCode: http.StatusOK, want: served{"/products/{id}", 1, false}},
+		{path: "/v2/products/p1", wantCode: http.StatusOK, want: served{"/products/{id}", 2, false}},
+		{path: "/v1/products/p1", header: "2", wantCode: http.StatusOK, want: served{"/products/{id}", 1, false}},
+		{path: "/products/p1", wantCode: http.StatusOK, want: served{"/products/{id}", 1, true}},
+		{path: "/products/p1", header: "2", wantCode: http.StatusOK, want: served{"/products/{id}", 2, true}},
+		{path: "/metrics", wantCode: http.StatusOK, want: served{"/metrics", 1, false}},
+		{path: "/v3/products/p1", wantCode: http.StatusNotFound},
+		{path: "/v1/metrics", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got = served{}
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(APIVersionHeader, tt.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
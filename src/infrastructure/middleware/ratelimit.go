@@ -1,23 +1,135 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/jimmitjoo/ecom/src/infrastructure/ratelimit"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
 )
 
+// RateLimitMiddleware limits every caller with the same limiter. Callers are
+// keyed like in TieredRateLimitMiddleware, and limiters reporting quotas get
+// the same headers.
 func RateLimitMiddleware(limiter ratelimit.RateLimiter) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Use IP as the key for rate limiting
-			key := r.RemoteAddr
+			key := rateLimitKey(r)
+			if quotas, ok := limiter.(ratelimit.QuotaLimiter); ok {
+				allowed, quota := quotas.Take(key)
+				if !writeRateLimit(w, allowed, quota) {
+					return
+				}
+			} else if !limiter.Allow(key) {
+				respond.Error(w, http.StatusTooManyRequests, "Rate limit exceeded")
+				return
+			}
 
-			if !limiter.Allow(key) {
-				http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientRateLimitMiddleware limits each client IP in the tier of anonymous
+// callers. It runs before authentication, so requests failing it count too
+// and credentials cannot be guessed faster than anonymous callers may call.
+// Its buckets are apart from those of TieredRateLimitMiddleware, which then
+// limits the authenticated callers in their own tiers.
+func ClientRateLimitMiddleware(limiter *ratelimit.TieredLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			allowed, quota := limiter.Take(limiter.TierFor("", nil), "client:"+clientIP(r))
+			if !allowed {
+				writeRateLimit(w, allowed, quota)
 				return
 			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TieredRateLimitMiddleware limits each caller by its tier. Authenticated
+// callers are keyed by their principal, so every API key or user has its own
+// quota wherever it calls from; anonymous callers are keyed by client IP.
+// Requests to a route with a policy in the registry, which may be nil, count
+// against the policy too, and the headers report the tighter of the two
+// quotas. It must run after authentication to see the principal and as
+// router middleware to see the route.
+func TieredRateLimitMiddleware(limiter *ratelimit.TieredLimiter, policies *ratelimit.PolicyRegistry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var tier string
+			if principal := PrincipalFromContext(r.Context()); principal != nil {
+				tier = limiter.TierFor(principal.Subject, principal.Roles)
+			} else {
+				tier = limiter.TierFor("", nil)
+			}
 
+			key := rateLimitKey(r)
+			allowed, quota := limiter.Take(tier, key)
+			if policy, ok := routePolicy(r, policies); ok && allowed {
+				var routeQuota ratelimit.Quota
+				allowed, routeQuota = policy.Take(key)
+				if !allowed || routeQuota.Remaining < quota.Remaining {
+					quota = routeQuota
+				}
+			}
+			if !writeRateLimit(w, allowed, quota) {
+				return
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
 }
+
+// routePolicy returns the limiter of the policy for the request's route
+func routePolicy(r *http.Request, policies *ratelimit.PolicyRegistry) (*ratelimit.TokenBucketLimiter, bool) {
+	if policies.Len() == 0 {
+		return nil, false
+	}
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return nil, false
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return nil, false
+	}
+	return policies.Lookup(r.Method, template)
+}
+
+// rateLimitKey identifies the caller of a request: the principal's subject,
+// or the client IP without the port for anonymous requests
+func rateLimitKey(r *http.Request) string {
+	if principal := PrincipalFromContext(r.Context()); principal != nil {
+		return "principal:" + principal.Subject
+	}
+	return "ip:" + clientIP(r)
+}
+
+// writeRateLimit sets the X-RateLimit headers of the caller's quota and, for
+// a rejected request, writes 429 Too Many Requests with Retry-After. It
+// returns whether the request may go on.
+func writeRateLimit(w http.ResponseWriter, allowed bool, quota ratelimit.Quota) bool {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(quota.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(quota.Remaining))
+	w.Header().Set("X-RateLimit-Reset", seconds(quota.Reset))
+	if allowed {
+		return true
+	}
+	w.Header().Set("Retry-After", seconds(quota.RetryAfter))
+	respond.Error(w, http.StatusTooManyRequests, "Rate limit exceeded")
+	return false
+}
+
+// seconds formats a duration in whole seconds, rounded up so a client
+// waiting that long is never early
+func seconds(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
+	"github.com/jimmitjoo/ecom/src/domain/models"
 	"github.com/jimmitjoo/ecom/src/infrastructure/ratelimit"
 	"github.com/stretchr/testify/assert"
 )
@@ -141,3 +143,145 @@ func TestRateLimitMiddlewareReset(t *testing.T) {
 	handler.ServeHTTP(rec, req)
 	assert.Equal(t, http.StatusOK, rec.Code, "Request should succeed after the window has passed")
 }
+
+func TestRateLimitMiddlewareHeaders(t *testing.T) {
+	handler := RateLimitMiddleware(ratelimit.NewTokenBucketLimiter(1, 2))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	// Connections from one IP share a quota whatever their port
+	var rec *httptest.ResponseRecorder
+	for _, addr := range []string{"192.168.1.1:1234", "192.168.1.1:5678"} {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = addr
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Reset"))
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "192.168.1.1:9999"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+}
+
+func TestTieredRateLimitMiddleware(t *testing.T) {
+	assignments, err := ratelimit.ParseAssignments("api-key:partner=partner")
+	assert.NoError(t, err)
+	limiter, err := ratelimit.NewTieredLimiter([]ratelimit.Tier{
+		{Name: ratelimit.DefaultTier, Rate: 5, Burst: 5},
+		{Name: ratelimit.AnonymousTier, Rate: 1, Burst: 1},
+		{Name: "partner", Rate: 100, Burst: 100},
+	}, assignments)
+	assert.NoError(t, err)
+	handler := TieredRateLimitMiddleware(limiter, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	request := func(principal *models.Principal) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/products", nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		if principal != nil {
+			req = req.WithContext(WithPrincipal(req.Context(), principal))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, request(nil).Code)
+	anonymous := request(nil)
+	assert.Equal(t, http.StatusTooManyRequests, anonymous.Code)
+	assert.Equal(t, "1", anonymous.Header().Get("X-RateLimit-Limit"))
+
+	// Authenticated callers from the same IP have their own quota in their tier
+	partner := request(&models.Principal{Subject: "api-key:partner"})
+	assert.Equal(t, http.StatusOK, partner.Code)
+	assert.Equal(t, "100", partner.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "99", partner.Header().Get("X-RateLimit-Remaining"))
+
+	user := request(&models.Principal{Subject: "user-1", Roles: []string{models.RoleViewer}})
+	assert.Equal(t, http.StatusOK, user.Code)
+	assert.Equal(t, "5", user.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestTieredRateLimitMiddlewareRoutePolicies(t *testing.T) {
+	limiter, err := ratelimit.NewTieredLimiter([]ratelimit.Tier{{Name: ratelimit.DefaultTier, Rate: 10, Burst: 10}}, ratelimit.Assignments{})
+	assert.NoError(t, err)
+	policies, err := ratelimit.NewPolicyRegistry([]ratelimit.RoutePolicy{{Method: "POST", Route: "/products/batch", Rate: 1, Burst: 2}})
+	assert.NoError(t, err)
+
+	router := mux.NewRouter()
+	router.Use(TieredRateLimitMiddleware(limiter, policies))
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/products/batch", ok).Methods("POST")
+	router.HandleFunc("/products/{id}", ok).Methods("GET")
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The route's quota is tighter than the tier's and is the one reported
+	batch := request("POST", "/products/batch")
+	assert.Equal(t, http.StatusOK, batch.Code)
+	assert.Equal(t, "2", batch.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", batch.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, http.StatusOK, request("POST", "/products/batch").Code)
+	batch = request("POST", "/products/batch")
+	assert.Equal(t, http.StatusTooManyRequests, batch.Code)
+	assert.Equal(t, "1", batch.Header().Get("Retry-After"))
+
+	// Other routes only count against the tier, which the batch calls used too
+	product := request("GET", "/products/prod_1")
+	assert.Equal(t, http.StatusOK, product.Code)
+	assert.Equal(t, "10", product.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "6", product.Header().Get("X-RateLimit-Remaining"))
+}
+
+func TestClientRateLimitMiddlewareCountsRejectedCredentials(t *testing.T) {
+	limiter, err := ratelimit.NewTieredLimiter([]ratelimit.Tier{
+		{Name: ratelimit.DefaultTier, Rate: 100, Burst: 100},
+		{Name: ratelimit.AnonymousTier, Rate: 0.001, Burst: 3},
+	}, ratelimit.Assignments{})
+	assert.NoError(t, err)
+	authenticator := NewAPIKeyAuthenticator([]string{"secret"}, models.RoleViewer)
+
+	router := mux.NewRouter()
+	router.Use(ClientRateLimitMiddleware(limiter))
+	router.Use(RequireAuth("/", nil, authenticator))
+	router.Use(TieredRateLimitMiddleware(limiter, nil))
+	router.HandleFunc("/products", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	request := func(key, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/products", nil)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Guessed keys are rejected until the client runs out of its quota
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusUnauthorized, request("guess", "10.0.0.1").Code)
+	}
+	limited := request("guess", "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.NotEmpty(t, limited.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusTooManyRequests, request("secret", "10.0.0.1").Code)
+
+	// Other clients keep their own quota, and valid keys their tier's quota
+	valid := request("secret", "10.0.0.2")
+	assert.Equal(t, http.StatusOK, valid.Code)
+	assert.Equal(t, "100", valid.Header().Get("X-RateLimit-Limit"))
+}
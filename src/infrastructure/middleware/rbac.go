@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+)
+
+// roleRanks orders the product API roles; a role grants the access of every lower rank
+var roleRanks = map[string]int{
+	models.RoleViewer: 1,
+	models.RoleEditor: 2,
+	models.RoleAdmin:  3,
+}
+
+// AccessPolicy decides the role a request needs. Routes are named by method
+// and path template, e.g. "DELETE /products/batch".
+type AccessPolicy struct {
+	ReadRole  string            // Role for GET, HEAD and OPTIONS requests
+	WriteRole string            // Role for any other method
+	Routes    map[string]string // Role per route, overriding the others
+	Prefixes  map[string]string // Role for any method on the routes under a path prefix, the longest one counting, overriding ReadRole and WriteRole
+}
+
+// DefaultAccessPolicy lets viewers read, editors create, update and delete
+// single products, and only admins delete in bulk, roll jobs back, manage
+// webhooks and call the admin API
+func DefaultAccessPolicy() AccessPolicy {
+	return AccessPolicy{
+		ReadRole:  models.RoleViewer,
+		WriteRole: models.RoleEditor,
+		Routes: map[string]string{
+			"DELETE /products/batch":      models.RoleAdmin,
+			"DELETE /tags/{tag}/products": models.RoleAdmin,
+			"POST /jobs/{id}/rollback":    models.RoleAdmin,
+			"POST /webhooks":              models.RoleAdmin,
+			"DELETE /webhooks/{id}":       models.RoleAdmin,
+			"PUT /webhooks/{id}/state":    models.RoleAdmin,
+		},
+		Prefixes: map[string]string{
+			"/admin/": models.RoleAdmin,
+		},
+	}
+}
+
+// RequiredRole returns the role needed for a method on a route
+func (p AccessPolicy) RequiredRole(method, route string) string {
+	if role, exists := p.Routes[method+" "+route]; exists {
+		return role
+	}
+	matched, role := "", ""
+	for prefix, prefixRole := range p.Prefixes {
+		if strings.HasPrefix(route, prefix) && len(prefix) > len(matched) {
+			matched, role = prefix, prefixRole
+		}
+	}
+	if matched != "" {
+		return role
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return p.ReadRole
+	}
+	return p.WriteRole
+}
+
+// HasAccess reports whether a principal holds the role or a higher one
+func HasAccess(principal *models.Principal, role string) bool {
+	if role == "" {
+		return true
+	}
+	if principal == nil {
+		return false
+	}
+	required, ranked := roleRanks[role]
+	for _, granted := range principal.Roles {
+		if granted == role || (ranked && roleRanks[granted] >= required) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authorize rejects requests whose principal lacks the role the policy
+// requires for the route with 403. It runs after RequireAuth, which
+// establishes the principal; requests to exempt prefixes and anonymous
+// requests RequireAuth let through are passed on. Routes are read from mux, so
+// it is installed with Router.Use; outside a router the request path is used.
+func Authorize(policy AccessPolicy, exempt []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal := PrincipalFromContext(r.Context())
+			if principal == nil || hasAnyPrefix(r.URL.Path, exempt) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !HasAccess(principal, policy.RequiredRole(r.Method, routeTemplate(r))) {
+				respond.Error(w, http.StatusForbidden, "Forbidden")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeTemplate returns the path template of the matched route, or the request path
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestHasAccess(t *testing.T) {
+	viewer := &models.Principal{Roles: []string{models.RoleViewer}}
+	editor := &models.Principal{Roles: []string{models.RoleEditor}}
+	admin := &models.Principal{Roles: []string{models.RoleAdmin}}
+	auditor := &models.Principal{Roles: []string{"auditor"}}
+
+	assert.True(t, HasAccess(viewer, models.RoleViewer))
+	assert.False(t, HasAccess(viewer, models.RoleEditor))
+	assert.True(t, HasAccess(editor, models.RoleViewer))
+	assert.False(t, HasAccess(editor, models.RoleAdmin))
+	assert.True(t, HasAccess(admin, models.RoleEditor))
+	assert.False(t, HasAccess(auditor, models.RoleViewer))
+	assert.True(t, HasAccess(auditor, "auditor"))
+	assert.False(t, HasAccess(admin, "auditor"))
+	assert.False(t, HasAccess(nil, models.RoleViewer))
+	assert.True(t, HasAccess(nil, ""))
+}
+
+func TestDefaultAccessPolicy(t *testing.T) {
+	policy := DefaultAccessPolicy()
+	assert.Equal(t, models.RoleViewer, policy.RequiredRole("GET", "/products/{id}"))
+	assert.Equal(t, models.RoleEditor, policy.RequiredRole("POST", "/products"))
+	assert.Equal(t, models.RoleEditor, policy.RequiredRole("PUT", "/products/batch"))
+	assert.Equal(t, models.RoleEditor, policy.RequiredRole("DELETE", "/products/{id}"))
+	assert.Equal(t, models.RoleAdmin, policy.RequiredRole("DELETE", "/products/batch"))
+	assert.Equal(t, models.RoleAdmin, policy.RequiredRole("POST", "/webhooks"))
+	assert.Equal(t, models.RoleViewer, policy.RequiredRole("GET", "/webhooks/{id}/deliveries"))
+	assert.Equal(t, models.RoleAdmin, policy.RequiredRole("POST", "/jobs/{id}/rollback"))
+	assert.Equal(t, models.RoleViewer, policy.RequiredRole("GET", "/jobs/{id}"))
+	assert.Equal(t, models.RoleAdmin, policy.RequiredRole("GET", "/admin/catalog/export"))
+	assert.Equal(t, models.RoleAdmin, policy.RequiredRole("POST", "/admin/catalog/import"))
+	assert.Equal(t, models.RoleViewer, policy.RequiredRole("GET", "/administrators"))
+}
+
+func TestAccessPolicyLongestPrefix(t *testing.T) {
+	policy := AccessPolicy{
+		ReadRole:  models.RoleViewer,
+		WriteRole: models.RoleEditor,
+		Routes:    map[string]string{"GET /admin/notes/{id}": models.RoleViewer},
+		Prefixes:  map[string]string{"/admin/": models.RoleAdmin, "/admin/notes/": models.RoleEditor},
+	}
+	assert.Equal(t, models.RoleAdmin, policy.RequiredRole("GET", "/admin/diagnostics"))
+	assert.Equal(t, models.RoleEditor, policy.RequiredRole("POST", "/admin/notes/{id}"))
+	assert.Equal(t, models.RoleViewer, policy.RequiredRole("GET", "/admin/notes/{id}"))
+}
+
+func TestAuthorize(t *testing.T) {
+	router := mux.NewRouter()
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/products/batch", ok).Methods("POST", "DELETE")
+	router.HandleFunc("/products/{id}", ok).Methods("GET", "PUT")
+	router.HandleFunc("/admin/diagnostics", ok).Methods("GET")
+	router.HandleFunc("/admin/catalog/export", ok).Methods("GET")
+	router.HandleFunc("/admin/catalog/import", ok).Methods("POST")
+	router.HandleFunc("/admin/swagger/", ok).Methods("GET")
+	router.HandleFunc("/jobs/{id}/rollback", ok).Methods("POST")
+	router.Use(Authorize(DefaultAccessPolicy(), []string{"/admin/swagger/"}))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		roles          []string
+		anonymous      bool
+		expectedStatus int
+	}{
+		{"Viewer reads", "GET", "/products/prod_1", []string{models.RoleViewer}, false, http.StatusOK},
+		{"Viewer updates", "PUT", "/products/prod_1", []string{models.RoleViewer}, false, http.StatusForbidden},
+		{"Editor updates", "PUT", "/products/prod_1", []string{models.RoleEditor}, false, http.StatusOK},
+		{"Editor creates in bulk", "POST", "/products/batch", []string{models.RoleEditor}, false, http.StatusOK},
+		{"Editor deletes in bulk", "DELETE", "/products/batch", []string{models.RoleEditor}, false, http.StatusForbidden},
+		{"Admin deletes in bulk", "DELETE", "/products/batch", []string{models.RoleAdmin}, false, http.StatusOK},
+		{"No roles", "GET", "/products/prod_1", nil, false, http.StatusForbidden},
+		{"Viewer reads diagnostics", "GET", "/admin/diagnostics", []string{models.RoleViewer}, false, http.StatusForbidden},
+		{"Viewer exports", "GET", "/admin/catalog/export", []string{models.RoleViewer}, false, http.StatusForbidden},
+		{"Editor exports", "GET", "/admin/catalog/export", []string{models.RoleEditor}, false, http.StatusForbidden},
+		{"Editor imports", "POST", "/admin/catalog/import", []string{models.RoleEditor}, false, http.StatusForbidden},
+		{"Admin imports", "POST", "/admin/catalog/import", []string{models.RoleAdmin}, false, http.StatusOK},
+		{"Editor rolls a job back", "POST", "/jobs/job_1/rollback", []string{models.RoleEditor}, false, http.StatusForbidden},
+		{"Viewer rolls a job back", "POST", "/jobs/job_1/rollback", []string{models.RoleViewer}, false, http.StatusForbidden},
+		{"Admin rolls a job back", "POST", "/jobs/job_1/rollback", []string{models.RoleAdmin}, false, http.StatusOK},
+		{"Exempt prefix", "GET", "/admin/swagger/", nil, false, http.StatusOK},
+		{"Anonymous", "DELETE", "/products/batch", nil, true, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if !tc.anonymous {
+				req = req.WithContext(WithPrincipal(req.Context(), &models.Principal{Subject: "svc", Roles: tc.roles}))
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+)
+
+// RequestIDHeader carries the ID of a request, both from callers that already
+// assigned one and back to the caller in the response
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs accepted from callers
+const maxRequestIDLength = 128
+
+const requestIDKey = contextKey("request_id")
+
+// RequestIDFromContext returns the ID of the request, or "" outside of RequestLoggerMiddleware
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// RequestLoggerMiddleware gives every request an ID and a logger derived from
+// base carrying it, read with logging.FromContext. The ID is taken from the
+// caller's X-Request-ID header when it is a valid one and generated otherwise,
+// and is returned in the response header. Added after TracingMiddleware, the
+// logger carries the trace ID of sampled requests too.
+func RequestLoggerMiddleware(base *logging.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = uuid.New().String()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			logger := base.WithRequestID(requestID)
+			if span := trace.SpanContextFromContext(r.Context()); span.IsSampled() {
+				logger = logger.WithTraceID(span.TraceID().String())
+			}
+			ctx := context.WithValue(r.Context(), requestIDKey, requestID)
+			next.ServeHTTP(w, r.WithContext(logging.WithContext(ctx, logger)))
+		})
+	}
+}
+
+// validRequestID reports whether a caller's request ID is short and printable
+// enough to be logged and echoed as is
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range requestID {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
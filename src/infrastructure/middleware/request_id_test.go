@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+)
+
+func TestRequestLoggerMiddlewareGeneratesRequestID(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	var seen string
+	handler := RequestLoggerMiddleware(&logging.Logger{Logger: zap.New(core)})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestIDFromContext(r.Context())
+		logging.FromContext(r.Context()).Info("handled")
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", "/products", nil))
+
+	_, err := uuid.Parse(seen)
+	assert.NoError(t, err)
+	assert.Equal(t, seen, rr.Header().Get(RequestIDHeader))
+	if assert.Len(t, recorded.All(), 1) {
+		assert.Equal(t, seen, recorded.All()[0].ContextMap()["request_id"])
+	}
+}
+
+func TestRequestLoggerMiddlewarePropagatesRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		kept     bool
+	}{
+		{name: "valid", incoming: "req-123", kept: true},
+		{name: "too long", incoming: strings.Repeat("a", maxRequestIDLength+1)},
+		{name: "control characters", incoming: "req\n123"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var seen string
+			handler := RequestLoggerMiddleware(&logging.Logger{Logger: zap.NewNop()})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = RequestIDFromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/products", nil)
+			req.Header.Set(RequestIDHeader, tc.incoming)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.kept, seen == tc.incoming)
+			assert.NotEmpty(t, seen)
+			assert.Equal(t, seen, rr.Header().Get(RequestIDHeader))
+		})
+	}
+}
+
+func TestRequestLoggerMiddlewareAddsTraceID(t *testing.T) {
+	setupTracing(t)
+	core, recorded := observer.New(zap.InfoLevel)
+	router := mux.NewRouter()
+	router.Use(TracingMiddleware)
+	router.Use(RequestLoggerMiddleware(&logging.Logger{Logger: zap.New(core)}))
+	router.HandleFunc("/products", func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("handled")
+	})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/products", nil))
+
+	if assert.Len(t, recorded.All(), 1) {
+		assert.Equal(t, rr.Header().Get(TraceIDHeader), recorded.All()[0].ContextMap()["trace_id"])
+	}
+}
+
+func TestCallerLoggerKeepsRequestID(t *testing.T) {
+	core, recorded := observer.New(zap.InfoLevel)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("handled")
+	})
+	handler := RequestLoggerMiddleware(&logging.Logger{Logger: zap.New(core)})(RequireAuth("/admin/", nil, headerAuthenticator{})(next))
+
+	req := httptest.NewRequest("GET", "/admin/dashboard/jobs", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	req.Header.Set("X-Test-User", "alice")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if assert.Len(t, recorded.All(), 1) {
+		fields := recorded.All()[0].ContextMap()
+		assert.Equal(t, "req-123", fields["request_id"])
+		assert.Equal(t, "alice", fields["user_id"])
+	}
+}
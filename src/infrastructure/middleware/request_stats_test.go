@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStatusRecorder struct {
+	mu       sync.Mutex
+	statuses []int
+}
+
+func (m *mockStatusRecorder) Record(status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses = append(m.statuses, status)
+}
+
+func TestRequestStatsMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected int
+	}{
+		{
+			name: "Explicit status",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			expected: http.StatusNotFound,
+		},
+		{
+			name: "Implicit OK on write",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("ok"))
+			},
+			expected: http.StatusOK,
+		},
+		{
+			name:     "No write at all",
+			handler:  func(w http.ResponseWriter, r *http.Request) {},
+			expected: http.StatusOK,
+		},
+		{
+			name: "Server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "boom", http.StatusInternalServerError)
+			},
+			expected: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := &mockStatusRecorder{}
+			handler := RequestStatsMiddleware(recorder)(tc.handler)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+
+			assert.Equal(t, []int{tc.expected}, recorder.statuses)
+		})
+	}
+}
@@ -6,6 +6,16 @@ import (
 )
 
 var (
+	// HTTP request metrics
+	HTTPRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time spent serving HTTP requests",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"route", "method", "status"},
+	)
+
 	// Product operations metrics
 	ProductOperations = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -31,6 +41,25 @@ var (
 			Help: "Number of active WebSocket connections",
 		},
 	)
+	WebSocketClientsReaped = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "websocket_clients_reaped_total",
+			Help: "WebSocket connections dropped for missing keepalive pongs",
+		},
+	)
+	WebSocketQueuedMessages = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "websocket_send_queue_depth",
+			Help: "Messages waiting in the send queues of all WebSocket clients",
+		},
+	)
+	WebSocketQueueOverflows = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "websocket_send_queue_overflows_total",
+			Help: "Messages that did not fit in a WebSocket client's send queue, by what happened to the client",
+		},
+		[]string{"action"},
+	)
 
 	// Event processing metrics
 	EventProcessingDuration = promauto.NewHistogramVec(
@@ -51,4 +80,139 @@ var (
 		},
 		[]string{"operation"},
 	)
+
+	// Latency objective metrics
+	SLOBurnRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "slo_latency_burn_rate",
+			Help: "Rate at which a route consumes its latency error budget; 1 uses it up exactly over the objective period",
+		},
+		[]string{"route", "window"},
+	)
+
+	SLOLatencyP95 = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "slo_latency_p95_seconds",
+			Help: "Estimated 95th percentile latency per route over the short window",
+		},
+		[]string{"route"},
+	)
+
+	SLORequestsOverBudget = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "slo_requests_over_budget_total",
+			Help: "Requests slower than their route's latency objective",
+		},
+		[]string{"route"},
+	)
+
+	// Shadow repository metrics
+	ShadowComparisons = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "shadow_repository_comparisons_total",
+			Help: "Reads and writes repeated on the shadow repository, by result (match, mismatch or error)",
+		},
+		[]string{"operation", "result"},
+	)
+
+	ShadowWriteErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "shadow_repository_write_errors_total",
+			Help: "Mutations that succeeded on the primary but failed on the shadow repository",
+		},
+		[]string{"operation"},
+	)
+
+	// Event consumer metrics
+	EventConsumerLag = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "event_consumer_lag",
+			Help: "Events published but not yet committed by an internal subscriber",
+		},
+		[]string{"consumer"},
+	)
+
+	EventConsumerQueued = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "event_consumer_queued_deliveries",
+			Help: "Deliveries waiting for a rate-limited subscriber",
+		},
+		[]string{"consumer"},
+	)
+
+	EventConsumerQueueWait = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "event_consumer_queue_wait_seconds",
+			Help:    "Time deliveries wait for a rate-limited subscriber",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60},
+		},
+		[]string{"consumer"},
+	)
+
+	// Event outbox metrics
+	OutboxPendingEvents = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "event_outbox_pending",
+			Help: "Committed events waiting in the outbox to be published",
+		},
+	)
+
+	OutboxPublishAttempts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "event_outbox_publish_attempts_total",
+			Help: "Attempts to publish outbox events, by outcome",
+		},
+		[]string{"result"},
+	)
+
+	// Product JSON cache metrics
+	ProductJSONCacheRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "product_json_cache_requests_total",
+			Help: "Product reads served from or missing the encoded JSON cache",
+		},
+		[]string{"result"},
+	)
+
+	ProductJSONCacheWarms = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "product_json_cache_warms_total",
+			Help: "Hot products re-encoded into the JSON cache after an update",
+		},
+	)
+
+	// Repository read-through cache metrics
+	RepositoryCacheRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "repository_cache_requests_total",
+			Help: "Repository reads served from or missing the read-through cache, by operation and result",
+		},
+		[]string{"operation", "result"},
+	)
+
+	RepositoryCacheInvalidations = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "repository_cache_invalidations_total",
+			Help: "Products dropped from the read-through cache, by what changed them",
+		},
+		[]string{"source"},
+	)
+
+	// API deprecation metrics
+	DeprecatedRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "api_deprecated_requests_total",
+			Help: "Requests to deprecated routes and fields, by deprecation",
+		},
+		[]string{"deprecation"},
+	)
+
+	// Data quality metrics
+	ValidationWarnings = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "product_validation_warnings_total",
+			Help: "Soft validation issues found on written products",
+		},
+		[]string{"code"},
+	)
 )
@@ -0,0 +1,98 @@
+package slo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Objective is a route's latency objective: Target is the share of requests
+// that must complete within Latency, e.g. 0.99 for 99%
+type Objective struct {
+	Latency time.Duration
+	Target  float64
+}
+
+// objectiveJSON is the config file form of an Objective, with the latency as a duration string
+type objectiveJSON struct {
+	Latency string  `json:"latency"`
+	Target  float64 `json:"target"`
+}
+
+// Config holds the latency objectives per route. Routes are named by method
+// and path template, e.g. "GET /products/{id}". Routes without their own
+// objective use Default; a route objective without a target inherits the
+// default target.
+type Config struct {
+	Default *Objective
+	Routes  map[string]Objective
+}
+
+// DefaultConfig gives every route a 500ms objective for 99% of requests
+func DefaultConfig() Config {
+	return Config{
+		Default: &Objective{Latency: 500 * time.Millisecond, Target: 0.99},
+		Routes:  map[string]Objective{},
+	}
+}
+
+// ParseConfig reads objectives from JSON such as
+//
+//	{"default": {"latency": "500ms", "target": 0.99},
+//	 "routes": {"GET /products/{id}": {"latency": "100ms"}}}
+func ParseConfig(r io.Reader) (Config, error) {
+	var raw struct {
+		Default *objectiveJSON           `json:"default"`
+		Routes  map[string]objectiveJSON `json:"routes"`
+	}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return Config{}, fmt.Errorf("invalid latency objectives: %v", err)
+	}
+
+	config := Config{Routes: make(map[string]Objective, len(raw.Routes))}
+	defaultTarget := 0.0
+	if raw.Default != nil {
+		objective, err := raw.Default.objective("default", 0)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Default = &objective
+		defaultTarget = objective.Target
+	}
+	for route, value := range raw.Routes {
+		objective, err := value.objective(route, defaultTarget)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Routes[route] = objective
+	}
+	return config, nil
+}
+
+// objective validates and converts a config entry
+func (o objectiveJSON) objective(name string, defaultTarget float64) (Objective, error) {
+	latency, err := time.ParseDuration(o.Latency)
+	if err != nil || latency <= 0 {
+		return Objective{}, fmt.Errorf("invalid latency objective for %s: %q", name, o.Latency)
+	}
+	target := o.Target
+	if target == 0 {
+		target = defaultTarget
+	}
+	if target <= 0 || target >= 1 {
+		return Objective{}, fmt.Errorf("invalid target for %s: must be between 0 and 1", name)
+	}
+	return Objective{Latency: latency, Target: target}, nil
+}
+
+// objectiveFor returns the objective that applies to a route
+func (c Config) objectiveFor(route string) (Objective, bool) {
+	if objective, exists := c.Routes[route]; exists {
+		return objective, true
+	}
+	if c.Default != nil {
+		return *c.Default, true
+	}
+	return Objective{}, false
+}
@@ -0,0 +1,54 @@
+package slo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig(strings.NewReader(`{
+		"default": {"latency": "300ms", "target": 0.99},
+		"routes": {
+			"GET /products/{id}": {"latency": "50ms"},
+			"POST /products/import": {"latency": "5s", "target": 0.9}
+		}
+	}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &Objective{Latency: 300 * time.Millisecond, Target: 0.99}, config.Default)
+	assert.Equal(t, Objective{Latency: 50 * time.Millisecond, Target: 0.99}, config.Routes["GET /products/{id}"])
+	assert.Equal(t, Objective{Latency: 5 * time.Second, Target: 0.9}, config.Routes["POST /products/import"])
+
+	objective, tracked := config.objectiveFor("GET /products")
+	assert.True(t, tracked)
+	assert.Equal(t, 300*time.Millisecond, objective.Latency)
+}
+
+func TestParseConfigWithoutDefault(t *testing.T) {
+	config, err := ParseConfig(strings.NewReader(`{"routes": {"GET /products": {"latency": "100ms", "target": 0.95}}}`))
+	assert.NoError(t, err)
+
+	_, tracked := config.objectiveFor("GET /products/{id}")
+	assert.False(t, tracked)
+}
+
+func TestParseConfigRejectsInvalidObjectives(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"bad latency", `{"default": {"latency": "fast", "target": 0.99}}`},
+		{"target of 100%", `{"default": {"latency": "1s", "target": 1}}`},
+		{"route without target or default", `{"routes": {"GET /products": {"latency": "1s"}}}`},
+		{"not json", `latency: 1s`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig(strings.NewReader(tt.config))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package slo
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+	"go.uber.org/zap"
+)
+
+// Burn rates are reported over a short window that reacts quickly and a long
+// window that filters out brief spikes, the usual pairing for burn-rate alerts
+const (
+	shortWindow = 5 * time.Minute
+	longWindow  = time.Hour
+)
+
+// minWarningRequests is the number of requests in the short window a route needs
+// before a slow p95 is logged, so a single slow call does not raise a warning
+const minWarningRequests = 20
+
+// latencyBounds are the upper bounds of the latency histogram used to estimate p95
+var latencyBounds = []time.Duration{
+	5 * time.Millisecond, 10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	75 * time.Millisecond, 100 * time.Millisecond, 150 * time.Millisecond, 200 * time.Millisecond,
+	300 * time.Millisecond, 500 * time.Millisecond, 750 * time.Millisecond, time.Second,
+	1500 * time.Millisecond, 2 * time.Second, 3 * time.Second, 5 * time.Second, 10 * time.Second,
+}
+
+// minuteBucket holds the requests of one route in one minute
+type minuteBucket struct {
+	total     int
+	over      int
+	histogram []int // counts per latency bound, plus one for slower requests
+	max       time.Duration
+}
+
+// routeStats holds the recent requests of one route
+type routeStats struct {
+	objective Objective
+	minutes   map[int64]*minuteBucket // minute (unix) -> bucket
+}
+
+// Tracker measures request latency per route against the configured objectives.
+// Evaluate exports burn rates and p95 latency as metrics and logs a warning for
+// every route whose p95 exceeds its budget.
+type Tracker struct {
+	config Config
+	now    func() time.Time
+
+	mu     sync.Mutex
+	routes map[string]*routeStats
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTracker creates a tracker for the given objectives
+func NewTracker(config Config) *Tracker {
+	return &Tracker{
+		config: config,
+		now:    time.Now,
+		routes: make(map[string]*routeStats),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// RecordLatency records how long a request to a route took. Routes without an objective are ignored.
+func (t *Tracker) RecordLatency(route string, duration time.Duration) {
+	objective, tracked := t.config.objectiveFor(route)
+	if !tracked {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats, exists := t.routes[route]
+	if !exists {
+		stats = &routeStats{objective: objective, minutes: make(map[int64]*minuteBucket)}
+		t.routes[route] = stats
+	}
+
+	now := t.now()
+	minute := now.Truncate(time.Minute).Unix()
+	bucket, exists := stats.minutes[minute]
+	if !exists {
+		bucket = &minuteBucket{histogram: make([]int, len(latencyBounds)+1)}
+		stats.minutes[minute] = bucket
+		stats.prune(now)
+	}
+
+	bucket.total++
+	bucket.histogram[sort.Search(len(latencyBounds), func(i int) bool { return duration <= latencyBounds[i] })]++
+	if duration > bucket.max {
+		bucket.max = duration
+	}
+	if duration > objective.Latency {
+		bucket.over++
+		metrics.SLORequestsOverBudget.WithLabelValues(route).Inc()
+	}
+}
+
+// LatencyObjectives reports every route that has received requests, ordered by route
+func (t *Tracker) LatencyObjectives() []*interfaces.RouteLatency {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	reports := make([]*interfaces.RouteLatency, 0, len(t.routes))
+	for route, stats := range t.routes {
+		reports = append(reports, stats.report(route, now))
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Route < reports[j].Route
+	})
+	return reports
+}
+
+// Evaluate exports the current latency metrics and logs routes that are over budget
+func (t *Tracker) Evaluate() []*interfaces.RouteLatency {
+	reports := t.LatencyObjectives()
+	logger := logging.Shared()
+
+	for _, report := range reports {
+		for window, rate := range report.BurnRates {
+			metrics.SLOBurnRate.WithLabelValues(report.Route, window).Set(rate)
+		}
+		metrics.SLOLatencyP95.WithLabelValues(report.Route).Set(report.P95Ms / 1000)
+
+		if report.Breached && report.Requests >= minWarningRequests {
+			logger.Warn("Route latency over budget",
+				zap.String("route", report.Route),
+				zap.Float64("p95_ms", report.P95Ms),
+				zap.Float64("budget_ms", report.BudgetMs),
+				zap.Float64("target", report.Target),
+				zap.Int("requests", report.Requests),
+				zap.Int("over_budget", report.OverBudget),
+				zap.Float64("burn_rate_5m", report.BurnRates[shortWindow.String()]),
+				zap.Float64("burn_rate_1h", report.BurnRates[longWindow.String()]),
+			)
+		}
+	}
+	return reports
+}
+
+// Start evaluates the objectives on an interval until Stop is called
+func (t *Tracker) Start(interval time.Duration) {
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.stop:
+				return
+			case <-ticker.C:
+				t.Evaluate()
+			}
+		}
+	}()
+}
+
+// Stop ends the periodic evaluation
+func (t *Tracker) Stop() {
+	t.stopOnce.Do(func() { close(t.stop) })
+	<-t.done
+}
+
+// report summarises a route: requests and p95 over the short window, burn rates over both windows
+func (s *routeStats) report(route string, now time.Time) *interfaces.RouteLatency {
+	report := &interfaces.RouteLatency{
+		Route:     route,
+		BudgetMs:  milliseconds(s.objective.Latency),
+		Target:    s.objective.Target,
+		BurnRates: make(map[string]float64, 2),
+	}
+
+	for _, window := range []time.Duration{shortWindow, longWindow} {
+		summary := s.summarise(now, window)
+		if summary.total > 0 {
+			report.BurnRates[window.String()] = float64(summary.over) / float64(summary.total) / (1 - s.objective.Target)
+		} else {
+			report.BurnRates[window.String()] = 0
+		}
+		if window == shortWindow {
+			report.Requests = summary.total
+			report.OverBudget = summary.over
+			report.P95Ms = milliseconds(summary.percentile(0.95))
+		}
+	}
+	report.Breached = report.P95Ms > report.BudgetMs
+	return report
+}
+
+// summarise merges the buckets of the minutes within a window, including the current minute
+func (s *routeStats) summarise(now time.Time, window time.Duration) *minuteBucket {
+	from := now.Add(-window).Truncate(time.Minute).Unix()
+	summary := &minuteBucket{histogram: make([]int, len(latencyBounds)+1)}
+	for minute, bucket := range s.minutes {
+		if minute <= from {
+			continue
+		}
+		summary.total += bucket.total
+		summary.over += bucket.over
+		for i, count := range bucket.histogram {
+			summary.histogram[i] += count
+		}
+		if bucket.max > summary.max {
+			summary.max = bucket.max
+		}
+	}
+	return summary
+}
+
+// percentile estimates a latency percentile as the upper bound of the histogram
+// bucket it falls in, capped by the slowest request seen
+func (b *minuteBucket) percentile(p float64) time.Duration {
+	if b.total == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p * float64(b.total)))
+	seen := 0
+	for i, count := range b.histogram {
+		seen += count
+		if seen >= rank {
+			if i < len(latencyBounds) && latencyBounds[i] < b.max {
+				return latencyBounds[i]
+			}
+			return b.max
+		}
+	}
+	return b.max
+}
+
+// prune drops minutes that fall outside the long window
+func (s *routeStats) prune(now time.Time) {
+	cutoff := now.Add(-longWindow).Truncate(time.Minute).Unix()
+	for minute := range s.minutes {
+		if minute <= cutoff {
+			delete(s.minutes, minute)
+		}
+	}
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,125 @@
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTracker(now *time.Time) *Tracker {
+	tracker := NewTracker(Config{
+		Default: &Objective{Latency: 100 * time.Millisecond, Target: 0.9},
+		Routes: map[string]Objective{
+			"GET /products/{id}": {Latency: 20 * time.Millisecond, Target: 0.99},
+		},
+	})
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func reportFor(t *testing.T, tracker *Tracker, route string) *interfaces.RouteLatency {
+	for _, report := range tracker.LatencyObjectives() {
+		if report.Route == route {
+			return report
+		}
+	}
+	t.Fatalf("route %s is not tracked", route)
+	return nil
+}
+
+func TestTrackerWithinBudget(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 30, 0, time.UTC)
+	tracker := newTestTracker(&now)
+
+	for i := 0; i < 100; i++ {
+		tracker.RecordLatency("GET /products", 30*time.Millisecond)
+	}
+
+	report := reportFor(t, tracker, "GET /products")
+	assert.Equal(t, 100.0, report.BudgetMs)
+	assert.Equal(t, 100, report.Requests)
+	assert.Equal(t, 0, report.OverBudget)
+	assert.Equal(t, 30.0, report.P95Ms)
+	assert.False(t, report.Breached)
+	assert.Equal(t, 0.0, report.BurnRates["5m0s"])
+}
+
+func TestTrackerBurnRateAndBreach(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 30, 0, time.UTC)
+	tracker := newTestTracker(&now)
+
+	// 10% of requests miss a 99% objective: the budget burns 10x too fast
+	for i := 0; i < 90; i++ {
+		tracker.RecordLatency("GET /products/{id}", 10*time.Millisecond)
+	}
+	for i := 0; i < 10; i++ {
+		tracker.RecordLatency("GET /products/{id}", 400*time.Millisecond)
+	}
+
+	report := reportFor(t, tracker, "GET /products/{id}")
+	assert.Equal(t, 10, report.OverBudget)
+	assert.InDelta(t, 10.0, report.BurnRates["5m0s"], 0.001)
+	assert.InDelta(t, 10.0, report.BurnRates["1h0m0s"], 0.001)
+	assert.Equal(t, 400.0, report.P95Ms)
+	assert.True(t, report.Breached)
+
+	reports := tracker.Evaluate()
+	assert.Len(t, reports, 1)
+}
+
+func TestTrackerWindows(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 30, 0, time.UTC)
+	tracker := newTestTracker(&now)
+
+	for i := 0; i < 10; i++ {
+		tracker.RecordLatency("GET /products", time.Second)
+	}
+
+	// Ten minutes later the slow requests only count in the long window
+	now = now.Add(10 * time.Minute)
+	for i := 0; i < 10; i++ {
+		tracker.RecordLatency("GET /products", time.Millisecond)
+	}
+	report := reportFor(t, tracker, "GET /products")
+	assert.Equal(t, 10, report.Requests)
+	assert.Equal(t, 0.0, report.BurnRates["5m0s"])
+	assert.InDelta(t, 5.0, report.BurnRates["1h0m0s"], 0.001)
+
+	// After an hour they are gone entirely
+	now = now.Add(time.Hour)
+	tracker.RecordLatency("GET /products", time.Millisecond)
+	report = reportFor(t, tracker, "GET /products")
+	assert.Equal(t, 0.0, report.BurnRates["1h0m0s"])
+}
+
+func TestTrackerIgnoresRoutesWithoutObjective(t *testing.T) {
+	now := time.Now()
+	tracker := NewTracker(Config{Routes: map[string]Objective{}})
+	tracker.now = func() time.Time { return now }
+
+	tracker.RecordLatency("GET /products", time.Second)
+	assert.Empty(t, tracker.LatencyObjectives())
+}
+
+func TestPercentileUsesBucketBounds(t *testing.T) {
+	bucket := &minuteBucket{histogram: make([]int, len(latencyBounds)+1)}
+	assert.Equal(t, time.Duration(0), bucket.percentile(0.95))
+
+	// 95 fast requests and 5 slow ones: p95 falls in the fast bucket
+	bucket.total = 100
+	bucket.histogram[1] = 95 // <= 10ms
+	bucket.histogram[len(latencyBounds)] = 5
+	bucket.max = 20 * time.Second
+	assert.Equal(t, 10*time.Millisecond, bucket.percentile(0.95))
+	assert.Equal(t, 20*time.Second, bucket.percentile(0.99))
+}
+
+func TestTrackerStartStop(t *testing.T) {
+	tracker := NewTracker(DefaultConfig())
+	tracker.Start(time.Millisecond)
+	tracker.RecordLatency("GET /products", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	tracker.Stop()
+}
@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"sync"
+	"time"
+)
+
+// RequestStats keeps per-minute counts of HTTP response status codes
+type RequestStats struct {
+	mu        sync.Mutex
+	buckets   map[int64]map[int]int // minute (unix) -> status code -> count
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewRequestStats creates a tracker that keeps counts for the given retention period
+func NewRequestStats(retention time.Duration) *RequestStats {
+	return &RequestStats{
+		buckets:   make(map[int64]map[int]int),
+		retention: retention,
+		now:       time.Now,
+	}
+}
+
+// Record counts a response with the given status code
+func (s *RequestStats) Record(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	minute := now.Truncate(time.Minute).Unix()
+
+	bucket, exists := s.buckets[minute]
+	if !exists {
+		bucket = make(map[int]int)
+		s.buckets[minute] = bucket
+		s.prune(now)
+	}
+	bucket[status]++
+}
+
+// Counts returns the number of responses per status code recorded since the given time
+func (s *RequestStats) Counts(since time.Time) map[int]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	from := since.Truncate(time.Minute).Unix()
+	counts := make(map[int]int)
+	for minute, bucket := range s.buckets {
+		if minute < from {
+			continue
+		}
+		for status, count := range bucket {
+			counts[status] += count
+		}
+	}
+	return counts
+}
+
+// prune drops buckets that fall outside the retention period
+func (s *RequestStats) prune(now time.Time) {
+	cutoff := now.Add(-s.retention).Truncate(time.Minute).Unix()
+	for minute := range s.buckets {
+		if minute < cutoff {
+			delete(s.buckets, minute)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndCounts(t *testing.T) {
+	s := NewRequestStats(time.Hour)
+
+	s.Record(http.StatusOK)
+	s.Record(http.StatusOK)
+	s.Record(http.StatusNotFound)
+	s.Record(http.StatusInternalServerError)
+
+	counts := s.Counts(time.Now().Add(-time.Minute))
+	assert.Equal(t, 2, counts[http.StatusOK])
+	assert.Equal(t, 1, counts[http.StatusNotFound])
+	assert.Equal(t, 1, counts[http.StatusInternalServerError])
+}
+
+func TestCountsRespectsWindow(t *testing.T) {
+	s := NewRequestStats(time.Hour)
+	current := time.Now()
+	s.now = func() time.Time { return current }
+
+	// Record an old response, then move the clock forward
+	s.Record(http.StatusInternalServerError)
+	current = current.Add(10 * time.Minute)
+	s.Record(http.StatusOK)
+
+	recent := s.Counts(current.Add(-5 * time.Minute))
+	assert.Equal(t, 1, recent[http.StatusOK])
+	assert.Equal(t, 0, recent[http.StatusInternalServerError])
+
+	all := s.Counts(current.Add(-time.Hour))
+	assert.Equal(t, 1, all[http.StatusInternalServerError])
+}
+
+func TestPruneDropsExpiredBuckets(t *testing.T) {
+	s := NewRequestStats(5 * time.Minute)
+	current := time.Now()
+	s.now = func() time.Time { return current }
+
+	s.Record(http.StatusOK)
+	current = current.Add(30 * time.Minute)
+	s.Record(http.StatusOK)
+
+	s.mu.Lock()
+	buckets := len(s.buckets)
+	s.mu.Unlock()
+	assert.Equal(t, 1, buckets)
+}
+
+func TestConcurrentRecord(t *testing.T) {
+	s := NewRequestStats(time.Hour)
+	var wg sync.WaitGroup
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Record(http.StatusOK)
+		}()
+	}
+	wg.Wait()
+
+	counts := s.Counts(time.Now().Add(-time.Minute))
+	assert.Equal(t, 100, counts[http.StatusOK])
+}
@@ -0,0 +1,80 @@
+package oidc
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults for optional settings
+const (
+	DefaultGroupsClaim = "groups"
+	DefaultSessionTTL  = 8 * time.Hour
+	// minSecretLength is the shortest session secret accepted, in bytes
+	minSecretLength = 32
+)
+
+// Config configures login to the admin endpoints with an OpenID Connect provider
+type Config struct {
+	Issuer       string // e.g. https://login.example.com/realms/corp
+	ClientID     string
+	ClientSecret string // Optional: public clients rely on PKCE alone
+	RedirectURL  string // Must point to /auth/callback of this service
+	Scopes       []string
+	// GroupsClaim is the ID token claim holding the user's groups
+	GroupsClaim string
+	// GroupRoles maps IdP groups to roles. Users whose groups map to no role cannot log in.
+	GroupRoles map[string][]string
+	// SessionSecret signs session cookies; at least 32 bytes
+	SessionSecret string
+	SessionTTL    time.Duration
+}
+
+// withDefaults returns the config with optional settings filled in
+func (c Config) withDefaults() Config {
+	if len(c.Scopes) == 0 {
+		c.Scopes = []string{"openid", "profile", "email"}
+	}
+	if c.GroupsClaim == "" {
+		c.GroupsClaim = DefaultGroupsClaim
+	}
+	if c.SessionTTL <= 0 {
+		c.SessionTTL = DefaultSessionTTL
+	}
+	c.Issuer = strings.TrimRight(c.Issuer, "/")
+	return c
+}
+
+// validate checks the required settings
+func (c Config) validate() error {
+	switch {
+	case c.Issuer == "":
+		return errors.New("oidc: issuer is required")
+	case c.ClientID == "":
+		return errors.New("oidc: client ID is required")
+	case c.RedirectURL == "":
+		return errors.New("oidc: redirect URL is required")
+	case len(c.SessionSecret) < minSecretLength:
+		return errors.New("oidc: session secret must be at least 32 bytes")
+	case len(c.GroupRoles) == 0:
+		return errors.New("oidc: group roles are required, nobody could log in")
+	}
+	return nil
+}
+
+// Roles returns the sorted roles granted by a user's groups
+func (c Config) Roles(groups []string) []string {
+	granted := make(map[string]bool)
+	for _, group := range groups {
+		for _, role := range c.GroupRoles[group] {
+			granted[role] = true
+		}
+	}
+	roles := make([]string, 0, len(granted))
+	for role := range granted {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
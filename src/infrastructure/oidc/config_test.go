@@ -0,0 +1,54 @@
+package oidc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigRoles(t *testing.T) {
+	config := Config{GroupRoles: map[string][]string{
+		"catalog-admins":  {"admin", "editor"},
+		"catalog-editors": {"editor"},
+	}}
+
+	assert.Equal(t, []string{"admin", "editor"}, config.Roles([]string{"catalog-editors", "catalog-admins", "finance"}))
+	assert.Empty(t, config.Roles([]string{"finance"}))
+	assert.Empty(t, config.Roles(nil))
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{
+		Issuer:        "https://login.example.com",
+		ClientID:      "ecom-admin",
+		RedirectURL:   "https://ecom.example.com/auth/callback",
+		SessionSecret: strings.Repeat("s", 32),
+		GroupRoles:    map[string][]string{"admins": {"admin"}},
+	}
+	assert.NoError(t, valid.validate())
+
+	tests := []struct {
+		name   string
+		change func(c *Config)
+	}{
+		{"no issuer", func(c *Config) { c.Issuer = "" }},
+		{"no client", func(c *Config) { c.ClientID = "" }},
+		{"no redirect", func(c *Config) { c.RedirectURL = "" }},
+		{"short secret", func(c *Config) { c.SessionSecret = "short" }},
+		{"no group roles", func(c *Config) { c.GroupRoles = nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.change(&config)
+			assert.Error(t, config.validate())
+		})
+	}
+
+	defaults := valid.withDefaults()
+	assert.Equal(t, []string{"openid", "profile", "email"}, defaults.Scopes)
+	assert.Equal(t, DefaultGroupsClaim, defaults.GroupsClaim)
+	assert.Equal(t, DefaultSessionTTL, defaults.SessionTTL)
+}
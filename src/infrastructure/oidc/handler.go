@@ -0,0 +1,287 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/respond"
+	"go.uber.org/zap"
+)
+
+const (
+	// SessionCookie holds the signed session of a logged in user
+	SessionCookie = "ecom_session"
+	// flowCookie holds the state, nonce and PKCE verifier between login and callback
+	flowCookie = "ecom_oidc_flow"
+	// flowTTL is how long a user has to complete the login at the provider
+	flowTTL = 10 * time.Minute
+	// defaultRedirect is where users land after login when they did not ask for a page
+	defaultRedirect = "/auth/me"
+)
+
+// flow is the login in progress, kept in a signed cookie
+type flow struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Redirect string `json:"redirect"`
+}
+
+// Handler runs the authorization code flow with PKCE and authenticates
+// requests by their session cookie
+type Handler struct {
+	config   Config
+	provider *provider
+	codec    cookieCodec
+	secure   bool
+	now      func() time.Time
+}
+
+// NewHandler creates the login handler for a provider. The provider is not
+// contacted until the first login.
+func NewHandler(config Config) (*Handler, error) {
+	config = config.withDefaults()
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &Handler{
+		config:   config,
+		provider: newProvider(config.Issuer),
+		codec:    cookieCodec{key: []byte(config.SessionSecret)},
+		secure:   strings.HasPrefix(config.RedirectURL, "https://"),
+		now:      time.Now,
+	}, nil
+}
+
+// Login redirects to the provider. The optional redirect query parameter is
+// the local page to return to after login, e.g. /admin/dashboard/jobs.
+func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
+	doc, err := h.provider.endpoints()
+	if err != nil {
+		logging.Shared().Error("OIDC discovery failed", zap.Error(err))
+		respond.Error(w, http.StatusBadGateway, "Identity provider unavailable")
+		return
+	}
+
+	login := flow{
+		State:    randomString(),
+		Nonce:    randomString(),
+		Verifier: randomString(),
+		Redirect: localRedirect(r.URL.Query().Get("redirect")),
+	}
+	value, err := h.codec.encode(login, h.now().Add(flowTTL))
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to start login")
+		return
+	}
+	h.setCookie(w, flowCookie, value, "/auth/", h.now().Add(flowTTL))
+
+	challenge := sha256.Sum256([]byte(login.Verifier))
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {h.config.ClientID},
+		"redirect_uri":          {h.config.RedirectURL},
+		"scope":                 {strings.Join(h.config.Scopes, " ")},
+		"state":                 {login.State},
+		"nonce":                 {login.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+	separator := "?"
+	if strings.Contains(doc.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	http.Redirect(w, r, doc.AuthorizationEndpoint+separator+query.Encode(), http.StatusFound)
+}
+
+// Callback completes the login: it exchanges the code, verifies the ID token,
+// maps the user's groups to roles and starts a session
+func (h *Handler) Callback(w http.ResponseWriter, r *http.Request) {
+	logger := logging.Shared()
+	query := r.URL.Query()
+	if providerError := query.Get("error"); providerError != "" {
+		logger.Warn("OIDC login refused by provider", zap.String("error", providerError), zap.String("description", query.Get("error_description")))
+		respond.Error(w, http.StatusUnauthorized, "Login failed: "+providerError)
+		return
+	}
+
+	var login flow
+	cookie, err := r.Cookie(flowCookie)
+	if err != nil || h.codec.decode(cookie.Value, h.now(), &login) != nil {
+		respond.Error(w, http.StatusBadRequest, "Login expired, please try again")
+		return
+	}
+	h.setCookie(w, flowCookie, "", "/auth/", time.Unix(0, 0))
+	if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(login.State)) != 1 {
+		respond.Error(w, http.StatusBadRequest, "Login state mismatch, please try again")
+		return
+	}
+
+	idToken, err := h.exchange(query.Get("code"), login.Verifier)
+	if err != nil {
+		logger.Error("OIDC code exchange failed", zap.Error(err))
+		respond.Error(w, http.StatusBadGateway, "Login failed at the identity provider")
+		return
+	}
+	claims, err := h.provider.verify(idToken, h.config.ClientID, login.Nonce, h.now())
+	if err != nil {
+		logger.Warn("OIDC ID token rejected", zap.Error(err))
+		respond.Error(w, http.StatusUnauthorized, "Login failed: invalid ID token")
+		return
+	}
+
+	principal := h.principal(claims)
+	if len(principal.Roles) == 0 {
+		logger.Warn("OIDC user has no roles", zap.String("subject", principal.Subject), zap.Strings("groups", principal.Groups))
+		respond.Error(w, http.StatusForbidden, "Your groups do not grant access to the admin endpoints")
+		return
+	}
+
+	expires := h.now().Add(h.config.SessionTTL)
+	value, err := h.codec.encode(principal, expires)
+	if err != nil {
+		respond.Error(w, http.StatusInternalServerError, "Failed to start session")
+		return
+	}
+	h.setCookie(w, SessionCookie, value, "/", expires)
+	logger.Info("Admin user logged in", zap.String("subject", principal.Subject), zap.Strings("roles", principal.Roles))
+	http.Redirect(w, r, login.Redirect, http.StatusFound)
+}
+
+// Logout ends the session
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	h.setCookie(w, SessionCookie, "", "/", time.Unix(0, 0))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me returns the logged in user
+func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
+	principal, err := h.Authenticate(r)
+	if err != nil || principal == nil {
+		respond.Error(w, http.StatusUnauthorized, "Not logged in")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(principal)
+}
+
+// Authenticate returns the user of a session cookie. Requests without the
+// cookie are left to other authenticators.
+func (h *Handler) Authenticate(r *http.Request) (*models.Principal, error) {
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil || cookie.Value == "" {
+		return nil, nil
+	}
+	var principal models.Principal
+	if err := h.codec.decode(cookie.Value, h.now(), &principal); err != nil {
+		return nil, err
+	}
+	return &principal, nil
+}
+
+// exchange trades the authorization code for tokens and returns the ID token
+func (h *Handler) exchange(code, verifier string) (string, error) {
+	if code == "" {
+		return "", fmt.Errorf("no authorization code")
+	}
+	doc, err := h.provider.endpoints()
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {h.config.RedirectURL},
+		"client_id":     {h.config.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequest(http.MethodPost, doc.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if h.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(h.config.ClientID), url.QueryEscape(h.config.ClientSecret))
+	}
+
+	resp, err := h.provider.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var tokens struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+		return "", fmt.Errorf("token endpoint returned %d with an invalid body", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %d: %s %s", resp.StatusCode, tokens.Error, tokens.ErrorDescription)
+	}
+	if tokens.IDToken == "" {
+		return "", fmt.Errorf("token endpoint returned no ID token")
+	}
+	return tokens.IDToken, nil
+}
+
+// principal builds the user from verified ID token claims
+func (h *Handler) principal(claims map[string]interface{}) *models.Principal {
+	subject, _ := claims["sub"].(string)
+	name, _ := claims["name"].(string)
+	email, _ := claims["email"].(string)
+	groups := stringList(claims[h.config.GroupsClaim])
+	return &models.Principal{
+		Subject: subject,
+		Name:    name,
+		Email:   email,
+		Groups:  groups,
+		Roles:   h.config.Roles(groups),
+		Method:  models.AuthMethodOIDC,
+	}
+}
+
+// setCookie sets an HttpOnly cookie; SameSite=Lax keeps it off cross-site POSTs
+func (h *Handler) setCookie(w http.ResponseWriter, name, value, path string, expires time.Time) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   h.secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// randomString returns 32 random bytes, base64url encoded
+func randomString() string {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		panic(fmt.Sprintf("oidc: no randomness: %v", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// localRedirect only allows paths on this service, so the login cannot be
+// used to send users to another site
+func localRedirect(target string) string {
+	if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") || strings.HasPrefix(target, "/\\") {
+		return defaultRedirect
+	}
+	return target
+}
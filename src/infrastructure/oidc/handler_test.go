@@ -0,0 +1,172 @@
+package oidc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func newTestHandler(t *testing.T, fake *fakeProvider) *Handler {
+	handler, err := NewHandler(Config{
+		Issuer:        fake.server.URL,
+		ClientID:      "ecom-admin",
+		ClientSecret:  "client-secret",
+		RedirectURL:   "https://ecom.example.com/auth/callback",
+		SessionSecret: strings.Repeat("s", 32),
+		GroupRoles:    map[string][]string{"catalog-editors": {"editor"}},
+	})
+	assert.NoError(t, err)
+	return handler
+}
+
+// login runs the login and callback and returns the callback response
+func login(t *testing.T, handler *Handler, fake *fakeProvider, claims map[string]interface{}) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	handler.Login(rr, httptest.NewRequest("GET", "/auth/login?redirect=/admin/dashboard/jobs", nil))
+	assert.Equal(t, http.StatusFound, rr.Code)
+
+	callback := fake.authorize(t, rr.Header().Get("Location"), claims)
+	req := httptest.NewRequest("GET", "/auth/callback?"+callback.Encode(), nil)
+	for _, cookie := range rr.Result().Cookies() {
+		req.AddCookie(cookie)
+	}
+	rr = httptest.NewRecorder()
+	handler.Callback(rr, req)
+	return rr
+}
+
+func sessionCookie(rr *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range rr.Result().Cookies() {
+		if cookie.Name == SessionCookie {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestLoginRedirectsToProvider(t *testing.T) {
+	fake := newFakeProvider(t)
+	handler := newTestHandler(t, fake)
+
+	rr := httptest.NewRecorder()
+	handler.Login(rr, httptest.NewRequest("GET", "/auth/login", nil))
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	location := rr.Header().Get("Location")
+	assert.True(t, strings.HasPrefix(location, fake.server.URL+"/authorize?"))
+	assert.Contains(t, location, "code_challenge_method=S256")
+	assert.Contains(t, location, "client_id=ecom-admin")
+
+	cookies := rr.Result().Cookies()
+	assert.Len(t, cookies, 1)
+	assert.Equal(t, flowCookie, cookies[0].Name)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.True(t, cookies[0].Secure)
+}
+
+func TestCallbackStartsSession(t *testing.T) {
+	fake := newFakeProvider(t)
+	handler := newTestHandler(t, fake)
+
+	claims := fake.validClaims(time.Now())
+	delete(claims, "nonce")
+	rr := login(t, handler, fake, claims)
+
+	assert.Equal(t, http.StatusFound, rr.Code)
+	assert.Equal(t, "/admin/dashboard/jobs", rr.Header().Get("Location"))
+	cookie := sessionCookie(rr)
+	assert.NotNil(t, cookie)
+
+	req := httptest.NewRequest("GET", "/auth/me", nil)
+	req.AddCookie(cookie)
+	principal, err := handler.Authenticate(req)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", principal.Subject)
+	assert.Equal(t, "ada@example.com", principal.Email)
+	assert.Equal(t, []string{"editor"}, principal.Roles)
+	assert.Equal(t, models.AuthMethodOIDC, principal.Method)
+
+	me := httptest.NewRecorder()
+	handler.Me(me, req)
+	var body models.Principal
+	assert.NoError(t, json.NewDecoder(me.Body).Decode(&body))
+	assert.Equal(t, "user-1", body.Subject)
+
+	// Sessions end when they expire
+	handler.now = func() time.Time { return time.Now().Add(DefaultSessionTTL + time.Minute) }
+	_, err = handler.Authenticate(req)
+	assert.Error(t, err)
+}
+
+func TestCallbackRejects(t *testing.T) {
+	fake := newFakeProvider(t)
+	handler := newTestHandler(t, fake)
+
+	t.Run("no roles for groups", func(t *testing.T) {
+		claims := fake.validClaims(time.Now())
+		delete(claims, "nonce")
+		claims["groups"] = []string{"finance"}
+		rr := login(t, handler, fake, claims)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+		assert.Nil(t, sessionCookie(rr))
+	})
+
+	t.Run("replayed nonce", func(t *testing.T) {
+		rr := login(t, handler, fake, fake.validClaims(time.Now()))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("state mismatch", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Login(rr, httptest.NewRequest("GET", "/auth/login", nil))
+		callback := fake.authorize(t, rr.Header().Get("Location"), nil)
+		callback.Set("state", "forged")
+
+		req := httptest.NewRequest("GET", "/auth/callback?"+callback.Encode(), nil)
+		for _, cookie := range rr.Result().Cookies() {
+			req.AddCookie(cookie)
+		}
+		rr = httptest.NewRecorder()
+		handler.Callback(rr, req)
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("no login in progress", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Callback(rr, httptest.NewRequest("GET", "/auth/callback?code=abc&state=xyz", nil))
+		assert.Equal(t, http.StatusBadRequest, rr.Code)
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.Callback(rr, httptest.NewRequest("GET", "/auth/callback?error=access_denied", nil))
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
+func TestLocalRedirect(t *testing.T) {
+	assert.Equal(t, "/admin/dashboard/jobs", localRedirect("/admin/dashboard/jobs"))
+	assert.Equal(t, defaultRedirect, localRedirect("https://evil.example.com"))
+	assert.Equal(t, defaultRedirect, localRedirect("//evil.example.com"))
+	assert.Equal(t, defaultRedirect, localRedirect("/\\evil.example.com"))
+	assert.Equal(t, defaultRedirect, localRedirect(""))
+}
+
+func TestLogoutClearsSession(t *testing.T) {
+	handler := newTestHandler(t, newFakeProvider(t))
+
+	rr := httptest.NewRecorder()
+	handler.Logout(rr, httptest.NewRequest("POST", "/auth/logout", nil))
+
+	assert.Equal(t, http.StatusNoContent, rr.Code)
+	cookie := sessionCookie(rr)
+	assert.Equal(t, "", cookie.Value)
+	assert.True(t, cookie.Expires.Before(time.Now()))
+}
@@ -0,0 +1,185 @@
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeProvider is an identity provider that issues ID tokens for codes it handed out
+type fakeProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+
+	mu         sync.Mutex
+	challenges map[string]string // code -> PKCE challenge
+	claims     map[string]interface{}
+	jwksCalls  int
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	p := &fakeProvider{key: key, kid: "key-1", challenges: make(map[string]string)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.jwksCalls++
+		kid := p.kid
+		p.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": kid,
+			"kty": "RSA",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		p.mu.Lock()
+		challenge, exists := p.challenges[r.Form.Get("code")]
+		claims := p.claims
+		p.mu.Unlock()
+
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if !exists || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": p.sign(t, claims, "RS256")})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+// authorize plays the user logging in at the provider and returns the callback query
+func (p *fakeProvider) authorize(t *testing.T, location string, claims map[string]interface{}) url.Values {
+	authorize, err := url.Parse(location)
+	assert.NoError(t, err)
+	query := authorize.Query()
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+
+	code := "code-" + query.Get("state")[:8]
+	p.mu.Lock()
+	p.challenges[code] = query.Get("code_challenge")
+	p.claims = claims
+	if p.claims != nil {
+		if _, set := p.claims["nonce"]; !set {
+			p.claims["nonce"] = query.Get("nonce")
+		}
+	}
+	p.mu.Unlock()
+	return url.Values{"code": {code}, "state": {query.Get("state")}}
+}
+
+// sign creates a compact JWT with the provider's key
+func (p *fakeProvider) sign(t *testing.T, claims map[string]interface{}, alg string) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": p.kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (p *fakeProvider) validClaims(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"iss":    p.server.URL,
+		"aud":    "ecom-admin",
+		"sub":    "user-1",
+		"exp":    float64(now.Add(time.Hour).Unix()),
+		"nonce":  "nonce-1",
+		"email":  "ada@example.com",
+		"groups": []string{"catalog-editors"},
+	}
+}
+
+func TestProviderVerify(t *testing.T) {
+	fake := newFakeProvider(t)
+	p := newProvider(fake.server.URL)
+	now := time.Now()
+
+	claims, err := p.verify(fake.sign(t, fake.validClaims(now), "RS256"), "ecom-admin", "nonce-1", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", claims["sub"])
+
+	// The audience may be a list
+	listed := fake.validClaims(now)
+	listed["aud"] = []string{"other", "ecom-admin"}
+	_, err = p.verify(fake.sign(t, listed, "RS256"), "ecom-admin", "nonce-1", now)
+	assert.NoError(t, err)
+}
+
+func TestProviderVerifyRejects(t *testing.T) {
+	fake := newFakeProvider(t)
+	p := newProvider(fake.server.URL)
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		change func(claims map[string]interface{})
+		alg    string
+	}{
+		{"other issuer", func(c map[string]interface{}) { c["iss"] = "https://evil.example.com" }, "RS256"},
+		{"other audience", func(c map[string]interface{}) { c["aud"] = "other-client" }, "RS256"},
+		{"expired", func(c map[string]interface{}) { c["exp"] = float64(now.Add(-2 * time.Minute).Unix()) }, "RS256"},
+		{"nonce mismatch", func(c map[string]interface{}) { c["nonce"] = "replayed" }, "RS256"},
+		{"no subject", func(c map[string]interface{}) { delete(c, "sub") }, "RS256"},
+		{"unsigned algorithm", func(c map[string]interface{}) {}, "none"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := fake.validClaims(now)
+			tt.change(claims)
+			_, err := p.verify(fake.sign(t, claims, tt.alg), "ecom-admin", "nonce-1", now)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+
+	token := fake.sign(t, fake.validClaims(now), "RS256")
+	_, err := p.verify(token[:len(token)-4]+"AAAA", "ecom-admin", "nonce-1", now)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestProviderRefetchesRotatedKeys(t *testing.T) {
+	fake := newFakeProvider(t)
+	p := newProvider(fake.server.URL)
+	now := time.Now()
+
+	_, err := p.verify(fake.sign(t, fake.validClaims(now), "RS256"), "ecom-admin", "nonce-1", now)
+	assert.NoError(t, err)
+
+	fake.mu.Lock()
+	fake.kid = "key-2"
+	fake.mu.Unlock()
+	_, err = p.verify(fake.sign(t, fake.validClaims(now), "RS256"), "ecom-admin", "nonce-1", now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fake.jwksCalls)
+}
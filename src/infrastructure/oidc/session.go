@@ -0,0 +1,67 @@
+package oidc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// errInvalidCookie is returned for cookies that were tampered with, expired or are malformed
+var errInvalidCookie = errors.New("oidc: invalid or expired cookie")
+
+// cookieCodec signs values stored in cookies so the browser cannot change them.
+// Values are readable by the user, so they must not hold secrets the user may
+// not see.
+type cookieCodec struct {
+	key []byte
+}
+
+// signedValue wraps a value with its expiry
+type signedValue struct {
+	Value   json.RawMessage `json:"v"`
+	Expires int64           `json:"exp"`
+}
+
+// encode serializes and signs a value that expires at the given time
+func (c cookieCodec) encode(value interface{}, expires time.Time) (string, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(signedValue{Value: raw, Expires: expires.Unix()})
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + c.sign(encoded), nil
+}
+
+// decode verifies a cookie value and unmarshals it into value
+func (c cookieCodec) decode(cookie string, now time.Time, value interface{}) error {
+	encoded, signature, found := strings.Cut(cookie, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(c.sign(encoded))) {
+		return errInvalidCookie
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return errInvalidCookie
+	}
+	var signed signedValue
+	if err := json.Unmarshal(payload, &signed); err != nil || now.Unix() >= signed.Expires {
+		return errInvalidCookie
+	}
+	if err := json.Unmarshal(signed.Value, value); err != nil {
+		return errInvalidCookie
+	}
+	return nil
+}
+
+func (c cookieCodec) sign(encoded string) string {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,49 @@
+package oidc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookieCodecRoundTrip(t *testing.T) {
+	codec := cookieCodec{key: []byte(strings.Repeat("k", 32))}
+	now := time.Now()
+
+	cookie, err := codec.encode(map[string]string{"sub": "user-1"}, now.Add(time.Hour))
+	assert.NoError(t, err)
+
+	var value map[string]string
+	assert.NoError(t, codec.decode(cookie, now, &value))
+	assert.Equal(t, "user-1", value["sub"])
+}
+
+func TestCookieCodecRejects(t *testing.T) {
+	codec := cookieCodec{key: []byte(strings.Repeat("k", 32))}
+	other := cookieCodec{key: []byte(strings.Repeat("x", 32))}
+	now := time.Now()
+	cookie, _ := codec.encode("value", now.Add(time.Hour))
+	forged, _ := other.encode("value", now.Add(time.Hour))
+	encoded, signature, _ := strings.Cut(cookie, ".")
+
+	tests := []struct {
+		name   string
+		cookie string
+		now    time.Time
+	}{
+		{"expired", cookie, now.Add(2 * time.Hour)},
+		{"other key", forged, now},
+		{"changed payload", encoded + "x." + signature, now},
+		{"no signature", encoded, now},
+		{"garbage", "...", now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var value string
+			assert.ErrorIs(t, codec.decode(tt.cookie, tt.now, &value), errInvalidCookie)
+		})
+	}
+}
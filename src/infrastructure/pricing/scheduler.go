@@ -0,0 +1,89 @@
+// Package pricing runs the price schedule: it announces scheduled prices as
+// they start and end, since nothing else changes when they do.
+package pricing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+)
+
+// DefaultInterval is how often the schedule is checked by default
+const DefaultInterval = time.Minute
+
+// Scheduler publishes price.changed events for the scheduled prices that
+// started or ended since its previous check. A price is announced up to one
+// interval late; it applies on time, since prices are resolved per request.
+type Scheduler struct {
+	prices interfaces.PriceListService
+	now    func() time.Time
+
+	mu   sync.Mutex
+	last time.Time
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewScheduler creates a scheduler that checks the schedule from now on
+func NewScheduler(prices interfaces.PriceListService) *Scheduler {
+	return &Scheduler{
+		prices: prices,
+		now:    time.Now,
+		last:   time.Now(),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Check publishes the changes since the previous check and returns their number
+func (s *Scheduler) Check() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	count, err := s.prices.PublishScheduledChanges(context.Background(), s.last, now)
+	if err != nil {
+		// The window is kept, so the next check covers it again
+		logging.Shared().Error("Failed to publish scheduled price changes", zap.Error(err))
+		return 0
+	}
+	s.last = now
+	if count > 0 {
+		logging.Shared().Info("Published scheduled price changes", zap.Int("events", count))
+	}
+	return count
+}
+
+// Start checks the schedule on an interval until Stop is called
+func (s *Scheduler) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				s.Check()
+			}
+		}
+	}()
+}
+
+// Stop ends the periodic checks
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() { close(s.stop) })
+	<-s.done
+}
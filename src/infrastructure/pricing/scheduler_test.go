@@ -0,0 +1,95 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// MockPriceListService is a mock for the PriceListService interface
+type MockPriceListService struct {
+	mock.Mock
+}
+
+func (m *MockPriceListService) ListPriceLists() ([]*models.PriceList, error) {
+	args := m.Called()
+	return nil, args.Error(1)
+}
+
+func (m *MockPriceListService) GetPriceList(id string) (*models.PriceList, error) {
+	args := m.Called(id)
+	return nil, args.Error(1)
+}
+
+func (m *MockPriceListService) CreatePriceList(ctx context.Context, list *models.PriceList) (*models.PriceList, error) {
+	args := m.Called(list)
+	return nil, args.Error(1)
+}
+
+func (m *MockPriceListService) UpdatePriceList(ctx context.Context, list *models.PriceList) (*models.PriceList, error) {
+	args := m.Called(list)
+	return nil, args.Error(1)
+}
+
+func (m *MockPriceListService) DeletePriceList(ctx context.Context, id string) error {
+	return m.Called(id).Error(0)
+}
+
+func (m *MockPriceListService) ResolvePrice(ctx context.Context, productID string, query models.PriceQuery) (*models.ResolvedPrice, error) {
+	args := m.Called(productID, query)
+	return nil, args.Error(1)
+}
+
+func (m *MockPriceListService) PublishScheduledChanges(ctx context.Context, since, until time.Time) (int, error) {
+	args := m.Called(since, until)
+	return args.Int(0), args.Error(1)
+}
+
+func TestSchedulerChecksConsecutiveWindows(t *testing.T) {
+	prices := new(MockPriceListService)
+	scheduler := NewScheduler(prices)
+	start := scheduler.last
+	now := start.Add(time.Minute)
+	scheduler.now = func() time.Time { return now }
+
+	prices.On("PublishScheduledChanges", start, now).Return(2, nil).Once()
+	assert.Equal(t, 2, scheduler.Check())
+
+	// A failed check is covered again by the next one
+	later := now.Add(time.Minute)
+	scheduler.now = func() time.Time { return later }
+	prices.On("PublishScheduledChanges", now, later).Return(0, errors.New("unavailable")).Once()
+	assert.Equal(t, 0, scheduler.Check())
+
+	latest := later.Add(time.Minute)
+	scheduler.now = func() time.Time { return latest }
+	prices.On("PublishScheduledChanges", now, latest).Return(1, nil).Once()
+	assert.Equal(t, 1, scheduler.Check())
+	prices.AssertExpectations(t)
+}
+
+func TestSchedulerStartAndStop(t *testing.T) {
+	prices := new(MockPriceListService)
+	checked := make(chan struct{}, 1)
+	prices.On("PublishScheduledChanges", mock.Anything, mock.Anything).Return(0, nil).Run(func(mock.Arguments) {
+		select {
+		case checked <- struct{}{}:
+		default:
+		}
+	})
+	scheduler := NewScheduler(prices)
+
+	scheduler.Start(time.Millisecond)
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("the schedule was not checked")
+	}
+	scheduler.Stop()
+}
@@ -1,6 +1,7 @@
 package ratelimit
 
 import (
+	"math"
 	"sync"
 	"time"
 )
@@ -11,6 +12,23 @@ type RateLimiter interface {
 	Reset(key string)
 }
 
+// Quota is what is left of a caller's limit after a request
+type Quota struct {
+	Limit     int           // Requests allowed back to back
+	Remaining int           // Requests left right now
+	Reset     time.Duration // Until the full limit is available again
+	// RetryAfter is how long a rejected caller has to wait for its next request
+	RetryAfter time.Duration
+}
+
+// QuotaLimiter is a RateLimiter that reports the caller's quota, which the
+// rate limit middleware sends as X-RateLimit headers
+type QuotaLimiter interface {
+	RateLimiter
+	// Take counts a request like Allow and returns the quota left after it
+	Take(key string) (bool, Quota)
+}
+
 // TokenBucketLimiter implements the token bucket algorithm
 type TokenBucketLimiter struct {
 	mu           sync.RWMutex
@@ -32,6 +50,13 @@ func NewTokenBucketLimiter(rate float64, capacity float64) *TokenBucketLimiter {
 }
 
 func (l *TokenBucketLimiter) Allow(key string) bool {
+	allowed, _ := l.Take(key)
+	return allowed
+}
+
+// Take takes a token from the caller's bucket. The quota's limit is the
+// bucket size, and it resets once the bucket has refilled.
+func (l *TokenBucketLimiter) Take(key string) (bool, Quota) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -41,7 +66,6 @@ func (l *TokenBucketLimiter) Allow(key string) bool {
 	if _, exists := l.tokens[key]; !exists {
 		l.tokens[key] = l.capacity
 		l.lastRefill[key] = now
-		return true
 	}
 
 	// Calculate tokens to refill
@@ -55,12 +79,28 @@ func (l *TokenBucketLimiter) Allow(key string) bool {
 
 	// Check if we have enough tokens
 	if currentTokens < 1 {
-		return false
+		quota := l.quota(currentTokens)
+		quota.RetryAfter = l.refillTime(1 - currentTokens)
+		return false, quota
 	}
 
 	l.tokens[key] = currentTokens - 1
 	l.lastRefill[key] = now
-	return true
+	return true, l.quota(currentTokens - 1)
+}
+
+// quota reports a bucket holding tokens
+func (l *TokenBucketLimiter) quota(tokens float64) Quota {
+	return Quota{
+		Limit:     int(l.capacity),
+		Remaining: int(math.Floor(tokens)),
+		Reset:     l.refillTime(l.capacity - tokens),
+	}
+}
+
+// refillTime returns how long refilling the tokens takes
+func (l *TokenBucketLimiter) refillTime(tokens float64) time.Duration {
+	return time.Duration(tokens / l.rate * float64(l.refillPeriod))
 }
 
 func (l *TokenBucketLimiter) Reset(key string) {
@@ -87,6 +127,13 @@ func NewSlidingWindowLimiter(limit int, duration time.Duration) *SlidingWindowLi
 }
 
 func (l *SlidingWindowLimiter) Allow(key string) bool {
+	allowed, _ := l.Take(key)
+	return allowed
+}
+
+// Take records a request in the caller's window. The quota resets when the
+// newest request leaves the window.
+func (l *SlidingWindowLimiter) Take(key string) (bool, Quota) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
@@ -110,14 +157,27 @@ func (l *SlidingWindowLimiter) Allow(key string) bool {
 	// Check if we are over the limit
 	if len(validWindow) >= l.limit {
 		l.windows[key] = validWindow // Save the updated window
-		return false
+		quota := l.quota(validWindow, now)
+		if len(validWindow) > 0 {
+			quota.RetryAfter = validWindow[0].Add(l.duration).Sub(now)
+		}
+		return false, quota
 	}
 
 	// Add new timestamp and save
 	validWindow = append(validWindow, now)
 	l.windows[key] = validWindow
 
-	return true
+	return true, l.quota(validWindow, now)
+}
+
+// quota reports a window of requests
+func (l *SlidingWindowLimiter) quota(window []time.Time, now time.Time) Quota {
+	quota := Quota{Limit: l.limit, Remaining: l.limit - len(window)}
+	if len(window) > 0 {
+		quota.Reset = window[len(window)-1].Add(l.duration).Sub(now)
+	}
+	return quota
 }
 
 func (l *SlidingWindowLimiter) Reset(key string) {
@@ -88,3 +88,37 @@ func TestConcurrentAccess(t *testing.T) {
 		<-done
 	}
 }
+
+func TestTokenBucketQuota(t *testing.T) {
+	limiter := NewTokenBucketLimiter(2, 3)
+
+	allowed, quota := limiter.Take("key")
+	assert.True(t, allowed)
+	assert.Equal(t, 3, quota.Limit)
+	assert.Equal(t, 2, quota.Remaining)
+	assert.InDelta(t, float64(500*time.Millisecond), float64(quota.Reset), float64(10*time.Millisecond))
+
+	limiter.Take("key")
+	limiter.Take("key")
+	allowed, quota = limiter.Take("key")
+	assert.False(t, allowed)
+	assert.Equal(t, 0, quota.Remaining)
+	assert.InDelta(t, float64(500*time.Millisecond), float64(quota.RetryAfter), float64(10*time.Millisecond))
+	assert.InDelta(t, float64(1500*time.Millisecond), float64(quota.Reset), float64(10*time.Millisecond))
+}
+
+func TestSlidingWindowQuota(t *testing.T) {
+	limiter := NewSlidingWindowLimiter(2, time.Second)
+
+	allowed, quota := limiter.Take("key")
+	assert.True(t, allowed)
+	assert.Equal(t, Quota{Limit: 2, Remaining: 1, Reset: quota.Reset}, quota)
+	assert.InDelta(t, float64(time.Second), float64(quota.Reset), float64(10*time.Millisecond))
+
+	limiter.Take("key")
+	allowed, quota = limiter.Take("key")
+	assert.False(t, allowed)
+	assert.Equal(t, 0, quota.Remaining)
+	assert.Greater(t, quota.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, quota.RetryAfter, quota.Reset)
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RoutePolicy is a limit on one route, e.g. 1 request per second for
+// POST /products/batch. An empty method applies it to every method.
+type RoutePolicy struct {
+	Method string
+	Route  string // Route template as registered, e.g. /products/{id}
+	Rate   float64
+	Burst  float64
+}
+
+// PolicyRegistry holds the route policies. Each policy keeps a bucket per
+// caller, counted on top of the caller's tier, so a route can only be
+// stricter than the tier.
+type PolicyRegistry struct {
+	limiters map[string]*TokenBucketLimiter // by policyKey
+}
+
+// NewPolicyRegistry creates a registry of the policies. A route and method
+// can only have one policy.
+func NewPolicyRegistry(policies []RoutePolicy) (*PolicyRegistry, error) {
+	registry := &PolicyRegistry{limiters: make(map[string]*TokenBucketLimiter, len(policies))}
+	for _, policy := range policies {
+		if !strings.HasPrefix(policy.Route, "/") {
+			return nil, fmt.Errorf("rate limit policy route %q must start with /", policy.Route)
+		}
+		if policy.Rate <= 0 || policy.Burst < 1 {
+			return nil, fmt.Errorf("rate limit policy for %s needs a positive rate and a burst of at least 1", policyName(policy.Method, policy.Route))
+		}
+		key := policyKey(strings.ToUpper(policy.Method), policy.Route)
+		if _, ok := registry.limiters[key]; ok {
+			return nil, fmt.Errorf("rate limit policy for %s is defined twice", policyName(policy.Method, policy.Route))
+		}
+		registry.limiters[key] = NewTokenBucketLimiter(policy.Rate, policy.Burst)
+	}
+	return registry, nil
+}
+
+// Lookup returns the limiter of the policy for a method and route template.
+// A policy for the method wins over one for every method.
+func (r *PolicyRegistry) Lookup(method, route string) (*TokenBucketLimiter, bool) {
+	if r == nil {
+		return nil, false
+	}
+	if limiter, ok := r.limiters[policyKey(method, route)]; ok {
+		return limiter, true
+	}
+	limiter, ok := r.limiters[policyKey("", route)]
+	return limiter, ok
+}
+
+// Len returns the number of policies
+func (r *PolicyRegistry) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.limiters)
+}
+
+func policyKey(method, route string) string {
+	return method + " " + route
+}
+
+// policyName names a policy in errors
+func policyName(method, route string) string {
+	if method == "" {
+		return route
+	}
+	return strings.ToUpper(method) + " " + route
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyRegistryLookup(t *testing.T) {
+	registry, err := NewPolicyRegistry([]RoutePolicy{
+		{Method: "post", Route: "/products/batch", Rate: 1, Burst: 1},
+		{Route: "/products/batch", Rate: 5, Burst: 5},
+		{Route: "/products/search", Rate: 20, Burst: 20},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, registry.Len())
+
+	// The policy for the method wins over the one for every method
+	post, ok := registry.Lookup("POST", "/products/batch")
+	assert.True(t, ok)
+	_, quota := post.Take("client")
+	assert.Equal(t, 1, quota.Limit)
+	put, ok := registry.Lookup("PUT", "/products/batch")
+	assert.True(t, ok)
+	_, quota = put.Take("client")
+	assert.Equal(t, 5, quota.Limit)
+
+	_, ok = registry.Lookup("GET", "/products")
+	assert.False(t, ok)
+
+	var empty *PolicyRegistry
+	_, ok = empty.Lookup("GET", "/products")
+	assert.False(t, ok)
+	assert.Equal(t, 0, empty.Len())
+}
+
+func TestNewPolicyRegistryErrors(t *testing.T) {
+	tests := map[string][]RoutePolicy{
+		"Relative route": {{Route: "products", Rate: 1, Burst: 1}},
+		"Zero rate":      {{Route: "/products", Rate: 0, Burst: 1}},
+		"Small burst":    {{Route: "/products", Rate: 1, Burst: 0.5}},
+		"Duplicate":      {{Method: "GET", Route: "/products", Rate: 1, Burst: 1}, {Method: "get", Route: "/products", Rate: 2, Burst: 2}},
+	}
+	for name, policies := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewPolicyRegistry(policies)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tier names with a fixed meaning. Every TieredLimiter has a default tier;
+// unauthenticated callers are put in the anonymous tier when there is one.
+const (
+	DefaultTier   = "default"
+	AnonymousTier = "anonymous"
+)
+
+// rolePrefix marks role assignments, e.g. role:editor=partner
+const rolePrefix = "role:"
+
+// Tier is a named limit: a token bucket refilling at Rate requests per
+// second and holding up to Burst
+type Tier struct {
+	Name  string
+	Rate  float64
+	Burst float64
+}
+
+// Assignments put callers into tiers, by subject first, e.g. the
+// "api-key:3f2a9c1b7d4e" of an API key, and then by the first of their roles
+// with a tier
+type Assignments struct {
+	Subjects map[string]string
+	Roles    map[string]string
+}
+
+// ParseTiers parses a list of tiers such as "partner:100:200,anonymous:10",
+// i.e. name:per_second[:burst]. The burst defaults to the rate.
+func ParseTiers(value string) ([]Tier, error) {
+	var tiers []Tier
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid rate limit tier %q, expected name:per_second[:burst]", entry)
+		}
+		rate, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid rate %q for tier %s", parts[1], parts[0])
+		}
+		tier := Tier{Name: parts[0], Rate: rate, Burst: rate}
+		if len(parts) == 3 {
+			if tier.Burst, err = strconv.ParseFloat(parts[2], 64); err != nil || tier.Burst < 1 {
+				return nil, fmt.Errorf("invalid burst %q for tier %s", parts[2], parts[0])
+			}
+		}
+		if tier.Burst < 1 {
+			tier.Burst = 1
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, nil
+}
+
+// ParseAssignments parses a list of assignments such as
+// "api-key:3f2a9c1b7d4e=partner,role:editor=internal", i.e. subject=tier or
+// role:name=tier
+func ParseAssignments(value string) (Assignments, error) {
+	assignments := Assignments{Subjects: make(map[string]string), Roles: make(map[string]string)}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		caller, tier, ok := strings.Cut(entry, "=")
+		caller, tier = strings.TrimSpace(caller), strings.TrimSpace(tier)
+		if !ok || caller == "" || tier == "" {
+			return Assignments{}, fmt.Errorf("invalid rate limit assignment %q, expected subject=tier or role:name=tier", entry)
+		}
+		if role, isRole := strings.CutPrefix(caller, rolePrefix); isRole {
+			assignments.Roles[role] = tier
+		} else {
+			assignments.Subjects[caller] = tier
+		}
+	}
+	return assignments, nil
+}
+
+// TieredLimiter limits each caller with the tier it is assigned to. Every
+// caller has its own bucket in its tier.
+type TieredLimiter struct {
+	limiters    map[string]*TokenBucketLimiter
+	assignments Assignments
+}
+
+// NewTieredLimiter creates a limiter for the tiers, which must include
+// DefaultTier. Assignments to tiers that do not exist are an error.
+func NewTieredLimiter(tiers []Tier, assignments Assignments) (*TieredLimiter, error) {
+	limiter := &TieredLimiter{limiters: make(map[string]*TokenBucketLimiter, len(tiers)), assignments: assignments}
+	for _, tier := range tiers {
+		if _, ok := limiter.limiters[tier.Name]; ok {
+			return nil, fmt.Errorf("rate limit tier %s is defined twice", tier.Name)
+		}
+		limiter.limiters[tier.Name] = NewTokenBucketLimiter(tier.Rate, tier.Burst)
+	}
+	if _, ok := limiter.limiters[DefaultTier]; !ok {
+		return nil, fmt.Errorf("rate limit tiers need a %s tier", DefaultTier)
+	}
+	for _, assigned := range []map[string]string{assignments.Subjects, assignments.Roles} {
+		for caller, tier := range assigned {
+			if _, ok := limiter.limiters[tier]; !ok {
+				return nil, fmt.Errorf("%s is assigned to unknown rate limit tier %s", caller, tier)
+			}
+		}
+	}
+	return limiter, nil
+}
+
+// TierFor returns the tier of a caller. An empty subject is an
+// unauthenticated caller.
+func (l *TieredLimiter) TierFor(subject string, roles []string) string {
+	if subject == "" {
+		if _, ok := l.limiters[AnonymousTier]; ok {
+			return AnonymousTier
+		}
+		return DefaultTier
+	}
+	if tier, ok := l.assignments.Subjects[subject]; ok {
+		return tier
+	}
+	for _, role := range roles {
+		if tier, ok := l.assignments.Roles[role]; ok {
+			return tier
+		}
+	}
+	return DefaultTier
+}
+
+// Take counts a request of the caller with the key in a tier
+func (l *TieredLimiter) Take(tier, key string) (bool, Quota) {
+	limiter, ok := l.limiters[tier]
+	if !ok {
+		limiter = l.limiters[DefaultTier]
+	}
+	return limiter.Take(key)
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTiers(t *testing.T) {
+	tiers, err := ParseTiers("partner:100:200, anonymous:10,,slow:0.5")
+	assert.NoError(t, err)
+	assert.Equal(t, []Tier{
+		{Name: "partner", Rate: 100, Burst: 200},
+		{Name: "anonymous", Rate: 10, Burst: 10},
+		{Name: "slow", Rate: 0.5, Burst: 1},
+	}, tiers)
+
+	for _, value := range []string{"partner", "partner:fast", "partner:0", "partner:10:0", ":10", "a:1:2:3"} {
+		_, err := ParseTiers(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseAssignments(t *testing.T) {
+	assignments, err := ParseAssignments("api-key:3f2a9c1b7d4e=partner, role:editor=internal")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"api-key:3f2a9c1b7d4e": "partner"}, assignments.Subjects)
+	assert.Equal(t, map[string]string{"editor": "internal"}, assignments.Roles)
+
+	for _, value := range []string{"partner", "=partner", "user-1="} {
+		_, err := ParseAssignments(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestTieredLimiter(t *testing.T) {
+	assignments, _ := ParseAssignments("user-1=partner,role:editor=internal")
+	tiers := []Tier{{Name: DefaultTier, Rate: 1, Burst: 1}, {Name: "partner", Rate: 100, Burst: 100}, {Name: "internal", Rate: 10, Burst: 10}}
+	limiter, err := NewTieredLimiter(tiers, assignments)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "partner", limiter.TierFor("user-1", []string{"editor"}))
+	assert.Equal(t, "internal", limiter.TierFor("user-2", []string{"viewer", "editor"}))
+	assert.Equal(t, DefaultTier, limiter.TierFor("user-3", []string{"viewer"}))
+	// Without an anonymous tier unauthenticated callers get the default
+	assert.Equal(t, DefaultTier, limiter.TierFor("", nil))
+
+	allowed, quota := limiter.Take("partner", "user-1")
+	assert.True(t, allowed)
+	assert.Equal(t, 100, quota.Limit)
+	limiter.Take(DefaultTier, "user-3")
+	allowed, _ = limiter.Take(DefaultTier, "user-3")
+	assert.False(t, allowed)
+	// Callers have their own buckets
+	allowed, _ = limiter.Take(DefaultTier, "user-4")
+	assert.True(t, allowed)
+
+	limiter, err = NewTieredLimiter(append(tiers, Tier{Name: AnonymousTier, Rate: 5, Burst: 5}), Assignments{})
+	assert.NoError(t, err)
+	assert.Equal(t, AnonymousTier, limiter.TierFor("", nil))
+}
+
+func TestNewTieredLimiterErrors(t *testing.T) {
+	_, err := NewTieredLimiter([]Tier{{Name: "partner", Rate: 1, Burst: 1}}, Assignments{})
+	assert.Error(t, err)
+
+	_, err = NewTieredLimiter([]Tier{{Name: DefaultTier, Rate: 1, Burst: 1}, {Name: DefaultTier, Rate: 2, Burst: 2}}, Assignments{})
+	assert.Error(t, err)
+
+	_, err = NewTieredLimiter([]Tier{{Name: DefaultTier, Rate: 1, Burst: 1}}, Assignments{Roles: map[string]string{"editor": "missing"}})
+	assert.Error(t, err)
+}
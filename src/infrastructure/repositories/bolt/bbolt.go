@@ -0,0 +1,86 @@
+//go:build bolt
+
+package bolt
+
+import (
+	"bytes"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+// Open opens the database file at path, creating it if needed. bbolt syncs
+// every commit to disk and, after a crash, starts from the last committed
+// transaction, so a write is either fully there or not at all.
+func Open(path string, options Options) (Store, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: options.LockTimeout})
+	if err != nil {
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+type boltStore struct {
+	db *bbolt.DB
+}
+
+func (s *boltStore) View(fn func(tx Tx) error) error {
+	return s.db.View(func(tx *bbolt.Tx) error { return fn(boltTx{tx: tx}) })
+}
+
+func (s *boltStore) Update(fn func(tx Tx) error) error {
+	return s.db.Update(func(tx *bbolt.Tx) error { return fn(boltTx{tx: tx}) })
+}
+
+func (s *boltStore) Close() error {
+	return s.db.Close()
+}
+
+type boltTx struct {
+	tx *bbolt.Tx
+}
+
+func (t boltTx) Bucket(name string) Bucket {
+	bucket := t.tx.Bucket([]byte(name))
+	if bucket == nil {
+		return nil
+	}
+	return boltBucket{bucket: bucket}
+}
+
+func (t boltTx) CreateBucket(name string) (Bucket, error) {
+	bucket, err := t.tx.CreateBucketIfNotExists([]byte(name))
+	if err != nil {
+		return nil, err
+	}
+	return boltBucket{bucket: bucket}, nil
+}
+
+type boltBucket struct {
+	bucket *bbolt.Bucket
+}
+
+func (b boltBucket) Get(key []byte) []byte {
+	return b.bucket.Get(key)
+}
+
+func (b boltBucket) Put(key, value []byte) error {
+	return b.bucket.Put(key, value)
+}
+
+func (b boltBucket) Delete(key []byte) error {
+	return b.bucket.Delete(key)
+}
+
+func (b boltBucket) Scan(prefix []byte, fn func(key, value []byte) error) error {
+	cursor := b.bucket.Cursor()
+	for key, value := cursor.Seek(prefix); key != nil && bytes.HasPrefix(key, prefix); key, value = cursor.Next() {
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b boltBucket) NextSequence() (uint64, error) {
+	return b.bucket.NextSequence()
+}
@@ -0,0 +1,8 @@
+//go:build !bolt
+
+package bolt
+
+// Open fails: no embedded store is linked
+func Open(path string, options Options) (Store, error) {
+	return nil, ErrStoreNotLinked
+}
@@ -0,0 +1,220 @@
+package bolt
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// OutboxRepository implements repositories.OutboxRepository on the outbox
+// buckets of a Store opened by NewProductRepository. Entries are keyed by
+// the order they were added, and every call runs in one transaction.
+type OutboxRepository struct {
+	store Store
+}
+
+// NewOutboxRepository creates an outbox repository on a store recovered by NewProductRepository
+func NewOutboxRepository(store Store) repositories.OutboxRepository {
+	return &OutboxRepository{store: store}
+}
+
+// addOutboxEntries queues events as pending, skipping events already in the outbox
+func addOutboxEntries(tx Tx, now time.Time, events ...*models.Event) error {
+	entries, ids := tx.Bucket(outboxBucket), tx.Bucket(outboxIDsBucket)
+	for _, event := range events {
+		if ids.Get([]byte(event.ID)) != nil {
+			continue
+		}
+		sequence, err := entries.NextSequence()
+		if err != nil {
+			return err
+		}
+		key := binary.BigEndian.AppendUint64(nil, sequence)
+		entry := &models.OutboxEntry{
+			Event:       event,
+			State:       models.OutboxPending,
+			NextAttempt: now,
+			CreatedAt:   now,
+		}
+		if err := putOutboxEntry(entries, key, entry); err != nil {
+			return err
+		}
+		if err := ids.Put([]byte(event.ID), key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add queues events as pending, skipping events already in the outbox
+func (r *OutboxRepository) Add(events ...*models.Event) error {
+	return r.store.Update(func(tx Tx) error {
+		return addOutboxEntries(tx, time.Now(), events...)
+	})
+}
+
+// Pending returns the pending entries, oldest first
+func (r *OutboxRepository) Pending(limit int) ([]*models.OutboxEntry, error) {
+	pending := make([]*models.OutboxEntry, 0)
+	err := r.store.View(func(tx Tx) error {
+		return scanOutbox(tx, func(key []byte, entry *models.OutboxEntry) (bool, error) {
+			if entry.State == models.OutboxPending {
+				pending = append(pending, entry)
+			}
+			return limit <= 0 || len(pending) < limit, nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// MarkDelivered records that an event was published
+func (r *OutboxRepository) MarkDelivered(eventID string, at time.Time) error {
+	return r.updateEntry(eventID, func(entry *models.OutboxEntry) {
+		entry.State = models.OutboxDelivered
+		entry.Attempts++
+		entry.LastError = ""
+		entry.DeliveredAt = &at
+	})
+}
+
+// MarkAttemptFailed records a failed attempt and when to retry it
+func (r *OutboxRepository) MarkAttemptFailed(eventID string, reason string, retryAt time.Time) error {
+	return r.updateEntry(eventID, func(entry *models.OutboxEntry) {
+		entry.Attempts++
+		entry.LastError = reason
+		if retryAt.IsZero() {
+			entry.State = models.OutboxFailed
+			return
+		}
+		entry.NextAttempt = retryAt
+	})
+}
+
+// updateEntry changes the entry of an event, or fails with ErrOutboxEntryNotFound
+func (r *OutboxRepository) updateEntry(eventID string, change func(entry *models.OutboxEntry)) error {
+	return r.store.Update(func(tx Tx) error {
+		entries := tx.Bucket(outboxBucket)
+		key := tx.Bucket(outboxIDsBucket).Get([]byte(eventID))
+		if key == nil {
+			return models.ErrOutboxEntryNotFound
+		}
+		entry, err := decodeOutboxEntry(entries.Get(key))
+		if err != nil {
+			return err
+		}
+		change(entry)
+		return putOutboxEntry(entries, append([]byte(nil), key...), entry)
+	})
+}
+
+// Stats counts the entries by state
+func (r *OutboxRepository) Stats() (models.OutboxStats, error) {
+	var stats models.OutboxStats
+	err := r.store.View(func(tx Tx) error {
+		return scanOutbox(tx, func(key []byte, entry *models.OutboxEntry) (bool, error) {
+			switch entry.State {
+			case models.OutboxPending:
+				stats.Pending++
+			case models.OutboxDelivered:
+				stats.Delivered++
+			case models.OutboxFailed:
+				stats.Failed++
+			}
+			return true, nil
+		})
+	})
+	return stats, err
+}
+
+// Prune removes the entries delivered before the given time
+func (r *OutboxRepository) Prune(before time.Time) (int, error) {
+	pruned := 0
+	err := r.store.Update(func(tx Tx) error {
+		var keys, ids [][]byte
+		err := scanOutbox(tx, func(key []byte, entry *models.OutboxEntry) (bool, error) {
+			if entry.State == models.OutboxDelivered && entry.DeliveredAt.Before(before) {
+				keys = append(keys, append([]byte(nil), key...))
+				ids = append(ids, []byte(entry.Event.ID))
+			}
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+		for i := range keys {
+			if err := tx.Bucket(outboxBucket).Delete(keys[i]); err != nil {
+				return err
+			}
+			if err := tx.Bucket(outboxIDsBucket).Delete(ids[i]); err != nil {
+				return err
+			}
+		}
+		pruned = len(keys)
+		return nil
+	})
+	return pruned, err
+}
+
+// errStopScan ends a scan of the outbox early
+var errStopScan = errors.New("stop scan")
+
+// scanOutbox decodes the entries in the order they were added until fn
+// returns false or an error
+func scanOutbox(tx Tx, fn func(key []byte, entry *models.OutboxEntry) (bool, error)) error {
+	err := tx.Bucket(outboxBucket).Scan(nil, func(key, value []byte) error {
+		entry, err := decodeOutboxEntry(value)
+		if err != nil {
+			return err
+		}
+		more, err := fn(key, entry)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return errStopScan
+		}
+		return nil
+	})
+	if err == errStopScan {
+		return nil
+	}
+	return err
+}
+
+// putOutboxEntry stores an entry under its key
+func putOutboxEntry(entries Bucket, key []byte, entry *models.OutboxEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return entries.Put(key, data)
+}
+
+// storedOutboxEntry decodes a stored entry, with the event decoded by models.DecodeEvent
+type storedOutboxEntry struct {
+	models.OutboxEntry
+	Event json.RawMessage `json:"event"`
+}
+
+// decodeOutboxEntry decodes a stored entry
+func decodeOutboxEntry(data []byte) (*models.OutboxEntry, error) {
+	var stored storedOutboxEntry
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("invalid outbox entry: %v", err)
+	}
+	entry := stored.OutboxEntry
+	event, err := models.DecodeEvent(stored.Event)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outbox entry: %v", err)
+	}
+	entry.Event = event
+	return &entry, nil
+}
@@ -0,0 +1,87 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxQueuesCommittedEvents(t *testing.T) {
+	repo, store := setupRepository(t)
+	ctx := context.Background()
+	assert.NoError(t, repo.StoreEvent(ctx, &models.Event{ID: "evt_before", EntityID: "prod_0"}))
+	outbox := repo.Outbox()
+	product := createTestProduct("prod_1", time.Now())
+	event := func(id string, version int64) *models.Event {
+		return &models.Event{ID: id, Type: models.EventProductUpdated, EntityID: "prod_1", Version: version,
+			Data: &models.ProductEvent{ProductID: "prod_1", Action: "updated", Product: product, Version: version}}
+	}
+
+	failed := errors.New("failed")
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		assert.NoError(t, tx.StoreEvent(ctx, event("evt_failed", 1)))
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	assert.NoError(t, repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, event("evt_1", 1)); err != nil {
+			return err
+		}
+		return tx.Create(ctx, product)
+	}))
+	assert.NoError(t, repo.StoreEvent(ctx, event("evt_2", 2)))
+	assert.NoError(t, outbox.Add(event("evt_1", 1)))
+
+	// Only the committed events stored after the outbox was enabled are
+	// pending, also after reopening the store
+	_, err = NewProductRepository(store)
+	assert.NoError(t, err)
+	pending, err := NewOutboxRepository(store).Pending(0)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 2) {
+		assert.Equal(t, "evt_1", pending[0].Event.ID)
+		assert.Equal(t, "evt_2", pending[1].Event.ID)
+		assert.Equal(t, "prod_1", pending[0].Event.Data.(*models.ProductEvent).Product.ID)
+	}
+	pending, err = outbox.Pending(1)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
+
+func TestOutboxAttempts(t *testing.T) {
+	repo, _ := setupRepository(t)
+	outbox := repo.Outbox()
+	assert.NoError(t, outbox.Add(&models.Event{ID: "evt_1"}, &models.Event{ID: "evt_2"}, &models.Event{ID: "evt_3"}))
+	now := time.Now()
+
+	assert.NoError(t, outbox.MarkDelivered("evt_1", now.Add(-time.Hour)))
+	assert.NoError(t, outbox.MarkAttemptFailed("evt_2", "broker down", now.Add(time.Minute)))
+	assert.NoError(t, outbox.MarkAttemptFailed("evt_3", "broker down", time.Time{}))
+	assert.ErrorIs(t, outbox.MarkDelivered("evt_missing", now), models.ErrOutboxEntryNotFound)
+	assert.ErrorIs(t, outbox.MarkAttemptFailed("evt_missing", "", now), models.ErrOutboxEntryNotFound)
+
+	pending, err := outbox.Pending(0)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, "evt_2", pending[0].Event.ID)
+		assert.Equal(t, 1, pending[0].Attempts)
+		assert.Equal(t, "broker down", pending[0].LastError)
+		assert.True(t, now.Add(time.Minute).Equal(pending[0].NextAttempt))
+	}
+	stats, err := outbox.Stats()
+	assert.NoError(t, err)
+	assert.Equal(t, models.OutboxStats{Pending: 1, Delivered: 1, Failed: 1}, stats)
+
+	// A pruned event can be queued again
+	pruned, err := outbox.Prune(now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+	assert.NoError(t, outbox.Add(&models.Event{ID: "evt_1"}))
+	stats, _ = outbox.Stats()
+	assert.Equal(t, models.OutboxStats{Pending: 2, Failed: 1}, stats)
+}
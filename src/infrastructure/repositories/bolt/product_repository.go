@@ -0,0 +1,423 @@
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// schemaVersion is the layout of the buckets written by this version
+const schemaVersion = 1
+
+// Buckets the repository keeps its data in
+const (
+	metaBucket      = "meta"       // schema_version
+	productsBucket  = "products"   // Product ID to product JSON
+	skusBucket      = "skus"       // SKU to product ID
+	eventsBucket    = "events"     // Product ID, a zero byte and the big-endian event sequence to event JSON
+	outboxBucket    = "outbox"     // Big-endian outbox sequence to outbox entry JSON
+	outboxIDsBucket = "outbox_ids" // Event ID to its outbox key
+)
+
+var schemaVersionKey = []byte("schema_version")
+
+// ProductRepository implements repositories.ProductRepository on a Store.
+// Every call runs in one transaction of the store, so writes are atomic and
+// survive a crash once they return. The store has no cancellation; calls
+// fail with the context's error when it is done before they start.
+type ProductRepository struct {
+	store  Store
+	tx     Tx   // Set on the repository a WithTx callback gets
+	outbox bool // Whether StoreEvent queues events in the outbox
+}
+
+// NewProductRepository creates a repository on a store and recovers it: it
+// creates missing buckets, refuses a store written by a newer version and
+// rebuilds the SKU index from the stored products
+func NewProductRepository(store Store) (*ProductRepository, error) {
+	err := store.Update(func(tx Tx) error {
+		buckets := make(map[string]Bucket)
+		for _, name := range []string{metaBucket, productsBucket, skusBucket, eventsBucket, outboxBucket, outboxIDsBucket} {
+			bucket, err := tx.CreateBucket(name)
+			if err != nil {
+				return err
+			}
+			buckets[name] = bucket
+		}
+		if err := checkSchemaVersion(buckets[metaBucket]); err != nil {
+			return err
+		}
+		return rebuildSKUIndex(buckets[productsBucket], buckets[skusBucket])
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover embedded store: %w", err)
+	}
+	return &ProductRepository{store: store}, nil
+}
+
+// checkSchemaVersion records the schema version of a new store and fails on
+// one written by a newer version
+func checkSchemaVersion(meta Bucket) error {
+	stored := meta.Get(schemaVersionKey)
+	if stored == nil {
+		return meta.Put(schemaVersionKey, []byte(strconv.Itoa(schemaVersion)))
+	}
+	version, err := strconv.Atoi(string(stored))
+	if err != nil {
+		return fmt.Errorf("invalid schema version %q", stored)
+	}
+	if version > schemaVersion {
+		return fmt.Errorf("store has schema version %d, this version reads up to %d", version, schemaVersion)
+	}
+	return nil
+}
+
+// rebuildSKUIndex replaces the SKU index with the SKUs of the stored
+// products, which also checks that every product decodes
+func rebuildSKUIndex(products, skus Bucket) error {
+	var stale [][]byte
+	err := skus.Scan(nil, func(key, value []byte) error {
+		stale = append(stale, append([]byte(nil), key...))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, key := range stale {
+		if err := skus.Delete(key); err != nil {
+			return err
+		}
+	}
+	return products.Scan(nil, func(key, value []byte) error {
+		product, err := decodeProduct(value)
+		if err != nil {
+			return fmt.Errorf("product %s: %v", key, err)
+		}
+		if product.SKU == "" {
+			return nil
+		}
+		return skus.Put([]byte(product.SKU), []byte(product.ID))
+	})
+}
+
+// view runs fn in a read-only transaction, or the repository's own
+func (r *ProductRepository) view(ctx context.Context, fn func(tx Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	return r.store.View(fn)
+}
+
+// update runs fn in a read-write transaction, or the repository's own
+func (r *ProductRepository) update(ctx context.Context, fn func(tx Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	return r.store.Update(fn)
+}
+
+// WithTx runs fn in one read-write transaction of the store, which holds off
+// every other write until it ends
+func (r *ProductRepository) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	return r.update(ctx, func(tx Tx) error {
+		return fn(&ProductRepository{store: r.store, tx: tx, outbox: r.outbox})
+	})
+}
+
+// Create stores a product, replacing a stored product with the same ID like the memory repository does
+func (r *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	return r.update(ctx, func(tx Tx) error {
+		return putProduct(tx, product, false)
+	})
+}
+
+// GetByID retrieves a product by its ID
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (product *models.Product, err error) {
+	err = r.view(ctx, func(tx Tx) error {
+		product, err = getProduct(tx, id)
+		return err
+	})
+	return product, err
+}
+
+// GetBySKU retrieves a product by its SKU
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (product *models.Product, err error) {
+	err = r.view(ctx, func(tx Tx) error {
+		id := tx.Bucket(skusBucket).Get([]byte(sku))
+		if sku == "" || id == nil {
+			return models.ErrProductNotFound
+		}
+		product, err = getProduct(tx, string(id))
+		return err
+	})
+	return product, err
+}
+
+// Update modifies an existing product
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product) error {
+	return r.update(ctx, func(tx Tx) error {
+		return putProduct(tx, product, true)
+	})
+}
+
+// AdjustStock applies stock adjustments in one read-write transaction, so no
+// other write acts on the same quantities
+func (r *ProductRepository) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (current, updated *models.Product, err error) {
+	err = r.update(ctx, func(tx Tx) error {
+		if current, err = getProduct(tx, productID); err != nil {
+			return err
+		}
+		if updated, err = current.WithStockAdjustments(adjustments, time.Now()); err != nil {
+			return err
+		}
+		return putProduct(tx, updated, true)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, updated, nil
+}
+
+// Delete removes a product from storage; its events are kept
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	return r.update(ctx, func(tx Tx) error {
+		product, err := getProduct(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := releaseSKU(tx, product); err != nil {
+			return err
+		}
+		return tx.Bucket(productsBucket).Delete([]byte(id))
+	})
+}
+
+// List returns a page of the products that match the filter, newest first
+func (r *ProductRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	products, err := r.matching(ctx, filter, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(products)
+	start := (page - 1) * pageSize
+	if start < 0 || pageSize <= 0 || start >= total {
+		return []*models.Product{}, total, nil
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+	return products[start:end], total, nil
+}
+
+// ListAfter returns up to limit matching products after the cursor
+func (r *ProductRepository) ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) ([]*models.Product, *models.ProductCursor, error) {
+	products, err := r.matching(ctx, filter, after)
+	if err != nil {
+		return nil, nil, err
+	}
+	if limit <= 0 || len(products) <= limit {
+		return products, nil, nil
+	}
+	return products[:limit], models.CursorFor(products[limit-1]), nil
+}
+
+// matching decodes the stored products that match the filter and follow the
+// cursor, in listing order. The store keeps products by ID, so every
+// listing reads them all.
+func (r *ProductRepository) matching(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor) ([]*models.Product, error) {
+	filter = filter.Normalize()
+	products := make([]*models.Product, 0)
+	err := r.view(ctx, func(tx Tx) error {
+		return tx.Bucket(productsBucket).Scan(nil, func(key, value []byte) error {
+			product, err := decodeProduct(value)
+			if err != nil {
+				return err
+			}
+			if filter.Matches(product) && after.Follows(product) {
+				products = append(products, product)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	models.SortForListing(products)
+	return products, nil
+}
+
+// StoreEvent appends an event to the product's events and, with the outbox
+// enabled, queues it in the same transaction
+func (r *ProductRepository) StoreEvent(ctx context.Context, event *models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.update(ctx, func(tx Tx) error {
+		events := tx.Bucket(eventsBucket)
+		sequence, err := events.NextSequence()
+		if err != nil {
+			return err
+		}
+		if err := events.Put(binary.BigEndian.AppendUint64(eventPrefix(event.EntityID), sequence), data); err != nil {
+			return err
+		}
+		if !r.outbox {
+			return nil
+		}
+		return addOutboxEntries(tx, time.Now(), event)
+	})
+}
+
+// Outbox returns the outbox in the store and queues every event stored from
+// then on in it. It must not be called while the repository is written.
+func (r *ProductRepository) Outbox() repositories.OutboxRepository {
+	r.outbox = true
+	return NewOutboxRepository(r.store)
+}
+
+// GetEventsByProductID returns a product's events from a version on, in storage order
+func (r *ProductRepository) GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
+	return r.events(ctx, eventPrefix(productID), func(event *models.Event) bool {
+		return event.Version >= fromVersion
+	})
+}
+
+// GetEventsUntil returns all product events recorded at or before the given time in the order they happened
+func (r *ProductRepository) GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error) {
+	events, err := r.events(ctx, nil, func(event *models.Event) bool {
+		return !event.Timestamp.After(until)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Events are stored by product, so merge them back into the order they happened
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Timestamp.Equal(events[j].Timestamp) {
+			return events[i].Timestamp.Before(events[j].Timestamp)
+		}
+		return events[i].Sequence < events[j].Sequence
+	})
+	return events, nil
+}
+
+// events decodes the stored events under a key prefix that the filter keeps
+func (r *ProductRepository) events(ctx context.Context, prefix []byte, keep func(event *models.Event) bool) ([]*models.Event, error) {
+	events := make([]*models.Event, 0)
+	err := r.view(ctx, func(tx Tx) error {
+		return tx.Bucket(eventsBucket).Scan(prefix, func(key, value []byte) error {
+			event, err := decodeEvent(value)
+			if err != nil {
+				return err
+			}
+			if keep(event) {
+				events = append(events, event)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// eventPrefix is the key prefix of a product's events. Product IDs never
+// contain a zero byte, so no product's prefix starts another's.
+func eventPrefix(productID string) []byte {
+	return append([]byte(productID), 0)
+}
+
+// getProduct reads a product, or fails with ErrProductNotFound
+func getProduct(tx Tx, id string) (*models.Product, error) {
+	data := tx.Bucket(productsBucket).Get([]byte(id))
+	if data == nil {
+		return nil, models.ErrProductNotFound
+	}
+	return decodeProduct(data)
+}
+
+// putProduct stores a product and indexes its SKU. It fails with
+// ErrDuplicateSKU when another product has the SKU and, when the product
+// must exist, with ErrProductNotFound.
+func putProduct(tx Tx, product *models.Product, mustExist bool) error {
+	skus := tx.Bucket(skusBucket)
+	if product.SKU != "" {
+		if owner := skus.Get([]byte(product.SKU)); owner != nil && string(owner) != product.ID {
+			return models.ErrDuplicateSKU
+		}
+	}
+	previous, err := getProduct(tx, product.ID)
+	switch {
+	case err == nil:
+		if err := releaseSKU(tx, previous); err != nil {
+			return err
+		}
+	case !errors.Is(err, models.ErrProductNotFound) || mustExist:
+		return err
+	}
+
+	data, err := json.Marshal(product)
+	if err != nil {
+		return err
+	}
+	if err := tx.Bucket(productsBucket).Put([]byte(product.ID), data); err != nil {
+		return err
+	}
+	if product.SKU == "" {
+		return nil
+	}
+	return skus.Put([]byte(product.SKU), []byte(product.ID))
+}
+
+// releaseSKU removes a stored product's SKU from the index
+func releaseSKU(tx Tx, product *models.Product) error {
+	if product.SKU == "" {
+		return nil
+	}
+	return tx.Bucket(skusBucket).Delete([]byte(product.SKU))
+}
+
+// decodeProduct decodes a stored product
+func decodeProduct(data []byte) (*models.Product, error) {
+	var product models.Product
+	if err := json.Unmarshal(data, &product); err != nil {
+		return nil, fmt.Errorf("invalid stored product: %v", err)
+	}
+	return &product, nil
+}
+
+// storedEvent decodes a stored event; every stored event is a product event
+type storedEvent struct {
+	models.Event
+	Data *models.ProductEvent `json:"data"`
+}
+
+// decodeEvent decodes a stored event
+func decodeEvent(data []byte) (*models.Event, error) {
+	var stored storedEvent
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("invalid stored event: %v", err)
+	}
+	event := stored.Event
+	if stored.Data != nil {
+		event.Data = stored.Data
+	}
+	return &event, nil
+}
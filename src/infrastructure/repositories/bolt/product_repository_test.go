@@ -0,0 +1,328 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore is a Store in memory. Updates run on a copy of the buckets,
+// which replaces them on commit, like bbolt's copy-on-write pages.
+type memoryStore struct {
+	mu      sync.RWMutex
+	buckets map[string]*memoryBucket
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{buckets: make(map[string]*memoryBucket)}
+}
+
+func (s *memoryStore) View(fn func(tx Tx) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(&memoryTx{buckets: s.buckets})
+}
+
+func (s *memoryStore) Update(fn func(tx Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tx := &memoryTx{buckets: make(map[string]*memoryBucket, len(s.buckets)), writable: true}
+	for name, bucket := range s.buckets {
+		tx.buckets[name] = bucket.clone()
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	s.buckets = tx.buckets
+	return nil
+}
+
+func (s *memoryStore) Close() error { return nil }
+
+type memoryTx struct {
+	buckets  map[string]*memoryBucket
+	writable bool
+}
+
+func (t *memoryTx) Bucket(name string) Bucket {
+	bucket, ok := t.buckets[name]
+	if !ok {
+		return nil
+	}
+	return bucket
+}
+
+func (t *memoryTx) CreateBucket(name string) (Bucket, error) {
+	if !t.writable {
+		return nil, errors.New("read-only transaction")
+	}
+	if _, ok := t.buckets[name]; !ok {
+		t.buckets[name] = &memoryBucket{values: make(map[string][]byte)}
+	}
+	return t.buckets[name], nil
+}
+
+type memoryBucket struct {
+	values   map[string][]byte
+	sequence uint64
+}
+
+func (b *memoryBucket) clone() *memoryBucket {
+	clone := &memoryBucket{values: make(map[string][]byte, len(b.values)), sequence: b.sequence}
+	for key, value := range b.values {
+		clone.values[key] = value
+	}
+	return clone
+}
+
+func (b *memoryBucket) Get(key []byte) []byte {
+	return b.values[string(key)]
+}
+
+func (b *memoryBucket) Put(key, value []byte) error {
+	b.values[string(key)] = append([]byte(nil), value...)
+	return nil
+}
+
+func (b *memoryBucket) Delete(key []byte) error {
+	delete(b.values, string(key))
+	return nil
+}
+
+func (b *memoryBucket) Scan(prefix []byte, fn func(key, value []byte) error) error {
+	keys := make([]string, 0, len(b.values))
+	for key := range b.values {
+		if strings.HasPrefix(key, string(prefix)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := fn([]byte(key), b.values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *memoryBucket) NextSequence() (uint64, error) {
+	b.sequence++
+	return b.sequence, nil
+}
+
+func setupRepository(t *testing.T) (*ProductRepository, *memoryStore) {
+	store := newMemoryStore()
+	repo, err := NewProductRepository(store)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return repo, store
+}
+
+func createTestProduct(id string, createdAt time.Time) *models.Product {
+	return &models.Product{
+		ID:        id,
+		SKU:       "SKU-" + id,
+		BaseTitle: "Product " + id,
+		Prices:    []models.Price{{Currency: "SEK", Amount: 100}},
+		Variants: []models.Variant{{
+			ID:    id + "-v1",
+			Stock: []models.Stock{{LocationID: "wh1", Quantity: 5}},
+		}},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		Version:   1,
+	}
+}
+
+func TestProductCRUD(t *testing.T) {
+	repo, _ := setupRepository(t)
+	ctx := context.Background()
+	product := createTestProduct("prod_1", time.Now())
+
+	assert.NoError(t, repo.Create(ctx, product))
+	stored, err := repo.GetByID(ctx, "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Product prod_1", stored.BaseTitle)
+	assert.NotSame(t, product, stored)
+
+	stored.BaseTitle = "Renamed"
+	assert.NoError(t, repo.Update(ctx, stored))
+	found, err := repo.GetBySKU(ctx, "SKU-prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Renamed", found.BaseTitle)
+
+	assert.ErrorIs(t, repo.Update(ctx, createTestProduct("missing", time.Now())), models.ErrProductNotFound)
+	assert.NoError(t, repo.Delete(ctx, "prod_1"))
+	assert.ErrorIs(t, repo.Delete(ctx, "prod_1"), models.ErrProductNotFound)
+	_, err = repo.GetBySKU(ctx, "SKU-prod_1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestProductSKUUniqueness(t *testing.T) {
+	repo, _ := setupRepository(t)
+	ctx := context.Background()
+	assert.NoError(t, repo.Create(ctx, createTestProduct("prod_1", time.Now())))
+
+	duplicate := createTestProduct("prod_2", time.Now())
+	duplicate.SKU = "SKU-prod_1"
+	assert.ErrorIs(t, repo.Create(ctx, duplicate), models.ErrDuplicateSKU)
+
+	// Renaming frees the old SKU
+	renamed := createTestProduct("prod_1", time.Now())
+	renamed.SKU = "SKU-renamed"
+	assert.NoError(t, repo.Update(ctx, renamed))
+	assert.NoError(t, repo.Create(ctx, duplicate))
+}
+
+func TestProductListAfter(t *testing.T) {
+	repo, _ := setupRepository(t)
+	ctx := context.Background()
+	base := time.Now()
+	for i, id := range []string{"prod_1", "prod_2", "prod_3"} {
+		assert.NoError(t, repo.Create(ctx, createTestProduct(id, base.Add(time.Duration(i)*time.Second))))
+	}
+
+	products, total, err := repo.List(ctx, models.ProductFilter{}, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, "prod_3", products[0].ID)
+
+	page, next, err := repo.ListAfter(ctx, models.ProductFilter{}, nil, 2)
+	assert.NoError(t, err)
+	assert.Len(t, page, 2)
+	page, next, err = repo.ListAfter(ctx, models.ProductFilter{}, next, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Equal(t, "prod_1", page[0].ID)
+}
+
+func TestProductEvents(t *testing.T) {
+	repo, _ := setupRepository(t)
+	ctx := context.Background()
+	base := time.Now()
+	for i, entity := range []string{"prod_1", "prod_10", "prod_1"} {
+		assert.NoError(t, repo.StoreEvent(ctx, &models.Event{
+			ID:        entity + "-" + string(rune('a'+i)),
+			Type:      models.EventProductUpdated,
+			EntityID:  entity,
+			Version:   int64(i + 1),
+			Sequence:  int64(i + 1),
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+			Data:      &models.ProductEvent{ProductID: entity, Action: "updated", Version: int64(i + 1)},
+		}))
+	}
+
+	events, err := repo.GetEventsByProductID(ctx, "prod_1", 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2, "prod_10's events are not prod_1's")
+	assert.Equal(t, "prod_1", events[1].Data.(*models.ProductEvent).ProductID)
+
+	until, err := repo.GetEventsUntil(ctx, base.Add(time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, until, 2)
+	assert.Equal(t, "prod_10", until[1].EntityID)
+}
+
+func TestProductAdjustStock(t *testing.T) {
+	repo, _ := setupRepository(t)
+	ctx := context.Background()
+	assert.NoError(t, repo.Create(ctx, createTestProduct("prod_1", time.Now())))
+
+	previous, updated, err := repo.AdjustStock(ctx, "prod_1", []models.StockAdjustment{{VariantID: "prod_1-v1", LocationID: "wh1", Delta: -2}})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, previous.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, 3, updated.Variants[0].Stock[0].Quantity)
+
+	_, _, err = repo.AdjustStock(ctx, "prod_1", []models.StockAdjustment{{VariantID: "prod_1-v1", LocationID: "wh1", Delta: -10}})
+	assert.ErrorIs(t, err, models.ErrInsufficientStock)
+	stored, _ := repo.GetByID(ctx, "prod_1")
+	assert.Equal(t, 3, stored.Variants[0].Stock[0].Quantity)
+}
+
+func TestProductWithTx(t *testing.T) {
+	repo, _ := setupRepository(t)
+	ctx := context.Background()
+	event := &models.Event{ID: "evt_1", EntityID: "prod_1", Version: 1, Timestamp: time.Now()}
+
+	failed := errors.New("failed")
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		assert.NoError(t, tx.StoreEvent(ctx, event))
+		assert.NoError(t, tx.Create(ctx, createTestProduct("prod_1", time.Now())))
+		_, err := tx.GetByID(ctx, "prod_1")
+		assert.NoError(t, err)
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	_, err = repo.GetByID(ctx, "prod_1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	events, err := repo.GetEventsByProductID(ctx, "prod_1", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	assert.NoError(t, repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, event); err != nil {
+			return err
+		}
+		return tx.Create(ctx, createTestProduct("prod_1", time.Now()))
+	}))
+	events, err = repo.GetEventsByProductID(ctx, "prod_1", 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestCanceledContext(t *testing.T) {
+	repo, _ := setupRepository(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, repo.Create(ctx, createTestProduct("prod_1", time.Now())), context.Canceled)
+	_, err := repo.GetByID(context.Background(), "prod_1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestRecovery(t *testing.T) {
+	repo, store := setupRepository(t)
+	ctx := context.Background()
+	assert.NoError(t, repo.Create(ctx, createTestProduct("prod_1", time.Now())))
+
+	// A lost index entry is rebuilt from the products when reopening
+	assert.NoError(t, store.Update(func(tx Tx) error {
+		return tx.Bucket(skusBucket).Delete([]byte("SKU-prod_1"))
+	}))
+	reopened, err := NewProductRepository(store)
+	assert.NoError(t, err)
+	found, err := reopened.GetBySKU(ctx, "SKU-prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "prod_1", found.ID)
+
+	// A store written by a newer version is not touched
+	assert.NoError(t, store.Update(func(tx Tx) error {
+		return tx.Bucket(metaBucket).Put(schemaVersionKey, []byte("99"))
+	}))
+	_, err = NewProductRepository(store)
+	assert.ErrorContains(t, err, "schema version 99")
+
+	// Undecodable products fail the recovery instead of being dropped
+	assert.NoError(t, store.Update(func(tx Tx) error {
+		if err := tx.Bucket(metaBucket).Put(schemaVersionKey, []byte("1")); err != nil {
+			return err
+		}
+		return tx.Bucket(productsBucket).Put([]byte("prod_2"), []byte("{"))
+	}))
+	_, err = NewProductRepository(store)
+	assert.ErrorContains(t, err, "prod_2")
+}
+
+func TestEventPrefix(t *testing.T) {
+	assert.False(t, bytes.HasPrefix(eventPrefix("prod_10"), eventPrefix("prod_1")))
+}
@@ -0,0 +1,55 @@
+// Package bolt stores products and their events in an embedded key-value
+// database file, for single-node deployments that need durability without
+// running Postgres. The repository runs on the Store interface; Open provides
+// one on go.etcd.io/bbolt when the binary is built with -tags bolt.
+package bolt
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrStoreNotLinked is returned when the binary was built without -tags bolt
+var ErrStoreNotLinked = errors.New("built without the embedded store: build with -tags bolt")
+
+// Options configures the database file
+type Options struct {
+	// LockTimeout is how long Open waits for another process holding the
+	// file to let go, forever when zero
+	LockTimeout time.Duration
+}
+
+// Store is a key-value database of named buckets with serializable
+// transactions. A transaction that returns an error writes nothing, and a
+// committed one survives a crash.
+type Store interface {
+	// View runs fn in a read-only transaction
+	View(fn func(tx Tx) error) error
+	// Update runs fn in a read-write transaction, committed when fn
+	// returns nil. Only one runs at a time.
+	Update(fn func(tx Tx) error) error
+	Close() error
+}
+
+// Tx is a transaction of a Store
+type Tx interface {
+	// Bucket returns the named bucket, or nil if it does not exist
+	Bucket(name string) Bucket
+	// CreateBucket returns the named bucket, creating it if it does not
+	// exist; it fails in a read-only transaction
+	CreateBucket(name string) (Bucket, error)
+}
+
+// Bucket is an ordered set of keys. Values it returns are only valid until
+// the transaction ends, and must not be changed.
+type Bucket interface {
+	// Get returns the value of a key, or nil if the key does not exist
+	Get(key []byte) []byte
+	Put(key, value []byte) error
+	Delete(key []byte) error
+	// Scan calls fn for every key with the prefix, in key order, until fn
+	// returns an error
+	Scan(prefix []byte, fn func(key, value []byte) error) error
+	// NextSequence returns the next number of a counter kept per bucket
+	NextSequence() (uint64, error)
+}
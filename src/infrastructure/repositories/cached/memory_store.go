@@ -0,0 +1,104 @@
+package cached
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultCapacity is the number of entries the memory store keeps by default
+const DefaultCapacity = 10000
+
+// memoryEntry is one cached value
+type memoryEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // Zero for values without a TTL
+}
+
+// MemoryStore is a least recently used cache in the process. Expired values
+// are dropped when they are read; the least recently used value is evicted
+// when the store is full.
+type MemoryStore struct {
+	capacity int
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// NewMemoryStore creates a store holding up to capacity values
+func NewMemoryStore(capacity int) *MemoryStore {
+	if capacity < 1 {
+		capacity = DefaultCapacity
+	}
+	return &MemoryStore{
+		capacity: capacity,
+		now:      time.Now,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// Get returns the value under a key unless it expired
+func (s *MemoryStore) Get(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	entry := element.Value.(*memoryEntry)
+	if !entry.expires.IsZero() && !s.now().Before(entry.expires) {
+		s.lru.Remove(element)
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	s.lru.MoveToFront(element)
+	return entry.value, true, nil
+}
+
+// Set stores a value, evicting the least recently used one when the store is full
+func (s *MemoryStore) Set(key string, value []byte, ttl time.Duration) error {
+	entry := &memoryEntry{key: key, value: value}
+	if ttl > 0 {
+		entry.expires = s.now().Add(ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[key]; ok {
+		element.Value = entry
+		s.lru.MoveToFront(element)
+		return nil
+	}
+	s.entries[key] = s.lru.PushFront(entry)
+	if s.lru.Len() > s.capacity {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryEntry).key)
+	}
+	return nil
+}
+
+// Delete drops the keys
+func (s *MemoryStore) Delete(keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		if element, ok := s.entries[key]; ok {
+			s.lru.Remove(element)
+			delete(s.entries, key)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of stored values, expired ones included
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lru.Len()
+}
@@ -0,0 +1,58 @@
+package cached
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewMemoryStore(2)
+	assert.NoError(t, store.Set("a", []byte("1"), 0))
+	assert.NoError(t, store.Set("b", []byte("2"), 0))
+	_, ok, _ := store.Get("a")
+	assert.True(t, ok)
+
+	assert.NoError(t, store.Set("c", []byte("3"), 0))
+	assert.Equal(t, 2, store.Len())
+	_, ok, _ = store.Get("b")
+	assert.False(t, ok)
+	value, ok, err := store.Get("a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	assert.NoError(t, store.Delete("a", "missing"))
+	_, ok, _ = store.Get("a")
+	assert.False(t, ok)
+}
+
+func TestMemoryStoreTTL(t *testing.T) {
+	store := NewMemoryStore(0)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	assert.NoError(t, store.Set("short", []byte("1"), time.Minute))
+	assert.NoError(t, store.Set("forever", []byte("2"), 0))
+	now = now.Add(time.Minute)
+
+	_, ok, _ := store.Get("short")
+	assert.False(t, ok)
+	_, ok, _ = store.Get("forever")
+	assert.True(t, ok)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestNewStore(t *testing.T) {
+	store, err := NewStore(StoreConfig{})
+	assert.NoError(t, err)
+	assert.IsType(t, &MemoryStore{}, store)
+
+	store, err = NewStore(StoreConfig{Name: StoreRedis, RedisURL: "redis://localhost"})
+	assert.NoError(t, err)
+	assert.IsType(t, &RedisStore{}, store)
+
+	_, err = NewStore(StoreConfig{Name: "memcached"})
+	assert.Error(t, err)
+}
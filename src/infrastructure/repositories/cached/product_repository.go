@@ -0,0 +1,317 @@
+package cached
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+	"go.uber.org/zap"
+)
+
+// Default TTLs when Options leaves them unset
+const (
+	DefaultProductTTL = 5 * time.Minute
+	DefaultListTTL    = 30 * time.Second
+)
+
+// Keys in the store. List pages are keyed under the current list generation,
+// which is replaced on every change, so one write retires every cached page
+// without having to find them.
+const (
+	productKeyPrefix  = "ecom:product:"
+	listKeyPrefix     = "ecom:products:list:"
+	listGenerationKey = "ecom:products:list_generation"
+)
+
+// Cache results used in metrics
+const (
+	resultHit   = "hit"
+	resultMiss  = "miss"
+	resultError = "error"
+)
+
+// Options configures how long reads are cached
+type Options struct {
+	ProductTTL time.Duration // GetByID results, DefaultProductTTL when zero
+	ListTTL    time.Duration // List pages, DefaultListTTL when zero
+}
+
+// ProductRepository serves GetByID and List from a cache in front of the
+// wrapped repository and fills it on misses. Writes through the repository
+// invalidate the product and the cached list pages at once; writes by other
+// instances sharing a Redis store reach it as product events, see Subscribe.
+// The TTLs bound how stale an entry can get if an invalidation is lost.
+// The cache never fails a read: store errors are logged and the read goes to
+// the wrapped repository.
+type ProductRepository struct {
+	inner   repositories.ProductRepository
+	store   Store
+	options Options
+	now     func() time.Time
+}
+
+// NewProductRepository wraps a repository with a read-through cache in the store
+func NewProductRepository(inner repositories.ProductRepository, store Store, options Options) *ProductRepository {
+	if options.ProductTTL <= 0 {
+		options.ProductTTL = DefaultProductTTL
+	}
+	if options.ListTTL <= 0 {
+		options.ListTTL = DefaultListTTL
+	}
+	return &ProductRepository{inner: inner, store: store, options: options, now: time.Now}
+}
+
+// cachedList is a cached List page
+type cachedList struct {
+	Products []*models.Product `json:"products"`
+	Total    int               `json:"total"`
+}
+
+// Subscribe invalidates products from the product events of a publisher
+func (r *ProductRepository) Subscribe(publisher events.EventPublisher) {
+	for _, eventType := range []models.EventType{
+		models.EventProductCreated,
+		models.EventProductUpdated,
+		models.EventProductDeleted,
+		models.EventProductRestored,
+	} {
+		publisher.Subscribe(eventType, r.HandleEvent)
+	}
+}
+
+// HandleEvent drops the product of an event and the cached list pages
+func (r *ProductRepository) HandleEvent(event *models.Event) {
+	productEvent, ok := event.Data.(*models.ProductEvent)
+	if !ok {
+		return
+	}
+	r.invalidate(productEvent.ProductID, "event")
+}
+
+// GetByID returns a cached product, or reads it and caches it. Errors such as
+// models.ErrProductNotFound are not cached.
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	key := productKeyPrefix + id
+	var product *models.Product
+	if r.lookup("get_by_id", key, &product) {
+		return product, nil
+	}
+
+	product, err := r.inner.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.fill(key, product, r.options.ProductTTL)
+	return product, nil
+}
+
+// List returns a cached page, or reads it and caches it
+func (r *ProductRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	key, ok := r.listKey(filter, page, pageSize)
+	var cached cachedList
+	if ok && r.lookup("list", key, &cached) {
+		return cached.Products, cached.Total, nil
+	}
+
+	products, total, err := r.inner.List(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	if ok {
+		r.fill(key, cachedList{Products: products, Total: total}, r.options.ListTTL)
+	}
+	return products, total, nil
+}
+
+// GetBySKU reads the wrapped repository; the service checks SKU uniqueness
+// with it before writing, which a stale entry could miss
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	return r.inner.GetBySKU(ctx, sku)
+}
+
+// ListAfter reads the wrapped repository; cursor pages serve exports and
+// reprocessing, which walk the catalog once
+func (r *ProductRepository) ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) ([]*models.Product, *models.ProductCursor, error) {
+	return r.inner.ListAfter(ctx, filter, after, limit)
+}
+
+// Create stores a new product and retires the cached list pages
+func (r *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	if err := r.inner.Create(ctx, product); err != nil {
+		return err
+	}
+	r.invalidate(product.ID, "write")
+	return nil
+}
+
+// Update stores a product and drops its cached reads
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product) error {
+	err := r.inner.Update(ctx, product)
+	// A failed update may still have been applied, so invalidate either way
+	r.invalidate(product.ID, "write")
+	return err
+}
+
+// Delete removes a product and drops its cached reads
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	err := r.inner.Delete(ctx, id)
+	r.invalidate(id, "write")
+	return err
+}
+
+// AdjustStock adjusts the stock of a product and drops its cached reads
+func (r *ProductRepository) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (*models.Product, *models.Product, error) {
+	previous, updated, err := r.inner.AdjustStock(ctx, productID, adjustments)
+	if err == nil {
+		r.invalidate(productID, "write")
+	}
+	return previous, updated, err
+}
+
+func (r *ProductRepository) GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
+	return r.inner.GetEventsByProductID(ctx, productID, fromVersion)
+}
+
+func (r *ProductRepository) StoreEvent(ctx context.Context, event *models.Event) error {
+	return r.inner.StoreEvent(ctx, event)
+}
+
+func (r *ProductRepository) GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error) {
+	return r.inner.GetEventsUntil(ctx, until)
+}
+
+// WithTx runs fn in a transaction of the wrapped repository, which reads and
+// writes uncached, and drops the cached reads of the products it wrote once
+// the transaction is over
+func (r *ProductRepository) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	var written []string
+	err := r.inner.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		return fn(&txRepository{ProductRepository: tx, written: &written})
+	})
+	// A failed commit may still have been applied, so invalidate either way
+	for _, id := range written {
+		r.invalidate(id, "write")
+	}
+	return err
+}
+
+// txRepository is the transaction of the wrapped repository, recording the
+// products written in it
+type txRepository struct {
+	repositories.ProductRepository
+	written *[]string
+}
+
+func (tx *txRepository) Create(ctx context.Context, product *models.Product) error {
+	*tx.written = append(*tx.written, product.ID)
+	return tx.ProductRepository.Create(ctx, product)
+}
+
+func (tx *txRepository) Update(ctx context.Context, product *models.Product) error {
+	*tx.written = append(*tx.written, product.ID)
+	return tx.ProductRepository.Update(ctx, product)
+}
+
+func (tx *txRepository) Delete(ctx context.Context, id string) error {
+	*tx.written = append(*tx.written, id)
+	return tx.ProductRepository.Delete(ctx, id)
+}
+
+func (tx *txRepository) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (*models.Product, *models.Product, error) {
+	*tx.written = append(*tx.written, productID)
+	return tx.ProductRepository.AdjustStock(ctx, productID, adjustments)
+}
+
+// WithTx joins the running transaction
+func (tx *txRepository) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	return fn(tx)
+}
+
+// lookup decodes a cached value into target and counts the hit or miss
+func (r *ProductRepository) lookup(operation, key string, target interface{}) bool {
+	data, ok, err := r.store.Get(key)
+	if err == nil && ok {
+		err = json.Unmarshal(data, target)
+	}
+	switch {
+	case err != nil:
+		logFailure("Repository cache read failed", key, err)
+		metrics.RepositoryCacheRequests.WithLabelValues(operation, resultError).Inc()
+		return false
+	case !ok:
+		metrics.RepositoryCacheRequests.WithLabelValues(operation, resultMiss).Inc()
+		return false
+	}
+	metrics.RepositoryCacheRequests.WithLabelValues(operation, resultHit).Inc()
+	return true
+}
+
+// fill caches a value read from the wrapped repository
+func (r *ProductRepository) fill(key string, value interface{}, ttl time.Duration) {
+	data, err := json.Marshal(value)
+	if err == nil {
+		err = r.store.Set(key, data, ttl)
+	}
+	if err != nil {
+		logFailure("Repository cache write failed", key, err)
+	}
+}
+
+// listKey returns the key of a List page under the current generation. It
+// starts a generation when the store has none, e.g. after an eviction or a
+// Redis restart; without a generation the page is not cached.
+func (r *ProductRepository) listKey(filter models.ProductFilter, page, pageSize int) (string, bool) {
+	generation, ok, err := r.store.Get(listGenerationKey)
+	if err == nil && !ok {
+		generation, err = r.newListGeneration()
+	}
+	if err != nil {
+		logFailure("Repository cache read failed", listGenerationKey, err)
+		return "", false
+	}
+
+	query, err := json.Marshal(struct {
+		Filter   models.ProductFilter
+		Page     int
+		PageSize int
+	}{filter.Normalize(), page, pageSize})
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(query)
+	return listKeyPrefix + string(generation) + ":" + hex.EncodeToString(sum[:]), true
+}
+
+// newListGeneration replaces the list generation, retiring every cached page
+func (r *ProductRepository) newListGeneration() ([]byte, error) {
+	generation := []byte(strconv.FormatInt(r.now().UnixNano(), 36))
+	if err := r.store.Set(listGenerationKey, generation, 0); err != nil {
+		return nil, err
+	}
+	return generation, nil
+}
+
+// invalidate drops a product and retires the cached list pages
+func (r *ProductRepository) invalidate(id, source string) {
+	metrics.RepositoryCacheInvalidations.WithLabelValues(source).Inc()
+	if err := r.store.Delete(productKeyPrefix + id); err != nil {
+		logFailure("Repository cache invalidation failed", productKeyPrefix+id, err)
+	}
+	if _, err := r.newListGeneration(); err != nil {
+		logFailure("Repository cache invalidation failed", listGenerationKey, err)
+	}
+}
+
+// logFailure logs a store error; the read or write it belonged to carries on
+// without the cache
+func logFailure(message, key string, err error) {
+	logging.Shared().Warn(message, zap.String("key", key), zap.Error(err))
+}
@@ -0,0 +1,171 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+// countingRepository counts the reads that reach the wrapped repository
+type countingRepository struct {
+	repositories.ProductRepository
+	gets  int
+	lists int
+}
+
+func (r *countingRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	r.gets++
+	return r.ProductRepository.GetByID(context.Background(), id)
+}
+
+func (r *countingRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	r.lists++
+	return r.ProductRepository.List(context.Background(), filter, page, pageSize)
+}
+
+// failingStore fails every operation
+type failingStore struct{}
+
+func (failingStore) Get(string) ([]byte, bool, error)        { return nil, false, errors.New("down") }
+func (failingStore) Set(string, []byte, time.Duration) error { return errors.New("down") }
+func (failingStore) Delete(...string) error                  { return errors.New("down") }
+
+func setupCachedRepository(t *testing.T) (*ProductRepository, *countingRepository) {
+	inner := &countingRepository{ProductRepository: memory.NewProductRepository()}
+	assert.NoError(t, inner.Create(context.Background(), &models.Product{ID: "prod_1", SKU: "SKU-1", BaseTitle: "Shirt", Version: 1}))
+	return NewProductRepository(inner, NewMemoryStore(0), Options{}), inner
+}
+
+func TestGetByIDIsCached(t *testing.T) {
+	repo, inner := setupCachedRepository(t)
+	hits := testutil.ToFloat64(metrics.RepositoryCacheRequests.WithLabelValues("get_by_id", resultHit))
+	misses := testutil.ToFloat64(metrics.RepositoryCacheRequests.WithLabelValues("get_by_id", resultMiss))
+
+	for i := 0; i < 3; i++ {
+		product, err := repo.GetByID(context.Background(), "prod_1")
+		assert.NoError(t, err)
+		assert.Equal(t, "Shirt", product.BaseTitle)
+	}
+	assert.Equal(t, 1, inner.gets)
+	assert.Equal(t, hits+2, testutil.ToFloat64(metrics.RepositoryCacheRequests.WithLabelValues("get_by_id", resultHit)))
+	assert.Equal(t, misses+1, testutil.ToFloat64(metrics.RepositoryCacheRequests.WithLabelValues("get_by_id", resultMiss)))
+
+	// Missing products are not cached
+	_, err := repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	_, err = repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	assert.Equal(t, 3, inner.gets)
+}
+
+func TestWritesInvalidate(t *testing.T) {
+	repo, inner := setupCachedRepository(t)
+	_, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	products, total, err := repo.List(context.Background(), models.ProductFilter{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, 1, total)
+
+	assert.NoError(t, repo.Update(context.Background(), &models.Product{ID: "prod_1", SKU: "SKU-1", BaseTitle: "Polo", Version: 2}))
+	assert.NoError(t, repo.Create(context.Background(), &models.Product{ID: "prod_2", SKU: "SKU-2", BaseTitle: "Scarf", Version: 1}))
+
+	product, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Polo", product.BaseTitle)
+	products, total, err = repo.List(context.Background(), models.ProductFilter{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, products, 2)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 2, inner.gets)
+	assert.Equal(t, 2, inner.lists)
+
+	// Pages of different filters are cached apart
+	_, _, err = repo.List(context.Background(), models.ProductFilter{SKU: "SKU-2"}, 1, 10)
+	assert.NoError(t, err)
+	_, _, err = repo.List(context.Background(), models.ProductFilter{SKU: " SKU-2 "}, 1, 10)
+	assert.NoError(t, err)
+	_, _, err = repo.List(context.Background(), models.ProductFilter{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inner.lists)
+}
+
+func TestTransactionWritesInvalidate(t *testing.T) {
+	repo, inner := setupCachedRepository(t)
+	_, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.WithTx(context.Background(), func(tx repositories.ProductRepository) error {
+		return tx.Update(context.Background(), &models.Product{ID: "prod_1", SKU: "SKU-1", BaseTitle: "Polo", Version: 2})
+	}))
+	product, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Polo", product.BaseTitle)
+	assert.Equal(t, 2, inner.gets)
+}
+
+func TestEventsInvalidate(t *testing.T) {
+	repo, inner := setupCachedRepository(t)
+	_, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+
+	// Another instance changes the product behind the cache
+	changed := &models.Product{ID: "prod_1", SKU: "SKU-1", BaseTitle: "Polo", Version: 2}
+	assert.NoError(t, inner.Update(context.Background(), changed))
+	product, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shirt", product.BaseTitle)
+
+	repo.HandleEvent(&models.Event{
+		Type:     models.EventProductUpdated,
+		EntityID: "prod_1",
+		Data:     &models.ProductEvent{ProductID: "prod_1", Action: "updated", Product: changed},
+	})
+	product, err = repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Polo", product.BaseTitle)
+	assert.Equal(t, 2, inner.gets)
+}
+
+func TestProductTTL(t *testing.T) {
+	repo, inner := setupCachedRepository(t)
+	store := repo.store.(*MemoryStore)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	_, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	now = now.Add(DefaultProductTTL - time.Second)
+	_, err = repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, inner.gets)
+
+	now = now.Add(time.Second)
+	_, err = repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, inner.gets)
+}
+
+func TestStoreFailuresFallThrough(t *testing.T) {
+	inner := &countingRepository{ProductRepository: memory.NewProductRepository()}
+	repo := NewProductRepository(inner, failingStore{}, Options{})
+	errorsBefore := testutil.ToFloat64(metrics.RepositoryCacheRequests.WithLabelValues("get_by_id", resultError))
+
+	assert.NoError(t, repo.Create(context.Background(), &models.Product{ID: "prod_1", SKU: "SKU-1", BaseTitle: "Shirt"}))
+	product, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shirt", product.BaseTitle)
+	products, _, err := repo.List(context.Background(), models.ProductFilter{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Equal(t, errorsBefore+1, testutil.ToFloat64(metrics.RepositoryCacheRequests.WithLabelValues("get_by_id", resultError)))
+}
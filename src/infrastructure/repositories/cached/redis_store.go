@@ -0,0 +1,240 @@
+package cached
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redisTimeout bounds dialing and each command, so a slow Redis degrades to
+// cache misses instead of slow reads
+const redisTimeout = 500 * time.Millisecond
+
+// redisMaxIdle is the number of idle connections kept open
+const redisMaxIdle = 16
+
+// RedisError is an error reply from the server
+type RedisError string
+
+func (e RedisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// RedisStore keeps cached values in Redis, shared by every instance of the
+// service. It speaks just enough of the RESP protocol for GET, SET and DEL.
+type RedisStore struct {
+	address  string
+	tls      bool
+	username string
+	password string
+	database int
+	timeout  time.Duration
+
+	idle chan *redisConn
+}
+
+// redisConn is one connection to the server
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisStore creates a store for a redis:// or rediss:// URL. Connections
+// are opened on first use.
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "redis" && parsed.Scheme != "rediss") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid Redis URL %q, expected redis://[:password@]host[:port][/db]", rawURL)
+	}
+	store := &RedisStore{
+		address: parsed.Host,
+		tls:     parsed.Scheme == "rediss",
+		timeout: redisTimeout,
+		idle:    make(chan *redisConn, redisMaxIdle),
+	}
+	if parsed.Port() == "" {
+		store.address = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+	if parsed.User != nil {
+		store.username = parsed.User.Username()
+		store.password, _ = parsed.User.Password()
+	}
+	if db := strings.Trim(parsed.Path, "/"); db != "" {
+		if store.database, err = strconv.Atoi(db); err != nil || store.database < 0 {
+			return nil, fmt.Errorf("invalid Redis database %q", db)
+		}
+	}
+	return store, nil
+}
+
+// Get returns the value under a key
+func (s *RedisStore) Get(key string) ([]byte, bool, error) {
+	value, err := s.do("GET", key)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, value != nil, nil
+}
+
+// Set stores a value, expiring it after ttl when ttl is set
+func (s *RedisStore) Set(key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := s.do(args...)
+	return err
+}
+
+// Delete drops the keys
+func (s *RedisStore) Delete(keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := s.do(append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+// Close closes the idle connections
+func (s *RedisStore) Close() error {
+	for {
+		select {
+		case conn := <-s.idle:
+			conn.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do sends a command and returns its reply, nil for a nil reply. A connection
+// that failed is closed rather than reused, since its stream may be out of step.
+func (s *RedisStore) do(args ...string) ([]byte, error) {
+	conn, err := s.conn()
+	if err != nil {
+		return nil, err
+	}
+	reply, err := conn.command(s.timeout, args...)
+	var replyErr RedisError
+	if err != nil && !errors.As(err, &replyErr) {
+		conn.conn.Close()
+		return nil, err
+	}
+	select {
+	case s.idle <- conn:
+	default:
+		conn.conn.Close()
+	}
+	return reply, err
+}
+
+// conn takes an idle connection or dials a new one, authenticating and
+// selecting the database
+func (s *RedisStore) conn() (*redisConn, error) {
+	select {
+	case conn := <-s.idle:
+		return conn, nil
+	default:
+	}
+
+	dialer := &net.Dialer{Timeout: s.timeout}
+	var conn net.Conn
+	var err error
+	if s.tls {
+		host, _, _ := net.SplitHostPort(s.address)
+		conn, err = tls.DialWithDialer(dialer, "tcp", s.address, &tls.Config{ServerName: host})
+	} else {
+		conn, err = dialer.Dial("tcp", s.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	rc := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+
+	var setup [][]string
+	switch {
+	case s.username != "" && s.password != "":
+		setup = append(setup, []string{"AUTH", s.username, s.password})
+	case s.password != "":
+		setup = append(setup, []string{"AUTH", s.password})
+	}
+	if s.database != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(s.database)})
+	}
+	for _, args := range setup {
+		if _, err := rc.command(s.timeout, args...); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to set up Redis connection: %w", err)
+		}
+	}
+	return rc, nil
+}
+
+// command writes a command as an array of bulk strings and reads the reply
+func (c *redisConn) command(timeout time.Duration, args ...string) ([]byte, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, err
+	}
+	return c.reply()
+}
+
+// reply reads one reply. Simple strings and integers are returned as their
+// text; arrays, which none of the commands used here return, are read and
+// discarded.
+func (c *redisConn) reply() ([]byte, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, RedisError(line[1:])
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", line)
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return data[:size], nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid array length %q", line)
+		}
+		for i := 0; i < count; i++ {
+			if _, err := c.reply(); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
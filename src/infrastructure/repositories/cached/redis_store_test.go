@@ -0,0 +1,165 @@
+package cached
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRedis answers GET, SET, DEL, AUTH and SELECT over RESP and records the commands
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu       sync.Mutex
+	values   map[string]string
+	commands [][]string
+}
+
+func startFakeRedis(t *testing.T, password string) *fakeRedis {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	server := &fakeRedis{listener: listener, password: password, values: make(map[string]string)}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	authenticated := f.password == ""
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, args)
+		var reply string
+		switch {
+		case args[0] == "AUTH":
+			authenticated = args[len(args)-1] == f.password
+			reply = "+OK\r\n"
+			if !authenticated {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required.\r\n"
+		case args[0] == "SELECT":
+			reply = "+OK\r\n"
+		case args[0] == "GET":
+			if value, ok := f.values[args[1]]; ok {
+				reply = fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+			} else {
+				reply = "$-1\r\n"
+			}
+		case args[0] == "SET":
+			f.values[args[1]] = args[2]
+			reply = "+OK\r\n"
+		case args[0] == "DEL":
+			for _, key := range args[1:] {
+				delete(f.values, key)
+			}
+			reply = fmt.Sprintf(":%d\r\n", len(args)-1)
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads one command sent as an array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	args := make([]string, count)
+	for i := range args {
+		if line, err = reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		size, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func (f *fakeRedis) sent() [][]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]string(nil), f.commands...)
+}
+
+func TestRedisStore(t *testing.T) {
+	server := startFakeRedis(t, "secret")
+	store, err := NewRedisStore("redis://:secret@" + server.listener.Addr().String() + "/2")
+	assert.NoError(t, err)
+	defer store.Close()
+
+	_, ok, err := store.Get("missing")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, store.Set("key", []byte("line\r\nbreak"), 1500*time.Millisecond))
+	value, ok, err := store.Get("key")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("line\r\nbreak"), value)
+
+	assert.NoError(t, store.Delete("key", "other"))
+	_, ok, err = store.Get("key")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// The connection is authenticated and set up once, then reused
+	sent := server.sent()
+	assert.Equal(t, []string{"AUTH", "secret"}, sent[0])
+	assert.Equal(t, []string{"SELECT", "2"}, sent[1])
+	assert.Equal(t, []string{"SET", "key", "line\r\nbreak", "PX", "1500"}, sent[3])
+	assert.Equal(t, []string{"DEL", "key", "other"}, sent[5])
+	assert.Len(t, sent, 7)
+}
+
+func TestRedisStoreErrors(t *testing.T) {
+	server := startFakeRedis(t, "secret")
+	store, err := NewRedisStore("redis://:wrong@" + server.listener.Addr().String())
+	assert.NoError(t, err)
+	_, _, err = store.Get("key")
+	var replyErr RedisError
+	assert.ErrorAs(t, err, &replyErr)
+
+	for _, url := range []string{"http://localhost", "redis://", "redis://localhost/db"} {
+		_, err := NewRedisStore(url)
+		assert.Error(t, err, url)
+	}
+
+	store, err = NewRedisStore("redis://localhost")
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:6379", store.address)
+}
@@ -0,0 +1,40 @@
+package cached
+
+import (
+	"fmt"
+	"time"
+)
+
+// Store names accepted by NewStore
+const (
+	StoreMemory = "memory"
+	StoreRedis  = "redis"
+)
+
+// Store keeps cached values by key. Values expire after their TTL; a zero
+// TTL keeps a value until it is deleted or evicted.
+type Store interface {
+	// Get returns the value under a key, and false when there is none
+	Get(key string) ([]byte, bool, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete drops the keys; missing keys are ignored
+	Delete(keys ...string) error
+}
+
+// StoreConfig selects and configures a cache store
+type StoreConfig struct {
+	Name     string // memory (default) or redis
+	Capacity int    // Entries kept by the memory store, DefaultCapacity when zero
+	RedisURL string // Redis server, e.g. redis://:password@localhost:6379/0
+}
+
+// NewStore creates the configured store
+func NewStore(config StoreConfig) (Store, error) {
+	switch config.Name {
+	case "", StoreMemory:
+		return NewMemoryStore(config.Capacity), nil
+	case StoreRedis:
+		return NewRedisStore(config.RedisURL)
+	}
+	return nil, fmt.Errorf("unknown repository cache %q, expected memory or redis", config.Name)
+}
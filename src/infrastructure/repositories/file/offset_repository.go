@@ -0,0 +1,105 @@
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// OffsetRepository persists consumer offsets as a JSON document on disk.
+// Every commit rewrites the file through a temporary file and a rename, so a
+// crash leaves either the previous or the new offsets, never a partial write.
+type OffsetRepository struct {
+	path    string
+	offsets map[string]int64
+	mu      sync.RWMutex
+}
+
+// NewOffsetRepository opens the offset file at path, creating it on the first commit
+func NewOffsetRepository(path string) (repositories.OffsetRepository, error) {
+	r := &OffsetRepository{
+		path:    path,
+		offsets: make(map[string]int64),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offsets: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &r.offsets); err != nil {
+			return nil, fmt.Errorf("failed to decode offsets: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// Get returns the committed sequence for a consumer
+func (r *OffsetRepository) Get(consumer string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.offsets[consumer], nil
+}
+
+// Commit stores the committed sequence for a consumer and writes it to disk
+func (r *OffsetRepository) Commit(consumer string, sequence int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, existed := r.offsets[consumer]
+	r.offsets[consumer] = sequence
+	if err := r.write(); err != nil {
+		if existed {
+			r.offsets[consumer] = previous
+		} else {
+			delete(r.offsets, consumer)
+		}
+		return err
+	}
+	return nil
+}
+
+// List returns a copy of all committed sequences
+func (r *OffsetRepository) List() (map[string]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make(map[string]int64, len(r.offsets))
+	for consumer, sequence := range r.offsets {
+		result[consumer] = sequence
+	}
+	return result, nil
+}
+
+// write replaces the offset file with the current offsets
+func (r *OffsetRepository) write() error {
+	data, err := json.Marshal(r.offsets)
+	if err != nil {
+		return fmt.Errorf("failed to encode offsets: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), filepath.Base(r.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write offsets: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write offsets: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write offsets: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return fmt.Errorf("failed to write offsets: %w", err)
+	}
+	return nil
+}
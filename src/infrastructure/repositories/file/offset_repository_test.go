@@ -0,0 +1,61 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsetsSurviveReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "offsets.json")
+
+	repo, err := NewOffsetRepository(path)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Commit("websocket", 7))
+	assert.NoError(t, repo.Commit("dashboard", 3))
+
+	reopened, err := NewOffsetRepository(path)
+	assert.NoError(t, err)
+
+	offset, err := reopened.Get("websocket")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), offset)
+
+	offsets, err := reopened.List()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"websocket": 7, "dashboard": 3}, offsets)
+}
+
+func TestOffsetsMissingFileStartsEmpty(t *testing.T) {
+	repo, err := NewOffsetRepository(filepath.Join(t.TempDir(), "offsets.json"))
+	assert.NoError(t, err)
+
+	offsets, err := repo.List()
+	assert.NoError(t, err)
+	assert.Empty(t, offsets)
+}
+
+func TestOffsetsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "offsets.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{not json"), 0o644))
+
+	_, err := NewOffsetRepository(path)
+	assert.Error(t, err)
+}
+
+func TestOffsetsCommitFailureKeepsPreviousValue(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "offsets.json")
+	repo, err := NewOffsetRepository(path)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.Commit("websocket", 1))
+
+	// Removing the directory makes the next write fail
+	assert.NoError(t, os.RemoveAll(dir))
+	assert.Error(t, repo.Commit("websocket", 2))
+
+	offset, _ := repo.Get("websocket")
+	assert.Equal(t, int64(1), offset)
+}
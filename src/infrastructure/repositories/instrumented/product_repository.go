@@ -0,0 +1,112 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+	"github.com/jimmitjoo/ecom/src/infrastructure/tracing"
+)
+
+// ProductRepository records the duration of every operation of the wrapped
+// repository in the repository_operation_duration_seconds histogram, under
+// the operation's method name in snake case, and traces it in a
+// "repository.<operation>" span
+type ProductRepository struct {
+	inner repositories.ProductRepository
+}
+
+// NewProductRepository wraps a repository with operation metrics and spans
+func NewProductRepository(inner repositories.ProductRepository) *ProductRepository {
+	return &ProductRepository{inner: inner}
+}
+
+// track starts timing and tracing an operation in a span under the caller's,
+// and returns the span's context and the function that ends both with the
+// operation's error
+func track(ctx context.Context, operation string) (context.Context, func(err *error)) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "repository."+operation)
+	return ctx, func(err *error) {
+		metrics.RepositoryOperationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+		tracing.End(span, *err)
+	}
+}
+
+func (r *ProductRepository) Create(ctx context.Context, product *models.Product) (err error) {
+	ctx, end := track(ctx, "create")
+	defer end(&err)
+	return r.inner.Create(ctx, product)
+}
+
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (product *models.Product, err error) {
+	ctx, end := track(ctx, "get_by_id")
+	defer end(&err)
+	return r.inner.GetByID(ctx, id)
+}
+
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (product *models.Product, err error) {
+	ctx, end := track(ctx, "get_by_sku")
+	defer end(&err)
+	return r.inner.GetBySKU(ctx, sku)
+}
+
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product) (err error) {
+	ctx, end := track(ctx, "update")
+	defer end(&err)
+	return r.inner.Update(ctx, product)
+}
+
+func (r *ProductRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, end := track(ctx, "delete")
+	defer end(&err)
+	return r.inner.Delete(ctx, id)
+}
+
+func (r *ProductRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) (products []*models.Product, total int, err error) {
+	ctx, end := track(ctx, "list")
+	defer end(&err)
+	return r.inner.List(ctx, filter, page, pageSize)
+}
+
+func (r *ProductRepository) ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) (products []*models.Product, next *models.ProductCursor, err error) {
+	ctx, end := track(ctx, "list_after")
+	defer end(&err)
+	return r.inner.ListAfter(ctx, filter, after, limit)
+}
+
+func (r *ProductRepository) GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) (events []*models.Event, err error) {
+	ctx, end := track(ctx, "get_events")
+	defer end(&err)
+	return r.inner.GetEventsByProductID(ctx, productID, fromVersion)
+}
+
+func (r *ProductRepository) StoreEvent(ctx context.Context, event *models.Event) (err error) {
+	ctx, end := track(ctx, "store_event")
+	defer end(&err)
+	return r.inner.StoreEvent(ctx, event)
+}
+
+func (r *ProductRepository) GetEventsUntil(ctx context.Context, until time.Time) (events []*models.Event, err error) {
+	ctx, end := track(ctx, "get_events_until")
+	defer end(&err)
+	return r.inner.GetEventsUntil(ctx, until)
+}
+
+func (r *ProductRepository) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (previous, updated *models.Product, err error) {
+	ctx, end := track(ctx, "adjust_stock")
+	defer end(&err)
+	return r.inner.AdjustStock(ctx, productID, adjustments)
+}
+
+// WithTx tracks the whole transaction as "with_tx" and the calls in it as
+// their own operations
+func (r *ProductRepository) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) (err error) {
+	ctx, end := track(ctx, "with_tx")
+	defer end(&err)
+	return r.inner.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		return fn(&ProductRepository{inner: tx})
+	})
+}
@@ -0,0 +1,56 @@
+package instrumented
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+// observations returns how many durations were recorded for an operation
+func observations(t *testing.T, operation string) uint64 {
+	observer, err := metrics.RepositoryOperationDuration.GetMetricWithLabelValues(operation)
+	assert.NoError(t, err)
+	metric := &dto.Metric{}
+	assert.NoError(t, observer.(prometheus.Histogram).Write(metric))
+	return metric.Histogram.GetSampleCount()
+}
+
+func TestOperationsAreTimed(t *testing.T) {
+	repo := NewProductRepository(memory.NewProductRepository())
+	created, read, missing := observations(t, "create"), observations(t, "get_by_id"), observations(t, "get_by_sku")
+
+	assert.NoError(t, repo.Create(context.Background(), &models.Product{ID: "prod_1", SKU: "SKU-1", BaseTitle: "Shirt"}))
+	product, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SKU-1", product.SKU)
+
+	// Failed operations are timed as well
+	_, err = repo.GetBySKU(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+
+	assert.Equal(t, created+1, observations(t, "create"))
+	assert.Equal(t, read+1, observations(t, "get_by_id"))
+	assert.Equal(t, missing+1, observations(t, "get_by_sku"))
+}
+
+func TestTransactionsAreTimed(t *testing.T) {
+	repo := NewProductRepository(memory.NewProductRepository())
+	transactions, created := observations(t, "with_tx"), observations(t, "create")
+
+	assert.NoError(t, repo.WithTx(context.Background(), func(tx repositories.ProductRepository) error {
+		return tx.Create(context.Background(), &models.Product{ID: "prod_1", SKU: "SKU-1"})
+	}))
+	_, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+
+	assert.Equal(t, transactions+1, observations(t, "with_tx"))
+	assert.Equal(t, created+1, observations(t, "create"))
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// AllocationPolicyRepository implements an in-memory allocation policy repository
+type AllocationPolicyRepository struct {
+	policies map[string]map[string]*models.AllocationPolicy // market -> channel -> policy
+	mu       sync.RWMutex
+}
+
+// NewAllocationPolicyRepository creates a new in-memory allocation policy repository
+func NewAllocationPolicyRepository() repositories.AllocationPolicyRepository {
+	return &AllocationPolicyRepository{
+		policies: make(map[string]map[string]*models.AllocationPolicy),
+	}
+}
+
+// Get returns the policy of a market and channel
+func (r *AllocationPolicyRepository) Get(market, channel string) (*models.AllocationPolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	policy, exists := r.policies[market][channel]
+	if !exists {
+		return nil, models.ErrAllocationPolicyNotFound
+	}
+	return copyAllocationPolicy(policy), nil
+}
+
+// Save creates or replaces the policy of a market and channel
+func (r *AllocationPolicyRepository) Save(policy *models.AllocationPolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	channels, exists := r.policies[policy.Market]
+	if !exists {
+		channels = make(map[string]*models.AllocationPolicy)
+		r.policies[policy.Market] = channels
+	}
+	channels[policy.Channel] = copyAllocationPolicy(policy)
+	return nil
+}
+
+// Delete removes the policy of a market and channel
+func (r *AllocationPolicyRepository) Delete(market, channel string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.policies[market][channel]; !exists {
+		return models.ErrAllocationPolicyNotFound
+	}
+	delete(r.policies[market], channel)
+	return nil
+}
+
+// ListByMarket returns every policy of a market, ordered by channel
+func (r *AllocationPolicyRepository) ListByMarket(market string) ([]*models.AllocationPolicy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	policies := make([]*models.AllocationPolicy, 0, len(r.policies[market]))
+	for _, policy := range r.policies[market] {
+		policies = append(policies, copyAllocationPolicy(policy))
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Channel < policies[j].Channel
+	})
+	return policies, nil
+}
+
+// copyAllocationPolicy copies a policy so callers never share its locations with the store
+func copyAllocationPolicy(policy *models.AllocationPolicy) *models.AllocationPolicy {
+	copied := *policy
+	copied.Locations = make([]models.FulfillmentLocation, len(policy.Locations))
+	for i, location := range policy.Locations {
+		copied.Locations[i] = location
+		if location.Coordinates != nil {
+			coordinates := *location.Coordinates
+			copied.Locations[i].Coordinates = &coordinates
+		}
+	}
+	return &copied
+}
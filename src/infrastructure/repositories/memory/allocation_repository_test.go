@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocationPolicySaveGetAndDelete(t *testing.T) {
+	repo := NewAllocationPolicyRepository()
+
+	_, err := repo.Get("SE", "web")
+	assert.ErrorIs(t, err, models.ErrAllocationPolicyNotFound)
+
+	policy := &models.AllocationPolicy{
+		Market:    "SE",
+		Channel:   "web",
+		Strategy:  models.AllocationNearest,
+		Locations: []models.FulfillmentLocation{{ID: "sto", Coordinates: &models.Coordinates{Latitude: 59.3, Longitude: 18.1}}},
+	}
+	assert.NoError(t, repo.Save(policy))
+
+	stored, err := repo.Get("SE", "web")
+	assert.NoError(t, err)
+	assert.Equal(t, policy.Locations, stored.Locations)
+
+	// Changing the returned copy must not change the stored policy
+	stored.Locations[0].Coordinates.Latitude = 0
+	again, _ := repo.Get("SE", "web")
+	assert.Equal(t, 59.3, again.Locations[0].Coordinates.Latitude)
+
+	_, err = repo.Get("SE", "")
+	assert.ErrorIs(t, err, models.ErrAllocationPolicyNotFound)
+
+	assert.NoError(t, repo.Delete("SE", "web"))
+	assert.ErrorIs(t, repo.Delete("SE", "web"), models.ErrAllocationPolicyNotFound)
+}
+
+func TestAllocationPolicyListByMarket(t *testing.T) {
+	repo := NewAllocationPolicyRepository()
+	repo.Save(&models.AllocationPolicy{Market: "SE", Channel: "web"})
+	repo.Save(&models.AllocationPolicy{Market: "SE"})
+	repo.Save(&models.AllocationPolicy{Market: "NO", Channel: "pos"})
+
+	policies, err := repo.ListByMarket("SE")
+	assert.NoError(t, err)
+	assert.Len(t, policies, 2)
+	assert.Equal(t, "", policies[0].Channel)
+	assert.Equal(t, "web", policies[1].Channel)
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// CategoryRepository implements an in-memory category repository
+type CategoryRepository struct {
+	categories map[string]*models.Category
+	mu         sync.RWMutex
+}
+
+// NewCategoryRepository creates a new in-memory category repository
+func NewCategoryRepository() repositories.CategoryRepository {
+	return &CategoryRepository{
+		categories: make(map[string]*models.Category),
+	}
+}
+
+// Get returns a category
+func (r *CategoryRepository) Get(id string) (*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	category, exists := r.categories[id]
+	if !exists {
+		return nil, models.ErrCategoryNotFound
+	}
+	return copyCategory(category), nil
+}
+
+// Save creates or replaces a category
+func (r *CategoryRepository) Save(category *models.Category) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.categories[category.ID] = copyCategory(category)
+	return nil
+}
+
+// Delete removes a category
+func (r *CategoryRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.categories[id]; !exists {
+		return models.ErrCategoryNotFound
+	}
+	delete(r.categories, id)
+	return nil
+}
+
+// List returns every category, ordered by ID
+func (r *CategoryRepository) List() ([]*models.Category, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	categories := make([]*models.Category, 0, len(r.categories))
+	for _, category := range r.categories {
+		categories = append(categories, copyCategory(category))
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].ID < categories[j].ID
+	})
+	return categories, nil
+}
+
+// copyCategory copies a category so callers never share its names with the store
+func copyCategory(category *models.Category) *models.Category {
+	copied := *category
+	copied.Names = make(map[string]string, len(category.Names))
+	for market, name := range category.Names {
+		copied.Names[market] = name
+	}
+	return &copied
+}
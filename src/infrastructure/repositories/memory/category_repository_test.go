@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategorySaveGetListAndDelete(t *testing.T) {
+	repo := NewCategoryRepository()
+
+	_, err := repo.Get("cat_shirts")
+	assert.ErrorIs(t, err, models.ErrCategoryNotFound)
+
+	category := &models.Category{ID: "cat_shirts", Slug: "shirts", ParentID: "cat_clothing", Names: map[string]string{"SE": "Skjortor"}}
+	assert.NoError(t, repo.Save(category))
+	assert.NoError(t, repo.Save(&models.Category{ID: "cat_clothing", Slug: "clothing"}))
+
+	stored, err := repo.Get("cat_shirts")
+	assert.NoError(t, err)
+	assert.Equal(t, category, stored)
+
+	// Changing the returned copy must not change the stored category
+	stored.Names["SE"] = "Tröjor"
+	again, _ := repo.Get("cat_shirts")
+	assert.Equal(t, "Skjortor", again.Names["SE"])
+
+	categories, err := repo.List()
+	assert.NoError(t, err)
+	assert.Len(t, categories, 2)
+	assert.Equal(t, "cat_clothing", categories[0].ID)
+
+	assert.NoError(t, repo.Delete("cat_shirts"))
+	assert.ErrorIs(t, repo.Delete("cat_shirts"), models.ErrCategoryNotFound)
+}
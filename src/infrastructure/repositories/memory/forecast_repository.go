@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// maxLedgerEntries caps the movements kept per variant; the oldest are dropped first
+const maxLedgerEntries = 1000
+
+// ForecastInputRepository implements an in-memory forecast input repository
+type ForecastInputRepository struct {
+	ledger     map[string][]models.LedgerEntry // variant ID -> movements, oldest first
+	velocities map[string]models.SalesVelocity
+	mu         sync.RWMutex
+}
+
+// NewForecastInputRepository creates a new in-memory forecast input repository
+func NewForecastInputRepository() repositories.ForecastInputRepository {
+	return &ForecastInputRepository{
+		ledger:     make(map[string][]models.LedgerEntry),
+		velocities: make(map[string]models.SalesVelocity),
+	}
+}
+
+// AppendLedger adds movements and keeps every variant's ledger in time order
+func (r *ForecastInputRepository) AppendLedger(entries []models.LedgerEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	changed := make(map[string]bool)
+	for _, entry := range entries {
+		r.ledger[entry.VariantID] = append(r.ledger[entry.VariantID], entry)
+		changed[entry.VariantID] = true
+	}
+	for variantID := range changed {
+		ledger := r.ledger[variantID]
+		sort.SliceStable(ledger, func(i, j int) bool {
+			return ledger[i].At.Before(ledger[j].At)
+		})
+		if len(ledger) > maxLedgerEntries {
+			ledger = append([]models.LedgerEntry(nil), ledger[len(ledger)-maxLedgerEntries:]...)
+		}
+		r.ledger[variantID] = ledger
+	}
+	return nil
+}
+
+// SaveVelocity stores a velocity unless a newer one is stored for the variant
+func (r *ForecastInputRepository) SaveVelocity(velocity models.SalesVelocity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stored, exists := r.velocities[velocity.VariantID]; exists && stored.AsOf.After(velocity.AsOf) {
+		return nil
+	}
+	r.velocities[velocity.VariantID] = velocity
+	return nil
+}
+
+// Ledger returns a variant's movements at or after since, oldest first
+func (r *ForecastInputRepository) Ledger(variantID string, since time.Time) ([]models.LedgerEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ledger := r.ledger[variantID]
+	start := sort.Search(len(ledger), func(i int) bool {
+		return !ledger[i].At.Before(since)
+	})
+	return append([]models.LedgerEntry(nil), ledger[start:]...), nil
+}
+
+// Velocity returns a variant's latest velocity, or nil if none was pushed
+func (r *ForecastInputRepository) Velocity(variantID string) (*models.SalesVelocity, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	velocity, exists := r.velocities[variantID]
+	if !exists {
+		return nil, nil
+	}
+	return &velocity, nil
+}
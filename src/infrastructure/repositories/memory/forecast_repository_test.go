@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecastInputLedger(t *testing.T) {
+	repo := NewForecastInputRepository()
+	now := time.Now()
+
+	assert.NoError(t, repo.AppendLedger([]models.LedgerEntry{
+		{VariantID: "v1", Delta: -1, At: now.Add(-time.Hour)},
+		{VariantID: "v1", Delta: -3, At: now.Add(-72 * time.Hour)},
+		{VariantID: "v2", Delta: 10, At: now},
+	}))
+	assert.NoError(t, repo.AppendLedger([]models.LedgerEntry{{VariantID: "v1", Delta: -2, At: now.Add(-24 * time.Hour)}}))
+
+	ledger, err := repo.Ledger("v1", now.Add(-48*time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, ledger, 2)
+	assert.Equal(t, -2, ledger[0].Delta)
+	assert.Equal(t, -1, ledger[1].Delta)
+
+	ledger, _ = repo.Ledger("unknown", time.Time{})
+	assert.Empty(t, ledger)
+}
+
+func TestForecastInputLedgerIsCapped(t *testing.T) {
+	repo := NewForecastInputRepository()
+	start := time.Now()
+	entries := make([]models.LedgerEntry, maxLedgerEntries+10)
+	for i := range entries {
+		entries[i] = models.LedgerEntry{VariantID: "v1", Delta: -1, At: start.Add(time.Duration(i) * time.Minute)}
+	}
+	repo.AppendLedger(entries)
+
+	ledger, _ := repo.Ledger("v1", time.Time{})
+	assert.Len(t, ledger, maxLedgerEntries)
+	assert.True(t, ledger[0].At.Equal(entries[10].At))
+}
+
+func TestForecastInputVelocity(t *testing.T) {
+	repo := NewForecastInputRepository()
+	now := time.Now()
+
+	velocity, err := repo.Velocity("v1")
+	assert.NoError(t, err)
+	assert.Nil(t, velocity)
+
+	repo.SaveVelocity(models.SalesVelocity{VariantID: "v1", UnitsPerDay: 4, AsOf: now})
+	// An older figure arriving late does not replace the newer one
+	repo.SaveVelocity(models.SalesVelocity{VariantID: "v1", UnitsPerDay: 1, AsOf: now.Add(-time.Hour)})
+
+	velocity, _ = repo.Velocity("v1")
+	assert.Equal(t, 4.0, velocity.UnitsPerDay)
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// JobRepository implements an in-memory job repository
+type JobRepository struct {
+	jobs map[string]*models.Job
+	mu   sync.RWMutex
+}
+
+// NewJobRepository creates a new in-memory job repository
+func NewJobRepository() repositories.JobRepository {
+	return &JobRepository{
+		jobs: make(map[string]*models.Job),
+	}
+}
+
+// Create stores a new job in memory
+func (r *JobRepository) Create(job *models.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// GetByID retrieves a job by its ID
+func (r *JobRepository) GetByID(id string) (*models.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	job, exists := r.jobs[id]
+	if !exists {
+		return nil, models.ErrJobNotFound
+	}
+	return copyJob(job), nil
+}
+
+// Update modifies an existing job
+func (r *JobRepository) Update(job *models.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.jobs[job.ID]; !exists {
+		return models.ErrJobNotFound
+	}
+	r.jobs[job.ID] = copyJob(job)
+	return nil
+}
+
+// List returns the most recently created jobs, newest first
+func (r *JobRepository) List(limit int) ([]*models.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	jobs := make([]*models.Job, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, copyJob(job))
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.After(jobs[j].CreatedAt)
+	})
+
+	if limit > 0 && len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// copyJob copies a job so callers never share its error list with the store
+func copyJob(job *models.Job) *models.Job {
+	copied := *job
+	if job.Errors != nil {
+		copied.Errors = append([]string(nil), job.Errors...)
+	}
+	return &copied
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestJob(id string, createdAt time.Time) *models.Job {
+	return &models.Job{
+		ID:        id,
+		Type:      models.JobBatchCreate,
+		Status:    models.JobStatusRunning,
+		Total:     3,
+		CreatedAt: createdAt,
+	}
+}
+
+func TestJobCreateAndGet(t *testing.T) {
+	repo := NewJobRepository()
+	job := createTestJob("job_1", time.Now())
+
+	err := repo.Create(job)
+	assert.NoError(t, err)
+
+	retrieved, err := repo.GetByID(job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, job.ID, retrieved.ID)
+	assert.Equal(t, models.JobStatusRunning, retrieved.Status)
+
+	// Modifying the returned job must not change the stored copy
+	retrieved.Status = models.JobStatusFailed
+	again, err := repo.GetByID(job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobStatusRunning, again.Status)
+}
+
+func TestJobGetNotFound(t *testing.T) {
+	repo := NewJobRepository()
+
+	_, err := repo.GetByID("nonexistent")
+	assert.ErrorIs(t, err, models.ErrJobNotFound)
+}
+
+func TestJobUpdate(t *testing.T) {
+	repo := NewJobRepository()
+	job := createTestJob("job_1", time.Now())
+	assert.NoError(t, repo.Create(job))
+
+	job.Complete(2, 1)
+	err := repo.Update(job)
+	assert.NoError(t, err)
+
+	retrieved, err := repo.GetByID(job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.JobStatusCompleted, retrieved.Status)
+	assert.Equal(t, 2, retrieved.Succeeded)
+	assert.Equal(t, 1, retrieved.Failed)
+	assert.NotNil(t, retrieved.CompletedAt)
+
+	err = repo.Update(createTestJob("nonexistent", time.Now()))
+	assert.ErrorIs(t, err, models.ErrJobNotFound)
+}
+
+func TestJobListNewestFirst(t *testing.T) {
+	repo := NewJobRepository()
+	base := time.Now()
+
+	for i := 0; i < 5; i++ {
+		job := createTestJob(fmt.Sprintf("job_%d", i), base.Add(time.Duration(i)*time.Second))
+		assert.NoError(t, repo.Create(job))
+	}
+
+	jobs, err := repo.List(3)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 3)
+	assert.Equal(t, "job_4", jobs[0].ID)
+	assert.Equal(t, "job_3", jobs[1].ID)
+	assert.Equal(t, "job_2", jobs[2].ID)
+
+	all, err := repo.List(0)
+	assert.NoError(t, err)
+	assert.Len(t, all, 5)
+}
+
+func TestJobErrorsAreCopied(t *testing.T) {
+	repo := NewJobRepository()
+	job := createTestJob("job_1", time.Now())
+	job.Errors = []string{"row 1: invalid"}
+	assert.NoError(t, repo.Create(job))
+
+	job.Errors[0] = "changed"
+	retrieved, err := repo.GetByID(job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"row 1: invalid"}, retrieved.Errors)
+
+	retrieved.Errors[0] = "changed again"
+	again, _ := repo.GetByID(job.ID)
+	assert.Equal(t, "row 1: invalid", again.Errors[0])
+}
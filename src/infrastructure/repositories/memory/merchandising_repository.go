@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// MerchandisingRepository implements an in-memory merchandising repository
+type MerchandisingRepository struct {
+	listings map[string]map[string]*models.Merchandising // market -> category -> positions
+	mu       sync.RWMutex
+}
+
+// NewMerchandisingRepository creates a new in-memory merchandising repository
+func NewMerchandisingRepository() repositories.MerchandisingRepository {
+	return &MerchandisingRepository{
+		listings: make(map[string]map[string]*models.Merchandising),
+	}
+}
+
+// Get returns the positions of a market listing
+func (r *MerchandisingRepository) Get(market, category string) (*models.Merchandising, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	merchandising, exists := r.listings[market][category]
+	if !exists {
+		return nil, models.ErrMerchandisingNotFound
+	}
+	return copyMerchandising(merchandising), nil
+}
+
+// Save creates or replaces the positions of a listing
+func (r *MerchandisingRepository) Save(merchandising *models.Merchandising) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	categories, exists := r.listings[merchandising.Market]
+	if !exists {
+		categories = make(map[string]*models.Merchandising)
+		r.listings[merchandising.Market] = categories
+	}
+	categories[merchandising.Category] = copyMerchandising(merchandising)
+	return nil
+}
+
+// ListByMarket returns the positions of every listing in a market, ordered by category
+func (r *MerchandisingRepository) ListByMarket(market string) ([]*models.Merchandising, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	listings := make([]*models.Merchandising, 0, len(r.listings[market]))
+	for _, merchandising := range r.listings[market] {
+		listings = append(listings, copyMerchandising(merchandising))
+	}
+	sort.Slice(listings, func(i, j int) bool {
+		return listings[i].Category < listings[j].Category
+	})
+	return listings, nil
+}
+
+// copyMerchandising copies positions so callers never share the pins with the store
+func copyMerchandising(merchandising *models.Merchandising) *models.Merchandising {
+	copied := *merchandising
+	copied.Pins = append(make([]models.Pin, 0, len(merchandising.Pins)), merchandising.Pins...)
+	return &copied
+}
@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerchandisingSaveAndGet(t *testing.T) {
+	repo := NewMerchandisingRepository()
+
+	_, err := repo.Get("SE", "shirts")
+	assert.ErrorIs(t, err, models.ErrMerchandisingNotFound)
+
+	merchandising := models.NewMerchandising("SE", "shirts")
+	merchandising.Pins = []models.Pin{{ProductID: "prod_1", Position: 1}}
+	assert.NoError(t, repo.Save(merchandising))
+
+	stored, err := repo.Get("SE", "shirts")
+	assert.NoError(t, err)
+	assert.Equal(t, merchandising.Pins, stored.Pins)
+
+	// Changing the returned copy must not change the stored pins
+	stored.Pins[0].Position = 5
+	again, _ := repo.Get("SE", "shirts")
+	assert.Equal(t, 1, again.Pins[0].Position)
+
+	_, err = repo.Get("NO", "shirts")
+	assert.ErrorIs(t, err, models.ErrMerchandisingNotFound)
+}
+
+func TestMerchandisingListByMarket(t *testing.T) {
+	repo := NewMerchandisingRepository()
+	repo.Save(models.NewMerchandising("SE", "shirts"))
+	repo.Save(models.NewMerchandising("SE", ""))
+	repo.Save(models.NewMerchandising("NO", "shoes"))
+
+	listings, err := repo.ListByMarket("SE")
+	assert.NoError(t, err)
+	assert.Len(t, listings, 2)
+	assert.Equal(t, "", listings[0].Category)
+	assert.Equal(t, "shirts", listings[1].Category)
+}
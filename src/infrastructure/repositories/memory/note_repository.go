@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// NoteRepository implements an in-memory note repository
+type NoteRepository struct {
+	notes map[string]map[string]*models.ProductNote // product ID -> note ID -> note
+	mu    sync.RWMutex
+}
+
+// NewNoteRepository creates a new in-memory note repository
+func NewNoteRepository() repositories.NoteRepository {
+	return &NoteRepository{
+		notes: make(map[string]map[string]*models.ProductNote),
+	}
+}
+
+// Save creates or replaces a note
+func (r *NoteRepository) Save(note *models.ProductNote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	notes, exists := r.notes[note.ProductID]
+	if !exists {
+		notes = make(map[string]*models.ProductNote)
+		r.notes[note.ProductID] = notes
+	}
+	copied := *note
+	notes[note.ID] = &copied
+	return nil
+}
+
+// Get returns a note of a product
+func (r *NoteRepository) Get(productID, noteID string) (*models.ProductNote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	note, exists := r.notes[productID][noteID]
+	if !exists {
+		return nil, models.ErrNoteNotFound
+	}
+	copied := *note
+	return &copied, nil
+}
+
+// Delete removes notes of a product
+func (r *NoteRepository) Delete(productID string, noteIDs ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range noteIDs {
+		delete(r.notes[productID], id)
+	}
+	return nil
+}
+
+// ListByProduct returns every note of a product, oldest first
+func (r *NoteRepository) ListByProduct(productID string) ([]*models.ProductNote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notes := make([]*models.ProductNote, 0, len(r.notes[productID]))
+	for _, note := range r.notes[productID] {
+		copied := *note
+		notes = append(notes, &copied)
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		if !notes[i].CreatedAt.Equal(notes[j].CreatedAt) {
+			return notes[i].CreatedAt.Before(notes[j].CreatedAt)
+		}
+		return notes[i].ID < notes[j].ID
+	})
+	return notes, nil
+}
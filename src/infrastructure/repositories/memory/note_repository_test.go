@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoteSaveGetAndDelete(t *testing.T) {
+	repo := NewNoteRepository()
+
+	_, err := repo.Get("prod_1", "n1")
+	assert.ErrorIs(t, err, models.ErrNoteNotFound)
+
+	note := &models.ProductNote{ID: "n1", ProductID: "prod_1", Author: "ada", Body: "Is the EU size chart right?"}
+	assert.NoError(t, repo.Save(note))
+
+	stored, err := repo.Get("prod_1", "n1")
+	assert.NoError(t, err)
+	assert.Equal(t, note, stored)
+
+	// Changing the returned copy must not change the stored note
+	stored.Body = "changed"
+	again, _ := repo.Get("prod_1", "n1")
+	assert.Equal(t, "Is the EU size chart right?", again.Body)
+
+	_, err = repo.Get("prod_2", "n1")
+	assert.ErrorIs(t, err, models.ErrNoteNotFound)
+
+	assert.NoError(t, repo.Delete("prod_1", "n1", "unknown"))
+	_, err = repo.Get("prod_1", "n1")
+	assert.ErrorIs(t, err, models.ErrNoteNotFound)
+}
+
+func TestNoteListByProduct(t *testing.T) {
+	repo := NewNoteRepository()
+	now := time.Now()
+	repo.Save(&models.ProductNote{ID: "b", ProductID: "prod_1", CreatedAt: now})
+	repo.Save(&models.ProductNote{ID: "a", ProductID: "prod_1", CreatedAt: now.Add(time.Second)})
+	repo.Save(&models.ProductNote{ID: "c", ProductID: "prod_1", CreatedAt: now})
+	repo.Save(&models.ProductNote{ID: "d", ProductID: "prod_2", CreatedAt: now})
+
+	notes, err := repo.ListByProduct("prod_1")
+	assert.NoError(t, err)
+	assert.Len(t, notes, 3)
+	assert.Equal(t, []string{"b", "c", "a"}, []string{notes[0].ID, notes[1].ID, notes[2].ID})
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// OffsetRepository implements an in-memory consumer offset repository
+type OffsetRepository struct {
+	offsets map[string]int64
+	mu      sync.RWMutex
+}
+
+// NewOffsetRepository creates a new in-memory offset repository
+func NewOffsetRepository() repositories.OffsetRepository {
+	return &OffsetRepository{
+		offsets: make(map[string]int64),
+	}
+}
+
+// Get returns the committed sequence for a consumer
+func (r *OffsetRepository) Get(consumer string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.offsets[consumer], nil
+}
+
+// Commit stores the committed sequence for a consumer
+func (r *OffsetRepository) Commit(consumer string, sequence int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.offsets[consumer] = sequence
+	return nil
+}
+
+// List returns a copy of all committed sequences
+func (r *OffsetRepository) List() (map[string]int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make(map[string]int64, len(r.offsets))
+	for consumer, sequence := range r.offsets {
+		result[consumer] = sequence
+	}
+	return result, nil
+}
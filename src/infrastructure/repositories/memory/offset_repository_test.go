@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffsetCommitAndGet(t *testing.T) {
+	repo := NewOffsetRepository()
+
+	offset, err := repo.Get("websocket")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), offset)
+
+	assert.NoError(t, repo.Commit("websocket", 42))
+	offset, err = repo.Get("websocket")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), offset)
+}
+
+func TestOffsetListReturnsCopy(t *testing.T) {
+	repo := NewOffsetRepository()
+	assert.NoError(t, repo.Commit("websocket", 3))
+	assert.NoError(t, repo.Commit("dashboard", 5))
+
+	offsets, err := repo.List()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"websocket": 3, "dashboard": 5}, offsets)
+
+	offsets["websocket"] = 100
+	offset, _ := repo.Get("websocket")
+	assert.Equal(t, int64(3), offset)
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// OutboxRepository implements an in-memory event outbox. Entries are kept in
+// the order they were added and indexed by event ID.
+type OutboxRepository struct {
+	entries []*models.OutboxEntry
+	byID    map[string]*models.OutboxEntry
+	mu      sync.RWMutex
+}
+
+// NewOutboxRepository creates a new in-memory outbox repository
+func NewOutboxRepository() repositories.OutboxRepository {
+	return &OutboxRepository{
+		byID: make(map[string]*models.OutboxEntry),
+	}
+}
+
+// Add queues events as pending, skipping events already in the outbox
+func (r *OutboxRepository) Add(events ...*models.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, event := range events {
+		if _, exists := r.byID[event.ID]; exists {
+			continue
+		}
+		entry := &models.OutboxEntry{
+			Event:       event,
+			State:       models.OutboxPending,
+			NextAttempt: now,
+			CreatedAt:   now,
+		}
+		r.entries = append(r.entries, entry)
+		r.byID[event.ID] = entry
+	}
+	return nil
+}
+
+// Pending returns copies of the pending entries, oldest first
+func (r *OutboxRepository) Pending(limit int) ([]*models.OutboxEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pending := make([]*models.OutboxEntry, 0)
+	for _, entry := range r.entries {
+		if limit > 0 && len(pending) >= limit {
+			break
+		}
+		if entry.State == models.OutboxPending {
+			entryCopy := *entry
+			pending = append(pending, &entryCopy)
+		}
+	}
+	return pending, nil
+}
+
+// MarkDelivered records that an event was published
+func (r *OutboxRepository) MarkDelivered(eventID string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, exists := r.byID[eventID]
+	if !exists {
+		return models.ErrOutboxEntryNotFound
+	}
+	entry.State = models.OutboxDelivered
+	entry.Attempts++
+	entry.LastError = ""
+	entry.DeliveredAt = &at
+	return nil
+}
+
+// MarkAttemptFailed records a failed attempt and when to retry it
+func (r *OutboxRepository) MarkAttemptFailed(eventID string, reason string, retryAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, exists := r.byID[eventID]
+	if !exists {
+		return models.ErrOutboxEntryNotFound
+	}
+	entry.Attempts++
+	entry.LastError = reason
+	if retryAt.IsZero() {
+		entry.State = models.OutboxFailed
+		return nil
+	}
+	entry.NextAttempt = retryAt
+	return nil
+}
+
+// Stats counts the entries by state
+func (r *OutboxRepository) Stats() (models.OutboxStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var stats models.OutboxStats
+	for _, entry := range r.entries {
+		switch entry.State {
+		case models.OutboxPending:
+			stats.Pending++
+		case models.OutboxDelivered:
+			stats.Delivered++
+		case models.OutboxFailed:
+			stats.Failed++
+		}
+	}
+	return stats, nil
+}
+
+// Prune removes the entries delivered before the given time
+func (r *OutboxRepository) Prune(before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := make([]*models.OutboxEntry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		if entry.State == models.OutboxDelivered && entry.DeliveredAt.Before(before) {
+			delete(r.byID, entry.Event.ID)
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	pruned := len(r.entries) - len(kept)
+	r.entries = kept
+	return pruned, nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxPendingInOrder(t *testing.T) {
+	repo := NewOutboxRepository()
+	assert.NoError(t, repo.Add(&models.Event{ID: "evt_1"}, &models.Event{ID: "evt_2"}))
+	assert.NoError(t, repo.Add(&models.Event{ID: "evt_3"}, &models.Event{ID: "evt_1"}))
+
+	pending, err := repo.Pending(0)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 3)
+	for i, id := range []string{"evt_1", "evt_2", "evt_3"} {
+		assert.Equal(t, id, pending[i].Event.ID)
+		assert.Equal(t, models.OutboxPending, pending[i].State)
+	}
+
+	pending, err = repo.Pending(2)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 2)
+}
+
+func TestOutboxAttempts(t *testing.T) {
+	repo := NewOutboxRepository()
+	assert.NoError(t, repo.Add(&models.Event{ID: "evt_1"}, &models.Event{ID: "evt_2"}, &models.Event{ID: "evt_3"}))
+	now := time.Now()
+
+	assert.NoError(t, repo.MarkDelivered("evt_1", now))
+	assert.NoError(t, repo.MarkAttemptFailed("evt_2", "broker down", now.Add(time.Minute)))
+	assert.NoError(t, repo.MarkAttemptFailed("evt_3", "broker down", time.Time{}))
+	assert.ErrorIs(t, repo.MarkDelivered("evt_missing", now), models.ErrOutboxEntryNotFound)
+	assert.ErrorIs(t, repo.MarkAttemptFailed("evt_missing", "", now), models.ErrOutboxEntryNotFound)
+
+	// The entry with a retry stays pending until its next attempt
+	pending, _ := repo.Pending(0)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, "evt_2", pending[0].Event.ID)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "broker down", pending[0].LastError)
+	assert.Equal(t, now.Add(time.Minute), pending[0].NextAttempt)
+
+	stats, err := repo.Stats()
+	assert.NoError(t, err)
+	assert.Equal(t, models.OutboxStats{Pending: 1, Delivered: 1, Failed: 1}, stats)
+}
+
+func TestOutboxPrune(t *testing.T) {
+	repo := NewOutboxRepository()
+	assert.NoError(t, repo.Add(&models.Event{ID: "evt_1"}, &models.Event{ID: "evt_2"}))
+	now := time.Now()
+	assert.NoError(t, repo.MarkDelivered("evt_1", now.Add(-time.Hour)))
+
+	pruned, err := repo.Prune(now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+	stats, _ := repo.Stats()
+	assert.Equal(t, models.OutboxStats{Pending: 1}, stats)
+
+	// A pruned event can be queued again
+	assert.NoError(t, repo.Add(&models.Event{ID: "evt_1"}))
+	stats, _ = repo.Stats()
+	assert.Equal(t, 2, stats.Pending)
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// PriceListRepository implements an in-memory price list repository
+type PriceListRepository struct {
+	lists map[string]*models.PriceList
+	mu    sync.RWMutex
+}
+
+// NewPriceListRepository creates a new in-memory price list repository
+func NewPriceListRepository() repositories.PriceListRepository {
+	return &PriceListRepository{
+		lists: make(map[string]*models.PriceList),
+	}
+}
+
+// Get returns a price list
+func (r *PriceListRepository) Get(id string) (*models.PriceList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list, exists := r.lists[id]
+	if !exists {
+		return nil, models.ErrPriceListNotFound
+	}
+	return copyPriceList(list), nil
+}
+
+// Save creates or replaces a price list
+func (r *PriceListRepository) Save(list *models.PriceList) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lists[list.ID] = copyPriceList(list)
+	return nil
+}
+
+// Delete removes a price list
+func (r *PriceListRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.lists[id]; !exists {
+		return models.ErrPriceListNotFound
+	}
+	delete(r.lists, id)
+	return nil
+}
+
+// List returns every price list, ordered by ID
+func (r *PriceListRepository) List() ([]*models.PriceList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	lists := make([]*models.PriceList, 0, len(r.lists))
+	for _, list := range r.lists {
+		lists = append(lists, copyPriceList(list))
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return lists[i].ID < lists[j].ID
+	})
+	return lists, nil
+}
+
+// copyPriceList copies a list so callers never share its prices with the store
+func copyPriceList(list *models.PriceList) *models.PriceList {
+	copied := *list
+	copied.Prices = append([]models.ScheduledPrice{}, list.Prices...)
+	return &copied
+}
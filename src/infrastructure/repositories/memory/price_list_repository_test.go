@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceListSaveGetListAndDelete(t *testing.T) {
+	repo := NewPriceListRepository()
+
+	_, err := repo.Get("b2b")
+	assert.ErrorIs(t, err, models.ErrPriceListNotFound)
+
+	list := &models.PriceList{ID: "b2b", Currency: "SEK", Prices: []models.ScheduledPrice{{ProductID: "prod_1", Amount: 80}}}
+	assert.NoError(t, repo.Save(list))
+	assert.NoError(t, repo.Save(&models.PriceList{ID: "a-sale", Currency: "EUR"}))
+
+	stored, err := repo.Get("b2b")
+	assert.NoError(t, err)
+	assert.Equal(t, list.Prices, stored.Prices)
+
+	// Changing the returned copy must not change the stored list
+	stored.Prices[0].Amount = 0
+	again, _ := repo.Get("b2b")
+	assert.Equal(t, 80.0, again.Prices[0].Amount)
+
+	lists, err := repo.List()
+	assert.NoError(t, err)
+	assert.Len(t, lists, 2)
+	assert.Equal(t, "a-sale", lists[0].ID)
+
+	assert.NoError(t, repo.Delete("b2b"))
+	assert.ErrorIs(t, repo.Delete("b2b"), models.ErrPriceListNotFound)
+}
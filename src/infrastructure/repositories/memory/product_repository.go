@@ -1,87 +1,290 @@
 package memory
 
 import (
+	"context"
+	"hash/fnv"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/jimmitjoo/ecom/src/domain/models"
 	"github.com/jimmitjoo/ecom/src/domain/repositories"
 	eventstore "github.com/jimmitjoo/ecom/src/infrastructure/events/memory"
 )
 
-// ProductRepository implements an in-memory product repository
+// DefaultShardCount is the number of shards used by NewProductRepository
+const DefaultShardCount = 32
+
+// productShard holds the products whose IDs hash to the shard
+type productShard struct {
+	products map[string]*models.Product
+	mu       sync.RWMutex // Mutex to protect map-operations
+}
+
+// skuStripe indexes the SKUs that hash to the stripe
+type skuStripe struct {
+	skus map[string]string // SKU to product ID
+	mu   sync.RWMutex
+}
+
+// ProductRepository implements an in-memory product repository.
+// Products and events are split into shards by product ID so that operations
+// on different products do not contend for the same lock. SKUs are indexed
+// across shards in stripes by SKU; writes that change a product's SKU take
+// the locks of the old and new SKU's stripes, in index order, before the
+// shard lock. Other writes take only the shard lock.
 type ProductRepository struct {
-	products   map[string]*models.Product
-	eventStore *eventstore.MemoryEventStore
-	mu         sync.RWMutex // Mutex to protect map-operations
+	shards      []*productShard
+	eventStores []*eventstore.MemoryEventStore // event stripes, same hashing as shards
+	skuStripes  []*skuStripe                   // SKU stripes, same count as shards
+	outbox      repositories.OutboxRepository  // Queues the stored events once Outbox was called
 }
 
 // NewProductRepository creates a new in-memory product repository
 func NewProductRepository() repositories.ProductRepository {
-	return &ProductRepository{
-		products:   make(map[string]*models.Product),
-		eventStore: eventstore.NewMemoryEventStore(),
+	return NewShardedProductRepository(DefaultShardCount)
+}
+
+// Options configures an in-memory product repository; zero values use the defaults
+type Options struct {
+	ShardCount       int   // Defaults to DefaultShardCount
+	SnapshotInterval int64 // Versions between event snapshots, defaults to eventstore.DefaultSnapshotInterval; negative takes none
+}
+
+// NewShardedProductRepository creates an in-memory product repository with the given number of shards
+func NewShardedProductRepository(shardCount int) *ProductRepository {
+	if shardCount < 1 {
+		shardCount = 1
+	}
+	return NewProductRepositoryWithOptions(Options{ShardCount: shardCount})
+}
+
+// NewProductRepositoryWithOptions creates an in-memory product repository configured by options
+func NewProductRepositoryWithOptions(options Options) *ProductRepository {
+	shardCount := options.ShardCount
+	if shardCount < 1 {
+		shardCount = DefaultShardCount
 	}
+	snapshotInterval := options.SnapshotInterval
+	if snapshotInterval == 0 {
+		snapshotInterval = eventstore.DefaultSnapshotInterval
+	}
+
+	r := &ProductRepository{
+		shards:      make([]*productShard, shardCount),
+		eventStores: make([]*eventstore.MemoryEventStore, shardCount),
+		skuStripes:  make([]*skuStripe, shardCount),
+	}
+	for i := range r.shards {
+		r.shards[i] = &productShard{products: make(map[string]*models.Product)}
+		r.skuStripes[i] = &skuStripe{skus: make(map[string]string)}
+		r.eventStores[i] = eventstore.NewMemoryEventStoreWithSnapshots(snapshotInterval)
+	}
+	return r
+}
+
+// shardIndex maps a product ID to its shard, and a SKU to its stripe
+func (r *ProductRepository) shardIndex(id string) int {
+	h := fnv.New32a()
+	h.Write([]byte(id))
+	return int(h.Sum32() % uint32(len(r.shards)))
+}
+
+func (r *ProductRepository) shardFor(id string) *productShard {
+	return r.shards[r.shardIndex(id)]
+}
+
+func (r *ProductRepository) stripeFor(sku string) *skuStripe {
+	return r.skuStripes[r.shardIndex(sku)]
+}
+
+// lockSKUs locks the stripes of the non-empty SKUs in index order and returns
+// the function that unlocks them
+func (r *ProductRepository) lockSKUs(skus ...string) (unlock func()) {
+	locked := make(map[int]bool)
+	indexes := make([]int, 0, len(skus))
+	for _, sku := range skus {
+		if index := r.shardIndex(sku); sku != "" && !locked[index] {
+			locked[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		r.skuStripes[index].mu.Lock()
+	}
+	return func() {
+		for _, index := range indexes {
+			r.skuStripes[index].mu.Unlock()
+		}
+	}
+}
+
+// skuOf returns the product's SKU, empty for no product
+func skuOf(product *models.Product) string {
+	if product == nil {
+		return ""
+	}
+	return product.SKU
+}
+
+// put stores the product under the ID, or deletes the ID's product when
+// product is nil, failing with models.ErrProductNotFound when the ID must
+// exist and does not. A write that keeps the SKU holds only the shard lock.
+func (r *ProductRepository) put(ctx context.Context, id string, product *models.Product, mustExist bool) error {
+	for {
+		shard := r.shardFor(id)
+		shard.mu.Lock()
+		previous, exists := shard.products[id]
+		if !exists && mustExist {
+			shard.mu.Unlock()
+			return models.ErrProductNotFound
+		}
+		if skuOf(previous) == skuOf(product) {
+			// The index already maps the SKU to the product
+			if product == nil {
+				delete(shard.products, id)
+			} else {
+				shard.products[id] = product
+			}
+			shard.mu.Unlock()
+			return nil
+		}
+		shard.mu.Unlock()
+
+		if done, err := r.putChangingSKU(ctx, id, previous, product); done {
+			return err
+		}
+	}
+}
+
+// putChangingSKU does put's write when it changes the SKU from the previous
+// product's, under the locks of both SKUs' stripes. It is not done when the
+// stored product is no longer previous, and put reads it again.
+func (r *ProductRepository) putChangingSKU(ctx context.Context, id string, previous, product *models.Product) (done bool, err error) {
+	unlock := r.lockSKUs(skuOf(previous), skuOf(product))
+	defer unlock()
+	if product != nil && r.skuTaken(ctx, product) {
+		return true, models.ErrDuplicateSKU
+	}
+
+	shard := r.shardFor(id)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+	if shard.products[id] != previous {
+		return false, nil
+	}
+	if previous != nil {
+		r.releaseSKU(previous)
+	}
+	if product == nil {
+		delete(shard.products, id)
+		return true, nil
+	}
+	shard.products[id] = product
+	r.claimSKU(product)
+	return true, nil
 }
 
 // Create stores a new product in memory
-func (r *ProductRepository) Create(product *models.Product) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	r.products[product.ID] = product
-	return nil
+func (r *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	return r.put(ctx, product.ID, product, false)
 }
 
-// GetByID retrieves a product by its ID
-func (r *ProductRepository) GetByID(id string) (*models.Product, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	product, exists := r.products[id]
+// GetBySKU retrieves the product with a SKU
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	stripe := r.stripeFor(sku)
+	stripe.mu.RLock()
+	id, exists := stripe.skus[sku]
+	stripe.mu.RUnlock()
 	if !exists {
 		return nil, models.ErrProductNotFound
 	}
+	product, err := r.GetByID(ctx, id)
+	if err != nil || product.SKU != sku {
+		return nil, models.ErrProductNotFound
+	}
 	return product, nil
 }
 
-// Update modifies an existing product
-func (r *ProductRepository) Update(product *models.Product) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	if _, exists := r.products[product.ID]; !exists {
-		return models.ErrProductNotFound
+// skuTaken reports whether another product has the product's SKU; the caller
+// holds the SKU's stripe lock but no shard lock
+func (r *ProductRepository) skuTaken(ctx context.Context, product *models.Product) bool {
+	if product.SKU == "" {
+		return false
 	}
-	r.products[product.ID] = product
-	return nil
+	id, exists := r.stripeFor(product.SKU).skus[product.SKU]
+	if !exists || id == product.ID {
+		return false
+	}
+	other, err := r.GetByID(ctx, id)
+	return err == nil && other.SKU == product.SKU
 }
 
-// Delete removes a product from storage
-func (r *ProductRepository) Delete(id string) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-	if _, exists := r.products[id]; !exists {
-		return models.ErrProductNotFound
+// claimSKU indexes the product's SKU; the caller holds the SKU's stripe lock
+func (r *ProductRepository) claimSKU(product *models.Product) {
+	if product.SKU != "" {
+		r.stripeFor(product.SKU).skus[product.SKU] = product.ID
 	}
-	delete(r.products, id)
-	return nil
 }
 
-// List returns all stored products
-func (r *ProductRepository) List(page, pageSize int) ([]*models.Product, int, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+// releaseSKU removes the product's SKU from the index; the caller holds the
+// SKU's stripe lock
+func (r *ProductRepository) releaseSKU(product *models.Product) {
+	if product.SKU == "" {
+		return
+	}
+	stripe := r.stripeFor(product.SKU)
+	if stripe.skus[product.SKU] == product.ID {
+		delete(stripe.skus, product.SKU)
+	}
+}
 
-	// Convert map to slice for pagination
-	allProducts := make([]*models.Product, 0, len(r.products))
-	for _, product := range r.products {
-		allProducts = append(allProducts, product)
+// GetByID retrieves a product by its ID
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	shard := r.shardFor(id)
+	shard.mu.RLock()
+	defer shard.mu.RUnlock()
+	product, exists := shard.products[id]
+	if !exists {
+		return nil, models.ErrProductNotFound
 	}
+	return product, nil
+}
 
-	// Sort products by CreatedAt in descending order (newest first)
-	sort.Slice(allProducts, func(i, j int) bool {
-		return allProducts[i].CreatedAt.After(allProducts[j].CreatedAt)
-	})
+// Update modifies an existing product
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product) error {
+	return r.put(ctx, product.ID, product, true)
+}
+
+// AdjustStock applies stock adjustments while holding the product's shard lock
+func (r *ProductRepository) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (*models.Product, *models.Product, error) {
+	shard := r.shardFor(productID)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
 
-	// Calculate total number of products
+	current, exists := shard.products[productID]
+	if !exists {
+		return nil, nil, models.ErrProductNotFound
+	}
+	updated, err := current.WithStockAdjustments(adjustments, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	shard.products[productID] = updated
+	return current, updated.Clone(), nil
+}
+
+// Delete removes a product from storage
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	return r.put(ctx, id, nil, true)
+}
+
+// List returns the stored products that match the filter
+func (r *ProductRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	allProducts := r.matching(filter, nil)
+
+	// Calculate total number of matching products
 	total := len(allProducts)
 
 	// Calculate start and end index for pagination
@@ -101,11 +304,118 @@ func (r *ProductRepository) List(page, pageSize int) ([]*models.Product, int, er
 	return allProducts[start:end], total, nil
 }
 
+// ListAfter returns the matching products after the cursor
+func (r *ProductRepository) ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) ([]*models.Product, *models.ProductCursor, error) {
+	products := r.matching(filter, after)
+	if limit <= 0 || len(products) <= limit {
+		return products, nil, nil
+	}
+	return products[:limit], models.CursorFor(products[limit-1]), nil
+}
+
+// matching collects the products that match the filter and follow the cursor
+// from every shard, holding one shard lock at a time, in listing order
+func (r *ProductRepository) matching(filter models.ProductFilter, after *models.ProductCursor) []*models.Product {
+	filter = filter.Normalize()
+	products := make([]*models.Product, 0)
+	for _, shard := range r.shards {
+		shard.mu.RLock()
+		for _, product := range shard.products {
+			if filter.Matches(product) && after.Follows(product) {
+				products = append(products, product)
+			}
+		}
+		shard.mu.RUnlock()
+	}
+	models.SortForListing(products)
+	return products
+}
+
 // GetEventsByProductID hämtar alla events för en produkt från en given version
-func (r *ProductRepository) GetEventsByProductID(productID string, fromVersion int64) ([]*models.Event, error) {
-	return r.eventStore.GetEvents(productID, fromVersion)
+func (r *ProductRepository) GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
+	return r.eventStores[r.shardIndex(productID)].GetEvents(productID, fromVersion)
+}
+
+func (r *ProductRepository) StoreEvent(ctx context.Context, event *models.Event) error {
+	if err := r.eventStores[r.shardIndex(event.EntityID)].StoreEvent(event); err != nil {
+		return err
+	}
+	return r.queue(event)
+}
+
+// Outbox returns the repository's event outbox, in memory like its products,
+// and queues every event stored from then on in it. It must not be called
+// while the repository is written.
+func (r *ProductRepository) Outbox() repositories.OutboxRepository {
+	if r.outbox == nil {
+		r.outbox = NewOutboxRepository()
+	}
+	return r.outbox
+}
+
+// queue adds stored events to the outbox, if the repository has one
+func (r *ProductRepository) queue(events ...*models.Event) error {
+	if r.outbox == nil || len(events) == 0 {
+		return nil
+	}
+	return r.outbox.Add(events...)
 }
 
-func (r *ProductRepository) StoreEvent(event *models.Event) error {
-	return r.eventStore.StoreEvent(event)
+// GetEventsUntil returns all product events recorded at or before the given time
+func (r *ProductRepository) GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error) {
+	events := make([]*models.Event, 0)
+	for _, store := range r.eventStores {
+		stripe, err := store.GetEventsUntil(until)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, stripe...)
+	}
+
+	// Merge the stripes back into the order the events happened
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Timestamp.Equal(events[j].Timestamp) {
+			return events[i].Timestamp.Before(events[j].Timestamp)
+		}
+		return events[i].Sequence < events[j].Sequence
+	})
+	return events, nil
+}
+
+// CompactEvents drops the events recorded before each product's latest event
+// snapshot and returns how many were dropped. Replays, version listings and
+// rollbacks can no longer reach the dropped versions.
+func (r *ProductRepository) CompactEvents() int {
+	dropped := 0
+	for _, store := range r.eventStores {
+		dropped += store.Compact()
+	}
+	return dropped
+}
+
+// StartEventCompaction compacts the events every interval until the returned
+// function is called, which waits for a running compaction to finish
+func (r *ProductRepository) StartEventCompaction(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				r.CompactEvents()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+		<-stopped
+	}
 }
@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -53,11 +54,11 @@ func TestCreateAndGetProduct(t *testing.T) {
 	product := createTestProduct()
 
 	// Test Create
-	err := repo.Create(product)
+	err := repo.Create(context.Background(), product)
 	assert.NoError(t, err)
 
 	// Test GetByID
-	retrieved, err := repo.GetByID(product.ID)
+	retrieved, err := repo.GetByID(context.Background(), product.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, product.ID, retrieved.ID)
 	assert.Equal(t, product.SKU, retrieved.SKU)
@@ -67,7 +68,7 @@ func TestCreateAndGetProduct(t *testing.T) {
 func TestGetNonExistentProduct(t *testing.T) {
 	repo := NewProductRepository()
 
-	_, err := repo.GetByID("nonexistent")
+	_, err := repo.GetByID(context.Background(), "nonexistent")
 	assert.Error(t, err)
 	assert.Equal(t, models.ErrProductNotFound, err)
 }
@@ -77,17 +78,17 @@ func TestUpdateProduct(t *testing.T) {
 	product := createTestProduct()
 
 	// Create first
-	err := repo.Create(product)
+	err := repo.Create(context.Background(), product)
 	assert.NoError(t, err)
 
 	// Update
 	product.BaseTitle = "Updated Title"
 	product.Version++
-	err = repo.Update(product)
+	err = repo.Update(context.Background(), product)
 	assert.NoError(t, err)
 
 	// Verify update
-	updated, err := repo.GetByID(product.ID)
+	updated, err := repo.GetByID(context.Background(), product.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, "Updated Title", updated.BaseTitle)
 	assert.Equal(t, int64(2), updated.Version)
@@ -97,7 +98,7 @@ func TestUpdateNonExistentProduct(t *testing.T) {
 	repo := NewProductRepository()
 	product := createTestProduct()
 
-	err := repo.Update(product)
+	err := repo.Update(context.Background(), product)
 	assert.Error(t, err)
 	assert.Equal(t, models.ErrProductNotFound, err)
 }
@@ -107,15 +108,15 @@ func TestDeleteProduct(t *testing.T) {
 	product := createTestProduct()
 
 	// Create first
-	err := repo.Create(product)
+	err := repo.Create(context.Background(), product)
 	assert.NoError(t, err)
 
 	// Delete
-	err = repo.Delete(product.ID)
+	err = repo.Delete(context.Background(), product.ID)
 	assert.NoError(t, err)
 
 	// Verify deletion
-	_, err = repo.GetByID(product.ID)
+	_, err = repo.GetByID(context.Background(), product.ID)
 	assert.Error(t, err)
 	assert.Equal(t, models.ErrProductNotFound, err)
 }
@@ -123,11 +124,56 @@ func TestDeleteProduct(t *testing.T) {
 func TestDeleteNonExistentProduct(t *testing.T) {
 	repo := NewProductRepository()
 
-	err := repo.Delete("nonexistent")
+	err := repo.Delete(context.Background(), "nonexistent")
 	assert.Error(t, err)
 	assert.Equal(t, models.ErrProductNotFound, err)
 }
 
+func TestSKUUniqueness(t *testing.T) {
+	repo := NewProductRepository()
+	products := createTestProducts(2)
+	for _, product := range products {
+		assert.NoError(t, repo.Create(context.Background(), product))
+	}
+
+	duplicate := createTestProduct()
+	duplicate.ID = "test_prod_3"
+	duplicate.SKU = products[0].SKU
+	assert.ErrorIs(t, repo.Create(context.Background(), duplicate), models.ErrDuplicateSKU)
+
+	renamed := products[1].Clone()
+	renamed.SKU = products[0].SKU
+	assert.ErrorIs(t, repo.Update(context.Background(), renamed), models.ErrDuplicateSKU)
+
+	// Re-creating a product under its own ID keeps its SKU
+	assert.NoError(t, repo.Create(context.Background(), products[0].Clone()))
+}
+
+func TestGetBySKU(t *testing.T) {
+	repo := NewProductRepository()
+	product := createTestProduct()
+	assert.NoError(t, repo.Create(context.Background(), product))
+
+	found, err := repo.GetBySKU(context.Background(), "TEST-123")
+	assert.NoError(t, err)
+	assert.Equal(t, product.ID, found.ID)
+
+	// Changing the SKU frees the old one
+	renamed := product.Clone()
+	renamed.SKU = "TEST-456"
+	assert.NoError(t, repo.Update(context.Background(), renamed))
+	_, err = repo.GetBySKU(context.Background(), "TEST-123")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	other := createTestProduct()
+	other.ID = "test_prod_2"
+	assert.NoError(t, repo.Create(context.Background(), other))
+
+	// Deleting frees the SKU too
+	assert.NoError(t, repo.Delete(context.Background(), renamed.ID))
+	_, err = repo.GetBySKU(context.Background(), "TEST-456")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
 func TestListProducts(t *testing.T) {
 	repo := NewProductRepository()
 
@@ -141,12 +187,12 @@ func TestListProducts(t *testing.T) {
 	for i, p := range products {
 		p.ID = p.ID + string(rune('A'+i))
 		p.SKU = p.SKU + string(rune('A'+i))
-		err := repo.Create(p)
+		err := repo.Create(context.Background(), p)
 		assert.NoError(t, err)
 	}
 
 	// List all products
-	listed, total, err := repo.List(1, 10)
+	listed, total, err := repo.List(context.Background(), models.ProductFilter{}, 1, 10)
 	assert.NoError(t, err)
 	assert.Len(t, listed, len(products))
 	assert.Equal(t, len(products), total)
@@ -172,7 +218,7 @@ func TestEventStorage(t *testing.T) {
 	}
 
 	// Store event
-	err := repo.StoreEvent(createEvent)
+	err := repo.StoreEvent(context.Background(), createEvent)
 	assert.NoError(t, err)
 
 	// Update event
@@ -193,11 +239,11 @@ func TestEventStorage(t *testing.T) {
 		Timestamp: time.Now(),
 	}
 
-	err = repo.StoreEvent(updateEvent)
+	err = repo.StoreEvent(context.Background(), updateEvent)
 	assert.NoError(t, err)
 
 	// Get events
-	events, err := repo.GetEventsByProductID(product.ID, 1)
+	events, err := repo.GetEventsByProductID(context.Background(), product.ID, 1)
 	assert.NoError(t, err)
 	assert.Len(t, events, 2)
 
@@ -208,19 +254,58 @@ func TestEventStorage(t *testing.T) {
 	assert.Equal(t, int64(2), events[1].Version)
 }
 
+func TestConcurrentSKUClaims(t *testing.T) {
+	repo := NewShardedProductRepository(4)
+	ctx := context.Background()
+
+	// Products racing for the same SKUs, and for SKUs others rename away from
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			product := createTestProduct()
+			product.ID = fmt.Sprintf("test_prod_%d", i)
+			product.SKU = fmt.Sprintf("TEST-%d", i%8)
+			if repo.Create(ctx, product) != nil {
+				return
+			}
+			renamed := product.Clone()
+			renamed.SKU = fmt.Sprintf("RENAMED-%d", i)
+			assert.NoError(t, repo.Update(ctx, renamed))
+		}(i)
+	}
+	wg.Wait()
+
+	products, _, err := repo.List(ctx, models.ProductFilter{}, 1, 100)
+	assert.NoError(t, err)
+	skus := make(map[string]bool)
+	for _, product := range products {
+		assert.False(t, skus[product.SKU], "SKU %s is taken twice", product.SKU)
+		skus[product.SKU] = true
+		found, err := repo.GetBySKU(ctx, product.SKU)
+		assert.NoError(t, err)
+		assert.Equal(t, product.ID, found.ID)
+	}
+	for i := 0; i < 8; i++ {
+		_, err := repo.GetBySKU(ctx, fmt.Sprintf("TEST-%d", i))
+		assert.ErrorIs(t, err, models.ErrProductNotFound)
+	}
+}
+
 func TestConcurrentAccess(t *testing.T) {
 	repo := NewProductRepository()
 	product := createTestProduct()
 
 	// Create product
-	err := repo.Create(product)
+	err := repo.Create(context.Background(), product)
 	assert.NoError(t, err)
 
 	// Concurrent reads
 	done := make(chan bool)
 	for i := 0; i < 10; i++ {
 		go func() {
-			_, err := repo.GetByID(product.ID)
+			_, err := repo.GetByID(context.Background(), product.ID)
 			assert.NoError(t, err)
 			done <- true
 		}()
@@ -239,14 +324,14 @@ func TestConcurrentAccess(t *testing.T) {
 			p := createTestProduct()
 			p.ID = product.ID
 			p.Version = version
-			repo.Update(p)
+			repo.Update(context.Background(), p)
 		}(int64(i + 2))
 	}
 
 	wg.Wait()
 
 	// Verify final state
-	final, err := repo.GetByID(product.ID)
+	final, err := repo.GetByID(context.Background(), product.ID)
 	assert.NoError(t, err)
 	assert.NotNil(t, final)
 }
@@ -271,7 +356,7 @@ func TestEventVersionFiltering(t *testing.T) {
 			},
 			Timestamp: time.Now(),
 		}
-		err := repo.StoreEvent(event)
+		err := repo.StoreEvent(context.Background(), event)
 		assert.NoError(t, err)
 	}
 
@@ -287,7 +372,7 @@ func TestEventVersionFiltering(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		events, err := repo.GetEventsByProductID(product.ID, tc.fromVersion)
+		events, err := repo.GetEventsByProductID(context.Background(), product.ID, tc.fromVersion)
 		assert.NoError(t, err)
 		assert.Len(t, events, tc.expected)
 
@@ -303,19 +388,256 @@ func TestList(t *testing.T) {
 	// Skapa några testprodukter
 	products := createTestProducts(5)
 	for _, p := range products {
-		err := repo.Create(p)
+		err := repo.Create(context.Background(), p)
 		assert.NoError(t, err)
 	}
 
 	// Testa paginering
-	listed, total, err := repo.List(1, 2)
+	listed, total, err := repo.List(context.Background(), models.ProductFilter{}, 1, 2)
 	assert.NoError(t, err)
 	assert.Len(t, listed, 2)
 	assert.Equal(t, 5, total)
 
 	// Testa andra sidan
-	listed, total, err = repo.List(2, 2)
+	listed, total, err = repo.List(context.Background(), models.ProductFilter{}, 2, 2)
 	assert.NoError(t, err)
 	assert.Len(t, listed, 2)
 	assert.Equal(t, 5, total)
 }
+
+func TestListFilter(t *testing.T) {
+	repo := NewShardedProductRepository(4)
+	for _, p := range createTestProducts(5) {
+		assert.NoError(t, repo.Create(context.Background(), p))
+	}
+
+	// Prices go from 100 to 140 SEK in steps of 10
+	minPrice, maxPrice := 115.0, 135.0
+	listed, total, err := repo.List(context.Background(), models.ProductFilter{Currency: "SEK", MinPrice: &minPrice, MaxPrice: &maxPrice}, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Len(t, listed, 1)
+
+	listed, total, err = repo.List(context.Background(), models.ProductFilter{SKU: " TEST-3 "}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "test_prod_3", listed[0].ID)
+
+	_, total, err = repo.List(context.Background(), models.ProductFilter{Market: "NO"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+}
+
+func TestListAfter(t *testing.T) {
+	repo := NewShardedProductRepository(4)
+	base := time.Now()
+	products := createTestProducts(5)
+	for i, p := range products {
+		p.CreatedAt = base.Add(time.Duration(i) * time.Second)
+		assert.NoError(t, repo.Create(context.Background(), p))
+	}
+
+	first, next, err := repo.ListAfter(context.Background(), models.ProductFilter{}, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "test_prod_5", first[0].ID)
+	assert.Equal(t, "test_prod_4", first[1].ID)
+	assert.NotNil(t, next)
+
+	// A product created between pages does not shift the next page
+	newer := createTestProduct()
+	newer.ID = "test_prod_new"
+	newer.CreatedAt = base.Add(time.Minute)
+	assert.NoError(t, repo.Create(context.Background(), newer))
+
+	second, next, err := repo.ListAfter(context.Background(), models.ProductFilter{}, next, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "test_prod_3", second[0].ID)
+	assert.Equal(t, "test_prod_2", second[1].ID)
+
+	last, next, err := repo.ListAfter(context.Background(), models.ProductFilter{}, next, 2)
+	assert.NoError(t, err)
+	assert.Len(t, last, 1)
+	assert.Nil(t, next)
+}
+
+func TestShardedRepositorySpreadsProducts(t *testing.T) {
+	repo := NewShardedProductRepository(8)
+	products := createTestProducts(100)
+	for _, p := range products {
+		assert.NoError(t, repo.Create(context.Background(), p))
+	}
+
+	used := 0
+	for _, shard := range repo.shards {
+		if len(shard.products) > 0 {
+			used++
+		}
+	}
+	assert.Greater(t, used, 1)
+
+	// Listing still sees every shard
+	listed, total, err := repo.List(context.Background(), models.ProductFilter{}, 1, 200)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, total)
+	assert.Len(t, listed, 100)
+
+	// A single shard behaves like the unsharded repository
+	single := NewShardedProductRepository(0)
+	assert.Len(t, single.shards, 1)
+	assert.NoError(t, single.Create(context.Background(), products[0]))
+	_, err = single.GetByID(context.Background(), products[0].ID)
+	assert.NoError(t, err)
+}
+
+func TestGetEventsUntilMergesStripes(t *testing.T) {
+	repo := NewShardedProductRepository(4)
+	start := time.Now()
+
+	for i := 0; i < 20; i++ {
+		product := createTestProducts(1)[0]
+		product.ID = fmt.Sprintf("prod_%d", i)
+		assert.NoError(t, repo.StoreEvent(context.Background(), &models.Event{
+			ID:        fmt.Sprintf("evt_%d", i),
+			Type:      models.EventProductCreated,
+			EntityID:  product.ID,
+			Version:   1,
+			Sequence:  int64(i + 1),
+			Data:      &models.ProductEvent{ProductID: product.ID, Action: "created", Product: product},
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+		}))
+	}
+
+	events, err := repo.GetEventsUntil(context.Background(), start.Add(9*time.Second))
+	assert.NoError(t, err)
+	assert.Len(t, events, 10)
+	for i, event := range events {
+		assert.Equal(t, fmt.Sprintf("evt_%d", i), event.ID)
+	}
+}
+
+func TestAdjustStockConcurrentReservations(t *testing.T) {
+	repo := NewProductRepository()
+	product := createTestProduct()
+	product.Variants = []models.Variant{{ID: "v1", SKU: "TEST-123-S", Stock: []models.Stock{{LocationID: "wh1", Quantity: 10}}}}
+	assert.NoError(t, repo.Create(context.Background(), product))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := repo.AdjustStock(context.Background(), product.ID, []models.StockAdjustment{{VariantID: "v1", LocationID: "wh1", Delta: -1}})
+			if err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+				return
+			}
+			assert.ErrorIs(t, err, models.ErrInsufficientStock)
+		}()
+	}
+	wg.Wait()
+
+	stored, err := repo.GetByID(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, succeeded)
+	assert.Equal(t, 0, stored.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, int64(11), stored.Version)
+}
+
+func TestAdjustStockLeavesPreviousVersionUntouched(t *testing.T) {
+	repo := NewProductRepository()
+	product := createTestProduct()
+	product.Variants = []models.Variant{{ID: "v1", SKU: "TEST-123-S", Stock: []models.Stock{{LocationID: "wh1", Quantity: 2}}}}
+	assert.NoError(t, repo.Create(context.Background(), product))
+
+	previous, updated, err := repo.AdjustStock(context.Background(), product.ID, []models.StockAdjustment{{VariantID: "v1", LocationID: "wh1", Delta: -2}})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, previous.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, 0, updated.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, previous.Version+1, updated.Version)
+	assert.Equal(t, updated.CalculateHash(), updated.LastHash)
+
+	_, _, err = repo.AdjustStock(context.Background(), "missing", nil)
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func benchmarkParallelWrites(b *testing.B, shardCount int) {
+	repo := NewShardedProductRepository(shardCount)
+	products := createTestProducts(1000)
+	for _, p := range products {
+		repo.Create(context.Background(), p)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			p := products[i%len(products)]
+			repo.Update(context.Background(), p)
+			repo.GetByID(context.Background(), p.ID)
+			i++
+		}
+	})
+}
+
+func BenchmarkParallelWritesSingleShard(b *testing.B) {
+	benchmarkParallelWrites(b, 1)
+}
+
+func BenchmarkParallelWritesSharded(b *testing.B) {
+	benchmarkParallelWrites(b, DefaultShardCount)
+}
+
+// storeVersions stores an update event for every version of a product up to the given one
+func storeVersions(t *testing.T, repo *ProductRepository, id string, versions int64) {
+	for v := int64(1); v <= versions; v++ {
+		product := createTestProduct()
+		product.ID = id
+		product.Version = v
+		assert.NoError(t, repo.StoreEvent(context.Background(), &models.Event{
+			ID:        fmt.Sprintf("evt_%s_%d", id, v),
+			Type:      models.EventProductUpdated,
+			EntityID:  id,
+			Version:   v,
+			Data:      &models.ProductEvent{ProductID: id, Action: "updated", Product: product, Version: v},
+			Timestamp: time.Now(),
+		}))
+	}
+}
+
+func TestCompactEvents(t *testing.T) {
+	repo := NewProductRepositoryWithOptions(Options{ShardCount: 4, SnapshotInterval: 5})
+	storeVersions(t, repo, "prod_1", 12)
+	storeVersions(t, repo, "prod_2", 3)
+
+	// prod_1 keeps its events from the snapshot at version 10
+	assert.Equal(t, 9, repo.CompactEvents())
+	events, err := repo.GetEventsByProductID(context.Background(), "prod_1", 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	assert.Equal(t, int64(10), events[0].Version)
+	events, err = repo.GetEventsByProductID(context.Background(), "prod_2", 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+
+	// Without snapshots nothing is compacted
+	repo = NewProductRepositoryWithOptions(Options{SnapshotInterval: -1})
+	storeVersions(t, repo, "prod_1", 12)
+	assert.Zero(t, repo.CompactEvents())
+}
+
+func TestStartEventCompaction(t *testing.T) {
+	repo := NewProductRepositoryWithOptions(Options{SnapshotInterval: 2})
+	storeVersions(t, repo, "prod_1", 4)
+
+	stop := repo.StartEventCompaction(time.Millisecond)
+	assert.Eventually(t, func() bool {
+		events, _ := repo.GetEventsByProductID(context.Background(), "prod_1", 1)
+		return len(events) == 1
+	}, time.Second, time.Millisecond)
+	stop()
+	stop()
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// WithTx runs fn in a transaction. Its writes are applied at once under the
+// locks of the SKU stripes and shards they touch. A transaction whose
+// products were changed by another write since it first wrote them fails
+// with models.ErrVersionConflict and applies nothing.
+func (r *ProductRepository) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	return repositories.RunStagedTx(ctx, r, fn, r.commit)
+}
+
+// commit applies the staged writes and then stores the staged events and
+// queues them in the outbox
+func (r *ProductRepository) commit(tx *repositories.StagedTx) error {
+	// The stored SKUs are the base ones, or the commit fails on the conflict
+	unlock := r.lockSKUs(tx.SKUs()...)
+	defer unlock()
+
+	// Lock the shards of the written products and of the current owners of
+	// their SKUs, which the index under the stripe locks names, in index
+	// order. Other writes hold one shard at a time, and other commits take
+	// their shards in the same order.
+	owners := make(map[string]string)
+	locked := make(map[int]bool)
+	indexes := make([]int, 0, 2*len(tx.Writes()))
+	lock := func(id string) {
+		if index := r.shardIndex(id); !locked[index] {
+			locked[index] = true
+			indexes = append(indexes, index)
+		}
+	}
+	for id, product := range tx.Writes() {
+		lock(id)
+		if product == nil || product.SKU == "" {
+			continue
+		}
+		if owner, exists := r.stripeFor(product.SKU).skus[product.SKU]; exists {
+			owners[product.SKU] = owner
+			lock(owner)
+		}
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		r.shards[index].mu.Lock()
+		defer r.shards[index].mu.Unlock()
+	}
+
+	owner := func(sku string) *models.Product {
+		id, exists := owners[sku]
+		if !exists {
+			return nil
+		}
+		return r.shardFor(id).products[id]
+	}
+	for id, product := range tx.Writes() {
+		if r.shardFor(id).products[id] != tx.Base(id) {
+			return models.ErrVersionConflict
+		}
+		if product != nil && tx.SKUTaken(product, owner) {
+			return models.ErrDuplicateSKU
+		}
+	}
+
+	for id, product := range tx.Writes() {
+		shard := r.shardFor(id)
+		if previous, exists := shard.products[id]; exists {
+			r.releaseSKU(previous)
+		}
+		if product == nil {
+			delete(shard.products, id)
+			continue
+		}
+		shard.products[id] = product
+		r.claimSKU(product)
+	}
+	for _, event := range tx.Events() {
+		if err := r.eventStores[r.shardIndex(event.EntityID)].StoreEvent(event); err != nil {
+			return err
+		}
+	}
+	return r.queue(tx.Events()...)
+}
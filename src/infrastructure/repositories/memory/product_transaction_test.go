@@ -0,0 +1,221 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTxCommits(t *testing.T) {
+	ctx := context.Background()
+	repo := NewShardedProductRepository(4)
+	product := createTestProduct()
+
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, &models.Event{ID: "evt_1", EntityID: product.ID, Version: 1}); err != nil {
+			return err
+		}
+		if err := tx.Create(ctx, product); err != nil {
+			return err
+		}
+
+		// The transaction sees its writes, the repository not yet
+		staged, err := tx.GetBySKU(ctx, product.SKU)
+		assert.NoError(t, err)
+		assert.Equal(t, product.ID, staged.ID)
+		products, total, err := tx.List(ctx, models.ProductFilter{}, 1, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, total)
+		assert.Len(t, products, 1)
+		events, err := tx.GetEventsByProductID(ctx, product.ID, 0)
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+		_, err = repo.GetByID(ctx, product.ID)
+		assert.ErrorIs(t, err, models.ErrProductNotFound)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	stored, err := repo.GetBySKU(ctx, product.SKU)
+	assert.NoError(t, err)
+	assert.Equal(t, product.ID, stored.ID)
+	events, err := repo.GetEventsByProductID(ctx, product.ID, 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestWithTxQueuesEventsInOutbox(t *testing.T) {
+	ctx := context.Background()
+	repo := NewShardedProductRepository(4)
+	assert.NoError(t, repo.StoreEvent(ctx, &models.Event{ID: "evt_before", EntityID: "prod_0"}))
+	outbox := repo.Outbox()
+	product := createTestProduct()
+
+	failed := errors.New("failed")
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		assert.NoError(t, tx.StoreEvent(ctx, &models.Event{ID: "evt_failed", EntityID: product.ID, Version: 1}))
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	err = repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, &models.Event{ID: "evt_1", EntityID: product.ID, Version: 1}); err != nil {
+			return err
+		}
+		return tx.Create(ctx, product)
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, repo.StoreEvent(ctx, &models.Event{ID: "evt_2", EntityID: product.ID, Version: 2}))
+
+	// Only the committed events stored after the outbox was enabled are pending
+	pending, err := outbox.Pending(0)
+	assert.NoError(t, err)
+	if assert.Len(t, pending, 2) {
+		assert.Equal(t, "evt_1", pending[0].Event.ID)
+		assert.Equal(t, "evt_2", pending[1].Event.ID)
+	}
+	assert.Same(t, outbox, repo.Outbox())
+}
+
+func TestWithTxRollsBack(t *testing.T) {
+	ctx := context.Background()
+	repo := NewShardedProductRepository(4)
+	product := createTestProduct()
+	assert.NoError(t, repo.Create(ctx, product))
+
+	failed := errors.New("failed")
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		assert.NoError(t, tx.StoreEvent(ctx, &models.Event{ID: "evt_1", EntityID: product.ID, Version: 2}))
+		assert.NoError(t, tx.Delete(ctx, product.ID))
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+
+	_, err = repo.GetByID(ctx, product.ID)
+	assert.NoError(t, err)
+	events, err := repo.GetEventsByProductID(ctx, product.ID, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestWithTxConflicts(t *testing.T) {
+	ctx := context.Background()
+	repo := NewShardedProductRepository(4)
+	product := createTestProduct()
+	assert.NoError(t, repo.Create(ctx, product))
+
+	// Another write changes the product before the transaction commits
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if _, _, err := tx.AdjustStock(ctx, product.ID, nil); err != nil {
+			return err
+		}
+		assert.NoError(t, tx.StoreEvent(ctx, &models.Event{ID: "evt_1", EntityID: product.ID, Version: 2}))
+		changed := product.Clone()
+		changed.BaseTitle = "Changed"
+		return repo.Update(ctx, changed)
+	})
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+	stored, err := repo.GetByID(ctx, product.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Changed", stored.BaseTitle)
+	events, err := repo.GetEventsByProductID(ctx, product.ID, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	// Another product takes the SKU before the transaction commits
+	err = repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.Create(ctx, &models.Product{ID: "prod_2", SKU: "SKU-2"}); err != nil {
+			return err
+		}
+		return repo.Create(ctx, &models.Product{ID: "prod_3", SKU: "SKU-2"})
+	})
+	assert.ErrorIs(t, err, models.ErrDuplicateSKU)
+	_, err = repo.GetByID(ctx, "prod_2")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+
+	// Writes in the transaction are checked against each other
+	err = repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		assert.NoError(t, tx.Create(ctx, &models.Product{ID: "prod_4", SKU: "SKU-4"}))
+		return tx.Create(ctx, &models.Product{ID: "prod_5", SKU: "SKU-4"})
+	})
+	assert.ErrorIs(t, err, models.ErrDuplicateSKU)
+}
+
+func TestWithTxJoins(t *testing.T) {
+	ctx := context.Background()
+	repo := NewShardedProductRepository(4)
+
+	failed := errors.New("failed")
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		assert.NoError(t, tx.WithTx(ctx, func(inner repositories.ProductRepository) error {
+			return inner.Create(ctx, createTestProduct())
+		}))
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	products, total, err := repo.List(ctx, models.ProductFilter{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, products)
+}
+
+// idInShard returns the first ID with the prefix that the repository puts in the shard
+func idInShard(repo *ProductRepository, shard int, prefix string) string {
+	for i := 0; ; i++ {
+		if id := fmt.Sprintf("%s_%d", prefix, i); repo.shardIndex(id) == shard {
+			return id
+		}
+	}
+}
+
+func TestWithTxCrossingCommits(t *testing.T) {
+	// The commits only cross when they run in parallel
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	ctx := context.Background()
+	repo := NewShardedProductRepository(2)
+
+	// Each transaction writes a product in its shard and commits after a
+	// product in the other shard took the SKU, so the commits look owners up
+	// in each other's shards
+	commit := func(worker, round int) error {
+		shard := worker % 2
+		id := idInShard(repo, shard, fmt.Sprintf("written_%d_%d", worker, round))
+		owner := idInShard(repo, 1-shard, fmt.Sprintf("owner_%d_%d", worker, round))
+		sku := fmt.Sprintf("SKU-%s", id)
+		return repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+			if err := tx.Create(ctx, &models.Product{ID: id, SKU: sku}); err != nil {
+				return err
+			}
+			return repo.Create(ctx, &models.Product{ID: owner, SKU: sku})
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		var wg sync.WaitGroup
+		for worker := 0; worker < 8; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for round := 0; round < 2000; round++ {
+					assert.ErrorIs(t, commit(worker, round), models.ErrDuplicateSKU)
+				}
+			}(worker)
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("crossing commits deadlocked")
+	}
+}
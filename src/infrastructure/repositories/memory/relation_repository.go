@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// relationKey identifies a relation among the relations of its product
+type relationKey struct {
+	relatedID    string
+	relationType models.RelationType
+}
+
+// RelationRepository implements an in-memory product relation repository
+type RelationRepository struct {
+	// relations holds the relations from each product
+	relations map[string]map[relationKey]*models.ProductRelation
+	mu        sync.RWMutex
+}
+
+// NewRelationRepository creates a new in-memory relation repository
+func NewRelationRepository() repositories.RelationRepository {
+	return &RelationRepository{
+		relations: make(map[string]map[relationKey]*models.ProductRelation),
+	}
+}
+
+// Save creates or replaces a relation
+func (r *RelationRepository) Save(relation *models.ProductRelation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	from, exists := r.relations[relation.ProductID]
+	if !exists {
+		from = make(map[relationKey]*models.ProductRelation)
+		r.relations[relation.ProductID] = from
+	}
+	copied := *relation
+	from[relationKey{relation.RelatedID, relation.Type}] = &copied
+	return nil
+}
+
+// Delete removes a relation
+func (r *RelationRepository) Delete(productID, relatedID string, relationType models.RelationType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := relationKey{relatedID, relationType}
+	if _, exists := r.relations[productID][key]; !exists {
+		return models.ErrRelationNotFound
+	}
+	delete(r.relations[productID], key)
+	if len(r.relations[productID]) == 0 {
+		delete(r.relations, productID)
+	}
+	return nil
+}
+
+// ListByProduct returns the relations from a product, ordered by type, position and related ID
+func (r *RelationRepository) ListByProduct(productID string) ([]*models.ProductRelation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	relations := make([]*models.ProductRelation, 0, len(r.relations[productID]))
+	for _, relation := range r.relations[productID] {
+		copied := *relation
+		relations = append(relations, &copied)
+	}
+	sort.Slice(relations, func(i, j int) bool {
+		a, b := relations[i], relations[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Position != b.Position {
+			return a.Position < b.Position
+		}
+		return a.RelatedID < b.RelatedID
+	})
+	return relations, nil
+}
+
+// DeleteByProduct removes every relation from and to a product
+func (r *RelationRepository) DeleteByProduct(productID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	removed := len(r.relations[productID])
+	delete(r.relations, productID)
+	for id, from := range r.relations {
+		for key := range from {
+			if key.relatedID == productID {
+				delete(from, key)
+				removed++
+			}
+		}
+		if len(from) == 0 {
+			delete(r.relations, id)
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelationSaveListAndDelete(t *testing.T) {
+	repo := NewRelationRepository()
+
+	assert.NoError(t, repo.Save(&models.ProductRelation{ProductID: "shirt", RelatedID: "tie", Type: models.RelationCrossSell, Position: 2}))
+	assert.NoError(t, repo.Save(&models.ProductRelation{ProductID: "shirt", RelatedID: "belt", Type: models.RelationCrossSell, Position: 1}))
+	assert.NoError(t, repo.Save(&models.ProductRelation{ProductID: "shirt", RelatedID: "tie", Type: models.RelationBundle}))
+	// Saving the same link replaces it
+	assert.NoError(t, repo.Save(&models.ProductRelation{ProductID: "shirt", RelatedID: "tie", Type: models.RelationBundle, Position: 5}))
+
+	relations, err := repo.ListByProduct("shirt")
+	assert.NoError(t, err)
+	if assert.Len(t, relations, 3) {
+		assert.Equal(t, models.RelationBundle, relations[0].Type)
+		assert.Equal(t, 5, relations[0].Position)
+		assert.Equal(t, "belt", relations[1].RelatedID)
+		assert.Equal(t, "tie", relations[2].RelatedID)
+	}
+
+	assert.NoError(t, repo.Delete("shirt", "tie", models.RelationBundle))
+	assert.ErrorIs(t, repo.Delete("shirt", "tie", models.RelationBundle), models.ErrRelationNotFound)
+
+	relations, err = repo.ListByProduct("unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, relations)
+}
+
+func TestRelationDeleteByProduct(t *testing.T) {
+	repo := NewRelationRepository()
+	assert.NoError(t, repo.Save(&models.ProductRelation{ProductID: "shirt", RelatedID: "tie", Type: models.RelationCrossSell}))
+	assert.NoError(t, repo.Save(&models.ProductRelation{ProductID: "shirt", RelatedID: "belt", Type: models.RelationCrossSell}))
+	assert.NoError(t, repo.Save(&models.ProductRelation{ProductID: "tie", RelatedID: "shirt", Type: models.RelationRelated}))
+	assert.NoError(t, repo.Save(&models.ProductRelation{ProductID: "tie", RelatedID: "belt", Type: models.RelationRelated}))
+
+	removed, err := repo.DeleteByProduct("shirt")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, removed)
+
+	relations, _ := repo.ListByProduct("tie")
+	if assert.Len(t, relations, 1) {
+		assert.Equal(t, "belt", relations[0].RelatedID)
+	}
+	relations, _ = repo.ListByProduct("shirt")
+	assert.Empty(t, relations)
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// maxRunbookRuns caps the audit trail kept in memory
+const maxRunbookRuns = 1000
+
+// RunbookRunRepository implements an in-memory audit trail of runbook actions
+type RunbookRunRepository struct {
+	runs []*models.RunbookRun // oldest first
+	mu   sync.RWMutex
+}
+
+// NewRunbookRunRepository creates a new in-memory runbook run repository
+func NewRunbookRunRepository() repositories.RunbookRunRepository {
+	return &RunbookRunRepository{
+		runs: make([]*models.RunbookRun, 0),
+	}
+}
+
+// Save records a run, dropping the oldest once the cap is reached
+func (r *RunbookRunRepository) Save(run *models.RunbookRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *run
+	r.runs = append(r.runs, &copied)
+	if len(r.runs) > maxRunbookRuns {
+		r.runs = r.runs[len(r.runs)-maxRunbookRuns:]
+	}
+	return nil
+}
+
+// List returns the latest runs, newest first
+func (r *RunbookRunRepository) List(limit int) ([]*models.RunbookRun, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if limit <= 0 || limit > len(r.runs) {
+		limit = len(r.runs)
+	}
+	runs := make([]*models.RunbookRun, 0, limit)
+	for i := len(r.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		copied := *r.runs[i]
+		runs = append(runs, &copied)
+	}
+	return runs, nil
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunbookRunListNewestFirst(t *testing.T) {
+	repo := NewRunbookRunRepository()
+	for _, id := range []string{"r1", "r2", "r3"} {
+		assert.NoError(t, repo.Save(&models.RunbookRun{ID: id, Action: models.RunbookVerifyEventChain}))
+	}
+
+	runs, err := repo.List(2)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	assert.Equal(t, "r3", runs[0].ID)
+	assert.Equal(t, "r2", runs[1].ID)
+
+	all, _ := repo.List(0)
+	assert.Len(t, all, 3)
+
+	// Changing a returned run must not change the audit trail
+	runs[0].Actor = "changed"
+	again, _ := repo.List(1)
+	assert.Empty(t, again[0].Actor)
+}
+
+func TestRunbookRunCap(t *testing.T) {
+	repo := NewRunbookRunRepository()
+	for i := 0; i < maxRunbookRuns+5; i++ {
+		repo.Save(&models.RunbookRun{ID: fmt.Sprintf("r%d", i)})
+	}
+
+	runs, _ := repo.List(0)
+	assert.Len(t, runs, maxRunbookRuns)
+	assert.Equal(t, fmt.Sprintf("r%d", maxRunbookRuns+4), runs[0].ID)
+	assert.Equal(t, "r5", runs[len(runs)-1].ID)
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// SyncStatusRepository implements an in-memory sync status repository
+type SyncStatusRepository struct {
+	statuses map[string]map[string]*models.SyncStatus // product ID -> target -> status
+	mu       sync.RWMutex
+}
+
+// NewSyncStatusRepository creates a new in-memory sync status repository
+func NewSyncStatusRepository() repositories.SyncStatusRepository {
+	return &SyncStatusRepository{
+		statuses: make(map[string]map[string]*models.SyncStatus),
+	}
+}
+
+// Get returns the status of a product for a target
+func (r *SyncStatusRepository) Get(productID, target string) (*models.SyncStatus, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	status, exists := r.statuses[productID][target]
+	if !exists {
+		return nil, models.ErrSyncStatusNotFound
+	}
+	return copySyncStatus(status), nil
+}
+
+// Save creates or replaces a status
+func (r *SyncStatusRepository) Save(status *models.SyncStatus) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	targets, exists := r.statuses[status.ProductID]
+	if !exists {
+		targets = make(map[string]*models.SyncStatus)
+		r.statuses[status.ProductID] = targets
+	}
+	targets[status.Target] = copySyncStatus(status)
+	return nil
+}
+
+// ListByProduct returns the statuses of a product, ordered by target
+func (r *SyncStatusRepository) ListByProduct(productID string) ([]*models.SyncStatus, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]*models.SyncStatus, 0, len(r.statuses[productID]))
+	for _, status := range r.statuses[productID] {
+		statuses = append(statuses, copySyncStatus(status))
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Target < statuses[j].Target
+	})
+	return statuses, nil
+}
+
+// ListByTarget returns the most recently updated statuses for a target, newest first
+func (r *SyncStatusRepository) ListByTarget(target string, limit int) ([]*models.SyncStatus, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]*models.SyncStatus, 0)
+	for _, targets := range r.statuses {
+		if status, exists := targets[target]; exists {
+			statuses = append(statuses, copySyncStatus(status))
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].UpdatedAt.After(statuses[j].UpdatedAt)
+	})
+
+	if limit > 0 && len(statuses) > limit {
+		statuses = statuses[:limit]
+	}
+	return statuses, nil
+}
+
+// copySyncStatus copies a status so callers never share its errors or timestamps with the store
+func copySyncStatus(status *models.SyncStatus) *models.SyncStatus {
+	copied := *status
+	if status.Errors != nil {
+		copied.Errors = append([]models.DeliveryError(nil), status.Errors...)
+	}
+	if status.SyncedAt != nil {
+		syncedAt := *status.SyncedAt
+		copied.SyncedAt = &syncedAt
+	}
+	return &copied
+}
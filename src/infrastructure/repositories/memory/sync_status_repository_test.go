@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncStatusSaveAndGet(t *testing.T) {
+	repo := NewSyncStatusRepository()
+
+	_, err := repo.Get("prod_1", "marketplace:amazon")
+	assert.ErrorIs(t, err, models.ErrSyncStatusNotFound)
+
+	syncedAt := time.Now()
+	assert.NoError(t, repo.Save(&models.SyncStatus{
+		ProductID:     "prod_1",
+		Target:        "marketplace:amazon",
+		State:         models.SyncStateSynced,
+		Version:       3,
+		SyncedVersion: 3,
+		SyncedAt:      &syncedAt,
+	}))
+
+	status, err := repo.Get("prod_1", "marketplace:amazon")
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStateSynced, status.State)
+	assert.Equal(t, int64(3), status.SyncedVersion)
+
+	// Changing the returned copy must not change the stored status
+	stored, _ := repo.Get("prod_1", "marketplace:amazon")
+	assert.True(t, stored.SyncedAt.Equal(syncedAt))
+}
+
+func TestSyncStatusListByProduct(t *testing.T) {
+	repo := NewSyncStatusRepository()
+	repo.Save(&models.SyncStatus{ProductID: "prod_1", Target: "marketplace:zalando"})
+	repo.Save(&models.SyncStatus{ProductID: "prod_1", Target: "marketplace:amazon"})
+	repo.Save(&models.SyncStatus{ProductID: "prod_2", Target: "marketplace:amazon"})
+
+	statuses, err := repo.ListByProduct("prod_1")
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "marketplace:amazon", statuses[0].Target)
+	assert.Equal(t, "marketplace:zalando", statuses[1].Target)
+
+	statuses, err = repo.ListByProduct("prod_3")
+	assert.NoError(t, err)
+	assert.Empty(t, statuses)
+}
+
+func TestSyncStatusListByTarget(t *testing.T) {
+	repo := NewSyncStatusRepository()
+	now := time.Now()
+	repo.Save(&models.SyncStatus{ProductID: "prod_1", Target: "marketplace:amazon", UpdatedAt: now.Add(-time.Minute)})
+	repo.Save(&models.SyncStatus{ProductID: "prod_2", Target: "marketplace:amazon", UpdatedAt: now})
+	repo.Save(&models.SyncStatus{ProductID: "prod_3", Target: "marketplace:zalando", UpdatedAt: now})
+
+	statuses, err := repo.ListByTarget("marketplace:amazon", 0)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "prod_2", statuses[0].ProductID)
+
+	statuses, err = repo.ListByTarget("marketplace:amazon", 1)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 1)
+}
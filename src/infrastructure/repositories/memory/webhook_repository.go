@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// maxWebhookDeliveries caps the delivery log kept per endpoint
+const maxWebhookDeliveries = 100
+
+// WebhookRepository implements an in-memory webhook repository
+type WebhookRepository struct {
+	endpoints  map[string]*models.WebhookEndpoint
+	deliveries map[string][]*models.WebhookDelivery // endpoint ID -> attempts, oldest first
+	mu         sync.RWMutex
+}
+
+// NewWebhookRepository creates a new in-memory webhook repository
+func NewWebhookRepository() repositories.WebhookRepository {
+	return &WebhookRepository{
+		endpoints:  make(map[string]*models.WebhookEndpoint),
+		deliveries: make(map[string][]*models.WebhookDelivery),
+	}
+}
+
+// copyEndpoint copies an endpoint so callers cannot modify stored data
+func copyEndpoint(endpoint *models.WebhookEndpoint) *models.WebhookEndpoint {
+	copied := *endpoint
+	copied.EventTypes = append([]models.EventType(nil), endpoint.EventTypes...)
+	if endpoint.Auth != nil {
+		auth := *endpoint.Auth
+		auth.Scopes = append([]string(nil), endpoint.Auth.Scopes...)
+		copied.Auth = &auth
+	}
+	return &copied
+}
+
+// Save creates or replaces an endpoint
+func (r *WebhookRepository) Save(endpoint *models.WebhookEndpoint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.endpoints[endpoint.ID] = copyEndpoint(endpoint)
+	return nil
+}
+
+// Get returns an endpoint
+func (r *WebhookRepository) Get(id string) (*models.WebhookEndpoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	endpoint, exists := r.endpoints[id]
+	if !exists {
+		return nil, models.ErrWebhookNotFound
+	}
+	return copyEndpoint(endpoint), nil
+}
+
+// List returns every endpoint, oldest first
+func (r *WebhookRepository) List() ([]*models.WebhookEndpoint, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	endpoints := make([]*models.WebhookEndpoint, 0, len(r.endpoints))
+	for _, endpoint := range r.endpoints {
+		endpoints = append(endpoints, copyEndpoint(endpoint))
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if !endpoints[i].CreatedAt.Equal(endpoints[j].CreatedAt) {
+			return endpoints[i].CreatedAt.Before(endpoints[j].CreatedAt)
+		}
+		return endpoints[i].ID < endpoints[j].ID
+	})
+	return endpoints, nil
+}
+
+// Delete removes an endpoint and its delivery log
+func (r *WebhookRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.endpoints[id]; !exists {
+		return models.ErrWebhookNotFound
+	}
+	delete(r.endpoints, id)
+	delete(r.deliveries, id)
+	return nil
+}
+
+// AddDelivery appends an attempt, dropping the oldest beyond maxWebhookDeliveries
+func (r *WebhookRepository) AddDelivery(delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *delivery
+	log := append(r.deliveries[delivery.EndpointID], &copied)
+	if len(log) > maxWebhookDeliveries {
+		log = append([]*models.WebhookDelivery(nil), log[len(log)-maxWebhookDeliveries:]...)
+	}
+	r.deliveries[delivery.EndpointID] = log
+	return nil
+}
+
+// ListDeliveries returns the latest attempts of an endpoint, newest first
+func (r *WebhookRepository) ListDeliveries(endpointID string, limit int) ([]*models.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	log := r.deliveries[endpointID]
+	deliveries := make([]*models.WebhookDelivery, 0, len(log))
+	for i := len(log) - 1; i >= 0; i-- {
+		if limit > 0 && len(deliveries) >= limit {
+			break
+		}
+		copied := *log[i]
+		deliveries = append(deliveries, &copied)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookSaveReturnsCopies(t *testing.T) {
+	repo := NewWebhookRepository()
+	now := time.Now()
+	assert.NoError(t, repo.Save(&models.WebhookEndpoint{ID: "wh_2", CreatedAt: now.Add(time.Second), EventTypes: []models.EventType{models.EventProductUpdated}}))
+	assert.NoError(t, repo.Save(&models.WebhookEndpoint{ID: "wh_1", CreatedAt: now}))
+
+	endpoint, err := repo.Get("wh_2")
+	assert.NoError(t, err)
+	endpoint.EventTypes[0] = models.EventProductDeleted
+	endpoint, _ = repo.Get("wh_2")
+	assert.Equal(t, models.EventProductUpdated, endpoint.EventTypes[0])
+
+	endpoints, err := repo.List()
+	assert.NoError(t, err)
+	assert.Equal(t, "wh_1", endpoints[0].ID)
+	assert.Equal(t, "wh_2", endpoints[1].ID)
+
+	_, err = repo.Get("wh_missing")
+	assert.ErrorIs(t, err, models.ErrWebhookNotFound)
+	assert.ErrorIs(t, repo.Delete("wh_missing"), models.ErrWebhookNotFound)
+}
+
+func TestWebhookDeliveryLogIsCapped(t *testing.T) {
+	repo := NewWebhookRepository()
+	assert.NoError(t, repo.Save(&models.WebhookEndpoint{ID: "wh_1"}))
+	for i := 0; i < maxWebhookDeliveries+5; i++ {
+		assert.NoError(t, repo.AddDelivery(&models.WebhookDelivery{ID: fmt.Sprintf("d%d", i), EndpointID: "wh_1"}))
+	}
+
+	deliveries, err := repo.ListDeliveries("wh_1", 0)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, maxWebhookDeliveries)
+	assert.Equal(t, fmt.Sprintf("d%d", maxWebhookDeliveries+4), deliveries[0].ID)
+
+	deliveries, _ = repo.ListDeliveries("wh_1", 2)
+	assert.Len(t, deliveries, 2)
+
+	assert.NoError(t, repo.Delete("wh_1"))
+	deliveries, _ = repo.ListDeliveries("wh_1", 0)
+	assert.Empty(t, deliveries)
+}
@@ -0,0 +1,159 @@
+// Package postgres stores products and their events in PostgreSQL, so the
+// catalog survives restarts. It uses database/sql and works with any driver
+// registered for Postgres, e.g. github.com/jackc/pgx/v5/stdlib as "pgx".
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// migrationLockID is the advisory lock held while migrating, so instances
+// starting together do not apply the same migration twice
+const migrationLockID = 4751
+
+// migration is a schema change, applied once in version order
+type migration struct {
+	version     int
+	description string
+	statements  []string
+}
+
+// migrations are the schema changes in order. Never edit an applied migration;
+// add a new one instead.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create products",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS products (
+				id         TEXT PRIMARY KEY,
+				sku        TEXT NOT NULL,
+				version    BIGINT NOT NULL,
+				data       JSONB NOT NULL,
+				created_at TIMESTAMPTZ NOT NULL,
+				updated_at TIMESTAMPTZ NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS products_created_at_idx ON products (created_at DESC, id)`,
+		},
+	},
+	{
+		version:     2,
+		description: "create product events",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS product_events (
+				id             TEXT PRIMARY KEY,
+				type           TEXT NOT NULL,
+				entity_id      TEXT NOT NULL,
+				version        BIGINT NOT NULL,
+				sequence       BIGINT NOT NULL,
+				schema_version INTEGER NOT NULL,
+				job_id         TEXT NOT NULL DEFAULT '',
+				data           JSONB NOT NULL,
+				occurred_at    TIMESTAMPTZ NOT NULL,
+				position       BIGSERIAL
+			)`,
+			`CREATE INDEX IF NOT EXISTS product_events_entity_idx ON product_events (entity_id, version)`,
+			`CREATE INDEX IF NOT EXISTS product_events_occurred_at_idx ON product_events (occurred_at, sequence)`,
+		},
+	},
+	{
+		version:     3,
+		description: "unique product skus",
+		statements: []string{
+			// Fails while two products share a SKU; rename one of them first
+			`CREATE UNIQUE INDEX IF NOT EXISTS products_sku_idx ON products (sku) WHERE sku <> ''`,
+		},
+	},
+	{
+		version:     4,
+		description: "unique product event versions",
+		statements: []string{
+			// Fails while a product has two events for a version; the later
+			// one was written over a concurrent update and has to be removed
+			`CREATE UNIQUE INDEX IF NOT EXISTS product_events_entity_version_idx ON product_events (entity_id, version)`,
+			`DROP INDEX IF EXISTS product_events_entity_idx`,
+		},
+	},
+	{
+		version:     5,
+		description: "create event outbox",
+		statements: []string{
+			`CREATE TABLE IF NOT EXISTS event_outbox (
+				event_id     TEXT PRIMARY KEY,
+				event        JSONB NOT NULL,
+				state        TEXT NOT NULL,
+				attempts     INTEGER NOT NULL DEFAULT 0,
+				last_error   TEXT NOT NULL DEFAULT '',
+				next_attempt TIMESTAMPTZ NOT NULL,
+				created_at   TIMESTAMPTZ NOT NULL,
+				delivered_at TIMESTAMPTZ,
+				position     BIGSERIAL
+			)`,
+			`CREATE INDEX IF NOT EXISTS event_outbox_pending_idx ON event_outbox (position) WHERE state = 'pending'`,
+		},
+	},
+}
+
+// Migrate applies the migrations the database does not have yet, each in its
+// own transaction, and returns how many it applied
+func Migrate(ctx context.Context, db *sql.DB) (int, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return 0, fmt.Errorf("failed to lock migrations: %v", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version     INTEGER PRIMARY KEY,
+		description TEXT NOT NULL,
+		applied_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return 0, fmt.Errorf("failed to create schema_migrations: %v", err)
+	}
+
+	var current int
+	if err := conn.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, m := range pending(current) {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return applied, err
+		}
+		for _, statement := range m.statements {
+			if _, err := tx.ExecContext(ctx, statement); err != nil {
+				tx.Rollback()
+				return applied, fmt.Errorf("migration %d (%s) failed: %v", m.version, m.description, err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, description) VALUES ($1, $2)`, m.version, m.description); err != nil {
+			tx.Rollback()
+			return applied, err
+		}
+		if err := tx.Commit(); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// pending returns the migrations above the current schema version
+func pending(current int) []migration {
+	var result []migration
+	for _, m := range migrations {
+		if m.version > current {
+			result = append(result, m)
+		}
+	}
+	return result
+}
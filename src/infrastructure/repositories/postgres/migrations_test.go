@@ -0,0 +1,21 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationsAreOrdered(t *testing.T) {
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.version, "migrations must be numbered in order without gaps")
+		assert.NotEmpty(t, m.description)
+		assert.NotEmpty(t, m.statements)
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	assert.Len(t, pending(0), len(migrations))
+	assert.Equal(t, 2, pending(1)[0].version)
+	assert.Empty(t, pending(len(migrations)))
+}
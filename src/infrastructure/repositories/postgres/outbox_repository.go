@@ -0,0 +1,182 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// OutboxRepository implements repositories.OutboxRepository on the
+// event_outbox table created by Migrate. Entries are kept in the order they
+// were added; instances sharing the database share the outbox.
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates an outbox repository on a migrated database
+func NewOutboxRepository(db *sql.DB) repositories.OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// addOutboxEntries queues events as pending, skipping events already in the outbox
+func addOutboxEntries(ctx context.Context, q queryer, now time.Time, events ...*models.Event) error {
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		if _, err := q.ExecContext(ctx, `
+			INSERT INTO event_outbox (event_id, event, state, next_attempt, created_at)
+			VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (event_id) DO NOTHING`,
+			event.ID, data, string(models.OutboxPending), now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add queues events as pending in one transaction, skipping events already in the outbox
+func (r *OutboxRepository) Add(events ...*models.Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := addOutboxEntries(ctx, tx, time.Now(), events...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Pending returns the pending entries, oldest first
+func (r *OutboxRepository) Pending(limit int) ([]*models.OutboxEntry, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT event, state, attempts, last_error, next_attempt, created_at
+		FROM event_outbox WHERE state = $1 ORDER BY position`
+	args := []interface{}{string(models.OutboxPending)}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pending := make([]*models.OutboxEntry, 0)
+	for rows.Next() {
+		var (
+			entry models.OutboxEntry
+			state string
+			data  []byte
+		)
+		if err := rows.Scan(&data, &state, &entry.Attempts, &entry.LastError, &entry.NextAttempt, &entry.CreatedAt); err != nil {
+			return nil, err
+		}
+		entry.State = models.OutboxState(state)
+		if entry.Event, err = models.DecodeEvent(data); err != nil {
+			return nil, fmt.Errorf("invalid outbox entry: %v", err)
+		}
+		pending = append(pending, &entry)
+	}
+	return pending, rows.Err()
+}
+
+// MarkDelivered records that an event was published
+func (r *OutboxRepository) MarkDelivered(eventID string, at time.Time) error {
+	return r.update(`
+		UPDATE event_outbox SET state = $2, attempts = attempts + 1, last_error = '', delivered_at = $3
+		WHERE event_id = $1`,
+		eventID, string(models.OutboxDelivered), at)
+}
+
+// MarkAttemptFailed records a failed attempt and when to retry it
+func (r *OutboxRepository) MarkAttemptFailed(eventID string, reason string, retryAt time.Time) error {
+	if retryAt.IsZero() {
+		return r.update(`
+			UPDATE event_outbox SET state = $2, attempts = attempts + 1, last_error = $3
+			WHERE event_id = $1`,
+			eventID, string(models.OutboxFailed), reason)
+	}
+	return r.update(`
+		UPDATE event_outbox SET attempts = attempts + 1, last_error = $2, next_attempt = $3
+		WHERE event_id = $1`,
+		eventID, reason, retryAt)
+}
+
+// update runs a statement on one entry, or fails with ErrOutboxEntryNotFound
+func (r *OutboxRepository) update(query string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrOutboxEntryNotFound
+	}
+	return nil
+}
+
+// Stats counts the entries by state
+func (r *OutboxRepository) Stats() (models.OutboxStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	var stats models.OutboxStats
+	rows, err := r.db.QueryContext(ctx, `SELECT state, COUNT(*) FROM event_outbox GROUP BY state`)
+	if err != nil {
+		return stats, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			state string
+			count int
+		)
+		if err := rows.Scan(&state, &count); err != nil {
+			return stats, err
+		}
+		switch models.OutboxState(state) {
+		case models.OutboxPending:
+			stats.Pending = count
+		case models.OutboxDelivered:
+			stats.Delivered = count
+		case models.OutboxFailed:
+			stats.Failed = count
+		}
+	}
+	return stats, rows.Err()
+}
+
+// Prune removes the entries delivered before the given time
+func (r *OutboxRepository) Prune(before time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM event_outbox WHERE state = $1 AND delivered_at < $2`,
+		string(models.OutboxDelivered), before)
+	if err != nil {
+		return 0, err
+	}
+	pruned, err := result.RowsAffected()
+	return int(pruned), err
+}
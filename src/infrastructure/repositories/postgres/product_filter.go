@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// filterQuery collects the conditions of a WHERE clause and their arguments
+type filterQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+// whereClause turns a normalized filter into a WHERE clause over the products
+// table with placeholders numbered from $1. Soft-deleted products are excluded
+// unless the filter includes them. Products encode false drafts as no field.
+func whereClause(filter models.ProductFilter) (string, []interface{}) {
+	q := &filterQuery{}
+	if !filter.IncludeDeleted {
+		q.where(`data->>'deleted_at' IS NULL`)
+	}
+	if filter.PublishedOnly {
+		q.where(`data->'draft' IS DISTINCT FROM 'true'::jsonb`)
+	}
+	if filter.SKU != "" {
+		sku := q.arg(filter.SKU)
+		q.where(`(sku = %[1]s OR data->'variants' @> jsonb_build_array(jsonb_build_object('sku', %[1]s::text)))`, sku)
+	}
+	if filter.Market != "" {
+		q.where(`EXISTS (SELECT 1 FROM `+elements("metadata")+` m WHERE upper(m->>'market') = %s)`, q.arg(filter.Market))
+	}
+	if filter.Currency != "" || filter.MinPrice != nil || filter.MaxPrice != nil {
+		price := make([]string, 0, 3)
+		if filter.Currency != "" {
+			price = append(price, fmt.Sprintf(`upper(p->>'currency') = %s`, q.arg(filter.Currency)))
+		}
+		if filter.MinPrice != nil {
+			price = append(price, fmt.Sprintf(`(p->>'amount')::float8 >= %s`, q.arg(*filter.MinPrice)))
+		}
+		if filter.MaxPrice != nil {
+			price = append(price, fmt.Sprintf(`(p->>'amount')::float8 <= %s`, q.arg(*filter.MaxPrice)))
+		}
+		q.where(`EXISTS (SELECT 1 FROM `+elements("prices")+` p WHERE %s)`, strings.Join(price, " AND "))
+	}
+	if filter.Title != "" {
+		title := q.arg("%" + escapeLike(filter.Title) + "%")
+		q.where(`(data->>'base_title' ILIKE %[1]s OR EXISTS (SELECT 1 FROM `+elements("metadata")+` m WHERE m->>'title' ILIKE %[1]s))`, title)
+	}
+	if len(filter.Tags) > 0 {
+		tags, _ := json.Marshal(filter.Tags)
+		q.where(`data->'tags' @> %s::jsonb`, q.arg(string(tags)))
+	}
+	if len(filter.Categories) > 0 {
+		categories := make([]string, len(filter.Categories))
+		for i, id := range filter.Categories {
+			category, _ := json.Marshal([]string{id})
+			categories[i] = fmt.Sprintf(`data->'category_ids' @> %s::jsonb`, q.arg(string(category)))
+		}
+		q.where(`(%s)`, strings.Join(categories, " OR "))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		q.where(`created_at > %s`, q.arg(filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		q.where(`created_at < %s`, q.arg(filter.CreatedBefore))
+	}
+
+	if len(q.conditions) == 0 {
+		return "", nil
+	}
+	return "WHERE " + strings.Join(q.conditions, " AND "), q.args
+}
+
+// arg adds an argument and returns its placeholder
+func (q *filterQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// where adds a condition, formatted with placeholders
+func (q *filterQuery) where(format string, placeholders ...interface{}) {
+	q.conditions = append(q.conditions, fmt.Sprintf(format, placeholders...))
+}
+
+// elements expands a JSON array field of a stored product into rows; products
+// encode empty slices as null, which has no elements
+func elements(field string) string {
+	return fmt.Sprintf(`jsonb_array_elements(CASE WHEN jsonb_typeof(data->'%[1]s') = 'array' THEN data->'%[1]s' ELSE '[]' END)`, field)
+}
+
+// escapeLike escapes the LIKE wildcards in text so it matches literally
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhereClauseZeroFilter(t *testing.T) {
+	where, args := whereClause(models.ProductFilter{})
+	assert.Equal(t, `WHERE data->>'deleted_at' IS NULL`, where)
+	assert.Empty(t, args)
+
+	where, args = whereClause(models.ProductFilter{IncludeDeleted: true})
+	assert.Empty(t, where)
+	assert.Empty(t, args)
+}
+
+func TestWhereClausePublishedOnly(t *testing.T) {
+	where, args := whereClause(models.ProductFilter{PublishedOnly: true})
+	assert.Equal(t, `WHERE data->>'deleted_at' IS NULL AND data->'draft' IS DISTINCT FROM 'true'::jsonb`, where)
+	assert.Empty(t, args)
+}
+
+func TestWhereClauseNumbersPlaceholders(t *testing.T) {
+	minPrice := 100.0
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	where, args := whereClause(models.ProductFilter{
+		SKU:          "SKU-1",
+		Currency:     "SEK",
+		MinPrice:     &minPrice,
+		Title:        "50%_off",
+		Tags:         []string{"sale"},
+		CreatedAfter: after,
+	}.Normalize())
+
+	assert.Equal(t, []interface{}{"SKU-1", "SEK", 100.0, `%50\%\_off%`, `["sale"]`, after}, args)
+	assert.Contains(t, where, `(sku = $1 OR data->'variants' @> jsonb_build_array(jsonb_build_object('sku', $1::text)))`)
+	assert.Contains(t, where, `upper(p->>'currency') = $2 AND (p->>'amount')::float8 >= $3`)
+	assert.Contains(t, where, `data->>'base_title' ILIKE $4 OR`)
+	assert.Contains(t, where, `m->>'title' ILIKE $4`)
+	assert.Contains(t, where, `data->'tags' @> $5::jsonb`)
+	assert.Contains(t, where, `created_at > $6`)
+	assert.NotContains(t, where, "$7")
+}
+
+func TestWhereClauseCategories(t *testing.T) {
+	where, args := whereClause(models.ProductFilter{Categories: []string{"cat_1", "cat_2"}}.Normalize())
+	assert.Equal(t, []interface{}{`["cat_1"]`, `["cat_2"]`}, args)
+	assert.Contains(t, where, `(data->'category_ids' @> $1::jsonb OR data->'category_ids' @> $2::jsonb)`)
+}
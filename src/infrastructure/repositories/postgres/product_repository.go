@@ -0,0 +1,460 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// queryTimeout bounds one repository call within the caller's context, so a
+// stuck query gives up even when the caller set no deadline
+const queryTimeout = 10 * time.Second
+
+// Config configures the connection pool
+type Config struct {
+	Driver          string // Name the driver is registered under, e.g. "pgx"
+	DSN             string // e.g. postgres://ecom:secret@db:5432/ecom?sslmode=require
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// Open connects to the database, sizes the pool and checks the connection
+func Open(config Config) (*sql.DB, error) {
+	db, err := sql.Open(config.Driver, config.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database (is the driver linked in?): %v", config.Driver, err)
+	}
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// ProductRepository implements repositories.ProductRepository on the products
+// and product_events tables created by Migrate. Products are stored as JSON
+// next to the columns they are queried by.
+type ProductRepository struct {
+	db     *sql.DB
+	tx     *sql.Tx // Set on the repository a WithTx callback gets
+	outbox bool    // Whether StoreEvent queues events in event_outbox
+}
+
+// NewProductRepository creates a repository on a migrated database
+func NewProductRepository(db *sql.DB) repositories.ProductRepository {
+	return &ProductRepository{db: db}
+}
+
+// queryer is what the product queries need from a *sql.DB or *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// conn returns the transaction the repository is bound to, or the pool
+func (r *ProductRepository) conn() queryer {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db
+}
+
+// WithTx runs fn in a database transaction, committed when fn returns nil
+// and rolled back otherwise. The transaction is bound to ctx, not to
+// queryTimeout, since it spans several calls; each call is still bounded.
+func (r *ProductRepository) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	return r.transact(ctx, func(tx *ProductRepository) error { return fn(tx) })
+}
+
+// transact runs fn on a repository bound to a transaction, joining the
+// repository's own if it has one
+func (r *ProductRepository) transact(ctx context.Context, fn func(tx *ProductRepository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(&ProductRepository{db: r.db, tx: tx, outbox: r.outbox}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Create stores a product, replacing a stored product with the same ID like the memory repository does
+func (r *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(product)
+	if err != nil {
+		return err
+	}
+	_, err = r.conn().ExecContext(ctx, `
+		INSERT INTO products (id, sku, version, data, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			sku = EXCLUDED.sku, version = EXCLUDED.version, data = EXCLUDED.data,
+			created_at = EXCLUDED.created_at, updated_at = EXCLUDED.updated_at`,
+		product.ID, product.SKU, product.Version, data, product.CreatedAt, product.UpdatedAt)
+	return skuError(err)
+}
+
+// GetByID retrieves a product by its ID
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	return getProduct(ctx, r.conn(), id, "")
+}
+
+// GetBySKU retrieves a product by its SKU
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	var data []byte
+	err := r.conn().QueryRowContext(ctx, `SELECT data FROM products WHERE sku = $1 AND sku <> ''`, sku).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeProduct(data)
+}
+
+// getProduct reads a product, with a locking clause such as FOR UPDATE inside a transaction
+func getProduct(ctx context.Context, q queryer, id, locking string) (*models.Product, error) {
+	var data []byte
+	err := q.QueryRowContext(ctx, `SELECT data FROM products WHERE id = $1 `+locking, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, models.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeProduct(data)
+}
+
+// Update modifies an existing product whose stored version is the one before
+// the product's, and fails with models.ErrVersionConflict when another write,
+// possibly from another instance, changed it since
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+	return updateProduct(ctx, r.conn(), product)
+}
+
+// updateProduct replaces the stored product at the version before the
+// product's, or fails with ErrProductNotFound or ErrVersionConflict
+func updateProduct(ctx context.Context, q queryer, product *models.Product) error {
+	data, err := json.Marshal(product)
+	if err != nil {
+		return err
+	}
+	result, err := q.ExecContext(ctx, `
+		UPDATE products SET sku = $2, version = $3, data = $4, created_at = $5, updated_at = $6
+		WHERE id = $1 AND version = $3 - 1`,
+		product.ID, product.SKU, product.Version, data, product.CreatedAt, product.UpdatedAt)
+	if err != nil {
+		return skuError(err)
+	}
+	if err := requireRow(result); !errors.Is(err, models.ErrProductNotFound) {
+		return err
+	}
+
+	var exists bool
+	if err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)`, product.ID).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: product %s is no longer at version %d", models.ErrVersionConflict, product.ID, product.Version-1)
+	}
+	return models.ErrProductNotFound
+}
+
+// AdjustStock applies stock adjustments in a transaction that holds a row lock
+// on the product, so concurrent adjustments never act on the same quantities
+func (r *ProductRepository) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (current, updated *models.Product, err error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	err = r.transact(ctx, func(tx *ProductRepository) error {
+		if current, err = getProduct(ctx, tx.tx, productID, "FOR UPDATE"); err != nil {
+			return err
+		}
+		if updated, err = current.WithStockAdjustments(adjustments, time.Now()); err != nil {
+			return err
+		}
+		return updateProduct(ctx, tx.tx, updated)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return current, updated, nil
+}
+
+// Delete removes a product from storage; its events are kept
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	result, err := r.conn().ExecContext(ctx, `DELETE FROM products WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(result)
+}
+
+// List returns a page of the products that match the filter, newest first
+func (r *ProductRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	where, args := whereClause(filter.Normalize())
+	var total int
+	if err := r.conn().QueryRowContext(ctx, `SELECT COUNT(*) FROM products `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	if offset < 0 || pageSize <= 0 || offset >= total {
+		return []*models.Product{}, total, nil
+	}
+
+	rows, err := r.conn().QueryContext(ctx, fmt.Sprintf(`
+		SELECT data FROM products %s ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d`,
+		where, len(args)+1, len(args)+2),
+		append(args, pageSize, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	products := make([]*models.Product, 0, pageSize)
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, 0, err
+		}
+		product, err := decodeProduct(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		products = append(products, product)
+	}
+	return products, total, rows.Err()
+}
+
+// ListAfter returns up to limit matching products after the cursor. Cursors
+// carry the stored created_at column rather than the product's own timestamp,
+// which has more precision than the column keeps.
+func (r *ProductRepository) ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) ([]*models.Product, *models.ProductCursor, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	where, args := whereClause(filter.Normalize())
+	if after != nil {
+		args = append(args, after.CreatedAt, after.ID)
+		position := fmt.Sprintf("(created_at < $%[1]d OR (created_at = $%[1]d AND id > $%[2]d))", len(args)-1, len(args))
+		if where == "" {
+			where = "WHERE " + position
+		} else {
+			where += " AND " + position
+		}
+	}
+	query := `SELECT created_at, id, data FROM products ` + where + ` ORDER BY created_at DESC, id`
+	if limit > 0 {
+		// One more row tells whether there is a next page
+		args = append(args, limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	products := make([]*models.Product, 0)
+	var next *models.ProductCursor
+	for rows.Next() {
+		if limit > 0 && len(products) == limit {
+			return products, next, rows.Close()
+		}
+		var (
+			cursor models.ProductCursor
+			data   []byte
+		)
+		if err := rows.Scan(&cursor.CreatedAt, &cursor.ID, &data); err != nil {
+			return nil, nil, err
+		}
+		product, err := decodeProduct(data)
+		if err != nil {
+			return nil, nil, err
+		}
+		products = append(products, product)
+		next = &cursor
+	}
+	return products, nil, rows.Err()
+}
+
+// StoreEvent appends an event to the event log. A product has one event per
+// version, so an event for a stored version fails with ErrVersionConflict.
+// With the outbox enabled, the event is queued in the same transaction.
+func (r *ProductRepository) StoreEvent(ctx context.Context, event *models.Event) error {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	if !r.outbox {
+		return insertEvent(ctx, r.conn(), event, data)
+	}
+	return r.transact(ctx, func(tx *ProductRepository) error {
+		if err := insertEvent(ctx, tx.tx, event, data); err != nil {
+			return err
+		}
+		return addOutboxEntries(ctx, tx.tx, time.Now(), event)
+	})
+}
+
+// insertEvent inserts an event with its encoded data into product_events
+func insertEvent(ctx context.Context, q queryer, event *models.Event, data []byte) error {
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO product_events (id, type, entity_id, version, sequence, schema_version, job_id, data, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		event.ID, string(event.Type), event.EntityID, event.Version, event.Sequence, event.SchemaVersion, event.JobID, data, event.Timestamp)
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: product %s already has an event for version %d", models.ErrVersionConflict, event.EntityID, event.Version)
+	}
+	return err
+}
+
+// Outbox returns the outbox in event_outbox and queues every event stored
+// from then on in it. It must not be called while the repository is written.
+func (r *ProductRepository) Outbox() repositories.OutboxRepository {
+	r.outbox = true
+	return NewOutboxRepository(r.db)
+}
+
+// GetEventsByProductID returns a product's events from a version on, in storage order
+func (r *ProductRepository) GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	return r.queryEvents(ctx, `
+		SELECT id, type, entity_id, version, sequence, schema_version, job_id, data, occurred_at
+		FROM product_events WHERE entity_id = $1 AND version >= $2 ORDER BY position`,
+		productID, fromVersion)
+}
+
+// GetEventsUntil returns all product events recorded at or before the given time in the order they happened
+func (r *ProductRepository) GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error) {
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	return r.queryEvents(ctx, `
+		SELECT id, type, entity_id, version, sequence, schema_version, job_id, data, occurred_at
+		FROM product_events WHERE occurred_at <= $1 ORDER BY occurred_at, sequence`,
+		until)
+}
+
+// queryEvents reads events selected by a query over the event columns
+func (r *ProductRepository) queryEvents(ctx context.Context, query string, args ...interface{}) ([]*models.Event, error) {
+	rows, err := r.conn().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]*models.Event, 0)
+	for rows.Next() {
+		var (
+			event     models.Event
+			eventType string
+			data      []byte
+		)
+		if err := rows.Scan(&event.ID, &eventType, &event.EntityID, &event.Version, &event.Sequence, &event.SchemaVersion, &event.JobID, &data, &event.Timestamp); err != nil {
+			return nil, err
+		}
+		event.Type = models.EventType(eventType)
+		productEvent, err := decodeProductEvent(data)
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %v", event.ID, err)
+		}
+		event.Data = productEvent
+		events = append(events, &event)
+	}
+	return events, rows.Err()
+}
+
+// decodeProduct decodes a stored product
+func decodeProduct(data []byte) (*models.Product, error) {
+	var product models.Product
+	if err := json.Unmarshal(data, &product); err != nil {
+		return nil, fmt.Errorf("invalid stored product: %v", err)
+	}
+	return &product, nil
+}
+
+// decodeProductEvent decodes stored event data; every stored event is a product event
+func decodeProductEvent(data []byte) (*models.ProductEvent, error) {
+	var productEvent models.ProductEvent
+	if err := json.Unmarshal(data, &productEvent); err != nil {
+		return nil, fmt.Errorf("invalid stored event data: %v", err)
+	}
+	return &productEvent, nil
+}
+
+// uniqueViolation is the SQLSTATE of a unique constraint violation
+const uniqueViolation = "23505"
+
+// skuError turns a violation of the unique SKU index into ErrDuplicateSKU. The
+// products primary key never conflicts on writes: Create upserts and Update
+// matches the row by it. Drivers expose the SQLSTATE through SQLState(), as
+// pgx's *pgconn.PgError and lib/pq's *pq.Error do.
+func skuError(err error) error {
+	if isUniqueViolation(err) {
+		return fmt.Errorf("%w: %v", models.ErrDuplicateSKU, err)
+	}
+	return err
+}
+
+// isUniqueViolation reports whether a driver error is a unique constraint violation
+func isUniqueViolation(err error) bool {
+	var state interface{ SQLState() string }
+	return errors.As(err, &state) && state.SQLState() == uniqueViolation
+}
+
+// requireRow turns a write that matched no product into ErrProductNotFound
+func requireRow(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return models.ErrProductNotFound
+	}
+	return nil
+}
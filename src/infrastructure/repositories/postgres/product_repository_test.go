@@ -0,0 +1,291 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/stretchr/testify/assert"
+)
+
+// openTestRepository connects to the database in POSTGRES_TEST_DSN with the
+// driver in POSTGRES_TEST_DRIVER (default pgx), which the test binary must link.
+// Tests using it are skipped without a database.
+func openTestRepository(t *testing.T) repositories.ProductRepository {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN not set")
+	}
+	driver := os.Getenv("POSTGRES_TEST_DRIVER")
+	if driver == "" {
+		driver = "pgx"
+	}
+	registered := false
+	for _, name := range sql.Drivers() {
+		registered = registered || name == driver
+	}
+	if !registered {
+		t.Skipf("database driver %q not linked into the test binary", driver)
+	}
+
+	db, err := Open(Config{Driver: driver, DSN: dsn, MaxOpenConns: 4})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { db.Close() })
+	_, err = Migrate(context.Background(), db)
+	assert.NoError(t, err)
+	_, err = db.Exec(`TRUNCATE products, product_events, event_outbox`)
+	assert.NoError(t, err)
+	return NewProductRepository(db)
+}
+
+func createTestProduct(id string, createdAt time.Time) *models.Product {
+	return &models.Product{
+		ID:        id,
+		SKU:       "SKU-" + id,
+		BaseTitle: "Product " + id,
+		Prices:    []models.Price{{Currency: "SEK", Amount: 100}},
+		Variants: []models.Variant{{
+			ID:    id + "-v1",
+			Stock: []models.Stock{{LocationID: "wh1", Quantity: 5}},
+		}},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		Version:   1,
+	}
+}
+
+func TestDecodeProductEvent(t *testing.T) {
+	data, _ := json.Marshal(&models.ProductEvent{
+		ProductID: "prod_1",
+		Action:    "updated",
+		Product:   &models.Product{ID: "prod_1", LastHash: "abc"},
+		Version:   2,
+		PrevHash:  "def",
+	})
+
+	decoded, err := decodeProductEvent(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", decoded.Product.LastHash)
+	assert.Equal(t, "def", decoded.PrevHash)
+
+	_, err = decodeProductEvent([]byte("{"))
+	assert.Error(t, err)
+}
+
+func TestProductCRUD(t *testing.T) {
+	repo := openTestRepository(t)
+	product := createTestProduct("prod_1", time.Now().UTC().Truncate(time.Microsecond))
+
+	assert.NoError(t, repo.Create(context.Background(), product))
+	stored, err := repo.GetByID(context.Background(), "prod_1")
+	assert.NoError(t, err)
+	assert.Equal(t, product.SKU, stored.SKU)
+	assert.True(t, product.CreatedAt.Equal(stored.CreatedAt))
+
+	stored.BaseTitle = "Changed"
+	stored.Version = 2
+	assert.NoError(t, repo.Update(context.Background(), stored))
+	updated, _ := repo.GetByID(context.Background(), "prod_1")
+	assert.Equal(t, "Changed", updated.BaseTitle)
+	assert.Equal(t, int64(2), updated.Version)
+
+	// Writing over a version other than the stored one conflicts
+	stale := createTestProduct("prod_1", product.CreatedAt)
+	stale.Version = 2
+	assert.ErrorIs(t, repo.Update(context.Background(), stale), models.ErrVersionConflict)
+	updated, _ = repo.GetByID(context.Background(), "prod_1")
+	assert.Equal(t, "Changed", updated.BaseTitle)
+
+	assert.ErrorIs(t, repo.Update(context.Background(), createTestProduct("missing", time.Now())), models.ErrProductNotFound)
+	assert.NoError(t, repo.Delete(context.Background(), "prod_1"))
+	assert.ErrorIs(t, repo.Delete(context.Background(), "prod_1"), models.ErrProductNotFound)
+	_, err = repo.GetByID(context.Background(), "prod_1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestProductSKUUniqueness(t *testing.T) {
+	repo := openTestRepository(t)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	assert.NoError(t, repo.Create(context.Background(), createTestProduct("prod_1", now)))
+	assert.NoError(t, repo.Create(context.Background(), createTestProduct("prod_2", now)))
+
+	duplicate := createTestProduct("prod_3", now)
+	duplicate.SKU = "SKU-prod_1"
+	assert.ErrorIs(t, repo.Create(context.Background(), duplicate), models.ErrDuplicateSKU)
+	renamed := createTestProduct("prod_2", now)
+	renamed.SKU = "SKU-prod_1"
+	renamed.Version = 2
+	assert.ErrorIs(t, repo.Update(context.Background(), renamed), models.ErrDuplicateSKU)
+
+	found, err := repo.GetBySKU(context.Background(), "SKU-prod_2")
+	assert.NoError(t, err)
+	assert.Equal(t, "prod_2", found.ID)
+	_, err = repo.GetBySKU(context.Background(), "SKU-prod_3")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+// sqlStateError is a driver error with a SQLSTATE
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "driver error " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestSKUError(t *testing.T) {
+	assert.ErrorIs(t, skuError(fmt.Errorf("insert: %w", sqlStateError("23505"))), models.ErrDuplicateSKU)
+	assert.NotErrorIs(t, skuError(sqlStateError("23503")), models.ErrDuplicateSKU)
+	assert.NoError(t, skuError(nil))
+	assert.False(t, isUniqueViolation(nil))
+}
+
+func TestProductList(t *testing.T) {
+	repo := openTestRepository(t)
+	base := time.Now()
+	for i, id := range []string{"a", "b", "c"} {
+		repo.Create(context.Background(), createTestProduct(id, base.Add(time.Duration(i)*time.Second)))
+	}
+
+	page, total, err := repo.List(context.Background(), models.ProductFilter{}, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, "c", page[0].ID)
+	assert.Equal(t, "b", page[1].ID)
+
+	last, _, _ := repo.List(context.Background(), models.ProductFilter{}, 2, 2)
+	assert.Len(t, last, 1)
+	beyond, _, _ := repo.List(context.Background(), models.ProductFilter{}, 3, 2)
+	assert.Empty(t, beyond)
+}
+
+func TestProductListAfter(t *testing.T) {
+	repo := openTestRepository(t)
+	base := time.Now()
+	for i, id := range []string{"a", "b", "c"} {
+		repo.Create(context.Background(), createTestProduct(id, base.Add(time.Duration(i)*time.Second)))
+	}
+
+	page, next, err := repo.ListAfter(context.Background(), models.ProductFilter{}, nil, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "c", page[0].ID)
+	assert.Equal(t, "b", page[1].ID)
+	assert.NotNil(t, next)
+
+	repo.Create(context.Background(), createTestProduct("d", base.Add(time.Minute)))
+	page, next, err = repo.ListAfter(context.Background(), models.ProductFilter{}, next, 2)
+	assert.NoError(t, err)
+	assert.Len(t, page, 1)
+	assert.Equal(t, "a", page[0].ID)
+	assert.Nil(t, next)
+}
+
+func TestProductListFilter(t *testing.T) {
+	repo := openTestRepository(t)
+	base := time.Now()
+	for i, id := range []string{"a", "b", "c"} {
+		product := createTestProduct(id, base.Add(time.Duration(i)*time.Second))
+		product.Prices[0].Amount = float64(100 * (i + 1))
+		repo.Create(context.Background(), product)
+	}
+
+	minPrice := 150.0
+	products, total, err := repo.List(context.Background(), models.ProductFilter{Currency: "sek", MinPrice: &minPrice}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, "c", products[0].ID)
+
+	products, total, err = repo.List(context.Background(), models.ProductFilter{SKU: "SKU-b", Title: "product"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, "b", products[0].ID)
+
+	_, total, err = repo.List(context.Background(), models.ProductFilter{Market: "SE"}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+}
+
+func TestProductEvents(t *testing.T) {
+	repo := openTestRepository(t)
+	base := time.Now().UTC()
+	for version := int64(1); version <= 3; version++ {
+		assert.NoError(t, repo.StoreEvent(context.Background(), &models.Event{
+			ID:            "evt_" + string(rune('0'+version)),
+			Type:          models.EventProductUpdated,
+			EntityID:      "prod_1",
+			Version:       version,
+			Sequence:      version,
+			SchemaVersion: models.EventSchemaVersion,
+			Data:          &models.ProductEvent{ProductID: "prod_1", Version: version},
+			Timestamp:     base.Add(time.Duration(version) * time.Second),
+		}))
+	}
+
+	events, err := repo.GetEventsByProductID(context.Background(), "prod_1", 2)
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, int64(2), events[0].Data.(*models.ProductEvent).Version)
+
+	until, _ := repo.GetEventsUntil(context.Background(), base.Add(2*time.Second))
+	assert.Len(t, until, 2)
+
+	// A second event for a version conflicts
+	err = repo.StoreEvent(context.Background(), &models.Event{
+		ID: "evt_other", Type: models.EventProductUpdated, EntityID: "prod_1", Version: 3, Sequence: 4,
+		Data: &models.ProductEvent{ProductID: "prod_1", Version: 3}, Timestamp: base,
+	})
+	assert.ErrorIs(t, err, models.ErrVersionConflict)
+}
+
+func TestProductAdjustStock(t *testing.T) {
+	repo := openTestRepository(t)
+	repo.Create(context.Background(), createTestProduct("prod_1", time.Now()))
+
+	previous, updated, err := repo.AdjustStock(context.Background(), "prod_1", []models.StockAdjustment{{VariantID: "prod_1-v1", LocationID: "wh1", Delta: -2}})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, previous.Variants[0].Stock[0].Quantity)
+	assert.Equal(t, 3, updated.Variants[0].Stock[0].Quantity)
+
+	_, _, err = repo.AdjustStock(context.Background(), "prod_1", []models.StockAdjustment{{VariantID: "prod_1-v1", LocationID: "wh1", Delta: -10}})
+	assert.ErrorIs(t, err, models.ErrInsufficientStock)
+}
+
+func TestProductWithTx(t *testing.T) {
+	repo := openTestRepository(t)
+	ctx := context.Background()
+	product := createTestProduct("prod_1", time.Now())
+	event := &models.Event{ID: "evt_1", Type: models.EventProductCreated, EntityID: "prod_1", Version: 1, Timestamp: time.Now(),
+		Data: &models.ProductEvent{ProductID: "prod_1", Action: "created", Product: product, Version: 1}}
+
+	failed := fmt.Errorf("failed")
+	err := repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		assert.NoError(t, tx.StoreEvent(ctx, event))
+		assert.NoError(t, tx.Create(ctx, product))
+		_, err := tx.GetByID(ctx, "prod_1")
+		assert.NoError(t, err)
+		return failed
+	})
+	assert.ErrorIs(t, err, failed)
+	_, err = repo.GetByID(ctx, "prod_1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+	events, err := repo.GetEventsByProductID(ctx, "prod_1", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+
+	assert.NoError(t, repo.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(ctx, event); err != nil {
+			return err
+		}
+		return tx.Create(ctx, product)
+	}))
+	_, err = repo.GetByID(ctx, "prod_1")
+	assert.NoError(t, err)
+	events, err = repo.GetEventsByProductID(ctx, "prod_1", 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
@@ -0,0 +1,217 @@
+package replica
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+)
+
+// pruneThreshold is the number of tracked writes above which expired entries are dropped
+const pruneThreshold = 1024
+
+// LagReporter is implemented by replicas that know how far they are behind the primary
+type LagReporter interface {
+	ReplicationLag() time.Duration
+}
+
+// ReadPolicy controls where a kind of read is served from
+type ReadPolicy struct {
+	// MaxStaleness is the replication lag the read tolerates.
+	// Zero sends the read to the primary.
+	MaxStaleness time.Duration
+}
+
+// Options configures read routing per repository operation. The operations map
+// to endpoints: GetByID serves GET /products/{id}, List serves GET /products and
+// Events serves event replay and history.
+type Options struct {
+	GetByID ReadPolicy
+	List    ReadPolicy
+	Events  ReadPolicy
+
+	// ReadYourWritesWindow sends reads of a product to the primary for this
+	// long after it was written, so clients see their own changes
+	ReadYourWritesWindow time.Duration
+}
+
+// ProductRepository routes mutations to a primary and reads to replicas
+type ProductRepository struct {
+	primary  repositories.ProductRepository
+	replicas []repositories.ProductRepository
+	options  Options
+	next     atomic.Uint64
+	now      func() time.Time
+
+	mu     sync.Mutex
+	writes map[string]time.Time // product ID -> last write through this repository
+}
+
+// NewProductRepository creates a repository that writes to primary and reads from replicas
+func NewProductRepository(primary repositories.ProductRepository, replicas []repositories.ProductRepository, options Options) *ProductRepository {
+	return &ProductRepository{
+		primary:  primary,
+		replicas: replicas,
+		options:  options,
+		now:      time.Now,
+		writes:   make(map[string]time.Time),
+	}
+}
+
+// Create stores a new product on the primary
+func (r *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	if err := r.primary.Create(ctx, product); err != nil {
+		return err
+	}
+	r.recordWrite(product.ID)
+	return nil
+}
+
+// GetByID reads a product from a replica, or the primary if it was written recently
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	return r.readerFor(r.options.GetByID, id).GetByID(ctx, id)
+}
+
+// GetBySKU retrieves a product by SKU from the primary; the service checks SKU
+// uniqueness with it before writing, which a lagging replica could miss
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	return r.primary.GetBySKU(ctx, sku)
+}
+
+// Update modifies a product on the primary
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product) error {
+	if err := r.primary.Update(ctx, product); err != nil {
+		return err
+	}
+	r.recordWrite(product.ID)
+	return nil
+}
+
+// AdjustStock applies stock adjustments on the primary
+func (r *ProductRepository) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (*models.Product, *models.Product, error) {
+	previous, updated, err := r.primary.AdjustStock(ctx, productID, adjustments)
+	if err != nil {
+		return nil, nil, err
+	}
+	r.recordWrite(productID)
+	return previous, updated, nil
+}
+
+// Delete removes a product on the primary
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	if err := r.primary.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.recordWrite(id)
+	return nil
+}
+
+// List reads a page of matching products from a replica
+func (r *ProductRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	return r.readerFor(r.options.List, "").List(ctx, filter, page, pageSize)
+}
+
+// ListAfter reads a page of matching products after a cursor from a replica
+func (r *ProductRepository) ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) ([]*models.Product, *models.ProductCursor, error) {
+	return r.readerFor(r.options.List, "").ListAfter(ctx, filter, after, limit)
+}
+
+// GetEventsByProductID reads the events of a product from a replica, or the primary if it was written recently
+func (r *ProductRepository) GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
+	return r.readerFor(r.options.Events, productID).GetEventsByProductID(ctx, productID, fromVersion)
+}
+
+// StoreEvent stores an event on the primary
+func (r *ProductRepository) StoreEvent(ctx context.Context, event *models.Event) error {
+	if err := r.primary.StoreEvent(ctx, event); err != nil {
+		return err
+	}
+	r.recordWrite(event.EntityID)
+	return nil
+}
+
+// GetEventsUntil reads events from a replica
+func (r *ProductRepository) GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error) {
+	return r.readerFor(r.options.Events, "").GetEventsUntil(ctx, until)
+}
+
+// WithTx runs fn in a transaction on the primary, which also serves every read
+// in it, and records the products written once it commits
+func (r *ProductRepository) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	var written *ProductRepository
+	err := r.primary.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		written = NewProductRepository(tx, nil, r.options)
+		written.now = r.now
+		return fn(written)
+	})
+	if err != nil {
+		return err
+	}
+	written.mu.Lock()
+	defer written.mu.Unlock()
+	for id := range written.writes {
+		r.recordWrite(id)
+	}
+	return nil
+}
+
+// Primary returns the primary repository for reads that must not be stale
+func (r *ProductRepository) Primary() repositories.ProductRepository {
+	return r.primary
+}
+
+// readerFor picks the repository to serve a read with the given policy
+func (r *ProductRepository) readerFor(policy ReadPolicy, productID string) repositories.ProductRepository {
+	if len(r.replicas) == 0 || policy.MaxStaleness <= 0 || r.recentlyWritten(productID) {
+		return r.primary
+	}
+
+	// Round-robin over the replicas, skipping those lagging too far behind
+	start := r.next.Add(1)
+	for i := 0; i < len(r.replicas); i++ {
+		replica := r.replicas[(start+uint64(i))%uint64(len(r.replicas))]
+		if lagging, ok := replica.(LagReporter); ok && lagging.ReplicationLag() > policy.MaxStaleness {
+			continue
+		}
+		return replica
+	}
+	return r.primary
+}
+
+// recordWrite remembers when a product was last written
+func (r *ProductRepository) recordWrite(productID string) {
+	if r.options.ReadYourWritesWindow <= 0 || productID == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.writes[productID] = now
+
+	// Drop expired entries so the map does not grow without bound
+	if len(r.writes) > pruneThreshold {
+		for id, at := range r.writes {
+			if now.Sub(at) > r.options.ReadYourWritesWindow {
+				delete(r.writes, id)
+			}
+		}
+	}
+}
+
+// recentlyWritten reports whether the product was written within the read-your-writes window
+func (r *ProductRepository) recentlyWritten(productID string) bool {
+	if r.options.ReadYourWritesWindow <= 0 || productID == "" {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	at, ok := r.writes[productID]
+	return ok && r.now().Sub(at) <= r.options.ReadYourWritesWindow
+}
@@ -0,0 +1,155 @@
+package replica
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+// laggingReplica is a replica that reports a fixed replication lag
+type laggingReplica struct {
+	repositories.ProductRepository
+	lag time.Duration
+}
+
+func (r *laggingReplica) ReplicationLag() time.Duration {
+	return r.lag
+}
+
+func createRoutedProduct(id string) *models.Product {
+	return &models.Product{ID: id, SKU: "SKU-" + id, BaseTitle: "Product " + id}
+}
+
+func TestWritesGoToPrimary(t *testing.T) {
+	primary := memory.NewProductRepository()
+	replica := memory.NewProductRepository()
+	repo := NewProductRepository(primary, []repositories.ProductRepository{replica}, Options{})
+
+	assert.NoError(t, repo.Create(context.Background(), createRoutedProduct("p1")))
+	assert.NoError(t, repo.StoreEvent(context.Background(), &models.Event{ID: "e1", EntityID: "p1", Version: 1}))
+
+	_, err := primary.GetByID(context.Background(), "p1")
+	assert.NoError(t, err)
+	_, err = replica.GetByID(context.Background(), "p1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestAdjustStockGoesToPrimary(t *testing.T) {
+	primary := memory.NewProductRepository()
+	replica := memory.NewProductRepository()
+	repo := NewProductRepository(primary, []repositories.ProductRepository{replica}, Options{})
+
+	product := createRoutedProduct("p1")
+	product.Variants = []models.Variant{{ID: "v1", Stock: []models.Stock{{LocationID: "wh1", Quantity: 1}}}}
+	assert.NoError(t, primary.Create(context.Background(), product))
+	assert.NoError(t, replica.Create(context.Background(), product.Clone()))
+
+	_, updated, err := repo.AdjustStock(context.Background(), "p1", []models.StockAdjustment{{VariantID: "v1", LocationID: "wh1", Delta: -1}})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, updated.Variants[0].Stock[0].Quantity)
+
+	stale, _ := replica.GetByID(context.Background(), "p1")
+	assert.Equal(t, 1, stale.Variants[0].Stock[0].Quantity)
+}
+
+func TestReadsFollowPolicy(t *testing.T) {
+	primary := memory.NewProductRepository()
+	replica := memory.NewProductRepository()
+	assert.NoError(t, primary.Create(context.Background(), createRoutedProduct("on-primary")))
+	assert.NoError(t, replica.Create(context.Background(), createRoutedProduct("on-replica")))
+
+	repo := NewProductRepository(primary, []repositories.ProductRepository{replica}, Options{
+		List: ReadPolicy{MaxStaleness: time.Second},
+	})
+
+	// List tolerates staleness and is served by the replica
+	products, _, err := repo.List(context.Background(), models.ProductFilter{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "on-replica", products[0].ID)
+
+	// GetByID has no staleness budget and is served by the primary
+	_, err = repo.GetByID(context.Background(), "on-primary")
+	assert.NoError(t, err)
+	_, err = repo.GetByID(context.Background(), "on-replica")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestLaggingReplicasAreSkipped(t *testing.T) {
+	primary := memory.NewProductRepository()
+	fresh := memory.NewProductRepository()
+	assert.NoError(t, fresh.Create(context.Background(), createRoutedProduct("fresh")))
+	stale := &laggingReplica{ProductRepository: memory.NewProductRepository(), lag: time.Minute}
+
+	repo := NewProductRepository(primary, []repositories.ProductRepository{stale, fresh}, Options{
+		GetByID: ReadPolicy{MaxStaleness: 5 * time.Second},
+	})
+
+	for i := 0; i < 4; i++ {
+		_, err := repo.GetByID(context.Background(), "fresh")
+		assert.NoError(t, err)
+	}
+
+	// With every replica lagging, reads fall back to the primary
+	repo = NewProductRepository(primary, []repositories.ProductRepository{stale}, Options{
+		GetByID: ReadPolicy{MaxStaleness: 5 * time.Second},
+	})
+	assert.NoError(t, primary.Create(context.Background(), createRoutedProduct("primary-only")))
+	_, err := repo.GetByID(context.Background(), "primary-only")
+	assert.NoError(t, err)
+}
+
+func TestReadYourWrites(t *testing.T) {
+	primary := memory.NewProductRepository()
+	replica := memory.NewProductRepository()
+	repo := NewProductRepository(primary, []repositories.ProductRepository{replica}, Options{
+		GetByID:              ReadPolicy{MaxStaleness: time.Second},
+		ReadYourWritesWindow: 10 * time.Second,
+	})
+
+	now := time.Now()
+	repo.now = func() time.Time { return now }
+
+	assert.NoError(t, repo.Create(context.Background(), createRoutedProduct("p1")))
+
+	// The replica has not caught up yet, but the write is visible
+	_, err := repo.GetByID(context.Background(), "p1")
+	assert.NoError(t, err)
+
+	// After the window the read goes to the replica again
+	now = now.Add(11 * time.Second)
+	_, err = repo.GetByID(context.Background(), "p1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+}
+
+func TestTransactionsGoToPrimary(t *testing.T) {
+	primary := memory.NewProductRepository()
+	replica := memory.NewProductRepository()
+	repo := NewProductRepository(primary, []repositories.ProductRepository{replica}, Options{
+		GetByID:              ReadPolicy{MaxStaleness: time.Second},
+		ReadYourWritesWindow: 10 * time.Second,
+	})
+
+	assert.NoError(t, repo.WithTx(context.Background(), func(tx repositories.ProductRepository) error {
+		if err := tx.Create(context.Background(), createRoutedProduct("p1")); err != nil {
+			return err
+		}
+		// Reads in the transaction see its writes
+		_, err := tx.GetByID(context.Background(), "p1")
+		return err
+	}))
+
+	_, err := primary.GetByID(context.Background(), "p1")
+	assert.NoError(t, err)
+	_, err = replica.GetByID(context.Background(), "p1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+
+	// The committed write counts for read-your-writes
+	_, err = repo.GetByID(context.Background(), "p1")
+	assert.NoError(t, err)
+}
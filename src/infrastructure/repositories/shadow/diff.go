@@ -0,0 +1,133 @@
+package shadow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// maxListedDiffs caps the differences reported for a single list comparison
+const maxListedDiffs = 10
+
+// cloneProduct copies a primary result before it is compared in the background
+func cloneProduct(product *models.Product) *models.Product {
+	if product == nil {
+		return nil
+	}
+	return product.Clone()
+}
+
+// diffResults compares the outcome of a read on both backends. Matching domain
+// errors, such as both reporting a missing product, are a match; any other
+// shadow failure is returned as an error rather than a difference.
+func diffResults(primaryErr, shadowErr error, diff func() []string) ([]string, error) {
+	switch {
+	case primaryErr == nil && shadowErr == nil:
+		return diff(), nil
+	case primaryErr != nil && shadowErr != nil:
+		if errors.Is(shadowErr, unwrapAll(primaryErr)) {
+			return nil, nil
+		}
+		return []string{fmt.Sprintf("error: %v != %v", primaryErr, shadowErr)}, nil
+	case shadowErr != nil && !errors.Is(shadowErr, models.ErrProductNotFound):
+		return nil, shadowErr
+	default:
+		return []string{fmt.Sprintf("error: %v != %v", primaryErr, shadowErr)}, nil
+	}
+}
+
+// unwrapAll returns the innermost error, e.g. the sentinel of a wrapped domain error
+func unwrapAll(err error) error {
+	for {
+		inner := errors.Unwrap(err)
+		if inner == nil {
+			return err
+		}
+		err = inner
+	}
+}
+
+// productDiff lists the fields that differ between two products. Timestamps are
+// compared at millisecond precision, since databases store them with less
+// precision than Go, and UpdatedAt is ignored because stock adjustments set it
+// independently on each backend.
+func productDiff(expected, actual *models.Product) []string {
+	if expected == nil || actual == nil {
+		if expected == actual {
+			return nil
+		}
+		return []string{fmt.Sprintf("product: %v != %v", expected != nil, actual != nil)}
+	}
+
+	fields := []struct {
+		name             string
+		expected, actual interface{}
+	}{
+		{"sku", expected.SKU, actual.SKU},
+		{"base_title", expected.BaseTitle, actual.BaseTitle},
+		{"description", expected.Description, actual.Description},
+		{"prices", expected.Prices, actual.Prices},
+		{"variants", expected.Variants, actual.Variants},
+		{"metadata", expected.Metadata, actual.Metadata},
+		{"images", expected.Images, actual.Images},
+		{"version", expected.Version, actual.Version},
+		{"deleted", expected.IsDeleted(), actual.IsDeleted()},
+		{"last_hash", expected.LastHash, actual.LastHash},
+		{"created_at", expected.CreatedAt.Truncate(time.Millisecond).UTC(), actual.CreatedAt.Truncate(time.Millisecond).UTC()},
+	}
+
+	diffs := make([]string, 0)
+	for _, field := range fields {
+		expectedJSON, actualJSON := canonical(field.expected), canonical(field.actual)
+		if expectedJSON != actualJSON {
+			diffs = append(diffs, fmt.Sprintf("%s: %s != %s", field.name, expectedJSON, actualJSON))
+		}
+	}
+	return diffs
+}
+
+// canonical encodes a value for comparison, treating nil and empty slices alike
+func canonical(value interface{}) string {
+	data, _ := json.Marshal(value)
+	if string(data) == "null" {
+		return "[]"
+	}
+	return string(data)
+}
+
+// productListDiff compares two pages of products position by position
+func productListDiff(expected, actual []*models.Product) []string {
+	diffs := make([]string, 0)
+	if len(expected) != len(actual) {
+		diffs = append(diffs, fmt.Sprintf("count: %d != %d", len(expected), len(actual)))
+	}
+	for i := 0; i < len(expected) && i < len(actual) && len(diffs) < maxListedDiffs; i++ {
+		if expected[i].ID != actual[i].ID {
+			diffs = append(diffs, fmt.Sprintf("[%d] id: %s != %s", i, expected[i].ID, actual[i].ID))
+			continue
+		}
+		for _, diff := range productDiff(expected[i], actual[i]) {
+			diffs = append(diffs, fmt.Sprintf("[%d] %s", i, diff))
+		}
+	}
+	return diffs
+}
+
+// eventListDiff compares two event lists by identity and position in the log
+func eventListDiff(expected, actual []*models.Event) []string {
+	diffs := make([]string, 0)
+	if len(expected) != len(actual) {
+		diffs = append(diffs, fmt.Sprintf("count: %d != %d", len(expected), len(actual)))
+	}
+	for i := 0; i < len(expected) && i < len(actual) && len(diffs) < maxListedDiffs; i++ {
+		e, a := expected[i], actual[i]
+		if e.ID != a.ID || e.Type != a.Type || e.Version != a.Version || e.Sequence != a.Sequence {
+			diffs = append(diffs, fmt.Sprintf("[%d] event: %s %s v%d #%d != %s %s v%d #%d",
+				i, e.ID, e.Type, e.Version, e.Sequence, a.ID, a.Type, a.Version, a.Sequence))
+		}
+	}
+	return diffs
+}
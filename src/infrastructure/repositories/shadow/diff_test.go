@@ -0,0 +1,57 @@
+package shadow
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestProductDiff(t *testing.T) {
+	expected := createShadowProduct("p1")
+	expected.CreatedAt = time.Date(2024, 3, 1, 12, 0, 0, 123456789, time.UTC)
+	actual := expected.Clone()
+	actual.UpdatedAt = time.Now().Add(time.Hour)
+	actual.CreatedAt = expected.CreatedAt.Truncate(time.Microsecond)
+	actual.Images = []models.Image{}
+	assert.Empty(t, productDiff(expected, actual), "timestamps, precision and empty slices are not differences")
+
+	actual.Prices[0].Amount = 120
+	actual.Version = 2
+	diffs := productDiff(expected, actual)
+	assert.Len(t, diffs, 2)
+	assert.Equal(t, `prices: [{"currency":"SEK","amount":100}] != [{"currency":"SEK","amount":120}]`, diffs[0])
+	assert.Equal(t, "version: 1 != 2", diffs[1])
+
+	assert.Equal(t, []string{"product: true != false"}, productDiff(expected, nil))
+}
+
+func TestDiffResults(t *testing.T) {
+	noDiff := func() []string { return nil }
+
+	diffs, err := diffResults(models.ErrProductNotFound, fmt.Errorf("%w: p1", models.ErrProductNotFound), noDiff)
+	assert.NoError(t, err)
+	assert.Empty(t, diffs)
+
+	diffs, err = diffResults(nil, models.ErrProductNotFound, noDiff)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"error: <nil> != product not found"}, diffs)
+
+	_, err = diffResults(nil, errors.New("timeout"), noDiff)
+	assert.EqualError(t, err, "timeout")
+}
+
+func TestListDiffs(t *testing.T) {
+	expected := []*models.Product{createShadowProduct("p1"), createShadowProduct("p2")}
+	actual := []*models.Product{createShadowProduct("p2")}
+	assert.Equal(t, []string{"count: 2 != 1", "[0] id: p1 != p2"}, productListDiff(expected, actual))
+
+	events := []*models.Event{{ID: "e1", Type: models.EventProductCreated, Version: 1, Sequence: 1}}
+	assert.Empty(t, eventListDiff(events, []*models.Event{{ID: "e1", Type: models.EventProductCreated, Version: 1, Sequence: 1}}))
+	assert.Equal(t, []string{"[0] event: e1 product.created v1 #1 != e1 product.created v1 #2"},
+		eventListDiff(events, []*models.Event{{ID: "e1", Type: models.EventProductCreated, Version: 1, Sequence: 2}}))
+}
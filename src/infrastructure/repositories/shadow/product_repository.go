@@ -0,0 +1,390 @@
+package shadow
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/metrics"
+	"go.uber.org/zap"
+)
+
+// defaultMaxPendingComparisons bounds the background read comparisons when Options leaves it unset
+const defaultMaxPendingComparisons = 64
+
+// Comparison results used in metrics
+const (
+	resultMatch    = "match"
+	resultMismatch = "mismatch"
+	resultError    = "error"
+)
+
+// Options configures how the shadow repository is exercised
+type Options struct {
+	// ReadSampleRate is the share of reads, from 0 to 1, that are repeated on the
+	// shadow and compared with the primary's result
+	ReadSampleRate float64
+	// MaxPendingComparisons bounds the read comparisons running in the background.
+	// Reads sampled while the limit is reached are not compared.
+	MaxPendingComparisons int
+}
+
+// Stats counts the outcome of shadow traffic since the repository was created
+type Stats struct {
+	Comparisons  int64 `json:"comparisons"`
+	Mismatches   int64 `json:"mismatches"`
+	ShadowErrors int64 `json:"shadow_errors"`
+	Skipped      int64 `json:"skipped"`
+}
+
+// ProductRepository serves every call from the primary and mirrors it to a shadow
+// backend that is being migrated to. Mutations that succeed on the primary are
+// repeated on the shadow; sampled reads are repeated in the background and the
+// results compared. The shadow never changes a response: its errors and
+// differences are only logged and counted.
+type ProductRepository struct {
+	primary repositories.ProductRepository
+	shadow  repositories.ProductRepository
+	options Options
+	sample  func() float64
+
+	pending chan struct{}
+	wg      sync.WaitGroup
+
+	comparisons  atomic.Int64
+	mismatches   atomic.Int64
+	shadowErrors atomic.Int64
+	skipped      atomic.Int64
+}
+
+// NewProductRepository creates a repository that serves from primary and mirrors traffic to shadow
+func NewProductRepository(primary, shadow repositories.ProductRepository, options Options) *ProductRepository {
+	if options.MaxPendingComparisons <= 0 {
+		options.MaxPendingComparisons = defaultMaxPendingComparisons
+	}
+	return &ProductRepository{
+		primary: primary,
+		shadow:  shadow,
+		options: options,
+		sample:  rand.Float64,
+		pending: make(chan struct{}, options.MaxPendingComparisons),
+	}
+}
+
+// Create stores a new product on the primary and then on the shadow
+func (r *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	if err := r.primary.Create(ctx, product); err != nil {
+		return err
+	}
+	r.mirrorWrite("create", product.ID, r.shadow.Create(ctx, product.Clone()))
+	return nil
+}
+
+// GetByID reads a product from the primary
+func (r *ProductRepository) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	product, err := r.primary.GetByID(ctx, id)
+	if r.sampled() {
+		expected := cloneProduct(product)
+		r.compareInBackground("get", id, func() ([]string, error) {
+			actual, shadowErr := r.shadow.GetByID(ctx, id)
+			return diffResults(err, shadowErr, func() []string { return productDiff(expected, actual) })
+		})
+	}
+	return product, err
+}
+
+// GetBySKU retrieves a product by SKU from the primary, comparing a sample with the shadow
+func (r *ProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	product, err := r.primary.GetBySKU(ctx, sku)
+	if r.sampled() {
+		expected := cloneProduct(product)
+		r.compareInBackground("get_by_sku", "", func() ([]string, error) {
+			actual, shadowErr := r.shadow.GetBySKU(ctx, sku)
+			return diffResults(err, shadowErr, func() []string { return productDiff(expected, actual) })
+		})
+	}
+	return product, err
+}
+
+// Update modifies a product on the primary and then on the shadow
+func (r *ProductRepository) Update(ctx context.Context, product *models.Product) error {
+	if err := r.primary.Update(ctx, product); err != nil {
+		return err
+	}
+	r.mirrorWrite("update", product.ID, r.shadow.Update(ctx, product.Clone()))
+	return nil
+}
+
+// AdjustStock applies stock adjustments on the primary and repeats them on the
+// shadow. Both results are compared since the shadow runs its own compare-and-set.
+func (r *ProductRepository) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (*models.Product, *models.Product, error) {
+	previous, updated, err := r.primary.AdjustStock(ctx, productID, adjustments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, shadowUpdated, shadowErr := r.shadow.AdjustStock(ctx, productID, adjustments)
+	if shadowErr != nil {
+		r.mirrorWrite("adjust_stock", productID, shadowErr)
+	} else {
+		r.record("adjust_stock", productID, productDiff(updated, shadowUpdated), nil)
+	}
+	return previous, updated, nil
+}
+
+// Delete removes a product on the primary and then on the shadow
+func (r *ProductRepository) Delete(ctx context.Context, id string) error {
+	if err := r.primary.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.mirrorWrite("delete", id, r.shadow.Delete(ctx, id))
+	return nil
+}
+
+// List reads a page of matching products from the primary
+func (r *ProductRepository) List(ctx context.Context, filter models.ProductFilter, page, pageSize int) ([]*models.Product, int, error) {
+	products, total, err := r.primary.List(ctx, filter, page, pageSize)
+	if r.sampled() {
+		expected := make([]*models.Product, len(products))
+		for i, product := range products {
+			expected[i] = cloneProduct(product)
+		}
+		r.compareInBackground("list", "", func() ([]string, error) {
+			actual, shadowTotal, shadowErr := r.shadow.List(ctx, filter, page, pageSize)
+			return diffResults(err, shadowErr, func() []string {
+				diffs := make([]string, 0)
+				if total != shadowTotal {
+					diffs = append(diffs, fmt.Sprintf("total: %d != %d", total, shadowTotal))
+				}
+				return append(diffs, productListDiff(expected, actual)...)
+			})
+		})
+	}
+	return products, total, err
+}
+
+// ListAfter reads a page of matching products after a cursor from the primary
+func (r *ProductRepository) ListAfter(ctx context.Context, filter models.ProductFilter, after *models.ProductCursor, limit int) ([]*models.Product, *models.ProductCursor, error) {
+	products, next, err := r.primary.ListAfter(ctx, filter, after, limit)
+	if r.sampled() {
+		expected := make([]*models.Product, len(products))
+		for i, product := range products {
+			expected[i] = cloneProduct(product)
+		}
+		r.compareInBackground("list_after", "", func() ([]string, error) {
+			actual, _, shadowErr := r.shadow.ListAfter(ctx, filter, after, limit)
+			return diffResults(err, shadowErr, func() []string {
+				return productListDiff(expected, actual)
+			})
+		})
+	}
+	return products, next, err
+}
+
+// GetEventsByProductID reads the events of a product from the primary
+func (r *ProductRepository) GetEventsByProductID(ctx context.Context, productID string, fromVersion int64) ([]*models.Event, error) {
+	events, err := r.primary.GetEventsByProductID(ctx, productID, fromVersion)
+	if r.sampled() {
+		expected := append([]*models.Event(nil), events...)
+		r.compareInBackground("events", productID, func() ([]string, error) {
+			actual, shadowErr := r.shadow.GetEventsByProductID(ctx, productID, fromVersion)
+			return diffResults(err, shadowErr, func() []string { return eventListDiff(expected, actual) })
+		})
+	}
+	return events, err
+}
+
+// StoreEvent stores an event on the primary and then on the shadow
+func (r *ProductRepository) StoreEvent(ctx context.Context, event *models.Event) error {
+	if err := r.primary.StoreEvent(ctx, event); err != nil {
+		return err
+	}
+	r.mirrorWrite("store_event", event.EntityID, r.shadow.StoreEvent(ctx, event))
+	return nil
+}
+
+// GetEventsUntil reads events from the primary
+func (r *ProductRepository) GetEventsUntil(ctx context.Context, until time.Time) ([]*models.Event, error) {
+	events, err := r.primary.GetEventsUntil(ctx, until)
+	if r.sampled() {
+		expected := append([]*models.Event(nil), events...)
+		r.compareInBackground("events_until", "", func() ([]string, error) {
+			actual, shadowErr := r.shadow.GetEventsUntil(ctx, until)
+			return diffResults(err, shadowErr, func() []string { return eventListDiff(expected, actual) })
+		})
+	}
+	return events, err
+}
+
+// WithTx runs fn in a transaction on the primary, whose reads are not
+// compared. Once it commits its writes are repeated in one transaction on the
+// shadow, which are all discarded if one of them fails there.
+func (r *ProductRepository) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	recorder := &txRecorder{}
+	err := r.primary.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		recorder.ProductRepository = tx
+		return fn(recorder)
+	})
+	if err != nil || len(recorder.writes) == 0 {
+		return err
+	}
+
+	failed := mirroredWrite{operation: "with_tx"}
+	shadowErr := r.shadow.WithTx(ctx, func(tx repositories.ProductRepository) error {
+		for _, write := range recorder.writes {
+			if err := write.apply(tx); err != nil {
+				failed = write
+				return err
+			}
+		}
+		return nil
+	})
+	r.mirrorWrite(failed.operation, failed.productID, shadowErr)
+	return nil
+}
+
+// mirroredWrite is a write of a transaction to repeat on the shadow
+type mirroredWrite struct {
+	operation string
+	productID string
+	apply     func(shadow repositories.ProductRepository) error
+}
+
+// txRecorder is a primary transaction recording its successful writes
+type txRecorder struct {
+	repositories.ProductRepository
+	writes []mirroredWrite
+}
+
+// record keeps a write when it succeeded on the primary
+func (t *txRecorder) record(err error, operation, productID string, apply func(shadow repositories.ProductRepository) error) error {
+	if err == nil {
+		t.writes = append(t.writes, mirroredWrite{operation: operation, productID: productID, apply: apply})
+	}
+	return err
+}
+
+func (t *txRecorder) Create(ctx context.Context, product *models.Product) error {
+	clone := product.Clone()
+	return t.record(t.ProductRepository.Create(ctx, product), "create", product.ID, func(shadow repositories.ProductRepository) error {
+		return shadow.Create(ctx, clone)
+	})
+}
+
+func (t *txRecorder) Update(ctx context.Context, product *models.Product) error {
+	clone := product.Clone()
+	return t.record(t.ProductRepository.Update(ctx, product), "update", product.ID, func(shadow repositories.ProductRepository) error {
+		return shadow.Update(ctx, clone)
+	})
+}
+
+func (t *txRecorder) AdjustStock(ctx context.Context, productID string, adjustments []models.StockAdjustment) (*models.Product, *models.Product, error) {
+	previous, updated, err := t.ProductRepository.AdjustStock(ctx, productID, adjustments)
+	return previous, updated, t.record(err, "adjust_stock", productID, func(shadow repositories.ProductRepository) error {
+		_, _, err := shadow.AdjustStock(ctx, productID, adjustments)
+		return err
+	})
+}
+
+func (t *txRecorder) Delete(ctx context.Context, id string) error {
+	return t.record(t.ProductRepository.Delete(ctx, id), "delete", id, func(shadow repositories.ProductRepository) error {
+		return shadow.Delete(ctx, id)
+	})
+}
+
+func (t *txRecorder) StoreEvent(ctx context.Context, event *models.Event) error {
+	return t.record(t.ProductRepository.StoreEvent(ctx, event), "store_event", event.EntityID, func(shadow repositories.ProductRepository) error {
+		return shadow.StoreEvent(ctx, event)
+	})
+}
+
+// WithTx joins the running transaction
+func (t *txRecorder) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	return fn(t)
+}
+
+// Primary returns the repository that serves responses
+func (r *ProductRepository) Primary() repositories.ProductRepository {
+	return r.primary
+}
+
+// Stats returns the outcome of shadow traffic so far
+func (r *ProductRepository) Stats() Stats {
+	return Stats{
+		Comparisons:  r.comparisons.Load(),
+		Mismatches:   r.mismatches.Load(),
+		ShadowErrors: r.shadowErrors.Load(),
+		Skipped:      r.skipped.Load(),
+	}
+}
+
+// Wait blocks until the background comparisons have finished
+func (r *ProductRepository) Wait() {
+	r.wg.Wait()
+}
+
+// sampled decides whether a read is compared
+func (r *ProductRepository) sampled() bool {
+	return r.options.ReadSampleRate > 0 && r.sample() < r.options.ReadSampleRate
+}
+
+// compareInBackground runs a read comparison without delaying the response,
+// dropping it if too many comparisons are already running
+func (r *ProductRepository) compareInBackground(operation, productID string, compare func() ([]string, error)) {
+	select {
+	case r.pending <- struct{}{}:
+	default:
+		r.skipped.Add(1)
+		return
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() { <-r.pending }()
+		diffs, err := compare()
+		r.record(operation, productID, diffs, err)
+	}()
+}
+
+// mirrorWrite records the outcome of a mutation repeated on the shadow
+func (r *ProductRepository) mirrorWrite(operation, productID string, err error) {
+	if err == nil {
+		return
+	}
+	r.shadowErrors.Add(1)
+	metrics.ShadowWriteErrors.WithLabelValues(operation).Inc()
+	logging.Shared().Warn("Shadow repository write failed",
+		zap.String("operation", operation),
+		zap.String("product_id", productID),
+		zap.Error(err),
+	)
+}
+
+// record counts a comparison and logs its differences, or the shadow's error
+func (r *ProductRepository) record(operation, productID string, diffs []string, err error) {
+	r.comparisons.Add(1)
+	logger := logging.Shared().WithFields(
+		zap.String("operation", operation),
+		zap.String("product_id", productID),
+	)
+
+	switch {
+	case err != nil:
+		r.shadowErrors.Add(1)
+		metrics.ShadowComparisons.WithLabelValues(operation, resultError).Inc()
+		logger.Warn("Shadow repository read failed", zap.Error(err))
+	case len(diffs) > 0:
+		r.mismatches.Add(1)
+		metrics.ShadowComparisons.WithLabelValues(operation, resultMismatch).Inc()
+		logger.Warn("Shadow repository read mismatch", zap.Strings("diffs", diffs))
+	default:
+		metrics.ShadowComparisons.WithLabelValues(operation, resultMatch).Inc()
+	}
+}
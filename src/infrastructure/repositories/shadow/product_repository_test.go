@@ -0,0 +1,193 @@
+package shadow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+// failingShadow is a shadow backend that fails every call
+type failingShadow struct {
+	repositories.ProductRepository
+}
+
+func (f *failingShadow) Create(ctx context.Context, product *models.Product) error {
+	return errors.New("connection refused")
+}
+
+func (f *failingShadow) GetByID(ctx context.Context, id string) (*models.Product, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (f *failingShadow) WithTx(ctx context.Context, fn func(tx repositories.ProductRepository) error) error {
+	return fn(f)
+}
+
+func createShadowProduct(id string) *models.Product {
+	return &models.Product{
+		ID:        id,
+		SKU:       "SKU-" + id,
+		BaseTitle: "Product " + id,
+		Prices:    []models.Price{{Currency: "SEK", Amount: 100}},
+		Variants: []models.Variant{
+			{ID: "v1", SKU: "SKU-" + id + "-1", Stock: []models.Stock{{LocationID: "wh1", Quantity: 2}}},
+		},
+		CreatedAt: time.Now(),
+		Version:   1,
+	}
+}
+
+func TestMutationsAreMirrored(t *testing.T) {
+	primary := memory.NewProductRepository()
+	mirror := memory.NewProductRepository()
+	repo := NewProductRepository(primary, mirror, Options{})
+
+	product := createShadowProduct("p1")
+	assert.NoError(t, repo.Create(context.Background(), product))
+	assert.NoError(t, repo.StoreEvent(context.Background(), &models.Event{ID: "e1", EntityID: "p1", Version: 1}))
+
+	copied, err := mirror.GetByID(context.Background(), "p1")
+	assert.NoError(t, err)
+	assert.Equal(t, "SKU-p1", copied.SKU)
+	assert.NotSame(t, product, copied, "the shadow must not share the primary's product")
+
+	updated := product.Clone()
+	updated.BaseTitle = "Renamed"
+	assert.NoError(t, repo.Update(context.Background(), updated))
+	copied, _ = mirror.GetByID(context.Background(), "p1")
+	assert.Equal(t, "Renamed", copied.BaseTitle)
+
+	_, _, err = repo.AdjustStock(context.Background(), "p1", []models.StockAdjustment{{VariantID: "v1", LocationID: "wh1", Delta: -1}})
+	assert.NoError(t, err)
+	copied, _ = mirror.GetByID(context.Background(), "p1")
+	assert.Equal(t, 1, copied.Variants[0].Stock[0].Quantity)
+
+	assert.NoError(t, repo.Delete(context.Background(), "p1"))
+	_, err = mirror.GetByID(context.Background(), "p1")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+
+	stats := repo.Stats()
+	assert.Equal(t, int64(1), stats.Comparisons, "the stock adjustment results are compared")
+	assert.Equal(t, int64(0), stats.Mismatches)
+	assert.Equal(t, int64(0), stats.ShadowErrors)
+}
+
+func TestTransactionsAreMirrored(t *testing.T) {
+	primary := memory.NewProductRepository()
+	mirror := memory.NewProductRepository()
+	repo := NewProductRepository(primary, mirror, Options{})
+
+	assert.NoError(t, repo.WithTx(context.Background(), func(tx repositories.ProductRepository) error {
+		if err := tx.StoreEvent(context.Background(), &models.Event{ID: "e1", EntityID: "p1", Version: 1}); err != nil {
+			return err
+		}
+		return tx.Create(context.Background(), createShadowProduct("p1"))
+	}))
+	_, err := mirror.GetByID(context.Background(), "p1")
+	assert.NoError(t, err)
+	events, err := mirror.GetEventsByProductID(context.Background(), "p1", 0)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	// A failing shadow discards the transaction there without failing it
+	repo = NewProductRepository(primary, &failingShadow{}, Options{})
+	assert.NoError(t, repo.WithTx(context.Background(), func(tx repositories.ProductRepository) error {
+		return tx.Create(context.Background(), createShadowProduct("p2"))
+	}))
+	_, err = primary.GetByID(context.Background(), "p2")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), repo.Stats().ShadowErrors)
+}
+
+func TestShadowFailuresDoNotAffectResponses(t *testing.T) {
+	primary := memory.NewProductRepository()
+	repo := NewProductRepository(primary, &failingShadow{}, Options{ReadSampleRate: 1})
+
+	assert.NoError(t, repo.Create(context.Background(), createShadowProduct("p1")))
+	product, err := repo.GetByID(context.Background(), "p1")
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", product.ID)
+
+	repo.Wait()
+	stats := repo.Stats()
+	assert.Equal(t, int64(2), stats.ShadowErrors)
+	assert.Equal(t, int64(0), stats.Mismatches)
+}
+
+func TestPrimaryFailuresAreNotMirrored(t *testing.T) {
+	mirror := memory.NewProductRepository()
+	repo := NewProductRepository(memory.NewProductRepository(), mirror, Options{})
+	assert.NoError(t, mirror.Create(context.Background(), createShadowProduct("p1")))
+
+	// The primary does not have the product, so the shadow keeps its copy
+	assert.ErrorIs(t, repo.Delete(context.Background(), "p1"), models.ErrProductNotFound)
+	_, err := mirror.GetByID(context.Background(), "p1")
+	assert.NoError(t, err)
+}
+
+func TestReadsAreCompared(t *testing.T) {
+	primary := memory.NewProductRepository()
+	mirror := memory.NewProductRepository()
+	repo := NewProductRepository(primary, mirror, Options{ReadSampleRate: 1})
+
+	assert.NoError(t, repo.Create(context.Background(), createShadowProduct("p1")))
+	assert.NoError(t, repo.Create(context.Background(), createShadowProduct("p2")))
+
+	// Diverge the shadow behind the repository's back
+	drifted, _ := mirror.GetByID(context.Background(), "p2")
+	drifted = drifted.Clone()
+	drifted.BaseTitle = "Drifted"
+	assert.NoError(t, mirror.Update(context.Background(), drifted))
+
+	_, err := repo.GetByID(context.Background(), "p1")
+	assert.NoError(t, err)
+	product, err := repo.GetByID(context.Background(), "p2")
+	assert.NoError(t, err)
+	assert.Equal(t, "Product p2", product.BaseTitle, "responses always come from the primary")
+	_, err = repo.GetByID(context.Background(), "missing")
+	assert.ErrorIs(t, err, models.ErrProductNotFound)
+
+	repo.Wait()
+	stats := repo.Stats()
+	assert.Equal(t, int64(3), stats.Comparisons)
+	assert.Equal(t, int64(1), stats.Mismatches)
+}
+
+func TestReadSampling(t *testing.T) {
+	repo := NewProductRepository(memory.NewProductRepository(), memory.NewProductRepository(), Options{ReadSampleRate: 0.5})
+	samples := []float64{0.2, 0.7}
+	repo.sample = func() float64 {
+		sample := samples[0]
+		samples = samples[1:]
+		return sample
+	}
+
+	repo.List(context.Background(), models.ProductFilter{}, 1, 10)
+	repo.List(context.Background(), models.ProductFilter{}, 1, 10)
+	repo.Wait()
+	assert.Equal(t, int64(1), repo.Stats().Comparisons)
+}
+
+func TestComparisonsAreBounded(t *testing.T) {
+	release := make(chan struct{})
+	repo := NewProductRepository(memory.NewProductRepository(), memory.NewProductRepository(), Options{ReadSampleRate: 1, MaxPendingComparisons: 1})
+
+	repo.compareInBackground("get", "p1", func() ([]string, error) {
+		<-release
+		return nil, nil
+	})
+	repo.GetByID(context.Background(), "p1")
+	close(release)
+	repo.Wait()
+
+	stats := repo.Stats()
+	assert.Equal(t, int64(1), stats.Comparisons)
+	assert.Equal(t, int64(1), stats.Skipped)
+}
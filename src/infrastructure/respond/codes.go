@@ -0,0 +1,143 @@
+package respond
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Error codes of the statuses and of errors found in the request itself.
+// Codes are part of the API: add new ones, but never change or reuse one.
+const (
+	CodeInvalidRequest       = "invalid_request"
+	CodeInvalidJSON          = "invalid_json"
+	CodeBatchTooLarge        = "batch_too_large"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeConflict             = "conflict"
+	CodePreconditionFailed   = "precondition_failed"
+	CodeRequestTooLarge      = "request_too_large"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeValidationFailed     = "validation_failed"
+	CodeLocked               = "locked"
+	CodePreconditionRequired = "precondition_required"
+	CodeRateLimited          = "rate_limited"
+	CodeInternal             = "internal_error"
+	CodeUnavailable          = "unavailable"
+)
+
+// statusCodes are the codes of errors without a more specific one
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            CodeInvalidRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusPreconditionFailed:    CodePreconditionFailed,
+	http.StatusRequestEntityTooLarge: CodeRequestTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMediaType,
+	http.StatusUnprocessableEntity:   CodeValidationFailed,
+	http.StatusLocked:                CodeLocked,
+	http.StatusPreconditionRequired:  CodePreconditionRequired,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusServiceUnavailable:    CodeUnavailable,
+}
+
+// domainCodes are the codes of the domain errors, checked in order so that
+// specific errors win over the generic ones at the end
+var domainCodes = []struct {
+	err  error
+	code string
+}{
+	{models.ErrProductNotFound, "product_not_found"},
+	{models.ErrVersionConflict, "version_conflict"},
+	{models.ErrDuplicateSKU, "duplicate_sku"},
+	{models.ErrNotDeleted, "product_not_deleted"},
+	{models.ErrLockFailed, "lock_failed"},
+	{models.ErrJobNotFound, "job_not_found"},
+	{models.ErrJobNotFinished, "job_not_finished"},
+	{models.ErrConsumerNotFound, "consumer_not_found"},
+	{models.ErrProjectionNotFound, "projection_not_found"},
+	{models.ErrOutboxEntryNotFound, "outbox_entry_not_found"},
+	{models.ErrVersionNotFound, "version_not_found"},
+	{models.ErrInvalidRollbackVersion, "invalid_rollback_version"},
+	{models.ErrBrokenEventChain, "broken_event_chain"},
+	{models.ErrInvalidMapping, "invalid_mapping"},
+	{models.ErrInsufficientStock, "insufficient_stock"},
+	{models.ErrVariantNotFound, "variant_not_found"},
+	{models.ErrSyncStatusNotFound, "sync_status_not_found"},
+	{models.ErrMarketplaceNotFound, "marketplace_not_found"},
+	{models.ErrCatalogSourceNotFound, "catalog_source_not_found"},
+	{models.ErrCatalogSourceFailed, "catalog_source_failed"},
+	{models.ErrUnknownMarketCurrency, "unknown_market_currency"},
+	{models.ErrMerchandisingNotFound, "merchandising_not_found"},
+	{models.ErrPinNotFound, "pin_not_found"},
+	{models.ErrAllocationPolicyNotFound, "allocation_policy_not_found"},
+	{models.ErrPriceListNotFound, "price_list_not_found"},
+	{models.ErrPriceNotFound, "price_not_found"},
+	{models.ErrCategoryNotFound, "category_not_found"},
+	{models.ErrDuplicateCategorySlug, "duplicate_category_slug"},
+	{models.ErrCategoryInUse, "category_in_use"},
+	{models.ErrImageNotFound, "image_not_found"},
+	{models.ErrNotABundle, "not_a_bundle"},
+	{models.ErrRelationNotFound, "relation_not_found"},
+	{models.ErrUnknownCurrency, "unknown_currency"},
+	{models.ErrExchangeRateNotFound, "exchange_rate_not_found"},
+	{models.ErrEditSessionNotFound, "edit_session_not_found"},
+	{models.ErrWebhookNotFound, "webhook_not_found"},
+	{models.ErrNoteNotFound, "note_not_found"},
+	{models.ErrNotNoteAuthor, "not_note_author"},
+	{models.ErrInvalidProduct, "invalid_product"},
+	{models.ErrInvalidRequest, CodeInvalidRequest},
+	{models.ErrInternalError, CodeInternal},
+}
+
+// CodeOf returns the code of the domain error err is or wraps, or "" for
+// other errors
+func CodeOf(err error) string {
+	var invalid *models.ValidationError
+	if errors.As(err, &invalid) {
+		return CodeValidationFailed
+	}
+	for _, domain := range domainCodes {
+		if errors.Is(err, domain.err) {
+			return domain.code
+		}
+	}
+	return ""
+}
+
+// StatusOf returns the HTTP status of a service error by its kind: 404 Not
+// Found, 409 Conflict, 422 Unprocessable Entity or 423 Locked, 400 Bad
+// Request for models.ErrInvalidRequest and 500 Internal Server Error for any
+// other error
+func StatusOf(err error) int {
+	switch {
+	case errors.Is(err, models.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, models.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, models.ErrValidation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, models.ErrLockTimeout):
+		return http.StatusLocked
+	case errors.Is(err, models.ErrInvalidRequest):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// StatusCode returns the code of an HTTP status, e.g. not_found for 404.
+// Statuses without a listed code get their status text in snake case.
+func StatusCode(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
@@ -0,0 +1,44 @@
+package respond
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestCodeOf(t *testing.T) {
+	assert.Equal(t, "product_not_found", CodeOf(models.ErrProductNotFound))
+	assert.Equal(t, "version_conflict", CodeOf(fmt.Errorf("update: %w", models.ErrVersionConflict)))
+	assert.Equal(t, CodeValidationFailed, CodeOf(&models.ValidationError{}))
+	assert.Equal(t, "", CodeOf(errors.New("boom")))
+	assert.Equal(t, "", CodeOf(nil))
+}
+
+func TestStatusOf(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, StatusOf(fmt.Errorf("get: %w", models.ErrCategoryNotFound)))
+	assert.Equal(t, http.StatusConflict, StatusOf(models.ErrDuplicateSKU))
+	assert.Equal(t, http.StatusUnprocessableEntity, StatusOf(&models.ValidationError{}))
+	assert.Equal(t, http.StatusLocked, StatusOf(fmt.Errorf("%w: product prod_1 is being written", models.ErrLockFailed)))
+	assert.Equal(t, http.StatusBadRequest, StatusOf(fmt.Errorf("%w: bad sort", models.ErrInvalidRequest)))
+	assert.Equal(t, http.StatusInternalServerError, StatusOf(errors.New("boom")))
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, CodeNotFound, StatusCode(http.StatusNotFound))
+	assert.Equal(t, CodeRateLimited, StatusCode(http.StatusTooManyRequests))
+	assert.Equal(t, CodeLocked, StatusCode(http.StatusLocked))
+	assert.Equal(t, "gateway_timeout", StatusCode(http.StatusGatewayTimeout))
+}
+
+func TestDomainCodesAreUnique(t *testing.T) {
+	seen := make(map[string]bool, len(domainCodes))
+	for _, domain := range domainCodes {
+		assert.False(t, seen[domain.code], "code %s is used twice", domain.code)
+		seen[domain.code] = true
+	}
+}
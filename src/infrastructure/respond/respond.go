@@ -0,0 +1,101 @@
+// Package respond writes the JSON responses of every handler. Resources are
+// written as they are; errors are always models.APIError, with the HTTP
+// status, a machine-readable code and a message, so clients can branch on
+// the code instead of parsing messages.
+package respond
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// maxPooledBufferSize keeps unusually large responses from pinning memory in the pool
+const maxPooledBufferSize = 1 << 20
+
+// jsonEncoder is a reusable encoder writing into its own buffer
+type jsonEncoder struct {
+	buf *bytes.Buffer
+	enc *json.Encoder
+}
+
+var encoderPool = sync.Pool{
+	New: func() interface{} {
+		buf := new(bytes.Buffer)
+		return &jsonEncoder{buf: buf, enc: json.NewEncoder(buf)}
+	},
+}
+
+// Encode writes v as JSON using a pooled encoder and buffer, so a value that
+// fails to encode writes nothing. Headers and status must be set by the caller.
+func Encode(w io.Writer, v interface{}) error {
+	e := encoderPool.Get().(*jsonEncoder)
+	defer func() {
+		if e.buf.Cap() <= maxPooledBufferSize {
+			e.buf.Reset()
+			encoderPool.Put(e)
+		}
+	}()
+
+	if err := e.enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := w.Write(e.buf.Bytes())
+	return err
+}
+
+// JSON writes v with the status
+func JSON(w http.ResponseWriter, status int, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return Encode(w, v)
+}
+
+// Error writes an error with the code of its status, e.g. not_found for 404
+func Error(w http.ResponseWriter, status int, message string) {
+	ErrorCode(w, status, StatusCode(status), message)
+}
+
+// ErrorCode writes an error with a code of its own, e.g. batch_too_large
+func ErrorCode(w http.ResponseWriter, status int, code, message string) {
+	JSON(w, status, models.NewAPIError(status, code, message))
+}
+
+// Failure writes the error a service returned with the code of its domain
+// error, e.g. product_not_found, falling back to the code of the status
+func Failure(w http.ResponseWriter, status int, err error, message string) {
+	code := CodeOf(err)
+	if code == "" {
+		code = StatusCode(status)
+	}
+	ErrorCode(w, status, code, message)
+}
+
+// ServiceError writes the error a service returned with the status of its
+// kind, see StatusOf. Validation errors list their fields. Errors of a kind
+// are written with their own message and any other with the message, which
+// should say what failed.
+func ServiceError(w http.ResponseWriter, err error, message string) {
+	var invalid *models.ValidationError
+	if errors.As(err, &invalid) {
+		ValidationFailed(w, invalid.Fields)
+		return
+	}
+	status := StatusOf(err)
+	if status != http.StatusInternalServerError {
+		message = err.Error()
+	}
+	Failure(w, status, err, message)
+}
+
+// ValidationFailed writes 422 Unprocessable Entity listing the failing fields
+func ValidationFailed(w http.ResponseWriter, fields []models.FieldError) {
+	body := models.NewAPIError(http.StatusUnprocessableEntity, CodeValidationFailed, "Validation failed")
+	body.Errors = fields
+	JSON(w, http.StatusUnprocessableEntity, body)
+}
@@ -0,0 +1,127 @@
+package respond
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func TestEncode(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		assert.NoError(t, Encode(w, map[string]int{"n": i}))
+
+		var decoded map[string]int
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &decoded))
+		assert.Equal(t, i, decoded["n"])
+	}
+}
+
+func TestEncodeErrorWritesNothing(t *testing.T) {
+	w := httptest.NewRecorder()
+	assert.Error(t, Encode(w, map[string]interface{}{"bad": make(chan int)}))
+	assert.Empty(t, w.Body.String())
+
+	// A failed encode does not leave data behind in the pooled buffer
+	w = httptest.NewRecorder()
+	assert.NoError(t, Encode(w, "ok"))
+	assert.Equal(t, `"ok"`, strings.TrimSpace(w.Body.String()))
+}
+
+func TestJSON(t *testing.T) {
+	w := httptest.NewRecorder()
+	assert.NoError(t, JSON(w, http.StatusCreated, map[string]string{"id": "prod_1"}))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id":"prod_1"}`, w.Body.String())
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w http.ResponseWriter)
+		code  int
+		body  string
+	}{
+		{
+			name:  "status code",
+			write: func(w http.ResponseWriter) { Error(w, http.StatusNotFound, "Route not found") },
+			code:  http.StatusNotFound,
+			body:  `{"code":404,"error_code":"not_found","message":"Route not found"}`,
+		},
+		{
+			name: "own code",
+			write: func(w http.ResponseWriter) {
+				ErrorCode(w, http.StatusBadRequest, CodeBatchTooLarge, "Too many products")
+			},
+			code: http.StatusBadRequest,
+			body: `{"code":400,"error_code":"batch_too_large","message":"Too many products"}`,
+		},
+		{
+			name: "wrapped domain error",
+			write: func(w http.ResponseWriter) {
+				Failure(w, http.StatusNotFound, fmt.Errorf("get prod_1: %w", models.ErrProductNotFound), "Product not found")
+			},
+			code: http.StatusNotFound,
+			body: `{"code":404,"error_code":"product_not_found","message":"Product not found"}`,
+		},
+		{
+			name: "other error",
+			write: func(w http.ResponseWriter) {
+				Failure(w, http.StatusInternalServerError, fmt.Errorf("disk full"), "Failed to save product")
+			},
+			code: http.StatusInternalServerError,
+			body: `{"code":500,"error_code":"internal_error","message":"Failed to save product"}`,
+		},
+		{
+			name: "validation",
+			write: func(w http.ResponseWriter) {
+				ValidationFailed(w, []models.FieldError{{Field: "sku", Tag: "required", Message: "sku is required"}})
+			},
+			code: http.StatusUnprocessableEntity,
+			body: `{"code":422,"error_code":"validation_failed","message":"Validation failed","errors":[{"field":"sku","tag":"required","message":"sku is required"}]}`,
+		},
+		{
+			name: "locked service error",
+			write: func(w http.ResponseWriter) {
+				ServiceError(w, fmt.Errorf("%w: product prod_1 is being written", models.ErrLockFailed), "Failed to update product")
+			},
+			code: http.StatusLocked,
+			body: `{"code":423,"error_code":"lock_failed","message":"failed to acquire lock: product prod_1 is being written"}`,
+		},
+		{
+			name: "internal service error",
+			write: func(w http.ResponseWriter) {
+				ServiceError(w, fmt.Errorf("disk full"), "Failed to update product")
+			},
+			code: http.StatusInternalServerError,
+			body: `{"code":500,"error_code":"internal_error","message":"Failed to update product"}`,
+		},
+		{
+			name: "validation service error",
+			write: func(w http.ResponseWriter) {
+				ServiceError(w, &models.ValidationError{Fields: []models.FieldError{{Field: "sku", Tag: "required", Message: "sku is required"}}}, "Failed to update product")
+			},
+			code: http.StatusUnprocessableEntity,
+			body: `{"code":422,"error_code":"validation_failed","message":"Validation failed","errors":[{"field":"sku","tag":"required","message":"sku is required"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.write(w)
+			assert.Equal(t, tt.code, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.body, w.Body.String())
+		})
+	}
+}
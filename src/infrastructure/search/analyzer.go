@@ -0,0 +1,161 @@
+// Package search indexes the text of products for full-text search: analyzers
+// turn text into the terms a query is matched against, kept in an in-memory
+// inverted index or in Elasticsearch/OpenSearch.
+package search
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Languages an analyzer can stem for. LanguageStandard only lowercases and
+// folds diacritics.
+const (
+	LanguageStandard  = "standard"
+	LanguageSwedish   = "swedish"
+	LanguageNorwegian = "norwegian"
+)
+
+// defaultLanguages are the analyzers of markets that are not configured
+var defaultLanguages = map[string]string{
+	"SE": LanguageSwedish,
+	"NO": LanguageNorwegian,
+}
+
+// foldings replaces letters with diacritics by their base letters, so a query
+// typed without them ("trojor") matches text written with them ("tröjor")
+var foldings = map[rune]string{
+	'å': "a", 'ä': "a", 'à': "a", 'á': "a", 'â': "a", 'ã': "a",
+	'æ': "ae",
+	'ç': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ñ': "n",
+	'ö': "o", 'ø': "o", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'ý': "y", 'ÿ': "y",
+	'ß': "ss",
+}
+
+// Analyzer splits text into terms. Terms are lowercased, stemmed with the
+// language's stemmer and then folded, in that order: the stemmers need the
+// diacritics to recognise suffixes, and folding last makes "tröjor" and
+// "trojor" the same term.
+type Analyzer struct {
+	Language       string
+	FoldDiacritics bool
+	stem           func(string) string
+}
+
+// NewAnalyzer creates an analyzer for a language that folds diacritics
+func NewAnalyzer(language string) (*Analyzer, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	analyzer := &Analyzer{Language: language, FoldDiacritics: true}
+	switch language {
+	case LanguageStandard:
+	case LanguageSwedish:
+		analyzer.stem = stemSwedish
+	case LanguageNorwegian:
+		analyzer.stem = stemNorwegian
+	default:
+		return nil, fmt.Errorf("unknown search language %q, expected %s, %s or %s", language, LanguageStandard, LanguageSwedish, LanguageNorwegian)
+	}
+	return analyzer, nil
+}
+
+// Analyze returns the terms of a text in order, repeats included
+func (a *Analyzer) Analyze(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		if a.stem != nil {
+			word = a.stem(word)
+		}
+		if a.FoldDiacritics {
+			word = fold(word)
+		}
+		terms = append(terms, word)
+	}
+	return terms
+}
+
+// fold replaces the diacritics of a lowercase word
+func fold(word string) string {
+	var b strings.Builder
+	b.Grow(len(word))
+	for _, r := range word {
+		if folded, ok := foldings[r]; ok {
+			b.WriteString(folded)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Analyzers holds the analyzer of every market
+type Analyzers struct {
+	markets  map[string]*Analyzer
+	fallback *Analyzer
+}
+
+// NewAnalyzers creates the analyzers of the markets, given as market to
+// language. Sweden and Norway stem in their languages unless configured
+// otherwise; every other market uses the standard analyzer.
+func NewAnalyzers(languages map[string]string) (*Analyzers, error) {
+	fallback, _ := NewAnalyzer(LanguageStandard)
+	analyzers := &Analyzers{markets: make(map[string]*Analyzer), fallback: fallback}
+
+	configured := make(map[string]string, len(defaultLanguages)+len(languages))
+	for market, language := range defaultLanguages {
+		configured[market] = language
+	}
+	for market, language := range languages {
+		configured[strings.ToUpper(strings.TrimSpace(market))] = language
+	}
+	for market, language := range configured {
+		analyzer, err := NewAnalyzer(language)
+		if err != nil {
+			return nil, fmt.Errorf("market %s: %v", market, err)
+		}
+		analyzers.markets[market] = analyzer
+	}
+	return analyzers, nil
+}
+
+// ParseAnalyzers parses per-market languages such as "SE:swedish,NO:norwegian,FI:standard"
+func ParseAnalyzers(value string) (*Analyzers, error) {
+	languages := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		market, language, ok := strings.Cut(entry, ":")
+		if !ok || strings.TrimSpace(market) == "" {
+			return nil, fmt.Errorf("invalid search analyzer %q, expected market:language", entry)
+		}
+		languages[market] = language
+	}
+	return NewAnalyzers(languages)
+}
+
+// For returns the analyzer of a market; an empty market gets the standard analyzer
+func (a *Analyzers) For(market string) *Analyzer {
+	if analyzer, ok := a.markets[strings.ToUpper(strings.TrimSpace(market))]; ok {
+		return analyzer
+	}
+	return a.fallback
+}
+
+// Markets returns the analyzer of every configured market
+func (a *Analyzers) Markets() map[string]*Analyzer {
+	markets := make(map[string]*Analyzer, len(a.markets))
+	for market, analyzer := range a.markets {
+		markets[market] = analyzer
+	}
+	return markets
+}
@@ -0,0 +1,52 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwedishAnalyzerMatchesWithoutDiacritics(t *testing.T) {
+	analyzer, err := NewAnalyzer("Swedish")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"troj"}, analyzer.Analyze("trojor"))
+	assert.Equal(t, []string{"troj"}, analyzer.Analyze("Tröjor"))
+	assert.Equal(t, []string{"troj"}, analyzer.Analyze("tröja"))
+	assert.Equal(t, []string{"bla", "byx", "i", "bomull"}, analyzer.Analyze("Blå byxor i bomull!"))
+}
+
+func TestStandardAnalyzer(t *testing.T) {
+	analyzer, err := NewAnalyzer(LanguageStandard)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"creme", "brulee", "300ml", "strasse"}, analyzer.Analyze("Crème Brûlée, 300ml — Straße"))
+
+	analyzer.FoldDiacritics = false
+	assert.Equal(t, []string{"crème"}, analyzer.Analyze("Crème"))
+
+	_, err = NewAnalyzer("klingon")
+	assert.Error(t, err)
+}
+
+func TestAnalyzersPerMarket(t *testing.T) {
+	analyzers, err := ParseAnalyzers("fi:standard, DK:norwegian,")
+	assert.NoError(t, err)
+
+	assert.Equal(t, LanguageSwedish, analyzers.For("se").Language)
+	assert.Equal(t, LanguageNorwegian, analyzers.For("NO").Language)
+	assert.Equal(t, LanguageNorwegian, analyzers.For("DK").Language)
+	assert.Equal(t, LanguageStandard, analyzers.For("FI").Language)
+	assert.Equal(t, LanguageStandard, analyzers.For("DE").Language)
+	assert.Equal(t, LanguageStandard, analyzers.For("").Language)
+	assert.Len(t, analyzers.Markets(), 4)
+
+	// Configuration overrides the defaults
+	analyzers, err = ParseAnalyzers("SE:standard")
+	assert.NoError(t, err)
+	assert.Equal(t, LanguageStandard, analyzers.For("SE").Language)
+
+	for _, invalid := range []string{"SE", ":swedish", "SE:latin"} {
+		_, err := ParseAnalyzers(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
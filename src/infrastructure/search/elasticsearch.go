@@ -0,0 +1,68 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// elasticsearchStemmers are the languages of the Elasticsearch snowball
+// filter matching the built-in stemmers
+var elasticsearchStemmers = map[string]string{
+	LanguageSwedish:   "Swedish",
+	LanguageNorwegian: "Norwegian",
+}
+
+// ElasticsearchAnalyzerName is the name of a market's analyzer in the index settings
+func ElasticsearchAnalyzerName(market string) string {
+	market = strings.ToLower(strings.TrimSpace(market))
+	if market == "" {
+		return "market_default"
+	}
+	return "market_" + market
+}
+
+// ElasticsearchSettings returns the "analysis" index settings that make
+// Elasticsearch or OpenSearch analyze text like the built-in analyzers: the
+// standard tokenizer, lowercase, the language's Snowball stemmer and then
+// ASCII folding. Text fields of a market use ElasticsearchAnalyzerName(market);
+// market_default covers everything else.
+func ElasticsearchSettings(analyzers *Analyzers) map[string]interface{} {
+	filters := make(map[string]interface{})
+	definitions := map[string]interface{}{
+		ElasticsearchAnalyzerName(""): elasticsearchAnalyzer(analyzers.fallback, filters),
+	}
+
+	markets := make([]string, 0, len(analyzers.markets))
+	for market := range analyzers.markets {
+		markets = append(markets, market)
+	}
+	sort.Strings(markets)
+	for _, market := range markets {
+		definitions[ElasticsearchAnalyzerName(market)] = elasticsearchAnalyzer(analyzers.markets[market], filters)
+	}
+
+	return map[string]interface{}{
+		"analysis": map[string]interface{}{
+			"filter":   filters,
+			"analyzer": definitions,
+		},
+	}
+}
+
+// elasticsearchAnalyzer defines one custom analyzer, adding the stemmer filter it needs
+func elasticsearchAnalyzer(analyzer *Analyzer, filters map[string]interface{}) map[string]interface{} {
+	chain := []string{"lowercase"}
+	if language, ok := elasticsearchStemmers[analyzer.Language]; ok {
+		name := analyzer.Language + "_stemmer"
+		filters[name] = map[string]interface{}{"type": "snowball", "language": language}
+		chain = append(chain, name)
+	}
+	if analyzer.FoldDiacritics {
+		chain = append(chain, "asciifolding")
+	}
+	return map[string]interface{}{
+		"type":      "custom",
+		"tokenizer": "standard",
+		"filter":    chain,
+	}
+}
@@ -0,0 +1,295 @@
+package search
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// DefaultIndexName is the Elasticsearch index products are stored in
+const DefaultIndexName = "products"
+
+// requestTimeout bounds a request to the cluster
+const requestTimeout = 10 * time.Second
+
+// ElasticsearchIndex keeps products in an Elasticsearch or OpenSearch index.
+// The texts of a market are stored under markets.<market> and analyzed with
+// the market's analyzer; searches without a market match every market.
+type ElasticsearchIndex struct {
+	url       string
+	index     string
+	username  string
+	password  string
+	analyzers *Analyzers
+	http      *http.Client
+}
+
+// elasticsearchDocument is a product as it is stored in the index
+type elasticsearchDocument struct {
+	SKUs        []string                          `json:"skus"`
+	Title       string                            `json:"title"`
+	Description string                            `json:"description,omitempty"`
+	Tags        []string                          `json:"tags,omitempty"`
+	Markets     map[string]elasticsearchMarketDoc `json:"markets,omitempty"`
+}
+
+// elasticsearchMarketDoc holds the texts of a market, including the base
+// title and description so they are analyzed in the market's language too
+type elasticsearchMarketDoc struct {
+	Title       []string `json:"title"`
+	Description []string `json:"description,omitempty"`
+	Keywords    string   `json:"keywords,omitempty"`
+}
+
+// elasticsearchResponse is the part of a search response the hits are read from
+type elasticsearchResponse struct {
+	Hits struct {
+		Total struct {
+			Value int `json:"value"`
+		} `json:"total"`
+		Hits []struct {
+			ID    string  `json:"_id"`
+			Score float64 `json:"_score"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// NewElasticsearchIndex creates an index client for the cluster at config.URL
+func NewElasticsearchIndex(config IndexConfig, analyzers *Analyzers) (*ElasticsearchIndex, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("elasticsearch search index needs a URL")
+	}
+	index := config.Index
+	if index == "" {
+		index = DefaultIndexName
+	}
+	return &ElasticsearchIndex{
+		url:       strings.TrimSuffix(config.URL, "/"),
+		index:     index,
+		username:  config.Username,
+		password:  config.Password,
+		analyzers: analyzers,
+		http:      &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+func (i *ElasticsearchIndex) Name() string {
+	return IndexElasticsearch
+}
+
+// EnsureIndex creates the index with the analysis settings and mappings
+// unless it exists. An existing index is left as it is.
+func (i *ElasticsearchIndex) EnsureIndex() error {
+	status, _, err := i.do(http.MethodHead, "/"+url.PathEscape(i.index), nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusOK {
+		return nil
+	}
+	body := map[string]interface{}{
+		"settings": ElasticsearchSettings(i.analyzers),
+		"mappings": ElasticsearchMappings(i.analyzers),
+	}
+	return i.expect(http.MethodPut, "/"+url.PathEscape(i.index), body, http.StatusOK)
+}
+
+func (i *ElasticsearchIndex) Index(document *models.SearchDocument) error {
+	stored := elasticsearchDocument{
+		SKUs:        document.SKUs,
+		Title:       document.Title,
+		Description: document.Description,
+		Tags:        document.Tags,
+		Markets:     make(map[string]elasticsearchMarketDoc, len(document.Markets)),
+	}
+	for market, text := range document.Markets {
+		stored.Markets[strings.ToLower(market)] = elasticsearchMarketDoc{
+			Title:       nonEmpty(document.Title, text.Title),
+			Description: nonEmpty(document.Description, text.Description),
+			Keywords:    text.Keywords,
+		}
+	}
+	return i.expect(http.MethodPut, i.documentPath(document.ProductID), stored, http.StatusOK, http.StatusCreated)
+}
+
+func (i *ElasticsearchIndex) Remove(productID string) error {
+	return i.expect(http.MethodDelete, i.documentPath(productID), nil, http.StatusOK, http.StatusNotFound)
+}
+
+func (i *ElasticsearchIndex) Search(query models.SearchQuery) (*models.SearchHits, error) {
+	status, body, err := i.do(http.MethodPost, "/"+url.PathEscape(i.index)+"/_search", ElasticsearchQuery(query))
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("elasticsearch search returned %d: %s", status, truncate(body))
+	}
+	var response elasticsearchResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("invalid elasticsearch response: %v", err)
+	}
+	hits := &models.SearchHits{Hits: make([]models.SearchHit, 0, len(response.Hits.Hits)), Total: response.Hits.Total.Value}
+	for _, hit := range response.Hits.Hits {
+		hits.Hits = append(hits.Hits, models.SearchHit{ProductID: hit.ID, Score: hit.Score})
+	}
+	return hits, nil
+}
+
+// ElasticsearchMappings returns the index mappings: shared fields use
+// market_default, and the fields of every configured market the market's
+// analyzer. Markets without an analyzer of their own fall back to a dynamic
+// template with market_default.
+func ElasticsearchMappings(analyzers *Analyzers) map[string]interface{} {
+	text := func(market string) map[string]interface{} {
+		return map[string]interface{}{"type": "text", "analyzer": ElasticsearchAnalyzerName(market)}
+	}
+	marketFields := func(market string) map[string]interface{} {
+		return map[string]interface{}{"properties": map[string]interface{}{
+			"title":       text(market),
+			"description": text(market),
+			"keywords":    text(market),
+		}}
+	}
+
+	markets := make(map[string]interface{})
+	for market := range analyzers.Markets() {
+		markets[strings.ToLower(market)] = marketFields(market)
+	}
+	return map[string]interface{}{
+		"dynamic_templates": []interface{}{
+			map[string]interface{}{"market_text": map[string]interface{}{
+				"path_match":         "markets.*",
+				"match_mapping_type": "string",
+				"mapping":            text(""),
+			}},
+		},
+		"properties": map[string]interface{}{
+			"skus":        text(""),
+			"title":       text(""),
+			"description": text(""),
+			"tags":        text(""),
+			"markets":     map[string]interface{}{"properties": markets},
+		},
+	}
+}
+
+// ElasticsearchQuery returns the search request of a query. The fields are
+// boosted like in the memory index and their scores summed.
+func ElasticsearchQuery(query models.SearchQuery) map[string]interface{} {
+	prefix := "markets.*."
+	fields := []string{
+		fmt.Sprintf("skus^%d", boostSKU),
+		fmt.Sprintf("tags^%d", boostTags),
+	}
+	if query.Market == "" {
+		fields = append(fields,
+			fmt.Sprintf("title^%d", boostTitle),
+			fmt.Sprintf("description^%d", boostDescription))
+	} else {
+		prefix = "markets." + strings.ToLower(query.Market) + "."
+	}
+	fields = append(fields,
+		fmt.Sprintf("%stitle^%d", prefix, boostTitle),
+		fmt.Sprintf("%skeywords^%d", prefix, boostKeywords),
+		fmt.Sprintf("%sdescription^%d", prefix, boostDescription))
+	sort.Strings(fields)
+
+	match := map[string]interface{}{"multi_match": map[string]interface{}{
+		"query":  query.Text,
+		"fields": fields,
+		"type":   "most_fields",
+	}}
+	if query.Market != "" {
+		// Only products with metadata for the market
+		match = map[string]interface{}{"bool": map[string]interface{}{
+			"must":   match,
+			"filter": map[string]interface{}{"exists": map[string]interface{}{"field": prefix + "title"}},
+		}}
+	}
+	return map[string]interface{}{
+		"query":            match,
+		"from":             (query.Page - 1) * query.PageSize,
+		"size":             query.PageSize,
+		"track_total_hits": true,
+		"_source":          false,
+	}
+}
+
+func (i *ElasticsearchIndex) documentPath(productID string) string {
+	return "/" + url.PathEscape(i.index) + "/_doc/" + url.PathEscape(productID)
+}
+
+// expect sends a request and fails unless one of the status codes is returned
+func (i *ElasticsearchIndex) expect(method, path string, body interface{}, expected ...int) error {
+	status, response, err := i.do(method, path, body)
+	if err != nil {
+		return err
+	}
+	for _, code := range expected {
+		if status == code {
+			return nil
+		}
+	}
+	return fmt.Errorf("elasticsearch %s %s returned %d: %s", method, path, status, truncate(response))
+}
+
+// do sends a request with a JSON body and returns the status and response body
+func (i *ElasticsearchIndex) do(method, path string, body interface{}) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	request, err := http.NewRequest(method, i.url+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if i.username != "" {
+		request.SetBasicAuth(i.username, i.password)
+	}
+	response, err := i.http.Do(request)
+	if err != nil {
+		return 0, nil, fmt.Errorf("elasticsearch %s %s failed: %v", method, path, err)
+	}
+	defer response.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(response.Body, 10<<20))
+	if err != nil {
+		return 0, nil, err
+	}
+	return response.StatusCode, data, nil
+}
+
+// nonEmpty returns the texts that are not empty
+func nonEmpty(texts ...string) []string {
+	var kept []string
+	for _, text := range texts {
+		if strings.TrimSpace(text) != "" {
+			kept = append(kept, text)
+		}
+	}
+	return kept
+}
+
+// truncate shortens a response body for an error message
+func truncate(body []byte) string {
+	const max = 512
+	text := strings.TrimSpace(string(body))
+	if len(text) > max {
+		text = text[:max] + "..."
+	}
+	return text
+}
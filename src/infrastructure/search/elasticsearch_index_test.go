@@ -0,0 +1,125 @@
+package search
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// recordedRequest is a request the fake cluster received
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   map[string]interface{}
+}
+
+func fakeCluster(t *testing.T, indexExists bool) (*httptest.Server, *[]recordedRequest) {
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request := recordedRequest{Method: r.Method, Path: r.URL.Path}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			assert.NoError(t, json.Unmarshal(data, &request.Body))
+		}
+		requests = append(requests, request)
+
+		switch {
+		case r.Method == http.MethodHead && !indexExists:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == "/products/_search":
+			w.Write([]byte(`{"hits":{"total":{"value":7},"hits":[{"_id":"shirt","_score":2.5},{"_id":"socks","_score":1.25}]}}`))
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	return server, &requests
+}
+
+func TestElasticsearchIndexCreatesMissingIndex(t *testing.T) {
+	server, requests := fakeCluster(t, false)
+	defer server.Close()
+	analyzers, _ := NewAnalyzers(nil)
+
+	_, err := NewIndex(IndexConfig{Name: IndexElasticsearch, URL: server.URL}, analyzers)
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 2)
+	created := (*requests)[1]
+	assert.Equal(t, http.MethodPut, created.Method)
+	assert.Equal(t, "/products", created.Path)
+	assert.Contains(t, created.Body, "settings")
+
+	mappings := created.Body["mappings"].(map[string]interface{})
+	markets := mappings["properties"].(map[string]interface{})["markets"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, "market_se", markets["se"].(map[string]interface{})["properties"].(map[string]interface{})["title"].(map[string]interface{})["analyzer"])
+
+	// An existing index is kept
+	server, requests = fakeCluster(t, true)
+	defer server.Close()
+	_, err = NewIndex(IndexConfig{Name: "opensearch", URL: server.URL, Index: "catalog"}, analyzers)
+	assert.NoError(t, err)
+	assert.Len(t, *requests, 1)
+	assert.Equal(t, "/catalog", (*requests)[0].Path)
+}
+
+func TestElasticsearchIndex(t *testing.T) {
+	server, requests := fakeCluster(t, true)
+	defer server.Close()
+	analyzers, _ := NewAnalyzers(nil)
+	index, err := NewElasticsearchIndex(IndexConfig{URL: server.URL + "/"}, analyzers)
+	assert.NoError(t, err)
+
+	assert.NoError(t, index.Index(&models.SearchDocument{
+		ProductID: "shirt",
+		SKUs:      []string{"SHIRT-1"},
+		Title:     "Blue shirt",
+		Markets:   map[string]models.MarketText{"SE": {Title: "Blå skjorta", Keywords: "skjortor"}},
+	}))
+	stored := (*requests)[0]
+	assert.Equal(t, "/products/_doc/shirt", stored.Path)
+	assert.Equal(t, map[string]interface{}{"title": []interface{}{"Blue shirt", "Blå skjorta"}, "keywords": "skjortor"},
+		stored.Body["markets"].(map[string]interface{})["se"])
+
+	// Removing a product that is not indexed succeeds
+	assert.NoError(t, index.Remove("missing"))
+
+	hits, err := index.Search(models.SearchQuery{Text: "blue", Market: "SE", Page: 2, PageSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, &models.SearchHits{Total: 7, Hits: []models.SearchHit{{ProductID: "shirt", Score: 2.5}, {ProductID: "socks", Score: 1.25}}}, hits)
+	search := (*requests)[2].Body
+	assert.Equal(t, 2.0, search["from"])
+	filter := search["query"].(map[string]interface{})["bool"].(map[string]interface{})["filter"]
+	assert.Equal(t, map[string]interface{}{"exists": map[string]interface{}{"field": "markets.se.title"}}, filter)
+}
+
+func TestElasticsearchQueryFields(t *testing.T) {
+	query := ElasticsearchQuery(models.SearchQuery{Text: "blue", Page: 1, PageSize: 10})
+	match := query["query"].(map[string]interface{})["multi_match"].(map[string]interface{})
+	assert.Equal(t, []string{
+		"description^1", "markets.*.description^1", "markets.*.keywords^2", "markets.*.title^3", "skus^5", "tags^2", "title^3",
+	}, match["fields"])
+
+	query = ElasticsearchQuery(models.SearchQuery{Text: "blue", Market: "SE", Page: 1, PageSize: 10})
+	match = query["query"].(map[string]interface{})["bool"].(map[string]interface{})["must"].(map[string]interface{})["multi_match"].(map[string]interface{})
+	assert.Equal(t, []string{
+		"markets.se.description^1", "markets.se.keywords^2", "markets.se.title^3", "skus^5", "tags^2",
+	}, match["fields"])
+}
+
+func TestNewIndex(t *testing.T) {
+	analyzers, _ := NewAnalyzers(nil)
+	index, err := NewIndex(IndexConfig{}, analyzers)
+	assert.NoError(t, err)
+	assert.Equal(t, IndexMemory, index.Name())
+
+	_, err = NewIndex(IndexConfig{Name: IndexElasticsearch}, analyzers)
+	assert.Error(t, err)
+	_, err = NewIndex(IndexConfig{Name: "solr"}, analyzers)
+	assert.Error(t, err)
+}
@@ -0,0 +1,35 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElasticsearchSettings(t *testing.T) {
+	analyzers, err := ParseAnalyzers("FI:standard")
+	assert.NoError(t, err)
+
+	settings := ElasticsearchSettings(analyzers)
+	analysis := settings["analysis"].(map[string]interface{})
+
+	assert.Equal(t, map[string]interface{}{
+		"swedish_stemmer":   map[string]interface{}{"type": "snowball", "language": "Swedish"},
+		"norwegian_stemmer": map[string]interface{}{"type": "snowball", "language": "Norwegian"},
+	}, analysis["filter"])
+
+	definitions := analysis["analyzer"].(map[string]interface{})
+	assert.Len(t, definitions, 4)
+	assert.Equal(t, map[string]interface{}{
+		"type":      "custom",
+		"tokenizer": "standard",
+		"filter":    []string{"lowercase", "swedish_stemmer", "asciifolding"},
+	}, definitions["market_se"])
+	assert.Equal(t, []string{"lowercase", "asciifolding"}, definitions["market_fi"].(map[string]interface{})["filter"])
+	assert.Equal(t, []string{"lowercase", "asciifolding"}, definitions["market_default"].(map[string]interface{})["filter"])
+}
+
+func TestElasticsearchAnalyzerName(t *testing.T) {
+	assert.Equal(t, "market_se", ElasticsearchAnalyzerName(" SE "))
+	assert.Equal(t, "market_default", ElasticsearchAnalyzerName(""))
+}
@@ -0,0 +1,35 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
+)
+
+// IndexConfig selects and configures a search index
+type IndexConfig struct {
+	Name     string // memory (default) or elasticsearch, which covers OpenSearch too
+	URL      string // Elasticsearch or OpenSearch URL, e.g. http://localhost:9200
+	Index    string // Index name, "products" by default
+	Username string // Basic auth credentials, if the cluster needs them
+	Password string
+}
+
+// NewIndex creates the configured index. An Elasticsearch index is created
+// with the analyzers' settings when it does not exist yet.
+func NewIndex(config IndexConfig, analyzers *Analyzers) (interfaces.SearchIndex, error) {
+	switch config.Name {
+	case "", IndexMemory:
+		return NewMemoryIndex(analyzers), nil
+	case IndexElasticsearch, "opensearch":
+		index, err := NewElasticsearchIndex(config, analyzers)
+		if err != nil {
+			return nil, err
+		}
+		if err := index.EnsureIndex(); err != nil {
+			return nil, err
+		}
+		return index, nil
+	}
+	return nil, fmt.Errorf("unknown search index %q, expected memory or elasticsearch", config.Name)
+}
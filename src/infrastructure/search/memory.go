@@ -0,0 +1,232 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// Index names
+const (
+	IndexMemory        = "memory"
+	IndexElasticsearch = "elasticsearch"
+)
+
+// Field boosts: a term in the SKU counts five times a term in the description
+const (
+	boostSKU         = 5
+	boostTitle       = 3
+	boostKeywords    = 2
+	boostTags        = 2
+	boostDescription = 1
+)
+
+// Okapi BM25 parameters: k1 bounds how much repeating a term adds, b how
+// much longer documents are penalized
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// allMarkets keys the texts of every market, searched without a market
+const allMarkets = ""
+
+// MemoryIndex is an inverted index kept in memory. Each market the product
+// has metadata for is indexed with the market's analyzer, and all texts
+// together with the standard analyzer for searches without a market.
+// Matches are ranked with BM25 over boosted term frequencies.
+type MemoryIndex struct {
+	analyzers *Analyzers
+
+	mu      sync.RWMutex
+	markets map[string]*marketIndex
+	indexed map[string][]string // Markets each product is indexed in
+}
+
+// marketIndex holds the postings of one market
+type marketIndex struct {
+	postings    map[string]map[string]float64 // Term to product to boosted frequency
+	documents   map[string]map[string]float64 // Product to term to boosted frequency
+	lengths     map[string]float64            // Boosted number of terms per product
+	totalLength float64
+}
+
+// NewMemoryIndex creates an empty index analyzing text with the analyzers
+func NewMemoryIndex(analyzers *Analyzers) *MemoryIndex {
+	return &MemoryIndex{
+		analyzers: analyzers,
+		markets:   make(map[string]*marketIndex),
+		indexed:   make(map[string][]string),
+	}
+}
+
+func (i *MemoryIndex) Name() string {
+	return IndexMemory
+}
+
+func (i *MemoryIndex) Index(document *models.SearchDocument) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(document.ProductID)
+
+	all := i.analyzers.fallback
+	terms := documentTerms(all, document)
+	for _, text := range document.Markets {
+		addMarketTerms(terms, all, text)
+	}
+	i.add(allMarkets, document.ProductID, terms)
+	markets := []string{allMarkets}
+
+	for market, text := range document.Markets {
+		analyzer := i.analyzers.For(market)
+		terms := documentTerms(analyzer, document)
+		addMarketTerms(terms, analyzer, text)
+		i.add(market, document.ProductID, terms)
+		markets = append(markets, market)
+	}
+	i.indexed[document.ProductID] = markets
+	return nil
+}
+
+func (i *MemoryIndex) Remove(productID string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.remove(productID)
+	return nil
+}
+
+func (i *MemoryIndex) Search(query models.SearchQuery) (*models.SearchHits, error) {
+	analyzer := i.analyzers.fallback
+	if query.Market != allMarkets {
+		analyzer = i.analyzers.For(query.Market)
+	}
+	terms := uniqueTerms(analyzer.Analyze(query.Text))
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	index, ok := i.markets[query.Market]
+	if !ok || len(index.lengths) == 0 {
+		return &models.SearchHits{Hits: []models.SearchHit{}}, nil
+	}
+
+	count := float64(len(index.lengths))
+	averageLength := index.totalLength / count
+	scores := make(map[string]float64)
+	for _, term := range terms {
+		postings := index.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		df := float64(len(postings))
+		idf := math.Log(1 + (count-df+0.5)/(df+0.5))
+		for productID, tf := range postings {
+			norm := bm25K1 * (1 - bm25B + bm25B*index.lengths[productID]/averageLength)
+			scores[productID] += idf * tf * (bm25K1 + 1) / (tf + norm)
+		}
+	}
+
+	hits := make([]models.SearchHit, 0, len(scores))
+	for productID, score := range scores {
+		hits = append(hits, models.SearchHit{ProductID: productID, Score: math.Round(score*1e4) / 1e4})
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].ProductID < hits[b].ProductID
+	})
+	return &models.SearchHits{Hits: pageOf(hits, query.Page, query.PageSize), Total: len(hits)}, nil
+}
+
+// add stores the terms of a product in a market
+func (i *MemoryIndex) add(market, productID string, terms map[string]float64) {
+	index, ok := i.markets[market]
+	if !ok {
+		index = &marketIndex{
+			postings:  make(map[string]map[string]float64),
+			documents: make(map[string]map[string]float64),
+			lengths:   make(map[string]float64),
+		}
+		i.markets[market] = index
+	}
+	length := 0.0
+	for term, frequency := range terms {
+		if index.postings[term] == nil {
+			index.postings[term] = make(map[string]float64)
+		}
+		index.postings[term][productID] = frequency
+		length += frequency
+	}
+	index.documents[productID] = terms
+	index.lengths[productID] = length
+	index.totalLength += length
+}
+
+// remove drops a product from every market it is indexed in
+func (i *MemoryIndex) remove(productID string) {
+	for _, market := range i.indexed[productID] {
+		index := i.markets[market]
+		for term := range index.documents[productID] {
+			delete(index.postings[term], productID)
+			if len(index.postings[term]) == 0 {
+				delete(index.postings, term)
+			}
+		}
+		index.totalLength -= index.lengths[productID]
+		delete(index.documents, productID)
+		delete(index.lengths, productID)
+	}
+	delete(i.indexed, productID)
+}
+
+// documentTerms analyzes the texts every market shares
+func documentTerms(analyzer *Analyzer, document *models.SearchDocument) map[string]float64 {
+	terms := make(map[string]float64)
+	addTerms(terms, analyzer, strings.Join(document.SKUs, " "), boostSKU)
+	addTerms(terms, analyzer, document.Title, boostTitle)
+	addTerms(terms, analyzer, strings.Join(document.Tags, " "), boostTags)
+	addTerms(terms, analyzer, document.Description, boostDescription)
+	return terms
+}
+
+// addMarketTerms analyzes the texts of a market
+func addMarketTerms(terms map[string]float64, analyzer *Analyzer, text models.MarketText) {
+	addTerms(terms, analyzer, text.Title, boostTitle)
+	addTerms(terms, analyzer, text.Keywords, boostKeywords)
+	addTerms(terms, analyzer, text.Description, boostDescription)
+}
+
+func addTerms(terms map[string]float64, analyzer *Analyzer, text string, boost float64) {
+	for _, term := range analyzer.Analyze(text) {
+		terms[term] += boost
+	}
+}
+
+// uniqueTerms drops repeated query terms, so repeating a word does not weigh it more
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	unique := terms[:0]
+	for _, term := range terms {
+		if !seen[term] {
+			seen[term] = true
+			unique = append(unique, term)
+		}
+	}
+	return unique
+}
+
+// pageOf returns a page of hits; pages start at 1
+func pageOf(hits []models.SearchHit, page, pageSize int) []models.SearchHit {
+	start := (page - 1) * pageSize
+	if start >= len(hits) || start < 0 {
+		return []models.SearchHit{}
+	}
+	end := start + pageSize
+	if end > len(hits) {
+		end = len(hits)
+	}
+	return hits[start:end]
+}
@@ -0,0 +1,81 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+func testMemoryIndex(t *testing.T) *MemoryIndex {
+	analyzers, err := NewAnalyzers(nil)
+	assert.NoError(t, err)
+	index := NewMemoryIndex(analyzers)
+	documents := []*models.SearchDocument{
+		{ProductID: "shirt", SKUs: []string{"SHIRT-1"}, Title: "Blue shirt", Description: "A cotton shirt",
+			Markets: map[string]models.MarketText{"SE": {Title: "Blå skjorta", Keywords: "tröjor"}}},
+		{ProductID: "sweater", SKUs: []string{"SW-2", "SW-2-XL"}, Title: "Wool sweater", Description: "Goes well with a blue shirt",
+			Markets: map[string]models.MarketText{"SE": {Title: "Ulltröja"}, "DE": {Title: "Wollpullover"}}},
+		{ProductID: "socks", SKUs: []string{"SOCK-3"}, Title: "Socks", Tags: []string{"blue"}},
+	}
+	for _, document := range documents {
+		assert.NoError(t, index.Index(document))
+	}
+	return index
+}
+
+func searchIDs(t *testing.T, index *MemoryIndex, query models.SearchQuery) []string {
+	if query.Page == 0 {
+		query.Page, query.PageSize = 1, 10
+	}
+	hits, err := index.Search(query)
+	assert.NoError(t, err)
+	ids := make([]string, 0, len(hits.Hits))
+	for _, hit := range hits.Hits {
+		ids = append(ids, hit.ProductID)
+	}
+	return ids
+}
+
+func TestMemoryIndexRanksByRelevance(t *testing.T) {
+	index := testMemoryIndex(t)
+
+	// The title outweighs the tag and the description
+	assert.Equal(t, []string{"shirt", "socks", "sweater"}, searchIDs(t, index, models.SearchQuery{Text: "blue"}))
+	// Any word matches, and products matching more of them rank higher
+	assert.Equal(t, []string{"shirt", "sweater", "socks"}, searchIDs(t, index, models.SearchQuery{Text: "blue shirt"}))
+	assert.Equal(t, []string{"sweater"}, searchIDs(t, index, models.SearchQuery{Text: "sw-2-xl"}))
+	assert.Empty(t, searchIDs(t, index, models.SearchQuery{Text: "trousers"}))
+
+	hits, err := index.Search(models.SearchQuery{Text: "blue", Page: 2, PageSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, hits.Total)
+	assert.Len(t, hits.Hits, 1)
+}
+
+func TestMemoryIndexMarkets(t *testing.T) {
+	index := testMemoryIndex(t)
+
+	// Swedish texts are stemmed, so the plural matches the singular
+	assert.Equal(t, []string{"shirt"}, searchIDs(t, index, models.SearchQuery{Text: "skjortor", Market: "SE"}))
+	assert.Equal(t, []string{"shirt"}, searchIDs(t, index, models.SearchQuery{Text: "trojor", Market: "SE"}))
+	// Only products with metadata for the market are found in it
+	assert.Equal(t, []string{"sweater"}, searchIDs(t, index, models.SearchQuery{Text: "wool", Market: "DE"}))
+	assert.Empty(t, searchIDs(t, index, models.SearchQuery{Text: "socks", Market: "DE"}))
+	assert.Empty(t, searchIDs(t, index, models.SearchQuery{Text: "socks", Market: "FI"}))
+	// Without a market every market's texts are searched
+	assert.Equal(t, []string{"sweater"}, searchIDs(t, index, models.SearchQuery{Text: "wollpullover"}))
+}
+
+func TestMemoryIndexReplaceAndRemove(t *testing.T) {
+	index := testMemoryIndex(t)
+
+	assert.NoError(t, index.Index(&models.SearchDocument{ProductID: "shirt", SKUs: []string{"SHIRT-1"}, Title: "Red shirt"}))
+	assert.Equal(t, []string{"socks", "sweater"}, searchIDs(t, index, models.SearchQuery{Text: "blue"}))
+	assert.Empty(t, searchIDs(t, index, models.SearchQuery{Text: "skjorta", Market: "SE"}))
+
+	assert.NoError(t, index.Remove("socks"))
+	assert.NoError(t, index.Remove("missing"))
+	assert.Equal(t, []string{"sweater"}, searchIDs(t, index, models.SearchQuery{Text: "blue"}))
+}
@@ -0,0 +1,128 @@
+package search
+
+import "strings"
+
+// The stemmers are the Snowball stemmers for Swedish and Norwegian (bokmål).
+// They strip inflections so "tröja", "tröjor" and "tröjorna" share one term.
+// See https://snowballstem.org/algorithms/ for the steps.
+
+var (
+	swedishVowels   = "aeiouyäåö"
+	norwegianVowels = "aeiouyæåø"
+
+	swedishStep1 = []string{
+		"heterna", "hetens", "anden", "heten", "heter", "arnas", "ernas", "ornas", "andes", "arens", "andet",
+		"arna", "erna", "orna", "ande", "arne", "aste", "aren", "ades", "erns",
+		"ade", "are", "ern", "ens", "het", "ast",
+		"ad", "en", "ar", "er", "or", "as", "es", "at",
+		"a", "e",
+	}
+	swedishSEndings = "bcdfghjklmnoprtvy"
+	swedishStep2    = []string{"dd", "gd", "nn", "dt", "gt", "kt", "tt"}
+
+	norwegianStep1 = []string{
+		"hetenes", "hetene", "hetens", "heten", "heter", "endes", "enes", "edes",
+		"ande", "ende", "ede", "ane", "ene", "ens", "ers", "ets", "het", "ast",
+		"en", "ar", "er", "as", "es", "et",
+		"a", "e",
+	}
+	norwegianSEndings = "bcdfghjlmnoprtvyz"
+	norwegianStep3    = []string{"hetslov", "eleg", "elig", "elov", "slov", "leg", "eig", "lig", "els", "lov", "ig"}
+)
+
+// stemSwedish returns the stem of a lowercase Swedish word
+func stemSwedish(word string) string {
+	w := []rune(word)
+	r1 := region1(w, swedishVowels)
+
+	// Step 1: inflectional endings
+	if suffix := longestSuffix(w, r1, swedishStep1); suffix != "" {
+		w = w[:len(w)-len([]rune(suffix))]
+	} else if len(w) > r1 && w[len(w)-1] == 's' && len(w) >= 2 && strings.ContainsRune(swedishSEndings, w[len(w)-2]) {
+		w = w[:len(w)-1]
+	}
+
+	// Step 2: double consonants left by step 1
+	if longestSuffix(w, r1, swedishStep2) != "" {
+		w = w[:len(w)-1]
+	}
+
+	// Step 3: derivational endings
+	switch {
+	case longestSuffix(w, r1, []string{"löst"}) != "":
+		w = w[:len(w)-1]
+	case longestSuffix(w, r1, []string{"fullt"}) != "":
+		w = w[:len(w)-1]
+	default:
+		if suffix := longestSuffix(w, r1, []string{"lig", "els", "ig"}); suffix != "" {
+			w = w[:len(w)-len([]rune(suffix))]
+		}
+	}
+	return string(w)
+}
+
+// stemNorwegian returns the stem of a lowercase Norwegian word
+func stemNorwegian(word string) string {
+	w := []rune(word)
+	r1 := region1(w, norwegianVowels)
+
+	// Step 1: inflectional endings
+	switch suffix := longestSuffix(w, r1, append([]string{"erte", "ert"}, norwegianStep1...)); {
+	case suffix == "erte" || suffix == "ert":
+		w = append(w[:len(w)-len(suffix)], 'e', 'r')
+	case suffix != "":
+		w = w[:len(w)-len([]rune(suffix))]
+	case len(w) > r1 && w[len(w)-1] == 's' && len(w) >= 2 && norwegianSEnding(w[:len(w)-1]):
+		w = w[:len(w)-1]
+	}
+
+	// Step 2: dt and vt lose the t
+	if longestSuffix(w, r1, []string{"dt", "vt"}) != "" {
+		w = w[:len(w)-1]
+	}
+
+	// Step 3: derivational endings
+	if suffix := longestSuffix(w, r1, norwegianStep3); suffix != "" {
+		w = w[:len(w)-len([]rune(suffix))]
+	}
+	return string(w)
+}
+
+// norwegianSEnding reports whether a word without its final s may lose it
+func norwegianSEnding(w []rune) bool {
+	last := w[len(w)-1]
+	if strings.ContainsRune(norwegianSEndings, last) {
+		return true
+	}
+	return last == 'k' && (len(w) < 2 || !strings.ContainsRune(norwegianVowels, w[len(w)-2]))
+}
+
+// region1 returns where R1 starts: after the first non-vowel that follows a
+// vowel, but with at least three letters before it
+func region1(w []rune, vowels string) int {
+	for i := 1; i < len(w); i++ {
+		if !strings.ContainsRune(vowels, w[i]) && strings.ContainsRune(vowels, w[i-1]) {
+			if i+1 < 3 {
+				return 3
+			}
+			return i + 1
+		}
+	}
+	return len(w)
+}
+
+// longestSuffix returns the longest of the suffixes that the word ends with
+// inside R1, or the empty string
+func longestSuffix(w []rune, r1 int, suffixes []string) string {
+	longest := ""
+	for _, suffix := range suffixes {
+		length := len([]rune(suffix))
+		if length <= len([]rune(longest)) || len(w)-length < r1 {
+			continue
+		}
+		if string(w[len(w)-length:]) == suffix {
+			longest = suffix
+		}
+	}
+	return longest
+}
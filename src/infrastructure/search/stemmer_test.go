@@ -0,0 +1,44 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStemSwedish(t *testing.T) {
+	// Expected stems follow the Snowball algorithm, which removes one ending per step
+	for word, stem := range map[string]string{
+		"tröja":      "tröj",
+		"tröjor":     "tröj",
+		"tröjorna":   "tröj",
+		"klänningar": "klänning",
+		"jacka":      "jack",
+		"jackor":     "jack",
+		"skor":       "skor",
+		"byxornas":   "byx",
+		"vänlig":     "vän",
+		"bädds":      "bädd",
+		"kraftlöst":  "kraftlös",
+		"ut":         "ut",
+	} {
+		assert.Equal(t, stem, stemSwedish(word), word)
+	}
+}
+
+func TestStemNorwegian(t *testing.T) {
+	for word, stem := range map[string]string{
+		"genser":     "gens",
+		"jakke":      "jakk",
+		"jakken":     "jakk",
+		"jakkene":    "jakk",
+		"bukser":     "buks",
+		"kjolene":    "kjol",
+		"hyttene":    "hytt",
+		"rettferdig": "rettferd",
+		"opplevert":  "opplever",
+		"ut":         "ut",
+	} {
+		assert.Equal(t, stem, stemNorwegian(word), word)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build otlp
+
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// newOTLPExporter creates an exporter that sends spans over OTLP/HTTP to
+// endpoint, a host and port such as "collector:4318", or to the collector in
+// OTEL_EXPORTER_OTLP_ENDPOINT when it is empty
+func newOTLPExporter(endpoint string) (tracesdk.SpanExporter, error) {
+	var options []otlptracehttp.Option
+	if endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpoint(endpoint))
+	}
+	return otlptracehttp.New(context.Background(), options...)
+}
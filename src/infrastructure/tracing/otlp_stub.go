@@ -0,0 +1,17 @@
+//go:build !otlp
+
+package tracing
+
+import (
+	"errors"
+
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ErrOTLPNotLinked is returned when the binary was built without -tags otlp
+var ErrOTLPNotLinked = errors.New("built without the OTLP exporter: add go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp and build with -tags otlp")
+
+// newOTLPExporter fails: no OTLP exporter is linked
+func newOTLPExporter(endpoint string) (tracesdk.SpanExporter, error) {
+	return nil, ErrOTLPNotLinked
+}
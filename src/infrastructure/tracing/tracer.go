@@ -1,30 +1,112 @@
 package tracing
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/jaeger"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	tracesdk "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// InitTracer initializes a new tracer
-func InitTracer(serviceName string) (*tracesdk.TracerProvider, error) {
-	exporter, err := jaeger.New(jaeger.WithCollectorEndpoint())
-	if err != nil {
+// instrumentationName names the tracer the service's spans are created with
+const instrumentationName = "github.com/jimmitjoo/ecom"
+
+// Exporters spans can be sent with
+const (
+	ExporterNone   = "none"
+	ExporterJaeger = "jaeger"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config selects where spans are exported to
+type Config struct {
+	// Exporter is one of the Exporter constants; empty disables tracing
+	Exporter string
+	// Endpoint is the collector to export to: the Jaeger collector URL, or the
+	// OTLP/HTTP host and port. Empty uses the exporter's default, which can
+	// also be set with the exporter's own environment variables.
+	Endpoint    string
+	ServiceName string
+	Environment string
+	// SampleRatio is the share of new traces that are recorded, between 0 and
+	// 1; 0 records every trace. Requests that carry a sampled parent are
+	// always recorded.
+	SampleRatio float64
+}
+
+// InitTracer creates the tracer provider for the configured exporter and
+// installs it, together with W3C trace context propagation, as the global
+// provider. It returns nil without an exporter; spans are then not recorded.
+func InitTracer(config Config) (*tracesdk.TracerProvider, error) {
+	exporter, err := newExporter(config)
+	if err != nil || exporter == nil {
 		return nil, err
 	}
 
+	ratio := config.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
 	tp := tracesdk.NewTracerProvider(
 		tracesdk.WithBatcher(exporter),
+		tracesdk.WithSampler(tracesdk.ParentBased(tracesdk.TraceIDRatioBased(ratio))),
 		tracesdk.WithResource(resource.NewWithAttributes(
 			semconv.SchemaURL,
-			semconv.ServiceNameKey.String(serviceName),
-			attribute.String("environment", "production"),
+			semconv.ServiceNameKey.String(config.ServiceName),
+			attribute.String("environment", config.Environment),
 		)),
 	)
 
 	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
 	return tp, nil
 }
+
+// newExporter creates the span exporter a configuration names
+func newExporter(config Config) (tracesdk.SpanExporter, error) {
+	switch config.Exporter {
+	case "", ExporterNone:
+		return nil, nil
+	case ExporterJaeger:
+		var options []jaeger.CollectorEndpointOption
+		if config.Endpoint != "" {
+			options = append(options, jaeger.WithEndpoint(config.Endpoint))
+		}
+		return jaeger.New(jaeger.WithCollectorEndpoint(options...))
+	case ExporterOTLP:
+		return newOTLPExporter(config.Endpoint)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", config.Exporter)
+	}
+}
+
+// Tracer returns the tracer of the global provider the service's spans are created with
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span as a child of the span in ctx, if any
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err on the span, if it is not nil, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -2,13 +2,16 @@ package tracing
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -124,3 +127,38 @@ func TestTracerSpanHierarchy(t *testing.T) {
 	assert.NotEqual(t, parentContext.SpanID(), childContext.SpanID())
 	assert.Equal(t, parentContext.TraceID(), childContext.TraceID())
 }
+
+func TestInitTracerExporters(t *testing.T) {
+	tp, err := InitTracer(Config{})
+	assert.NoError(t, err)
+	assert.Nil(t, tp)
+
+	_, err = InitTracer(Config{Exporter: "zipkin"})
+	assert.ErrorContains(t, err, "unknown trace exporter")
+
+	tp, err = InitTracer(Config{Exporter: ExporterStdout, ServiceName: "test-service", SampleRatio: 0.5})
+	assert.NoError(t, err)
+	assert.NotNil(t, tp)
+	assert.Same(t, tp, otel.GetTracerProvider())
+	assert.NoError(t, tp.Shutdown(context.Background()))
+}
+
+func TestStartAndEnd(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	otel.SetTracerProvider(tp)
+	defer tp.Shutdown(context.Background())
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child", attribute.String("product.id", "prod_1"))
+	End(child, errors.New("lock taken"))
+	End(parent, nil)
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "child", spans[0].Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Contains(t, spans[0].Attributes, attribute.String("product.id", "prod_1"))
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+}
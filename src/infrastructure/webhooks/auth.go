@@ -0,0 +1,238 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jimmitjoo/ecom/src/domain/models"
+)
+
+// AuthOAuth2ClientCredentials fetches bearer tokens with the OAuth2 client credentials grant
+const AuthOAuth2ClientCredentials = "oauth2_client_credentials"
+
+const (
+	// tokenRefreshMargin renews a cached token this long before it expires so a
+	// delivery never carries a token that runs out in flight
+	tokenRefreshMargin = 30 * time.Second
+	// defaultTokenLifetime is assumed when a token response has no expires_in
+	defaultTokenLifetime = 5 * time.Minute
+	// tokenRequestTimeout bounds a single call to a token endpoint
+	tokenRequestTimeout = 10 * time.Second
+)
+
+// TokenProvider supplies the bearer token attached to deliveries for one subscription
+type TokenProvider interface {
+	// Token returns a valid token, fetching a new one when needed
+	Token() (string, error)
+	// Invalidate drops the cached token, e.g. after the receiver rejected it
+	Invalidate()
+}
+
+// AuthConfig describes how deliveries to a receiver are authenticated
+type AuthConfig = models.WebhookAuth
+
+// TokenProviderFactory creates a token provider from a subscription's auth configuration
+type TokenProviderFactory func(config AuthConfig) (TokenProvider, error)
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]TokenProviderFactory{
+		AuthOAuth2ClientCredentials: func(config AuthConfig) (TokenProvider, error) {
+			return NewClientCredentials(config)
+		},
+	}
+)
+
+// RegisterTokenProvider makes an auth type available to subscriptions, e.g. for
+// receivers that expect tokens from a cloud provider's metadata service
+func RegisterTokenProvider(authType string, factory TokenProviderFactory) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[authType] = factory
+}
+
+// NewTokenProvider creates the token provider for an auth configuration
+func NewTokenProvider(config AuthConfig) (TokenProvider, error) {
+	providersMu.RLock()
+	factory, exists := providers[config.Type]
+	providersMu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("unknown webhook auth type %q", config.Type)
+	}
+	return factory(config)
+}
+
+// ClientCredentials obtains tokens from an OAuth2 token endpoint and caches them
+// until shortly before they expire. Concurrent deliveries share one token request.
+type ClientCredentials struct {
+	config AuthConfig
+	http   *http.Client
+	now    func() time.Time
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewClientCredentials creates a token provider for the client credentials grant
+func NewClientCredentials(config AuthConfig) (*ClientCredentials, error) {
+	if config.TokenURL == "" || config.ClientID == "" {
+		return nil, fmt.Errorf("client credentials auth requires token_url and client_id")
+	}
+	return &ClientCredentials{
+		config: config,
+		http:   &http.Client{Timeout: tokenRequestTimeout},
+		now:    time.Now,
+	}, nil
+}
+
+// Token returns the cached token, or requests a new one once it is about to expire
+func (c *ClientCredentials) Token() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && c.now().Before(c.expires.Add(-tokenRefreshMargin)) {
+		return c.token, nil
+	}
+
+	token, lifetime, err := c.fetch()
+	if err != nil {
+		return "", err
+	}
+	c.token = token
+	c.expires = c.now().Add(lifetime)
+	return c.token, nil
+}
+
+// Invalidate drops the cached token so the next delivery requests a new one
+func (c *ClientCredentials) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = ""
+}
+
+// RotateSecret replaces the client secret, e.g. after it was rotated at the
+// identity provider, and drops the token obtained with the old one
+func (c *ClientCredentials) RotateSecret(secret string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config.ClientSecret = secret
+	c.token = ""
+}
+
+// tokenResponse is the token endpoint's response body
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// fetch requests a token from the token endpoint
+func (c *ClientCredentials) fetch() (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(c.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.config.Scopes, " "))
+	}
+	if c.config.Audience != "" {
+		form.Set("audience", c.config.Audience)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(c.config.ClientID), url.QueryEscape(c.config.ClientSecret))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("token request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", 0, fmt.Errorf("token endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	var body tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", 0, fmt.Errorf("invalid token response: %v", err)
+	}
+	if body.AccessToken == "" {
+		return "", 0, fmt.Errorf("token response has no access_token")
+	}
+	if body.TokenType != "" && !strings.EqualFold(body.TokenType, "bearer") {
+		return "", 0, fmt.Errorf("unsupported token type %q", body.TokenType)
+	}
+
+	lifetime := defaultTokenLifetime
+	if body.ExpiresIn > 0 {
+		lifetime = time.Duration(body.ExpiresIn) * time.Second
+	}
+	return body.AccessToken, lifetime, nil
+}
+
+// Transport attaches a bearer token to every request. When the receiver answers
+// 401 the token is dropped and the request is retried once with a fresh one,
+// which covers tokens revoked or rotated before their expiry.
+type Transport struct {
+	Tokens TokenProvider
+	Base   http.RoundTripper
+}
+
+// RoundTrip sends the request with the current token
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.send(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// Only retry when the body can be sent again
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	resp.Body.Close()
+	t.Tokens.Invalidate()
+	return t.send(retry)
+}
+
+// send attaches the token to a copy of the request and sends it
+func (t *Transport) send(req *http.Request) (*http.Response, error) {
+	token, err := t.Tokens.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain webhook token: %v", err)
+	}
+	authorized := req.Clone(req.Context())
+	authorized.Header.Set("Authorization", "Bearer "+token)
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(authorized)
+}
+
+// NewHTTPClient returns the client deliveries are sent with. Without a token
+// provider requests go out unauthenticated.
+func NewHTTPClient(tokens TokenProvider, timeout time.Duration) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if tokens != nil {
+		client.Transport = &Transport{Tokens: tokens}
+	}
+	return client
+}
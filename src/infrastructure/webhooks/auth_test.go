@@ -0,0 +1,159 @@
+package webhooks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tokenServer issues numbered tokens and records the credentials it was called with
+func tokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	issued := new(atomic.Int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != "catalog" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "products:write", r.PostForm.Get("scope"))
+
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	t.Cleanup(server.Close)
+	return server, issued
+}
+
+func testAuthConfig(tokenURL string) AuthConfig {
+	return AuthConfig{
+		Type:         AuthOAuth2ClientCredentials,
+		TokenURL:     tokenURL,
+		ClientID:     "catalog",
+		ClientSecret: "s3cret",
+		Scopes:       []string{"products:write"},
+	}
+}
+
+func TestClientCredentialsCachesToken(t *testing.T) {
+	server, issued := tokenServer(t, 3600)
+	tokens, err := NewClientCredentials(testAuthConfig(server.URL))
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		token, err := tokens.Token()
+		assert.NoError(t, err)
+		assert.Equal(t, "token-1", token)
+	}
+	assert.Equal(t, int32(1), issued.Load())
+}
+
+func TestClientCredentialsRefreshesBeforeExpiry(t *testing.T) {
+	server, issued := tokenServer(t, 60)
+	tokens, err := NewClientCredentials(testAuthConfig(server.URL))
+	assert.NoError(t, err)
+
+	now := time.Now()
+	tokens.now = func() time.Time { return now }
+	token, _ := tokens.Token()
+	assert.Equal(t, "token-1", token)
+
+	// Within the refresh margin of expiry a new token is requested
+	now = now.Add(60*time.Second - tokenRefreshMargin)
+	token, _ = tokens.Token()
+	assert.Equal(t, "token-2", token)
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestClientCredentialsRotateSecret(t *testing.T) {
+	server, _ := tokenServer(t, 3600)
+	config := testAuthConfig(server.URL)
+	config.ClientSecret = "old"
+	tokens, err := NewClientCredentials(config)
+	assert.NoError(t, err)
+
+	_, err = tokens.Token()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+
+	tokens.RotateSecret("s3cret")
+	token, err := tokens.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+}
+
+func TestNewTokenProvider(t *testing.T) {
+	_, err := NewTokenProvider(AuthConfig{Type: "kerberos"})
+	assert.Error(t, err)
+
+	_, err = NewTokenProvider(AuthConfig{Type: AuthOAuth2ClientCredentials})
+	assert.Error(t, err)
+
+	RegisterTokenProvider("static", func(config AuthConfig) (TokenProvider, error) {
+		return &staticTokens{token: config.ClientSecret}, nil
+	})
+	tokens, err := NewTokenProvider(AuthConfig{Type: "static", ClientSecret: "abc"})
+	assert.NoError(t, err)
+	token, _ := tokens.Token()
+	assert.Equal(t, "abc", token)
+}
+
+type staticTokens struct {
+	token       string
+	invalidated int
+}
+
+func (s *staticTokens) Token() (string, error) { return s.token, nil }
+func (s *staticTokens) Invalidate()            { s.invalidated++ }
+
+func TestTransportRetriesOnceWithFreshToken(t *testing.T) {
+	tokenSrv, issued := tokenServer(t, 3600)
+	tokens, err := NewClientCredentials(testAuthConfig(tokenSrv.URL))
+	assert.NoError(t, err)
+
+	bodies := make([]string, 0)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		// The receiver revoked the first token before it expired
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	client := NewHTTPClient(tokens, time.Second)
+	resp, err := client.Post(receiver.URL, "application/json", strings.NewReader(`{"id":"prod_1"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, []string{`{"id":"prod_1"}`, `{"id":"prod_1"}`}, bodies)
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestTransportGivesUpAfterSecondRejection(t *testing.T) {
+	tokens := &staticTokens{token: "abc"}
+	calls := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "Bearer abc", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer receiver.Close()
+
+	resp, err := NewHTTPClient(tokens, time.Second).Get(receiver.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 1, tokens.invalidated)
+}
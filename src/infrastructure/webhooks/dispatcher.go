@@ -0,0 +1,320 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jimmitjoo/ecom/src/client/verify"
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+)
+
+const (
+	// EventHeader names the event type of a delivery
+	EventHeader = "X-Ecom-Event"
+	// DeliveryHeader carries the ID of the delivered event, the same for every attempt
+	DeliveryHeader = "X-Ecom-Delivery"
+)
+
+// deliveryLockStripes is the number of locks endpoint and product pairs are
+// spread over so a product's events reach an endpoint one at a time
+const deliveryLockStripes = 64
+
+// Target returns the sync status target name for a webhook endpoint
+func Target(endpointID string) string {
+	return models.SyncTarget(models.SyncTargetWebhook, endpointID)
+}
+
+// Options configures deliveries; zero values use the defaults
+type Options struct {
+	Timeout     time.Duration // Per attempt, default 10s
+	MaxAttempts int           // Attempts per event and endpoint, default 8
+	BaseBackoff time.Duration // Wait after the first failed attempt, doubled per attempt, default 1s
+	MaxBackoff  time.Duration // Longest wait between attempts, default 10m
+}
+
+func (o Options) withDefaults() Options {
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 8
+	}
+	if o.BaseBackoff <= 0 {
+		o.BaseBackoff = time.Second
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 10 * time.Minute
+	}
+	return o
+}
+
+// Dispatcher delivers product events to the registered webhook endpoints.
+// Every delivery is a POST of the event as JSON, signed with the endpoint's
+// secret in the X-Ecom-Signature header. Failed attempts are retried with a
+// doubling backoff, and each attempt is written to the endpoint's delivery
+// log. The outcome per product and endpoint is recorded as the sync status of
+// the target "webhook:<endpoint id>".
+type Dispatcher struct {
+	endpoints repositories.WebhookRepository
+	statuses  repositories.SyncStatusRepository
+	options   Options
+	locks     [deliveryLockStripes]sync.Mutex
+
+	mu      sync.Mutex
+	clients map[string]*http.Client // per endpoint, so token caches are kept
+	retries map[*time.Timer]struct{}
+	stopped bool
+	running sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher for the endpoints in the repository
+func NewDispatcher(endpoints repositories.WebhookRepository, statuses repositories.SyncStatusRepository, options Options) *Dispatcher {
+	return &Dispatcher{
+		endpoints: endpoints,
+		statuses:  statuses,
+		options:   options.withDefaults(),
+		clients:   make(map[string]*http.Client),
+		retries:   make(map[*time.Timer]struct{}),
+	}
+}
+
+// Subscribe delivers product events from the publisher to the endpoints
+func (d *Dispatcher) Subscribe(publisher events.EventPublisher) {
+	for _, eventType := range models.WebhookEventTypes {
+		publisher.Subscribe(eventType, d.HandleEvent)
+	}
+}
+
+// HandleEvent makes the first attempt to deliver an event to every enabled
+// endpoint subscribed to its type; retries happen in the background
+func (d *Dispatcher) HandleEvent(event *models.Event) {
+	productEvent, ok := event.Data.(*models.ProductEvent)
+	if !ok {
+		return
+	}
+	endpoints, err := d.endpoints.List()
+	if err != nil {
+		logging.Shared().Error("Failed to list webhook endpoints", zap.Error(err))
+		return
+	}
+
+	version := event.Version
+	if productEvent.Product != nil && event.Type != models.EventProductDeleted {
+		version = productEvent.Product.Version
+	}
+
+	var wg sync.WaitGroup
+	for _, endpoint := range endpoints {
+		if endpoint.Disabled || !endpoint.Subscribes(event.Type) {
+			continue
+		}
+		wg.Add(1)
+		go func(endpointID string) {
+			defer wg.Done()
+			d.attempt(endpointID, event, productEvent.ProductID, version)
+		}(endpoint.ID)
+	}
+	wg.Wait()
+}
+
+// Stop cancels the scheduled retries and waits for attempts in progress.
+// Events whose retries were cancelled keep a failed sync status and can be
+// resent with the runbook.
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	d.stopped = true
+	for timer := range d.retries {
+		if timer.Stop() {
+			d.running.Done()
+		}
+	}
+	d.retries = make(map[*time.Timer]struct{})
+	d.mu.Unlock()
+	d.running.Wait()
+}
+
+// attempt delivers one event to one endpoint unless it was handled already,
+// and schedules a retry when the attempt failed and may be repeated
+func (d *Dispatcher) attempt(endpointID string, event *models.Event, productID string, version int64) {
+	lock := d.lockFor(endpointID, productID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	target := Target(endpointID)
+	logger := logging.Shared().WithFields(
+		zap.String("webhook", endpointID),
+		zap.String("event_id", event.ID),
+		zap.String("product_id", productID),
+		zap.Int64("version", version),
+	)
+
+	endpoint, err := d.endpoints.Get(endpointID)
+	if err != nil || endpoint.Disabled {
+		return // Removed or disabled since the event was published
+	}
+	status, err := d.statuses.Get(productID, target)
+	if errors.Is(err, models.ErrSyncStatusNotFound) {
+		status = models.NewSyncStatus(productID, target)
+	} else if err != nil {
+		logger.Error("Failed to read webhook sync status", zap.Error(err))
+		return
+	}
+	// Retries, redeliveries and replays of versions that were delivered or
+	// superseded since are dropped
+	if status.Handled(version) {
+		return
+	}
+	status.Begin(version, time.Now())
+
+	delivery := &models.WebhookDelivery{
+		ID:         uuid.New().String(),
+		EndpointID: endpointID,
+		EventID:    event.ID,
+		EventType:  event.Type,
+		ProductID:  productID,
+		Version:    version,
+		Attempt:    status.Attempts,
+		At:         time.Now(),
+	}
+	retry, err := d.send(endpoint, event, delivery)
+	delivery.DurationMs = time.Since(delivery.At).Milliseconds()
+
+	if err != nil {
+		status.Fail(err, time.Now())
+		delivery.Error = err.Error()
+		if retry && status.Attempts < d.options.MaxAttempts {
+			next := time.Now().Add(d.backoff(status.Attempts))
+			delivery.NextRetry = &next
+			d.scheduleRetry(time.Until(next), endpointID, event, productID, version)
+		}
+		logger.Warn("Webhook delivery failed", zap.Int("attempt", status.Attempts), zap.Bool("retrying", delivery.NextRetry != nil), zap.Error(err))
+	} else {
+		state := models.SyncStateSynced
+		if event.Type == models.EventProductDeleted {
+			state = models.SyncStateRemoved
+		}
+		status.Succeed(state, time.Now())
+		delivery.Succeeded = true
+	}
+
+	if err := d.endpoints.AddDelivery(delivery); err != nil {
+		logger.Error("Failed to record webhook delivery", zap.Error(err))
+	}
+	if err := d.statuses.Save(status); err != nil {
+		logger.Error("Failed to save webhook sync status", zap.Error(err))
+	}
+}
+
+// send posts a signed event to an endpoint. It reports whether a failure is
+// worth retrying: no response, a timeout, throttling or a server error.
+func (d *Dispatcher) send(endpoint *models.WebhookEndpoint, event *models.Event, delivery *models.WebhookDelivery) (bool, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode event: %v", err)
+	}
+	client, err := d.clientFor(endpoint)
+	if err != nil {
+		return false, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("invalid webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(verify.SignatureHeader, verify.Sign([]byte(endpoint.Secret), time.Now(), body))
+	req.Header.Set(EventHeader, string(event.Type))
+	req.Header.Set(DeliveryHeader, event.ID)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("webhook request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	delivery.StatusCode = resp.StatusCode
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return false, nil
+	}
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	retry := resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+}
+
+// clientFor returns the HTTP client of an endpoint, authenticating deliveries
+// when the endpoint has an auth configuration
+func (d *Dispatcher) clientFor(endpoint *models.WebhookEndpoint) (*http.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if client, exists := d.clients[endpoint.ID]; exists {
+		return client, nil
+	}
+
+	var tokens TokenProvider
+	if endpoint.Auth != nil {
+		provider, err := NewTokenProvider(*endpoint.Auth)
+		if err != nil {
+			return nil, err
+		}
+		tokens = provider
+	}
+	client := NewHTTPClient(tokens, d.options.Timeout)
+	d.clients[endpoint.ID] = client
+	return client, nil
+}
+
+// scheduleRetry runs another attempt after the delay unless the dispatcher was stopped
+func (d *Dispatcher) scheduleRetry(delay time.Duration, endpointID string, event *models.Event, productID string, version int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return
+	}
+
+	d.running.Add(1)
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		defer d.running.Done()
+		d.mu.Lock()
+		delete(d.retries, timer)
+		d.mu.Unlock()
+		d.attempt(endpointID, event, productID, version)
+	})
+	d.retries[timer] = struct{}{}
+}
+
+// backoff returns the wait after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.options.BaseBackoff
+	for i := 1; i < attempts && wait < d.options.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.options.MaxBackoff {
+		return d.options.MaxBackoff
+	}
+	return wait
+}
+
+// lockFor returns the lock serialising deliveries of a product to an endpoint
+func (d *Dispatcher) lockFor(endpointID, productID string) *sync.Mutex {
+	hash := fnv.New32a()
+	hash.Write([]byte(endpointID))
+	hash.Write([]byte{0})
+	hash.Write([]byte(productID))
+	return &d.locks[hash.Sum32()%deliveryLockStripes]
+}
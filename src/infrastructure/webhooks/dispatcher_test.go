@@ -0,0 +1,172 @@
+package webhooks
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jimmitjoo/ecom/src/client/verify"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+)
+
+const testSecret = "0123456789abcdef"
+
+// receiver answers deliveries with the statuses in turn, then 204, and records
+// the verified bodies
+type receiver struct {
+	t        *testing.T
+	statuses []int
+	calls    atomic.Int32
+	mu       sync.Mutex
+	bodies   []string
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	n := int(r.calls.Add(1))
+	body, err := verify.VerifyRequest(req, []byte(testSecret))
+	assert.NoError(r.t, err)
+	assert.Equal(r.t, "product.updated", req.Header.Get(EventHeader))
+	assert.Equal(r.t, "evt_1", req.Header.Get(DeliveryHeader))
+	if n <= len(r.statuses) {
+		w.WriteHeader(r.statuses[n-1])
+		io.WriteString(w, "unavailable")
+		return
+	}
+	r.mu.Lock()
+	r.bodies = append(r.bodies, string(body))
+	r.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func setupDispatcher(t *testing.T, statuses ...int) (*Dispatcher, repositories.WebhookRepository, repositories.SyncStatusRepository, *receiver) {
+	rcv := &receiver{t: t, statuses: statuses}
+	server := httptest.NewServer(rcv)
+	t.Cleanup(server.Close)
+
+	endpoints := memory.NewWebhookRepository()
+	assert.NoError(t, endpoints.Save(&models.WebhookEndpoint{
+		ID:         "wh_1",
+		URL:        server.URL,
+		EventTypes: []models.EventType{models.EventProductUpdated},
+		Secret:     testSecret,
+	}))
+	syncStatuses := memory.NewSyncStatusRepository()
+	dispatcher := NewDispatcher(endpoints, syncStatuses, Options{BaseBackoff: time.Millisecond, MaxAttempts: 3})
+	t.Cleanup(dispatcher.Stop)
+	return dispatcher, endpoints, syncStatuses, rcv
+}
+
+func testEvent(eventType models.EventType, version int64) *models.Event {
+	return &models.Event{
+		ID:       "evt_1",
+		Type:     eventType,
+		EntityID: "prod_1",
+		Version:  version,
+		Data: &models.ProductEvent{
+			ProductID: "prod_1",
+			Product:   &models.Product{ID: "prod_1", Version: version},
+			Version:   version,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+func TestDispatcherDeliversSignedEvents(t *testing.T) {
+	dispatcher, endpoints, statuses, rcv := setupDispatcher(t)
+
+	dispatcher.HandleEvent(testEvent(models.EventProductUpdated, 2))
+	// Not subscribed
+	dispatcher.HandleEvent(testEvent(models.EventProductCreated, 1))
+	// Delivered already
+	dispatcher.HandleEvent(testEvent(models.EventProductUpdated, 2))
+
+	assert.Equal(t, int32(1), rcv.calls.Load())
+	assert.Contains(t, rcv.bodies[0], `"id":"evt_1"`)
+
+	status, err := statuses.Get("prod_1", Target("wh_1"))
+	assert.NoError(t, err)
+	assert.Equal(t, models.SyncStateSynced, status.State)
+	assert.Equal(t, int64(2), status.SyncedVersion)
+
+	deliveries, err := endpoints.ListDeliveries("wh_1", 0)
+	assert.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+	assert.True(t, deliveries[0].Succeeded)
+	assert.Equal(t, http.StatusNoContent, deliveries[0].StatusCode)
+	assert.Equal(t, 1, deliveries[0].Attempt)
+}
+
+func TestDispatcherRetriesFailedDeliveries(t *testing.T) {
+	dispatcher, endpoints, statuses, rcv := setupDispatcher(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	dispatcher.HandleEvent(testEvent(models.EventProductUpdated, 2))
+	assert.Eventually(t, func() bool {
+		status, err := statuses.Get("prod_1", Target("wh_1"))
+		return err == nil && status.State == models.SyncStateSynced
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(3), rcv.calls.Load())
+
+	deliveries, _ := endpoints.ListDeliveries("wh_1", 0)
+	assert.Len(t, deliveries, 3)
+	assert.True(t, deliveries[0].Succeeded)
+	assert.Equal(t, 3, deliveries[0].Attempt)
+	assert.Equal(t, http.StatusServiceUnavailable, deliveries[2].StatusCode)
+	assert.NotNil(t, deliveries[2].NextRetry)
+	assert.Contains(t, deliveries[2].Error, "unavailable")
+}
+
+func TestDispatcherGivesUp(t *testing.T) {
+	dispatcher, endpoints, statuses, _ := setupDispatcher(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+	dispatcher.HandleEvent(testEvent(models.EventProductUpdated, 2))
+	assert.Eventually(t, func() bool {
+		deliveries, _ := endpoints.ListDeliveries("wh_1", 0)
+		return len(deliveries) == 3
+	}, time.Second, time.Millisecond)
+	dispatcher.Stop()
+
+	deliveries, _ := endpoints.ListDeliveries("wh_1", 0)
+	assert.Len(t, deliveries, 3)
+	assert.Nil(t, deliveries[0].NextRetry)
+	status, _ := statuses.Get("prod_1", Target("wh_1"))
+	assert.Equal(t, models.SyncStateFailed, status.State)
+	assert.Equal(t, 3, status.Attempts)
+}
+
+func TestDispatcherDoesNotRetryRejectedDeliveries(t *testing.T) {
+	dispatcher, endpoints, _, rcv := setupDispatcher(t, http.StatusBadRequest)
+
+	dispatcher.HandleEvent(testEvent(models.EventProductUpdated, 2))
+	dispatcher.Stop()
+
+	assert.Equal(t, int32(1), rcv.calls.Load())
+	deliveries, _ := endpoints.ListDeliveries("wh_1", 0)
+	assert.Len(t, deliveries, 1)
+	assert.Nil(t, deliveries[0].NextRetry)
+}
+
+func TestDispatcherSkipsDisabledEndpoints(t *testing.T) {
+	dispatcher, endpoints, _, rcv := setupDispatcher(t)
+	endpoint, _ := endpoints.Get("wh_1")
+	endpoint.Disabled = true
+	assert.NoError(t, endpoints.Save(endpoint))
+
+	dispatcher.HandleEvent(testEvent(models.EventProductUpdated, 2))
+	assert.Zero(t, rcv.calls.Load())
+}
+
+func TestDispatcherBackoff(t *testing.T) {
+	dispatcher := NewDispatcher(memory.NewWebhookRepository(), memory.NewSyncStatusRepository(), Options{BaseBackoff: time.Second, MaxBackoff: 3 * time.Second})
+
+	assert.Equal(t, time.Second, dispatcher.backoff(1))
+	assert.Equal(t, 2*time.Second, dispatcher.backoff(2))
+	assert.Equal(t, 3*time.Second, dispatcher.backoff(3))
+}
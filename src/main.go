@@ -1,22 +1,68 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/jimmitjoo/ecom/src/application/interfaces"
 	"github.com/jimmitjoo/ecom/src/application/services"
+	"github.com/jimmitjoo/ecom/src/domain/events"
+	"github.com/jimmitjoo/ecom/src/domain/models"
+	"github.com/jimmitjoo/ecom/src/domain/repositories"
+	"github.com/jimmitjoo/ecom/src/infrastructure/apidocs"
+	"github.com/jimmitjoo/ecom/src/infrastructure/buildinfo"
+	"github.com/jimmitjoo/ecom/src/infrastructure/cache"
+	"github.com/jimmitjoo/ecom/src/infrastructure/catalog"
+	"github.com/jimmitjoo/ecom/src/infrastructure/config"
+	"github.com/jimmitjoo/ecom/src/infrastructure/currency"
+	"github.com/jimmitjoo/ecom/src/infrastructure/deprecation"
+	"github.com/jimmitjoo/ecom/src/infrastructure/events/kafka"
 	"github.com/jimmitjoo/ecom/src/infrastructure/events/memory"
+	"github.com/jimmitjoo/ecom/src/infrastructure/events/outbox"
+	"github.com/jimmitjoo/ecom/src/infrastructure/events/tracking"
+	"github.com/jimmitjoo/ecom/src/infrastructure/forecasting"
 	"github.com/jimmitjoo/ecom/src/infrastructure/handlers"
+	"github.com/jimmitjoo/ecom/src/infrastructure/imports"
+	"github.com/jimmitjoo/ecom/src/infrastructure/lifecycle"
 	"github.com/jimmitjoo/ecom/src/infrastructure/locks"
+	"github.com/jimmitjoo/ecom/src/infrastructure/logging"
+	"github.com/jimmitjoo/ecom/src/infrastructure/marketplaces"
+	"github.com/jimmitjoo/ecom/src/infrastructure/media"
 	"github.com/jimmitjoo/ecom/src/infrastructure/middleware"
+	"github.com/jimmitjoo/ecom/src/infrastructure/middleware/auth"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/slo"
+	"github.com/jimmitjoo/ecom/src/infrastructure/monitoring/stats"
+	"github.com/jimmitjoo/ecom/src/infrastructure/oidc"
+	"github.com/jimmitjoo/ecom/src/infrastructure/pricing"
 	"github.com/jimmitjoo/ecom/src/infrastructure/ratelimit"
+	boltRepo "github.com/jimmitjoo/ecom/src/infrastructure/repositories/bolt"
+	cachedRepo "github.com/jimmitjoo/ecom/src/infrastructure/repositories/cached"
+	fileRepo "github.com/jimmitjoo/ecom/src/infrastructure/repositories/file"
+	instrumentedRepo "github.com/jimmitjoo/ecom/src/infrastructure/repositories/instrumented"
 	memoryRepo "github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
+	postgresRepo "github.com/jimmitjoo/ecom/src/infrastructure/repositories/postgres"
+	shadowRepo "github.com/jimmitjoo/ecom/src/infrastructure/repositories/shadow"
+	"github.com/jimmitjoo/ecom/src/infrastructure/search"
+	"github.com/jimmitjoo/ecom/src/infrastructure/tracing"
+	"github.com/jimmitjoo/ecom/src/infrastructure/webhooks"
+	"github.com/jimmitjoo/ecom/src/testing/contract"
 
 	gorillaHandlers "github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
 	_ "github.com/jimmitjoo/ecom/docs" // This is generated by swag
-	httpSwagger "github.com/swaggo/http-swagger"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
 )
 
 // @title E-commerce Product API
@@ -36,62 +82,640 @@ import (
 // @schemes http ws
 
 func main() {
-	// Create repository instance
-	repo := memoryRepo.NewProductRepository()
+	// Core settings come from defaults, CONFIG_FILE, the environment and flags, in that order
+	settings, err := config.Load(os.Args[1:], os.Getenv)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if settings.Log.Level != "" {
+		logging.SetLevel(settings.Log.Level)
+	}
+
+	// Reported by /admin/diagnostics; filled in as components and features are wired
+	backends := map[string]string{
+		"repository":    "memory",
+		"events":        "memory",
+		"event_offsets": "memory",
+		"locks":         settings.Locks.Backend,
+		"jobs":          "memory",
+		"rate_limiter":  "token_bucket",
+	}
+	features := make(map[string]bool)
+
+	// Export spans of requests, repository calls, locks and broadcasts to TRACING_EXPORTER
+	tracerProvider := newTracerProvider()
+	if tracerProvider != nil {
+		backends["tracing"] = os.Getenv("TRACING_EXPORTER")
+	}
+	features["tracing"] = tracerProvider != nil
+
+	// Create repository instance; the postgres and bolt backends keep the catalog across restarts
+	var repo repositories.ProductRepository
+	var stopCompaction func()
+	switch settings.Repository.Backend {
+	case "postgres":
+		repo = postgresRepo.NewProductRepository(openPostgres())
+	case "bolt":
+		repo = openBolt()
+	default:
+		inMemory := newMemoryRepository()
+		// Drop the event history before each product's latest snapshot every EVENT_COMPACTION_INTERVAL
+		if interval := durationEnv("EVENT_COMPACTION_INTERVAL", 0); interval > 0 {
+			stopCompaction = inMemory.StartEventCompaction(interval)
+			features["event_compaction"] = true
+		}
+		repo = inMemory
+	}
+	backends["repository"] = settings.Repository.Backend
+
+	// Preview environments start from a catalog snapshot instead of replaying history
+	if location := os.Getenv("CATALOG_SNAPSHOT"); location != "" {
+		snapshot, err := catalog.LoadSnapshot(location)
+		if err != nil {
+			log.Fatalf("Failed to load catalog snapshot: %v", err)
+		}
+		count, err := catalog.RestoreSnapshot(context.Background(), repo, snapshot)
+		if err != nil {
+			log.Fatalf("Failed to restore catalog snapshot: %v", err)
+		}
+		log.Printf("Loaded %d products from the catalog snapshot exported %s", count, snapshot.ExportedAt.Format(time.RFC3339))
+	}
+	features["catalog_snapshot"] = os.Getenv("CATALOG_SNAPSHOT") != ""
+
+	// With EVENT_OUTBOX, the backend queues every event it stores in an outbox
+	// it keeps itself, in the transaction of the write
+	var outboxStore repositories.OutboxRepository
+	if os.Getenv("EVENT_OUTBOX") == "true" {
+		backend, ok := repo.(repositories.OutboxStore)
+		if !ok {
+			log.Fatalf("The %s repository keeps no event outbox", settings.Repository.Backend)
+		}
+		outboxStore = backend.Outbox()
+	}
+
+	// Mirror repository traffic to the backend in REPOSITORY_SHADOW while migrating to it
+	if backend := os.Getenv("REPOSITORY_SHADOW"); backend != "" {
+		repo = newShadowRepository(repo, backend)
+		backends["repository_shadow"] = backend
+	}
+	features["repository_shadow"] = backends["repository_shadow"] != ""
+	repo = instrumentedRepo.NewProductRepository(repo)
 
-	// Create event publisher
-	publisher := memory.NewMemoryEventPublisher()
+	// Serve product reads from the read-through cache in REPOSITORY_CACHE, memory or redis
+	var repoCache *cachedRepo.ProductRepository
+	if name := os.Getenv("REPOSITORY_CACHE"); name != "" {
+		capacity, _ := strconv.Atoi(os.Getenv("REPOSITORY_CACHE_SIZE"))
+		store, err := cachedRepo.NewStore(cachedRepo.StoreConfig{Name: name, Capacity: capacity, RedisURL: os.Getenv("REDIS_URL")})
+		if err != nil {
+			log.Fatalf("Failed to create repository cache: %v", err)
+		}
+		repoCache = cachedRepo.NewProductRepository(repo, store, cachedRepo.Options{
+			ProductTTL: durationEnv("REPOSITORY_CACHE_PRODUCT_TTL", cachedRepo.DefaultProductTTL),
+			ListTTL:    durationEnv("REPOSITORY_CACHE_LIST_TTL", cachedRepo.DefaultListTTL),
+		})
+		repo = repoCache
+		backends["repository_cache"] = name
+	}
+	features["repository_cache"] = repoCache != nil
+
+	// Create event publisher; the kafka publisher also writes every event to Kafka
+	var publisher events.EventPublisher = memory.NewMemoryEventPublisher()
+	var kafkaPublisher *kafka.Publisher
+	if settings.Events.Publisher == "kafka" {
+		kafkaPublisher = newKafkaPublisher(publisher)
+		publisher = kafkaPublisher
+	}
+	backends["events"] = settings.Events.Publisher
+
+	// Track internal subscribers' offsets so they resume after a restart
+	offsets := memoryRepo.NewOffsetRepository()
+	if path := os.Getenv("EVENT_OFFSETS_FILE"); path != "" {
+		fileOffsets, err := fileRepo.NewOffsetRepository(path)
+		if err != nil {
+			log.Fatalf("Failed to open event offsets: %v", err)
+		}
+		offsets = fileOffsets
+		backends["event_offsets"] = "file"
+	}
+	tracker := tracking.NewTracker(publisher, offsets)
+	rateLimits, err := tracking.ParseRateLimits(os.Getenv("EVENT_CONSUMER_RATE_LIMITS"))
+	if err != nil {
+		log.Fatalf("Invalid EVENT_CONSUMER_RATE_LIMITS: %v", err)
+	}
+	for consumer, limit := range rateLimits {
+		tracker.SetRateLimit(consumer, limit)
+	}
 
-	// Create lock manager
+	// Commit events to an outbox and publish them from a relay that retries
+	// failed attempts, so a write never fails because publishing did
+	var servicePublisher events.EventPublisher = tracker
+	var eventOutbox *outbox.Publisher
+	if outboxStore != nil {
+		eventOutbox = newEventOutbox(outboxStore, tracker)
+		eventOutbox.Start()
+		servicePublisher = eventOutbox
+		features["event_outbox"] = true
+	}
+
+	// Create lock manager; memory is the only lock backend so far
 	lockManager := locks.NewMemoryLockManager()
 
+	// Create job repository for batch operations
+	jobRepo := memoryRepo.NewJobRepository()
+
 	// Create product service
-	productService := services.NewProductService(repo, publisher, lockManager)
+	productService := services.NewProductService(repo, servicePublisher, lockManager, jobRepo)
+	marketService := services.NewMarketService(repo, memoryRepo.NewMerchandisingRepository())
+	importService := services.NewImportService(productService, jobRepo)
+	jobService := services.NewJobService(jobRepo)
+
+	// Track response statuses for the admin dashboard
+	requestStats := stats.NewRequestStats(24 * time.Hour)
+
+	// Measure route latency against the objectives in SLO_CONFIG
+	latencyTracker := slo.NewTracker(loadLatencyObjectives())
+	latencyTracker.Start(durationEnv("SLO_EVALUATE_INTERVAL", time.Minute))
 
 	// Create handlers
 	productHandler := handlers.NewProductHandler(productService)
-	wsHandler := handlers.NewWebSocketHandler(publisher)
+	productHandler.LimitBatchSize(settings.Server.MaxBatchSize)
+	wsHandler := handlers.NewWebSocketHandler(tracker.Consumer("websocket"))
+	marketHandler := handlers.NewMarketHandler(marketService)
+	importHandler := handlers.NewImportHandler(importService)
+	exportHandler := handlers.NewExportHandler(productService)
+	jobHandler := handlers.NewJobHandler(jobService)
+	reprocessHandler := handlers.NewReprocessHandler(services.NewReprocessService(repo, jobRepo, tracker))
+	forecaster, err := forecasting.New(os.Getenv("FORECASTER"))
+	if err != nil {
+		log.Fatalf("Failed to create forecaster: %v", err)
+	}
+	backends["forecaster"] = forecaster.Name()
+	forecastHandler := handlers.NewForecastHandler(services.NewForecastService(repo, memoryRepo.NewForecastInputRepository(), forecaster))
+	editSessionHandler := handlers.NewEditSessionHandler(services.NewEditSessionService(repo, lockManager))
+	noteHandler := handlers.NewNoteHandler(services.NewNoteService(repo, memoryRepo.NewNoteRepository()))
+	catalogCloneService := services.NewCatalogCloneService(productService, loadCatalogSources())
+	features["catalog_cloning"] = len(catalogCloneService.Sources()) > 0
+	catalogHandler := handlers.NewCatalogHandler(productService, catalogCloneService)
+	tagHandler := handlers.NewTagHandler(productService)
+	publicHandler := handlers.NewPublicHandler(services.NewPublicCatalogService(repo, marketService))
+	stockHandler := handlers.NewStockHandler(services.NewStockService(productService))
+	bundleHandler := handlers.NewBundleHandler(services.NewBundleService(productService))
+	facetHandler := handlers.NewFacetHandler(services.NewFacetService(productService))
+	categoryHandler := handlers.NewCategoryHandler(services.NewCategoryService(memoryRepo.NewCategoryRepository(), productService, servicePublisher))
+	relationHandler := handlers.NewRelationHandler(services.NewRelationService(memoryRepo.NewRelationRepository(), productService, tracker.Consumer("relations")))
+
+	// Product images are attached by URL or uploaded to the media storage
+	assetStorage, err := media.NewStorage(media.StorageConfig{
+		Name:            os.Getenv("MEDIA_STORAGE"),
+		Dir:             os.Getenv("MEDIA_DIR"),
+		BaseURL:         os.Getenv("MEDIA_BASE_URL"),
+		Bucket:          os.Getenv("MEDIA_S3_BUCKET"),
+		Region:          os.Getenv("MEDIA_S3_REGION"),
+		Endpoint:        os.Getenv("MEDIA_S3_ENDPOINT"),
+		AccessKeyID:     os.Getenv("MEDIA_S3_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("MEDIA_S3_SECRET_ACCESS_KEY"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create media storage: %v", err)
+	}
+	backends["media_storage"] = assetStorage.Name()
+	maxUploadBytes, _ := strconv.ParseInt(os.Getenv("MEDIA_MAX_UPLOAD_BYTES"), 10, 64)
+	if maxUploadBytes <= 0 {
+		maxUploadBytes = handlers.DefaultMaxUploadBytes
+	}
+	assetHandler := handlers.NewAssetHandler(services.NewAssetService(productService, assetStorage), maxUploadBytes)
+
+	// Price lists per market and customer group, with scheduled prices
+	// announced as they start and end
+	priceListService := services.NewPriceListService(memoryRepo.NewPriceListRepository(), repo, servicePublisher)
+	priceListHandler := handlers.NewPriceListHandler(priceListService)
+	priceScheduler := pricing.NewScheduler(priceListService)
+	priceScheduler.Start(durationEnv("PRICE_SCHEDULE_INTERVAL", pricing.DefaultInterval))
+	allocationHandler := handlers.NewAllocationHandler(services.NewAllocationService(repo, productService, memoryRepo.NewAllocationPolicyRepository()))
+
+	// Prices shown in a display currency, converted with the configured rates
+	rateProvider, err := currency.NewProvider(currency.ProviderConfig{
+		Name:   os.Getenv("CURRENCY_RATES_PROVIDER"),
+		Base:   os.Getenv("CURRENCY_BASE"),
+		Rates:  os.Getenv("CURRENCY_RATES"),
+		ECBURL: os.Getenv("CURRENCY_ECB_URL"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create exchange rate provider: %v", err)
+	}
+	backends["exchange_rates"] = rateProvider.Name()
+	productHandler.EnableCurrencyConversion(services.NewCurrencyService(rateProvider, durationEnv("CURRENCY_RATES_TTL", services.DefaultRatesTTL)))
+
+	// Create dashboard service and admin handler
+	// Deprecated routes and fields are announced in headers and their use is counted
+	deprecations, err := deprecation.NewRegistry(deprecation.Registered...)
+	if err != nil {
+		log.Fatalf("Invalid deprecation registry: %v", err)
+	}
+
+	dashboardService := services.NewDashboardService(tracker.Consumer("dashboard"), jobRepo, requestStats, wsHandler, tracker, latencyTracker, deprecations)
+	adminHandler := handlers.NewAdminHandler(dashboardService)
+	diagnosticsService := services.NewDiagnosticsService(interfaces.DiagnosticsConfig{
+		Build:    buildinfo.Get(),
+		Backends: backends,
+		Features: features,
+		Config:   configFromEnv(),
+		Checks:   startupChecks(repo, lockManager),
+	}, dashboardService)
+	diagnosticsHandler := handlers.NewDiagnosticsHandler(diagnosticsService)
+	versionHandler := handlers.NewVersionHandler(buildinfo.GetVersion())
+
+	// Integrations record per-product delivery status per downstream target;
+	// marketplaces configured in MARKETPLACES_CONFIG are the first of them
+	syncStatusRepo := memoryRepo.NewSyncStatusRepository()
+	marketplaceSyncer := newMarketplaceSyncer(syncStatusRepo)
+	marketplaceSyncer.Subscribe(tracker.Consumer("marketplaces"))
+	features["marketplace_sync"] = len(marketplaceSyncer.Marketplaces()) > 0
+	marketplaceHandler := handlers.NewMarketplaceHandler(marketplaceSyncer)
+
+	// Webhook endpoints registered through the API receive product events as signed POSTs
+	webhookRepo := memoryRepo.NewWebhookRepository()
+	webhookDispatcher := newWebhookDispatcher(webhookRepo, syncStatusRepo)
+	webhookDispatcher.Subscribe(tracker.Consumer("webhooks"))
+	webhookHandler := handlers.NewWebhookHandler(services.NewWebhookService(webhookRepo))
+	syncStatusHandler := handlers.NewSyncStatusHandler(services.NewSyncStatusService(repo, syncStatusRepo))
+
+	// Full-text search, kept in sync with the products by their events. The
+	// index is filled from the repository in the background at startup.
+	searchAnalyzers, err := search.ParseAnalyzers(os.Getenv("SEARCH_ANALYZERS"))
+	if err != nil {
+		log.Fatalf("Invalid SEARCH_ANALYZERS: %v", err)
+	}
+	searchIndex, err := search.NewIndex(search.IndexConfig{
+		Name:     os.Getenv("SEARCH_INDEX"),
+		URL:      os.Getenv("SEARCH_URL"),
+		Index:    os.Getenv("SEARCH_INDEX_NAME"),
+		Username: os.Getenv("SEARCH_USERNAME"),
+		Password: os.Getenv("SEARCH_PASSWORD"),
+	}, searchAnalyzers)
+	if err != nil {
+		log.Fatalf("Failed to create search index: %v", err)
+	}
+	backends["search"] = searchIndex.Name()
+	searchService := services.NewSearchService(searchIndex, productService, syncStatusRepo, tracker.Consumer("search"))
+	go func() {
+		indexed, err := searchService.Reindex(context.Background())
+		if err != nil {
+			log.Printf("Failed to index products for search: %v", err)
+			return
+		}
+		log.Printf("Indexed %d products for search", indexed)
+	}()
+	searchHandler := handlers.NewSearchHandler(searchService)
+
+	// On-call recovery actions, each recorded in an audit trail
+	runbookHandler := handlers.NewRunbookHandler(services.NewRunbookService(repo, tracker, syncStatusRepo, lockManager, memoryRepo.NewRunbookRunRepository(), interfaces.RunbookConfig{
+		Projections:       map[string]interfaces.Projection{"dashboard": dashboardService},
+		DeliveryConsumers: map[string]string{models.SyncTargetMarketplace: "marketplaces", models.SyncTargetWebhook: "webhooks", models.SyncTargetSearch: "search"},
+	}))
+
+	// Drop cached products changed by other instances sharing the cache
+	if repoCache != nil {
+		repoCache.Subscribe(tracker.Consumer("repository_cache"))
+	}
+
+	// Keep the most requested products encoded across their updates
+	var cacheWarmer *cache.Warmer
+	if topN, _ := strconv.Atoi(os.Getenv("CACHE_WARM_TOP_N")); topN >= 0 {
+		cacheWarmer = productHandler.EnableCacheWarming(topN)
+		cacheWarmer.Subscribe(tracker.Consumer("cache"))
+		cacheWarmer.Start(durationEnv("CACHE_WARM_INTERVAL", time.Minute))
+		features["cache_warming"] = true
+	}
+
+	// Deliver events the subscribers missed while the process was down
+	if replayed, err := tracker.Resume(context.Background(), repo); err != nil {
+		log.Printf("Failed to resume event consumers: %v", err)
+	} else if replayed > 0 {
+		log.Printf("Replayed %d events to resumed consumers", replayed)
+	}
+
+	// Optionally send new WebSocket clients a snapshot of recent activity
+	sendQueueSize, _ := strconv.Atoi(os.Getenv("WS_SEND_QUEUE_SIZE"))
+	wsHandler.SetSendQueue(handlers.SendQueueConfig{
+		Size:     sendQueueSize,
+		Overflow: handlers.OverflowPolicy(os.Getenv("WS_SEND_QUEUE_OVERFLOW")),
+	})
+	maxMissedPongs, _ := strconv.Atoi(os.Getenv("WS_MAX_MISSED_PONGS"))
+	wsHandler.EnableKeepalive(handlers.KeepaliveConfig{
+		PingInterval:   durationEnv("WS_PING_INTERVAL", 30*time.Second),
+		MaxMissedPongs: maxMissedPongs,
+	})
+	if mode := handlers.SnapshotMode(os.Getenv("WS_SNAPSHOT_MODE")); mode == handlers.SnapshotEvents || mode == handlers.SnapshotProducts {
+		limit, _ := strconv.Atoi(os.Getenv("WS_SNAPSHOT_LIMIT"))
+		wsHandler.EnableSnapshots(dashboardService, handlers.SnapshotConfig{Mode: mode, Limit: limit})
+		features["websocket_snapshots"] = true
+	}
+
+	// Optionally import supplier files dropped into a directory (e.g. by an SFTP server)
+	watcher := startImportWatcher(importService, jobRepo)
+	features["import_watcher"] = watcher != nil
 
 	// Set up router
 	r := mux.NewRouter()
 
-	// Set up rate limiter
-	limiter := ratelimit.NewTokenBucketLimiter(10, 10) // 10 tokens/sec, max 10 tokens
-	rateLimitMiddleware := middleware.RateLimitMiddleware(limiter)
-	r.Use(rateLimitMiddleware)
+	// Set up rate limiter; RATE_LIMIT_RATE and RATE_LIMIT_BURST size the default
+	// tier, RATE_LIMIT_TIERS adds tiers and RATE_LIMIT_TIER_ASSIGNMENTS puts callers in them;
+	// rate_limit.routes or RATE_LIMIT_ROUTES limit single routes further
+	limiter := newRateLimiter(settings.RateLimit)
+	routePolicies := newRoutePolicies(settings.RateLimit.Routes)
+	features["rate_limit_routes"] = routePolicies.Len() > 0
+	r.Use(middleware.TracingMiddleware)
+	// After tracing, so request loggers carry the trace ID
+	r.Use(middleware.RequestLoggerMiddleware(logging.Shared()))
+	if settings.Log.Access.Enabled {
+		r.Use(middleware.AccessLogMiddleware(middleware.AccessLogOptions{
+			SampleRate:    settings.Log.Access.SampleRate,
+			SampledRoutes: settings.Log.Access.SampledRoutes,
+		}))
+	}
+	r.Use(middleware.RequestStatsMiddleware(requestStats))
+	r.Use(middleware.LatencyMiddleware(latencyTracker))
+	r.Use(middleware.MetricsMiddleware)
+	// Before rate limiting and authentication, so their errors are enveloped too
+	r.Use(middleware.EnvelopeMiddleware)
+	// Image uploads and CSV imports are limited to their own sizes
+	r.Use(middleware.BodyLimitMiddleware(settings.Server.MaxBodyBytes, map[string]int64{
+		"POST /products/{id}/images": maxUploadBytes,
+		"POST /products/import":      handlers.MaxCSVImportBytes,
+	}))
+	// Before authentication, so rejected credentials count against the client
+	r.Use(middleware.ClientRateLimitMiddleware(limiter))
+
+	// Integrations call the product API with a bearer token or an API key
+	authConfig := apiAuthConfig()
+	var apiAuthenticators []middleware.Authenticator
+	if authConfig != nil {
+		var err error
+		if apiAuthenticators, err = authConfig.Authenticators(); err != nil {
+			log.Fatalf("Invalid API authentication: %v", err)
+		}
+	}
+
+	// Human admin users sign in through the corporate identity provider; the
+	// admin API accepts the integrations' credentials next to sessions
+	oidcHandler := newOIDCHandler()
+	features["admin_login"] = oidcHandler != nil
+	if oidcHandler != nil {
+		r.HandleFunc("/auth/login", oidcHandler.Login).Methods("GET")
+		r.HandleFunc("/auth/callback", oidcHandler.Callback).Methods("GET")
+		r.HandleFunc("/auth/logout", oidcHandler.Logout).Methods("POST")
+		r.HandleFunc("/auth/me", oidcHandler.Me).Methods("GET")
+		adminAuthenticators := append([]middleware.Authenticator{oidcHandler}, apiAuthenticators...)
+		r.Use(middleware.RequireAuth("/admin/", []string{"/admin/swagger/"}, adminAuthenticators...))
+	} else {
+		log.Printf("Admin login disabled: OIDC_ISSUER not set")
+	}
+
+	// Storefronts read the public catalog with an API key, or anonymously when no keys are set
+	if keys := os.Getenv("PUBLIC_API_KEYS"); keys != "" {
+		apiKeys := middleware.NewAPIKeyAuthenticator(strings.Split(keys, ","), models.RolePublic)
+		r.Use(middleware.RequireAuth("/public/", nil, apiKeys))
+		features["public_api_keys"] = true
+	} else {
+		log.Printf("Public API open to anonymous callers: PUBLIC_API_KEYS not set")
+	}
+
+	if authConfig != nil {
+		// Admin callers were authenticated above, sessions included
+		authenticated := *authConfig
+		if oidcHandler != nil {
+			authenticated.Exempt = append(append([]string(nil), authConfig.Exempt...), "/admin/")
+		}
+		apiAuth, err := auth.Middleware(authenticated)
+		if err != nil {
+			log.Fatalf("Invalid API authentication: %v", err)
+		}
+		r.Use(apiAuth)
+		features["api_auth"] = true
+	} else {
+		log.Printf("Product API open to anonymous callers: AUTH_JWT_SECRET, AUTH_JWT_PUBLIC_KEY_FILE and API_KEYS not set")
+	}
+	if authConfig != nil || oidcHandler != nil {
+		exempt := auth.DefaultExempt
+		if authConfig != nil {
+			exempt = authConfig.ExemptPaths()
+		}
+		r.Use(middleware.Authorize(middleware.DefaultAccessPolicy(), exempt))
+	}
+
+	// After authentication, so callers are limited by API key or user in their tier
+	r.Use(middleware.TieredRateLimitMiddleware(limiter, routePolicies))
+
+	// After authentication, so usage of deprecations is counted per principal
+	r.Use(middleware.DeprecationMiddleware(deprecations))
+
+	// In test environments, record request/response examples for contract tests
+	if dir := os.Getenv("CONTRACT_RECORD_DIR"); dir != "" {
+		if os.Getenv("GO_ENV") != "test" {
+			log.Printf("Contract recording disabled: CONTRACT_RECORD_DIR requires GO_ENV=test")
+		} else if recorder, err := contract.NewRecorder(dir); err != nil {
+			log.Fatalf("Failed to start contract recording: %v", err)
+		} else {
+			r.Use(recorder.Middleware)
+			features["contract_recording"] = true
+			log.Printf("Recording contract examples to %s", dir)
+		}
+	}
 
 	// Batch endpoints (must come before specific product endpoints)
 	r.HandleFunc("/products/batch", productHandler.BatchCreateProducts).Methods("POST")
 	r.HandleFunc("/products/batch", productHandler.BatchUpdateProducts).Methods("PUT")
 	r.HandleFunc("/products/batch", productHandler.BatchDeleteProducts).Methods("DELETE")
+	r.HandleFunc("/products/import", importHandler.ImportProducts).Methods("POST")
+	r.HandleFunc("/products/export", exportHandler.ExportProducts).Methods("GET")
+	r.HandleFunc("/products/tags", tagHandler.UpdateTags).Methods("POST")
+	r.HandleFunc("/products/metadata/copy", productHandler.CopyMetadata).Methods("POST")
 
 	// REST endpoints for individual products
 	r.HandleFunc("/products", productHandler.ListProducts).Methods("GET")
 	r.HandleFunc("/products", productHandler.CreateProduct).Methods("POST")
+	r.HandleFunc("/products/compare", productHandler.CompareProducts).Methods("GET")
+	r.HandleFunc("/products/search", searchHandler.SearchProducts).Methods("GET")
+	r.HandleFunc("/products/facets", facetHandler.ProductFacets).Methods("GET")
+	r.HandleFunc("/products/sku/{sku}", productHandler.GetProductBySKU).Methods("GET")
 	r.HandleFunc("/products/{id}", productHandler.GetProduct).Methods("GET")
 	r.HandleFunc("/products/{id}", productHandler.UpdateProduct).Methods("PUT")
+	r.HandleFunc("/products/{id}", productHandler.PatchProduct).Methods("PATCH")
 	r.HandleFunc("/products/{id}", productHandler.DeleteProduct).Methods("DELETE")
+	r.HandleFunc("/products/{id}/rollback", productHandler.RollbackProduct).Methods("POST")
+	r.HandleFunc("/products/{id}/restore", productHandler.RestoreProduct).Methods("POST")
+	r.HandleFunc("/products/{id}/events", productHandler.ProductEvents).Methods("GET")
+	r.HandleFunc("/products/{id}/versions", productHandler.ListProductVersions).Methods("GET")
+	r.HandleFunc("/products/{id}/versions/{version}", productHandler.GetProductVersion).Methods("GET")
+	r.HandleFunc("/products/{id}/stock", productHandler.AdjustStock).Methods("POST")
+	r.HandleFunc("/products/{id}/variants/{vid}/stock", stockHandler.GetStock).Methods("GET")
+	r.HandleFunc("/products/{id}/variants/{vid}/stock", stockHandler.SetStock).Methods("PUT")
+	r.HandleFunc("/products/{id}/bundle", bundleHandler.GetBundle).Methods("GET")
+	r.HandleFunc("/products/{id}/price", priceListHandler.ResolvePrice).Methods("GET")
+	r.HandleFunc("/products/{id}/availability", allocationHandler.Availability).Methods("GET")
+	r.HandleFunc("/products/{id}/reservations", allocationHandler.Reserve).Methods("POST")
+	r.HandleFunc("/products/{id}/sync-status", syncStatusHandler.GetSyncStatus).Methods("GET")
+	r.HandleFunc("/products/{id}/categories", categoryHandler.AssignCategories).Methods("PUT")
+	r.HandleFunc("/products/{id}/relations", relationHandler.ListRelations).Methods("GET")
+	r.HandleFunc("/products/{id}/relations", relationHandler.AddRelation).Methods("POST")
+	r.HandleFunc("/products/{id}/relations/{type}/{related}", relationHandler.RemoveRelation).Methods("DELETE")
+	r.HandleFunc("/products/{id}/related", relationHandler.RelatedProducts).Methods("GET")
+	r.HandleFunc("/products/{id}/images", assetHandler.AddImage).Methods("POST")
+	r.HandleFunc("/products/{id}/images/order", assetHandler.ReorderImages).Methods("PUT")
+	r.HandleFunc("/products/{id}/images/{image_id}", assetHandler.DeleteImage).Methods("DELETE")
+
+	// Tag routes
+	r.HandleFunc("/tags", tagHandler.ListTags).Methods("GET")
+	r.HandleFunc("/tags/{tag}/products", tagHandler.DeleteTaggedProducts).Methods("DELETE")
+
+	// Category routes
+	r.HandleFunc("/categories", categoryHandler.ListCategories).Methods("GET")
+	r.HandleFunc("/categories", categoryHandler.CreateCategory).Methods("POST")
+	r.HandleFunc("/categories/{id}", categoryHandler.GetCategory).Methods("GET")
+	r.HandleFunc("/categories/{id}", categoryHandler.UpdateCategory).Methods("PUT")
+	r.HandleFunc("/categories/{id}", categoryHandler.DeleteCategory).Methods("DELETE")
+	r.HandleFunc("/categories/{id}/products", categoryHandler.ListCategoryProducts).Methods("GET")
+
+	// Job routes
+	r.HandleFunc("/jobs", jobHandler.ListJobs).Methods("GET")
+	r.HandleFunc("/jobs/{id}", jobHandler.GetJob).Methods("GET")
+	r.HandleFunc("/jobs/{id}/rollback", productHandler.RollbackJob).Methods("POST")
+
+	// Market rollout routes
+	r.HandleFunc("/markets/{market}/launch-checklist", marketHandler.LaunchChecklist).Methods("GET")
+
+	// Market listings and merchandising positions
+	r.HandleFunc("/markets/{market}/products", marketHandler.ListMarketProducts).Methods("GET")
+	r.HandleFunc("/markets/{market}/merchandising", marketHandler.ListMerchandising).Methods("GET")
+	r.HandleFunc("/markets/{market}/merchandising/pins", marketHandler.GetPins).Methods("GET")
+	r.HandleFunc("/markets/{market}/merchandising/pins", marketHandler.SetPins).Methods("PUT")
+	r.HandleFunc("/markets/{market}/merchandising/pins/{id}", marketHandler.PinProduct).Methods("PUT")
+	r.HandleFunc("/markets/{market}/merchandising/pins/{id}", marketHandler.UnpinProduct).Methods("DELETE")
+
+	// Price lists
+	r.HandleFunc("/price-lists", priceListHandler.ListPriceLists).Methods("GET")
+	r.HandleFunc("/price-lists", priceListHandler.CreatePriceList).Methods("POST")
+	r.HandleFunc("/price-lists/{id}", priceListHandler.GetPriceList).Methods("GET")
+	r.HandleFunc("/price-lists/{id}", priceListHandler.UpdatePriceList).Methods("PUT")
+	r.HandleFunc("/price-lists/{id}", priceListHandler.DeletePriceList).Methods("DELETE")
+
+	// Stock adjustments across products
+	r.HandleFunc("/stock/adjustments", stockHandler.AdjustStock).Methods("POST")
+
+	// Allocation of orders to stock locations, per market and sales channel
+	r.HandleFunc("/markets/{market}/allocation-policies", allocationHandler.ListPolicies).Methods("GET")
+	r.HandleFunc("/markets/{market}/allocation-policy", allocationHandler.GetPolicy).Methods("GET")
+	r.HandleFunc("/markets/{market}/allocation-policy", allocationHandler.SetPolicy).Methods("PUT")
+	r.HandleFunc("/markets/{market}/allocation-policy", allocationHandler.DeletePolicy).Methods("DELETE")
+
+	// Public read-only routes for storefronts
+	r.HandleFunc("/public/products", publicHandler.ListProducts).Methods("GET")
+	r.HandleFunc("/public/products/{id}", publicHandler.GetProduct).Methods("GET")
+	r.HandleFunc("/public/markets/{market}/products", publicHandler.ListMarketProducts).Methods("GET")
+
+	// Marketplace export routes
+	r.HandleFunc("/marketplaces", marketplaceHandler.ListMarketplaces).Methods("GET")
+	r.HandleFunc("/marketplaces/{marketplace}/sync-status", marketplaceHandler.SyncStatuses).Methods("GET")
+
+	// Webhook subscriptions
+	r.HandleFunc("/webhooks", webhookHandler.ListWebhooks).Methods("GET")
+	r.HandleFunc("/webhooks", webhookHandler.RegisterWebhook).Methods("POST")
+	r.HandleFunc("/webhooks/{id}", webhookHandler.GetWebhook).Methods("GET")
+	r.HandleFunc("/webhooks/{id}", webhookHandler.DeleteWebhook).Methods("DELETE")
+	r.HandleFunc("/webhooks/{id}/state", webhookHandler.SetWebhookState).Methods("PUT")
+	r.HandleFunc("/webhooks/{id}/deliveries", webhookHandler.ListWebhookDeliveries).Methods("GET")
+
+	// Admin dashboard endpoints
+	r.HandleFunc("/admin/dashboard/events", adminHandler.RecentEvents).Methods("GET")
+	r.HandleFunc("/admin/dashboard/top-edited", adminHandler.TopEditedProducts).Methods("GET")
+	r.HandleFunc("/admin/dashboard/errors", adminHandler.ErrorRate).Methods("GET")
+	r.HandleFunc("/admin/dashboard/websocket", adminHandler.WebSocketClients).Methods("GET")
+	r.HandleFunc("/admin/dashboard/jobs", adminHandler.JobStatuses).Methods("GET")
+	r.HandleFunc("/admin/dashboard/consumers", adminHandler.ConsumerLags).Methods("GET")
+	r.HandleFunc("/admin/dashboard/latency", adminHandler.LatencyObjectives).Methods("GET")
+	r.HandleFunc("/admin/dashboard/deprecations", adminHandler.DeprecationUsage).Methods("GET")
+
+	// Admin catalog maintenance
+	r.HandleFunc("/admin/attributes/migrate", productHandler.MigrateAttributes).Methods("POST")
+	r.HandleFunc("/admin/text/replace", productHandler.ReplaceText).Methods("POST")
+	r.HandleFunc("/admin/reprocess", reprocessHandler.StartReprocess).Methods("POST")
+
+	// Runbook automation for on-call engineers
+	r.HandleFunc("/admin/runbook/projections/{name}/rebuild", runbookHandler.RebuildProjection).Methods("POST")
+	r.HandleFunc("/admin/runbook/deliveries/resend", runbookHandler.ResendFailedDeliveries).Methods("POST")
+	r.HandleFunc("/admin/runbook/products/{id}/locks/release", runbookHandler.ReleaseStaleLocks).Methods("POST")
+	r.HandleFunc("/admin/runbook/products/{id}/event-chain/verify", runbookHandler.VerifyEventChain).Methods("POST")
+	r.HandleFunc("/admin/runbook/runs", runbookHandler.ListRuns).Methods("GET")
+
+	// Inventory forecasting
+	r.HandleFunc("/admin/forecasting/inputs", forecastHandler.PushInputs).Methods("POST")
+	r.HandleFunc("/admin/forecasting/stockouts", forecastHandler.Stockouts).Methods("GET")
+
+	// Advisory edit sessions that warn admins about concurrent edits of a product
+	r.HandleFunc("/admin/products/{id}/edit-sessions", editSessionHandler.ClaimEditSession).Methods("POST")
+	r.HandleFunc("/admin/products/{id}/edit-sessions", editSessionHandler.ListEditSessions).Methods("GET")
+	r.HandleFunc("/admin/products/{id}/edit-sessions/{session}", editSessionHandler.RenewEditSession).Methods("PUT")
+	r.HandleFunc("/admin/products/{id}/edit-sessions/{session}", editSessionHandler.ReleaseEditSession).Methods("DELETE")
+
+	// Internal notes merchandisers keep on products
+	r.HandleFunc("/admin/products/{id}/notes", noteHandler.ListNotes).Methods("GET")
+	r.HandleFunc("/admin/products/{id}/notes", noteHandler.AddNote).Methods("POST")
+	r.HandleFunc("/admin/products/{id}/notes/{note}", noteHandler.EditNote).Methods("PUT")
+	r.HandleFunc("/admin/products/{id}/notes/{note}", noteHandler.DeleteNote).Methods("DELETE")
+	r.HandleFunc("/admin/products/{id}/notes/{note}/resolution", noteHandler.ResolveNote).Methods("PUT")
+
+	r.HandleFunc("/admin/diagnostics", diagnosticsHandler.GetDiagnostics).Methods("GET")
+	r.HandleFunc("/version", versionHandler.GetVersion).Methods("GET")
+	r.Handle("/metrics", promhttp.Handler()).Methods("GET")
+
+	// Catalog cloning between environments
+	r.HandleFunc("/admin/catalog/export", catalogHandler.ExportCatalog).Methods("GET")
+	r.HandleFunc("/admin/catalog/import", catalogHandler.ImportCatalog).Methods("POST")
+	r.HandleFunc("/admin/catalog/sources", catalogHandler.ListSources).Methods("GET")
+	r.HandleFunc("/admin/catalog/clone", catalogHandler.CloneCatalog).Methods("POST")
 
 	// WebSocket endpoint
 	r.HandleFunc("/ws", wsHandler.HandleWebSocket)
 
-	// Swagger documentation
-	r.PathPrefix("/swagger/").Handler(httpSwagger.Handler(
-		httpSwagger.URL("/swagger/doc.json"),
-		httpSwagger.DeepLinking(true),
-		httpSwagger.DocExpansion("none"),
-		httpSwagger.DomID("swagger-ui"),
-	))
+	// Uploaded images in local storage are served from the media directory
+	if local, ok := assetStorage.(*media.LocalStorage); ok {
+		r.PathPrefix("/media/").Handler(http.StripPrefix("/media/", http.FileServer(http.Dir(local.Dir()))))
+	}
+
+	// Swagger documentation: the public read API is open, the full API requires admin credentials
+	if err := apidocs.RegisterVariants(); err != nil {
+		log.Fatalf("Failed to register API documentation: %v", err)
+	}
+	r.PathPrefix("/swagger/").Handler(apidocs.Handler("/swagger/", apidocs.PublicInstance))
+
+	if adminDocsPassword := os.Getenv("ADMIN_DOCS_PASSWORD"); adminDocsPassword != "" {
+		adminDocsUser := os.Getenv("ADMIN_DOCS_USERNAME")
+		if adminDocsUser == "" {
+			adminDocsUser = "admin"
+		}
+		features["admin_docs"] = true
+		adminDocsAuth := middleware.BasicAuthMiddleware("admin-docs", adminDocsUser, adminDocsPassword)
+		r.PathPrefix("/admin/swagger/").Handler(adminDocsAuth(apidocs.Handler("/admin/swagger/", apidocs.AdminInstance)))
+	} else {
+		log.Printf("Admin API documentation disabled: ADMIN_DOCS_PASSWORD not set")
+	}
 
 	// CORS configuration
 	corsMiddleware := gorillaHandlers.CORS(
-		gorillaHandlers.AllowedOrigins([]string{"*"}),
+		gorillaHandlers.AllowedOrigins(settings.Server.CORSOrigins),
 		gorillaHandlers.AllowedMethods([]string{
-			"GET", "POST", "PUT", "DELETE", "OPTIONS", "HEAD",
+			"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "HEAD",
 		}),
 		gorillaHandlers.AllowedHeaders([]string{
 			"Content-Type",
 			"Authorization",
+			"X-API-Key",
+			"API-Version",
+			"If-Match",
+			"If-None-Match",
+			"If-Modified-Since",
+			"X-Request-ID",
 			"X-Requested-With",
 			"Access-Control-Allow-Origin",
 			"Access-Control-Allow-Methods",
@@ -101,13 +725,25 @@ func main() {
 		}),
 		gorillaHandlers.ExposedHeaders([]string{
 			"Content-Length",
+			"API-Version",
+			"ETag",
+			"Deprecation",
+			"Sunset",
+			"Warning",
+			"Link",
+			"X-Request-ID",
+			"X-RateLimit-Limit",
+			"X-RateLimit-Remaining",
+			"X-RateLimit-Reset",
+			"Retry-After",
 			"Access-Control-Allow-Origin",
 		}),
 		gorillaHandlers.AllowCredentials(),
 	)
 
 	// Use CORS middleware
-	handler := corsMiddleware(r)
+	// Outside the router, so versioned paths are routed like their legacy aliases
+	handler := corsMiddleware(middleware.VersionedPathMiddleware(versionedResources)(r))
 
 	log.Printf("Repository initialized: %v", repo != nil)
 	log.Printf("Publisher initialized: %v", publisher != nil)
@@ -119,12 +755,627 @@ func main() {
 	profilingHandler := handlers.NewProfilingHandler()
 
 	// Add profiling routes (only available in development mode)
-	if os.Getenv("GO_ENV") == "development" {
+	features["profiling"] = os.Getenv("GO_ENV") == "development"
+	if features["profiling"] {
 		r.HandleFunc("/debug/pprof/cpu", profilingHandler.CPUProfile)
 		r.HandleFunc("/debug/pprof/heap", profilingHandler.HeapProfile)
 		r.HandleFunc("/debug/pprof/goroutine", profilingHandler.GoroutineProfile)
 	}
 
-	log.Printf("Server starting on http://localhost:8080")
-	log.Fatal(http.ListenAndServe(":8080", handler))
+	server := &http.Server{Addr: settings.Server.Addr, Handler: handler}
+
+	// gRPC clients use the product service on a port of their own
+	stopGRPC := func() {}
+	if grpcLinked {
+		stopGRPC = serveGRPC(settings.Server.GRPCAddr, productService, tracker, apiAuthenticators)
+		features["grpc"] = true
+	} else {
+		log.Printf("gRPC API disabled: built without -tags grpc")
+	}
+
+	// On SIGINT or SIGTERM stop taking work, drain requests and connections, then flush events
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+		<-stop
+
+		ctx, cancel := context.WithTimeout(context.Background(), settings.Server.ShutdownTimeout)
+		defer cancel()
+
+		var steps []lifecycle.Step
+		if watcher != nil {
+			steps = append(steps, lifecycle.Func("import_watcher", watcher.Stop))
+		}
+		steps = append(steps, lifecycle.Func("latency_tracker", latencyTracker.Stop))
+		steps = append(steps, lifecycle.Func("price_scheduler", priceScheduler.Stop))
+		if cacheWarmer != nil {
+			steps = append(steps, lifecycle.Func("cache_warmer", cacheWarmer.Stop))
+		}
+		if stopCompaction != nil {
+			steps = append(steps, lifecycle.Func("event_compaction", stopCompaction))
+		}
+		steps = append(steps, lifecycle.Func("webhook_retries", webhookDispatcher.Stop))
+		steps = append(steps,
+			// Ask WebSocket clients to reconnect with staggered delays before the listener closes
+			lifecycle.Func("websocket_reconnect", func() {
+				wsHandler.Shutdown(handlers.ReconnectPolicy{
+					After:  durationEnv("WS_RECONNECT_AFTER", time.Second),
+					Spread: durationEnv("WS_RECONNECT_SPREAD", 10*time.Second),
+				})
+			}),
+			lifecycle.Step{Name: "http", Stop: server.Shutdown},
+			lifecycle.Step{Name: "websocket_close", Stop: func(ctx context.Context) error {
+				if closed := wsHandler.Drain(ctx); closed > 0 {
+					return fmt.Errorf("closed %d clients that did not complete the close handshake", closed)
+				}
+				return nil
+			}},
+			lifecycle.Func("grpc", stopGRPC),
+		)
+		if eventOutbox != nil {
+			// Publish the events committed by the drained requests
+			steps = append(steps, lifecycle.Func("event_outbox", eventOutbox.Stop))
+		}
+		steps = append(steps, lifecycle.Step{Name: "event_deliveries", Stop: tracker.Flush})
+		if kafkaPublisher != nil {
+			steps = append(steps, lifecycle.Step{Name: "kafka", Stop: func(context.Context) error {
+				return kafkaPublisher.Close()
+			}})
+		}
+		steps = append(steps, lifecycle.Func("locks", lockManager.Close))
+		if tracerProvider != nil {
+			steps = append(steps, lifecycle.Step{Name: "tracing", Stop: tracerProvider.Shutdown})
+		}
+
+		if err := lifecycle.Shutdown(ctx, steps...); err != nil {
+			log.Printf("Shutdown finished with errors: %v", err)
+		}
+	}()
+
+	// Check the dependencies once everything is wired; failures are reported, not fatal
+	for _, check := range diagnosticsService.SelfCheck() {
+		if check.OK {
+			logging.Shared().Info("Startup check passed", zap.String("check", check.Name), zap.String("duration", check.Duration))
+		} else {
+			logging.Shared().Error("Startup check failed", zap.String("check", check.Name), zap.String("error", check.Error))
+		}
+	}
+
+	log.Printf("Server starting on %s", settings.Server.Addr)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	// ListenAndServe returns as soon as shutdown begins; wait for it to finish
+	<-shutdownDone
+	log.Printf("Server stopped")
+}
+
+// startImportWatcher starts polling IMPORT_WATCH_DIR when it is set. The mapping
+// template is read from the JSON file in IMPORT_WATCH_MAPPING.
+func startImportWatcher(importService interfaces.ImportService, jobRepo repositories.JobRepository) *imports.Watcher {
+	dir := os.Getenv("IMPORT_WATCH_DIR")
+	if dir == "" {
+		return nil
+	}
+
+	pattern := os.Getenv("IMPORT_WATCH_PATTERN")
+	if pattern == "" {
+		pattern = "*.csv"
+	}
+	source, err := imports.NewDirectorySource(dir, pattern, durationEnv("IMPORT_WATCH_MIN_AGE", 10*time.Second))
+	if err != nil {
+		log.Fatalf("Failed to watch import directory: %v", err)
+	}
+
+	data, err := os.ReadFile(os.Getenv("IMPORT_WATCH_MAPPING"))
+	if err != nil {
+		log.Fatalf("Failed to read import mapping: %v", err)
+	}
+	var mapping map[string]string
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		log.Fatalf("Failed to parse import mapping: %v", err)
+	}
+
+	mode := interfaces.ImportMode(os.Getenv("IMPORT_WATCH_MODE"))
+	if mode == "" {
+		mode = interfaces.ImportUpdate
+	}
+
+	// Column types are given as "price:number,delivery_date:date"
+	columns := make(map[string]string)
+	for _, declaration := range strings.Split(os.Getenv("IMPORT_WATCH_COLUMNS"), ",") {
+		if column, columnType, found := strings.Cut(strings.TrimSpace(declaration), ":"); found {
+			columns[column] = columnType
+		}
+	}
+
+	watcher, err := imports.NewWatcher(source, importService, jobRepo, imports.WatcherConfig{
+		Mapping:  mapping,
+		Mode:     mode,
+		Locale:   os.Getenv("IMPORT_WATCH_LOCALE"),
+		Columns:  columns,
+		Interval: durationEnv("IMPORT_WATCH_INTERVAL", time.Minute),
+	})
+	if err != nil {
+		log.Fatalf("Failed to start import watcher: %v", err)
+	}
+	watcher.Start()
+	log.Printf("Watching %s for %s import files", dir, pattern)
+	return watcher
+}
+
+// newEventOutbox creates the outbox publisher in front of inner, relaying the
+// events in store. Attempts are retried up to EVENT_OUTBOX_MAX_ATTEMPTS times
+// (default 10), waiting EVENT_OUTBOX_BACKOFF (default 1s) doubled per attempt
+// up to EVENT_OUTBOX_MAX_BACKOFF (default 5m).
+func newEventOutbox(store repositories.OutboxRepository, inner events.EventPublisher) *outbox.Publisher {
+	options := outbox.Options{
+		PollInterval: durationEnv("EVENT_OUTBOX_POLL_INTERVAL", time.Second),
+		BaseBackoff:  durationEnv("EVENT_OUTBOX_BACKOFF", time.Second),
+		MaxBackoff:   durationEnv("EVENT_OUTBOX_MAX_BACKOFF", 5*time.Minute),
+		Retention:    durationEnv("EVENT_OUTBOX_RETENTION", time.Hour),
+	}
+	if value := os.Getenv("EVENT_OUTBOX_MAX_ATTEMPTS"); value != "" {
+		attempts, err := strconv.Atoi(value)
+		if err != nil || attempts < 1 {
+			log.Fatalf("Invalid EVENT_OUTBOX_MAX_ATTEMPTS %q: must be a positive number", value)
+		}
+		options.MaxAttempts = attempts
+	}
+	return outbox.NewPublisher(store, inner, options)
+}
+
+// newMemoryRepository creates the in-memory repository, which snapshots product
+// events every EVENT_SNAPSHOT_INTERVAL versions (default 100, 0 disables)
+func newMemoryRepository() *memoryRepo.ProductRepository {
+	options := memoryRepo.Options{}
+	if value := os.Getenv("EVENT_SNAPSHOT_INTERVAL"); value != "" {
+		interval, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || interval < 0 {
+			log.Fatalf("Invalid EVENT_SNAPSHOT_INTERVAL %q: must be a non-negative number of versions", value)
+		}
+		options.SnapshotInterval = interval
+		if interval == 0 {
+			options.SnapshotInterval = -1
+		}
+	}
+	return memoryRepo.NewProductRepositoryWithOptions(options)
+}
+
+// newShadowRepository serves from primary and mirrors writes and a share of reads
+// (SHADOW_READ_SAMPLE_RATE, default 1) to the named backend. Only "memory" is
+// available until other backends are added.
+func newShadowRepository(primary repositories.ProductRepository, backend string) repositories.ProductRepository {
+	var shadow repositories.ProductRepository
+	switch backend {
+	case "memory":
+		shadow = memoryRepo.NewProductRepository()
+	case "postgres":
+		shadow = postgresRepo.NewProductRepository(openPostgres())
+	default:
+		log.Fatalf("Unknown shadow repository backend %q", backend)
+	}
+
+	sampleRate := 1.0
+	if value := os.Getenv("SHADOW_READ_SAMPLE_RATE"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			log.Fatalf("Invalid SHADOW_READ_SAMPLE_RATE %q: must be between 0 and 1", value)
+		}
+		sampleRate = rate
+	}
+
+	log.Printf("Shadowing repository traffic to %s (read sample rate %.2f)", backend, sampleRate)
+	return shadowRepo.NewProductRepository(primary, shadow, shadowRepo.Options{ReadSampleRate: sampleRate})
+}
+
+// newRateLimiter creates the tiered rate limiter. The default tier comes from
+// the core settings, so RATE_LIMIT_TIERS cannot redefine it.
+func newRateLimiter(settings config.RateLimitConfig) *ratelimit.TieredLimiter {
+	tiers, err := ratelimit.ParseTiers(os.Getenv("RATE_LIMIT_TIERS"))
+	if err != nil {
+		log.Fatalf("Invalid RATE_LIMIT_TIERS: %v", err)
+	}
+	assignments, err := ratelimit.ParseAssignments(os.Getenv("RATE_LIMIT_TIER_ASSIGNMENTS"))
+	if err != nil {
+		log.Fatalf("Invalid RATE_LIMIT_TIER_ASSIGNMENTS: %v", err)
+	}
+	tiers = append([]ratelimit.Tier{{Name: ratelimit.DefaultTier, Rate: settings.Rate, Burst: settings.Burst}}, tiers...)
+	limiter, err := ratelimit.NewTieredLimiter(tiers, assignments)
+	if err != nil {
+		log.Fatalf("Invalid rate limit tiers: %v", err)
+	}
+	return limiter
+}
+
+// newRoutePolicies creates the registry of route rate limits. A policy without
+// a burst allows bursts of its rate.
+func newRoutePolicies(routes []config.RoutePolicyConfig) *ratelimit.PolicyRegistry {
+	policies := make([]ratelimit.RoutePolicy, 0, len(routes))
+	for _, route := range routes {
+		burst := route.Burst
+		if burst == 0 {
+			burst = route.Rate
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		policies = append(policies, ratelimit.RoutePolicy{Method: route.Method, Route: route.Route, Rate: route.Rate, Burst: burst})
+	}
+	registry, err := ratelimit.NewPolicyRegistry(policies)
+	if err != nil {
+		log.Fatalf("Invalid rate limit routes: %v", err)
+	}
+	return registry
+}
+
+// openPostgres connects to the database in DATABASE_URL and applies pending migrations
+func openPostgres() *sql.DB {
+	driver := os.Getenv("DATABASE_DRIVER")
+	if driver == "" {
+		driver = "pgx"
+	}
+	maxOpen, err := strconv.Atoi(os.Getenv("DATABASE_MAX_OPEN_CONNS"))
+	if err != nil || maxOpen <= 0 {
+		maxOpen = 20
+	}
+	maxIdle, err := strconv.Atoi(os.Getenv("DATABASE_MAX_IDLE_CONNS"))
+	if err != nil || maxIdle < 0 {
+		maxIdle = 5
+	}
+
+	db, err := postgresRepo.Open(postgresRepo.Config{
+		Driver:          driver,
+		DSN:             os.Getenv("DATABASE_URL"),
+		MaxOpenConns:    maxOpen,
+		MaxIdleConns:    maxIdle,
+		ConnMaxLifetime: durationEnv("DATABASE_CONN_MAX_LIFETIME", 30*time.Minute),
+		ConnMaxIdleTime: durationEnv("DATABASE_CONN_MAX_IDLE_TIME", 5*time.Minute),
+	})
+	if err != nil {
+		log.Fatalf("Failed to connect to Postgres: %v", err)
+	}
+	applied, err := postgresRepo.Migrate(context.Background(), db)
+	if err != nil {
+		log.Fatalf("Failed to migrate Postgres: %v", err)
+	}
+	log.Printf("Connected to Postgres (%d connections max, %d migrations applied)", maxOpen, applied)
+	return db
+}
+
+// openBolt opens the embedded store in BOLT_PATH and recovers it
+func openBolt() *boltRepo.ProductRepository {
+	path := os.Getenv("BOLT_PATH")
+	if path == "" {
+		path = "ecom.db"
+	}
+	store, err := boltRepo.Open(path, boltRepo.Options{LockTimeout: durationEnv("BOLT_LOCK_TIMEOUT", 10*time.Second)})
+	if err != nil {
+		log.Fatalf("Failed to open embedded store %s: %v", path, err)
+	}
+	repo, err := boltRepo.NewProductRepository(store)
+	if err != nil {
+		log.Fatalf("Failed to open embedded store %s: %v", path, err)
+	}
+	log.Printf("Opened embedded store %s", path)
+	return repo
+}
+
+// newKafkaPublisher connects a Kafka writer to the brokers in KAFKA_BROKERS and
+// wraps the local publisher with it
+func newKafkaPublisher(local events.EventPublisher) *kafka.Publisher {
+	topic := os.Getenv("KAFKA_TOPIC")
+	if topic == "" {
+		topic = "product-events"
+	}
+	retries, err := strconv.Atoi(os.Getenv("KAFKA_PUBLISH_RETRIES"))
+	if err != nil || retries < 0 {
+		retries = 3
+	}
+	config := kafka.Config{
+		Brokers:      strings.Split(os.Getenv("KAFKA_BROKERS"), ","),
+		Topic:        topic,
+		TopicPerType: os.Getenv("KAFKA_TOPIC_PER_TYPE") == "true",
+		Retries:      retries,
+		RetryBackoff: durationEnv("KAFKA_RETRY_BACKOFF", 100*time.Millisecond),
+	}
+
+	writer, err := kafka.NewWriter(config)
+	if err != nil {
+		log.Fatalf("Failed to create Kafka writer: %v", err)
+	}
+	log.Printf("Publishing events to Kafka topics %v", config.Topics())
+	return kafka.NewPublisher(local, writer, config)
+}
+
+// apiAuthConfig reads the product API's credentials from the environment, or
+// returns nil when none are configured
+func apiAuthConfig() *auth.Config {
+	config := &auth.Config{
+		APIKeys:     splitList(os.Getenv("API_KEYS")),
+		APIKeyRoles: splitList(os.Getenv("API_KEY_ROLES")),
+		Exempt:      splitList(os.Getenv("AUTH_EXEMPT_PATHS")),
+	}
+
+	jwt := &auth.JWTConfig{
+		Secret:     []byte(os.Getenv("AUTH_JWT_SECRET")),
+		Issuer:     os.Getenv("AUTH_JWT_ISSUER"),
+		Audience:   os.Getenv("AUTH_JWT_AUDIENCE"),
+		RolesClaim: os.Getenv("AUTH_JWT_ROLES_CLAIM"),
+	}
+	if path := os.Getenv("AUTH_JWT_PUBLIC_KEY_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read JWT public key: %v", err)
+		}
+		if jwt.PublicKey, err = auth.ParsePublicKey(data); err != nil {
+			log.Fatalf("Failed to parse JWT public key: %v", err)
+		}
+	}
+	if len(jwt.Secret) > 0 || jwt.PublicKey != nil {
+		config.JWT = jwt
+	}
+
+	if config.JWT == nil && len(config.APIKeys) == 0 {
+		return nil
+	}
+	return config
+}
+
+// splitList splits a comma-separated variable, dropping empty entries
+func splitList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// loadLatencyObjectives reads per-route latency objectives from the JSON file in
+// SLO_CONFIG, falling back to a 500ms objective for 99% of requests on every route
+func loadLatencyObjectives() slo.Config {
+	path := os.Getenv("SLO_CONFIG")
+	if path == "" {
+		return slo.DefaultConfig()
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to read latency objectives: %v", err)
+	}
+	defer file.Close()
+
+	config, err := slo.ParseConfig(file)
+	if err != nil {
+		log.Fatalf("Failed to parse latency objectives: %v", err)
+	}
+	return config
+}
+
+// newTracerProvider sets up tracing with the exporter in TRACING_EXPORTER
+// (jaeger, otlp or stdout; none by default) sending to TRACING_ENDPOINT, and
+// records TRACING_SAMPLE_RATIO of new traces (default all). It returns nil
+// when tracing is off.
+func newTracerProvider() *tracesdk.TracerProvider {
+	config := tracing.Config{
+		Exporter:    os.Getenv("TRACING_EXPORTER"),
+		Endpoint:    os.Getenv("TRACING_ENDPOINT"),
+		ServiceName: "ecom",
+		Environment: os.Getenv("GO_ENV"),
+	}
+	if value := os.Getenv("TRACING_SAMPLE_RATIO"); value != "" {
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil || ratio <= 0 || ratio > 1 {
+			log.Fatalf("Invalid TRACING_SAMPLE_RATIO %q: must be above 0 and at most 1", value)
+		}
+		config.SampleRatio = ratio
+	}
+	provider, err := tracing.InitTracer(config)
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	return provider
+}
+
+// newWebhookDispatcher creates the webhook dispatcher. Deliveries time out
+// after WEBHOOK_TIMEOUT (default 10s) and are attempted up to
+// WEBHOOK_MAX_ATTEMPTS times (default 8), waiting WEBHOOK_BACKOFF (default 1s)
+// doubled per attempt up to WEBHOOK_MAX_BACKOFF (default 10m).
+func newWebhookDispatcher(endpoints repositories.WebhookRepository, statuses repositories.SyncStatusRepository) *webhooks.Dispatcher {
+	options := webhooks.Options{
+		Timeout:     durationEnv("WEBHOOK_TIMEOUT", 10*time.Second),
+		BaseBackoff: durationEnv("WEBHOOK_BACKOFF", time.Second),
+		MaxBackoff:  durationEnv("WEBHOOK_MAX_BACKOFF", 10*time.Minute),
+	}
+	if value := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); value != "" {
+		attempts, err := strconv.Atoi(value)
+		if err != nil || attempts < 1 {
+			log.Fatalf("Invalid WEBHOOK_MAX_ATTEMPTS %q: must be a positive number", value)
+		}
+		options.MaxAttempts = attempts
+	}
+	return webhooks.NewDispatcher(endpoints, statuses, options)
+}
+
+// newMarketplaceSyncer reads the marketplace adapters from the JSON file in
+// MARKETPLACES_CONFIG, keyed by marketplace name. Without it no products are exported.
+func newMarketplaceSyncer(statuses repositories.SyncStatusRepository) *marketplaces.Syncer {
+	path := os.Getenv("MARKETPLACES_CONFIG")
+	if path == "" {
+		return marketplaces.NewSyncer(statuses)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read marketplace config: %v", err)
+	}
+	var configs map[string]marketplaces.Config
+	if err := json.Unmarshal(data, &configs); err != nil {
+		log.Fatalf("Failed to parse marketplace config: %v", err)
+	}
+
+	adapters := make([]marketplaces.Marketplace, 0, len(configs))
+	for name, config := range configs {
+		switch name {
+		case "amazon":
+			adapters = append(adapters, marketplaces.NewAmazon(config))
+		case "zalando":
+			adapters = append(adapters, marketplaces.NewZalando(config))
+		default:
+			log.Fatalf("Unknown marketplace %q in marketplace config", name)
+		}
+		log.Printf("Exporting products to %s", name)
+	}
+	return marketplaces.NewSyncer(statuses, adapters...)
+}
+
+// loadCatalogSources reads the environments catalogs can be cloned from out of
+// the JSON file in CATALOG_SOURCES_CONFIG, keyed by environment name
+func loadCatalogSources() map[string]interfaces.CatalogSource {
+	sources := make(map[string]interfaces.CatalogSource)
+	path := os.Getenv("CATALOG_SOURCES_CONFIG")
+	if path == "" {
+		return sources
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("Failed to read catalog sources: %v", err)
+	}
+	var configs map[string]catalog.SourceConfig
+	if err := json.Unmarshal(data, &configs); err != nil {
+		log.Fatalf("Failed to parse catalog sources: %v", err)
+	}
+	for name, config := range configs {
+		sources[name] = catalog.NewHTTPSource(config)
+		log.Printf("Catalogs can be cloned from %s", name)
+	}
+	return sources
+}
+
+// newOIDCHandler configures admin login from the OIDC_* environment variables,
+// or returns nil when OIDC_ISSUER is not set
+func newOIDCHandler() *oidc.Handler {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil
+	}
+
+	var groupRoles map[string][]string
+	if err := json.Unmarshal([]byte(os.Getenv("OIDC_GROUP_ROLES")), &groupRoles); err != nil {
+		log.Fatalf("Failed to parse OIDC_GROUP_ROLES: %v", err)
+	}
+	handler, err := oidc.NewHandler(oidc.Config{
+		Issuer:        issuer,
+		ClientID:      os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret:  os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:   os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:        strings.Fields(os.Getenv("OIDC_SCOPES")),
+		GroupsClaim:   os.Getenv("OIDC_GROUPS_CLAIM"),
+		GroupRoles:    groupRoles,
+		SessionSecret: os.Getenv("OIDC_SESSION_SECRET"),
+		SessionTTL:    durationEnv("OIDC_SESSION_TTL", oidc.DefaultSessionTTL),
+	})
+	if err != nil {
+		log.Fatalf("Failed to configure admin login: %v", err)
+	}
+	log.Printf("Admin login enabled through %s", issuer)
+	return handler
+}
+
+// versionedResources are the API resources served under /v1 and /v2 and,
+// deprecated, at their legacy paths. Operational endpoints such as /metrics,
+// /admin and /auth are not versioned.
+var versionedResources = []string{"products", "categories", "jobs", "marketplaces", "markets", "price-lists", "stock", "tags", "webhooks"}
+
+// configVariables are the environment variables the service is configured with
+var configVariables = []string{
+	"GO_ENV", "CONFIG_FILE", "LOG_LEVEL", "ACCESS_LOG", "ACCESS_LOG_SAMPLE_RATE", "ACCESS_LOG_SAMPLED_ROUTES",
+	"SERVER_ADDR", "GRPC_ADDR", "CORS_ALLOWED_ORIGINS", "SHUTDOWN_TIMEOUT", "MAX_BODY_BYTES", "MAX_BATCH_SIZE",
+	"RATE_LIMIT_RATE", "RATE_LIMIT_BURST",
+	"RATE_LIMIT_TIERS", "RATE_LIMIT_TIER_ASSIGNMENTS", "RATE_LIMIT_ROUTES",
+	"LOCK_BACKEND",
+	"REPOSITORY", "DATABASE_DRIVER", "DATABASE_MAX_OPEN_CONNS", "DATABASE_MAX_IDLE_CONNS", "DATABASE_CONN_MAX_LIFETIME", "DATABASE_CONN_MAX_IDLE_TIME",
+	"BOLT_PATH", "BOLT_LOCK_TIMEOUT",
+	"REPOSITORY_SHADOW", "SHADOW_READ_SAMPLE_RATE",
+	"REPOSITORY_CACHE", "REPOSITORY_CACHE_SIZE", "REPOSITORY_CACHE_PRODUCT_TTL", "REPOSITORY_CACHE_LIST_TTL", "REDIS_URL",
+	"EVENT_SNAPSHOT_INTERVAL", "EVENT_COMPACTION_INTERVAL",
+	"EVENT_PUBLISHER", "KAFKA_BROKERS", "KAFKA_TOPIC", "KAFKA_TOPIC_PER_TYPE", "KAFKA_PUBLISH_RETRIES", "KAFKA_RETRY_BACKOFF",
+	"EVENT_OFFSETS_FILE", "EVENT_CONSUMER_RATE_LIMITS",
+	"EVENT_OUTBOX", "EVENT_OUTBOX_POLL_INTERVAL", "EVENT_OUTBOX_MAX_ATTEMPTS", "EVENT_OUTBOX_BACKOFF", "EVENT_OUTBOX_MAX_BACKOFF", "EVENT_OUTBOX_RETENTION",
+	"SLO_CONFIG", "SLO_EVALUATE_INTERVAL",
+	"FORECASTER",
+	"CACHE_WARM_TOP_N", "CACHE_WARM_INTERVAL",
+	"PRICE_SCHEDULE_INTERVAL",
+	"CURRENCY_RATES_PROVIDER", "CURRENCY_BASE", "CURRENCY_RATES", "CURRENCY_ECB_URL", "CURRENCY_RATES_TTL",
+	"MEDIA_STORAGE", "MEDIA_DIR", "MEDIA_BASE_URL", "MEDIA_MAX_UPLOAD_BYTES",
+	"MEDIA_S3_BUCKET", "MEDIA_S3_REGION", "MEDIA_S3_ENDPOINT", "MEDIA_S3_ACCESS_KEY_ID", "MEDIA_S3_SECRET_ACCESS_KEY",
+	"SEARCH_INDEX", "SEARCH_URL", "SEARCH_INDEX_NAME", "SEARCH_USERNAME", "SEARCH_PASSWORD", "SEARCH_ANALYZERS",
+	"CATALOG_SOURCES_CONFIG", "CATALOG_SNAPSHOT",
+	"MARKETPLACES_CONFIG",
+	"WEBHOOK_TIMEOUT", "WEBHOOK_MAX_ATTEMPTS", "WEBHOOK_BACKOFF", "WEBHOOK_MAX_BACKOFF",
+	"TRACING_EXPORTER", "TRACING_ENDPOINT", "TRACING_SAMPLE_RATIO",
+	"WS_SNAPSHOT_MODE", "WS_SNAPSHOT_LIMIT", "WS_RECONNECT_AFTER", "WS_RECONNECT_SPREAD", "WS_PING_INTERVAL", "WS_MAX_MISSED_PONGS", "WS_SEND_QUEUE_SIZE", "WS_SEND_QUEUE_OVERFLOW",
+	"IMPORT_WATCH_DIR", "IMPORT_WATCH_PATTERN", "IMPORT_WATCH_MIN_AGE", "IMPORT_WATCH_MAPPING",
+	"IMPORT_WATCH_MODE", "IMPORT_WATCH_COLUMNS", "IMPORT_WATCH_LOCALE", "IMPORT_WATCH_INTERVAL",
+	"ADMIN_DOCS_USERNAME", "ADMIN_DOCS_PASSWORD",
+	"OIDC_ISSUER", "OIDC_CLIENT_ID", "OIDC_CLIENT_SECRET", "OIDC_REDIRECT_URL", "OIDC_SCOPES",
+	"OIDC_GROUPS_CLAIM", "OIDC_GROUP_ROLES", "OIDC_SESSION_SECRET", "OIDC_SESSION_TTL",
+	"PUBLIC_API_KEYS",
+	"AUTH_JWT_SECRET", "AUTH_JWT_PUBLIC_KEY_FILE", "AUTH_JWT_ISSUER", "AUTH_JWT_AUDIENCE", "AUTH_JWT_ROLES_CLAIM",
+	"API_KEYS", "API_KEY_ROLES", "AUTH_EXEMPT_PATHS",
+	"CONTRACT_RECORD_DIR",
+}
+
+// configFromEnv returns the configuration variables that are set
+func configFromEnv() map[string]string {
+	config := make(map[string]string)
+	for _, key := range configVariables {
+		if value, set := os.LookupEnv(key); set {
+			config[key] = value
+		}
+	}
+	return config
+}
+
+// startupChecks verify that the dependencies answer before traffic is served
+func startupChecks(repo repositories.ProductRepository, lockManager locks.LockManager) []interfaces.Check {
+	checks := []interfaces.Check{
+		{Name: "repository", Run: func() error {
+			_, _, err := repo.List(context.Background(), models.ProductFilter{}, 1, 1)
+			return err
+		}},
+		{Name: "locks", Run: func() error {
+			acquired, err := lockManager.AcquireLock(context.Background(), "diagnostics:self-check", time.Second)
+			if err != nil {
+				return err
+			}
+			if !acquired {
+				return fmt.Errorf("lock was not acquired")
+			}
+			return lockManager.ReleaseLock("diagnostics:self-check")
+		}},
+	}
+	if dir := os.Getenv("IMPORT_WATCH_DIR"); dir != "" {
+		checks = append(checks, interfaces.Check{Name: "import_watch_dir", Run: func() error {
+			info, err := os.Stat(dir)
+			if err == nil && !info.IsDir() {
+				err = fmt.Errorf("%s is not a directory", dir)
+			}
+			return err
+		}})
+	}
+	return checks
+}
+
+// durationEnv reads a duration such as "1s" from the environment, falling back to def
+func durationEnv(key string, def time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value >= 0 {
+		return value
+	}
+	return def
 }
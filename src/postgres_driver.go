@@ -0,0 +1,7 @@
+//go:build postgres
+
+package main
+
+// The Postgres driver for REPOSITORY=postgres is linked by building with
+// -tags postgres, after adding it with go get github.com/jackc/pgx/v5
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -1,6 +1,7 @@
 package chaos
 
 import (
+	"context"
 	"encoding/json"
 	"math/rand"
 	"runtime"
@@ -77,7 +78,7 @@ func TestMemoryPressure(t *testing.T) {
 			go func() {
 				defer wg.Done()
 				products := generateLargeProductBatch(100)
-				_, err := service.BatchCreateProducts(products)
+				_, err := service.BatchCreateProducts(context.Background(), products)
 				assert.NoError(t, err)
 			}()
 		}
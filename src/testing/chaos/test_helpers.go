@@ -11,14 +11,16 @@ import (
 	"github.com/jimmitjoo/ecom/src/domain/repositories"
 	"github.com/jimmitjoo/ecom/src/infrastructure/events/memory"
 	memorylocks "github.com/jimmitjoo/ecom/src/infrastructure/locks/memory"
+	memoryrepo "github.com/jimmitjoo/ecom/src/infrastructure/repositories/memory"
 )
 
 func setupTestService() (interfaces.ProductService, error) {
 	repo := repositories.NewMemoryProductRepository()
 	publisher := memory.NewMemoryEventPublisher()
 	lockManager := memorylocks.NewMemoryLockManager()
+	jobRepo := memoryrepo.NewJobRepository()
 
-	service := services.NewProductService(repo, publisher, lockManager)
+	service := services.NewProductService(repo, publisher, lockManager, jobRepo)
 	if service == nil {
 		return nil, fmt.Errorf("failed to create product service")
 	}
@@ -0,0 +1,114 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Golden is the golden file of one endpoint: the recorded examples, at most one per status code
+type Golden struct {
+	Endpoint string     `json:"endpoint"` // e.g. "GET /products/{id}"
+	Examples []*Example `json:"examples"`
+}
+
+// Example is a recorded request and the response it got
+type Example struct {
+	Request    RecordedRequest  `json:"request"`
+	Response   RecordedResponse `json:"response"`
+	RecordedAt time.Time        `json:"recorded_at"`
+}
+
+// RecordedRequest is a request as sent by a client. Credentials are not recorded.
+type RecordedRequest struct {
+	Method      string          `json:"method"`
+	Path        string          `json:"path"` // Including the query string
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"` // JSON bodies
+	Text        string          `json:"text,omitempty"` // Other bodies, e.g. CSV
+}
+
+// RecordedResponse is the response a request got
+type RecordedResponse struct {
+	Status      int             `json:"status"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	Text        string          `json:"text,omitempty"`
+}
+
+// goldenFile is the file an endpoint is recorded in, e.g. GET_products_id.json
+func goldenFile(dir, endpoint string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
+			return r
+		}
+		return '_'
+	}, endpoint)
+	for strings.Contains(name, "__") {
+		name = strings.ReplaceAll(name, "__", "_")
+	}
+	return filepath.Join(dir, strings.Trim(name, "_")+".json")
+}
+
+// readGolden reads a golden file, returning nil if it does not exist
+func readGolden(path string) (*Golden, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var golden Golden
+	if err := json.Unmarshal(data, &golden); err != nil {
+		return nil, fmt.Errorf("invalid golden file %s: %v", path, err)
+	}
+	return &golden, nil
+}
+
+// writeGolden writes a golden file with its examples ordered by status code
+func writeGolden(path string, golden *Golden) error {
+	sort.Slice(golden.Examples, func(i, j int) bool {
+		return golden.Examples[i].Response.Status < golden.Examples[j].Response.Status
+	})
+	data, err := json.MarshalIndent(golden, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// LoadGoldens reads every golden file in dir
+func LoadGoldens(dir string) ([]*Golden, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	goldens := make([]*Golden, 0, len(paths))
+	for _, path := range paths {
+		golden, err := readGolden(path)
+		if err != nil {
+			return nil, err
+		}
+		goldens = append(goldens, golden)
+	}
+	return goldens, nil
+}
+
+// body splits a body into its JSON or text form
+func body(contentType string, data []byte) (json.RawMessage, string) {
+	if len(data) == 0 {
+		return nil, ""
+	}
+	if strings.Contains(contentType, "json") && json.Valid(data) {
+		return json.RawMessage(data), ""
+	}
+	return nil, string(data)
+}
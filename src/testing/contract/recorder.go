@@ -0,0 +1,139 @@
+package contract
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// maxRecordedBody is the largest request or response body that is recorded
+const maxRecordedBody = 1 << 20
+
+// Recorder captures real requests and responses into golden files, one per
+// endpoint with one example per status code. It is meant for test
+// environments: run a build with the recorder, exercise the API the way
+// integrators do, and commit the golden files.
+type Recorder struct {
+	dir string
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewRecorder creates a recorder that writes golden files to dir
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create contract directory: %v", err)
+	}
+	return &Recorder{dir: dir, now: time.Now}, nil
+}
+
+// Middleware records the requests it serves. Used as router middleware the
+// endpoint is the route template, e.g. "GET /products/{id}"; otherwise the path.
+// WebSocket upgrades are not recorded.
+func (rec *Recorder) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		requestBody, err := io.ReadAll(io.LimitReader(r.Body, maxRecordedBody+1))
+		if err != nil {
+			http.Error(w, "failed to read request", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(requestBody), r.Body))
+
+		capture := &capturingWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(capture, r)
+
+		if len(requestBody) > maxRecordedBody || capture.body.Len() > maxRecordedBody {
+			return
+		}
+		example := &Example{
+			Request: RecordedRequest{
+				Method:      r.Method,
+				Path:        r.URL.RequestURI(),
+				ContentType: r.Header.Get("Content-Type"),
+			},
+			Response: RecordedResponse{
+				Status:      capture.status,
+				ContentType: w.Header().Get("Content-Type"),
+			},
+			RecordedAt: rec.now(),
+		}
+		example.Request.Body, example.Request.Text = body(example.Request.ContentType, requestBody)
+		example.Response.Body, example.Response.Text = body(example.Response.ContentType, capture.body.Bytes())
+
+		if err := rec.record(endpoint(r), example); err != nil {
+			log.Printf("Failed to record contract example: %v", err)
+		}
+	})
+}
+
+// record adds an example to its endpoint's golden file unless it already has
+// one with the same status. The first example is kept because later examples
+// may refer to what it created; delete the golden file to record it again.
+func (rec *Recorder) record(endpoint string, example *Example) error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	path := goldenFile(rec.dir, endpoint)
+	golden, err := readGolden(path)
+	if err != nil {
+		return err
+	}
+	if golden == nil {
+		golden = &Golden{Endpoint: endpoint}
+	}
+
+	for _, existing := range golden.Examples {
+		if existing.Response.Status == example.Response.Status {
+			return nil
+		}
+	}
+	golden.Examples = append(golden.Examples, example)
+	return writeGolden(path, golden)
+}
+
+// endpoint names the endpoint a request was routed to
+func endpoint(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return r.Method + " " + template
+		}
+	}
+	return r.Method + " " + r.URL.Path
+}
+
+// capturingWriter keeps a copy of the response
+type capturingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *capturingWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.wroteHeader = true
+	if w.body.Len() <= maxRecordedBody {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
@@ -0,0 +1,123 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// itemServer is a small API that assigns IDs with its own prefix, so
+// replays against another instance see different IDs
+func itemServer(prefix, nameField string) *mux.Router {
+	var mu sync.Mutex
+	items := make(map[string]map[string]interface{})
+
+	r := mux.NewRouter()
+	r.HandleFunc("/items", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message": "Invalid JSON data"}`)
+			return
+		}
+		mu.Lock()
+		item := map[string]interface{}{"id": fmt.Sprintf("%s-%d", prefix, len(items)+1), nameField: req.Name, "tags": []string{"new"}}
+		items[item["id"].(string)] = item
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(item)
+	}).Methods("POST")
+	r.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		item, exists := items[mux.Vars(r)["id"]]
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Item not found"}`)
+			return
+		}
+		json.NewEncoder(w).Encode(item)
+	}).Methods("GET")
+	return r
+}
+
+// record exercises an item server through a recorder
+func record(t *testing.T, dir string) {
+	recorder, err := NewRecorder(dir)
+	assert.NoError(t, err)
+	router := itemServer("a", "name")
+	router.Use(recorder.Middleware)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	assert.Equal(t, http.StatusCreated, do("POST", "/items", `{"name": "Shirt"}`).Code)
+	do("POST", "/items", `{"name": "Socks"}`)
+	do("POST", "/items", `{`)
+	assert.Equal(t, http.StatusOK, do("GET", "/items/a-1", "").Code)
+	do("GET", "/items/missing", "")
+}
+
+func TestRecorder(t *testing.T) {
+	dir := t.TempDir()
+	record(t, dir)
+
+	goldens, err := LoadGoldens(dir)
+	assert.NoError(t, err)
+	assert.Len(t, goldens, 2)
+
+	// The endpoint is the route template, with one example per status
+	get := goldens[0]
+	assert.Equal(t, "GET /items/{id}", get.Endpoint)
+	assert.Len(t, get.Examples, 2)
+	assert.Equal(t, http.StatusOK, get.Examples[0].Response.Status)
+	assert.Equal(t, "/items/a-1", get.Examples[0].Request.Path)
+	assert.JSONEq(t, `{"id": "a-1", "name": "Shirt", "tags": ["new"]}`, string(get.Examples[0].Response.Body))
+	assert.Equal(t, http.StatusNotFound, get.Examples[1].Response.Status)
+
+	// A later example with the same status does not replace the first
+	post := goldens[1]
+	assert.Equal(t, "POST /items", post.Endpoint)
+	assert.Len(t, post.Examples, 2)
+	assert.JSONEq(t, `{"name": "Shirt"}`, string(post.Examples[0].Request.Body))
+	assert.Equal(t, "application/json", post.Examples[0].Request.ContentType)
+	assert.Equal(t, http.StatusBadRequest, post.Examples[1].Response.Status)
+	assert.Equal(t, "{", post.Examples[1].Request.Text)
+	assert.Equal(t, goldenFile(dir, "POST /items"), dir+"/POST_items.json")
+}
+
+func TestRecorderSkipsWebSockets(t *testing.T) {
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir)
+	assert.NoError(t, err)
+	handler := recorder.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusSwitchingProtocols)
+	}))
+
+	req := httptest.NewRequest("GET", "/ws", nil)
+	req.Header.Set("Upgrade", "websocket")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	goldens, err := LoadGoldens(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, goldens)
+}
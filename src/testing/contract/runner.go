@@ -0,0 +1,235 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Mismatch is a way a new build responds differently from a golden example
+type Mismatch struct {
+	Endpoint string `json:"endpoint"`
+	Status   int    `json:"status"` // Recorded status of the example
+	Problem  string `json:"problem"`
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s (%d): %s", m.Endpoint, m.Status, m.Problem)
+}
+
+// Runner replays golden examples against a running build and reports
+// responses whose shape changed. Values are not compared, only the status,
+// content type and JSON structure: a field that disappears or changes type
+// breaks integrators, a new field does not.
+type Runner struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewRunner creates a runner for the build at baseURL, e.g. http://localhost:8080
+func NewRunner(baseURL string) *Runner {
+	return &Runner{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Run replays every example in dir in the order it was recorded, so a product
+// created by one example exists for the next. IDs the new build assigns
+// differently are substituted into later requests.
+func (r *Runner) Run(dir string) ([]Mismatch, error) {
+	goldens, err := LoadGoldens(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type replay struct {
+		endpoint string
+		example  *Example
+	}
+	replays := make([]replay, 0)
+	for _, golden := range goldens {
+		for _, example := range golden.Examples {
+			replays = append(replays, replay{endpoint: golden.Endpoint, example: example})
+		}
+	}
+	sort.SliceStable(replays, func(i, j int) bool {
+		return replays[i].example.RecordedAt.Before(replays[j].example.RecordedAt)
+	})
+
+	ids := make(map[string]string)
+	mismatches := make([]Mismatch, 0)
+	for _, replay := range replays {
+		problems, err := r.replay(replay.example, ids)
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay %s: %v", replay.endpoint, err)
+		}
+		for _, problem := range problems {
+			mismatches = append(mismatches, Mismatch{
+				Endpoint: replay.endpoint,
+				Status:   replay.example.Response.Status,
+				Problem:  problem,
+			})
+		}
+	}
+	return mismatches, nil
+}
+
+// replay sends one example and compares the response with the recorded one
+func (r *Runner) replay(example *Example, ids map[string]string) ([]string, error) {
+	requestBody := string(example.Request.Body)
+	if requestBody == "" {
+		requestBody = example.Request.Text
+	}
+	req, err := http.NewRequest(example.Request.Method, r.BaseURL+substitute(example.Request.Path, ids), strings.NewReader(substitute(requestBody, ids)))
+	if err != nil {
+		return nil, err
+	}
+	if example.Request.ContentType != "" {
+		req.Header.Set("Content-Type", example.Request.ContentType)
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	want := example.Response
+	if resp.StatusCode != want.Status {
+		return []string{fmt.Sprintf("status is %d, was %d", resp.StatusCode, want.Status)}, nil
+	}
+	if mediaType(resp.Header.Get("Content-Type")) != mediaType(want.ContentType) {
+		return []string{fmt.Sprintf("content type is %q, was %q", resp.Header.Get("Content-Type"), want.ContentType)}, nil
+	}
+	if want.Body == nil {
+		return nil, nil
+	}
+
+	var recorded, got interface{}
+	if err := json.Unmarshal(want.Body, &recorded); err != nil {
+		return nil, err
+	}
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&got); err != nil {
+		return []string{"response is not JSON"}, nil
+	}
+	collectIDs("", recorded, got, ids)
+	return CompareShape("$", recorded, got), nil
+}
+
+// CompareShape lists where got does not have the structure of want: missing
+// fields, changed types and fields that became null. Fields only in got are
+// allowed. Array elements are compared against the first recorded element.
+func CompareShape(path string, want, got interface{}) []string {
+	if want == nil {
+		return nil
+	}
+	if got == nil {
+		return []string{fmt.Sprintf("%s is null, was %s", path, jsonType(want))}
+	}
+	if jsonType(want) != jsonType(got) {
+		return []string{fmt.Sprintf("%s is %s, was %s", path, jsonType(got), jsonType(want))}
+	}
+
+	var problems []string
+	switch want := want.(type) {
+	case map[string]interface{}:
+		got := got.(map[string]interface{})
+		keys := make([]string, 0, len(want))
+		for key := range want {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, exists := got[key]
+			if !exists {
+				problems = append(problems, fmt.Sprintf("%s.%s is missing", path, key))
+				continue
+			}
+			problems = append(problems, CompareShape(path+"."+key, want[key], value)...)
+		}
+	case []interface{}:
+		if len(want) > 0 {
+			for i, element := range got.([]interface{}) {
+				problems = append(problems, CompareShape(fmt.Sprintf("%s[%d]", path, i), want[0], element)...)
+			}
+		}
+	}
+	return problems
+}
+
+// collectIDs records IDs the new build assigned differently, for fields named
+// id or ending in _id, so later requests can refer to the same resources
+func collectIDs(key string, want, got interface{}, ids map[string]string) {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		if got, ok := got.(map[string]interface{}); ok {
+			for k, value := range want {
+				collectIDs(k, value, got[k], ids)
+			}
+		}
+	case []interface{}:
+		if got, ok := got.([]interface{}); ok {
+			for i := 0; i < len(want) && i < len(got); i++ {
+				collectIDs(key, want[i], got[i], ids)
+			}
+		}
+	case string:
+		if got, ok := got.(string); ok && want != "" && want != got && (key == "id" || strings.HasSuffix(key, "_id")) {
+			ids[want] = got
+		}
+	}
+}
+
+// substitute replaces recorded IDs with the ones the new build assigned
+func substitute(s string, ids map[string]string) string {
+	// Longest first so an ID is not replaced inside a longer one
+	recorded := make([]string, 0, len(ids))
+	for id := range ids {
+		recorded = append(recorded, id)
+	}
+	sort.Slice(recorded, func(i, j int) bool {
+		if len(recorded[i]) != len(recorded[j]) {
+			return len(recorded[i]) > len(recorded[j])
+		}
+		return recorded[i] < recorded[j]
+	})
+	for _, id := range recorded {
+		s = strings.ReplaceAll(s, id, ids[id])
+	}
+	return s
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// mediaType strips parameters such as charset from a content type
+func mediaType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.TrimSpace(mediaType)
+}
@@ -0,0 +1,88 @@
+package contract
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunnerReplaysWithNewIDs(t *testing.T) {
+	dir := t.TempDir()
+	record(t, dir)
+
+	// Another build assigns other IDs; the recorded a-1 is found as b-1
+	server := httptest.NewServer(itemServer("b", "name"))
+	defer server.Close()
+
+	mismatches, err := NewRunner(server.URL).Run(dir)
+	assert.NoError(t, err)
+	assert.Empty(t, mismatches)
+}
+
+func TestRunnerReportsShapeChanges(t *testing.T) {
+	dir := t.TempDir()
+	record(t, dir)
+
+	server := httptest.NewServer(itemServer("b", "title"))
+	defer server.Close()
+
+	mismatches, err := NewRunner(server.URL).Run(dir)
+	assert.NoError(t, err)
+	assert.Len(t, mismatches, 2)
+	for _, mismatch := range mismatches {
+		assert.Equal(t, "$.name is missing", mismatch.Problem)
+	}
+	assert.Equal(t, "POST /items (201): $.name is missing", mismatches[0].String())
+}
+
+func TestCompareShape(t *testing.T) {
+	tests := []struct {
+		name     string
+		want     string
+		got      string
+		problems []string
+	}{
+		{"same shape", `{"id": "a", "n": 1, "ok": true}`, `{"id": "b", "n": 2, "ok": false}`, nil},
+		{"added field", `{"id": "a"}`, `{"id": "b", "extra": 1}`, nil},
+		{"missing field", `{"id": "a", "sku": "x"}`, `{"id": "b"}`, []string{"$.sku is missing"}},
+		{"changed type", `{"price": 1.5}`, `{"price": "1.5"}`, []string{"$.price is string, was number"}},
+		{"became null", `{"prices": []}`, `{"prices": null}`, []string{"$.prices is null, was array"}},
+		{"was null", `{"deleted_at": null}`, `{"deleted_at": "2024-01-01"}`, nil},
+		{"array elements", `[{"id": "a"}]`, `[{"id": "b"}, {"name": "c"}]`, []string{"$[1].id is missing"}},
+		{"nested", `{"data": {"product": {"sku": "x"}}}`, `{"data": {"product": {}}}`, []string{"$.data.product.sku is missing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want, got interface{}
+			assert.NoError(t, json.Unmarshal([]byte(tt.want), &want))
+			assert.NoError(t, json.Unmarshal([]byte(tt.got), &got))
+			assert.Equal(t, tt.problems, CompareShape("$", want, got))
+		})
+	}
+}
+
+// TestContracts replays the committed golden files against a running build,
+// e.g. CONTRACT_BASE_URL=http://localhost:8080 go test ./src/testing/contract/
+func TestContracts(t *testing.T) {
+	baseURL := os.Getenv("CONTRACT_BASE_URL")
+	if baseURL == "" {
+		t.Skip("CONTRACT_BASE_URL not set")
+	}
+	dir := os.Getenv("CONTRACT_DIR")
+	if dir == "" {
+		dir = "../../../test/contracts"
+	}
+
+	mismatches, err := NewRunner(baseURL).Run(dir)
+	assert.NoError(t, err)
+	problems := make([]string, 0, len(mismatches))
+	for _, mismatch := range mismatches {
+		problems = append(problems, mismatch.String())
+	}
+	assert.Empty(t, mismatches, strings.Join(problems, "\n"))
+}
@@ -1,6 +1,7 @@
 package load
 
 import (
+	"context"
 	"testing"
 
 	"github.com/jimmitjoo/ecom/src/application/interfaces"
@@ -15,7 +16,8 @@ func setupBenchmarkService() (interfaces.ProductService, error) {
 	publisher := eventmem.NewMemoryEventPublisher()
 	repository := memory.NewProductRepository()
 	lockManager := locks.NewMemoryLockManager()
-	return services.NewProductService(repository, publisher, lockManager), nil
+	jobRepository := memory.NewJobRepository()
+	return services.NewProductService(repository, publisher, lockManager, jobRepository), nil
 }
 
 func BenchmarkBatchOperations(b *testing.B) {
@@ -41,7 +43,7 @@ func BenchmarkBatchOperations(b *testing.B) {
 			b.ResetTimer()
 
 			for i := 0; i < b.N; i++ {
-				results, err := service.BatchCreateProducts(products)
+				results, err := service.BatchCreateProducts(context.Background(), products)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -80,7 +82,7 @@ func BenchmarkParallelBatchOperations(b *testing.B) {
 
 			b.RunParallel(func(pb *testing.PB) {
 				for pb.Next() {
-					results, err := service.BatchCreateProducts(products)
+					results, err := service.BatchCreateProducts(context.Background(), products)
 					if err != nil {
 						b.Fatal(err)
 					}